@@ -128,7 +128,7 @@ const rules: PatternOf<Token[], PatternData>[] = [
     allOf(
         not(some({ field: { value: 'type' }, value: { value: oneOf('diff', 'commit') } })),
         each({
-            field: { value: oneOf('author', 'before', 'until', 'after', 'since', 'message', 'msg', 'm') },
+            field: { value: oneOf('author', 'before', 'until', 'after', 'since', 'during', 'message', 'msg', 'm') },
             $data: addDiagnostic(token => [
                 createDiagnostic('This filter requires `type:commit` or `type:diff` in the query', token, 'error', [
                     {
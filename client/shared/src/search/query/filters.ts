@@ -17,6 +17,7 @@ export enum FilterType {
     content = 'content',
     context = 'context',
     count = 'count',
+    during = 'during',
     file = 'file',
     fork = 'fork',
     lang = 'lang',
@@ -231,6 +232,22 @@ export const FILTERS: Record<NegatableFilter, NegatableFilterDefinition> &
         placeholder: 'number',
         singular: true,
     },
+    [FilterType.during]: {
+        description: 'Commits made during a period relative to now, e.g. lastweek, or lastquarter',
+        discreteValues: () =>
+            [
+                'today',
+                'yesterday',
+                'thisweek',
+                'lastweek',
+                'thismonth',
+                'lastmonth',
+                'thisquarter',
+                'lastquarter',
+                'thisyear',
+                'lastyear',
+            ].map(value => ({ label: value })),
+    },
     [FilterType.file]: {
         alias: 'f',
         negatable: true,
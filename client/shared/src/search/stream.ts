@@ -56,6 +56,8 @@ export interface ContentMatch {
     chunkMatches?: ChunkMatch[]
     hunks?: DecoratedHunk[]
     debug?: string
+    /** A unified diff previewing the rewrite of the file, for structural search queries with `rewrite:`. */
+    rewriteDiff?: string
 }
 
 export interface DecoratedHunk {
@@ -113,6 +115,9 @@ export interface MatchedSymbol {
     containerName: string
     kind: SymbolKind
     line: number
+    signature?: string
+    /** Whether the symbol was read from a precise code intelligence index rather than extracted by ctags. */
+    precise?: boolean
 }
 
 type MarkdownText = string
@@ -321,7 +321,7 @@ const RELATED_FILTERS: Partial<Record<FilterType, (filter: Filter) => FilterType
         switch (filter.value?.value) {
             case 'diff':
             case 'commit':
-                return [FilterType.author, FilterType.before, FilterType.after, FilterType.message]
+                return [FilterType.author, FilterType.before, FilterType.after, FilterType.during, FilterType.message]
         }
         return []
     },
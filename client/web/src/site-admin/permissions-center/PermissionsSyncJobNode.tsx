@@ -54,6 +54,9 @@ const JOB_REASON_TO_READABLE_REASON: Record<PermissionsSyncJobReason, string> =
     REASON_GITHUB_USER_MEMBERSHIP_ADDED_EVENT: 'User membership added',
     REASON_GITHUB_USER_MEMBERSHIP_REMOVED_EVENT: 'User membership removed',
     REASON_GITHUB_USER_REMOVED_EVENT: 'User removed',
+    REASON_GITLAB_MEMBER_ADDED_EVENT: 'Member added',
+    REASON_GITLAB_MEMBER_REMOVED_EVENT: 'Member removed',
+    REASON_GITLAB_MEMBER_UPDATED_EVENT: 'Member access updated',
     REASON_MANUAL_REPO_SYNC: 'Repository synchronization triggered manually',
     REASON_MANUAL_USER_SYNC: 'User synchronization triggered manually',
     REASON_REPO_NO_PERMS: 'Repository has no permissions',
@@ -11,12 +11,14 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/authz/permssync"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/deviceid"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/featureflag"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/usagestats"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -189,6 +191,9 @@ func GetAndSaveUser(ctx context.Context, db database.DB, op GetAndSaveUserOp) (u
 
 		return user.ID, true, true, "", nil
 	}()
+	if err != nil && !op.CreateIfNotExist && errcode.IsNotFound(err) && conf.IsExternalIdentityAccessRequestEnabled() {
+		safeErrMsg = requestAccess(ctx, logger, db, op, safeErrMsg)
+	}
 	if err != nil {
 		const eventName = "ExternalAuthSignupFailed"
 		serviceTypeArg := json.RawMessage(fmt.Sprintf(`{"serviceType": %q}`, op.ExternalAccount.ServiceType))
@@ -254,3 +259,63 @@ func GetAndSaveUser(ctx context.Context, db database.DB, op GetAndSaveUserOp) (u
 
 	return userID, "", nil
 }
+
+// requestAccess submits an access request bound to the external account of a
+// user who is not allowed to sign up, so that a site admin can approve it and
+// create their account. It returns the message to show to the user.
+func requestAccess(ctx context.Context, logger sglog.Logger, db database.DB, op GetAndSaveUserOp, safeErrMsg string) string {
+	const pendingMsg = "Your request for access has been submitted. You can sign in once a site admin approves it."
+
+	// Access requests are identified by their email.
+	if op.UserProps.Email == "" {
+		return safeErrMsg
+	}
+
+	accessRequest, err := db.AccessRequests().GetByExternalAccount(ctx, op.ExternalAccount)
+	if err == nil {
+		if accessRequest.Status == types.AccessRequestStatusRejected {
+			return "Your request for access was rejected. Ask a site admin for help."
+		}
+		return pendingMsg
+	}
+	if !errcode.IsNotFound(err) {
+		logger.Error("failed to look up access request", sglog.Error(err))
+		return safeErrMsg
+	}
+
+	name := op.UserProps.DisplayName
+	if name == "" {
+		name = op.UserProps.Username
+	}
+	request := &types.AccessRequest{
+		Name:            name,
+		Email:           op.UserProps.Email,
+		Username:        op.UserProps.Username,
+		EmailVerified:   op.UserProps.EmailIsVerified,
+		ExternalAccount: &op.ExternalAccount,
+	}
+	_, err = db.AccessRequests().Create(ctx, request)
+	switch {
+	case err == nil:
+		return pendingMsg
+	case database.IsAccessRequestWithEmailExists(err):
+		// The request was made before with the built-in form. Bind it to the external
+		// account, so that approving it creates the account of this user. Only an
+		// email verified by the auth provider proves that the request is theirs, and
+		// requests bound to another external account are left alone.
+		if op.UserProps.EmailIsVerified {
+			if _, err := db.AccessRequests().BindExternalAccount(ctx, request); err != nil && !errcode.IsNotFound(err) {
+				logger.Error("failed to bind access request to external account", sglog.Error(err))
+				return safeErrMsg
+			}
+		}
+		// 🚨 SECURITY: We show the same message when an access request with the same
+		// email exists, as to not leak the existence of a given email address.
+		return pendingMsg
+	default:
+		if !database.IsAccessRequestUserWithEmailExists(err) {
+			logger.Error("failed to create access request", sglog.Error(err))
+		}
+		return safeErrMsg
+	}
+}
@@ -11,11 +11,13 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 // TestGetAndSaveUser ensures the correctness of the GetAndSaveUser function.
@@ -815,3 +817,50 @@ func userProps(username, email string) database.NewUser {
 		EmailIsVerified: true,
 	}
 }
+
+func TestGetAndSaveUserRequestsAccess(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		AuthAccessRequest: &schema.AuthAccessRequest{ExternalIdentities: true},
+	}})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	errNotFound := &errcode.Mock{IsNotFound: true}
+	usersStore := database.NewMockUserStore()
+	usersStore.GetByVerifiedEmailFunc.SetDefaultReturn(nil, errNotFound)
+	externalAccountsStore := database.NewMockUserExternalAccountsStore()
+	externalAccountsStore.LookupUserAndSaveFunc.SetDefaultReturn(0, errNotFound)
+	accessRequestsStore := database.NewMockAccessRequestStore()
+	accessRequestsStore.GetByExternalAccountFunc.SetDefaultReturn(nil, &database.ErrAccessRequestNotFound{})
+	db := database.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(usersStore)
+	db.UserExternalAccountsFunc.SetDefaultReturn(externalAccountsStore)
+	db.AccessRequestsFunc.SetDefaultReturn(accessRequestsStore)
+	db.EventLogsFunc.SetDefaultReturn(database.NewMockEventLogStore())
+
+	spec := ext("saml", "https://idp.example.com", "", "u-new")
+	op := GetAndSaveUserOp{
+		ExternalAccount: spec,
+		UserProps:       userProps("u-new", "u-new@example.com"),
+	}
+
+	_, safeErrMsg, err := GetAndSaveUser(context.Background(), db, op)
+	require.Error(t, err)
+	require.Equal(t, "Your request for access has been submitted. You can sign in once a site admin approves it.", safeErrMsg)
+
+	mockrequire.CalledOnce(t, accessRequestsStore.CreateFunc)
+	created := accessRequestsStore.CreateFunc.History()[0].Arg1
+	require.Equal(t, &types.AccessRequest{
+		Name:            "u-new",
+		Email:           "u-new@example.com",
+		Username:        "u-new",
+		EmailVerified:   true,
+		ExternalAccount: &spec,
+	}, created)
+
+	// A rejected request is not submitted again.
+	accessRequestsStore.GetByExternalAccountFunc.SetDefaultReturn(&types.AccessRequest{Status: types.AccessRequestStatusRejected}, nil)
+	_, safeErrMsg, err = GetAndSaveUser(context.Background(), db, op)
+	require.Error(t, err)
+	require.Equal(t, "Your request for access was rejected. Ask a site admin for help.", safeErrMsg)
+	mockrequire.CalledOnce(t, accessRequestsStore.CreateFunc)
+}
@@ -0,0 +1,103 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/config"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/cloneurls"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ListSubmodules returns the submodules of the repository at the given commit,
+// sorted by path. Submodules that are declared in .gitmodules but are not
+// present in the tree of the commit are omitted.
+func ListSubmodules(ctx context.Context, client gitserver.Client, repo api.RepoName, commit api.CommitID) (_ []gitdomain.Submodule, err error) {
+	tr, ctx := trace.New(ctx, "backend.ListSubmodules")
+	defer tr.FinishWithErr(&err)
+
+	content, err := client.ReadFile(ctx, authz.DefaultSubRepoPermsChecker, repo, commit, ".gitmodules")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var cfg config.Config
+	if err := config.NewDecoder(bytes.NewReader(content)).Decode(&cfg); err != nil {
+		return nil, errors.Wrap(err, "parsing .gitmodules")
+	}
+
+	var submodules []gitdomain.Submodule
+	for _, s := range cfg.Section("submodule").Subsections {
+		p := s.Option("path")
+		if p == "" {
+			continue
+		}
+		fi, err := client.Stat(ctx, authz.DefaultSubRepoPermsChecker, repo, commit, p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		submodule, ok := fi.Sys().(gitdomain.Submodule)
+		if !ok {
+			continue
+		}
+		// The path and URL are read from .gitmodules by Stat too, but only when
+		// the name of the submodule is its path.
+		submodule.Path = p
+		submodule.URL = s.Option("url")
+		submodules = append(submodules, submodule)
+	}
+	sort.Slice(submodules, func(i, j int) bool { return submodules[i].Path < submodules[j].Path })
+	return submodules, nil
+}
+
+// ResolveSubmoduleRepo returns the repository that a submodule of repo with the
+// given URL points to. It returns nil if the URL does not map to a repository
+// that is known to Sourcegraph and visible to the current user.
+//
+// Relative URLs (e.g. ../other.git) are resolved against the name of repo, in
+// the same way git resolves them against the URL of the superproject.
+func ResolveSubmoduleRepo(ctx context.Context, db database.DB, repo api.RepoName, submoduleURL string) (_ *types.Repo, err error) {
+	tr, ctx := trace.New(ctx, "backend.ResolveSubmoduleRepo")
+	defer tr.FinishWithErr(&err)
+
+	var name api.RepoName
+	if strings.HasPrefix(submoduleURL, "../") || strings.HasPrefix(submoduleURL, "./") {
+		name = api.RepoName(strings.TrimSuffix(path.Join(string(repo), submoduleURL), ".git"))
+	} else {
+		name, err = cloneurls.RepoSourceCloneURLToRepoName(ctx, db, submoduleURL)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			return nil, nil
+		}
+	}
+
+	r, err := db.Repos().GetByName(ctx, name)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r, nil
+}
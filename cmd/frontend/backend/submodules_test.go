@@ -0,0 +1,89 @@
+package backend
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/fileutil"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestListSubmodules(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no .gitmodules", func(t *testing.T) {
+		client := gitserver.NewMockClient()
+		client.ReadFileFunc.SetDefaultReturn(nil, os.ErrNotExist)
+
+		submodules, err := ListSubmodules(ctx, client, "repo", "deadbeef")
+		require.NoError(t, err)
+		require.Empty(t, submodules)
+	})
+
+	t.Run("submodules", func(t *testing.T) {
+		client := gitserver.NewMockClient()
+		client.ReadFileFunc.SetDefaultReturn([]byte(`[submodule "lib"]
+	path = vendor/lib
+	url = https://github.com/sourcegraph/lib
+[submodule "docs"]
+	path = docs
+	url = ../docs.git
+[submodule "removed"]
+	path = removed
+	url = https://github.com/sourcegraph/removed
+`), nil)
+		client.StatFunc.SetDefaultHook(func(_ context.Context, _ authz.SubRepoPermissionChecker, _ api.RepoName, _ api.CommitID, path string) (fs.FileInfo, error) {
+			switch path {
+			case "vendor/lib":
+				return &fileutil.FileInfo{Name_: path, Mode_: gitdomain.ModeSubmodule, Sys_: gitdomain.Submodule{CommitID: "c1"}}, nil
+			case "docs":
+				return &fileutil.FileInfo{Name_: path, Mode_: gitdomain.ModeSubmodule, Sys_: gitdomain.Submodule{CommitID: "c2"}}, nil
+			}
+			return nil, &os.PathError{Op: "ls-tree", Path: path, Err: os.ErrNotExist}
+		})
+
+		submodules, err := ListSubmodules(ctx, client, "repo", "deadbeef")
+		require.NoError(t, err)
+
+		want := []gitdomain.Submodule{
+			{Path: "docs", URL: "../docs.git", CommitID: "c2"},
+			{Path: "vendor/lib", URL: "https://github.com/sourcegraph/lib", CommitID: "c1"},
+		}
+		if diff := cmp.Diff(want, submodules); diff != "" {
+			t.Errorf("unexpected submodules (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestResolveSubmoduleRepo(t *testing.T) {
+	ctx := context.Background()
+
+	repos := database.NewMockRepoStore()
+	repos.GetByNameFunc.SetDefaultHook(func(_ context.Context, name api.RepoName) (*types.Repo, error) {
+		if name == "github.com/sourcegraph/docs" {
+			return &types.Repo{Name: name}, nil
+		}
+		return nil, &database.RepoNotFoundErr{Name: name}
+	})
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repos)
+
+	repo, err := ResolveSubmoduleRepo(ctx, db, "github.com/sourcegraph/sourcegraph", "../docs.git")
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+	require.Equal(t, api.RepoName("github.com/sourcegraph/docs"), repo.Name)
+
+	repo, err = ResolveSubmoduleRepo(ctx, db, "github.com/sourcegraph/sourcegraph", "./unknown")
+	require.NoError(t, err)
+	require.Nil(t, repo)
+}
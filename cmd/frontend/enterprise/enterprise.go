@@ -3,6 +3,7 @@ package enterprise
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -41,6 +42,15 @@ type Services struct {
 	// Handler for exporting code insights data.
 	CodeInsightsDataExportHandler http.Handler
 
+	// Handler for downloading search exports.
+	SearchExportDownloadHandler http.Handler
+
+	// Handler for downloading user data exports and deletion reports.
+	UserDataExportDownloadHandler http.Handler
+
+	// Handler for downloading executor job artifacts.
+	ExecutorArtifactDownloadHandler http.Handler
+
 	// Handler for completions stream.
 	NewChatCompletionsStreamHandler NewChatCompletionsStreamHandler
 
@@ -51,6 +61,7 @@ type Services struct {
 	NewDotcomLicenseCheckHandler NewDotcomLicenseCheckHandler
 
 	PermissionsGitHubWebhook  webhooks.Registerer
+	PermissionsGitLabWebhook  webhooks.Registerer
 	NewCodeIntelUploadHandler NewCodeIntelUploadHandler
 	RankingService            RankingService
 	NewExecutorProxyHandler   NewExecutorProxyHandler
@@ -69,6 +80,7 @@ type RankingService interface {
 	LastUpdatedAt(ctx context.Context, repoIDs []api.RepoID) (map[api.RepoID]time.Time, error)
 	GetRepoRank(ctx context.Context, repoName api.RepoName) (_ []float64, err error)
 	GetDocumentRanks(ctx context.Context, repoName api.RepoName) (_ types.RepoPathRanks, err error)
+	ExportRankingSignals(ctx context.Context, repoName api.RepoName, w io.Writer) error
 }
 
 // NewExecutorProxyHandler creates a new proxy handler for routes accessible to the
@@ -100,6 +112,7 @@ func DefaultServices() Services {
 		ReposBitbucketServerWebhook:     &emptyWebhookHandler{name: "bitbucket server sync webhook"},
 		ReposBitbucketCloudWebhook:      &emptyWebhookHandler{name: "bitbucket cloud sync webhook"},
 		PermissionsGitHubWebhook:        &emptyWebhookHandler{name: "permissions github webhook"},
+		PermissionsGitLabWebhook:        &emptyWebhookHandler{name: "permissions gitlab webhook"},
 		BatchesGitHubWebhook:            &emptyWebhookHandler{name: "batches github webhook"},
 		BatchesGitLabWebhook:            &emptyWebhookHandler{name: "batches gitlab webhook"},
 		BatchesBitbucketServerWebhook:   &emptyWebhookHandler{name: "batches bitbucket server webhook"},
@@ -115,6 +128,9 @@ func DefaultServices() Services {
 		NewGitHubAppSetupHandler:        func() http.Handler { return makeNotFoundHandler("Sourcegraph GitHub App setup") },
 		NewComputeStreamHandler:         func() http.Handler { return makeNotFoundHandler("compute streaming endpoint") },
 		CodeInsightsDataExportHandler:   makeNotFoundHandler("code insights data export handler"),
+		SearchExportDownloadHandler:     makeNotFoundHandler("search export download handler"),
+		UserDataExportDownloadHandler:   makeNotFoundHandler("user data export download handler"),
+		ExecutorArtifactDownloadHandler: makeNotFoundHandler("executor artifact download handler"),
 		NewDotcomLicenseCheckHandler:    func() http.Handler { return makeNotFoundHandler("dotcom license check handler") },
 		NewChatCompletionsStreamHandler: func() http.Handler { return makeNotFoundHandler("chat completions streaming endpoint") },
 		NewCodeCompletionsHandler:       func() http.Handler { return makeNotFoundHandler("code completions streaming endpoint") },
@@ -199,3 +215,7 @@ func (s stubRankingService) GetRepoRank(ctx context.Context, repoName api.RepoNa
 func (s stubRankingService) GetDocumentRanks(ctx context.Context, repoName api.RepoName) (_ types.RepoPathRanks, err error) {
 	return types.RepoPathRanks{}, nil
 }
+
+func (s stubRankingService) ExportRankingSignals(ctx context.Context, repoName api.RepoName, w io.Writer) error {
+	return nil
+}
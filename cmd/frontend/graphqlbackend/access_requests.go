@@ -8,9 +8,15 @@ import (
 	"github.com/graph-gophers/graphql-go/relay"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/authz/permssync"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -64,7 +70,7 @@ func (s *accessRequestConnectionStore) ComputeNodes(ctx context.Context, args *d
 
 	resolvers := make([]*accessRequestResolver, len(accessRequests))
 	for i, accessRequest := range accessRequests {
-		resolvers[i] = &accessRequestResolver{accessRequest: accessRequest}
+		resolvers[i] = &accessRequestResolver{db: s.db, accessRequest: accessRequest}
 	}
 
 	return resolvers, nil
@@ -93,6 +99,7 @@ func (s *accessRequestConnectionStore) UnmarshalCursor(cursor string, _ database
 
 // accessRequestResolver resolves an access request.
 type accessRequestResolver struct {
+	db            database.DB
 	accessRequest *types.AccessRequest
 }
 
@@ -110,6 +117,31 @@ func (s *accessRequestResolver) AdditionalInfo() *string { return &s.accessReque
 
 func (s *accessRequestResolver) Status() string { return string(s.accessRequest.Status) }
 
+func (s *accessRequestResolver) Username() *string {
+	if s.accessRequest.Username == "" {
+		return nil
+	}
+	return &s.accessRequest.Username
+}
+
+func (s *accessRequestResolver) ExternalServiceType() *string {
+	if s.accessRequest.ExternalAccount == nil {
+		return nil
+	}
+	return &s.accessRequest.ExternalAccount.ServiceType
+}
+
+func (s *accessRequestResolver) User(ctx context.Context) (*UserResolver, error) {
+	if s.accessRequest.UserID == nil {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, s.db, *s.accessRequest.UserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
 func (r *schemaResolver) SetAccessRequestStatus(ctx context.Context, args *struct {
 	ID     graphql.ID
 	Status types.AccessRequestStatus
@@ -124,6 +156,7 @@ func (r *schemaResolver) SetAccessRequestStatus(ctx context.Context, args *struc
 		return nil, err
 	}
 
+	var createdUserID int32
 	err = r.db.WithTransact(ctx, func(tx database.DB) error {
 		store := tx.AccessRequests()
 
@@ -137,8 +170,17 @@ func (r *schemaResolver) SetAccessRequestStatus(ctx context.Context, args *struc
 			return err
 		}
 
-		accessRequest.Status = args.Status
-		if _, err := store.Update(ctx, &types.AccessRequest{ID: accessRequest.ID, Status: accessRequest.Status, DecisionByUserID: &currentUser.ID}); err != nil {
+		update := &types.AccessRequest{ID: accessRequest.ID, Status: args.Status, DecisionByUserID: &currentUser.ID}
+		if args.Status == types.AccessRequestStatusApproved && accessRequest.ExternalAccount != nil && accessRequest.UserID == nil {
+			user, err := createAccessRequestUser(ctx, tx, accessRequest)
+			if err != nil {
+				return err
+			}
+			createdUserID = user.ID
+			update.UserID = &user.ID
+		}
+
+		if _, err := store.Update(ctx, update); err != nil {
 			return err
 		}
 		return nil
@@ -148,9 +190,46 @@ func (r *schemaResolver) SetAccessRequestStatus(ctx context.Context, args *struc
 		return nil, err
 	}
 
+	if createdUserID != 0 {
+		permssync.SchedulePermsSync(ctx, r.logger, r.db, protocol.PermsSyncRequest{
+			UserIDs:           []int32{createdUserID},
+			Reason:            database.ReasonUserAdded,
+			TriggeredByUserID: actor.FromContext(ctx).UID,
+		})
+	}
+
 	return &EmptyResponse{}, nil
 }
 
+// createAccessRequestUser creates the account of an approved access request made
+// by signing in with an auth provider that does not allow signup, bound to the
+// external identity the request was made with. The data of the external account
+// is saved the next time the user signs in.
+func createAccessRequestUser(ctx context.Context, tx database.DB, accessRequest *types.AccessRequest) (*types.User, error) {
+	user, err := tx.UserExternalAccounts().CreateUserAndSave(ctx, database.NewUser{
+		Username:        accessRequest.Username,
+		Email:           accessRequest.Email,
+		EmailIsVerified: accessRequest.EmailVerified,
+		DisplayName:     accessRequest.Name,
+	}, *accessRequest.ExternalAccount, extsvc.AccountData{})
+	if err != nil {
+		if database.IsUsernameExists(err) {
+			return nil, errors.Newf("cannot approve the access request: username %q is already taken", accessRequest.Username)
+		}
+		return nil, err
+	}
+
+	if err := tx.Authz().GrantPendingPermissions(ctx, &database.GrantPendingPermissionsArgs{
+		UserID: user.ID,
+		Perm:   authz.Read,
+		Type:   authz.PermRepos,
+	}); err != nil {
+		return nil, errors.Wrap(err, "granting pending permissions")
+	}
+
+	return user, nil
+}
+
 func accessRequestByID(ctx context.Context, db database.DB, id graphql.ID) (*accessRequestResolver, error) {
 	// 🚨 SECURITY: Only site admins can see access requests.
 	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, db); err != nil {
@@ -166,7 +245,7 @@ func accessRequestByID(ctx context.Context, db database.DB, id graphql.ID) (*acc
 		return nil, err
 	}
 
-	return &accessRequestResolver{accessRequest}, nil
+	return &accessRequestResolver{db: db, accessRequest: accessRequest}, nil
 }
 
 func marshalAccessRequestID(id int32) graphql.ID { return relay.MarshalID("AccessRequest", id) }
@@ -7,10 +7,12 @@ import (
 
 	gqlerrors "github.com/graph-gophers/graphql-go/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
@@ -244,6 +246,47 @@ func TestSetAccessRequestStatusMutation(t *testing.T) {
 		assert.Equal(t, types.AccessRequest{ID: mockAccessRequest.ID, DecisionByUserID: &userID, Status: types.AccessRequestStatusApproved}, *accessRequestStore.UpdateFunc.History()[0].Arg1)
 	})
 
+	t.Run("access request with external identity", func(t *testing.T) {
+		accessRequestStore := database.NewMockAccessRequestStore()
+		db.AccessRequestsFunc.SetDefaultReturn(accessRequestStore)
+		externalAccountsStore := database.NewMockUserExternalAccountsStore()
+		db.UserExternalAccountsFunc.SetDefaultReturn(externalAccountsStore)
+		authzStore := database.NewMockAuthzStore()
+		db.AuthzFunc.SetDefaultReturn(authzStore)
+		db.PermissionSyncJobsFunc.SetDefaultReturn(database.NewMockPermissionSyncJobStore())
+
+		spec := extsvc.AccountSpec{ServiceType: "saml", ServiceID: "https://idp.example.com", AccountID: "a1"}
+		mockAccessRequest := &types.AccessRequest{ID: 1, Email: "a1@example.com", EmailVerified: true, Name: "A One", Username: "a1", ExternalAccount: &spec, Status: types.AccessRequestStatusPending}
+		accessRequestStore.GetByIDFunc.SetDefaultReturn(mockAccessRequest, nil)
+		accessRequestStore.UpdateFunc.SetDefaultReturn(mockAccessRequest, nil)
+		externalAccountsStore.CreateUserAndSaveFunc.SetDefaultReturn(&types.User{ID: 10, Username: "a1"}, nil)
+		userID := int32(123)
+		userStore.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: userID, SiteAdmin: true}, nil)
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+
+		RunTest(t, &Test{
+			Schema:         mustParseGraphQLSchema(t, db),
+			Context:        ctx,
+			Query:          setAccessRequestStatusMutation,
+			ExpectedResult: `{"setAccessRequestStatus": { "alwaysNil": null } }`,
+			Variables: map[string]any{
+				"id":     string(marshalAccessRequestID(1)),
+				"status": string(types.AccessRequestStatusApproved),
+			},
+		})
+
+		require.Len(t, externalAccountsStore.CreateUserAndSaveFunc.History(), 1)
+		call := externalAccountsStore.CreateUserAndSaveFunc.History()[0]
+		assert.Equal(t, database.NewUser{Username: "a1", Email: "a1@example.com", EmailIsVerified: true, DisplayName: "A One"}, call.Arg1)
+		assert.Equal(t, spec, call.Arg2)
+		assert.Len(t, authzStore.GrantPendingPermissionsFunc.History(), 1)
+
+		createdUserID := int32(10)
+		require.Len(t, accessRequestStore.UpdateFunc.History(), 1)
+		assert.Equal(t, types.AccessRequest{ID: mockAccessRequest.ID, DecisionByUserID: &userID, Status: types.AccessRequestStatusApproved, UserID: &createdUserID}, *accessRequestStore.UpdateFunc.History()[0].Arg1)
+	})
+
 	t.Run("non-existing access request", func(t *testing.T) {
 		accessRequestStore := database.NewMockAccessRequestStore()
 		db.AccessRequestsFunc.SetDefaultReturn(accessRequestStore)
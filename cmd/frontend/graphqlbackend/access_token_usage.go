@@ -0,0 +1,150 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+)
+
+type accessTokenUsageArgs struct {
+	Since gqlutil.DateTime
+}
+
+func (r *siteResolver) AccessTokenUsage(ctx context.Context, args *struct {
+	accessTokenUsageArgs
+	First int32
+}) ([]*accessTokenUsageResolver, error) {
+	// 🚨 SECURITY: Only site admins can see the usage of all access tokens.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	return listAccessTokenUsage(ctx, r.db, database.AccessTokenUsageListOptions{
+		Since: args.Since.Time,
+		Limit: int(args.First),
+	})
+}
+
+func (r *siteResolver) AccessTokenUsageAlerts(ctx context.Context, args *struct {
+	accessTokenUsageArgs
+	First int32
+}) ([]*accessTokenUsageAlertResolver, error) {
+	// 🚨 SECURITY: Only site admins can see the usage alerts of all access tokens.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	alerts, err := r.db.AccessTokenUsage().ListAlerts(ctx, database.AccessTokenUsageListOptions{
+		Since: args.Since.Time,
+		Limit: int(args.First),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*accessTokenUsageAlertResolver, 0, len(alerts))
+	for _, alert := range alerts {
+		resolvers = append(resolvers, &accessTokenUsageAlertResolver{db: r.db, alert: alert})
+	}
+	return resolvers, nil
+}
+
+func (r *UserResolver) AccessTokenUsage(ctx context.Context, args *accessTokenUsageArgs) ([]*accessTokenUsageResolver, error) {
+	// 🚨 SECURITY: Only site admins and the user can see the usage of a user's access tokens.
+	if err := auth.CheckSiteAdminOrSameUser(ctx, r.db, r.user.ID); err != nil {
+		return nil, err
+	}
+
+	return listAccessTokenUsage(ctx, r.db, database.AccessTokenUsageListOptions{
+		Since:         args.Since.Time,
+		SubjectUserID: r.user.ID,
+	})
+}
+
+func (r *accessTokenResolver) Usage(ctx context.Context, args *accessTokenUsageArgs) ([]*accessTokenUsageResolver, error) {
+	// accessTokenResolver values are only created after checking that the
+	// viewer is the token owner or a site admin.
+	return listAccessTokenUsage(ctx, r.db, database.AccessTokenUsageListOptions{
+		Since:         args.Since.Time,
+		AccessTokenID: r.accessToken.ID,
+	})
+}
+
+// listAccessTokenUsage lists the usage of access tokens.
+//
+// 🚨 SECURITY: The caller MUST check that the viewer can see the usage of the
+// access tokens matching opts.
+func listAccessTokenUsage(ctx context.Context, db database.DB, opts database.AccessTokenUsageListOptions) ([]*accessTokenUsageResolver, error) {
+	usage, err := db.AccessTokenUsage().List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*accessTokenUsageResolver, 0, len(usage))
+	for _, u := range usage {
+		resolvers = append(resolvers, &accessTokenUsageResolver{db: db, usage: u})
+	}
+	return resolvers, nil
+}
+
+// usageAccessToken resolves the access token of usage or a usage alert, or nil
+// if the access token was deleted.
+func usageAccessToken(ctx context.Context, db database.DB, id int64) (*accessTokenResolver, error) {
+	accessToken, err := db.AccessTokens().GetByID(ctx, id)
+	if err == database.ErrAccessTokenNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &accessTokenResolver{db: db, accessToken: *accessToken}, nil
+}
+
+type accessTokenUsageResolver struct {
+	db    database.DB
+	usage *database.AccessTokenUsage
+}
+
+func (r *accessTokenUsageResolver) AccessToken(ctx context.Context) (*accessTokenResolver, error) {
+	return usageAccessToken(ctx, r.db, r.usage.AccessTokenID)
+}
+
+func (r *accessTokenUsageResolver) Endpoint() string { return r.usage.Endpoint }
+
+func (r *accessTokenUsageResolver) Operation() string { return r.usage.Operation }
+
+func (r *accessTokenUsageResolver) RequestCount() int32 { return int32(r.usage.RequestCount) }
+
+func (r *accessTokenUsageResolver) ErrorCount() int32 { return int32(r.usage.ErrorCount) }
+
+func (r *accessTokenUsageResolver) ResponseBytes() BigInt { return BigInt(r.usage.ResponseBytes) }
+
+func (r *accessTokenUsageResolver) LatencyP50() float64 { return r.usage.LatencyPercentile(50) }
+
+func (r *accessTokenUsageResolver) LatencyP90() float64 { return r.usage.LatencyPercentile(90) }
+
+func (r *accessTokenUsageResolver) LatencyP99() float64 { return r.usage.LatencyPercentile(99) }
+
+type accessTokenUsageAlertResolver struct {
+	db    database.DB
+	alert *database.AccessTokenUsageAlert
+}
+
+func (r *accessTokenUsageAlertResolver) AccessToken(ctx context.Context) (*accessTokenResolver, error) {
+	return usageAccessToken(ctx, r.db, r.alert.AccessTokenID)
+}
+
+func (r *accessTokenUsageAlertResolver) Hour() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.alert.Bucket}
+}
+
+func (r *accessTokenUsageAlertResolver) RequestCount() int32 { return int32(r.alert.RequestCount) }
+
+func (r *accessTokenUsageAlertResolver) BaselineRequestCount() float64 {
+	return r.alert.BaselineRequestCount
+}
+
+func (r *accessTokenUsageAlertResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.alert.CreatedAt}
+}
@@ -0,0 +1,110 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+type fakeAccessTokenUsageStore struct {
+	database.AccessTokenUsageStore
+	usage    []*database.AccessTokenUsage
+	lastOpts database.AccessTokenUsageListOptions
+}
+
+func (s *fakeAccessTokenUsageStore) List(_ context.Context, opts database.AccessTokenUsageListOptions) ([]*database.AccessTokenUsage, error) {
+	s.lastOpts = opts
+	return s.usage, nil
+}
+
+func TestSiteAccessTokenUsage(t *testing.T) {
+	const query = `{
+		site {
+			accessTokenUsage(since: "2023-07-01T00:00:00Z", first: 10) {
+				accessToken { id }
+				endpoint
+				operation
+				requestCount
+				errorCount
+				responseBytes
+				latencyP50
+				latencyP99
+			}
+		}
+	}`
+
+	latencyBuckets := make([]int32, len(database.AccessTokenUsageLatencyBounds)+1)
+	latencyBuckets[database.AccessTokenUsageLatencyBucket(20*time.Millisecond)] = 99
+	latencyBuckets[database.AccessTokenUsageLatencyBucket(time.Minute)] = 1
+	usageStore := &fakeAccessTokenUsageStore{usage: []*database.AccessTokenUsage{{
+		AccessTokenID:  1,
+		SubjectUserID:  2,
+		Endpoint:       "graphql",
+		Operation:      "Search",
+		RequestCount:   100,
+		ErrorCount:     3,
+		ResponseBytes:  4096,
+		LatencyBuckets: latencyBuckets,
+	}}}
+
+	users := database.NewMockUserStore()
+	accessTokens := database.NewMockAccessTokenStore()
+	accessTokens.GetByIDFunc.SetDefaultReturn(nil, database.ErrAccessTokenNotFound)
+
+	db := database.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+	db.AccessTokenUsageFunc.SetDefaultReturn(usageStore)
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+
+	t.Run("non-admin user", func(t *testing.T) {
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1}, nil)
+		RunTest(t, &Test{
+			Schema:         mustParseGraphQLSchema(t, db),
+			Context:        ctx,
+			Query:          query,
+			ExpectedResult: `null`,
+			ExpectedErrors: []*gqlerrors.QueryError{
+				{
+					Path:          []any{"site", "accessTokenUsage"},
+					Message:       auth.ErrMustBeSiteAdmin.Error(),
+					ResolverError: auth.ErrMustBeSiteAdmin,
+				},
+			},
+		})
+	})
+
+	t.Run("admin user", func(t *testing.T) {
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+		RunTest(t, &Test{
+			Schema:  mustParseGraphQLSchema(t, db),
+			Context: ctx,
+			Query:   query,
+			ExpectedResult: `{
+				"site": {
+					"accessTokenUsage": [{
+						"accessToken": null,
+						"endpoint": "graphql",
+						"operation": "Search",
+						"requestCount": 100,
+						"errorCount": 3,
+						"responseBytes": "4096",
+						"latencyP50": 25,
+						"latencyP99": 25
+					}]
+				}
+			}`,
+		})
+		assert.Equal(t, 10, usageStore.lastOpts.Limit)
+		assert.Equal(t, time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), usageStore.lastOpts.Since.UTC())
+	})
+}
@@ -0,0 +1,191 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+const auditLogEventIDKind = "AuditLogEvent"
+
+type auditLogEventsArgs struct {
+	First        int32
+	After        *string
+	Actions      *[]string
+	EntityType   *string
+	Organization *graphql.ID
+	Actor        *graphql.ID
+	Since        *gqlutil.DateTime
+	Until        *gqlutil.DateTime
+}
+
+func (r *schemaResolver) AuditLogEvents(ctx context.Context, args *auditLogEventsArgs) (*auditLogEventConnectionResolver, error) {
+	opts := database.AuditLogEventsListOptions{}
+
+	if args.Organization != nil {
+		orgID, err := UnmarshalOrgID(*args.Organization)
+		if err != nil {
+			return nil, err
+		}
+		// 🚨 SECURITY: Members of an organization can list the events of their
+		// organization, site admins the events of all organizations.
+		if err := auth.CheckOrgAccessOrSiteAdmin(ctx, r.db, orgID); err != nil {
+			return nil, err
+		}
+		opts.OrgID = orgID
+	} else {
+		// 🚨 SECURITY: Only site admins can list all events.
+		if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+			return nil, err
+		}
+	}
+
+	if args.Actor != nil {
+		userID, err := UnmarshalUserID(*args.Actor)
+		if err != nil {
+			return nil, err
+		}
+		opts.ActorUserID = userID
+	}
+	if args.Actions != nil {
+		opts.Actions = *args.Actions
+	}
+	if args.EntityType != nil {
+		opts.EntityType = *args.EntityType
+	}
+	if args.Since != nil {
+		opts.Since = args.Since.Time
+	}
+	if args.Until != nil {
+		opts.Until = args.Until.Time
+	}
+
+	offset, err := graphqlutil.DecodeIntCursor(args.After)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auditLogEventConnectionResolver{
+		db:     r.db,
+		opts:   opts,
+		limit:  int(args.First),
+		offset: offset,
+	}, nil
+}
+
+type auditLogEventConnectionResolver struct {
+	db            database.DB
+	opts          database.AuditLogEventsListOptions
+	limit, offset int
+
+	once   sync.Once
+	events []*types.AuditLogEvent
+	next   int
+	err    error
+}
+
+func (r *auditLogEventConnectionResolver) compute(ctx context.Context) ([]*types.AuditLogEvent, int, error) {
+	r.once.Do(func() {
+		opts := r.opts
+		// Request one more event to know whether there is a next page.
+		opts.LimitOffset = &database.LimitOffset{Limit: r.limit + 1, Offset: r.offset}
+		r.events, r.err = r.db.AuditLogEvents().List(ctx, opts)
+		if r.err == nil && len(r.events) > r.limit {
+			r.events = r.events[:r.limit]
+			r.next = r.offset + r.limit
+		}
+	})
+	return r.events, r.next, r.err
+}
+
+func (r *auditLogEventConnectionResolver) Nodes(ctx context.Context) ([]*auditLogEventResolver, error) {
+	events, _, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*auditLogEventResolver, len(events))
+	for i, event := range events {
+		nodes[i] = &auditLogEventResolver{db: r.db, event: event}
+	}
+	return nodes, nil
+}
+
+func (r *auditLogEventConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := r.db.AuditLogEvents().Count(ctx, r.opts)
+	return int32(count), err
+}
+
+func (r *auditLogEventConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	_, next, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if next != 0 {
+		n := int32(next)
+		return graphqlutil.EncodeIntCursor(&n), nil
+	}
+	return graphqlutil.HasNextPage(false), nil
+}
+
+type auditLogEventResolver struct {
+	db    database.DB
+	event *types.AuditLogEvent
+}
+
+func (r *auditLogEventResolver) ID() graphql.ID {
+	return relay.MarshalID(auditLogEventIDKind, r.event.ID)
+}
+
+func (r *auditLogEventResolver) Action() string { return r.event.Action }
+
+func (r *auditLogEventResolver) EntityType() string { return r.event.EntityType }
+
+func (r *auditLogEventResolver) EntityID() string { return r.event.EntityID }
+
+func (r *auditLogEventResolver) Organization(ctx context.Context) (*OrgResolver, error) {
+	if r.event.OrgID == nil {
+		return nil, nil
+	}
+	org, err := OrgByIDInt32(ctx, r.db, *r.event.OrgID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return org, err
+}
+
+func (r *auditLogEventResolver) Actor(ctx context.Context) (*UserResolver, error) {
+	if r.event.ActorUserID == nil {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, r.db, *r.event.ActorUserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *auditLogEventResolver) ActorIP() *string {
+	if r.event.ActorIP == "" {
+		return nil
+	}
+	return &r.event.ActorIP
+}
+
+func (r *auditLogEventResolver) Argument() JSONValue {
+	return JSONValue{Value: r.event.Argument}
+}
+
+func (r *auditLogEventResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.event.CreatedAt}
+}
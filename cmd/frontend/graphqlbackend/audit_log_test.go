@@ -0,0 +1,89 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestAuditLogEvents(t *testing.T) {
+	users := database.NewMockUserStore()
+	orgMembers := database.NewMockOrgMemberStore()
+	orgMembers.GetByOrgIDAndUserIDFunc.SetDefaultHook(func(_ context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+		if orgID == 1 && userID == 2 {
+			return &types.OrgMembership{OrgID: orgID, UserID: userID}, nil
+		}
+		return nil, &errcode.Mock{IsNotFound: true}
+	})
+
+	orgID := int32(1)
+	events := database.NewMockAuditLogEventStore()
+	events.ListFunc.SetDefaultReturn([]*types.AuditLogEvent{
+		{ID: 3, Action: types.AuditLogActionOrgMemberRemoved, EntityType: "org_member", OrgID: &orgID, Argument: []byte(`{}`)},
+		{ID: 2, Action: types.AuditLogActionOrgMemberAdded, EntityType: "org_member", OrgID: &orgID, Argument: []byte(`{}`)},
+	}, nil)
+	events.CountFunc.SetDefaultReturn(2, nil)
+
+	db := database.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	db.OrgMembersFunc.SetDefaultReturn(orgMembers)
+	db.AuditLogEventsFunc.SetDefaultReturn(events)
+
+	schema := newSchemaResolver(db, nil, nil)
+	ctx := actor.WithActor(context.Background(), actor.FromUser(2))
+	org := MarshalOrgID(orgID)
+	otherOrg := MarshalOrgID(orgID + 1)
+
+	t.Run("non-admin", func(t *testing.T) {
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 2}, nil)
+
+		_, err := schema.AuditLogEvents(ctx, &auditLogEventsArgs{First: 50})
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+		_, err = schema.AuditLogEvents(ctx, &auditLogEventsArgs{First: 50, Organization: &otherOrg})
+		require.ErrorIs(t, err, auth.ErrNotAnOrgMember)
+
+		conn, err := schema.AuditLogEvents(ctx, &auditLogEventsArgs{First: 1, Organization: &org})
+		require.NoError(t, err)
+
+		nodes, err := conn.Nodes(ctx)
+		require.NoError(t, err)
+		require.Len(t, nodes, 1)
+		assert.Equal(t, types.AuditLogActionOrgMemberRemoved, nodes[0].Action())
+
+		pageInfo, err := conn.PageInfo(ctx)
+		require.NoError(t, err)
+		assert.True(t, pageInfo.HasNextPage())
+
+		opts := events.ListFunc.History()[0].Arg1
+		assert.Equal(t, orgID, opts.OrgID)
+		assert.Equal(t, &database.LimitOffset{Limit: 2}, opts.LimitOffset)
+	})
+
+	t.Run("site admin", func(t *testing.T) {
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 2, SiteAdmin: true}, nil)
+
+		actions := []string{types.AuditLogActionOrgMemberAdded}
+		conn, err := schema.AuditLogEvents(ctx, &auditLogEventsArgs{First: 50, Actions: &actions, Organization: &otherOrg})
+		require.NoError(t, err)
+
+		nodes, err := conn.Nodes(ctx)
+		require.NoError(t, err)
+		require.Len(t, nodes, 2)
+
+		totalCount, err := conn.TotalCount(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), totalCount)
+
+		pageInfo, err := conn.PageInfo(ctx)
+		require.NoError(t, err)
+		assert.False(t, pageInfo.HasNextPage())
+	})
+}
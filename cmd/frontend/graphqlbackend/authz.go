@@ -28,6 +28,8 @@ type AuthzResolver interface {
 	AuthzProviderTypes(ctx context.Context) ([]string, error)
 	PermissionsSyncJobs(ctx context.Context, args ListPermissionsSyncJobsArgs) (*graphqlutil.ConnectionResolver[PermissionsSyncJobResolver], error)
 	PermissionsSyncingStats(ctx context.Context) (PermissionsSyncingStatsResolver, error)
+	PermissionsExplanation(ctx context.Context, args *PermissionsExplanationArgs) (PermissionsExplanationResolver, error)
+	SubRepositoryPermissionsExplanation(ctx context.Context, args *SubRepositoryPermissionsExplanationArgs) (SubRepositoryPermissionsExplanationResolver, error)
 
 	// RepositoryPermissionsInfo and UserPermissionsInfo are helpers functions.
 	RepositoryPermissionsInfo(ctx context.Context, repoID graphql.ID) (PermissionsInfoResolver, error)
@@ -68,6 +70,17 @@ type SubRepoPermsArgs struct {
 	}
 }
 
+type PermissionsExplanationArgs struct {
+	User       graphql.ID
+	Repository graphql.ID
+}
+
+type SubRepositoryPermissionsExplanationArgs struct {
+	User       graphql.ID
+	Repository graphql.ID
+	Path       string
+}
+
 type AuthorizedRepoArgs struct {
 	Username *string
 	Email    *string
@@ -170,3 +183,33 @@ type PermissionsSyncingStatsResolver interface {
 	UsersWithStalePermissions(ctx context.Context) (int32, error)
 	ReposWithStalePermissions(ctx context.Context) (int32, error)
 }
+
+type SubRepositoryPermissionsExplanationResolver interface {
+	Visible() bool
+	Source() string
+	Rule() *string
+}
+
+type PermissionsExplanationResolver interface {
+	Allowed() bool
+	Reason() string
+	AuthzProvider() PermissionsExplanationProviderResolver
+	Grants(ctx context.Context) ([]PermissionsExplanationGrantResolver, error)
+	LatestUserSyncJob(ctx context.Context) (PermissionsSyncJobResolver, error)
+	LatestRepositorySyncJob(ctx context.Context) (PermissionsSyncJobResolver, error)
+	PendingSyncJobs(ctx context.Context) ([]PermissionsSyncJobResolver, error)
+}
+
+type PermissionsExplanationProviderResolver interface {
+	ServiceType() string
+	ServiceID() string
+	URN() string
+}
+
+type PermissionsExplanationGrantResolver interface {
+	Source() string
+	ExternalAccountServiceType() *string
+	ExternalAccountServiceID() *string
+	CreatedAt() gqlutil.DateTime
+	UpdatedAt() gqlutil.DateTime
+}
@@ -2,14 +2,20 @@ package graphqlbackend
 
 import (
 	"context"
+	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine/recorder"
 	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 // dayCountForStats is hard-coded for now. This signifies the number of days to use for generating the stats for each routine.
@@ -25,15 +31,18 @@ type backgroundJobsArgs struct {
 }
 
 type BackgroundJobResolver struct {
+	db      database.DB
 	jobInfo recorder.JobInfo
 }
 
 type RoutineResolver struct {
+	db      database.DB
 	routine recorder.RoutineInfo
 }
 
 type RoutineInstanceResolver struct {
 	instance recorder.RoutineInstanceInfo
+	interval time.Duration
 }
 
 type RoutineRecentRunResolver struct {
@@ -49,6 +58,7 @@ type RoutineStatsResolver struct {
 // 🚨 SECURITY: When instantiating a backgroundJobConnectionResolver value, the caller MUST check
 // permissions.
 type backgroundJobConnectionResolver struct {
+	db             database.DB
 	first          *int32
 	after          string
 	recentRunCount *int32
@@ -75,6 +85,7 @@ func (r *schemaResolver) BackgroundJobs(ctx context.Context, args *backgroundJob
 	}
 
 	return &backgroundJobConnectionResolver{
+		db:             r.db,
 		first:          args.First,
 		after:          after,
 		recentRunCount: args.RecentRunCount,
@@ -96,7 +107,76 @@ func (r *schemaResolver) backgroundJobByID(ctx context.Context, id graphql.ID) (
 	if err != nil {
 		return nil, err
 	}
-	return &BackgroundJobResolver{jobInfo: item}, nil
+	return &BackgroundJobResolver{db: r.db, jobInfo: item}, nil
+}
+
+type backgroundRoutineControlArgs struct {
+	Job     graphql.ID
+	Routine string
+}
+
+func (r *schemaResolver) PauseBackgroundRoutine(ctx context.Context, args *backgroundRoutineControlArgs) (*BackgroundJobResolver, error) {
+	return r.controlBackgroundRoutine(ctx, args, recorder.ControlStatePaused, database.SecurityEventNameBackgroundRoutinePaused)
+}
+
+func (r *schemaResolver) DrainBackgroundRoutine(ctx context.Context, args *backgroundRoutineControlArgs) (*BackgroundJobResolver, error) {
+	return r.controlBackgroundRoutine(ctx, args, recorder.ControlStateDraining, database.SecurityEventNameBackgroundRoutineDrained)
+}
+
+func (r *schemaResolver) ResumeBackgroundRoutine(ctx context.Context, args *backgroundRoutineControlArgs) (*BackgroundJobResolver, error) {
+	return r.controlBackgroundRoutine(ctx, args, recorder.ControlStateRunning, database.SecurityEventNameBackgroundRoutineResumed)
+}
+
+// controlBackgroundRoutine puts the given routine in the given state and records the
+// action in the audit log.
+func (r *schemaResolver) controlBackgroundRoutine(ctx context.Context, args *backgroundRoutineControlArgs, state recorder.ControlState, eventName database.SecurityEventName) (*BackgroundJobResolver, error) {
+	// 🚨 SECURITY: Only site admins may control background routines.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	var jobName string
+	if err := relay.UnmarshalSpec(args.Job, &jobName); err != nil {
+		return nil, err
+	}
+	c := recorder.GetCache()
+	job, err := recorder.GetBackgroundJobInfo(c, jobName, defaultRecentRunCount, dayCountForStats)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, routine := range job.Routines {
+		found = found || routine.Name == args.Routine
+	}
+	if !found {
+		return nil, errors.Newf("job %q has no routine named %q", jobName, args.Routine)
+	}
+
+	uid := actor.FromContext(ctx).UID
+	if err := recorder.SetRoutineControl(c, jobName, args.Routine, state, uid); err != nil {
+		return nil, err
+	}
+	logBackgroundRoutineControl(ctx, r.db, eventName, uid, jobName, args.Routine)
+
+	return r.backgroundJobByID(ctx, args.Job)
+}
+
+func logBackgroundRoutineControl(ctx context.Context, db database.DB, name database.SecurityEventName, by int32, jobName, routineName string) {
+	args, _ := json.Marshal(struct {
+		Job     string `json:"job"`
+		Routine string `json:"routine"`
+	}{
+		Job:     jobName,
+		Routine: routineName,
+	})
+
+	db.SecurityEventLogs().LogEvent(ctx, &database.SecurityEvent{
+		Name:      name,
+		UserID:    uint32(by),
+		Argument:  args,
+		Source:    "BACKEND",
+		Timestamp: time.Now(),
+	})
 }
 
 func (r *backgroundJobConnectionResolver) Nodes(context.Context) ([]*BackgroundJobResolver, error) {
@@ -146,7 +226,7 @@ func (r *backgroundJobConnectionResolver) compute() ([]*BackgroundJobResolver, e
 
 		resolvers := make([]*BackgroundJobResolver, 0, len(jobInfos))
 		for _, jobInfo := range jobInfos {
-			resolvers = append(resolvers, &BackgroundJobResolver{jobInfo: jobInfo})
+			resolvers = append(resolvers, &BackgroundJobResolver{db: r.db, jobInfo: jobInfo})
 		}
 
 		r.resolvers, r.err = resolvers, nil
@@ -163,7 +243,7 @@ func (r *BackgroundJobResolver) Name() string { return r.jobInfo.Name }
 func (r *BackgroundJobResolver) Routines() []*RoutineResolver {
 	resolvers := make([]*RoutineResolver, 0, len(r.jobInfo.Routines))
 	for _, routine := range r.jobInfo.Routines {
-		resolvers = append(resolvers, &RoutineResolver{routine: routine})
+		resolvers = append(resolvers, &RoutineResolver{db: r.db, routine: routine})
 	}
 	return resolvers
 }
@@ -184,7 +264,10 @@ func (r *RoutineResolver) IntervalMs() *int32 {
 func (r *RoutineResolver) Instances() []*RoutineInstanceResolver {
 	resolvers := make([]*RoutineInstanceResolver, 0, len(r.routine.Instances))
 	for _, routineInstance := range r.routine.Instances {
-		resolvers = append(resolvers, &RoutineInstanceResolver{instance: routineInstance})
+		resolvers = append(resolvers, &RoutineInstanceResolver{
+			instance: routineInstance,
+			interval: time.Duration(r.routine.IntervalMs) * time.Millisecond,
+		})
 	}
 	return resolvers
 }
@@ -199,6 +282,33 @@ func (r *RoutineInstanceResolver) LastStoppedAt() *gqlutil.DateTime {
 	return gqlutil.DateTimeOrNil(r.instance.LastStoppedAt)
 }
 
+func (r *RoutineInstanceResolver) LastHeartbeatAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.instance.LastHeartbeatAt)
+}
+
+func (r *RoutineInstanceResolver) CurrentRunStartedAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.instance.CurrentRunStartedAt())
+}
+
+func (r *RoutineInstanceResolver) LastError() *RoutineRecentRunResolver {
+	if r.instance.LastError == nil {
+		return nil
+	}
+	return &RoutineRecentRunResolver{recentRun: *r.instance.LastError}
+}
+
+func (r *RoutineInstanceResolver) Stuck() bool {
+	return r.instance.Stuck(time.Now(), r.interval)
+}
+
+func (r *RoutineInstanceResolver) InFlight() *int32 {
+	if r.instance.InFlight == nil {
+		return nil
+	}
+	inFlight := int32(*r.instance.InFlight)
+	return &inFlight
+}
+
 func (r *RoutineResolver) RecentRuns() []*RoutineRecentRunResolver {
 	resolvers := make([]*RoutineRecentRunResolver, 0, len(r.routine.RecentRuns))
 	for _, recentRun := range r.routine.RecentRuns {
@@ -226,6 +336,26 @@ func (r *RoutineResolver) Stats() *RoutineStatsResolver {
 	return &RoutineStatsResolver{stats: r.routine.Stats}
 }
 
+func (r *RoutineResolver) ControlState() recorder.ControlState { return r.routine.ControlState() }
+
+func (r *RoutineResolver) ControlledBy(ctx context.Context) (*UserResolver, error) {
+	if r.routine.Control == nil {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, r.db, r.routine.Control.UserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *RoutineResolver) ControlledAt() *gqlutil.DateTime {
+	if r.routine.Control == nil {
+		return nil
+	}
+	return &gqlutil.DateTime{Time: r.routine.Control.At}
+}
+
 func (r *RoutineStatsResolver) Since() *gqlutil.DateTime {
 	if r.stats.Since.IsZero() {
 		return nil
@@ -44,6 +44,32 @@ type CloseBatchChangeArgs struct {
 	CloseChangesets bool
 }
 
+type SetBatchChangeRolloutPolicyArgs struct {
+	BatchChange          graphql.ID
+	MaxChangesets        int32
+	WindowHours          int32
+	PrioritizeByActivity bool
+	MinMergeRate         *float64
+	MaxCIFailureRate     *float64
+}
+
+type DeleteBatchChangeRolloutPolicyArgs struct {
+	BatchChange graphql.ID
+}
+
+type ResumeBatchChangeRolloutArgs struct {
+	BatchChange graphql.ID
+}
+
+type SetBatchChangeApproversArgs struct {
+	BatchChange graphql.ID
+	Approvers   []graphql.ID
+}
+
+type ApproveBatchSpecArgs struct {
+	BatchSpec graphql.ID
+}
+
 type MoveBatchChangeArgs struct {
 	BatchChange  graphql.ID
 	NewName      *string
@@ -269,6 +295,11 @@ type BatchChangesResolver interface {
 
 	ApplyBatchChange(ctx context.Context, args *ApplyBatchChangeArgs) (BatchChangeResolver, error)
 	CloseBatchChange(ctx context.Context, args *CloseBatchChangeArgs) (BatchChangeResolver, error)
+	SetBatchChangeRolloutPolicy(ctx context.Context, args *SetBatchChangeRolloutPolicyArgs) (BatchChangeResolver, error)
+	DeleteBatchChangeRolloutPolicy(ctx context.Context, args *DeleteBatchChangeRolloutPolicyArgs) (BatchChangeResolver, error)
+	ResumeBatchChangeRollout(ctx context.Context, args *ResumeBatchChangeRolloutArgs) (BatchChangeResolver, error)
+	SetBatchChangeApprovers(ctx context.Context, args *SetBatchChangeApproversArgs) (BatchChangeResolver, error)
+	ApproveBatchSpec(ctx context.Context, args *ApproveBatchSpecArgs) (BatchSpecResolver, error)
 	MoveBatchChange(ctx context.Context, args *MoveBatchChangeArgs) (BatchChangeResolver, error)
 	DeleteBatchChange(ctx context.Context, args *DeleteBatchChangeArgs) (*EmptyResponse, error)
 	CreateBatchChangesCredential(ctx context.Context, args *CreateBatchChangesCredentialArgs) (BatchChangesCredentialResolver, error)
@@ -374,9 +405,16 @@ type BatchSpecResolver interface {
 
 	Source() string
 
+	Approvals(ctx context.Context) ([]BatchSpecApprovalResolver, error)
+
 	Files(ctx context.Context, args *ListBatchSpecWorkspaceFilesArgs) (BatchSpecWorkspaceFileConnectionResolver, error)
 }
 
+type BatchSpecApprovalResolver interface {
+	Approver(ctx context.Context) (*UserResolver, error)
+	CreatedAt() gqlutil.DateTime
+}
+
 type BatchChangeDescriptionResolver interface {
 	Name() string
 	Description() string
@@ -667,10 +705,24 @@ type BatchChangeResolver interface {
 	ClosedAt() *gqlutil.DateTime
 	DiffStat(ctx context.Context) (*DiffStat, error)
 	CurrentSpec(ctx context.Context) (BatchSpecResolver, error)
+	RolloutPolicy(ctx context.Context) (BatchChangeRolloutPolicyResolver, error)
+	Approvers(ctx context.Context) ([]*UserResolver, error)
 	BulkOperations(ctx context.Context, args *ListBatchChangeBulkOperationArgs) (BulkOperationConnectionResolver, error)
 	BatchSpecs(ctx context.Context, args *ListBatchSpecArgs) (BatchSpecConnectionResolver, error)
 }
 
+type BatchChangeRolloutPolicyResolver interface {
+	MaxChangesets() int32
+	WindowHours() int32
+	PrioritizeByActivity() bool
+	MinMergeRate() *float64
+	MaxCIFailureRate() *float64
+	WindowStartedAt() *gqlutil.DateTime
+	PublishedInWindow() int32
+	PausedAt() *gqlutil.DateTime
+	PauseReason() *string
+}
+
 type BatchChangesConnectionResolver interface {
 	Nodes(ctx context.Context) ([]BatchChangeResolver, error)
 	TotalCount(ctx context.Context) (int32, error)
@@ -0,0 +1,127 @@
+package graphqlbackend
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type cloneQueueEntryResolver struct {
+	gitserver string
+	job       protocol.CloneQueueJob
+	repo      *RepositoryResolver
+}
+
+func (r *cloneQueueEntryResolver) Name() string { return string(r.job.Repo) }
+
+func (r *cloneQueueEntryResolver) Repository() *RepositoryResolver { return r.repo }
+
+func (r *cloneQueueEntryResolver) Gitserver() string { return r.gitserver }
+
+func (r *cloneQueueEntryResolver) Position() int32 { return int32(r.job.Position) }
+
+func (r *cloneQueueEntryResolver) Priority() string { return strings.ToUpper(r.job.Priority) }
+
+func (r *cloneQueueEntryResolver) QueuedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.job.QueuedAt}
+}
+
+func (r *schemaResolver) CloneQueue(ctx context.Context) ([]*cloneQueueEntryResolver, error) {
+	// 🚨 SECURITY: Only site admins can view the clone queue.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	// We still return the clone queues of the gitservers we could reach.
+	queues, err := r.gitserverClient.CloneQueue(ctx)
+	if err != nil {
+		r.logger.Warn("failed to fetch the clone queue of some gitservers", log.Error(err))
+	}
+
+	var entries []*cloneQueueEntryResolver
+	var names []string
+	for addr, jobs := range queues {
+		for _, job := range jobs {
+			entries = append(entries, &cloneQueueEntryResolver{gitserver: addr, job: job})
+			names = append(names, string(job.Repo))
+		}
+	}
+	if len(entries) == 0 {
+		return entries, nil
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].gitserver != entries[j].gitserver {
+			return entries[i].gitserver < entries[j].gitserver
+		}
+		return entries[i].job.Position < entries[j].job.Position
+	})
+
+	repos, err := r.db.Repos().List(ctx, database.ReposListOptions{Names: names})
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[api.RepoName]*types.Repo, len(repos))
+	for _, repo := range repos {
+		byName[repo.Name] = repo
+	}
+	for _, entry := range entries {
+		if repo, ok := byName[entry.job.Repo]; ok {
+			entry.repo = NewRepositoryResolver(r.db, r.gitserverClient, repo)
+		}
+	}
+
+	return entries, nil
+}
+
+func (r *schemaResolver) BoostRepositoryClone(ctx context.Context, args *struct {
+	Repository graphql.ID
+},
+) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can reorder the clone queue.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	repo, err := r.repositoryByID(ctx, args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	boosted, err := r.gitserverClient.BoostClone(ctx, repo.RepoName())
+	if err != nil {
+		return nil, err
+	}
+	if !boosted {
+		return nil, errors.Newf("repository %s is not waiting in the clone queue", repo.RepoName())
+	}
+
+	a := actor.FromContext(ctx)
+	eventArgs, _ := json.Marshal(struct {
+		RepoID   api.RepoID   `json:"repoID"`
+		RepoName api.RepoName `json:"repoName"`
+	}{repo.IDInt32(), repo.RepoName()})
+	r.db.SecurityEventLogs().LogEvent(ctx, &database.SecurityEvent{
+		Name:      database.SecurityEventNameRepoCloneBoosted,
+		UserID:    uint32(a.UID),
+		Argument:  eventArgs,
+		Source:    "BACKEND",
+		Timestamp: time.Now(),
+	})
+
+	return &EmptyResponse{}, nil
+}
@@ -0,0 +1,91 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestCloneQueue(t *testing.T) {
+	ctx := context.Background()
+
+	logger := logtest.Scoped(t)
+	db := database.NewMockDBFrom(database.NewDB(logger, dbtest.NewDB(logger, t)))
+
+	users := database.NewMockUserStore()
+	db.UsersFunc.SetDefaultReturn(users)
+
+	err := db.Repos().Create(ctx, &types.Repo{Name: "github.com/foo/queued"})
+	require.NoError(t, err)
+	repo, err := db.Repos().GetByName(ctx, "github.com/foo/queued")
+	require.NoError(t, err)
+
+	queuedAt := time.Now()
+	gsClient := gitserver.NewMockClient()
+	gsClient.CloneQueueFunc.SetDefaultReturn(map[string][]protocol.CloneQueueJob{
+		"gitserver-1": {
+			{Repo: "github.com/foo/deleted", Priority: "boosted", Position: 1, QueuedAt: queuedAt},
+			{Repo: repo.Name, Priority: "low", Position: 2, QueuedAt: queuedAt},
+		},
+		"gitserver-0": {
+			{Repo: "github.com/foo/other", Priority: "high", Position: 1, QueuedAt: queuedAt},
+		},
+	}, nil)
+	gsClient.BoostCloneFunc.SetDefaultHook(func(_ context.Context, name api.RepoName) (bool, error) {
+		return name == repo.Name, nil
+	})
+
+	schema := newSchemaResolver(db, gsClient, jobutil.NewUnimplementedEnterpriseJobs())
+	boostArgs := &struct {
+		Repository graphql.ID
+	}{
+		Repository: MarshalRepositoryID(repo.ID),
+	}
+
+	t.Run("non-admin", func(t *testing.T) {
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{}, nil)
+
+		_, err := schema.CloneQueue(ctx)
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+		_, err = schema.BoostRepositoryClone(ctx, boostArgs)
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+	})
+
+	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{SiteAdmin: true}, nil)
+
+	t.Run("list", func(t *testing.T) {
+		entries, err := schema.CloneQueue(ctx)
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+
+		require.Equal(t, "gitserver-0", entries[0].Gitserver())
+		require.Equal(t, "HIGH", entries[0].Priority())
+		require.Equal(t, "github.com/foo/deleted", entries[1].Name())
+		require.Nil(t, entries[1].Repository())
+		require.Equal(t, "BOOSTED", entries[1].Priority())
+		require.Equal(t, int32(2), entries[2].Position())
+		require.Equal(t, repo.ID, entries[2].Repository().IDInt32())
+	})
+
+	t.Run("boost", func(t *testing.T) {
+		_, err := schema.BoostRepositoryClone(ctx, boostArgs)
+		require.NoError(t, err)
+
+		gsClient.BoostCloneFunc.SetDefaultReturn(false, nil)
+		_, err = schema.BoostRepositoryClone(ctx, boostArgs)
+		require.Error(t, err)
+	})
+}
@@ -98,7 +98,24 @@ type MonitorWebhookResolver interface {
 	Enabled() bool
 	IncludeResults() bool
 	URL() string
+	PayloadTemplate() *string
+	HasSigningSecret() bool
 	Events(ctx context.Context, args *ListEventsArgs) (MonitorActionEventConnectionResolver, error)
+	DeadLetters(ctx context.Context, args *ListDeadLettersArgs) (MonitorWebhookDeadLetterConnectionResolver, error)
+}
+
+type MonitorWebhookDeadLetterConnectionResolver interface {
+	Nodes() []MonitorWebhookDeadLetterResolver
+	TotalCount() int32
+	PageInfo() *graphqlutil.PageInfo
+}
+
+type MonitorWebhookDeadLetterResolver interface {
+	ID() graphql.ID
+	Payload() string
+	FailureMessage() string
+	Attempts() int32
+	CreatedAt() gqlutil.DateTime
 }
 
 type MonitorSlackWebhookResolver interface {
@@ -137,6 +154,11 @@ type ListEventsArgs struct {
 	After *string
 }
 
+type ListDeadLettersArgs struct {
+	First int32
+	After *string
+}
+
 type ListMonitorsArgs struct {
 	First int32
 	After *string
@@ -177,9 +199,11 @@ type CreateActionEmailArgs struct {
 }
 
 type CreateActionWebhookArgs struct {
-	Enabled        bool
-	IncludeResults bool
-	URL            string
+	Enabled         bool
+	IncludeResults  bool
+	URL             string
+	PayloadTemplate *string
+	SigningSecret   *string
 }
 
 type CreateActionSlackWebhookArgs struct {
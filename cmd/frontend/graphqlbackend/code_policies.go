@@ -0,0 +1,351 @@
+package graphqlbackend
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/codepolicies"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// maxCodePolicyViolations is the maximum number of violations returned by
+// codePolicyViolations.
+const maxCodePolicyViolations = 1000
+
+type codePolicyResolver struct {
+	db     database.DB
+	policy *codepolicies.Policy
+}
+
+func marshalCodePolicyID(id int32) graphql.ID { return relay.MarshalID("CodePolicy", id) }
+
+func unmarshalCodePolicyID(id graphql.ID) (policyID int32, err error) {
+	err = relay.UnmarshalSpec(id, &policyID)
+	return
+}
+
+func (r *codePolicyResolver) ID() graphql.ID { return marshalCodePolicyID(r.policy.ID) }
+
+func (r *codePolicyResolver) Name() string { return r.policy.Name }
+
+func (r *codePolicyResolver) Description() string { return r.policy.Description }
+
+func (r *codePolicyResolver) Query() string { return r.policy.Query }
+
+func (r *codePolicyResolver) RepositoryQuery() string { return r.policy.RepoQuery }
+
+func (r *codePolicyResolver) WebhookURL() *string { return r.policy.WebhookURL }
+
+func (r *codePolicyResolver) Enabled() bool { return r.policy.Enabled }
+
+func (r *codePolicyResolver) IntervalMinutes() int32 {
+	return int32(r.policy.Interval / time.Minute)
+}
+
+func (r *codePolicyResolver) Creator(ctx context.Context) (*UserResolver, error) {
+	if r.policy.CreatorID == nil {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, r.db, *r.policy.CreatorID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *codePolicyResolver) NextRunAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.policy.NextRunAt}
+}
+
+func (r *codePolicyResolver) LastRunAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.policy.LastRunAt)
+}
+
+func (r *codePolicyResolver) LastError() *string { return r.policy.LastError }
+
+func (r *codePolicyResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.policy.CreatedAt}
+}
+
+func (r *codePolicyResolver) UpdatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.policy.UpdatedAt}
+}
+
+func (r *codePolicyResolver) OpenViolationCount(ctx context.Context) (int32, error) {
+	count, err := codepolicies.NewStore(r.db).CountViolations(ctx, codepolicies.ListViolationsOpts{
+		PolicyID: r.policy.ID,
+		State:    codepolicies.ViolationStateOpen,
+	})
+	return int32(count), err
+}
+
+type codePolicyViolationResolver struct {
+	db        database.DB
+	violation *codepolicies.Violation
+}
+
+func (r *codePolicyViolationResolver) ID() graphql.ID {
+	return relay.MarshalID("CodePolicyViolation", r.violation.ID)
+}
+
+func (r *codePolicyViolationResolver) Policy(ctx context.Context) (*codePolicyResolver, error) {
+	policy, err := codepolicies.NewStore(r.db).GetPolicy(ctx, r.violation.PolicyID)
+	if err != nil {
+		return nil, err
+	}
+	return &codePolicyResolver{db: r.db, policy: policy}, nil
+}
+
+func (r *codePolicyViolationResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	repo, err := r.db.Repos().Get(ctx, r.violation.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	return NewRepositoryResolver(r.db, gitserver.NewClient(), repo), nil
+}
+
+func (r *codePolicyViolationResolver) Path() *string { return pointers.NonZeroPtr(r.violation.Path) }
+
+func (r *codePolicyViolationResolver) Commit() *string {
+	return pointers.NonZeroPtr(r.violation.Commit)
+}
+
+func (r *codePolicyViolationResolver) LineNumber() *int32 {
+	return pointers.NonZeroPtr(r.violation.LineNumber)
+}
+
+func (r *codePolicyViolationResolver) Preview() string { return r.violation.Preview }
+
+func (r *codePolicyViolationResolver) State() string {
+	return strings.ToUpper(string(r.violation.State))
+}
+
+func (r *codePolicyViolationResolver) URL() string { return r.violation.URL().String() }
+
+func (r *codePolicyViolationResolver) FirstSeenAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.violation.FirstSeenAt}
+}
+
+func (r *codePolicyViolationResolver) LastSeenAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.violation.LastSeenAt}
+}
+
+func (r *codePolicyViolationResolver) ResolvedAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.violation.ResolvedAt)
+}
+
+func (r *codePolicyViolationResolver) ResolvedBy(ctx context.Context) (*UserResolver, error) {
+	if r.violation.ResolvedByID == nil {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, r.db, *r.violation.ResolvedByID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+type codePolicyViolationConnectionResolver struct {
+	db         database.DB
+	violations []*codepolicies.Violation
+	totalCount int
+}
+
+func (r *codePolicyViolationConnectionResolver) Nodes() []*codePolicyViolationResolver {
+	resolvers := make([]*codePolicyViolationResolver, 0, len(r.violations))
+	for _, v := range r.violations {
+		resolvers = append(resolvers, &codePolicyViolationResolver{db: r.db, violation: v})
+	}
+	return resolvers
+}
+
+func (r *codePolicyViolationConnectionResolver) TotalCount() int32 { return int32(r.totalCount) }
+
+func (r *schemaResolver) CodePolicies(ctx context.Context) ([]*codePolicyResolver, error) {
+	// 🚨 SECURITY: Only site admins can view code policies.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	policies, err := codepolicies.NewStore(r.db).ListPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*codePolicyResolver, 0, len(policies))
+	for _, p := range policies {
+		resolvers = append(resolvers, &codePolicyResolver{db: r.db, policy: p})
+	}
+	return resolvers, nil
+}
+
+func (r *schemaResolver) CodePolicyViolations(ctx context.Context, args *struct {
+	Policy     *graphql.ID
+	Repository *graphql.ID
+	State      *string
+	First      int32
+}) (*codePolicyViolationConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins can view code policy violations, as they
+	// are found in repositories that the current user may not have access to.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+	if args.First < 1 || args.First > maxCodePolicyViolations {
+		return nil, errors.Newf("first must be between 1 and %d", maxCodePolicyViolations)
+	}
+
+	opts := codepolicies.ListViolationsOpts{Limit: int(args.First)}
+	if args.Policy != nil {
+		id, err := unmarshalCodePolicyID(*args.Policy)
+		if err != nil {
+			return nil, err
+		}
+		opts.PolicyID = id
+	}
+	if args.Repository != nil {
+		id, err := UnmarshalRepositoryID(*args.Repository)
+		if err != nil {
+			return nil, err
+		}
+		opts.RepoID = id
+	}
+	if args.State != nil {
+		opts.State = codepolicies.ViolationState(strings.ToLower(*args.State))
+	}
+
+	store := codepolicies.NewStore(r.db)
+	violations, err := store.ListViolations(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	totalCount, err := store.CountViolations(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &codePolicyViolationConnectionResolver{db: r.db, violations: violations, totalCount: totalCount}, nil
+}
+
+type codePolicyInput struct {
+	Name            string
+	Description     string
+	Query           string
+	RepositoryQuery string
+	WebhookURL      *string
+	Enabled         bool
+	IntervalMinutes int32
+}
+
+func (in codePolicyInput) toPolicy() (*codepolicies.Policy, error) {
+	if in.WebhookURL != nil && *in.WebhookURL != "" {
+		if parsed, err := url.Parse(*in.WebhookURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return nil, errors.Errorf("invalid webhook URL %q", *in.WebhookURL)
+		}
+	}
+	if in.IntervalMinutes < 1 {
+		return nil, errors.New("intervalMinutes must be at least 1")
+	}
+	return &codepolicies.Policy{
+		Name:        strings.TrimSpace(in.Name),
+		Description: in.Description,
+		Query:       strings.TrimSpace(in.Query),
+		RepoQuery:   strings.TrimSpace(in.RepositoryQuery),
+		WebhookURL:  in.WebhookURL,
+		Enabled:     in.Enabled,
+		Interval:    time.Duration(in.IntervalMinutes) * time.Minute,
+	}, nil
+}
+
+func (r *schemaResolver) CreateCodePolicy(ctx context.Context, args *struct {
+	Input codePolicyInput
+}) (*codePolicyResolver, error) {
+	// 🚨 SECURITY: Only site admins can create code policies. The policy query
+	// runs as its creator.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	p, err := args.Input.toPolicy()
+	if err != nil {
+		return nil, err
+	}
+	uid := actor.FromContext(ctx).UID
+	p.CreatorID = &uid
+
+	policy, err := codepolicies.NewStore(r.db).CreatePolicy(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return &codePolicyResolver{db: r.db, policy: policy}, nil
+}
+
+func (r *schemaResolver) UpdateCodePolicy(ctx context.Context, args *struct {
+	ID    graphql.ID
+	Input codePolicyInput
+}) (*codePolicyResolver, error) {
+	// 🚨 SECURITY: Only site admins can update code policies.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	p, err := args.Input.toPolicy()
+	if err != nil {
+		return nil, err
+	}
+	if p.ID, err = unmarshalCodePolicyID(args.ID); err != nil {
+		return nil, err
+	}
+
+	policy, err := codepolicies.NewStore(r.db).UpdatePolicy(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return &codePolicyResolver{db: r.db, policy: policy}, nil
+}
+
+func (r *schemaResolver) DeleteCodePolicy(ctx context.Context, args *struct {
+	ID graphql.ID
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can delete code policies.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	id, err := unmarshalCodePolicyID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := codepolicies.NewStore(r.db).DeletePolicy(ctx, id); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *schemaResolver) ResolveCodePolicyViolation(ctx context.Context, args *struct {
+	ID graphql.ID
+}) (*codePolicyViolationResolver, error) {
+	// 🚨 SECURITY: Only site admins can resolve code policy violations.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	var id int32
+	if err := relay.UnmarshalSpec(args.ID, &id); err != nil {
+		return nil, err
+	}
+	violation, err := codepolicies.NewStore(r.db).ResolveViolation(ctx, id, actor.FromContext(ctx).UID)
+	if err != nil {
+		return nil, err
+	}
+	return &codePolicyViolationResolver{db: r.db, violation: violation}, nil
+}
@@ -0,0 +1,67 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/codepolicies"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestCodePolicyInput(t *testing.T) {
+	webhookURL := "file:///etc/passwd"
+	_, err := codePolicyInput{Name: "no-md5", Query: "md5.New", IntervalMinutes: 60, WebhookURL: &webhookURL}.toPolicy()
+	require.Error(t, err)
+
+	_, err = codePolicyInput{Name: "no-md5", Query: "md5.New"}.toPolicy()
+	require.Error(t, err)
+
+	p, err := codePolicyInput{Name: " no-md5 ", Query: "md5.New", RepositoryQuery: "repo:^r$ ", IntervalMinutes: 30}.toPolicy()
+	require.NoError(t, err)
+	require.Equal(t, "no-md5", p.Name)
+	require.Equal(t, "repo:^r$", p.RepoQuery)
+	require.Equal(t, 30*time.Minute, p.Interval)
+}
+
+func TestCodePoliciesRequireSiteAdmin(t *testing.T) {
+	users := database.NewMockUserStore()
+	users.GetByCurrentUserFunc.SetDefaultReturn(&types.User{ID: 1}, nil)
+	db := database.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+
+	r := &schemaResolver{db: db}
+	ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+
+	_, err := r.CodePolicies(ctx)
+	require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+
+	_, err = r.CreateCodePolicy(ctx, &struct{ Input codePolicyInput }{
+		Input: codePolicyInput{Name: "no-md5", Query: "md5.New", IntervalMinutes: 60},
+	})
+	require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+}
+
+func TestCodePolicyViolationResolver(t *testing.T) {
+	r := &codePolicyViolationResolver{violation: &codepolicies.Violation{
+		RepoName:   "r",
+		Path:       "a.go",
+		Commit:     "c1",
+		LineNumber: 10,
+		State:      codepolicies.ViolationStateOpen,
+	}}
+	require.Equal(t, "OPEN", r.State())
+	require.Equal(t, "/r@c1/-/blob/a.go?L10", r.URL())
+	require.Equal(t, int32(10), *r.LineNumber())
+
+	r.violation.Path = ""
+	r.violation.LineNumber = 0
+	require.Nil(t, r.Path())
+	require.Nil(t, r.LineNumber())
+	require.Equal(t, "/r/-/commit/c1", r.URL())
+}
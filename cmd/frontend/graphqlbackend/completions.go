@@ -1,9 +1,18 @@
 package graphqlbackend
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+)
 
 type CompletionsResolver interface {
 	Completions(ctx context.Context, args CompletionsArgs) (string, error)
+	CodyPromptLogs(ctx context.Context, args *CodyPromptLogsArgs) (CodyPromptLogConnectionResolver, error)
 }
 
 type CompletionsArgs struct {
@@ -23,3 +32,33 @@ type CompletionsInput struct {
 	TopK              int32     `json:"topK"`
 	TopP              int32     `json:"topP"`
 }
+
+type CodyPromptLogsArgs struct {
+	graphqlutil.ConnectionArgs
+	After *string
+	User  *graphql.ID
+	Since *time.Time
+	Until *time.Time
+}
+
+type CodyPromptLogConnectionResolver interface {
+	Nodes(ctx context.Context) ([]CodyPromptLogResolver, error)
+	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
+}
+
+type CodyPromptLogResolver interface {
+	ID() graphql.ID
+	User(ctx context.Context) (*UserResolver, error)
+	AnonymousUserID() *string
+	Intent() string
+	Provider() string
+	Model() string
+	Prompt(ctx context.Context) ([]CodyPromptMessageResolver, error)
+	Response(ctx context.Context) (string, error)
+	CreatedAt() gqlutil.DateTime
+}
+
+type CodyPromptMessageResolver interface {
+	Speaker() string
+	Text() string
+}
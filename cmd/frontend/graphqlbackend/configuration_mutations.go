@@ -0,0 +1,487 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type applyConfigurationMutationsArgs struct {
+	Mutations []*configurationMutationInput
+	DryRun    bool
+}
+
+type configurationMutationInput struct {
+	OverwriteSettings       *overwriteSettingsMutationInput
+	EditSettings            *editSettingsMutationInput
+	UpdateSiteConfiguration *updateSiteConfigurationMutationInput
+	AddExternalService      *addExternalServiceInput
+	UpdateExternalService   *updateExternalServiceMutationInput
+	DeleteExternalService   *deleteExternalServiceMutationInput
+}
+
+type overwriteSettingsMutationInput struct {
+	Subject  graphql.ID
+	LastID   *int32
+	Contents string
+}
+
+type editSettingsMutationInput struct {
+	Subject graphql.ID
+	LastID  *int32
+	Edit    *settingsEdit
+}
+
+type updateSiteConfigurationMutationInput struct {
+	LastID int32
+	Input  string
+}
+
+type updateExternalServiceMutationInput struct {
+	ID            graphql.ID
+	DisplayName   *string
+	Config        *string
+	LastUpdatedAt *gqlutil.DateTime
+}
+
+type deleteExternalServiceMutationInput struct {
+	ID            graphql.ID
+	LastUpdatedAt *gqlutil.DateTime
+}
+
+// errConfigurationMutationsDryRun rolls back the transaction of a dry run.
+var errConfigurationMutationsDryRun = errors.New("dry run")
+
+// ApplyConfigurationMutations applies a list of settings, site configuration, and
+// external service mutations in a single transaction.
+func (r *schemaResolver) ApplyConfigurationMutations(ctx context.Context, args *applyConfigurationMutationsArgs) (*applyConfigurationMutationsResultResolver, error) {
+	// 🚨 SECURITY: Only site admins may apply configuration mutations, as they
+	// include mutations of the site configuration and of external services.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	batch := &configurationMutationBatch{
+		resolver:         r,
+		subjects:         map[graphql.ID]*settingsSubjectResolver{},
+		settingsVersions: map[graphql.ID]batchVersion{},
+		extsvcUpdatedAt:  map[int64]time.Time{},
+	}
+	for i, mutation := range args.Mutations {
+		if err := batch.prepare(ctx, mutation); err != nil {
+			return nil, errors.Wrapf(err, "mutation %d", i)
+		}
+	}
+
+	// Deleting an external service waits for its running sync jobs to be
+	// canceled, which they only notice outside the transaction.
+	for _, id := range batch.deletedExternalServices {
+		if err := r.db.ExternalServices().CancelSyncJob(ctx, database.ExternalServicesCancelSyncJobOptions{ExternalServiceID: id}); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]*configurationMutationResultResolver, len(args.Mutations))
+	err := r.db.WithTransact(ctx, func(tx database.DB) error {
+		for i, mutation := range args.Mutations {
+			result, err := batch.apply(ctx, tx, mutation)
+			if err != nil {
+				return errors.Wrapf(err, "mutation %d", i)
+			}
+			results[i] = result
+		}
+
+		if args.DryRun {
+			return errConfigurationMutationsDryRun
+		}
+		return nil
+	})
+	if args.DryRun && errors.Is(err, errConfigurationMutationsDryRun) {
+		// The external services of a dry run do not exist.
+		for _, result := range results {
+			result.externalService = nil
+		}
+		return &applyConfigurationMutationsResultResolver{results: results, dryRun: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &applyConfigurationMutationsResultResolver{results: results}
+	if batch.siteConfigWritten {
+		server := globals.ConfigurationServerFrontendOnly
+		server.Reload()
+		res.restartRequired = server.NeedServerRestart()
+	}
+
+	// Validate the added and updated external services once they are committed,
+	// as the sync happens in repo-updater.
+	externalServices := backend.NewExternalServices(r.logger, r.db, r.repoupdaterClient)
+	for _, result := range results {
+		if result.externalService == nil || !result.sync {
+			continue
+		}
+		if err := externalServices.SyncExternalService(ctx, result.externalService.externalService, syncExternalServiceTimeout); err != nil {
+			warning := fmt.Sprintf("External service saved, but we encountered a problem while validating the external service: %s", err)
+			result.warning = &warning
+		}
+	}
+
+	return res, nil
+}
+
+// configurationMutationBatch holds the state of the mutations applied by
+// ApplyConfigurationMutations.
+type configurationMutationBatch struct {
+	resolver *schemaResolver
+
+	subjects                map[graphql.ID]*settingsSubjectResolver
+	deletedExternalServices []int64
+
+	// settingsVersions and siteConfigVersion track the versions written by the
+	// batch, so that the lastID of every mutation refers to the version before
+	// the batch.
+	settingsVersions  map[graphql.ID]batchVersion
+	siteConfigVersion *batchVersion
+	siteConfigWritten bool
+	// extsvcUpdatedAt holds the time external services mutated by the batch were
+	// last updated before the batch.
+	extsvcUpdatedAt map[int64]time.Time
+}
+
+type batchVersion struct {
+	before *int32
+	after  int32
+}
+
+// prepare validates the given mutation and resolves its settings subject
+// before the transaction is started.
+func (b *configurationMutationBatch) prepare(ctx context.Context, mutation *configurationMutationInput) error {
+	set := 0
+	for _, isSet := range []bool{
+		mutation.OverwriteSettings != nil,
+		mutation.EditSettings != nil,
+		mutation.UpdateSiteConfiguration != nil,
+		mutation.AddExternalService != nil,
+		mutation.UpdateExternalService != nil,
+		mutation.DeleteExternalService != nil,
+	} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("exactly one mutation must be set")
+	}
+
+	switch {
+	case mutation.OverwriteSettings != nil:
+		return b.resolveSubject(ctx, mutation.OverwriteSettings.Subject)
+
+	case mutation.EditSettings != nil:
+		return b.resolveSubject(ctx, mutation.EditSettings.Subject)
+
+	case mutation.UpdateSiteConfiguration != nil:
+		if !canUpdateSiteConfiguration() {
+			return errors.New("updating site configuration not allowed when using SITE_CONFIG_FILE")
+		}
+		if strings.TrimSpace(mutation.UpdateSiteConfiguration.Input) == "" {
+			return errors.Errorf("blank site configuration is invalid (you can clear the site configuration by entering an empty JSON object: {})")
+		}
+		return nil
+
+	case mutation.DeleteExternalService != nil:
+		id, err := UnmarshalExternalServiceID(mutation.DeleteExternalService.ID)
+		if err != nil {
+			return err
+		}
+		b.deletedExternalServices = append(b.deletedExternalServices, id)
+		return externalServicesWritable()
+
+	default:
+		return externalServicesWritable()
+	}
+}
+
+func (b *configurationMutationBatch) resolveSubject(ctx context.Context, id graphql.ID) error {
+	if _, ok := b.subjects[id]; ok {
+		return nil
+	}
+
+	n, err := b.resolver.nodeByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	subject, err := settingsSubjectForNode(ctx, n)
+	if err != nil {
+		return err
+	}
+
+	// 🚨 SECURITY: Check whether the viewer can administer this subject (which is equivalent to
+	// being able to mutate its settings).
+	if canAdmin, err := subject.ViewerCanAdminister(ctx); err != nil {
+		return err
+	} else if !canAdmin {
+		return errors.New("viewer is not allowed to edit these settings")
+	}
+
+	b.subjects[id] = subject
+	return nil
+}
+
+func (b *configurationMutationBatch) apply(ctx context.Context, tx database.DB, mutation *configurationMutationInput) (*configurationMutationResultResolver, error) {
+	switch {
+	case mutation.OverwriteSettings != nil:
+		input := mutation.OverwriteSettings
+		return b.mutateSettings(tx, input.Subject, input.LastID, func(m *settingsMutation) (int32, error) {
+			// Check the version of the settings.
+			if _, err := m.getCurrentSettings(ctx); err != nil {
+				return 0, err
+			}
+			settings, err := settingsCreateIfUpToDate(ctx, tx, m.subject, m.input.LastID, actor.FromContext(ctx).UID, input.Contents)
+			if err != nil {
+				return 0, err
+			}
+			return settings.ID, nil
+		})
+
+	case mutation.EditSettings != nil:
+		input := mutation.EditSettings
+		keyPath, value, remove, err := parseSettingsEdit(input.Edit)
+		if err != nil {
+			return nil, err
+		}
+		return b.mutateSettings(tx, input.Subject, input.LastID, func(m *settingsMutation) (int32, error) {
+			return m.editSettings(ctx, keyPath, value, remove)
+		})
+
+	case mutation.UpdateSiteConfiguration != nil:
+		return b.updateSiteConfiguration(ctx, tx, mutation.UpdateSiteConfiguration)
+
+	case mutation.AddExternalService != nil:
+		return b.addExternalService(ctx, tx, mutation.AddExternalService)
+
+	case mutation.UpdateExternalService != nil:
+		return b.updateExternalService(ctx, tx, mutation.UpdateExternalService)
+
+	default:
+		return b.deleteExternalService(ctx, tx, mutation.DeleteExternalService)
+	}
+}
+
+func (b *configurationMutationBatch) mutateSettings(tx database.DB, subject graphql.ID, lastID *int32, mutate func(m *settingsMutation) (int32, error)) (*configurationMutationResultResolver, error) {
+	version, ok := b.settingsVersions[subject]
+	if ok {
+		if !equalIDs(version.before, lastID) {
+			return nil, errors.Errorf("update settings version mismatch: last ID before the batch is %s (mutation wanted %s)", formatID(version.before), formatID(lastID))
+		}
+		lastID = &version.after
+	} else {
+		version.before = lastID
+	}
+
+	id, err := mutate(&settingsMutation{
+		db:      tx,
+		input:   &settingsMutationGroupInput{Subject: subject, LastID: lastID},
+		subject: b.subjects[subject],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	version.after = id
+	b.settingsVersions[subject] = version
+	return &configurationMutationResultResolver{settingsID: &id}, nil
+}
+
+func (b *configurationMutationBatch) updateSiteConfiguration(ctx context.Context, tx database.DB, input *updateSiteConfigurationMutationInput) (*configurationMutationResultResolver, error) {
+	lastID := input.LastID
+	if b.siteConfigVersion != nil {
+		if *b.siteConfigVersion.before != lastID {
+			return nil, errors.Errorf("site config version mismatch: last ID before the batch is %d (mutation wanted %d)", *b.siteConfigVersion.before, lastID)
+		}
+		lastID = b.siteConfigVersion.after
+	}
+
+	latest, err := tx.Conf().SiteGetLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if latest.ID != lastID {
+		return nil, errors.New("site config has been modified by another request, write not allowed")
+	}
+
+	unredacted, err := conf.UnredactSecrets(input.Input, conftypes.RawUnified{Site: latest.Contents})
+	if err != nil {
+		return nil, errors.Errorf("error unredacting secrets: %s", err)
+	}
+	site, err := tx.Conf().SiteCreateIfUpToDate(ctx, &latest.ID, actor.FromContext(ctx).UID, unredacted, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.siteConfigVersion == nil {
+		before := input.LastID
+		b.siteConfigVersion = &batchVersion{before: &before}
+	}
+	b.siteConfigVersion.after = site.ID
+	b.siteConfigWritten = true
+	return &configurationMutationResultResolver{siteConfigurationID: &site.ID}, nil
+}
+
+func (b *configurationMutationBatch) addExternalService(ctx context.Context, tx database.DB, input *addExternalServiceInput) (*configurationMutationResultResolver, error) {
+	externalService := &types.ExternalService{
+		Kind:        input.Kind,
+		DisplayName: input.DisplayName,
+		Config:      extsvc.NewUnencryptedConfig(input.Config),
+	}
+	if err := tx.ExternalServices().Create(ctx, conf.Get, externalService); err != nil {
+		return nil, err
+	}
+
+	return b.externalServiceResult(externalService, true), nil
+}
+
+func (b *configurationMutationBatch) updateExternalService(ctx context.Context, tx database.DB, input *updateExternalServiceMutationInput) (*configurationMutationResultResolver, error) {
+	id, err := UnmarshalExternalServiceID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.checkExternalServiceUpToDate(ctx, tx, id, input.LastUpdatedAt); err != nil {
+		return nil, err
+	}
+
+	if input.Config != nil && strings.TrimSpace(*input.Config) == "" {
+		return nil, errors.New("blank external service configuration is invalid (must be valid JSONC)")
+	}
+
+	update := &database.ExternalServiceUpdate{
+		DisplayName: input.DisplayName,
+		Config:      input.Config,
+	}
+	if err := tx.ExternalServices().Update(ctx, conf.Get().AuthProviders, id, update); err != nil {
+		return nil, err
+	}
+
+	// Fetch from database again to get all fields with updated values.
+	externalService, err := tx.ExternalServices().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.externalServiceResult(externalService, input.Config != nil), nil
+}
+
+func (b *configurationMutationBatch) deleteExternalService(ctx context.Context, tx database.DB, input *deleteExternalServiceMutationInput) (*configurationMutationResultResolver, error) {
+	id, err := UnmarshalExternalServiceID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.checkExternalServiceUpToDate(ctx, tx, id, input.LastUpdatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.ExternalServices().Delete(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return &configurationMutationResultResolver{}, nil
+}
+
+// checkExternalServiceUpToDate returns an error if the external service with the
+// given ID has been updated since lastUpdatedAt, ignoring the updates of the
+// batch. The check is skipped if lastUpdatedAt is nil.
+func (b *configurationMutationBatch) checkExternalServiceUpToDate(ctx context.Context, tx database.DB, id int64, lastUpdatedAt *gqlutil.DateTime) error {
+	updatedAt, ok := b.extsvcUpdatedAt[id]
+	if !ok {
+		// Load external service to make sure it exists
+		externalService, err := tx.ExternalServices().GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		updatedAt = externalService.UpdatedAt
+		b.extsvcUpdatedAt[id] = updatedAt
+	}
+
+	// DateTime values have a precision of seconds.
+	if lastUpdatedAt != nil && !updatedAt.Truncate(time.Second).Equal(lastUpdatedAt.Time.Truncate(time.Second)) {
+		return errors.Errorf("external service has been updated at %s (mutation wanted %s)", updatedAt.UTC().Format(time.RFC3339), lastUpdatedAt.Time.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (b *configurationMutationBatch) externalServiceResult(externalService *types.ExternalService, sync bool) *configurationMutationResultResolver {
+	return &configurationMutationResultResolver{
+		externalService: &externalServiceResolver{
+			logger:          b.resolver.logger.Scoped("externalServiceResolver", ""),
+			db:              b.resolver.db,
+			externalService: externalService,
+		},
+		sync: sync,
+	}
+}
+
+func equalIDs(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func formatID(id *int32) string {
+	if id == nil {
+		return "null"
+	}
+	return strconv.FormatInt(int64(*id), 10)
+}
+
+type applyConfigurationMutationsResultResolver struct {
+	results         []*configurationMutationResultResolver
+	dryRun          bool
+	restartRequired bool
+}
+
+func (r *applyConfigurationMutationsResultResolver) Results() []*configurationMutationResultResolver {
+	return r.results
+}
+
+func (r *applyConfigurationMutationsResultResolver) DryRun() bool { return r.dryRun }
+
+func (r *applyConfigurationMutationsResultResolver) RestartRequired() bool { return r.restartRequired }
+
+type configurationMutationResultResolver struct {
+	settingsID          *int32
+	siteConfigurationID *int32
+	externalService     *externalServiceResolver
+	// sync is whether the external service must be synced to validate it.
+	sync    bool
+	warning *string
+}
+
+func (r *configurationMutationResultResolver) SettingsID() *int32 { return r.settingsID }
+
+func (r *configurationMutationResultResolver) SiteConfigurationID() *int32 {
+	return r.siteConfigurationID
+}
+
+func (r *configurationMutationResultResolver) ExternalService() *externalServiceResolver {
+	return r.externalService
+}
+
+func (r *configurationMutationResultResolver) Warning() *string { return r.warning }
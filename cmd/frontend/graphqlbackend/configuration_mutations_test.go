@@ -0,0 +1,192 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestApplyConfigurationMutations(t *testing.T) {
+	users := database.NewMockUserStore()
+	users.GetByIDFunc.SetDefaultReturn(&types.User{ID: 1}, nil)
+	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+
+	latest := &api.Settings{ID: 1, Contents: "{}"}
+	var lastIDs []int32
+	settings := database.NewMockSettingsStore()
+	settings.GetLatestFunc.SetDefaultHook(func(ctx context.Context, subject api.SettingsSubject) (*api.Settings, error) {
+		return latest, nil
+	})
+	settings.CreateIfUpToDateFunc.SetDefaultHook(func(ctx context.Context, subject api.SettingsSubject, lastID, authorUserID *int32, contents string) (*api.Settings, error) {
+		lastIDs = append(lastIDs, *lastID)
+		latest = &api.Settings{ID: latest.ID + 1, Contents: contents}
+		return latest, nil
+	})
+
+	updatedAt := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	externalServices := database.NewMockExternalServiceStore()
+	externalServices.GetByIDFunc.SetDefaultReturn(&types.ExternalService{ID: 1, UpdatedAt: updatedAt}, nil)
+
+	var transactErrs []error
+	db := database.NewMockDB()
+	db.WithTransactFunc.SetDefaultHook(func(ctx context.Context, f func(database.DB) error) error {
+		err := f(db)
+		transactErrs = append(transactErrs, err)
+		return err
+	})
+	db.UsersFunc.SetDefaultReturn(users)
+	db.SettingsFunc.SetDefaultReturn(settings)
+	db.ExternalServicesFunc.SetDefaultReturn(externalServices)
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+
+	t.Run("applies mutations in order", func(t *testing.T) {
+		RunTest(t, &Test{
+			Context: ctx,
+			Schema:  mustParseGraphQLSchema(t, db),
+			Query: `
+				mutation {
+					applyConfigurationMutations(mutations: [
+						{editSettings: {subject: "VXNlcjox", lastID: 1, edit: {keyPath: [{property: "a"}], value: 1}}},
+						{editSettings: {subject: "VXNlcjox", lastID: 1, edit: {keyPath: [{property: "b"}], value: 2}}},
+					]) {
+						results {
+							settingsID
+						}
+						dryRun
+						restartRequired
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"applyConfigurationMutations": {
+						"results": [{"settingsID": 2}, {"settingsID": 3}],
+						"dryRun": false,
+						"restartRequired": false
+					}
+				}
+			`,
+		})
+
+		// The second mutation is applied on top of the settings written by the first one.
+		assert.Equal(t, []int32{1, 2}, lastIDs)
+		assert.Equal(t, "{\n  \"a\": 1,\n  \"b\": 2\n}", latest.Contents)
+		assert.Equal(t, []error{nil}, transactErrs)
+	})
+
+	t.Run("fails without applying changes on conflict", func(t *testing.T) {
+		latest = &api.Settings{ID: 1, Contents: "{}"}
+		lastIDs = nil
+		transactErrs = nil
+
+		RunTest(t, &Test{
+			Context: ctx,
+			Schema:  mustParseGraphQLSchema(t, db),
+			Query: `
+				mutation {
+					applyConfigurationMutations(mutations: [
+						{overwriteSettings: {subject: "VXNlcjox", lastID: 1, contents: "{\"a\": 1}"}},
+						{deleteExternalService: {id: "RXh0ZXJuYWxTZXJ2aWNlOjE=", lastUpdatedAt: "2023-05-01T12:00:00Z"}},
+					]) {
+						dryRun
+					}
+				}
+			`,
+			ExpectedResult: `null`,
+			ExpectedErrors: []*gqlerrors.QueryError{
+				{
+					Path:    []any{"applyConfigurationMutations"},
+					Message: "mutation 1: external service has been updated at 2023-06-01T12:00:00Z (mutation wanted 2023-05-01T12:00:00Z)",
+				},
+			},
+		})
+
+		// The transaction is rolled back and the external service is not deleted.
+		assert.Len(t, transactErrs, 1)
+		assert.Error(t, transactErrs[0])
+		assert.Len(t, externalServices.DeleteFunc.History(), 0)
+	})
+
+	t.Run("fails on settings version mismatch", func(t *testing.T) {
+		latest = &api.Settings{ID: 1, Contents: "{}"}
+
+		RunTest(t, &Test{
+			Context: ctx,
+			Schema:  mustParseGraphQLSchema(t, db),
+			Query: `
+				mutation {
+					applyConfigurationMutations(mutations: [
+						{overwriteSettings: {subject: "VXNlcjox", lastID: 1, contents: "{}"}},
+						{overwriteSettings: {subject: "VXNlcjox", lastID: 2, contents: "{}"}},
+					]) {
+						dryRun
+					}
+				}
+			`,
+			ExpectedResult: `null`,
+			ExpectedErrors: []*gqlerrors.QueryError{
+				{
+					Path:    []any{"applyConfigurationMutations"},
+					Message: "mutation 1: update settings version mismatch: last ID before the batch is 1 (mutation wanted 2)",
+				},
+			},
+		})
+	})
+
+	t.Run("requires exactly one mutation", func(t *testing.T) {
+		RunTest(t, &Test{
+			Context: ctx,
+			Schema:  mustParseGraphQLSchema(t, db),
+			Query: `
+				mutation {
+					applyConfigurationMutations(mutations: [{}]) {
+						dryRun
+					}
+				}
+			`,
+			ExpectedResult: `null`,
+			ExpectedErrors: []*gqlerrors.QueryError{
+				{
+					Path:    []any{"applyConfigurationMutations"},
+					Message: "mutation 0: exactly one mutation must be set",
+				},
+			},
+		})
+	})
+
+	t.Run("non site admin", func(t *testing.T) {
+		users := database.NewMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 2}, nil)
+		db := database.NewMockDBFrom(db)
+		db.UsersFunc.SetDefaultReturn(users)
+
+		RunTest(t, &Test{
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 2}),
+			Schema:  mustParseGraphQLSchema(t, db),
+			Query: `
+				mutation {
+					applyConfigurationMutations(mutations: []) {
+						dryRun
+					}
+				}
+			`,
+			ExpectedResult: `null`,
+			ExpectedErrors: []*gqlerrors.QueryError{
+				{
+					Path:    []any{"applyConfigurationMutations"},
+					Message: auth.ErrMustBeSiteAdmin.Error(),
+				},
+			},
+		})
+	})
+}
@@ -0,0 +1,47 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+// EncryptionProgress resolves the encryption at rest of each table with
+// encrypted columns.
+func (r *schemaResolver) EncryptionProgress(ctx context.Context) ([]*encryptionProgressResolver, error) {
+	// 🚨 SECURITY: Only site admins may view the encryption progress
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	store := database.NewRecordEncrypter(r.db)
+
+	resolvers := make([]*encryptionProgressResolver, 0, len(database.EncryptionConfigs))
+	for _, config := range database.EncryptionConfigs {
+		progress, err := store.Progress(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+		resolvers = append(resolvers, &encryptionProgressResolver{progress})
+	}
+
+	return resolvers, nil
+}
+
+type encryptionProgressResolver struct {
+	p database.EncryptionProgress
+}
+
+func (r *encryptionProgressResolver) TableName() string  { return r.p.TableName }
+func (r *encryptionProgressResolver) Unencrypted() int32 { return int32(r.p.Unencrypted) }
+func (r *encryptionProgressResolver) Outdated() int32    { return int32(r.p.Outdated) }
+func (r *encryptionProgressResolver) Current() int32     { return int32(r.p.Current) }
+
+func (r *encryptionProgressResolver) Progress() float64 {
+	total := r.p.Unencrypted + r.p.Outdated + r.p.Current
+	if total == 0 {
+		return 1
+	}
+	return float64(r.p.Current) / float64(total)
+}
@@ -0,0 +1,64 @@
+package graphqlbackend
+
+import (
+	"context"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/executor/artifacts"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/signedlink"
+)
+
+type executorJobArtifactResolver struct {
+	artifact *artifacts.Artifact
+}
+
+func (r *executorJobArtifactResolver) ID() graphql.ID {
+	return relay.MarshalID("ExecutorJobArtifact", r.artifact.ID)
+}
+
+func (r *executorJobArtifactResolver) Name() string { return r.artifact.Name }
+
+func (r *executorJobArtifactResolver) ContentType() string { return r.artifact.ContentType }
+
+func (r *executorJobArtifactResolver) ByteSize() BigInt { return BigInt(r.artifact.Size) }
+
+func (r *executorJobArtifactResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.artifact.CreatedAt}
+}
+
+func (r *executorJobArtifactResolver) ExpiresAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.artifact.ExpiresAt}
+}
+
+func (r *executorJobArtifactResolver) DownloadURL() string {
+	// 🚨 SECURITY: Anyone with the link can download the artifact. Resolvers are
+	// only created for site admins.
+	return artifacts.DownloadURL(globals.ExternalURL(), r.artifact, time.Now().Add(signedlink.TTL))
+}
+
+func (r *schemaResolver) ExecutorJobArtifacts(ctx context.Context, args *struct {
+	Queue string
+	JobID int32
+}) ([]*executorJobArtifactResolver, error) {
+	// 🚨 SECURITY: Artifacts may contain the contents of any repository, so only
+	// site admins may list them.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	as, err := artifacts.NewStore(r.db).ListByJob(ctx, args.Queue, int(args.JobID))
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*executorJobArtifactResolver, 0, len(as))
+	for _, a := range as {
+		resolvers = append(resolvers, &executorJobArtifactResolver{artifact: a})
+	}
+	return resolvers, nil
+}
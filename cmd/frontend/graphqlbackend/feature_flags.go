@@ -2,6 +2,7 @@ package graphqlbackend
 
 import (
 	"context"
+	"strings"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
@@ -10,6 +11,7 @@ import (
 	sgactor "github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/featureflag"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -54,6 +56,9 @@ func (f *FeatureFlagBooleanResolver) Overrides(ctx context.Context) ([]*FeatureF
 	}
 	return overridesToResolvers(f.db, overrides), nil
 }
+func (f *FeatureFlagBooleanResolver) TargetingRules() []*FeatureFlagTargetingRuleResolver {
+	return targetingRulesToResolvers(f.db, f.inner.TargetingRules)
+}
 
 type FeatureFlagRolloutResolver struct {
 	db database.DB
@@ -76,6 +81,86 @@ func (f *FeatureFlagRolloutResolver) Overrides(ctx context.Context) ([]*FeatureF
 	}
 	return overridesToResolvers(f.db, overrides), nil
 }
+func (f *FeatureFlagRolloutResolver) TargetingRules() []*FeatureFlagTargetingRuleResolver {
+	return targetingRulesToResolvers(f.db, f.inner.TargetingRules)
+}
+func (f *FeatureFlagRolloutResolver) BucketBy() string {
+	if f.inner.Rollout.BucketBy == featureflag.RolloutBucketByOrg {
+		return "ORG"
+	}
+	return "USER"
+}
+
+func targetingRulesToResolvers(db database.DB, input []featureflag.TargetingRule) []*FeatureFlagTargetingRuleResolver {
+	res := make([]*FeatureFlagTargetingRuleResolver, 0, len(input))
+	for _, rule := range input {
+		res = append(res, &FeatureFlagTargetingRuleResolver{db, rule})
+	}
+	return res
+}
+
+type FeatureFlagTargetingRuleResolver struct {
+	db    database.DB
+	inner featureflag.TargetingRule
+}
+
+func (f *FeatureFlagTargetingRuleResolver) Org(ctx context.Context) (*OrgResolver, error) {
+	if f.inner.OrgID == nil {
+		return nil, nil
+	}
+	return OrgByIDInt32(ctx, f.db, *f.inner.OrgID)
+}
+func (f *FeatureFlagTargetingRuleResolver) Role() *string {
+	if f.inner.Role == "" {
+		return nil
+	}
+	return &f.inner.Role
+}
+func (f *FeatureFlagTargetingRuleResolver) Value() bool { return f.inner.Value }
+
+type featureFlagTargetingRuleInput struct {
+	Org   *graphql.ID
+	Role  *string
+	Value bool
+}
+
+// toFeatureFlag returns the feature flag described by the arguments of the
+// createFeatureFlag and updateFeatureFlag mutations.
+func toFeatureFlag(name string, value *bool, rolloutBasisPoints *int32, rolloutBucketBy *string, targetingRules *[]featureFlagTargetingRuleInput) (*featureflag.FeatureFlag, error) {
+	ff := &featureflag.FeatureFlag{Name: name}
+	if value != nil {
+		ff.Bool = &featureflag.FeatureFlagBool{Value: *value}
+	} else if rolloutBasisPoints != nil {
+		ff.Rollout = &featureflag.FeatureFlagRollout{Rollout: *rolloutBasisPoints}
+		if rolloutBucketBy != nil {
+			ff.Rollout.BucketBy = featureflag.RolloutBucketBy(strings.ToLower(*rolloutBucketBy))
+		}
+	} else {
+		return nil, errors.Errorf("either 'value' or 'rolloutBasisPoints' must be set")
+	}
+
+	if targetingRules != nil {
+		ff.TargetingRules = make([]featureflag.TargetingRule, 0, len(*targetingRules))
+		for _, input := range *targetingRules {
+			if (input.Org == nil) == (input.Role == nil || *input.Role == "") {
+				return nil, errors.New("targeting rules must have exactly one of 'org' or 'role' set")
+			}
+			rule := featureflag.TargetingRule{Value: input.Value}
+			if input.Org != nil {
+				orgID, err := UnmarshalOrgID(*input.Org)
+				if err != nil {
+					return nil, err
+				}
+				rule.OrgID = &orgID
+			} else {
+				rule.Role = *input.Role
+			}
+			ff.TargetingRules = append(ff.TargetingRules, rule)
+		}
+	}
+
+	return ff, nil
+}
 
 func overridesToResolvers(db database.DB, input []*featureflag.Override) []*FeatureFlagOverrideResolver {
 	res := make([]*FeatureFlagOverrideResolver, 0, len(input))
@@ -223,6 +308,53 @@ func (r *schemaResolver) FeatureFlags(ctx context.Context) ([]*FeatureFlagResolv
 	return flagsToResolvers(r.db, flags), nil
 }
 
+func (r *schemaResolver) FeatureFlagEvaluations(ctx context.Context, args struct {
+	FlagName string
+	First    int32
+}) ([]*FeatureFlagEvaluationResolver, error) {
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	events, err := featureflag.GetEvaluationEvents(args.FlagName, int(args.First))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*FeatureFlagEvaluationResolver, 0, len(events))
+	for _, event := range events {
+		res = append(res, &FeatureFlagEvaluationResolver{r.db, event})
+	}
+	return res, nil
+}
+
+type FeatureFlagEvaluationResolver struct {
+	db    database.DB
+	inner featureflag.EvaluationEvent
+}
+
+func (f *FeatureFlagEvaluationResolver) FlagName() string { return f.inner.FlagName }
+func (f *FeatureFlagEvaluationResolver) Value() bool      { return f.inner.Value }
+func (f *FeatureFlagEvaluationResolver) User(ctx context.Context) (*UserResolver, error) {
+	if f.inner.UserID == 0 {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, f.db, f.inner.UserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+func (f *FeatureFlagEvaluationResolver) AnonymousUserID() *string {
+	if f.inner.AnonymousUID == "" {
+		return nil
+	}
+	return &f.inner.AnonymousUID
+}
+func (f *FeatureFlagEvaluationResolver) EvaluatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: f.inner.Timestamp}
+}
+
 func flagsToResolvers(db database.DB, flags []*featureflag.FeatureFlag) []*FeatureFlagResolver {
 	res := make([]*FeatureFlagResolver, 0, len(flags))
 	for _, flag := range flags {
@@ -235,23 +367,19 @@ func (r *schemaResolver) CreateFeatureFlag(ctx context.Context, args struct {
 	Name               string
 	Value              *bool
 	RolloutBasisPoints *int32
+	RolloutBucketBy    *string
+	TargetingRules     *[]featureFlagTargetingRuleInput
 }) (*FeatureFlagResolver, error) {
 	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
 		return nil, err
 	}
 
-	ff := r.db.FeatureFlags()
-
-	var res *featureflag.FeatureFlag
-	var err error
-	if args.Value != nil {
-		res, err = ff.CreateBool(ctx, args.Name, *args.Value)
-	} else if args.RolloutBasisPoints != nil {
-		res, err = ff.CreateRollout(ctx, args.Name, *args.RolloutBasisPoints)
-	} else {
-		return nil, errors.Errorf("either 'value' or 'rolloutBasisPoints' must be set")
+	ff, err := toFeatureFlag(args.Name, args.Value, args.RolloutBasisPoints, args.RolloutBucketBy, args.TargetingRules)
+	if err != nil {
+		return nil, err
 	}
 
+	res, err := r.db.FeatureFlags().CreateFeatureFlag(ctx, ff)
 	return &FeatureFlagResolver{r.db, res}, err
 }
 
@@ -268,17 +396,15 @@ func (r *schemaResolver) UpdateFeatureFlag(ctx context.Context, args struct {
 	Name               string
 	Value              *bool
 	RolloutBasisPoints *int32
+	RolloutBucketBy    *string
+	TargetingRules     *[]featureFlagTargetingRuleInput
 }) (*FeatureFlagResolver, error) {
 	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
 		return nil, err
 	}
-	ff := &featureflag.FeatureFlag{Name: args.Name}
-	if args.Value != nil {
-		ff.Bool = &featureflag.FeatureFlagBool{Value: *args.Value}
-	} else if args.RolloutBasisPoints != nil {
-		ff.Rollout = &featureflag.FeatureFlagRollout{Rollout: *args.RolloutBasisPoints}
-	} else {
-		return nil, errors.Errorf("either 'value' or 'rolloutBasisPoints' must be set")
+	ff, err := toFeatureFlag(args.Name, args.Value, args.RolloutBasisPoints, args.RolloutBucketBy, args.TargetingRules)
+	if err != nil {
+		return nil, err
 	}
 
 	res, err := r.db.FeatureFlags().UpdateFeatureFlag(ctx, ff)
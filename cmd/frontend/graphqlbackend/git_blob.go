@@ -13,7 +13,7 @@ func (r *GitTreeEntryResolver) Blame(ctx context.Context,
 		StartLine int32
 		EndLine   int32
 	}) ([]*hunkResolver, error) {
-	hunks, err := r.gitserverClient.BlameFile(ctx, authz.DefaultSubRepoPermsChecker, r.commit.repoResolver.RepoName(), r.Path(), &gitserver.BlameOptions{
+	hunkReader, err := r.gitserverClient.StreamBlameFile(ctx, authz.DefaultSubRepoPermsChecker, r.commit.repoResolver.RepoName(), r.Path(), &gitserver.BlameOptions{
 		NewestCommit: api.CommitID(r.commit.OID()),
 		StartLine:    int(args.StartLine),
 		EndLine:      int(args.EndLine),
@@ -21,6 +21,12 @@ func (r *GitTreeEntryResolver) Blame(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	defer hunkReader.Close()
+
+	hunks, err := gitserver.ReadAllHunks(hunkReader)
+	if err != nil {
+		return nil, err
+	}
 
 	var hunksResolver []*hunkResolver
 	for _, hunk := range hunks {
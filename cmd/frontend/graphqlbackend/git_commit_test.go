@@ -22,6 +22,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
 func TestGitCommitResolver(t *testing.T) {
@@ -799,3 +800,48 @@ func TestGitCommitPerforceChangelist(t *testing.T) {
 		})
 	})
 }
+
+func TestGitCommitSignatureVerification(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMockDB()
+	repos := database.NewMockRepoStore()
+	repos.GetFunc.SetDefaultReturn(&types.Repo{ID: 1, Name: "bob-repo"}, nil)
+	db.ReposFunc.SetDefaultReturn(repos)
+
+	for _, tc := range []struct {
+		name       string
+		obj        *gitdomain.SignedObject
+		wantStatus string
+		wantFormat *string
+	}{
+		{
+			name:       "unsigned",
+			wantStatus: "UNSIGNED",
+		},
+		{
+			name: "unsupported format",
+			obj: &gitdomain.SignedObject{
+				Type:      gitdomain.ObjectTypeCommit,
+				Format:    gitdomain.SignatureFormatX509,
+				Signature: []byte("-----BEGIN SIGNED MESSAGE-----\n-----END SIGNED MESSAGE-----\n"),
+			},
+			wantStatus: "UNSUPPORTED",
+			wantFormat: pointers.Ptr("X509"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := gitserver.NewMockClient()
+			client.ObjectSignatureFunc.SetDefaultReturn(tc.obj, nil)
+
+			repo := NewRepositoryResolver(db, client, &types.Repo{ID: 1, Name: "bob-repo"})
+			commitResolver := NewGitCommitResolver(db, client, repo, "c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1", nil)
+
+			verification, err := commitResolver.SignatureVerification(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantStatus, verification.Status())
+			assert.Equal(t, tc.wantFormat, verification.Format())
+			assert.Equal(t, "TRUSTED_KEYS", verification.VerifiedBy())
+			assert.Equal(t, "c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1", client.ObjectSignatureFunc.History()[0].Arg3)
+		})
+	}
+}
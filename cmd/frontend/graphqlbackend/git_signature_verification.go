@@ -0,0 +1,84 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/commitsigning"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+func (r *GitCommitResolver) SignatureVerification(ctx context.Context) (*gitSignatureVerificationResolver, error) {
+	obj, err := r.gitserverClient.ObjectSignature(ctx, authz.DefaultSubRepoPermsChecker, r.gitRepo, string(r.oid))
+	if err != nil {
+		return nil, err
+	}
+	repo, err := r.repoResolver.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := commitsigning.NewVerifier(r.logger, r.db).Verify(ctx, repo, string(r.oid), obj)
+	return &gitSignatureVerificationResolver{result: res}, nil
+}
+
+func (r *GitRefResolver) TagSignatureVerification(ctx context.Context) (*gitSignatureVerificationResolver, error) {
+	if gitRefType(r.name) != gitRefTypeTag {
+		return nil, nil
+	}
+
+	tag, err := r.repo.gitserverClient.GetObject(ctx, r.repo.RepoName(), r.name)
+	if err != nil {
+		return nil, err
+	}
+	if tag.Type != gitdomain.ObjectTypeTag {
+		// Lightweight tags point to commits, and have no signature of their own.
+		return nil, nil
+	}
+
+	obj, err := r.repo.gitserverClient.ObjectSignature(ctx, authz.DefaultSubRepoPermsChecker, r.repo.RepoName(), tag.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	repo, err := r.repo.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := commitsigning.NewVerifier(r.repo.logger, r.repo.db).Verify(ctx, repo, tag.ID.String(), obj)
+	return &gitSignatureVerificationResolver{result: res}, nil
+}
+
+type gitSignatureVerificationResolver struct {
+	result *commitsigning.Result
+}
+
+func (r *gitSignatureVerificationResolver) Status() string {
+	return strings.ToUpper(string(r.result.Status))
+}
+
+func (r *gitSignatureVerificationResolver) Format() *string {
+	if r.result.Format == "" {
+		return nil
+	}
+	format := strings.ToUpper(string(r.result.Format))
+	return &format
+}
+
+func (r *gitSignatureVerificationResolver) KeyID() *string {
+	return pointers.NonZeroPtr(r.result.KeyID)
+}
+
+func (r *gitSignatureVerificationResolver) Signer() *string {
+	return pointers.NonZeroPtr(r.result.Signer)
+}
+
+func (r *gitSignatureVerificationResolver) VerifiedBy() string {
+	return strings.ToUpper(string(r.result.VerifiedBy))
+}
+
+func (r *gitSignatureVerificationResolver) Reason() *string {
+	return pointers.NonZeroPtr(r.result.Reason)
+}
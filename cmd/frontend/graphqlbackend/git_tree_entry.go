@@ -288,7 +288,12 @@ func (r *GitTreeEntryResolver) RawZipArchiveURL() string {
 
 func (r *GitTreeEntryResolver) Submodule() *gitSubmoduleResolver {
 	if submoduleInfo, ok := r.stat.Sys().(gitdomain.Submodule); ok {
-		return &gitSubmoduleResolver{submodule: submoduleInfo}
+		return &gitSubmoduleResolver{
+			db:              r.db,
+			gitserverClient: r.gitserverClient,
+			repo:            r.commit.repoResolver.RepoName(),
+			submodule:       submoduleInfo,
+		}
 	}
 	return nil
 }
@@ -1,11 +1,35 @@
 package graphqlbackend
 
 import (
+	"context"
+	"path"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
+// maxSubmoduleDepth is the maximum depth of nested submodules that are listed
+// by GitCommit.submodules(recursive: true).
+const maxSubmoduleDepth = 8
+
 type gitSubmoduleResolver struct {
+	db              database.DB
+	gitserverClient gitserver.Client
+	// repo is the repository that contains the submodule. Relative submodule
+	// URLs are resolved against it.
+	repo      api.RepoName
 	submodule gitdomain.Submodule
+
+	targetRepoOnce sync.Once
+	targetRepo     *types.Repo
+	targetRepoErr  error
 }
 
 func (r *gitSubmoduleResolver) URL() string {
@@ -19,3 +43,94 @@ func (r *gitSubmoduleResolver) Commit() string {
 func (r *gitSubmoduleResolver) Path() string {
 	return r.submodule.Path
 }
+
+func (r *gitSubmoduleResolver) resolveTargetRepo(ctx context.Context) (*types.Repo, error) {
+	r.targetRepoOnce.Do(func() {
+		r.targetRepo, r.targetRepoErr = backend.ResolveSubmoduleRepo(ctx, r.db, r.repo, r.submodule.URL)
+	})
+	return r.targetRepo, r.targetRepoErr
+}
+
+func (r *gitSubmoduleResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	repo, err := r.resolveTargetRepo(ctx)
+	if err != nil || repo == nil {
+		return nil, err
+	}
+	return NewRepositoryResolver(r.db, r.gitserverClient, repo), nil
+}
+
+func (r *gitSubmoduleResolver) Target(ctx context.Context) (*GitCommitResolver, error) {
+	repo, err := r.resolveTargetRepo(ctx)
+	if err != nil || repo == nil {
+		return nil, err
+	}
+	commit, err := r.gitserverClient.GetCommit(ctx, authz.DefaultSubRepoPermsChecker, repo.Name, r.submodule.CommitID, gitserver.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		// The pinned commit may not have been pushed to the target repository, or
+		// not be fetched yet.
+		if errcode.IsNotFound(err) || gitdomain.IsRepoNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return NewGitCommitResolver(r.db, r.gitserverClient, NewRepositoryResolver(r.db, r.gitserverClient, repo), commit.ID, commit), nil
+}
+
+type gitCommitSubmodulesArgs struct {
+	Recursive bool
+}
+
+func (r *GitCommitResolver) Submodules(ctx context.Context, args *gitCommitSubmodulesArgs) ([]*gitSubmoduleResolver, error) {
+	var resolvers []*gitSubmoduleResolver
+	visited := map[string]struct{}{}
+
+	var list func(repo api.RepoName, commit api.CommitID, prefix string, depth int) error
+	list = func(repo api.RepoName, commit api.CommitID, prefix string, depth int) error {
+		key := string(repo) + "@" + string(commit)
+		if _, ok := visited[key]; ok {
+			return nil
+		}
+		visited[key] = struct{}{}
+
+		submodules, err := backend.ListSubmodules(ctx, r.gitserverClient, repo, commit)
+		if err != nil {
+			return err
+		}
+		for _, submodule := range submodules {
+			submodule.Path = path.Join(prefix, submodule.Path)
+			resolver := &gitSubmoduleResolver{
+				db:              r.db,
+				gitserverClient: r.gitserverClient,
+				repo:            repo,
+				submodule:       submodule,
+			}
+			resolvers = append(resolvers, resolver)
+
+			if !args.Recursive || depth >= maxSubmoduleDepth {
+				continue
+			}
+			target, err := resolver.resolveTargetRepo(ctx)
+			if err != nil {
+				return err
+			}
+			if target == nil {
+				continue
+			}
+			if err := list(target.Name, submodule.CommitID, submodule.Path, depth+1); err != nil {
+				// Submodules of a target repository whose pinned commit is not
+				// available are skipped, like target repositories that are not
+				// known to Sourcegraph.
+				if errcode.IsNotFound(err) || gitdomain.IsRepoNotExist(err) {
+					continue
+				}
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := list(r.gitRepo, api.CommitID(r.oid), "", 0); err != nil {
+		return nil, err
+	}
+	return resolvers, nil
+}
@@ -0,0 +1,222 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/relocator"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const gitserverRepositoryMoveIDKind = "GitserverRepositoryMove"
+
+type gitserverRepositoryMovesArgs struct {
+	First int32
+	State *string
+}
+
+func (r *schemaResolver) GitserverRepositoryMoves(ctx context.Context, args *gitserverRepositoryMovesArgs) ([]*gitserverRepositoryMoveResolver, error) {
+	// 🚨 SECURITY: Only site admins can list the moves of repositories between gitservers.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	opts := relocator.ListOptions{Limit: int(args.First)}
+	if args.State != nil {
+		opts.State = strings.ToLower(*args.State)
+	}
+	jobs, err := relocator.NewStore(r.db).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return r.gitserverRepositoryMoveResolvers(ctx, jobs)
+}
+
+type gitserverRebalanceArgs struct {
+	MaxMoves int32
+}
+
+func (r *schemaResolver) GitserverRebalancePlan(ctx context.Context, args *gitserverRebalanceArgs) ([]*plannedGitserverRepositoryMoveResolver, error) {
+	// 🚨 SECURITY: Only site admins can plan the moves of repositories between gitservers.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	moves, err := r.planGitserverRebalance(ctx, int(args.MaxMoves))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]api.RepoID, 0, len(moves))
+	for _, move := range moves {
+		ids = append(ids, move.Repo.ID)
+	}
+	repos, err := r.db.Repos().GetReposSetByIDs(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*plannedGitserverRepositoryMoveResolver, 0, len(moves))
+	for _, move := range moves {
+		repo, ok := repos[move.Repo.ID]
+		if !ok {
+			// Deleted since it was planned
+			continue
+		}
+		resolvers = append(resolvers, &plannedGitserverRepositoryMoveResolver{
+			move: move,
+			repo: NewRepositoryResolver(r.db, r.gitserverClient, repo),
+		})
+	}
+	return resolvers, nil
+}
+
+type moveRepositoryToGitserverArgs struct {
+	Repo         graphql.ID
+	Gitserver    string
+	DeleteSource bool
+}
+
+func (r *schemaResolver) MoveRepositoryToGitserver(ctx context.Context, args *moveRepositoryToGitserverArgs) (*gitserverRepositoryMoveResolver, error) {
+	// 🚨 SECURITY: Only site admins can move repositories between gitservers.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	known := false
+	for _, addr := range r.gitserverClient.Addrs() {
+		known = known || addr == args.Gitserver
+	}
+	if !known {
+		return nil, errors.Newf("unknown gitserver %q", args.Gitserver)
+	}
+
+	repo, err := r.repositoryByID(ctx, args.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := relocator.NewStore(r.db).Enqueue(ctx, repo.IDInt32(), r.gitserverClient.AddrForRepo(repo.RepoName()), args.Gitserver, args.DeleteSource)
+	if err != nil {
+		return nil, err
+	}
+	return &gitserverRepositoryMoveResolver{job: job, repo: repo}, nil
+}
+
+func (r *schemaResolver) RebalanceGitservers(ctx context.Context, args *gitserverRebalanceArgs) ([]*gitserverRepositoryMoveResolver, error) {
+	// 🚨 SECURITY: Only site admins can move repositories between gitservers.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	moves, err := r.planGitserverRebalance(ctx, int(args.MaxMoves))
+	if err != nil {
+		return nil, err
+	}
+
+	store := relocator.NewStore(r.db)
+	jobs := make([]*relocator.Job, 0, len(moves))
+	for _, move := range moves {
+		job, err := store.Enqueue(ctx, move.Repo.ID, move.SourceHostname, move.DestHostname, true)
+		if err != nil {
+			if errors.Is(err, relocator.ErrMoveInProgress) {
+				// Enqueued concurrently
+				continue
+			}
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return r.gitserverRepositoryMoveResolvers(ctx, jobs)
+}
+
+func (r *schemaResolver) planGitserverRebalance(ctx context.Context, maxMoves int) ([]relocator.Move, error) {
+	repos, err := relocator.NewStore(r.db).ListRepoSizes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return relocator.Plan(gitserver.NewGitserverAddressesFromConf(conf.Get()), repos, maxMoves), nil
+}
+
+func (r *schemaResolver) gitserverRepositoryMoveResolvers(ctx context.Context, jobs []*relocator.Job) ([]*gitserverRepositoryMoveResolver, error) {
+	ids := make([]api.RepoID, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, job.RepoID)
+	}
+	repos, err := r.db.Repos().GetReposSetByIDs(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*gitserverRepositoryMoveResolver, 0, len(jobs))
+	for _, job := range jobs {
+		repo, ok := repos[job.RepoID]
+		if !ok {
+			repo = &types.Repo{ID: job.RepoID, Name: job.RepoName}
+		}
+		resolvers = append(resolvers, &gitserverRepositoryMoveResolver{
+			job:  job,
+			repo: NewRepositoryResolver(r.db, r.gitserverClient, repo),
+		})
+	}
+	return resolvers, nil
+}
+
+type gitserverRepositoryMoveResolver struct {
+	job  *relocator.Job
+	repo *RepositoryResolver
+}
+
+func (r *gitserverRepositoryMoveResolver) ID() graphql.ID {
+	return relay.MarshalID(gitserverRepositoryMoveIDKind, r.job.ID)
+}
+
+func (r *gitserverRepositoryMoveResolver) Repository() *RepositoryResolver { return r.repo }
+
+func (r *gitserverRepositoryMoveResolver) Source() string { return r.job.SourceHostname }
+
+func (r *gitserverRepositoryMoveResolver) Destination() string { return r.job.DestHostname }
+
+func (r *gitserverRepositoryMoveResolver) DeleteSource() bool { return r.job.DeleteSource }
+
+func (r *gitserverRepositoryMoveResolver) State() string { return strings.ToUpper(r.job.State) }
+
+func (r *gitserverRepositoryMoveResolver) FailureMessage() *string { return r.job.FailureMessage }
+
+func (r *gitserverRepositoryMoveResolver) NumFailures() int32 { return int32(r.job.NumFailures) }
+
+func (r *gitserverRepositoryMoveResolver) QueuedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.job.QueuedAt}
+}
+
+func (r *gitserverRepositoryMoveResolver) StartedAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.job.StartedAt)
+}
+
+func (r *gitserverRepositoryMoveResolver) FinishedAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.job.FinishedAt)
+}
+
+type plannedGitserverRepositoryMoveResolver struct {
+	move relocator.Move
+	repo *RepositoryResolver
+}
+
+func (r *plannedGitserverRepositoryMoveResolver) Repository() *RepositoryResolver { return r.repo }
+
+func (r *plannedGitserverRepositoryMoveResolver) ByteSize() BigInt {
+	return BigInt(r.move.Repo.SizeBytes)
+}
+
+func (r *plannedGitserverRepositoryMoveResolver) Source() string { return r.move.SourceHostname }
+
+func (r *plannedGitserverRepositoryMoveResolver) Destination() string { return r.move.DestHostname }
@@ -0,0 +1,33 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+func TestSchemaResolver_GitserverRelocator_NotSiteAdmin(t *testing.T) {
+	t.Parallel()
+
+	for name, query := range map[string]string{
+		"gitserverRepositoryMoves":  `{ gitserverRepositoryMoves { id } }`,
+		"gitserverRebalancePlan":    `{ gitserverRebalancePlan { source } }`,
+		"moveRepositoryToGitserver": `mutation { moveRepositoryToGitserver(repo: "UmVwb3NpdG9yeTox", gitserver: "gitserver-1:3178") { id } }`,
+		"rebalanceGitservers":       `mutation { rebalanceGitservers { id } }`,
+	} {
+		name, query := name, query
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db := database.NewMockDB()
+			ctx, _, _ := fakeUser(t, context.Background(), db, false)
+
+			runMustBeSiteAdminTest(t, []any{name}, &Test{
+				Context: ctx,
+				Schema:  mustParseGraphQLSchema(t, db),
+				Query:   query,
+			})
+		})
+	}
+}
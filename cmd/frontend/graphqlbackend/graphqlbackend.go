@@ -430,7 +430,7 @@ func NewSchema(
 	graphqlOpts ...graphql.SchemaOpt,
 ) (*graphql.Schema, error) {
 	resolver := newSchemaResolver(db, gitserverClient, enterpriseJobs)
-	schemas := []string{mainSchema, outboundWebhooksSchema}
+	schemas := []string{mainSchema, outboundWebhooksSchema, gitserverRelocatorSchema, notificationsSchema, auditLogSchema}
 
 	for _, optional := range optionals {
 		if batchChanges := optional.BatchChangesResolver; batchChanges != nil {
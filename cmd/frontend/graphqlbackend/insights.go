@@ -45,6 +45,7 @@ type InsightsResolver interface {
 	InsightSeriesQueryStatus(ctx context.Context) ([]InsightSeriesQueryStatusResolver, error)
 	InsightViewDebug(ctx context.Context, args InsightViewDebugArgs) (InsightViewDebugResolver, error)
 	InsightAdminBackfillQueue(ctx context.Context, args *AdminBackfillQueueArgs) (*graphqlutil.ConnectionResolver[*BackfillQueueItemResolver], error)
+	InsightsComputedSeriesMetrics(ctx context.Context) ([]InsightsComputedSeriesMetricResolver, error)
 	// Admin Mutations
 	UpdateInsightSeries(ctx context.Context, args *UpdateInsightSeriesArgs) (InsightSeriesMetadataPayloadResolver, error)
 	RetryInsightSeriesBackfill(ctx context.Context, args *BackfillArgs) (*BackfillQueueItemResolver, error)
@@ -98,6 +99,12 @@ type InsightsDataPointResolver interface {
 type InsightViewDebugResolver interface {
 	Raw(context.Context) ([]string, error)
 }
+
+type InsightsComputedSeriesMetricResolver interface {
+	Name() string
+	Description() string
+}
+
 type InsightStatusResolver interface {
 	TotalPoints(context.Context) (int32, error)
 	PendingJobs(context.Context) (int32, error)
@@ -429,6 +436,14 @@ type LineChartSearchInsightDataSeriesInput struct {
 	Options                    LineChartDataSeriesOptionsInput
 	GeneratedFromCaptureGroups *bool
 	GroupBy                    *string
+	Computed                   *bool
+	BackfillSampling           *InsightBackfillSamplingInput
+}
+
+type InsightBackfillSamplingInput struct {
+	Strategy       string
+	TagPattern     *string
+	CommitInterval *int32
 }
 
 type LineChartDataSeriesOptionsInput struct {
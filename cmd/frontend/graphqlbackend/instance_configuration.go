@@ -0,0 +1,243 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/codepolicies"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/instanceconfig"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ApplyInstanceConfiguration converges the configuration of the instance to a
+// declarative snapshot.
+func (r *schemaResolver) ApplyInstanceConfiguration(ctx context.Context, args *struct {
+	Snapshot string
+	DryRun   bool
+}) (*instanceConfigurationDiffResolver, error) {
+	// 🚨 SECURITY: Only site admins may apply instance configuration, as it
+	// includes the site configuration and external services.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := instanceconfig.ParseSnapshot(args.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, _, err := planInstanceConfiguration(ctx, r.db, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if args.DryRun || len(plan.Changes) == 0 {
+		return &instanceConfigurationDiffResolver{changes: plan.Changes}, nil
+	}
+
+	if plan.SiteConfiguration != nil && !canUpdateSiteConfiguration() {
+		return nil, errors.New("updating site configuration not allowed when using SITE_CONFIG_FILE")
+	}
+	if len(plan.CreateExternalServices)+len(plan.UpdateExternalServices)+len(plan.DeleteExternalServices) > 0 {
+		if err := externalServicesWritable(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Deleting an external service waits for its running sync jobs to be
+	// canceled, which they only notice outside the transaction.
+	for _, id := range plan.DeleteExternalServices {
+		if err := r.db.ExternalServices().CancelSyncJob(ctx, database.ExternalServicesCancelSyncJobOptions{ExternalServiceID: id}); err != nil {
+			return nil, err
+		}
+	}
+
+	var saved []*types.ExternalService
+	err = r.db.WithTransact(ctx, func(tx database.DB) error {
+		// Plan again within the transaction, so that the changes are based on
+		// the configuration they are applied to.
+		var siteConfigID int32
+		plan, siteConfigID, err = planInstanceConfiguration(ctx, tx, snapshot)
+		if err != nil {
+			return err
+		}
+		saved, err = applyInstanceConfigurationPlan(ctx, tx, plan, siteConfigID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &instanceConfigurationDiffResolver{changes: plan.Changes, applied: true}
+	if plan.SiteConfiguration != nil {
+		server := globals.ConfigurationServerFrontendOnly
+		server.Reload()
+		res.restartRequired = server.NeedServerRestart()
+	}
+
+	// Validate the added and updated external services once they are committed,
+	// as the sync happens in repo-updater.
+	externalServices := backend.NewExternalServices(r.logger, r.db, r.repoupdaterClient)
+	for _, svc := range saved {
+		if err := externalServices.SyncExternalService(ctx, svc, syncExternalServiceTimeout); err != nil {
+			res.warnings = append(res.warnings, fmt.Sprintf("External service %q saved, but we encountered a problem while validating the external service: %s", svc.DisplayName, err))
+		}
+	}
+
+	return res, nil
+}
+
+// planInstanceConfiguration loads the configuration managed by the snapshot and
+// returns the plan that converges it to the snapshot, along with the ID of the
+// current site configuration.
+func planInstanceConfiguration(ctx context.Context, db database.DB, snapshot *instanceconfig.Snapshot) (*instanceconfig.Plan, int32, error) {
+	var state instanceconfig.State
+
+	site, err := db.Conf().SiteGetLatest(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	state.SiteConfiguration = site.Contents
+
+	if snapshot.ExternalServices != nil {
+		services, err := db.ExternalServices().List(ctx, database.ExternalServicesListOptions{})
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, svc := range services {
+			config, err := svc.Config.Decrypt(ctx)
+			if err != nil {
+				return nil, 0, err
+			}
+			state.ExternalServices = append(state.ExternalServices, &instanceconfig.ExternalServiceState{
+				ID:          svc.ID,
+				Kind:        svc.Kind,
+				DisplayName: svc.DisplayName,
+				Config:      config,
+			})
+		}
+	}
+
+	if snapshot.CodePolicies != nil {
+		if state.CodePolicies, err = codepolicies.NewStore(db).ListPolicies(ctx); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	plan, err := instanceconfig.NewPlan(&state, snapshot)
+	if err != nil {
+		return nil, 0, err
+	}
+	return plan, site.ID, nil
+}
+
+// applyInstanceConfigurationPlan applies the plan within the given transaction,
+// and returns the external services it added or updated.
+func applyInstanceConfigurationPlan(ctx context.Context, tx database.DB, plan *instanceconfig.Plan, siteConfigID int32) ([]*types.ExternalService, error) {
+	uid := actor.FromContext(ctx).UID
+
+	if plan.SiteConfiguration != nil {
+		if _, err := tx.Conf().SiteCreateIfUpToDate(ctx, &siteConfigID, uid, *plan.SiteConfiguration, false); err != nil {
+			return nil, err
+		}
+	}
+
+	var saved []*types.ExternalService
+	for _, svc := range plan.CreateExternalServices {
+		externalService := &types.ExternalService{
+			Kind:        svc.Kind,
+			DisplayName: svc.DisplayName,
+			Config:      extsvc.NewUnencryptedConfig(string(svc.Config)),
+		}
+		if err := tx.ExternalServices().Create(ctx, conf.Get, externalService); err != nil {
+			return nil, errors.Wrapf(err, "creating external service %q", svc.DisplayName)
+		}
+		saved = append(saved, externalService)
+	}
+	for _, update := range plan.UpdateExternalServices {
+		config := update.Config
+		if err := tx.ExternalServices().Update(ctx, conf.Get().AuthProviders, update.ID, &database.ExternalServiceUpdate{Config: &config}); err != nil {
+			return nil, errors.Wrapf(err, "updating external service %d", update.ID)
+		}
+		externalService, err := tx.ExternalServices().GetByID(ctx, update.ID)
+		if err != nil {
+			return nil, err
+		}
+		saved = append(saved, externalService)
+	}
+	for _, id := range plan.DeleteExternalServices {
+		if err := tx.ExternalServices().Delete(ctx, id); err != nil {
+			return nil, errors.Wrapf(err, "deleting external service %d", id)
+		}
+	}
+
+	policies := codepolicies.NewStore(tx)
+	for _, p := range plan.CreateCodePolicies {
+		p.CreatorID = &uid
+		if _, err := policies.CreatePolicy(ctx, p); err != nil {
+			return nil, errors.Wrapf(err, "creating code policy %q", p.Name)
+		}
+	}
+	for _, p := range plan.UpdateCodePolicies {
+		if _, err := policies.UpdatePolicy(ctx, p); err != nil {
+			return nil, errors.Wrapf(err, "updating code policy %q", p.Name)
+		}
+	}
+	for _, id := range plan.DeleteCodePolicies {
+		if err := policies.DeletePolicy(ctx, id); err != nil {
+			return nil, errors.Wrapf(err, "deleting code policy %d", id)
+		}
+	}
+
+	return saved, nil
+}
+
+type instanceConfigurationDiffResolver struct {
+	changes         []instanceconfig.Change
+	applied         bool
+	restartRequired bool
+	warnings        []string
+}
+
+func (r *instanceConfigurationDiffResolver) Changes() []*instanceConfigurationChangeResolver {
+	changes := make([]*instanceConfigurationChangeResolver, 0, len(r.changes))
+	for _, change := range r.changes {
+		changes = append(changes, &instanceConfigurationChangeResolver{change: change})
+	}
+	return changes
+}
+
+func (r *instanceConfigurationDiffResolver) Applied() bool { return r.applied }
+
+func (r *instanceConfigurationDiffResolver) RestartRequired() bool { return r.restartRequired }
+
+func (r *instanceConfigurationDiffResolver) Warnings() []string {
+	if r.warnings == nil {
+		return []string{}
+	}
+	return r.warnings
+}
+
+type instanceConfigurationChangeResolver struct {
+	change instanceconfig.Change
+}
+
+func (r *instanceConfigurationChangeResolver) Kind() string { return string(r.change.Kind) }
+
+func (r *instanceConfigurationChangeResolver) Action() string { return string(r.change.Action) }
+
+func (r *instanceConfigurationChangeResolver) Name() string { return r.change.Name }
+
+func (r *instanceConfigurationChangeResolver) Fields() []string {
+	if r.change.Fields == nil {
+		return []string{}
+	}
+	return r.change.Fields
+}
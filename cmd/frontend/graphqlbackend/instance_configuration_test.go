@@ -0,0 +1,126 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestApplyInstanceConfiguration(t *testing.T) {
+	users := database.NewMockUserStore()
+	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+
+	conf := database.NewMockConfStore()
+	conf.SiteGetLatestFunc.SetDefaultReturn(&database.SiteConfig{ID: 1, Contents: `{"externalURL": "https://sourcegraph.example.com"}`}, nil)
+
+	externalServices := database.NewMockExternalServiceStore()
+	externalServices.ListFunc.SetDefaultReturn([]*types.ExternalService{
+		{ID: 1, Kind: extsvc.KindGitHub, DisplayName: "GitHub", Config: extsvc.NewUnencryptedConfig(`{"url": "https://github.com", "token": "abc"}`)},
+		{ID: 2, Kind: extsvc.KindGitLab, DisplayName: "GitLab", Config: extsvc.NewUnencryptedConfig(`{"url": "https://gitlab.com", "token": "abc"}`)},
+	}, nil)
+
+	db := database.NewMockDB()
+	db.WithTransactFunc.SetDefaultHook(func(ctx context.Context, f func(database.DB) error) error {
+		return f(db)
+	})
+	db.UsersFunc.SetDefaultReturn(users)
+	db.ConfFunc.SetDefaultReturn(conf)
+	db.ExternalServicesFunc.SetDefaultReturn(externalServices)
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+
+	t.Run("dry run", func(t *testing.T) {
+		RunTest(t, &Test{
+			Context: ctx,
+			Schema:  mustParseGraphQLSchema(t, db),
+			Query: `
+				mutation {
+					applyInstanceConfiguration(snapshot: "{\"externalServices\": [{\"kind\": \"GITHUB\", \"displayName\": \"GitHub\", \"config\": {\"url\": \"https://github.com\", \"token\": \"def\"}}]}", dryRun: true) {
+						changes {
+							kind
+							action
+							name
+							fields
+						}
+						applied
+						restartRequired
+						warnings
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"applyInstanceConfiguration": {
+						"changes": [
+							{"kind": "EXTERNAL_SERVICE", "action": "UPDATE", "name": "GITHUB/GitHub", "fields": ["config.token"]},
+							{"kind": "EXTERNAL_SERVICE", "action": "DELETE", "name": "GITLAB/GitLab", "fields": []}
+						],
+						"applied": false,
+						"restartRequired": false,
+						"warnings": []
+					}
+				}
+			`,
+		})
+
+		// Nothing is written on a dry run.
+		assert.Len(t, db.WithTransactFunc.History(), 0)
+		assert.Len(t, externalServices.UpdateFunc.History(), 0)
+		assert.Len(t, externalServices.DeleteFunc.History(), 0)
+	})
+
+	t.Run("invalid snapshot", func(t *testing.T) {
+		RunTest(t, &Test{
+			Context: ctx,
+			Schema:  mustParseGraphQLSchema(t, db),
+			Query: `
+				mutation {
+					applyInstanceConfiguration(snapshot: "{\"users\": []}") {
+						applied
+					}
+				}
+			`,
+			ExpectedResult: `null`,
+			ExpectedErrors: []*gqlerrors.QueryError{
+				{
+					Path:    []any{"applyInstanceConfiguration"},
+					Message: `unknown snapshot section "users"`,
+				},
+			},
+		})
+	})
+
+	t.Run("non site admin", func(t *testing.T) {
+		users := database.NewMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 2}, nil)
+		db := database.NewMockDBFrom(db)
+		db.UsersFunc.SetDefaultReturn(users)
+
+		RunTest(t, &Test{
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 2}),
+			Schema:  mustParseGraphQLSchema(t, db),
+			Query: `
+				mutation {
+					applyInstanceConfiguration(snapshot: "{}") {
+						applied
+					}
+				}
+			`,
+			ExpectedResult: `null`,
+			ExpectedErrors: []*gqlerrors.QueryError{
+				{
+					Path:    []any{"applyInstanceConfiguration"},
+					Message: auth.ErrMustBeSiteAdmin.Error(),
+				},
+			},
+		})
+	})
+}
@@ -0,0 +1,168 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const notificationIDKind = "Notification"
+
+type notificationsArgs struct {
+	First      int32
+	After      *string
+	UnreadOnly bool
+}
+
+func (r *schemaResolver) Notifications(ctx context.Context, args *notificationsArgs) (*notificationConnectionResolver, error) {
+	// 🚨 SECURITY: Users can only list their own notifications.
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, auth.ErrNotAuthenticated
+	}
+
+	opts := database.NotificationListOpts{
+		UserID:     a.UID,
+		UnreadOnly: args.UnreadOnly,
+		Limit:      int(args.First),
+	}
+	if args.After != nil {
+		var err error
+		opts.Cursor, err = strconv.ParseInt(*args.After, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing the after cursor")
+		}
+	}
+
+	return &notificationConnectionResolver{store: r.db.Notifications(), opts: opts}, nil
+}
+
+type markNotificationsReadArgs struct {
+	Notifications *[]graphql.ID
+}
+
+func (r *schemaResolver) MarkNotificationsRead(ctx context.Context, args *markNotificationsReadArgs) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Users can only mark their own notifications as read, which the
+	// store enforces by the user ID.
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, auth.ErrNotAuthenticated
+	}
+
+	var ids []int64
+	if args.Notifications != nil {
+		ids = make([]int64, 0, len(*args.Notifications))
+		for _, id := range *args.Notifications {
+			notificationID, err := unmarshalNotificationID(id)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, notificationID)
+		}
+	}
+
+	if err := r.db.Notifications().MarkRead(ctx, a.UID, ids); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func marshalNotificationID(id int64) graphql.ID {
+	return relay.MarshalID(notificationIDKind, id)
+}
+
+func unmarshalNotificationID(id graphql.ID) (notificationID int64, err error) {
+	if kind := relay.UnmarshalKind(id); kind != notificationIDKind {
+		return 0, errors.Newf("expected a notification ID, got a %s ID", kind)
+	}
+	err = relay.UnmarshalSpec(id, &notificationID)
+	return notificationID, err
+}
+
+type notificationConnectionResolver struct {
+	store database.NotificationStore
+	opts  database.NotificationListOpts
+
+	once          sync.Once
+	notifications []*types.Notification
+	next          int64
+	err           error
+}
+
+func (r *notificationConnectionResolver) compute(ctx context.Context) ([]*types.Notification, int64, error) {
+	r.once.Do(func() {
+		r.notifications, r.next, r.err = r.store.List(ctx, r.opts)
+	})
+	return r.notifications, r.next, r.err
+}
+
+func (r *notificationConnectionResolver) Nodes(ctx context.Context) ([]*notificationResolver, error) {
+	notifications, _, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*notificationResolver, len(notifications))
+	for i, n := range notifications {
+		nodes[i] = &notificationResolver{notification: n}
+	}
+	return nodes, nil
+}
+
+func (r *notificationConnectionResolver) UnreadCount(ctx context.Context) (int32, error) {
+	count, err := r.store.CountUnread(ctx, r.opts.UserID)
+	return int32(count), err
+}
+
+func (r *notificationConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	_, next, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if next == 0 {
+		return graphqlutil.HasNextPage(false), nil
+	}
+	return graphqlutil.NextPageCursor(fmt.Sprint(next)), nil
+}
+
+type notificationResolver struct {
+	notification *types.Notification
+}
+
+func (r *notificationResolver) ID() graphql.ID {
+	return marshalNotificationID(r.notification.ID)
+}
+
+func (r *notificationResolver) Kind() string { return string(r.notification.Kind) }
+
+func (r *notificationResolver) Title() string { return r.notification.Title }
+
+func (r *notificationResolver) Body() *string {
+	if r.notification.Body == "" {
+		return nil
+	}
+	return &r.notification.Body
+}
+
+func (r *notificationResolver) URL() string { return r.notification.URL }
+
+func (r *notificationResolver) ReadAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.notification.ReadAt)
+}
+
+func (r *notificationResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.notification.CreatedAt}
+}
@@ -0,0 +1,71 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestNotifications(t *testing.T) {
+	createdAt := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	store := database.NewMockNotificationStore()
+	store.ListFunc.SetDefaultReturn([]*types.Notification{{
+		ID:        2,
+		UserID:    1,
+		Kind:      types.NotificationKindPermissionSync,
+		Title:     "Permission sync failed",
+		URL:       "/site-admin/permissions-syncs",
+		CreatedAt: createdAt,
+	}}, 1, nil)
+	store.CountUnreadFunc.SetDefaultReturn(3, nil)
+	db := database.NewMockDB()
+	db.NotificationsFunc.SetDefaultReturn(store)
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	schema := mustParseGraphQLSchema(t, db)
+
+	RunTest(t, &Test{
+		Context: ctx,
+		Schema:  schema,
+		Query: `{
+			notifications(first: 1, unreadOnly: true) {
+				nodes { id kind title body url readAt createdAt }
+				unreadCount
+				pageInfo { hasNextPage endCursor }
+			}
+		}`,
+		ExpectedResult: `{
+			"notifications": {
+				"nodes": [{
+					"id": "Tm90aWZpY2F0aW9uOjI=",
+					"kind": "PERMISSION_SYNC",
+					"title": "Permission sync failed",
+					"body": null,
+					"url": "/site-admin/permissions-syncs",
+					"readAt": null,
+					"createdAt": "2023-07-01T00:00:00Z"
+				}],
+				"unreadCount": 3,
+				"pageInfo": { "hasNextPage": true, "endCursor": "1" }
+			}
+		}`,
+	})
+	opts := store.ListFunc.History()[0].Arg1
+	assert.Equal(t, database.NotificationListOpts{UserID: 1, UnreadOnly: true, Limit: 1}, opts)
+
+	RunTest(t, &Test{
+		Context:        ctx,
+		Schema:         schema,
+		Query:          `mutation { markNotificationsRead(notifications: ["Tm90aWZpY2F0aW9uOjI="]) { alwaysNil } }`,
+		ExpectedResult: `{ "markNotificationsRead": { "alwaysNil": null } }`,
+	})
+	call := store.MarkReadFunc.History()[0]
+	assert.Equal(t, int32(1), call.Arg1)
+	assert.Equal(t, []int64{2}, call.Arg2)
+}
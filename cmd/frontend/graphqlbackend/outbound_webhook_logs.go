@@ -3,6 +3,7 @@ package graphqlbackend
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
@@ -45,6 +46,9 @@ type OutboundWebhookJobResolver interface {
 	EventType() (string, error)
 	Scope() (*string, error)
 	Payload(context.Context) (string, error)
+	State() (string, error)
+	FailureMessage() (*string, error)
+	NextAttemptAt() (*gqlutil.DateTime, error)
 }
 
 type outboundWebhookLogStatsResolver struct {
@@ -199,6 +203,7 @@ func newOutboundWebhookJobResolver(
 	id int64,
 ) OutboundWebhookJobResolver {
 	return &outboundWebhookJobResolver{
+		id: id,
 		job: syncx.OnceValues(func() (*types.OutboundWebhookJob, error) {
 			return store.GetByID(ctx, id)
 		}),
@@ -241,6 +246,37 @@ func (r *outboundWebhookJobResolver) Payload(ctx context.Context) (string, error
 	return payload, nil
 }
 
+func (r *outboundWebhookJobResolver) State() (string, error) {
+	job, err := r.job()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ToUpper(job.State), nil
+}
+
+func (r *outboundWebhookJobResolver) FailureMessage() (*string, error) {
+	job, err := r.job()
+	if err != nil {
+		return nil, err
+	}
+
+	return job.FailureMessage, nil
+}
+
+func (r *outboundWebhookJobResolver) NextAttemptAt() (*gqlutil.DateTime, error) {
+	job, err := r.job()
+	if err != nil {
+		return nil, err
+	}
+
+	// Jobs are requeued with a delay when a delivery fails.
+	if job.State != "queued" || job.ProcessAfter == nil {
+		return nil, nil
+	}
+	return &gqlutil.DateTime{Time: *job.ProcessAfter}, nil
+}
+
 func marshalOutboundWebhookJobID(id int64) graphql.ID {
 	return relay.MarshalID(outboundWebhookJobIDKind, id)
 }
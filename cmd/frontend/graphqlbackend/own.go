@@ -87,6 +87,16 @@ type OwnershipStatsResolver interface {
 	TotalOwnedFiles(context.Context) (int32, error)
 	TotalAssignedOwnershipFiles(context.Context) (int32, error)
 	UpdatedAt(ctx context.Context) (*gqlutil.DateTime, error)
+	TeamCoverage(context.Context, *TeamCoverageArgs) ([]TeamOwnershipCoverageResolver, error)
+}
+
+type TeamCoverageArgs struct {
+	First *int32
+}
+
+type TeamOwnershipCoverageResolver interface {
+	Team() *TeamResolver
+	OwnedFiles() int32
 }
 
 type Ownable interface {
@@ -16,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -134,34 +135,57 @@ type repositoriesConnectionStore struct {
 
 func (s *repositoriesConnectionStore) MarshalCursor(node *RepositoryResolver, orderBy database.OrderBy) (*string, error) {
 	column := orderBy[0].Field
-	var value string
+	var value any
 
 	switch database.RepoListColumn(column) {
 	case database.RepoListName:
 		value = node.Name()
 	case database.RepoListCreatedAt:
-		value = fmt.Sprintf("'%v'", node.RawCreatedAt())
+		if node.innerRepo == nil {
+			return nil, errors.New("repository creation time is not loaded")
+		}
+		value = node.innerRepo.CreatedAt
 	case database.RepoListSize:
 		size, err := node.DiskSizeBytes(s.ctx)
 		if err != nil {
 			return nil, err
 		}
-		value = strconv.FormatInt(int64(*size), 10)
+		var bytes int64
+		if size != nil {
+			bytes = int64(*size)
+		}
+		value = bytes
 	default:
 		return nil, errors.New(fmt.Sprintf("invalid OrderBy.Field. Expected: one of (name, created_at, gr.repo_size_bytes). Actual: %s", column))
 	}
 
+	keysetCursor, err := database.EncodeKeysetCursor(value, node.IDInt32())
+	if err != nil {
+		return nil, err
+	}
+
 	cursor := MarshalRepositoryCursor(
 		&types.Cursor{
 			Column: column,
-			Value:  fmt.Sprintf("%s@%d", value, node.IDInt32()),
+			Value:  keysetCursor,
 		},
 	)
 
 	return &cursor, nil
 }
 
+// UnmarshalCursor validates the given cursor and returns it unchanged. The cursor
+// values are decoded by ComputeNodes and bound as query arguments.
 func (s *repositoriesConnectionStore) UnmarshalCursor(cursor string, orderBy database.OrderBy) (*string, error) {
+	if _, err := unmarshalRepositoryKeysetCursor(cursor, orderBy); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// unmarshalRepositoryKeysetCursor returns the values of the ordering columns held
+// by the given repository cursor.
+func unmarshalRepositoryKeysetCursor(cursor string, orderBy database.OrderBy) ([]any, error) {
 	repoCursor, err := UnmarshalRepositoryCursor(&cursor)
 	if err != nil {
 		return nil, err
@@ -176,24 +200,66 @@ func (s *repositoriesConnectionStore) UnmarshalCursor(cursor string, orderBy dat
 		return nil, errors.New(fmt.Sprintf("Invalid cursor. Expected: %s Actual: %s", column, repoCursor.Column))
 	}
 
-	csv := ""
-	values := strings.Split(repoCursor.Value, "@")
-	if len(values) != 2 {
-		return nil, errors.New(fmt.Sprintf("Invalid cursor. Expected Value: <%s>@<id> Actual Value: %s", column, repoCursor.Value))
+	if values, ok := unmarshalLegacyRepositoryCursorValue(column, repoCursor.Value); ok {
+		return values, nil
 	}
 
+	var id int32
 	switch database.RepoListColumn(column) {
 	case database.RepoListName:
-		csv = fmt.Sprintf("'%v', %v", values[0], values[1])
+		var name string
+		if err := database.DecodeKeysetCursor(repoCursor.Value, &name, &id); err != nil {
+			return nil, err
+		}
+		return []any{name, id}, nil
 	case database.RepoListCreatedAt:
-		csv = fmt.Sprintf("%v, %v", values[0], values[1])
+		var createdAt time.Time
+		if err := database.DecodeKeysetCursor(repoCursor.Value, &createdAt, &id); err != nil {
+			return nil, err
+		}
+		return []any{createdAt, id}, nil
 	case database.RepoListSize:
-		csv = fmt.Sprintf("%v, %v", values[0], values[1])
+		var size int64
+		if err := database.DecodeKeysetCursor(repoCursor.Value, &size, &id); err != nil {
+			return nil, err
+		}
+		return []any{size, id}, nil
 	default:
 		return nil, errors.New("Invalid OrderBy Field.")
 	}
+}
+
+// unmarshalLegacyRepositoryCursorValue parses cursor values in the format used
+// before keyset cursors, `<value>@<id>`, so that clients holding such a cursor
+// can continue paginating. It returns false if the value is not in that format.
+func unmarshalLegacyRepositoryCursorValue(column, value string) ([]any, bool) {
+	i := strings.LastIndex(value, "@")
+	if i < 0 {
+		return nil, false
+	}
+	id, err := strconv.ParseInt(value[i+1:], 10, 32)
+	if err != nil {
+		return nil, false
+	}
+	value = value[:i]
 
-	return &csv, err
+	switch database.RepoListColumn(column) {
+	case database.RepoListName:
+		return []any{value, int32(id)}, true
+	case database.RepoListCreatedAt:
+		createdAt, err := time.Parse(time.RFC3339, strings.Trim(value, "'"))
+		if err != nil {
+			return nil, false
+		}
+		return []any{createdAt, int32(id)}, true
+	case database.RepoListSize:
+		size, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		return []any{size, int32(id)}, true
+	}
+	return nil, false
 }
 
 func i32ptr(v int32) *int32 { return &v }
@@ -210,16 +276,31 @@ func (s *repositoriesConnectionStore) ComputeTotal(ctx context.Context) (countpt
 		return i32ptr(int32(0)), nil
 	}
 
-	count, err := s.db.Repos().Count(ctx, s.opt)
+	// Repository listings tolerate stale results and may be served by a read replica.
+	count, err := s.db.Repos().Count(basestore.WithStaleReads(ctx), s.opt)
 	return i32ptr(int32(count)), err
 }
 
 func (s *repositoriesConnectionStore) ComputeNodes(ctx context.Context, args *database.PaginationArgs) ([]*RepositoryResolver, error) {
 	opt := s.opt
-	opt.PaginationArgs = args
+	if args != nil {
+		keyset, err := repositoriesKeyset(args)
+		if err != nil {
+			return nil, err
+		}
+		opt.Keyset = keyset
+
+		// The connection resolver already requests one node more than the page
+		// size to detect further pages.
+		if args.First != nil {
+			opt.LimitOffset = &database.LimitOffset{Limit: *args.First}
+		} else if args.Last != nil {
+			opt.LimitOffset = &database.LimitOffset{Limit: *args.Last}
+		}
+	}
 
 	client := gitserver.NewClient()
-	repos, err := backend.NewRepos(s.logger, s.db, client).List(ctx, opt)
+	repos, err := backend.NewRepos(s.logger, s.db, client).List(basestore.WithStaleReads(ctx), opt)
 	if err != nil {
 		return nil, err
 	}
@@ -232,6 +313,32 @@ func (s *repositoriesConnectionStore) ComputeNodes(ctx context.Context, args *da
 	return resolvers, nil
 }
 
+// repositoriesKeyset converts the pagination arguments of the connection resolver
+// into a keyset pagination without a limit. When paginating backwards with `last`,
+// the ordering is reversed and the page is fetched after the `before` cursor.
+func repositoriesKeyset(args *database.PaginationArgs) (*database.KeysetPagination, error) {
+	descending := !args.Ascending
+	cursor := args.After
+	if args.Last != nil {
+		descending = !descending
+		cursor = args.Before
+	}
+
+	p := &database.KeysetPagination{}
+	for _, column := range args.OrderBy {
+		p.Columns = append(p.Columns, database.KeysetColumn{Field: column.Field, Descending: descending})
+	}
+	if cursor != nil {
+		values, err := unmarshalRepositoryKeysetCursor(*cursor, args.OrderBy)
+		if err != nil {
+			return nil, err
+		}
+		p.After = values
+	}
+
+	return p, nil
+}
+
 // NOTE(naman): The old resolver `RepositoryConnectionResolver` defined below is
 // deprecated and replaced by `graphqlutil.ConnectionResolver` above which implements
 // proper cursor-based pagination and do not support `precise` argument for totalCount.
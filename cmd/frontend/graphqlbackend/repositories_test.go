@@ -7,7 +7,9 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/graph-gophers/graphql-go"
 	gqlerrors "github.com/graph-gophers/graphql-go/errors"
 
@@ -24,10 +26,11 @@ import (
 )
 
 func buildCursor(node *types.Repo) *string {
+	value, _ := database.EncodeKeysetCursor(string(node.Name), int32(node.ID))
 	cursor := MarshalRepositoryCursor(
 		&types.Cursor{
 			Column: "name",
-			Value:  fmt.Sprintf("%s@%d", node.Name, node.ID),
+			Value:  value,
 		},
 	)
 
@@ -35,10 +38,11 @@ func buildCursor(node *types.Repo) *string {
 }
 
 func buildCursorBySize(node *types.Repo, size int64) *string {
+	value, _ := database.EncodeKeysetCursor(size, int32(node.ID))
 	cursor := MarshalRepositoryCursor(
 		&types.Cursor{
 			Column: "gr.repo_size_bytes",
-			Value:  fmt.Sprintf("%d@%d", size, node.ID),
+			Value:  value,
 		},
 	)
 
@@ -703,6 +707,101 @@ func TestRepositories_CursorPagination(t *testing.T) {
 	})
 }
 
+func TestUnmarshalRepositoryKeysetCursor(t *testing.T) {
+	createdAt := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	legacyCursor := func(column, value string) string {
+		return MarshalRepositoryCursor(&types.Cursor{Column: column, Value: value})
+	}
+	keysetCursor := func(column string, values ...any) string {
+		value, err := database.EncodeKeysetCursor(values...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return MarshalRepositoryCursor(&types.Cursor{Column: column, Value: value})
+	}
+
+	tests := []struct {
+		name    string
+		column  string
+		cursor  string
+		want    []any
+		wantErr bool
+	}{
+		{
+			name:   "name",
+			column: "name",
+			cursor: keysetCursor("name", "github.com/sourcegraph/sourcegraph", 5),
+			want:   []any{"github.com/sourcegraph/sourcegraph", int32(5)},
+		},
+		{
+			name:   "created_at",
+			column: "created_at",
+			cursor: keysetCursor("created_at", createdAt, 5),
+			want:   []any{createdAt, int32(5)},
+		},
+		{
+			name:   "size",
+			column: "gr.repo_size_bytes",
+			cursor: keysetCursor("gr.repo_size_bytes", 1024, 5),
+			want:   []any{int64(1024), int32(5)},
+		},
+		{
+			name:   "legacy name",
+			column: "name",
+			cursor: legacyCursor("name", "github.com/sourcegraph/sourcegraph@5"),
+			want:   []any{"github.com/sourcegraph/sourcegraph", int32(5)},
+		},
+		{
+			name:   "legacy created_at",
+			column: "created_at",
+			cursor: legacyCursor("created_at", "'2023-06-01T12:00:00Z'@5"),
+			want:   []any{createdAt, int32(5)},
+		},
+		{
+			name:   "legacy size",
+			column: "gr.repo_size_bytes",
+			cursor: legacyCursor("gr.repo_size_bytes", "1024@5"),
+			want:   []any{int64(1024), int32(5)},
+		},
+		{
+			name:    "legacy invalid id",
+			column:  "name",
+			cursor:  legacyCursor("name", "github.com/sourcegraph/sourcegraph@five"),
+			wantErr: true,
+		},
+		{
+			name:    "legacy invalid size",
+			column:  "gr.repo_size_bytes",
+			cursor:  legacyCursor("gr.repo_size_bytes", "large@5"),
+			wantErr: true,
+		},
+		{
+			name:    "other column",
+			column:  "created_at",
+			cursor:  legacyCursor("name", "github.com/sourcegraph/sourcegraph@5"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			have, err := unmarshalRepositoryKeysetCursor(tt.cursor, database.OrderBy{{Field: tt.column}})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got values %v", have)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, have); diff != "" {
+				t.Errorf("unexpected values (-want +have):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestRepositories_Integration(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -516,6 +516,10 @@ func (r *RepositoryResolver) CodeIntelSummary(ctx context.Context) (resolverstub
 	return EnterpriseResolvers.codeIntelResolver.RepositorySummary(ctx, r.ID())
 }
 
+func (r *RepositoryResolver) CodeIntelCoverage(ctx context.Context) (resolverstubs.CodeIntelRepositoryCoverageResolver, error) {
+	return EnterpriseResolvers.codeIntelResolver.RepositoryCoverage(ctx, r.ID())
+}
+
 func (r *RepositoryResolver) PreviewGitObjectFilter(ctx context.Context, args *resolverstubs.PreviewGitObjectFilterArgs) (resolverstubs.GitObjectFilterPreviewResolver, error) {
 	return EnterpriseResolvers.codeIntelResolver.PreviewGitObjectFilter(ctx, r.ID(), args)
 }
@@ -0,0 +1,234 @@
+package graphqlbackend
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type repositoryArchivalPolicyResolver struct {
+	db     database.DB
+	policy *database.RepoArchivalPolicy
+}
+
+func (r *repositoryArchivalPolicyResolver) Enabled() bool { return r.policy.Enabled }
+
+func (r *repositoryArchivalPolicyResolver) InactiveMonths() int32 { return r.policy.InactiveMonths }
+
+func (r *repositoryArchivalPolicyResolver) ReduceSyncFrequency() bool {
+	return r.policy.ReduceSyncFrequency
+}
+
+func (r *repositoryArchivalPolicyResolver) DropEmbeddings() bool { return r.policy.DropEmbeddings }
+
+func (r *repositoryArchivalPolicyResolver) DropCodeIntel() bool { return r.policy.DropCodeIntel }
+
+func (r *repositoryArchivalPolicyResolver) UpdatedBy(ctx context.Context) (*UserResolver, error) {
+	if r.policy.UpdatedBy == nil {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, r.db, *r.policy.UpdatedBy)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *repositoryArchivalPolicyResolver) UpdatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.policy.UpdatedAt}
+}
+
+type repositoryArchivalResolver struct {
+	repo *RepositoryResolver
+	// archival is nil for repositories that have never been archived and have
+	// no override.
+	archival *database.RepoArchival
+}
+
+func (r *repositoryArchivalResolver) Repository() *RepositoryResolver { return r.repo }
+
+func (r *repositoryArchivalResolver) Archived() bool { return r.archival.Archived() }
+
+func (r *repositoryArchivalResolver) Override() *string {
+	if r.archival == nil || r.archival.Override == "" {
+		return nil
+	}
+	override := strings.ToUpper(string(r.archival.Override))
+	return &override
+}
+
+func (r *repositoryArchivalResolver) ArchivedAt() *gqlutil.DateTime {
+	if r.archival == nil {
+		return nil
+	}
+	return gqlutil.DateTimeOrNil(r.archival.ArchivedAt)
+}
+
+func (r *repositoryArchivalResolver) RestoredAt() *gqlutil.DateTime {
+	if r.archival == nil {
+		return nil
+	}
+	return gqlutil.DateTimeOrNil(r.archival.RestoredAt)
+}
+
+func (r *RepositoryResolver) Archival(ctx context.Context) (*repositoryArchivalResolver, error) {
+	// 🚨 SECURITY: Only site admins can view the archival state of repositories.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	archival, err := r.db.RepoArchival().Get(ctx, r.IDInt32())
+	if err != nil {
+		return nil, err
+	}
+	return &repositoryArchivalResolver{repo: r, archival: archival}, nil
+}
+
+func (r *schemaResolver) RepositoryArchivalPolicy(ctx context.Context) (*repositoryArchivalPolicyResolver, error) {
+	// 🚨 SECURITY: Only site admins can view the archival policy.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	policy, err := r.db.RepoArchival().GetPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &repositoryArchivalPolicyResolver{db: r.db, policy: policy}, nil
+}
+
+func (r *schemaResolver) UpdateRepositoryArchivalPolicy(ctx context.Context, args *struct {
+	Enabled             bool
+	InactiveMonths      int32
+	ReduceSyncFrequency bool
+	DropEmbeddings      bool
+	DropCodeIntel       bool
+},
+) (*repositoryArchivalPolicyResolver, error) {
+	// 🚨 SECURITY: Only site admins can update the archival policy.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	a := actor.FromContext(ctx)
+	policy, err := r.db.RepoArchival().UpdatePolicy(ctx, database.RepoArchivalPolicy{
+		Enabled:             args.Enabled,
+		InactiveMonths:      args.InactiveMonths,
+		ReduceSyncFrequency: args.ReduceSyncFrequency,
+		DropEmbeddings:      args.DropEmbeddings,
+		DropCodeIntel:       args.DropCodeIntel,
+	}, a.UID)
+	if err != nil {
+		return nil, err
+	}
+	logRepoArchivalChange(ctx, r.db, database.SecurityEventNameRepoArchivalPolicyUpdated, a.UID, struct {
+		Enabled             bool  `json:"enabled"`
+		InactiveMonths      int32 `json:"inactiveMonths"`
+		ReduceSyncFrequency bool  `json:"reduceSyncFrequency"`
+		DropEmbeddings      bool  `json:"dropEmbeddings"`
+		DropCodeIntel       bool  `json:"dropCodeIntel"`
+	}(*args))
+
+	return &repositoryArchivalPolicyResolver{db: r.db, policy: policy}, nil
+}
+
+func (r *schemaResolver) SetRepositoryArchivalOverride(ctx context.Context, args *struct {
+	Repository graphql.ID
+	Override   *string
+},
+) (*repositoryArchivalResolver, error) {
+	// 🚨 SECURITY: Only site admins can override the archival policy.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	// Looking up the repository rejects repositories that have already been deleted.
+	repo, err := r.repositoryByID(ctx, args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	var override database.RepoArchivalOverride
+	if args.Override != nil {
+		override = database.RepoArchivalOverride(strings.ToLower(*args.Override))
+	}
+
+	a := actor.FromContext(ctx)
+	archival, err := r.db.RepoArchival().SetOverride(ctx, repo.IDInt32(), override, a.UID)
+	if err != nil {
+		return nil, err
+	}
+	logRepoArchivalChange(ctx, r.db, database.SecurityEventNameRepoArchivalOverrideUpdated, a.UID, struct {
+		RepoID   api.RepoID                    `json:"repoID"`
+		RepoName api.RepoName                  `json:"repoName"`
+		Override database.RepoArchivalOverride `json:"override"`
+	}{repo.IDInt32(), repo.RepoName(), override})
+
+	return &repositoryArchivalResolver{repo: repo, archival: archival}, nil
+}
+
+func (r *schemaResolver) RestoreArchivedRepository(ctx context.Context, args *struct {
+	Repository graphql.ID
+},
+) (*repositoryArchivalResolver, error) {
+	// 🚨 SECURITY: Only site admins can restore archived repositories.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	repo, err := r.repositoryByID(ctx, args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	archival, err := r.db.RepoArchival().Get(ctx, repo.IDInt32())
+	if err != nil {
+		return nil, err
+	}
+	if !archival.Archived() {
+		return nil, errors.New("repository is not archived")
+	}
+
+	a := actor.FromContext(ctx)
+	if archival, err = r.db.RepoArchival().Restore(ctx, repo.IDInt32(), a.UID); err != nil {
+		return nil, err
+	}
+	logRepoArchivalChange(ctx, r.db, database.SecurityEventNameRepoArchivalRestored, a.UID, struct {
+		RepoID   api.RepoID   `json:"repoID"`
+		RepoName api.RepoName `json:"repoName"`
+	}{repo.IDInt32(), repo.RepoName()})
+
+	// The repository may not have been synced for a while, so we sync it right
+	// away. The restoration succeeded even if the sync cannot be enqueued.
+	if _, err := repoupdater.DefaultClient.EnqueueRepoUpdate(ctx, repo.RepoName()); err != nil {
+		r.logger.Warn("failed to enqueue update of restored repository", log.String("repo", string(repo.RepoName())), log.Error(err))
+	}
+
+	return &repositoryArchivalResolver{repo: repo, archival: archival}, nil
+}
+
+func logRepoArchivalChange(ctx context.Context, db database.DB, name database.SecurityEventName, by int32, arg any) {
+	args, _ := json.Marshal(arg)
+
+	db.SecurityEventLogs().LogEvent(ctx, &database.SecurityEvent{
+		Name:      name,
+		UserID:    uint32(by),
+		Argument:  args,
+		Source:    "BACKEND",
+		Timestamp: time.Now(),
+	})
+}
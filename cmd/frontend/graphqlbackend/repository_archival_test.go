@@ -0,0 +1,134 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestRepositoryArchival(t *testing.T) {
+	ctx := context.Background()
+
+	logger := logtest.Scoped(t)
+	db := database.NewMockDBFrom(database.NewDB(logger, dbtest.NewDB(logger, t)))
+
+	users := database.NewMockUserStore()
+	db.UsersFunc.SetDefaultReturn(users)
+
+	err := db.Repos().Create(ctx, &types.Repo{Name: "testrepo"})
+	require.NoError(t, err)
+	repo, err := db.Repos().GetByName(ctx, "testrepo")
+	require.NoError(t, err)
+
+	var enqueued []api.RepoName
+	repoupdater.MockEnqueueRepoUpdate = func(_ context.Context, repo api.RepoName) (*protocol.RepoUpdateResponse, error) {
+		enqueued = append(enqueued, repo)
+		return &protocol.RepoUpdateResponse{}, nil
+	}
+	t.Cleanup(func() { repoupdater.MockEnqueueRepoUpdate = nil })
+
+	schema := newSchemaResolver(db, gitserver.NewClient(), jobutil.NewUnimplementedEnterpriseJobs())
+	gqlID := MarshalRepositoryID(repo.ID)
+
+	policyArgs := &struct {
+		Enabled             bool
+		InactiveMonths      int32
+		ReduceSyncFrequency bool
+		DropEmbeddings      bool
+		DropCodeIntel       bool
+	}{
+		Enabled:        true,
+		InactiveMonths: 6,
+		DropEmbeddings: true,
+	}
+	overrideArgs := func(override *string) *struct {
+		Repository graphql.ID
+		Override   *string
+	} {
+		return &struct {
+			Repository graphql.ID
+			Override   *string
+		}{Repository: gqlID, Override: override}
+	}
+	restoreArgs := &struct {
+		Repository graphql.ID
+	}{
+		Repository: gqlID,
+	}
+
+	t.Run("non-admin", func(t *testing.T) {
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{}, nil)
+
+		_, err := schema.RepositoryArchivalPolicy(ctx)
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+		_, err = schema.UpdateRepositoryArchivalPolicy(ctx, policyArgs)
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+		_, err = schema.SetRepositoryArchivalOverride(ctx, overrideArgs(nil))
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+		_, err = schema.RestoreArchivedRepository(ctx, restoreArgs)
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+		_, err = NewRepositoryResolver(db, nil, repo).Archival(ctx)
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+	})
+
+	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{SiteAdmin: true}, nil)
+
+	t.Run("policy", func(t *testing.T) {
+		policy, err := schema.UpdateRepositoryArchivalPolicy(ctx, policyArgs)
+		require.NoError(t, err)
+		require.True(t, policy.Enabled())
+		require.Equal(t, int32(6), policy.InactiveMonths())
+		require.True(t, policy.DropEmbeddings())
+		require.False(t, policy.DropCodeIntel())
+
+		policy, err = schema.RepositoryArchivalPolicy(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int32(6), policy.InactiveMonths())
+	})
+
+	t.Run("override", func(t *testing.T) {
+		archival, err := NewRepositoryResolver(db, nil, repo).Archival(ctx)
+		require.NoError(t, err)
+		require.False(t, archival.Archived())
+		require.Nil(t, archival.Override())
+
+		always := "ALWAYS"
+		archival, err = schema.SetRepositoryArchivalOverride(ctx, overrideArgs(&always))
+		require.NoError(t, err)
+		require.Equal(t, "ALWAYS", *archival.Override())
+
+		archival, err = schema.SetRepositoryArchivalOverride(ctx, overrideArgs(nil))
+		require.NoError(t, err)
+		require.Nil(t, archival.Override())
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		_, err := schema.RestoreArchivedRepository(ctx, restoreArgs)
+		require.Error(t, err)
+
+		require.NoError(t, db.RepoArchival().Archive(ctx, repo.ID))
+		archival, err := NewRepositoryResolver(db, nil, repo).Archival(ctx)
+		require.NoError(t, err)
+		require.True(t, archival.Archived())
+		require.NotNil(t, archival.ArchivedAt())
+
+		archival, err = schema.RestoreArchivedRepository(ctx, restoreArgs)
+		require.NoError(t, err)
+		require.False(t, archival.Archived())
+		require.NotNil(t, archival.RestoredAt())
+		require.Equal(t, []api.RepoName{repo.Name}, enqueued)
+	})
+}
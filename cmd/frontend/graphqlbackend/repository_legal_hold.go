@@ -0,0 +1,157 @@
+package graphqlbackend
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type repositoryLegalHoldResolver struct {
+	db              database.DB
+	gitserverClient gitserver.Client
+	hold            *database.RepoLegalHold
+}
+
+func (r *repositoryLegalHoldResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	repo, err := r.db.Repos().Get(ctx, r.hold.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	return NewRepositoryResolver(r.db, r.gitserverClient, repo), nil
+}
+
+func (r *repositoryLegalHoldResolver) Reason() string { return r.hold.Reason }
+
+func (r *repositoryLegalHoldResolver) CreatedBy(ctx context.Context) (*UserResolver, error) {
+	if r.hold.CreatedBy == nil {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, r.db, *r.hold.CreatedBy)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *repositoryLegalHoldResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.hold.CreatedAt}
+}
+
+func (r *RepositoryResolver) LegalHold(ctx context.Context) (*repositoryLegalHoldResolver, error) {
+	// 🚨 SECURITY: Only site admins can view legal holds.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	hold, err := r.db.RepoLegalHolds().Get(ctx, r.IDInt32())
+	if err != nil || hold == nil {
+		return nil, err
+	}
+	return &repositoryLegalHoldResolver{db: r.db, gitserverClient: r.gitserverClient, hold: hold}, nil
+}
+
+func (r *schemaResolver) RepositoriesOnLegalHold(ctx context.Context) ([]*repositoryLegalHoldResolver, error) {
+	// 🚨 SECURITY: Only site admins can view legal holds.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	holds, err := r.db.RepoLegalHolds().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*repositoryLegalHoldResolver, 0, len(holds))
+	for _, hold := range holds {
+		resolvers = append(resolvers, &repositoryLegalHoldResolver{db: r.db, gitserverClient: r.gitserverClient, hold: hold})
+	}
+	return resolvers, nil
+}
+
+func (r *schemaResolver) SetRepositoryLegalHold(ctx context.Context, args *struct {
+	Repository graphql.ID
+	Reason     string
+},
+) (*repositoryLegalHoldResolver, error) {
+	// 🚨 SECURITY: Only site admins can place legal holds.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(args.Reason) == "" {
+		return nil, errors.New("a reason is required to place a repository on legal hold")
+	}
+
+	// Looking up the repository rejects repositories that have already been deleted.
+	repo, err := r.repositoryByID(ctx, args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	a := actor.FromContext(ctx)
+	hold, err := r.db.RepoLegalHolds().Create(ctx, repo.IDInt32(), args.Reason, a.UID)
+	if err != nil {
+		return nil, err
+	}
+	logLegalHoldChange(ctx, r.db, database.SecurityEventNameRepoLegalHoldPlaced, a.UID, repo.IDInt32(), repo.RepoName(), args.Reason)
+
+	return &repositoryLegalHoldResolver{db: r.db, gitserverClient: r.gitserverClient, hold: hold}, nil
+}
+
+func (r *schemaResolver) ReleaseRepositoryLegalHold(ctx context.Context, args *struct {
+	Repository graphql.ID
+},
+) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can release legal holds.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	repoID, err := UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	hold, err := r.db.RepoLegalHolds().Get(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+	if hold == nil {
+		return nil, errors.New("repository is not on legal hold")
+	}
+	if err := r.db.RepoLegalHolds().Delete(ctx, repoID); err != nil {
+		return nil, err
+	}
+	logLegalHoldChange(ctx, r.db, database.SecurityEventNameRepoLegalHoldReleased, actor.FromContext(ctx).UID, repoID, hold.RepoName, hold.Reason)
+
+	return &EmptyResponse{}, nil
+}
+
+func logLegalHoldChange(ctx context.Context, db database.DB, name database.SecurityEventName, by int32, repoID api.RepoID, repoName api.RepoName, reason string) {
+	args, _ := json.Marshal(struct {
+		RepoID   api.RepoID   `json:"repoID"`
+		RepoName api.RepoName `json:"repoName"`
+		Reason   string       `json:"reason"`
+	}{
+		RepoID:   repoID,
+		RepoName: repoName,
+		Reason:   reason,
+	})
+
+	db.SecurityEventLogs().LogEvent(ctx, &database.SecurityEvent{
+		Name:      name,
+		UserID:    uint32(by),
+		Argument:  args,
+		Source:    "BACKEND",
+		Timestamp: time.Now(),
+	})
+}
@@ -0,0 +1,99 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestRepositoryLegalHold(t *testing.T) {
+	ctx := context.Background()
+
+	logger := logtest.Scoped(t)
+	db := database.NewMockDBFrom(database.NewDB(logger, dbtest.NewDB(logger, t)))
+
+	users := database.NewMockUserStore()
+	db.UsersFunc.SetDefaultReturn(users)
+
+	err := db.Repos().Create(ctx, &types.Repo{Name: "testrepo"})
+	require.NoError(t, err)
+	repo, err := db.Repos().GetByName(ctx, "testrepo")
+	require.NoError(t, err)
+
+	schema := newSchemaResolver(db, gitserver.NewClient(), jobutil.NewUnimplementedEnterpriseJobs())
+	gqlID := MarshalRepositoryID(repo.ID)
+
+	setArgs := &struct {
+		Repository graphql.ID
+		Reason     string
+	}{
+		Repository: gqlID,
+		Reason:     "litigation",
+	}
+	releaseArgs := &struct {
+		Repository graphql.ID
+	}{
+		Repository: gqlID,
+	}
+
+	t.Run("non-admin", func(t *testing.T) {
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{}, nil)
+
+		_, err := schema.SetRepositoryLegalHold(ctx, setArgs)
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+		_, err = schema.ReleaseRepositoryLegalHold(ctx, releaseArgs)
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+		_, err = schema.RepositoriesOnLegalHold(ctx)
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdmin)
+	})
+
+	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{SiteAdmin: true}, nil)
+
+	t.Run("set", func(t *testing.T) {
+		_, err := schema.SetRepositoryLegalHold(ctx, &struct {
+			Repository graphql.ID
+			Reason     string
+		}{
+			Repository: gqlID,
+			Reason:     " ",
+		})
+		require.Error(t, err)
+
+		hold, err := schema.SetRepositoryLegalHold(ctx, setArgs)
+		require.NoError(t, err)
+		require.Equal(t, "litigation", hold.Reason())
+
+		_, err = schema.SetRepositoryLegalHold(ctx, setArgs)
+		require.Error(t, err)
+
+		holds, err := schema.RepositoriesOnLegalHold(ctx)
+		require.NoError(t, err)
+		require.Len(t, holds, 1)
+
+		held, err := NewRepositoryResolver(db, nil, repo).LegalHold(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, held)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		_, err := schema.ReleaseRepositoryLegalHold(ctx, releaseArgs)
+		require.NoError(t, err)
+
+		_, err = schema.ReleaseRepositoryLegalHold(ctx, releaseArgs)
+		require.Error(t, err)
+
+		held, err := NewRepositoryResolver(db, nil, repo).LegalHold(ctx)
+		require.NoError(t, err)
+		require.Nil(t, held)
+	})
+}
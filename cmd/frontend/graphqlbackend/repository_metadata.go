@@ -156,6 +156,49 @@ func (r *schemaResolver) DeleteRepoMetadata(ctx context.Context, args struct {
 	return &EmptyResponse{}, err
 }
 
+type KeyValuePairInput struct {
+	Key   string
+	Value *string
+}
+
+func (r *schemaResolver) SetRepoMetadata(ctx context.Context, args struct {
+	Repo     graphql.ID
+	Metadata []KeyValuePairInput
+},
+) (*EmptyResponse, error) {
+	if err := rbac.CheckCurrentUserHasPermission(ctx, r.db, rbac.RepoMetadataWritePermission); err != nil {
+		return &EmptyResponse{}, err
+	}
+
+	if !featureflag.FromContext(ctx).GetBoolOr("repository-metadata", true) {
+		return nil, featureDisabledError
+	}
+
+	repoID, err := UnmarshalRepositoryID(args.Repo)
+	if err != nil {
+		return &EmptyResponse{}, err
+	}
+
+	kvps := make([]database.KeyValuePair, 0, len(args.Metadata))
+	seen := make(map[string]struct{}, len(args.Metadata))
+	for _, m := range args.Metadata {
+		if m.Value != nil && strings.TrimSpace(*m.Value) == "" {
+			return &EmptyResponse{}, emptyNonNilValueError{value: *m.Value}
+		}
+		if _, ok := seen[m.Key]; ok {
+			return &EmptyResponse{}, errors.Newf("duplicate metadata key %q", m.Key)
+		}
+		seen[m.Key] = struct{}{}
+		kvps = append(kvps, database.KeyValuePair{Key: m.Key, Value: m.Value})
+	}
+
+	err = r.db.RepoKVPs().Set(ctx, repoID, kvps)
+	if err == nil {
+		r.logBackendEvent(ctx, "RepoMetadataUpdated")
+	}
+	return &EmptyResponse{}, err
+}
+
 func (r *schemaResolver) logBackendEvent(ctx context.Context, eventName string) {
 	a := actor.FromContext(ctx)
 	if a.IsAuthenticated() && !a.IsMockUser() {
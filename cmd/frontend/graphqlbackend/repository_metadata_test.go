@@ -186,6 +186,65 @@ func TestRepositoryMetadata(t *testing.T) {
 		require.Empty(t, kvps)
 	})
 
+	t.Run("set", func(t *testing.T) {
+		type setArgs = struct {
+			Repo     graphql.ID
+			Metadata []KeyValuePairInput
+		}
+
+		_, err = schema.SetRepoMetadata(ctx, setArgs{
+			Repo: gqlID,
+			Metadata: []KeyValuePairInput{
+				{Key: "owning-team", Value: pointers.Ptr("security")},
+				{Key: "deprecated", Value: nil},
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = schema.SetRepoMetadata(ctx, setArgs{
+			Repo: gqlID,
+			Metadata: []KeyValuePairInput{
+				{Key: "tier", Value: pointers.Ptr("1")},
+				{Key: "tier", Value: pointers.Ptr("2")},
+			},
+		})
+		require.Error(t, err)
+
+		_, err = schema.SetRepoMetadata(ctx, setArgs{
+			Repo:     gqlID,
+			Metadata: []KeyValuePairInput{{Key: "tier", Value: pointers.Ptr(" ")}},
+		})
+		require.Equal(t, emptyNonNilValueError{value: " "}, err)
+
+		_, err = schema.SetRepoMetadata(ctx, setArgs{
+			Repo: gqlID,
+			Metadata: []KeyValuePairInput{
+				{Key: "owning-team", Value: pointers.Ptr("security++")},
+				{Key: "tier", Value: pointers.Ptr("1")},
+			},
+		})
+		require.NoError(t, err)
+
+		repoResolver, err := schema.repositoryByID(ctx, gqlID)
+		require.NoError(t, err)
+
+		kvps, err := repoResolver.Metadata(ctx)
+		require.NoError(t, err)
+		sort.Slice(kvps, func(i, j int) bool {
+			return kvps[i].key < kvps[j].key
+		})
+		require.Equal(t, []KeyValuePair{{
+			key:   "owning-team",
+			value: pointers.Ptr("security++"),
+		}, {
+			key:   "tier",
+			value: pointers.Ptr("1"),
+		}}, kvps)
+
+		_, err = schema.SetRepoMetadata(ctx, setArgs{Repo: gqlID})
+		require.NoError(t, err)
+	})
+
 	t.Run("handles feature flag", func(t *testing.T) {
 		flags := map[string]bool{"repository-metadata": false}
 		ctx = featureflag.WithFlags(ctx, featureflag.NewMemoryStore(flags, flags, flags))
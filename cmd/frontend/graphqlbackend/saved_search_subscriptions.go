@@ -0,0 +1,140 @@
+package graphqlbackend
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type savedSearchSubscriptionResolver struct {
+	s *database.SavedSearchSubscription
+}
+
+func (r *savedSearchSubscriptionResolver) NotifyEmail() bool { return r.s.NotifyEmail }
+
+func (r *savedSearchSubscriptionResolver) WebhookURL() *string { return r.s.WebhookURL }
+
+func (r *savedSearchSubscriptionResolver) SlackWebhookURL() *string { return r.s.SlackWebhookURL }
+
+func (r *savedSearchSubscriptionResolver) IntervalMinutes() int32 {
+	return int32(r.s.Interval / time.Minute)
+}
+
+func (r *savedSearchSubscriptionResolver) DigestIntervalMinutes() int32 {
+	return int32(r.s.DigestInterval / time.Minute)
+}
+
+func (r *savedSearchSubscriptionResolver) Muted() bool { return r.s.Muted }
+
+func (r *savedSearchSubscriptionResolver) PendingResultCount() int32 {
+	return int32(len(r.s.PendingResults))
+}
+
+func (r *savedSearchSubscriptionResolver) NextRunAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.s.NextRunAt}
+}
+
+func (r *savedSearchSubscriptionResolver) LastRunAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.s.LastRunAt)
+}
+
+func (r *savedSearchSubscriptionResolver) LastNotifiedAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.s.LastNotifiedAt)
+}
+
+func (r *savedSearchSubscriptionResolver) LastError() *string { return r.s.LastError }
+
+func (r savedSearchResolver) ViewerSubscription(ctx context.Context) (*savedSearchSubscriptionResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, nil
+	}
+	s, err := r.db.SavedSearchSubscriptions().Get(ctx, r.s.ID, a.UID)
+	if err != nil || s == nil {
+		return nil, err
+	}
+	return &savedSearchSubscriptionResolver{s: s}, nil
+}
+
+type savedSearchSubscriptionInput struct {
+	NotifyEmail           bool
+	WebhookURL            *string
+	SlackWebhookURL       *string
+	IntervalMinutes       int32
+	DigestIntervalMinutes int32
+	Muted                 bool
+}
+
+func (r *schemaResolver) SubscribeToSavedSearch(ctx context.Context, args *struct {
+	SavedSearch graphql.ID
+	Input       savedSearchSubscriptionInput
+}) (*savedSearchSubscriptionResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, auth.ErrNotAuthenticated
+	}
+
+	// 🚨 SECURITY: savedSearchByID checks that the current user has access to
+	// the saved search.
+	ss, err := r.savedSearchByID(ctx, args.SavedSearch)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range []*string{args.Input.WebhookURL, args.Input.SlackWebhookURL} {
+		if u == nil || *u == "" {
+			continue
+		}
+		if parsed, err := url.Parse(*u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return nil, errors.Errorf("invalid webhook URL %q", *u)
+		}
+	}
+	if args.Input.IntervalMinutes < 1 {
+		return nil, errors.New("intervalMinutes must be at least 1")
+	}
+	if args.Input.DigestIntervalMinutes < 0 {
+		return nil, errors.New("digestIntervalMinutes must not be negative")
+	}
+
+	s, err := r.db.SavedSearchSubscriptions().Upsert(ctx, &database.SavedSearchSubscription{
+		SavedSearchID:   ss.s.ID,
+		UserID:          a.UID,
+		NotifyEmail:     args.Input.NotifyEmail,
+		WebhookURL:      args.Input.WebhookURL,
+		SlackWebhookURL: args.Input.SlackWebhookURL,
+		Interval:        time.Duration(args.Input.IntervalMinutes) * time.Minute,
+		DigestInterval:  time.Duration(args.Input.DigestIntervalMinutes) * time.Minute,
+		Muted:           args.Input.Muted,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &savedSearchSubscriptionResolver{s: s}, nil
+}
+
+func (r *schemaResolver) UnsubscribeFromSavedSearch(ctx context.Context, args *struct {
+	SavedSearch graphql.ID
+}) (*EmptyResponse, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, auth.ErrNotAuthenticated
+	}
+
+	id, err := unmarshalSavedSearchID(args.SavedSearch)
+	if err != nil {
+		return nil, err
+	}
+	// Users can always unsubscribe, even if they lost access to the saved search.
+	if err := r.db.SavedSearchSubscriptions().Delete(ctx, id, a.UID); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
@@ -0,0 +1,75 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
+)
+
+type fakeSavedSearchSubscriptionStore struct {
+	database.SavedSearchSubscriptionStore
+	upserted *database.SavedSearchSubscription
+}
+
+func (s *fakeSavedSearchSubscriptionStore) Upsert(_ context.Context, sub *database.SavedSearchSubscription) (*database.SavedSearchSubscription, error) {
+	s.upserted = sub
+	return sub, nil
+}
+
+func TestSubscribeToSavedSearch(t *testing.T) {
+	owner := int32(1)
+
+	ss := database.NewMockSavedSearchStore()
+	ss.GetByIDFunc.SetDefaultReturn(&api.SavedQuerySpecAndConfig{
+		Config: api.ConfigSavedQuery{Description: "TODOs", Query: "TODO", UserID: &owner},
+	}, nil)
+	subscriptions := &fakeSavedSearchSubscriptionStore{}
+
+	db := database.NewMockDB()
+	db.SavedSearchesFunc.SetDefaultReturn(ss)
+	db.SavedSearchSubscriptionsFunc.SetDefaultReturn(subscriptions)
+
+	r := newSchemaResolver(db, gitserver.NewClient(), jobutil.NewUnimplementedEnterpriseJobs())
+	args := &struct {
+		SavedSearch graphql.ID
+		Input       savedSearchSubscriptionInput
+	}{
+		SavedSearch: marshalSavedSearchID(1),
+		Input:       savedSearchSubscriptionInput{NotifyEmail: true, IntervalMinutes: 30, DigestIntervalMinutes: 1440},
+	}
+
+	t.Run("other user", func(t *testing.T) {
+		ctx := actor.WithActor(context.Background(), actor.FromUser(2))
+		_, err := r.SubscribeToSavedSearch(ctx, args)
+		require.Error(t, err)
+		require.Nil(t, subscriptions.upserted)
+	})
+
+	t.Run("invalid webhook URL", func(t *testing.T) {
+		ctx := actor.WithActor(context.Background(), actor.FromUser(owner))
+		webhookURL := "file:///etc/passwd"
+		invalid := *args
+		invalid.Input.WebhookURL = &webhookURL
+		_, err := r.SubscribeToSavedSearch(ctx, &invalid)
+		require.Error(t, err)
+	})
+
+	t.Run("owner", func(t *testing.T) {
+		ctx := actor.WithActor(context.Background(), actor.FromUser(owner))
+		sub, err := r.SubscribeToSavedSearch(ctx, args)
+		require.NoError(t, err)
+		require.True(t, sub.NotifyEmail())
+		require.Equal(t, owner, subscriptions.upserted.UserID)
+		require.Equal(t, 30*time.Minute, subscriptions.upserted.Interval)
+		require.Equal(t, int32(1440), sub.DigestIntervalMinutes())
+	})
+}
@@ -70,6 +70,22 @@ var insightsAggregationsSchema string
 //go:embed outbound_webhooks.graphql
 var outboundWebhooksSchema string
 
+// gitserverRelocatorSchema is the raw GraphQL schema of the moves of repositories
+// between gitservers.
+//
+//go:embed gitserver_relocator.graphql
+var gitserverRelocatorSchema string
+
+// notificationsSchema is the raw GraphQL schema of the notifications inbox.
+//
+//go:embed notifications.graphql
+var notificationsSchema string
+
+// auditLogSchema is the raw GraphQL schema of the audit log.
+//
+//go:embed audit_log.graphql
+var auditLogSchema string
+
 // embeddingsSchema is the Embeddings raw graqhql schema.
 //
 //go:embed embeddings.graphql
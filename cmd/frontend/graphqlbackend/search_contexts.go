@@ -50,6 +50,8 @@ type SearchContextResolver interface {
 	ViewerHasAsDefault(ctx context.Context) bool
 	ViewerHasStarred(ctx context.Context) bool
 	Repositories(ctx context.Context) ([]SearchContextRepositoryRevisionsResolver, error)
+	IncludedSearchContexts(ctx context.Context) ([]SearchContextResolver, error)
+	RepositoryQueries() []string
 	Query() string
 }
 
@@ -65,18 +67,22 @@ type SearchContextRepositoryRevisionsResolver interface {
 }
 
 type SearchContextInputArgs struct {
-	Name        string
-	Description string
-	Public      bool
-	Namespace   *graphql.ID
-	Query       string
+	Name                   string
+	Description            string
+	Public                 bool
+	Namespace              *graphql.ID
+	Query                  string
+	IncludedSearchContexts *[]graphql.ID
+	RepositoryQueries      *[]string
 }
 
 type SearchContextEditInputArgs struct {
-	Name        string
-	Description string
-	Public      bool
-	Query       string
+	Name                   string
+	Description            string
+	Public                 bool
+	Query                  string
+	IncludedSearchContexts *[]graphql.ID
+	RepositoryQueries      *[]string
 }
 
 type SearchContextRepositoryRevisionsInputArgs struct {
@@ -0,0 +1,105 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/search/exports"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/signedlink"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// maxSearchExportJobs is the maximum number of search exports returned by
+// searchExportJobs.
+const maxSearchExportJobs = 100
+
+type searchExportJobResolver struct {
+	job *exports.Job
+}
+
+func (r *searchExportJobResolver) ID() graphql.ID {
+	return relay.MarshalID("SearchExportJob", r.job.ID)
+}
+
+func (r *searchExportJobResolver) Query() string { return r.job.Query }
+
+func (r *searchExportJobResolver) Format() string {
+	return strings.ToUpper(string(r.job.Format))
+}
+
+func (r *searchExportJobResolver) State() string {
+	return strings.ToUpper(r.job.State)
+}
+
+func (r *searchExportJobResolver) FailureMessage() *string { return r.job.FailureMessage }
+
+func (r *searchExportJobResolver) ResultCount() int32 { return int32(r.job.ResultCount) }
+
+func (r *searchExportJobResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.job.CreatedAt}
+}
+
+func (r *searchExportJobResolver) FinishedAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.job.FinishedAt)
+}
+
+func (r *searchExportJobResolver) DownloadURL() *string {
+	if r.job.State != "completed" || r.job.ObjectKey == nil {
+		return nil
+	}
+	// 🚨 SECURITY: Anyone with the link can download the export. Resolvers are
+	// only created for the exports of the current user.
+	u := exports.DownloadURL(globals.ExternalURL(), r.job, time.Now().Add(signedlink.TTL))
+	return &u
+}
+
+func (r *schemaResolver) ExportSearchResults(ctx context.Context, args *struct {
+	Query  string
+	Format string
+}) (*searchExportJobResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, auth.ErrNotAuthenticated
+	}
+	if strings.TrimSpace(args.Query) == "" {
+		return nil, errors.New("query must not be empty")
+	}
+
+	// The search is run as the current user, so the export only contains results
+	// they have access to.
+	job, err := exports.NewStore(r.db).Enqueue(ctx, a.UID, args.Query, exports.Format(strings.ToLower(args.Format)))
+	if err != nil {
+		return nil, err
+	}
+	return &searchExportJobResolver{job: job}, nil
+}
+
+func (r *schemaResolver) SearchExportJobs(ctx context.Context, args *struct {
+	First int32
+}) ([]*searchExportJobResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, auth.ErrNotAuthenticated
+	}
+	if args.First < 1 || args.First > maxSearchExportJobs {
+		return nil, errors.Newf("first must be between 1 and %d", maxSearchExportJobs)
+	}
+
+	jobs, err := exports.NewStore(r.db).ListByUser(ctx, a.UID, int(args.First))
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*searchExportJobResolver, 0, len(jobs))
+	for _, job := range jobs {
+		resolvers = append(resolvers, &searchExportJobResolver{job: job})
+	}
+	return resolvers, nil
+}
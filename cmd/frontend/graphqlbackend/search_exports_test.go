@@ -0,0 +1,40 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/search/exports"
+)
+
+func TestSearchExportJobResolver(t *testing.T) {
+	key := "search-exports/1.csv"
+	r := &searchExportJobResolver{job: &exports.Job{
+		ID:            1,
+		State:         "completed",
+		Format:        exports.FormatJSONL,
+		ObjectKey:     &key,
+		SigningSecret: []byte("secret"),
+	}}
+	require.Equal(t, "COMPLETED", r.State())
+	require.Equal(t, "JSONL", r.Format())
+	require.NotNil(t, r.DownloadURL())
+	require.True(t, strings.Contains(*r.DownloadURL(), "/.api/search/export/1?"))
+
+	r.job.State = "processing"
+	require.Nil(t, r.DownloadURL())
+}
+
+func TestExportSearchResultsRequiresAuthentication(t *testing.T) {
+	r := &schemaResolver{db: database.NewMockDB()}
+	_, err := r.ExportSearchResults(context.Background(), &struct {
+		Query  string
+		Format string
+	}{Query: "TODO", Format: "CSV"})
+	require.ErrorIs(t, err, auth.ErrNotAuthenticated)
+}
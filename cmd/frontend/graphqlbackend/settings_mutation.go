@@ -118,25 +118,38 @@ func toKeyPath(gqlKeyPath []*keyPathSegment) (jsonx.Path, error) {
 func (r *settingsMutation) EditSettings(ctx context.Context, args *struct {
 	Edit *settingsEdit
 }) (*updateSettingsPayload, error) {
-	keyPath, err := toKeyPath(args.Edit.KeyPath)
+	keyPath, value, remove, err := parseSettingsEdit(args.Edit)
 	if err != nil {
 		return nil, err
 	}
 
-	remove := args.Edit.Value == nil
-	var value any
-	if args.Edit.Value != nil {
-		value = args.Edit.Value.Value
+	if _, err := r.editSettings(ctx, keyPath, value, remove); err != nil {
+		return nil, err
+	}
+	return &updateSettingsPayload{}, nil
+}
+
+// parseSettingsEdit returns the key path and the value of the given edit, and
+// whether the edit removes the property.
+func parseSettingsEdit(edit *settingsEdit) (keyPath jsonx.Path, value any, remove bool, err error) {
+	keyPath, err = toKeyPath(edit.KeyPath)
+	if err != nil {
+		return nil, nil, false, err
 	}
-	if args.Edit.ValueIsJSONCEncodedString {
+
+	remove = edit.Value == nil
+	if edit.Value != nil {
+		value = edit.Value.Value
+	}
+	if edit.ValueIsJSONCEncodedString {
 		s, ok := value.(string)
 		if !ok {
-			return nil, errors.New("value must be a string for valueIsJSONCEncodedString")
+			return nil, nil, false, errors.New("value must be a string for valueIsJSONCEncodedString")
 		}
 		value = json.RawMessage(s)
 	}
 
-	return r.editSettings(ctx, keyPath, value, remove)
+	return keyPath, value, remove, nil
 }
 
 func (r *settingsMutation) EditConfiguration(ctx context.Context, args *struct {
@@ -145,8 +158,8 @@ func (r *settingsMutation) EditConfiguration(ctx context.Context, args *struct {
 	return r.EditSettings(ctx, args)
 }
 
-func (r *settingsMutation) editSettings(ctx context.Context, keyPath jsonx.Path, value any, remove bool) (*updateSettingsPayload, error) {
-	_, err := r.doUpdateSettings(ctx, func(oldSettings string) (edits []jsonx.Edit, err error) {
+func (r *settingsMutation) editSettings(ctx context.Context, keyPath jsonx.Path, value any, remove bool) (idAfterUpdate int32, err error) {
+	return r.doUpdateSettings(ctx, func(oldSettings string) (edits []jsonx.Edit, err error) {
 		if remove {
 			edits, _, err = jsonx.ComputePropertyRemoval(oldSettings, keyPath, conf.FormatOptions)
 		} else {
@@ -154,10 +167,6 @@ func (r *settingsMutation) editSettings(ctx context.Context, keyPath jsonx.Path,
 		}
 		return edits, err
 	})
-	if err != nil {
-		return nil, err
-	}
-	return &updateSettingsPayload{}, nil
 }
 
 func (r *settingsMutation) OverwriteSettings(ctx context.Context, args *struct {
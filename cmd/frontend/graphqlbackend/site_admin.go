@@ -55,28 +55,32 @@ func (r *schemaResolver) RecoverUsers(ctx context.Context, args *RecoverUsersReq
 
 	if len(users) != len(ids) {
 		missingUserIds := missingUserIds(ids, users)
-		return nil, errors.Errorf("some users were not found, expected to recover %d users, but found only %d users. Missing user IDs: %s", len(ids), len(users), missingUserIds)
+		return nil, errors.Errorf("some users were not found or were deleted before the retention period, expected to recover %d users, but found only %d users. Missing user IDs: %s", len(ids), len(users), missingUserIds)
 	}
 
 	return &EmptyResponse{}, nil
 }
 
 func (r *schemaResolver) DeleteUser(ctx context.Context, args *struct {
-	User graphql.ID
-	Hard *bool
+	User       graphql.ID
+	Hard       *bool
+	ReassignTo *graphql.ID
 }) (*EmptyResponse, error) {
 	return r.DeleteUsers(ctx, &struct {
-		Users []graphql.ID
-		Hard  *bool
+		Users      []graphql.ID
+		Hard       *bool
+		ReassignTo *graphql.ID
 	}{
-		Users: []graphql.ID{args.User},
-		Hard:  args.Hard,
+		Users:      []graphql.ID{args.User},
+		Hard:       args.Hard,
+		ReassignTo: args.ReassignTo,
 	})
 }
 
 func (r *schemaResolver) DeleteUsers(ctx context.Context, args *struct {
-	Users []graphql.ID
-	Hard  *bool
+	Users      []graphql.ID
+	Hard       *bool
+	ReassignTo *graphql.ID
 }) (*EmptyResponse, error) {
 	// 🚨 SECURITY: Only site admins can delete users.
 	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
@@ -102,6 +106,29 @@ func (r *schemaResolver) DeleteUsers(ctx context.Context, args *struct {
 		ids[index] = id
 	}
 
+	hard := args.Hard != nil && *args.Hard
+
+	var reassignTo int32
+	if args.ReassignTo != nil {
+		if !hard {
+			return nil, errors.New("ownership can only be reassigned when hard deleting users")
+		}
+		id, err := UnmarshalUserID(*args.ReassignTo)
+		if err != nil {
+			return nil, err
+		}
+		for _, deleted := range ids {
+			if deleted == id {
+				return nil, errors.New("unable to reassign ownership to a deleted user")
+			}
+		}
+		// GetByID does not return soft-deleted users.
+		if _, err := r.db.Users().GetByID(ctx, id); err != nil {
+			return nil, errors.Wrap(err, "get user to reassign ownership to")
+		}
+		reassignTo = id
+	}
+
 	logger := r.logger.Scoped("DeleteUsers", "delete users mutation").
 		With(log.Int32s("users", ids))
 
@@ -171,8 +198,26 @@ func (r *schemaResolver) DeleteUsers(ctx context.Context, args *struct {
 		})
 	}
 
-	if args.Hard != nil && *args.Hard {
-		if err := r.db.Users().HardDeleteList(ctx, ids); err != nil {
+	if hard {
+		if err := r.db.WithTransact(ctx, func(tx database.DB) error {
+			if reassignTo != 0 {
+				for _, id := range ids {
+					if err := tx.Users().ReassignOwnership(ctx, id, reassignTo); err != nil {
+						return err
+					}
+				}
+			}
+			if err := tx.Users().HardDeleteList(ctx, ids); err != nil {
+				return err
+			}
+			// Certify that the personal data of the users has been deleted.
+			for _, id := range ids {
+				if _, err := userDataExportStore(tx).EnqueueDeletionReport(ctx, id, &a.UID); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
 			return nil, err
 		}
 	} else {
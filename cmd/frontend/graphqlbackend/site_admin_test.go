@@ -33,8 +33,9 @@ func TestDeleteUser(t *testing.T) {
 
 		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
 		result, err := newSchemaResolver(db, gitserver.NewClient(), jobutil.NewUnimplementedEnterpriseJobs()).DeleteUser(ctx, &struct {
-			User graphql.ID
-			Hard *bool
+			User       graphql.ID
+			Hard       *bool
+			ReassignTo *graphql.ID
 		}{
 			User: MarshalUserID(1),
 		})
@@ -55,8 +56,9 @@ func TestDeleteUser(t *testing.T) {
 
 		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
 		_, err := newSchemaResolver(db, gitserver.NewClient(), jobutil.NewUnimplementedEnterpriseJobs()).DeleteUser(ctx, &struct {
-			User graphql.ID
-			Hard *bool
+			User       graphql.ID
+			Hard       *bool
+			ReassignTo *graphql.ID
 		}{
 			User: MarshalUserID(1),
 		})
@@ -128,6 +130,9 @@ func TestDeleteUser(t *testing.T) {
 	db.UserEmailsFunc.SetDefaultReturn(userEmails)
 	db.UserExternalAccountsFunc.SetDefaultReturn(externalAccounts)
 	db.AuthzFunc.SetDefaultReturn(authzStore)
+	db.WithTransactFunc.SetDefaultHook(func(ctx context.Context, f func(database.DB) error) error {
+		return f(db)
+	})
 
 	// Disable event logging, which is triggered for SOAP users
 	conf.Mock(&conf.Unified{
@@ -139,6 +144,8 @@ func TestDeleteUser(t *testing.T) {
 	})
 	t.Cleanup(func() { conf.Mock(nil) })
 
+	dataExports := mockUserDataExports(t)
+
 	tests := []struct {
 		name     string
 		setup    func(t *testing.T)
@@ -190,6 +197,13 @@ func TestDeleteUser(t *testing.T) {
 		},
 		{
 			name: "hard delete a user",
+			setup: func(t *testing.T) {
+				t.Cleanup(func() {
+					if len(dataExports.jobs) != 1 || dataExports.jobs[0].UserID != aliceUID {
+						t.Errorf("expected a deletion report of user %d to be enqueued", aliceUID)
+					}
+				})
+			},
 			gqlTests: []*Test{
 				{
 					Schema: mustParseGraphQLSchema(t, db),
@@ -210,6 +224,60 @@ func TestDeleteUser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "hard delete a user and reassign ownership",
+			setup: func(t *testing.T) {
+				t.Cleanup(func() { users.ReassignOwnershipFunc.SetDefaultReturn(nil) })
+
+				users.ReassignOwnershipFunc.SetDefaultHook(func(_ context.Context, from, to int32) error {
+					if from != aliceUID || to != 7 {
+						return errors.Errorf("unexpected reassignment from %d to %d", from, to)
+					}
+					return nil
+				})
+			},
+			gqlTests: []*Test{
+				{
+					Schema: mustParseGraphQLSchema(t, db),
+					Query: `
+				mutation {
+					deleteUser(user: "VXNlcjo2", hard: true, reassignTo: "VXNlcjo3") {
+						alwaysNil
+					}
+				}
+			`,
+					ExpectedResult: `
+				{
+					"deleteUser": {
+						"alwaysNil": null
+					}
+				}
+			`,
+				},
+			},
+		},
+		{
+			name: "reassign ownership requires a hard delete",
+			gqlTests: []*Test{
+				{
+					Schema: mustParseGraphQLSchema(t, db),
+					Query: `
+				mutation {
+					deleteUser(user: "VXNlcjo2", reassignTo: "VXNlcjo3") {
+						alwaysNil
+					}
+				}
+			`,
+					ExpectedResult: `{ "deleteUser": null }`,
+					ExpectedErrors: []*gqlerrors.QueryError{
+						{
+							Path:    []any{"deleteUser"},
+							Message: "ownership can only be reassigned when hard deleting users",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "non-SOAP user cannot delete SOAP user",
 			setup: func(t *testing.T) {
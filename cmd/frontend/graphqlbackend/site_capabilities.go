@@ -0,0 +1,75 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/cody"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/internal/rbac"
+)
+
+func (r *siteResolver) Capabilities() *siteCapabilitiesResolver {
+	return &siteCapabilitiesResolver{logger: r.logger, db: r.db}
+}
+
+// siteCapabilitiesResolver resolves the features of the site that are enabled for the
+// current user. Each capability runs the same license, site configuration, feature
+// flag and permission checks as the feature it describes, so that a client is never
+// told that a feature is available when using it would fail.
+type siteCapabilitiesResolver struct {
+	logger log.Logger
+	db     database.DB
+}
+
+// BatchChanges mirrors the checks of the batch changes resolvers before a batch spec
+// is created or applied.
+func (r *siteCapabilitiesResolver) BatchChanges(ctx context.Context) bool {
+	if licensing.Check(&licensing.FeatureBatchChanges{}) != nil {
+		return false
+	}
+	// Batch changes are disabled on sourcegraph.com.
+	if !conf.BatchChangesEnabled() || envvar.SourcegraphDotComMode() {
+		return false
+	}
+	if !actor.FromContext(ctx).IsAuthenticated() {
+		return false
+	}
+	if conf.BatchChangesRestrictedToAdmins() && auth.CheckCurrentUserIsSiteAdmin(ctx, r.db) != nil {
+		return false
+	}
+	return rbac.CheckCurrentUserHasPermission(ctx, r.db, rbac.BatchChangesWritePermission) == nil
+}
+
+func (r *siteCapabilitiesResolver) BatchChangesServerSideExecution(ctx context.Context) bool {
+	return conf.ExecutorsEnabled() && r.BatchChanges(ctx)
+}
+
+// Cody mirrors the checks of the completions resolvers, which include the "cody"
+// feature flag if the site restricts Cody to the users it is enabled for.
+func (r *siteCapabilitiesResolver) Cody(ctx context.Context) bool {
+	return cody.IsCodyEnabled(ctx) && cody.CheckVerifiedEmailRequirement(ctx, r.db, r.logger) == nil
+}
+
+// Embeddings mirrors the checks of the embeddings search resolvers.
+func (r *siteCapabilitiesResolver) Embeddings(ctx context.Context) bool {
+	return conf.EmbeddingsEnabled() && r.Cody(ctx)
+}
+
+// SCIM mirrors the checks of the SCIM handler. Whether users are provisioned through
+// SCIM is part of the authentication setup of the site, so it is only revealed to
+// site admins.
+func (r *siteCapabilitiesResolver) SCIM(ctx context.Context) *bool {
+	// 🚨 SECURITY: Only site admins may know how users are provisioned.
+	if auth.CheckCurrentUserIsSiteAdmin(ctx, r.db) != nil {
+		return nil
+	}
+	enabled := conf.Get().ScimAuthToken != "" && licensing.Check(licensing.FeatureSCIM) == nil
+	return &enabled
+}
@@ -0,0 +1,166 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/featureflag"
+	"github.com/sourcegraph/sourcegraph/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSiteCapabilities(t *testing.T) {
+	const query = `{ site { capabilities { batchChanges batchChangesServerSideExecution cody embeddings scim } } }`
+
+	newDB := func(siteAdmin, batchChangesWrite bool) database.DB {
+		users := database.NewMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: siteAdmin}, nil)
+		permissions := database.NewMockPermissionStore()
+		if batchChangesWrite {
+			permissions.GetPermissionForUserFunc.SetDefaultReturn(&types.Permission{ID: 1}, nil)
+		}
+		db := database.NewMockDB()
+		db.UsersFunc.SetDefaultReturn(users)
+		db.PermissionsFunc.SetDefaultReturn(permissions)
+		return db
+	}
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+
+	mockConf := func(t *testing.T, c schema.SiteConfiguration) {
+		conf.Mock(&conf.Unified{SiteConfiguration: c})
+		t.Cleanup(func() { conf.Mock(nil) })
+	}
+	defaultConf := schema.SiteConfiguration{
+		CodyEnabled:          pointers.Ptr(true),
+		ExecutorsAccessToken: "secret",
+		ScimAuthToken:        "secret",
+	}
+
+	t.Run("unlicensed", func(t *testing.T) {
+		t.Cleanup(licensing.MockCheckFeatureError("unlicensed"))
+		mockConf(t, defaultConf)
+
+		RunTest(t, &Test{
+			Context: ctx,
+			Schema:  mustParseGraphQLSchema(t, newDB(true, true)),
+			Query:   query,
+			ExpectedResult: `{"site": {"capabilities": {
+				"batchChanges": false,
+				"batchChangesServerSideExecution": false,
+				"cody": false,
+				"embeddings": false,
+				"scim": false
+			}}}`,
+		})
+	})
+
+	t.Run("licensed", func(t *testing.T) {
+		t.Cleanup(licensing.MockCheckFeatureError(""))
+		mockConf(t, defaultConf)
+
+		RunTest(t, &Test{
+			Context: ctx,
+			Schema:  mustParseGraphQLSchema(t, newDB(false, true)),
+			Query:   query,
+			ExpectedResult: `{"site": {"capabilities": {
+				"batchChanges": true,
+				"batchChangesServerSideExecution": true,
+				"cody": true,
+				"embeddings": true,
+				"scim": null
+			}}}`,
+		})
+	})
+
+	t.Run("scim is only visible to site admins", func(t *testing.T) {
+		t.Cleanup(licensing.MockCheckFeatureError(""))
+		mockConf(t, defaultConf)
+
+		RunTest(t, &Test{
+			Context:        ctx,
+			Schema:         mustParseGraphQLSchema(t, newDB(true, true)),
+			Query:          `{ site { capabilities { scim } } }`,
+			ExpectedResult: `{"site": {"capabilities": {"scim": true}}}`,
+		})
+		RunTest(t, &Test{
+			Context:        ctx,
+			Schema:         mustParseGraphQLSchema(t, newDB(false, true)),
+			Query:          `{ site { capabilities { scim } } }`,
+			ExpectedResult: `{"site": {"capabilities": {"scim": null}}}`,
+		})
+	})
+
+	t.Run("batch changes restricted to site admins", func(t *testing.T) {
+		t.Cleanup(licensing.MockCheckFeatureError(""))
+		mockConf(t, schema.SiteConfiguration{
+			BatchChangesRestrictToAdmins: pointers.Ptr(true),
+		})
+
+		for _, siteAdmin := range []bool{false, true} {
+			expected := `false`
+			if siteAdmin {
+				expected = `true`
+			}
+			RunTest(t, &Test{
+				Context:        ctx,
+				Schema:         mustParseGraphQLSchema(t, newDB(siteAdmin, true)),
+				Query:          `{ site { capabilities { batchChanges } } }`,
+				ExpectedResult: `{"site": {"capabilities": {"batchChanges": ` + expected + `}}}`,
+			})
+		}
+	})
+
+	t.Run("batch changes without write permission", func(t *testing.T) {
+		t.Cleanup(licensing.MockCheckFeatureError(""))
+		mockConf(t, defaultConf)
+
+		RunTest(t, &Test{
+			Context:        ctx,
+			Schema:         mustParseGraphQLSchema(t, newDB(false, false)),
+			Query:          `{ site { capabilities { batchChanges batchChangesServerSideExecution } } }`,
+			ExpectedResult: `{"site": {"capabilities": {"batchChanges": false, "batchChangesServerSideExecution": false}}}`,
+		})
+	})
+
+	t.Run("batch changes on sourcegraph.com", func(t *testing.T) {
+		t.Cleanup(licensing.MockCheckFeatureError(""))
+		mockConf(t, defaultConf)
+		envvar.MockSourcegraphDotComMode(true)
+		t.Cleanup(func() { envvar.MockSourcegraphDotComMode(false) })
+
+		RunTest(t, &Test{
+			Context:        ctx,
+			Schema:         mustParseGraphQLSchema(t, newDB(false, true)),
+			Query:          `{ site { capabilities { batchChanges } } }`,
+			ExpectedResult: `{"site": {"capabilities": {"batchChanges": false}}}`,
+		})
+	})
+
+	t.Run("cody restricted by feature flag", func(t *testing.T) {
+		t.Cleanup(licensing.MockCheckFeatureError(""))
+		mockConf(t, schema.SiteConfiguration{
+			CodyEnabled:                  pointers.Ptr(true),
+			CodyRestrictUsersFeatureFlag: pointers.Ptr(true),
+		})
+
+		for _, enabled := range []bool{false, true} {
+			flags := map[string]bool{"cody": enabled}
+			expected := `false`
+			if enabled {
+				expected = `true`
+			}
+			RunTest(t, &Test{
+				Context:        featureflag.WithFlags(ctx, featureflag.NewMemoryStore(flags, flags, flags)),
+				Schema:         mustParseGraphQLSchema(t, newDB(false, true)),
+				Query:          `{ site { capabilities { cody embeddings } } }`,
+				ExpectedResult: `{"site": {"capabilities": {"cody": ` + expected + `, "embeddings": ` + expected + `}}}`,
+			})
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/redispubsub"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// NodeStateTopicsFunc returns the redispubsub topics on which changes to the
+// state of the node with the given ID are published.
+type NodeStateTopicsFunc func(ctx context.Context, id graphql.ID) ([]string, error)
+
+var nodeStateTopics = struct {
+	sync.RWMutex
+	funcs map[string]NodeStateTopicsFunc
+}{funcs: map[string]NodeStateTopicsFunc{}}
+
+// RegisterNodeStateTopics makes the state of nodes of the given kind available
+// to the nodeStateChanged subscription.
+func RegisterNodeStateTopics(kind string, topics NodeStateTopicsFunc) {
+	nodeStateTopics.Lock()
+	defer nodeStateTopics.Unlock()
+
+	nodeStateTopics.funcs[kind] = topics
+}
+
+func getNodeStateTopicsFunc(kind string) (NodeStateTopicsFunc, bool) {
+	nodeStateTopics.RLock()
+	defer nodeStateTopics.RUnlock()
+
+	topics, ok := nodeStateTopics.funcs[kind]
+	return topics, ok
+}
+
+func (r *schemaResolver) NodeStateChanged(ctx context.Context, args *struct{ ID graphql.ID }) (<-chan *NodeResolver, error) {
+	kind := relay.UnmarshalKind(args.ID)
+	topicsFunc, ok := getNodeStateTopicsFunc(kind)
+	if !ok {
+		return nil, errors.Newf("cannot subscribe to state changes of %q nodes", kind)
+	}
+
+	topics, err := topicsFunc(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Subscribe before reading the current state of the node, so that no change
+	// is missed in between.
+	ctx, cancel := context.WithCancel(ctx)
+	notifications, err := redispubsub.Subscribe(ctx, topics...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Resolving the node checks that the current user can access it.
+	node, err := r.nodeByID(ctx, args.ID)
+	if err != nil || node == nil {
+		cancel()
+		if err == nil {
+			err = errors.New("node not found")
+		}
+		return nil, err
+	}
+
+	logger := r.logger.Scoped("nodeStateChanged", "subscription to node state changes").With(log.String("id", string(args.ID)))
+	nodes := make(chan *NodeResolver)
+
+	go func() {
+		defer cancel()
+		defer close(nodes)
+
+		for {
+			select {
+			case nodes <- &NodeResolver{node}:
+			case <-ctx.Done():
+				return
+			}
+
+			// Wait for the next change, and read the new state of the node.
+			if _, ok := <-notifications; !ok {
+				return
+			}
+			if node, err = r.nodeByID(ctx, args.ID); err != nil || node == nil {
+				if err != nil && ctx.Err() == nil {
+					logger.Warn("failed to resolve node after state change", log.Error(err))
+				}
+				return
+			}
+		}
+	}()
+
+	return nodes, nil
+}
@@ -135,3 +135,12 @@ func (r symbolResolver) URL(ctx context.Context) (string, error) { return r.Loca
 func (r symbolResolver) CanonicalURL() string { return r.Location().CanonicalURL() }
 
 func (r symbolResolver) FileLocal() bool { return r.Symbol.FileLimited }
+
+func (r symbolResolver) Signature() *string {
+	if r.Symbol.Signature == "" {
+		return nil
+	}
+	return &r.Symbol.Signature
+}
+
+func (r symbolResolver) Precise() bool { return r.Symbol.Precise }
@@ -0,0 +1,133 @@
+package graphqlbackend
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/signedlink"
+	"github.com/sourcegraph/sourcegraph/internal/users/dataexport"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// maxUserDataExportJobs is the maximum number of jobs returned by
+// userDataExportJobs.
+const maxUserDataExportJobs = 100
+
+// mockUserDataExportStore mocks the store of user data exports.
+var mockUserDataExportStore dataexport.Store
+
+func userDataExportStore(db database.DB) dataexport.Store {
+	if mockUserDataExportStore != nil {
+		return mockUserDataExportStore
+	}
+	return dataexport.NewStore(db)
+}
+
+type userDataExportJobResolver struct {
+	job *dataexport.Job
+}
+
+func (r *userDataExportJobResolver) ID() graphql.ID {
+	return relay.MarshalID("UserDataExportJob", r.job.ID)
+}
+
+func (r *userDataExportJobResolver) Kind() string {
+	return strings.ToUpper(string(r.job.Kind))
+}
+
+func (r *userDataExportJobResolver) State() string {
+	return strings.ToUpper(r.job.State)
+}
+
+func (r *userDataExportJobResolver) FailureMessage() *string { return r.job.FailureMessage }
+
+func (r *userDataExportJobResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.job.CreatedAt}
+}
+
+func (r *userDataExportJobResolver) FinishedAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.job.FinishedAt)
+}
+
+func (r *userDataExportJobResolver) DownloadURL() *string {
+	if r.job.State != "completed" || r.job.ObjectKey == nil {
+		return nil
+	}
+	// 🚨 SECURITY: Resolvers are only created for the user and site admins, and
+	// the download handler checks the same before serving the personal data of
+	// the user.
+	u := dataexport.DownloadURL(globals.ExternalURL(), r.job, time.Now().Add(signedlink.TTL))
+	return &u
+}
+
+func (r *schemaResolver) ExportUserData(ctx context.Context, args *struct {
+	User graphql.ID
+}) (*userDataExportJobResolver, error) {
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only the user and site admins can export the data of the user.
+	if err := auth.CheckSiteAdminOrSameUser(ctx, r.db, userID); err != nil {
+		return nil, err
+	}
+
+	a := actor.FromContext(ctx)
+	job, err := userDataExportStore(r.db).EnqueueExport(ctx, userID, a.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	eventArgs, _ := json.Marshal(struct {
+		UserID int32 `json:"userID"`
+		JobID  int   `json:"jobID"`
+	}{userID, job.ID})
+	r.db.SecurityEventLogs().LogEvent(ctx, &database.SecurityEvent{
+		Name:      database.SecurityEventNameUserDataExportRequested,
+		UserID:    uint32(a.UID),
+		Argument:  eventArgs,
+		Source:    "BACKEND",
+		Timestamp: time.Now(),
+	})
+
+	return &userDataExportJobResolver{job: job}, nil
+}
+
+func (r *schemaResolver) UserDataExportJobs(ctx context.Context, args *struct {
+	User  graphql.ID
+	First int32
+}) ([]*userDataExportJobResolver, error) {
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only the user and site admins can download the data of the
+	// user. The deletion reports of deleted users are only visible to site
+	// admins, since the user does not exist anymore.
+	if err := auth.CheckSiteAdminOrSameUser(ctx, r.db, userID); err != nil {
+		return nil, err
+	}
+	if args.First < 1 || args.First > maxUserDataExportJobs {
+		return nil, errors.Newf("first must be between 1 and %d", maxUserDataExportJobs)
+	}
+
+	jobs, err := userDataExportStore(r.db).ListByUser(ctx, userID, int(args.First))
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*userDataExportJobResolver, 0, len(jobs))
+	for _, job := range jobs {
+		resolvers = append(resolvers, &userDataExportJobResolver{job: job})
+	}
+	return resolvers, nil
+}
@@ -0,0 +1,120 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/users/dataexport"
+)
+
+type fakeUserDataExportStore struct {
+	dataexport.Store
+	jobs []*dataexport.Job
+}
+
+func (s *fakeUserDataExportStore) enqueue(userID int32, kind dataexport.Kind, requestedBy *int32) *dataexport.Job {
+	job := &dataexport.Job{ID: len(s.jobs) + 1, State: "queued", UserID: userID, Kind: kind, RequestedBy: requestedBy}
+	s.jobs = append(s.jobs, job)
+	return job
+}
+
+func (s *fakeUserDataExportStore) EnqueueExport(_ context.Context, userID, requestedBy int32) (*dataexport.Job, error) {
+	return s.enqueue(userID, dataexport.KindExport, &requestedBy), nil
+}
+
+func (s *fakeUserDataExportStore) EnqueueDeletionReport(_ context.Context, userID int32, requestedBy *int32) (*dataexport.Job, error) {
+	return s.enqueue(userID, dataexport.KindDeletionReport, requestedBy), nil
+}
+
+func (s *fakeUserDataExportStore) ListByUser(_ context.Context, userID int32, _ int) ([]*dataexport.Job, error) {
+	var jobs []*dataexport.Job
+	for _, job := range s.jobs {
+		if job.UserID == userID {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func mockUserDataExports(t *testing.T) *fakeUserDataExportStore {
+	store := &fakeUserDataExportStore{}
+	mockUserDataExportStore = store
+	t.Cleanup(func() { mockUserDataExportStore = nil })
+	return store
+}
+
+func TestUserDataExportJobResolver(t *testing.T) {
+	key := "user-data-exports/1.zip"
+	r := &userDataExportJobResolver{job: &dataexport.Job{
+		ID:            1,
+		State:         "completed",
+		Kind:          dataexport.KindDeletionReport,
+		ObjectKey:     &key,
+		SigningSecret: []byte("secret"),
+	}}
+	require.Equal(t, "COMPLETED", r.State())
+	require.Equal(t, "DELETION_REPORT", r.Kind())
+	require.NotNil(t, r.DownloadURL())
+	require.True(t, strings.Contains(*r.DownloadURL(), "/.api/users/data-export/1?"))
+
+	r.job.State = "processing"
+	require.Nil(t, r.DownloadURL())
+}
+
+func TestExportUserData(t *testing.T) {
+	store := mockUserDataExports(t)
+
+	users := database.NewMockUserStore()
+	users.GetByIDFunc.SetDefaultReturn(&types.User{ID: 2}, nil)
+	db := database.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	db.SecurityEventLogsFunc.SetDefaultReturn(database.NewMockSecurityEventLogsStore())
+	r := &schemaResolver{db: db}
+
+	exportArgs := func(userID int32) *struct{ User graphql.ID } {
+		return &struct{ User graphql.ID }{User: MarshalUserID(userID)}
+	}
+	listArgs := func(userID int32) *struct {
+		User  graphql.ID
+		First int32
+	} {
+		return &struct {
+			User  graphql.ID
+			First int32
+		}{User: MarshalUserID(userID), First: 20}
+	}
+
+	_, err := r.ExportUserData(context.Background(), exportArgs(1))
+	require.ErrorIs(t, err, auth.ErrMustBeSiteAdminOrSameUser)
+
+	ctx := actor.WithActor(context.Background(), actor.FromUser(2))
+
+	t.Run("other user", func(t *testing.T) {
+		_, err := r.ExportUserData(ctx, exportArgs(1))
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdminOrSameUser)
+		_, err = r.UserDataExportJobs(ctx, listArgs(1))
+		require.ErrorIs(t, err, auth.ErrMustBeSiteAdminOrSameUser)
+	})
+
+	t.Run("same user", func(t *testing.T) {
+		job, err := r.ExportUserData(ctx, exportArgs(2))
+		require.NoError(t, err)
+		require.Equal(t, "EXPORT", job.Kind())
+		require.Equal(t, "QUEUED", job.State())
+		require.Nil(t, job.DownloadURL())
+
+		jobs, err := r.UserDataExportJobs(ctx, listArgs(2))
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		require.Len(t, store.jobs, 1)
+		require.Equal(t, int32(2), *store.jobs[0].RequestedBy)
+	})
+}
@@ -41,13 +41,22 @@ func (r *schemaResolver) Users(ctx context.Context, args *usersArgs) (*userConne
 		opt.InactiveSince = args.InactiveSince.Time
 	}
 	args.ConnectionArgs.Set(&opt.LimitOffset)
-	if args.After != nil && opt.LimitOffset != nil {
-		cursor, err := strconv.ParseInt(*args.After, 10, 32)
-		if err != nil {
-			return nil, err
+	cursor := ""
+	if args.After != nil {
+		// Cursors used to be the offset of the page. Keep accepting them as an
+		// offset so that clients paginating across an upgrade can continue.
+		if offset, err := strconv.Atoi(*args.After); err == nil {
+			if offset < 0 {
+				return nil, errors.Errorf("invalid cursor %q", *args.After)
+			}
+			if opt.LimitOffset != nil {
+				opt.LimitOffset.Offset = offset
+			}
+		} else {
+			cursor = *args.After
 		}
-		opt.LimitOffset.Offset = int(cursor)
 	}
+	opt.Cursor = &cursor
 
 	return &userConnectionResolver{db: r.db, opt: opt, activePeriod: args.ActivePeriod}, nil
 }
@@ -66,10 +75,11 @@ type userConnectionResolver struct {
 	activePeriod *string
 
 	// cache results because they are used by multiple fields
-	once       sync.Once
-	users      []*types.User
-	totalCount int
-	err        error
+	once        sync.Once
+	users       []*types.User
+	hasNextPage bool
+	totalCount  int
+	err         error
 }
 
 func (r *userConnectionResolver) compute(ctx context.Context) ([]*types.User, int, error) {
@@ -92,11 +102,16 @@ func (r *userConnectionResolver) compute(ctx context.Context) ([]*types.User, in
 			return
 		}
 
-		r.users, err = r.db.Users().List(ctx, &r.opt)
+		users, err := r.db.Users().List(ctx, &r.opt)
 		if err != nil {
 			r.err = err
 			return
 		}
+		if r.opt.LimitOffset != nil {
+			r.users, r.hasNextPage = database.KeysetPage(users, r.opt.Limit)
+		} else {
+			r.users = users
+		}
 		r.totalCount, r.err = r.db.Users().Count(ctx, &r.opt)
 	})
 	return r.users, r.totalCount, r.err
@@ -127,27 +142,20 @@ func (r *userConnectionResolver) TotalCount(ctx context.Context) (int32, error)
 }
 
 func (r *userConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
-	users, totalCount, err := r.compute(ctx)
+	users, _, err := r.compute(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// We would have had all results when no limit set
-	if r.opt.LimitOffset == nil {
+	if !r.hasNextPage || len(users) == 0 {
 		return graphqlutil.HasNextPage(false), nil
 	}
 
-	after := r.opt.LimitOffset.Offset + len(users)
-
-	// We got less results than limit, means we've had all results
-	if after < r.opt.Limit {
-		return graphqlutil.HasNextPage(false), nil
-	}
-
-	if totalCount > after {
-		return graphqlutil.NextPageCursor(strconv.Itoa(after)), nil
+	cursor, err := database.UserKeysetCursor(users[len(users)-1])
+	if err != nil {
+		return nil, err
 	}
-	return graphqlutil.HasNextPage(false), nil
+	return graphqlutil.NextPageCursor(cursor), nil
 }
 
 func checkMembersAccess(ctx context.Context, db database.DB) error {
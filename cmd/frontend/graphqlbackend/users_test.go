@@ -58,18 +58,26 @@ func TestUsers(t *testing.T) {
 }
 
 func TestUsers_Pagination(t *testing.T) {
+	cursor, err := database.UserKeysetCursor(&types.User{ID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	users := database.NewMockUserStore()
 	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{SiteAdmin: true}, nil)
 	users.ListFunc.SetDefaultHook(func(ctx context.Context, opt *database.UsersListOptions) ([]*types.User, error) {
-		if opt.LimitOffset.Offset == 2 {
+		// Legacy offset cursors are passed as an offset after the first page.
+		if *opt.Cursor == cursor || (*opt.Cursor == "" && opt.Offset == 2) {
 			return []*types.User{
-				{Username: "user3"},
-				{Username: "user4"},
+				{ID: 3, Username: "user3"},
+				{ID: 4, Username: "user4"},
 			}, nil
 		}
+		// One more user than requested signals a next page
 		return []*types.User{
-			{Username: "user1"},
-			{Username: "user2"}}, nil
+			{ID: 1, Username: "user1"},
+			{ID: 2, Username: "user2"},
+			{ID: 3, Username: "user3"}}, nil
 	})
 	users.CountFunc.SetDefaultReturn(4, nil)
 
@@ -102,7 +110,38 @@ func TestUsers_Pagination(t *testing.T) {
 						"totalCount": 4,
 						"pageInfo": {
 							"hasNextPage": true,
-							"endCursor": "2"
+							"endCursor": "` + cursor + `"
+						 }
+					}
+				}
+			`,
+		},
+		{
+			Schema: mustParseGraphQLSchema(t, db),
+			Query: `
+				{
+					users(first: 2, after: "` + cursor + `") {
+						nodes { username }
+						totalCount
+						pageInfo { hasNextPage, endCursor }
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"users": {
+						"nodes": [
+							{
+								"username": "user3"
+							},
+							{
+								"username": "user4"
+							}
+						],
+						"totalCount": 4,
+						"pageInfo": {
+							"hasNextPage": false,
+							"endCursor": null
 						 }
 					}
 				}
@@ -183,6 +222,11 @@ func TestUsers_Pagination_Integration(t *testing.T) {
 	admin := users[0]
 	nonadmin := users[1]
 
+	cursor, err := database.UserKeysetCursor(users[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	tests := []usersQueryTest{
 		// no args
 		{
@@ -204,20 +248,34 @@ func TestUsers_Pagination_Integration(t *testing.T) {
 			wantUsers:      []string{"user1", "user2"},
 			wantTotalCount: 4,
 		},
-		// first: 2, after: 2
+		// first: 2, after: user2
 		{
 			ctx:            actor.WithActor(ctx, actor.FromUser(admin.ID)),
-			args:           "first: 2, after: \"2\"",
+			args:           fmt.Sprintf("first: 2, after: %q", cursor),
 			wantUsers:      []string{"user3", "user4"},
 			wantTotalCount: 4,
 		},
-		// first: 1, after: 2
+		// first: 1, after: user2
 		{
 			ctx:            actor.WithActor(ctx, actor.FromUser(admin.ID)),
-			args:           "first: 1, after: \"2\"",
+			args:           fmt.Sprintf("first: 1, after: %q", cursor),
 			wantUsers:      []string{"user3"},
 			wantTotalCount: 4,
 		},
+		// first: 2, after: legacy offset cursor
+		{
+			ctx:            actor.WithActor(ctx, actor.FromUser(admin.ID)),
+			args:           `first: 2, after: "2"`,
+			wantUsers:      []string{"user3", "user4"},
+			wantTotalCount: 4,
+		},
+		// first: 1, after: legacy offset cursor
+		{
+			ctx:            actor.WithActor(ctx, actor.FromUser(admin.ID)),
+			args:           `first: 1, after: "1"`,
+			wantUsers:      []string{"user2"},
+			wantTotalCount: 4,
+		},
 		// no admin on dotcom
 		{
 			ctx:       actor.WithActor(ctx, actor.FromUser(nonadmin.ID)),
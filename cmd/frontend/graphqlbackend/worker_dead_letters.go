@@ -0,0 +1,181 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/deadletter"
+)
+
+type workerDeadLettersArgs struct {
+	Store *string
+	First int32
+	After *string
+}
+
+func (r *schemaResolver) WorkerDeadLetters(ctx context.Context, args *workerDeadLettersArgs) (*workerDeadLetterConnectionResolver, error) {
+	// 🚨 SECURITY: Dead letters expose internal failure details, so only site admins
+	// may list them.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	opts := deadletter.ListOptions{Limit: int(args.First)}
+	if args.Store != nil {
+		opts.StoreName = *args.Store
+	}
+	if args.After != nil {
+		after, err := strconv.Atoi(*args.After)
+		if err != nil {
+			return nil, err
+		}
+		opts.After = after
+	}
+
+	return &workerDeadLetterConnectionResolver{store: deadletter.NewStore(r.db.Handle()), opts: opts}, nil
+}
+
+func (r *schemaResolver) RetryWorkerDeadLetters(ctx context.Context, args *struct{ IDs []graphql.ID }) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins may retry dead letters.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	ids, err := unmarshalWorkerDeadLetterIDs(args.IDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := deadletter.NewStore(r.db.Handle()).RequestRetry(ctx, ids); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *schemaResolver) DiscardWorkerDeadLetters(ctx context.Context, args *struct{ IDs []graphql.ID }) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins may discard dead letters.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	ids, err := unmarshalWorkerDeadLetterIDs(args.IDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := deadletter.NewStore(r.db.Handle()).Discard(ctx, ids); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func unmarshalWorkerDeadLetterIDs(gqlIDs []graphql.ID) ([]int, error) {
+	ids := make([]int, 0, len(gqlIDs))
+	for _, gqlID := range gqlIDs {
+		var id int
+		if err := relay.UnmarshalSpec(gqlID, &id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// workerDeadLetterConnectionResolver resolves a list of dead letters.
+//
+// 🚨 SECURITY: When instantiating a workerDeadLetterConnectionResolver value, the caller
+// MUST check permissions.
+type workerDeadLetterConnectionResolver struct {
+	store deadletter.Store
+	opts  deadletter.ListOptions
+
+	// cache results because they are used by multiple fields
+	once        sync.Once
+	deadLetters []*deadletter.DeadLetter
+	next        int
+	err         error
+}
+
+func (r *workerDeadLetterConnectionResolver) Nodes(ctx context.Context) ([]*workerDeadLetterResolver, error) {
+	deadLetters, _, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*workerDeadLetterResolver, 0, len(deadLetters))
+	for _, d := range deadLetters {
+		resolvers = append(resolvers, &workerDeadLetterResolver{deadLetter: d})
+	}
+	return resolvers, nil
+}
+
+func (r *workerDeadLetterConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := r.store.Count(ctx, r.opts.StoreName)
+	return int32(count), err
+}
+
+func (r *workerDeadLetterConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	_, next, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if next != 0 {
+		return graphqlutil.NextPageCursor(strconv.Itoa(next)), nil
+	}
+	return graphqlutil.HasNextPage(false), nil
+}
+
+func (r *workerDeadLetterConnectionResolver) compute(ctx context.Context) ([]*deadletter.DeadLetter, int, error) {
+	r.once.Do(func() {
+		// Fetch one more dead letter than requested to determine whether there is a next page.
+		opts := r.opts
+		opts.Limit++
+		r.deadLetters, r.err = r.store.List(ctx, opts)
+		if r.err == nil && len(r.deadLetters) > r.opts.Limit {
+			r.deadLetters = r.deadLetters[:r.opts.Limit]
+			r.next = r.deadLetters[len(r.deadLetters)-1].ID
+		}
+	})
+	return r.deadLetters, r.next, r.err
+}
+
+type workerDeadLetterResolver struct {
+	deadLetter *deadletter.DeadLetter
+}
+
+func (r *workerDeadLetterResolver) ID() graphql.ID {
+	return relay.MarshalID("WorkerDeadLetter", r.deadLetter.ID)
+}
+
+func (r *workerDeadLetterResolver) Store() string { return r.deadLetter.StoreName }
+
+func (r *workerDeadLetterResolver) RecordID() int32 { return int32(r.deadLetter.RecordID) }
+
+func (r *workerDeadLetterResolver) FailureMessage() *string {
+	if r.deadLetter.FailureMessage == "" {
+		return nil
+	}
+	return &r.deadLetter.FailureMessage
+}
+
+func (r *workerDeadLetterResolver) Stack() *string {
+	if r.deadLetter.Stack == "" {
+		return nil
+	}
+	return &r.deadLetter.Stack
+}
+
+func (r *workerDeadLetterResolver) NumCrashes() int32 { return int32(r.deadLetter.NumCrashes) }
+
+func (r *workerDeadLetterResolver) RetryRequestedAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.deadLetter.RetryRequestedAt)
+}
+
+func (r *workerDeadLetterResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.deadLetter.CreatedAt}
+}
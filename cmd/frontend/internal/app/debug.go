@@ -11,6 +11,7 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -26,6 +27,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/debugserver"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine/recorder"
 	"github.com/sourcegraph/sourcegraph/internal/otlpenv"
 	srcprometheus "github.com/sourcegraph/sourcegraph/internal/src-prometheus"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -45,6 +47,7 @@ func addNoK8sClientHandler(r *mux.Router, db database.DB) {
 	noHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, `Cluster information not available`)
 		fmt.Fprintf(w, `<br><br><a href="headers">headers</a><br>`)
+		fmt.Fprintf(w, `<a href="background-jobs">background jobs</a><br>`)
 	})
 	r.Handle("/", debugproxies.AdminOnly(db, noHandler))
 }
@@ -56,6 +59,7 @@ func addDebugHandlers(r *mux.Router, db database.DB) {
 	addJaeger(r, db)
 	addSentry(r)
 	addOpenTelemetryProtocolAdapter(r)
+	addBackgroundJobs(r, db)
 
 	var rph debugproxies.ReverseProxyHandler
 
@@ -84,6 +88,89 @@ func addDebugHandlers(r *mux.Router, db database.DB) {
 	rph.AddToRouter(r, db) // todo
 }
 
+// addBackgroundJobs registers a plain-text overview of the liveness of the background
+// routines of all services, listing stuck routines first.
+func addBackgroundJobs(r *mux.Router, db database.DB) {
+	backgroundJobs := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobs, err := recorder.GetBackgroundJobInfos(recorder.GetCache(), "", 1, 1)
+		if err != nil {
+			http.Error(w, "failed to get background jobs: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		type row struct {
+			job      string
+			routine  recorder.RoutineInfo
+			instance recorder.RoutineInstanceInfo
+			stuck    bool
+		}
+		now := time.Now()
+		var stuck, other []row
+		for _, job := range jobs {
+			for _, routine := range job.Routines {
+				interval := time.Duration(routine.IntervalMs) * time.Millisecond
+				for _, instance := range routine.Instances {
+					rr := row{job: job.Name, routine: routine, instance: instance, stuck: instance.Stuck(now, interval)}
+					if rr.stuck {
+						stuck = append(stuck, rr)
+					} else {
+						other = append(other, rr)
+					}
+				}
+			}
+		}
+
+		formatTime := func(t *time.Time) string {
+			if t == nil {
+				return "-"
+			}
+			return now.Sub(*t).Truncate(time.Second).String() + " ago"
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintf(w, "%d stuck routine instance(s)\n\n", len(stuck))
+
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "STATUS\tJOB\tROUTINE\tHOST\tINTERVAL\tLAST HEARTBEAT\tCURRENT RUN STARTED\tLAST RUN DURATION\tLAST ERROR")
+		for _, rr := range append(stuck, other...) {
+			status := "stopped"
+			switch {
+			case rr.stuck:
+				status = "STUCK"
+			case rr.instance.Running():
+				status = "running"
+			}
+
+			interval := "-"
+			if rr.routine.IntervalMs > 0 {
+				interval = (time.Duration(rr.routine.IntervalMs) * time.Millisecond).String()
+			}
+			lastRunDuration := "-"
+			if len(rr.routine.RecentRuns) > 0 {
+				lastRunDuration = (time.Duration(rr.routine.RecentRuns[0].DurationMs) * time.Millisecond).String()
+			}
+			lastError := "-"
+			if rr.instance.LastError != nil {
+				lastError = formatTime(&rr.instance.LastError.At) + ": " + strings.ReplaceAll(rr.instance.LastError.ErrorMessage, "\n", " ")
+			}
+
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				status,
+				rr.job,
+				rr.routine.Name,
+				rr.instance.HostName,
+				interval,
+				formatTime(rr.instance.LastHeartbeatAt),
+				formatTime(rr.instance.CurrentRunStartedAt()),
+				lastRunDuration,
+				lastError,
+			)
+		}
+		_ = tw.Flush()
+	})
+	r.Handle("/background-jobs", debugproxies.AdminOnly(db, backgroundJobs))
+}
+
 // PreMountGrafanaHook (if set) is invoked as a hook prior to mounting a
 // the Grafana endpoint to the debug router.
 var PreMountGrafanaHook func() error
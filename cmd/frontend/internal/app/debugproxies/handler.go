@@ -54,6 +54,7 @@ func (rph *ReverseProxyHandler) serveIndex(w http.ResponseWriter, r *http.Reques
 	if len(displayNames) == 0 {
 		fmt.Fprintf(w, `Instrumentation: no endpoints found<br>`)
 		fmt.Fprintf(w, `<br><br><a href="headers">headers</a><br>`)
+		fmt.Fprintf(w, `<a href="background-jobs">background jobs</a><br>`)
 		return
 	}
 
@@ -63,6 +64,7 @@ func (rph *ReverseProxyHandler) serveIndex(w http.ResponseWriter, r *http.Reques
 		fmt.Fprintf(w, `<a href="proxies/%s/">%s</a><br>`, displayName, displayName)
 	}
 	fmt.Fprintf(w, `<br><br><a href="headers">headers</a><br>`)
+	fmt.Fprintf(w, `<a href="background-jobs">background jobs</a><br>`)
 }
 
 // serveReverseProxy routes the request to the appropriate reverse proxy by splitting the request path and finding
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -20,11 +21,13 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/lfs"
 )
 
 // Examples:
@@ -48,12 +51,21 @@ import (
 // Get a zip/tar archive of a _file_ in a repository:
 //     curl -H 'Accept: application/zip' http://localhost:3080/github.com/gorilla/mux/-/raw/mux.go -o repo-file.zip
 //
+// Get a zip/tar archive of only some subdirectories of a repository, relative to the requested path:
+//     curl -H 'Accept: application/x-tar' 'http://localhost:3080/github.com/sourcegraph/sourcegraph/-/raw/?path=cmd/frontend&path=internal/gitserver' -o repo-sparse.tar
+//
 // Authenticate using an access token:
 //     curl -H 'Accept: application/zip' http://fe70a9eeffc8ea7b1edf7c67095c143d1ada7e1b@localhost:3080/github.com/gorilla/mux/-/raw/ -o repo.zip
 //
 // Download an archive without specifying an Accept header (e.g. download via browser):
 //     curl -O -J http://localhost:3080/github.com/gorilla/mux/-/raw?format=zip
 //
+// Get a zip/tar archive of a repository, including the content of its submodules:
+//     curl -H 'Accept: application/x-tar' 'http://localhost:3080/github.com/sourcegraph/sourcegraph/-/raw/?submodules=true' -o repo.tar
+//
+// Get the pointer of a file stored in Git LFS, even if fetching LFS content is enabled:
+//     http://localhost:3080/github.com/sourcegraph/sourcegraph/-/raw/ui/assets/video.mp4?lfs=pointer
+//
 // Known issues:
 //
 // - For security reasons, all non-archive files (e.g. code, images, binaries) are served with a Content-Type of text/plain.
@@ -150,6 +162,41 @@ func serveRaw(db database.DB, gitserverClient gitserver.Client) handlerFunc {
 
 		switch contentType {
 		case applicationZip, applicationXTar:
+			relativePath := strings.TrimPrefix(requestedPath, "/")
+			if relativePath == "" {
+				relativePath = "."
+			}
+
+			if relativePath == "." {
+				requestType = "rootarchive"
+			} else {
+				requestType = "patharchive"
+			}
+
+			paths := []string{relativePath}
+			if subPaths := r.URL.Query()["path"]; len(subPaths) > 0 {
+				// Only archive the given subtrees of the requested path, so that
+				// consumers interested in a few directories of a large repository
+				// do not need to download all of it.
+				requestType = "sparsearchive"
+				paths = paths[:0]
+				for _, p := range subPaths {
+					p = path.Join(relativePath, p)
+					if p == ".." || strings.HasPrefix(p, "../") {
+						http.Error(w, html.EscapeString(fmt.Sprintf("path %q is outside of the repository", p)), http.StatusBadRequest)
+						return nil // request handled
+					}
+					if _, err := gitserverClient.Stat(r.Context(), authz.DefaultSubRepoPermsChecker, common.Repo.Name, common.CommitID, p); err != nil {
+						if os.IsNotExist(err) {
+							http.Error(w, html.EscapeString(err.Error()), http.StatusNotFound)
+							return nil // request handled
+						}
+						return err
+					}
+					paths = append(paths, p)
+				}
+			}
+
 			// Set the proper filename field, so that downloading "/github.com/gorilla/mux/-/raw" gives us a
 			// "mux.zip" file (e.g. when downloading via a browser) or a .tar file depending on the contentType.
 			ext := ".zip"
@@ -166,27 +213,26 @@ func serveRaw(db database.DB, gitserverClient gitserver.Client) handlerFunc {
 				format = gitserver.ArchiveFormatTar
 			}
 
-			relativePath := strings.TrimPrefix(requestedPath, "/")
-			if relativePath == "" {
-				relativePath = "."
-			}
-
-			if relativePath == "." {
-				requestType = "rootarchive"
-			} else {
-				requestType = "patharchive"
-			}
-
 			metricRunning := metricRawArchiveRunning.WithLabelValues(string(format))
 			metricRunning.Inc()
 			defer metricRunning.Dec()
 
+			if r.URL.Query().Get("submodules") == "true" {
+				requestType = "submodulearchive"
+				return writeArchiveWithSubmodules(r.Context(), w, db, gitserverClient, format, common.Repo.Name, common.CommitID, paths)
+			}
+
+			pathspecs := make([]gitdomain.Pathspec, 0, len(paths))
+			for _, p := range paths {
+				pathspecs = append(pathspecs, gitdomain.PathspecLiteral(p))
+			}
+
 			// NOTE: we do not use vfsutil since most archives are just streamed once so
 			// caching locally is not useful. Additionally we transfer the output over the
 			// internet, so we use default compression levels on zips (instead of no
 			// compression).
 			f, err := gitserverClient.ArchiveReader(r.Context(), authz.DefaultSubRepoPermsChecker, common.Repo.Name,
-				gitserver.ArchiveOptions{Format: format, Treeish: string(common.CommitID), Pathspecs: []gitdomain.Pathspec{gitdomain.PathspecLiteral(relativePath)}})
+				gitserver.ArchiveOptions{Format: format, Treeish: string(common.CommitID), Pathspecs: pathspecs})
 			if err != nil {
 				return err
 			}
@@ -277,12 +323,53 @@ func serveRaw(db database.DB, gitserverClient gitserver.Client) handlerFunc {
 				return err
 			}
 			defer f.Close()
+
+			if size < lfs.PointerSizeCutoff {
+				content, err := io.ReadAll(f)
+				if err != nil {
+					return err
+				}
+				if pointer := lfs.ParsePointer(string(content)); pointer != nil {
+					requestType = "lfs"
+					return serveLFSPointer(w, r, gitserverClient, common.Repo.Name, pointer, content)
+				}
+				_, err = w.Write(content)
+				return err
+			}
+
 			_, err = io.Copy(w, f)
 			return err
 		}
 	}
 }
 
+// serveLFSPointer serves the file stored in Git LFS that the given pointer was
+// read from. If fetching LFS content is enabled and the file is not too large,
+// its content is served. Otherwise the pointer itself is served, so that
+// clients can show a placeholder instead. Either way, the X-Sourcegraph-LFS-*
+// headers mark the response as an LFS file.
+func serveLFSPointer(w http.ResponseWriter, r *http.Request, gitserverClient gitserver.Client, repo api.RepoName, pointer *lfs.Pointer, content []byte) error {
+	w.Header().Set("X-Sourcegraph-LFS-Oid", pointer.OID)
+	w.Header().Set("X-Sourcegraph-LFS-Size", strconv.FormatInt(pointer.Size, 10))
+
+	if maxSize, ok := lfs.FetchContentEnabled(); ok && pointer.Size <= maxSize && r.URL.Query().Get("lfs") != "pointer" {
+		rc, err := gitserverClient.LFSContent(r.Context(), repo, pointer.OID, pointer.Size)
+		if err == nil {
+			defer rc.Close()
+			w.Header().Set("X-Sourcegraph-LFS", "content")
+			_, err = io.Copy(w, rc)
+			return err
+		}
+		// The pointer is still useful to the client, so we serve it rather than
+		// failing the request.
+		log15.Warn("failed to fetch LFS content", "repo", repo, "oid", pointer.OID, "error", err)
+	}
+
+	w.Header().Set("X-Sourcegraph-LFS", "pointer")
+	_, err := w.Write(content)
+	return err
+}
+
 var metricRawDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 	Name:    "src_frontend_http_raw_duration_seconds",
 	Help:    "A histogram of latencies for the raw endpoint.",
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"context"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+)
+
+// maxArchiveSubmoduleDepth is the maximum depth of nested submodules whose
+// content is included in archives.
+const maxArchiveSubmoduleDepth = 8
+
+// archiveWriter writes the entries of an archive.
+type archiveWriter interface {
+	WriteEntry(hdr *tar.Header, r io.Reader) error
+	Close() error
+}
+
+type tarArchiveWriter struct{ *tar.Writer }
+
+func (w tarArchiveWriter) WriteEntry(hdr *tar.Header, r io.Reader) error {
+	if err := w.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := io.Copy(w, r)
+	return err
+}
+
+type zipArchiveWriter struct{ *zip.Writer }
+
+func (w zipArchiveWriter) WriteEntry(hdr *tar.Header, r io.Reader) error {
+	zh, err := zip.FileInfoHeader(hdr.FileInfo())
+	if err != nil {
+		return err
+	}
+	zh.Name = hdr.Name
+	zh.Modified = hdr.ModTime
+	if hdr.Typeflag != tar.TypeDir {
+		zh.Method = zip.Deflate
+	}
+	f, err := w.CreateHeader(zh)
+	if err != nil {
+		return err
+	}
+	if hdr.Typeflag == tar.TypeSymlink {
+		// Zip archives store the target of symlinks as their content.
+		_, err = io.WriteString(f, hdr.Linkname)
+		return err
+	}
+	_, err = io.Copy(f, r)
+	return err
+}
+
+// writeArchiveWithSubmodules writes an archive of the given paths of repo at
+// commit to w. Unlike the archives created by git, it includes the content of
+// submodules that map to repositories known to Sourcegraph, at the commits they
+// are pinned to. Submodules that do not map to a known repository are left as
+// empty directories.
+func writeArchiveWithSubmodules(ctx context.Context, w io.Writer, db database.DB, client gitserver.Client, format gitserver.ArchiveFormat, repo api.RepoName, commit api.CommitID, paths []string) error {
+	var aw archiveWriter
+	if format == gitserver.ArchiveFormatZip {
+		aw = zipArchiveWriter{zip.NewWriter(w)}
+	} else {
+		aw = tarArchiveWriter{tar.NewWriter(w)}
+	}
+
+	visited := map[string]struct{}{}
+	if err := appendArchive(ctx, aw, db, client, repo, commit, paths, "", 0, visited); err != nil {
+		return err
+	}
+	return aw.Close()
+}
+
+// appendArchive appends the given paths of repo at commit to the archive, with
+// their names prefixed by prefix, followed by the content of its submodules.
+func appendArchive(ctx context.Context, aw archiveWriter, db database.DB, client gitserver.Client, repo api.RepoName, commit api.CommitID, paths []string, prefix string, depth int, visited map[string]struct{}) error {
+	key := string(repo) + "@" + string(commit)
+	if _, ok := visited[key]; ok {
+		return nil
+	}
+	visited[key] = struct{}{}
+
+	pathspecs := make([]gitdomain.Pathspec, 0, len(paths))
+	for _, p := range paths {
+		pathspecs = append(pathspecs, gitdomain.PathspecLiteral(p))
+	}
+
+	// Archives are always requested as tar from gitserver, since the entries of
+	// a tar archive can be read while it is streamed.
+	rc, err := client.ArchiveReader(ctx, authz.DefaultSubRepoPermsChecker, repo,
+		gitserver.ArchiveOptions{Format: gitserver.ArchiveFormatTar, Treeish: string(commit), Pathspecs: pathspecs})
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		// The global header only holds the commit ID of the archive, which is
+		// ambiguous once submodules are included.
+		if hdr.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+		if prefix != "" {
+			name := path.Join(prefix, hdr.Name)
+			if strings.HasSuffix(hdr.Name, "/") {
+				name += "/"
+			}
+			hdr.Name = name
+		}
+		if err := aw.WriteEntry(hdr, tr); err != nil {
+			return err
+		}
+	}
+
+	if depth >= maxArchiveSubmoduleDepth {
+		return nil
+	}
+
+	submodules, err := backend.ListSubmodules(ctx, client, repo, commit)
+	if err != nil {
+		return err
+	}
+	for _, submodule := range submodules {
+		if !pathIncluded(submodule.Path, paths) {
+			continue
+		}
+		target, err := backend.ResolveSubmoduleRepo(ctx, db, repo, submodule.URL)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			continue
+		}
+		err = appendArchive(ctx, aw, db, client, target.Name, submodule.CommitID, []string{"."}, path.Join(prefix, submodule.Path), depth+1, visited)
+		if err != nil {
+			// The pinned commit may not have been pushed to the target repository,
+			// or the target repository may not be cloned yet. The submodule is
+			// then left as an empty directory.
+			if errcode.IsNotFound(err) || gitdomain.IsRepoNotExist(err) {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// pathIncluded reports whether p is one of paths or is contained in one of them.
+func pathIncluded(p string, paths []string) bool {
+	for _, included := range paths {
+		if included == "." || p == included || strings.HasPrefix(p, included+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -10,14 +12,18 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sort"
 	"strings"
 	"testing"
 
+	"github.com/gorilla/mux"
+
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/fileutil"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -218,6 +224,135 @@ func Test_serveRawWithContentArchive(t *testing.T) {
 		}
 	})
 
+	t.Run("success response for sparse archive", func(t *testing.T) {
+		gsClient := gitserver.NewMockClient()
+		gsClient.StatFunc.SetDefaultReturn(&fileutil.FileInfo{Mode_: os.ModeDir}, nil)
+		gsClient.ArchiveReaderFunc.SetDefaultReturn(io.NopCloser(strings.NewReader(mockGitServerResponse)), nil)
+
+		req := httptest.NewRequest("GET", "/github.com/sourcegraph/sourcegraph/-/raw/cmd?format=tar&path=frontend&path=gitserver", nil)
+		req = mux.SetURLVars(req, map[string]string{"Path": "cmd"})
+		w := httptest.NewRecorder()
+
+		db := database.NewMockDB()
+		err := serveRaw(db, gsClient)(w, req)
+		if err != nil {
+			t.Fatalf("Failed to invoke serveRaw: %v", err)
+		}
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Want %d but got %d", http.StatusOK, w.Code)
+		}
+
+		history := gsClient.ArchiveReaderFunc.History()
+		if len(history) != 1 {
+			t.Fatalf("Want 1 call to ArchiveReader but got %d", len(history))
+		}
+		want := []gitdomain.Pathspec{gitdomain.PathspecLiteral("cmd/frontend"), gitdomain.PathspecLiteral("cmd/gitserver")}
+		assert.Equal(t, want, history[0].Arg3.Pathspecs)
+
+		body := string(w.Body.Bytes())
+		if body != mockGitServerResponse {
+			t.Errorf("Want %q in body, but got %q", mockGitServerResponse, body)
+		}
+	})
+
+	t.Run("success response for archive with submodules", func(t *testing.T) {
+		archives := map[api.RepoName][]byte{
+			"test": makeTar(t, map[string]string{"README.md": "test", "vendor/lib/": ""}),
+			"lib":  makeTar(t, map[string]string{"lib.go": "package lib"}),
+		}
+
+		gsClient := gitserver.NewMockClient()
+		gsClient.ArchiveReaderFunc.SetDefaultHook(func(_ context.Context, _ authz.SubRepoPermissionChecker, repo api.RepoName, _ gitserver.ArchiveOptions) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(archives[repo])), nil
+		})
+		gsClient.ReadFileFunc.SetDefaultHook(func(_ context.Context, _ authz.SubRepoPermissionChecker, repo api.RepoName, _ api.CommitID, name string) ([]byte, error) {
+			if repo == "test" && name == ".gitmodules" {
+				return []byte("[submodule \"lib\"]\n\tpath = vendor/lib\n\turl = ../lib.git\n"), nil
+			}
+			return nil, os.ErrNotExist
+		})
+		gsClient.StatFunc.SetDefaultReturn(&fileutil.FileInfo{
+			Name_: "vendor/lib",
+			Mode_: gitdomain.ModeSubmodule,
+			Sys_:  gitdomain.Submodule{CommitID: "deadbeef"},
+		}, nil)
+
+		repos := database.NewMockRepoStore()
+		repos.GetByNameFunc.SetDefaultHook(func(_ context.Context, name api.RepoName) (*types.Repo, error) {
+			return &types.Repo{Name: name}, nil
+		})
+		db := database.NewMockDB()
+		db.ReposFunc.SetDefaultReturn(repos)
+
+		req := httptest.NewRequest("GET", "/github.com/sourcegraph/sourcegraph/-/raw?format=tar&submodules=true", nil)
+		w := httptest.NewRecorder()
+
+		err := serveRaw(db, gsClient)(w, req)
+		if err != nil {
+			t.Fatalf("Failed to invoke serveRaw: %v", err)
+		}
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Want %d but got %d", http.StatusOK, w.Code)
+		}
+
+		var names []string
+		tr := tar.NewReader(w.Body)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, hdr.Name)
+		}
+		assert.Equal(t, []string{"README.md", "vendor/lib/", "vendor/lib/lib.go"}, names)
+
+		history := gsClient.ArchiveReaderFunc.History()
+		if len(history) != 2 {
+			t.Fatalf("Want 2 calls to ArchiveReader but got %d", len(history))
+		}
+		assert.Equal(t, "deadbeef", history[1].Arg3.Treeish)
+	})
+
+	t.Run("404 Not Found for sparse archive of non existent path", func(t *testing.T) {
+		gsClient := gitserver.NewMockClient()
+		gsClient.StatFunc.SetDefaultReturn(nil, os.ErrNotExist)
+
+		req := httptest.NewRequest("GET", "/github.com/sourcegraph/sourcegraph/-/raw?format=tar&path=missing", nil)
+		w := httptest.NewRecorder()
+
+		db := database.NewMockDB()
+		err := serveRaw(db, gsClient)(w, req)
+		if err != nil {
+			t.Fatalf("Failed to invoke serveRaw: %v", err)
+		}
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("Want %d but got %d", http.StatusNotFound, w.Code)
+		}
+		assert.Empty(t, gsClient.ArchiveReaderFunc.History())
+	})
+
+	t.Run("400 Bad Request for sparse archive of path outside of the repository", func(t *testing.T) {
+		gsClient := gitserver.NewMockClient()
+
+		req := httptest.NewRequest("GET", "/github.com/sourcegraph/sourcegraph/-/raw?format=tar&path=../../etc", nil)
+		w := httptest.NewRecorder()
+
+		db := database.NewMockDB()
+		err := serveRaw(db, gsClient)(w, req)
+		if err != nil {
+			t.Fatalf("Failed to invoke serveRaw: %v", err)
+		}
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Want %d but got %d", http.StatusBadRequest, w.Code)
+		}
+	})
 }
 
 func Test_serveRawWithContentTypePlain(t *testing.T) {
@@ -405,3 +540,34 @@ func Test_serveRawRepoCloning(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code, "http response status")
 	assert.Equal(t, "Repository unavailable while cloning.", string(w.Body.Bytes()), "http response body")
 }
+
+// makeTar returns a tar archive with the given files. Names ending with a slash
+// are directories.
+func makeTar(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range names {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name])), Typeflag: tar.TypeReg}
+		if strings.HasSuffix(name, "/") {
+			hdr.Mode, hdr.Typeflag = 0o755, tar.TypeDir
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(files[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
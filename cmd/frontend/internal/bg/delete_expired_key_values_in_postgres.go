@@ -0,0 +1,27 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+// DeleteExpiredKeyValuesInPostgres deletes the expired values of the postgres
+// backed replacement of redis. Reads ignore expired values, so this only keeps
+// the table from growing.
+func DeleteExpiredKeyValuesInPostgres(ctx context.Context, logger log.Logger, db database.DB) {
+	logger = logger.Scoped("deleteExpiredKeyValues", "background job to prune expired values of the postgres backed replacement of redis")
+
+	for {
+		deleted, err := db.RedisKeyValue().DeleteExpired(ctx)
+		if err != nil {
+			logger.Error("deleting expired rows from redis_key_value table", log.Error(err))
+		} else if deleted > 0 {
+			logger.Debug("deleted expired rows from redis_key_value table", log.Int("count", deleted))
+		}
+		time.Sleep(10 * time.Minute)
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf/deploy"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	connections "github.com/sourcegraph/sourcegraph/internal/database/connections/live"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/database/migration/store"
 	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
 	"github.com/sourcegraph/sourcegraph/internal/env"
@@ -45,6 +46,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/oobmigration"
 	"github.com/sourcegraph/sourcegraph/internal/redispool"
+	"github.com/sourcegraph/sourcegraph/internal/redispool/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/service"
 	"github.com/sourcegraph/sourcegraph/internal/sysreq"
 	"github.com/sourcegraph/sourcegraph/internal/users"
@@ -99,7 +101,11 @@ func Main(ctx context.Context, observationCtx *observation.Context, ready servic
 	if err != nil {
 		return err
 	}
-	db := database.NewDB(logger, sqlDB)
+	replicas, err := dbconn.ConnectReplicas(logger, "frontend")
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to database replicas")
+	}
+	db := database.NewDBWithReplicas(logger, sqlDB, replicas)
 
 	if os.Getenv("SRC_DISABLE_OOBMIGRATION_VALIDATION") != "" {
 		if !deploy.IsApp() {
@@ -121,7 +127,7 @@ func Main(ctx context.Context, observationCtx *observation.Context, ready servic
 	highlight.Init()
 
 	// After our DB, redis is our next most important datastore
-	if err := redispoolRegisterDB(db); err != nil {
+	if err := dbstore.Register(db); err != nil {
 		return errors.Wrap(err, "failed to register postgres backed redis")
 	}
 
@@ -210,6 +216,7 @@ func Main(ctx context.Context, observationCtx *observation.Context, ready servic
 
 	goroutine.Go(func() { bg.CheckRedisCacheEvictionPolicy() })
 	goroutine.Go(func() { bg.DeleteOldCacheDataInRedis() })
+	goroutine.Go(func() { bg.DeleteExpiredKeyValuesInPostgres(context.Background(), logger, db) })
 	goroutine.Go(func() { bg.DeleteOldEventLogsInPostgres(context.Background(), logger, db) })
 	goroutine.Go(func() { bg.DeleteOldSecurityEventLogsInPostgres(context.Background(), logger, db) })
 	goroutine.Go(func() { bg.UpdatePermissions(ctx, logger, db) })
@@ -268,6 +275,10 @@ func makeExternalAPI(db database.DB, logger sglog.Logger, schema *graphql.Schema
 		return nil, err
 	}
 
+	// The usage of access tokens is recorded by both the API and the app
+	// handlers, and written to the database in the background.
+	accessTokenUsage := httpapi.NewAccessTokenUsageRecorder(db, logger)
+
 	// Create the external HTTP handler.
 	externalHandler := newExternalHTTPHandler(
 		db,
@@ -280,6 +291,7 @@ func makeExternalAPI(db database.DB, logger sglog.Logger, schema *graphql.Schema
 			BitbucketServerSyncWebhook:      enterprise.ReposBitbucketServerWebhook,
 			BitbucketCloudSyncWebhook:       enterprise.ReposBitbucketCloudWebhook,
 			PermissionsGitHubWebhook:        enterprise.PermissionsGitHubWebhook,
+			PermissionsGitLabWebhook:        enterprise.PermissionsGitLabWebhook,
 			BatchesGitHubWebhook:            enterprise.BatchesGitHubWebhook,
 			BatchesGitLabWebhook:            enterprise.BatchesGitLabWebhook,
 			BatchesBitbucketServerWebhook:   enterprise.BatchesBitbucketServerWebhook,
@@ -292,12 +304,16 @@ func makeExternalAPI(db database.DB, logger sglog.Logger, schema *graphql.Schema
 			NewCodeIntelUploadHandler:       enterprise.NewCodeIntelUploadHandler,
 			NewComputeStreamHandler:         enterprise.NewComputeStreamHandler,
 			CodeInsightsDataExportHandler:   enterprise.CodeInsightsDataExportHandler,
+			SearchExportDownloadHandler:     enterprise.SearchExportDownloadHandler,
+			UserDataExportDownloadHandler:   enterprise.UserDataExportDownloadHandler,
+			ExecutorArtifactDownloadHandler: enterprise.ExecutorArtifactDownloadHandler,
 			NewDotcomLicenseCheckHandler:    enterprise.NewDotcomLicenseCheckHandler,
 			NewChatCompletionsStreamHandler: enterprise.NewChatCompletionsStreamHandler,
 			NewCodeCompletionsHandler:       enterprise.NewCodeCompletionsHandler,
 		},
 		enterprise.NewExecutorProxyHandler,
 		enterprise.NewGitHubAppSetupHandler,
+		accessTokenUsage,
 	)
 	httpServer := &http.Server{
 		Handler:      externalHandler,
@@ -307,7 +323,7 @@ func makeExternalAPI(db database.DB, logger sglog.Logger, schema *graphql.Schema
 
 	server := httpserver.New(listener, httpServer, makeServerOptions()...)
 	logger.Debug("HTTP running", sglog.String("on", httpAddr))
-	return server, nil
+	return goroutine.CombinedRoutine{server, accessTokenUsage.Routine()}, nil
 }
 
 func makeInternalAPI(
@@ -395,18 +411,6 @@ func makeRateLimitWatcher() (*graphqlbackend.BasicLimitWatcher, error) {
 	return graphqlbackend.NewBasicLimitWatcher(sglog.Scoped("BasicLimitWatcher", "basic rate-limiter"), store), nil
 }
 
-// redispoolRegisterDB registers our postgres backed redis. These package
-// avoid depending on each other, hence the wrapping to get Go to play nice
-// with the interface definitions.
-func redispoolRegisterDB(db database.DB) error {
-	kvNoTX := db.RedisKeyValue()
-	return redispool.DBRegisterStore(func(ctx context.Context, f func(redispool.DBStore) error) error {
-		return kvNoTX.WithTransact(ctx, func(tx database.RedisKeyValueStore) error {
-			return f(tx)
-		})
-	})
-}
-
 // GetInternalAddr returns the address of the internal HTTP API server.
 func GetInternalAddr() string {
 	return httpAddrInternal
@@ -0,0 +1,161 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/felixge/httpsnoop"
+	"github.com/sourcegraph/log"
+
+	apirouter "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/httpapi/router"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// accessTokenUsageFlushInterval is how often the usage of access tokens
+// recorded by an instance of the frontend is written to the database.
+const accessTokenUsageFlushInterval = time.Minute
+
+// maxAccessTokenUsageOperationLength is the maximum length of the GraphQL
+// request names recorded as operations.
+const maxAccessTokenUsageOperationLength = 100
+
+type accessTokenUsageKey struct {
+	tokenHash string
+	bucket    time.Time
+	endpoint  string
+	operation string
+}
+
+// AccessTokenUsageRecorder records the usage of the API with access tokens.
+// The usage is aggregated in memory, and written to the database every
+// accessTokenUsageFlushInterval by the background routine returned by
+// Routine, and once more when the routine is stopped.
+type AccessTokenUsageRecorder struct {
+	db     database.DB
+	logger log.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	pending map[accessTokenUsageKey]*database.AccessTokenUsageRecord
+}
+
+func NewAccessTokenUsageRecorder(db database.DB, logger log.Logger) *AccessTokenUsageRecorder {
+	return &AccessTokenUsageRecorder{
+		db:      db,
+		logger:  logger.Scoped("accessTokenUsage", "records the usage of access tokens"),
+		now:     time.Now,
+		pending: map[accessTokenUsageKey]*database.AccessTokenUsageRecord{},
+	}
+}
+
+var (
+	_ goroutine.Handler   = (*AccessTokenUsageRecorder)(nil)
+	_ goroutine.Finalizer = (*AccessTokenUsageRecorder)(nil)
+)
+
+// Routine returns the background routine that writes the recorded usage to
+// the database.
+func (u *AccessTokenUsageRecorder) Routine() goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(
+		context.Background(),
+		u,
+		goroutine.WithName("frontend.access_token_usage_recorder"),
+		goroutine.WithDescription("writes the usage of access tokens to the database"),
+		goroutine.WithInterval(accessTokenUsageFlushInterval),
+	)
+}
+
+// serveHTTP serves the request authenticated with the given access token, and
+// records its usage.
+func (u *AccessTokenUsageRecorder) serveHTTP(next http.Handler, w http.ResponseWriter, r *http.Request, token string) {
+	m := httpsnoop.CaptureMetrics(next, w, r)
+
+	// The route name is only known once the request has been routed.
+	endpoint := trace.RouteName(r.Context())
+	var operation string
+	// GraphQL requests are named by their query string, such as
+	// /.api/graphql?Search. Query strings with parameters are not names, and
+	// may contain the access token.
+	if endpoint == apirouter.GraphQL && !strings.Contains(r.URL.RawQuery, "=") {
+		operation = r.URL.RawQuery
+		if len(operation) > maxAccessTokenUsageOperationLength {
+			operation = operation[:maxAccessTokenUsageOperationLength]
+		}
+	}
+
+	u.record(token, endpoint, operation, m.Code, m.Written, m.Duration)
+}
+
+// record records a request made with the given access token.
+func (u *AccessTokenUsageRecorder) record(token, endpoint, operation string, status int, responseBytes int64, duration time.Duration) {
+	tokenHash, err := database.AccessTokenHash(token)
+	if err != nil {
+		// The access token was validated before the request was served.
+		return
+	}
+
+	key := accessTokenUsageKey{
+		tokenHash: string(tokenHash),
+		bucket:    u.now().UTC().Truncate(time.Hour),
+		endpoint:  endpoint,
+		operation: operation,
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	record, ok := u.pending[key]
+	if !ok {
+		record = &database.AccessTokenUsageRecord{
+			TokenHash:      tokenHash,
+			Bucket:         key.bucket,
+			Endpoint:       endpoint,
+			Operation:      operation,
+			LatencyBuckets: make([]int32, len(database.AccessTokenUsageLatencyBounds)+1),
+		}
+		u.pending[key] = record
+	}
+	record.RequestCount++
+	if status >= http.StatusBadRequest {
+		record.ErrorCount++
+	}
+	record.ResponseBytes += responseBytes
+	record.LatencyBuckets[database.AccessTokenUsageLatencyBucket(duration)]++
+}
+
+// Handle writes the usage recorded since the last call to the database.
+func (u *AccessTokenUsageRecorder) Handle(ctx context.Context) error {
+	u.mu.Lock()
+	pending := u.pending
+	u.pending = map[accessTokenUsageKey]*database.AccessTokenUsageRecord{}
+	u.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	records := make([]database.AccessTokenUsageRecord, 0, len(pending))
+	for _, record := range pending {
+		records = append(records, *record)
+	}
+	if err := u.db.AccessTokenUsage().Record(ctx, records...); err != nil {
+		return errors.Wrapf(err, "recording %d access token usage records", len(records))
+	}
+	return nil
+}
+
+// OnShutdown writes the usage that has not been written yet, so that it is not
+// lost when the frontend shuts down.
+func (u *AccessTokenUsageRecorder) OnShutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := u.Handle(ctx); err != nil {
+		u.logger.Warn("failed to record access token usage", log.Error(err))
+	}
+}
@@ -0,0 +1,84 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+func TestAccessTokenUsageRecorder(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 30, 0, 0, time.UTC)
+	u := NewAccessTokenUsageRecorder(database.NewMockDB(), logtest.Scoped(t))
+	u.now = func() time.Time { return now }
+
+	token := "sgp_0123456789abcdef"
+	u.record(token, "graphql", "Search", http.StatusOK, 100, 5*time.Millisecond)
+	u.record(token, "graphql", "Search", http.StatusInternalServerError, 50, 2*time.Second)
+	u.record(token, "rest.search", "", http.StatusOK, 10, 5*time.Millisecond)
+	// Invalid access tokens are ignored.
+	u.record("not hex", "graphql", "Search", http.StatusOK, 100, time.Millisecond)
+
+	require.Len(t, u.pending, 2)
+
+	tokenHash, err := database.AccessTokenHash(token)
+	require.NoError(t, err)
+	record := u.pending[accessTokenUsageKey{
+		tokenHash: string(tokenHash),
+		bucket:    time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC),
+		endpoint:  "graphql",
+		operation: "Search",
+	}]
+	require.NotNil(t, record)
+	assert.Equal(t, 2, record.RequestCount)
+	assert.Equal(t, 1, record.ErrorCount)
+	assert.Equal(t, int64(150), record.ResponseBytes)
+	assert.Equal(t, int32(1), record.LatencyBuckets[database.AccessTokenUsageLatencyBucket(5*time.Millisecond)])
+	assert.Equal(t, int32(1), record.LatencyBuckets[database.AccessTokenUsageLatencyBucket(2*time.Second)])
+}
+
+type recordingAccessTokenUsageStore struct {
+	database.AccessTokenUsageStore
+	records chan []database.AccessTokenUsageRecord
+}
+
+func (s *recordingAccessTokenUsageStore) Record(_ context.Context, records ...database.AccessTokenUsageRecord) error {
+	s.records <- records
+	return nil
+}
+
+func TestAccessTokenUsageRecorder_Idle(t *testing.T) {
+	store := &recordingAccessTokenUsageStore{records: make(chan []database.AccessTokenUsageRecord, 1)}
+	db := database.NewMockDB()
+	db.AccessTokenUsageFunc.SetDefaultReturn(store)
+
+	u := NewAccessTokenUsageRecorder(db, logtest.Scoped(t))
+	token := "sgp_0123456789abcdef"
+
+	// The usage is written without any further requests.
+	u.record(token, "graphql", "Search", http.StatusOK, 100, time.Millisecond)
+	require.NoError(t, u.Handle(context.Background()))
+	records := <-store.records
+	require.Len(t, records, 1)
+	assert.Equal(t, 1, records[0].RequestCount)
+	require.Empty(t, u.pending)
+
+	// Nothing is written when no usage was recorded.
+	require.NoError(t, u.Handle(context.Background()))
+	require.Empty(t, store.records)
+
+	// The usage that has not been written yet is written on shutdown.
+	routine := u.Routine()
+	go routine.Start()
+	u.record(token, "rest.search", "", http.StatusOK, 10, time.Millisecond)
+	routine.Stop()
+	records = <-store.records
+	require.Len(t, records, 1)
+	assert.Equal(t, "rest.search", records[0].Endpoint)
+}
@@ -48,6 +48,7 @@ func newTest(t *testing.T) *httptestutil.Client {
 			NewCodeIntelUploadHandler:       enterpriseServices.NewCodeIntelUploadHandler,
 			NewComputeStreamHandler:         enterpriseServices.NewComputeStreamHandler,
 			PermissionsGitHubWebhook:        enterpriseServices.PermissionsGitHubWebhook,
+			PermissionsGitLabWebhook:        enterpriseServices.PermissionsGitLabWebhook,
 			NewChatCompletionsStreamHandler: enterpriseServices.NewChatCompletionsStreamHandler,
 			NewCodeCompletionsHandler:       enterpriseServices.NewCodeCompletionsHandler,
 		},
@@ -0,0 +1,69 @@
+package httpapi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+// archivedRepoIDsMaxAge is the maximum age of the cached set of archived
+// repositories. The set is listed again as soon as the archival state of a
+// repository changes, so this only bounds how long a change whose timestamp
+// is older than the last seen one can be missed.
+const archivedRepoIDsMaxAge = 10 * time.Minute
+
+// archivedRepoIDsCache caches the set of the repositories archived by the
+// archival policy. Zoekt requests the configuration of the repositories it
+// indexes every few seconds, while the set only changes when the archiver runs
+// or a repository is restored. Each request only looks up when the archival
+// state of a repository last changed, which is answered by an index, and the
+// set is listed again when that changed.
+type archivedRepoIDsCache struct {
+	store database.RepoArchivalStore
+	now   func() time.Time
+
+	mu        sync.Mutex
+	ids       map[api.RepoID]struct{}
+	updatedAt time.Time
+	listedAt  time.Time
+}
+
+func newArchivedRepoIDsCache(store database.RepoArchivalStore) *archivedRepoIDsCache {
+	return &archivedRepoIDsCache{store: store, now: time.Now}
+}
+
+// Get returns the set of archived repositories. The returned map is shared and
+// must not be modified.
+func (c *archivedRepoIDsCache) Get(ctx context.Context) (map[api.RepoID]struct{}, error) {
+	updatedAt, err := c.store.LastUpdatedAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if updatedAt == nil {
+		// No repository was ever archived.
+		return nil, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if c.ids != nil && c.updatedAt.Equal(*updatedAt) && now.Sub(c.listedAt) < archivedRepoIDsMaxAge {
+		return c.ids, nil
+	}
+
+	ids, err := c.store.ListArchivedIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[api.RepoID]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	c.ids, c.updatedAt, c.listedAt = set, *updatedAt, now
+	return set, nil
+}
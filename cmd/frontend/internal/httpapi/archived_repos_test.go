@@ -0,0 +1,81 @@
+package httpapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+func TestArchivedRepoIDsCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	var updatedAt *time.Time
+	ids := []api.RepoID{1, 2}
+
+	store := database.NewMockRepoArchivalStore()
+	store.LastUpdatedAtFunc.SetDefaultHook(func(context.Context) (*time.Time, error) {
+		return updatedAt, nil
+	})
+	store.ListArchivedIDsFunc.SetDefaultHook(func(context.Context) ([]api.RepoID, error) {
+		return ids, nil
+	})
+
+	cache := newArchivedRepoIDsCache(store)
+	cache.now = func() time.Time { return now }
+
+	get := func(want ...api.RepoID) {
+		t.Helper()
+
+		set, err := cache.Get(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range want {
+			if _, ok := set[id]; !ok {
+				t.Fatalf("expected repo %d to be archived, got %v", id, set)
+			}
+		}
+		if len(set) != len(want) {
+			t.Fatalf("expected %d archived repos, got %v", len(want), set)
+		}
+	}
+	listCalls := func(want int) {
+		t.Helper()
+
+		if diff := cmp.Diff(want, len(store.ListArchivedIDsFunc.History())); diff != "" {
+			t.Fatalf("unexpected number of list calls (-want +got):\n%s", diff)
+		}
+	}
+
+	// Nothing is listed before a repository was archived.
+	get()
+	listCalls(0)
+
+	t1 := now.Add(-time.Hour)
+	updatedAt = &t1
+	get(1, 2)
+	listCalls(1)
+
+	// The set is not listed again while the archival state is unchanged.
+	get(1, 2)
+	get(1, 2)
+	listCalls(1)
+
+	t2 := now
+	updatedAt = &t2
+	ids = []api.RepoID{2}
+	get(2)
+	listCalls(2)
+
+	// The set is listed again once it is too old.
+	now = now.Add(archivedRepoIDsMaxAge)
+	ids = []api.RepoID{2, 3}
+	get(2, 3)
+	listCalls(3)
+}
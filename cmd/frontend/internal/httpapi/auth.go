@@ -22,8 +22,9 @@ import (
 )
 
 // AccessTokenAuthMiddleware authenticates the user based on the
-// token query parameter or the "Authorization" header.
-func AccessTokenAuthMiddleware(db database.DB, logger log.Logger, next http.Handler) http.Handler {
+// token query parameter or the "Authorization" header. The usage of access
+// tokens is recorded with the given recorder.
+func AccessTokenAuthMiddleware(db database.DB, logger log.Logger, usage *AccessTokenUsageRecorder, next http.Handler) http.Handler {
 	logger = logger.Scoped("accessTokenAuth", "Access token authentication middleware")
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// SCIM uses an auth token which is checked separately in the SCIM package.
@@ -270,6 +271,9 @@ func AccessTokenAuthMiddleware(db database.DB, logger log.Logger, next http.Hand
 					},
 				),
 			)
+
+			usage.serveHTTP(next, w, r, token)
+			return
 		}
 
 		next.ServeHTTP(w, r)
@@ -24,6 +24,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		return AccessTokenAuthMiddleware(
 			db,
 			logtest.NoOp(t),
+			NewAccessTokenUsageRecorder(db, logtest.NoOp(t)),
 			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				actor := sgactor.FromContext(r.Context())
 				if actor.IsAuthenticated() {
@@ -0,0 +1,91 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/felixge/httpsnoop"
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/debugbundle"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// debugBundleTTLSeconds is the time for which a captured debug bundle can be downloaded.
+const debugBundleTTLSeconds = 24 * 60 * 60
+
+// debugBundles holds captured debug bundles by ID.
+var debugBundles = rcache.NewWithTTL("debug-bundle", debugBundleTTLSeconds)
+
+// DebugBundleMiddleware captures a debug bundle of the operations, SQL queries, and logs
+// of requests that set the debugbundle.RequestHeader header. The ID of the bundle is
+// returned in the debugbundle.IDHeader response header, and the bundle can be downloaded
+// from the debug-bundle API route once the request completes.
+//
+// 🚨 SECURITY: Bundles contain SQL statements and log messages, so they are only captured
+// for site admins. This must run after the auth middlewares.
+func DebugBundleMiddleware(db database.DB, logger log.Logger, next http.Handler) http.Handler {
+	logger = logger.Scoped("DebugBundleMiddleware", "captures debug bundles of requests from site admins")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if wants, _ := strconv.ParseBool(r.Header.Get(debugbundle.RequestHeader)); !wants {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err := auth.CheckCurrentUserIsSiteAdmin(ctx, db); err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := debugbundle.NewRecorder(r.Method, r.URL.RequestURI())
+		recorder.SetUser(actor.FromContext(ctx).UID)
+		recorder.SetTraceID(trace.Context(ctx).TraceID)
+		w.Header().Set(debugbundle.IDHeader, recorder.ID())
+
+		m := httpsnoop.CaptureMetrics(next, w, r.WithContext(debugbundle.WithRecorder(ctx, recorder)))
+
+		if err := saveDebugBundle(recorder.Finish(m.Code)); err != nil {
+			logger.Error("failed to save debug bundle", log.String("id", recorder.ID()), log.Error(err))
+		}
+	})
+}
+
+func saveDebugBundle(bundle *debugbundle.Bundle) error {
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return err
+	}
+
+	debugBundles.Set(bundle.ID, data)
+	return nil
+}
+
+// serveDebugBundle serves a debug bundle captured by DebugBundleMiddleware as JSON.
+func serveDebugBundle(db database.DB) func(http.ResponseWriter, *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		// 🚨 SECURITY: Only site admins may download debug bundles.
+		if err := auth.CheckCurrentUserIsSiteAdmin(r.Context(), db); err != nil {
+			return &errcode.HTTPErr{Status: http.StatusForbidden, Err: err}
+		}
+
+		id := mux.Vars(r)["id"]
+		data, ok := debugBundles.Get(id)
+		if !ok {
+			return &errcode.HTTPErr{Status: http.StatusNotFound, Err: errors.Newf("debug bundle %q not found or expired", id)}
+		}
+
+		w.Header().Set("Content-Disposition", "attachment; filename=\"debug-bundle-"+id+".json\"")
+		_, err := w.Write(data)
+		return err
+	}
+}
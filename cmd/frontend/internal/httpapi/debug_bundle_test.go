@@ -0,0 +1,100 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/debugbundle"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestDebugBundleMiddleware(t *testing.T) {
+	rcache.SetupForTest(t)
+	logger := logtest.Scoped(t)
+
+	users := database.NewMockUserStore()
+	db := database.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+
+	handler := DebugBundleMiddleware(db, logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trace.Logger(r.Context(), logger).Warn("handling request")
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	serve := func(header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/.api/graphql?Search", nil)
+		req = req.WithContext(actor.WithActor(context.Background(), actor.FromUser(1)))
+		if header != "" {
+			req.Header.Set(debugbundle.RequestHeader, header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("not requested", func(t *testing.T) {
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+		if id := serve("").Header().Get(debugbundle.IDHeader); id != "" {
+			t.Errorf("unexpected debug bundle %q", id)
+		}
+	})
+
+	t.Run("not a site admin", func(t *testing.T) {
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1}, nil)
+		if id := serve("true").Header().Get(debugbundle.IDHeader); id != "" {
+			t.Errorf("unexpected debug bundle %q", id)
+		}
+	})
+
+	t.Run("site admin", func(t *testing.T) {
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+		id := serve("true").Header().Get(debugbundle.IDHeader)
+		if id == "" {
+			t.Fatal("expected a debug bundle")
+		}
+
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/.api/debug-bundles/"+id, nil), map[string]string{"id": id})
+		rec := httptest.NewRecorder()
+		if err := serveDebugBundle(db)(rec, req); err != nil {
+			t.Fatalf("unexpected error serving debug bundle: %s", err)
+		}
+
+		var bundle debugbundle.Bundle
+		if err := json.Unmarshal(rec.Body.Bytes(), &bundle); err != nil {
+			t.Fatalf("unexpected error decoding debug bundle: %s", err)
+		}
+		if bundle.ID != id || bundle.UserID != 1 || bundle.Status != http.StatusTeapot || bundle.URL != "/.api/graphql?Search" {
+			t.Errorf("unexpected bundle. id=%q user=%d status=%d url=%q", bundle.ID, bundle.UserID, bundle.Status, bundle.URL)
+		}
+		if len(bundle.Logs) != 1 || bundle.Logs[0].Message != "handling request" {
+			t.Errorf("unexpected logs: %v", bundle.Logs)
+		}
+	})
+
+	t.Run("download requires site admin", func(t *testing.T) {
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1}, nil)
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/.api/debug-bundles/x", nil), map[string]string{"id": "x"})
+		if err := serveDebugBundle(db)(httptest.NewRecorder(), req); errcode.HTTP(err) != http.StatusForbidden {
+			t.Errorf("unexpected error. want status %d, have %v", http.StatusForbidden, err)
+		}
+	})
+
+	t.Run("unknown bundle", func(t *testing.T) {
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/.api/debug-bundles/x", nil), map[string]string{"id": "x"})
+		if err := serveDebugBundle(db)(httptest.NewRecorder(), req); errcode.HTTP(err) != http.StatusNotFound {
+			t.Errorf("unexpected error. want status %d, have %v", http.StatusNotFound, err)
+		}
+	})
+}
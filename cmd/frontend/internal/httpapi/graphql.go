@@ -21,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/audit"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/cookie"
+	"github.com/sourcegraph/sourcegraph/internal/requestclient"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -61,11 +62,17 @@ func serveGraphQL(logger sglog.Logger, schema *graphql.Schema, rlw graphqlbacken
 			return err
 		}
 
+		persistedQueryID, err := resolvePersistedQuery(&params, persistedQueries())
+		if err != nil {
+			return writeRequestError(w, err)
+		}
+
 		traceData := traceData{
-			queryParams:   params,
-			isInternal:    isInternal,
-			requestName:   requestName,
-			requestSource: string(requestSource),
+			queryParams:      params,
+			persistedQueryID: persistedQueryID,
+			isInternal:       isInternal,
+			requestName:      requestName,
+			requestSource:    string(requestSource),
 		}
 
 		defer func() {
@@ -77,6 +84,12 @@ func serveGraphQL(logger sglog.Logger, schema *graphql.Schema, rlw graphqlbacken
 		traceData.uid = uid
 		traceData.anonymous = anonymous
 
+		siteConfig := conf.SiteConfig()
+		if err := checkPersistedQueryAllowlist(siteConfig.GraphqlPersistedQueries, anonymous, persistedQueryID); err != nil {
+			traceData.rejected = true
+			return writeRequestError(w, err)
+		}
+
 		validationErrs := schema.ValidateWithVariables(params.Query, params.Variables)
 
 		var cost *graphqlbackend.QueryCost
@@ -91,6 +104,22 @@ func serveGraphQL(logger sglog.Logger, schema *graphql.Schema, rlw graphqlbacken
 			traceData.costError = costErr
 			traceData.cost = cost
 
+			// Persisted queries are vetted by site admins, so only ad-hoc
+			// queries are subject to complexity budgets.
+			if persistedQueryID == "" {
+				// 🚨 SECURITY: The budget of an unauthenticated client is keyed on the
+				// address of its connection, since X-Forwarded-For can be spoofed.
+				var ip string
+				if client := requestclient.FromContext(r.Context()); client != nil {
+					ip = client.IP
+				}
+				budget := complexityBudget(siteConfig.GraphqlComplexityBudgets, uid, anonymous, ip)
+				if err := checkComplexityBudget(cost, budget); err != nil {
+					traceData.rejected = true
+					return writeRequestError(w, err)
+				}
+			}
+
 			if rl, enabled := rlw.Get(); enabled && cost != nil {
 				limited, result, err := rl.RateLimit(uid, cost.FieldCount, graphqlbackend.LimiterArgs{
 					IsIP:          isIP,
@@ -129,20 +158,36 @@ func serveGraphQL(logger sglog.Logger, schema *graphql.Schema, rlw graphqlbacken
 }
 
 type graphQLQueryParams struct {
-	Query         string         `json:"query"`
-	OperationName string         `json:"operationName"`
-	Variables     map[string]any `json:"variables"`
+	Query         string            `json:"query"`
+	OperationName string            `json:"operationName"`
+	Variables     map[string]any    `json:"variables"`
+	Extensions    graphQLExtensions `json:"extensions"`
+}
+
+// writeRequestError writes a GraphQL response for errors rejecting a request
+// before it is executed, and returns any other error unchanged.
+func writeRequestError(w http.ResponseWriter, err error) error {
+	var requestErr *graphQLRequestError
+	if errors.As(err, &requestErr) {
+		return requestErr.write(w)
+	}
+	return err
 }
 
 type traceData struct {
-	queryParams   graphQLQueryParams
-	execStart     time.Time
-	uid           string
-	anonymous     bool
-	isInternal    bool
-	requestName   string
-	requestSource string
-	queryErrors   []*gqlerrors.QueryError
+	queryParams      graphQLQueryParams
+	persistedQueryID string
+	execStart        time.Time
+	uid              string
+	anonymous        bool
+	isInternal       bool
+	requestName      string
+	requestSource    string
+	queryErrors      []*gqlerrors.QueryError
+
+	// rejected is true if the request was rejected by the persisted query
+	// allowlist or by a complexity budget.
+	rejected bool
 
 	cost      *graphqlbackend.QueryCost
 	costError error
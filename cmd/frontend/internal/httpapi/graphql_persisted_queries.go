@@ -0,0 +1,187 @@
+package httpapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// graphQLExtensions are the extensions of a GraphQL request. Persisted queries
+// follow the protocol of Apollo's automatic persisted queries, except that
+// queries can only be persisted by site admins in the site configuration.
+type graphQLExtensions struct {
+	PersistedQuery *persistedQueryExtension `json:"persistedQuery,omitempty"`
+}
+
+type persistedQueryExtension struct {
+	Version    int    `json:"version"`
+	Sha256Hash string `json:"sha256Hash"`
+}
+
+// Error codes of persisted query errors, returned in the extensions of the
+// error so that clients can tell them apart.
+const (
+	persistedQueryNotFoundCode     = "PERSISTED_QUERY_NOT_FOUND"
+	persistedQueryHashMismatchCode = "PERSISTED_QUERY_HASH_MISMATCH"
+	persistedQueryRequiredCode     = "PERSISTED_QUERY_REQUIRED"
+	complexityBudgetExceededCode   = "COMPLEXITY_BUDGET_EXCEEDED"
+)
+
+// graphQLRequestError is an error rejecting a GraphQL request before it is
+// executed.
+type graphQLRequestError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *graphQLRequestError) Error() string {
+	return e.message
+}
+
+// write writes the error as a GraphQL response.
+func (e *graphQLRequestError) write(w http.ResponseWriter) error {
+	responseJSON, err := json.Marshal(graphql.Response{
+		Errors: []*gqlerrors.QueryError{{
+			Message:    e.message,
+			Extensions: map[string]any{"code": e.code},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.status)
+	w.Write(responseJSON)
+	return nil
+}
+
+// persistedQueryHash returns the hex-encoded SHA-256 hash identifying the
+// given query.
+func persistedQueryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// persistedQueries returns the persisted queries of the site configuration,
+// by hash.
+var persistedQueries = conf.Cached(func() map[string]*schema.GraphQLPersistedQuery {
+	return indexPersistedQueries(conf.Get().GraphqlPersistedQueries)
+})
+
+func indexPersistedQueries(config *schema.GraphqlPersistedQueries) map[string]*schema.GraphQLPersistedQuery {
+	index := map[string]*schema.GraphQLPersistedQuery{}
+	if config == nil {
+		return index
+	}
+	for _, query := range config.Queries {
+		index[persistedQueryHash(query.Query)] = query
+	}
+	return index
+}
+
+// resolvePersistedQuery resolves the query of a request referring to a
+// persisted query by hash, and returns the ID of the persisted query, or an
+// empty string if the request is an ad-hoc query. Requests that send the full
+// text of a persisted query are treated as requests of the persisted query.
+func resolvePersistedQuery(params *graphQLQueryParams, index map[string]*schema.GraphQLPersistedQuery) (string, error) {
+	if params.Extensions.PersistedQuery == nil {
+		if persisted, ok := index[persistedQueryHash(params.Query)]; ok {
+			return persisted.Id, nil
+		}
+		return "", nil
+	}
+
+	hash := strings.ToLower(params.Extensions.PersistedQuery.Sha256Hash)
+	persisted, ok := index[hash]
+	if !ok {
+		return "", &graphQLRequestError{
+			// Clients of automatic persisted queries expect this error as a
+			// successful response, and retry with the full query.
+			status:  http.StatusOK,
+			code:    persistedQueryNotFoundCode,
+			message: "PersistedQueryNotFound",
+		}
+	}
+	if params.Query != "" && persistedQueryHash(params.Query) != hash {
+		return "", &graphQLRequestError{
+			status:  http.StatusBadRequest,
+			code:    persistedQueryHashMismatchCode,
+			message: "provided sha256Hash does not match query",
+		}
+	}
+
+	params.Query = persisted.Query
+	return persisted.Id, nil
+}
+
+// checkPersistedQueryAllowlist returns an error if the site configuration only
+// permits unauthenticated clients to execute persisted queries, and the request
+// of such a client is not for a persisted query.
+func checkPersistedQueryAllowlist(config *schema.GraphqlPersistedQueries, anonymous bool, persistedQueryID string) error {
+	if !anonymous || persistedQueryID != "" || config == nil || !config.AnonymousAllowlistOnly {
+		return nil
+	}
+
+	return &graphQLRequestError{
+		status:  http.StatusForbidden,
+		code:    persistedQueryRequiredCode,
+		message: "unauthenticated clients may only execute persisted queries",
+	}
+}
+
+// complexityBudget returns the complexity budget of the client identified by
+// the given user ID or IP address, or nil if the client has no budget.
+func complexityBudget(config *schema.GraphqlComplexityBudgets, uid string, anonymous bool, ip string) *schema.GraphQLComplexityBudget {
+	if config == nil {
+		return nil
+	}
+
+	for _, client := range config.Clients {
+		if (!anonymous && client.UserID != 0 && strconv.Itoa(client.UserID) == uid) ||
+			(anonymous && client.Ip != "" && client.Ip == ip) {
+			return &schema.GraphQLComplexityBudget{MaxDepth: client.MaxDepth, MaxFieldCount: client.MaxFieldCount}
+		}
+	}
+
+	if anonymous && config.Anonymous != nil {
+		return config.Anonymous
+	}
+	return config.Default
+}
+
+// checkComplexityBudget returns an error if the estimated cost of a query
+// exceeds the given budget.
+func checkComplexityBudget(cost *graphqlbackend.QueryCost, budget *schema.GraphQLComplexityBudget) error {
+	if cost == nil || budget == nil {
+		return nil
+	}
+
+	var message string
+	switch {
+	case budget.MaxDepth > 0 && cost.MaxDepth > budget.MaxDepth:
+		message = fmt.Sprintf("query depth %d exceeds the maximum depth of %d", cost.MaxDepth, budget.MaxDepth)
+	case budget.MaxFieldCount > 0 && cost.FieldCount > budget.MaxFieldCount:
+		message = fmt.Sprintf("estimated query field count %d exceeds the maximum of %d, request fewer results per page", cost.FieldCount, budget.MaxFieldCount)
+	default:
+		return nil
+	}
+
+	return &graphQLRequestError{
+		status:  http.StatusBadRequest,
+		code:    complexityBudgetExceededCode,
+		message: message,
+	}
+}
@@ -0,0 +1,114 @@
+package httpapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestResolvePersistedQuery(t *testing.T) {
+	const query = `query RepositoryCount { repositories { totalCount } }`
+	hash := persistedQueryHash(query)
+	index := indexPersistedQueries(&schema.GraphqlPersistedQueries{
+		Queries: []*schema.GraphQLPersistedQuery{{Id: "repositoryCount", Query: query}},
+	})
+
+	withHash := func(query, hash string) *graphQLQueryParams {
+		return &graphQLQueryParams{
+			Query:      query,
+			Extensions: graphQLExtensions{PersistedQuery: &persistedQueryExtension{Version: 1, Sha256Hash: hash}},
+		}
+	}
+
+	t.Run("by hash", func(t *testing.T) {
+		params := withHash("", hash)
+		id, err := resolvePersistedQuery(params, index)
+		require.NoError(t, err)
+		assert.Equal(t, "repositoryCount", id)
+		assert.Equal(t, query, params.Query)
+	})
+
+	t.Run("by hash and query", func(t *testing.T) {
+		id, err := resolvePersistedQuery(withHash(query, hash), index)
+		require.NoError(t, err)
+		assert.Equal(t, "repositoryCount", id)
+	})
+
+	t.Run("by query text", func(t *testing.T) {
+		id, err := resolvePersistedQuery(&graphQLQueryParams{Query: query}, index)
+		require.NoError(t, err)
+		assert.Equal(t, "repositoryCount", id)
+	})
+
+	t.Run("ad-hoc query", func(t *testing.T) {
+		id, err := resolvePersistedQuery(&graphQLQueryParams{Query: `{ currentUser { id } }`}, index)
+		require.NoError(t, err)
+		assert.Empty(t, id)
+	})
+
+	t.Run("unknown hash", func(t *testing.T) {
+		_, err := resolvePersistedQuery(withHash("", persistedQueryHash("{ site { id } }")), index)
+		assertRequestError(t, err, http.StatusOK, persistedQueryNotFoundCode)
+	})
+
+	t.Run("hash mismatch", func(t *testing.T) {
+		_, err := resolvePersistedQuery(withHash(`{ currentUser { id } }`, hash), index)
+		assertRequestError(t, err, http.StatusBadRequest, persistedQueryHashMismatchCode)
+	})
+}
+
+func TestCheckPersistedQueryAllowlist(t *testing.T) {
+	config := &schema.GraphqlPersistedQueries{AnonymousAllowlistOnly: true}
+
+	assert.NoError(t, checkPersistedQueryAllowlist(nil, true, ""))
+	assert.NoError(t, checkPersistedQueryAllowlist(&schema.GraphqlPersistedQueries{}, true, ""))
+	assert.NoError(t, checkPersistedQueryAllowlist(config, false, ""))
+	assert.NoError(t, checkPersistedQueryAllowlist(config, true, "repositoryCount"))
+	assertRequestError(t, checkPersistedQueryAllowlist(config, true, ""), http.StatusForbidden, persistedQueryRequiredCode)
+}
+
+func TestComplexityBudget(t *testing.T) {
+	defaultBudget := &schema.GraphQLComplexityBudget{MaxDepth: 20, MaxFieldCount: 1000}
+	anonymousBudget := &schema.GraphQLComplexityBudget{MaxDepth: 10, MaxFieldCount: 100}
+	config := &schema.GraphqlComplexityBudgets{
+		Default:   defaultBudget,
+		Anonymous: anonymousBudget,
+		Clients: []*schema.GraphQLClientComplexityBudget{
+			{UserID: 42, MaxFieldCount: 5000},
+			{Ip: "10.0.0.1", MaxDepth: 5},
+		},
+	}
+
+	assert.Nil(t, complexityBudget(nil, "1", false, ""))
+	assert.Equal(t, defaultBudget, complexityBudget(config, "1", false, "10.0.0.1"))
+	assert.Equal(t, anonymousBudget, complexityBudget(config, "42", true, "10.0.0.2"))
+	assert.Equal(t, &schema.GraphQLComplexityBudget{MaxFieldCount: 5000}, complexityBudget(config, "42", false, ""))
+	assert.Equal(t, &schema.GraphQLComplexityBudget{MaxDepth: 5}, complexityBudget(config, "abc", true, "10.0.0.1"))
+	assert.Equal(t, defaultBudget, complexityBudget(&schema.GraphqlComplexityBudgets{Default: defaultBudget}, "", true, ""))
+}
+
+func TestCheckComplexityBudget(t *testing.T) {
+	budget := &schema.GraphQLComplexityBudget{MaxDepth: 5, MaxFieldCount: 100}
+
+	assert.NoError(t, checkComplexityBudget(&graphqlbackend.QueryCost{FieldCount: 100, MaxDepth: 5}, budget))
+	assert.NoError(t, checkComplexityBudget(&graphqlbackend.QueryCost{FieldCount: 1e6, MaxDepth: 50}, &schema.GraphQLComplexityBudget{}))
+	assert.NoError(t, checkComplexityBudget(nil, budget))
+	assert.NoError(t, checkComplexityBudget(&graphqlbackend.QueryCost{FieldCount: 1e6}, nil))
+	assertRequestError(t, checkComplexityBudget(&graphqlbackend.QueryCost{FieldCount: 101, MaxDepth: 1}, budget), http.StatusBadRequest, complexityBudgetExceededCode)
+	assertRequestError(t, checkComplexityBudget(&graphqlbackend.QueryCost{FieldCount: 1, MaxDepth: 6}, budget), http.StatusBadRequest, complexityBudgetExceededCode)
+}
+
+func assertRequestError(t *testing.T, err error, status int, code string) {
+	t.Helper()
+
+	var requestErr *graphQLRequestError
+	require.True(t, errors.As(err, &requestErr), "unexpected error: %v", err)
+	assert.Equal(t, status, requestErr.status)
+	assert.Equal(t, code, requestErr.code)
+}
@@ -0,0 +1,310 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	sglog "github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// graphQLTransportWSProtocol is the WebSocket subprotocol of GraphQL
+// subscriptions. See
+// https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md.
+const graphQLTransportWSProtocol = "graphql-transport-ws"
+
+// Message types of the graphql-transport-ws protocol.
+const (
+	connectionInitMessage = "connection_init"
+	connectionAckMessage  = "connection_ack"
+	pingMessage           = "ping"
+	pongMessage           = "pong"
+	subscribeMessage      = "subscribe"
+	nextMessage           = "next"
+	errorMessage          = "error"
+	completeMessage       = "complete"
+)
+
+// Close codes of the graphql-transport-ws protocol.
+const (
+	closeBadRequest          = 4400
+	closeUnauthorized        = 4401
+	closeInitTimeout         = 4408
+	closeSubscriberExists    = 4409
+	closeTooManyInitRequests = 4429
+)
+
+const (
+	subscriptionInitTimeout  = 10 * time.Second
+	subscriptionWriteTimeout = 10 * time.Second
+
+	// maxSubscriptionsPerConnection bounds the number of concurrent
+	// subscriptions of a single connection.
+	maxSubscriptionsPerConnection = 100
+)
+
+type subscriptionMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+var subscriptionUpgrader = websocket.Upgrader{
+	Subprotocols: []string{graphQLTransportWSProtocol},
+	// 🚨 SECURITY: The request is authenticated by the API middlewares before it
+	// is upgraded, and session cookies are only accepted from trusted origins
+	// (see session.CookieMiddlewareWithCSRFSafety), so any origin is allowed here.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// serveGraphQLSubscriptions serves GraphQL subscriptions over WebSocket, using
+// the graphql-transport-ws protocol.
+func serveGraphQLSubscriptions(logger sglog.Logger, schema *graphql.Schema) http.HandlerFunc {
+	logger = logger.Scoped("graphQLSubscriptions", "GraphQL subscriptions over WebSocket")
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := subscriptionUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// The upgrader has already replied with an HTTP error.
+			return
+		}
+		if conn.Subprotocol() != graphQLTransportWSProtocol {
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseProtocolError, "unsupported subprotocol"), time.Now().Add(subscriptionWriteTimeout))
+			conn.Close()
+			return
+		}
+
+		c := &subscriptionConn{
+			logger:        logger,
+			schema:        schema,
+			conn:          conn,
+			anonymous:     !actor.FromContext(r.Context()).IsAuthenticated(),
+			subscriptions: map[string]*subscription{},
+		}
+		c.serve(r.Context())
+	}
+}
+
+// subscriptionConn is a WebSocket connection serving GraphQL subscriptions.
+type subscriptionConn struct {
+	logger    sglog.Logger
+	schema    *graphql.Schema
+	conn      *websocket.Conn
+	anonymous bool
+
+	// writeMu guards writes to conn, which do not support concurrent writers.
+	writeMu sync.Mutex
+
+	// initialized is only accessed by the read loop.
+	initialized bool
+
+	mu            sync.Mutex
+	subscriptions map[string]*subscription
+}
+
+type subscription struct {
+	cancel context.CancelFunc
+}
+
+// subscriptionCloseError is returned by handle to close the connection with
+// the given code.
+type subscriptionCloseError struct {
+	code   int
+	reason string
+}
+
+func (e *subscriptionCloseError) Error() string {
+	return fmt.Sprintf("%d: %s", e.code, e.reason)
+}
+
+func (c *subscriptionConn) serve(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	initTimer := time.AfterFunc(subscriptionInitTimeout, func() {
+		c.close(closeInitTimeout, "Connection initialisation timeout")
+	})
+	defer initTimer.Stop()
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			// The connection was closed by the client, or by us.
+			c.conn.Close()
+			return
+		}
+
+		var msg subscriptionMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.close(closeBadRequest, "Invalid message received")
+			return
+		}
+
+		if err := c.handle(ctx, msg, initTimer); err != nil {
+			if closeErr, ok := err.(*subscriptionCloseError); ok {
+				c.close(closeErr.code, closeErr.reason)
+			} else {
+				c.logger.Warn("failed to handle message", sglog.String("type", msg.Type), sglog.Error(err))
+				c.close(websocket.CloseInternalServerErr, "Internal server error")
+			}
+			return
+		}
+	}
+}
+
+func (c *subscriptionConn) handle(ctx context.Context, msg subscriptionMessage, initTimer *time.Timer) error {
+	switch msg.Type {
+	case connectionInitMessage:
+		if c.initialized {
+			return &subscriptionCloseError{closeTooManyInitRequests, "Too many initialisation requests"}
+		}
+		c.initialized = true
+		initTimer.Stop()
+		return c.write(subscriptionMessage{Type: connectionAckMessage})
+
+	case pingMessage:
+		return c.write(subscriptionMessage{Type: pongMessage, Payload: msg.Payload})
+
+	case pongMessage:
+		return nil
+
+	case subscribeMessage:
+		if !c.initialized {
+			return &subscriptionCloseError{closeUnauthorized, "Unauthorized"}
+		}
+		if msg.ID == "" {
+			return &subscriptionCloseError{closeBadRequest, "Invalid message received"}
+		}
+
+		var params graphQLQueryParams
+		if err := json.Unmarshal(msg.Payload, &params); err != nil {
+			return &subscriptionCloseError{closeBadRequest, "Invalid message received"}
+		}
+		return c.subscribe(ctx, msg.ID, params)
+
+	case completeMessage:
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if s, ok := c.subscriptions[msg.ID]; ok {
+			s.cancel()
+			delete(c.subscriptions, msg.ID)
+		}
+		return nil
+
+	default:
+		return &subscriptionCloseError{closeBadRequest, "Invalid message received"}
+	}
+}
+
+func (c *subscriptionConn) subscribe(ctx context.Context, id string, params graphQLQueryParams) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.subscriptions[id]; ok {
+		return &subscriptionCloseError{closeSubscriberExists, fmt.Sprintf("Subscriber for %s already exists", id)}
+	}
+	if len(c.subscriptions) >= maxSubscriptionsPerConnection {
+		return c.writeErrors(id, fmt.Sprintf("too many subscriptions, at most %d are allowed per connection", maxSubscriptionsPerConnection))
+	}
+
+	persistedQueryID, err := resolvePersistedQuery(&params, persistedQueries())
+	if err == nil {
+		err = checkPersistedQueryAllowlist(conf.SiteConfig().GraphqlPersistedQueries, c.anonymous, persistedQueryID)
+	}
+	if err != nil {
+		return c.writeErrors(id, err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	responses, err := c.schema.Subscribe(ctx, params.Query, params.OperationName, params.Variables)
+	if err != nil {
+		cancel()
+		return c.writeErrors(id, err.Error())
+	}
+
+	s := &subscription{cancel: cancel}
+	c.subscriptions[id] = s
+	go c.forward(ctx, id, s, responses)
+
+	return nil
+}
+
+// forward sends the responses of a subscription to the client, until either
+// the client or the server completes the subscription.
+func (c *subscriptionConn) forward(ctx context.Context, id string, s *subscription, responses <-chan any) {
+	activeSubscriptions.Inc()
+	defer activeSubscriptions.Dec()
+
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		// The client may have completed the subscription, and reused its ID.
+		if c.subscriptions[id] == s {
+			delete(c.subscriptions, id)
+		}
+		s.cancel()
+	}()
+
+	for response := range responses {
+		if ctx.Err() != nil {
+			continue
+		}
+
+		payload, err := json.Marshal(response)
+		if err != nil {
+			c.logger.Warn("failed to marshal subscription response", sglog.Error(err))
+			continue
+		}
+		if err := c.write(subscriptionMessage{ID: id, Type: nextMessage, Payload: payload}); err != nil {
+			s.cancel()
+		}
+	}
+
+	if ctx.Err() == nil {
+		_ = c.write(subscriptionMessage{ID: id, Type: completeMessage})
+	}
+}
+
+// writeErrors rejects the subscription with the given ID before it is
+// executed.
+func (c *subscriptionConn) writeErrors(id string, messages ...string) error {
+	errs := make([]*gqlerrors.QueryError, 0, len(messages))
+	for _, message := range messages {
+		errs = append(errs, &gqlerrors.QueryError{Message: message})
+	}
+
+	payload, err := json.Marshal(errs)
+	if err != nil {
+		return err
+	}
+	return c.write(subscriptionMessage{ID: id, Type: errorMessage, Payload: payload})
+}
+
+func (c *subscriptionConn) write(msg subscriptionMessage) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(subscriptionWriteTimeout))
+	return c.conn.WriteJSON(msg)
+}
+
+// close closes the connection with the given close code. Closing the
+// connection stops the read loop, which cancels all subscriptions.
+func (c *subscriptionConn) close(code int, reason string) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(subscriptionWriteTimeout))
+	c.conn.Close()
+}
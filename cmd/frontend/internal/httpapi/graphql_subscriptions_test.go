@@ -0,0 +1,162 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+const testSubscriptionSchema = `
+schema {
+	query: Query
+	subscription: Subscription
+}
+
+type Query {
+	hello: String!
+}
+
+type Subscription {
+	count(to: Int!): Int!
+}
+`
+
+type testSubscriptionResolver struct{}
+
+func (testSubscriptionResolver) Hello() string { return "hello" }
+
+func (testSubscriptionResolver) Count(ctx context.Context, args *struct{ To int32 }) <-chan int32 {
+	counts := make(chan int32)
+	go func() {
+		defer close(counts)
+		for i := int32(1); i <= args.To; i++ {
+			select {
+			case counts <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return counts
+}
+
+func TestServeGraphQLSubscriptions(t *testing.T) {
+	conf.Mock(&conf.Unified{})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	schema := graphql.MustParseSchema(testSubscriptionSchema, testSubscriptionResolver{})
+	server := httptest.NewServer(serveGraphQLSubscriptions(logtest.Scoped(t), schema))
+	t.Cleanup(server.Close)
+
+	dial := func(t *testing.T) *websocket.Conn {
+		t.Helper()
+
+		dialer := websocket.Dialer{Subprotocols: []string{graphQLTransportWSProtocol}}
+		conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+
+	send := func(t *testing.T, conn *websocket.Conn, msg string) {
+		t.Helper()
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(msg)))
+	}
+
+	receive := func(t *testing.T, conn *websocket.Conn) subscriptionMessage {
+		t.Helper()
+
+		var msg subscriptionMessage
+		require.NoError(t, conn.ReadJSON(&msg))
+		return msg
+	}
+
+	assertClosed := func(t *testing.T, conn *websocket.Conn, code int) {
+		t.Helper()
+
+		_, _, err := conn.ReadMessage()
+		assert.True(t, websocket.IsCloseError(err, code), "unexpected error: %v", err)
+	}
+
+	t.Run("subscribe", func(t *testing.T) {
+		conn := dial(t)
+		send(t, conn, `{"type":"connection_init"}`)
+		assert.Equal(t, connectionAckMessage, receive(t, conn).Type)
+
+		send(t, conn, `{"type":"ping","payload":{"x":1}}`)
+		pong := receive(t, conn)
+		assert.Equal(t, pongMessage, pong.Type)
+		assert.JSONEq(t, `{"x":1}`, string(pong.Payload))
+
+		send(t, conn, `{"id":"1","type":"subscribe","payload":{"query":"subscription { count(to: 2) }"}}`)
+		for _, want := range []string{`{"data":{"count":1}}`, `{"data":{"count":2}}`} {
+			next := receive(t, conn)
+			assert.Equal(t, nextMessage, next.Type)
+			assert.Equal(t, "1", next.ID)
+			assert.JSONEq(t, want, string(next.Payload))
+		}
+		complete := receive(t, conn)
+		assert.Equal(t, completeMessage, complete.Type)
+		assert.Equal(t, "1", complete.ID)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		conn := dial(t)
+		send(t, conn, `{"type":"connection_init"}`)
+		receive(t, conn)
+
+		send(t, conn, `{"id":"1","type":"subscribe","payload":{"query":"subscription { unknown }"}}`)
+		next := receive(t, conn)
+		assert.Equal(t, nextMessage, next.Type)
+
+		var response graphql.Response
+		require.NoError(t, json.Unmarshal(next.Payload, &response))
+		assert.NotEmpty(t, response.Errors)
+	})
+
+	t.Run("subscribe before init", func(t *testing.T) {
+		conn := dial(t)
+		send(t, conn, `{"id":"1","type":"subscribe","payload":{"query":"subscription { count(to: 1) }"}}`)
+		assertClosed(t, conn, closeUnauthorized)
+	})
+
+	t.Run("duplicate init", func(t *testing.T) {
+		conn := dial(t)
+		send(t, conn, `{"type":"connection_init"}`)
+		receive(t, conn)
+		send(t, conn, `{"type":"connection_init"}`)
+		assertClosed(t, conn, closeTooManyInitRequests)
+	})
+
+	t.Run("duplicate subscription ID", func(t *testing.T) {
+		conn := dial(t)
+		send(t, conn, `{"type":"connection_init"}`)
+		receive(t, conn)
+		send(t, conn, `{"id":"1","type":"subscribe","payload":{"query":"subscription { count(to: 1000000) }"}}`)
+		send(t, conn, `{"id":"1","type":"subscribe","payload":{"query":"subscription { count(to: 1) }"}}`)
+
+		for {
+			_, _, err := conn.ReadMessage()
+			if err != nil {
+				assert.True(t, websocket.IsCloseError(err, closeSubscriberExists), "unexpected error: %v", err)
+				break
+			}
+		}
+	})
+
+	t.Run("invalid message", func(t *testing.T) {
+		conn := dial(t)
+		send(t, conn, `not json`)
+		assertClosed(t, conn, closeBadRequest)
+	})
+}
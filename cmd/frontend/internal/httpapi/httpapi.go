@@ -36,6 +36,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
 	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
 	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
@@ -51,6 +52,7 @@ type Handlers struct {
 
 	// Permissions
 	PermissionsGitHubWebhook webhooks.Registerer
+	PermissionsGitLabWebhook webhooks.Registerer
 
 	// Batch changes
 	BatchesGitHubWebhook            webhooks.Registerer
@@ -74,6 +76,15 @@ type Handlers struct {
 	// Code Insights
 	CodeInsightsDataExportHandler http.Handler
 
+	// Search exports
+	SearchExportDownloadHandler http.Handler
+
+	// User data exports
+	UserDataExportDownloadHandler http.Handler
+
+	// Executor job artifacts
+	ExecutorArtifactDownloadHandler http.Handler
+
 	// Dotcom license check
 	NewDotcomLicenseCheckHandler enterprise.NewDotcomLicenseCheckHandler
 
@@ -133,6 +144,7 @@ func NewHandler(
 	handlers.GitHubSyncWebhook.Register(&wh)
 	handlers.GitLabSyncWebhook.Register(&wh)
 	handlers.PermissionsGitHubWebhook.Register(&wh)
+	handlers.PermissionsGitLabWebhook.Register(&wh)
 	handlers.BatchesAzureDevOpsWebhook.Register(&wh)
 	// 🚨 SECURITY: This handler implements its own secret-based auth
 	webhookHandler := webhooks.NewHandler(logger, db, &wh)
@@ -159,6 +171,11 @@ func NewHandler(
 	m.Get(apirouter.CodeCompletions).Handler(trace.Route(handlers.NewCodeCompletionsHandler()))
 
 	m.Get(apirouter.CodeInsightsDataExport).Handler(trace.Route(handlers.CodeInsightsDataExportHandler))
+	m.Get(apirouter.SearchExportDownload).Handler(trace.Route(handlers.SearchExportDownloadHandler))
+	m.Get(apirouter.UserDataExportDownload).Handler(trace.Route(handlers.UserDataExportDownloadHandler))
+	m.Get(apirouter.ExecutorArtifactDownload).Handler(trace.Route(handlers.ExecutorArtifactDownloadHandler))
+
+	m.Get(apirouter.DebugBundle).Handler(trace.Route(handler(serveDebugBundle(db))))
 
 	if envvar.SourcegraphDotComMode() {
 		m.Path("/app/check/update").Name(codyapp.RouteAppUpdateCheck).Handler(trace.Route(codyapp.AppUpdateHandler(logger)))
@@ -169,6 +186,16 @@ func NewHandler(
 
 	m.Get(apirouter.SCIM).Handler(trace.Route(handlers.SCIMHandler))
 	m.Get(apirouter.GraphQL).Handler(trace.Route(handler(serveGraphQL(logger, schema, rateLimiter, false))))
+	m.Get(apirouter.GraphQLSubscriptions).Handler(trace.Route(serveGraphQLSubscriptions(logger, schema)))
+
+	m.Get(apirouter.RESTOpenAPI).Handler(trace.Route(handler(serveRESTOpenAPI)))
+	m.Get(apirouter.RESTRepositories).Handler(trace.Route(serveREST(logger, schema, rateLimiter, apirouter.RESTRepositories, serveRESTRepositories)))
+	m.Get(apirouter.RESTRepository).Handler(trace.Route(serveREST(logger, schema, rateLimiter, apirouter.RESTRepository, serveRESTRepository)))
+	m.Get(apirouter.RESTFile).Handler(trace.Route(serveREST(logger, schema, rateLimiter, apirouter.RESTFile, serveRESTFile)))
+	m.Get(apirouter.RESTDefinitions).Handler(trace.Route(serveREST(logger, schema, rateLimiter, apirouter.RESTDefinitions, serveRESTDefinitions)))
+	m.Get(apirouter.RESTReferences).Handler(trace.Route(serveREST(logger, schema, rateLimiter, apirouter.RESTReferences, serveRESTReferences)))
+	m.Get(apirouter.RESTSearch).Handler(trace.Route(serveREST(logger, schema, rateLimiter, apirouter.RESTSearch, serveRESTSearch)))
+	m.Get(apirouter.RESTCapabilities).Handler(trace.Route(serveREST(logger, schema, rateLimiter, apirouter.RESTCapabilities, serveRESTCapabilities)))
 
 	m.Get(apirouter.SearchStream).Handler(trace.Route(frontendsearch.StreamHandler(db, enterpriseJobs)))
 
@@ -232,6 +259,9 @@ func RegisterInternalServices(
 		SearchContextsRepoRevs: func(ctx context.Context, repoIDs []api.RepoID) (map[api.RepoID][]string, error) {
 			return searchcontexts.RepoRevs(ctx, db, repoIDs)
 		},
+		ArchivedRepoIDs:        newArchivedRepoIDsCache(db.RepoArchival()).Get,
+		IndexAdmission:         searchbackend.NewIndexAdmission(),
+		RepoSizes:              db.GitserverRepos().GetByNames,
 		Indexers:               search.Indexers(),
 		Ranking:                rankingService,
 		MinLastChangedDisabled: os.Getenv("SRC_SEARCH_INDEXER_EFFICIENT_POLLING_DISABLED") != "",
@@ -239,7 +269,9 @@ func RegisterInternalServices(
 	m.Get(apirouter.SearchConfiguration).Handler(trace.Route(handler(indexer.serveConfiguration)))
 	m.Get(apirouter.ReposIndex).Handler(trace.Route(handler(indexer.serveList)))
 	m.Get(apirouter.DocumentRanks).Handler(trace.Route(handler(indexer.serveDocumentRanks)))
+	m.Get(apirouter.RankingSignals).Handler(trace.Route(handler(indexer.serveRankingSignals)))
 	m.Get(apirouter.UpdateIndexStatus).Handler(trace.Route(handler(indexer.handleIndexStatusUpdate)))
+	m.Get(apirouter.UpdateIndexPressure).Handler(trace.Route(handler(indexer.handleIndexPressureUpdate)))
 
 	proto.RegisterZoektConfigurationServiceServer(s, &searchIndexerGRPCServer{server: indexer})
 
@@ -18,9 +18,27 @@ var (
 		Help:    "GraphQL request latencies in seconds.",
 		Buckets: trace.UserLatencyBuckets,
 	}, metricLabels)
+	persistedQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "src_graphql_persisted_query_duration_seconds",
+		Help:    "GraphQL persisted query latencies in seconds, by persisted query ID.",
+		Buckets: trace.UserLatencyBuckets,
+	}, []string{"query_id", "success"})
+	rejectedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_graphql_rejected_requests_total",
+		Help: "The number of GraphQL requests rejected by the persisted query allowlist or a complexity budget.",
+	}, []string{"anonymous"})
+	activeSubscriptions = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "src_graphql_active_subscriptions",
+		Help: "The number of active GraphQL subscriptions.",
+	})
 )
 
 func instrumentGraphQL(data traceData) {
+	if data.rejected {
+		rejectedRequests.WithLabelValues(strconv.FormatBool(data.anonymous)).Inc()
+		return
+	}
+
 	duration := time.Since(data.execStart)
 	labels := prometheus.Labels{
 		"route":    data.requestName,
@@ -28,4 +46,7 @@ func instrumentGraphQL(data traceData) {
 		"mutation": strconv.FormatBool(strings.Contains(data.queryParams.Query, "mutation")),
 	}
 	requestDuration.With(labels).Observe(duration.Seconds())
+	if data.persistedQueryID != "" {
+		persistedQueryDuration.WithLabelValues(data.persistedQueryID, labels["success"]).Observe(duration.Seconds())
+	}
 }
@@ -0,0 +1,173 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	sglog "github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// restHandlerFunc handles a request to an endpoint of the REST API, and returns
+// the response, which is marshalled to JSON.
+type restHandlerFunc func(r *http.Request, client *restGraphQLClient) (any, error)
+
+// restError is an error returned to clients of the REST API with the given
+// HTTP status.
+type restError struct {
+	status     int
+	message    string
+	retryAfter time.Duration
+}
+
+func (e *restError) Error() string {
+	return e.message
+}
+
+// restErrorResponse is the body of error responses of the REST API.
+type restErrorResponse struct {
+	Error string `json:"error" description:"A description of the error."`
+}
+
+// serveREST serves an endpoint of the REST API. The endpoints of the REST API
+// are implemented with GraphQL queries, so that they share the authorization
+// checks and rate limits of the GraphQL API.
+func serveREST(logger sglog.Logger, schema *graphql.Schema, rlw graphqlbackend.LimitWatcher, name string, h restHandlerFunc) http.HandlerFunc {
+	logger = logger.Scoped("restapi", "REST API handler").With(sglog.String("endpoint", name))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🚨 SECURITY: The REST API is only available to authenticated clients,
+		// which authenticate with access tokens or with session cookies from
+		// trusted origins.
+		if !actor.FromContext(r.Context()).IsAuthenticated() {
+			w.Header().Set("WWW-Authenticate", `token realm="Sourcegraph"`)
+			writeRESTError(w, &restError{status: http.StatusUnauthorized, message: "authentication required"})
+			return
+		}
+
+		uid, _, _ := getUID(r)
+		client := &restGraphQLClient{
+			schema:        schema,
+			rateLimiter:   rlw,
+			uid:           uid,
+			requestName:   name,
+			requestSource: search.GuessSource(r),
+		}
+
+		response, err := h(r, client)
+		if err != nil {
+			var restErr *restError
+			if !errors.As(err, &restErr) {
+				logger.Error("failed to handle REST API request", sglog.Error(err))
+				restErr = &restError{status: http.StatusInternalServerError, message: "internal error"}
+			}
+			writeRESTError(w, restErr)
+			return
+		}
+
+		if err := writeJSON(w, response); err != nil {
+			logger.Warn("failed to write REST API response", sglog.Error(err))
+		}
+	}
+}
+
+func writeRESTError(w http.ResponseWriter, err *restError) {
+	if err.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(err.retryAfter.Seconds())))
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(err.status)
+	_ = json.NewEncoder(w).Encode(restErrorResponse{Error: err.message})
+}
+
+// restGraphQLClient executes the GraphQL queries of REST API endpoints on
+// behalf of the client of the REST API.
+type restGraphQLClient struct {
+	schema        *graphql.Schema
+	rateLimiter   graphqlbackend.LimitWatcher
+	uid           string
+	requestName   string
+	requestSource trace.SourceType
+}
+
+// query executes the given GraphQL query, and unmarshals its data into result.
+func (c *restGraphQLClient) query(ctx context.Context, query string, variables map[string]any, result any) error {
+	// The queries of the REST API never change, so they only fail validation if
+	// the parts of the schema they depend on are not available on this instance.
+	if errs := c.schema.Validate(query); len(errs) > 0 {
+		return &restError{status: http.StatusNotImplemented, message: "this endpoint is not available on this instance"}
+	}
+
+	if rl, enabled := c.rateLimiter.Get(); enabled {
+		cost, err := graphqlbackend.EstimateQueryCost(query, variables)
+		if err != nil {
+			return errors.Wrap(err, "estimating query cost")
+		}
+
+		limited, limitResult, err := rl.RateLimit(c.uid, cost.FieldCount, graphqlbackend.LimiterArgs{
+			RequestName:   c.requestName,
+			RequestSource: c.requestSource,
+		})
+		if err != nil {
+			return errors.Wrap(err, "checking rate limit")
+		}
+		if limited {
+			return &restError{status: http.StatusTooManyRequests, message: "rate limit exceeded", retryAfter: limitResult.RetryAfter}
+		}
+	}
+
+	response := c.schema.Exec(ctx, query, "", variables)
+	if len(response.Errors) > 0 {
+		return restErrorFromGraphQL(response.Errors)
+	}
+	return json.Unmarshal(response.Data, result)
+}
+
+// restErrorFromGraphQL returns the error returned to REST API clients for the
+// given errors of a GraphQL query.
+func restErrorFromGraphQL(errs []*gqlerrors.QueryError) error {
+	for _, err := range errs {
+		switch {
+		case err.ResolverError == nil:
+			continue
+		case errcode.IsNotFound(err.ResolverError):
+			return &restError{status: http.StatusNotFound, message: err.Message}
+		case errcode.IsUnauthorized(err.ResolverError), errcode.IsForbidden(err.ResolverError):
+			return &restError{status: http.StatusForbidden, message: err.Message}
+		case errcode.IsBadRequest(err.ResolverError):
+			return &restError{status: http.StatusBadRequest, message: err.Message}
+		}
+	}
+
+	return errors.New(errs[0].Message)
+}
+
+// restIntParam returns the value of the given integer query parameter, or
+// defaultValue if it is not set.
+func restIntParam(r *http.Request, name string, defaultValue, min, max int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < min || value > max {
+		return 0, &restError{
+			status:  http.StatusBadRequest,
+			message: fmt.Sprintf("invalid %s parameter, expected an integer between %d and %d", name, min, max),
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,602 @@
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/routevar"
+)
+
+type restRepository struct {
+	Name                string `json:"name" description:"The name of the repository, such as github.com/sourcegraph/sourcegraph."`
+	URL                 string `json:"url" description:"The path of the repository on this Sourcegraph instance."`
+	Description         string `json:"description"`
+	DefaultBranch       string `json:"defaultBranch,omitempty" description:"The default branch, omitted if the repository is empty."`
+	ExternalServiceType string `json:"externalServiceType" description:"The type of the code host of the repository, such as github."`
+	Fork                bool   `json:"fork"`
+	Archived            bool   `json:"archived"`
+	Private             bool   `json:"private"`
+}
+
+type restRepositoryList struct {
+	Repositories []restRepository `json:"repositories"`
+	NextCursor   string           `json:"nextCursor,omitempty" description:"The cursor of the next page, omitted on the last page."`
+}
+
+type restFile struct {
+	Repository string `json:"repository"`
+	Commit     string `json:"commit" description:"The ID of the commit the revision resolved to."`
+	Path       string `json:"path"`
+	Size       int    `json:"size" description:"The size of the file in bytes."`
+	Binary     bool   `json:"binary"`
+	Content    string `json:"content,omitempty" description:"The content of the file, omitted for binary files."`
+}
+
+type restPosition struct {
+	Line      int `json:"line" description:"The zero-based line number."`
+	Character int `json:"character" description:"The zero-based character offset in the line."`
+}
+
+type restRange struct {
+	Start restPosition `json:"start"`
+	End   restPosition `json:"end" description:"The end of the range, exclusive."`
+}
+
+type restLocation struct {
+	Repository string     `json:"repository"`
+	Commit     string     `json:"commit"`
+	Path       string     `json:"path"`
+	Range      *restRange `json:"range,omitempty"`
+}
+
+type restLocationList struct {
+	Locations  []restLocation `json:"locations"`
+	NextCursor string         `json:"nextCursor,omitempty" description:"The cursor of the next page, omitted on the last page."`
+}
+
+type restLineMatch struct {
+	Line             int     `json:"line" description:"The zero-based line number of the match."`
+	Preview          string  `json:"preview" description:"The content of the line."`
+	OffsetAndLengths [][]int `json:"offsetAndLengths" description:"The offsets and lengths of the matches in the line."`
+}
+
+type restSearchResult struct {
+	Type        string          `json:"type" description:"The type of the result: file, repository or commit."`
+	Repository  string          `json:"repository"`
+	Path        string          `json:"path,omitempty" description:"The path of the matching file, for file results."`
+	Commit      string          `json:"commit,omitempty" description:"The ID of the matching commit, for commit results."`
+	URL         string          `json:"url"`
+	LineMatches []restLineMatch `json:"lineMatches,omitempty" description:"The matching lines, for file results."`
+}
+
+type restSearchResults struct {
+	MatchCount int                `json:"matchCount"`
+	LimitHit   bool               `json:"limitHit" description:"Whether more results exist than were returned. Use count:N in the query to return more results."`
+	Results    []restSearchResult `json:"results"`
+}
+
+type restCapabilities struct {
+	BatchChanges                    bool  `json:"batchChanges" description:"Whether the client can create and apply batch changes."`
+	BatchChangesServerSideExecution bool  `json:"batchChangesServerSideExecution" description:"Whether the client can run batch specs on the instance with executors."`
+	Cody                            bool  `json:"cody" description:"Whether Cody is enabled for the client."`
+	Embeddings                      bool  `json:"embeddings" description:"Whether embeddings are enabled for the client."`
+	SCIM                            *bool `json:"scim,omitempty" description:"Whether users can be provisioned through the SCIM API. Only returned to site admins."`
+}
+
+const restRepositoryFields = `
+fragment RESTRepositoryFields on Repository {
+	name
+	url
+	description
+	isFork
+	isArchived
+	isPrivate
+	defaultBranch { abbrevName }
+	externalRepository { serviceType }
+}
+`
+
+type restGraphQLRepository struct {
+	Name               string
+	URL                string
+	Description        string
+	IsFork             bool
+	IsArchived         bool
+	IsPrivate          bool
+	DefaultBranch      *struct{ AbbrevName string }
+	ExternalRepository struct {
+		ServiceType string
+	}
+}
+
+func (r restGraphQLRepository) toREST() restRepository {
+	repo := restRepository{
+		Name:                r.Name,
+		URL:                 r.URL,
+		Description:         r.Description,
+		ExternalServiceType: r.ExternalRepository.ServiceType,
+		Fork:                r.IsFork,
+		Archived:            r.IsArchived,
+		Private:             r.IsPrivate,
+	}
+	if r.DefaultBranch != nil {
+		repo.DefaultBranch = r.DefaultBranch.AbbrevName
+	}
+	return repo
+}
+
+type restGraphQLPageInfo struct {
+	EndCursor   *string
+	HasNextPage bool
+}
+
+func (p restGraphQLPageInfo) nextCursor() string {
+	if !p.HasNextPage || p.EndCursor == nil {
+		return ""
+	}
+	return *p.EndCursor
+}
+
+const restRepositoriesQuery = `
+query RESTRepositories($query: String, $first: Int!, $after: String) {
+	repositories(query: $query, first: $first, after: $after) {
+		nodes { ...RESTRepositoryFields }
+		pageInfo { endCursor hasNextPage }
+	}
+}
+` + restRepositoryFields
+
+func serveRESTRepositories(r *http.Request, client *restGraphQLClient) (any, error) {
+	first, err := restIntParam(r, "first", 50, 1, 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := map[string]any{"first": first}
+	if query := r.URL.Query().Get("query"); query != "" {
+		variables["query"] = query
+	}
+	if after := r.URL.Query().Get("after"); after != "" {
+		variables["after"] = after
+	}
+
+	var data struct {
+		Repositories struct {
+			Nodes    []restGraphQLRepository
+			PageInfo restGraphQLPageInfo
+		}
+	}
+	if err := client.query(r.Context(), restRepositoriesQuery, variables, &data); err != nil {
+		return nil, err
+	}
+
+	list := restRepositoryList{
+		Repositories: make([]restRepository, 0, len(data.Repositories.Nodes)),
+		NextCursor:   data.Repositories.PageInfo.nextCursor(),
+	}
+	for _, repo := range data.Repositories.Nodes {
+		list.Repositories = append(list.Repositories, repo.toREST())
+	}
+	return list, nil
+}
+
+const restRepositoryQuery = `
+query RESTRepository($name: String!) {
+	repository(name: $name) { ...RESTRepositoryFields }
+}
+` + restRepositoryFields
+
+func serveRESTRepository(r *http.Request, client *restGraphQLClient) (any, error) {
+	var data struct {
+		Repository *restGraphQLRepository
+	}
+	if err := client.query(r.Context(), restRepositoryQuery, map[string]any{"name": string(routevar.ToRepo(mux.Vars(r)))}, &data); err != nil {
+		return nil, err
+	}
+	if data.Repository == nil {
+		return nil, errRESTRepositoryNotFound
+	}
+	return data.Repository.toREST(), nil
+}
+
+var (
+	errRESTRepositoryNotFound = &restError{status: http.StatusNotFound, message: "repository not found"}
+	errRESTRevisionNotFound   = &restError{status: http.StatusNotFound, message: "revision not found"}
+	errRESTFileNotFound       = &restError{status: http.StatusNotFound, message: "file not found"}
+)
+
+// restFileVariables returns the variables identifying the file of a request to
+// a REST API endpoint for files at a revision.
+func restFileVariables(r *http.Request) map[string]any {
+	vars := mux.Vars(r)
+	repoRev := routevar.ToRepoRev(vars)
+	if repoRev.Rev == "" {
+		repoRev.Rev = "HEAD"
+	}
+
+	return map[string]any{
+		"repo": string(repoRev.Repo),
+		"rev":  repoRev.Rev,
+		"path": strings.TrimPrefix(vars["Path"], "/"),
+	}
+}
+
+const restFileQuery = `
+query RESTFile($repo: String!, $rev: String!, $path: String!) {
+	repository(name: $repo) {
+		commit(rev: $rev) {
+			oid
+			blob(path: $path) {
+				path
+				byteSize
+				binary
+				content
+			}
+		}
+	}
+}
+`
+
+func serveRESTFile(r *http.Request, client *restGraphQLClient) (any, error) {
+	var data struct {
+		Repository *struct {
+			Commit *struct {
+				OID  string
+				Blob *struct {
+					Path     string
+					ByteSize int
+					Binary   bool
+					Content  string
+				}
+			}
+		}
+	}
+	variables := restFileVariables(r)
+	if err := client.query(r.Context(), restFileQuery, variables, &data); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case data.Repository == nil:
+		return nil, errRESTRepositoryNotFound
+	case data.Repository.Commit == nil:
+		return nil, errRESTRevisionNotFound
+	case data.Repository.Commit.Blob == nil:
+		return nil, errRESTFileNotFound
+	}
+
+	blob := data.Repository.Commit.Blob
+	file := restFile{
+		Repository: variables["repo"].(string),
+		Commit:     data.Repository.Commit.OID,
+		Path:       blob.Path,
+		Size:       blob.ByteSize,
+		Binary:     blob.Binary,
+	}
+	if !blob.Binary {
+		file.Content = blob.Content
+	}
+	return file, nil
+}
+
+const restLocationFields = `
+fragment RESTLocationFields on Location {
+	resource {
+		path
+		repository { name }
+		commit { oid }
+	}
+	range {
+		start { line character }
+		end { line character }
+	}
+}
+`
+
+type restGraphQLLocation struct {
+	Resource struct {
+		Path       string
+		Repository struct{ Name string }
+		Commit     struct{ OID string }
+	}
+	Range *restRange
+}
+
+// restGraphQLLocations is the data of a query for the locations of the symbol
+// at a position in a file.
+type restGraphQLLocations struct {
+	Repository *struct {
+		Commit *struct {
+			Blob *struct {
+				LSIF *struct {
+					Definitions *restGraphQLLocationConnection
+					References  *restGraphQLLocationConnection
+				}
+			}
+		}
+	}
+}
+
+type restGraphQLLocationConnection struct {
+	Nodes    []restGraphQLLocation
+	PageInfo restGraphQLPageInfo
+}
+
+// locations returns the definitions or references of the query, or nil if no
+// precise code intelligence is available for the file.
+func (d *restGraphQLLocations) locations() (*restGraphQLLocationConnection, error) {
+	switch {
+	case d.Repository == nil:
+		return nil, errRESTRepositoryNotFound
+	case d.Repository.Commit == nil:
+		return nil, errRESTRevisionNotFound
+	case d.Repository.Commit.Blob == nil:
+		return nil, errRESTFileNotFound
+	case d.Repository.Commit.Blob.LSIF == nil:
+		return nil, nil
+	}
+
+	if lsif := d.Repository.Commit.Blob.LSIF; lsif.Definitions != nil {
+		return lsif.Definitions, nil
+	}
+	return d.Repository.Commit.Blob.LSIF.References, nil
+}
+
+func (c *restGraphQLLocationConnection) toREST() restLocationList {
+	list := restLocationList{Locations: []restLocation{}}
+	if c == nil {
+		return list
+	}
+
+	list.NextCursor = c.PageInfo.nextCursor()
+	for _, location := range c.Nodes {
+		list.Locations = append(list.Locations, restLocation{
+			Repository: location.Resource.Repository.Name,
+			Commit:     location.Resource.Commit.OID,
+			Path:       location.Resource.Path,
+			Range:      location.Range,
+		})
+	}
+	return list
+}
+
+// restPositionVariables adds the position of a request to a REST API endpoint
+// for the symbol at a position to the given variables.
+func restPositionVariables(r *http.Request, variables map[string]any) error {
+	line, err := restIntParam(r, "line", -1, 0, 1<<31-1)
+	if err == nil && line < 0 {
+		err = &restError{status: http.StatusBadRequest, message: "missing line parameter"}
+	}
+	if err != nil {
+		return err
+	}
+
+	character, err := restIntParam(r, "character", -1, 0, 1<<31-1)
+	if err == nil && character < 0 {
+		err = &restError{status: http.StatusBadRequest, message: "missing character parameter"}
+	}
+	if err != nil {
+		return err
+	}
+
+	variables["line"] = line
+	variables["character"] = character
+	return nil
+}
+
+const restDefinitionsQuery = `
+query RESTDefinitions($repo: String!, $rev: String!, $path: String!, $line: Int!, $character: Int!) {
+	repository(name: $repo) {
+		commit(rev: $rev) {
+			blob(path: $path) {
+				lsif {
+					definitions(line: $line, character: $character) {
+						nodes { ...RESTLocationFields }
+						pageInfo { endCursor hasNextPage }
+					}
+				}
+			}
+		}
+	}
+}
+` + restLocationFields
+
+func serveRESTDefinitions(r *http.Request, client *restGraphQLClient) (any, error) {
+	variables := restFileVariables(r)
+	if err := restPositionVariables(r, variables); err != nil {
+		return nil, err
+	}
+
+	var data restGraphQLLocations
+	if err := client.query(r.Context(), restDefinitionsQuery, variables, &data); err != nil {
+		return nil, err
+	}
+
+	locations, err := data.locations()
+	if err != nil {
+		return nil, err
+	}
+	return locations.toREST(), nil
+}
+
+const restReferencesQuery = `
+query RESTReferences($repo: String!, $rev: String!, $path: String!, $line: Int!, $character: Int!, $first: Int!, $after: String) {
+	repository(name: $repo) {
+		commit(rev: $rev) {
+			blob(path: $path) {
+				lsif {
+					references(line: $line, character: $character, first: $first, after: $after) {
+						nodes { ...RESTLocationFields }
+						pageInfo { endCursor hasNextPage }
+					}
+				}
+			}
+		}
+	}
+}
+` + restLocationFields
+
+func serveRESTReferences(r *http.Request, client *restGraphQLClient) (any, error) {
+	variables := restFileVariables(r)
+	if err := restPositionVariables(r, variables); err != nil {
+		return nil, err
+	}
+
+	first, err := restIntParam(r, "first", 100, 1, 1000)
+	if err != nil {
+		return nil, err
+	}
+	variables["first"] = first
+	if after := r.URL.Query().Get("after"); after != "" {
+		variables["after"] = after
+	}
+
+	var data restGraphQLLocations
+	if err := client.query(r.Context(), restReferencesQuery, variables, &data); err != nil {
+		return nil, err
+	}
+
+	locations, err := data.locations()
+	if err != nil {
+		return nil, err
+	}
+	return locations.toREST(), nil
+}
+
+const restSearchQuery = `
+query RESTSearch($query: String!) {
+	search(query: $query, version: V3) {
+		results {
+			matchCount
+			limitHit
+			results {
+				__typename
+				... on FileMatch {
+					repository { name }
+					file { path url }
+					lineMatches { lineNumber preview offsetAndLengths }
+				}
+				... on Repository {
+					name
+					url
+				}
+				... on CommitSearchResult {
+					url
+					commit {
+						oid
+						repository { name }
+					}
+				}
+			}
+		}
+	}
+}
+`
+
+func serveRESTSearch(r *http.Request, client *restGraphQLClient) (any, error) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		return nil, &restError{status: http.StatusBadRequest, message: "missing q parameter"}
+	}
+
+	var data struct {
+		Search *struct {
+			Results struct {
+				MatchCount int
+				LimitHit   bool
+				Results    []struct {
+					Typename   string `json:"__typename"`
+					Name       string
+					URL        string
+					Repository struct{ Name string }
+					File       struct {
+						Path string
+						URL  string
+					}
+					LineMatches []struct {
+						LineNumber       int
+						Preview          string
+						OffsetAndLengths [][]int
+					}
+					Commit struct {
+						OID        string
+						Repository struct{ Name string }
+					}
+				}
+			}
+		}
+	}
+	if err := client.query(r.Context(), restSearchQuery, map[string]any{"query": query}, &data); err != nil {
+		return nil, err
+	}
+
+	results := restSearchResults{Results: []restSearchResult{}}
+	if data.Search == nil {
+		return results, nil
+	}
+
+	results.MatchCount = data.Search.Results.MatchCount
+	results.LimitHit = data.Search.Results.LimitHit
+	for _, result := range data.Search.Results.Results {
+		switch result.Typename {
+		case "FileMatch":
+			match := restSearchResult{
+				Type:        "file",
+				Repository:  result.Repository.Name,
+				Path:        result.File.Path,
+				URL:         result.File.URL,
+				LineMatches: make([]restLineMatch, 0, len(result.LineMatches)),
+			}
+			for _, lineMatch := range result.LineMatches {
+				match.LineMatches = append(match.LineMatches, restLineMatch{
+					Line:             lineMatch.LineNumber,
+					Preview:          lineMatch.Preview,
+					OffsetAndLengths: lineMatch.OffsetAndLengths,
+				})
+			}
+			results.Results = append(results.Results, match)
+		case "Repository":
+			results.Results = append(results.Results, restSearchResult{
+				Type:       "repository",
+				Repository: result.Name,
+				URL:        result.URL,
+			})
+		case "CommitSearchResult":
+			results.Results = append(results.Results, restSearchResult{
+				Type:       "commit",
+				Repository: result.Commit.Repository.Name,
+				Commit:     result.Commit.OID,
+				URL:        result.URL,
+			})
+		}
+	}
+	return results, nil
+}
+
+const restCapabilitiesQuery = `
+query RESTCapabilities {
+	site {
+		capabilities {
+			batchChanges
+			batchChangesServerSideExecution
+			cody
+			embeddings
+			scim
+		}
+	}
+}
+`
+
+func serveRESTCapabilities(r *http.Request, client *restGraphQLClient) (any, error) {
+	var data struct {
+		Site struct {
+			Capabilities restCapabilities
+		}
+	}
+	if err := client.query(r.Context(), restCapabilitiesQuery, nil, &data); err != nil {
+		return nil, err
+	}
+	return data.Site.Capabilities, nil
+}
@@ -0,0 +1,229 @@
+package httpapi
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// restEndpoint describes an endpoint of the REST API in its OpenAPI
+// specification.
+type restEndpoint struct {
+	// path is the OpenAPI path template of the endpoint, relative to /.api/v1.
+	path        string
+	operationID string
+	summary     string
+	params      []restParam
+	// response is a value of the type of successful responses.
+	response any
+}
+
+type restParam struct {
+	name        string
+	in          string
+	description string
+	integer     bool
+	required    bool
+}
+
+var (
+	restRepoParam = restParam{name: "repo", in: "path", required: true, description: "The name of the repository, such as github.com/sourcegraph/sourcegraph. Its slashes are not escaped."}
+	restRevParam  = restParam{name: "rev", in: "path", required: true, description: "A revision, such as a branch name or a commit ID. The @{rev} suffix of the repository can be omitted for the default branch."}
+	restPathParam = restParam{name: "path", in: "path", required: true, description: "The path of the file. Its slashes are not escaped."}
+	restLineParam = restParam{name: "line", in: "query", required: true, integer: true, description: "The zero-based line of the symbol."}
+	restCharParam = restParam{name: "character", in: "query", required: true, integer: true, description: "The zero-based character offset of the symbol in the line."}
+)
+
+// restEndpoints are the endpoints of the REST API. The OpenAPI specification
+// of the REST API is generated from them.
+var restEndpoints = []restEndpoint{
+	{
+		path:        "/repos",
+		operationID: "listRepositories",
+		summary:     "List the repositories the client has access to.",
+		params: []restParam{
+			{name: "query", in: "query", description: "Only return repositories whose names match this query."},
+			{name: "first", in: "query", integer: true, description: "The number of repositories to return, between 1 and 1000. Defaults to 50."},
+			{name: "after", in: "query", description: "The nextCursor of the previous page."},
+		},
+		response: restRepositoryList{},
+	},
+	{
+		path:        "/repos/{repo}",
+		operationID: "getRepository",
+		summary:     "Get a repository.",
+		params:      []restParam{restRepoParam},
+		response:    restRepository{},
+	},
+	{
+		path:        "/repos/{repo}@{rev}/-/files/{path}",
+		operationID: "getFile",
+		summary:     "Get a file at a revision.",
+		params:      []restParam{restRepoParam, restRevParam, restPathParam},
+		response:    restFile{},
+	},
+	{
+		path:        "/repos/{repo}@{rev}/-/definitions/{path}",
+		operationID: "listDefinitions",
+		summary:     "List the precise definitions of the symbol at a position in a file.",
+		params:      []restParam{restRepoParam, restRevParam, restPathParam, restLineParam, restCharParam},
+		response:    restLocationList{},
+	},
+	{
+		path:        "/repos/{repo}@{rev}/-/references/{path}",
+		operationID: "listReferences",
+		summary:     "List the precise references of the symbol at a position in a file.",
+		params: []restParam{
+			restRepoParam, restRevParam, restPathParam, restLineParam, restCharParam,
+			{name: "first", in: "query", integer: true, description: "The number of references to return, between 1 and 1000. Defaults to 100."},
+			{name: "after", in: "query", description: "The nextCursor of the previous page."},
+		},
+		response: restLocationList{},
+	},
+	{
+		path:        "/search",
+		operationID: "search",
+		summary:     "Search code, repositories and commits.",
+		params: []restParam{
+			{name: "q", in: "query", required: true, description: "The search query, using the Sourcegraph search syntax."},
+		},
+		response: restSearchResults{},
+	},
+	{
+		path:        "/capabilities",
+		operationID: "getCapabilities",
+		summary:     "Get the features of the instance that are enabled for the client.",
+		response:    restCapabilities{},
+	},
+}
+
+// serveRESTOpenAPI serves the OpenAPI specification of the REST API.
+func serveRESTOpenAPI(w http.ResponseWriter, r *http.Request) error {
+	return writeJSON(w, restOpenAPISpec(conf.ExternalURL()))
+}
+
+// restOpenAPISpec returns the OpenAPI specification of the REST API served by
+// the Sourcegraph instance at the given URL.
+func restOpenAPISpec(externalURL string) map[string]any {
+	schemas := map[string]any{}
+	errorResponse := map[string]any{
+		"description": "An error.",
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": restOpenAPISchema(reflect.TypeOf(restErrorResponse{}), schemas)},
+		},
+	}
+
+	paths := map[string]any{}
+	for _, endpoint := range restEndpoints {
+		params := make([]any, 0, len(endpoint.params))
+		for _, param := range endpoint.params {
+			paramType := "string"
+			if param.integer {
+				paramType = "integer"
+			}
+			params = append(params, map[string]any{
+				"name":        param.name,
+				"in":          param.in,
+				"description": param.description,
+				"required":    param.required,
+				"schema":      map[string]any{"type": paramType},
+			})
+		}
+
+		paths[endpoint.path] = map[string]any{
+			"get": map[string]any{
+				"operationId": endpoint.operationID,
+				"summary":     endpoint.summary,
+				"parameters":  params,
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "OK",
+						"content": map[string]any{
+							"application/json": map[string]any{"schema": restOpenAPISchema(reflect.TypeOf(endpoint.response), schemas)},
+						},
+					},
+					"default": errorResponse,
+				},
+			},
+		}
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "Sourcegraph REST API",
+			"version": "v1",
+		},
+		"servers":  []any{map[string]any{"url": strings.TrimSuffix(externalURL, "/") + "/.api/v1"}},
+		"security": []any{map[string]any{"token": []any{}}},
+		"paths":    paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"token": map[string]any{
+					"type":        "apiKey",
+					"in":          "header",
+					"name":        "Authorization",
+					"description": "An access token, in the form `token <access token>`.",
+				},
+			},
+		},
+	}
+}
+
+// restOpenAPISchema returns the OpenAPI schema of the given type. Struct types
+// are added to schemas, and referenced by name.
+func restOpenAPISchema(t reflect.Type, schemas map[string]any) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return restOpenAPISchema(t.Elem(), schemas)
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": restOpenAPISchema(t.Elem(), schemas)}
+	case reflect.Struct:
+		name := strings.TrimPrefix(t.Name(), "rest")
+		ref := map[string]any{"$ref": "#/components/schemas/" + name}
+		if _, ok := schemas[name]; ok {
+			return ref
+		}
+		// Register the schema before its fields, in case of recursive types.
+		schema := map[string]any{"type": "object"}
+		schemas[name] = schema
+
+		properties := map[string]any{}
+		required := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			jsonName, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if jsonName == "" || jsonName == "-" {
+				continue
+			}
+
+			property := restOpenAPISchema(field.Type, schemas)
+			if description := field.Tag.Get("description"); description != "" {
+				if _, isRef := property["$ref"]; isRef {
+					// Siblings of $ref are ignored in OpenAPI 3.0.
+					property = map[string]any{"allOf": []any{property}}
+				}
+				property["description"] = description
+			}
+			properties[jsonName] = property
+
+			if options != "omitempty" {
+				required = append(required, jsonName)
+			}
+		}
+		schema["properties"] = properties
+		schema["required"] = required
+		return ref
+	default:
+		panic("unsupported type in REST API response: " + t.String())
+	}
+}
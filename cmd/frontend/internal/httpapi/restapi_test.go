@@ -0,0 +1,207 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/throttled/throttled/v2"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type testRESTResolver struct{}
+
+func (testRESTResolver) Hello() string { return "hello" }
+
+type testLimitWatcher struct{ limiter graphqlbackend.Limiter }
+
+func (w testLimitWatcher) Get() (graphqlbackend.Limiter, bool) { return w.limiter, w.limiter != nil }
+
+type testLimiter struct{ limited bool }
+
+func (l testLimiter) RateLimit(string, int, graphqlbackend.LimiterArgs) (bool, throttled.RateLimitResult, error) {
+	return l.limited, throttled.RateLimitResult{RetryAfter: 30 * time.Second}, nil
+}
+
+func TestServeREST(t *testing.T) {
+	schema := graphql.MustParseSchema(`type Query { hello: String! }`, testRESTResolver{})
+	hello := func(r *http.Request, client *restGraphQLClient) (any, error) {
+		var data struct{ Hello string }
+		if err := client.query(r.Context(), `{ hello }`, nil, &data); err != nil {
+			return nil, err
+		}
+		return map[string]string{"message": data.Hello}, nil
+	}
+
+	serve := func(limiter graphqlbackend.Limiter, a *actor.Actor, h restHandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/.api/v1/hello", nil)
+		req = req.WithContext(actor.WithActor(context.Background(), a))
+		rec := httptest.NewRecorder()
+		serveREST(logtest.Scoped(t), schema, testLimitWatcher{limiter}, "rest.hello", h).ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("success", func(t *testing.T) {
+		rec := serve(testLimiter{}, &actor.Actor{UID: 1}, hello)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"message":"hello"}`, rec.Body.String())
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		rec := serve(nil, &actor.Actor{}, hello)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.JSONEq(t, `{"error":"authentication required"}`, rec.Body.String())
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		rec := serve(testLimiter{limited: true}, &actor.Actor{UID: 1}, hello)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+	})
+
+	t.Run("unavailable query", func(t *testing.T) {
+		rec := serve(nil, &actor.Actor{UID: 1}, func(r *http.Request, client *restGraphQLClient) (any, error) {
+			return nil, client.query(r.Context(), `{ goodbye }`, nil, &struct{}{})
+		})
+		assert.Equal(t, http.StatusNotImplemented, rec.Code)
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		rec := serve(nil, &actor.Actor{UID: 1}, func(r *http.Request, client *restGraphQLClient) (any, error) {
+			return nil, errors.New("secret details")
+		})
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "secret details")
+	})
+}
+
+type testUnauthorizedError struct{}
+
+func (testUnauthorizedError) Error() string      { return "must be site admin" }
+func (testUnauthorizedError) Unauthorized() bool { return true }
+
+type testBadRequestError struct{}
+
+func (testBadRequestError) Error() string    { return "invalid cursor" }
+func (testBadRequestError) BadRequest() bool { return true }
+
+func TestRESTErrorFromGraphQL(t *testing.T) {
+	resolverError := func(err error) *gqlerrors.QueryError {
+		return &gqlerrors.QueryError{Message: err.Error(), ResolverError: err}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		err    *gqlerrors.QueryError
+		status int
+	}{
+		{name: "not found", err: resolverError(&errcode.Mock{Message: "repo not found", IsNotFound: true}), status: http.StatusNotFound},
+		{name: "unauthorized", err: resolverError(testUnauthorizedError{}), status: http.StatusForbidden},
+		{name: "bad request", err: resolverError(testBadRequestError{}), status: http.StatusBadRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var restErr *restError
+			require.True(t, errors.As(restErrorFromGraphQL([]*gqlerrors.QueryError{tc.err}), &restErr))
+			assert.Equal(t, tc.status, restErr.status)
+			assert.Equal(t, tc.err.Message, restErr.message)
+		})
+	}
+
+	t.Run("other", func(t *testing.T) {
+		var restErr *restError
+		err := restErrorFromGraphQL([]*gqlerrors.QueryError{resolverError(errors.New("boom"))})
+		assert.False(t, errors.As(err, &restErr))
+	})
+}
+
+func TestRESTIntParam(t *testing.T) {
+	get := func(query string) (int, error) {
+		return restIntParam(httptest.NewRequest("GET", "/.api/v1/repos?"+query, nil), "first", 50, 1, 1000)
+	}
+
+	value, err := get("")
+	require.NoError(t, err)
+	assert.Equal(t, 50, value)
+
+	value, err = get("first=10")
+	require.NoError(t, err)
+	assert.Equal(t, 10, value)
+
+	for _, query := range []string{"first=0", "first=1001", "first=ten"} {
+		_, err := get(query)
+		var restErr *restError
+		require.True(t, errors.As(err, &restErr), query)
+		assert.Equal(t, http.StatusBadRequest, restErr.status)
+	}
+}
+
+func TestRESTQueries(t *testing.T) {
+	schema, err := graphqlbackend.NewSchemaWithoutResolvers(database.NewMockDB())
+	require.NoError(t, err)
+
+	for name, query := range map[string]string{
+		"repositories": restRepositoriesQuery,
+		"repository":   restRepositoryQuery,
+		"file":         restFileQuery,
+		"search":       restSearchQuery,
+		"capabilities": restCapabilitiesQuery,
+	} {
+		assert.Empty(t, schema.Validate(query), name)
+	}
+}
+
+func TestRESTOpenAPISpec(t *testing.T) {
+	spec := restOpenAPISpec("https://sourcegraph.example.com/")
+
+	assert.Equal(t, []any{map[string]any{"url": "https://sourcegraph.example.com/.api/v1"}}, spec["servers"])
+
+	paths := spec["paths"].(map[string]any)
+	assert.Len(t, paths, len(restEndpoints))
+	for _, endpoint := range restEndpoints {
+		assert.Contains(t, paths, endpoint.path)
+	}
+
+	schemas := spec["components"].(map[string]any)["schemas"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"repository": map[string]any{"type": "string"},
+			"commit":     map[string]any{"type": "string"},
+			"path":       map[string]any{"type": "string"},
+			"range":      map[string]any{"$ref": "#/components/schemas/Range"},
+		},
+		"required": []string{"repository", "commit", "path"},
+	}, schemas["Location"])
+
+	// Every reference must resolve to a schema.
+	var checkRefs func(v any)
+	checkRefs = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok {
+				assert.Contains(t, schemas, strings.TrimPrefix(ref, "#/components/schemas/"))
+			}
+			for _, child := range v {
+				checkRefs(child)
+			}
+		case []any:
+			for _, child := range v {
+				checkRefs(child)
+			}
+		}
+	}
+	checkRefs(spec)
+}
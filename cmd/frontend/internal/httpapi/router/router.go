@@ -8,12 +8,22 @@ import (
 )
 
 const (
-	GraphQL = "graphql"
+	GraphQL              = "graphql"
+	GraphQLSubscriptions = "graphql.subscriptions"
 
 	LSIFUpload       = "lsif.upload"
 	SCIPUpload       = "scip.upload"
 	SCIPUploadExists = "scip.upload.exists"
 
+	RESTOpenAPI      = "rest.openapi"
+	RESTRepositories = "rest.repositories"
+	RESTRepository   = "rest.repository"
+	RESTFile         = "rest.file"
+	RESTDefinitions  = "rest.definitions"
+	RESTReferences   = "rest.references"
+	RESTSearch       = "rest.search"
+	RESTCapabilities = "rest.capabilities"
+
 	SearchStream          = "search.stream"
 	ComputeStream         = "compute.stream"
 	GitBlameStream        = "git.blame.stream"
@@ -43,6 +53,14 @@ const (
 
 	CodeInsightsDataExport = "insights.data.export"
 
+	SearchExportDownload = "search.export.download"
+
+	UserDataExportDownload = "users.data-export.download"
+
+	ExecutorArtifactDownload = "executors.artifact.download"
+
+	DebugBundle = "debug-bundle"
+
 	ExternalURL            = "internal.app-url"
 	SendEmail              = "internal.send-email"
 	GitInfoRefs            = "internal.git.info-refs"
@@ -54,7 +72,9 @@ const (
 	StreamingSearch        = "internal.stream-search"
 	RepoRank               = "internal.repo-rank"
 	DocumentRanks          = "internal.document-ranks"
+	RankingSignals         = "internal.ranking-signals"
 	UpdateIndexStatus      = "internal.update-index-status"
+	UpdateIndexPressure    = "internal.update-index-pressure"
 )
 
 // New creates a new API router with route URL pattern definitions but
@@ -69,6 +89,8 @@ func New(base *mux.Router) *mux.Router {
 	addRegistryRoute(base)
 	addSCIMRoute(base)
 	addGraphQLRoute(base)
+	addRESTRoutes(base)
+	base.Path("/graphql/subscriptions").Methods("GET").Name(GraphQLSubscriptions)
 	base.Path("/webhooks/{webhook_uuid}").Methods("POST").Name(Webhooks)
 	base.Path("/github-webhooks").Methods("POST").Name(GitHubWebhooks)
 	base.Path("/gitlab-webhooks").Methods("POST").Name(GitLabWebhooks)
@@ -86,6 +108,10 @@ func New(base *mux.Router) *mux.Router {
 	base.Path("/src-cli/versions/{rest:.*}").Methods("GET", "POST").Name(SrcCliVersionCache)
 	base.Path("/src-cli/{rest:.*}").Methods("GET").Name(SrcCli)
 	base.Path("/insights/export/{id}").Methods("GET").Name(CodeInsightsDataExport)
+	base.Path("/search/export/{id}").Methods("GET").Name(SearchExportDownload)
+	base.Path("/users/data-export/{id}").Methods("GET").Name(UserDataExportDownload)
+	base.Path("/executors/artifacts/{id}").Methods("GET").Name(ExecutorArtifactDownload)
+	base.Path("/debug-bundles/{id}").Methods("GET").Name(DebugBundle)
 	base.Path("/completions/stream").Methods("POST").Name(ChatCompletionsStream)
 	base.Path("/completions/code").Methods("POST").Name(CodeCompletions)
 
@@ -117,9 +143,11 @@ func NewInternal(base *mux.Router) *mux.Router {
 	base.Path("/repos/index").Methods("POST").Name(ReposIndex)
 	base.Path("/configuration").Methods("POST").Name(Configuration)
 	base.Path("/ranks/{RepoName:.*}/documents").Methods("GET").Name(DocumentRanks)
+	base.Path("/ranks/{RepoName:.*}/signals").Methods("GET").Name(RankingSignals)
 	base.Path("/ranks/{RepoName:.*}").Methods("GET").Name(RepoRank)
 	base.Path("/search/configuration").Methods("GET", "POST").Name(SearchConfiguration)
 	base.Path("/search/index-status").Methods("POST").Name(UpdateIndexStatus)
+	base.Path("/search/index-pressure").Methods("POST").Name(UpdateIndexPressure)
 	base.Path("/telemetry").Methods("POST").Name(Telemetry)
 	base.Path("/lsif/upload").Methods("POST").Name(LSIFUpload)
 	base.Path("/scip/upload").Methods("POST").Name(SCIPUpload)
@@ -143,3 +171,19 @@ func addSCIMRoute(m *mux.Router) {
 func addGraphQLRoute(m *mux.Router) {
 	m.Path("/graphql").Methods("POST").Name(GraphQL)
 }
+
+// addRESTRoutes adds the routes of the versioned public REST API.
+func addRESTRoutes(m *mux.Router) {
+	v1 := m.PathPrefix("/v1").Subrouter()
+	v1.Path("/openapi.json").Methods("GET").Name(RESTOpenAPI)
+	v1.Path("/repos").Methods("GET").Name(RESTRepositories)
+	v1.Path("/repos/" + routevar.Repo).Methods("GET").Name(RESTRepository)
+
+	repoRev := "/repos/" + routevar.Repo + routevar.RepoRevSuffix + "/" + routevar.RepoPathDelim
+	v1.Path(repoRev + "/files/{Path:.*}").Methods("GET").Name(RESTFile)
+	v1.Path(repoRev + "/definitions/{Path:.*}").Methods("GET").Name(RESTDefinitions)
+	v1.Path(repoRev + "/references/{Path:.*}").Methods("GET").Name(RESTReferences)
+
+	v1.Path("/search").Methods("GET").Name(RESTSearch)
+	v1.Path("/capabilities").Methods("GET").Name(RESTCapabilities)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
 	"github.com/sourcegraph/sourcegraph/internal/types"
@@ -162,6 +163,21 @@ type searchIndexerServer struct {
 
 	SearchContextsRepoRevs func(context.Context, []api.RepoID) (map[api.RepoID][]string, error)
 
+	// ArchivedRepoIDs returns the set of the repositories archived by the
+	// archival policy, which are indexed as archived. The returned map must not
+	// be modified. It is optional.
+	ArchivedRepoIDs func(context.Context) (map[api.RepoID]struct{}, error)
+
+	// IndexAdmission postpones indexing large repositories while the
+	// indexserver requesting their configuration is under memory or disk
+	// pressure. It is optional.
+	IndexAdmission *searchbackend.IndexAdmission
+
+	// RepoSizes returns the gitserver state of repositories, whose size is
+	// used to estimate the cost of indexing them. It is required if
+	// IndexAdmission is set.
+	RepoSizes func(context.Context, ...api.RepoName) (map[api.RepoName]*types.GitserverRepo, error)
+
 	// Indexers is the subset of searchbackend.Indexers methods we
 	// use. reposListServer is used by indexed-search to get the list of
 	// repositories to index. These methods are used to return the correct
@@ -218,6 +234,8 @@ func (h *searchIndexerServer) serveConfiguration(w http.ResponseWriter, r *http.
 	response, err := h.doSearchConfiguration(ctx, searchConfigurationParameters{
 		repoIDs:     indexedIDs,
 		fingerprint: clientFingerprint,
+		// Only sent by indexservers that report their pressure.
+		hostname: r.Form.Get("hostname"),
 	})
 
 	if err != nil {
@@ -306,6 +324,16 @@ func (h *searchIndexerServer) doSearchConfiguration(ctx context.Context, paramet
 		rankingLastUpdatedAt = make(map[api.RepoID]time.Time)
 	}
 
+	var archivedRepoIDs map[api.RepoID]struct{}
+	if h.ArchivedRepoIDs != nil {
+		archivedRepoIDs, err = h.ArchivedRepoIDs(ctx)
+		if err != nil {
+			h.logger.Warn("failed to list archived repositories, falling back to code host archived state", log.Error(err))
+		}
+	}
+
+	admissionControl, pressure, repoSizes := h.indexAdmissionState(ctx, &siteConfig, parameters.hostname, repos)
+
 	getRepoIndexOptions := func(repoID api.RepoID) (*searchbackend.RepoIndexOptions, error) {
 		if loadReposErr != nil {
 			return nil, loadReposErr
@@ -316,6 +344,14 @@ func (h *searchIndexerServer) doSearchConfiguration(ctx context.Context, paramet
 			return nil, &database.RepoNotFoundErr{ID: repoID}
 		}
 
+		// The indexserver keeps serving the existing index of a postponed
+		// repository and retries indexing it later.
+		if pressure != nil {
+			if admitted, reason := h.IndexAdmission.Admit(admissionControl, pressure, repoID, repoSizes[repo.Name]); !admitted {
+				return nil, errors.Newf("indexing postponed: %s", reason)
+			}
+		}
+
 		getVersion := func(branch string) (string, error) {
 			metricGetVersion.Inc()
 			// Do not to trigger a repo-updater lookup since this is a batch job.
@@ -337,13 +373,24 @@ func (h *searchIndexerServer) doSearchConfiguration(ctx context.Context, paramet
 			documentRanksVersion = t.String()
 		}
 
+		// Index the open changes of Gerrit projects as virtual branches.
+		var revisions []string
+		if p, ok := repo.Metadata.(*gerrit.Project); ok {
+			for _, c := range p.OpenChanges {
+				revisions = append(revisions, c.Ref)
+			}
+		}
+
+		_, archived := archivedRepoIDs[repoID]
+
 		return &searchbackend.RepoIndexOptions{
 			Name:       string(repo.Name),
 			RepoID:     repo.ID,
 			Public:     !repo.Private,
 			Priority:   priority,
 			Fork:       repo.Fork,
-			Archived:   repo.Archived,
+			Archived:   repo.Archived || archived,
+			Revisions:  revisions,
 			GetVersion: getVersion,
 
 			DocumentRanksVersion: documentRanksVersion,
@@ -371,6 +418,37 @@ func (h *searchIndexerServer) doSearchConfiguration(ctx context.Context, paramet
 	}, nil
 }
 
+// indexAdmissionState returns the state needed to decide whether the
+// indexserver with the given hostname may index repos now. The returned
+// pressure is nil if admission control does not apply.
+func (h *searchIndexerServer) indexAdmissionState(ctx context.Context, siteConfig *schema.SiteConfiguration, hostname string, repos []*types.Repo) (*schema.SearchIndexAdmissionControl, *searchbackend.IndexPressure, map[api.RepoName]int64) {
+	c := siteConfig.SearchIndexAdmissionControl
+	if h.IndexAdmission == nil || c == nil || !c.Enabled || hostname == "" {
+		return c, nil, nil
+	}
+	pressure, ok := h.IndexAdmission.Pressure(hostname)
+	if !ok {
+		return c, nil, nil
+	}
+
+	names := make([]api.RepoName, 0, len(repos))
+	for _, repo := range repos {
+		names = append(names, repo.Name)
+	}
+	gitserverRepos, err := h.RepoSizes(ctx, names...)
+	if err != nil {
+		// Without the sizes of the repositories we cannot tell large from small
+		// repositories, so we rather admit all of them.
+		h.logger.Warn("failed to get repository sizes, admitting all repositories", log.Error(err))
+		return c, nil, nil
+	}
+	sizes := make(map[api.RepoName]int64, len(gitserverRepos))
+	for name, gr := range gitserverRepos {
+		sizes[name] = gr.RepoSizeBytes
+	}
+	return c, pressure, sizes
+}
+
 type parameterError struct {
 	err string
 }
@@ -380,6 +458,10 @@ func (e *parameterError) Error() string { return e.err }
 type searchConfigurationParameters struct {
 	repoIDs     []api.RepoID
 	fingerprint searchbackend.ConfigFingerprint
+	// hostname is the hostname of the indexserver requesting the
+	// configuration. It is empty for indexservers that do not report their
+	// pressure.
+	hostname string
 }
 
 type searchConfigurationResponse struct {
@@ -464,6 +546,15 @@ func (h *searchIndexerServer) serveDocumentRanks(w http.ResponseWriter, r *http.
 	return serveRank(h.Ranking.GetDocumentRanks, w, r)
 }
 
+// serveRankingSignals streams the per-document and per-symbol ranking signals of the
+// given repository as newline-delimited JSON.
+func (h *searchIndexerServer) serveRankingSignals(w http.ResponseWriter, r *http.Request) error {
+	repoName := api.RepoName(mux.Vars(r)["RepoName"])
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	return h.Ranking.ExportRankingSignals(r.Context(), repoName, w)
+}
+
 func serveRank[T []float64 | citypes.RepoPathRanks](
 	f func(ctx context.Context, name api.RepoName) (r T, err error),
 	w http.ResponseWriter,
@@ -516,6 +607,24 @@ func (h *searchIndexerServer) doIndexStatusUpdate(ctx context.Context, args *ind
 	return h.db.ZoektRepos().UpdateIndexStatuses(ctx, minimal)
 }
 
+// handleIndexPressureUpdate records the memory and disk pressure reported by
+// an indexserver, see searchbackend.IndexAdmission.
+func (h *searchIndexerServer) handleIndexPressureUpdate(_ http.ResponseWriter, r *http.Request) error {
+	if h.IndexAdmission == nil {
+		return nil
+	}
+
+	var pressure searchbackend.IndexPressure
+	if err := json.NewDecoder(r.Body).Decode(&pressure); err != nil {
+		return errors.Wrap(err, "failed to decode request args")
+	}
+	if pressure.Hostname == "" {
+		return errors.New("hostname is required")
+	}
+
+	return h.IndexAdmission.ReportPressure(pressure)
+}
+
 type indexStatusUpdateArgs struct {
 	Repositories []indexStatusUpdateRepository
 }
@@ -16,6 +16,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/gorilla/mux"
 	"github.com/sourcegraph/log/logtest"
 	"github.com/sourcegraph/zoekt"
 	"google.golang.org/protobuf/testing/protocmp"
@@ -456,6 +457,13 @@ func (*fakeRankingService) GetRepoRank(ctx context.Context, repoName api.RepoNam
 func (*fakeRankingService) GetDocumentRanks(ctx context.Context, repoName api.RepoName) (_ citypes.RepoPathRanks, err error) {
 	return citypes.RepoPathRanks{}, nil
 }
+func (*fakeRankingService) ExportRankingSignals(ctx context.Context, repoName api.RepoName, w io.Writer) error {
+	if repoName == "missing" {
+		return errors.New("no ranking signals")
+	}
+	_, err := io.WriteString(w, `{"type":"document","repository":"`+string(repoName)+`","path":"main.go","referenceCount":1}`+"\n")
+	return err
+}
 
 // suffixIndexers mocks Indexers. ReposSubset will return all repoNames with
 // the suffix of hostname.
@@ -509,6 +517,31 @@ func TestRepoRankFromConfig(t *testing.T) {
 	}
 }
 
+func TestServeRankingSignals(t *testing.T) {
+	srv := &searchIndexerServer{Ranking: &fakeRankingService{}}
+
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/", nil), map[string]string{"RepoName": "github.com/sourcegraph/sourcegraph"})
+	w := httptest.NewRecorder()
+
+	if err := srv.serveRankingSignals(w, req); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := w.Result()
+	if have, want := resp.Header.Get("Content-Type"), "application/x-ndjson"; have != want {
+		t.Errorf("unexpected content type. want=%q have=%q", want, have)
+	}
+	want := `{"type":"document","repository":"github.com/sourcegraph/sourcegraph","path":"main.go","referenceCount":1}` + "\n"
+	if diff := cmp.Diff(want, w.Body.String()); diff != "" {
+		t.Errorf("unexpected body (-want +got):\n%s", diff)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/", nil), map[string]string{"RepoName": "missing"})
+	if err := srv.serveRankingSignals(httptest.NewRecorder(), req); err == nil {
+		t.Fatal("expected an error for a repository without ranking signals")
+	}
+}
+
 func TestIndexStatusUpdate(t *testing.T) {
 
 	t.Run("REST", func(t *testing.T) {
@@ -5,6 +5,7 @@ import (
 	"html"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
@@ -29,6 +30,9 @@ import (
 // git blame with the --incremental flag. It will stream back to the client the most
 // recent hunks first and will gradually reach the oldests, or not if we timeout
 // before that.
+//
+// The optional startLine and endLine query parameters limit the blame to the given
+// 1-indexed, inclusive line range.
 func handleStreamBlame(logger log.Logger, db database.DB, gitserverClient gitserver.Client) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		flags := featureflag.FromContext(r.Context())
@@ -61,6 +65,12 @@ func handleStreamBlame(logger log.Logger, db database.DB, gitserverClient gitser
 			return
 		}
 
+		startLine, endLine, err := parseBlameLineRange(r)
+		if err != nil {
+			http.Error(w, html.EscapeString(err.Error()), http.StatusBadRequest)
+			return
+		}
+
 		requestedPath := mux.Vars(r)["Path"]
 		streamWriter, err := streamhttp.NewWriter(w)
 		if err != nil {
@@ -85,6 +95,8 @@ func handleStreamBlame(logger log.Logger, db database.DB, gitserverClient gitser
 
 		hunkReader, err := gitserverClient.StreamBlameFile(r.Context(), authz.DefaultSubRepoPermsChecker, repo.Name, requestedPath, &gitserver.BlameOptions{
 			NewestCommit: commitID,
+			StartLine:    startLine,
+			EndLine:      endLine,
 		})
 		if err != nil {
 			tr.SetError(err)
@@ -168,6 +180,30 @@ func handleStreamBlame(logger log.Logger, db database.DB, gitserverClient gitser
 	}
 }
 
+// parseBlameLineRange returns the line range requested in the query parameters of r,
+// which are zero if not set.
+func parseBlameLineRange(r *http.Request) (startLine, endLine int, err error) {
+	q := r.URL.Query()
+	for _, p := range []struct {
+		name  string
+		value *int
+	}{{"startLine", &startLine}, {"endLine", &endLine}} {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return 0, 0, errors.Newf("invalid %s %q", p.name, v)
+		}
+		*p.value = n
+	}
+	if endLine != 0 && endLine < startLine {
+		return 0, 0, errors.Newf("endLine %d is before startLine %d", endLine, startLine)
+	}
+	return startLine, endLine, nil
+}
+
 type BlameHunkResponse struct {
 	api.CommitID `json:"commitID"`
 
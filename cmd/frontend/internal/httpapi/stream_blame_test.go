@@ -233,6 +233,43 @@ func TestStreamBlame(t *testing.T) {
 		assert.Contains(t, data, `"url":"github.com/bob/foo/-/commit/efgh"`)
 	})
 
+	t.Run("OK line range", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodGet, "/?startLine=10&endLine=20", nil)
+		require.NoError(t, err)
+		req = req.WithContext(ctx)
+
+		req = mux.SetURLVars(req, map[string]string{
+			"Repo": "github.com/bob/foo",
+			"path": "foo.c",
+		})
+		gsClient := gitserver.NewMockClient()
+		gsClient.GetCommitFunc.SetDefaultReturn(&gitdomain.Commit{}, nil)
+		gsClient.StreamBlameFileFunc.SetDefaultReturn(gitserver.NewMockHunkReader(nil, nil), nil)
+		handleStreamBlame(logger, db, gsClient).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		opts := gsClient.StreamBlameFileFunc.History()[0].Arg4
+		assert.Equal(t, 10, opts.StartLine)
+		assert.Equal(t, 20, opts.EndLine)
+	})
+
+	t.Run("NOK invalid line range", func(t *testing.T) {
+		for _, query := range []string{"startLine=foo", "startLine=0", "startLine=20&endLine=10"} {
+			rec := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodGet, "/?"+query, nil)
+			require.NoError(t, err)
+			req = req.WithContext(ctx)
+
+			req = mux.SetURLVars(req, map[string]string{
+				"Repo": "github.com/bob/foo",
+				"path": "foo.c",
+			})
+			gsClient := setupMockGSClient(t, "abcd", nil, hunks)
+			handleStreamBlame(logger, db, gsClient).ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+
 	t.Run("NOK err reading hunks", func(t *testing.T) {
 		rec := httptest.NewRecorder()
 		req, err := http.NewRequest(http.MethodGet, "/", nil)
@@ -29,6 +29,10 @@ func (e *eventWriter) MatchesJSON(data []byte) error {
 	return e.inner.EventBytes("matches", data)
 }
 
+func (e *eventWriter) GroupsJSON(data []byte) error {
+	return e.inner.EventBytes("groups", data)
+}
+
 func (e *eventWriter) Filters(fs []*streaming.Filter) error {
 	if len(fs) > 0 {
 		buf := make([]streamhttp.EventFilter, 0, len(fs))
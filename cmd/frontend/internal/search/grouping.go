@@ -0,0 +1,177 @@
+package search
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// groupMode is the server-side grouping requested by the client. The zero
+// value disables grouping and matches are streamed as they are found.
+type groupMode string
+
+const (
+	groupNone   groupMode = ""
+	groupRepo   groupMode = "repo"
+	groupFile   groupMode = "file"
+	groupSymbol groupMode = "symbol"
+)
+
+func parseGroupMode(s string) (groupMode, error) {
+	switch m := groupMode(s); m {
+	case groupNone, groupRepo, groupFile, groupSymbol:
+		return m, nil
+	}
+	return groupNone, errors.Errorf("group must be one of repo, file or symbol, got %q", s)
+}
+
+// matchDeduper drops matches which are identical to a match already seen at
+// another revision of the same repository. The first revision seen wins.
+type matchDeduper struct {
+	seen map[dedupKey]struct{}
+}
+
+type dedupKey struct {
+	key         result.Key
+	fingerprint uint64
+}
+
+func newMatchDeduper() *matchDeduper {
+	return &matchDeduper{seen: map[dedupKey]struct{}{}}
+}
+
+// Dedup returns the matches which have not been seen before.
+func (d *matchDeduper) Dedup(matches result.Matches) result.Matches {
+	out := make(result.Matches, 0, len(matches))
+	for _, match := range matches {
+		k := dedupKey{key: match.Key()}
+		k.key.Rev = ""
+		if fm, ok := match.(*result.FileMatch); ok {
+			// The same file can have different commits at different
+			// revisions, so compare the content of the match instead.
+			k.key.Commit = ""
+			k.fingerprint = fileMatchFingerprint(fm)
+		}
+
+		if _, ok := d.seen[k]; ok {
+			continue
+		}
+		d.seen[k] = struct{}{}
+		out = append(out, match)
+	}
+	return out
+}
+
+// fileMatchFingerprint hashes everything about a file match that is sent to
+// the client, except for the revision it was found at.
+func fileMatchFingerprint(fm *result.FileMatch) uint64 {
+	h := fnv.New64a()
+	writeRanges := func(rs result.Ranges) {
+		for _, r := range rs {
+			fmt.Fprintf(h, "%d:%d-%d:%d;", r.Start.Line, r.Start.Column, r.End.Line, r.End.Column)
+		}
+	}
+
+	for _, cm := range fm.ChunkMatches {
+		fmt.Fprintf(h, "%d\x00", cm.ContentStart.Line)
+		io.WriteString(h, cm.Content)
+		h.Write([]byte{0})
+		writeRanges(cm.Ranges)
+	}
+	h.Write([]byte{0})
+	writeRanges(fm.PathMatches)
+	h.Write([]byte{0})
+	for _, sym := range fm.Symbols {
+		fmt.Fprintf(h, "%s\x00%s\x00%d;", sym.Symbol.Name, sym.Symbol.Kind, sym.Symbol.Line)
+	}
+	return h.Sum64()
+}
+
+// matchGrouper collects matches into groups, in the order in which the first
+// match of each group was seen.
+type matchGrouper struct {
+	mode   groupMode
+	groups []*streamhttp.EventGroup
+	index  map[groupKey]*streamhttp.EventGroup
+}
+
+type groupKey struct {
+	repoID     api.RepoID
+	path       string
+	symbol     string
+	symbolKind string
+}
+
+func newMatchGrouper(mode groupMode) *matchGrouper {
+	return &matchGrouper{
+		mode:  mode,
+		index: map[groupKey]*streamhttp.EventGroup{},
+	}
+}
+
+// Add adds match to its group. eventMatch is the event for match.
+func (g *matchGrouper) Add(match result.Match, eventMatch streamhttp.EventMatch) {
+	repo := match.RepoName()
+	fm, isFile := match.(*result.FileMatch)
+
+	if g.mode == groupSymbol {
+		if symbolMatch, ok := eventMatch.(*streamhttp.EventSymbolMatch); ok {
+			// Symbols are grouped across repositories, so that all the
+			// definitions of a symbol end up in the same group.
+			for _, sym := range symbolMatch.Symbols {
+				single := *symbolMatch
+				single.Symbols = []streamhttp.Symbol{sym}
+
+				group := g.group(groupKey{symbol: sym.Name, symbolKind: sym.Kind}, func() *streamhttp.EventGroup {
+					return &streamhttp.EventGroup{
+						Type:       string(groupSymbol),
+						Symbol:     sym.Name,
+						SymbolKind: sym.Kind,
+					}
+				})
+				group.MatchCount++
+				group.Matches = append(group.Matches, &single)
+			}
+			return
+		}
+	}
+
+	key := groupKey{repoID: repo.ID}
+	if g.mode != groupRepo && isFile {
+		key.path = fm.Path
+	}
+	group := g.group(key, func() *streamhttp.EventGroup {
+		group := &streamhttp.EventGroup{
+			Type:         string(groupRepo),
+			Repository:   string(repo.Name),
+			RepositoryID: int32(repo.ID),
+		}
+		if key.path != "" {
+			group.Type = string(groupFile)
+			group.Path = key.path
+		}
+		return group
+	})
+	group.MatchCount += match.ResultCount()
+	group.Matches = append(group.Matches, eventMatch)
+}
+
+func (g *matchGrouper) group(key groupKey, create func() *streamhttp.EventGroup) *streamhttp.EventGroup {
+	group, ok := g.index[key]
+	if !ok {
+		group = create()
+		g.index[key] = group
+		g.groups = append(g.groups, group)
+	}
+	return group
+}
+
+// Groups returns the groups in the order they were created.
+func (g *matchGrouper) Groups() []*streamhttp.EventGroup {
+	return g.groups
+}
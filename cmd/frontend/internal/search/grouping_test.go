@@ -0,0 +1,155 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+func TestParseGroupMode(t *testing.T) {
+	for _, s := range []string{"", "repo", "file", "symbol"} {
+		m, err := parseGroupMode(s)
+		require.NoError(t, err)
+		require.Equal(t, groupMode(s), m)
+	}
+
+	_, err := parseGroupMode("commit")
+	require.ErrorContains(t, err, `got "commit"`)
+}
+
+func TestMatchDeduper(t *testing.T) {
+	repo := types.MinimalRepo{ID: 1, Name: "repo"}
+	fileMatch := func(rev, commit, content string) *result.FileMatch {
+		return &result.FileMatch{
+			File: result.File{
+				Repo:     repo,
+				InputRev: pointers.Ptr(rev),
+				CommitID: api.CommitID(commit),
+				Path:     "a.go",
+			},
+			ChunkMatches: result.ChunkMatches{{
+				Content: content,
+				Ranges:  result.Ranges{{Start: result.Location{Column: 0}, End: result.Location{Column: 3}}},
+			}},
+		}
+	}
+
+	onMain := fileMatch("main", "c1", "foo")
+	sameAtBranch := fileMatch("branch", "c2", "foo")
+	changedAtBranch := fileMatch("branch", "c2", "foo bar")
+	repoMain := &result.RepoMatch{ID: repo.ID, Name: repo.Name, Rev: "main"}
+	repoBranch := &result.RepoMatch{ID: repo.ID, Name: repo.Name, Rev: "branch"}
+
+	d := newMatchDeduper()
+	require.Equal(t, result.Matches{onMain, repoMain}, d.Dedup(result.Matches{onMain, repoMain}))
+	require.Equal(t, result.Matches{changedAtBranch}, d.Dedup(result.Matches{sameAtBranch, changedAtBranch, repoBranch}))
+}
+
+func TestMatchGrouper(t *testing.T) {
+	repo1 := types.MinimalRepo{ID: 1, Name: "repo1"}
+	repo2 := types.MinimalRepo{ID: 2, Name: "repo2"}
+
+	contentMatch := func(repo types.MinimalRepo, path string) *result.FileMatch {
+		return &result.FileMatch{
+			File: result.File{Repo: repo, Path: path},
+			ChunkMatches: result.ChunkMatches{{
+				Content: "foo",
+				Ranges:  result.Ranges{{Start: result.Location{Column: 0}, End: result.Location{Column: 3}}},
+			}},
+		}
+	}
+	symbolMatch := func(repo types.MinimalRepo, path string, names ...string) *result.FileMatch {
+		fm := &result.FileMatch{File: result.File{Repo: repo, Path: path}}
+		for _, name := range names {
+			fm.Symbols = append(fm.Symbols, &result.SymbolMatch{
+				Symbol: result.Symbol{Name: name, Kind: "function"},
+				File:   &fm.File,
+			})
+		}
+		return fm
+	}
+
+	matches := result.Matches{
+		contentMatch(repo1, "a.go"),
+		contentMatch(repo2, "a.go"),
+		contentMatch(repo1, "b.go"),
+		&result.RepoMatch{ID: repo1.ID, Name: repo1.Name},
+		symbolMatch(repo2, "c.go", "Foo", "Bar"),
+		symbolMatch(repo1, "d.go", "Foo"),
+	}
+
+	type group struct {
+		Type       string
+		Repository string
+		Path       string
+		Symbol     string
+		MatchCount int
+		Matches    int
+	}
+
+	cases := []struct {
+		mode groupMode
+		want []group
+	}{{
+		mode: groupRepo,
+		want: []group{
+			{Type: "repo", Repository: "repo1", MatchCount: 4, Matches: 4},
+			{Type: "repo", Repository: "repo2", MatchCount: 3, Matches: 2},
+		},
+	}, {
+		mode: groupFile,
+		want: []group{
+			{Type: "file", Repository: "repo1", Path: "a.go", MatchCount: 1, Matches: 1},
+			{Type: "file", Repository: "repo2", Path: "a.go", MatchCount: 1, Matches: 1},
+			{Type: "file", Repository: "repo1", Path: "b.go", MatchCount: 1, Matches: 1},
+			{Type: "repo", Repository: "repo1", MatchCount: 1, Matches: 1},
+			{Type: "file", Repository: "repo2", Path: "c.go", MatchCount: 2, Matches: 1},
+			{Type: "file", Repository: "repo1", Path: "d.go", MatchCount: 1, Matches: 1},
+		},
+	}, {
+		mode: groupSymbol,
+		want: []group{
+			{Type: "file", Repository: "repo1", Path: "a.go", MatchCount: 1, Matches: 1},
+			{Type: "file", Repository: "repo2", Path: "a.go", MatchCount: 1, Matches: 1},
+			{Type: "file", Repository: "repo1", Path: "b.go", MatchCount: 1, Matches: 1},
+			{Type: "repo", Repository: "repo1", MatchCount: 1, Matches: 1},
+			{Type: "symbol", Symbol: "Foo", MatchCount: 2, Matches: 2},
+			{Type: "symbol", Symbol: "Bar", MatchCount: 1, Matches: 1},
+		},
+	}}
+
+	for _, tc := range cases {
+		t.Run(string(tc.mode), func(t *testing.T) {
+			g := newMatchGrouper(tc.mode)
+			for _, match := range matches {
+				g.Add(match, fromMatch(match, nil, true))
+			}
+
+			var got []group
+			for _, eg := range g.Groups() {
+				got = append(got, group{
+					Type:       eg.Type,
+					Repository: eg.Repository,
+					Path:       eg.Path,
+					Symbol:     eg.Symbol,
+					MatchCount: eg.MatchCount,
+					Matches:    len(eg.Matches),
+				})
+			}
+			require.Equal(t, tc.want, got)
+
+			if tc.mode == groupSymbol {
+				// Each match in a symbol group contains only that symbol.
+				for _, m := range g.Groups()[4].Matches {
+					require.Len(t, m.(*streamhttp.EventSymbolMatch).Symbols, 1)
+				}
+			}
+		})
+	}
+}
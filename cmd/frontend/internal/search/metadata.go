@@ -5,6 +5,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -17,7 +18,9 @@ func getEventRepoMetadata(ctx context.Context, db database.DB, event streaming.S
 		return nil, nil
 	}
 
-	metadataList, err := db.Repos().Metadata(ctx, ids...)
+	// The metadata of search results tolerates stale results and may be served
+	// by a read replica.
+	metadataList, err := db.Repos().Metadata(basestore.WithStaleReads(ctx), ids...)
 	if err != nil {
 		return nil, errors.Wrap(err, "fetch metadata from db")
 	}
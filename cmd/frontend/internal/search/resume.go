@@ -0,0 +1,58 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/grafana/regexp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	streamapi "github.com/sourcegraph/sourcegraph/internal/search/streaming/api"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// resumeInputs restricts inputs to the repositories captured by a resume
+// token. Repositories the current user cannot access are dropped.
+func resumeInputs(ctx context.Context, db database.DB, inputs *search.Inputs, token *streamapi.ResumeToken) (*search.Inputs, error) {
+	repos, err := db.Repos().GetReposSetByIDs(ctx, token.Repos...)
+	if err != nil {
+		return nil, err
+	}
+	if len(repos) == 0 {
+		return nil, errors.New("none of the repositories in the resume token are accessible")
+	}
+
+	names := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		names = append(names, string(repo.Name))
+	}
+
+	resumed := *inputs
+	resumed.Plan = restrictToRepos(inputs.Plan, names)
+	resumed.Query = resumed.Plan.ToQ()
+	return &resumed, nil
+}
+
+// restrictToRepos returns a copy of plan in which every query is limited to
+// the repositories with the given names.
+func restrictToRepos(plan query.Plan, names []string) query.Plan {
+	sorted := make([]string, 0, len(names))
+	for _, name := range names {
+		sorted = append(sorted, regexp.QuoteMeta(name))
+	}
+	sort.Strings(sorted)
+	repoParam := query.Parameter{
+		Field: query.FieldRepo,
+		Value: "^(?:" + strings.Join(sorted, "|") + ")$",
+	}
+
+	restricted := make(query.Plan, 0, len(plan))
+	for _, basic := range plan {
+		parameters := append(append(make([]query.Parameter, 0, len(basic.Parameters)+1), basic.Parameters...), repoParam)
+		restricted = append(restricted, basic.MapParameters(parameters))
+	}
+	return restricted
+}
@@ -0,0 +1,39 @@
+package search
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api2 "github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming/api"
+)
+
+func TestRestrictToRepos(t *testing.T) {
+	plan, err := query.Pipeline(query.InitRegexp("foo or repo:bar baz"))
+	require.NoError(t, err)
+
+	restricted := restrictToRepos(plan, []string{"github.com/b/b", "github.com/a/a"})
+	require.Len(t, restricted, len(plan))
+	for _, basic := range restricted {
+		require.Contains(t, basic.StringHuman(), `repo:^(?:github\.com/a/a|github\.com/b/b)$`)
+	}
+	// The original plan is not modified.
+	require.NotContains(t, plan[0].StringHuman(), "github")
+}
+
+func TestParseURLQueryResume(t *testing.T) {
+	token := api.NewResumeToken("foo", []api2.RepoID{1})
+
+	a, err := parseURLQuery(url.Values{"q": {"foo"}, "resume": {token}})
+	require.NoError(t, err)
+	require.Equal(t, []api2.RepoID{1}, a.Resume.Repos)
+
+	_, err = parseURLQuery(url.Values{"q": {"bar"}, "resume": {token}})
+	require.Error(t, err)
+
+	_, err = parseURLQuery(url.Values{"q": {"foo"}, "resume": {"garbage"}})
+	require.Error(t, err)
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	streamapi "github.com/sourcegraph/sourcegraph/internal/search/streaming/api"
 	streamclient "github.com/sourcegraph/sourcegraph/internal/search/streaming/client"
 	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
@@ -96,6 +97,9 @@ func (h *streamHandler) serveHTTP(r *http.Request, tr *trace.Trace, eventWriter
 		attribute.String("version", args.Version),
 		attribute.String("pattern_type", args.PatternType),
 		attribute.Int("search_mode", args.SearchMode),
+		attribute.String("group", string(args.Group)),
+		attribute.Bool("dedup", args.Dedup),
+		attribute.Bool("resume", args.Resume != nil),
 	)
 
 	inputs, err := h.searchClient.Plan(
@@ -116,6 +120,13 @@ func (h *streamHandler) serveHTTP(r *http.Request, tr *trace.Trace, eventWriter
 		}
 	}
 
+	if args.Resume != nil {
+		inputs, err = resumeInputs(ctx, h.db, inputs, args.Resume)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Display is the number of results we send down. If display is < 0 we
 	// want to send everything we find before hitting a limit. Otherwise we
 	// can only send up to limit results.
@@ -127,6 +138,7 @@ func (h *streamHandler) serveHTTP(r *http.Request, tr *trace.Trace, eventWriter
 
 	progress := &streamclient.ProgressAggregator{
 		Start:        start,
+		Query:        args.Query,
 		Limit:        limit,
 		Trace:        trace.URL(trace.ID(ctx), conf.DefaultClient()),
 		DisplayLimit: displayLimit,
@@ -161,6 +173,8 @@ func (h *streamHandler) serveHTTP(r *http.Request, tr *trace.Trace, eventWriter
 			h.pingTickerInterval,
 			displayLimit,
 			args.EnableChunkMatches,
+			args.Group,
+			args.Dedup,
 			logLatency,
 		)
 		defer eventHandler.Done()
@@ -217,6 +231,9 @@ type args struct {
 	Display            int
 	EnableChunkMatches bool
 	SearchMode         int
+	Group              groupMode
+	Dedup              bool
+	Resume             *streamapi.ResumeToken
 }
 
 func parseURLQuery(q url.Values) (*args, error) {
@@ -254,6 +271,24 @@ func parseURLQuery(q url.Values) (*args, error) {
 		return nil, errors.Errorf("search mode must be integer, got %q: %w", searchMode, err)
 	}
 
+	if a.Group, err = parseGroupMode(get("group", "")); err != nil {
+		return nil, err
+	}
+
+	dedup := get("dedup", "f")
+	if a.Dedup, err = strconv.ParseBool(dedup); err != nil {
+		return nil, errors.Errorf("dedup must be parseable as a boolean, got %q: %w", dedup, err)
+	}
+
+	if resume := get("resume", ""); resume != "" {
+		if a.Resume, err = streamapi.ParseResumeToken(resume); err != nil {
+			return nil, err
+		}
+		if a.Resume.Query != a.Query {
+			return nil, errors.New("resume token was issued for a different query")
+		}
+	}
+
 	return &a, nil
 }
 
@@ -387,6 +422,10 @@ func fromContentMatch(fm *result.FileMatch, repoCache map[api.RepoID]*types.Sear
 		contentEvent.Debug = *fm.Debug
 	}
 
+	if fm.RewriteDiff != nil {
+		contentEvent.RewriteDiff = *fm.RewriteDiff
+	}
+
 	return contentEvent
 }
 
@@ -405,6 +444,8 @@ func fromSymbolMatch(fm *result.FileMatch, repoCache map[api.RepoID]*types.Searc
 			ContainerName: sym.Symbol.Parent,
 			Kind:          kindString,
 			Line:          int32(sym.Symbol.Line),
+			Signature:     sym.Symbol.Signature,
+			Precise:       sym.Symbol.Precise,
 		})
 	}
 
@@ -590,6 +631,8 @@ func newEventHandler(
 	progressInterval time.Duration,
 	displayLimit int,
 	enableChunkMatches bool,
+	group groupMode,
+	dedup bool,
 	logLatency func(),
 ) *eventHandler {
 	// Store marshalled matches and flush periodically or when we go over
@@ -615,6 +658,13 @@ func newEventHandler(
 		logLatency:         logLatency,
 	}
 
+	if dedup {
+		eh.deduper = newMatchDeduper()
+	}
+	if group != groupNone {
+		eh.grouper = newMatchGrouper(group)
+	}
+
 	// Schedule the first flushes.
 	// Lock because if flushInterval is small, scheduled tick could
 	// race with setting eh.flushTimer.
@@ -647,6 +697,13 @@ type eventHandler struct {
 	filters    *streaming.SearchFilters
 	progress   *streamclient.ProgressAggregator
 
+	// deduper is non-nil if matches identical across revisions are dropped.
+	deduper *matchDeduper
+
+	// grouper is non-nil if matches are grouped. Groups are only sent once
+	// the search is done.
+	grouper *matchGrouper
+
 	// These timers will be non-nil unless Done() was called
 	flushTimer    *time.Timer
 	progressTimer *time.Timer
@@ -662,6 +719,10 @@ func (h *eventHandler) Send(event streaming.SearchEvent) {
 	h.progress.Update(event)
 	h.filters.Update(event)
 
+	if h.deduper != nil {
+		event.Results = h.deduper.Dedup(event.Results)
+	}
+
 	h.displayRemaining = event.Results.Limit(h.displayRemaining)
 
 	repoMetadata, err := getEventRepoMetadata(h.ctx, h.db, event)
@@ -683,6 +744,10 @@ func (h *eventHandler) Send(event streaming.SearchEvent) {
 		}
 
 		eventMatch := fromMatch(match, repoMetadata, h.enableChunkMatches)
+		if h.grouper != nil {
+			h.grouper.Add(match, eventMatch)
+			continue
+		}
 		h.matchesBuf.Append(eventMatch)
 	}
 
@@ -709,9 +774,23 @@ func (h *eventHandler) Done() {
 	// Flush the final state
 	h.eventWriter.Filters(h.filters.Compute())
 	h.matchesBuf.Flush()
+	if h.grouper != nil {
+		h.flushGroups()
+	}
 	h.eventWriter.Progress(h.progress.Final())
 }
 
+func (h *eventHandler) flushGroups() {
+	// Groups can be large, so split them over several events like matches.
+	groupsBuf := streamhttp.NewJSONArrayBuf(32*1024, func(data []byte) error {
+		return h.eventWriter.GroupsJSON(data)
+	})
+	for _, group := range h.grouper.Groups() {
+		groupsBuf.Append(group)
+	}
+	groupsBuf.Flush()
+}
+
 func (h *eventHandler) progressTick() {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -2,7 +2,6 @@ package webhooks
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -21,11 +20,9 @@ func (wr *Router) HandleGitLabWebhook(ctx context.Context, logger log.Logger, w
 	// internal actor on the context.
 	ctx = actor.WithInternalActor(ctx)
 
-	var eventKind struct {
-		ObjectKind string `json:"object_kind"`
-	}
-	if err := json.Unmarshal(payload, &eventKind); err != nil {
-		http.Error(w, errors.Wrap(err, "determining object kind").Error(), http.StatusInternalServerError)
+	eventKind, err := webhooks.EventKind(payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
@@ -49,7 +46,7 @@ func (wr *Router) HandleGitLabWebhook(ctx context.Context, logger log.Logger, w
 	}
 
 	// Route the request based on the event type.
-	err = wr.Dispatch(ctx, eventKind.ObjectKind, extsvc.KindGitLab, codeHostURN, event)
+	err = wr.Dispatch(ctx, eventKind, extsvc.KindGitLab, codeHostURN, event)
 	if err != nil {
 		logger.Error("Error handling gitlab webhook event", log.Error(err))
 		if errcode.IsNotFound(err) {
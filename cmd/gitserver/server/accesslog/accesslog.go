@@ -64,6 +64,11 @@ func fromContext(ctx context.Context) *paramsContext {
 	return pc
 }
 
+// RepoOnLegalHold reports whether the given repository is on legal hold. Accesses
+// to repositories on legal hold are always logged, even if access logging is
+// disabled. It is set by gitserver on startup.
+var RepoOnLegalHold = func(ctx context.Context, repo string) bool { return false }
+
 // accessLogger watches the site configuration and logs accesses (if enabled).
 type accessLogger struct {
 	logger log.Logger
@@ -89,12 +94,7 @@ const (
 )
 
 func (a *accessLogger) maybeLog(ctx context.Context) {
-	// If access logging is not enabled, we are done
-	if !a.isEnabled() {
-		return
-	}
-
-	// Otherwise, log this access
+	enabled := a.isEnabled()
 
 	// Now we've gone through the handler, we can get the params that the handler
 	// got from the request body.
@@ -108,6 +108,17 @@ func (a *accessLogger) maybeLog(ctx context.Context) {
 		return
 	}
 
+	// If access logging is not enabled, we are done, unless the repository is
+	// on legal hold.
+	if !enabled {
+		if !RepoOnLegalHold(ctx, repository) {
+			return
+		}
+		metadata = append(metadata, log.Bool("legalHold", true))
+	}
+
+	// Otherwise, log this access
+
 	var fields []log.Field
 
 	if paramsCtx != nil {
@@ -111,6 +111,27 @@ func TestHTTPMiddleware(t *testing.T) {
 		assert.NotEqual(t, accessEventMessage, logs[0].Message)
 	})
 
+	t.Run("disabled, but repo on legal hold", func(t *testing.T) {
+		RepoOnLegalHold = func(_ context.Context, repo string) bool { return repo == "github.com/foo/held" }
+		t.Cleanup(func() { RepoOnLegalHold = func(context.Context, string) bool { return false } })
+
+		logger, exportLogs := logtest.Captured(t)
+		h := HTTPMiddleware(logger, &accessLogConf{disabled: true}, func(w http.ResponseWriter, r *http.Request) {
+			Record(r.Context(), r.URL.Query().Get("repo"), log.String("cmd", "git"), log.String("args", "grep foo"))
+		})
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/?repo=github.com/foo/bar", nil))
+		require.Len(t, exportLogs(), 0)
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/?repo=github.com/foo/held", nil))
+		logs := exportLogs()
+		require.Len(t, logs, 1)
+		assert.Contains(t, logs[0].Message, accessEventMessage)
+		params := logs[0].Fields["params"].(map[string]any)
+		assert.Equal(t, "github.com/foo/held", params["repo"])
+		assert.Equal(t, true, params["legalHold"])
+	})
+
 	t.Run("disabled, then enabled", func(t *testing.T) {
 		logger, exportLogs := logtest.Captured(t)
 		cfg := &accessLogConf{disabled: true}
@@ -61,6 +61,9 @@ const (
 	gitGCModeJanitorAutoGC = 2
 	// gitGCModeMaintenance is when during janitor jobs we run sg maintenance.
 	gitGCModeMaintenance = 3
+	// gitGCModeGitMaintenance is when during janitor jobs we run the git maintenance
+	// tasks that are due according to the size and activity of each repository.
+	gitGCModeGitMaintenance = 4
 )
 
 // gitGCMode describes which mode we should be running git gc.
@@ -76,6 +79,13 @@ var gitGCMode = func() int {
 	// SRC_ENABLE_SG_MAINTENANCE.
 	enableSGMaintenance, _ := strconv.ParseBool(env.Get("SRC_ENABLE_SG_MAINTENANCE", "false", "Use sg maintenance during janitorial cleanup phases"))
 
+	// Scheduled git maintenance replaces both git gc and sg maintenance, so it takes
+	// precedence over them.
+	enableGitMaintenance, _ := strconv.ParseBool(env.Get("SRC_ENABLE_GIT_MAINTENANCE", "false", "Use scheduled git maintenance tasks instead of git gc or sg maintenance during janitorial cleanup phases"))
+	if enableGitMaintenance {
+		return gitGCModeGitMaintenance
+	}
+
 	if enableGCAuto && !enableSGMaintenance {
 		return gitGCModeJanitorAutoGC
 	}
@@ -172,7 +182,8 @@ const reposStatsName = "repos-stats.json"
 // 8. Remove repos based on disk pressure.
 // 9. Perform sg-maintenance
 // 10. Git prune
-// 11. Set sizes of repos
+// 11. Perform scheduled git maintenance
+// 12. Set sizes of repos
 func (s *Server) cleanupRepos(ctx context.Context, gitServerAddrs gitserver.GitserverAddresses) {
 	janitorRunning.Set(1)
 	janitorStart := time.Now()
@@ -197,6 +208,19 @@ func (s *Server) cleanupRepos(ctx context.Context, gitServerAddrs gitserver.Gits
 	bCtx, bCancel := s.serverContext()
 	defer bCancel()
 
+	// The clones of repositories on legal hold must be left untouched. If we
+	// cannot tell which repositories are on legal hold, we leave all of them
+	// untouched.
+	onLegalHold := func(api.RepoName) bool { return true }
+	if held, err := s.reposOnLegalHold(ctx); err != nil {
+		logger.Error("failed to look up repositories on legal hold, skipping destructive cleanups", log.Error(err))
+	} else {
+		onLegalHold = func(name api.RepoName) bool {
+			_, ok := held[name]
+			return ok
+		}
+	}
+
 	stats := protocol.ReposStats{
 		UpdatedAt: time.Now(),
 	}
@@ -232,7 +256,7 @@ func (s *Server) cleanupRepos(ctx context.Context, gitServerAddrs gitserver.Gits
 			wrongShardRepoCount++
 			wrongShardRepoSize += size
 
-			if knownGitServerShard && wrongShardReposDeleteLimit > 0 && wrongShardReposDeleted < int64(wrongShardReposDeleteLimit) {
+			if knownGitServerShard && wrongShardReposDeleteLimit > 0 && wrongShardReposDeleted < int64(wrongShardReposDeleteLimit) && !onLegalHold(name) {
 				logger.Info(
 					"removing repo cloned on the wrong shard",
 					log.String("dir", string(dir)),
@@ -437,27 +461,41 @@ func (s *Server) cleanupRepos(ctx context.Context, gitServerAddrs gitserver.Gits
 		return false, pruneIfNeeded(dir, looseObjectsLimit)
 	}
 
+	var objStats objectStats
+	performGitMaintenance := func(dir common.GitDir) (done bool, err error) {
+		err = gitMaintenance(logger, dir, repoToSize[s.name(dir)])
+		stats, statsErr := gitObjectStats(dir)
+		if statsErr != nil {
+			return false, errors.Append(err, statsErr)
+		}
+		objStats.add(stats)
+		return false, err
+	}
+
 	type cleanupFn struct {
 		Name string
 		Do   func(common.GitDir) (bool, error)
+		// Destructive cleanups remove, re-clone or garbage collect the
+		// repository, so they are skipped for repositories on legal hold.
+		Destructive bool
 	}
 	cleanups := []cleanupFn{
 		// Compute the amount of space used by the repo
-		{"compute stats and delete wrong shard repos", collectSizeAndMaybeDeleteWrongShardRepos},
+		{"compute stats and delete wrong shard repos", collectSizeAndMaybeDeleteWrongShardRepos, false},
 		// Do some sanity checks on the repository.
-		{"maybe remove corrupt", maybeRemoveCorrupt},
+		{"maybe remove corrupt", maybeRemoveCorrupt, true},
 		// Remove repo if DB does not contain it anymore
-		{"maybe remove non existing", maybeRemoveNonExisting},
+		{"maybe remove non existing", maybeRemoveNonExisting, true},
 		// If git is interrupted it can leave lock files lying around. It does not clean
 		// these up, and instead fails commands.
-		{"remove stale locks", removeStaleLocks},
+		{"remove stale locks", removeStaleLocks, false},
 		// We always want to have the same git attributes file at info/attributes.
-		{"ensure git attributes", ensureGitAttributes},
+		{"ensure git attributes", ensureGitAttributes, false},
 		// Enable or disable background garbage collection depending on
 		// gitGCMode. The purpose is to avoid repository corruption which can
 		// happen if several git-gc operations are running at the same time.
 		// We only disable if sg is managing gc.
-		{"auto gc config", ensureAutoGC},
+		{"auto gc config", ensureAutoGC, false},
 	}
 
 	if gitGCMode == gitGCModeJanitorAutoGC {
@@ -466,15 +504,24 @@ func (s *Server) cleanupRepos(ctx context.Context, gitServerAddrs gitserver.Gits
 		// removing unreachable objects which may have been created from prior
 		// invocations of git add, packing refs, pruning reflog, rerere metadata or stale
 		// working trees. May also update ancillary indexes such as the commit-graph.
-		cleanups = append(cleanups, cleanupFn{"garbage collect", performGC})
+		cleanups = append(cleanups, cleanupFn{"garbage collect", performGC, true})
 	}
 
 	if gitGCMode == gitGCModeMaintenance {
 		// Run tasks to optimize Git repository data, speeding up other Git commands and
 		// reducing storage requirements for the repository. Note: "garbage collect" and
 		// "sg maintenance" must not be enabled at the same time.
-		cleanups = append(cleanups, cleanupFn{"sg maintenance", performSGMaintenance})
-		cleanups = append(cleanups, cleanupFn{"git prune", performGitPrune})
+		cleanups = append(cleanups, cleanupFn{"sg maintenance", performSGMaintenance, true})
+		cleanups = append(cleanups, cleanupFn{"git prune", performGitPrune, true})
+	}
+
+	if gitGCMode == gitGCModeGitMaintenance {
+		// Run the git maintenance tasks that are due, such as writing the commit-graph
+		// and multi-pack-index, on a schedule that depends on the size and activity of
+		// the repository. Tasks are skipped on repositories that did not change since
+		// they last ran.
+		cleanups = append(cleanups, cleanupFn{"git maintenance", performGitMaintenance, true})
+		defer objStats.report()
 	}
 
 	if !conf.Get().DisableAutoGitUpdates {
@@ -484,8 +531,9 @@ func (s *Server) cleanupRepos(ctx context.Context, gitServerAddrs gitserver.Gits
 		// repository. We don't do this if DisableAutoGitUpdates is set as it could
 		// potentially kick off a clone operation.
 		cleanups = append(cleanups, cleanupFn{
-			Name: "maybe re-clone",
-			Do:   maybeReclone,
+			Name:        "maybe re-clone",
+			Do:          maybeReclone,
+			Destructive: true,
 		})
 	}
 
@@ -504,8 +552,12 @@ func (s *Server) cleanupRepos(ctx context.Context, gitServerAddrs gitserver.Gits
 
 		// We are sure this is a GIT_DIR after the above check
 		gitDir := common.GitDir(dir)
+		held := onLegalHold(s.name(gitDir))
 
 		for _, cfn := range cleanups {
+			if held && cfn.Destructive {
+				continue
+			}
 			start := time.Now()
 			done, err := cfn.Do(gitDir)
 			if err != nil {
@@ -543,7 +595,7 @@ func (s *Server) cleanupRepos(ctx context.Context, gitServerAddrs gitserver.Gits
 	if err != nil {
 		logger.Error("ensuring free disk space", log.Error(err))
 	}
-	if err := s.freeUpSpace(logger, b); err != nil {
+	if err := s.freeUpSpace(logger, b, onLegalHold); err != nil {
 		logger.Error("error freeing up space", log.Error(err))
 	}
 }
@@ -643,8 +695,9 @@ func (s *StatDiskSizer) DiskSizeBytes(mountPoint string) (uint64, error) {
 }
 
 // freeUpSpace removes git directories under ReposDir, in order from least
-// recently to most recently used, until it has freed howManyBytesToFree.
-func (s *Server) freeUpSpace(logger log.Logger, howManyBytesToFree int64) error {
+// recently to most recently used, until it has freed howManyBytesToFree. The
+// clones of repositories on legal hold are never removed.
+func (s *Server) freeUpSpace(logger log.Logger, howManyBytesToFree int64, onLegalHold func(api.RepoName) bool) error {
 	if howManyBytesToFree <= 0 {
 		return nil
 	}
@@ -680,6 +733,9 @@ func (s *Server) freeUpSpace(logger log.Logger, howManyBytesToFree int64) error
 		if spaceFreed >= howManyBytesToFree {
 			return nil
 		}
+		if onLegalHold(s.name(d)) {
+			continue
+		}
 		delta := dirSize(d.Path("."))
 		if err := s.removeRepoDirectory(d, logger, true); err != nil {
 			return errors.Wrap(err, "removing repo directory")
@@ -1342,7 +1398,7 @@ func gitSetAutoGC(dir common.GitDir) error {
 	case gitGCModeGitAutoGC, gitGCModeJanitorAutoGC:
 		return gitConfigUnset(dir, "gc.auto")
 
-	case gitGCModeMaintenance:
+	case gitGCModeMaintenance, gitGCModeGitMaintenance:
 		return gitConfigSet(dir, "gc.auto", "0")
 
 	default:
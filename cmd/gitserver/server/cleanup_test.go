@@ -1130,15 +1130,16 @@ func isEmptyDir(path string) (bool, error) {
 
 func TestFreeUpSpace(t *testing.T) {
 	logger := logtest.Scoped(t)
+	notOnLegalHold := func(api.RepoName) bool { return false }
 	t.Run("no error if no space requested and no repos", func(t *testing.T) {
 		s := &Server{DiskSizer: &fakeDiskSizer{}, Logger: logger, ObservationCtx: observation.TestContextTB(t), DB: database.NewMockDB()}
-		if err := s.freeUpSpace(logger, 0); err != nil {
+		if err := s.freeUpSpace(logger, 0, notOnLegalHold); err != nil {
 			t.Fatal(err)
 		}
 	})
 	t.Run("error if space requested and no repos", func(t *testing.T) {
 		s := &Server{DiskSizer: &fakeDiskSizer{}, Logger: logger, ObservationCtx: observation.TestContextTB(t), DB: database.NewMockDB()}
-		if err := s.freeUpSpace(logger, 1); err == nil {
+		if err := s.freeUpSpace(logger, 1, notOnLegalHold); err == nil {
 			t.Fatal("want error")
 		}
 	})
@@ -1175,7 +1176,7 @@ func TestFreeUpSpace(t *testing.T) {
 			DiskSizer:      &fakeDiskSizer{},
 			DB:             db,
 		}
-		if err := s.freeUpSpace(logger, 1000); err != nil {
+		if err := s.freeUpSpace(logger, 1000, notOnLegalHold); err != nil {
 			t.Fatal(err)
 		}
 
@@ -1195,6 +1196,46 @@ func TestFreeUpSpace(t *testing.T) {
 		}
 		require.Equal(t, gr.SetCloneStatusFunc.History()[0].Arg2, types.CloneStatusNotCloned)
 	})
+	t.Run("repos on legal hold are not removed", func(t *testing.T) {
+		rd := t.TempDir()
+
+		r1 := filepath.Join(rd, "repo1")
+		r2 := filepath.Join(rd, "repo2")
+		if err := makeFakeRepo(r1, 1000); err != nil {
+			t.Fatal(err)
+		}
+		if err := makeFakeRepo(r2, 1000); err != nil {
+			t.Fatal(err)
+		}
+		// Force the modification time of r2 to be after that of r1.
+		fi1, err := os.Stat(r1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(r2, time.Now(), fi1.ModTime().Add(time.Second)); err != nil {
+			t.Fatal(err)
+		}
+
+		db := database.NewMockDB()
+		db.GitserverReposFunc.SetDefaultReturn(database.NewMockGitserverRepoStore())
+		s := Server{
+			Logger:         logger,
+			ObservationCtx: observation.TestContextTB(t),
+			ReposDir:       rd,
+			DiskSizer:      &fakeDiskSizer{},
+			DB:             db,
+		}
+		// repo1 is the least recently used, but it must be kept.
+		onLegalHold := func(name api.RepoName) bool { return name == "repo1" }
+		if err := s.freeUpSpace(logger, 1000, onLegalHold); err != nil {
+			t.Fatal(err)
+		}
+
+		assertPaths(t, rd,
+			".tmp",
+			"repo1/.git/HEAD",
+			"repo1/.git/space_eater")
+	})
 }
 
 func makeFakeRepo(d string, sizeBytes int) error {
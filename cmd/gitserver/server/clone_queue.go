@@ -0,0 +1,54 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+// handleCloneQueue lists the repositories waiting in the clone queue, in the
+// order in which they will be cloned.
+func (s *Server) handleCloneQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobs := s.CloneQueue.Jobs()
+	resp := protocol.CloneQueueResponse{Jobs: make([]protocol.CloneQueueJob, 0, len(jobs))}
+	for _, job := range jobs {
+		resp.Jobs = append(resp.Jobs, protocol.CloneQueueJob{
+			Repo:     job.Job.repo,
+			Priority: job.Priority.String(),
+			Position: job.Position,
+			QueuedAt: job.PushedAt,
+		})
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleCloneQueueBoost moves a repository to the front of the clone queue, so
+// that it is cloned next. This is meant for incident recovery, when many
+// repositories need to be recloned and some of them are more urgent than others.
+func (s *Server) handleCloneQueueBoost(w http.ResponseWriter, r *http.Request) {
+	var req protocol.CloneQueueBoostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	repo := protocol.NormalizeRepo(req.Repo)
+
+	resp := protocol.CloneQueueBoostResponse{
+		Boosted: s.CloneQueue.Boost(func(job *cloneJob) bool { return job.repo == repo }),
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"container/list"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server/common"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestCloneQueueHandlers(t *testing.T) {
+	s := &Server{CloneQueue: NewCloneQueue(observation.TestContextTB(t), list.New())}
+	s.CloneQueue.PushWithPriority(&cloneJob{repo: "github.com/foo/bulk"}, common.PriorityLow)
+	s.CloneQueue.PushWithPriority(&cloneJob{repo: "github.com/foo/api"}, common.PriorityNormal)
+	s.CloneQueue.PushWithPriority(&cloneJob{repo: "github.com/foo/visited"}, common.PriorityHigh)
+
+	listQueue := func() []protocol.CloneQueueJob {
+		w := httptest.NewRecorder()
+		s.handleCloneQueue(w, httptest.NewRequest("GET", "/clone-queue", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp protocol.CloneQueueResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		return resp.Jobs
+	}
+	boost := func(repo string) bool {
+		w := httptest.NewRecorder()
+		s.handleCloneQueueBoost(w, httptest.NewRequest("POST", "/clone-queue/boost", strings.NewReader(`{"repo":"`+repo+`"}`)))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp protocol.CloneQueueBoostResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		return resp.Boosted
+	}
+
+	jobs := listQueue()
+	require.Len(t, jobs, 3)
+	require.Equal(t, "github.com/foo/visited", string(jobs[0].Repo))
+	require.Equal(t, "high", jobs[0].Priority)
+	require.Equal(t, 1, jobs[0].Position)
+	require.Equal(t, "github.com/foo/bulk", string(jobs[2].Repo))
+	require.Equal(t, "low", jobs[2].Priority)
+	require.Equal(t, 3, jobs[2].Position)
+
+	require.True(t, boost("github.com/foo/bulk"))
+	require.False(t, boost("github.com/foo/missing"))
+
+	jobs = listQueue()
+	require.Equal(t, "github.com/foo/bulk", string(jobs[0].Repo))
+	require.Equal(t, "boosted", jobs[0].Priority)
+	require.Equal(t, 1, jobs[0].Position)
+}
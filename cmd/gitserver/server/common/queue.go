@@ -45,12 +45,50 @@ func registerMetrics(observationCtx *observation.Context) {
 	})
 }
 
+// Priority determines the order in which jobs are popped from a Queue. Jobs with a higher
+// priority are popped first, jobs with the same priority in FIFO order.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+	// PriorityBoosted is the priority of jobs that were moved to the front of the queue with
+	// Boost. It is not meant to be used when pushing jobs.
+	PriorityBoosted
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	case PriorityBoosted:
+		return "boosted"
+	default:
+		return "unknown"
+	}
+}
+
 type queueItem[T any] struct {
 	job      T
+	priority Priority
 	pushedAt time.Time
 }
 
-// Queue is a threadsafe FIFO queue.
+// QueuedJob is a snapshot of a job waiting in a Queue.
+type QueuedJob[T any] struct {
+	Job      T
+	Priority Priority
+	// Position is the 1-based position of the job in the queue.
+	Position int
+	PushedAt time.Time
+}
+
+// Queue is a threadsafe priority queue. Jobs of the same priority are processed in FIFO order.
 type Queue[T any] struct {
 	*metrics
 
@@ -83,15 +121,33 @@ func NewQueue[T any](obctx *observation.Context, name string, jobs *list.List) *
 	return &q
 }
 
-// Push will queue the job to the end of the queue.
+// Push will queue the job with normal priority.
 func (q *Queue[T]) Push(job T) {
+	q.PushWithPriority(job, PriorityNormal)
+}
+
+// PushWithPriority will queue the job behind all jobs of the same or a higher priority.
+func (q *Queue[T]) PushWithPriority(job T, priority Priority) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	q.jobs.PushBack(&queueItem[T]{
+	item := &queueItem[T]{
 		job:      job,
+		priority: priority,
 		pushedAt: time.Now(),
-	})
+	}
+
+	// Most jobs are pushed with the priority of the jobs at the end of the queue, so we look
+	// for the insertion point from the back.
+	e := q.jobs.Back()
+	for e != nil && e.Value.(*queueItem[T]).priority < priority {
+		e = e.Prev()
+	}
+	if e == nil {
+		q.jobs.PushFront(item)
+	} else {
+		q.jobs.InsertAfter(item, e)
+	}
 	q.Cond.Signal()
 
 	// Set the push time on the job's metadata. This will be used to observe the total wait time in
@@ -127,6 +183,42 @@ func (q *Queue[T]) Pop() (*T, func() time.Duration) {
 	}
 }
 
+// Boost moves the first job for which match returns true to the front of the queue. It
+// returns false if no job matches.
+func (q *Queue[T]) Boost(match func(T) bool) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for e := q.jobs.Front(); e != nil; e = e.Next() {
+		item := e.Value.(*queueItem[T])
+		if match(item.job) {
+			item.priority = PriorityBoosted
+			q.jobs.MoveToFront(e)
+			return true
+		}
+	}
+	return false
+}
+
+// Jobs returns the jobs currently waiting in the queue, in the order in which they will be
+// popped.
+func (q *Queue[T]) Jobs() []QueuedJob[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	jobs := make([]QueuedJob[T], 0, q.jobs.Len())
+	for e := q.jobs.Front(); e != nil; e = e.Next() {
+		item := e.Value.(*queueItem[T])
+		jobs = append(jobs, QueuedJob[T]{
+			Job:      item.job,
+			Priority: item.priority,
+			Position: len(jobs) + 1,
+			PushedAt: item.pushedAt,
+		})
+	}
+	return jobs
+}
+
 func (q *Queue[T]) Empty() bool {
 	q.mu.Lock()
 	defer q.mu.Unlock()
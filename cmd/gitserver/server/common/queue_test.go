@@ -53,3 +53,42 @@ func TestQueue(t *testing.T) {
 		t.Error("Expected queue to be empty after popping all elements")
 	}
 }
+
+func TestQueuePriority(t *testing.T) {
+	queue := NewQueue[*testJob](observation.TestContextTB(t), "test-priority", list.New())
+
+	queue.PushWithPriority(&testJob{Value: "bulk-1"}, PriorityLow)
+	queue.PushWithPriority(&testJob{Value: "api-1"}, PriorityNormal)
+	queue.PushWithPriority(&testJob{Value: "bulk-2"}, PriorityLow)
+	queue.PushWithPriority(&testJob{Value: "user-1"}, PriorityHigh)
+	queue.Push(&testJob{Value: "api-2"})
+	queue.PushWithPriority(&testJob{Value: "user-2"}, PriorityHigh)
+
+	values := func() []string {
+		var values []string
+		for _, j := range queue.Jobs() {
+			values = append(values, j.Job.Value)
+		}
+		return values
+	}
+
+	require.Equal(t, []string{"user-1", "user-2", "api-1", "api-2", "bulk-1", "bulk-2"}, values())
+
+	require.True(t, queue.Boost(func(j *testJob) bool { return j.Value == "bulk-2" }))
+	require.False(t, queue.Boost(func(j *testJob) bool { return j.Value == "missing" }))
+
+	// Boosted jobs stay ahead of jobs pushed later with a high priority.
+	queue.PushWithPriority(&testJob{Value: "user-3"}, PriorityHigh)
+	require.Equal(t, []string{"bulk-2", "user-1", "user-2", "user-3", "api-1", "api-2", "bulk-1"}, values())
+
+	jobs := queue.Jobs()
+	require.Equal(t, 1, jobs[0].Position)
+	require.Equal(t, PriorityBoosted, jobs[0].Priority)
+	require.Equal(t, 7, jobs[6].Position)
+	require.Equal(t, PriorityLow, jobs[6].Priority)
+
+	got, doneFunc := queue.Pop()
+	require.NotNil(t, doneFunc)
+	require.Equal(t, "bulk-2", (*got).Value)
+	require.Len(t, queue.Jobs(), 6)
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Repositories on legal hold are frozen by site admins. Their clones are never
+// removed, re-cloned or garbage collected by gitserver, and every access to them
+// is recorded in the audit log.
+
+// errRepoOnLegalHold is returned when trying to remove the clone of a repository
+// on legal hold.
+var errRepoOnLegalHold = errors.New("repository is on legal hold")
+
+// legalHoldsTTL is how long the repositories on legal hold are cached for.
+const legalHoldsTTL = time.Minute
+
+// legalHolds caches the names of the repositories on legal hold, as they are
+// looked up on every access.
+type legalHolds struct {
+	mu        sync.Mutex
+	repos     map[api.RepoName]struct{}
+	fetchedAt time.Time
+}
+
+// reposOnLegalHold returns the names of the repositories on legal hold.
+func (s *Server) reposOnLegalHold(ctx context.Context) (map[api.RepoName]struct{}, error) {
+	s.legalHolds.mu.Lock()
+	defer s.legalHolds.mu.Unlock()
+
+	if s.legalHolds.repos != nil && time.Since(s.legalHolds.fetchedAt) < legalHoldsTTL {
+		return s.legalHolds.repos, nil
+	}
+
+	names, err := s.DB.RepoLegalHolds().ListRepoNames(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing repositories on legal hold")
+	}
+	repos := make(map[api.RepoName]struct{}, len(names))
+	for _, name := range names {
+		repos[name] = struct{}{}
+	}
+	s.legalHolds.repos = repos
+	s.legalHolds.fetchedAt = time.Now()
+	return repos, nil
+}
+
+// RepoOnLegalHold returns whether the repository is on legal hold. If that
+// cannot be determined, the repository is assumed to be on legal hold, so that
+// it is not modified by mistake.
+func (s *Server) RepoOnLegalHold(ctx context.Context, repo api.RepoName) bool {
+	repos, err := s.reposOnLegalHold(ctx)
+	if err != nil {
+		s.Logger.Warn("failed to look up repositories on legal hold", log.Error(err))
+		return true
+	}
+	_, ok := repos[api.UndeletedRepoName(repo)]
+	return ok
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/lfs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Repositories are cloned and fetched with GIT_LFS_SKIP_SMUDGE=1, so the files
+// stored in LFS are only present as pointers. handleLFSContent fetches the
+// content of a pointer from the LFS server of the repository's code host, using
+// the credentials of its remote URL. The content is streamed to the caller and
+// never stored on disk.
+func (s *Server) handleLFSContent(w http.ResponseWriter, r *http.Request) {
+	var req protocol.LFSContentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Repo == "" || req.OID == "" {
+		http.Error(w, "no repo or oid given", http.StatusBadRequest)
+		return
+	}
+
+	maxSize, ok := lfs.FetchContentEnabled()
+	if !ok {
+		http.Error(w, "fetching LFS content is disabled", http.StatusForbidden)
+		return
+	}
+	if req.Size > maxSize {
+		http.Error(w, lfs.ErrTooLarge.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// The caller has already checked that the pointer can be read, so we use an
+	// internal actor to get the remote URL of a repository that may be private.
+	ctx := r.Context()
+	remoteURL, err := s.getRemoteURL(actor.WithInternalActor(ctx), req.Repo)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rc, err := lfs.Fetch(ctx, httpcli.ExternalDoer, &remoteURL.URL, lfs.Pointer{OID: req.OID, Size: req.Size}, maxSize)
+	if err != nil {
+		status := http.StatusBadGateway
+		switch {
+		case errors.Is(err, lfs.ErrTooLarge):
+			status = http.StatusRequestEntityTooLarge
+		case errors.Is(err, lfs.ErrUnsupportedRemote):
+			status = http.StatusNotImplemented
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.FormatInt(req.Size, 10))
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, rc)
+}
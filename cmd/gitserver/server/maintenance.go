@@ -0,0 +1,265 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server/common"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// maintenanceTask is a task of `git maintenance run` that gitserver schedules when
+// gitGCMode is gitGCModeGitMaintenance.
+type maintenanceTask struct {
+	// Name is the name of the task, as passed to `git maintenance run --task`.
+	Name string
+	// Interval is the minimum time between two runs of the task on a repository.
+	Interval time.Duration
+	// ScalesWithSize is true for tasks whose cost grows with the size of the
+	// repository. Their interval is multiplied by the size of the repository in
+	// maintenanceSizeStep units, up to maintenanceMaxScale.
+	ScalesWithSize bool
+}
+
+const (
+	maintenanceSizeStep = 1 << 30 // 1 GiB
+	maintenanceMaxScale = 7
+)
+
+var maintenanceTasks = []maintenanceTask{
+	// Incrementally writes the commit-graph file, which speeds up commit graph walks
+	// such as git log. It only adds the commits new since the last write, so it is
+	// cheap to run often.
+	{Name: "commit-graph", Interval: time.Hour},
+	// Deletes the loose objects that are already packed, and packs the remaining
+	// ones into a new packfile.
+	{Name: "loose-objects", Interval: 6 * time.Hour},
+	// Writes a multi-pack-index over all packfiles, and repacks the small packfiles
+	// it covers into a larger one. Unlike git gc, it never rewrites the whole
+	// repository.
+	{Name: "incremental-repack", Interval: 24 * time.Hour, ScalesWithSize: true},
+}
+
+var (
+	maintenanceTaskRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_gitserver_git_maintenance_task_runs_total",
+		Help: "number of git maintenance task runs, by task and whether the run was a success (true/false)",
+	}, []string{"task", "success"})
+	maintenanceTaskDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "src_gitserver_git_maintenance_task_duration_seconds",
+		Help:    "Duration of git maintenance task runs",
+		Buckets: []float64{0.1, 1, 10, 60, 300, 1800, 3600},
+	}, []string{"task"})
+	reposObjects = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "src_gitserver_repos_objects",
+		Help: "number of git objects in all repositories on disk, by whether they are loose or packed",
+	}, []string{"type"})
+	reposRedundantObjects = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "src_gitserver_repos_redundant_loose_objects",
+		Help: "number of loose git objects in all repositories on disk that are also in a packfile",
+	})
+	reposPackfiles = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "src_gitserver_repos_packfiles",
+		Help: "number of packfiles in all repositories on disk",
+	})
+	reposBloatedPacks = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "src_gitserver_repos_bloated_packs",
+		Help: "number of repositories on disk with more packfiles than SRC_GIT_AUTO_PACK_LIMIT",
+	})
+	reposGarbageBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "src_gitserver_repos_garbage_bytes",
+		Help: "size of the garbage files in the object stores of all repositories on disk",
+	})
+)
+
+// due returns whether the task should run at now on a repository of the given size
+// that last changed at lastChanged. lastRun is zero if the task never ran on the
+// repository.
+func (t maintenanceTask) due(now, lastRun, lastChanged time.Time, size int64) bool {
+	if lastRun.IsZero() {
+		return true
+	}
+	// There is nothing new to optimize in inactive repositories.
+	if lastChanged.Before(lastRun) {
+		return false
+	}
+
+	interval := t.Interval
+	if t.ScalesWithSize {
+		scale := 1 + size/maintenanceSizeStep
+		if scale > maintenanceMaxScale {
+			scale = maintenanceMaxScale
+		}
+		interval *= time.Duration(scale)
+	}
+	return now.Sub(lastRun) >= interval
+}
+
+// gitMaintenance runs the maintenance tasks that are due in dir, which is of the
+// given size. Like sgMaintenance, it must not be run concurrently with git gc.
+func gitMaintenance(logger log.Logger, dir common.GitDir, size int64) error {
+	lastChanged, err := repoLastChanged(dir)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var due []maintenanceTask
+	for _, task := range maintenanceTasks {
+		lastRun, err := getMaintenanceTime(dir, task.Name)
+		if err != nil {
+			return err
+		}
+		if task.due(now, lastRun, lastChanged, size) {
+			due = append(due, task)
+		}
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	err, unlock := lockRepoForGC(dir)
+	if err != nil {
+		logger.Debug(
+			"could not lock repository for git maintenance",
+			log.String("dir", string(dir)),
+			log.Error(err),
+		)
+		return nil
+	}
+	defer unlock()
+
+	var errs error
+	for _, task := range due {
+		start := time.Now()
+		cmd := exec.Command("git", "-c", "core.commitGraph=true", "-c", "core.multiPackIndex=true", "maintenance", "run", "--task="+task.Name)
+		dir.Set(cmd)
+		err := cmd.Run()
+		maintenanceTaskRuns.WithLabelValues(task.Name, strconv.FormatBool(err == nil)).Inc()
+		maintenanceTaskDuration.WithLabelValues(task.Name).Observe(time.Since(start).Seconds())
+		if err != nil {
+			errs = errors.Append(errs, errors.Wrapf(wrapCmdError(cmd, err), "failed to run git maintenance task %s", task.Name))
+			continue
+		}
+		if err := setMaintenanceTime(dir, task.Name, now); err != nil {
+			errs = errors.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+func maintenanceTimeKey(task string) string {
+	return "sourcegraph.maintenance." + task
+}
+
+// setMaintenanceTime sets the time the given maintenance task last ran in dir.
+func setMaintenanceTime(dir common.GitDir, task string, t time.Time) error {
+	err := gitConfigSet(dir, maintenanceTimeKey(task), strconv.FormatInt(t.Unix(), 10))
+	if err != nil {
+		return errors.Wrapf(err, "failed to update last run time of maintenance task %s", task)
+	}
+	return nil
+}
+
+// getMaintenanceTime returns the time the given maintenance task last ran in dir, or
+// the zero time if it never ran or the stored value is invalid.
+func getMaintenanceTime(dir common.GitDir, task string) (time.Time, error) {
+	value, err := gitConfigGet(dir, maintenanceTimeKey(task))
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to determine last run time of maintenance task %s", task)
+	}
+	sec, err := strconv.ParseInt(value, 10, 0)
+	if err != nil {
+		return time.Time{}, nil
+	}
+	return time.Unix(sec, 0), nil
+}
+
+// objectStats are statistics about the object store of repositories, as reported by
+// `git count-objects -v`.
+type objectStats struct {
+	Repos int64
+	// LooseObjects is the number of loose objects.
+	LooseObjects int64
+	// PackedObjects is the number of objects in packfiles.
+	PackedObjects int64
+	// RedundantObjects is the number of loose objects that are also in packfiles.
+	RedundantObjects int64
+	Packfiles        int64
+	// BloatedRepos is the number of repositories with more packfiles than
+	// autoPackLimit.
+	BloatedRepos int64
+	GarbageBytes int64
+}
+
+func (s *objectStats) add(o objectStats) {
+	s.Repos += o.Repos
+	s.LooseObjects += o.LooseObjects
+	s.PackedObjects += o.PackedObjects
+	s.RedundantObjects += o.RedundantObjects
+	s.Packfiles += o.Packfiles
+	s.BloatedRepos += o.BloatedRepos
+	s.GarbageBytes += o.GarbageBytes
+}
+
+// report sets the object store metrics to the values of s.
+func (s *objectStats) report() {
+	reposObjects.WithLabelValues("loose").Set(float64(s.LooseObjects))
+	reposObjects.WithLabelValues("packed").Set(float64(s.PackedObjects))
+	reposRedundantObjects.Set(float64(s.RedundantObjects))
+	reposPackfiles.Set(float64(s.Packfiles))
+	reposBloatedPacks.Set(float64(s.BloatedRepos))
+	reposGarbageBytes.Set(float64(s.GarbageBytes))
+}
+
+// gitObjectStats returns the statistics of the object store of dir.
+func gitObjectStats(dir common.GitDir) (objectStats, error) {
+	cmd := exec.Command("git", "count-objects", "-v")
+	dir.Set(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return objectStats{}, errors.Wrap(wrapCmdError(cmd, err), "failed to count objects")
+	}
+	return parseCountObjects(out)
+}
+
+func parseCountObjects(out []byte) (objectStats, error) {
+	stats := objectStats{Repos: 1}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), ": ")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return objectStats{}, errors.Wrapf(err, "invalid count-objects value for %s", key)
+		}
+		switch key {
+		case "count":
+			stats.LooseObjects = n
+		case "in-pack":
+			stats.PackedObjects = n
+		case "prune-packable":
+			stats.RedundantObjects = n
+		case "packs":
+			stats.Packfiles = n
+		case "size-garbage":
+			// Sizes are reported in KiB.
+			stats.GarbageBytes = n * 1024
+		}
+	}
+	if stats.Packfiles > int64(autoPackLimit) {
+		stats.BloatedRepos = 1
+	}
+	return stats, sc.Err()
+}
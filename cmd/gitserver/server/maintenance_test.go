@@ -0,0 +1,140 @@
+package server
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+)
+
+func TestMaintenanceTaskDue(t *testing.T) {
+	now := time.Now()
+	task := maintenanceTask{Name: "incremental-repack", Interval: time.Hour, ScalesWithSize: true}
+
+	tests := map[string]struct {
+		lastRun     time.Time
+		lastChanged time.Time
+		size        int64
+		want        bool
+	}{
+		"never ran": {
+			lastChanged: now.Add(-48 * time.Hour),
+			want:        true,
+		},
+		"unchanged since last run": {
+			lastRun:     now.Add(-48 * time.Hour),
+			lastChanged: now.Add(-72 * time.Hour),
+			want:        false,
+		},
+		"changed, interval elapsed": {
+			lastRun:     now.Add(-2 * time.Hour),
+			lastChanged: now.Add(-time.Minute),
+			want:        true,
+		},
+		"changed, interval not elapsed": {
+			lastRun:     now.Add(-30 * time.Minute),
+			lastChanged: now.Add(-time.Minute),
+			want:        false,
+		},
+		"large repo, scaled interval not elapsed": {
+			lastRun:     now.Add(-2 * time.Hour),
+			lastChanged: now.Add(-time.Minute),
+			size:        3 * maintenanceSizeStep,
+			want:        false,
+		},
+		"huge repo, capped interval elapsed": {
+			lastRun:     now.Add(-8 * time.Hour),
+			lastChanged: now.Add(-time.Minute),
+			size:        100 * maintenanceSizeStep,
+			want:        true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := task.due(now, test.lastRun, test.lastChanged, test.size); got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestParseCountObjects(t *testing.T) {
+	out := `count: 12
+size: 48
+in-pack: 3000
+packs: 60
+size-pack: 1024
+prune-packable: 2
+garbage: 1
+size-garbage: 4
+`
+	got, err := parseCountObjects([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := objectStats{
+		Repos:            1,
+		LooseObjects:     12,
+		PackedObjects:    3000,
+		RedundantObjects: 2,
+		Packfiles:        60,
+		BloatedRepos:     1,
+		GarbageBytes:     4096,
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("unexpected stats (-want, +got):\n%s", d)
+	}
+
+	if _, err := parseCountObjects([]byte("count: many\n")); err == nil {
+		t.Fatal("expected an error for an invalid count")
+	}
+}
+
+func TestGitMaintenance(t *testing.T) {
+	logger := logtest.Scoped(t)
+	dir := t.TempDir()
+	gitDir := prepareEmptyGitRepo(t, dir)
+
+	script := `echo acont > afile
+git add afile
+git commit -am amsg
+`
+	cmd := exec.Command("/bin/sh", "-euxc", script)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("out=%s, err=%s", out, err)
+	}
+
+	if err := gitMaintenance(logger, gitDir, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// The commit-graph task writes incremental commit-graph chains.
+	if _, err := os.Stat(gitDir.Path("objects", "info", "commit-graphs", "commit-graph-chain")); err != nil {
+		t.Fatalf("expected git maintenance to write a commit-graph chain: %s", err)
+	}
+	if _, err := os.Stat(gitDir.Path("objects", "pack", "multi-pack-index")); err != nil {
+		t.Fatalf("expected git maintenance to write a multi-pack-index: %s", err)
+	}
+
+	for _, task := range maintenanceTasks {
+		lastRun, err := getMaintenanceTime(gitDir, task.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lastRun.IsZero() {
+			t.Fatalf("expected the last run time of task %s to be recorded", task.Name)
+		}
+	}
+
+	stats, err := gitObjectStats(gitDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.LooseObjects+stats.PackedObjects == 0 {
+		t.Fatal("expected git to report objects")
+	}
+}
@@ -0,0 +1,241 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server/common"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A partial clone is a Git repository cloned without some of its objects, e.g. without
+// file contents for the `blob:none` filter. Git fetches missing objects on demand from
+// the promisor remote, which is always named origin. As gitserver never stores remote
+// URLs in the repository config, the URL of origin is passed on the command line of
+// every command that may need to fetch missing objects.
+
+const (
+	// partialCloneMarker is the file in the git directory of a partial clone holding
+	// the filter with which it was cloned. Its existence is checked on every exec, so
+	// that we do not need to run `git config` to know whether a repository is partial.
+	partialCloneMarker = "sg_partial_clone"
+
+	// partialClonePromotionMarker is the file in the git directory of a partial clone
+	// that is to be promoted to a full clone on its next fetch.
+	partialClonePromotionMarker = "sg_partial_clone_promote"
+
+	// defaultPartialCloneFilter is the filter used if none is configured.
+	defaultPartialCloneFilter = "blob:none"
+
+	// defaultPartialClonePromotionThreshold is the promotion threshold used if none is
+	// configured.
+	defaultPartialClonePromotionThreshold = 1000
+)
+
+var partialCloneConfig = conf.Cached(func() *schema.GitPartialClone {
+	return conf.ExperimentalFeatures().GitPartialClone
+})
+
+// partialCloneFilter returns the filter with which the repository with the given
+// remote URL should be cloned, or an empty string if it should be fully cloned.
+func partialCloneFilter(remoteURL *vcs.URL) string {
+	c := partialCloneConfig()
+	if c == nil {
+		return ""
+	}
+
+	if !c.All && !matchesAnyDomainPath(c.Repos, remoteURL) {
+		return ""
+	}
+	if c.Filter == "" {
+		return defaultPartialCloneFilter
+	}
+	return c.Filter
+}
+
+// matchesAnyDomainPath returns true if the domain/path of the given remote URL matches
+// any of the given patterns.
+func matchesAnyDomainPath(patterns []string, remoteURL *vcs.URL) bool {
+	domainPath := strings.TrimSuffix(path.Join(remoteURL.Host, remoteURL.Path), ".git")
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.TrimSuffix(pattern, ".git"), domainPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// partialClonePromotionThreshold returns the number of commands reading file contents
+// of a partial clone after which it is promoted to a full clone, or 0 if partial clones
+// are never promoted.
+func partialClonePromotionThreshold() int {
+	c := partialCloneConfig()
+	if c == nil || c.PromotionThreshold == nil {
+		return defaultPartialClonePromotionThreshold
+	}
+	return *c.PromotionThreshold
+}
+
+// configurePartialClone configures the empty repository in dir as a partial clone with
+// the given filter.
+func configurePartialClone(dir common.GitDir, filter string) error {
+	for _, kv := range [][2]string{
+		// Extensions are only read by repositories of version 1
+		{"core.repositoryformatversion", "1"},
+		{"extensions.partialClone", "origin"},
+		{"remote.origin.promisor", "true"},
+		{"remote.origin.partialclonefilter", filter},
+	} {
+		if err := gitConfigSet(dir, kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(dir.Path(partialCloneMarker), []byte(filter), os.ModePerm)
+}
+
+// readPartialClone returns the filter with which the repository in dir was partially
+// cloned, or an empty string if it is a full clone. promote is true if the repository
+// should be promoted to a full clone.
+func readPartialClone(dir common.GitDir) (filter string, promote bool) {
+	b, err := os.ReadFile(dir.Path(partialCloneMarker))
+	if err != nil {
+		return "", false
+	}
+	_, err = os.Stat(dir.Path(partialClonePromotionMarker))
+	return strings.TrimSpace(string(b)), err == nil
+}
+
+// completePartialClonePromotion turns the repository in dir, whose missing objects have
+// all been fetched, into a full clone.
+func completePartialClonePromotion(dir common.GitDir) error {
+	for _, key := range []string{"extensions.partialClone", "remote.origin.promisor", "remote.origin.partialclonefilter"} {
+		if err := gitConfigUnset(dir, key); err != nil {
+			return err
+		}
+	}
+	for _, marker := range []string{partialCloneMarker, partialClonePromotionMarker} {
+		if err := os.Remove(dir.Path(marker)); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "failed to remove partial clone marker")
+		}
+	}
+	return nil
+}
+
+// partialFetchCommand returns the command fetching the given refspecs into a partial
+// clone. If promote is true, all objects are fetched again without a filter, so that
+// the repository no longer misses any object.
+func partialFetchCommand(ctx context.Context, dir common.GitDir, remoteURL *vcs.URL, filter string, promote bool, refspecs []string) (*exec.Cmd, error) {
+	args := []string{"-c", "remote.origin.url=" + remoteURL.String(), "fetch", "--progress", "--prune"}
+	if promote {
+		// The configured filter would otherwise apply to the refetch
+		if err := gitConfigUnset(dir, "remote.origin.partialclonefilter"); err != nil {
+			return nil, err
+		}
+		args = append(args, "--refetch")
+	} else {
+		args = append(args, "--filter="+filter)
+	}
+	args = append(args, "origin")
+	args = append(args, refspecs...)
+
+	return exec.CommandContext(ctx, "git", args...), nil
+}
+
+// partialCloneReadCommands are the git commands that read file contents, and thus may
+// fetch missing objects of a partial clone.
+var partialCloneReadCommands = map[string]struct{}{
+	"archive":  {},
+	"blame":    {},
+	"cat-file": {},
+	"diff":     {},
+	"grep":     {},
+	"show":     {},
+}
+
+// partialCloneReads counts the commands reading file contents of partial clones, in
+// order to promote the clones whose file contents are frequently read.
+type partialCloneReads struct {
+	mu     sync.Mutex
+	counts map[api.RepoName]int
+}
+
+// record records a command reading file contents of the given partial clone, and
+// returns true if the repository has reached the given promotion threshold.
+func (r *partialCloneReads) record(repo api.RepoName, threshold int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.counts == nil {
+		r.counts = make(map[api.RepoName]int)
+	}
+	r.counts[repo]++
+	if threshold <= 0 || r.counts[repo] < threshold {
+		return false
+	}
+	delete(r.counts, repo)
+	return true
+}
+
+// partialCloneExecArgs returns the arguments with which to run the given exec request
+// against a partial clone, so that git fetches missing objects on demand.
+func (s *Server) partialCloneExecArgs(ctx context.Context, repo api.RepoName, args []string) ([]string, *vcs.URL, error) {
+	remoteURL, err := s.getRemoteURL(ctx, repo)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "get remote URL of partial clone")
+	}
+	return append([]string{"-c", "remote.origin.url=" + remoteURL.String()}, args...), remoteURL, nil
+}
+
+// recordPartialCloneRead records a command run against the given partial clone, and
+// requests its promotion to a full clone once its file contents have been read by
+// enough commands.
+func (s *Server) recordPartialCloneRead(logger log.Logger, repo api.RepoName, dir common.GitDir, args []string) {
+	if len(args) == 0 {
+		return
+	}
+	if _, ok := partialCloneReadCommands[args[0]]; !ok {
+		return
+	}
+	partialCloneReadsCounter.Inc()
+
+	if !s.partialCloneReads.record(repo, partialClonePromotionThreshold()) {
+		return
+	}
+
+	if err := os.WriteFile(dir.Path(partialClonePromotionMarker), nil, os.ModePerm); err != nil {
+		logger.Warn("failed to request promotion of partial clone", log.Error(err))
+		return
+	}
+	logger.Info("promoting partial clone to a full clone")
+	partialClonePromotionsCounter.Inc()
+
+	go func() {
+		if err := s.doRepoUpdate(context.Background(), repo, ""); err != nil {
+			logger.Warn("failed to promote partial clone", log.Error(err))
+		}
+	}()
+}
+
+var (
+	partialCloneReadsCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_partial_clone_reads_total",
+		Help: "Total number of commands reading file contents of partial clones, which may fetch missing objects on demand.",
+	})
+	partialClonePromotionsCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_partial_clone_promotions_total",
+		Help: "Total number of partial clones promoted to full clones.",
+	})
+)
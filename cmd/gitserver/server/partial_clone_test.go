@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server/common"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/internal/wrexec"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestPartialCloneFilter(t *testing.T) {
+	mockPartialCloneConfig(t, &schema.GitPartialClone{
+		Repos: []string{"github.com/sourcegraph/huge", "gitlab.com/monorepos/*"},
+	})
+
+	for remote, want := range map[string]string{
+		"https://github.com/sourcegraph/huge.git":      "blob:none",
+		"git@github.com:sourcegraph/huge.git":          "blob:none",
+		"https://github.com/sourcegraph/sourcegraph":   "",
+		"https://gitlab.com/monorepos/frontend":        "blob:none",
+		"https://gitlab.com/monorepos/frontend/nested": "",
+	} {
+		remoteURL, err := vcs.ParseURL(remote)
+		require.NoError(t, err)
+		assert.Equal(t, want, partialCloneFilter(remoteURL), remote)
+	}
+
+	mockPartialCloneConfig(t, &schema.GitPartialClone{All: true, Filter: "tree:0"})
+	remoteURL, err := vcs.ParseURL("https://github.com/sourcegraph/sourcegraph")
+	require.NoError(t, err)
+	assert.Equal(t, "tree:0", partialCloneFilter(remoteURL))
+
+	mockPartialCloneConfig(t, nil)
+	assert.Equal(t, "", partialCloneFilter(remoteURL))
+}
+
+func TestPartialCloneReads(t *testing.T) {
+	var reads partialCloneReads
+	repo := api.RepoName("github.com/sourcegraph/huge")
+
+	assert.False(t, reads.record(repo, 2))
+	assert.True(t, reads.record(repo, 2))
+	// The count starts over after promotion
+	assert.False(t, reads.record(repo, 2))
+	// A threshold of 0 disables promotion
+	assert.False(t, reads.record(repo, 0))
+}
+
+func TestPartialClone(t *testing.T) {
+	mockPartialCloneConfig(t, &schema.GitPartialClone{All: true})
+
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote")
+	require.NoError(t, os.MkdirAll(remote, os.ModePerm))
+	cmd := func(name string, arg ...string) string {
+		return runCmd(t, remote, name, arg...)
+	}
+	makeSingleCommitRepo(cmd)
+	cmd("git", "config", "uploadpack.allowFilter", "true")
+
+	ctx := context.Background()
+	remoteURL, err := vcs.ParseURL("file://" + remote)
+	require.NoError(t, err)
+	syncer := NewGitRepoSyncer(wrexec.NewNoOpRecordingCommandFactory())
+
+	// Clone without file contents
+	tmpPath := filepath.Join(root, "clone", ".git")
+	cloneCmd, err := syncer.CloneCommand(ctx, remoteURL, tmpPath)
+	require.NoError(t, err)
+	out, err := cloneCmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	dir := common.GitDir(tmpPath)
+	filter, promote := readPartialClone(dir)
+	assert.Equal(t, "blob:none", filter)
+	assert.False(t, promote)
+	assert.NotEmpty(t, missingObjects(t, dir))
+
+	// File contents are fetched on demand
+	catFile := exec.Command("git", "-c", "remote.origin.url="+remoteURL.String(), "cat-file", "-p", "HEAD:hello.txt")
+	dir.Set(catFile)
+	out, err = catFile.CombinedOutput()
+	require.NoError(t, err, string(out))
+	assert.Equal(t, "hello world\n", string(out))
+
+	// Promotion fetches all missing objects on the next fetch
+	cmd("sh", "-c", "echo goodbye > goodbye.txt")
+	cmd("git", "add", "goodbye.txt")
+	cmd("git", "commit", "-m", "goodbye")
+	require.NoError(t, os.WriteFile(dir.Path(partialClonePromotionMarker), nil, os.ModePerm))
+
+	_, err = syncer.Fetch(ctx, remoteURL, dir, "")
+	require.NoError(t, err)
+
+	filter, _ = readPartialClone(dir)
+	assert.Equal(t, "", filter)
+	assert.Empty(t, missingObjects(t, dir))
+}
+
+func mockPartialCloneConfig(t *testing.T, c *schema.GitPartialClone) {
+	old := partialCloneConfig
+	partialCloneConfig = func() *schema.GitPartialClone { return c }
+	t.Cleanup(func() { partialCloneConfig = old })
+}
+
+// missingObjects returns the objects reachable from the refs of the repository in dir
+// that are missing from it.
+func missingObjects(t *testing.T, dir common.GitDir) []string {
+	t.Helper()
+
+	cmd := exec.Command("git", "rev-list", "--objects", "--all", "--missing=print")
+	dir.Set(cmd)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	var missing []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "?") {
+			missing = append(missing, strings.TrimPrefix(line, "?"))
+		}
+	}
+	return missing
+}
@@ -90,6 +90,10 @@ func (s *Server) handleRepoDelete(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) deleteRepo(ctx context.Context, repo api.RepoName) error {
+	if s.RepoOnLegalHold(ctx, repo) {
+		return errRepoOnLegalHold
+	}
+
 	// The repo may be deleted in the database, in this case we need to get the
 	// original name in order to find it on disk
 	err := s.removeRepoDirectory(s.dir(api.UndeletedRepoName(repo)), s.Logger, true)
@@ -195,6 +195,10 @@ type cloneJob struct {
 
 	remoteURL *vcs.URL
 	options   *cloneOptions
+
+	// result receives the outcome of the clone if the caller of cloneRepo is
+	// blocking on it.
+	result chan error
 }
 
 // cloneTask is a thin wrapper around a cloneJob to associate the doneFunc with each job.
@@ -297,6 +301,12 @@ type Server struct {
 
 	// Perforce is a plugin-like service attached to Server for all things Perforce.
 	Perforce *perforce.Service
+
+	// partialCloneReads counts the commands reading file contents of partial clones.
+	partialCloneReads partialCloneReads
+
+	// legalHolds caches the repositories on legal hold.
+	legalHolds legalHolds
 }
 
 type locks struct {
@@ -444,9 +454,12 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/is-repo-cloneable", trace.WithRouteName("is-repo-cloneable", s.handleIsRepoCloneable))
 	mux.HandleFunc("/repos-stats", trace.WithRouteName("repos-stats", s.handleReposStats))
 	mux.HandleFunc("/repo-clone-progress", trace.WithRouteName("repo-clone-progress", s.handleRepoCloneProgress))
+	mux.HandleFunc("/lfs-content", trace.WithRouteName("lfs-content", s.handleLFSContent))
 	mux.HandleFunc("/delete", trace.WithRouteName("delete", s.handleRepoDelete))
 	mux.HandleFunc("/repo-update", trace.WithRouteName("repo-update", s.handleRepoUpdate))
 	mux.HandleFunc("/repo-clone", trace.WithRouteName("repo-clone", s.handleRepoClone))
+	mux.HandleFunc("/clone-queue", trace.WithRouteName("clone-queue", s.handleCloneQueue))
+	mux.HandleFunc("/clone-queue/boost", trace.WithRouteName("clone-queue-boost", s.handleCloneQueueBoost))
 	mux.HandleFunc("/create-commit-from-patch-binary", trace.WithRouteName("create-commit-from-patch-binary", s.handleCreateCommitFromPatchBinary))
 	mux.HandleFunc("/ping", trace.WithRouteName("ping", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -622,6 +635,9 @@ func (s *Server) cloneJobConsumer(ctx context.Context, tasks <-chan *cloneTask)
 			if err != nil {
 				logger.Error("failed to clone repo", log.Error(err))
 			}
+			if task.result != nil {
+				task.result <- err
+			}
 			// Use a different context in case we failed because the original context failed.
 			s.setLastErrorNonFatal(s.ctx, task.repo, err)
 			_ = task.done()
@@ -1010,7 +1026,15 @@ func (s *Server) repoUpdate(req *protocol.RepoUpdateRequest) protocol.RepoUpdate
 		// want to go ahead and clone the repo. The responsibility of figuring out where to clone
 		// the repo from (upstream URL of the external service or the gitserver instance) lies with
 		// the implementation details of cloneRepo.
-		_, err := s.cloneRepo(ctx, req.Repo, &cloneOptions{Block: true, CloneFromShard: req.CloneFromShard})
+		//
+		// Repo updates are scheduled by repo-updater, so these clones are mostly the result
+		// of bulk syncs and wait in the clone queue behind clones that users are waiting for.
+		_, err := s.cloneRepo(ctx, req.Repo, &cloneOptions{
+			Block:          true,
+			Queue:          true,
+			Priority:       common.PriorityLow,
+			CloneFromShard: req.CloneFromShard,
+		})
 		if err != nil {
 			logger.Warn("error cloning repo", log.String("repo", string(req.Repo)), log.Error(err))
 			resp.Error = err.Error()
@@ -1068,7 +1092,7 @@ func (s *Server) handleRepoClone(w http.ResponseWriter, r *http.Request) {
 	var resp protocol.RepoCloneResponse
 	req.Repo = protocol.NormalizeRepo(req.Repo)
 
-	_, err := s.cloneRepo(context.Background(), req.Repo, &cloneOptions{Block: false})
+	_, err := s.cloneRepo(context.Background(), req.Repo, &cloneOptions{Block: false, Priority: common.PriorityNormal})
 	if err != nil {
 		logger.Warn("error cloning repo", log.String("repo", string(req.Repo)), log.Error(err))
 		resp.Error = err.Error()
@@ -1304,7 +1328,7 @@ func (s *Server) search(ctx context.Context, args *protocol.SearchRequest, onMat
 			}
 		}
 
-		cloneProgress, err := s.cloneRepo(ctx, args.Repo, nil)
+		cloneProgress, err := s.cloneRepo(ctx, args.Repo, &cloneOptions{Priority: common.PriorityHigh})
 		if err != nil {
 			s.Logger.Debug("error starting repo clone", log.String("repo", string(args.Repo)), log.Error(err))
 			return false, &gitdomain.RepoNotExistError{
@@ -1737,9 +1761,27 @@ func (s *Server) exec(ctx context.Context, logger log.Logger, req *protocol.Exec
 	stdoutW := &writeCounter{w: w}
 	stderrW := &writeCounter{w: &limitWriter{W: &stderrBuf, N: 1024}}
 
+	args := req.Args
+	var redactor *urlRedactor
+	partialFilter, _ := readPartialClone(dir)
+	if partialFilter != "" {
+		var remoteURL *vcs.URL
+		args, remoteURL, execErr = s.partialCloneExecArgs(ctx, req.Repo, req.Args)
+		if execErr != nil {
+			status = "partial-clone-error"
+			return execStatus{}, execErr
+		}
+		redactor = newURLRedactor(remoteURL)
+		s.recordPartialCloneRead(logger.With(log.String("repo", string(req.Repo))), req.Repo, dir, req.Args)
+	}
+
 	cmdStart = time.Now()
-	cmd := s.RecordingCommandFactory.Command(ctx, s.Logger, "git", req.Args...)
+	cmd := s.RecordingCommandFactory.Command(ctx, s.Logger, "git", args...)
 	dir.Set(cmd.Unwrap())
+	if partialFilter != "" {
+		// Missing objects of partial clones are fetched from the code host
+		configureRemoteGitCommand(cmd.Unwrap(), tlsExternal())
+	}
 	cmd.Unwrap().Stdout = stdoutW
 	cmd.Unwrap().Stderr = stderrW
 	cmd.Unwrap().Stdin = bytes.NewReader(req.Stdin)
@@ -1751,7 +1793,13 @@ func (s *Server) exec(ctx context.Context, logger log.Logger, req *protocol.Exec
 	stderrN = stderrW.n
 
 	stderr := stderrBuf.String()
-	s.logIfCorrupt(ctx, req.Repo, dir, stderr)
+	if redactor != nil {
+		stderr = redactor.redact(stderr)
+	}
+	// Failing to fetch missing objects of a partial clone does not indicate corruption
+	if partialFilter == "" || !strings.Contains(stderr, "promisor remote") {
+		s.logIfCorrupt(ctx, req.Repo, dir, stderr)
+	}
 
 	return execStatus{
 		Err:        execErr,
@@ -2086,6 +2134,14 @@ type cloneOptions struct {
 	// repository. If this is a non-zero string, then gitserver will attempt to clone the repo from
 	// that gitserver instance instead of the upstream repo URL of the external service.
 	CloneFromShard string
+
+	// Priority is the priority of the clone in the clone queue. Clones of repositories that
+	// users are waiting for should be processed before clones of bulk syncs.
+	Priority common.Priority
+
+	// Queue makes a blocking clone wait for its turn in the clone queue instead of cloning
+	// right away. Non-blocking clones are always queued.
+	Queue bool
 }
 
 // cloneRepo performs a clone operation for the given repository. It is
@@ -2138,7 +2194,8 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 	// run at one time. We use a separate semaphore to cloning since these
 	// checks being blocked by a few slow clones will lead to poor feedback to
 	// users. We can defer since the rest of the function does not block this
-	// goroutine.
+	// goroutine, except for queued blocking clones which release it early.
+	waitCtx := ctx
 	ctx, cancel, err := s.acquireCloneableLimiter(ctx)
 	if err != nil {
 		return "", err // err will be a context error
@@ -2172,7 +2229,7 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 	// We clone to a temporary location first to avoid having incomplete
 	// clones in the repo tree. This also avoids leaving behind corrupt clones
 	// if the clone is interrupted.
-	if opts != nil && opts.Block {
+	if opts != nil && opts.Block && !opts.Queue {
 		ctx, cancel, err := s.acquireCloneLimiter(ctx)
 		if err != nil {
 			return "", err
@@ -2185,19 +2242,41 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 		return "", err
 	}
 
-	// We push the cloneJob to a queue and let the producer-consumer pipeline take over from this
-	// point. See definitions of cloneJobProducer and cloneJobConsumer to understand how these jobs
-	// are processed.
-	s.CloneQueue.Push(&cloneJob{
+	job := &cloneJob{
 		repo:      repo,
 		dir:       dir,
 		syncer:    syncer,
 		lock:      lock,
 		remoteURL: remoteURL,
 		options:   opts,
-	})
+	}
+	priority := common.PriorityNormal
+	if opts != nil {
+		priority = opts.Priority
+		if opts.Block {
+			job.result = make(chan error, 1)
+		}
+	}
+
+	// We push the cloneJob to a queue and let the producer-consumer pipeline take over from this
+	// point. See definitions of cloneJobProducer and cloneJobConsumer to understand how these jobs
+	// are processed.
+	s.CloneQueue.PushWithPriority(job, priority)
+
+	if job.result == nil {
+		return "", nil
+	}
+
+	// Don't hold up the isCloneable checks of other repositories while we wait for our turn.
+	cancel()
 
-	return "", nil
+	select {
+	case err := <-job.result:
+		return "", errors.Wrapf(err, "failed to clone %s", repo)
+	case <-waitCtx.Done():
+		// The clone stays queued and will still happen.
+		return "", waitCtx.Err()
+	}
 }
 
 func (s *Server) doClone(ctx context.Context, repo api.RepoName, dir common.GitDir, syncer VCSSyncer, lock *RepositoryLock, remoteURL *vcs.URL, opts *cloneOptions) (err error) {
@@ -14,6 +14,7 @@ import (
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server/accesslog"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server/common"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/adapters"
@@ -341,7 +342,7 @@ func (gs *GRPCServer) RepoClone(ctx context.Context, in *proto.RepoCloneRequest)
 
 	repo := protocol.NormalizeRepo(api.RepoName(in.GetRepo()))
 
-	if _, err := gs.Server.cloneRepo(ctx, repo, &cloneOptions{Block: false}); err != nil {
+	if _, err := gs.Server.cloneRepo(ctx, repo, &cloneOptions{Block: false, Priority: common.PriorityNormal}); err != nil {
 
 		return &proto.RepoCloneResponse{Error: err.Error()}, nil
 	}
@@ -65,18 +65,41 @@ func (s *gitRepoSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tm
 		return nil, errors.Wrapf(&common.GitCommandError{Err: err}, "clone setup failed")
 	}
 
-	cmd, _ = s.fetchCommand(ctx, remoteURL)
+	dir := common.GitDir(tmpPath)
+
+	// Partial clones are only supported with the default fetch command
+	if customFetchCmd(ctx, remoteURL) == nil && !useRefspecOverrides() {
+		if filter := partialCloneFilter(remoteURL); filter != "" {
+			if err := configurePartialClone(dir, filter); err != nil {
+				return nil, errors.Wrap(err, "partial clone setup failed")
+			}
+		}
+	}
+
+	cmd, _, err = s.fetchCommand(ctx, remoteURL, dir)
+	if err != nil {
+		return nil, err
+	}
 	cmd.Dir = tmpPath
 	return cmd, nil
 }
 
 // Fetch tries to fetch updates of a Git repository.
 func (s *gitRepoSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir common.GitDir, revspec string) ([]byte, error) {
-	cmd, configRemoteOpts := s.fetchCommand(ctx, remoteURL)
+	cmd, configRemoteOpts, err := s.fetchCommand(ctx, remoteURL, dir)
+	if err != nil {
+		return nil, err
+	}
 	dir.Set(cmd)
 	if output, err := runRemoteGitCommand(ctx, s.recordingCommandFactory.Wrap(ctx, log.NoOp(), cmd), configRemoteOpts, nil); err != nil {
 		return nil, &common.GitCommandError{Err: err, Output: newURLRedactor(remoteURL).redact(string(output))}
 	}
+
+	if _, promote := readPartialClone(dir); promote {
+		if err := completePartialClonePromotion(dir); err != nil {
+			return nil, errors.Wrap(err, "failed to promote partial clone")
+		}
+	}
 	return nil, nil
 }
 
@@ -85,28 +108,33 @@ func (s *gitRepoSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.UR
 	return exec.CommandContext(ctx, "git", "remote", "show", remoteURL.String()), nil
 }
 
-func (s *gitRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL) (cmd *exec.Cmd, configRemoteOpts bool) {
+// fetchRefspecs are the refspecs fetched by the default fetch command.
+var fetchRefspecs = []string{
+	// Normal git refs
+	"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*",
+	// GitHub pull requests
+	"+refs/pull/*:refs/pull/*",
+	// GitLab merge requests
+	"+refs/merge-requests/*:refs/merge-requests/*",
+	// Bitbucket pull requests
+	"+refs/pull-requests/*:refs/pull-requests/*",
+	// Gerrit changesets
+	"+refs/changes/*:refs/changes/*",
+	// Possibly deprecated refs for sourcegraph zap experiment?
+	"+refs/sourcegraph/*:refs/sourcegraph/*",
+}
+
+func (s *gitRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL, dir common.GitDir) (cmd *exec.Cmd, configRemoteOpts bool, err error) {
 	configRemoteOpts = true
 	if customCmd := customFetchCmd(ctx, remoteURL); customCmd != nil {
 		cmd = customCmd
 		configRemoteOpts = false
 	} else if useRefspecOverrides() {
 		cmd = refspecOverridesFetchCmd(ctx, remoteURL)
+	} else if filter, promote := readPartialClone(dir); filter != "" {
+		cmd, err = partialFetchCommand(ctx, dir, remoteURL, filter, promote, fetchRefspecs)
 	} else {
-		cmd = exec.CommandContext(ctx, "git", "fetch",
-			"--progress", "--prune", remoteURL.String(),
-			// Normal git refs
-			"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*",
-			// GitHub pull requests
-			"+refs/pull/*:refs/pull/*",
-			// GitLab merge requests
-			"+refs/merge-requests/*:refs/merge-requests/*",
-			// Bitbucket pull requests
-			"+refs/pull-requests/*:refs/pull-requests/*",
-			// Gerrit changesets
-			"+refs/changes/*:refs/changes/*",
-			// Possibly deprecated refs for sourcegraph zap experiment?
-			"+refs/sourcegraph/*:refs/sourcegraph/*")
+		cmd = exec.CommandContext(ctx, "git", append([]string{"fetch", "--progress", "--prune", remoteURL.String()}, fetchRefspecs...)...)
 	}
-	return cmd, configRemoteOpts
+	return cmd, configRemoteOpts, err
 }
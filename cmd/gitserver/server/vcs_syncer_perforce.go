@@ -16,6 +16,7 @@ import (
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server/common"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/vcs"
 	"github.com/sourcegraph/sourcegraph/internal/wrexec"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -58,6 +59,10 @@ type PerforceDepotSyncer struct {
 	// FusionConfig contains information about the experimental p4-fusion client.
 	FusionConfig FusionConfig
 
+	// ImportShelvedChangelists enables the import of shelved changelists as
+	// hidden refs when fetching.
+	ImportShelvedChangelists bool
+
 	// P4Home is a directory we will pass to `git p4` commands as the
 	// $HOME directory as it requires this to write cache data.
 	P4Home string
@@ -118,7 +123,7 @@ func (s *PerforceDepotSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.U
 
 	var cmd *exec.Cmd
 	if s.FusionConfig.Enabled {
-		cmd = s.buildP4FusionCmd(ctx, depot, username, tmpPath, p4port)
+		cmd = s.buildP4FusionCmd(ctx, depot, username, tmpPath, p4port, s.FusionConfig.NetworkThreads)
 	} else {
 		// Example: git p4 clone --bare --max-changes 1000 //Sourcegraph/@all /tmp/clone-584194180/.git
 		args := append([]string{"p4", "clone", "--bare"}, s.p4CommandOptions()...)
@@ -130,14 +135,14 @@ func (s *PerforceDepotSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.U
 	return cmd, nil
 }
 
-func (s *PerforceDepotSyncer) buildP4FusionCmd(ctx context.Context, depot, username, src, port string) *exec.Cmd {
+func (s *PerforceDepotSyncer) buildP4FusionCmd(ctx context.Context, depot, username, src, port string, networkThreads int) *exec.Cmd {
 	// Example: p4-fusion --path //depot/... --user $P4USER --src clones/ --networkThreads 64 --printBatch 10 --port $P4PORT --lookAhead 2000 --retries 10 --refresh 100
 	return exec.CommandContext(ctx, "p4-fusion",
 		"--path", depot+"...",
 		"--client", s.FusionConfig.Client,
 		"--user", username,
 		"--src", src,
-		"--networkThreads", strconv.Itoa(s.FusionConfig.NetworkThreads),
+		"--networkThreads", strconv.Itoa(networkThreads),
 		"--printBatch", strconv.Itoa(s.FusionConfig.PrintBatch),
 		"--port", port,
 		"--lookAhead", strconv.Itoa(s.FusionConfig.LookAhead),
@@ -162,11 +167,40 @@ func (s *PerforceDepotSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir
 		return nil, errors.Wrap(err, "test with trust")
 	}
 
+	// Changelists are imported in order, so there is nothing to fetch if the last
+	// imported changelist is the latest submitted changelist of the depot. We
+	// fall back to a regular fetch if either of them can't be determined.
+	var output []byte
+	imported, _ := importedChangelist(ctx, dir)
+	latest, _ := p4LatestChangelist(ctx, host, username, password, depot)
+	if imported > 0 && latest > 0 && latest <= imported {
+		output = []byte(fmt.Sprintf("depot %s is up to date at changelist %d\n", depot, imported))
+	} else {
+		output, err = s.fetchChangelists(ctx, remoteURL, dir, host, username, password, depot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.ImportShelvedChangelists {
+		out, err := s.importShelvedChangelists(ctx, dir, host, username, password, depot)
+		if err != nil {
+			return nil, errors.Wrap(err, "import shelved changelists")
+		}
+		output = append(output, out...)
+	}
+
+	return output, nil
+}
+
+// fetchChangelists imports the changelists of the depot that have not been
+// imported yet.
+func (s *PerforceDepotSyncer) fetchChangelists(ctx context.Context, remoteURL *vcs.URL, dir common.GitDir, host, username, password, depot string) ([]byte, error) {
 	var cmd *wrexec.Cmd
 	if s.FusionConfig.Enabled {
 		// Example: p4-fusion --path //depot/... --user $P4USER --src clones/ --networkThreads 64 --printBatch 10 --port $P4PORT --lookAhead 2000 --retries 10 --refresh 100
 		root, _ := filepath.Split(string(dir))
-		cmd = wrexec.Wrap(ctx, nil, s.buildP4FusionCmd(ctx, depot, username, root+".git", host))
+		cmd = wrexec.Wrap(ctx, nil, s.buildP4FusionCmd(ctx, depot, username, root+".git", host, s.FusionConfig.NetworkThreadsFetch))
 	} else {
 		// Example: git p4 sync --max-changes 1000
 		args := append([]string{"p4", "sync"}, s.p4CommandOptions()...)
@@ -350,6 +384,100 @@ func p4depots(ctx context.Context, host, username, password, nameFilter string)
 	return depots, nil
 }
 
+// LatestChangelist returns the latest submitted changelist of the Perforce
+// depot, or 0 if the depot has no submitted changelists.
+func (s *PerforceDepotSyncer) LatestChangelist(ctx context.Context, remoteURL *vcs.URL) (int64, error) {
+	username, password, host, depot, err := decomposePerforceRemoteURL(remoteURL)
+	if err != nil {
+		return 0, errors.Wrap(err, "decompose")
+	}
+	return p4LatestChangelist(ctx, host, username, password, depot)
+}
+
+// p4LatestChangelist returns the latest submitted changelist of the depot, or 0
+// if the depot has no submitted changelists.
+func p4LatestChangelist(ctx context.Context, host, username, password, depot string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// Example: p4 -Mj -ztag changes -m1 -s submitted //Sourcegraph/...
+	cmd := exec.CommandContext(ctx, "p4", "-Mj", "-ztag", "changes", "-m1", "-s", "submitted", depot+"...")
+	cmd.Env = append(os.Environ(),
+		"P4PORT="+host,
+		"P4USER="+username,
+		"P4PASSWD="+password,
+	)
+
+	out, err := runCommandCombinedOutput(ctx, wrexec.Wrap(ctx, log.NoOp(), cmd))
+	if err != nil {
+		if ctxerr := ctx.Err(); ctxerr != nil {
+			err = ctxerr
+		}
+		if len(out) > 0 {
+			err = errors.Wrapf(err, `failed to run command "p4 changes" (output follows)\n\n%s`, specifyCommandInErrorMessage(string(out), cmd))
+		}
+		return 0, err
+	}
+
+	changes, err := parseP4Changes(out)
+	if err != nil || len(changes) == 0 {
+		return 0, err
+	}
+	return changes[0], nil
+}
+
+// parseP4Changes parses the changelist numbers from the output of
+// `p4 -Mj -ztag changes`, which is a series of JSON objects, one per line.
+func parseP4Changes(out []byte) ([]int64, error) {
+	var changes []int64
+	buf := bufio.NewScanner(bytes.NewBuffer(out))
+	for buf.Scan() {
+		var change struct {
+			Change string `json:"change"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &change); err != nil {
+			return nil, errors.Wrap(err, "malformed output from p4 changes")
+		}
+		id, err := strconv.ParseInt(change.Change, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "malformed changelist number from p4 changes")
+		}
+		changes = append(changes, id)
+	}
+	if err := buf.Err(); err != nil {
+		return nil, errors.Wrap(err, "malformed output from p4 changes")
+	}
+	return changes, nil
+}
+
+// changelistPattern matches the metadata that both git p4 and p4-fusion add to
+// the message of the commits they import, e.g.
+// [p4-fusion: depot-paths = "//Sourcegraph/": change = 48485]
+var changelistPattern = lazyregexp.New(`\[(?:git-p4|p4-fusion): depot-paths = "[^"]*": change = (\d+)`)
+
+// importedChangelist returns the changelist imported as the HEAD commit of the
+// repository, or 0 if the commit was not imported from Perforce.
+func importedChangelist(ctx context.Context, dir common.GitDir) (int64, error) {
+	cmd := exec.CommandContext(ctx, "git", "log", "-1", "--format=%B", "HEAD")
+	dir.Set(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, &common.GitCommandError{Err: err, Output: string(out)}
+	}
+	return parseImportedChangelist(string(out)), nil
+}
+
+// parseImportedChangelist returns the changelist of the Perforce metadata in the
+// commit message, or 0 if there is none.
+func parseImportedChangelist(message string) int64 {
+	m := changelistPattern.FindStringSubmatch(message)
+	if m == nil {
+		return 0
+	}
+	id, _ := strconv.ParseInt(m[1], 10, 64)
+	return id
+}
+
 func specifyCommandInErrorMessage(errorMsg string, command *exec.Cmd) string {
 	if !strings.Contains(errorMsg, "this operation") {
 		return errorMsg
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server/common"
+	"github.com/sourcegraph/sourcegraph/internal/wrexec"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// shelvedRefPrefix is the prefix of the hidden refs that shelved changelists are
+// imported as. They are not branches, so they don't show up in the UI and are
+// not indexed, but they can be resolved as revisions by review tooling.
+const shelvedRefPrefix = "refs/shelved/"
+
+// shelvedFile is a file of a shelved changelist.
+type shelvedFile struct {
+	DepotFile string
+	Action    string
+}
+
+// shelvedChangelist is the description of a shelved changelist, as returned by
+// `p4 -Mj -ztag describe -S -s`.
+type shelvedChangelist struct {
+	Change      int64
+	User        string
+	Time        time.Time
+	Description string
+	Files       []shelvedFile
+}
+
+// importShelvedChangelists imports the shelved changelists of the depot as
+// commits on top of HEAD, under refs of the form refs/shelved/<changelist>. The
+// refs of changelists that are no longer shelved are deleted.
+func (s *PerforceDepotSyncer) importShelvedChangelists(ctx context.Context, dir common.GitDir, host, username, password, depot string) ([]byte, error) {
+	env := s.p4CommandEnv(host, username, password)
+
+	// Example: p4 -Mj -ztag changes -s shelved //Sourcegraph/...
+	out, err := runP4(ctx, env, "-Mj", "-ztag", "changes", "-s", "shelved", depot+"...")
+	if err != nil {
+		return nil, err
+	}
+	changes, err := parseP4Changes(out)
+	if err != nil {
+		return nil, err
+	}
+
+	var output bytes.Buffer
+	shelved := make(map[string]struct{}, len(changes))
+	for _, change := range changes {
+		ref := shelvedRefPrefix + strconv.FormatInt(change, 10)
+		shelved[ref] = struct{}{}
+
+		out, err := runP4(ctx, env, "-Mj", "-ztag", "describe", "-S", "-s", strconv.FormatInt(change, 10))
+		if err != nil {
+			return nil, err
+		}
+		cl, err := parseShelvedChangelist(out)
+		if err != nil {
+			return nil, err
+		}
+
+		commit, err := s.commitShelvedChangelist(ctx, dir, env, depot, cl)
+		if err != nil {
+			return nil, errors.Wrapf(err, "importing shelved changelist %d", change)
+		}
+		if _, err := runGit(ctx, dir, nil, nil, "update-ref", ref, commit); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&output, "imported shelved changelist %d as %s\n", change, ref)
+	}
+
+	out, err = runGit(ctx, dir, nil, nil, "for-each-ref", "--format=%(refname)", shelvedRefPrefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range strings.Fields(string(out)) {
+		if _, ok := shelved[ref]; ok {
+			continue
+		}
+		if _, err := runGit(ctx, dir, nil, nil, "update-ref", "-d", ref); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&output, "deleted %s\n", ref)
+	}
+
+	return output.Bytes(), nil
+}
+
+// commitShelvedChangelist creates a commit with the files of the shelved
+// changelist on top of HEAD and returns its hash. Files outside of the depot
+// are ignored.
+func (s *PerforceDepotSyncer) commitShelvedChangelist(ctx context.Context, dir common.GitDir, env []string, depot string, cl *shelvedChangelist) (string, error) {
+	// The changelist is staged in a temporary index, so that the repository
+	// itself is left untouched.
+	index, err := os.CreateTemp("", "shelved-index")
+	if err != nil {
+		return "", err
+	}
+	index.Close()
+	defer os.Remove(index.Name())
+	indexEnv := []string{"GIT_INDEX_FILE=" + index.Name()}
+
+	if _, err := runGit(ctx, dir, indexEnv, nil, "read-tree", "HEAD"); err != nil {
+		return "", err
+	}
+
+	for _, f := range cl.Files {
+		if !strings.HasPrefix(f.DepotFile, depot) {
+			continue
+		}
+		path := filepath.ToSlash(strings.TrimPrefix(f.DepotFile, depot))
+
+		switch f.Action {
+		case "delete", "move/delete", "purge", "archive":
+			if _, err := runGit(ctx, dir, indexEnv, nil, "update-index", "--force-remove", "--", path); err != nil {
+				return "", err
+			}
+		default:
+			// Example: p4 print -q //Sourcegraph/README.md@=1234
+			content, err := runP4(ctx, env, "print", "-q", fmt.Sprintf("%s@=%d", f.DepotFile, cl.Change))
+			if err != nil {
+				return "", err
+			}
+			blob, err := runGit(ctx, dir, nil, content, "hash-object", "-w", "--stdin")
+			if err != nil {
+				return "", err
+			}
+			cacheInfo := fmt.Sprintf("100644,%s,%s", bytes.TrimSpace(blob), path)
+			if _, err := runGit(ctx, dir, indexEnv, nil, "update-index", "--add", "--cacheinfo", cacheInfo); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	tree, err := runGit(ctx, dir, indexEnv, nil, "write-tree")
+	if err != nil {
+		return "", err
+	}
+
+	date := cl.Time.Format(time.RFC3339)
+	commitEnv := []string{
+		"GIT_AUTHOR_NAME=" + cl.User,
+		"GIT_AUTHOR_EMAIL=" + cl.User,
+		"GIT_AUTHOR_DATE=" + date,
+		"GIT_COMMITTER_NAME=" + cl.User,
+		"GIT_COMMITTER_EMAIL=" + cl.User,
+		"GIT_COMMITTER_DATE=" + date,
+	}
+	message := fmt.Sprintf("%s\n\n[p4-shelved: depot-paths = %q: change = %d]\n", strings.TrimSpace(cl.Description), depot, cl.Change)
+	commit, err := runGit(ctx, dir, commitEnv, []byte(message), "commit-tree", string(bytes.TrimSpace(tree)), "-p", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSpace(commit)), nil
+}
+
+// parseShelvedChangelist parses the output of `p4 -Mj -ztag describe -S -s`,
+// which lists the files of the changelist as numbered fields, e.g. depotFile0,
+// action0, depotFile1, action1.
+func parseShelvedChangelist(out []byte) (*shelvedChangelist, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(out), &fields); err != nil {
+		return nil, errors.Wrap(err, "malformed output from p4 describe")
+	}
+	field := func(name string) string {
+		v, _ := fields[name].(string)
+		return v
+	}
+
+	change, err := strconv.ParseInt(field("change"), 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed changelist number from p4 describe")
+	}
+	cl := &shelvedChangelist{
+		Change:      change,
+		User:        field("user"),
+		Description: field("desc"),
+	}
+	if secs, err := strconv.ParseInt(field("time"), 10, 64); err == nil {
+		cl.Time = time.Unix(secs, 0).UTC()
+	}
+	for i := 0; ; i++ {
+		depotFile := field("depotFile" + strconv.Itoa(i))
+		if depotFile == "" {
+			break
+		}
+		cl.Files = append(cl.Files, shelvedFile{
+			DepotFile: depotFile,
+			Action:    field("action" + strconv.Itoa(i)),
+		})
+	}
+	return cl, nil
+}
+
+// runP4 runs a p4 command with the given environment and returns its output.
+func runP4(ctx context.Context, env []string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "p4", args...)
+	cmd.Env = env
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if _, err := runCommand(ctx, wrexec.Wrap(ctx, log.NoOp(), cmd)); err != nil {
+		return nil, errors.Wrapf(err, "failed to run command %q (output follows)\n\n%s", strings.Join(cmd.Args, " "), specifyCommandInErrorMessage(stderr.String(), cmd))
+	}
+	return stdout.Bytes(), nil
+}
+
+// runGit runs a git command in dir with the given additional environment and
+// standard input, and returns its output.
+func runGit(ctx context.Context, dir common.GitDir, env []string, stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	dir.Set(cmd)
+	cmd.Env = append(os.Environ(), env...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if _, err := runCommand(ctx, wrexec.Wrap(ctx, log.NoOp(), cmd)); err != nil {
+		return nil, &common.GitCommandError{Err: err, Output: stderr.String()}
+	}
+	return stdout.Bytes(), nil
+}
@@ -4,8 +4,10 @@ import (
 	"os/exec"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/sourcegraph/sourcegraph/internal/vcs"
 )
@@ -175,3 +177,44 @@ func TestP4DepotSyncer_p4CommandEnv(t *testing.T) {
 	assertEnv("P4USER", "username")
 	assertEnv("P4PASSWD", "password")
 }
+
+func TestParseImportedChangelist(t *testing.T) {
+	tests := map[string]int64{
+		"Add README\n\n[p4-fusion: depot-paths = \"//Sourcegraph/\": change = 48485]":    48485,
+		"Add README\n[git-p4: depot-paths = \"//Sourcegraph/\": change = 1012]":          1012,
+		"Add README[p4-fusion: depot-paths = \"//Sourcegraph/\": change = 83733]":        83733,
+		"Add README\n\n[p4-shelved: depot-paths = \"//Sourcegraph/\": change = 83734]\n": 0,
+		"Add README": 0,
+	}
+	for message, want := range tests {
+		assert.Equal(t, want, parseImportedChangelist(message), message)
+	}
+}
+
+func TestParseP4Changes(t *testing.T) {
+	out := []byte(`{"change":"1234","changeType":"public","client":"admin-ws","desc":"Add README\n","status":"submitted","time":"1682515463","user":"admin"}
+{"change":"1233","changeType":"public","client":"admin-ws","desc":"Initial commit\n","status":"submitted","time":"1682515400","user":"admin"}
+`)
+	changes, err := parseP4Changes(out)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1234, 1233}, changes)
+
+	_, err = parseP4Changes([]byte(`{"change":"abc"}`))
+	assert.Error(t, err)
+}
+
+func TestParseShelvedChangelist(t *testing.T) {
+	out := []byte(`{"change":"1235","user":"admin","client":"admin-ws","time":"1682515463","desc":"Fix typo\n","status":"pending","shelved":"","depotFile0":"//Sourcegraph/README.md","action0":"edit","type0":"text","rev0":"1","depotFile1":"//Sourcegraph/old.txt","action1":"delete","type1":"text","rev1":"2"}`)
+	cl, err := parseShelvedChangelist(out)
+	require.NoError(t, err)
+	assert.Equal(t, &shelvedChangelist{
+		Change:      1235,
+		User:        "admin",
+		Time:        time.Unix(1682515463, 0).UTC(),
+		Description: "Fix typo\n",
+		Files: []shelvedFile{
+			{DepotFile: "//Sourcegraph/README.md", Action: "edit"},
+			{DepotFile: "//Sourcegraph/old.txt", Action: "delete"},
+		},
+	}, cl)
+}
@@ -50,6 +50,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/redispool/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/repos"
 	"github.com/sourcegraph/sourcegraph/internal/requestclient"
 	"github.com/sourcegraph/sourcegraph/internal/service"
@@ -119,6 +120,9 @@ func Main(ctx context.Context, observationCtx *observation.Context, ready servic
 		return errors.Wrap(err, "initializing database stores")
 	}
 	db := database.NewDB(observationCtx.Logger, sqlDB)
+	if err := dbstore.Register(db); err != nil {
+		return errors.Wrap(err, "failed to register postgres backed redis")
+	}
 
 	repoStore := db.Repos()
 	dependenciesSvc := dependencies.NewService(observationCtx, db)
@@ -176,6 +180,10 @@ func Main(ctx context.Context, observationCtx *observation.Context, ready servic
 		RecordingCommandFactory: recordingCommandFactory,
 	}
 
+	accesslog.RepoOnLegalHold = func(ctx context.Context, repo string) bool {
+		return gitserver.RepoOnLegalHold(ctx, api.RepoName(repo))
+	}
+
 	configurationWatcher := conf.DefaultClient()
 
 	var additionalServerOptions []grpc.ServerOption
@@ -445,6 +453,8 @@ func getVCSSyncer(ctx context.Context, opts *newVCSSyncerOpts) (server.VCSSyncer
 			Client:       c.P4Client,
 			FusionConfig: configureFusionClient(c),
 			P4Home:       p4Home,
+
+			ImportShelvedChangelists: c.ImportShelvedChangelists,
 		}, nil
 	case extsvc.TypeJVMPackages:
 		var c schema.JVMPackagesConnection
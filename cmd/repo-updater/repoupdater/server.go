@@ -62,6 +62,7 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/enqueue-changeset-sync", trace.WithRouteName("enqueue-changeset-sync", s.handleEnqueueChangesetSync))
 	mux.HandleFunc("/external-service-namespaces", trace.WithRouteName("external-service-namespaces", s.handleExternalServiceNamespaces))
 	mux.HandleFunc("/external-service-repositories", trace.WithRouteName("external-service-repositories", s.handleExternalServiceRepositories))
+	mux.HandleFunc("/perforce-depot-progress", trace.WithRouteName("perforce-depot-progress", s.handlePerforceDepotProgress))
 	return mux
 }
 
@@ -575,3 +576,41 @@ func (s *Server) NewGenericSourcer(logger log.Logger) repos.Sourcer {
 	cf := httpcli.NewExternalClientFactory(httpcli.NewLoggingMiddleware(sourcerLogger))
 	return repos.NewSourcer(sourcerLogger, db, cf, repos.WithDependenciesService(dependenciesService))
 }
+
+func (s *Server) handlePerforceDepotProgress(w http.ResponseWriter, r *http.Request) {
+	var req protocol.PerforceDepotProgressArgs
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	logger := s.Logger.With(log.Int64("ExternalServiceID", req.ExternalServiceID))
+
+	depots, err := s.perforceDepotProgress(r.Context(), logger, req.ExternalServiceID)
+	if err != nil {
+		logger.Error("server.perforce-depot-progress", log.Error(err))
+		httpCode := http.StatusInternalServerError
+		if errcode.IsNotFound(err) {
+			httpCode = http.StatusNotFound
+		}
+		s.respond(w, httpCode, &protocol.PerforceDepotProgressResult{Error: err.Error()})
+		return
+	}
+	s.respond(w, http.StatusOK, &protocol.PerforceDepotProgressResult{Depots: depots})
+}
+
+func (s *Server) perforceDepotProgress(ctx context.Context, logger log.Logger, externalServiceID int64) ([]protocol.PerforceDepotProgress, error) {
+	svc, err := s.ExternalServiceStore().GetByID(ctx, externalServiceID)
+	if err != nil {
+		return nil, err
+	}
+	if svc.Kind != extsvc.KindPerforce {
+		return nil, errors.Newf("external service %d is not a Perforce connection", externalServiceID)
+	}
+
+	src, err := repos.NewPerforceSource(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+	return src.DepotProgress(ctx, logger, s.Store)
+}
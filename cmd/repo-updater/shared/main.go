@@ -43,6 +43,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/instrumentation"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/redispool/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/repos"
 	proto "github.com/sourcegraph/sourcegraph/internal/repoupdater/v1"
 	"github.com/sourcegraph/sourcegraph/internal/service"
@@ -98,6 +99,9 @@ func Main(ctx context.Context, observationCtx *observation.Context, ready servic
 		return errors.Wrap(err, "initializing database store")
 	}
 	db := database.NewDB(logger, sqlDB)
+	if err := dbstore.Register(db); err != nil {
+		return errors.Wrap(err, "failed to register postgres backed redis")
+	}
 
 	// Generally we'll mark the service as ready sometime after the database has been
 	// connected; migrations may take a while and we don't want to start accepting
@@ -195,6 +199,9 @@ func Main(ctx context.Context, observationCtx *observation.Context, ready servic
 	go repos.RunScheduler(ctx, logger, updateScheduler)
 	logger.Debug("started scheduler")
 
+	// Archives inactive repositories and reduces their sync frequency
+	repositoryArchiver := repos.NewRepositoryArchiver(ctx, logger, db, updateScheduler)
+
 	host := ""
 	if env.InsecureDev {
 		host = "127.0.0.1"
@@ -247,7 +254,7 @@ func Main(ctx context.Context, observationCtx *observation.Context, ready servic
 		Handler: instrumentation.HTTPMiddleware("",
 			trace.HTTPMiddleware(logger, authzBypass(handler), conf.DefaultClient())),
 	})
-	goroutine.MonitorBackgroundRoutines(ctx, httpSrv)
+	goroutine.MonitorBackgroundRoutines(ctx, httpSrv, repositoryArchiver)
 
 	return nil
 }
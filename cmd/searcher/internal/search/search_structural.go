@@ -10,7 +10,6 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
-	"strings"
 	"time"
 
 	"github.com/RoaringBitmap/roaring"
@@ -24,7 +23,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/comby"
-	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -201,97 +199,6 @@ func chunksToMatches(buf []byte, chunks []rangeChunk) []protocol.ChunkMatch {
 	return chunkMatches
 }
 
-var isValidMatcher = lazyregexp.New(`\.(s|sh|bib|c|cs|css|dart|clj|elm|erl|ex|f|fsx|go|html|hs|java|js|json|jl|kt|tex|lisp|nim|md|ml|org|pas|php|py|re|rb|rs|rst|scala|sql|swift|tex|txt|ts)$`)
-
-func extensionToMatcher(extension string) string {
-	if isValidMatcher.MatchString(extension) {
-		return extension
-	}
-	return ".generic"
-}
-
-// lookupMatcher looks up a key for specifying -matcher in comby. Comby accepts
-// a representative file extension to set a language, so this lookup does not
-// need to consider all possible file extensions for a language. There is a generic
-// fallback language, so this lookup does not need to be exhaustive either.
-func lookupMatcher(language string) string {
-	switch strings.ToLower(language) {
-	case "assembly", "asm":
-		return ".s"
-	case "bash":
-		return ".sh"
-	case "c":
-		return ".c"
-	case "c#, csharp":
-		return ".cs"
-	case "css":
-		return ".css"
-	case "dart":
-		return ".dart"
-	case "clojure":
-		return ".clj"
-	case "elm":
-		return ".elm"
-	case "erlang":
-		return ".erl"
-	case "elixir":
-		return ".ex"
-	case "fortran":
-		return ".f"
-	case "f#", "fsharp":
-		return ".fsx"
-	case "go":
-		return ".go"
-	case "html":
-		return ".html"
-	case "haskell":
-		return ".hs"
-	case "java":
-		return ".java"
-	case "javascript":
-		return ".js"
-	case "json":
-		return ".json"
-	case "julia":
-		return ".jl"
-	case "kotlin":
-		return ".kt"
-	case "laTeX":
-		return ".tex"
-	case "lisp":
-		return ".lisp"
-	case "nim":
-		return ".nim"
-	case "ocaml":
-		return ".ml"
-	case "pascal":
-		return ".pas"
-	case "php":
-		return ".php"
-	case "python":
-		return ".py"
-	case "reason":
-		return ".re"
-	case "ruby":
-		return ".rb"
-	case "rust":
-		return ".rs"
-	case "scala":
-		return ".scala"
-	case "sql":
-		return ".sql"
-	case "swift":
-		return ".swift"
-	case "text":
-		return ".txt"
-	case "typescript", "ts":
-		return ".ts"
-	case "xml":
-		return ".xml"
-	}
-	return ".generic"
-}
-
 func structuralSearchWithZoekt(ctx context.Context, indexed zoekt.Streamer, p *protocol.Request, sender matchSender) (err error) {
 	patternInfo := &search.TextPatternInfo{
 		Pattern:                      p.Pattern,
@@ -361,13 +268,13 @@ func toMatcher(languages []string, extensionHint string) string {
 	if len(languages) > 0 {
 		// Pick the first language, there is no support for applying
 		// multiple language matchers in a single search query.
-		matcher := lookupMatcher(languages[0])
+		matcher := comby.LookupMatcher(languages[0])
 		metricRequestTotalStructuralSearch.WithLabelValues(matcher).Inc()
 		return matcher
 	}
 
 	if extensionHint != "" {
-		extension := extensionToMatcher(extensionHint)
+		extension := comby.ExtensionToMatcher(extensionHint)
 		metricRequestTotalStructuralSearch.WithLabelValues("inferred:" + extension).Inc()
 		return extension
 	}
@@ -53,6 +53,11 @@ func maybeRedisProcFile(c redisProcfileConfig) (string, error) {
 		return "", nil
 	}
 
+	// Redis is replaced by postgres or memory.
+	if backend := os.Getenv("SRC_KEY_VALUE_BACKEND"); backend != "" && backend != "redis" {
+		return "", nil
+	}
+
 	conf, err := tryCreateRedisConf(c)
 	if err != nil {
 		return "", err
@@ -27,6 +27,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/httpserver"
 	"github.com/sourcegraph/sourcegraph/internal/instrumentation"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/redispool/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/service"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -74,6 +75,9 @@ func Main(ctx context.Context, observationCtx *observation.Context, ready servic
 	// Initialize main DB connection.
 	sqlDB := mustInitializeFrontendDB(observationCtx)
 	db := database.NewDB(logger, sqlDB)
+	if err := dbstore.Register(db); err != nil {
+		return errors.Wrap(err, "failed to register postgres backed redis")
+	}
 
 	// Run setup
 	gitserverClient := gitserver.NewClient(observationCtx)
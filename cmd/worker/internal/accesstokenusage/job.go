@@ -0,0 +1,155 @@
+package accesstokenusage
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// usageJob is a worker responsible for raising alerts when the usage of an
+// access token changes sharply, and for deleting old access token usage.
+type usageJob struct{}
+
+var _ job.Job = &usageJob{}
+
+func NewJob() job.Job {
+	return &usageJob{}
+}
+
+func (j *usageJob) Description() string {
+	return "raises access token usage alerts and deletes old access token usage"
+}
+
+func (j *usageJob) Config() []env.Config {
+	return nil
+}
+
+const (
+	// alertInterval is the interval at which the usage of the previous hour is
+	// checked for spikes. Alerts are raised at most once per access token and
+	// hour, so the same hour is checked several times to tolerate restarts.
+	alertInterval = 15 * time.Minute
+	// baselinePeriod is the period over which the usual hourly usage of access
+	// tokens is computed.
+	baselinePeriod = 7 * 24 * time.Hour
+	// retention is the time access token usage and alerts are kept.
+	retention = 90 * 24 * time.Hour
+
+	defaultSpikeFactor     = 10
+	defaultMinimumRequests = 1000
+)
+
+func (j *usageJob) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := actor.WithInternalActor(context.Background())
+	logger := observationCtx.Logger
+
+	return []goroutine.BackgroundRoutine{
+		goroutine.NewPeriodicGoroutine(
+			ctx,
+			&alertHandler{db: db, logger: logger, now: time.Now},
+			goroutine.WithName("accesstokenusage.alerter"),
+			goroutine.WithDescription("raises alerts when the hourly usage of an access token is far above its usual hourly usage"),
+			goroutine.WithInterval(alertInterval),
+		),
+		goroutine.NewPeriodicGoroutine(
+			ctx,
+			&janitorHandler{store: db.AccessTokenUsage(), now: time.Now},
+			goroutine.WithName("accesstokenusage.janitor"),
+			goroutine.WithDescription("deletes access token usage and alerts after the retention period"),
+			goroutine.WithInterval(time.Hour),
+		),
+	}, nil
+}
+
+type alertHandler struct {
+	db     database.DB
+	logger log.Logger
+	now    func() time.Time
+}
+
+var (
+	_ goroutine.Handler      = &alertHandler{}
+	_ goroutine.ErrorHandler = &alertHandler{}
+)
+
+func (h *alertHandler) Handle(ctx context.Context) error {
+	accessTokens := conf.Get().AuthAccessTokens
+	if accessTokens == nil || accessTokens.UsageAlerts == nil {
+		return nil
+	}
+
+	spikeFactor := accessTokens.UsageAlerts.SpikeFactor
+	if spikeFactor == 0 {
+		spikeFactor = defaultSpikeFactor
+	}
+	minimumRequests := accessTokens.UsageAlerts.MinimumRequests
+	if minimumRequests == 0 {
+		minimumRequests = defaultMinimumRequests
+	}
+
+	// The usage of the current hour is still being recorded.
+	bucket := h.now().UTC().Truncate(time.Hour).Add(-time.Hour)
+	alerts, err := h.db.AccessTokenUsage().CreateSpikeAlerts(ctx, bucket, baselinePeriod, spikeFactor, minimumRequests)
+	if err != nil {
+		return err
+	}
+
+	for _, alert := range alerts {
+		h.logger.Warn("access token usage spike",
+			log.Int64("accessTokenID", alert.AccessTokenID),
+			log.Int32("userID", alert.SubjectUserID),
+			log.Int("requestCount", alert.RequestCount),
+			log.Float64("baselineRequestCount", alert.BaselineRequestCount),
+		)
+
+		args, err := json.Marshal(map[string]any{
+			"access_token_id":        alert.AccessTokenID,
+			"hour":                   alert.Bucket,
+			"request_count":          alert.RequestCount,
+			"baseline_request_count": alert.BaselineRequestCount,
+		})
+		if err != nil {
+			return err
+		}
+		h.db.SecurityEventLogs().LogEvent(ctx, &database.SecurityEvent{
+			Name:      database.SecurityEventAccessTokenUsageSpike,
+			UserID:    uint32(alert.SubjectUserID),
+			Argument:  args,
+			Source:    "BACKEND",
+			Timestamp: h.now(),
+		})
+	}
+
+	return nil
+}
+
+func (h *alertHandler) HandleError(err error) {
+	h.logger.Error("error raising access token usage alerts", log.Error(err))
+}
+
+type janitorHandler struct {
+	store database.AccessTokenUsageStore
+	now   func() time.Time
+}
+
+var _ goroutine.Handler = &janitorHandler{}
+
+func (h *janitorHandler) Handle(ctx context.Context) error {
+	return h.store.DeleteBefore(ctx, h.now().Add(-retention))
+}
@@ -12,6 +12,7 @@ type config struct {
 	EncryptionInterval time.Duration
 	MetricsInterval    time.Duration
 	Decrypt            bool
+	RotateKeys         bool
 }
 
 var ConfigInst = &config{}
@@ -20,4 +21,5 @@ func (c *config) Load() {
 	c.EncryptionInterval = c.GetInterval("RECORD_ENCRYPTER_INTERVAL", "1s", "How frequently to encrypt/decrypt a batch of records in the database.")
 	c.MetricsInterval = c.GetInterval("RECORD_ENCRYPTER_METRICS_INTERVAL", "10s", "How frequently to update progress metrics related to encryption/decryption.")
 	c.Decrypt = c.GetBool("ALLOW_DECRYPTION", "false", "If true, encrypted records will be decrypted and stored in plaintext.")
+	c.RotateKeys = c.GetBool("RECORD_ENCRYPTER_ROTATE_KEYS", "true", "If true, records encrypted with a previous version of a key will be re-encrypted with its current version.")
 }
@@ -22,13 +22,14 @@ var (
 
 func (c *recordCounter) Handle(ctx context.Context) (err error) {
 	for _, config := range database.EncryptionConfigs {
-		numEncrypted, numUnencrypted, err := c.store.Count(ctx, config)
+		progress, err := c.store.Progress(ctx, config)
 		if err != nil {
 			return err
 		}
 
-		c.metrics.numEncryptedAtRest.WithLabelValues(config.TableName).Set(float64(numEncrypted))
-		c.metrics.numUnencryptedAtRest.WithLabelValues(config.TableName).Set(float64(numUnencrypted))
+		c.metrics.numEncryptedAtRest.WithLabelValues(config.TableName).Set(float64(progress.Outdated + progress.Current))
+		c.metrics.numUnencryptedAtRest.WithLabelValues(config.TableName).Set(float64(progress.Unencrypted))
+		c.metrics.numOutdatedAtRest.WithLabelValues(config.TableName).Set(float64(progress.Outdated))
 	}
 
 	return err
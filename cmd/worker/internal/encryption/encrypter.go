@@ -14,6 +14,7 @@ import (
 type recordEncrypter struct {
 	store   *database.RecordEncrypter
 	decrypt bool
+	rotate  bool
 	metrics *metrics
 	logger  log.Logger
 }
@@ -38,7 +39,13 @@ func (e *recordEncrypter) handleBatch(ctx context.Context, config database.Encry
 		return e.handleDecryptBatch(ctx, config)
 	}
 
-	return e.handleEncryptBatch(ctx, config)
+	if err := e.handleEncryptBatch(ctx, config); err != nil {
+		return err
+	}
+	if e.rotate {
+		return e.handleRotateBatch(ctx, config)
+	}
+	return nil
 }
 
 func (e *recordEncrypter) handleEncryptBatch(ctx context.Context, config database.EncryptionConfig) error {
@@ -52,6 +59,17 @@ func (e *recordEncrypter) handleEncryptBatch(ctx context.Context, config databas
 	return nil
 }
 
+func (e *recordEncrypter) handleRotateBatch(ctx context.Context, config database.EncryptionConfig) error {
+	count, err := e.store.RotateBatch(ctx, config)
+	if err != nil || count == 0 {
+		return err
+	}
+
+	e.metrics.numRecordsRotated.WithLabelValues(config.TableName).Add(float64(count))
+	e.logger.Debug("re-encrypted records with current key version", log.String("tableName", config.TableName), log.Int("count", count))
+	return nil
+}
+
 func (e *recordEncrypter) handleDecryptBatch(ctx context.Context, config database.EncryptionConfig) error {
 	count, err := e.store.DecryptBatch(ctx, config)
 	if err != nil || count == 0 {
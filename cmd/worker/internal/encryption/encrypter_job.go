@@ -42,11 +42,12 @@ func (j *recordEncrypterJob) Routines(_ context.Context, observationCtx *observa
 			&recordEncrypter{
 				store:   store,
 				decrypt: ConfigInst.Decrypt,
+				rotate:  ConfigInst.RotateKeys,
 				metrics: metrics,
 				logger:  observationCtx.Logger,
 			},
 			goroutine.WithName("encryption.record-encrypter"),
-			goroutine.WithDescription("encrypts/decrypts existing data when a key is provided/removed"),
+			goroutine.WithDescription("encrypts/decrypts existing data when a key is provided/removed, and re-encrypts it when a key is rotated"),
 			goroutine.WithInterval(ConfigInst.EncryptionInterval),
 		),
 		goroutine.NewPeriodicGoroutine(
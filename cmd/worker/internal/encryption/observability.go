@@ -11,10 +11,12 @@ type metrics struct {
 	// current state
 	numEncryptedAtRest   *prometheus.GaugeVec
 	numUnencryptedAtRest *prometheus.GaugeVec
+	numOutdatedAtRest    *prometheus.GaugeVec
 
 	// processing status
 	numRecordsEncrypted *prometheus.CounterVec
 	numRecordsDecrypted *prometheus.CounterVec
+	numRecordsRotated   *prometheus.CounterVec
 	numErrors           prometheus.Counter
 }
 
@@ -57,6 +59,10 @@ func newMetrics(observationCtx *observation.Context) *metrics {
 		"src_records_unencrypted_at_rest_total",
 		"The number of database records unencrypted at rest.",
 	)
+	numOutdatedAtRest := gaugeVec(
+		"src_records_encrypted_with_outdated_key_total",
+		"The number of database records encrypted at rest with a previous version of their key.",
+	)
 	numRecordsEncrypted := counterVec(
 		"src_records_encrypted_total",
 		"The number of unencrypted database records that have been encrypted.",
@@ -65,6 +71,10 @@ func newMetrics(observationCtx *observation.Context) *metrics {
 		"src_records_decrypted_total",
 		"The number of encrypted database records that have been decrypted.",
 	)
+	numRecordsRotated := counterVec(
+		"src_records_reencrypted_total",
+		"The number of encrypted database records that have been re-encrypted with the current version of their key.",
+	)
 	numErrors := counter(
 		"src_record_encryption_errors_total",
 		"The number of errors that occur during record encryption/decryption.",
@@ -74,13 +84,16 @@ func newMetrics(observationCtx *observation.Context) *metrics {
 		// Initialize counters to zero
 		numRecordsEncrypted.WithLabelValues(config.TableName).Add(0)
 		numRecordsDecrypted.WithLabelValues(config.TableName).Add(0)
+		numRecordsRotated.WithLabelValues(config.TableName).Add(0)
 	}
 
 	return &metrics{
 		numEncryptedAtRest:   numEncryptedAtRest,
 		numUnencryptedAtRest: numUnencryptedAtRest,
+		numOutdatedAtRest:    numOutdatedAtRest,
 		numRecordsEncrypted:  numRecordsEncrypted,
 		numRecordsDecrypted:  numRecordsDecrypted,
+		numRecordsRotated:    numRecordsRotated,
 		numErrors:            numErrors,
 	}
 }
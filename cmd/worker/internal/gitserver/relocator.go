@@ -0,0 +1,43 @@
+package gitserver
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/relocator"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type relocatorJob struct{}
+
+// NewRelocatorJob returns the job moving repositories between gitservers, as requested
+// by site admins through the GraphQL API.
+func NewRelocatorJob() job.Job {
+	return &relocatorJob{}
+}
+
+func (j *relocatorJob) Description() string {
+	return "Moves repositories between gitservers."
+}
+
+func (j *relocatorJob) Config() []env.Config {
+	return nil
+}
+
+func (j *relocatorJob) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	observationCtx = observation.NewContext(observationCtx.Logger.Scoped("relocator", "gitserver relocator"))
+	ctx := actor.WithInternalActor(context.Background())
+
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, errors.Wrap(err, "initialising database")
+	}
+
+	return relocator.NewWorker(ctx, observationCtx, db, gitserver.NewClient()), nil
+}
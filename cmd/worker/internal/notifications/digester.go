@@ -0,0 +1,53 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/notifications"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// digester is a worker responsible for sending the email digests of
+// notifications.
+type digester struct{}
+
+var _ job.Job = &digester{}
+
+func NewDigester() job.Job {
+	return &digester{}
+}
+
+func (j *digester) Description() string {
+	return "sends the email digests of notifications"
+}
+
+func (j *digester) Config() []env.Config {
+	return nil
+}
+
+func (j *digester) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	notifier := notifications.NewNotifier(observationCtx.Logger, db)
+
+	return []goroutine.BackgroundRoutine{
+		// Digests are sent once a day, so checking every hour sends them at most
+		// an hour late.
+		goroutine.NewPeriodicGoroutine(
+			actor.WithInternalActor(context.Background()),
+			goroutine.HandlerFunc(notifier.SendDigests),
+			goroutine.WithName("notifications.digester"),
+			goroutine.WithDescription("sends the email digests of notifications"),
+			goroutine.WithInterval(time.Hour),
+		),
+	}, nil
+}
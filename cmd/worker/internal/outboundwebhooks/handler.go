@@ -3,11 +3,10 @@ package outboundwebhooks
 import (
 	"bytes"
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"io"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/sourcegraph/conc/pool"
 	"github.com/sourcegraph/log"
@@ -17,13 +16,45 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/webhooks/outbound"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
+// maxDeliveryAttempts is the number of times the payload of a job is sent to
+// each matching outbound webhook before giving up.
+const maxDeliveryAttempts = 8
+
+const (
+	initialRetryDelay = 30 * time.Second
+	maxRetryDelay     = time.Hour
+)
+
+// retryDelay returns the delay before retrying a failed delivery, which doubles
+// with every attempt.
+func retryDelay(attempt int) time.Duration {
+	delay := initialRetryDelay
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// deliveryError is returned when the payload was sent to a webhook, but the
+// webhook didn't receive it. Only these failures are retried: other errors,
+// such as a disallowed webhook URL, won't go away by themselves.
+type deliveryError struct{ error }
+
+func (e deliveryError) Unwrap() error { return e.error }
+
 type handler struct {
-	client   *http.Client
-	store    database.OutboundWebhookStore
-	logStore database.OutboundWebhookLogStore
+	client      *http.Client
+	store       database.OutboundWebhookStore
+	logStore    database.OutboundWebhookLogStore
+	workerStore store.Store[*types.OutboundWebhookJob]
+	now         func() time.Time
 }
 
 var _ workerutil.Handler[*types.OutboundWebhookJob] = &handler{}
@@ -48,21 +79,65 @@ func (h *handler) Handle(ctx context.Context, logger log.Logger, job *types.Outb
 		return errors.Wrap(err, "retrieving outbound webhooks")
 	}
 
+	// Jobs are requeued when a delivery fails, so we need to skip the webhooks
+	// that already received the payload or ran out of attempts.
+	deliveries, err := h.logStore.DeliveriesForJob(ctx, job.ID)
+	if err != nil {
+		logger.Error("error retrieving outbound webhook deliveries", log.Error(err))
+		return errors.Wrap(err, "retrieving outbound webhook deliveries")
+	}
+
+	var (
+		mu sync.Mutex
+		// retryAttempt is the lowest attempt of the failed deliveries that
+		// can be retried, or zero if there are none.
+		retryAttempt int
+	)
+
 	// Since sending HTTP requests is (generally) cheap, and we've already done
 	// the relatively expensive parts of constructing the payload and retrieving
 	// the matching hooks, we're going to just fan these out with a high
 	// concurrency limit.
 	p := pool.New().WithContext(ctx).WithMaxGoroutines(100)
 	for _, webhook := range webhooks {
-		p.Go(h.buildWebhookSender(
-			logger.With(log.Int64("webhook.id", webhook.ID)),
+		delivery := deliveries[webhook.ID]
+		if delivery.Delivered || delivery.Attempts >= maxDeliveryAttempts {
+			continue
+		}
+
+		attempt := delivery.Attempts + 1
+		send := h.buildWebhookSender(
+			logger.With(log.Int64("webhook.id", webhook.ID), log.Int("attempt", attempt)),
 			job, webhook,
-		))
+		)
+		p.Go(func(ctx context.Context) error {
+			err := send(ctx)
+			if errors.HasType(err, deliveryError{}) && attempt < maxDeliveryAttempts {
+				mu.Lock()
+				if retryAttempt == 0 || attempt < retryAttempt {
+					retryAttempt = attempt
+				}
+				mu.Unlock()
+			}
+			return err
+		})
 	}
 
 	// Errors will have been logged individually, so we can just return the
-	// error back out of the handler.
-	return p.Wait()
+	// error back out of the handler once no attempts are left.
+	if err := p.Wait(); err != nil {
+		if retryAttempt == 0 {
+			return err
+		}
+
+		after := h.now().Add(retryDelay(retryAttempt))
+		logger.Info("retrying failed outbound webhook deliveries", log.Error(err), log.Time("after", after))
+		if err := h.workerStore.Requeue(ctx, int(job.ID), after); err != nil {
+			return errors.Wrap(err, "requeueing job")
+		}
+	}
+
+	return nil
 }
 
 func (h *handler) buildWebhookSender(
@@ -109,28 +184,17 @@ func (h *handler) sendWebhook(
 		return errors.Wrap(err, "decrypting payload")
 	}
 
-	// Second, we need to generate a signature based on the shared secret and
-	// the payload contents.
-	payloadReader := bytes.NewReader([]byte(payload))
-	sig, err := calculateSignature(secret, payloadReader)
-	if err != nil {
-		logger.Error("error signing payload", log.Error(err))
-		return errors.Wrap(err, "calculating payload signature")
-	}
-	payloadReader.Seek(0, io.SeekStart)
-
-	// Third, we build the HTTP request.
-	req, err := http.NewRequestWithContext(ctx, "POST", url, payloadReader)
+	// Second, we build the HTTP request, signed with the shared secret.
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader([]byte(payload)))
 	if err != nil {
 		logger.Error("cannot build webhook request", log.Error(err))
 		return errors.Wrap(err, "building request")
 	}
 
 	req.Header.Add("Content-Type", "application/json; charset=utf-8")
-	req.Header.Add("X-Sourcegraph-Webhook-Event-Type", job.EventType)
-	req.Header.Add("X-Sourcegraph-Webhook-Signature", sig)
+	outbound.SetHeaders(req.Header, job.ID, job.EventType, secret, h.now(), []byte(payload))
 
-	// Fourth, we set up the outbound webhook logging, since at this point we
+	// Third, we set up the outbound webhook logging, since at this point we
 	// now know we'll send the request.
 	webhookLog := &types.OutboundWebhookLog{
 		JobID:             job.ID,
@@ -153,22 +217,22 @@ func (h *handler) sendWebhook(
 		}
 	}()
 
-	// Fifth, we actually send the request.
+	// Fourth, we actually send the request.
 	resp, err := h.client.Do(req)
 	if err != nil {
 		logger.Info("error sending webhook", log.Error(err))
 		webhookLog.Error = encryption.NewUnencrypted(err.Error())
-		return errors.Wrap(err, "sending webhook")
+		return deliveryError{errors.Wrap(err, "sending webhook")}
 	}
 
-	// Sixth, we process the response for logging purposes.
+	// Fifth, we process the response for logging purposes.
 	defer resp.Body.Close()
 	webhookLog.StatusCode = resp.StatusCode
 
 	response, err := io.ReadAll(resp.Body)
 	if err != nil {
 		logger.Error("cannot read response body", log.Error(err))
-		return errors.Wrap(err, "reading response body")
+		return deliveryError{errors.Wrap(err, "reading response body")}
 	}
 	webhookLog.Response = types.NewUnencryptedWebhookLogMessage(types.WebhookLogMessage{
 		Header: resp.Header,
@@ -179,18 +243,9 @@ func (h *handler) sendWebhook(
 
 	if resp.StatusCode >= http.StatusBadRequest {
 		logger.Info("got unexpected status code from webhook", log.Int("status_code", resp.StatusCode))
-		return errors.Errorf("unexpected status code: %d", resp.StatusCode)
+		return deliveryError{errors.Errorf("unexpected status code: %d", resp.StatusCode)}
 	}
 
 	logger.Debug("webhook sent successfully")
 	return nil
 }
-
-func calculateSignature(secret string, payload io.Reader) (string, error) {
-	mac := hmac.New(sha256.New, []byte(secret))
-	if _, err := io.Copy(mac, payload); err != nil {
-		return "", err
-	}
-
-	return hex.EncodeToString(mac.Sum(nil)), nil
-}
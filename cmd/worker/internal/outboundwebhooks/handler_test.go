@@ -8,6 +8,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
 	"github.com/sourcegraph/log/logtest"
@@ -17,6 +18,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/encryption"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/webhooks/outbound"
+	dbworkerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -34,6 +37,7 @@ func TestHandler_Handle(t *testing.T) {
 
 		happyServer := newMockServer(t, payload, http.StatusOK)
 		sadServer := newMockServer(t, payload, http.StatusInternalServerError)
+		now := time.Now()
 
 		job := &types.OutboundWebhookJob{
 			ID:        1,
@@ -56,6 +60,7 @@ func TestHandler_Handle(t *testing.T) {
 		store.ListFunc.SetDefaultReturn([]*types.OutboundWebhook{happyWebhook, sadWebhook}, nil)
 
 		logStore := database.NewMockOutboundWebhookLogStore()
+		logStore.DeliveriesForJobFunc.SetDefaultReturn(map[int64]database.OutboundWebhookDelivery{}, nil)
 		webhooksSeen := newSeen[int64]()
 		logStore.CreateFunc.SetDefaultHook(func(ctx context.Context, log *types.OutboundWebhookLog) error {
 			assert.Equal(t, job.ID, log.JobID)
@@ -77,18 +82,24 @@ func TestHandler_Handle(t *testing.T) {
 			return nil
 		})
 
+		workerStore := dbworkerstoremocks.NewMockStore[*types.OutboundWebhookJob]()
+
 		h := &handler{
-			client:   http.DefaultClient,
-			store:    store,
-			logStore: logStore,
+			client:      http.DefaultClient,
+			store:       store,
+			logStore:    logStore,
+			workerStore: workerStore,
+			now:         func() time.Time { return now },
 		}
 
 		err := h.Handle(ctx, logger, job)
-		// We expect an error here because sadServer returned a 500.
-		assert.Error(t, err)
+		// We don't expect an error here: the job is requeued because sadServer
+		// returned a 500.
+		assert.NoError(t, err)
 
 		mockassert.CalledN(t, store.ListFunc, 1)
 		mockassert.CalledN(t, logStore.CreateFunc, 2)
+		mockassert.CalledOnceWith(t, workerStore.RequeueFunc, mockassert.Values(mockassert.Skip, 1, now.Add(initialRetryDelay)))
 
 		assert.EqualValues(t, 1, happyServer.requestCount)
 		assert.EqualValues(t, 1, sadServer.requestCount)
@@ -122,6 +133,10 @@ func TestHandler_Handle(t *testing.T) {
 		want := errors.New("connection error")
 
 		logStore := database.NewMockOutboundWebhookLogStore()
+		// This is the last attempt, so the job won't be requeued.
+		logStore.DeliveriesForJobFunc.SetDefaultReturn(map[int64]database.OutboundWebhookDelivery{
+			webhook.ID: {Attempts: maxDeliveryAttempts - 1},
+		}, nil)
 		logStore.CreateFunc.SetDefaultHook(func(ctx context.Context, log *types.OutboundWebhookLog) error {
 			have, err := log.Error.Decrypt(ctx)
 			require.NoError(t, err)
@@ -130,10 +145,14 @@ func TestHandler_Handle(t *testing.T) {
 			return nil
 		})
 
+		workerStore := dbworkerstoremocks.NewMockStore[*types.OutboundWebhookJob]()
+
 		h := &handler{
-			client:   &http.Client{Transport: &badTransport{Err: want}},
-			store:    store,
-			logStore: logStore,
+			client:      &http.Client{Transport: &badTransport{Err: want}},
+			store:       store,
+			logStore:    logStore,
+			workerStore: workerStore,
+			now:         time.Now,
 		}
 
 		err := h.Handle(ctx, logger, job)
@@ -141,9 +160,82 @@ func TestHandler_Handle(t *testing.T) {
 
 		mockassert.CalledN(t, store.ListFunc, 1)
 		mockassert.CalledN(t, logStore.CreateFunc, 1)
+		mockassert.NotCalled(t, workerStore.RequeueFunc)
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		ctx := context.Background()
+		logger := logtest.Scoped(t)
+
+		payload := []byte(`"test payload"`)
+		secret := "shared secret"
+
+		server := newMockServer(t, payload, http.StatusOK)
+
+		job := &types.OutboundWebhookJob{
+			ID:        1,
+			EventType: "event",
+			Payload:   encryption.NewUnencrypted(string(payload)),
+		}
+
+		deliveredWebhook := &types.OutboundWebhook{
+			ID:     1,
+			URL:    encryption.NewUnencrypted(server.URL),
+			Secret: encryption.NewUnencrypted(secret),
+		}
+		exhaustedWebhook := &types.OutboundWebhook{
+			ID:     2,
+			URL:    encryption.NewUnencrypted(server.URL),
+			Secret: encryption.NewUnencrypted(secret),
+		}
+		failedWebhook := &types.OutboundWebhook{
+			ID:     3,
+			URL:    encryption.NewUnencrypted(server.URL),
+			Secret: encryption.NewUnencrypted(secret),
+		}
+
+		store := database.NewMockOutboundWebhookStore()
+		store.ListFunc.SetDefaultReturn([]*types.OutboundWebhook{deliveredWebhook, exhaustedWebhook, failedWebhook}, nil)
+
+		logStore := database.NewMockOutboundWebhookLogStore()
+		logStore.DeliveriesForJobFunc.SetDefaultReturn(map[int64]database.OutboundWebhookDelivery{
+			deliveredWebhook.ID: {Attempts: 2, Delivered: true},
+			exhaustedWebhook.ID: {Attempts: maxDeliveryAttempts},
+			failedWebhook.ID:    {Attempts: 2},
+		}, nil)
+		logStore.CreateFunc.SetDefaultHook(func(ctx context.Context, log *types.OutboundWebhookLog) error {
+			// Only the webhook that hasn't received the payload yet and has
+			// attempts left is sent the payload again.
+			assert.Equal(t, failedWebhook.ID, log.OutboundWebhookID)
+			return nil
+		})
+
+		workerStore := dbworkerstoremocks.NewMockStore[*types.OutboundWebhookJob]()
+
+		h := &handler{
+			client:      http.DefaultClient,
+			store:       store,
+			logStore:    logStore,
+			workerStore: workerStore,
+			now:         time.Now,
+		}
+
+		err := h.Handle(ctx, logger, job)
+		assert.NoError(t, err)
+
+		mockassert.CalledN(t, logStore.CreateFunc, 1)
+		mockassert.NotCalled(t, workerStore.RequeueFunc)
+		assert.EqualValues(t, 1, server.requestCount)
 	})
 }
 
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, retryDelay(1))
+	assert.Equal(t, time.Minute, retryDelay(2))
+	assert.Equal(t, 8*time.Minute, retryDelay(5))
+	assert.Equal(t, time.Hour, retryDelay(20))
+}
+
 type badTransport struct {
 	Err error
 }
@@ -169,6 +261,8 @@ func newMockServer(t *testing.T, expectedPayload []byte, statusCode int) *mockSe
 		body, err := io.ReadAll(r.Body)
 		require.NoError(t, err)
 		assert.Equal(t, expectedPayload, body)
+		assert.NotEmpty(t, r.Header.Get(outbound.HeaderID))
+		assert.NotEmpty(t, r.Header.Get(outbound.HeaderTimestampSignature))
 
 		w.WriteHeader(statusCode)
 	}))
@@ -68,9 +68,11 @@ func makeWorker(
 	logStore database.OutboundWebhookLogStore,
 ) *workerutil.Worker[*types.OutboundWebhookJob] {
 	handler := &handler{
-		client:   client,
-		store:    webhookStore,
-		logStore: logStore,
+		client:      client,
+		store:       webhookStore,
+		logStore:    logStore,
+		workerStore: workerStore,
+		now:         time.Now,
 	}
 
 	return dbworker.NewWorker[*types.OutboundWebhookJob](
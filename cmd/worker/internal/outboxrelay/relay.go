@@ -0,0 +1,135 @@
+package outboxrelay
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/outbox"
+)
+
+// relay is a worker responsible for delivering events from the transactional
+// outbox to the registered consumer groups, and for deleting delivered events
+// after the retention period.
+type relay struct{}
+
+var _ job.Job = &relay{}
+
+func NewRelay() job.Job {
+	return &relay{}
+}
+
+func (r *relay) Description() string {
+	return "delivers events from the transactional outbox to consumer groups"
+}
+
+func (r *relay) Config() []env.Config {
+	return nil
+}
+
+const (
+	// relayInterval is the interval at which consumer groups are checked for
+	// new events.
+	relayInterval = 5 * time.Second
+	// relayBatchSize is the maximum number of events handled in a single
+	// transaction.
+	relayBatchSize = 100
+	// retention is the time delivered events are kept in the outbox.
+	retention = 7 * 24 * time.Hour
+)
+
+func (r *relay) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := actor.WithInternalActor(context.Background())
+	logger := observationCtx.Logger
+
+	return []goroutine.BackgroundRoutine{
+		goroutine.NewPeriodicGoroutine(
+			ctx,
+			&relayHandler{db: db, logger: logger},
+			goroutine.WithName("outbox.relay"),
+			goroutine.WithDescription("delivers events from the transactional outbox to consumer groups"),
+			goroutine.WithInterval(relayInterval),
+		),
+		goroutine.NewPeriodicGoroutine(
+			ctx,
+			&janitorHandler{store: db.OutboxEvents(), logger: logger, now: time.Now},
+			goroutine.WithName("outbox.janitor"),
+			goroutine.WithDescription("deletes delivered events from the transactional outbox after the retention period"),
+			goroutine.WithInterval(time.Hour),
+		),
+	}, nil
+}
+
+type relayHandler struct {
+	db     database.DB
+	logger log.Logger
+}
+
+var (
+	_ goroutine.Handler      = &relayHandler{}
+	_ goroutine.ErrorHandler = &relayHandler{}
+)
+
+func (h *relayHandler) Handle(ctx context.Context) error {
+	for _, group := range outbox.ConsumerGroups() {
+		consumer, _ := outbox.GetConsumer(group)
+
+		// Drain the backlog of the group, one batch per transaction. A failing
+		// group is retried on the next run and does not hold back other groups.
+		for {
+			handled, err := outbox.Relay(ctx, h.db, group, consumer, relayBatchSize)
+			if err != nil {
+				h.logger.Error("error relaying outbox events", log.String("consumerGroup", group), log.Error(err))
+				break
+			}
+			if handled < relayBatchSize {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func (h *relayHandler) HandleError(err error) {
+	h.logger.Error("error relaying outbox events", log.Error(err))
+}
+
+type janitorHandler struct {
+	store  database.OutboxEventStore
+	logger log.Logger
+	now    func() time.Time
+}
+
+var (
+	_ goroutine.Handler      = &janitorHandler{}
+	_ goroutine.ErrorHandler = &janitorHandler{}
+)
+
+func (h *janitorHandler) Handle(ctx context.Context) error {
+	count, err := h.store.DeleteConsumedBefore(ctx, h.now().Add(-retention))
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		h.logger.Info("deleted delivered outbox events", log.Int("count", count))
+	}
+	return nil
+}
+
+func (h *janitorHandler) HandleError(err error) {
+	h.logger.Error("error deleting delivered outbox events", log.Error(err))
+}
@@ -0,0 +1,44 @@
+package users
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/users/dataexport"
+)
+
+// dataExportsJob exports the personal data of users on request, and reports on
+// its deletion once they are permanently deleted.
+type dataExportsJob struct{}
+
+var _ job.Job = &dataExportsJob{}
+
+func NewDataExportsJob() job.Job {
+	return &dataExportsJob{}
+}
+
+func (j *dataExportsJob) Description() string {
+	return "exports the personal data of users and reports on its deletion"
+}
+
+func (j *dataExportsJob) Config() []env.Config {
+	return []env.Config{dataexport.UploadStoreConfigInst}
+}
+
+func (j *dataExportsJob) Routines(ctx context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadStore, err := dataexport.NewUploadStore(ctx, observationCtx, dataexport.UploadStoreConfigInst)
+	if err != nil {
+		return nil, err
+	}
+
+	return dataexport.NewWorker(ctx, observationCtx, db, uploadStore), nil
+}
@@ -0,0 +1,104 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/users/dataexport"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// purger is a worker responsible for permanently deleting users that were
+// soft-deleted longer ago than the configured retention period.
+type purger struct{}
+
+var _ job.Job = &purger{}
+
+func NewDeletedUsersPurger() job.Job {
+	return &purger{}
+}
+
+func (p *purger) Description() string {
+	return "permanently deletes soft-deleted users after the retention period"
+}
+
+func (p *purger) Config() []env.Config {
+	return nil
+}
+
+func (p *purger) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []goroutine.BackgroundRoutine{
+		goroutine.NewPeriodicGoroutine(
+			actor.WithInternalActor(context.Background()),
+			&handler{
+				store:       db.Users(),
+				dataExports: dataexport.NewStore(db),
+				logger:      observationCtx.Logger,
+				now:         time.Now,
+			},
+			goroutine.WithName("users.deleted-users-purger"),
+			goroutine.WithDescription("permanently deletes soft-deleted users after the retention period"),
+			goroutine.WithInterval(time.Hour),
+		),
+	}, nil
+}
+
+// purgeBatchSize is the maximum number of users deleted by a single run.
+const purgeBatchSize = 100
+
+type handler struct {
+	store       database.UserStore
+	dataExports dataexport.Store
+	logger      log.Logger
+	now         func() time.Time
+}
+
+var (
+	_ goroutine.Handler      = &handler{}
+	_ goroutine.ErrorHandler = &handler{}
+)
+
+func (h *handler) Handle(ctx context.Context) error {
+	// Read the retention when the handler runs to get the latest value.
+	retention := conf.DeletedUsersRetention()
+	if retention == 0 {
+		return nil
+	}
+
+	ids, err := h.store.ListDeletedBefore(ctx, h.now().Add(-retention), purgeBatchSize)
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+
+	if err := h.store.HardDeleteList(ctx, ids); err != nil {
+		return err
+	}
+	h.logger.Info("permanently deleted users after retention period", log.Int32s("users", ids))
+
+	var errs error
+	for _, id := range ids {
+		if _, err := h.dataExports.EnqueueDeletionReport(ctx, id, nil); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "enqueueing deletion report of user %d", id))
+		}
+	}
+	return errs
+}
+
+func (h *handler) HandleError(err error) {
+	h.logger.Error("error permanently deleting users", log.Error(err))
+}
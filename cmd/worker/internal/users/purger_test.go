@@ -0,0 +1,67 @@
+package users
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/users/dataexport"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type fakeDataExportStore struct {
+	dataexport.Store
+	reported []int32
+}
+
+func (s *fakeDataExportStore) EnqueueDeletionReport(_ context.Context, userID int32, _ *int32) (*dataexport.Job, error) {
+	s.reported = append(s.reported, userID)
+	return &dataexport.Job{UserID: userID, Kind: dataexport.KindDeletionReport}, nil
+}
+
+func TestHandler(t *testing.T) {
+	now := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	mockRetentionDays := func(t *testing.T, days int) {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{AuthDeletedUsersRetentionDays: days}})
+		t.Cleanup(func() { conf.Mock(nil) })
+	}
+
+	t.Run("retained indefinitely", func(t *testing.T) {
+		mockRetentionDays(t, 0)
+		store := database.NewMockUserStore()
+
+		h := &handler{store: store, logger: logtest.Scoped(t), now: func() time.Time { return now }}
+		assert.NoError(t, h.Handle(context.Background()))
+		mockassert.NotCalled(t, store.ListDeletedBeforeFunc)
+		mockassert.NotCalled(t, store.HardDeleteListFunc)
+	})
+
+	t.Run("deletes expired users", func(t *testing.T) {
+		mockRetentionDays(t, 30)
+		store := database.NewMockUserStore()
+		store.ListDeletedBeforeFunc.SetDefaultReturn([]int32{1, 2}, nil)
+		dataExports := &fakeDataExportStore{}
+
+		h := &handler{store: store, dataExports: dataExports, logger: logtest.Scoped(t), now: func() time.Time { return now }}
+		assert.NoError(t, h.Handle(context.Background()))
+
+		mockassert.CalledOnceWith(t, store.ListDeletedBeforeFunc, mockassert.Values(mockassert.Skip, now.Add(-30*24*time.Hour), purgeBatchSize))
+		mockassert.CalledOnceWith(t, store.HardDeleteListFunc, mockassert.Values(mockassert.Skip, []int32{1, 2}))
+		assert.Equal(t, []int32{1, 2}, dataExports.reported)
+	})
+
+	t.Run("nothing to delete", func(t *testing.T) {
+		mockRetentionDays(t, 30)
+		store := database.NewMockUserStore()
+
+		h := &handler{store: store, logger: logtest.Scoped(t), now: func() time.Time { return now }}
+		assert.NoError(t, h.Handle(context.Background()))
+		mockassert.NotCalled(t, store.HardDeleteListFunc)
+	})
+}
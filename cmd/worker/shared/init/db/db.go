@@ -9,6 +9,7 @@ import (
 	connections "github.com/sourcegraph/sourcegraph/internal/database/connections/live"
 	"github.com/sourcegraph/sourcegraph/internal/memo"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/redispool/dbstore"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -18,7 +19,11 @@ func InitDB(observationCtx *observation.Context) (database.DB, error) {
 		return nil, err
 	}
 
-	return database.NewDB(observationCtx.Logger, rawDB), nil
+	db := database.NewDB(observationCtx.Logger, rawDB)
+	if err := dbstore.Register(db); err != nil {
+		return nil, errors.Wrap(err, "failed to register postgres backed redis")
+	}
+	return db, nil
 }
 
 var initDatabaseMemo = memo.NewMemoizedConstructorWithArg(func(observationCtx *observation.Context) (*sql.DB, error) {
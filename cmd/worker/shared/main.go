@@ -14,11 +14,15 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine/recorder"
 
+	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/accesstokenusage"
 	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/encryption"
 	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/gitserver"
 	workermigrations "github.com/sourcegraph/sourcegraph/cmd/worker/internal/migrations"
+	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/notifications"
 	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/outboundwebhooks"
+	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/outboxrelay"
 	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/repostatistics"
+	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/users"
 	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/webhooks"
 	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/zoektrepos"
 	workerjob "github.com/sourcegraph/sourcegraph/cmd/worker/job"
@@ -53,10 +57,16 @@ func LoadConfig(additionalJobs map[string]workerjob.Job, registerEnterpriseMigra
 		"webhook-log-janitor":       webhooks.NewJanitor(),
 		"out-of-band-migrations":    workermigrations.NewMigrator(registerMigrators),
 		"gitserver-metrics":         gitserver.NewMetricsJob(),
+		"gitserver-relocator":       gitserver.NewRelocatorJob(),
 		"record-encrypter":          encryption.NewRecordEncrypterJob(),
 		"repo-statistics-compactor": repostatistics.NewCompactor(),
 		"zoekt-repos-updater":       zoektrepos.NewUpdater(),
 		"outbound-webhook-sender":   outboundwebhooks.NewSender(),
+		"deleted-users-purger":      users.NewDeletedUsersPurger(),
+		"user-data-exports":         users.NewDataExportsJob(),
+		"outbox-relay":              outboxrelay.NewRelay(),
+		"access-token-usage":        accesstokenusage.NewJob(),
+		"notifications-digester":    notifications.NewDigester(),
 	}
 
 	var config Config
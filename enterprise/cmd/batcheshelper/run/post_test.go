@@ -66,7 +66,7 @@ func TestPost(t *testing.T) {
 				assert.Regexp(t, `^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d+ \+\d{4} UTC$`, logEntries[1].Timestamp)
 				assert.Equal(t, batcheslib.LogEventStatusSuccess, logEntries[1].Status)
 				assert.IsType(t, &batcheslib.CacheAfterStepResultMetadata{}, logEntries[1].Metadata)
-				assert.Equal(t, "deZzMP85HWs6lfhWRnMVBA-step-0", logEntries[1].Metadata.(*batcheslib.CacheAfterStepResultMetadata).Key)
+				assert.Equal(t, "La2G9GnEpk7j1rRBYNWPWg-step-0", logEntries[1].Metadata.(*batcheslib.CacheAfterStepResultMetadata).Key)
 				assert.Equal(t, "hello world", logEntries[1].Metadata.(*batcheslib.CacheAfterStepResultMetadata).Value.Stdout)
 				assert.Equal(t, "error", logEntries[1].Metadata.(*batcheslib.CacheAfterStepResultMetadata).Value.Stderr)
 				assert.Equal(t, []byte("git diff"), logEntries[1].Metadata.(*batcheslib.CacheAfterStepResultMetadata).Value.Diff)
@@ -124,7 +124,7 @@ func TestPost(t *testing.T) {
 			stderrLogs:     "error",
 			assertFunc: func(t *testing.T, logEntries []batcheslib.LogEvent, dir string, runner *fakeCmdRunner) {
 				require.Len(t, logEntries, 2)
-				assert.Equal(t, "4qXjs4-Arh1VpWWfWhqm3A-step-0", logEntries[1].Metadata.(*batcheslib.CacheAfterStepResultMetadata).Key)
+				assert.Equal(t, "1sJ5JUWomE1qfsPA8xy1_A-step-0", logEntries[1].Metadata.(*batcheslib.CacheAfterStepResultMetadata).Key)
 
 				entries, err := os.ReadDir(dir)
 				require.NoError(t, err)
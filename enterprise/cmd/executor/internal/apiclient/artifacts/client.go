@@ -0,0 +1,56 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/sourcegraph/log"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/apiclient"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor/types"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// Client uploads the artifacts of jobs to the Sourcegraph instance.
+type Client struct {
+	client     *apiclient.BaseClient
+	logger     log.Logger
+	operations *operations
+}
+
+// New creates a new Client based on the provided Options.
+func New(observationCtx *observation.Context, options apiclient.BaseClientOptions) (*Client, error) {
+	logger := log.Scoped("executor-api-artifacts-client", "The API client adapter for executors to upload job artifacts over HTTP")
+	client, err := apiclient.NewBaseClient(logger, options)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		client:     client,
+		logger:     logger,
+		operations: newOperations(observationCtx),
+	}, nil
+}
+
+// Upload uploads the artifact with the given name of the job. An existing artifact of the job with the same name is
+// replaced.
+func (c *Client) Upload(ctx context.Context, job types.Job, name string, contentType string, content io.Reader) (err error) {
+	ctx, _, endObservation := c.operations.upload.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("queue", job.Queue),
+		attribute.Int("jobID", job.ID),
+		attribute.String("name", name),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	req, err := c.client.NewRequest(job.ID, job.Token, http.MethodPut, fmt.Sprintf("%s/%s", url.PathEscape(job.Queue), url.PathEscape(name)), content)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	return c.client.DoAndDrop(ctx, req)
+}
@@ -0,0 +1,69 @@
+package artifacts_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/apiclient"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/apiclient/artifacts"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor/types"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestClient_Upload(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		expectErr  bool
+	}{
+		{name: "Uploaded", statusCode: http.StatusNoContent},
+		{name: "Rejected", statusCode: http.StatusBadRequest, expectErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPut, r.Method)
+				assert.Equal(t, "/.executors/artifacts/batches/results.sarif", r.URL.Path)
+				assert.Equal(t, "Bearer job-token", r.Header.Get("Authorization"))
+				assert.Equal(t, "42", r.Header.Get("X-Sourcegraph-Job-ID"))
+				assert.Equal(t, "test-executor", r.Header.Get("X-Sourcegraph-Executor-Name"))
+				assert.Equal(t, "application/sarif+json", r.Header.Get("Content-Type"))
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.Equal(t, `{"runs":[]}`, string(body))
+				w.WriteHeader(test.statusCode)
+			}))
+			t.Cleanup(srv.Close)
+
+			client, err := artifacts.New(&observation.TestContext, apiclient.BaseClientOptions{
+				ExecutorName: "test-executor",
+				EndpointOptions: apiclient.EndpointOptions{
+					URL:        srv.URL,
+					PathPrefix: "/.executors/artifacts",
+					Token:      "hunter2",
+				},
+			})
+			require.NoError(t, err)
+
+			err = client.Upload(
+				context.Background(),
+				types.Job{ID: 42, Queue: "batches", Token: "job-token"},
+				"results.sarif",
+				"application/sarif+json",
+				strings.NewReader(`{"runs":[]}`),
+			)
+			if test.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
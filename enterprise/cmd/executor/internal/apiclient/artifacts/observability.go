@@ -0,0 +1,33 @@
+package artifacts
+
+import (
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+type operations struct {
+	upload *observation.Operation
+}
+
+func newOperations(observationCtx *observation.Context) *operations {
+	m := metrics.NewREDMetrics(
+		observationCtx.Registerer,
+		"apiworker_apiclient_artifacts",
+		metrics.WithLabels("op"),
+		metrics.WithCountHelp("Total number of method invocations."),
+	)
+
+	op := func(name string) *observation.Operation {
+		return observationCtx.Operation(observation.Op{
+			Name:              fmt.Sprintf("apiworker.apiclient.artifacts.%s", name),
+			MetricLabelValues: []string{name},
+			Metrics:           m,
+		})
+	}
+
+	return &operations{
+		upload: op("Upload"),
+	}
+}
@@ -84,6 +84,10 @@ type Config struct {
 	// TODO remove in 5.2 if we have moved to a custom image to do the setup work.
 	KubernetesGitCACert string
 
+	KubernetesPodTemplatePath string
+	KubernetesPodTemplate     *corev1.PodTemplateSpec
+	KubernetesQueueNamespaces map[string]string
+
 	dockerAuthConfigStr                                          string
 	dockerAuthConfigUnmarshalError                               error
 	kubernetesNodeRequiredAffinityMatchExpressions               string
@@ -100,6 +104,8 @@ type Config struct {
 	kubernetesAdditionalJobVolumeMountsUnmarshalError            error
 	kubernetesAdditionalJobVolumes                               string
 	kubernetesAdditionalJobVolumesUnmarshalError                 error
+	kubernetesPodTemplateError                                   error
+	kubernetesQueueNamespaces                                    string
 
 	defaultFrontendPassword string
 }
@@ -165,6 +171,8 @@ func (c *Config) Load() {
 	c.kubernetesAdditionalJobVolumeMounts = c.GetOptional("KUBERNETES_ADDITIONAL_JOB_VOLUME_MOUNTS", "Volumes to mount to the Jobs. e.g. [{\"name\":\"my-volume\", \"mountPath\":\"/foo/bar\"}]")
 	c.KubernetesSingleJobStepImage = c.Get("KUBERNETES_SINGLE_JOB_STEP_IMAGE", "sourcegraph/batcheshelper:insiders", "The image to use for intermediate steps in the single job. Defaults to sourcegraph/batcheshelper:latest.")
 	c.KubernetesGitCACert = c.GetOptional("KUBERNETES_GIT_CA_CERT", "The CA certificate to use for git operations. If not set, the system CA bundle will be used. e.g. /path/to/ca.crt")
+	c.KubernetesPodTemplatePath = c.GetOptional("EXECUTOR_KUBERNETES_POD_TEMPLATE_PATH", "The path to a YAML or JSON encoded Pod template that is merged into every Kubernetes Job Pod, e.g. to set resources, node selectors, or environment variables from secrets.")
+	c.kubernetesQueueNamespaces = c.GetOptional("EXECUTOR_KUBERNETES_QUEUE_NAMESPACES", "A comma separated list of queue=namespace pairs to run the Kubernetes Jobs of a queue in a dedicated namespace. Queues that are not listed use EXECUTOR_KUBERNETES_NAMESPACE. e.g. batches=sg-batches,codeintel=sg-codeintel")
 
 	if c.QueueNamesStr != "" {
 		c.QueueNames = strings.Split(c.QueueNamesStr, ",")
@@ -196,6 +204,19 @@ func (c *Config) Load() {
 		c.kubernetesAdditionalJobVolumeMountsUnmarshalError = json.Unmarshal([]byte(c.kubernetesAdditionalJobVolumeMounts), &c.KubernetesAdditionalJobVolumeMounts)
 	}
 
+	if c.KubernetesPodTemplatePath != "" {
+		c.KubernetesPodTemplate, c.kubernetesPodTemplateError = readPodTemplate(c.KubernetesPodTemplatePath)
+	}
+	if c.kubernetesQueueNamespaces != "" {
+		c.KubernetesQueueNamespaces = make(map[string]string)
+		for _, value := range strings.Split(c.kubernetesQueueNamespaces, ",") {
+			parts := strings.Split(value, "=")
+			if len(parts) == 2 {
+				c.KubernetesQueueNamespaces[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+			}
+		}
+	}
+
 	if c.KubernetesConfigPath == "" {
 		c.KubernetesConfigPath = getKubeConfigPath()
 	}
@@ -293,6 +314,27 @@ func (c *Config) Validate() error {
 		c.AddError(errors.Wrap(c.kubernetesNodeTolerationsUnmarshalError, "invalid EXECUTOR_KUBERNETES_NODE_TOLERATIONS, failed to parse"))
 	}
 
+	if c.kubernetesPodTemplateError != nil {
+		c.AddError(errors.Wrap(c.kubernetesPodTemplateError, "invalid EXECUTOR_KUBERNETES_POD_TEMPLATE_PATH, failed to read template"))
+	}
+
+	if c.kubernetesQueueNamespaces != "" {
+		for _, value := range strings.Split(c.kubernetesQueueNamespaces, ",") {
+			parts := strings.Split(value, "=")
+			if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+				c.AddError(errors.New("EXECUTOR_KUBERNETES_QUEUE_NAMESPACES must be a comma separated list of queue=namespace pairs"))
+				continue
+			}
+			if queueName := strings.TrimSpace(parts[0]); !slices.Contains(types.ValidQueueNames, queueName) {
+				c.AddError(errors.Newf("EXECUTOR_KUBERNETES_QUEUE_NAMESPACES contains invalid queue name '%s', valid names are '%v'", queueName, strings.Join(types.ValidQueueNames, ", ")))
+			}
+		}
+		// Multiple Job Pods share the PersistentVolumeClaim of the executor, which only exists in its own namespace.
+		if !c.KubernetesSingleJobPod {
+			c.AddError(errors.New("EXECUTOR_KUBERNETES_QUEUE_NAMESPACES requires KUBERNETES_SINGLE_JOB_POD to be enabled"))
+		}
+	}
+
 	if c.UseFirecracker {
 		// Validate that firecracker can work on this host.
 		if runtime.GOOS != "linux" {
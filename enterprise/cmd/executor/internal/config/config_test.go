@@ -1,6 +1,8 @@
 package config_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -15,6 +17,20 @@ import (
 )
 
 func TestConfig_Load(t *testing.T) {
+	podTemplatePath := filepath.Join(t.TempDir(), "pod-template.yaml")
+	require.NoError(t, os.WriteFile(podTemplatePath, []byte(`
+metadata:
+  labels:
+    team: batches
+spec:
+  serviceAccountName: executor-jobs
+  containers:
+    - name: defaults
+      envFrom:
+        - secretRef:
+            name: job-secrets
+`), 0o644))
+
 	cfg := &config.Config{}
 	cfg.SetMockGetter(func(name, defaultValue, description string) string {
 		switch name {
@@ -68,6 +84,10 @@ func TestConfig_Load(t *testing.T) {
 			return `[{"name": "foo", "mountPath": "/foo"}]`
 		case "KUBERNETES_SINGLE_JOB_STEP_IMAGE":
 			return "sourcegraph/step-image:latest"
+		case "EXECUTOR_KUBERNETES_POD_TEMPLATE_PATH":
+			return podTemplatePath
+		case "EXECUTOR_KUBERNETES_QUEUE_NAMESPACES":
+			return "batches=sg-batches, codeintel=sg-codeintel"
 		default:
 			return name
 		}
@@ -168,6 +188,16 @@ func TestConfig_Load(t *testing.T) {
 		cfg.KubernetesAdditionalJobVolumeMounts,
 	)
 	assert.Equal(t, "sourcegraph/step-image:latest", cfg.KubernetesSingleJobStepImage)
+	require.NotNil(t, cfg.KubernetesPodTemplate)
+	assert.Equal(t, map[string]string{"team": "batches"}, cfg.KubernetesPodTemplate.Labels)
+	assert.Equal(t, "executor-jobs", cfg.KubernetesPodTemplate.Spec.ServiceAccountName)
+	require.Len(t, cfg.KubernetesPodTemplate.Spec.Containers, 1)
+	assert.Equal(
+		t,
+		[]corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "job-secrets"}}}},
+		cfg.KubernetesPodTemplate.Spec.Containers[0].EnvFrom,
+	)
+	assert.Equal(t, map[string]string{"batches": "sg-batches", "codeintel": "sg-codeintel"}, cfg.KubernetesQueueNamespaces)
 }
 
 func TestConfig_Load_Defaults(t *testing.T) {
@@ -329,6 +359,62 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expectedErr: errors.New("EXECUTOR_QUEUE_NAMES contains invalid queue name 'batches;codeintel', valid names are 'batches, codeintel' and should be comma-separated"),
 		},
+		{
+			name: "Invalid EXECUTOR_KUBERNETES_QUEUE_NAMESPACES",
+			getterFunc: func(name string, defaultValue, description string) string {
+				switch name {
+				case "EXECUTOR_QUEUE_NAMES":
+					return "batches,codeintel"
+				case "EXECUTOR_FRONTEND_URL":
+					return "http://some-url.com"
+				case "EXECUTOR_FRONTEND_PASSWORD":
+					return "some-password"
+				case "KUBERNETES_SINGLE_JOB_POD":
+					return "true"
+				case "EXECUTOR_KUBERNETES_QUEUE_NAMESPACES":
+					return "batches=sg-batches,insights=sg-insights"
+				default:
+					return defaultValue
+				}
+			},
+			expectedErr: errors.New("EXECUTOR_KUBERNETES_QUEUE_NAMESPACES contains invalid queue name 'insights', valid names are 'batches, codeintel'"),
+		},
+		{
+			name: "EXECUTOR_KUBERNETES_QUEUE_NAMESPACES without single job pod",
+			getterFunc: func(name string, defaultValue, description string) string {
+				switch name {
+				case "EXECUTOR_QUEUE_NAME":
+					return "batches"
+				case "EXECUTOR_FRONTEND_URL":
+					return "http://some-url.com"
+				case "EXECUTOR_FRONTEND_PASSWORD":
+					return "some-password"
+				case "EXECUTOR_KUBERNETES_QUEUE_NAMESPACES":
+					return "batches=sg-batches"
+				default:
+					return defaultValue
+				}
+			},
+			expectedErr: errors.New("EXECUTOR_KUBERNETES_QUEUE_NAMESPACES requires KUBERNETES_SINGLE_JOB_POD to be enabled"),
+		},
+		{
+			name: "Missing EXECUTOR_KUBERNETES_POD_TEMPLATE_PATH",
+			getterFunc: func(name string, defaultValue, description string) string {
+				switch name {
+				case "EXECUTOR_QUEUE_NAME":
+					return "batches"
+				case "EXECUTOR_FRONTEND_URL":
+					return "http://some-url.com"
+				case "EXECUTOR_FRONTEND_PASSWORD":
+					return "some-password"
+				case "EXECUTOR_KUBERNETES_POD_TEMPLATE_PATH":
+					return "/does/not/exist.yaml"
+				default:
+					return defaultValue
+				}
+			},
+			expectedErr: errors.New("invalid EXECUTOR_KUBERNETES_POD_TEMPLATE_PATH, failed to read template: open /does/not/exist.yaml: no such file or directory"),
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -2,6 +2,9 @@ package config
 
 import (
 	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
 )
 
 // IsKubernetes returns true if the executor is running in a Kubernetes cluster.
@@ -10,3 +13,16 @@ func IsKubernetes() bool {
 	_, hasKubernetesPort := os.LookupEnv("KUBERNETES_SERVICE_PORT")
 	return hasKubernetesHost && hasKubernetesPort
 }
+
+// readPodTemplate reads the YAML or JSON encoded Pod template at the given path.
+func readPodTemplate(path string) (*corev1.PodTemplateSpec, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var template corev1.PodTemplateSpec
+	if err := yaml.UnmarshalStrict(contents, &template); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
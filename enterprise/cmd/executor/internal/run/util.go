@@ -71,9 +71,10 @@ func apiWorkerOptions(c *config.Config, queueTelemetryOptions queue.TelemetryOpt
 			FirecrackerOptions: firecrackerOptions(c),
 			KubernetesOptions:  kubernetesOptions(c),
 		},
-		GitServicePath: "/.executors/git",
-		QueueOptions:   queueOptions(c, queueTelemetryOptions),
-		FilesOptions:   filesOptions(c),
+		GitServicePath:   "/.executors/git",
+		QueueOptions:     queueOptions(c, queueTelemetryOptions),
+		FilesOptions:     filesOptions(c),
+		ArtifactsOptions: baseClientOptions(c, "/.executors/artifacts"),
 		RedactedValues: map[string]string{
 			// 🚨 SECURITY: Catch uses of the shared frontend token used to clone
 			// git repositories that make it into commands or stdout/stderr streams.
@@ -243,6 +244,8 @@ func kubernetesOptions(c *config.Config) runner.KubernetesOptions {
 				Volumes: c.KubernetesAdditionalJobVolumes,
 				Mounts:  c.KubernetesAdditionalJobVolumeMounts,
 			},
+			PodTemplate:     c.KubernetesPodTemplate,
+			QueueNamespaces: c.KubernetesQueueNamespaces,
 		},
 	}
 }
@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor/types"
+)
+
+// ArtifactStore uploads the artifacts of jobs.
+type ArtifactStore interface {
+	Upload(ctx context.Context, job types.Job, name string, contentType string, content io.Reader) error
+}
+
+// uploadArtifacts uploads the files the job wrote to the artifacts directory of its working
+// directory. Artifacts that fail to upload are logged, but do not fail the job.
+func (h *handler) uploadArtifacts(ctx context.Context, logger log.Logger, job types.Job, workingDirectory string) {
+	// The working directory of Kubernetes jobs is not accessible to the executor.
+	if h.artifactStore == nil || workingDirectory == "" {
+		return
+	}
+
+	dir := filepath.Join(workingDirectory, types.ArtifactsDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn("Failed to read artifacts directory", log.Error(err))
+		}
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if err := h.uploadArtifact(ctx, job, filepath.Join(dir, entry.Name())); err != nil {
+			logger.Warn("Failed to upload artifact", log.String("name", entry.Name()), log.Error(err))
+		}
+	}
+}
+
+func (h *handler) uploadArtifact(ctx context.Context, job types.Job, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return h.artifactStore.Upload(ctx, job, filepath.Base(path), contentType, f)
+}
@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor/types"
+)
+
+type uploadedArtifact struct {
+	queue       string
+	jobID       int
+	name        string
+	contentType string
+	content     string
+}
+
+type recordingArtifactStore struct {
+	uploads []uploadedArtifact
+}
+
+func (s *recordingArtifactStore) Upload(_ context.Context, job types.Job, name string, contentType string, content io.Reader) error {
+	b, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	s.uploads = append(s.uploads, uploadedArtifact{queue: job.Queue, jobID: job.ID, name: name, contentType: contentType, content: string(b)})
+	return nil
+}
+
+func TestHandler_UploadArtifacts(t *testing.T) {
+	workingDirectory := t.TempDir()
+	dir := filepath.Join(workingDirectory, types.ArtifactsDir)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "nested"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "output"), []byte("ok"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "results.json"), []byte("{}"), os.ModePerm))
+
+	store := &recordingArtifactStore{}
+	h := &handler{artifactStore: store}
+	h.uploadArtifacts(context.Background(), logtest.Scoped(t), types.Job{ID: 42, Queue: "batches"}, workingDirectory)
+
+	assert.Equal(t, []uploadedArtifact{
+		{queue: "batches", jobID: 42, name: "output", contentType: "application/octet-stream", content: "ok"},
+		{queue: "batches", jobID: 42, name: "results.json", contentType: "application/json", content: "{}"},
+	}, store.uploads)
+}
+
+func TestHandler_UploadArtifacts_NoArtifacts(t *testing.T) {
+	store := &recordingArtifactStore{}
+	h := &handler{artifactStore: store}
+	h.uploadArtifacts(context.Background(), logtest.Scoped(t), types.Job{ID: 42, Queue: "batches"}, t.TempDir())
+
+	assert.Empty(t, store.uploads)
+}
@@ -60,6 +60,19 @@ type KubernetesContainerOptions struct {
 	StepImage             string
 	GitCACert             string
 	JobVolume             KubernetesJobVolume
+	// PodTemplate is an optional admin provided Pod template that is merged into every Job Pod.
+	PodTemplate *corev1.PodTemplateSpec
+	// QueueNamespaces maps queue names to the namespace the Jobs of that queue run in. Queues without an entry run
+	// in Namespace.
+	QueueNamespaces map[string]string
+}
+
+// JobNamespace returns the namespace the Jobs of the given queue run in.
+func (o KubernetesContainerOptions) JobNamespace(queue string) string {
+	if namespace, ok := o.QueueNamespaces[queue]; ok && namespace != "" {
+		return namespace
+	}
+	return o.Namespace
 }
 
 // KubernetesCloneOptions contains options for cloning a Git repository.
@@ -434,7 +447,7 @@ func NewKubernetesJob(name string, image string, spec Spec, path string, options
 	resourceLimit := newResourceLimit(options)
 	resourceRequest := newResourceRequest(options)
 
-	return &batchv1.Job{
+	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
 		},
@@ -490,6 +503,9 @@ func NewKubernetesJob(name string, image string, spec Spec, path string, options
 			},
 		},
 	}
+
+	applyPodTemplate(options.PodTemplate, &job.Spec.Template)
+	return job
 }
 
 // RepositoryOptions contains the options for a repository job.
@@ -651,7 +667,7 @@ func NewKubernetesSingleJob(
 		}
 	}
 
-	return &batchv1.Job{
+	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
 		},
@@ -690,6 +706,9 @@ func NewKubernetesSingleJob(
 			},
 		},
 	}
+
+	applyPodTemplate(options.PodTemplate, &job.Spec.Template)
+	return job
 }
 
 func newEnvVars(envs []string) []corev1.EnvVar {
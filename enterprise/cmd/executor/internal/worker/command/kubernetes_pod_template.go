@@ -0,0 +1,82 @@
+package command
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// applyPodTemplate merges the admin provided Pod template into the Pod spec of the given Job.
+//
+// Pod level settings (labels, annotations, node selectors, tolerations, image pull secrets, service account, priority
+// and runtime class) are merged into the Job Pod. The first container of the template acts as the defaults for every
+// container of the Job: its resources replace the configured resources and its env and envFrom (e.g. secrets) are
+// added to the container environment. Values explicitly set by the executor (images, commands, volumes) are never
+// overridden.
+func applyPodTemplate(template *corev1.PodTemplateSpec, podTemplate *corev1.PodTemplateSpec) {
+	if template == nil {
+		return
+	}
+
+	podTemplate.Labels = mergeStringMaps(podTemplate.Labels, template.Labels)
+	podTemplate.Annotations = mergeStringMaps(podTemplate.Annotations, template.Annotations)
+
+	spec := &podTemplate.Spec
+	spec.NodeSelector = mergeStringMaps(spec.NodeSelector, template.Spec.NodeSelector)
+	spec.Tolerations = append(spec.Tolerations, template.Spec.Tolerations...)
+	spec.ImagePullSecrets = append(spec.ImagePullSecrets, template.Spec.ImagePullSecrets...)
+	if template.Spec.ServiceAccountName != "" {
+		spec.ServiceAccountName = template.Spec.ServiceAccountName
+	}
+	if template.Spec.AutomountServiceAccountToken != nil {
+		spec.AutomountServiceAccountToken = template.Spec.AutomountServiceAccountToken
+	}
+	if template.Spec.PriorityClassName != "" {
+		spec.PriorityClassName = template.Spec.PriorityClassName
+	}
+	if template.Spec.RuntimeClassName != nil {
+		spec.RuntimeClassName = template.Spec.RuntimeClassName
+	}
+	if template.Spec.Affinity != nil && spec.Affinity == nil {
+		spec.Affinity = template.Spec.Affinity
+	}
+
+	if len(template.Spec.Containers) == 0 {
+		return
+	}
+	defaults := template.Spec.Containers[0]
+	for i := range spec.InitContainers {
+		applyContainerTemplate(defaults, &spec.InitContainers[i])
+	}
+	for i := range spec.Containers {
+		applyContainerTemplate(defaults, &spec.Containers[i])
+	}
+}
+
+func applyContainerTemplate(template corev1.Container, container *corev1.Container) {
+	if len(template.Resources.Limits) > 0 {
+		container.Resources.Limits = template.Resources.Limits
+	}
+	if len(template.Resources.Requests) > 0 {
+		container.Resources.Requests = template.Resources.Requests
+	}
+	// Environment variables of the step take precedence, so the template values go first.
+	container.Env = append(append([]corev1.EnvVar{}, template.Env...), container.Env...)
+	container.EnvFrom = append(container.EnvFrom, template.EnvFrom...)
+	if template.SecurityContext != nil && container.SecurityContext == nil {
+		container.SecurityContext = template.SecurityContext
+	}
+}
+
+func mergeStringMaps(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	merged := make(map[string]string, len(dst)+len(src))
+	for k, v := range src {
+		merged[k] = v
+	}
+	// Values set by the executor win over the template.
+	for k, v := range dst {
+		merged[k] = v
+	}
+	return merged
+}
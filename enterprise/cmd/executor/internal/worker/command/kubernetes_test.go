@@ -779,3 +779,73 @@ func TestNewKubernetesSingleJob(t *testing.T) {
 	assert.Equal(t, resource.MustParse("1"), *job.Spec.Template.Spec.Containers[0].Resources.Requests.Cpu())
 	assert.Equal(t, resource.MustParse("1Gi"), *job.Spec.Template.Spec.Containers[0].Resources.Requests.Memory())
 }
+
+func TestNewKubernetesJob_PodTemplate(t *testing.T) {
+	spec := command.Spec{
+		Key:     "my.container",
+		Name:    "my-container",
+		Command: []string{"echo", "hello"},
+		Env:     []string{"FOO=bar"},
+	}
+	options := command.KubernetesContainerOptions{
+		Namespace:             "default",
+		NodeSelector:          map[string]string{"zone": "west"},
+		PersistenceVolumeName: "my-pvc",
+		ResourceLimit:         command.KubernetesResource{Memory: resource.MustParse("10Gi")},
+		ResourceRequest:       command.KubernetesResource{Memory: resource.MustParse("1Gi")},
+		PodTemplate: &corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"team": "batches"},
+			},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: "executor-jobs",
+				NodeSelector:       map[string]string{"zone": "east", "pool": "executors"},
+				ImagePullSecrets:   []corev1.LocalObjectReference{{Name: "registry"}},
+				Containers: []corev1.Container{
+					{
+						Name: "defaults",
+						Env:  []corev1.EnvVar{{Name: "FOO", Value: "template"}, {Name: "BAR", Value: "baz"}},
+						EnvFrom: []corev1.EnvFromSource{
+							{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "job-secrets"}}},
+						},
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+						},
+					},
+				},
+			},
+		},
+	}
+	job := command.NewKubernetesJob("my-job", "my-image:latest", spec, "/my/path", options)
+
+	podTemplate := job.Spec.Template
+	assert.Equal(t, map[string]string{"team": "batches"}, podTemplate.Labels)
+	assert.Equal(t, "executor-jobs", podTemplate.Spec.ServiceAccountName)
+	// Values configured on the executor take precedence over the template.
+	assert.Equal(t, map[string]string{"zone": "west", "pool": "executors"}, podTemplate.Spec.NodeSelector)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "registry"}}, podTemplate.Spec.ImagePullSecrets)
+
+	require.Len(t, podTemplate.Spec.Containers, 1)
+	container := podTemplate.Spec.Containers[0]
+	assert.Equal(t, "my-container", container.Name)
+	assert.Equal(t, "my-image:latest", container.Image)
+	assert.Equal(
+		t,
+		[]corev1.EnvVar{{Name: "FOO", Value: "template"}, {Name: "BAR", Value: "baz"}, {Name: "FOO", Value: "bar"}},
+		container.Env,
+	)
+	require.Len(t, container.EnvFrom, 1)
+	assert.Equal(t, "job-secrets", container.EnvFrom[0].SecretRef.Name)
+	assert.Equal(t, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}, container.Resources.Limits)
+	assert.Equal(t, resource.MustParse("1Gi"), *container.Resources.Requests.Memory())
+}
+
+func TestKubernetesContainerOptions_JobNamespace(t *testing.T) {
+	options := command.KubernetesContainerOptions{
+		Namespace:       "default",
+		QueueNamespaces: map[string]string{"batches": "sg-batches"},
+	}
+
+	assert.Equal(t, "sg-batches", options.JobNamespace("batches"))
+	assert.Equal(t, "default", options.JobNamespace("codeintel"))
+}
@@ -26,15 +26,16 @@ import (
 )
 
 type handler struct {
-	nameSet      *janitor.NameSet
-	cmdRunner    util.CmdRunner
-	cmd          command.Command
-	logStore     cmdlogger.ExecutionLogEntryStore
-	filesStore   files.Store
-	options      Options
-	cloneOptions workspace.CloneOptions
-	operations   *command.Operations
-	jobRuntime   runtime.Runtime
+	nameSet       *janitor.NameSet
+	cmdRunner     util.CmdRunner
+	cmd           command.Command
+	logStore      cmdlogger.ExecutionLogEntryStore
+	filesStore    files.Store
+	artifactStore ArtifactStore
+	options       Options
+	cloneOptions  workspace.CloneOptions
+	operations    *command.Operations
+	jobRuntime    runtime.Runtime
 }
 
 var (
@@ -76,6 +77,11 @@ func (h *handler) Handle(ctx context.Context, logger log.Logger, job types.Job)
 		log.String("repositoryName", job.RepositoryName),
 		log.String("commit", job.Commit))
 
+	// Jobs dequeued from multiple queues already carry the name of their queue.
+	if job.Queue == "" {
+		job.Queue = h.options.QueueName
+	}
+
 	start := time.Now()
 	defer func() {
 		if honey.Enabled() {
@@ -144,9 +150,11 @@ func (h *handler) Handle(ctx context.Context, logger log.Logger, job types.Job)
 		}
 	}()
 
+	// Upload the artifacts of the steps that ran, also when a step failed.
+	defer h.uploadArtifacts(ctx, logger, job, ws.WorkingDirectory())
+
 	// Get the commands we will execute.
 	logger.Info("Creating commands")
-	job.Queue = h.options.QueueName
 	commands, err := h.jobRuntime.NewRunnerSpecs(ws, job)
 	if err != nil {
 		return errors.Wrap(err, "creating commands")
@@ -249,6 +257,9 @@ func (h *handler) handle(ctx context.Context, logger log.Logger, commandLogger c
 		}
 	}()
 
+	// Upload the artifacts of the steps that ran, also when a step failed.
+	defer h.uploadArtifacts(ctx, logger, job, ws.WorkingDirectory())
+
 	// Invoke each docker step sequentially
 	for i, dockerStep := range job.DockerSteps {
 		var key string
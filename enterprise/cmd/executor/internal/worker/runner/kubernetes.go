@@ -29,6 +29,8 @@ type kubernetesRunner struct {
 	jobNames       []string
 	secretName     string
 	volumeName     string
+	namespace      string
+	canceled       bool
 	dir            string
 	filesStore     files.Store
 	options        command.KubernetesContainerOptions
@@ -66,14 +68,16 @@ func (r *kubernetesRunner) TempDir() string {
 }
 
 func (r *kubernetesRunner) Teardown(ctx context.Context) error {
-	if !r.options.KeepJobs {
+	// Jobs of canceled runs are always cleaned up, otherwise their pods would keep running.
+	if !r.options.KeepJobs || r.canceled {
+		namespace := r.jobNamespace()
 		logEntry := r.commandLogger.LogEntry("teardown.kubernetes.job", nil)
 		defer logEntry.Close()
 
 		exitCode := 0
 		for _, name := range r.jobNames {
 			r.internalLogger.Debug("Deleting kubernetes job", log.String("name", name))
-			if err := r.cmd.DeleteJob(ctx, r.options.Namespace, name); err != nil {
+			if err := r.cmd.DeleteJob(ctx, namespace, name); err != nil {
 				r.internalLogger.Error(
 					"Failed to delete kubernetes job",
 					log.String("jobName", name),
@@ -85,7 +89,7 @@ func (r *kubernetesRunner) Teardown(ctx context.Context) error {
 		}
 
 		if r.secretName != "" {
-			if err := r.cmd.DeleteSecret(ctx, r.options.Namespace, r.secretName); err != nil {
+			if err := r.cmd.DeleteSecret(ctx, namespace, r.secretName); err != nil {
 				r.internalLogger.Error(
 					"Failed to delete kubernetes job secret",
 					log.String("secret", r.secretName),
@@ -97,7 +101,7 @@ func (r *kubernetesRunner) Teardown(ctx context.Context) error {
 		}
 
 		if r.volumeName != "" {
-			if err := r.cmd.DeleteJobPVC(ctx, r.options.Namespace, r.volumeName); err != nil {
+			if err := r.cmd.DeleteJobPVC(ctx, namespace, r.volumeName); err != nil {
 				r.internalLogger.Error(
 					"Failed to delete kubernetes job volume",
 					log.String("volume", r.volumeName),
@@ -114,7 +118,24 @@ func (r *kubernetesRunner) Teardown(ctx context.Context) error {
 	return nil
 }
 
-func (r *kubernetesRunner) Run(ctx context.Context, spec Spec) error {
+// jobNamespace returns the namespace the Jobs of this runner are created in.
+func (r *kubernetesRunner) jobNamespace() string {
+	if r.namespace != "" {
+		return r.namespace
+	}
+	return r.options.Namespace
+}
+
+func (r *kubernetesRunner) Run(ctx context.Context, spec Spec) (err error) {
+	defer func() {
+		if err != nil && ctx.Err() != nil {
+			r.canceled = true
+		}
+	}()
+
+	r.namespace = r.options.JobNamespace(spec.Job.Queue)
+	namespace := r.namespace
+
 	var job *batchv1.Job
 	if r.options.SingleJobPod {
 		workspaceFiles, err := files.GetWorkspaceFiles(ctx, r.filesStore, spec.Job, command.KubernetesJobMountPath)
@@ -125,14 +146,14 @@ func (r *kubernetesRunner) Run(ctx context.Context, spec Spec) error {
 		jobName := fmt.Sprintf("sg-executor-job-%s-%d", spec.Job.Queue, spec.Job.ID)
 
 		r.secretName = jobName + "-secrets"
-		secrets, err := r.cmd.CreateSecrets(ctx, r.options.Namespace, r.secretName, map[string]string{"TOKEN": spec.Job.Token})
+		secrets, err := r.cmd.CreateSecrets(ctx, namespace, r.secretName, map[string]string{"TOKEN": spec.Job.Token})
 		if err != nil {
 			return err
 		}
 
 		if r.options.JobVolume.Type == command.KubernetesVolumeTypePVC {
 			r.volumeName = jobName + "-pvc"
-			if err = r.cmd.CreateJobPVC(ctx, r.options.Namespace, r.volumeName, r.options.JobVolume.Size); err != nil {
+			if err = r.cmd.CreateJobPVC(ctx, namespace, r.volumeName, r.options.JobVolume.Size); err != nil {
 				return err
 			}
 		}
@@ -167,7 +188,7 @@ func (r *kubernetesRunner) Run(ctx context.Context, spec Spec) error {
 		)
 	}
 	r.internalLogger.Debug("Creating job", log.Int("jobID", spec.Job.ID))
-	if _, err := r.cmd.CreateJob(ctx, r.options.Namespace, job); err != nil {
+	if _, err := r.cmd.CreateJob(ctx, namespace, job); err != nil {
 		return errors.Wrap(err, "creating job")
 	}
 	r.jobNames = append(r.jobNames, job.Name)
@@ -175,7 +196,7 @@ func (r *kubernetesRunner) Run(ctx context.Context, spec Spec) error {
 	// Wait for the job to complete before reading the logs. This lets us get also get exit codes.
 	r.internalLogger.Debug("Waiting for pod to succeed", log.Int("jobID", spec.Job.ID), log.String("jobName", job.Name))
 
-	pod, podWaitErr := r.cmd.WaitForPodToSucceed(ctx, r.commandLogger, r.options.Namespace, job.Name, spec.CommandSpecs)
+	pod, podWaitErr := r.cmd.WaitForPodToSucceed(ctx, r.commandLogger, namespace, job.Name, spec.CommandSpecs)
 	// Handle when the wait failed to do the things.
 	if podWaitErr != nil && pod == nil {
 		return errors.Wrapf(podWaitErr, "waiting for job %s to complete", job.Name)
@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/apiclient"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/apiclient/artifacts"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/apiclient/files"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/apiclient/queue"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/janitor"
@@ -64,6 +65,9 @@ type Options struct {
 	// FilesOptions configures the client that interacts with the files API.
 	FilesOptions apiclient.BaseClientOptions
 
+	// ArtifactsOptions configures the client that uploads job artifacts.
+	ArtifactsOptions apiclient.BaseClientOptions
+
 	RunnerOptions runner.Options
 
 	// NodeExporterEndpoint is the URL of the local node_exporter endpoint, without
@@ -94,6 +98,11 @@ func NewWorker(observationCtx *observation.Context, nameSet *janitor.NameSet, op
 		return nil, errors.Wrap(err, "building files store")
 	}
 
+	artifactsClient, err := artifacts.New(observationCtx, options.ArtifactsOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "building artifacts store")
+	}
+
 	commandOps := command.NewOperations(observationCtx)
 	cloneOptions := workspace.CloneOptions{
 		ExecutorName:   options.WorkerOptions.WorkerHostname,
@@ -115,15 +124,16 @@ func NewWorker(observationCtx *observation.Context, nameSet *janitor.NameSet, op
 	}
 
 	h := &handler{
-		nameSet:      nameSet,
-		cmdRunner:    cmdRunner,
-		cmd:          cmd,
-		logStore:     queueClient,
-		filesStore:   filesClient,
-		options:      options,
-		cloneOptions: cloneOptions,
-		operations:   commandOps,
-		jobRuntime:   jobRuntime,
+		nameSet:       nameSet,
+		cmdRunner:     cmdRunner,
+		cmd:           cmd,
+		logStore:      queueClient,
+		filesStore:    filesClient,
+		artifactStore: artifactsClient,
+		options:       options,
+		cloneOptions:  cloneOptions,
+		operations:    commandOps,
+		jobRuntime:    jobRuntime,
 	}
 
 	return workerutil.NewWorker[types.Job](context.Background(), queueClient, h, options.WorkerOptions), nil
@@ -71,6 +71,7 @@ func Init(
 	})
 
 	enterpriseServices.PermissionsGitHubWebhook = webhooks.NewGitHubWebhook(log.Scoped("PermissionsGitHubWebhook", "permissions sync webhook handler for GitHub webhooks"))
+	enterpriseServices.PermissionsGitLabWebhook = webhooks.NewGitLabWebhook(log.Scoped("PermissionsGitLabWebhook", "permissions sync webhook handler for GitLab webhooks"))
 
 	var err error
 	authz.DefaultSubRepoPermsChecker, err = srp.NewSubRepoPermsClient(db.SubRepoPerms())
@@ -78,6 +79,8 @@ func Init(
 		return errors.Wrap(err, "Failed to createe sub-repo client")
 	}
 
+	graphqlbackend.RegisterNodeStateTopics("PermissionsSyncJob", resolvers.PermissionsSyncJobStateTopics)
+
 	graphqlbackend.AlertFuncs = append(graphqlbackend.AlertFuncs, func(args graphqlbackend.AlertFuncArgs) []*graphqlbackend.Alert {
 		if licensing.IsLicenseValid() {
 			return nil
@@ -0,0 +1,237 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+const (
+	permissionsExplanationReasonSiteAdmin        = "SITE_ADMIN"
+	permissionsExplanationReasonNoAuthzProvider  = "NO_AUTHZ_PROVIDER"
+	permissionsExplanationReasonPublicRepository = "PUBLIC_REPOSITORY"
+	permissionsExplanationReasonUnrestricted     = "UNRESTRICTED"
+	permissionsExplanationReasonPermissions      = "PERMISSIONS"
+	permissionsExplanationReasonNoPermissions    = "NO_PERMISSIONS"
+)
+
+func (r *Resolver) PermissionsExplanation(ctx context.Context, args *graphqlbackend.PermissionsExplanationArgs) (graphqlbackend.PermissionsExplanationResolver, error) {
+	// 🚨 SECURITY: Only site admins can explain permissions.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	userID, err := graphqlbackend.UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+	repoID, err := graphqlbackend.UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := r.db.Users().GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// The site admin may not have access to the repository when
+	// authz.enforceForSiteAdmins is set, so we look it up as an internal actor.
+	repo, err := r.db.Repos().Get(actor.WithInternalActor(ctx), repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The decision itself is made by the authz conditions of the repo store, so we
+	// ask it rather than reimplementing them.
+	_, err = r.db.Repos().Get(actor.WithActor(ctx, actor.FromUser(userID)), repoID)
+	if err != nil && !errcode.IsNotFound(err) {
+		return nil, err
+	}
+	allowed := err == nil
+
+	grants, err := r.db.Perms().LoadUserRepoPermissions(ctx, userID, repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowByDefault, providers := authz.GetProviders()
+	var provider authz.Provider
+	for _, p := range providers {
+		if p.ServiceType() == repo.ExternalRepo.ServiceType && p.ServiceID() == repo.ExternalRepo.ServiceID {
+			provider = p
+			break
+		}
+	}
+
+	var reason string
+	switch {
+	case user.SiteAdmin && !conf.Get().AuthzEnforceForSiteAdmins:
+		reason = permissionsExplanationReasonSiteAdmin
+	case !globals.PermissionsUserMapping().Enabled && allowByDefault && len(providers) == 0:
+		reason = permissionsExplanationReasonNoAuthzProvider
+	case !repo.Private:
+		reason = permissionsExplanationReasonPublicRepository
+	default:
+		unrestricted, err := r.db.Perms().IsRepoUnrestricted(ctx, repoID)
+		if err != nil {
+			return nil, err
+		}
+		if unrestricted {
+			reason = permissionsExplanationReasonUnrestricted
+		} else if len(grants) > 0 {
+			reason = permissionsExplanationReasonPermissions
+		} else {
+			reason = permissionsExplanationReasonNoPermissions
+		}
+	}
+
+	return &permissionsExplanationResolver{
+		db:       r.db,
+		userID:   userID,
+		repo:     repo,
+		allowed:  allowed,
+		reason:   reason,
+		provider: provider,
+		grants:   grants,
+	}, nil
+}
+
+type permissionsExplanationResolver struct {
+	db       database.DB
+	userID   int32
+	repo     *types.Repo
+	allowed  bool
+	reason   string
+	provider authz.Provider
+	grants   []authz.Permission
+}
+
+func (r *permissionsExplanationResolver) Allowed() bool { return r.allowed }
+
+func (r *permissionsExplanationResolver) Reason() string { return r.reason }
+
+func (r *permissionsExplanationResolver) AuthzProvider() graphqlbackend.PermissionsExplanationProviderResolver {
+	if r.provider == nil {
+		return nil
+	}
+	return &permissionsExplanationProviderResolver{provider: r.provider}
+}
+
+func (r *permissionsExplanationResolver) Grants(ctx context.Context) ([]graphqlbackend.PermissionsExplanationGrantResolver, error) {
+	grants := make([]graphqlbackend.PermissionsExplanationGrantResolver, 0, len(r.grants))
+	for _, grant := range r.grants {
+		g := &permissionsExplanationGrantResolver{grant: grant}
+		if grant.ExternalAccountID != 0 {
+			account, err := r.db.UserExternalAccounts().Get(ctx, grant.ExternalAccountID)
+			if err != nil && !errcode.IsNotFound(err) {
+				return nil, err
+			}
+			g.account = account
+		}
+		grants = append(grants, g)
+	}
+	return grants, nil
+}
+
+func (r *permissionsExplanationResolver) LatestUserSyncJob(ctx context.Context) (graphqlbackend.PermissionsSyncJobResolver, error) {
+	return r.latestSyncJob(ctx, database.ListPermissionSyncJobOpts{UserID: int(r.userID), NotCanceled: true})
+}
+
+func (r *permissionsExplanationResolver) LatestRepositorySyncJob(ctx context.Context) (graphqlbackend.PermissionsSyncJobResolver, error) {
+	return r.latestSyncJob(ctx, database.ListPermissionSyncJobOpts{RepoID: int(r.repo.ID), NotCanceled: true})
+}
+
+func (r *permissionsExplanationResolver) latestSyncJob(ctx context.Context, opts database.ListPermissionSyncJobOpts) (graphqlbackend.PermissionsSyncJobResolver, error) {
+	job, err := r.db.PermissionSyncJobs().GetLatestFinishedSyncJob(ctx, opts)
+	if err != nil || job == nil {
+		return nil, err
+	}
+	return r.syncJobResolver(ctx, job)
+}
+
+func (r *permissionsExplanationResolver) PendingSyncJobs(ctx context.Context) ([]graphqlbackend.PermissionsSyncJobResolver, error) {
+	var resolvers []graphqlbackend.PermissionsSyncJobResolver
+	for _, opts := range []database.ListPermissionSyncJobOpts{
+		{UserID: int(r.userID), State: database.PermissionsSyncJobStateQueued},
+		{UserID: int(r.userID), State: database.PermissionsSyncJobStateProcessing},
+		{RepoID: int(r.repo.ID), State: database.PermissionsSyncJobStateQueued},
+		{RepoID: int(r.repo.ID), State: database.PermissionsSyncJobStateProcessing},
+	} {
+		jobs, err := r.db.PermissionSyncJobs().List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range jobs {
+			resolver, err := r.syncJobResolver(ctx, job)
+			if err != nil {
+				return nil, err
+			}
+			resolvers = append(resolvers, resolver)
+		}
+	}
+	return resolvers, nil
+}
+
+func (r *permissionsExplanationResolver) syncJobResolver(ctx context.Context, job *database.PermissionSyncJob) (graphqlbackend.PermissionsSyncJobResolver, error) {
+	store := &permissionsSyncJobConnectionStore{db: r.db}
+	syncSubject, err := store.resolveSubject(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	return &permissionsSyncJobResolver{db: r.db, job: job, syncSubject: syncSubject}, nil
+}
+
+type permissionsExplanationProviderResolver struct {
+	provider authz.Provider
+}
+
+func (r *permissionsExplanationProviderResolver) ServiceType() string {
+	return r.provider.ServiceType()
+}
+
+func (r *permissionsExplanationProviderResolver) ServiceID() string {
+	return r.provider.ServiceID()
+}
+
+func (r *permissionsExplanationProviderResolver) URN() string {
+	return r.provider.URN()
+}
+
+type permissionsExplanationGrantResolver struct {
+	grant   authz.Permission
+	account *extsvc.Account
+}
+
+func (r *permissionsExplanationGrantResolver) Source() string { return string(r.grant.Source) }
+
+func (r *permissionsExplanationGrantResolver) ExternalAccountServiceType() *string {
+	if r.account == nil {
+		return nil
+	}
+	return &r.account.ServiceType
+}
+
+func (r *permissionsExplanationGrantResolver) ExternalAccountServiceID() *string {
+	if r.account == nil {
+		return nil
+	}
+	return &r.account.ServiceID
+}
+
+func (r *permissionsExplanationGrantResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.grant.CreatedAt}
+}
+
+func (r *permissionsExplanationGrantResolver) UpdatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.grant.UpdatedAt}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/redispubsub"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
@@ -330,6 +331,16 @@ func unmarshalPermissionsSyncJobID(id graphql.ID) (jobID int, err error) {
 	return
 }
 
+// PermissionsSyncJobStateTopics returns the redispubsub topics on which changes
+// to the state of the given permissions sync job are published.
+func PermissionsSyncJobStateTopics(_ context.Context, id graphql.ID) ([]string, error) {
+	jobID, err := unmarshalPermissionsSyncJobID(id)
+	if err != nil {
+		return nil, err
+	}
+	return []string{redispubsub.RecordTopic("permission_sync_jobs", jobID)}, nil
+}
+
 func intToInt32Ptr(value int) *int32 {
 	return pointers.Ptr(int32(value))
 }
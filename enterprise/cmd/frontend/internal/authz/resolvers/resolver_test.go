@@ -1203,6 +1203,100 @@ func TestResolver_AuthzProviderTypes(t *testing.T) {
 	})
 }
 
+func TestResolver_PermissionsExplanation(t *testing.T) {
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		users := database.NewStrictMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{}, nil)
+
+		db := database.NewStrictMockDB()
+		db.UsersFunc.SetDefaultReturn(users)
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&Resolver{db: db}).PermissionsExplanation(ctx, &graphqlbackend.PermissionsExplanationArgs{
+			User:       graphqlbackend.MarshalUserID(2),
+			Repository: graphqlbackend.MarshalRepositoryID(1),
+		})
+		if want := auth.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	ghProvider := github.NewProvider("https://github.com", github.ProviderOptions{GitHubURL: mustURL(t, "https://github.com")})
+	authz.SetProviders(false, []authz.Provider{ghProvider})
+	t.Cleanup(func() { authz.SetProviders(true, nil) })
+
+	repo := &types.Repo{
+		ID:      1,
+		Name:    "github.com/acme/private",
+		Private: true,
+		ExternalRepo: api.ExternalRepoSpec{
+			ServiceType: extsvc.TypeGitHub,
+			ServiceID:   ghProvider.ServiceID(),
+		},
+	}
+
+	setup := func(grants []authz.Permission) database.DB {
+		users := database.NewStrictMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+		users.GetByIDFunc.SetDefaultReturn(&types.User{ID: 2}, nil)
+
+		repos := database.NewStrictMockRepoStore()
+		repos.GetFunc.SetDefaultHook(func(ctx context.Context, id api.RepoID) (*types.Repo, error) {
+			// Access is decided by the authz conditions of the repo store.
+			if !actor.FromContext(ctx).IsInternal() && len(grants) == 0 {
+				return nil, &database.RepoNotFoundErr{ID: id}
+			}
+			return repo, nil
+		})
+
+		perms := database.NewStrictMockPermsStore()
+		perms.LoadUserRepoPermissionsFunc.SetDefaultReturn(grants, nil)
+		perms.IsRepoUnrestrictedFunc.SetDefaultReturn(false, nil)
+
+		db := database.NewStrictMockDB()
+		db.UsersFunc.SetDefaultReturn(users)
+		db.ReposFunc.SetDefaultReturn(repos)
+		db.PermsFunc.SetDefaultReturn(perms)
+		return db
+	}
+
+	args := &graphqlbackend.PermissionsExplanationArgs{
+		User:       graphqlbackend.MarshalUserID(2),
+		Repository: graphqlbackend.MarshalRepositoryID(1),
+	}
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+
+	t.Run("no permissions", func(t *testing.T) {
+		result, err := (&Resolver{db: setup(nil)}).PermissionsExplanation(ctx, args)
+		require.NoError(t, err)
+		assert.False(t, result.Allowed())
+		assert.Equal(t, "NO_PERMISSIONS", result.Reason())
+		require.NotNil(t, result.AuthzProvider())
+		assert.Equal(t, extsvc.TypeGitHub, result.AuthzProvider().ServiceType())
+	})
+
+	t.Run("synced permissions", func(t *testing.T) {
+		db := setup([]authz.Permission{{UserID: 2, RepoID: 1, ExternalAccountID: 3, Source: authz.SourceUserSync, UpdatedAt: clock()}})
+		accounts := database.NewStrictMockUserExternalAccountsStore()
+		accounts.GetFunc.SetDefaultReturn(&extsvc.Account{ID: 3, AccountSpec: extsvc.AccountSpec{ServiceType: extsvc.TypeGitHub, ServiceID: ghProvider.ServiceID()}}, nil)
+		db.(*database.MockDB).UserExternalAccountsFunc.SetDefaultReturn(accounts)
+
+		result, err := (&Resolver{db: db}).PermissionsExplanation(ctx, args)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed())
+		assert.Equal(t, "PERMISSIONS", result.Reason())
+
+		grants, err := result.Grants(ctx)
+		require.NoError(t, err)
+		require.Len(t, grants, 1)
+		assert.Equal(t, "user_sync", grants[0].Source())
+		assert.Equal(t, pointers.Ptr(ghProvider.ServiceID()), grants[0].ExternalAccountServiceID())
+	})
+}
+
 func mustURL(t *testing.T, u string) *url.URL {
 	parsed, err := url.Parse(u)
 	if err != nil {
@@ -1784,6 +1878,84 @@ func TestResolver_SetSubRepositoryPermissionsForUsers(t *testing.T) {
 	})
 }
 
+type fakeSubRepoPermsExplainer struct {
+	*authz.MockSubRepoPermissionChecker
+	explanation authz.SubRepoPermissionsExplanation
+}
+
+func (f *fakeSubRepoPermsExplainer) ExplainPermissions(_ context.Context, _ int32, _ authz.RepoContent) (authz.SubRepoPermissionsExplanation, error) {
+	return f.explanation, nil
+}
+
+func TestResolver_SubRepositoryPermissionsExplanation(t *testing.T) {
+	t.Run("authenticated as non-admin, asking not for self", func(t *testing.T) {
+		users := database.NewStrictMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 42}, nil)
+
+		db := database.NewStrictMockDB()
+		db.UsersFunc.SetDefaultReturn(users)
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 42})
+		result, err := (&Resolver{db: db}).SubRepositoryPermissionsExplanation(ctx, &graphqlbackend.SubRepositoryPermissionsExplanationArgs{
+			User:       graphqlbackend.MarshalUserID(1),
+			Repository: graphqlbackend.MarshalRepositoryID(1),
+			Path:       "README.md",
+		})
+		if want := auth.ErrMustBeSiteAdminOrSameUser; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	users := database.NewStrictMockUserStore()
+	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+
+	repos := database.NewStrictMockRepoStore()
+	repos.GetFunc.SetDefaultHook(func(_ context.Context, id api.RepoID) (*types.Repo, error) {
+		return &types.Repo{ID: id, Name: "github.com/acme/sample"}, nil
+	})
+
+	db := database.NewStrictMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	db.ReposFunc.SetDefaultReturn(repos)
+
+	before := authz.DefaultSubRepoPermsChecker
+	t.Cleanup(func() { authz.DefaultSubRepoPermsChecker = before })
+	authz.DefaultSubRepoPermsChecker = &fakeSubRepoPermsExplainer{
+		MockSubRepoPermissionChecker: authz.NewMockSubRepoPermissionChecker(),
+		explanation: authz.SubRepoPermissionsExplanation{
+			Perms:  authz.None,
+			Source: authz.SubRepoPermissionsSourcePathRule,
+			Rule:   "deny secrets/**",
+		},
+	}
+
+	graphqlbackend.RunTest(t, &graphqlbackend.Test{
+		Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+		Schema:  mustParseGraphQLSchema(t, db),
+		Query: fmt.Sprintf(`
+			{
+				subRepositoryPermissionsExplanation(user: %q, repository: %q, path: "secrets/token") {
+					visible
+					source
+					rule
+				}
+			}
+		`, graphqlbackend.MarshalUserID(2), graphqlbackend.MarshalRepositoryID(1)),
+		ExpectedResult: `
+			{
+				"subRepositoryPermissionsExplanation": {
+					"visible": false,
+					"source": "PATH_RULE",
+					"rule": "deny secrets/**"
+				}
+			}
+		`,
+	})
+}
+
 func TestResolver_BitbucketProjectPermissionJobs(t *testing.T) {
 	t.Run("disabled on dotcom", func(t *testing.T) {
 		envvar.MockSourcegraphDotComMode(true)
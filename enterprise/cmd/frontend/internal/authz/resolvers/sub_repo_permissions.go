@@ -0,0 +1,66 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+)
+
+func (r *Resolver) SubRepositoryPermissionsExplanation(ctx context.Context, args *graphqlbackend.SubRepositoryPermissionsExplanationArgs) (graphqlbackend.SubRepositoryPermissionsExplanationResolver, error) {
+	userID, err := graphqlbackend.UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Users can explain their own permissions, site admins the
+	// permissions of all users.
+	if err := auth.CheckSiteAdminOrSameUser(ctx, r.db, userID); err != nil {
+		return nil, err
+	}
+
+	repoID, err := graphqlbackend.UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the repository exists and is visible to the current user.
+	repo, err := r.db.Repos().Get(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	explainer, ok := authz.DefaultSubRepoPermsChecker.(authz.SubRepoPermissionsExplainer)
+	if !ok {
+		return &subRepoPermsExplanationResolver{explanation: authz.SubRepoPermissionsExplanation{
+			Perms:  authz.Read,
+			Source: authz.SubRepoPermissionsSourceDisabled,
+		}}, nil
+	}
+
+	explanation, err := explainer.ExplainPermissions(ctx, userID, authz.RepoContent{Repo: repo.Name, Path: args.Path})
+	if err != nil {
+		return nil, err
+	}
+	return &subRepoPermsExplanationResolver{explanation: explanation}, nil
+}
+
+type subRepoPermsExplanationResolver struct {
+	explanation authz.SubRepoPermissionsExplanation
+}
+
+func (r *subRepoPermsExplanationResolver) Visible() bool {
+	return r.explanation.Perms.Include(authz.Read)
+}
+
+func (r *subRepoPermsExplanationResolver) Source() string {
+	return strings.ToUpper(string(r.explanation.Source))
+}
+
+func (r *subRepoPermsExplanationResolver) Rule() *string {
+	if r.explanation.Rule == "" {
+		return nil
+	}
+	return &r.explanation.Rule
+}
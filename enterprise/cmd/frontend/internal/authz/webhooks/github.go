@@ -13,7 +13,6 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/webhooks"
 	"github.com/sourcegraph/sourcegraph/internal/api"
-	"github.com/sourcegraph/sourcegraph/internal/authz/permssync"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
@@ -166,10 +165,9 @@ func (h *GitHubWebhook) getUserAndSyncPerms(ctx context.Context, db database.DB,
 		return errors.Newf("no github external accounts found with account id %d", user.GetID())
 	}
 
-	permssync.SchedulePermsSync(ctx, h.logger, db, protocol.PermsSyncRequest{
-		UserIDs:      []int32{externalAccounts[0].UserID},
-		Reason:       reason,
-		ProcessAfter: time.Now().Add(sleepTime),
+	schedulePermsSync(ctx, h.logger, db, protocol.PermsSyncRequest{
+		UserIDs: []int32{externalAccounts[0].UserID},
+		Reason:  reason,
 	})
 
 	return err
@@ -183,10 +181,9 @@ func (h *GitHubWebhook) getRepoAndSyncPerms(ctx context.Context, db database.DB,
 		return err
 	}
 
-	permssync.SchedulePermsSync(ctx, h.logger, db, protocol.PermsSyncRequest{
-		RepoIDs:      []api.RepoID{repo.ID},
-		Reason:       reason,
-		ProcessAfter: time.Now().Add(sleepTime),
+	schedulePermsSync(ctx, h.logger, db, protocol.PermsSyncRequest{
+		RepoIDs: []api.RepoID{repo.ID},
+		Reason:  reason,
 	})
 
 	return nil
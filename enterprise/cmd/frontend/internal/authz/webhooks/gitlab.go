@@ -0,0 +1,113 @@
+package webhooks
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	gitlabwebhooks "github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var gitlabEvents = []string{
+	gitlabwebhooks.EventNameUserAddToGroup,
+	gitlabwebhooks.EventNameUserRemoveFromGroup,
+	gitlabwebhooks.EventNameUserUpdateForGroup,
+	gitlabwebhooks.EventNameUserAddToTeam,
+	gitlabwebhooks.EventNameUserRemoveFromTeam,
+	gitlabwebhooks.EventNameUserUpdateForTeam,
+}
+
+type GitLabWebhook struct {
+	logger log.Logger
+}
+
+func NewGitLabWebhook(logger log.Logger) *GitLabWebhook {
+	return &GitLabWebhook{logger: logger}
+}
+
+func (h *GitLabWebhook) Register(router *webhooks.Router) {
+	router.Register(
+		h.handleGitLabWebhook,
+		extsvc.KindGitLab,
+		gitlabEvents...,
+	)
+}
+
+func (h *GitLabWebhook) handleGitLabWebhook(_ context.Context, db database.DB, codeHostURN extsvc.CodeHostBaseURL, payload any) error {
+	// Like on GitHub, membership changes may not have propagated yet when the
+	// webhook is delivered, so we wait before handling it.
+	go func() {
+		time.Sleep(sleepTime)
+		eventContext, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+		defer cancel()
+
+		if e, ok := payload.(*gitlabwebhooks.MemberEvent); ok {
+			if err := h.handleMemberEvent(eventContext, db, e, codeHostURN); err != nil {
+				h.logger.Debug("failed to handle GitLab member event", log.String("event", e.EventName), log.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
+func (h *GitLabWebhook) handleMemberEvent(ctx context.Context, db database.DB, e *gitlabwebhooks.MemberEvent, codeHostURN extsvc.CodeHostBaseURL) error {
+	var reason database.PermissionsSyncJobReason
+	switch e.EventName {
+	case gitlabwebhooks.EventNameUserAddToGroup, gitlabwebhooks.EventNameUserAddToTeam:
+		reason = database.ReasonGitLabMemberAddedEvent
+	case gitlabwebhooks.EventNameUserRemoveFromGroup, gitlabwebhooks.EventNameUserRemoveFromTeam:
+		reason = database.ReasonGitLabMemberRemovedEvent
+	case gitlabwebhooks.EventNameUserUpdateForGroup, gitlabwebhooks.EventNameUserUpdateForTeam:
+		reason = database.ReasonGitLabMemberUpdatedEvent
+	default:
+		return nil
+	}
+
+	var req protocol.PermsSyncRequest
+	externalAccounts, err := db.UserExternalAccounts().List(ctx, database.ExternalAccountsListOptions{
+		ServiceID:      codeHostURN.String(),
+		AccountID:      strconv.Itoa(int(e.UserID)),
+		ExcludeExpired: true,
+	})
+	if err != nil {
+		return err
+	}
+	if len(externalAccounts) > 0 {
+		req.UserIDs = []int32{externalAccounts[0].UserID}
+	}
+
+	// The project is only set for project member events. Syncing its permissions
+	// also covers users that have not connected their GitLab account yet.
+	if e.ProjectID != 0 {
+		repos, err := db.Repos().List(actor.WithInternalActor(ctx), database.ReposListOptions{
+			ExternalRepos: []api.ExternalRepoSpec{{
+				ID:          strconv.Itoa(e.ProjectID),
+				ServiceType: extsvc.TypeGitLab,
+				ServiceID:   codeHostURN.String(),
+			}},
+		})
+		if err != nil {
+			return err
+		}
+		for _, repo := range repos {
+			req.RepoIDs = append(req.RepoIDs, repo.ID)
+		}
+	}
+
+	if len(req.UserIDs) == 0 && len(req.RepoIDs) == 0 {
+		return errors.Newf("no gitlab external accounts or repositories found for user id %d", e.UserID)
+	}
+
+	req.Reason = reason
+	schedulePermsSync(ctx, h.logger, db, req)
+	return nil
+}
@@ -0,0 +1,117 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	fewebhooks "github.com/sourcegraph/sourcegraph/cmd/frontend/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz/permssync"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	gitlabwebhooks "github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestGitLabWebhooks(t *testing.T) {
+	TestSetGitHubHandlerSleepTime(t, 0)
+
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+
+	codeHostURN, err := extsvc.NewCodeHostBaseURL("https://gitlab.com/")
+	require.NoError(t, err)
+
+	u, err := db.Users().Create(ctx, database.NewUser{
+		Username:        "testuser",
+		EmailIsVerified: true,
+	})
+	require.NoError(t, err)
+
+	err = db.UserExternalAccounts().Insert(ctx, u.ID, extsvc.AccountSpec{
+		ServiceType: extsvc.TypeGitLab,
+		ServiceID:   codeHostURN.String(),
+		AccountID:   "41",
+	}, extsvc.AccountData{})
+	require.NoError(t, err)
+
+	repo := &types.Repo{
+		Name: "gitlab.com/jsmith/storecloud",
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "74",
+			ServiceType: extsvc.TypeGitLab,
+			ServiceID:   codeHostURN.String(),
+		},
+	}
+	require.NoError(t, db.Repos().Create(ctx, repo))
+
+	router := &fewebhooks.Router{Logger: logger, DB: db}
+	NewGitLabWebhook(logger).Register(router)
+
+	tests := []struct {
+		name       string
+		event      *gitlabwebhooks.MemberEvent
+		wantReason database.PermissionsSyncJobReason
+		wantUsers  []int32
+		wantRepos  []api.RepoID
+	}{
+		{
+			name: "user added to group",
+			event: &gitlabwebhooks.MemberEvent{
+				EventName: gitlabwebhooks.EventNameUserAddToGroup,
+				UserID:    41,
+				GroupID:   78,
+			},
+			wantReason: database.ReasonGitLabMemberAddedEvent,
+			wantUsers:  []int32{u.ID},
+		},
+		{
+			name: "user removed from project",
+			event: &gitlabwebhooks.MemberEvent{
+				EventName: gitlabwebhooks.EventNameUserRemoveFromTeam,
+				UserID:    41,
+				ProjectID: 74,
+			},
+			wantReason: database.ReasonGitLabMemberRemovedEvent,
+			wantUsers:  []int32{u.ID},
+			wantRepos:  []api.RepoID{repo.ID},
+		},
+		{
+			name: "unknown user updated for project",
+			event: &gitlabwebhooks.MemberEvent{
+				EventName: gitlabwebhooks.EventNameUserUpdateForTeam,
+				UserID:    42,
+				ProjectID: 74,
+			},
+			wantReason: database.ReasonGitLabMemberUpdatedEvent,
+			wantRepos:  []api.RepoID{repo.ID},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			called := make(chan protocol.PermsSyncRequest, 1)
+			permssync.MockSchedulePermsSync = func(_ context.Context, _ log.Logger, _ database.DB, req protocol.PermsSyncRequest) {
+				called <- req
+			}
+			t.Cleanup(func() { permssync.MockSchedulePermsSync = nil })
+
+			err := router.Dispatch(ctx, test.event.EventName, extsvc.KindGitLab, codeHostURN, test.event)
+			require.NoError(t, err)
+
+			req := <-called
+			require.Equal(t, test.wantReason, req.Reason)
+			require.Equal(t, test.wantUsers, req.UserIDs)
+			require.Equal(t, test.wantRepos, req.RepoIDs)
+			require.True(t, req.Coalesce)
+			require.False(t, req.ProcessAfter.IsZero())
+		})
+	}
+}
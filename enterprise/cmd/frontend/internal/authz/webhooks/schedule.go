@@ -0,0 +1,38 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz/permssync"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+)
+
+// syncLimiter limits the rate at which webhook events schedule permission syncs.
+// Code hosts can deliver large bursts of events, e.g. when a team with many
+// members is added to a repository, so syncs exceeding the rate are postponed
+// instead of being scheduled right away. They are never dropped.
+var syncLimiter = rate.NewLimiter(rate.Limit(10), 100)
+
+// schedulePermsSync schedules a permission sync for the users and repositories of
+// req, after sleepTime and the delay imposed by syncLimiter. The sync jobs are
+// coalesced with the jobs already scheduled for the same users and repositories,
+// so that repeated events for a user or repository result in a single sync.
+func schedulePermsSync(ctx context.Context, logger log.Logger, db database.DB, req protocol.PermsSyncRequest) {
+	n := len(req.UserIDs) + len(req.RepoIDs)
+	if n == 0 {
+		return
+	}
+	if n > syncLimiter.Burst() {
+		n = syncLimiter.Burst()
+	}
+
+	now := time.Now()
+	req.ProcessAfter = now.Add(sleepTime + syncLimiter.ReserveN(now, n).DelayFrom(now))
+	req.Coalesce = true
+	permssync.SchedulePermsSync(ctx, logger, db, req)
+}
@@ -288,6 +288,38 @@ func (r *batchChangeResolver) CurrentSpec(ctx context.Context) (graphqlbackend.B
 	return &batchSpecResolver{store: r.store, batchSpec: batchSpec, logger: r.logger}, nil
 }
 
+func (r *batchChangeResolver) RolloutPolicy(ctx context.Context) (graphqlbackend.BatchChangeRolloutPolicyResolver, error) {
+	policy, err := r.store.GetBatchChangeRolloutPolicy(ctx, store.GetBatchChangeRolloutPolicyOpts{BatchChangeID: r.batchChange.ID})
+	if err != nil {
+		if err == store.ErrNoResults {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &batchChangeRolloutPolicyResolver{policy: policy}, nil
+}
+
+func (r *batchChangeResolver) Approvers(ctx context.Context) ([]*graphqlbackend.UserResolver, error) {
+	userIDs, err := r.store.ListBatchChangeApprovers(ctx, r.batchChange.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*graphqlbackend.UserResolver, 0, len(userIDs))
+	for _, id := range userIDs {
+		user, err := graphqlbackend.UserByIDInt32(ctx, r.store.DatabaseDB(), id)
+		if err != nil {
+			if errcode.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
 func (r *batchChangeResolver) BulkOperations(
 	ctx context.Context,
 	args *graphqlbackend.ListBatchChangeBulkOperationArgs,
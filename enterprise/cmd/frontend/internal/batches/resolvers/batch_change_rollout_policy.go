@@ -0,0 +1,54 @@
+package resolvers
+
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+)
+
+type batchChangeRolloutPolicyResolver struct {
+	policy *btypes.BatchChangeRolloutPolicy
+}
+
+var _ graphqlbackend.BatchChangeRolloutPolicyResolver = &batchChangeRolloutPolicyResolver{}
+
+func (r *batchChangeRolloutPolicyResolver) MaxChangesets() int32 {
+	return int32(r.policy.MaxChangesets)
+}
+
+func (r *batchChangeRolloutPolicyResolver) WindowHours() int32 {
+	return int32(r.policy.Window / time.Hour)
+}
+
+func (r *batchChangeRolloutPolicyResolver) PrioritizeByActivity() bool {
+	return r.policy.PrioritizeByActivity
+}
+
+func (r *batchChangeRolloutPolicyResolver) MinMergeRate() *float64 {
+	return r.policy.MinMergeRate
+}
+
+func (r *batchChangeRolloutPolicyResolver) MaxCIFailureRate() *float64 {
+	return r.policy.MaxCIFailureRate
+}
+
+func (r *batchChangeRolloutPolicyResolver) WindowStartedAt() *gqlutil.DateTime {
+	return gqlutil.FromTime(r.policy.WindowStartedAt)
+}
+
+func (r *batchChangeRolloutPolicyResolver) PublishedInWindow() int32 {
+	return int32(r.policy.PublishedInWindow)
+}
+
+func (r *batchChangeRolloutPolicyResolver) PausedAt() *gqlutil.DateTime {
+	return gqlutil.FromTime(r.policy.PausedAt)
+}
+
+func (r *batchChangeRolloutPolicyResolver) PauseReason() *string {
+	if r.policy.PauseReason == "" {
+		return nil
+	}
+	return &r.policy.PauseReason
+}
@@ -528,6 +528,19 @@ func (r *batchSpecResolver) Source() string {
 	return btypes.BatchSpecSourceLocal.ToGraphQL()
 }
 
+func (r *batchSpecResolver) Approvals(ctx context.Context) ([]graphqlbackend.BatchSpecApprovalResolver, error) {
+	approvals, err := r.store.ListBatchSpecApprovals(ctx, r.batchSpec.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.BatchSpecApprovalResolver, 0, len(approvals))
+	for _, a := range approvals {
+		resolvers = append(resolvers, &batchSpecApprovalResolver{store: r.store, approval: a})
+	}
+	return resolvers, nil
+}
+
 func (r *batchSpecResolver) computeNamespace(ctx context.Context) (*graphqlbackend.NamespaceResolver, error) {
 	r.namespaceOnce.Do(func() {
 		if r.preloadedNamespace != nil {
@@ -0,0 +1,30 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+)
+
+type batchSpecApprovalResolver struct {
+	store    *store.Store
+	approval *btypes.BatchSpecApproval
+}
+
+var _ graphqlbackend.BatchSpecApprovalResolver = &batchSpecApprovalResolver{}
+
+func (r *batchSpecApprovalResolver) Approver(ctx context.Context) (*graphqlbackend.UserResolver, error) {
+	user, err := graphqlbackend.UserByIDInt32(ctx, r.store.DatabaseDB(), r.approval.UserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *batchSpecApprovalResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.approval.CreatedAt}
+}
@@ -15,6 +15,7 @@ import (
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/redispubsub"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -30,6 +31,29 @@ func unmarshalBulkOperationID(id graphql.ID) (bulkOperationID string, err error)
 	return
 }
 
+// BulkOperationStateTopics returns a function returning the redispubsub topics
+// on which changes to the state of the changeset jobs of a bulk operation are
+// published.
+func BulkOperationStateTopics(s *store.Store) graphqlbackend.NodeStateTopicsFunc {
+	return func(ctx context.Context, id graphql.ID) ([]string, error) {
+		bulkOperationID, err := unmarshalBulkOperationID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		jobIDs, err := s.ListBulkOperationChangesetJobIDs(ctx, bulkOperationID)
+		if err != nil {
+			return nil, err
+		}
+
+		topics := make([]string, 0, len(jobIDs))
+		for _, jobID := range jobIDs {
+			topics = append(topics, redispubsub.RecordTopic("changeset_jobs", jobID))
+		}
+		return topics, nil
+	}
+}
+
 type bulkOperationResolver struct {
 	store           *store.Store
 	logger          log.Logger
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/graph-gophers/graphql-go"
 	"go.opentelemetry.io/otel/attribute"
@@ -993,6 +994,172 @@ func listChangesetOptsFromArgs(args *graphqlbackend.ListChangesetsArgs, batchCha
 	return opts, safe, nil
 }
 
+func (r *Resolver) SetBatchChangeRolloutPolicy(ctx context.Context, args *graphqlbackend.SetBatchChangeRolloutPolicyArgs) (_ graphqlbackend.BatchChangeResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.SetBatchChangeRolloutPolicy", attribute.String("batchChange", string(args.BatchChange)))
+	defer tr.FinishWithErr(&err)
+
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DatabaseDB()); err != nil {
+		return nil, err
+	}
+
+	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
+		return nil, err
+	}
+
+	batchChangeID, err := unmarshalBatchChangeID(args.BatchChange)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshaling batch change id")
+	}
+
+	if batchChangeID == 0 {
+		return nil, ErrIDIsZero{}
+	}
+
+	svc := service.New(r.store)
+	policy := &btypes.BatchChangeRolloutPolicy{
+		BatchChangeID:        batchChangeID,
+		MaxChangesets:        int(args.MaxChangesets),
+		Window:               time.Duration(args.WindowHours) * time.Hour,
+		PrioritizeByActivity: args.PrioritizeByActivity,
+		MinMergeRate:         args.MinMergeRate,
+		MaxCIFailureRate:     args.MaxCIFailureRate,
+	}
+	// 🚨 SECURITY: SetBatchChangeRolloutPolicy checks whether current user is authorized.
+	if err := svc.SetBatchChangeRolloutPolicy(ctx, policy); err != nil {
+		return nil, errors.Wrap(err, "setting rollout policy")
+	}
+
+	return r.batchChangeByID(ctx, args.BatchChange)
+}
+
+func (r *Resolver) DeleteBatchChangeRolloutPolicy(ctx context.Context, args *graphqlbackend.DeleteBatchChangeRolloutPolicyArgs) (_ graphqlbackend.BatchChangeResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.DeleteBatchChangeRolloutPolicy", attribute.String("batchChange", string(args.BatchChange)))
+	defer tr.FinishWithErr(&err)
+
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DatabaseDB()); err != nil {
+		return nil, err
+	}
+
+	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
+		return nil, err
+	}
+
+	batchChangeID, err := unmarshalBatchChangeID(args.BatchChange)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshaling batch change id")
+	}
+
+	if batchChangeID == 0 {
+		return nil, ErrIDIsZero{}
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: DeleteBatchChangeRolloutPolicy checks whether current user is authorized.
+	if err := svc.DeleteBatchChangeRolloutPolicy(ctx, batchChangeID); err != nil {
+		return nil, errors.Wrap(err, "deleting rollout policy")
+	}
+
+	return r.batchChangeByID(ctx, args.BatchChange)
+}
+
+func (r *Resolver) ResumeBatchChangeRollout(ctx context.Context, args *graphqlbackend.ResumeBatchChangeRolloutArgs) (_ graphqlbackend.BatchChangeResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.ResumeBatchChangeRollout", attribute.String("batchChange", string(args.BatchChange)))
+	defer tr.FinishWithErr(&err)
+
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DatabaseDB()); err != nil {
+		return nil, err
+	}
+
+	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
+		return nil, err
+	}
+
+	batchChangeID, err := unmarshalBatchChangeID(args.BatchChange)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshaling batch change id")
+	}
+
+	if batchChangeID == 0 {
+		return nil, ErrIDIsZero{}
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: ResumeBatchChangeRollout checks whether current user is authorized.
+	if err := svc.ResumeBatchChangeRollout(ctx, batchChangeID); err != nil {
+		return nil, errors.Wrap(err, "resuming rollout")
+	}
+
+	return r.batchChangeByID(ctx, args.BatchChange)
+}
+
+func (r *Resolver) SetBatchChangeApprovers(ctx context.Context, args *graphqlbackend.SetBatchChangeApproversArgs) (_ graphqlbackend.BatchChangeResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.SetBatchChangeApprovers",
+		attribute.String("batchChange", string(args.BatchChange)),
+		attribute.Int("approvers.len", len(args.Approvers)))
+	defer tr.FinishWithErr(&err)
+
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DatabaseDB()); err != nil {
+		return nil, err
+	}
+
+	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
+		return nil, err
+	}
+
+	batchChangeID, err := unmarshalBatchChangeID(args.BatchChange)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshaling batch change id")
+	}
+
+	if batchChangeID == 0 {
+		return nil, ErrIDIsZero{}
+	}
+
+	approvers := make([]int32, 0, len(args.Approvers))
+	for _, id := range args.Approvers {
+		userID, err := graphqlbackend.UnmarshalUserID(id)
+		if err != nil {
+			return nil, errors.Wrap(err, "unmarshaling user id")
+		}
+		approvers = append(approvers, userID)
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: SetBatchChangeApprovers checks whether current user is authorized.
+	if err := svc.SetBatchChangeApprovers(ctx, batchChangeID, approvers); err != nil {
+		return nil, errors.Wrap(err, "setting approvers")
+	}
+
+	return r.batchChangeByID(ctx, args.BatchChange)
+}
+
+func (r *Resolver) ApproveBatchSpec(ctx context.Context, args *graphqlbackend.ApproveBatchSpecArgs) (_ graphqlbackend.BatchSpecResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.ApproveBatchSpec", attribute.String("batchSpec", string(args.BatchSpec)))
+	defer tr.FinishWithErr(&err)
+
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DatabaseDB()); err != nil {
+		return nil, err
+	}
+
+	batchSpecRandID, err := unmarshalBatchSpecID(args.BatchSpec)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshaling batch spec id")
+	}
+
+	if batchSpecRandID == "" {
+		return nil, ErrIDIsZero{}
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: ApproveBatchSpec checks whether current user is an approver.
+	batchSpec, err := svc.ApproveBatchSpec(ctx, batchSpecRandID)
+	if err != nil {
+		return nil, errors.Wrap(err, "approving batch spec")
+	}
+
+	return &batchSpecResolver{store: r.store, logger: r.logger, batchSpec: batchSpec}, nil
+}
+
 func (r *Resolver) CloseBatchChange(ctx context.Context, args *graphqlbackend.CloseBatchChangeArgs) (_ graphqlbackend.BatchChangeResolver, err error) {
 	tr, ctx := trace.New(ctx, "Resolver.CloseBatchChange", attribute.String("batchChange", string(args.BatchChange)))
 	defer tr.FinishWithErr(&err)
@@ -11,9 +11,11 @@ import (
 
 	"github.com/inconshreveable/log15"
 
+	bgql "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/graphql"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/state"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	bwebhooks "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/webhooks"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
@@ -100,6 +102,15 @@ func (h webhook) upsertChangesetEvent(
 	pr PR,
 	ev keyer,
 ) (err error) {
+	// Outbound webhooks are enqueued once the transaction is committed, since
+	// their payload is read back through the GraphQL API.
+	var merged *btypes.Changeset
+	defer func() {
+		if err == nil && merged != nil {
+			bwebhooks.EnqueueChangeset(ctx, h.logger, h.Store, bwebhooks.ChangesetMerge, bgql.MarshalChangesetID(merged.ID))
+		}
+	}()
+
 	var tx *store.Store
 	if tx, err = h.Store.Transact(ctx); err != nil {
 		return err
@@ -172,11 +183,16 @@ func (h webhook) upsertChangesetEvent(
 	events, _, err := tx.ListChangesetEvents(ctx, store.ListChangesetEventsOpts{
 		ChangesetIDs: []int64{cs.ID},
 	})
+	wasMerged := cs.ExternalState == btypes.ChangesetExternalStateMerged
 	state.SetDerivedState(ctx, tx.Repos(), h.gitserverClient, cs, events)
 	if err := tx.UpdateChangesetCodeHostState(ctx, cs); err != nil {
 		return err
 	}
 
+	if !wasMerged && cs.ExternalState == btypes.ChangesetExternalStateMerged {
+		merged = cs
+	}
+
 	return nil
 }
 
@@ -132,6 +132,7 @@ func Init(
 		sentinelRootResolver,
 		rankingRootResolver,
 	))
+	graphqlbackend.RegisterNodeStateTopics("PreciseIndex", uploadgraphql.PreciseIndexStateTopics)
 	enterpriseServices.NewCodeIntelUploadHandler = newUploadHandler
 	enterpriseServices.RankingService = codeIntelServices.RankingService
 	return nil
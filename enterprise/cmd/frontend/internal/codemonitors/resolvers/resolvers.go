@@ -282,7 +282,10 @@ func (r *Resolver) createActions(ctx context.Context, monitorID int64, args []*g
 				return err
 			}
 		case a.Webhook != nil:
-			_, err := r.db.CodeMonitors().CreateWebhookAction(ctx, monitorID, a.Webhook.Enabled, a.Webhook.IncludeResults, a.Webhook.URL)
+			if err := validateWebhookPayloadTemplate(a.Webhook.PayloadTemplate); err != nil {
+				return err
+			}
+			_, err := r.db.CodeMonitors().CreateWebhookAction(ctx, monitorID, webhookActionArgs(a.Webhook))
 			if err != nil {
 				return err
 			}
@@ -394,7 +397,11 @@ func (r *Resolver) TriggerTestWebhookAction(ctx context.Context, args *graphqlba
 		return nil, err
 	}
 
-	if err := background.SendTestWebhook(ctx, httpcli.ExternalDoer, args.Description, args.Webhook.URL); err != nil {
+	if err := validateWebhookPayloadTemplate(args.Webhook.PayloadTemplate); err != nil {
+		return nil, err
+	}
+
+	if err := background.SendTestWebhook(ctx, httpcli.ExternalDoer, args.Description, args.Webhook.URL, args.Webhook.PayloadTemplate, args.Webhook.SigningSecret); err != nil {
 		return nil, err
 	}
 
@@ -620,10 +627,24 @@ func (r *Resolver) updateWebhookAction(ctx context.Context, args graphqlbackend.
 		return err
 	}
 
-	_, err = r.db.CodeMonitors().UpdateWebhookAction(ctx, id, args.Update.Enabled, args.Update.IncludeResults, args.Update.URL)
+	if err := validateWebhookPayloadTemplate(args.Update.PayloadTemplate); err != nil {
+		return err
+	}
+
+	_, err = r.db.CodeMonitors().UpdateWebhookAction(ctx, id, webhookActionArgs(args.Update))
 	return err
 }
 
+func webhookActionArgs(args *graphqlbackend.CreateActionWebhookArgs) *database.WebhookActionArgs {
+	return &database.WebhookActionArgs{
+		Enabled:         args.Enabled,
+		IncludeResults:  args.IncludeResults,
+		URL:             args.URL,
+		PayloadTemplate: args.PayloadTemplate,
+		SigningSecret:   args.SigningSecret,
+	}
+}
+
 func (r *Resolver) updateSlackWebhookAction(ctx context.Context, args graphqlbackend.EditActionSlackWebhookArgs) error {
 	var id int64
 	err := relay.UnmarshalSpec(*args.Id, &id)
@@ -723,6 +744,7 @@ const (
 	monitorActionWebhookEventKind      = "CodeMonitorActionWebhookEvent"
 	monitorActionSlackWebhookEventKind = "CodeMonitorActionSlackWebhookEvent"
 	monitorActionEmailRecipientKind    = "CodeMonitorActionEmailRecipient"
+	monitorWebhookDeadLetterKind       = "CodeMonitorWebhookDeadLetter"
 )
 
 func unmarshalMonitorID(id graphql.ID) (int64, error) {
@@ -1174,6 +1196,44 @@ func (m *monitorWebhook) URL() string {
 	return m.WebhookAction.URL
 }
 
+func (m *monitorWebhook) PayloadTemplate() *string {
+	return m.WebhookAction.PayloadTemplate
+}
+
+func (m *monitorWebhook) HasSigningSecret() bool {
+	return m.WebhookAction.SigningSecret != nil
+}
+
+func (m *monitorWebhook) DeadLetters(ctx context.Context, args *graphqlbackend.ListDeadLettersArgs) (graphqlbackend.MonitorWebhookDeadLetterConnectionResolver, error) {
+	var after *int64
+	if args.After != nil {
+		var a int64
+		if err := relay.UnmarshalSpec(graphql.ID(*args.After), &a); err != nil {
+			return nil, err
+		}
+		after = &a
+	}
+
+	ds, err := m.db.CodeMonitors().ListWebhookDeadLetters(ctx, database.ListWebhookDeadLettersOpts{
+		WebhookID: &m.WebhookAction.ID,
+		First:     pointers.Ptr(int(args.First)),
+		After:     after,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	totalCount, err := m.db.CodeMonitors().CountWebhookDeadLetters(ctx, m.WebhookAction.ID)
+	if err != nil {
+		return nil, err
+	}
+	deadLetters := make([]graphqlbackend.MonitorWebhookDeadLetterResolver, len(ds))
+	for i, d := range ds {
+		deadLetters[i] = &monitorWebhookDeadLetter{WebhookDeadLetter: d}
+	}
+	return &monitorWebhookDeadLetterConnection{deadLetters: deadLetters, totalCount: totalCount}, nil
+}
+
 func (m *monitorWebhook) Events(ctx context.Context, args *graphqlbackend.ListEventsArgs) (graphqlbackend.MonitorActionEventConnectionResolver, error) {
 	after, err := unmarshalAfter(args.After)
 	if err != nil {
@@ -1311,6 +1371,52 @@ func (a *monitorActionEventConnection) PageInfo() *graphqlutil.PageInfo {
 	return graphqlutil.NextPageCursor(string(a.events[len(a.events)-1].ID()))
 }
 
+// MonitorWebhookDeadLetterConnection
+type monitorWebhookDeadLetterConnection struct {
+	deadLetters []graphqlbackend.MonitorWebhookDeadLetterResolver
+	totalCount  int32
+}
+
+func (c *monitorWebhookDeadLetterConnection) Nodes() []graphqlbackend.MonitorWebhookDeadLetterResolver {
+	return c.deadLetters
+}
+
+func (c *monitorWebhookDeadLetterConnection) TotalCount() int32 {
+	return c.totalCount
+}
+
+func (c *monitorWebhookDeadLetterConnection) PageInfo() *graphqlutil.PageInfo {
+	if len(c.deadLetters) == 0 {
+		return graphqlutil.HasNextPage(false)
+	}
+	return graphqlutil.NextPageCursor(string(c.deadLetters[len(c.deadLetters)-1].ID()))
+}
+
+// MonitorWebhookDeadLetter
+type monitorWebhookDeadLetter struct {
+	*database.WebhookDeadLetter
+}
+
+func (d *monitorWebhookDeadLetter) ID() graphql.ID {
+	return relay.MarshalID(monitorWebhookDeadLetterKind, d.WebhookDeadLetter.ID)
+}
+
+func (d *monitorWebhookDeadLetter) Payload() string {
+	return d.WebhookDeadLetter.Payload
+}
+
+func (d *monitorWebhookDeadLetter) FailureMessage() string {
+	return d.WebhookDeadLetter.FailureMessage
+}
+
+func (d *monitorWebhookDeadLetter) Attempts() int32 {
+	return d.NumAttempts
+}
+
+func (d *monitorWebhookDeadLetter) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: d.WebhookDeadLetter.CreatedAt}
+}
+
 // MonitorEvent
 type monitorActionEvent struct {
 	*Resolver
@@ -1340,6 +1446,13 @@ func (m *monitorActionEvent) Timestamp() gqlutil.DateTime {
 	return gqlutil.DateTime{Time: *m.FinishedAt}
 }
 
+func validateWebhookPayloadTemplate(tmpl *string) error {
+	if tmpl == nil {
+		return nil
+	}
+	return background.ValidateWebhookPayloadTemplate(*tmpl)
+}
+
 func validateSlackURL(urlString string) error {
 	u, err := url.Parse(urlString)
 	if err != nil {
@@ -24,6 +24,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/settings"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -86,6 +87,21 @@ func TestCreateCodeMonitor(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("invalid webhook payload template", func(t *testing.T) {
+		namespace := relay.MarshalID("User", user.ID)
+		_, err := r.CreateCodeMonitor(ctx, &graphqlbackend.CreateCodeMonitorArgs{
+			Monitor: &graphqlbackend.CreateMonitorArgs{Namespace: namespace},
+			Trigger: &graphqlbackend.CreateTriggerArgs{Query: "repo:."},
+			Actions: []*graphqlbackend.CreateActionArgs{{
+				Webhook: &graphqlbackend.CreateActionWebhookArgs{
+					URL:             "https://generic.webhook.com",
+					PayloadTemplate: pointers.Ptr("{{ .MonitorDescription"),
+				},
+			}},
+		})
+		require.Error(t, err)
+	})
+
 	t.Run("invalid query", func(t *testing.T) {
 		namespace := relay.MarshalID("User", user.ID)
 		_, err := r.CreateCodeMonitor(ctx, &graphqlbackend.CreateCodeMonitorArgs{
@@ -7,9 +7,12 @@ import (
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/completions/resolvers"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel"
 	"github.com/sourcegraph/sourcegraph/internal/cody"
+	"github.com/sourcegraph/sourcegraph/internal/completions/guardrails"
 	"github.com/sourcegraph/sourcegraph/internal/completions/httpapi"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -26,19 +29,51 @@ func Init(
 ) error {
 	logger := log.Scoped("completions", "Cody completions")
 
+	// The guardrails resolver is only set once all initializers ran, so it is looked up
+	// on every request.
+	guard := guardrails.NewGuardrails(logger, db, snippetAttributionSearcher{enterpriseServices: enterpriseServices})
+
 	enterpriseServices.NewChatCompletionsStreamHandler = func() http.Handler {
-		completionsHandler := httpapi.NewChatCompletionsStreamHandler(logger, db)
+		completionsHandler := httpapi.NewChatCompletionsStreamHandler(logger, db, guard)
 		return requireVerifiedEmailMiddleware(db, observationCtx.Logger, completionsHandler)
 	}
 	enterpriseServices.NewCodeCompletionsHandler = func() http.Handler {
-		codeCompletionsHandler := httpapi.NewCodeCompletionsHandler(logger, db)
+		codeCompletionsHandler := httpapi.NewCodeCompletionsHandler(logger, db, guard)
 		return requireVerifiedEmailMiddleware(db, observationCtx.Logger, codeCompletionsHandler)
 	}
-	enterpriseServices.CompletionsResolver = resolvers.NewCompletionsResolver(db, observationCtx.Logger)
+	enterpriseServices.CompletionsResolver = resolvers.NewCompletionsResolver(db, observationCtx.Logger, guard)
 
 	return nil
 }
 
+// snippetAttributionSearcher searches for the snippets of completions with the
+// snippet attribution of the guardrails resolver.
+type snippetAttributionSearcher struct {
+	enterpriseServices *enterprise.Services
+}
+
+func (s snippetAttributionSearcher) SnippetAttribution(ctx context.Context, snippet string, limit int) ([]string, error) {
+	resolver := s.enterpriseServices.GuardrailsResolver
+	if resolver == nil {
+		return nil, nil
+	}
+
+	first := int32(limit)
+	connection, err := resolver.SnippetAttribution(ctx, &graphqlbackend.SnippetAttributionArgs{
+		ConnectionArgs: graphqlutil.ConnectionArgs{First: &first},
+		Snippet:        snippet,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, node := range connection.Nodes() {
+		names = append(names, node.RepositoryName())
+	}
+	return names, nil
+}
+
 func requireVerifiedEmailMiddleware(db database.DB, logger log.Logger, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if err := cody.CheckVerifiedEmailRequirement(r.Context(), db, logger); err != nil {
@@ -0,0 +1,163 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func (c *completionsResolver) CodyPromptLogs(ctx context.Context, args *graphqlbackend.CodyPromptLogsArgs) (graphqlbackend.CodyPromptLogConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins may read the recorded prompts and responses.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, c.db); err != nil {
+		return nil, err
+	}
+
+	opts := database.CodyPromptLogListOpts{
+		Limit: 50,
+		Since: args.Since,
+		Until: args.Until,
+	}
+	if args.First != nil {
+		opts.Limit = int(*args.First)
+	}
+	if args.After != nil {
+		var err error
+		opts.Cursor, err = strconv.ParseInt(*args.After, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing the after cursor")
+		}
+	}
+	if args.User != nil {
+		userID, err := graphqlbackend.UnmarshalUserID(*args.User)
+		if err != nil {
+			return nil, err
+		}
+		opts.UserID = &userID
+	}
+
+	return &codyPromptLogConnectionResolver{
+		db:    c.db,
+		store: c.db.CodyPromptLogs(keyring.Default().CodyPromptLogKey),
+		opts:  opts,
+	}, nil
+}
+
+type codyPromptLogConnectionResolver struct {
+	db    database.DB
+	store database.CodyPromptLogStore
+	opts  database.CodyPromptLogListOpts
+
+	once sync.Once
+	logs []*types.CodyPromptLog
+	next int64
+	err  error
+}
+
+func (r *codyPromptLogConnectionResolver) compute(ctx context.Context) ([]*types.CodyPromptLog, int64, error) {
+	r.once.Do(func() {
+		r.logs, r.next, r.err = r.store.List(ctx, r.opts)
+	})
+	return r.logs, r.next, r.err
+}
+
+func (r *codyPromptLogConnectionResolver) Nodes(ctx context.Context) ([]graphqlbackend.CodyPromptLogResolver, error) {
+	logs, _, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]graphqlbackend.CodyPromptLogResolver, len(logs))
+	for i, l := range logs {
+		nodes[i] = &codyPromptLogResolver{db: r.db, log: l}
+	}
+	return nodes, nil
+}
+
+func (r *codyPromptLogConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	_, next, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if next == 0 {
+		return graphqlutil.HasNextPage(false), nil
+	}
+	return graphqlutil.NextPageCursor(fmt.Sprint(next)), nil
+}
+
+type codyPromptLogResolver struct {
+	db  database.DB
+	log *types.CodyPromptLog
+}
+
+func (r *codyPromptLogResolver) ID() graphql.ID {
+	return relay.MarshalID("CodyPromptLog", r.log.ID)
+}
+
+func (r *codyPromptLogResolver) User(ctx context.Context) (*graphqlbackend.UserResolver, error) {
+	if r.log.UserID == 0 {
+		return nil, nil
+	}
+
+	user, err := graphqlbackend.UserByIDInt32(ctx, r.db, r.log.UserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *codyPromptLogResolver) AnonymousUserID() *string {
+	if r.log.AnonymousUserID == "" {
+		return nil
+	}
+	return &r.log.AnonymousUserID
+}
+
+func (r *codyPromptLogResolver) Intent() string { return r.log.Intent }
+
+func (r *codyPromptLogResolver) Provider() string { return r.log.Provider }
+
+func (r *codyPromptLogResolver) Model() string { return r.log.Model }
+
+func (r *codyPromptLogResolver) Prompt(ctx context.Context) ([]graphqlbackend.CodyPromptMessageResolver, error) {
+	messages, err := r.log.Prompt.Decrypt(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.CodyPromptMessageResolver, len(messages))
+	for i, m := range messages {
+		resolvers[i] = &codyPromptMessageResolver{message: m}
+	}
+	return resolvers, nil
+}
+
+func (r *codyPromptLogResolver) Response(ctx context.Context) (string, error) {
+	return r.log.Response.Decrypt(ctx)
+}
+
+func (r *codyPromptLogResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.log.CreatedAt}
+}
+
+type codyPromptMessageResolver struct {
+	message types.CodyPromptMessage
+}
+
+func (r *codyPromptMessageResolver) Speaker() string { return r.message.Speaker }
+
+func (r *codyPromptMessageResolver) Text() string { return r.message.Text }
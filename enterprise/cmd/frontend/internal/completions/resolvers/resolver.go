@@ -9,7 +9,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/internal/cody"
 	"github.com/sourcegraph/sourcegraph/internal/completions/client"
+	"github.com/sourcegraph/sourcegraph/internal/completions/guardrails"
 	"github.com/sourcegraph/sourcegraph/internal/completions/httpapi"
+	"github.com/sourcegraph/sourcegraph/internal/completions/promptlog"
 	"github.com/sourcegraph/sourcegraph/internal/completions/types"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -22,13 +24,16 @@ var _ graphqlbackend.CompletionsResolver = &completionsResolver{}
 // completionsResolver provides chat completions
 type completionsResolver struct {
 	rl     httpapi.RateLimiter
+	quota  httpapi.TokenQuota
+	guard  *guardrails.Guardrails
 	db     database.DB
 	logger log.Logger
 }
 
-func NewCompletionsResolver(db database.DB, logger log.Logger) graphqlbackend.CompletionsResolver {
+func NewCompletionsResolver(db database.DB, logger log.Logger, guard *guardrails.Guardrails) graphqlbackend.CompletionsResolver {
 	rl := httpapi.NewRateLimiter(db, redispool.Store, types.CompletionsFeatureChat)
-	return &completionsResolver{rl: rl, db: db, logger: logger}
+	quota := httpapi.NewTokenQuota(redispool.Store)
+	return &completionsResolver{rl: rl, quota: quota, guard: guard, db: db, logger: logger}
 }
 
 func (c *completionsResolver) Completions(ctx context.Context, args graphqlbackend.CompletionsArgs) (_ string, err error) {
@@ -52,15 +57,17 @@ func (c *completionsResolver) Completions(ctx context.Context, args graphqlbacke
 		chatModel = completionsConfig.ChatModel
 	}
 
-	ctx, done := httpapi.Trace(ctx, "resolver", chatModel).
+	route := httpapi.RouteCompletions(completionsConfig, types.CompletionIntentChat, chatModel)
+
+	ctx, done := httpapi.Trace(ctx, "resolver", route.Model).
 		WithErrorP(&err).
 		Build()
 	defer done()
 
 	client, err := client.Get(
-		completionsConfig.Endpoint,
-		completionsConfig.Provider,
-		completionsConfig.AccessToken,
+		route.Endpoint,
+		route.Provider,
+		route.AccessToken,
 	)
 	if err != nil {
 		return "", errors.Wrap(err, "GetCompletionStreamClient")
@@ -71,9 +78,23 @@ func (c *completionsResolver) Completions(ctx context.Context, args graphqlbacke
 		return "", err
 	}
 
+	// Check token quota.
+	if err := c.quota.Check(ctx); err != nil {
+		return "", err
+	}
+
 	params := convertParams(args)
 	// No way to configure the model through the request, we hard code to chat.
-	params.Model = chatModel
+	params.Model = route.Model
+
+	// Enforce guardrails on the prompt.
+	if err := c.guard.CheckPrompt(ctx, types.CompletionIntentChat, &params); err != nil {
+		return "", err
+	}
+
+	client = httpapi.NewUsageTrackingClient(c.logger, c.db, c.quota, types.CompletionIntentChat, route.Provider, client)
+	client = promptlog.NewLoggingClient(c.logger, c.db, types.CompletionIntentChat, route.Provider, client)
+	client = c.guard.Client(types.CompletionIntentChat, client)
 	resp, err := client.Complete(ctx, types.CompletionsFeatureChat, params)
 	if err != nil {
 		return "", errors.Wrap(err, "client.Complete")
@@ -28,11 +28,20 @@ func Init(
 		db,
 		enterpriseServices.EnterpriseSearchJobs,
 	)
+	codeGraphClient, err := codycontext.NewCodenavCodeGraphClient(
+		db,
+		services.CodenavService,
+		services.GitserverClient,
+	)
+	if err != nil {
+		return err
+	}
 	contextClient := codycontext.NewCodyContextClient(
 		observationCtx,
 		db,
 		embeddingsClient,
 		searchClient,
+		codeGraphClient,
 	)
 	enterpriseServices.CodyContextResolver = resolvers.NewResolver(
 		db,
@@ -69,8 +69,8 @@ func TestContextResolver(t *testing.T) {
 	mockEmbeddingsClient := embeddings.NewMockClient()
 	mockEmbeddingsClient.SearchFunc.SetDefaultHook(func(_ context.Context, params embeddings.EmbeddingsSearchParameters) (*embeddings.EmbeddingCombinedSearchResults, error) {
 		require.Equal(t, params.RepoNames, []api.RepoName{"repo1"})
-		require.Equal(t, params.TextResultsCount, 1)
-		require.Equal(t, params.CodeResultsCount, 1)
+		require.Equal(t, params.TextResultsCount, 2)
+		require.Equal(t, params.CodeResultsCount, 2)
 		return &embeddings.EmbeddingCombinedSearchResults{
 			CodeResults: embeddings.EmbeddingSearchResults{{
 				FileName: "testcode1.go",
@@ -130,6 +130,7 @@ func TestContextResolver(t *testing.T) {
 		db,
 		mockEmbeddingsClient,
 		mockSearchClient,
+		nil,
 	)
 
 	resolver := NewResolver(
@@ -161,8 +162,8 @@ func TestContextResolver(t *testing.T) {
 	for i, result := range results {
 		paths[i] = result.(*graphqlbackend.FileChunkContextResolver).Blob().Path()
 	}
-	// One code result and text result from each repo
-	expected := []string{"testcode1.go", "testtext1.md", "testcode2.go", "testtext2.md"}
+	// The best ranked code and text results of embeddings and keyword search
+	expected := []string{"testcode1.go", "testcode2.go", "testtext1.md", "testtext2.md"}
 	require.Equal(t, expected, paths)
 }
 
@@ -7,18 +7,15 @@ import (
 	"time"
 
 	"github.com/derision-test/glock"
-	"github.com/gomodule/redigo/redis"
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/licensing"
-	"github.com/sourcegraph/sourcegraph/internal/redispool"
 	"github.com/sourcegraph/sourcegraph/internal/slack"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/cron"
 )
 
-const lastLicenseExpirationCheckKey = "last_license_expiration_check"
-
 var licenseExpirationCheckers uint32
 
 // StartCheckForUpcomingLicenseExpirations checks for upcoming license expirations once per day.
@@ -33,40 +30,29 @@ func StartCheckForUpcomingLicenseExpirations(logger log.Logger, db database.DB)
 	}
 	client := slack.New(dotcom.SlackLicenseExpirationWebhook)
 
-	t := time.NewTicker(1 * time.Hour)
 	logger = logger.Scoped("StartCheckForUpcomingLicenseExpirations", "starts the various checks for upcoming license expiry")
-	for range t.C {
-		checkLicensesIfNeeded(logger, db, client)
+	scheduler, err := cron.NewScheduler(context.Background(), db, cron.Job{
+		Name:        "dotcom.license-expiration-check",
+		Description: "notifies about licenses that expire within a week or a day",
+		Schedule:    "@daily",
+		// A check that was missed while the frontend was down still needs to run.
+		CatchUp: cron.CatchUpOnce,
+		Handler: func(context.Context, time.Time) error {
+			checkForUpcomingLicenseExpirations(logger, db, glock.NewRealClock(), client)
+			return nil
+		},
+	})
+	if err != nil {
+		logger.Error("error scheduling license expiration check", log.Error(err))
+		return
 	}
+	scheduler.Start()
 }
 
 type slackClient interface {
 	Post(ctx context.Context, payload *slack.Payload) error
 }
 
-// checkLicensesIfNeeded checks whether a day has passed since the last license check, and if so, initiates one.
-func checkLicensesIfNeeded(logger log.Logger, db database.DB, client slackClient) {
-	store := redispool.Store
-	today := time.Now().UTC().Format("2006-01-02")
-
-	lastCheckDate, err := store.GetSet(lastLicenseExpirationCheckKey, today).String()
-	if err == redis.ErrNil {
-		// If the redis key hasn't been set yet, do so and leave lastCheckDate as nil
-		setErr := store.Set(lastLicenseExpirationCheckKey, today)
-		if setErr != nil {
-			logger.Error("error SET last license expiration check date", log.Error(err))
-			return
-		}
-	} else if err != nil {
-		logger.Error("error GETSET last license expiration check date", log.Error(err))
-		return
-	}
-
-	if today != lastCheckDate {
-		checkForUpcomingLicenseExpirations(logger, db, glock.NewRealClock(), client)
-	}
-}
-
 func checkForUpcomingLicenseExpirations(logger log.Logger, db database.DB, clock glock.Clock, client slackClient) {
 	if conf.Get().Dotcom == nil || conf.Get().Dotcom.SlackLicenseExpirationWebhook == "" {
 		return
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/embeddings/resolvers"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
@@ -32,6 +33,7 @@ func Init(
 		embeddingsClient,
 		repoEmbeddingsStore,
 	)
+	graphqlbackend.RegisterNodeStateTopics("RepoEmbeddingJob", resolvers.RepoEmbeddingJobStateTopics)
 
 	return nil
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/redispubsub"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -229,6 +230,16 @@ func unmarshalRepoEmbeddingJobID(id graphql.ID) (jobID int, err error) {
 	return
 }
 
+// RepoEmbeddingJobStateTopics returns the redispubsub topics on which changes to
+// the state and progress of the given repository embedding job are published.
+func RepoEmbeddingJobStateTopics(_ context.Context, id graphql.ID) ([]string, error) {
+	jobID, err := unmarshalRepoEmbeddingJobID(id)
+	if err != nil {
+		return nil, err
+	}
+	return []string{redispubsub.RecordTopic("repo_embedding_jobs", jobID)}, nil
+}
+
 type repoEmbeddingJobStatsResolver struct {
 	stats repobg.EmbedRepoStats
 }
@@ -86,14 +86,34 @@ func (r *Resolver) EmbeddingsMultiSearch(ctx context.Context, args graphqlbacken
 		return nil, err
 	}
 
-	repoNames := make([]api.RepoName, len(repos))
-	for i, repo := range repos {
-		repoNames[i] = repo.Name
+	// 🚨 SECURITY: The content of repos denied by the context filters must never be
+	// sent to the LLM, so they are not searched.
+	filters, err := cody.GetContextFilters(ctx, r.db, r.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		allowedNames []api.RepoName
+		allowedIDs   []api.RepoID
+	)
+	for _, repo := range repos {
+		if filters.Allowed(ctx, "embeddings", repo.Name) {
+			allowedNames = append(allowedNames, repo.Name)
+			allowedIDs = append(allowedIDs, repo.ID)
+		}
+	}
+	if len(allowedNames) == 0 {
+		return &embeddingsSearchResultsResolver{
+			results:   &embeddings.EmbeddingCombinedSearchResults{},
+			gitserver: r.gitserverClient,
+			logger:    r.logger,
+		}, nil
 	}
 
 	results, err := r.embeddingsClient.Search(ctx, embeddings.EmbeddingsSearchParameters{
-		RepoNames:        repoNames,
-		RepoIDs:          repoIDs,
+		RepoNames:        allowedNames,
+		RepoIDs:          allowedIDs,
 		Query:            args.Query,
 		CodeResultsCount: int(args.CodeResultsCount),
 		TextResultsCount: int(args.TextResultsCount),
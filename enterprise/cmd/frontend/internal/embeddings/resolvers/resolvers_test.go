@@ -40,6 +40,9 @@ func TestEmbeddingSearchResolver(t *testing.T) {
 	mockRepos := database.NewMockRepoStore()
 	mockRepos.GetByIDsFunc.SetDefaultReturn([]*types.Repo{{ID: 1, Name: "repo1"}}, nil)
 	mockDB.ReposFunc.SetDefaultReturn(mockRepos)
+	mockDB.OrgsFunc.SetDefaultReturn(database.NewMockOrgStore())
+	mockDB.SettingsFunc.SetDefaultReturn(database.NewMockSettingsStore())
+	mockDB.SecurityEventLogsFunc.SetDefaultReturn(database.NewMockSecurityEventLogsStore())
 
 	mockGitserver := gitserver.NewMockClient()
 	mockGitserver.ReadFileFunc.SetDefaultHook(func(_ context.Context, _ authz.SubRepoPermissionChecker, _ api.RepoName, _ api.CommitID, fileName string) ([]byte, error) {
@@ -95,6 +98,30 @@ func TestEmbeddingSearchResolver(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, codeResults, 1)
 	require.Equal(t, "test\nfirst\nfour\nlines", codeResults[0].Content(ctx))
+
+	t.Run("context filters", func(t *testing.T) {
+		conf.Mock(&conf.Unified{
+			SiteConfiguration: schema.SiteConfiguration{
+				CodyEnabled:        pointers.Ptr(true),
+				LicenseKey:         "asdf",
+				CodyContextFilters: &schema.CodyContextFilters{Exclude: []string{"^repo1$"}},
+			},
+		})
+		t.Cleanup(func() { conf.Mock(nil) })
+
+		results, err := resolver.EmbeddingsMultiSearch(ctx, graphqlbackend.EmbeddingsMultiSearchInputArgs{
+			Repos:            []graphql.ID{graphqlbackend.MarshalRepositoryID(3)},
+			Query:            "test",
+			CodeResultsCount: 1,
+			TextResultsCount: 1,
+		})
+		require.NoError(t, err)
+
+		codeResults, err := results.CodeResults(ctx)
+		require.NoError(t, err)
+		require.Empty(t, codeResults)
+		require.Len(t, mockEmbeddingsClient.SearchFunc.History(), 1)
+	})
 }
 
 func Test_extractLineRange(t *testing.T) {
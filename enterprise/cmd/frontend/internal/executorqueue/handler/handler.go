@@ -8,8 +8,10 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/keegancsmith/sqlf"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 	"github.com/sourcegraph/log"
@@ -65,6 +67,16 @@ type QueueHandler[T workerutil.Record] struct {
 	// RecordTransformer is a required hook for each registered queue that transforms a generic
 	// record from that queue into the job to be given to an executor.
 	RecordTransformer TransformerFunc[T]
+	// TenantQuotaCondition returns the condition that restricts a dequeue to the records of
+	// tenants with less than limit records processing. It is nil for queues that do not
+	// support tenant quotas.
+	TenantQuotaCondition func(limit int) *sqlf.Query
+	// QueuedAt returns when the record was queued, to measure how long jobs wait for an
+	// executor. It is optional.
+	QueuedAt func(record T) time.Time
+	// RevokeSecretLeases revokes the leases of the secrets resolved from external secret
+	// managers for the job once it finished. It is optional.
+	RevokeSecretLeases func(ctx context.Context, jobID int) error
 }
 
 // TransformerFunc is the function to transform a workerutil.Record into an executor.Job.
@@ -267,6 +279,8 @@ func (h *handler[T]) markComplete(ctx context.Context, queueName string, executo
 		return errors.Wrap(err, "jobTokenStore.Delete")
 	}
 
+	h.revokeSecretLeases(ctx, jobID)
+
 	return nil
 }
 
@@ -301,6 +315,8 @@ func (h *handler[T]) markErrored(ctx context.Context, queueName string, executor
 		return errors.Wrap(err, "jobTokenStore.Delete")
 	}
 
+	h.revokeSecretLeases(ctx, jobID)
+
 	return nil
 }
 
@@ -338,9 +354,22 @@ func (h *handler[T]) markFailed(ctx context.Context, queueName string, executorN
 		return errors.Wrap(err, "jobTokenStore.Delete")
 	}
 
+	h.revokeSecretLeases(ctx, jobID)
+
 	return nil
 }
 
+// revokeSecretLeases revokes the secret leases of the finished job. Failures are only logged, as
+// the job already finished and the leases are revoked again by the executors janitor.
+func (h *handler[T]) revokeSecretLeases(ctx context.Context, jobID int) {
+	if h.queueHandler.RevokeSecretLeases == nil {
+		return
+	}
+	if err := h.queueHandler.RevokeSecretLeases(ctx, jobID); err != nil {
+		h.logger.Warn("failed to revoke secret leases", log.String("queue", h.queueHandler.Name), log.Int("jobID", jobID), log.Error(err))
+	}
+}
+
 func (h *handler[T]) HandleHeartbeat(w http.ResponseWriter, r *http.Request) {
 	var payload executortypes.HeartbeatRequest
 
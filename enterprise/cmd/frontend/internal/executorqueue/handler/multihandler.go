@@ -7,6 +7,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/keegancsmith/sqlf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/sourcegraph/log"
 	"golang.org/x/exp/slices"
 
@@ -28,6 +31,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
+var queueWaitTime = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "src_executor_queue_wait_seconds",
+	Help:    "Time jobs waited in their queue before being dequeued by a multiqueue executor.",
+	Buckets: []float64{1, 5, 15, 30, 60, 300, 900, 1800, 3600, 10800, 21600, 86400},
+}, []string{"queue", "priority"})
+
 // MultiHandler handles the HTTP requests of an executor for more than one queue. See ExecutorHandler for single-queue implementation.
 type MultiHandler struct {
 	executorStore         database.ExecutorStore
@@ -37,6 +46,8 @@ type MultiHandler struct {
 	BatchesQueueHandler   QueueHandler[*btypes.BatchSpecWorkspaceExecutionJob]
 	DequeueCache          *rcache.Cache
 	dequeueCacheConfig    *schema.DequeueCacheConfig
+	priorities            map[string]string
+	quotas                map[string]schema.ExecutorQueueQuota
 	logger                log.Logger
 }
 
@@ -51,8 +62,14 @@ func NewMultiHandler(
 	siteConfig := conf.Get().SiteConfiguration
 	dequeueCache := rcache.New(executortypes.DequeueCachePrefix)
 	dequeueCacheConfig := executortypes.DequeuePropertiesPerQueue
-	if siteConfig.ExecutorsMultiqueue != nil && siteConfig.ExecutorsMultiqueue.DequeueCacheConfig != nil {
-		dequeueCacheConfig = siteConfig.ExecutorsMultiqueue.DequeueCacheConfig
+	var priorities map[string]string
+	var quotas map[string]schema.ExecutorQueueQuota
+	if siteConfig.ExecutorsMultiqueue != nil {
+		if siteConfig.ExecutorsMultiqueue.DequeueCacheConfig != nil {
+			dequeueCacheConfig = siteConfig.ExecutorsMultiqueue.DequeueCacheConfig
+		}
+		priorities = siteConfig.ExecutorsMultiqueue.Priorities
+		quotas = siteConfig.ExecutorsMultiqueue.Quotas
 	}
 	multiHandler := MultiHandler{
 		executorStore:         executorStore,
@@ -62,6 +79,8 @@ func NewMultiHandler(
 		BatchesQueueHandler:   batchesQueueHandler,
 		DequeueCache:          dequeueCache,
 		dequeueCacheConfig:    dequeueCacheConfig,
+		priorities:            priorities,
+		quotas:                quotas,
 		logger:                log.Scoped("executor-multi-queue-handler", "The route handler for all executor queues"),
 	}
 	return multiHandler
@@ -106,35 +125,14 @@ func (m *MultiHandler) dequeue(ctx context.Context, req executortypes.DequeueReq
 		return executortypes.Job{}, false, errors.New(message)
 	}
 
-	// discard empty queues
+	// discard empty queues and queues at their quota
 	nonEmptyQueues, err := m.SelectNonEmptyQueues(ctx, req.Queues)
 	if err != nil {
 		return executortypes.Job{}, false, err
 	}
-
-	var selectedQueue string
-	if len(nonEmptyQueues) == 0 {
-		// all queues are empty, dequeue nothing
-		return executortypes.Job{}, false, nil
-	} else if len(nonEmptyQueues) == 1 {
-		// only one queue contains items, select as candidate
-		selectedQueue = nonEmptyQueues[0]
-	} else {
-		// multiple populated queues, discard queues at dequeue limit
-		candidateQueues, err := m.SelectEligibleQueues(nonEmptyQueues)
-		if err != nil {
-			return executortypes.Job{}, false, err
-		}
-		if len(candidateQueues) == 1 {
-			// only one queue hasn't reached dequeue limit for this window, select as candidate
-			selectedQueue = candidateQueues[0]
-		} else {
-			// final list of candidates: multiple not at limit or all at limit.
-			selectedQueue, err = m.SelectQueueForDequeueing(candidateQueues)
-			if err != nil {
-				return executortypes.Job{}, false, err
-			}
-		}
+	candidateQueues, err := m.SelectQueuesWithinQuota(ctx, nonEmptyQueues)
+	if err != nil {
+		return executortypes.Job{}, false, err
 	}
 
 	resourceMetadata := ResourceMetadata{
@@ -143,50 +141,31 @@ func (m *MultiHandler) dequeue(ctx context.Context, req executortypes.DequeueReq
 		DiskSpace: req.DiskSpace,
 	}
 
-	logger := m.logger.Scoped("dequeue", "Pick a job record from the database.")
 	var job executortypes.Job
-	switch selectedQueue {
-	case m.BatchesQueueHandler.Name:
-		record, dequeued, err := m.BatchesQueueHandler.Store.Dequeue(ctx, req.ExecutorName, nil)
+	var selectedQueue string
+	dequeued := false
+	// try each candidate queue at most once
+	for attempts := len(candidateQueues); !dequeued && attempts > 0; attempts-- {
+		selectedQueue, err = m.selectQueue(candidateQueues)
 		if err != nil {
-			err = errors.Wrapf(err, "dbworkerstore.Dequeue %s", selectedQueue)
-			logger.Error("Failed to dequeue", log.String("queue", selectedQueue), log.Error(err))
 			return executortypes.Job{}, false, err
 		}
-		if !dequeued {
-			// no batches job to dequeue. Even though the queue was populated before, another executor
-			// instance could have dequeued in the meantime
-			return executortypes.Job{}, false, nil
-		}
 
-		job, err = m.BatchesQueueHandler.RecordTransformer(ctx, req.Version, record, resourceMetadata)
-		if err != nil {
-			markErr := markRecordAsFailed(ctx, m.BatchesQueueHandler.Store, record.RecordID(), err, logger)
-			err = errors.Wrapf(errors.Append(err, markErr), "RecordTransformer %s", selectedQueue)
-			logger.Error("Failed to transform record", log.String("queue", selectedQueue), log.Error(err))
-			return executortypes.Job{}, false, err
-		}
-	case m.CodeIntelQueueHandler.Name:
-		record, dequeued, err := m.CodeIntelQueueHandler.Store.Dequeue(ctx, req.ExecutorName, nil)
+		job, dequeued, err = m.dequeueFromQueue(ctx, selectedQueue, req, resourceMetadata)
 		if err != nil {
-			err = errors.Wrapf(err, "dbworkerstore.Dequeue %s", selectedQueue)
-			logger.Error("Failed to dequeue", log.String("queue", selectedQueue), log.Error(err))
 			return executortypes.Job{}, false, err
 		}
 		if !dequeued {
-			// no codeintel job to dequeue. Even though the queue was populated before, another executor
-			// instance could have dequeued in the meantime
-			return executortypes.Job{}, false, nil
-		}
-
-		job, err = m.CodeIntelQueueHandler.RecordTransformer(ctx, req.Version, record, resourceMetadata)
-		if err != nil {
-			markErr := markRecordAsFailed(ctx, m.CodeIntelQueueHandler.Store, record.RecordID(), err, logger)
-			err = errors.Wrapf(errors.Append(err, markErr), "RecordTransformer %s", selectedQueue)
-			logger.Error("Failed to transform record", log.String("queue", selectedQueue), log.Error(err))
-			return executortypes.Job{}, false, err
+			// The queue has no job to dequeue. Another executor instance could have dequeued in
+			// the meantime, or all queued jobs belong to tenants at their quota. Fall back to the
+			// remaining queues, so that they are not starved by the selected one.
+			candidateQueues = withoutQueue(candidateQueues, selectedQueue)
 		}
 	}
+	if !dequeued {
+		// all queues are empty or at their quota, dequeue nothing
+		return executortypes.Job{}, false, nil
+	}
 	job.Queue = selectedQueue
 
 	// If this executor supports v2, return a v2 payload. Based on this field,
@@ -195,7 +174,7 @@ func (m *MultiHandler) dequeue(ctx context.Context, req executortypes.DequeueReq
 		job.Version = 2
 	}
 
-	logger = m.logger.Scoped("token", "Create or regenerate a job token.")
+	logger := m.logger.Scoped("token", "Create or regenerate a job token.")
 	token, err := m.jobTokenStore.Create(ctx, job.ID, job.Queue, job.RepositoryName)
 	if err != nil {
 		if errors.Is(err, executorstore.ErrJobTokenAlreadyCreated) {
@@ -223,6 +202,160 @@ func (m *MultiHandler) dequeue(ctx context.Context, req executortypes.DequeueReq
 	return job, true, nil
 }
 
+// selectQueue picks the queue to dequeue from out of the non-empty candidate queues: queues of the
+// interactive priority class preempt bulk queues, and queues of the same class are picked by weight
+// unless they reached their dequeue limit.
+func (m *MultiHandler) selectQueue(candidateQueues []string) (string, error) {
+	candidateQueues = m.SelectHighestPriorityQueues(candidateQueues)
+	if len(candidateQueues) == 1 {
+		return candidateQueues[0], nil
+	}
+
+	// multiple populated queues, discard queues at dequeue limit
+	eligibleQueues, err := m.SelectEligibleQueues(candidateQueues)
+	if err != nil {
+		return "", err
+	}
+	if len(eligibleQueues) == 1 {
+		// only one queue hasn't reached dequeue limit for this window, select as candidate
+		return eligibleQueues[0], nil
+	}
+	// final list of candidates: multiple not at limit or all at limit.
+	return m.SelectQueueForDequeueing(eligibleQueues)
+}
+
+// dequeueFromQueue dequeues a record from the given queue and transforms it into a job.
+func (m *MultiHandler) dequeueFromQueue(ctx context.Context, queue string, req executortypes.DequeueRequest, resourceMetadata ResourceMetadata) (executortypes.Job, bool, error) {
+	priority := m.QueuePriority(queue)
+	switch queue {
+	case m.BatchesQueueHandler.Name:
+		return dequeueRecord(ctx, m.logger, m.BatchesQueueHandler, req, resourceMetadata, priority, m.tenantQuotaConditions(m.BatchesQueueHandler.Name, m.BatchesQueueHandler.TenantQuotaCondition))
+	case m.CodeIntelQueueHandler.Name:
+		return dequeueRecord(ctx, m.logger, m.CodeIntelQueueHandler, req, resourceMetadata, priority, m.tenantQuotaConditions(m.CodeIntelQueueHandler.Name, m.CodeIntelQueueHandler.TenantQuotaCondition))
+	}
+	return executortypes.Job{}, false, nil
+}
+
+func dequeueRecord[T workerutil.Record](
+	ctx context.Context,
+	logger log.Logger,
+	queueHandler QueueHandler[T],
+	req executortypes.DequeueRequest,
+	resourceMetadata ResourceMetadata,
+	priority string,
+	conditions []*sqlf.Query,
+) (executortypes.Job, bool, error) {
+	logger = logger.Scoped("dequeue", "Pick a job record from the database.")
+	record, dequeued, err := queueHandler.Store.Dequeue(ctx, req.ExecutorName, conditions)
+	if err != nil {
+		err = errors.Wrapf(err, "dbworkerstore.Dequeue %s", queueHandler.Name)
+		logger.Error("Failed to dequeue", log.String("queue", queueHandler.Name), log.Error(err))
+		return executortypes.Job{}, false, err
+	}
+	if !dequeued {
+		return executortypes.Job{}, false, nil
+	}
+
+	if queueHandler.QueuedAt != nil {
+		queueWaitTime.WithLabelValues(queueHandler.Name, priority).Observe(time.Since(queueHandler.QueuedAt(record)).Seconds())
+	}
+
+	job, err := queueHandler.RecordTransformer(ctx, req.Version, record, resourceMetadata)
+	if err != nil {
+		markErr := markRecordAsFailed(ctx, queueHandler.Store, record.RecordID(), err, logger)
+		err = errors.Wrapf(errors.Append(err, markErr), "RecordTransformer %s", queueHandler.Name)
+		logger.Error("Failed to transform record", log.String("queue", queueHandler.Name), log.Error(err))
+		return executortypes.Job{}, false, err
+	}
+	return job, true, nil
+}
+
+// tenantQuotaConditions returns the dequeue conditions enforcing the per-tenant quota of the queue.
+func (m *MultiHandler) tenantQuotaConditions(queue string, condition func(limit int) *sqlf.Query) []*sqlf.Query {
+	quota, ok := m.quotas[queue]
+	if !ok || quota.MaxProcessingPerTenant <= 0 || condition == nil {
+		return nil
+	}
+	return []*sqlf.Query{condition(quota.MaxProcessingPerTenant)}
+}
+
+// QueuePriority returns the priority class of the queue.
+func (m *MultiHandler) QueuePriority(queue string) string {
+	if priority, ok := m.priorities[queue]; ok {
+		return priority
+	}
+	if priority, ok := executortypes.DefaultQueuePriorities[queue]; ok {
+		return priority
+	}
+	return executortypes.PriorityBulk
+}
+
+// SelectHighestPriorityQueues returns the queues of the highest priority class in the provided list.
+func (m *MultiHandler) SelectHighestPriorityQueues(queues []string) []string {
+	var interactiveQueues []string
+	for _, queue := range queues {
+		if m.QueuePriority(queue) == executortypes.PriorityInteractive {
+			interactiveQueues = append(interactiveQueues, queue)
+		}
+	}
+	if len(interactiveQueues) == 0 {
+		return queues
+	}
+	return interactiveQueues
+}
+
+// SelectQueuesWithinQuota returns the queues that process less jobs than their configured quota.
+func (m *MultiHandler) SelectQueuesWithinQuota(ctx context.Context, queues []string) ([]string, error) {
+	var withinQuota []string
+	for _, queue := range queues {
+		quota, ok := m.quotas[queue]
+		if !ok || quota.MaxProcessing <= 0 {
+			withinQuota = append(withinQuota, queue)
+			continue
+		}
+
+		var err error
+		var processing int
+		switch queue {
+		case m.BatchesQueueHandler.Name:
+			processing, err = processingCount(ctx, m.BatchesQueueHandler.Store)
+		case m.CodeIntelQueueHandler.Name:
+			processing, err = processingCount(ctx, m.CodeIntelQueueHandler.Store)
+		}
+		if err != nil {
+			m.logger.Error("fetching number of processing jobs", log.Error(err), log.String("queue", queue))
+			return nil, err
+		}
+		if processing < quota.MaxProcessing {
+			withinQuota = append(withinQuota, queue)
+		}
+	}
+	return withinQuota, nil
+}
+
+// withoutQueue returns the queues except the given one.
+func withoutQueue(queues []string, queue string) []string {
+	remaining := make([]string, 0, len(queues))
+	for _, q := range queues {
+		if q != queue {
+			remaining = append(remaining, q)
+		}
+	}
+	return remaining
+}
+
+func processingCount[T workerutil.Record](ctx context.Context, store dbworkerstore.Store[T]) (int, error) {
+	total, err := store.QueuedCount(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+	queued, err := store.QueuedCount(ctx, false)
+	if err != nil {
+		return 0, err
+	}
+	return total - queued, nil
+}
+
 // SelectQueueForDequeueing selects a queue from the provided list with weighted randomness.
 func (m *MultiHandler) SelectQueueForDequeueing(candidateQueues []string) (string, error) {
 	return DoSelectQueueForDequeueing(candidateQueues, m.dequeueCacheConfig)
@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/gorilla/mux"
+	"github.com/keegancsmith/sqlf"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -64,17 +65,16 @@ func TestMultiHandler_HandleDequeue(t *testing.T) {
 			body: `{"executorName": "test-executor", "numCPUs": 1, "memory": "1GB", "diskSpace": "10GB","queues": ["codeintel", "batches"]}`,
 			mockFunc: func(codeintelMockStore *dbworkerstoremocks.MockStore[uploadsshared.Index], batchesMockStore *dbworkerstoremocks.MockStore[*btypes.BatchSpecWorkspaceExecutionJob], jobTokenStore *executorstore.MockJobTokenStore) {
 				// QueuedCount gets called for each queue in queues on every invocation of HandleDequeue to filter empty queues,
-				// so two calls are mocked for two dequeue events. Functionally it doesn't really matter what these return, but
-				// for the sake of accuracy, the codeintel store returns 1 less. The batches store returns the same value because
-				// the batches job isn't dequeued until after the second call to QueuedCount.
-				codeintelMockStore.QueuedCountFunc.PushReturn(2, nil)
+				// so two calls are mocked for two dequeue events. The batches queue is interactive and preempts the codeintel
+				// queue, so it is drained by the first dequeue event before the codeintel job is dequeued.
 				codeintelMockStore.QueuedCountFunc.PushReturn(1, nil)
-				batchesMockStore.QueuedCountFunc.PushReturn(2, nil)
-				batchesMockStore.QueuedCountFunc.PushReturn(2, nil)
+				codeintelMockStore.QueuedCountFunc.PushReturn(1, nil)
+				batchesMockStore.QueuedCountFunc.PushReturn(1, nil)
+				batchesMockStore.QueuedCountFunc.PushReturn(0, nil)
 
-				codeintelMockStore.DequeueFunc.PushReturn(uploadsshared.Index{ID: 1}, true, nil)
-				jobTokenStore.CreateFunc.PushReturn("token1", nil)
 				batchesMockStore.DequeueFunc.PushReturn(&btypes.BatchSpecWorkspaceExecutionJob{ID: 2}, true, nil)
+				jobTokenStore.CreateFunc.PushReturn("token1", nil)
+				codeintelMockStore.DequeueFunc.PushReturn(uploadsshared.Index{ID: 1}, true, nil)
 				jobTokenStore.CreateFunc.PushReturn("token2", nil)
 			},
 			assertionFunc: func(t *testing.T, codeintelMockStore *dbworkerstoremocks.MockStore[uploadsshared.Index], batchesMockStore *dbworkerstoremocks.MockStore[*btypes.BatchSpecWorkspaceExecutionJob], jobTokenStore *executorstore.MockJobTokenStore) {
@@ -82,28 +82,54 @@ func TestMultiHandler_HandleDequeue(t *testing.T) {
 
 				require.Len(t, codeintelMockStore.QueuedCountFunc.History(), 2)
 				require.Len(t, batchesMockStore.QueuedCountFunc.History(), 2)
-				require.Len(t, codeintelMockStore.DequeueFunc.History(), 1)
-				assert.Equal(t, "test-executor", codeintelMockStore.DequeueFunc.History()[0].Arg1)
-				assert.Nil(t, codeintelMockStore.DequeueFunc.History()[0].Arg2)
-				assert.Equal(t, 1, jobTokenStore.CreateFunc.History()[0].Arg1)
-				assert.Equal(t, "codeintel", jobTokenStore.CreateFunc.History()[0].Arg2)
 
 				require.Len(t, batchesMockStore.DequeueFunc.History(), 1)
 				assert.Equal(t, "test-executor", batchesMockStore.DequeueFunc.History()[0].Arg1)
 				assert.Nil(t, batchesMockStore.DequeueFunc.History()[0].Arg2)
-				assert.Equal(t, 2, jobTokenStore.CreateFunc.History()[1].Arg1)
-				assert.Equal(t, "batches", jobTokenStore.CreateFunc.History()[1].Arg2)
+				assert.Equal(t, 2, jobTokenStore.CreateFunc.History()[0].Arg1)
+				assert.Equal(t, "batches", jobTokenStore.CreateFunc.History()[0].Arg2)
+
+				require.Len(t, codeintelMockStore.DequeueFunc.History(), 1)
+				assert.Equal(t, "test-executor", codeintelMockStore.DequeueFunc.History()[0].Arg1)
+				assert.Nil(t, codeintelMockStore.DequeueFunc.History()[0].Arg2)
+				assert.Equal(t, 1, jobTokenStore.CreateFunc.History()[1].Arg1)
+				assert.Equal(t, "codeintel", jobTokenStore.CreateFunc.History()[1].Arg2)
 			},
 			dequeueEvents: []dequeueEvent{
+				{
+					queueName:            "batches",
+					expectedStatusCode:   http.StatusOK,
+					expectedResponseBody: `{"id":2,"token":"token1","queue":"batches","repositoryName":"","repositoryDirectory":"","commit":"","fetchTags":false,"shallowClone":false,"sparseCheckout":null,"files":{},"dockerSteps":null,"cliSteps":null,"redactedValues":null}`,
+				},
 				{
 					queueName:            "codeintel",
 					expectedStatusCode:   http.StatusOK,
-					expectedResponseBody: `{"id":1,"token":"token1","queue":"codeintel","repositoryName":"","repositoryDirectory":"","commit":"","fetchTags":false,"shallowClone":false,"sparseCheckout":null,"files":{},"dockerSteps":null,"cliSteps":null,"redactedValues":null}`,
+					expectedResponseBody: `{"id":1,"token":"token2","queue":"codeintel","repositoryName":"","repositoryDirectory":"","commit":"","fetchTags":false,"shallowClone":false,"sparseCheckout":null,"files":{},"dockerSteps":null,"cliSteps":null,"redactedValues":null}`,
 				},
+			},
+		},
+		{
+			name: "Fall back to codeintel when batches has no job to dequeue",
+			body: `{"executorName": "test-executor", "numCPUs": 1, "memory": "1GB", "diskSpace": "10GB","queues": ["codeintel", "batches"]}`,
+			mockFunc: func(codeintelMockStore *dbworkerstoremocks.MockStore[uploadsshared.Index], batchesMockStore *dbworkerstoremocks.MockStore[*btypes.BatchSpecWorkspaceExecutionJob], jobTokenStore *executorstore.MockJobTokenStore) {
+				codeintelMockStore.QueuedCountFunc.PushReturn(1, nil)
+				batchesMockStore.QueuedCountFunc.PushReturn(1, nil)
+				// another executor dequeued the batches job in the meantime
+				batchesMockStore.DequeueFunc.PushReturn(nil, false, nil)
+				codeintelMockStore.DequeueFunc.PushReturn(uploadsshared.Index{ID: 1}, true, nil)
+				jobTokenStore.CreateFunc.PushReturn("token1", nil)
+			},
+			assertionFunc: func(t *testing.T, codeintelMockStore *dbworkerstoremocks.MockStore[uploadsshared.Index], batchesMockStore *dbworkerstoremocks.MockStore[*btypes.BatchSpecWorkspaceExecutionJob], jobTokenStore *executorstore.MockJobTokenStore) {
+				require.Len(t, batchesMockStore.DequeueFunc.History(), 1)
+				require.Len(t, codeintelMockStore.DequeueFunc.History(), 1)
+				require.Len(t, jobTokenStore.CreateFunc.History(), 1)
+				assert.Equal(t, "codeintel", jobTokenStore.CreateFunc.History()[0].Arg2)
+			},
+			dequeueEvents: []dequeueEvent{
 				{
-					queueName:            "batches",
+					queueName:            "codeintel",
 					expectedStatusCode:   http.StatusOK,
-					expectedResponseBody: `{"id":2,"token":"token2","queue":"batches","repositoryName":"","repositoryDirectory":"","commit":"","fetchTags":false,"shallowClone":false,"sparseCheckout":null,"files":{},"dockerSteps":null,"cliSteps":null,"redactedValues":null}`,
+					expectedResponseBody: `{"id":1,"token":"token1","queue":"codeintel","repositoryName":"","repositoryDirectory":"","commit":"","fetchTags":false,"shallowClone":false,"sparseCheckout":null,"files":{},"dockerSteps":null,"cliSteps":null,"redactedValues":null}`,
 				},
 			},
 		},
@@ -1040,6 +1066,144 @@ func TestMultiHandler_SelectEligibleQueues(t *testing.T) {
 	}
 }
 
+func TestMultiHandler_SelectHighestPriorityQueues(t *testing.T) {
+	tests := []struct {
+		name           string
+		priorities     map[string]string
+		queues         []string
+		expectedQueues []string
+	}{
+		{
+			name:           "Default priorities",
+			queues:         []string{"codeintel", "batches"},
+			expectedQueues: []string{"batches"},
+		},
+		{
+			name:           "Only bulk queues",
+			queues:         []string{"codeintel"},
+			expectedQueues: []string{"codeintel"},
+		},
+		{
+			name:           "Same priority class",
+			priorities:     map[string]string{"batches": "bulk"},
+			queues:         []string{"codeintel", "batches"},
+			expectedQueues: []string{"codeintel", "batches"},
+		},
+		{
+			name:           "Configured priorities",
+			priorities:     map[string]string{"batches": "bulk", "codeintel": "interactive"},
+			queues:         []string{"codeintel", "batches"},
+			expectedQueues: []string{"codeintel"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMultiqueueSiteConfig(t, tt.priorities, nil)
+			m := handler.NewMultiHandler(
+				nil,
+				nil,
+				nil,
+				handler.QueueHandler[uploadsshared.Index]{Name: "codeintel"},
+				handler.QueueHandler[*btypes.BatchSpecWorkspaceExecutionJob]{Name: "batches"},
+			)
+
+			assert.Equal(t, tt.expectedQueues, m.SelectHighestPriorityQueues(tt.queues))
+		})
+	}
+}
+
+func TestMultiHandler_SelectQueuesWithinQuota(t *testing.T) {
+	mockMultiqueueSiteConfig(t, nil, map[string]schema.ExecutorQueueQuota{
+		"batches":   {MaxProcessing: 10},
+		"codeintel": {MaxProcessing: 5},
+	})
+
+	codeintelMockStore := dbworkerstoremocks.NewMockStore[uploadsshared.Index]()
+	// 5 processing and 3 queued jobs
+	codeintelMockStore.QueuedCountFunc.SetDefaultHook(func(_ context.Context, includeProcessing bool) (int, error) {
+		if includeProcessing {
+			return 8, nil
+		}
+		return 3, nil
+	})
+	batchesMockStore := dbworkerstoremocks.NewMockStore[*btypes.BatchSpecWorkspaceExecutionJob]()
+	// 2 processing and 1 queued job
+	batchesMockStore.QueuedCountFunc.SetDefaultHook(func(_ context.Context, includeProcessing bool) (int, error) {
+		if includeProcessing {
+			return 3, nil
+		}
+		return 1, nil
+	})
+
+	m := handler.NewMultiHandler(
+		nil,
+		nil,
+		nil,
+		handler.QueueHandler[uploadsshared.Index]{Name: "codeintel", Store: codeintelMockStore},
+		handler.QueueHandler[*btypes.BatchSpecWorkspaceExecutionJob]{Name: "batches", Store: batchesMockStore},
+	)
+
+	queues, err := m.SelectQueuesWithinQuota(context.Background(), []string{"codeintel", "batches"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"batches"}, queues)
+}
+
+func TestMultiHandler_HandleDequeue_TenantQuota(t *testing.T) {
+	rcache.SetupForTest(t)
+	mockMultiqueueSiteConfig(t, nil, map[string]schema.ExecutorQueueQuota{
+		"codeintel": {MaxProcessingPerTenant: 2},
+	})
+
+	codeintelMockStore := dbworkerstoremocks.NewMockStore[uploadsshared.Index]()
+	codeintelMockStore.QueuedCountFunc.SetDefaultReturn(1, nil)
+	codeintelMockStore.DequeueFunc.SetDefaultReturn(uploadsshared.Index{ID: 1}, true, nil)
+	jobTokenStore := executorstore.NewMockJobTokenStore()
+	jobTokenStore.CreateFunc.SetDefaultReturn("token1", nil)
+
+	mh := handler.NewMultiHandler(
+		database.NewMockExecutorStore(),
+		jobTokenStore,
+		metricsstore.NewMockDistributedStore(),
+		handler.QueueHandler[uploadsshared.Index]{
+			Name:              "codeintel",
+			Store:             codeintelMockStore,
+			RecordTransformer: transformerFunc[uploadsshared.Index],
+			TenantQuotaCondition: func(limit int) *sqlf.Query {
+				return sqlf.Sprintf("tenant_limit = %s", limit)
+			},
+		},
+		handler.QueueHandler[*btypes.BatchSpecWorkspaceExecutionJob]{Name: "batches", Store: dbworkerstoremocks.NewMockStore[*btypes.BatchSpecWorkspaceExecutionJob]()},
+	)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/dequeue", mh.HandleDequeue)
+	evaluateEvent(
+		`{"executorName": "test-executor", "numCPUs": 1, "memory": "1GB", "diskSpace": "10GB","queues": ["codeintel"]}`,
+		http.StatusOK,
+		`{"id":1,"token":"token1","queue":"codeintel","repositoryName":"","repositoryDirectory":"","commit":"","fetchTags":false,"shallowClone":false,"sparseCheckout":null,"files":{},"dockerSteps":null,"cliSteps":null,"redactedValues":null}`,
+		t,
+		router,
+	)
+
+	require.Len(t, codeintelMockStore.DequeueFunc.History(), 1)
+	conditions := codeintelMockStore.DequeueFunc.History()[0].Arg2
+	require.Len(t, conditions, 1)
+	assert.Equal(t, "tenant_limit = $1", conditions[0].Query(sqlf.PostgresBindVar))
+	assert.Equal(t, []any{2}, conditions[0].Args())
+}
+
+func mockMultiqueueSiteConfig(t *testing.T, priorities map[string]string, quotas map[string]schema.ExecutorQueueQuota) {
+	client := conf.DefaultClient()
+	client.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		ExecutorsMultiqueue: &schema.ExecutorsMultiqueue{
+			Priorities: priorities,
+			Quotas:     quotas,
+		},
+	}})
+	t.Cleanup(mockSiteConfig)
+}
+
 func mockSiteConfig() {
 	client := conf.DefaultClient()
 	client.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
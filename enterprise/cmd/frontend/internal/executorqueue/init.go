@@ -1,17 +1,24 @@
 package executorqueue
 
 import (
+	"context"
+
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf/confdefaults"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/conf/deploy"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/executor/artifacts"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
 )
 
+func LoadConfig() {
+	artifacts.UploadStoreConfigInst.Load()
+}
+
 // Init initializes the executor endpoints required for use with the executor service.
 func Init(
 	observationCtx *observation.Context,
@@ -32,6 +39,16 @@ func Init(
 
 	logger := log.Scoped("executorqueue", "")
 
+	if err := artifacts.UploadStoreConfigInst.Validate(); err != nil {
+		return err
+	}
+	artifactsUploadStore, err := artifacts.NewUploadStore(context.Background(), observationCtx, artifacts.UploadStoreConfigInst)
+	if err != nil {
+		return err
+	}
+	artifactsUploadHandler := artifacts.NewUploadHandler(logger.Scoped("artifacts", "stores executor job artifacts"), db, artifactsUploadStore, artifacts.UploadStoreConfigInst)
+	enterpriseServices.ExecutorArtifactDownloadHandler = artifacts.NewDownloadHandler(logger.Scoped("artifacts", "serves executor job artifacts"), db, artifactsUploadStore)
+
 	queueHandler := newExecutorQueuesHandler(
 		observationCtx,
 		db,
@@ -40,6 +57,7 @@ func Init(
 		codeintelUploadHandler,
 		batchesWorkspaceFileGetHandler,
 		batchesWorkspaceFileExistsHandler,
+		artifactsUploadHandler,
 	)
 
 	enterpriseServices.NewExecutorProxyHandler = queueHandler
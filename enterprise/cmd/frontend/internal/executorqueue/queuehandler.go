@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/grafana/regexp"
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/handler"
@@ -31,6 +32,7 @@ func newExecutorQueuesHandler(
 	uploadHandler http.Handler,
 	batchesWorkspaceFileGetHandler http.Handler,
 	batchesWorkspaceFileExistsHandler http.Handler,
+	artifactsUploadHandler http.Handler,
 ) func() http.Handler {
 	metricsStore := metricsstore.NewDistributedStore("executors:")
 	executorStore := db.Executors()
@@ -47,6 +49,10 @@ func newExecutorQueuesHandler(
 	codeintelHandler := handler.NewHandler(executorStore, jobTokenStore, metricsStore, codeIntelQueueHandler)
 	batchesHandler := handler.NewHandler(executorStore, jobTokenStore, metricsStore, batchesQueueHandler)
 	handlers := []handler.ExecutorHandler{codeintelHandler, batchesHandler}
+	queueNames := make([]string, 0, len(handlers))
+	for _, h := range handlers {
+		queueNames = append(queueNames, regexp.QuoteMeta(h.Name()))
+	}
 
 	multiHandler := handler.NewMultiHandler(executorStore, jobTokenStore, metricsStore, codeIntelQueueHandler, batchesQueueHandler)
 
@@ -117,6 +123,13 @@ func newExecutorQueuesHandler(
 		// The files route are treated as an internal actor and require the executor access token to authenticate.
 		filesRouter.Use(withInternalActor, jobAuthMiddleware(logger, routeFiles, jobTokenStore, executorStore))
 
+		// Upload the artifacts of a job to the blob store.
+		artifactsRouter := base.PathPrefix("/artifacts").Name("executor-artifacts").Subrouter()
+		artifactsRouter.Path(fmt.Sprintf("/{queueName:(?:%s)}/{name}", strings.Join(queueNames, "|"))).Methods(http.MethodPut).Handler(artifactsUploadHandler)
+		// The artifacts route are treated as an internal actor. Additionally, each job comes with a short-lived token
+		// that is checked by jobAuthMiddleware.
+		artifactsRouter.Use(withInternalActor, jobAuthMiddleware(logger, routeArtifacts, jobTokenStore, executorStore))
+
 		return base
 	}
 
@@ -126,9 +139,10 @@ func newExecutorQueuesHandler(
 type routeName string
 
 const (
-	routeFiles = "files"
-	routeGit   = "git"
-	routeQueue = "queue"
+	routeArtifacts = "artifacts"
+	routeFiles     = "files"
+	routeGit       = "git"
+	routeQueue     = "queue"
 )
 
 // withInternalActor ensures that the request handling is running as an internal actor.
@@ -265,7 +279,7 @@ func validateJobRequest(
 		queue = "batches"
 	case routeGit:
 		repo = mux.Vars(r)["RepoName"]
-	case routeQueue:
+	case routeQueue, routeArtifacts:
 		queue = mux.Vars(r)["queueName"]
 	default:
 		logger.Error("unsupported route", log.String("route", string(routeName)))
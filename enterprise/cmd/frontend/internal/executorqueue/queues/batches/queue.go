@@ -2,7 +2,9 @@ package batches
 
 import (
 	"context"
+	"time"
 
+	"github.com/keegancsmith/sqlf"
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/handler"
@@ -10,6 +12,7 @@ import (
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor/types"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/executor/secretmanager"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 )
 
@@ -22,8 +25,30 @@ func QueueHandler(observationCtx *observation.Context, db database.DB, _ func()
 
 	store := bstore.NewBatchSpecWorkspaceExecutionWorkerStore(observationCtx, db.Handle())
 	return handler.QueueHandler[*btypes.BatchSpecWorkspaceExecutionJob]{
-		Name:              "batches",
-		Store:             store,
-		RecordTransformer: recordTransformer,
+		Name:                 "batches",
+		Store:                store,
+		RecordTransformer:    recordTransformer,
+		TenantQuotaCondition: tenantQuotaCondition,
+		QueuedAt: func(record *btypes.BatchSpecWorkspaceExecutionJob) time.Time {
+			return record.CreatedAt
+		},
+		RevokeSecretLeases: func(ctx context.Context, jobID int) error {
+			return secretmanager.RevokeJob(ctx, db, "batches", jobID)
+		},
 	}
 }
+
+// tenantQuotaCondition excludes the jobs of users that already have limit jobs processing.
+func tenantQuotaCondition(limit int) *sqlf.Query {
+	return sqlf.Sprintf(tenantQuotaConditionFmtstr, limit)
+}
+
+const tenantQuotaConditionFmtstr = `
+batch_spec_workspace_execution_jobs.user_id NOT IN (
+	SELECT user_id
+	FROM batch_spec_workspace_execution_jobs
+	WHERE state = 'processing'
+	GROUP BY user_id
+	HAVING COUNT(*) >= %s
+)
+`
@@ -20,6 +20,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
+	"github.com/sourcegraph/sourcegraph/internal/executor/secretmanager"
 	"github.com/sourcegraph/sourcegraph/lib/api"
 	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 	"github.com/sourcegraph/sourcegraph/lib/batches/template"
@@ -92,6 +93,11 @@ func transformRecord(ctx context.Context, logger log.Logger, s BatchesStore, job
 		if err != nil {
 			return apiclient.Job{}, err
 		}
+		// Values referencing an external secret manager are resolved for this job.
+		val, err = secretmanager.Resolve(ctx, s.DatabaseDB(), "batches", int(job.ID), val)
+		if err != nil {
+			return apiclient.Job{}, errors.Wrapf(err, "resolving secret %s", secret.Key)
+		}
 
 		secretEnvVars[i] = fmt.Sprintf("%s=%s", secret.Key, val)
 		// We redact secret values as ${{ secrets.NAME }}.
@@ -337,6 +343,10 @@ func transformRecord(ctx context.Context, logger log.Logger, s BatchesStore, job
 		if err != nil {
 			return apiclient.Job{}, err
 		}
+		val, err = secretmanager.Resolve(ctx, s.DatabaseDB(), "batches", int(job.ID), val)
+		if err != nil {
+			return apiclient.Job{}, errors.Wrap(err, "resolving secret DOCKER_AUTH_CONFIG")
+		}
 		if err := json.Unmarshal([]byte(val), &aj.DockerAuthConfig); err != nil {
 			return aj, err
 		}
@@ -2,12 +2,16 @@ package codeintel
 
 import (
 	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/handler"
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor/types"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing"
 	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/executor/secretmanager"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
@@ -20,8 +24,31 @@ func QueueHandler(observationCtx *observation.Context, db database.DB, accessTok
 	store := dbworkerstore.New(observationCtx, db.Handle(), autoindexing.IndexWorkerStoreOptions)
 
 	return handler.QueueHandler[uploadsshared.Index]{
-		Name:              "codeintel",
-		Store:             store,
-		RecordTransformer: recordTransformer,
+		Name:                 "codeintel",
+		Store:                store,
+		RecordTransformer:    recordTransformer,
+		TenantQuotaCondition: tenantQuotaCondition,
+		QueuedAt: func(record uploadsshared.Index) time.Time {
+			return record.QueuedAt
+		},
+		RevokeSecretLeases: func(ctx context.Context, jobID int) error {
+			return secretmanager.RevokeJob(ctx, db, "codeintel", jobID)
+		},
 	}
 }
+
+// tenantQuotaCondition excludes the index jobs of repositories that already have limit jobs
+// processing.
+func tenantQuotaCondition(limit int) *sqlf.Query {
+	return sqlf.Sprintf(tenantQuotaConditionFmtstr, limit)
+}
+
+const tenantQuotaConditionFmtstr = `
+u.repository_id NOT IN (
+	SELECT repository_id
+	FROM lsif_indexes
+	WHERE state = 'processing'
+	GROUP BY repository_id
+	HAVING COUNT(*) >= %s
+)
+`
@@ -18,6 +18,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
+	"github.com/sourcegraph/sourcegraph/internal/executor/secretmanager"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 const (
@@ -65,6 +67,11 @@ func transformRecord(ctx context.Context, db database.DB, index uploadsshared.In
 		if err != nil {
 			return apiclient.Job{}, err
 		}
+		// Values referencing an external secret manager are resolved for this job.
+		val, err = secretmanager.Resolve(ctx, db, "codeintel", index.ID, val)
+		if err != nil {
+			return apiclient.Job{}, errors.Wrapf(err, "resolving secret %s", secret.Key)
+		}
 
 		secretEnvVars[i] = fmt.Sprintf("%s=%s", secret.Key, val)
 		// We redact secret values as ${{ secrets.NAME }}.
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
@@ -53,7 +54,13 @@ func (h *ExportHandler) ExportFunc() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := mux.Vars(r)["id"]
 
-		archive, err := h.exportCodeInsightData(r.Context(), id)
+		format := r.URL.Query().Get("format")
+		if format != "" && format != exportFormatCSV && format != exportFormatJSON {
+			http.Error(w, fmt.Sprintf("unsupported export format %q", format), http.StatusBadRequest)
+			return
+		}
+
+		archive, err := h.exportCodeInsightData(r.Context(), id, format)
 		if err != nil {
 			if errors.Is(err, notFoundError) {
 				http.Error(w, err.Error(), http.StatusNotFound)
@@ -66,8 +73,8 @@ func (h *ExportHandler) ExportFunc() http.HandlerFunc {
 			}
 			return
 		}
-		w.Header().Set("Content-Type", "application/zip")
-		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.zip\"", archive.name))
+		w.Header().Set("Content-Type", archive.contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", archive.name))
 
 		_, err = w.Write(archive.data)
 		if err != nil {
@@ -77,15 +84,42 @@ func (h *ExportHandler) ExportFunc() http.HandlerFunc {
 }
 
 type codeInsightsDataArchive struct {
-	name string
-	data []byte
+	name        string
+	contentType string
+	data        []byte
+}
+
+const (
+	// exportFormatCSV exports a zip archive containing a CSV file. It is the
+	// default export format.
+	exportFormatCSV = "csv"
+	// exportFormatJSON exports a JSON document.
+	exportFormatJSON = "json"
+)
+
+// exportedInsight is the JSON representation of an exported insight.
+type exportedInsight struct {
+	Title      string                 `json:"title"`
+	ExportedAt time.Time              `json:"exportedAt"`
+	Points     []exportedInsightPoint `json:"points"`
+}
+
+type exportedInsightPoint struct {
+	SeriesID         string    `json:"seriesId"`
+	Label            string    `json:"label"`
+	Query            string    `json:"query"`
+	SamplingStrategy string    `json:"samplingStrategy"`
+	RecordingTime    time.Time `json:"recordingTime"`
+	Repository       *string   `json:"repository"`
+	Value            int       `json:"value"`
+	Capture          *string   `json:"capture"`
 }
 
 var notFoundError = errors.New("insight not found")
 var authenticationError = errors.New("authentication error")
 var invalidLicenseError = errors.New("invalid license for code insights")
 
-func (h *ExportHandler) exportCodeInsightData(ctx context.Context, id string) (*codeInsightsDataArchive, error) {
+func (h *ExportHandler) exportCodeInsightData(ctx context.Context, id, format string) (*codeInsightsDataArchive, error) {
 	currentActor := actor.FromContext(ctx)
 	if !currentActor.IsAuthenticated() {
 		return nil, authenticationError
@@ -142,7 +176,7 @@ func (h *ExportHandler) exportCodeInsightData(ctx context.Context, id string) (*
 		includeRepo(*visibleViewSeries[0].DefaultFilterIncludeRepoRegex)
 	}
 	if visibleViewSeries[0].DefaultFilterExcludeRepoRegex != nil {
-		includeRepo(*visibleViewSeries[0].DefaultFilterExcludeRepoRegex)
+		excludeRepo(*visibleViewSeries[0].DefaultFilterExcludeRepoRegex)
 	}
 
 	inc, exc, err := h.searchContextHandler.UnwrapSearchContexts(ctx, visibleViewSeries[0].DefaultFilterSearchContexts)
@@ -152,12 +186,42 @@ func (h *ExportHandler) exportCodeInsightData(ctx context.Context, id string) (*
 	includeRepo(inc...)
 	excludeRepo(exc...)
 
-	var buf bytes.Buffer
-	zw := zip.NewWriter(&buf)
+	opts.InsightViewUniqueID = insightViewId
+	dataPoints, err := h.seriesStore.GetAllDataForInsightViewID(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch all data for insight")
+	}
 
-	timestamp := time.Now().Format(time.RFC3339)
+	now := time.Now()
 	escapedInsightViewTitle := regexp.MustCompile(`\W+`).ReplaceAllString(visibleViewSeries[0].Title, "-")
-	name := fmt.Sprintf("%s-%s", escapedInsightViewTitle, timestamp)
+	name := fmt.Sprintf("%s-%s", escapedInsightViewTitle, now.Format(time.RFC3339))
+
+	if format == exportFormatJSON {
+		data, err := exportJSON(visibleViewSeries[0].Title, now, dataPoints)
+		if err != nil {
+			return nil, err
+		}
+		return &codeInsightsDataArchive{
+			name:        fmt.Sprintf("%s.json", name),
+			contentType: "application/json",
+			data:        data,
+		}, nil
+	}
+
+	data, err := exportCSVArchive(name, dataPoints)
+	if err != nil {
+		return nil, err
+	}
+	return &codeInsightsDataArchive{
+		name:        fmt.Sprintf("%s.zip", name),
+		contentType: "application/zip",
+		data:        data,
+	}, nil
+}
+
+func exportCSVArchive(name string, dataPoints []store.SeriesPointForExport) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
 
 	dataFile, err := zw.Create(fmt.Sprintf("%s.csv", name))
 	if err != nil {
@@ -181,12 +245,6 @@ func (h *ExportHandler) exportCodeInsightData(ctx context.Context, id string) (*
 		return nil, errors.Wrap(err, "failed to write csv header")
 	}
 
-	opts.InsightViewUniqueID = insightViewId
-	dataPoints, err := h.seriesStore.GetAllDataForInsightViewID(ctx, opts)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to fetch all data for insight")
-	}
-
 	for _, d := range dataPoints {
 		dataPoint[0] = d.InsightViewTitle
 		dataPoint[1] = d.SeriesLabel
@@ -205,10 +263,28 @@ func (h *ExportHandler) exportCodeInsightData(ctx context.Context, id string) (*
 	if err := zw.Close(); err != nil {
 		return nil, err
 	}
-	return &codeInsightsDataArchive{
-		name: name,
-		data: buf.Bytes(),
-	}, nil
+	return buf.Bytes(), nil
+}
+
+func exportJSON(title string, exportedAt time.Time, dataPoints []store.SeriesPointForExport) ([]byte, error) {
+	insight := exportedInsight{
+		Title:      title,
+		ExportedAt: exportedAt.UTC(),
+		Points:     make([]exportedInsightPoint, 0, len(dataPoints)),
+	}
+	for _, d := range dataPoints {
+		insight.Points = append(insight.Points, exportedInsightPoint{
+			SeriesID:         d.SeriesID,
+			Label:            d.SeriesLabel,
+			Query:            d.SeriesQuery,
+			SamplingStrategy: string(d.SamplingStrategy),
+			RecordingTime:    d.RecordingTime.UTC(),
+			Repository:       d.RepoName,
+			Value:            d.Value,
+			Capture:          d.Capture,
+		})
+	}
+	return json.Marshal(insight)
 }
 
 func emptyStringIfNil(s *string) string {
@@ -15,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/computed"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/scheduler"
 	insightsstore "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
@@ -31,6 +32,7 @@ import (
 var _ graphqlbackend.InsightSeriesMetadataPayloadResolver = &insightSeriesMetadataPayloadResolver{}
 var _ graphqlbackend.InsightSeriesMetadataResolver = &insightSeriesMetadataResolver{}
 var _ graphqlbackend.InsightSeriesQueryStatusResolver = &insightSeriesQueryStatusResolver{}
+var _ graphqlbackend.InsightsComputedSeriesMetricResolver = &insightsComputedSeriesMetricResolver{}
 
 func (r *Resolver) UpdateInsightSeries(ctx context.Context, args *graphqlbackend.UpdateInsightSeriesArgs) (graphqlbackend.InsightSeriesMetadataPayloadResolver, error) {
 	actr := actor.FromContext(ctx)
@@ -91,6 +93,26 @@ func (r *Resolver) InsightSeriesQueryStatus(ctx context.Context) ([]graphqlbacke
 	return resolvers, nil
 }
 
+func (r *Resolver) InsightsComputedSeriesMetrics(ctx context.Context) ([]graphqlbackend.InsightsComputedSeriesMetricResolver, error) {
+	actr := actor.FromContext(ctx)
+	if err := auth.CheckUserIsSiteAdmin(ctx, r.postgresDB, actr.UID); err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.InsightsComputedSeriesMetricResolver, 0, len(computed.Metrics))
+	for _, metric := range computed.Metrics {
+		resolvers = append(resolvers, &insightsComputedSeriesMetricResolver{metric: metric})
+	}
+	return resolvers, nil
+}
+
+type insightsComputedSeriesMetricResolver struct {
+	metric computed.Metric
+}
+
+func (i *insightsComputedSeriesMetricResolver) Name() string        { return i.metric.Name }
+func (i *insightsComputedSeriesMetricResolver) Description() string { return i.metric.Description }
+
 func (r *Resolver) InsightViewDebug(ctx context.Context, args graphqlbackend.InsightViewDebugArgs) (graphqlbackend.InsightViewDebugResolver, error) {
 	actr := actor.FromContext(ctx)
 	if err := auth.CheckUserIsSiteAdmin(ctx, r.postgresDB, actr.UID); err != nil {
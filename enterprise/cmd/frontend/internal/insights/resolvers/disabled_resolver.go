@@ -84,6 +84,10 @@ func (r *disabledResolver) SearchQueryAggregate(ctx context.Context, args graphq
 	return nil, errors.New(r.reason)
 }
 
+func (r *disabledResolver) InsightsComputedSeriesMetrics(ctx context.Context) ([]graphqlbackend.InsightsComputedSeriesMetricResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
 func (r *disabledResolver) InsightViewDebug(ctx context.Context, args graphqlbackend.InsightViewDebugArgs) (graphqlbackend.InsightViewDebugResolver, error) {
 	return nil, errors.New(r.reason)
 }
@@ -2,6 +2,7 @@ package resolvers
 
 import (
 	"context"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -16,11 +17,13 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/computed"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/query/querybuilder"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/scheduler"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/licensing"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -454,6 +457,12 @@ func (r *Resolver) CreateLineChartSearchInsight(ctx context.Context, args *graph
 		if err != nil {
 			return nil, err
 		}
+		// 🚨 SECURITY: Computed series read metrics of the whole instance, so only site admins can create them.
+		if isComputedSeries(args.Input.DataSeries[i]) {
+			if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.postgresDB); err != nil {
+				return nil, err
+			}
+		}
 
 		if len(args.Input.DataSeries[i].RepositoryScope.Repositories) > 0 {
 			err := validateRepositoryList(ctx, args.Input.DataSeries[i].RepositoryScope.Repositories, r.postgresDB.Repos())
@@ -550,6 +559,12 @@ func (r *Resolver) UpdateLineChartSearchInsight(ctx context.Context, args *graph
 		if err != nil {
 			return nil, err
 		}
+		// 🚨 SECURITY: Computed series read metrics of the whole instance, so only site admins can create them.
+		if isComputedSeries(args.Input.DataSeries[i]) {
+			if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.postgresDB); err != nil {
+				return nil, err
+			}
+		}
 
 		if len(args.Input.DataSeries[i].RepositoryScope.Repositories) > 0 {
 			err := validateRepositoryList(ctx, args.Input.DataSeries[i].RepositoryScope.Repositories, r.postgresDB.Repos())
@@ -742,6 +757,9 @@ func existingSeriesHasChanged(new graphqlbackend.LineChartSearchInsightDataSerie
 	if new.Query != existing.Query {
 		return true
 	}
+	if isComputedSeries(new) != (existing.GenerationMethod == types.Computed) {
+		return true
+	}
 	if new.TimeScope.StepInterval.Unit != existing.SampleIntervalUnit {
 		return true
 	}
@@ -1277,6 +1295,9 @@ func makeFillSeriesStrategy(tx *store.InsightStore, scheduler *scheduler.Schedul
 		if series.GroupBy != nil {
 			return groupBySeriesFill(ctx, series, tx, insightEnqueuer)
 		}
+		if series.GenerationMethod == types.Computed {
+			return computedSeriesFill(ctx, series, tx, insightEnqueuer)
+		}
 		return historicFill(ctx, series, tx, scheduler)
 	}
 }
@@ -1294,6 +1315,18 @@ func groupBySeriesFill(ctx context.Context, series types.InsightSeries, tx *stor
 	return nil
 }
 
+func computedSeriesFill(ctx context.Context, series types.InsightSeries, tx *store.InsightStore, insightEnqueuer *background.InsightEnqueuer) error {
+	// Computed series have no history, so we record their current value right away instead of backfilling them.
+	if err := insightEnqueuer.EnqueueSingle(ctx, series, store.RecordMode, tx.StampRecording); err != nil {
+		return errors.Wrap(err, "Computed.EnqueueSingle")
+	}
+	_, err := tx.StampBackfill(ctx, series)
+	if err != nil {
+		return errors.Wrap(err, "Computed.StampBackfill")
+	}
+	return nil
+}
+
 func historicFill(ctx context.Context, series types.InsightSeries, tx *store.InsightStore, backfillScheduler *scheduler.Scheduler) error {
 	backfillScheduler = backfillScheduler.With(tx)
 	_, err := backfillScheduler.InitialBackfill(ctx, series)
@@ -1314,7 +1347,11 @@ func createAndAttachSeries(ctx context.Context, tx *store.InsightStore, startSer
 	var err error
 	var dynamic bool
 	// Validate the query before creating anything; we don't want faulty insights running pointlessly.
-	if series.GroupBy != nil || series.GeneratedFromCaptureGroups != nil {
+	if isComputedSeries(series) {
+		if _, err := computed.Parse(series.Query); err != nil {
+			return errors.Wrap(err, "expression validation")
+		}
+	} else if series.GroupBy != nil || series.GeneratedFromCaptureGroups != nil {
 		if _, err := querybuilder.ParseComputeQuery(series.Query); err != nil {
 			return errors.Wrap(err, "query validation")
 		}
@@ -1341,13 +1378,15 @@ func createAndAttachSeries(ctx context.Context, tx *store.InsightStore, startSer
 	// Don't try to match on non-global series, since they are always replaced
 	// Also don't try to match on series that use repo criteria
 	// TODO: Reconsider matching on criteria based series. If so the edit case would need work to ensure other insights remain the same.
-	if len(series.RepositoryScope.Repositories) == 0 && series.RepositoryScope.RepositoryCriteria == nil {
+	// Computed series aren't matched either, as their expression could be mistaken for the query of a search series.
+	if len(series.RepositoryScope.Repositories) == 0 && series.RepositoryScope.RepositoryCriteria == nil && !isComputedSeries(series) {
 		matchingSeries, foundSeries, err = tx.FindMatchingSeries(ctx, store.MatchSeriesArgs{
 			Query:                     series.Query,
 			StepIntervalUnit:          series.TimeScope.StepInterval.Unit,
 			StepIntervalValue:         int(series.TimeScope.StepInterval.Value),
 			GenerateFromCaptureGroups: dynamic,
 			GroupBy:                   groupBy,
+			SamplingStrategy:          backfillSamplingStrategy(series),
 		})
 		if err != nil {
 			return errors.Wrap(err, "FindMatchingSeries")
@@ -1356,6 +1395,14 @@ func createAndAttachSeries(ctx context.Context, tx *store.InsightStore, startSer
 
 	if !foundSeries {
 		repos := series.RepositoryScope.Repositories
+		var samplingTagPattern *string
+		var samplingCommitInterval int
+		if series.BackfillSampling != nil {
+			samplingTagPattern = series.BackfillSampling.TagPattern
+			if series.BackfillSampling.CommitInterval != nil {
+				samplingCommitInterval = int(*series.BackfillSampling.CommitInterval)
+			}
+		}
 		seriesToAdd, err = tx.CreateSeries(ctx, types.InsightSeries{
 			SeriesID:                   ksuid.New().String(),
 			Query:                      series.Query,
@@ -1370,6 +1417,9 @@ func createAndAttachSeries(ctx context.Context, tx *store.InsightStore, startSer
 			NextRecordingAfter:         nextRecordingAfter,
 			OldestHistoricalAt:         oldestHistoricalAt,
 			RepositoryCriteria:         series.RepositoryScope.RepositoryCriteria,
+			SamplingStrategy:           backfillSamplingStrategy(series),
+			SamplingTagPattern:         samplingTagPattern,
+			SamplingCommitInterval:     samplingCommitInterval,
 		})
 		if err != nil {
 			return errors.Wrap(err, "CreateSeries")
@@ -1397,6 +1447,9 @@ func createAndAttachSeries(ctx context.Context, tx *store.InsightStore, startSer
 }
 
 func searchGenerationMethod(series graphqlbackend.LineChartSearchInsightDataSeriesInput) types.GenerationMethod {
+	if isComputedSeries(series) {
+		return types.Computed
+	}
 	if series.GeneratedFromCaptureGroups != nil && *series.GeneratedFromCaptureGroups {
 		if series.GroupBy != nil {
 			return types.MappingCompute
@@ -1406,6 +1459,10 @@ func searchGenerationMethod(series graphqlbackend.LineChartSearchInsightDataSeri
 	return types.Search
 }
 
+func isComputedSeries(series graphqlbackend.LineChartSearchInsightDataSeriesInput) bool {
+	return series.Computed != nil && *series.Computed
+}
+
 func seriesFound(existingSeries types.InsightViewSeries, inputSeries []graphqlbackend.LineChartSearchInsightDataSeriesInput) bool {
 	for i := range inputSeries {
 		if inputSeries[i].SeriesId == nil {
@@ -1610,6 +1667,15 @@ func lowercaseGroupBy(groupBy *string) *string {
 	return groupBy
 }
 
+// backfillSamplingStrategy returns the backfill sampling strategy of the
+// series input, which defaults to sampling at the time scope interval.
+func backfillSamplingStrategy(series graphqlbackend.LineChartSearchInsightDataSeriesInput) types.SamplingStrategy {
+	if series.BackfillSampling == nil {
+		return types.SamplingInterval
+	}
+	return types.SamplingStrategy(strings.ReplaceAll(strings.ToLower(series.BackfillSampling.Strategy), "_", "-"))
+}
+
 func isValidSeriesInput(seriesInput graphqlbackend.LineChartSearchInsightDataSeriesInput) error {
 	if seriesInput.RepositoryScope == nil {
 		return errors.New("a repository scope is required")
@@ -1626,6 +1692,41 @@ func isValidSeriesInput(seriesInput graphqlbackend.LineChartSearchInsightDataSer
 		return errors.New("group by series require a list of repositories to be specified.")
 	}
 
+	if isComputedSeries(seriesInput) {
+		if repoListSpecified || repoCriteriaSpecified {
+			return errors.New("computed series can not be scoped to repositories")
+		}
+		if seriesInput.GroupBy != nil || isCaptureGroupSeries(seriesInput.GeneratedFromCaptureGroups) {
+			return errors.New("computed series can not be generated from capture groups or grouped")
+		}
+		if backfillSamplingStrategy(seriesInput) != types.SamplingInterval {
+			return errors.New("computed series can not be backfilled")
+		}
+		if _, err := computed.Parse(seriesInput.Query); err != nil {
+			return errors.Wrap(err, "invalid expression")
+		}
+	}
+
+	if sampling := seriesInput.BackfillSampling; sampling != nil {
+		strategy := backfillSamplingStrategy(seriesInput)
+		switch strategy {
+		case types.SamplingInterval, types.SamplingMonthly, types.SamplingReleaseTags, types.SamplingCommits:
+		default:
+			return errors.Newf("unknown backfill sampling strategy %q", sampling.Strategy)
+		}
+		if strategy.RequiresRepositories() && !repoListSpecified {
+			return errors.New("series sampled by release tags or commits require a list of repositories to be specified.")
+		}
+		if strategy == types.SamplingCommits && (sampling.CommitInterval == nil || *sampling.CommitInterval <= 0) {
+			return errors.New("series sampled by commits require a positive commit interval")
+		}
+		if sampling.TagPattern != nil {
+			if _, err := regexp.Compile(*sampling.TagPattern); err != nil {
+				return errors.Wrap(err, "invalid tag pattern")
+			}
+		}
+	}
+
 	if repoCriteriaSpecified {
 		plan, err := querybuilder.ParseQuery(*seriesInput.RepositoryScope.RepositoryCriteria, "literal")
 		if err != nil {
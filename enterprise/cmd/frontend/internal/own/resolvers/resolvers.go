@@ -483,6 +483,69 @@ func (r *ownStatsResolver) UpdatedAt(ctx context.Context) (*gqlutil.DateTime, er
 	return gqlutil.FromTime(counts.UpdatedAt), nil
 }
 
+func (r *ownStatsResolver) TeamCoverage(ctx context.Context, args *graphqlbackend.TeamCoverageArgs) ([]graphqlbackend.TeamOwnershipCoverageResolver, error) {
+	counts, err := r.db.OwnershipStats().QueryIndividualCounts(ctx, r.opts, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	bag := own.EmptyBag()
+	for _, c := range counts {
+		// Teams can only be referred to by handle.
+		if strings.HasPrefix(c.CodeownersReference, "@") {
+			bag.Add(own.Reference{Handle: strings.TrimPrefix(c.CodeownersReference, "@")})
+		}
+	}
+	bag.Resolve(ctx, r.db)
+
+	var coverage []*teamOwnershipCoverageResolver
+	byTeam := map[int32]*teamOwnershipCoverageResolver{}
+	for _, c := range counts {
+		if c.CodeownedFileCount == 0 || !strings.HasPrefix(c.CodeownersReference, "@") {
+			continue
+		}
+		resolved, found := bag.FindResolved(own.Reference{Handle: strings.TrimPrefix(c.CodeownersReference, "@")})
+		team, ok := resolved.(*codeowners.Team)
+		if !found || !ok || team.Team == nil {
+			continue
+		}
+		// Several references, like "@own" and "@sourcegraph/own", can resolve to the same team.
+		if tc, ok := byTeam[team.Team.ID]; ok {
+			tc.ownedFiles += int32(c.CodeownedFileCount)
+			continue
+		}
+		tc := &teamOwnershipCoverageResolver{db: r.db, team: team.Team, ownedFiles: int32(c.CodeownedFileCount)}
+		byTeam[team.Team.ID] = tc
+		coverage = append(coverage, tc)
+	}
+	sort.SliceStable(coverage, func(i, j int) bool {
+		return coverage[i].ownedFiles > coverage[j].ownedFiles
+	})
+	if args.First != nil && *args.First >= 0 && len(coverage) > int(*args.First) {
+		coverage = coverage[:*args.First]
+	}
+
+	rs := make([]graphqlbackend.TeamOwnershipCoverageResolver, 0, len(coverage))
+	for _, tc := range coverage {
+		rs = append(rs, tc)
+	}
+	return rs, nil
+}
+
+type teamOwnershipCoverageResolver struct {
+	db         database.DB
+	team       *types.Team
+	ownedFiles int32
+}
+
+func (r *teamOwnershipCoverageResolver) Team() *graphqlbackend.TeamResolver {
+	return graphqlbackend.NewTeamResolver(r.db, r.team)
+}
+
+func (r *teamOwnershipCoverageResolver) OwnedFiles() int32 {
+	return r.ownedFiles
+}
+
 type ownershipConnectionResolver struct {
 	db          database.DB
 	total       int
@@ -2271,6 +2271,61 @@ func TestDisplayOwnershipStats(t *testing.T) {
 	})
 }
 
+func TestOwnershipStatsTeamCoverage(t *testing.T) {
+	db := database.NewMockDB()
+	fakeOwnershipStats := database.NewMockOwnershipStatsStore()
+	fakeOwnershipStats.QueryIndividualCountsFunc.SetDefaultReturn([]database.PathCodeownersCounts{
+		{CodeownersReference: "@own", CodeownedFileCount: 10},
+		{CodeownersReference: "alice@example.com", CodeownedFileCount: 8},
+		{CodeownersReference: "@search", CodeownedFileCount: 5},
+		{CodeownersReference: "@sourcegraph/own", CodeownedFileCount: 2},
+		{CodeownersReference: "@ghost", CodeownedFileCount: 1},
+		{CodeownersReference: "@batches", CodeownedFileCount: 0},
+	}, nil)
+	db.OwnershipStatsFunc.SetDefaultReturn(fakeOwnershipStats)
+	db.UsersFunc.SetDefaultReturn(database.NewMockUserStore())
+	teams := map[string]*types.Team{
+		"own":     {ID: 1, Name: "own"},
+		"search":  {ID: 2, Name: "search"},
+		"batches": {ID: 3, Name: "batches"},
+	}
+	fakeTeams := database.NewMockTeamStore()
+	fakeTeams.GetTeamByNameFunc.SetDefaultHook(func(_ context.Context, name string) (*types.Team, error) {
+		if team, ok := teams[name]; ok {
+			return team, nil
+		}
+		return nil, database.TeamNotFoundError{}
+	})
+	db.TeamsFunc.SetDefaultReturn(fakeTeams)
+	ctx := context.Background()
+	schema, err := graphqlbackend.NewSchema(db, nil, nil, []graphqlbackend.OptionalResolver{{OwnResolver: resolvers.NewWithService(db, nil, nil, logtest.NoOp(t))}})
+	require.NoError(t, err)
+	graphqlbackend.RunTest(t, &graphqlbackend.Test{
+		Schema:  schema,
+		Context: ctx,
+		Query: `
+			query GetInstanceTeamCoverage {
+				instanceOwnershipStats {
+					teamCoverage(first: 5) {
+						team {
+							name
+						}
+						ownedFiles
+					}
+				}
+			}`,
+		ExpectedResult: `
+			{
+				"instanceOwnershipStats": {
+					"teamCoverage": [
+						{"team": {"name": "own"}, "ownedFiles": 12},
+						{"team": {"name": "search"}, "ownedFiles": 5}
+					]
+				}
+			}`,
+	})
+}
+
 func createTeam(t *testing.T, ctx context.Context, db database.DB, teamName string) *types.Team {
 	t.Helper()
 	team, err := db.Teams().CreateTeam(ctx, &types.Team{Name: teamName})
@@ -9,18 +9,33 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exports"
 )
 
+func LoadConfig() {
+	exports.UploadStoreConfigInst.Load()
+}
+
 // Init initializes the given enterpriseServices to include the required
-// enterprise jobs for search.
+// enterprise jobs for search, including symbol search backed by precise code
+// intelligence indexes, and the handler serving search exports.
 func Init(
 	ctx context.Context,
 	observationCtx *observation.Context,
-	_ database.DB,
-	_ codeintel.Services,
+	db database.DB,
+	codeIntelServices codeintel.Services,
 	_ conftypes.UnifiedWatchable,
 	enterpriseServices *enterprise.Services,
 ) error {
-	enterpriseServices.EnterpriseSearchJobs = enterprisesearch.NewEnterpriseSearchJobs()
+	enterpriseServices.EnterpriseSearchJobs = enterprisesearch.NewEnterpriseSearchJobsWithPreciseSymbols(codeIntelServices.CodenavService)
+
+	if err := exports.UploadStoreConfigInst.Validate(); err != nil {
+		return err
+	}
+	uploadStore, err := exports.NewUploadStore(ctx, observationCtx, exports.UploadStoreConfigInst)
+	if err != nil {
+		return err
+	}
+	enterpriseServices.SearchExportDownloadHandler = exports.NewDownloadHandler(observationCtx.Logger.Scoped("searchExports", "serves search exports"), db, uploadStore)
 	return nil
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
@@ -81,6 +82,19 @@ func (r *Resolver) CreateSearchContext(ctx context.Context, args graphqlbackend.
 		}
 	}
 
+	var includedSearchContextIDs []int64
+	if args.SearchContext.IncludedSearchContexts != nil {
+		includedSearchContextIDs, err = r.searchContextIDsFromInputArgs(ctx, *args.SearchContext.IncludedSearchContexts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var repositoryQueries []string
+	if args.SearchContext.RepositoryQueries != nil {
+		repositoryQueries = *args.SearchContext.RepositoryQueries
+	}
+
 	searchContext, err := searchcontexts.CreateSearchContextWithRepositoryRevisions(
 		ctx,
 		r.db,
@@ -91,6 +105,9 @@ func (r *Resolver) CreateSearchContext(ctx context.Context, args graphqlbackend.
 			NamespaceUserID: namespaceUserID,
 			NamespaceOrgID:  namespaceOrgID,
 			Query:           args.SearchContext.Query,
+
+			IncludedSearchContextIDs: includedSearchContextIDs,
+			RepositoryQueries:        repositoryQueries,
 		},
 		repositoryRevisions,
 	)
@@ -124,6 +141,15 @@ func (r *Resolver) UpdateSearchContext(ctx context.Context, args graphqlbackend.
 	updated.Description = args.SearchContext.Description
 	updated.Public = args.SearchContext.Public
 	updated.Query = args.SearchContext.Query
+	if args.SearchContext.IncludedSearchContexts != nil {
+		updated.IncludedSearchContextIDs, err = r.searchContextIDsFromInputArgs(ctx, *args.SearchContext.IncludedSearchContexts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if args.SearchContext.RepositoryQueries != nil {
+		updated.RepositoryQueries = *args.SearchContext.RepositoryQueries
+	}
 
 	searchContext, err := searchcontexts.UpdateSearchContextWithRepositoryRevisions(
 		ctx,
@@ -169,6 +195,25 @@ func (r *Resolver) repositoryRevisionsFromInputArgs(ctx context.Context, args []
 	return repositoryRevisions, nil
 }
 
+func (r *Resolver) searchContextIDsFromInputArgs(ctx context.Context, ids []graphql.ID) ([]int64, error) {
+	searchContextIDs := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		searchContextSpec, err := unmarshalSearchContextID(id)
+		if err != nil {
+			return nil, err
+		}
+		searchContext, err := searchcontexts.ResolveSearchContextSpec(ctx, r.db, searchContextSpec)
+		if err != nil {
+			return nil, err
+		}
+		if searchcontexts.IsAutoDefinedSearchContext(searchContext) {
+			return nil, errors.Errorf("cannot include auto-defined search context %q", searchContextSpec)
+		}
+		searchContextIDs = append(searchContextIDs, searchContext.ID)
+	}
+	return searchContextIDs, nil
+}
+
 func (r *Resolver) DeleteSearchContext(ctx context.Context, args graphqlbackend.DeleteSearchContextArgs) (*graphqlbackend.EmptyResponse, error) {
 	searchContextSpec, err := unmarshalSearchContextID(args.ID)
 	if err != nil {
@@ -350,14 +395,17 @@ func (r *Resolver) SearchContexts(ctx context.Context, args *graphqlbackend.List
 		OrderByDescending: args.Descending,
 	}
 
+	// Search context listings tolerate stale results and may be served by a read replica.
+	replicaCtx := basestore.WithStaleReads(ctx)
+
 	searchContextsStore := r.db.SearchContexts()
 	pageOpts := database.ListSearchContextsPageOptions{First: newArgs.First, After: afterCursor}
-	searchContexts, err := searchContextsStore.ListSearchContexts(ctx, pageOpts, opts)
+	searchContexts, err := searchContextsStore.ListSearchContexts(replicaCtx, pageOpts, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	count, err := searchContextsStore.CountSearchContexts(ctx, opts)
+	count, err := searchContextsStore.CountSearchContexts(replicaCtx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -505,6 +553,29 @@ func (r *searchContextResolver) Repositories(ctx context.Context) ([]graphqlback
 	return searchContextRepositories, nil
 }
 
+func (r *searchContextResolver) IncludedSearchContexts(ctx context.Context) ([]graphqlbackend.SearchContextResolver, error) {
+	includedSearchContexts := make([]graphqlbackend.SearchContextResolver, 0, len(r.sc.IncludedSearchContextIDs))
+	for _, id := range r.sc.IncludedSearchContextIDs {
+		searchContext, err := r.db.SearchContexts().GetSearchContext(ctx, database.GetSearchContextOptions{ID: id})
+		if err != nil {
+			if err == database.ErrSearchContextNotFound {
+				// The viewer cannot see the included search context.
+				continue
+			}
+			return nil, err
+		}
+		includedSearchContexts = append(includedSearchContexts, &searchContextResolver{searchContext, r.db})
+	}
+	return includedSearchContexts, nil
+}
+
+func (r *searchContextResolver) RepositoryQueries() []string {
+	if r.sc.RepositoryQueries == nil {
+		return []string{}
+	}
+	return r.sc.RepositoryQueries
+}
+
 func (r *searchContextResolver) Query() string {
 	return r.sc.Query
 }
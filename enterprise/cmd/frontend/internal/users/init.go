@@ -0,0 +1,37 @@
+package users
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/users/dataexport"
+)
+
+func LoadConfig() {
+	dataexport.UploadStoreConfigInst.Load()
+}
+
+// Init initializes the given enterpriseServices to include the handler serving
+// user data exports and deletion reports.
+func Init(
+	ctx context.Context,
+	observationCtx *observation.Context,
+	db database.DB,
+	_ codeintel.Services,
+	_ conftypes.UnifiedWatchable,
+	enterpriseServices *enterprise.Services,
+) error {
+	if err := dataexport.UploadStoreConfigInst.Validate(); err != nil {
+		return err
+	}
+	uploadStore, err := dataexport.NewUploadStore(ctx, observationCtx, dataexport.UploadStoreConfigInst)
+	if err != nil {
+		return err
+	}
+	enterpriseServices.UserDataExportDownloadHandler = dataexport.NewDownloadHandler(observationCtx.Logger.Scoped("userDataExports", "serves user data exports"), db, uploadStore)
+	return nil
+}
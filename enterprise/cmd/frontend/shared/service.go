@@ -5,6 +5,9 @@ import (
 
 	frontend_shared "github.com/sourcegraph/sourcegraph/cmd/frontend/shared"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/search"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/users"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/oobmigration/migrations"
 	"github.com/sourcegraph/sourcegraph/internal/debugserver"
 	"github.com/sourcegraph/sourcegraph/internal/env"
@@ -22,6 +25,9 @@ func (svc) Name() string { return "frontend" }
 func (svc) Configure() (env.Config, []debugserver.Endpoint) {
 	frontend_shared.CLILoadConfig()
 	codeintel.LoadConfig()
+	search.LoadConfig()
+	users.LoadConfig()
+	executorqueue.LoadConfig()
 	return nil, frontend_shared.GRPCWebUIDebugEndpoints()
 }
 
@@ -25,6 +25,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/httpserver"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/redispool/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/service"
 	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -46,6 +47,9 @@ func Main(ctx context.Context, observationCtx *observation.Context, ready servic
 
 	// Connect to databases
 	db := database.NewDB(logger, mustInitializeDB(observationCtx))
+	if err := dbstore.Register(db); err != nil {
+		return errors.Wrap(err, "failed to register postgres backed redis")
+	}
 	codeIntelDB := mustInitializeCodeIntelDB(observationCtx)
 
 	// Migrations may take a while, but after they're done we'll immediately
@@ -12,7 +12,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/notifications"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/redispubsub"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
@@ -44,6 +47,8 @@ type permsSyncerWorker struct {
 	syncer    permsSyncer
 	syncType  syncType
 	jobsStore database.PermissionSyncJobStore
+	// notifier, if set, notifies the users who scheduled failed syncs.
+	notifier *notifications.Notifier
 }
 
 // PreDequeue in our case does a nice trick of adding a predicate (WHERE clause)
@@ -72,7 +77,16 @@ func (h *permsSyncerWorker) Handle(ctx context.Context, _ log.Logger, record *da
 		log.Int("priority", int(record.Priority)),
 	)
 
-	return h.handlePermsSync(ctx, reqType, reqID, record.ID, record.NoPerms, record.InvalidateCaches)
+	err := h.handlePermsSync(ctx, reqType, reqID, record.ID, record.NoPerms, record.InvalidateCaches)
+	if err != nil && record.TriggeredByUserID != 0 && h.notifier != nil {
+		h.notifier.Notify(ctx, notifications.Notification{
+			Kind:  types.NotificationKindPermissionSync,
+			Title: fmt.Sprintf("Permission sync of %s %d failed", reqType, reqID),
+			Body:  err.Error(),
+			URL:   "/site-admin/permissions-syncs",
+		}, record.TriggeredByUserID)
+	}
+	return err
 }
 
 // handlePermsSync is effectively a sync version of `perms_syncer.syncPerms`
@@ -136,11 +150,13 @@ func MakeStore(observationCtx *observation.Context, dbHandle basestore.Transacta
 		OrderByExpression: sqlf.Sprintf("permission_sync_jobs.priority DESC, permission_sync_jobs.process_after ASC NULLS FIRST, permission_sync_jobs.id ASC"),
 		MaxNumResets:      5,
 		StalledMaxAge:     time.Second * 30,
+		OnStateChange:     redispubsub.RecordStateChangeHook("permission_sync_jobs"),
 	})
 }
 
 func MakeWorker(ctx context.Context, observationCtx *observation.Context, workerStore dbworkerstore.Store[*database.PermissionSyncJob], permsSyncer *PermsSyncer, syncType syncType, jobsStore database.PermissionSyncJobStore) *workerutil.Worker[*database.PermissionSyncJob] {
 	handler := MakePermsSyncerWorker(observationCtx, permsSyncer, syncType, jobsStore)
+	handler.notifier = notifications.NewNotifier(observationCtx.Logger, permsSyncer.db)
 	// Number of handlers depends on a type of perms sync jobs this worker processes.
 	numHandlers := 1
 	name := "repo_permissions_sync_job_worker"
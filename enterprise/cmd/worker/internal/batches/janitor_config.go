@@ -10,6 +10,7 @@ type janitorConfig struct {
 	env.BaseConfig
 
 	MetricsConfig *executorqueue.Config
+	ScalingHint   executorqueue.ScalingHint
 }
 
 var janitorConfigInst = &janitorConfig{}
@@ -17,11 +18,16 @@ var janitorConfigInst = &janitorConfig{}
 func (c *janitorConfig) Load() {
 	c.MetricsConfig = executorqueue.InitMetricsConfig()
 	c.MetricsConfig.Load()
+
+	c.ScalingHint.JobsPerExecutor = c.GetInt("EXECUTOR_BATCHES_JOBS_PER_INSTANCE_SCALING", "10", "The number of queued or processing batch spec workspaces a single executor is expected to handle, used to compute the desired number of executors.")
+	c.ScalingHint.MinExecutors = c.GetInt("EXECUTOR_BATCHES_MIN_INSTANCES", "0", "The minimum desired number of executors processing batch spec workspaces.")
+	c.ScalingHint.MaxExecutors = c.GetInt("EXECUTOR_BATCHES_MAX_INSTANCES", "0", "The maximum desired number of executors processing batch spec workspaces. Zero means no maximum.")
 }
 
 func (c *janitorConfig) Validate() error {
 	var errs error
 	errs = errors.Append(errs, c.BaseConfig.Validate())
 	errs = errors.Append(errs, c.MetricsConfig.Validate())
+	errs = errors.Append(errs, c.ScalingHint.Validate())
 	return errs
 }
@@ -61,6 +61,7 @@ func (j *janitorJob) Routines(_ context.Context, observationCtx *observation.Con
 
 	routines := []goroutine.BackgroundRoutine{
 		executorMetricsReporter,
+		executorqueue.NewScalingHintReporter("batches", janitorConfigInst.ScalingHint, workspaceExecutionStore.QueuedCount),
 
 		janitor.NewReconcilerWorkerResetter(
 			observationCtx.Logger.Scoped("ReconcilerWorkerResetter", ""),
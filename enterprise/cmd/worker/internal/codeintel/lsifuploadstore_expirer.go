@@ -34,14 +34,24 @@ func (j *lsifuploadstoreExpirer) Config() []env.Config {
 func (j *lsifuploadstoreExpirer) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
 	ctx := context.Background()
 
-	uploadStore, err := lsifuploadstore.New(ctx, observationCtx, lsifuploadstoreExpirerConfigInst.LSIFUploadStoreConfig)
+	// Aged raw upload payloads are moved into cold storage, if configured. The expirer
+	// removes objects from both tiers.
+	uploadStore, tierer, err := lsifuploadstore.NewWithTierer(ctx, observationCtx, lsifuploadstoreExpirerConfigInst.LSIFUploadStoreConfig, lsifuploadstoreExpirerConfigInst.prefix)
 	if err != nil {
 		observationCtx.Logger.Fatal("Failed to create upload store", log.Error(err))
 	}
 
-	return []goroutine.BackgroundRoutine{
+	routines := []goroutine.BackgroundRoutine{
 		uploadstore.NewExpirer(ctx, uploadStore, lsifuploadstoreExpirerConfigInst.prefix, lsifuploadstoreExpirerConfigInst.maxAge, lsifuploadstoreExpirerConfigInst.interval),
-	}, nil
+	}
+	if tierer != nil {
+		routines = append(routines, tierer)
+	}
+	if encrypted, ok := uploadStore.(*uploadstore.EncryptedStore); ok {
+		routines = append(routines, uploadstore.NewKeyRotator(ctx, encrypted, "codeintel.upload-store-key-rotator", lsifuploadstoreExpirerConfigInst.prefix, lsifuploadstoreExpirerConfigInst.keyRotationInterval))
+	}
+
+	return routines, nil
 }
 
 type lsifuploadstoreExpirerConfig struct {
@@ -50,6 +60,7 @@ type lsifuploadstoreExpirerConfig struct {
 	prefix                string
 	maxAge                time.Duration
 	interval              time.Duration
+	keyRotationInterval   time.Duration
 	LSIFUploadStoreConfig *lsifuploadstore.Config
 }
 
@@ -62,6 +73,7 @@ func (c *lsifuploadstoreExpirerConfig) Load() {
 	c.prefix = c.GetOptional("CODEINTEL_UPLOADSTORE_EXPIRER_PREFIX", "The prefix of objects to expire in the precise code intel upload bucket.")
 	c.maxAge = c.GetInterval("CODEINTEL_UPLOADSTORE_EXPIRER_MAX_AGE", "168h", "The max age of objects in the precise code intel upload bucket.")
 	c.interval = c.GetInterval("CODEINTEL_UPLOADSTORE_EXPIRER_INTERVAL", "1h", "The frequency at which to expire precise code intel upload bucket objects.")
+	c.keyRotationInterval = c.GetInterval("CODEINTEL_UPLOADSTORE_KEY_ROTATION_INTERVAL", "24h", "The frequency at which to re-encrypt the data keys of precise code intel upload bucket objects that are not encrypted with the current version of the code intel upload key.")
 }
 
 func (c *lsifuploadstoreExpirerConfig) Validate() error {
@@ -0,0 +1,67 @@
+package codeintel
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/shared/init/codeintel"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/shared/lsifuploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type rankingSignalExporterJob struct{}
+
+func NewRankingSignalExporter() job.Job {
+	return &rankingSignalExporterJob{}
+}
+
+func (j *rankingSignalExporterJob) Description() string {
+	return ""
+}
+
+func (j *rankingSignalExporterJob) Config() []env.Config {
+	return []env.Config{
+		ranking.SignalsConfigInst,
+		rankingSignalExporterConfigInst,
+	}
+}
+
+func (j *rankingSignalExporterJob) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	services, err := codeintel.InitServices(observationCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadStore, err := lsifuploadstore.New(context.Background(), observationCtx, rankingSignalExporterConfigInst.LSIFUploadStoreConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return []goroutine.BackgroundRoutine{
+		ranking.NewSignalExporter(observationCtx, services.RankingService, uploadStore),
+	}, nil
+}
+
+type rankingSignalExporterConfig struct {
+	env.BaseConfig
+
+	LSIFUploadStoreConfig *lsifuploadstore.Config
+}
+
+var rankingSignalExporterConfigInst = &rankingSignalExporterConfig{}
+
+func (c *rankingSignalExporterConfig) Load() {
+	c.LSIFUploadStoreConfig = &lsifuploadstore.Config{}
+	c.LSIFUploadStoreConfig.Load()
+}
+
+func (c *rankingSignalExporterConfig) Validate() error {
+	var errs error
+	errs = errors.Append(errs, c.BaseConfig.Validate())
+	errs = errors.Append(errs, c.LSIFUploadStoreConfig.Validate())
+	return errs
+}
@@ -0,0 +1,38 @@
+package codepolicies
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/codepolicies"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+type codePoliciesJob struct{}
+
+func NewCodePoliciesJob() job.Job {
+	return &codePoliciesJob{}
+}
+
+func (j *codePoliciesJob) Description() string {
+	return "Runs code policy queries and tracks their violations"
+}
+
+func (j *codePoliciesJob) Config() []env.Config {
+	return nil
+}
+
+func (j *codePoliciesJob) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []goroutine.BackgroundRoutine{
+		codepolicies.NewRunner(observationCtx, db, search.NewEnterpriseSearchJobs()),
+	}, nil
+}
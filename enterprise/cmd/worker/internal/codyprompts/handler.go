@@ -0,0 +1,32 @@
+package codyprompts
+
+import (
+	"context"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/completions/promptlog"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+type handler struct {
+	logger log.Logger
+	store  database.CodyPromptLogStore
+}
+
+var _ goroutine.Handler = &handler{}
+var _ goroutine.ErrorHandler = &handler{}
+
+func (h *handler) Handle(ctx context.Context) error {
+	// Records are deleted even if prompt logging has since been disabled.
+	retention := promptlog.Retention(conf.Get().CodyPromptLogging)
+	h.logger.Debug("purging cody prompt logs", log.Duration("retention", retention))
+
+	return h.store.DeleteStale(ctx, retention)
+}
+
+func (h *handler) HandleError(err error) {
+	h.logger.Error("error deleting stale cody prompt logs", log.Error(err))
+}
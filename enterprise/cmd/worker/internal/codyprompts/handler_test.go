@@ -0,0 +1,36 @@
+package codyprompts
+
+import (
+	"context"
+	"testing"
+
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestHandler(t *testing.T) {
+	t.Run("store error", func(t *testing.T) {
+		want := errors.New("error")
+		store := database.NewMockCodyPromptLogStore()
+		store.DeleteStaleFunc.SetDefaultReturn(want)
+
+		h := &handler{logger: logtest.Scoped(t), store: store}
+
+		err := h.Handle(context.Background())
+		assert.ErrorIs(t, err, want)
+		mockassert.CalledOnce(t, store.DeleteStaleFunc)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		store := database.NewMockCodyPromptLogStore()
+		h := &handler{logger: logtest.Scoped(t), store: store}
+
+		err := h.Handle(context.Background())
+		assert.Nil(t, err)
+		mockassert.CalledOnce(t, store.DeleteStaleFunc)
+	})
+}
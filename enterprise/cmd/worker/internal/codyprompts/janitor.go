@@ -0,0 +1,53 @@
+package codyprompts
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// janitor is a worker responsible for expunging the recorded Cody prompts and
+// responses that are older than the retention of the site configuration.
+type janitor struct{}
+
+var _ job.Job = &janitor{}
+
+func NewJanitor() job.Job {
+	return &janitor{}
+}
+
+func (j *janitor) Description() string {
+	return "Deletes the recorded Cody prompts and responses past their retention."
+}
+
+func (j *janitor) Config() []env.Config {
+	return nil
+}
+
+func (j *janitor) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []goroutine.BackgroundRoutine{
+		// Retention values under an hour aren't supported, so there's no point
+		// running this more frequently.
+		goroutine.NewPeriodicGoroutine(
+			context.Background(),
+			&handler{
+				logger: observationCtx.Logger.Scoped("cody-prompt-log-janitor", ""),
+				store:  db.CodyPromptLogs(keyring.Default().CodyPromptLogKey),
+			},
+			goroutine.WithName("cody.prompt-log-janitor"),
+			goroutine.WithDescription("cleans up stale Cody prompt logs"),
+			goroutine.WithInterval(1*time.Hour),
+		),
+	}, nil
+}
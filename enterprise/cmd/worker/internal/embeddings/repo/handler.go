@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/sourcegraph/log"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/embeddings/embed"
 	"github.com/sourcegraph/sourcegraph/internal/featureflag"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/notifications"
 	"github.com/sourcegraph/sourcegraph/internal/paths"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
@@ -30,6 +32,7 @@ type handler struct {
 	gitserverClient        gitserver.Client
 	contextService         embed.ContextService
 	repoEmbeddingJobsStore bgrepo.RepoEmbeddingJobsStore
+	notifier               *notifications.Notifier
 }
 
 var _ workerutil.Handler[*bgrepo.RepoEmbeddingJob] = &handler{}
@@ -49,6 +52,29 @@ var splitOptions = codeintelContext.SplitOptions{
 }
 
 func (h *handler) Handle(ctx context.Context, logger log.Logger, record *bgrepo.RepoEmbeddingJob) error {
+	err := h.handle(ctx, logger, record)
+	if err != nil && record.NumFailures+1 >= bgrepo.RepoEmbeddingJobMaxNumRetries {
+		h.notifyFailure(ctx, record, err)
+	}
+	return err
+}
+
+// notifyFailure notifies site admins of a job that failed for good.
+func (h *handler) notifyFailure(ctx context.Context, record *bgrepo.RepoEmbeddingJob, jobErr error) {
+	repoName := fmt.Sprintf("#%d", record.RepoID)
+	if repo, err := h.db.Repos().Get(ctx, record.RepoID); err == nil {
+		repoName = string(repo.Name)
+	}
+
+	h.notifier.NotifySiteAdmins(ctx, notifications.Notification{
+		Kind:  types.NotificationKindEmbeddingJob,
+		Title: fmt.Sprintf("Embedding job for repository %s failed", repoName),
+		Body:  jobErr.Error(),
+		URL:   "/site-admin/embeddings",
+	})
+}
+
+func (h *handler) handle(ctx context.Context, logger log.Logger, record *bgrepo.RepoEmbeddingJob) error {
 	embeddingsConfig := conf.GetEmbeddingsConfig(conf.Get().SiteConfig())
 	if embeddingsConfig == nil {
 		return errors.New("embeddings are not configured or disabled")
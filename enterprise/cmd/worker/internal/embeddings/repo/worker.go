@@ -15,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/notifications"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
@@ -54,6 +55,13 @@ func (s *repoEmbeddingJob) Routines(_ context.Context, observationCtx *observati
 
 	workCtx := actor.WithInternalActor(context.Background())
 	return []goroutine.BackgroundRoutine{
+		uploadstore.NewKeyRotator(
+			workCtx,
+			uploadStore,
+			"embeddings.index-key-rotator",
+			"",
+			embeddings.EmbeddingsUploadStoreConfigInst.KeyRotationInterval,
+		),
 		newRepoEmbeddingJobWorker(
 			workCtx,
 			observationCtx,
@@ -83,6 +91,7 @@ func newRepoEmbeddingJobWorker(
 		gitserverClient:        gitserverClient,
 		contextService:         contextService,
 		repoEmbeddingJobsStore: repoEmbeddingJobsStore,
+		notifier:               notifications.NewNotifier(observationCtx.Logger, db),
 	}
 	return dbworker.NewWorker[*repoembeddingsbg.RepoEmbeddingJob](ctx, workerStore, handler, workerutil.WorkerOptions{
 		Name:              "repo_embedding_job_worker",
@@ -0,0 +1,96 @@
+package executorqueue
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ScalingHint describes how many executors should be running for a queue of a
+// given depth. Auto-scalers that can't evaluate the queue size themselves, such
+// as a Kubernetes HorizontalPodAutoscaler reading from Prometheus, can use the
+// resulting number directly.
+type ScalingHint struct {
+	// JobsPerExecutor is the number of queued or processing jobs a single
+	// executor is expected to handle.
+	JobsPerExecutor int
+	// MinExecutors is the lower bound of the desired number of executors.
+	MinExecutors int
+	// MaxExecutors is the upper bound of the desired number of executors. Zero
+	// means that there is no upper bound.
+	MaxExecutors int
+}
+
+func (h ScalingHint) Validate() error {
+	if h.JobsPerExecutor <= 0 {
+		return errors.Newf("jobs per executor must be greater than zero, got %d", h.JobsPerExecutor)
+	}
+	if h.MinExecutors < 0 {
+		return errors.Newf("minimum executors must not be negative, got %d", h.MinExecutors)
+	}
+	if h.MaxExecutors != 0 && h.MaxExecutors < h.MinExecutors {
+		return errors.Newf("maximum executors (%d) must not be less than minimum executors (%d)", h.MaxExecutors, h.MinExecutors)
+	}
+	return nil
+}
+
+// DesiredExecutors returns the number of executors required to work off a queue
+// with the given number of queued and processing jobs.
+func (h ScalingHint) DesiredExecutors(queueDepth int) int {
+	desired := 0
+	if h.JobsPerExecutor > 0 {
+		desired = (queueDepth + h.JobsPerExecutor - 1) / h.JobsPerExecutor
+	}
+	if desired < h.MinExecutors {
+		desired = h.MinExecutors
+	}
+	if h.MaxExecutors > 0 && desired > h.MaxExecutors {
+		desired = h.MaxExecutors
+	}
+	return desired
+}
+
+var desiredExecutorsGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "src",
+	Name:      "executor_desired_instances",
+	Help:      "The number of executors required to work off the queue, based on the queue depth.",
+}, []string{"queue"})
+
+// NewScalingHintReporter returns a periodic background routine that exports the
+// desired number of executors for the given queue as a Prometheus gauge.
+func NewScalingHintReporter(queueName string, hint ScalingHint, countFunc func(ctx context.Context, includeProcessing bool) (int, error)) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(
+		context.Background(),
+		&scalingHintEmitter{
+			hint:      hint,
+			countFunc: countFunc,
+			gauge:     desiredExecutorsGauge.WithLabelValues(queueName),
+		},
+		goroutine.WithName("executors.scaling-hint"),
+		goroutine.WithDescription("emits the desired number of executors for auto-scaling"),
+		goroutine.WithInterval(5*time.Second),
+	)
+}
+
+type scalingHintEmitter struct {
+	hint      ScalingHint
+	countFunc func(ctx context.Context, includeProcessing bool) (int, error)
+	gauge     prometheus.Gauge
+}
+
+var _ goroutine.Handler = &scalingHintEmitter{}
+
+func (e *scalingHintEmitter) Handle(ctx context.Context) error {
+	count, err := e.countFunc(ctx, true)
+	if err != nil {
+		return errors.Wrap(err, "dbworkerstore.QueuedCount")
+	}
+
+	e.gauge.Set(float64(e.hint.DesiredExecutors(count)))
+	return nil
+}
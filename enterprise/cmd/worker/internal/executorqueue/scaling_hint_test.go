@@ -0,0 +1,48 @@
+package executorqueue
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestScalingHintDesiredExecutors(t *testing.T) {
+	for _, tc := range []struct {
+		hint       ScalingHint
+		queueDepth int
+		want       int
+	}{
+		{hint: ScalingHint{JobsPerExecutor: 10}, queueDepth: 0, want: 0},
+		{hint: ScalingHint{JobsPerExecutor: 10}, queueDepth: 1, want: 1},
+		{hint: ScalingHint{JobsPerExecutor: 10}, queueDepth: 10, want: 1},
+		{hint: ScalingHint{JobsPerExecutor: 10}, queueDepth: 11, want: 2},
+		{hint: ScalingHint{JobsPerExecutor: 10, MinExecutors: 2}, queueDepth: 0, want: 2},
+		{hint: ScalingHint{JobsPerExecutor: 10, MinExecutors: 2}, queueDepth: 35, want: 4},
+		{hint: ScalingHint{JobsPerExecutor: 10, MaxExecutors: 3}, queueDepth: 1000, want: 3},
+		{hint: ScalingHint{JobsPerExecutor: 10, MinExecutors: 1, MaxExecutors: 3}, queueDepth: 0, want: 1},
+	} {
+		t.Run(fmt.Sprintf("%+v depth=%d", tc.hint, tc.queueDepth), func(t *testing.T) {
+			if have := tc.hint.DesiredExecutors(tc.queueDepth); have != tc.want {
+				t.Errorf("unexpected desired executors. want=%d have=%d", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestScalingHintValidate(t *testing.T) {
+	for _, tc := range []struct {
+		hint    ScalingHint
+		wantErr bool
+	}{
+		{hint: ScalingHint{JobsPerExecutor: 10}},
+		{hint: ScalingHint{JobsPerExecutor: 10, MinExecutors: 2, MaxExecutors: 2}},
+		{hint: ScalingHint{JobsPerExecutor: 0}, wantErr: true},
+		{hint: ScalingHint{JobsPerExecutor: 10, MinExecutors: -1}, wantErr: true},
+		{hint: ScalingHint{JobsPerExecutor: 10, MinExecutors: 3, MaxExecutors: 2}, wantErr: true},
+	} {
+		t.Run(fmt.Sprintf("%+v", tc.hint), func(t *testing.T) {
+			if err := tc.hint.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("unexpected error. wantErr=%v err=%v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -15,6 +15,8 @@ type janitorConfig struct {
 
 	CacheCleanupInterval time.Duration
 	CacheDequeueTtl      time.Duration
+
+	SecretLeaseRevokeInterval time.Duration
 }
 
 var janitorConfigInst = &janitorConfig{}
@@ -25,4 +27,6 @@ func (c *janitorConfig) Load() {
 
 	c.CacheCleanupInterval = c.GetInterval("EXECUTORS_MULTIQUEUE_CACHE_CLEANUP_INTERVAL", executortypes.CleanupInterval.String(), "The frequency with which the multiqueue dequeue cache is cleaned up.")
 	c.CacheDequeueTtl = c.GetInterval("EXECUTORS_MULTIQUEUE_CACHE_DEQUEUE_TTL", executortypes.DequeueTtl.String(), "The duration after which a dequeue is deleted from the multiqueue dequeue cache.")
+
+	c.SecretLeaseRevokeInterval = c.GetInterval("EXECUTORS_SECRET_LEASE_REVOKE_INTERVAL", "1m", "The frequency with which leases of secrets resolved for finished executor jobs are revoked.")
 }
@@ -7,6 +7,8 @@ import (
 	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
 	executortypes "github.com/sourcegraph/sourcegraph/enterprise/internal/executor/types"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/executor/artifacts"
+	"github.com/sourcegraph/sourcegraph/internal/executor/secretmanager"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/rcache"
@@ -23,7 +25,7 @@ func (j *janitorJob) Description() string {
 }
 
 func (j *janitorJob) Config() []env.Config {
-	return []env.Config{janitorConfigInst}
+	return []env.Config{janitorConfigInst, artifacts.UploadStoreConfigInst}
 }
 
 func (j *janitorJob) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
@@ -34,6 +36,11 @@ func (j *janitorJob) Routines(_ context.Context, observationCtx *observation.Con
 
 	dequeueCache := rcache.New(executortypes.DequeueCachePrefix)
 
+	artifactsUploadStore, err := artifacts.NewUploadStore(context.Background(), observationCtx, artifacts.UploadStoreConfigInst)
+	if err != nil {
+		return nil, err
+	}
+
 	routines := []goroutine.BackgroundRoutine{
 		goroutine.NewPeriodicGoroutine(
 			context.Background(),
@@ -45,6 +52,8 @@ func (j *janitorJob) Routines(_ context.Context, observationCtx *observation.Con
 			goroutine.WithInterval(janitorConfigInst.CleanupTaskInterval),
 		),
 		NewMultiqueueCacheCleaner(executortypes.ValidQueueNames, dequeueCache, janitorConfigInst.CacheDequeueTtl, janitorConfigInst.CacheCleanupInterval),
+		artifacts.NewExpirer(context.Background(), db, artifactsUploadStore, janitorConfigInst.CleanupTaskInterval),
+		secretmanager.NewRevoker(context.Background(), db, janitorConfigInst.SecretLeaseRevokeInterval),
 	}
 
 	return routines, nil
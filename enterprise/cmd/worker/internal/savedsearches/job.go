@@ -0,0 +1,38 @@
+package savedsearches
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/savedsearches"
+)
+
+type subscriptionsJob struct{}
+
+func NewSubscriptionsJob() job.Job {
+	return &subscriptionsJob{}
+}
+
+func (j *subscriptionsJob) Description() string {
+	return "Runs saved search subscriptions and notifies subscribers of new results"
+}
+
+func (j *subscriptionsJob) Config() []env.Config {
+	return nil
+}
+
+func (j *subscriptionsJob) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []goroutine.BackgroundRoutine{
+		savedsearches.NewSubscriptionRunner(observationCtx, db, search.NewEnterpriseSearchJobs()),
+	}, nil
+}
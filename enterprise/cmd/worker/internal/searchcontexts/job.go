@@ -0,0 +1,37 @@
+package searchcontexts
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
+)
+
+type materializerJob struct{}
+
+func NewMaterializerJob() job.Job {
+	return &materializerJob{}
+}
+
+func (j *materializerJob) Description() string {
+	return "Re-resolves the repositories of search contexts composed from other search contexts and repository queries"
+}
+
+func (j *materializerJob) Config() []env.Config {
+	return nil
+}
+
+func (j *materializerJob) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []goroutine.BackgroundRoutine{
+		searchcontexts.NewMaterializer(observationCtx, db),
+	}, nil
+}
@@ -0,0 +1,41 @@
+package searchexports
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exports"
+)
+
+type searchExportsJob struct{}
+
+func NewSearchExportsJob() job.Job {
+	return &searchExportsJob{}
+}
+
+func (j *searchExportsJob) Description() string {
+	return "Runs search exports and uploads their results"
+}
+
+func (j *searchExportsJob) Config() []env.Config {
+	return []env.Config{exports.UploadStoreConfigInst}
+}
+
+func (j *searchExportsJob) Routines(ctx context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadStore, err := exports.NewUploadStore(ctx, observationCtx, exports.UploadStoreConfigInst)
+	if err != nil {
+		return nil, err
+	}
+
+	return exports.NewWorker(ctx, observationCtx, db, uploadStore, search.NewEnterpriseSearchJobs()), nil
+}
@@ -0,0 +1,120 @@
+package telemetry
+
+import (
+	"encoding/json"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// filterEvents returns the events that are in the allowlist of the site
+// configuration, if any.
+func filterEvents(events []*database.Event, allowlist []string) []*database.Event {
+	if len(allowlist) == 0 {
+		return events
+	}
+
+	allowed := make(map[string]struct{}, len(allowlist))
+	for _, name := range allowlist {
+		allowed[name] = struct{}{}
+	}
+
+	filtered := make([]*database.Event, 0, len(events))
+	for _, event := range events {
+		if _, ok := allowed[event.Name]; ok {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+// redactEvents removes the fields targeted by the given rules from the events and
+// the instance metadata, and returns the number of events that were redacted.
+func redactEvents(events []*database.Event, metadata *instanceMetadata, rules []*schema.TelemetryRedactionRule) (redacted int) {
+	for _, rule := range rules {
+		switch rule.Field {
+		case "initial_admin_email":
+			metadata.InitialAdminEmail = ""
+		case "license_key":
+			metadata.LicenseKey = ""
+		}
+	}
+
+	for _, event := range events {
+		applied := false
+		for _, rule := range rules {
+			if !ruleAppliesTo(rule, event.Name) {
+				continue
+			}
+			if redactField(event, rule) {
+				applied = true
+			}
+		}
+		if applied {
+			redacted++
+		}
+	}
+	return redacted
+}
+
+func ruleAppliesTo(rule *schema.TelemetryRedactionRule, eventName string) bool {
+	if len(rule.Events) == 0 {
+		return true
+	}
+	for _, name := range rule.Events {
+		if name == eventName {
+			return true
+		}
+	}
+	return false
+}
+
+// redactField removes the field targeted by the rule from the event, and returns
+// false if the rule targets a field of the instance metadata instead.
+func redactField(event *database.Event, rule *schema.TelemetryRedactionRule) bool {
+	switch rule.Field {
+	case "user_id":
+		event.UserID = 0
+	case "anonymous_user_id":
+		event.AnonymousUserID = ""
+	case "public_argument":
+		event.PublicArgument = redactKeys(event.PublicArgument, rule.Keys)
+	case "first_source_url":
+		event.FirstSourceURL = nil
+	case "last_source_url":
+		event.LastSourceURL = nil
+	case "referrer":
+		event.Referrer = nil
+	case "device_id":
+		event.DeviceID = nil
+	case "cohort_id":
+		event.CohortID = nil
+	case "feature_flags":
+		event.EvaluatedFlagSet = nil
+	default:
+		return false
+	}
+	return true
+}
+
+// redactKeys removes the given keys from the JSON object, or the whole object if
+// no keys are given or if it cannot be parsed.
+func redactKeys(argument json.RawMessage, keys []string) json.RawMessage {
+	if len(keys) == 0 || len(argument) == 0 {
+		return nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(argument, &object); err != nil {
+		return nil
+	}
+	for _, key := range keys {
+		delete(object, key)
+	}
+
+	redacted, err := json.Marshal(object)
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
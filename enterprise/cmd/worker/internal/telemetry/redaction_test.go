@@ -0,0 +1,78 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/featureflag"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestFilterEvents(t *testing.T) {
+	events := []*database.Event{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "a"}}
+
+	if diff := cmp.Diff(events, filterEvents(events, nil)); diff != "" {
+		t.Errorf("unexpected events without allowlist (-want +got):\n%s", diff)
+	}
+
+	want := []*database.Event{{ID: 1, Name: "a"}, {ID: 3, Name: "a"}}
+	if diff := cmp.Diff(want, filterEvents(events, []string{"a", "c"})); diff != "" {
+		t.Errorf("unexpected events with allowlist (-want +got):\n%s", diff)
+	}
+}
+
+func TestRedactEvents(t *testing.T) {
+	newEvent := func(name string) *database.Event {
+		return &database.Event{
+			Name:             name,
+			UserID:           5,
+			AnonymousUserID:  "anonymous",
+			PublicArgument:   json.RawMessage(`{"query":"secret","count":1}`),
+			EvaluatedFlagSet: featureflag.EvaluatedFlagSet{"flag": true},
+			FirstSourceURL:   pointers.Ptr("https://example.com"),
+			Referrer:         pointers.Ptr("https://example.com/referrer"),
+		}
+	}
+	events := []*database.Event{newEvent("search"), newEvent("view")}
+	metadata := instanceMetadata{SiteID: "site", LicenseKey: "license", InitialAdminEmail: "admin@example.com"}
+
+	redacted := redactEvents(events, &metadata, []*schema.TelemetryRedactionRule{
+		{Field: "anonymous_user_id"},
+		{Field: "public_argument", Events: []string{"search"}, Keys: []string{"query"}},
+		{Field: "referrer", Events: []string{"view"}},
+		{Field: "initial_admin_email"},
+	})
+	if redacted != 2 {
+		t.Errorf("unexpected number of redacted events: %d", redacted)
+	}
+
+	want := []*database.Event{
+		{
+			Name:             "search",
+			UserID:           5,
+			PublicArgument:   json.RawMessage(`{"count":1}`),
+			EvaluatedFlagSet: featureflag.EvaluatedFlagSet{"flag": true},
+			FirstSourceURL:   pointers.Ptr("https://example.com"),
+			Referrer:         pointers.Ptr("https://example.com/referrer"),
+		},
+		{
+			Name:             "view",
+			UserID:           5,
+			PublicArgument:   json.RawMessage(`{"query":"secret","count":1}`),
+			EvaluatedFlagSet: featureflag.EvaluatedFlagSet{"flag": true},
+			FirstSourceURL:   pointers.Ptr("https://example.com"),
+		},
+	}
+	if diff := cmp.Diff(want, events); diff != "" {
+		t.Errorf("unexpected redacted events (-want +got):\n%s", diff)
+	}
+
+	wantMetadata := instanceMetadata{SiteID: "site", LicenseKey: "license"}
+	if diff := cmp.Diff(wantMetadata, metadata); diff != "" {
+		t.Errorf("unexpected redacted metadata (-want +got):\n%s", diff)
+	}
+}
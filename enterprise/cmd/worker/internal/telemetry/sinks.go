@@ -0,0 +1,175 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const (
+	sinkTypePubSub = "pubsub"
+	sinkTypeS3     = "s3"
+	sinkTypeHTTPS  = "https"
+	sinkTypeNone   = "none"
+)
+
+// sink delivers batches of exported events.
+type sink interface {
+	// Name is the type of the sink, used to label delivery metrics.
+	Name() string
+	// Send delivers the given batch of events. Batches that are not delivered are
+	// sent again on the next run of the export job.
+	Send(ctx context.Context, events []*database.Event, metadata instanceMetadata) error
+}
+
+// newSink returns the sink configured in the site configuration. The pubsub sink,
+// used by default, publishes to the topic configured with environment variables.
+func (t *telemetryHandler) newSink(ctx context.Context, config *schema.TelemetryExportSink) (sink, error) {
+	sinkType := sinkTypePubSub
+	if config != nil {
+		sinkType = config.Type
+	}
+
+	switch sinkType {
+	case sinkTypePubSub:
+		topicConfig, err := getTopicConfig()
+		if err != nil {
+			return nil, errors.Wrap(err, "getTopicConfig")
+		}
+		return &pubSubSink{topic: topicConfig, send: t.sendEventsCallback}, nil
+
+	case sinkTypeS3:
+		if config.Bucket == "" {
+			return nil, errors.New("missing bucket for the s3 sink")
+		}
+		region := config.Region
+		if region == "" {
+			region = "us-east-1"
+		}
+		store, err := uploadstore.CreateLazy(ctx, uploadstore.Config{
+			Backend: "s3",
+			Bucket:  config.Bucket,
+			S3: uploadstore.S3Config{
+				Region:          region,
+				Endpoint:        config.Endpoint,
+				UsePathStyle:    config.Endpoint != "",
+				AccessKeyID:     config.AccessKeyID,
+				SecretAccessKey: config.SecretAccessKey,
+			},
+		}, t.metrics.uploadStore)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating s3 sink")
+		}
+		prefix := config.Prefix
+		if prefix == "" {
+			prefix = "telemetry/"
+		}
+		return &s3Sink{store: store, prefix: prefix}, nil
+
+	case sinkTypeHTTPS:
+		if config.Url == "" {
+			return nil, errors.New("missing url for the https sink")
+		}
+		return &httpsSink{doer: httpcli.ExternalDoer, url: config.Url, authorization: config.Authorization}, nil
+
+	case sinkTypeNone:
+		return noneSink{}, nil
+	}
+
+	return nil, errors.Newf("unknown sink type %q", sinkType)
+}
+
+// marshalEvents returns the JSON array of events delivered by the sinks.
+func marshalEvents(events []*database.Event, metadata instanceMetadata) ([]byte, error) {
+	toSend := make([]*bigQueryEvent, 0, len(events))
+	for _, event := range events {
+		toSend = append(toSend, buildBigQueryObject(event, &metadata))
+	}
+	return json.Marshal(toSend)
+}
+
+type pubSubSink struct {
+	topic topicConfig
+	send  sendEventsCallbackFunc
+}
+
+func (s *pubSubSink) Name() string { return sinkTypePubSub }
+
+func (s *pubSubSink) Send(ctx context.Context, events []*database.Event, metadata instanceMetadata) error {
+	return s.send(ctx, events, s.topic, metadata)
+}
+
+type s3Sink struct {
+	store  uploadstore.Store
+	prefix string
+}
+
+func (s *s3Sink) Name() string { return sinkTypeS3 }
+
+// Send writes the batch to an object named after the IDs of its first and last
+// events, so that sending a batch again overwrites the same object.
+func (s *s3Sink) Send(ctx context.Context, events []*database.Event, metadata instanceMetadata) error {
+	payload, err := marshalEvents(events, metadata)
+	if err != nil {
+		return errors.Wrap(err, "json.Marshal")
+	}
+
+	key := fmt.Sprintf("%s%010d-%010d.json", s.prefix, events[0].ID, events[len(events)-1].ID)
+	if _, err := s.store.Upload(ctx, key, bytes.NewReader(payload)); err != nil {
+		return errors.Wrapf(err, "uploading %q", key)
+	}
+	return nil
+}
+
+type httpsSink struct {
+	doer          httpcli.Doer
+	url           string
+	authorization string
+}
+
+func (s *httpsSink) Name() string { return sinkTypeHTTPS }
+
+func (s *httpsSink) Send(ctx context.Context, events []*database.Event, metadata instanceMetadata) error {
+	payload, err := marshalEvents(events, metadata)
+	if err != nil {
+		return errors.Wrap(err, "json.Marshal")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.authorization != "" {
+		req.Header.Set("Authorization", s.authorization)
+	}
+
+	resp, err := s.doer.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "sending events")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("unexpected status code %d from https sink: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// noneSink discards events, so that they are not exported but do not pile up
+// either.
+type noneSink struct{}
+
+func (noneSink) Name() string { return sinkTypeNone }
+
+func (noneSink) Send(context.Context, []*database.Event, instanceMetadata) error { return nil }
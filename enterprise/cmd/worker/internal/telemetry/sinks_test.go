@@ -0,0 +1,114 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestHTTPSSink(t *testing.T) {
+	var gotAuthorization string
+	var gotEvents []*bigQueryEvent
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthorization = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &gotEvents); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	sink := &httpsSink{doer: http.DefaultClient, url: server.URL, authorization: "Bearer token"}
+	events := []*database.Event{{ID: 1, Name: "event1"}, {ID: 2, Name: "event2"}}
+
+	if err := sink.Send(context.Background(), events, instanceMetadata{SiteID: "site"}); err != nil {
+		t.Fatal(err)
+	}
+	if gotAuthorization != "Bearer token" {
+		t.Errorf("unexpected authorization header %q", gotAuthorization)
+	}
+	if len(gotEvents) != 2 || gotEvents[0].EventName != "event1" || gotEvents[1].SiteID != "site" {
+		t.Errorf("unexpected events %+v", gotEvents)
+	}
+
+	status = http.StatusInternalServerError
+	if err := sink.Send(context.Background(), events, instanceMetadata{}); err == nil {
+		t.Error("expected error for failed delivery")
+	}
+}
+
+func TestHandlerExportConfig(t *testing.T) {
+	ctx := context.Background()
+	mockEnvVars(t, true)
+
+	events := []*database.Event{{ID: 1, Name: "event1"}, {ID: 2, Name: "event2", UserID: 5}, {ID: 3, Name: "event3"}}
+
+	newHandler := func(t *testing.T, exportConfig *schema.ExportUsageTelemetry, callback sendEventsCallbackFunc) *telemetryHandler {
+		confClient.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{ExportUsageTelemetry: exportConfig}})
+		handler := mockTelemetryHandler(t, callback)
+		eventLogStore := database.NewMockEventLogStore()
+		eventLogStore.ListExportableEventsFunc.SetDefaultReturn(events, nil)
+		handler.eventLogStore = eventLogStore
+		return handler
+	}
+
+	t.Run("allowlist and redaction rules", func(t *testing.T) {
+		var got []*database.Event
+		handler := newHandler(t, &schema.ExportUsageTelemetry{
+			EventAllowlist: []string{"event2"},
+			RedactionRules: []*schema.TelemetryRedactionRule{{Field: "user_id"}},
+		}, func(ctx context.Context, events []*database.Event, config topicConfig, metadata instanceMetadata) error {
+			got = events
+			return nil
+		})
+
+		if err := handler.Handle(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].Name != "event2" || got[0].UserID != 0 {
+			t.Errorf("unexpected sent events %+v", got)
+		}
+		if history := handler.bookmarkStore.(*MockBookmarkStore).UpdateBookmarkFunc.History(); len(history) != 1 || history[0].Arg1 != 3 {
+			t.Errorf("expected bookmark to be moved past all fetched events, got %+v", history)
+		}
+	})
+
+	t.Run("none sink", func(t *testing.T) {
+		called := false
+		handler := newHandler(t, &schema.ExportUsageTelemetry{
+			Sink: &schema.TelemetryExportSink{Type: "none"},
+		}, func(ctx context.Context, events []*database.Event, config topicConfig, metadata instanceMetadata) error {
+			called = true
+			return nil
+		})
+
+		if err := handler.Handle(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if called {
+			t.Error("expected events not to be published")
+		}
+		if history := handler.bookmarkStore.(*MockBookmarkStore).UpdateBookmarkFunc.History(); len(history) != 1 || history[0].Arg1 != 3 {
+			t.Errorf("expected bookmark to be moved past all fetched events, got %+v", history)
+		}
+	})
+
+	t.Run("invalid sink", func(t *testing.T) {
+		handler := newHandler(t, &schema.ExportUsageTelemetry{
+			Sink: &schema.TelemetryExportSink{Type: "https"},
+		}, noopHandler())
+
+		if err := handler.Handle(ctx); err == nil || err.Error() != "missing url for the https sink" {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+}
@@ -2,7 +2,6 @@ package telemetry
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strconv"
 	"time"
@@ -34,6 +33,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 type telemetryJob struct{}
@@ -147,10 +148,43 @@ func newHandlerMetrics(observationCtx *observation.Context) *handlerMetrics {
 			Metrics:           redM,
 		})
 	}
+	deliveredEvents := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "src",
+		Name:      "telemetry_export_delivered_events_total",
+		Help:      "Number of events delivered to the telemetry export sink.",
+	}, []string{"sink"})
+	failedBatches := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "src",
+		Name:      "telemetry_export_failed_batches_total",
+		Help:      "Number of batches of events the telemetry export sink failed to deliver.",
+	}, []string{"sink"})
+	droppedEvents := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "src",
+		Name:      "telemetry_export_dropped_events_total",
+		Help:      "Number of events not exported because of the event allowlist or the none sink.",
+	}, []string{"sink"})
+	redactedEvents := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "src",
+		Name:      "telemetry_export_redacted_events_total",
+		Help:      "Number of exported events redaction rules removed fields from.",
+	})
+	lastDelivery := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "src",
+		Name:      "telemetry_export_last_delivery_timestamp_seconds",
+		Help:      "Unix timestamp of the last batch of events delivered to the telemetry export sink.",
+	}, []string{"sink"})
+	observationCtx.Registerer.MustRegister(deliveredEvents, failedBatches, droppedEvents, redactedEvents, lastDelivery)
+
 	return &handlerMetrics{
-		sendEvents:  op("SendEvents"),
-		fetchEvents: op("FetchEvents"),
-		handler:     op("Handler"),
+		sendEvents:      op("SendEvents"),
+		fetchEvents:     op("FetchEvents"),
+		handler:         op("Handler"),
+		deliveredEvents: deliveredEvents,
+		failedBatches:   failedBatches,
+		droppedEvents:   droppedEvents,
+		redactedEvents:  redactedEvents,
+		lastDelivery:    lastDelivery,
+		uploadStore:     uploadstore.NewOperations(observationCtx, "telemetry_export", "uploadstore"),
 	}
 }
 
@@ -158,6 +192,15 @@ type handlerMetrics struct {
 	handler     *observation.Operation
 	sendEvents  *observation.Operation
 	fetchEvents *observation.Operation
+
+	// Delivery health of the configured sink.
+	deliveredEvents *prometheus.CounterVec
+	failedBatches   *prometheus.CounterVec
+	droppedEvents   *prometheus.CounterVec
+	redactedEvents  prometheus.Counter
+	lastDelivery    *prometheus.GaugeVec
+
+	uploadStore *uploadstore.Operations
 }
 
 type telemetryHandler struct {
@@ -193,9 +236,10 @@ func (t *telemetryHandler) Handle(ctx context.Context) (err error) {
 	if !isEnabled() {
 		return disabledErr
 	}
-	topicConfig, err := getTopicConfig()
+	exportConfig := getExportConfig()
+	sink, err := t.newSink(ctx, exportConfig.Sink)
 	if err != nil {
-		return errors.Wrap(err, "getTopicConfig")
+		return err
 	}
 
 	instanceMetadata, err := getInstanceMetadata(ctx, t.globalStateStore, t.userEmailsStore)
@@ -220,27 +264,41 @@ func (t *telemetryHandler) Handle(ctx context.Context) (err error) {
 	}
 
 	maxId := int(all[len(all)-1].ID)
-	t.logger.Info("telemetryHandler executed", log.Int("event count", len(all)), log.Int("maxId", maxId))
+	t.logger.Info("telemetryHandler executed", log.Int("event count", len(all)), log.Int("maxId", maxId), log.String("sink", sink.Name()))
 
-	err = sendBatch(ctx, all, topicConfig, instanceMetadata, t.metrics, t.sendEventsCallback)
-	if err != nil {
-		return errors.Wrap(err, "sendBatch")
+	toSend := filterEvents(all, exportConfig.EventAllowlist)
+	t.metrics.droppedEvents.WithLabelValues(sink.Name()).Add(float64(len(all) - len(toSend)))
+	t.metrics.redactedEvents.Add(float64(redactEvents(toSend, &instanceMetadata, exportConfig.RedactionRules)))
+
+	if len(toSend) > 0 {
+		err = sendBatch(ctx, toSend, instanceMetadata, t.metrics, sink)
+		if err != nil {
+			return errors.Wrap(err, "sendBatch")
+		}
 	}
 
 	return t.bookmarkStore.UpdateBookmark(ctx, maxId)
 }
 
-// sendBatch wraps the send events callback in a metric
-func sendBatch(ctx context.Context, events []*database.Event, topicConfig topicConfig, metadata instanceMetadata, metrics *handlerMetrics, callback sendEventsCallbackFunc) (err error) {
+// sendBatch wraps the delivery of events to the sink in metrics
+func sendBatch(ctx context.Context, events []*database.Event, metadata instanceMetadata, metrics *handlerMetrics, sink sink) (err error) {
 	ctx, _, endObservation := metrics.sendEvents.With(ctx, &err, observation.Args{})
 	sentCount := 0
 	defer func() { endObservation(float64(sentCount), observation.Args{}) }()
 
-	err = callback(ctx, events, topicConfig, metadata)
+	err = sink.Send(ctx, events, metadata)
 	if err != nil {
+		metrics.failedBatches.WithLabelValues(sink.Name()).Inc()
 		return err
 	}
 	sentCount = len(events)
+
+	if sink.Name() == sinkTypeNone {
+		metrics.droppedEvents.WithLabelValues(sink.Name()).Add(float64(len(events)))
+	} else {
+		metrics.deliveredEvents.WithLabelValues(sink.Name()).Add(float64(len(events)))
+		metrics.lastDelivery.WithLabelValues(sink.Name()).SetToCurrentTime()
+	}
 	return nil
 }
 
@@ -259,6 +317,16 @@ func isEnabled() bool {
 	return enabled
 }
 
+// getExportConfig returns the export settings of the site configuration, which are
+// read on every run so that changes apply without restarting the worker.
+func getExportConfig() *schema.ExportUsageTelemetry {
+	config := confClient.Get()
+	if config == nil || config.ExportUsageTelemetry == nil {
+		return &schema.ExportUsageTelemetry{}
+	}
+	return config.ExportUsageTelemetry
+}
+
 func getBatchSize() int {
 	config := confClient.Get()
 	if config == nil || config.ExportUsageTelemetry == nil || config.ExportUsageTelemetry.BatchSize <= 0 {
@@ -336,13 +404,7 @@ func sendEvents(ctx context.Context, events []*database.Event, config topicConfi
 	}
 	defer client.Close()
 
-	var toSend []*bigQueryEvent
-	for _, event := range events {
-		pubsubEvent := buildBigQueryObject(event, &metadata)
-		toSend = append(toSend, pubsubEvent)
-	}
-
-	marshal, err := json.Marshal(toSend)
+	marshal, err := marshalEvents(events, metadata)
 	if err != nil {
 		return errors.Wrap(err, "json.Marshal")
 	}
@@ -16,11 +16,16 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/batches"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/codeintel"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/codemonitors"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/codepolicies"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/codyprompts"
 	repoembeddings "github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/embeddings/repo"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/executormultiqueue"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/executors"
 	workerinsights "github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/insights"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/permissions"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/savedsearches"
+	workersearchcontexts "github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/searchcontexts"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/searchexports"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/telemetry"
 	eiauthz "github.com/sourcegraph/sourcegraph/enterprise/internal/authz"
 	srp "github.com/sourcegraph/sourcegraph/enterprise/internal/authz/subrepoperms"
@@ -45,6 +50,10 @@ var additionalJobs = map[string]job.Job{
 	"executors-metricsserver":               executors.NewMetricsServerJob(),
 	"executors-multiqueue-metrics-reporter": executormultiqueue.NewMultiqueueMetricsReporterJob(),
 	"codemonitors-job":                      codemonitors.NewCodeMonitorJob(),
+	"code-policies":                         codepolicies.NewCodePoliciesJob(),
+	"saved-search-subscriptions":            savedsearches.NewSubscriptionsJob(),
+	"search-exports":                        searchexports.NewSearchExportsJob(),
+	"search-context-materializer":           workersearchcontexts.NewMaterializerJob(),
 	"bitbucket-project-permissions":         permissions.NewBitbucketProjectPermissionsJob(),
 	"permission-sync-job-cleaner":           permissions.NewPermissionSyncJobCleaner(),
 	"permission-sync-job-scheduler":         permissions.NewPermissionSyncJobScheduler(),
@@ -60,6 +69,7 @@ var additionalJobs = map[string]job.Job{
 	"codeintel-upload-expirer":                    codeintel.NewUploadExpirerJob(),
 	"codeintel-upload-janitor":                    codeintel.NewUploadJanitorJob(),
 	"codeintel-ranking-file-reference-counter":    codeintel.NewRankingFileReferenceCounter(),
+	"codeintel-ranking-signal-exporter":           codeintel.NewRankingSignalExporter(),
 	"codeintel-uploadstore-expirer":               codeintel.NewPreciseCodeIntelUploadExpirer(),
 	"codeintel-crates-syncer":                     codeintel.NewCratesSyncerJob(),
 	"codeintel-sentinel-cve-scanner":              codeintel.NewSentinelCVEScannerJob(),
@@ -73,6 +83,8 @@ var additionalJobs = map[string]job.Job{
 
 	"own-repo-indexing-queue": own.NewOwnRepoIndexingQueue(),
 
+	"cody-prompt-log-janitor": codyprompts.NewJanitor(),
+
 	"github-apps-installation-validation-job": githubapps.NewGitHubApsInstallationJob(),
 }
 
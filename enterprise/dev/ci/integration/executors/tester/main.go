@@ -77,7 +77,7 @@ func main() {
 		PreExistingCacheEntries: map[string]execution.AfterStepResult{},
 		BatchSpecInput:          batchSpec,
 		ExpectedCacheEntries: map[string]execution.AfterStepResult{
-			"wHcoEItqNkdJj9k1-1sRCQ-step-0": {
+			"YHy1wSXmfDNUmI2m2sgxXQ-step-0": {
 				Version: 2,
 				Stdout:  "Hello World\n",
 				Diff:    []byte(expectedDiff),
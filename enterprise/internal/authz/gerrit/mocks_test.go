@@ -22,6 +22,9 @@ type MockGerritClient struct {
 	// AbandonChangeFunc is an instance of a mock function object
 	// controlling the behavior of the method AbandonChange.
 	AbandonChangeFunc *GerritClientAbandonChangeFunc
+	// AddReviewerFunc is an instance of a mock function object controlling
+	// the behavior of the method AddReviewer.
+	AddReviewerFunc *GerritClientAddReviewerFunc
 	// AuthenticatorFunc is an instance of a mock function object
 	// controlling the behavior of the method Authenticator.
 	AuthenticatorFunc *GerritClientAuthenticatorFunc
@@ -44,6 +47,9 @@ type MockGerritClient struct {
 	// GetURLFunc is an instance of a mock function object controlling the
 	// behavior of the method GetURL.
 	GetURLFunc *GerritClientGetURLFunc
+	// ListChangesFunc is an instance of a mock function object controlling
+	// the behavior of the method ListChanges.
+	ListChangesFunc *GerritClientListChangesFunc
 	// ListProjectsFunc is an instance of a mock function object controlling
 	// the behavior of the method ListProjects.
 	ListProjectsFunc *GerritClientListProjectsFunc
@@ -82,6 +88,11 @@ func NewMockGerritClient() *MockGerritClient {
 				return
 			},
 		},
+		AddReviewerFunc: &GerritClientAddReviewerFunc{
+			defaultHook: func(context.Context, string, gerrit.AddReviewerPayload) (r0 error) {
+				return
+			},
+		},
 		AuthenticatorFunc: &GerritClientAuthenticatorFunc{
 			defaultHook: func() (r0 auth.Authenticator) {
 				return
@@ -117,6 +128,11 @@ func NewMockGerritClient() *MockGerritClient {
 				return
 			},
 		},
+		ListChangesFunc: &GerritClientListChangesFunc{
+			defaultHook: func(context.Context, gerrit.ListChangesArgs) (r0 []gerrit.Change, r1 bool, r2 error) {
+				return
+			},
+		},
 		ListProjectsFunc: &GerritClientListProjectsFunc{
 			defaultHook: func(context.Context, gerrit.ListProjectsArgs) (r0 gerrit.ListProjectsResponse, r1 bool, r2 error) {
 				return
@@ -174,6 +190,11 @@ func NewStrictMockGerritClient() *MockGerritClient {
 				panic("unexpected invocation of MockGerritClient.AbandonChange")
 			},
 		},
+		AddReviewerFunc: &GerritClientAddReviewerFunc{
+			defaultHook: func(context.Context, string, gerrit.AddReviewerPayload) error {
+				panic("unexpected invocation of MockGerritClient.AddReviewer")
+			},
+		},
 		AuthenticatorFunc: &GerritClientAuthenticatorFunc{
 			defaultHook: func() auth.Authenticator {
 				panic("unexpected invocation of MockGerritClient.Authenticator")
@@ -209,6 +230,11 @@ func NewStrictMockGerritClient() *MockGerritClient {
 				panic("unexpected invocation of MockGerritClient.GetURL")
 			},
 		},
+		ListChangesFunc: &GerritClientListChangesFunc{
+			defaultHook: func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error) {
+				panic("unexpected invocation of MockGerritClient.ListChanges")
+			},
+		},
 		ListProjectsFunc: &GerritClientListProjectsFunc{
 			defaultHook: func(context.Context, gerrit.ListProjectsArgs) (gerrit.ListProjectsResponse, bool, error) {
 				panic("unexpected invocation of MockGerritClient.ListProjects")
@@ -265,6 +291,9 @@ func NewMockGerritClientFrom(i gerrit.Client) *MockGerritClient {
 		AbandonChangeFunc: &GerritClientAbandonChangeFunc{
 			defaultHook: i.AbandonChange,
 		},
+		AddReviewerFunc: &GerritClientAddReviewerFunc{
+			defaultHook: i.AddReviewer,
+		},
 		AuthenticatorFunc: &GerritClientAuthenticatorFunc{
 			defaultHook: i.Authenticator,
 		},
@@ -286,6 +315,9 @@ func NewMockGerritClientFrom(i gerrit.Client) *MockGerritClient {
 		GetURLFunc: &GerritClientGetURLFunc{
 			defaultHook: i.GetURL,
 		},
+		ListChangesFunc: &GerritClientListChangesFunc{
+			defaultHook: i.ListChanges,
+		},
 		ListProjectsFunc: &GerritClientListProjectsFunc{
 			defaultHook: i.ListProjects,
 		},
@@ -424,6 +456,114 @@ func (c GerritClientAbandonChangeFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// GerritClientAddReviewerFunc describes the behavior when the AddReviewer
+// method of the parent MockGerritClient instance is invoked.
+type GerritClientAddReviewerFunc struct {
+	defaultHook func(context.Context, string, gerrit.AddReviewerPayload) error
+	hooks       []func(context.Context, string, gerrit.AddReviewerPayload) error
+	history     []GerritClientAddReviewerFuncCall
+	mutex       sync.Mutex
+}
+
+// AddReviewer delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGerritClient) AddReviewer(v0 context.Context, v1 string, v2 gerrit.AddReviewerPayload) error {
+	r0 := m.AddReviewerFunc.nextHook()(v0, v1, v2)
+	m.AddReviewerFunc.appendCall(GerritClientAddReviewerFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the AddReviewer method
+// of the parent MockGerritClient instance is invoked and the hook queue is
+// empty.
+func (f *GerritClientAddReviewerFunc) SetDefaultHook(hook func(context.Context, string, gerrit.AddReviewerPayload) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// AddReviewer method of the parent MockGerritClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *GerritClientAddReviewerFunc) PushHook(hook func(context.Context, string, gerrit.AddReviewerPayload) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GerritClientAddReviewerFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, string, gerrit.AddReviewerPayload) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GerritClientAddReviewerFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, string, gerrit.AddReviewerPayload) error {
+		return r0
+	})
+}
+
+func (f *GerritClientAddReviewerFunc) nextHook() func(context.Context, string, gerrit.AddReviewerPayload) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GerritClientAddReviewerFunc) appendCall(r0 GerritClientAddReviewerFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GerritClientAddReviewerFuncCall objects
+// describing the invocations of this function.
+func (f *GerritClientAddReviewerFunc) History() []GerritClientAddReviewerFuncCall {
+	f.mutex.Lock()
+	history := make([]GerritClientAddReviewerFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GerritClientAddReviewerFuncCall is an object that describes an invocation
+// of method AddReviewer on an instance of MockGerritClient.
+type GerritClientAddReviewerFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 gerrit.AddReviewerPayload
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GerritClientAddReviewerFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GerritClientAddReviewerFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // GerritClientAuthenticatorFunc describes the behavior when the
 // Authenticator method of the parent MockGerritClient instance is invoked.
 type GerritClientAuthenticatorFunc struct {
@@ -1160,6 +1300,117 @@ func (c GerritClientGetURLFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// GerritClientListChangesFunc describes the behavior when the ListChanges
+// method of the parent MockGerritClient instance is invoked.
+type GerritClientListChangesFunc struct {
+	defaultHook func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error)
+	hooks       []func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error)
+	history     []GerritClientListChangesFuncCall
+	mutex       sync.Mutex
+}
+
+// ListChanges delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGerritClient) ListChanges(v0 context.Context, v1 gerrit.ListChangesArgs) ([]gerrit.Change, bool, error) {
+	r0, r1, r2 := m.ListChangesFunc.nextHook()(v0, v1)
+	m.ListChangesFunc.appendCall(GerritClientListChangesFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the ListChanges method
+// of the parent MockGerritClient instance is invoked and the hook queue is
+// empty.
+func (f *GerritClientListChangesFunc) SetDefaultHook(hook func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListChanges method of the parent MockGerritClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *GerritClientListChangesFunc) PushHook(hook func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GerritClientListChangesFunc) SetDefaultReturn(r0 []gerrit.Change, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GerritClientListChangesFunc) PushReturn(r0 []gerrit.Change, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *GerritClientListChangesFunc) nextHook() func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GerritClientListChangesFunc) appendCall(r0 GerritClientListChangesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GerritClientListChangesFuncCall objects
+// describing the invocations of this function.
+func (f *GerritClientListChangesFunc) History() []GerritClientListChangesFuncCall {
+	f.mutex.Lock()
+	history := make([]GerritClientListChangesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GerritClientListChangesFuncCall is an object that describes an invocation
+// of method ListChanges on an instance of MockGerritClient.
+type GerritClientListChangesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 gerrit.ListChangesArgs
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []gerrit.Change
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GerritClientListChangesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GerritClientListChangesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // GerritClientListProjectsFunc describes the behavior when the ListProjects
 // method of the parent MockGerritClient instance is invoked.
 type GerritClientListProjectsFunc struct {
@@ -0,0 +1,123 @@
+package subrepoperms
+
+import (
+	"fmt"
+
+	"github.com/grafana/regexp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const (
+	pathRuleAccessAllow = "allow"
+	pathRuleAccessDeny  = "deny"
+)
+
+func init() {
+	conf.ContributeValidator(func(cfg conftypes.SiteConfigQuerier) conf.Problems {
+		if _, err := compilePathRules(siteConfigPathRules(cfg.SiteConfig())); err != nil {
+			return conf.NewSiteProblems(err.Error())
+		}
+		return nil
+	})
+}
+
+// pathRule is a path rule from the site configuration, compiled into glob
+// matchers.
+type pathRule struct {
+	// repo matches the full names of the repositories the rule applies to. A
+	// nil repo matches all repositories.
+	repo  *regexp.Regexp
+	paths []path
+	deny  bool
+	// the rule as written in the site configuration
+	original string
+}
+
+// pathRules are the path rules from the site configuration. They are compiled
+// once per configuration change and layered on top of the rules synced from
+// code hosts.
+type pathRules []pathRule
+
+// siteConfigPathRules returns the path rules in the site configuration.
+func siteConfigPathRules(c schema.SiteConfiguration) []*schema.SubRepoPermissionsPathRule {
+	if c.ExperimentalFeatures == nil || c.ExperimentalFeatures.SubRepoPermissions == nil {
+		return nil
+	}
+	return c.ExperimentalFeatures.SubRepoPermissions.PathRules
+}
+
+func compilePathRules(rules []*schema.SubRepoPermissionsPathRule) (pathRules, error) {
+	compiled := make(pathRules, 0, len(rules))
+	for _, r := range rules {
+		rule := pathRule{
+			deny:     r.Access == pathRuleAccessDeny,
+			original: fmt.Sprintf("%s %s", r.Access, r.Path),
+		}
+		if r.Access != pathRuleAccessAllow && r.Access != pathRuleAccessDeny {
+			return nil, errors.Newf("sub-repo permissions path rule %q has invalid access %q", r.Path, r.Access)
+		}
+		if r.Repository != "" {
+			// The pattern must match the whole name, so that a rule for
+			// "github.com/acme/app" does not also apply to
+			// "github.com/acme/app-secrets".
+			re, err := regexp.Compile("^(?:" + r.Repository + ")$")
+			if err != nil {
+				return nil, errors.Wrapf(err, "sub-repo permissions path rule %q has invalid repository pattern", r.Path)
+			}
+			rule.repo = re
+			rule.original = fmt.Sprintf("%s (repository %s)", rule.original, r.Repository)
+		}
+
+		paths, err := compileRule(r.Path, rule.deny)
+		if err != nil {
+			return nil, errors.Wrapf(err, "sub-repo permissions path rule %q", r.Path)
+		}
+		rule.paths = paths
+		compiled = append(compiled, rule)
+	}
+	return compiled, nil
+}
+
+// forRepo returns the rules that apply to repo.
+func (rules pathRules) forRepo(repo api.RepoName) pathRules {
+	var applicable pathRules
+	for _, rule := range rules {
+		if rule.repo == nil || rule.repo.MatchString(string(repo)) {
+			applicable = append(applicable, rule)
+		}
+	}
+	return applicable
+}
+
+// match returns the rule that decides the permissions of path, if any. Deny
+// rules take precedence over allow rules, so that paths an admin always wants
+// hidden cannot be exposed by a broader allow rule.
+func (rules pathRules) match(path string) (*pathRule, bool) {
+	var allowed *pathRule
+	for i := range rules {
+		if !rules[i].matches(path) {
+			continue
+		}
+		if rules[i].deny {
+			return &rules[i], true
+		}
+		if allowed == nil {
+			allowed = &rules[i]
+		}
+	}
+	return allowed, allowed != nil
+}
+
+func (rule pathRule) matches(path string) bool {
+	for _, p := range rule.paths {
+		if p.globPath.Match(path) {
+			return true
+		}
+	}
+	return false
+}
@@ -41,6 +41,16 @@ type SubRepoPermsClient struct {
 	group   *singleflight.Group
 	cache   *lru.Cache[int32, cachedRules]
 	enabled *atomic.Bool
+
+	pathRules *atomic.Pointer[sitePathRules]
+}
+
+// sitePathRules are the compiled path rules of the current site configuration.
+type sitePathRules struct {
+	rules pathRules
+	// err is set if the path rules in the site configuration are invalid, in which
+	// case all paths are hidden rather than exposing paths an admin wants hidden.
+	err error
 }
 
 const (
@@ -70,19 +80,24 @@ type compiledRules struct {
 // traversed in reverse, and the function returns as soon as a match is found.
 // If no match is found, None is returned.
 func (rules compiledRules) GetPermissionsForPath(path string) authz.Perms {
-	for i := len(rules.paths) - 1; i >= 0; i-- {
-		if rules.paths[i].globPath.Match(path) {
-			if rules.paths[i].exclusion {
-				return authz.None
-			}
-			return authz.Read
-		}
+	if p, ok := rules.match(path); ok && !p.exclusion {
+		return authz.Read
 	}
 
 	// Return None if no rule matches
 	return authz.None
 }
 
+// match returns the last rule matching filePath.
+func (rules compiledRules) match(filePath string) (path, bool) {
+	for i := len(rules.paths) - 1; i >= 0; i-- {
+		if rules.paths[i].globPath.Match(filePath) {
+			return rules.paths[i], true
+		}
+	}
+	return path{}, false
+}
+
 // NewSubRepoPermsClient instantiates an instance of authz.SubRepoPermsClient
 // which implements SubRepoPermissionChecker.
 //
@@ -102,9 +117,13 @@ func NewSubRepoPermsClient(permissionsGetter SubRepoPermissionsGetter) (*SubRepo
 	}
 
 	enabled := atomic.NewBool(false)
+	sitePaths := atomic.NewPointer(&sitePathRules{})
 
 	conf.Watch(func() {
 		c := conf.Get()
+		rules, err := compilePathRules(siteConfigPathRules(c.SiteConfiguration))
+		sitePaths.Store(&sitePathRules{rules: rules, err: err})
+
 		if c.ExperimentalFeatures == nil || c.ExperimentalFeatures.SubRepoPermissions == nil {
 			enabled.Store(false)
 			return
@@ -125,6 +144,7 @@ func NewSubRepoPermsClient(permissionsGetter SubRepoPermissionsGetter) (*SubRepo
 		group:             &singleflight.Group{},
 		cache:             cache,
 		enabled:           enabled,
+		pathRules:         sitePaths,
 	}, nil
 }
 
@@ -206,25 +226,64 @@ func (s *SubRepoPermsClient) FilePermissionsFunc(ctx context.Context, userID int
 		return nil, &authz.ErrUnauthenticated{}
 	}
 
+	explain, err := s.explainFunc(ctx, userID, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(path string) (authz.Perms, error) {
+		return explain(path).Perms, nil
+	}, nil
+}
+
+// ExplainPermissions returns the permissions of the given user on the given
+// content, together with the rule that decided them.
+func (s *SubRepoPermsClient) ExplainPermissions(ctx context.Context, userID int32, content authz.RepoContent) (authz.SubRepoPermissionsExplanation, error) {
+	if !s.Enabled() {
+		return authz.SubRepoPermissionsExplanation{Perms: authz.Read, Source: authz.SubRepoPermissionsSourceDisabled}, nil
+	}
+
+	if s.permissionsGetter == nil {
+		return authz.SubRepoPermissionsExplanation{}, errors.New("permissionsGetter is nil")
+	}
+
+	if userID == 0 {
+		return authz.SubRepoPermissionsExplanation{}, &authz.ErrUnauthenticated{}
+	}
+
+	explain, err := s.explainFunc(ctx, userID, content.Repo)
+	if err != nil {
+		return authz.SubRepoPermissionsExplanation{}, err
+	}
+	return explain(content.Path), nil
+}
+
+// explainFunc returns a function explaining the permissions of userID on paths
+// in repo. Path rules from the site configuration take precedence over the
+// rules synced from the code host.
+func (s *SubRepoPermsClient) explainFunc(ctx context.Context, userID int32, repo api.RepoName) (func(path string) authz.SubRepoPermissionsExplanation, error) {
+	sitePaths := s.pathRules.Load()
+	if sitePaths.err != nil {
+		return nil, errors.Wrap(sitePaths.err, "invalid sub-repo permissions path rules")
+	}
+	siteRules := sitePaths.rules.forRepo(repo)
+
 	repoRules, err := s.getCompiledRules(ctx, userID)
 	if err != nil {
 		return nil, errors.Wrap(err, "compiling match rules")
 	}
 
+	// If we make it this far it implies that we have access at the repo level.
+	// Having any empty set of rules here implies that we can access the whole repo.
+	// Repos that support sub-repo permissions will only have an entry in our
+	// repo_permissions table after all sub-repo permissions have been processed.
 	rules, rulesExist := repoRules[repo]
-	if !rulesExist {
-		// If we make it this far it implies that we have access at the repo level.
-		// Having any empty set of rules here implies that we can access the whole repo.
-		// Repos that support sub-repo permissions will only have an entry in our
-		// repo_permissions table after all sub-repo permissions have been processed.
-		return filePermissionsFuncAllRead, nil
-	}
 
-	return func(path string) (authz.Perms, error) {
+	return func(path string) authz.SubRepoPermissionsExplanation {
 		// An empty path is equivalent to repo permissions so we can assume it has
 		// already been checked at that level.
 		if path == "" {
-			return authz.Read, nil
+			return authz.SubRepoPermissionsExplanation{Perms: authz.Read, Source: authz.SubRepoPermissionsSourceNoRules}
 		}
 
 		// Prefix path with "/", otherwise suffix rules like "**/file.txt" won't match
@@ -232,9 +291,30 @@ func (s *SubRepoPermsClient) FilePermissionsFunc(ctx context.Context, userID int
 			path = "/" + path
 		}
 
+		if rule, ok := siteRules.match(path); ok {
+			perms := authz.Read
+			if rule.deny {
+				perms = authz.None
+			}
+			return authz.SubRepoPermissionsExplanation{Perms: perms, Source: authz.SubRepoPermissionsSourcePathRule, Rule: rule.original}
+		}
+
+		if !rulesExist {
+			return authz.SubRepoPermissionsExplanation{Perms: authz.Read, Source: authz.SubRepoPermissionsSourceNoRules}
+		}
+
 		// Iterate through all rules for the current path, and the final match takes
 		// preference.
-		return rules.GetPermissionsForPath(path), nil
+		p, ok := rules.match(path)
+		if !ok {
+			return authz.SubRepoPermissionsExplanation{Perms: authz.None, Source: authz.SubRepoPermissionsSourceCodeHost}
+		}
+		explanation := authz.SubRepoPermissionsExplanation{Perms: authz.Read, Source: authz.SubRepoPermissionsSourceCodeHost, Rule: p.original}
+		if p.exclusion {
+			explanation.Perms = authz.None
+			explanation.Rule = "-" + p.original
+		}
+		return explanation
 	}, nil
 }
 
@@ -271,53 +351,11 @@ func (s *SubRepoPermsClient) getCompiledRules(ctx context.Context, userID int32)
 				exclusion := strings.HasPrefix(rule, "-")
 				rule = strings.TrimPrefix(rule, "-")
 
-				if !strings.HasPrefix(rule, "/") {
-					rule = "/" + rule
-				}
-
-				g, err := glob.Compile(rule, '/')
+				rulePaths, err := compileRule(rule, exclusion)
 				if err != nil {
-					return nil, errors.Wrap(err, "building include matcher")
-				}
-
-				paths = append(paths, path{globPath: g, exclusion: exclusion, original: rule})
-
-				// Special case. Our glob package does not handle rules starting with a double
-				// wildcard correctly. For example, we would expect `/**/*.java` to match all
-				// java files, but it does not match files at the root, eg `/foo.java`. To get
-				// around this we add an extra rule to cover this case.
-				if strings.HasPrefix(rule, "/**/") {
-					trimmed := rule
-					for {
-						trimmed = strings.TrimPrefix(trimmed, "/**")
-						if strings.HasPrefix(trimmed, "/**/") {
-							// Keep trimming
-							continue
-						}
-						g, err := glob.Compile(trimmed, '/')
-						if err != nil {
-							return nil, errors.Wrap(err, "building include matcher")
-						}
-						paths = append(paths, path{globPath: g, exclusion: exclusion, original: trimmed})
-						break
-					}
-				}
-
-				// We should include all directories above an include rule so that we can browse
-				// to the included items.
-				if exclusion {
-					// Not required for an exclude rule
-					continue
-				}
-
-				dirs := expandDirs(rule)
-				for _, dir := range dirs {
-					g, err := glob.Compile(dir, '/')
-					if err != nil {
-						return nil, errors.Wrap(err, "building include matcher for dir")
-					}
-					paths = append(paths, path{globPath: g, exclusion: false, original: dir})
+					return nil, err
 				}
+				paths = append(paths, rulePaths...)
 			}
 
 			toCache.rules[repo] = compiledRules{
@@ -336,15 +374,76 @@ func (s *SubRepoPermsClient) getCompiledRules(ctx context.Context, userID int32)
 	return compiled, nil
 }
 
+// compileRule compiles a single glob rule into the paths matching it.
+func compileRule(rule string, exclusion bool) ([]path, error) {
+	if !strings.HasPrefix(rule, "/") {
+		rule = "/" + rule
+	}
+
+	g, err := glob.Compile(rule, '/')
+	if err != nil {
+		return nil, errors.Wrap(err, "building include matcher")
+	}
+
+	paths := []path{{globPath: g, exclusion: exclusion, original: rule}}
+
+	// Special case. Our glob package does not handle rules starting with a double
+	// wildcard correctly. For example, we would expect `/**/*.java` to match all
+	// java files, but it does not match files at the root, eg `/foo.java`. To get
+	// around this we add an extra rule to cover this case.
+	if strings.HasPrefix(rule, "/**/") {
+		trimmed := rule
+		for {
+			trimmed = strings.TrimPrefix(trimmed, "/**")
+			if strings.HasPrefix(trimmed, "/**/") {
+				// Keep trimming
+				continue
+			}
+			g, err := glob.Compile(trimmed, '/')
+			if err != nil {
+				return nil, errors.Wrap(err, "building include matcher")
+			}
+			paths = append(paths, path{globPath: g, exclusion: exclusion, original: trimmed})
+			break
+		}
+	}
+
+	// We should include all directories above an include rule so that we can browse
+	// to the included items.
+	if exclusion {
+		// Not required for an exclude rule
+		return paths, nil
+	}
+
+	dirs := expandDirs(rule)
+	for _, dir := range dirs {
+		g, err := glob.Compile(dir, '/')
+		if err != nil {
+			return nil, errors.Wrap(err, "building include matcher for dir")
+		}
+		paths = append(paths, path{globPath: g, exclusion: false, original: dir})
+	}
+
+	return paths, nil
+}
+
 func (s *SubRepoPermsClient) Enabled() bool {
 	return s.enabled.Load()
 }
 
 func (s *SubRepoPermsClient) EnabledForRepoID(ctx context.Context, id api.RepoID) (bool, error) {
+	// Without the repository name we cannot tell which path rules apply, so any
+	// path rule enables sub-repo permissions.
+	if len(s.pathRules.Load().rules) > 0 {
+		return true, nil
+	}
 	return s.permissionsGetter.RepoIDSupported(ctx, id)
 }
 
 func (s *SubRepoPermsClient) EnabledForRepo(ctx context.Context, repo api.RepoName) (bool, error) {
+	if len(s.pathRules.Load().rules.forRepo(repo)) > 0 {
+		return true, nil
+	}
 	return s.permissionsGetter.RepoSupported(ctx, repo)
 }
 
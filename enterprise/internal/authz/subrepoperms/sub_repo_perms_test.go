@@ -428,3 +428,140 @@ func TestSubRepoPermsPermissionsCache(t *testing.T) {
 		t.Fatal("Should have been called twice")
 	}
 }
+
+func TestSubRepoPermsPathRules(t *testing.T) {
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{
+				SubRepoPermissions: &schema.SubRepoPermissions{
+					Enabled: true,
+					PathRules: []*schema.SubRepoPermissionsPathRule{
+						{Path: "**/secrets/**", Access: "deny"},
+						{Path: "docs/**", Access: "allow"},
+						{Repository: "public/.*", Path: "**", Access: "allow"},
+						{Repository: "exact", Path: "**", Access: "allow"},
+					},
+				},
+			},
+		},
+	})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	getter := NewMockSubRepoPermissionsGetter()
+	getter.GetByUserFunc.SetDefaultReturn(map[api.RepoName]authz.SubRepoPermissions{
+		"sample": {Paths: []string{"/src/**", "-/src/internal/**"}},
+	}, nil)
+	client, err := NewSubRepoPermsClient(getter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		repo api.RepoName
+		path string
+		want authz.SubRepoPermissionsExplanation
+	}{
+		{
+			repo: "sample",
+			path: "src/main.go",
+			want: authz.SubRepoPermissionsExplanation{Perms: authz.Read, Source: authz.SubRepoPermissionsSourceCodeHost, Rule: "/src/**"},
+		},
+		{
+			repo: "sample",
+			path: "src/internal/main.go",
+			want: authz.SubRepoPermissionsExplanation{Perms: authz.None, Source: authz.SubRepoPermissionsSourceCodeHost, Rule: "-/src/internal/**"},
+		},
+		{
+			repo: "sample",
+			path: "README.md",
+			want: authz.SubRepoPermissionsExplanation{Perms: authz.None, Source: authz.SubRepoPermissionsSourceCodeHost},
+		},
+		{
+			repo: "sample",
+			path: "docs/index.md",
+			want: authz.SubRepoPermissionsExplanation{Perms: authz.Read, Source: authz.SubRepoPermissionsSourcePathRule, Rule: "allow docs/**"},
+		},
+		{
+			repo: "sample",
+			path: "src/secrets/token",
+			want: authz.SubRepoPermissionsExplanation{Perms: authz.None, Source: authz.SubRepoPermissionsSourcePathRule, Rule: "deny **/secrets/**"},
+		},
+		{
+			repo: "public/sample",
+			path: "README.md",
+			want: authz.SubRepoPermissionsExplanation{Perms: authz.Read, Source: authz.SubRepoPermissionsSourcePathRule, Rule: "allow ** (repository public/.*)"},
+		},
+		{
+			repo: "public/sample",
+			path: "docs/secrets/token",
+			want: authz.SubRepoPermissionsExplanation{Perms: authz.None, Source: authz.SubRepoPermissionsSourcePathRule, Rule: "deny **/secrets/**"},
+		},
+		{
+			repo: "other",
+			path: "README.md",
+			want: authz.SubRepoPermissionsExplanation{Perms: authz.Read, Source: authz.SubRepoPermissionsSourceNoRules},
+		},
+		{
+			repo: "exact",
+			path: "README.md",
+			want: authz.SubRepoPermissionsExplanation{Perms: authz.Read, Source: authz.SubRepoPermissionsSourcePathRule, Rule: "allow ** (repository exact)"},
+		},
+		// Repository patterns match the full repository name, so neither
+		// a prefix nor a suffix of the name is enough.
+		{
+			repo: "notpublic/sample",
+			path: "README.md",
+			want: authz.SubRepoPermissionsExplanation{Perms: authz.Read, Source: authz.SubRepoPermissionsSourceNoRules},
+		},
+		{
+			repo: "not-exact",
+			path: "README.md",
+			want: authz.SubRepoPermissionsExplanation{Perms: authz.Read, Source: authz.SubRepoPermissionsSourceNoRules},
+		},
+		{
+			repo: "exact-fork",
+			path: "README.md",
+			want: authz.SubRepoPermissionsExplanation{Perms: authz.Read, Source: authz.SubRepoPermissionsSourceNoRules},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s/%s", tc.repo, tc.path), func(t *testing.T) {
+			got, err := client.ExplainPermissions(context.Background(), 1, authz.RepoContent{Repo: tc.repo, Path: tc.path})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Fatalf("got %+v, want %+v", got, tc.want)
+			}
+
+			perms, err := client.Permissions(context.Background(), 1, authz.RepoContent{Repo: tc.repo, Path: tc.path})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if perms != tc.want.Perms {
+				t.Fatalf("got perms %v, want %v", perms, tc.want.Perms)
+			}
+		})
+	}
+
+	enabled, err := client.EnabledForRepo(context.Background(), "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !enabled {
+		t.Fatal("expected path rules to enable sub-repo permissions for all repositories")
+	}
+}
+
+func TestCompilePathRules(t *testing.T) {
+	for _, rule := range []*schema.SubRepoPermissionsPathRule{
+		{Path: "docs/**", Access: "read"},
+		{Repository: "(", Path: "docs/**", Access: "allow"},
+		{Path: "docs/[", Access: "allow"},
+	} {
+		if _, err := compilePathRules([]*schema.SubRepoPermissionsPathRule{rule}); err == nil {
+			t.Errorf("expected error for rule %+v", rule)
+		}
+	}
+}
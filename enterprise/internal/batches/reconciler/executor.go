@@ -260,6 +260,7 @@ func (e *executor) publishChangeset(ctx context.Context, asDraft bool) (afterDon
 		Body:       body,
 		BaseRef:    e.spec.BaseRef,
 		HeadRef:    e.spec.HeadRef,
+		Reviewers:  e.spec.Reviewers,
 		RemoteRepo: remoteRepo,
 		TargetRepo: e.targetRepo,
 		Changeset:  e.ch,
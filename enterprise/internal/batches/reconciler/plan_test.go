@@ -50,7 +50,7 @@ func TestDetermineReconcilerPlan(t *testing.T) {
 			name:        "draft but unsupported",
 			currentSpec: &bt.TestSpecOpts{Published: "draft"},
 			changeset: bt.TestChangesetOpts{
-				ExternalServiceType: extsvc.TypeBitbucketServer,
+				ExternalServiceType: extsvc.TypeBitbucketCloud,
 				PublicationState:    btypes.ChangesetPublicationStateUnpublished,
 			},
 			// should be a noop
@@ -104,7 +104,7 @@ func TestDetermineReconcilerPlan(t *testing.T) {
 			name:        "publish nil; draft ui state; unsupported code host",
 			currentSpec: &bt.TestSpecOpts{Published: nil},
 			changeset: bt.TestChangesetOpts{
-				ExternalServiceType: extsvc.TypeBitbucketServer,
+				ExternalServiceType: extsvc.TypeBitbucketCloud,
 				PublicationState:    btypes.ChangesetPublicationStateUnpublished,
 				UiPublicationState:  pointers.Ptr(btypes.ChangesetUiPublicationStateDraft),
 			},
@@ -9,8 +9,11 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/notifications"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 )
 
@@ -21,6 +24,9 @@ type Reconciler struct {
 	client  gitserver.Client
 	sourcer sources.Sourcer
 	store   *store.Store
+	// notifier, if set, notifies the appliers of batch changes of changesets
+	// that failed for good.
+	notifier *notifications.Notifier
 
 	// This is used to disable a time.Sleep for operationSleep so that the
 	// tests don't run slower.
@@ -29,9 +35,10 @@ type Reconciler struct {
 
 func New(client gitserver.Client, sourcer sources.Sourcer, store *store.Store) *Reconciler {
 	return &Reconciler{
-		client:  client,
-		sourcer: sourcer,
-		store:   store,
+		client:   client,
+		sourcer:  sourcer,
+		store:    store,
+		notifier: notifications.NewNotifier(log.Scoped("reconciler", "changeset reconciler"), store.DatabaseDB()),
 	}
 }
 
@@ -46,6 +53,9 @@ func (r *Reconciler) HandlerFunc() workerutil.HandlerFunc[*btypes.Changeset] {
 
 		ctx = metrics.ContextWithTask(ctx, "Batches.Reconciler")
 		afterDone, err := r.process(ctx, logger, tx, job)
+		if err != nil && (errcode.IsNonRetryable(err) || job.NumFailures+1 >= store.ReconcilerMaxNumRetries) {
+			r.notifyFailure(ctx, logger, job, err)
+		}
 
 		defer func() {
 			err = tx.Done(err)
@@ -64,6 +74,32 @@ func (r *Reconciler) HandlerFunc() workerutil.HandlerFunc[*btypes.Changeset] {
 	}
 }
 
+// notifyFailure notifies the last applier of the batch change owning the
+// changeset that the changeset failed for good.
+func (r *Reconciler) notifyFailure(ctx context.Context, logger log.Logger, cs *btypes.Changeset, reconcileErr error) {
+	if r.notifier == nil || cs.OwnedByBatchChangeID == 0 {
+		return
+	}
+
+	batchChange, err := loadBatchChange(ctx, r.store, cs.OwnedByBatchChangeID)
+	if err != nil {
+		logger.Warn("failed to load batch change to notify of failed changeset", log.Error(err))
+		return
+	}
+	ns, err := r.store.DatabaseDB().Namespaces().GetByID(ctx, batchChange.NamespaceOrgID, batchChange.NamespaceUserID)
+	if err != nil {
+		logger.Warn("failed to load namespace to notify of failed changeset", log.Error(err))
+		return
+	}
+
+	r.notifier.Notify(ctx, notifications.Notification{
+		Kind:  types.NotificationKindBatchChange,
+		Title: fmt.Sprintf("A changeset of batch change %s failed to publish or update", batchChange.Name),
+		Body:  reconcileErr.Error(),
+		URL:   batchChange.Path(ns.Name),
+	}, batchChange.LastApplierID)
+}
+
 // process is the main entry point of the reconciler and processes changesets
 // that were marked as queued in the database.
 //
@@ -99,6 +135,20 @@ func (r *Reconciler) process(ctx context.Context, logger log.Logger, tx *store.S
 
 	logger.Info("Reconciler processing changeset", log.Int64("changeset", ch.ID), log.String("operations", fmt.Sprintf("%+v", plan.Ops)))
 
+	if publishesChangeset(plan.Ops) {
+		allowed, err := reserveRolloutPublication(ctx, logger, tx, ch)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			// The rollout policy of the batch change doesn't allow publishing
+			// the changeset right now. The scheduler enqueues it again once it
+			// does.
+			logger.Info("Deferring publication of changeset due to rollout policy", log.Int64("changeset", ch.ID))
+			return nil, tx.EnqueueChangeset(ctx, ch, btypes.ReconcilerStateScheduled, btypes.ReconcilerStateProcessing)
+		}
+	}
+
 	return executePlan(
 		ctx,
 		logger,
@@ -0,0 +1,59 @@
+package reconciler
+
+import (
+	"context"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+)
+
+// publishesChangeset returns whether executing the operations creates the
+// changeset on the code host.
+func publishesChangeset(ops Operations) bool {
+	return ops.Contains(btypes.ReconcilerOperationPublish) || ops.Contains(btypes.ReconcilerOperationPublishDraft)
+}
+
+// reserveRolloutPublication checks whether the rollout policy of the batch
+// change that owns the changeset allows publishing it now, and if so, counts
+// the publication against the current window of the policy.
+//
+// The policy is locked until the transaction ends, so concurrent publications
+// can't exceed the limit. If publishing fails, the transaction is rolled back
+// and the publication isn't counted.
+func reserveRolloutPublication(ctx context.Context, logger log.Logger, tx *store.Store, ch *btypes.Changeset) (bool, error) {
+	if ch.OwnedByBatchChangeID == 0 {
+		return true, nil
+	}
+
+	policy, err := tx.GetBatchChangeRolloutPolicy(ctx, store.GetBatchChangeRolloutPolicyOpts{
+		BatchChangeID: ch.OwnedByBatchChangeID,
+		ForUpdate:     true,
+	})
+	if err == store.ErrNoResults {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if policy.Paused() {
+		return false, nil
+	}
+
+	stats, err := tx.GetBatchChangeRolloutStats(ctx, ch.OwnedByBatchChangeID)
+	if err != nil {
+		return false, err
+	}
+
+	now := tx.Clock()()
+	if reason := policy.PauseReasonFor(stats); reason != "" {
+		logger.Info("Pausing batch change rollout", log.Int64("batchChange", ch.OwnedByBatchChangeID), log.String("reason", reason))
+		policy.PausedAt = now
+		policy.PauseReason = reason
+		return false, tx.UpdateBatchChangeRolloutPolicyState(ctx, policy)
+	}
+
+	allowed := policy.TryPublish(now)
+	return allowed, tx.UpdateBatchChangeRolloutPolicyState(ctx, policy)
+}
@@ -95,6 +95,11 @@ type operations struct {
 	applyBatchChange                     *observation.Operation
 	reconcileBatchChange                 *observation.Operation
 	validateChangesetSpecs               *observation.Operation
+	setBatchChangeRolloutPolicy          *observation.Operation
+	deleteBatchChangeRolloutPolicy       *observation.Operation
+	resumeBatchChangeRollout             *observation.Operation
+	setBatchChangeApprovers              *observation.Operation
+	approveBatchSpec                     *observation.Operation
 }
 
 var (
@@ -145,6 +150,11 @@ func newOperations(observationCtx *observation.Context) *operations {
 			applyBatchChange:                     op("ApplyBatchChange"),
 			reconcileBatchChange:                 op("ReconcileBatchChange"),
 			validateChangesetSpecs:               op("ValidateChangesetSpecs"),
+			setBatchChangeRolloutPolicy:          op("SetBatchChangeRolloutPolicy"),
+			deleteBatchChangeRolloutPolicy:       op("DeleteBatchChangeRolloutPolicy"),
+			resumeBatchChangeRollout:             op("ResumeBatchChangeRollout"),
+			setBatchChangeApprovers:              op("SetBatchChangeApprovers"),
+			approveBatchSpec:                     op("ApproveBatchSpec"),
 		}
 	})
 
@@ -1333,6 +1343,12 @@ func (s *Service) CreateChangesetJobs(ctx context.Context, batchChangeID int64,
 		return bulkGroupID, err
 	}
 
+	if jobType == btypes.ChangesetJobTypePublish {
+		if err := s.checkBatchSpecApproved(ctx, batchChange.ID, batchChange.BatchSpecID); err != nil {
+			return bulkGroupID, err
+		}
+	}
+
 	// Construct list options.
 	opts := listOpts
 	opts.IDs = ids
@@ -98,6 +98,20 @@ func (s *Service) ApplyBatchChange(
 		return batchChange, nil
 	}
 
+	// Changesets of batch changes with approvers can only be published once
+	// all approvers approved the batch spec.
+	if batchChange.ID != 0 {
+		publishes, err := s.publishesChangesets(ctx, batchSpec.ID, opts.PublicationStates)
+		if err != nil {
+			return nil, err
+		}
+		if publishes {
+			if err := s.checkBatchSpecApproved(ctx, batchChange.ID, batchSpec.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// Before we write to the database in a transaction, we cancel all
 	// currently enqueued/errored-and-retryable changesets the batch change might
 	// have.
@@ -181,6 +195,15 @@ func (s *Service) ApplyBatchChange(
 		}
 	}
 
+	// Unpublished changesets of batch changes with a rollout policy are handed
+	// to the scheduler, which decides the order in which they are published.
+	if _, err := tx.GetBatchChangeRolloutPolicy(ctx, store.GetBatchChangeRolloutPolicyOpts{BatchChangeID: batchChange.ID}); err == nil {
+		scheduleForRollout(newChangesets)
+		scheduleForRollout(updatedChangesets)
+	} else if err != store.ErrNoResults {
+		return nil, err
+	}
+
 	if len(newChangesets) > 0 {
 		if err = tx.CreateChangeset(ctx, newChangesets...); err != nil {
 			return nil, err
@@ -196,6 +219,16 @@ func (s *Service) ApplyBatchChange(
 	return batchChange, nil
 }
 
+// scheduleForRollout moves the unpublished changesets that are queued for the
+// reconciler to the scheduler instead.
+func scheduleForRollout(changesets []*btypes.Changeset) {
+	for _, c := range changesets {
+		if c.PublicationState.Unpublished() && c.ReconcilerState == btypes.ReconcilerStateQueued {
+			c.ReconcilerState = btypes.ReconcilerStateScheduled
+		}
+	}
+}
+
 func (s *Service) ReconcileBatchChange(
 	ctx context.Context,
 	batchSpec *btypes.BatchSpec,
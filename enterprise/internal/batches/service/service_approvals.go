@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	sgactor "github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ErrBatchSpecNotApproved is returned when changesets of a batch change are
+// about to be published, but not all approvers of the batch change approved
+// its batch spec.
+type ErrBatchSpecNotApproved struct {
+	MissingApprovers []int32
+}
+
+func (e ErrBatchSpecNotApproved) Error() string {
+	return fmt.Sprintf("changesets can't be published before the batch spec is approved: %d approval(s) missing", len(e.MissingApprovers))
+}
+
+// ErrMustBeApprover is returned by ApproveBatchSpec when the current user is
+// not an approver of the batch change.
+var ErrMustBeApprover = errors.New("must be an approver of the batch change")
+
+// ErrCannotApproveOwnBatchSpec is returned by ApproveBatchSpec when the
+// current user created the batch spec.
+var ErrCannotApproveOwnBatchSpec = errors.New("batch specs can't be approved by their creator")
+
+// SetBatchChangeApprovers replaces the users who must approve the batch specs
+// of a batch change before its changesets can be published. Passing no users
+// disables the approval step.
+func (s *Service) SetBatchChangeApprovers(ctx context.Context, batchChangeID int64, userIDs []int32) (err error) {
+	ctx, _, endObservation := s.operations.setBatchChangeApprovers.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	if err := s.checkViewerCanAdministerBatchChange(ctx, batchChangeID); err != nil {
+		return err
+	}
+
+	userIDs = dedupeUserIDs(userIDs)
+	if err := s.store.SetBatchChangeApprovers(ctx, batchChangeID, userIDs); err != nil {
+		return err
+	}
+
+	s.logApprovalEvent(ctx, database.SecurityEventBatchChangeApproversChanged, approvalEventArgs{
+		BatchChangeID: batchChangeID,
+		Approvers:     userIDs,
+	})
+	return nil
+}
+
+// ApproveBatchSpec records the approval of a batch spec by the current user,
+// who must be an approver of the batch change the batch spec is applied to.
+func (s *Service) ApproveBatchSpec(ctx context.Context, batchSpecRandID string) (spec *btypes.BatchSpec, err error) {
+	ctx, _, endObservation := s.operations.approveBatchSpec.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	spec, err = s.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{RandID: batchSpecRandID})
+	if err != nil {
+		return nil, err
+	}
+
+	batchChange, err := s.GetBatchChangeMatchingBatchSpec(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	if batchChange == nil {
+		return nil, errors.New("batch spec doesn't belong to an existing batch change")
+	}
+
+	approvers, err := s.store.ListBatchChangeApprovers(ctx, batchChange.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only the designated approvers of the batch change can
+	// approve its batch specs, and only if someone else created them.
+	a := sgactor.FromContext(ctx)
+	if !a.IsAuthenticated() || !containsUserID(approvers, a.UID) {
+		return nil, ErrMustBeApprover
+	}
+	if spec.UserID == a.UID {
+		return nil, ErrCannotApproveOwnBatchSpec
+	}
+
+	if err := s.store.CreateBatchSpecApproval(ctx, &btypes.BatchSpecApproval{BatchSpecID: spec.ID, UserID: a.UID}); err != nil {
+		return nil, err
+	}
+
+	s.logApprovalEvent(ctx, database.SecurityEventBatchSpecApproved, approvalEventArgs{
+		BatchChangeID: batchChange.ID,
+		BatchSpecID:   spec.ID,
+	})
+	return spec, nil
+}
+
+// checkBatchSpecApproved returns ErrBatchSpecNotApproved if the batch change
+// has approvers and not all of them approved the batch spec.
+func (s *Service) checkBatchSpecApproved(ctx context.Context, batchChangeID, batchSpecID int64) error {
+	approvers, err := s.store.ListBatchChangeApprovers(ctx, batchChangeID)
+	if err != nil {
+		return err
+	}
+	if len(approvers) == 0 {
+		return nil
+	}
+
+	approvals, err := s.store.ListBatchSpecApprovals(ctx, batchSpecID)
+	if err != nil {
+		return err
+	}
+
+	if missing := btypes.MissingApprovers(approvers, approvals); len(missing) > 0 {
+		s.logApprovalEvent(ctx, database.SecurityEventBatchChangePublishDenied, approvalEventArgs{
+			BatchChangeID:    batchChangeID,
+			BatchSpecID:      batchSpecID,
+			MissingApprovers: missing,
+		})
+		return ErrBatchSpecNotApproved{MissingApprovers: missing}
+	}
+	return nil
+}
+
+// publishesChangesets returns whether applying the batch spec with the given
+// UI publication states publishes any changesets.
+func (s *Service) publishesChangesets(ctx context.Context, batchSpecID int64, uiStates UiPublicationStates) (bool, error) {
+	if uiStates.publishesAny() {
+		return true, nil
+	}
+
+	specs, _, err := s.store.ListChangesetSpecs(ctx, store.ListChangesetSpecsOpts{BatchSpecID: batchSpecID})
+	if err != nil {
+		return false, err
+	}
+	for _, spec := range specs {
+		if spec.Published.True() || spec.Published.Draft() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type approvalEventArgs struct {
+	BatchChangeID    int64   `json:"batchChangeID"`
+	BatchSpecID      int64   `json:"batchSpecID,omitempty"`
+	Approvers        []int32 `json:"approvers,omitempty"`
+	MissingApprovers []int32 `json:"missingApprovers,omitempty"`
+}
+
+func (s *Service) logApprovalEvent(ctx context.Context, name database.SecurityEventName, args approvalEventArgs) {
+	arg, _ := json.Marshal(args)
+	s.store.DatabaseDB().SecurityEventLogs().LogEvent(ctx, &database.SecurityEvent{
+		Name:      name,
+		UserID:    uint32(sgactor.FromContext(ctx).UID),
+		Argument:  arg,
+		Source:    "BACKEND",
+		Timestamp: time.Now(),
+	})
+}
+
+func dedupeUserIDs(ids []int32) []int32 {
+	seen := make(map[int32]struct{}, len(ids))
+	deduped := make([]int32, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		deduped = append(deduped, id)
+	}
+	sort.Slice(deduped, func(i, j int) bool { return deduped[i] < deduped[j] })
+	return deduped
+}
+
+func containsUserID(ids []int32, id int32) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
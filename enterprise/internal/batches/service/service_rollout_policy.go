@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// SetBatchChangeRolloutPolicy creates or updates the rollout policy of a batch
+// change.
+func (s *Service) SetBatchChangeRolloutPolicy(ctx context.Context, policy *btypes.BatchChangeRolloutPolicy) (err error) {
+	ctx, _, endObservation := s.operations.setBatchChangeRolloutPolicy.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	if err := validateRolloutPolicy(policy); err != nil {
+		return err
+	}
+
+	if err := s.checkViewerCanAdministerBatchChange(ctx, policy.BatchChangeID); err != nil {
+		return err
+	}
+
+	return s.store.UpsertBatchChangeRolloutPolicy(ctx, policy)
+}
+
+// DeleteBatchChangeRolloutPolicy deletes the rollout policy of a batch change,
+// if it has one.
+func (s *Service) DeleteBatchChangeRolloutPolicy(ctx context.Context, batchChangeID int64) (err error) {
+	ctx, _, endObservation := s.operations.deleteBatchChangeRolloutPolicy.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	if err := s.checkViewerCanAdministerBatchChange(ctx, batchChangeID); err != nil {
+		return err
+	}
+
+	return s.store.DeleteBatchChangeRolloutPolicy(ctx, batchChangeID)
+}
+
+// ResumeBatchChangeRollout resumes a rollout that was paused because the merge
+// rate or CI failure rate of its changesets crossed a threshold of the policy.
+// The thresholds are evaluated again before the next changeset is published,
+// so they need to be adjusted if the rates haven't changed in the meantime.
+func (s *Service) ResumeBatchChangeRollout(ctx context.Context, batchChangeID int64) (err error) {
+	ctx, _, endObservation := s.operations.resumeBatchChangeRollout.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	if err := s.checkViewerCanAdministerBatchChange(ctx, batchChangeID); err != nil {
+		return err
+	}
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	policy, err := tx.GetBatchChangeRolloutPolicy(ctx, store.GetBatchChangeRolloutPolicyOpts{
+		BatchChangeID: batchChangeID,
+		ForUpdate:     true,
+	})
+	if err != nil {
+		if err == store.ErrNoResults {
+			return errors.New("batch change has no rollout policy")
+		}
+		return err
+	}
+
+	if !policy.Paused() {
+		return nil
+	}
+
+	policy.PausedAt = time.Time{}
+	policy.PauseReason = ""
+	return tx.UpdateBatchChangeRolloutPolicyState(ctx, policy)
+}
+
+func (s *Service) checkViewerCanAdministerBatchChange(ctx context.Context, batchChangeID int64) error {
+	batchChange, err := s.store.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: batchChangeID})
+	if err != nil {
+		return errors.Wrap(err, "getting batch change")
+	}
+
+	// 🚨 SECURITY: Only the author of the batch change or an admin of its
+	// namespace can change how it is rolled out.
+	return s.checkViewerCanAdminister(ctx, batchChange.NamespaceOrgID, batchChange.CreatorID, false)
+}
+
+func validateRolloutPolicy(policy *btypes.BatchChangeRolloutPolicy) error {
+	var errs error
+	if policy.MaxChangesets <= 0 {
+		errs = errors.Append(errs, errors.New("the maximum number of changesets per window must be positive"))
+	}
+	if policy.Window <= 0 {
+		errs = errors.Append(errs, errors.New("the rollout window must be positive"))
+	}
+	if r := policy.MinMergeRate; r != nil && (*r < 0 || *r > 1) {
+		errs = errors.Append(errs, errors.New("the minimum merge rate must be between 0 and 1"))
+	}
+	if r := policy.MaxCIFailureRate; r != nil && (*r < 0 || *r > 1) {
+		errs = errors.Append(errs, errors.New("the maximum CI failure rate must be between 0 and 1"))
+	}
+	return errs
+}
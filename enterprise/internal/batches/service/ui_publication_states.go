@@ -40,6 +40,17 @@ func (ps *UiPublicationStates) get(id int64) *btypes.ChangesetUiPublicationState
 	return nil
 }
 
+// publishesAny returns whether any of the publication states publishes a
+// changeset.
+func (ps *UiPublicationStates) publishesAny() bool {
+	for _, value := range ps.rand {
+		if value.True() || value.Draft() {
+			return true
+		}
+	}
+	return false
+}
+
 // prepareAndValidate looks up the random changeset spec IDs, and ensures that
 // the changeset specs are included in the current rewirer mappings and are
 // eligible for a UI publication state.
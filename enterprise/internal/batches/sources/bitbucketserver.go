@@ -25,7 +25,10 @@ type BitbucketServerSource struct {
 	au     auth.Authenticator
 }
 
-var _ ForkableChangesetSource = BitbucketServerSource{}
+var (
+	_ ForkableChangesetSource = BitbucketServerSource{}
+	_ DraftChangesetSource    = BitbucketServerSource{}
+)
 
 // NewBitbucketServerSource returns a new BitbucketServerSource from the given external service.
 func NewBitbucketServerSource(ctx context.Context, svc *types.ExternalService, cf *httpcli.Factory) (*BitbucketServerSource, error) {
@@ -97,12 +100,26 @@ func (s BitbucketServerSource) ValidateAuthenticator(ctx context.Context) error
 
 // CreateChangeset creates the given *Changeset in the code host.
 func (s BitbucketServerSource) CreateChangeset(ctx context.Context, c *Changeset) (bool, error) {
+	return s.createChangeset(ctx, c, false)
+}
+
+// CreateDraftChangeset creates the given changeset on the code host in draft
+// mode. Draft pull requests are supported by Bitbucket Data Center 8.18 and
+// later.
+func (s BitbucketServerSource) CreateDraftChangeset(ctx context.Context, c *Changeset) (bool, error) {
+	return s.createChangeset(ctx, c, true)
+}
+
+func (s BitbucketServerSource) createChangeset(ctx context.Context, c *Changeset, draft bool) (bool, error) {
 	var exists bool
 
 	remoteRepo := c.RemoteRepo.Metadata.(*bitbucketserver.Repo)
 	targetRepo := c.TargetRepo.Metadata.(*bitbucketserver.Repo)
 
-	pr := &bitbucketserver.PullRequest{Title: c.Title, Description: c.Body}
+	pr := &bitbucketserver.PullRequest{Title: c.Title, Description: c.Body, Draft: draft}
+	for _, name := range c.Reviewers {
+		pr.Reviewers = append(pr.Reviewers, bitbucketserver.Reviewer{User: &bitbucketserver.User{Name: name}})
+	}
 
 	pr.ToRef.Repository.Slug = targetRepo.Slug
 	pr.ToRef.Repository.ID = targetRepo.ID
@@ -231,17 +248,57 @@ func (s BitbucketServerSource) UpdateChangeset(ctx context.Context, c *Changeset
 	update.ToRef.Repository.Slug = pr.ToRef.Repository.Slug
 	update.ToRef.Repository.Project.Key = pr.ToRef.Repository.Project.Key
 
+	updated, err := s.updatePullRequest(ctx, pr, update)
+	if err != nil {
+		return err
+	}
+
+	return c.Changeset.SetMetadata(updated)
+}
+
+// UndraftChangeset will update the Changeset on the source to be not in draft
+// mode anymore.
+func (s BitbucketServerSource) UndraftChangeset(ctx context.Context, c *Changeset) error {
+	pr, ok := c.Changeset.Metadata.(*bitbucketserver.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Server pull request")
+	}
+
+	draft := false
+	update := &bitbucketserver.UpdatePullRequestInput{
+		PullRequestID: strconv.Itoa(pr.ID),
+		Title:         pr.Title,
+		Description:   pr.Description,
+		Version:       pr.Version,
+		Reviewers:     pr.Reviewers,
+		Draft:         &draft,
+	}
+	update.ToRef.ID = pr.ToRef.ID
+	update.ToRef.Repository.Slug = pr.ToRef.Repository.Slug
+	update.ToRef.Repository.Project.Key = pr.ToRef.Repository.Project.Key
+
+	updated, err := s.updatePullRequest(ctx, pr, update)
+	if err != nil {
+		return err
+	}
+
+	return c.Changeset.SetMetadata(updated)
+}
+
+// updatePullRequest updates the pull request, retrying once with the newest
+// version of the pull request if the given version is outdated.
+func (s BitbucketServerSource) updatePullRequest(ctx context.Context, pr *bitbucketserver.PullRequest, update *bitbucketserver.UpdatePullRequestInput) (*bitbucketserver.PullRequest, error) {
 	updated, err := s.client.UpdatePullRequest(ctx, update)
 	if err != nil {
 		if !bitbucketserver.IsPullRequestOutOfDate(err) {
-			return err
+			return nil, err
 		}
 
 		// If we have an outdated version of the pull request we extract the
 		// pull request that was returned with the error...
 		newestPR, err2 := bitbucketserver.ExtractPullRequest(err)
 		if err2 != nil {
-			return errors.Wrap(err, "failed to extract pull request after receiving error")
+			return nil, errors.Wrap(err, "failed to extract pull request after receiving error")
 		}
 
 		log15.Info("Updating Bitbucket Server PR failed because it's outdated. Retrying with newer version", "ID", pr.ID, "oldVersion", pr.Version, "newestVerssion", newestPR.Version)
@@ -251,11 +308,11 @@ func (s BitbucketServerSource) UpdateChangeset(ctx context.Context, c *Changeset
 		updated, err = s.client.UpdatePullRequest(ctx, update)
 		if err != nil {
 			// If that didn't work, we bail out
-			return err
+			return nil, err
 		}
 	}
 
-	return c.Changeset.SetMetadata(updated)
+	return updated, nil
 }
 
 // ReopenChangeset reopens the *Changeset on the code host and updates the
@@ -281,15 +338,23 @@ func (s BitbucketServerSource) CreateComment(ctx context.Context, c *Changeset,
 }
 
 // MergeChangeset merges a Changeset on the code host, if in a mergeable state.
-// The squash parameter is ignored, as Bitbucket Server does not support
-// squash merges.
+// If squash is true, the squash merge strategy is used, which must be enabled
+// for the repository. Otherwise, the default merge strategy of the repository
+// is used.
 func (s BitbucketServerSource) MergeChangeset(ctx context.Context, c *Changeset, squash bool) error {
 	pr, ok := c.Changeset.Metadata.(*bitbucketserver.PullRequest)
 	if !ok {
 		return errors.New("Changeset is not a Bitbucket Server pull request")
 	}
 
-	merged, err := s.callAndRetryIfOutdated(ctx, c, s.client.MergePullRequest)
+	strategyID := ""
+	if squash {
+		strategyID = bitbucketserver.MergeStrategySquash
+	}
+
+	merged, err := s.callAndRetryIfOutdated(ctx, c, func(ctx context.Context, pr *bitbucketserver.PullRequest) error {
+		return s.client.MergePullRequestWithStrategy(ctx, pr, strategyID)
+	})
 	if err != nil {
 		if bitbucketserver.IsMergePreconditionFailedException(err) {
 			return &ChangesetNotMergeableError{ErrorMsg: err.Error()}
@@ -122,6 +122,11 @@ type Changeset struct {
 	HeadRef string
 	BaseRef string
 
+	// Reviewers are the usernames of the users to request reviews from when
+	// the changeset is created. Code hosts that don't support requesting
+	// reviews ignore them.
+	Reviewers []string
+
 	// RemoteRepo is the repository the branch will be pushed to. This must be
 	// the same as TargetRepo if forking is not in use.
 	RemoteRepo *types.Repo
@@ -103,6 +103,10 @@ func (s GerritSource) LoadChangeset(ctx context.Context, cs *Changeset) error {
 // exists, *Changeset will be populated and the return value will be true.
 func (s GerritSource) CreateChangeset(ctx context.Context, cs *Changeset) (bool, error) {
 	changeID := GenerateGerritChangeID(*cs.Changeset)
+	if err := s.addReviewers(ctx, changeID, cs); err != nil {
+		return false, err
+	}
+
 	// For Gerrit, the Change is created at `git push` time, so we just load it here to verify it
 	// was created successfully.
 	pr, err := s.client.GetChange(ctx, changeID)
@@ -131,6 +135,10 @@ func (s GerritSource) CreateDraftChangeset(ctx context.Context, cs *Changeset) (
 		return false, errors.Wrap(err, "making change WIP")
 	}
 
+	if err := s.addReviewers(ctx, changeID, cs); err != nil {
+		return false, err
+	}
+
 	pr, err := s.client.GetChange(ctx, changeID)
 	if err != nil {
 		if errcode.IsNotFound(err) {
@@ -144,6 +152,20 @@ func (s GerritSource) CreateDraftChangeset(ctx context.Context, cs *Changeset) (
 	return false, errors.Wrap(s.setChangesetMetadata(ctx, pr, cs), "setting Gerrit changeset metadata")
 }
 
+// addReviewers requests reviews on the change from the reviewers of the
+// changeset.
+func (s GerritSource) addReviewers(ctx context.Context, changeID string, cs *Changeset) error {
+	for _, reviewer := range cs.Reviewers {
+		if err := s.client.AddReviewer(ctx, changeID, gerrit.AddReviewerPayload{Reviewer: reviewer}); err != nil {
+			if errcode.IsNotFound(err) {
+				return ChangesetNotFoundError{Changeset: cs}
+			}
+			return errors.Wrap(err, "adding reviewer")
+		}
+	}
+	return nil
+}
+
 // UndraftChangeset will update the Changeset on the source to be not in draft mode anymore.
 func (s GerritSource) UndraftChangeset(ctx context.Context, cs *Changeset) error {
 	if err := s.client.SetReadyForReview(ctx, cs.ExternalID); err != nil {
@@ -195,6 +195,50 @@ func TestGerritSource_CreateChangeset(t *testing.T) {
 		assert.Nil(t, err)
 		assert.False(t, b)
 	})
+
+	t.Run("error adding reviewer", func(t *testing.T) {
+		cs, id, _ := mockGerritChangeset()
+		cs.Reviewers = []string{"alice"}
+		s, client := mockGerritSource()
+		want := errors.New("error")
+		client.AddReviewerFunc.SetDefaultHook(func(ctx context.Context, changeID string, input gerrit.AddReviewerPayload) error {
+			assert.Equal(t, changeID, id)
+			return want
+		})
+
+		b, err := s.CreateChangeset(ctx, cs)
+		assert.NotNil(t, err)
+		assert.ErrorIs(t, err, want)
+		assert.False(t, b)
+	})
+
+	t.Run("success with reviewers", func(t *testing.T) {
+		cs, id, _ := mockGerritChangeset()
+		cs.Reviewers = []string{"alice", "bob"}
+		s, client := mockGerritSource()
+
+		change := mockGerritChange(&testProject, id)
+		client.AddReviewerFunc.SetDefaultHook(func(ctx context.Context, changeID string, input gerrit.AddReviewerPayload) error {
+			assert.Equal(t, changeID, id)
+			return nil
+		})
+		client.GetURLFunc.SetDefaultReturn(&url.URL{})
+		client.GetChangeFunc.SetDefaultHook(func(ctx context.Context, changeID string) (*gerrit.Change, error) {
+			assert.Equal(t, changeID, id)
+			return change, nil
+		})
+		client.GetChangeReviewsFunc.SetDefaultReturn(&[]gerrit.Reviewer{}, nil)
+
+		b, err := s.CreateChangeset(ctx, cs)
+		assert.Nil(t, err)
+		assert.False(t, b)
+
+		var reviewers []string
+		for _, call := range client.AddReviewerFunc.History() {
+			reviewers = append(reviewers, call.Arg2.Reviewer)
+		}
+		assert.Equal(t, []string{"alice", "bob"}, reviewers)
+	})
 }
 
 func TestGerritSource_CreateDraftChangeset(t *testing.T) {
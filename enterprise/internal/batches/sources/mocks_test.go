@@ -3855,6 +3855,9 @@ type MockBitbucketCloudClient struct {
 	// AllCurrentUserEmailsFunc is an instance of a mock function object
 	// controlling the behavior of the method AllCurrentUserEmails.
 	AllCurrentUserEmailsFunc *BitbucketCloudClientAllCurrentUserEmailsFunc
+	// AddReviewerFunc is an instance of a mock function object controlling
+	// the behavior of the method AddReviewer.
+	AddReviewerFunc *GerritClientAddReviewerFunc
 	// AuthenticatorFunc is an instance of a mock function object
 	// controlling the behavior of the method Authenticator.
 	AuthenticatorFunc *BitbucketCloudClientAuthenticatorFunc
@@ -8520,6 +8523,9 @@ type MockGerritClient struct {
 	// GetURLFunc is an instance of a mock function object controlling the
 	// behavior of the method GetURL.
 	GetURLFunc *GerritClientGetURLFunc
+	// ListChangesFunc is an instance of a mock function object controlling
+	// the behavior of the method ListChanges.
+	ListChangesFunc *GerritClientListChangesFunc
 	// ListProjectsFunc is an instance of a mock function object controlling
 	// the behavior of the method ListProjects.
 	ListProjectsFunc *GerritClientListProjectsFunc
@@ -8558,6 +8564,11 @@ func NewMockGerritClient() *MockGerritClient {
 				return
 			},
 		},
+		AddReviewerFunc: &GerritClientAddReviewerFunc{
+			defaultHook: func(context.Context, string, gerrit.AddReviewerPayload) (r0 error) {
+				return
+			},
+		},
 		AuthenticatorFunc: &GerritClientAuthenticatorFunc{
 			defaultHook: func() (r0 auth.Authenticator) {
 				return
@@ -8593,6 +8604,11 @@ func NewMockGerritClient() *MockGerritClient {
 				return
 			},
 		},
+		ListChangesFunc: &GerritClientListChangesFunc{
+			defaultHook: func(context.Context, gerrit.ListChangesArgs) (r0 []gerrit.Change, r1 bool, r2 error) {
+				return
+			},
+		},
 		ListProjectsFunc: &GerritClientListProjectsFunc{
 			defaultHook: func(context.Context, gerrit.ListProjectsArgs) (r0 gerrit.ListProjectsResponse, r1 bool, r2 error) {
 				return
@@ -8650,6 +8666,11 @@ func NewStrictMockGerritClient() *MockGerritClient {
 				panic("unexpected invocation of MockGerritClient.AbandonChange")
 			},
 		},
+		AddReviewerFunc: &GerritClientAddReviewerFunc{
+			defaultHook: func(context.Context, string, gerrit.AddReviewerPayload) error {
+				panic("unexpected invocation of MockGerritClient.AddReviewer")
+			},
+		},
 		AuthenticatorFunc: &GerritClientAuthenticatorFunc{
 			defaultHook: func() auth.Authenticator {
 				panic("unexpected invocation of MockGerritClient.Authenticator")
@@ -8685,6 +8706,11 @@ func NewStrictMockGerritClient() *MockGerritClient {
 				panic("unexpected invocation of MockGerritClient.GetURL")
 			},
 		},
+		ListChangesFunc: &GerritClientListChangesFunc{
+			defaultHook: func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error) {
+				panic("unexpected invocation of MockGerritClient.ListChanges")
+			},
+		},
 		ListProjectsFunc: &GerritClientListProjectsFunc{
 			defaultHook: func(context.Context, gerrit.ListProjectsArgs) (gerrit.ListProjectsResponse, bool, error) {
 				panic("unexpected invocation of MockGerritClient.ListProjects")
@@ -8741,6 +8767,9 @@ func NewMockGerritClientFrom(i gerrit.Client) *MockGerritClient {
 		AbandonChangeFunc: &GerritClientAbandonChangeFunc{
 			defaultHook: i.AbandonChange,
 		},
+		AddReviewerFunc: &GerritClientAddReviewerFunc{
+			defaultHook: i.AddReviewer,
+		},
 		AuthenticatorFunc: &GerritClientAuthenticatorFunc{
 			defaultHook: i.Authenticator,
 		},
@@ -8762,6 +8791,9 @@ func NewMockGerritClientFrom(i gerrit.Client) *MockGerritClient {
 		GetURLFunc: &GerritClientGetURLFunc{
 			defaultHook: i.GetURL,
 		},
+		ListChangesFunc: &GerritClientListChangesFunc{
+			defaultHook: i.ListChanges,
+		},
 		ListProjectsFunc: &GerritClientListProjectsFunc{
 			defaultHook: i.ListProjects,
 		},
@@ -8900,6 +8932,114 @@ func (c GerritClientAbandonChangeFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// GerritClientAddReviewerFunc describes the behavior when the AddReviewer
+// method of the parent MockGerritClient instance is invoked.
+type GerritClientAddReviewerFunc struct {
+	defaultHook func(context.Context, string, gerrit.AddReviewerPayload) error
+	hooks       []func(context.Context, string, gerrit.AddReviewerPayload) error
+	history     []GerritClientAddReviewerFuncCall
+	mutex       sync.Mutex
+}
+
+// AddReviewer delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGerritClient) AddReviewer(v0 context.Context, v1 string, v2 gerrit.AddReviewerPayload) error {
+	r0 := m.AddReviewerFunc.nextHook()(v0, v1, v2)
+	m.AddReviewerFunc.appendCall(GerritClientAddReviewerFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the AddReviewer method
+// of the parent MockGerritClient instance is invoked and the hook queue is
+// empty.
+func (f *GerritClientAddReviewerFunc) SetDefaultHook(hook func(context.Context, string, gerrit.AddReviewerPayload) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// AddReviewer method of the parent MockGerritClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *GerritClientAddReviewerFunc) PushHook(hook func(context.Context, string, gerrit.AddReviewerPayload) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GerritClientAddReviewerFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, string, gerrit.AddReviewerPayload) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GerritClientAddReviewerFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, string, gerrit.AddReviewerPayload) error {
+		return r0
+	})
+}
+
+func (f *GerritClientAddReviewerFunc) nextHook() func(context.Context, string, gerrit.AddReviewerPayload) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GerritClientAddReviewerFunc) appendCall(r0 GerritClientAddReviewerFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GerritClientAddReviewerFuncCall objects
+// describing the invocations of this function.
+func (f *GerritClientAddReviewerFunc) History() []GerritClientAddReviewerFuncCall {
+	f.mutex.Lock()
+	history := make([]GerritClientAddReviewerFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GerritClientAddReviewerFuncCall is an object that describes an invocation
+// of method AddReviewer on an instance of MockGerritClient.
+type GerritClientAddReviewerFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 gerrit.AddReviewerPayload
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GerritClientAddReviewerFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GerritClientAddReviewerFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // GerritClientAuthenticatorFunc describes the behavior when the
 // Authenticator method of the parent MockGerritClient instance is invoked.
 type GerritClientAuthenticatorFunc struct {
@@ -9636,6 +9776,117 @@ func (c GerritClientGetURLFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// GerritClientListChangesFunc describes the behavior when the ListChanges
+// method of the parent MockGerritClient instance is invoked.
+type GerritClientListChangesFunc struct {
+	defaultHook func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error)
+	hooks       []func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error)
+	history     []GerritClientListChangesFuncCall
+	mutex       sync.Mutex
+}
+
+// ListChanges delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGerritClient) ListChanges(v0 context.Context, v1 gerrit.ListChangesArgs) ([]gerrit.Change, bool, error) {
+	r0, r1, r2 := m.ListChangesFunc.nextHook()(v0, v1)
+	m.ListChangesFunc.appendCall(GerritClientListChangesFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the ListChanges method
+// of the parent MockGerritClient instance is invoked and the hook queue is
+// empty.
+func (f *GerritClientListChangesFunc) SetDefaultHook(hook func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListChanges method of the parent MockGerritClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *GerritClientListChangesFunc) PushHook(hook func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GerritClientListChangesFunc) SetDefaultReturn(r0 []gerrit.Change, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GerritClientListChangesFunc) PushReturn(r0 []gerrit.Change, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *GerritClientListChangesFunc) nextHook() func(context.Context, gerrit.ListChangesArgs) ([]gerrit.Change, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GerritClientListChangesFunc) appendCall(r0 GerritClientListChangesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GerritClientListChangesFuncCall objects
+// describing the invocations of this function.
+func (f *GerritClientListChangesFunc) History() []GerritClientListChangesFuncCall {
+	f.mutex.Lock()
+	history := make([]GerritClientListChangesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GerritClientListChangesFuncCall is an object that describes an invocation
+// of method ListChanges on an instance of MockGerritClient.
+type GerritClientListChangesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 gerrit.ListChangesArgs
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []gerrit.Change
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GerritClientListChangesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GerritClientListChangesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // GerritClientListProjectsFunc describes the behavior when the ListProjects
 // method of the parent MockGerritClient instance is invoked.
 type GerritClientListProjectsFunc struct {
@@ -10630,9 +10881,15 @@ type MockGitserverClient struct {
 	// BlameFileFunc is an instance of a mock function object controlling
 	// the behavior of the method BlameFile.
 	BlameFileFunc *GitserverClientBlameFileFunc
+	// BoostCloneFunc is an instance of a mock function object controlling
+	// the behavior of the method BoostClone.
+	BoostCloneFunc *GitserverClientBoostCloneFunc
 	// BranchesContainingFunc is an instance of a mock function object
 	// controlling the behavior of the method BranchesContaining.
 	BranchesContainingFunc *GitserverClientBranchesContainingFunc
+	// CloneQueueFunc is an instance of a mock function object controlling
+	// the behavior of the method CloneQueue.
+	CloneQueueFunc *GitserverClientCloneQueueFunc
 	// CommitDateFunc is an instance of a mock function object controlling
 	// the behavior of the method CommitDate.
 	CommitDateFunc *GitserverClientCommitDateFunc
@@ -10696,6 +10953,9 @@ type MockGitserverClient struct {
 	// IsRepoCloneableFunc is an instance of a mock function object
 	// controlling the behavior of the method IsRepoCloneable.
 	IsRepoCloneableFunc *GitserverClientIsRepoCloneableFunc
+	// LFSContentFunc is an instance of a mock function object controlling
+	// the behavior of the method LFSContent.
+	LFSContentFunc *GitserverClientLFSContentFunc
 	// ListBranchesFunc is an instance of a mock function object controlling
 	// the behavior of the method ListBranches.
 	ListBranchesFunc *GitserverClientListBranchesFunc
@@ -10708,6 +10968,9 @@ type MockGitserverClient struct {
 	// ListRefsFunc is an instance of a mock function object controlling the
 	// behavior of the method ListRefs.
 	ListRefsFunc *GitserverClientListRefsFunc
+	// ListRefsFromFunc is an instance of a mock function object controlling
+	// the behavior of the method ListRefsFrom.
+	ListRefsFromFunc *GitserverClientListRefsFromFunc
 	// ListTagsFunc is an instance of a mock function object controlling the
 	// behavior of the method ListTags.
 	ListTagsFunc *GitserverClientListTagsFunc
@@ -10723,6 +10986,9 @@ type MockGitserverClient struct {
 	// NewFileReaderFunc is an instance of a mock function object
 	// controlling the behavior of the method NewFileReader.
 	NewFileReaderFunc *GitserverClientNewFileReaderFunc
+	// ObjectSignatureFunc is an instance of a mock function object
+	// controlling the behavior of the method ObjectSignature.
+	ObjectSignatureFunc *GitserverClientObjectSignatureFunc
 	// P4ExecFunc is an instance of a mock function object controlling the
 	// behavior of the method P4Exec.
 	P4ExecFunc *GitserverClientP4ExecFunc
@@ -10753,6 +11019,9 @@ type MockGitserverClient struct {
 	// RequestRepoCloneFunc is an instance of a mock function object
 	// controlling the behavior of the method RequestRepoClone.
 	RequestRepoCloneFunc *GitserverClientRequestRepoCloneFunc
+	// RequestRepoMigrateFunc is an instance of a mock function object
+	// controlling the behavior of the method RequestRepoMigrate.
+	RequestRepoMigrateFunc *GitserverClientRequestRepoMigrateFunc
 	// RequestRepoUpdateFunc is an instance of a mock function object
 	// controlling the behavior of the method RequestRepoUpdate.
 	RequestRepoUpdateFunc *GitserverClientRequestRepoUpdateFunc
@@ -10805,11 +11074,21 @@ func NewMockGitserverClient() *MockGitserverClient {
 				return
 			},
 		},
+		BoostCloneFunc: &GitserverClientBoostCloneFunc{
+			defaultHook: func(context.Context, api.RepoName) (r0 bool, r1 error) {
+				return
+			},
+		},
 		BranchesContainingFunc: &GitserverClientBranchesContainingFunc{
 			defaultHook: func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) (r0 []string, r1 error) {
 				return
 			},
 		},
+		CloneQueueFunc: &GitserverClientCloneQueueFunc{
+			defaultHook: func(context.Context) (r0 map[string][]protocol.CloneQueueJob, r1 error) {
+				return
+			},
+		},
 		CommitDateFunc: &GitserverClientCommitDateFunc{
 			defaultHook: func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) (r0 string, r1 time.Time, r2 bool, r3 error) {
 				return
@@ -10915,6 +11194,11 @@ func NewMockGitserverClient() *MockGitserverClient {
 				return
 			},
 		},
+		LFSContentFunc: &GitserverClientLFSContentFunc{
+			defaultHook: func(context.Context, api.RepoName, string, int64) (r0 io.ReadCloser, r1 error) {
+				return
+			},
+		},
 		ListBranchesFunc: &GitserverClientListBranchesFunc{
 			defaultHook: func(context.Context, api.RepoName, gitserver.BranchesOptions) (r0 []*gitdomain.Branch, r1 error) {
 				return
@@ -10935,6 +11219,11 @@ func NewMockGitserverClient() *MockGitserverClient {
 				return
 			},
 		},
+		ListRefsFromFunc: &GitserverClientListRefsFromFunc{
+			defaultHook: func(context.Context, api.RepoName, string) (r0 []gitdomain.Ref, r1 error) {
+				return
+			},
+		},
 		ListTagsFunc: &GitserverClientListTagsFunc{
 			defaultHook: func(context.Context, api.RepoName, ...string) (r0 []*gitdomain.Tag, r1 error) {
 				return
@@ -10960,6 +11249,11 @@ func NewMockGitserverClient() *MockGitserverClient {
 				return
 			},
 		},
+		ObjectSignatureFunc: &GitserverClientObjectSignatureFunc{
+			defaultHook: func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (r0 *gitdomain.SignedObject, r1 error) {
+				return
+			},
+		},
 		P4ExecFunc: &GitserverClientP4ExecFunc{
 			defaultHook: func(context.Context, string, string, string, ...string) (r0 io.ReadCloser, r1 http.Header, r2 error) {
 				return
@@ -11010,6 +11304,11 @@ func NewMockGitserverClient() *MockGitserverClient {
 				return
 			},
 		},
+		RequestRepoMigrateFunc: &GitserverClientRequestRepoMigrateFunc{
+			defaultHook: func(context.Context, api.RepoName, string, string) (r0 *protocol.RepoUpdateResponse, r1 error) {
+				return
+			},
+		},
 		RequestRepoUpdateFunc: &GitserverClientRequestRepoUpdateFunc{
 			defaultHook: func(context.Context, api.RepoName, time.Duration) (r0 *protocol.RepoUpdateResponse, r1 error) {
 				return
@@ -11077,11 +11376,21 @@ func NewStrictMockGitserverClient() *MockGitserverClient {
 				panic("unexpected invocation of MockGitserverClient.BlameFile")
 			},
 		},
+		BoostCloneFunc: &GitserverClientBoostCloneFunc{
+			defaultHook: func(context.Context, api.RepoName) (bool, error) {
+				panic("unexpected invocation of MockGitserverClient.BoostClone")
+			},
+		},
 		BranchesContainingFunc: &GitserverClientBranchesContainingFunc{
 			defaultHook: func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error) {
 				panic("unexpected invocation of MockGitserverClient.BranchesContaining")
 			},
 		},
+		CloneQueueFunc: &GitserverClientCloneQueueFunc{
+			defaultHook: func(context.Context) (map[string][]protocol.CloneQueueJob, error) {
+				panic("unexpected invocation of MockGitserverClient.CloneQueue")
+			},
+		},
 		CommitDateFunc: &GitserverClientCommitDateFunc{
 			defaultHook: func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) (string, time.Time, bool, error) {
 				panic("unexpected invocation of MockGitserverClient.CommitDate")
@@ -11187,6 +11496,11 @@ func NewStrictMockGitserverClient() *MockGitserverClient {
 				panic("unexpected invocation of MockGitserverClient.IsRepoCloneable")
 			},
 		},
+		LFSContentFunc: &GitserverClientLFSContentFunc{
+			defaultHook: func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error) {
+				panic("unexpected invocation of MockGitserverClient.LFSContent")
+			},
+		},
 		ListBranchesFunc: &GitserverClientListBranchesFunc{
 			defaultHook: func(context.Context, api.RepoName, gitserver.BranchesOptions) ([]*gitdomain.Branch, error) {
 				panic("unexpected invocation of MockGitserverClient.ListBranches")
@@ -11207,6 +11521,11 @@ func NewStrictMockGitserverClient() *MockGitserverClient {
 				panic("unexpected invocation of MockGitserverClient.ListRefs")
 			},
 		},
+		ListRefsFromFunc: &GitserverClientListRefsFromFunc{
+			defaultHook: func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error) {
+				panic("unexpected invocation of MockGitserverClient.ListRefsFrom")
+			},
+		},
 		ListTagsFunc: &GitserverClientListTagsFunc{
 			defaultHook: func(context.Context, api.RepoName, ...string) ([]*gitdomain.Tag, error) {
 				panic("unexpected invocation of MockGitserverClient.ListTags")
@@ -11232,6 +11551,11 @@ func NewStrictMockGitserverClient() *MockGitserverClient {
 				panic("unexpected invocation of MockGitserverClient.NewFileReader")
 			},
 		},
+		ObjectSignatureFunc: &GitserverClientObjectSignatureFunc{
+			defaultHook: func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error) {
+				panic("unexpected invocation of MockGitserverClient.ObjectSignature")
+			},
+		},
 		P4ExecFunc: &GitserverClientP4ExecFunc{
 			defaultHook: func(context.Context, string, string, string, ...string) (io.ReadCloser, http.Header, error) {
 				panic("unexpected invocation of MockGitserverClient.P4Exec")
@@ -11282,6 +11606,11 @@ func NewStrictMockGitserverClient() *MockGitserverClient {
 				panic("unexpected invocation of MockGitserverClient.RequestRepoClone")
 			},
 		},
+		RequestRepoMigrateFunc: &GitserverClientRequestRepoMigrateFunc{
+			defaultHook: func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error) {
+				panic("unexpected invocation of MockGitserverClient.RequestRepoMigrate")
+			},
+		},
 		RequestRepoUpdateFunc: &GitserverClientRequestRepoUpdateFunc{
 			defaultHook: func(context.Context, api.RepoName, time.Duration) (*protocol.RepoUpdateResponse, error) {
 				panic("unexpected invocation of MockGitserverClient.RequestRepoUpdate")
@@ -11340,9 +11669,15 @@ func NewMockGitserverClientFrom(i gitserver.Client) *MockGitserverClient {
 		BlameFileFunc: &GitserverClientBlameFileFunc{
 			defaultHook: i.BlameFile,
 		},
+		BoostCloneFunc: &GitserverClientBoostCloneFunc{
+			defaultHook: i.BoostClone,
+		},
 		BranchesContainingFunc: &GitserverClientBranchesContainingFunc{
 			defaultHook: i.BranchesContaining,
 		},
+		CloneQueueFunc: &GitserverClientCloneQueueFunc{
+			defaultHook: i.CloneQueue,
+		},
 		CommitDateFunc: &GitserverClientCommitDateFunc{
 			defaultHook: i.CommitDate,
 		},
@@ -11406,6 +11741,9 @@ func NewMockGitserverClientFrom(i gitserver.Client) *MockGitserverClient {
 		IsRepoCloneableFunc: &GitserverClientIsRepoCloneableFunc{
 			defaultHook: i.IsRepoCloneable,
 		},
+		LFSContentFunc: &GitserverClientLFSContentFunc{
+			defaultHook: i.LFSContent,
+		},
 		ListBranchesFunc: &GitserverClientListBranchesFunc{
 			defaultHook: i.ListBranches,
 		},
@@ -11418,6 +11756,9 @@ func NewMockGitserverClientFrom(i gitserver.Client) *MockGitserverClient {
 		ListRefsFunc: &GitserverClientListRefsFunc{
 			defaultHook: i.ListRefs,
 		},
+		ListRefsFromFunc: &GitserverClientListRefsFromFunc{
+			defaultHook: i.ListRefsFrom,
+		},
 		ListTagsFunc: &GitserverClientListTagsFunc{
 			defaultHook: i.ListTags,
 		},
@@ -11433,6 +11774,9 @@ func NewMockGitserverClientFrom(i gitserver.Client) *MockGitserverClient {
 		NewFileReaderFunc: &GitserverClientNewFileReaderFunc{
 			defaultHook: i.NewFileReader,
 		},
+		ObjectSignatureFunc: &GitserverClientObjectSignatureFunc{
+			defaultHook: i.ObjectSignature,
+		},
 		P4ExecFunc: &GitserverClientP4ExecFunc{
 			defaultHook: i.P4Exec,
 		},
@@ -11463,6 +11807,9 @@ func NewMockGitserverClientFrom(i gitserver.Client) *MockGitserverClient {
 		RequestRepoCloneFunc: &GitserverClientRequestRepoCloneFunc{
 			defaultHook: i.RequestRepoClone,
 		},
+		RequestRepoMigrateFunc: &GitserverClientRequestRepoMigrateFunc{
+			defaultHook: i.RequestRepoMigrate,
+		},
 		RequestRepoUpdateFunc: &GitserverClientRequestRepoUpdateFunc{
 			defaultHook: i.RequestRepoUpdate,
 		},
@@ -12028,37 +12375,35 @@ func (c GitserverClientBlameFileFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// GitserverClientBranchesContainingFunc describes the behavior when the
-// BranchesContaining method of the parent MockGitserverClient instance is
-// invoked.
-type GitserverClientBranchesContainingFunc struct {
-	defaultHook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error)
-	hooks       []func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error)
-	history     []GitserverClientBranchesContainingFuncCall
+// GitserverClientBoostCloneFunc describes the behavior when the BoostClone
+// method of the parent MockGitserverClient instance is invoked.
+type GitserverClientBoostCloneFunc struct {
+	defaultHook func(context.Context, api.RepoName) (bool, error)
+	hooks       []func(context.Context, api.RepoName) (bool, error)
+	history     []GitserverClientBoostCloneFuncCall
 	mutex       sync.Mutex
 }
 
-// BranchesContaining delegates to the next hook function in the queue and
-// stores the parameter and result values of this invocation.
-func (m *MockGitserverClient) BranchesContaining(v0 context.Context, v1 authz.SubRepoPermissionChecker, v2 api.RepoName, v3 api.CommitID) ([]string, error) {
-	r0, r1 := m.BranchesContainingFunc.nextHook()(v0, v1, v2, v3)
-	m.BranchesContainingFunc.appendCall(GitserverClientBranchesContainingFuncCall{v0, v1, v2, v3, r0, r1})
+// BoostClone delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGitserverClient) BoostClone(v0 context.Context, v1 api.RepoName) (bool, error) {
+	r0, r1 := m.BoostCloneFunc.nextHook()(v0, v1)
+	m.BoostCloneFunc.appendCall(GitserverClientBoostCloneFuncCall{v0, v1, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the BranchesContaining
-// method of the parent MockGitserverClient instance is invoked and the hook
-// queue is empty.
-func (f *GitserverClientBranchesContainingFunc) SetDefaultHook(hook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error)) {
+// SetDefaultHook sets function that is called when the BoostClone method of
+// the parent MockGitserverClient instance is invoked and the hook queue is
+// empty.
+func (f *GitserverClientBoostCloneFunc) SetDefaultHook(hook func(context.Context, api.RepoName) (bool, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// BranchesContaining method of the parent MockGitserverClient instance
-// invokes the hook at the front of the queue and discards it. After the
-// queue is empty, the default hook function is invoked for any future
-// action.
-func (f *GitserverClientBranchesContainingFunc) PushHook(hook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error)) {
+// BoostClone method of the parent MockGitserverClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *GitserverClientBoostCloneFunc) PushHook(hook func(context.Context, api.RepoName) (bool, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -12066,20 +12411,20 @@ func (f *GitserverClientBranchesContainingFunc) PushHook(hook func(context.Conte
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *GitserverClientBranchesContainingFunc) SetDefaultReturn(r0 []string, r1 error) {
-	f.SetDefaultHook(func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error) {
+func (f *GitserverClientBoostCloneFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName) (bool, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *GitserverClientBranchesContainingFunc) PushReturn(r0 []string, r1 error) {
-	f.PushHook(func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error) {
+func (f *GitserverClientBoostCloneFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName) (bool, error) {
 		return r0, r1
 	})
 }
 
-func (f *GitserverClientBranchesContainingFunc) nextHook() func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error) {
+func (f *GitserverClientBoostCloneFunc) nextHook() func(context.Context, api.RepoName) (bool, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -12092,34 +12437,144 @@ func (f *GitserverClientBranchesContainingFunc) nextHook() func(context.Context,
 	return hook
 }
 
-func (f *GitserverClientBranchesContainingFunc) appendCall(r0 GitserverClientBranchesContainingFuncCall) {
+func (f *GitserverClientBoostCloneFunc) appendCall(r0 GitserverClientBoostCloneFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of GitserverClientBranchesContainingFuncCall
-// objects describing the invocations of this function.
-func (f *GitserverClientBranchesContainingFunc) History() []GitserverClientBranchesContainingFuncCall {
+// History returns a sequence of GitserverClientBoostCloneFuncCall objects
+// describing the invocations of this function.
+func (f *GitserverClientBoostCloneFunc) History() []GitserverClientBoostCloneFuncCall {
 	f.mutex.Lock()
-	history := make([]GitserverClientBranchesContainingFuncCall, len(f.history))
+	history := make([]GitserverClientBoostCloneFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// GitserverClientBranchesContainingFuncCall is an object that describes an
-// invocation of method BranchesContaining on an instance of
-// MockGitserverClient.
-type GitserverClientBranchesContainingFuncCall struct {
+// GitserverClientBoostCloneFuncCall is an object that describes an
+// invocation of method BoostClone on an instance of MockGitserverClient.
+type GitserverClientBoostCloneFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 authz.SubRepoPermissionChecker
-	// Arg2 is the value of the 3rd argument passed to this method
+	Arg1 api.RepoName
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientBoostCloneFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientBoostCloneFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// GitserverClientBranchesContainingFunc describes the behavior when the
+// BranchesContaining method of the parent MockGitserverClient instance is
+// invoked.
+type GitserverClientBranchesContainingFunc struct {
+	defaultHook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error)
+	hooks       []func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error)
+	history     []GitserverClientBranchesContainingFuncCall
+	mutex       sync.Mutex
+}
+
+// BranchesContaining delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockGitserverClient) BranchesContaining(v0 context.Context, v1 authz.SubRepoPermissionChecker, v2 api.RepoName, v3 api.CommitID) ([]string, error) {
+	r0, r1 := m.BranchesContainingFunc.nextHook()(v0, v1, v2, v3)
+	m.BranchesContainingFunc.appendCall(GitserverClientBranchesContainingFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the BranchesContaining
+// method of the parent MockGitserverClient instance is invoked and the hook
+// queue is empty.
+func (f *GitserverClientBranchesContainingFunc) SetDefaultHook(hook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// BranchesContaining method of the parent MockGitserverClient instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *GitserverClientBranchesContainingFunc) PushHook(hook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverClientBranchesContainingFunc) SetDefaultReturn(r0 []string, r1 error) {
+	f.SetDefaultHook(func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverClientBranchesContainingFunc) PushReturn(r0 []string, r1 error) {
+	f.PushHook(func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverClientBranchesContainingFunc) nextHook() func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientBranchesContainingFunc) appendCall(r0 GitserverClientBranchesContainingFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientBranchesContainingFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverClientBranchesContainingFunc) History() []GitserverClientBranchesContainingFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientBranchesContainingFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientBranchesContainingFuncCall is an object that describes an
+// invocation of method BranchesContaining on an instance of
+// MockGitserverClient.
+type GitserverClientBranchesContainingFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 authz.SubRepoPermissionChecker
+	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
 	Arg2 api.RepoName
 	// Arg3 is the value of the 4th argument passed to this method
@@ -12145,6 +12600,111 @@ func (c GitserverClientBranchesContainingFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// GitserverClientCloneQueueFunc describes the behavior when the CloneQueue
+// method of the parent MockGitserverClient instance is invoked.
+type GitserverClientCloneQueueFunc struct {
+	defaultHook func(context.Context) (map[string][]protocol.CloneQueueJob, error)
+	hooks       []func(context.Context) (map[string][]protocol.CloneQueueJob, error)
+	history     []GitserverClientCloneQueueFuncCall
+	mutex       sync.Mutex
+}
+
+// CloneQueue delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGitserverClient) CloneQueue(v0 context.Context) (map[string][]protocol.CloneQueueJob, error) {
+	r0, r1 := m.CloneQueueFunc.nextHook()(v0)
+	m.CloneQueueFunc.appendCall(GitserverClientCloneQueueFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the CloneQueue method of
+// the parent MockGitserverClient instance is invoked and the hook queue is
+// empty.
+func (f *GitserverClientCloneQueueFunc) SetDefaultHook(hook func(context.Context) (map[string][]protocol.CloneQueueJob, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CloneQueue method of the parent MockGitserverClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *GitserverClientCloneQueueFunc) PushHook(hook func(context.Context) (map[string][]protocol.CloneQueueJob, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverClientCloneQueueFunc) SetDefaultReturn(r0 map[string][]protocol.CloneQueueJob, r1 error) {
+	f.SetDefaultHook(func(context.Context) (map[string][]protocol.CloneQueueJob, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverClientCloneQueueFunc) PushReturn(r0 map[string][]protocol.CloneQueueJob, r1 error) {
+	f.PushHook(func(context.Context) (map[string][]protocol.CloneQueueJob, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverClientCloneQueueFunc) nextHook() func(context.Context) (map[string][]protocol.CloneQueueJob, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientCloneQueueFunc) appendCall(r0 GitserverClientCloneQueueFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientCloneQueueFuncCall objects
+// describing the invocations of this function.
+func (f *GitserverClientCloneQueueFunc) History() []GitserverClientCloneQueueFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientCloneQueueFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientCloneQueueFuncCall is an object that describes an
+// invocation of method CloneQueue on an instance of MockGitserverClient.
+type GitserverClientCloneQueueFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 map[string][]protocol.CloneQueueJob
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientCloneQueueFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientCloneQueueFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // GitserverClientCommitDateFunc describes the behavior when the CommitDate
 // method of the parent MockGitserverClient instance is invoked.
 type GitserverClientCommitDateFunc struct {
@@ -14551,36 +15111,35 @@ func (c GitserverClientIsRepoCloneableFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
-// GitserverClientListBranchesFunc describes the behavior when the
-// ListBranches method of the parent MockGitserverClient instance is
-// invoked.
-type GitserverClientListBranchesFunc struct {
-	defaultHook func(context.Context, api.RepoName, gitserver.BranchesOptions) ([]*gitdomain.Branch, error)
-	hooks       []func(context.Context, api.RepoName, gitserver.BranchesOptions) ([]*gitdomain.Branch, error)
-	history     []GitserverClientListBranchesFuncCall
+// GitserverClientLFSContentFunc describes the behavior when the LFSContent
+// method of the parent MockGitserverClient instance is invoked.
+type GitserverClientLFSContentFunc struct {
+	defaultHook func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error)
+	hooks       []func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error)
+	history     []GitserverClientLFSContentFuncCall
 	mutex       sync.Mutex
 }
 
-// ListBranches delegates to the next hook function in the queue and stores
+// LFSContent delegates to the next hook function in the queue and stores
 // the parameter and result values of this invocation.
-func (m *MockGitserverClient) ListBranches(v0 context.Context, v1 api.RepoName, v2 gitserver.BranchesOptions) ([]*gitdomain.Branch, error) {
-	r0, r1 := m.ListBranchesFunc.nextHook()(v0, v1, v2)
-	m.ListBranchesFunc.appendCall(GitserverClientListBranchesFuncCall{v0, v1, v2, r0, r1})
+func (m *MockGitserverClient) LFSContent(v0 context.Context, v1 api.RepoName, v2 string, v3 int64) (io.ReadCloser, error) {
+	r0, r1 := m.LFSContentFunc.nextHook()(v0, v1, v2, v3)
+	m.LFSContentFunc.appendCall(GitserverClientLFSContentFuncCall{v0, v1, v2, v3, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the ListBranches method
-// of the parent MockGitserverClient instance is invoked and the hook queue
-// is empty.
-func (f *GitserverClientListBranchesFunc) SetDefaultHook(hook func(context.Context, api.RepoName, gitserver.BranchesOptions) ([]*gitdomain.Branch, error)) {
+// SetDefaultHook sets function that is called when the LFSContent method of
+// the parent MockGitserverClient instance is invoked and the hook queue is
+// empty.
+func (f *GitserverClientLFSContentFunc) SetDefaultHook(hook func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// ListBranches method of the parent MockGitserverClient instance invokes
-// the hook at the front of the queue and discards it. After the queue is
-// empty, the default hook function is invoked for any future action.
-func (f *GitserverClientListBranchesFunc) PushHook(hook func(context.Context, api.RepoName, gitserver.BranchesOptions) ([]*gitdomain.Branch, error)) {
+// LFSContent method of the parent MockGitserverClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *GitserverClientLFSContentFunc) PushHook(hook func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -14588,20 +15147,20 @@ func (f *GitserverClientListBranchesFunc) PushHook(hook func(context.Context, ap
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *GitserverClientListBranchesFunc) SetDefaultReturn(r0 []*gitdomain.Branch, r1 error) {
-	f.SetDefaultHook(func(context.Context, api.RepoName, gitserver.BranchesOptions) ([]*gitdomain.Branch, error) {
+func (f *GitserverClientLFSContentFunc) SetDefaultReturn(r0 io.ReadCloser, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *GitserverClientListBranchesFunc) PushReturn(r0 []*gitdomain.Branch, r1 error) {
-	f.PushHook(func(context.Context, api.RepoName, gitserver.BranchesOptions) ([]*gitdomain.Branch, error) {
+func (f *GitserverClientLFSContentFunc) PushReturn(r0 io.ReadCloser, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error) {
 		return r0, r1
 	})
 }
 
-func (f *GitserverClientListBranchesFunc) nextHook() func(context.Context, api.RepoName, gitserver.BranchesOptions) ([]*gitdomain.Branch, error) {
+func (f *GitserverClientLFSContentFunc) nextHook() func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -14614,26 +15173,26 @@ func (f *GitserverClientListBranchesFunc) nextHook() func(context.Context, api.R
 	return hook
 }
 
-func (f *GitserverClientListBranchesFunc) appendCall(r0 GitserverClientListBranchesFuncCall) {
+func (f *GitserverClientLFSContentFunc) appendCall(r0 GitserverClientLFSContentFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of GitserverClientListBranchesFuncCall objects
+// History returns a sequence of GitserverClientLFSContentFuncCall objects
 // describing the invocations of this function.
-func (f *GitserverClientListBranchesFunc) History() []GitserverClientListBranchesFuncCall {
+func (f *GitserverClientLFSContentFunc) History() []GitserverClientLFSContentFuncCall {
 	f.mutex.Lock()
-	history := make([]GitserverClientListBranchesFuncCall, len(f.history))
+	history := make([]GitserverClientLFSContentFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// GitserverClientListBranchesFuncCall is an object that describes an
-// invocation of method ListBranches on an instance of MockGitserverClient.
-type GitserverClientListBranchesFuncCall struct {
+// GitserverClientLFSContentFuncCall is an object that describes an
+// invocation of method LFSContent on an instance of MockGitserverClient.
+type GitserverClientLFSContentFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -14642,10 +15201,13 @@ type GitserverClientListBranchesFuncCall struct {
 	Arg1 api.RepoName
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
-	Arg2 gitserver.BranchesOptions
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int64
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []*gitdomain.Branch
+	Result0 io.ReadCloser
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -14653,31 +15215,143 @@ type GitserverClientListBranchesFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c GitserverClientListBranchesFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+func (c GitserverClientLFSContentFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c GitserverClientListBranchesFuncCall) Results() []interface{} {
+func (c GitserverClientLFSContentFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// GitserverClientListDirectoryChildrenFunc describes the behavior when the
-// ListDirectoryChildren method of the parent MockGitserverClient instance
-// is invoked.
-type GitserverClientListDirectoryChildrenFunc struct {
-	defaultHook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID, []string) (map[string][]string, error)
-	hooks       []func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID, []string) (map[string][]string, error)
-	history     []GitserverClientListDirectoryChildrenFuncCall
+// GitserverClientListBranchesFunc describes the behavior when the
+// ListBranches method of the parent MockGitserverClient instance is
+// invoked.
+type GitserverClientListBranchesFunc struct {
+	defaultHook func(context.Context, api.RepoName, gitserver.BranchesOptions) ([]*gitdomain.Branch, error)
+	hooks       []func(context.Context, api.RepoName, gitserver.BranchesOptions) ([]*gitdomain.Branch, error)
+	history     []GitserverClientListBranchesFuncCall
 	mutex       sync.Mutex
 }
 
-// ListDirectoryChildren delegates to the next hook function in the queue
-// and stores the parameter and result values of this invocation.
-func (m *MockGitserverClient) ListDirectoryChildren(v0 context.Context, v1 authz.SubRepoPermissionChecker, v2 api.RepoName, v3 api.CommitID, v4 []string) (map[string][]string, error) {
-	r0, r1 := m.ListDirectoryChildrenFunc.nextHook()(v0, v1, v2, v3, v4)
-	m.ListDirectoryChildrenFunc.appendCall(GitserverClientListDirectoryChildrenFuncCall{v0, v1, v2, v3, v4, r0, r1})
+// ListBranches delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGitserverClient) ListBranches(v0 context.Context, v1 api.RepoName, v2 gitserver.BranchesOptions) ([]*gitdomain.Branch, error) {
+	r0, r1 := m.ListBranchesFunc.nextHook()(v0, v1, v2)
+	m.ListBranchesFunc.appendCall(GitserverClientListBranchesFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ListBranches method
+// of the parent MockGitserverClient instance is invoked and the hook queue
+// is empty.
+func (f *GitserverClientListBranchesFunc) SetDefaultHook(hook func(context.Context, api.RepoName, gitserver.BranchesOptions) ([]*gitdomain.Branch, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListBranches method of the parent MockGitserverClient instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *GitserverClientListBranchesFunc) PushHook(hook func(context.Context, api.RepoName, gitserver.BranchesOptions) ([]*gitdomain.Branch, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverClientListBranchesFunc) SetDefaultReturn(r0 []*gitdomain.Branch, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, gitserver.BranchesOptions) ([]*gitdomain.Branch, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverClientListBranchesFunc) PushReturn(r0 []*gitdomain.Branch, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, gitserver.BranchesOptions) ([]*gitdomain.Branch, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverClientListBranchesFunc) nextHook() func(context.Context, api.RepoName, gitserver.BranchesOptions) ([]*gitdomain.Branch, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientListBranchesFunc) appendCall(r0 GitserverClientListBranchesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientListBranchesFuncCall objects
+// describing the invocations of this function.
+func (f *GitserverClientListBranchesFunc) History() []GitserverClientListBranchesFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientListBranchesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientListBranchesFuncCall is an object that describes an
+// invocation of method ListBranches on an instance of MockGitserverClient.
+type GitserverClientListBranchesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 gitserver.BranchesOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*gitdomain.Branch
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientListBranchesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientListBranchesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// GitserverClientListDirectoryChildrenFunc describes the behavior when the
+// ListDirectoryChildren method of the parent MockGitserverClient instance
+// is invoked.
+type GitserverClientListDirectoryChildrenFunc struct {
+	defaultHook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID, []string) (map[string][]string, error)
+	hooks       []func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID, []string) (map[string][]string, error)
+	history     []GitserverClientListDirectoryChildrenFuncCall
+	mutex       sync.Mutex
+}
+
+// ListDirectoryChildren delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockGitserverClient) ListDirectoryChildren(v0 context.Context, v1 authz.SubRepoPermissionChecker, v2 api.RepoName, v3 api.CommitID, v4 []string) (map[string][]string, error) {
+	r0, r1 := m.ListDirectoryChildrenFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.ListDirectoryChildrenFunc.appendCall(GitserverClientListDirectoryChildrenFuncCall{v0, v1, v2, v3, v4, r0, r1})
 	return r0, r1
 }
 
@@ -15009,6 +15683,118 @@ func (c GitserverClientListRefsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// GitserverClientListRefsFromFunc describes the behavior when the
+// ListRefsFrom method of the parent MockGitserverClient instance is
+// invoked.
+type GitserverClientListRefsFromFunc struct {
+	defaultHook func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error)
+	hooks       []func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error)
+	history     []GitserverClientListRefsFromFuncCall
+	mutex       sync.Mutex
+}
+
+// ListRefsFrom delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGitserverClient) ListRefsFrom(v0 context.Context, v1 api.RepoName, v2 string) ([]gitdomain.Ref, error) {
+	r0, r1 := m.ListRefsFromFunc.nextHook()(v0, v1, v2)
+	m.ListRefsFromFunc.appendCall(GitserverClientListRefsFromFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ListRefsFrom method
+// of the parent MockGitserverClient instance is invoked and the hook queue
+// is empty.
+func (f *GitserverClientListRefsFromFunc) SetDefaultHook(hook func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListRefsFrom method of the parent MockGitserverClient instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *GitserverClientListRefsFromFunc) PushHook(hook func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverClientListRefsFromFunc) SetDefaultReturn(r0 []gitdomain.Ref, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverClientListRefsFromFunc) PushReturn(r0 []gitdomain.Ref, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverClientListRefsFromFunc) nextHook() func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientListRefsFromFunc) appendCall(r0 GitserverClientListRefsFromFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientListRefsFromFuncCall objects
+// describing the invocations of this function.
+func (f *GitserverClientListRefsFromFunc) History() []GitserverClientListRefsFromFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientListRefsFromFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientListRefsFromFuncCall is an object that describes an
+// invocation of method ListRefsFrom on an instance of MockGitserverClient.
+type GitserverClientListRefsFromFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []gitdomain.Ref
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientListRefsFromFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientListRefsFromFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // GitserverClientListTagsFunc describes the behavior when the ListTags
 // method of the parent MockGitserverClient instance is invoked.
 type GitserverClientListTagsFunc struct {
@@ -15599,6 +16385,122 @@ func (c GitserverClientNewFileReaderFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// GitserverClientObjectSignatureFunc describes the behavior when the
+// ObjectSignature method of the parent MockGitserverClient instance is
+// invoked.
+type GitserverClientObjectSignatureFunc struct {
+	defaultHook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error)
+	hooks       []func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error)
+	history     []GitserverClientObjectSignatureFuncCall
+	mutex       sync.Mutex
+}
+
+// ObjectSignature delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockGitserverClient) ObjectSignature(v0 context.Context, v1 authz.SubRepoPermissionChecker, v2 api.RepoName, v3 string) (*gitdomain.SignedObject, error) {
+	r0, r1 := m.ObjectSignatureFunc.nextHook()(v0, v1, v2, v3)
+	m.ObjectSignatureFunc.appendCall(GitserverClientObjectSignatureFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ObjectSignature
+// method of the parent MockGitserverClient instance is invoked and the hook
+// queue is empty.
+func (f *GitserverClientObjectSignatureFunc) SetDefaultHook(hook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ObjectSignature method of the parent MockGitserverClient instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *GitserverClientObjectSignatureFunc) PushHook(hook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverClientObjectSignatureFunc) SetDefaultReturn(r0 *gitdomain.SignedObject, r1 error) {
+	f.SetDefaultHook(func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverClientObjectSignatureFunc) PushReturn(r0 *gitdomain.SignedObject, r1 error) {
+	f.PushHook(func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverClientObjectSignatureFunc) nextHook() func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientObjectSignatureFunc) appendCall(r0 GitserverClientObjectSignatureFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientObjectSignatureFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverClientObjectSignatureFunc) History() []GitserverClientObjectSignatureFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientObjectSignatureFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientObjectSignatureFuncCall is an object that describes an
+// invocation of method ObjectSignature on an instance of
+// MockGitserverClient.
+type GitserverClientObjectSignatureFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 authz.SubRepoPermissionChecker
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 api.RepoName
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *gitdomain.SignedObject
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientObjectSignatureFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientObjectSignatureFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // GitserverClientP4ExecFunc describes the behavior when the P4Exec method
 // of the parent MockGitserverClient instance is invoked.
 type GitserverClientP4ExecFunc struct {
@@ -16746,6 +17648,123 @@ func (c GitserverClientRequestRepoCloneFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// GitserverClientRequestRepoMigrateFunc describes the behavior when the
+// RequestRepoMigrate method of the parent MockGitserverClient instance is
+// invoked.
+type GitserverClientRequestRepoMigrateFunc struct {
+	defaultHook func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error)
+	hooks       []func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error)
+	history     []GitserverClientRequestRepoMigrateFuncCall
+	mutex       sync.Mutex
+}
+
+// RequestRepoMigrate delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockGitserverClient) RequestRepoMigrate(v0 context.Context, v1 api.RepoName, v2 string, v3 string) (*protocol.RepoUpdateResponse, error) {
+	r0, r1 := m.RequestRepoMigrateFunc.nextHook()(v0, v1, v2, v3)
+	m.RequestRepoMigrateFunc.appendCall(GitserverClientRequestRepoMigrateFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the RequestRepoMigrate
+// method of the parent MockGitserverClient instance is invoked and the hook
+// queue is empty.
+func (f *GitserverClientRequestRepoMigrateFunc) SetDefaultHook(hook func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RequestRepoMigrate method of the parent MockGitserverClient instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *GitserverClientRequestRepoMigrateFunc) PushHook(hook func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverClientRequestRepoMigrateFunc) SetDefaultReturn(r0 *protocol.RepoUpdateResponse, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverClientRequestRepoMigrateFunc) PushReturn(r0 *protocol.RepoUpdateResponse, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverClientRequestRepoMigrateFunc) nextHook() func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientRequestRepoMigrateFunc) appendCall(r0 GitserverClientRequestRepoMigrateFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientRequestRepoMigrateFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverClientRequestRepoMigrateFunc) History() []GitserverClientRequestRepoMigrateFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientRequestRepoMigrateFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientRequestRepoMigrateFuncCall is an object that describes an
+// invocation of method RequestRepoMigrate on an instance of
+// MockGitserverClient.
+type GitserverClientRequestRepoMigrateFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *protocol.RepoUpdateResponse
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientRequestRepoMigrateFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientRequestRepoMigrateFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // GitserverClientRequestRepoUpdateFunc describes the behavior when the
 // RequestRepoUpdate method of the parent MockGitserverClient instance is
 // invoked.
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 )
@@ -14,6 +15,8 @@ func setDraft(c *btypes.Changeset) *btypes.Changeset {
 		m.IsDraft = true
 	case *gitlab.MergeRequest:
 		m.WorkInProgress = true
+	case *bitbucketserver.PullRequest:
+		m.Draft = true
 	}
 	return c
 }
@@ -580,6 +580,8 @@ func computeSingleChangesetExternalState(c *btypes.Changeset) (s btypes.Changese
 	case *bitbucketserver.PullRequest:
 		if m.State == "DECLINED" {
 			s = btypes.ChangesetExternalStateClosed
+		} else if m.Draft && m.State == string(btypes.ChangesetExternalStateOpen) {
+			s = btypes.ChangesetExternalStateDraft
 		} else {
 			s = btypes.ChangesetExternalState(m.State)
 		}
@@ -764,6 +764,18 @@ func TestComputeExternalState(t *testing.T) {
 			},
 			want: btypes.ChangesetExternalStateOpen,
 		},
+		{
+			name:      "bitbucketserver draft - no events",
+			changeset: setDraft(bitbucketChangeset(daysAgo(10), "OPEN", "UNAPPROVED")),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateDraft,
+		},
+		{
+			name:      "bitbucketserver draft - declined",
+			changeset: setDraft(bitbucketChangeset(daysAgo(10), "DECLINED", "UNAPPROVED")),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateClosed,
+		},
 		{
 			name:      "bitbucketserver - changeset newer and deleted",
 			changeset: setDeletedAt(bitbucketChangeset(daysAgo(0), "OPEN", "NEEDS_WORK"), daysAgo(0)),
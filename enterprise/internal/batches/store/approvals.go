@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// SetBatchChangeApprovers replaces the approvers of a batch change.
+func (s *Store) SetBatchChangeApprovers(ctx context.Context, batchChangeID int64, userIDs []int32) (err error) {
+	ctx, _, endObservation := s.operations.setBatchChangeApprovers.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("batchChangeID", int(batchChangeID)),
+		attribute.Int("count", len(userIDs)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if userIDs == nil {
+		userIDs = []int32{}
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(
+		setBatchChangeApproversQueryFmtstr,
+		batchChangeID,
+		pq.Array(userIDs),
+		batchChangeID,
+		s.now(),
+		pq.Array(userIDs),
+	))
+}
+
+const setBatchChangeApproversQueryFmtstr = `
+WITH deleted AS (
+	DELETE FROM batch_change_approvers
+	WHERE batch_change_id = %s AND NOT user_id = ANY(%s)
+)
+INSERT INTO batch_change_approvers (batch_change_id, user_id, created_at)
+SELECT %s, user_id, %s FROM unnest(%s::integer[]) AS user_id
+ON CONFLICT (batch_change_id, user_id) DO NOTHING
+`
+
+// ListBatchChangeApprovers lists the IDs of the users who must approve the
+// batch specs of a batch change before its changesets can be published.
+func (s *Store) ListBatchChangeApprovers(ctx context.Context, batchChangeID int64) (userIDs []int32, err error) {
+	ctx, _, endObservation := s.operations.listBatchChangeApprovers.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("batchChangeID", int(batchChangeID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return basestore.ScanInt32s(s.Query(ctx, sqlf.Sprintf(listBatchChangeApproversQueryFmtstr, batchChangeID)))
+}
+
+const listBatchChangeApproversQueryFmtstr = `
+SELECT user_id FROM batch_change_approvers WHERE batch_change_id = %s ORDER BY user_id ASC
+`
+
+// CreateBatchSpecApproval records the approval of a batch spec by a user. If
+// the user already approved the batch spec, the existing approval is kept.
+func (s *Store) CreateBatchSpecApproval(ctx context.Context, a *btypes.BatchSpecApproval) (err error) {
+	ctx, _, endObservation := s.operations.createBatchSpecApproval.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("batchSpecID", int(a.BatchSpecID)),
+		attribute.Int("userID", int(a.UserID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = s.now()
+	}
+
+	q := sqlf.Sprintf(
+		createBatchSpecApprovalQueryFmtstr,
+		a.BatchSpecID,
+		a.UserID,
+		a.CreatedAt,
+	)
+
+	return s.query(ctx, q, func(sc dbutil.Scanner) error {
+		return scanBatchSpecApproval(a, sc)
+	})
+}
+
+const createBatchSpecApprovalQueryFmtstr = `
+INSERT INTO batch_spec_approvals (batch_spec_id, user_id, created_at)
+VALUES (%s, %s, %s)
+ON CONFLICT (batch_spec_id, user_id) DO UPDATE SET created_at = batch_spec_approvals.created_at
+RETURNING batch_spec_id, user_id, created_at
+`
+
+// ListBatchSpecApprovals lists the approvals of a batch spec, oldest first.
+func (s *Store) ListBatchSpecApprovals(ctx context.Context, batchSpecID int64) (as []*btypes.BatchSpecApproval, err error) {
+	ctx, _, endObservation := s.operations.listBatchSpecApprovals.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("batchSpecID", int(batchSpecID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	q := sqlf.Sprintf(listBatchSpecApprovalsQueryFmtstr, batchSpecID)
+	err = s.query(ctx, q, func(sc dbutil.Scanner) error {
+		var a btypes.BatchSpecApproval
+		if err := scanBatchSpecApproval(&a, sc); err != nil {
+			return err
+		}
+		as = append(as, &a)
+		return nil
+	})
+	return as, err
+}
+
+const listBatchSpecApprovalsQueryFmtstr = `
+SELECT batch_spec_id, user_id, created_at
+FROM batch_spec_approvals
+WHERE batch_spec_id = %s
+ORDER BY created_at ASC, user_id ASC
+`
+
+func scanBatchSpecApproval(a *btypes.BatchSpecApproval, sc dbutil.Scanner) error {
+	return sc.Scan(&a.BatchSpecID, &a.UserID, &a.CreatedAt)
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	bt "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+)
+
+func testStoreBatchChangeApprovals(t *testing.T, ctx context.Context, s *Store, clock bt.Clock) {
+	creator := bt.CreateTestUser(t, s.DatabaseDB(), false)
+	approver1 := bt.CreateTestUser(t, s.DatabaseDB(), false)
+	approver2 := bt.CreateTestUser(t, s.DatabaseDB(), false)
+
+	batchSpec := bt.CreateBatchSpec(t, ctx, s, "approvals", creator.ID, 0)
+	batchChange := bt.CreateBatchChange(t, ctx, s, "approvals", creator.ID, batchSpec.ID)
+
+	t.Run("SetBatchChangeApprovers", func(t *testing.T) {
+		for _, tc := range []struct {
+			set  []int32
+			want []int32
+		}{
+			{set: []int32{approver2.ID, approver1.ID}, want: []int32{approver1.ID, approver2.ID}},
+			{set: []int32{approver2.ID}, want: []int32{approver2.ID}},
+			{set: nil, want: []int32{}},
+		} {
+			if err := s.SetBatchChangeApprovers(ctx, batchChange.ID, tc.set); err != nil {
+				t.Fatal(err)
+			}
+
+			have, err := s.ListBatchChangeApprovers(ctx, batchChange.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have == nil {
+				have = []int32{}
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatalf("unexpected approvers (-want +have):\n%s", diff)
+			}
+		}
+	})
+
+	t.Run("BatchSpecApprovals", func(t *testing.T) {
+		approval := &btypes.BatchSpecApproval{BatchSpecID: batchSpec.ID, UserID: approver1.ID}
+		if err := s.CreateBatchSpecApproval(ctx, approval); err != nil {
+			t.Fatal(err)
+		}
+		if approval.CreatedAt.IsZero() {
+			t.Fatal("CreatedAt should be set")
+		}
+
+		// Approving again keeps the original approval.
+		again := &btypes.BatchSpecApproval{BatchSpecID: batchSpec.ID, UserID: approver1.ID, CreatedAt: clock.Now().Add(1)}
+		if err := s.CreateBatchSpecApproval(ctx, again); err != nil {
+			t.Fatal(err)
+		}
+		if !again.CreatedAt.Equal(approval.CreatedAt) {
+			t.Fatalf("approval was overwritten: want=%s have=%s", approval.CreatedAt, again.CreatedAt)
+		}
+
+		have, err := s.ListBatchSpecApprovals(ctx, batchSpec.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]*btypes.BatchSpecApproval{approval}, have); diff != "" {
+			t.Fatalf("unexpected approvals (-want +have):\n%s", diff)
+		}
+	})
+}
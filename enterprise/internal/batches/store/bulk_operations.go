@@ -8,6 +8,7 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 )
@@ -265,6 +266,24 @@ func listBulkOperationErrorsQuery(opts *ListBulkOperationErrorsOpts) *sqlf.Query
 	)
 }
 
+// ListBulkOperationChangesetJobIDs returns the IDs of the changeset jobs of the
+// given BulkOperation.
+func (s *Store) ListBulkOperationChangesetJobIDs(ctx context.Context, bulkOperationID string) (ids []int, err error) {
+	ctx, _, endObservation := s.operations.listBulkOperationChangesetJobs.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("bulkOperationID", bulkOperationID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return basestore.ScanInts(s.Query(ctx, sqlf.Sprintf(listBulkOperationChangesetJobIDsQueryFmtstr, bulkOperationID)))
+}
+
+var listBulkOperationChangesetJobIDsQueryFmtstr = `
+SELECT changeset_jobs.id
+FROM changeset_jobs
+WHERE changeset_jobs.bulk_group = %s
+ORDER BY changeset_jobs.id
+`
+
 func scanBulkOperation(b *btypes.BulkOperation, s dbutil.Scanner) error {
 	return s.Scan(
 		&b.ID,
@@ -253,4 +253,24 @@ func testStoreBulkOperations(t *testing.T, ctx context.Context, s *Store, clock
 			}
 		}
 	})
+
+	t.Run("ListBulkOperationChangesetJobIDs", func(t *testing.T) {
+		for _, job := range jobs {
+			have, err := s.ListBulkOperationChangesetJobIDs(ctx, job.BulkGroup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(have, []int{int(job.ID)}); diff != "" {
+				t.Fatal(diff)
+			}
+		}
+
+		have, err := s.ListBulkOperationChangesetJobIDs(ctx, "deadbeef")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != 0 {
+			t.Fatalf("unexpected changeset jobs: %v", have)
+		}
+	})
 }
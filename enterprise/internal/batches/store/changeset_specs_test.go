@@ -77,6 +77,7 @@ func testStoreChangesetSpecs(t *testing.T, ctx context.Context, s *Store, clock
 			c.Diff = testDiff
 			c.CommitAuthorName = "name"
 			c.CommitAuthorEmail = "email"
+			c.Reviewers = []string{"alice", "bob"}
 			c.Type = btypes.ChangesetSpecTypeBranch
 		} else {
 			c.ExternalID = "123456"
@@ -1670,6 +1670,7 @@ func (s *Store) EnqueueNextScheduledChangeset(ctx context.Context) (ch *btypes.C
 	q := sqlf.Sprintf(
 		enqueueNextScheduledChangesetFmtstr,
 		btypes.ReconcilerStateScheduled.ToDB(),
+		s.now(),
 		btypes.ReconcilerStateQueued.ToDB(),
 		sqlf.Join(ChangesetColumns, ","),
 	)
@@ -1689,12 +1690,30 @@ func (s *Store) EnqueueNextScheduledChangeset(ctx context.Context) (ch *btypes.C
 	return &c, nil
 }
 
+// enqueueNextScheduledChangesetFmtstr skips changesets whose batch change has
+// a rollout policy that doesn't allow publishing changesets right now.
+// Changesets of batch changes whose policy prioritizes by activity are enqueued
+// first, starting with the most recently changed repositories.
 const enqueueNextScheduledChangesetFmtstr = `
 WITH c AS (
-	SELECT *
+	SELECT changesets.id
 	FROM changesets
-	WHERE reconciler_state = %s
-	ORDER BY updated_at ASC
+	LEFT JOIN batch_change_rollout_policies p ON p.batch_change_id = changesets.owned_by_batch_change_id
+	LEFT JOIN gitserver_repos gr ON gr.repo_id = changesets.repo_id AND p.prioritize_by_activity
+	WHERE
+		changesets.reconciler_state = %s
+		AND (
+			p.batch_change_id IS NULL
+			OR (
+				p.paused_at IS NULL
+				AND (
+					p.window_started_at IS NULL
+					OR p.window_started_at + p.window_seconds * INTERVAL '1 second' <= %s
+					OR p.published_in_window < p.max_changesets
+				)
+			)
+		)
+	ORDER BY gr.last_changed DESC NULLS LAST, changesets.updated_at ASC
 	LIMIT 1
 )
 UPDATE changesets
@@ -45,6 +45,8 @@ func TestIntegration(t *testing.T) {
 		t.Run("BatchSpecWorkspaceExecutionJobs", storeTest(db, nil, testStoreBatchSpecWorkspaceExecutionJobs))
 		t.Run("BatchSpecResolutionJobs", storeTest(db, nil, testStoreBatchSpecResolutionJobs))
 		t.Run("BatchSpecExecutionCacheEntries", storeTest(db, nil, testStoreBatchSpecExecutionCacheEntries))
+		t.Run("BatchChangeRolloutPolicies", storeTest(db, nil, testStoreBatchChangeRolloutPolicies))
+		t.Run("BatchChangeApprovals", storeTest(db, nil, testStoreBatchChangeApprovals))
 
 		for name, key := range map[string]encryption.Key{
 			"no key":   nil,
@@ -0,0 +1,266 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"go.opentelemetry.io/otel/attribute"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+var rolloutPolicyColumns = []*sqlf.Query{
+	sqlf.Sprintf("batch_change_rollout_policies.batch_change_id"),
+	sqlf.Sprintf("batch_change_rollout_policies.max_changesets"),
+	sqlf.Sprintf("batch_change_rollout_policies.window_seconds"),
+	sqlf.Sprintf("batch_change_rollout_policies.prioritize_by_activity"),
+	sqlf.Sprintf("batch_change_rollout_policies.min_merge_rate"),
+	sqlf.Sprintf("batch_change_rollout_policies.max_ci_failure_rate"),
+	sqlf.Sprintf("batch_change_rollout_policies.window_started_at"),
+	sqlf.Sprintf("batch_change_rollout_policies.published_in_window"),
+	sqlf.Sprintf("batch_change_rollout_policies.paused_at"),
+	sqlf.Sprintf("batch_change_rollout_policies.pause_reason"),
+	sqlf.Sprintf("batch_change_rollout_policies.created_at"),
+	sqlf.Sprintf("batch_change_rollout_policies.updated_at"),
+}
+
+// UpsertBatchChangeRolloutPolicy creates the rollout policy of a batch change,
+// or updates its settings if it already exists. The state of the current
+// window and whether the rollout is paused are retained.
+func (s *Store) UpsertBatchChangeRolloutPolicy(ctx context.Context, p *btypes.BatchChangeRolloutPolicy) (err error) {
+	ctx, _, endObservation := s.operations.upsertBatchChangeRolloutPolicy.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("batchChangeID", int(p.BatchChangeID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	now := s.now()
+	q := sqlf.Sprintf(
+		upsertBatchChangeRolloutPolicyQueryFmtstr,
+		p.BatchChangeID,
+		p.MaxChangesets,
+		int(p.Window/time.Second),
+		p.PrioritizeByActivity,
+		p.MinMergeRate,
+		p.MaxCIFailureRate,
+		now,
+		now,
+		sqlf.Join(rolloutPolicyColumns, ", "),
+	)
+
+	return s.query(ctx, q, func(sc dbutil.Scanner) error {
+		return scanRolloutPolicy(p, sc)
+	})
+}
+
+const upsertBatchChangeRolloutPolicyQueryFmtstr = `
+INSERT INTO batch_change_rollout_policies (
+	batch_change_id,
+	max_changesets,
+	window_seconds,
+	prioritize_by_activity,
+	min_merge_rate,
+	max_ci_failure_rate,
+	created_at,
+	updated_at
+)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
+ON CONFLICT (batch_change_id) DO UPDATE SET
+	max_changesets = EXCLUDED.max_changesets,
+	window_seconds = EXCLUDED.window_seconds,
+	prioritize_by_activity = EXCLUDED.prioritize_by_activity,
+	min_merge_rate = EXCLUDED.min_merge_rate,
+	max_ci_failure_rate = EXCLUDED.max_ci_failure_rate,
+	updated_at = EXCLUDED.updated_at
+RETURNING %s
+`
+
+// GetBatchChangeRolloutPolicyOpts captures the query options needed for
+// getting a rollout policy.
+type GetBatchChangeRolloutPolicyOpts struct {
+	BatchChangeID int64
+	// ForUpdate locks the policy until the end of the transaction.
+	ForUpdate bool
+}
+
+// GetBatchChangeRolloutPolicy gets the rollout policy of a batch change. If
+// the batch change has no rollout policy, ErrNoResults is returned.
+func (s *Store) GetBatchChangeRolloutPolicy(ctx context.Context, opts GetBatchChangeRolloutPolicyOpts) (p *btypes.BatchChangeRolloutPolicy, err error) {
+	ctx, _, endObservation := s.operations.getBatchChangeRolloutPolicy.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("batchChangeID", int(opts.BatchChangeID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	forUpdate := &sqlf.Query{}
+	if opts.ForUpdate {
+		forUpdate = sqlf.Sprintf("FOR UPDATE")
+	}
+
+	q := sqlf.Sprintf(
+		getBatchChangeRolloutPolicyQueryFmtstr,
+		sqlf.Join(rolloutPolicyColumns, ", "),
+		opts.BatchChangeID,
+		forUpdate,
+	)
+
+	var policy btypes.BatchChangeRolloutPolicy
+	err = s.query(ctx, q, func(sc dbutil.Scanner) error {
+		return scanRolloutPolicy(&policy, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.BatchChangeID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &policy, nil
+}
+
+const getBatchChangeRolloutPolicyQueryFmtstr = `
+SELECT %s
+FROM batch_change_rollout_policies
+WHERE batch_change_id = %s
+%s  -- optional FOR UPDATE
+`
+
+// UpdateBatchChangeRolloutPolicyState persists the state of the current window
+// and whether the rollout is paused.
+func (s *Store) UpdateBatchChangeRolloutPolicyState(ctx context.Context, p *btypes.BatchChangeRolloutPolicy) (err error) {
+	ctx, _, endObservation := s.operations.updateBatchChangeRolloutPolicyState.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("batchChangeID", int(p.BatchChangeID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	q := sqlf.Sprintf(
+		updateBatchChangeRolloutPolicyStateQueryFmtstr,
+		dbutil.NullTimeColumn(p.WindowStartedAt),
+		p.PublishedInWindow,
+		dbutil.NullTimeColumn(p.PausedAt),
+		dbutil.NewNullString(p.PauseReason),
+		s.now(),
+		p.BatchChangeID,
+		sqlf.Join(rolloutPolicyColumns, ", "),
+	)
+
+	updated := btypes.BatchChangeRolloutPolicy{}
+	err = s.query(ctx, q, func(sc dbutil.Scanner) error {
+		return scanRolloutPolicy(&updated, sc)
+	})
+	if err != nil {
+		return err
+	}
+
+	if updated.BatchChangeID == 0 {
+		return ErrNoResults
+	}
+	*p = updated
+	return nil
+}
+
+const updateBatchChangeRolloutPolicyStateQueryFmtstr = `
+UPDATE batch_change_rollout_policies
+SET
+	window_started_at = %s,
+	published_in_window = %s,
+	paused_at = %s,
+	pause_reason = %s,
+	updated_at = %s
+WHERE batch_change_id = %s
+RETURNING %s
+`
+
+// DeleteBatchChangeRolloutPolicy deletes the rollout policy of a batch change.
+// Changesets that are waiting for the rollout are published according to the
+// rollout windows of the site afterwards.
+func (s *Store) DeleteBatchChangeRolloutPolicy(ctx context.Context, batchChangeID int64) (err error) {
+	ctx, _, endObservation := s.operations.deleteBatchChangeRolloutPolicy.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("batchChangeID", int(batchChangeID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.Exec(ctx, sqlf.Sprintf(deleteBatchChangeRolloutPolicyQueryFmtstr, batchChangeID))
+}
+
+const deleteBatchChangeRolloutPolicyQueryFmtstr = `
+DELETE FROM batch_change_rollout_policies WHERE batch_change_id = %s
+`
+
+// GetBatchChangeRolloutStats returns the statistics of the changesets of a
+// batch change that its rollout policy is evaluated against.
+func (s *Store) GetBatchChangeRolloutStats(ctx context.Context, batchChangeID int64) (stats btypes.RolloutStats, err error) {
+	ctx, _, endObservation := s.operations.getBatchChangeRolloutStats.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("batchChangeID", int(batchChangeID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	q := sqlf.Sprintf(
+		getBatchChangeRolloutStatsQueryFmtstr,
+		btypes.ChangesetExternalStateMerged,
+		btypes.ChangesetCheckStatePassed,
+		btypes.ChangesetCheckStateFailed,
+		btypes.ChangesetPublicationStatePublished,
+		batchChangeID,
+	)
+
+	err = s.query(ctx, q, func(sc dbutil.Scanner) error {
+		return sc.Scan(
+			&stats.Published,
+			&stats.Merged,
+			&stats.ChecksPassed,
+			&stats.ChecksFailed,
+		)
+	})
+	return stats, err
+}
+
+const getBatchChangeRolloutStatsQueryFmtstr = `
+SELECT
+	COUNT(*),
+	COUNT(*) FILTER (WHERE external_state = %s),
+	COUNT(*) FILTER (WHERE external_check_state = %s),
+	COUNT(*) FILTER (WHERE external_check_state = %s)
+FROM changesets
+WHERE
+	publication_state = %s
+	AND owned_by_batch_change_id = %s
+`
+
+func scanRolloutPolicy(p *btypes.BatchChangeRolloutPolicy, sc dbutil.Scanner) error {
+	var (
+		windowSeconds    int
+		minMergeRate     sql.NullFloat64
+		maxCIFailureRate sql.NullFloat64
+	)
+	if err := sc.Scan(
+		&p.BatchChangeID,
+		&p.MaxChangesets,
+		&windowSeconds,
+		&p.PrioritizeByActivity,
+		&minMergeRate,
+		&maxCIFailureRate,
+		&dbutil.NullTime{Time: &p.WindowStartedAt},
+		&p.PublishedInWindow,
+		&dbutil.NullTime{Time: &p.PausedAt},
+		&dbutil.NullString{S: &p.PauseReason},
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	p.Window = time.Duration(windowSeconds) * time.Second
+	p.MinMergeRate = nil
+	if minMergeRate.Valid {
+		p.MinMergeRate = &minMergeRate.Float64
+	}
+	p.MaxCIFailureRate = nil
+	if maxCIFailureRate.Valid {
+		p.MaxCIFailureRate = &maxCIFailureRate.Float64
+	}
+	return nil
+}
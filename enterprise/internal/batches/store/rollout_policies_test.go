@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bt "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+)
+
+func testStoreBatchChangeRolloutPolicies(t *testing.T, ctx context.Context, s *Store, clock bt.Clock) {
+	user := bt.CreateTestUser(t, s.DatabaseDB(), false)
+	batchSpec := bt.CreateBatchSpec(t, ctx, s, "rollout", user.ID, 0)
+	batchChange := bt.CreateBatchChange(t, ctx, s, "rollout", user.ID, batchSpec.ID)
+
+	t.Run("Get without policy", func(t *testing.T) {
+		_, err := s.GetBatchChangeRolloutPolicy(ctx, GetBatchChangeRolloutPolicyOpts{BatchChangeID: batchChange.ID})
+		if err != ErrNoResults {
+			t.Fatalf("unexpected error: want=%v have=%v", ErrNoResults, err)
+		}
+	})
+
+	mergeRate := 0.5
+	policy := &btypes.BatchChangeRolloutPolicy{
+		BatchChangeID: batchChange.ID,
+		MaxChangesets: 5,
+		Window:        24 * time.Hour,
+		MinMergeRate:  &mergeRate,
+	}
+
+	t.Run("Upsert", func(t *testing.T) {
+		if err := s.UpsertBatchChangeRolloutPolicy(ctx, policy); err != nil {
+			t.Fatal(err)
+		}
+		if policy.CreatedAt.IsZero() {
+			t.Fatal("CreatedAt should be set")
+		}
+
+		have, err := s.GetBatchChangeRolloutPolicy(ctx, GetBatchChangeRolloutPolicyOpts{BatchChangeID: batchChange.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have.MaxChangesets != 5 || have.Window != 24*time.Hour {
+			t.Fatalf("unexpected policy: %+v", have)
+		}
+		if have.MinMergeRate == nil || *have.MinMergeRate != mergeRate {
+			t.Fatalf("unexpected min merge rate: %v", have.MinMergeRate)
+		}
+		if have.MaxCIFailureRate != nil {
+			t.Fatalf("unexpected max CI failure rate: %v", *have.MaxCIFailureRate)
+		}
+	})
+
+	t.Run("UpdateState", func(t *testing.T) {
+		if !policy.TryPublish(clock.Now()) {
+			t.Fatal("publishing not allowed")
+		}
+		policy.PausedAt = clock.Now()
+		policy.PauseReason = "paused"
+		if err := s.UpdateBatchChangeRolloutPolicyState(ctx, policy); err != nil {
+			t.Fatal(err)
+		}
+
+		// Changing the settings retains the state.
+		policy.MaxChangesets = 10
+		if err := s.UpsertBatchChangeRolloutPolicy(ctx, policy); err != nil {
+			t.Fatal(err)
+		}
+
+		have, err := s.GetBatchChangeRolloutPolicy(ctx, GetBatchChangeRolloutPolicyOpts{BatchChangeID: batchChange.ID, ForUpdate: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have.MaxChangesets != 10 {
+			t.Fatalf("unexpected max changesets: %d", have.MaxChangesets)
+		}
+		if have.PublishedInWindow != 1 || have.WindowStartedAt.IsZero() {
+			t.Fatalf("window state not retained: %+v", have)
+		}
+		if !have.Paused() || have.PauseReason != "paused" {
+			t.Fatalf("pause state not retained: %+v", have)
+		}
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		stats, err := s.GetBatchChangeRolloutStats(ctx, batchChange.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats != (btypes.RolloutStats{}) {
+			t.Fatalf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := s.DeleteBatchChangeRolloutPolicy(ctx, batchChange.ID); err != nil {
+			t.Fatal(err)
+		}
+		_, err := s.GetBatchChangeRolloutPolicy(ctx, GetBatchChangeRolloutPolicyOpts{BatchChangeID: batchChange.ID})
+		if err != ErrNoResults {
+			t.Fatalf("unexpected error: want=%v have=%v", ErrNoResults, err)
+		}
+	})
+}
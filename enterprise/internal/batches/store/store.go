@@ -208,10 +208,11 @@ type operations struct {
 	listBatchSpecWorkspaceFiles  *observation.Operation
 	countBatchSpecWorkspaceFiles *observation.Operation
 
-	getBulkOperation        *observation.Operation
-	listBulkOperations      *observation.Operation
-	countBulkOperations     *observation.Operation
-	listBulkOperationErrors *observation.Operation
+	getBulkOperation               *observation.Operation
+	listBulkOperations             *observation.Operation
+	countBulkOperations            *observation.Operation
+	listBulkOperationErrors        *observation.Operation
+	listBulkOperationChangesetJobs *observation.Operation
 
 	getChangesetEvent     *observation.Operation
 	listChangesetEvents   *observation.Operation
@@ -288,6 +289,17 @@ type operations struct {
 	markUsedBatchSpecExecutionCacheEntries *observation.Operation
 	createBatchSpecExecutionCacheEntry     *observation.Operation
 	cleanBatchSpecExecutionCacheEntries    *observation.Operation
+
+	upsertBatchChangeRolloutPolicy      *observation.Operation
+	getBatchChangeRolloutPolicy         *observation.Operation
+	updateBatchChangeRolloutPolicyState *observation.Operation
+	deleteBatchChangeRolloutPolicy      *observation.Operation
+	getBatchChangeRolloutStats          *observation.Operation
+
+	setBatchChangeApprovers  *observation.Operation
+	listBatchChangeApprovers *observation.Operation
+	createBatchSpecApproval  *observation.Operation
+	listBatchSpecApprovals   *observation.Operation
 }
 
 var (
@@ -353,10 +365,11 @@ func newOperations(observationCtx *observation.Context) *operations {
 			listBatchSpecWorkspaceFiles:  op("ListBatchSpecWorkspaceFiles"),
 			countBatchSpecWorkspaceFiles: op("CountBatchSpecWorkspaceFiles"),
 
-			getBulkOperation:        op("GetBulkOperation"),
-			listBulkOperations:      op("ListBulkOperations"),
-			countBulkOperations:     op("CountBulkOperations"),
-			listBulkOperationErrors: op("ListBulkOperationErrors"),
+			getBulkOperation:               op("GetBulkOperation"),
+			listBulkOperations:             op("ListBulkOperations"),
+			countBulkOperations:            op("CountBulkOperations"),
+			listBulkOperationErrors:        op("ListBulkOperationErrors"),
+			listBulkOperationChangesetJobs: op("ListBulkOperationChangesetJobIDs"),
 
 			getChangesetEvent:     op("GetChangesetEvent"),
 			listChangesetEvents:   op("ListChangesetEvents"),
@@ -434,6 +447,17 @@ func newOperations(observationCtx *observation.Context) *operations {
 			createBatchSpecExecutionCacheEntry:     op("CreateBatchSpecExecutionCacheEntry"),
 
 			cleanBatchSpecExecutionCacheEntries: op("CleanBatchSpecExecutionCacheEntries"),
+
+			upsertBatchChangeRolloutPolicy:      op("UpsertBatchChangeRolloutPolicy"),
+			getBatchChangeRolloutPolicy:         op("GetBatchChangeRolloutPolicy"),
+			updateBatchChangeRolloutPolicyState: op("UpdateBatchChangeRolloutPolicyState"),
+			deleteBatchChangeRolloutPolicy:      op("DeleteBatchChangeRolloutPolicy"),
+			getBatchChangeRolloutStats:          op("GetBatchChangeRolloutStats"),
+
+			setBatchChangeApprovers:  op("SetBatchChangeApprovers"),
+			listBatchChangeApprovers: op("ListBatchChangeApprovers"),
+			createBatchSpecApproval:  op("CreateBatchSpecApproval"),
+			listBatchSpecApprovals:   op("ListBatchSpecApprovals"),
 		}
 	})
 
@@ -8,6 +8,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/redispubsub"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
 
@@ -33,6 +34,8 @@ var bulkOperationWorkerStoreOpts = dbworkerstore.Options[*types.ChangesetJob]{
 
 	RetryAfter:    5 * time.Second,
 	MaxNumRetries: bulkProcessorMaxNumRetries,
+
+	OnStateChange: redispubsub.RecordStateChangeHook("changeset_jobs"),
 }
 
 func NewBulkOperationWorkerStore(observationCtx *observation.Context, handle basestore.TransactableHandle) dbworkerstore.Store[*types.ChangesetJob] {
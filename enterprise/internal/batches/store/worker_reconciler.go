@@ -11,9 +11,9 @@ import (
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
 
-// reconcilerMaxNumRetries is the maximum number of attempts the reconciler
+// ReconcilerMaxNumRetries is the maximum number of attempts the reconciler
 // makes to process a changeset when it fails.
-const reconcilerMaxNumRetries = 10
+const ReconcilerMaxNumRetries = 10
 
 // reconcilerMaxNumResets is the maximum number of attempts the reconciler
 // makes to process a changeset when it stalls (process crashes, etc.).
@@ -37,7 +37,7 @@ var reconcilerWorkerStoreOpts = dbworkerstore.Options[*types.Changeset]{
 	MaxNumResets:  reconcilerMaxNumResets,
 
 	RetryAfter:    5 * time.Second,
-	MaxNumRetries: reconcilerMaxNumRetries,
+	MaxNumRetries: ReconcilerMaxNumRetries,
 }
 
 func NewReconcilerWorkerStore(observationCtx *observation.Context, handle basestore.TransactableHandle) dbworkerstore.Store[*types.Changeset] {
@@ -10,10 +10,12 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	bgql "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/graphql"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/state"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/webhooks"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/batches"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
@@ -496,7 +498,16 @@ func (s *changesetSyncer) SyncChangeset(ctx context.Context, id int64) error {
 		return err
 	}
 
-	return SyncChangeset(ctx, s.syncStore, gitserver.NewClient(), source, repo, cs)
+	wasMerged := cs.ExternalState == btypes.ChangesetExternalStateMerged
+	if err := SyncChangeset(ctx, s.syncStore, gitserver.NewClient(), source, repo, cs); err != nil {
+		return err
+	}
+
+	if !wasMerged && cs.ExternalState == btypes.ChangesetExternalStateMerged {
+		webhooks.EnqueueChangeset(ctx, syncLogger, s.syncStore.DatabaseDB(), webhooks.ChangesetMerge, bgql.MarshalChangesetID(cs.ID))
+	}
+
+	return nil
 }
 
 // SyncChangeset refreshes the metadata of the given changeset and
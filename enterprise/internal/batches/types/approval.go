@@ -0,0 +1,27 @@
+package types
+
+import "time"
+
+// BatchSpecApproval is the sign-off of an approver of a batch change on a
+// batch spec that is applied to it.
+type BatchSpecApproval struct {
+	BatchSpecID int64
+	UserID      int32
+	CreatedAt   time.Time
+}
+
+// MissingApprovers returns the approvers that haven't approved yet.
+func MissingApprovers(approvers []int32, approvals []*BatchSpecApproval) []int32 {
+	approved := make(map[int32]struct{}, len(approvals))
+	for _, a := range approvals {
+		approved[a.UserID] = struct{}{}
+	}
+
+	var missing []int32
+	for _, id := range approvers {
+		if _, ok := approved[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMissingApprovers(t *testing.T) {
+	approvals := []*BatchSpecApproval{{UserID: 2}, {UserID: 4}}
+
+	for name, tc := range map[string]struct {
+		approvers []int32
+		want      []int32
+	}{
+		"no approvers":      {approvers: nil, want: nil},
+		"all approved":      {approvers: []int32{2, 4}, want: nil},
+		"some missing":      {approvers: []int32{1, 2, 3, 4}, want: []int32{1, 3}},
+		"approvals ignored": {approvers: []int32{5}, want: []int32{5}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, MissingApprovers(tc.approvers, approvals)); diff != "" {
+				t.Errorf("unexpected missing approvers (-want +have):\n%s", diff)
+			}
+		})
+	}
+}
@@ -77,13 +77,18 @@ func (c *BatchChange) URL(ctx context.Context, namespaceName string) (string, er
 		return "", errors.Wrap(err, "parsing external Sourcegraph URL")
 	}
 
+	u := extURL.ResolveReference(&url.URL{Path: c.Path(namespaceName)})
+
+	return u.String(), nil
+}
+
+// Path returns the URL path of the batch change, relative to the external URL.
+func (c *BatchChange) Path(namespaceName string) string {
 	// This needs to be kept consistent with resolvers.batchChangeURL().
 	// (Refactoring the resolver to use the same function is difficult due to
 	// the different querying and caching behaviour in GraphQL resolvers, so we
 	// simply replicate the logic here.)
-	u := extURL.ResolveReference(&url.URL{Path: namespaceURL(c.NamespaceOrgID, namespaceName) + "/batch-changes/" + c.Name})
-
-	return u.String(), nil
+	return namespaceURL(c.NamespaceOrgID, namespaceName) + "/batch-changes/" + c.Name
 }
 
 // ToGraphQL returns the GraphQL representation of the state.
@@ -74,6 +74,7 @@ func NewChangesetSpecFromSpec(spec *batcheslib.ChangesetSpec) (*ChangesetSpec, e
 		c.CommitMessage = commitMsg
 		c.CommitAuthorName = authorName
 		c.CommitAuthorEmail = authorEmail
+		c.Reviewers = spec.Reviewers
 	}
 
 	c.computeForkNamespace(spec.Fork)
@@ -114,6 +115,7 @@ type ChangesetSpec struct {
 	CommitMessage     string
 	CommitAuthorName  string
 	CommitAuthorEmail string
+	Reviewers         []string
 
 	ForkNamespace *string
 }
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// MinRolloutSampleSize is the number of published changesets (for the merge
+// rate) or changesets with completed checks (for the CI failure rate) required
+// before a rollout policy pauses a rollout based on these rates.
+const MinRolloutSampleSize = 10
+
+// BatchChangeRolloutPolicy limits how many changesets of a batch change are
+// published per time window.
+type BatchChangeRolloutPolicy struct {
+	BatchChangeID int64
+
+	// MaxChangesets is the number of changesets that may be published per
+	// Window.
+	MaxChangesets int
+	Window        time.Duration
+
+	// PrioritizeByActivity makes changesets in repositories that were changed
+	// most recently get published first.
+	PrioritizeByActivity bool
+
+	// MinMergeRate and MaxCIFailureRate are optional thresholds between 0 and
+	// 1. When either is crossed, the rollout is paused.
+	MinMergeRate     *float64
+	MaxCIFailureRate *float64
+
+	WindowStartedAt   time.Time
+	PublishedInWindow int
+
+	PausedAt    time.Time
+	PauseReason string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Paused returns whether the rollout is paused.
+func (p *BatchChangeRolloutPolicy) Paused() bool {
+	return !p.PausedAt.IsZero()
+}
+
+// TryPublish records the publication of a changeset at the given time, if the
+// policy allows it. It returns false if the rollout is paused or if the limit
+// of the current window has been reached.
+func (p *BatchChangeRolloutPolicy) TryPublish(now time.Time) bool {
+	if p.Paused() {
+		return false
+	}
+
+	if p.WindowStartedAt.IsZero() || !now.Before(p.WindowStartedAt.Add(p.Window)) {
+		p.WindowStartedAt = now
+		p.PublishedInWindow = 0
+	}
+
+	if p.PublishedInWindow >= p.MaxChangesets {
+		return false
+	}
+
+	p.PublishedInWindow++
+	return true
+}
+
+// RolloutStats are the statistics of the changesets of a batch change that
+// rollout policies are evaluated against.
+type RolloutStats struct {
+	// Published is the number of published changesets.
+	Published int
+	// Merged is the number of merged changesets.
+	Merged int
+	// ChecksPassed and ChecksFailed are the numbers of published changesets
+	// whose checks passed or failed.
+	ChecksPassed int
+	ChecksFailed int
+}
+
+// PauseReasonFor returns why the rollout must be paused given the stats, or an
+// empty string if it may continue.
+func (p *BatchChangeRolloutPolicy) PauseReasonFor(stats RolloutStats) string {
+	if p.MinMergeRate != nil && stats.Published >= MinRolloutSampleSize {
+		if rate := float64(stats.Merged) / float64(stats.Published); rate < *p.MinMergeRate {
+			return fmt.Sprintf("merge rate %.2f is below the minimum of %.2f", rate, *p.MinMergeRate)
+		}
+	}
+
+	if completed := stats.ChecksPassed + stats.ChecksFailed; p.MaxCIFailureRate != nil && completed >= MinRolloutSampleSize {
+		if rate := float64(stats.ChecksFailed) / float64(completed); rate > *p.MaxCIFailureRate {
+			return fmt.Sprintf("CI failure rate %.2f is above the maximum of %.2f", rate, *p.MaxCIFailureRate)
+		}
+	}
+
+	return ""
+}
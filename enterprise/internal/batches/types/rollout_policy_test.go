@@ -0,0 +1,88 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBatchChangeRolloutPolicy_TryPublish(t *testing.T) {
+	start := time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC)
+	p := &BatchChangeRolloutPolicy{MaxChangesets: 2, Window: 24 * time.Hour}
+
+	for i, tc := range []struct {
+		now  time.Time
+		want bool
+	}{
+		{now: start, want: true},
+		{now: start.Add(time.Hour), want: true},
+		{now: start.Add(2 * time.Hour), want: false},
+		{now: start.Add(23 * time.Hour), want: false},
+		// A new window starts once the previous one has passed.
+		{now: start.Add(24 * time.Hour), want: true},
+		{now: start.Add(25 * time.Hour), want: true},
+		{now: start.Add(26 * time.Hour), want: false},
+	} {
+		if have := p.TryPublish(tc.now); have != tc.want {
+			t.Fatalf("%d: unexpected result. want=%t have=%t", i, tc.want, have)
+		}
+	}
+
+	if want, have := start.Add(24*time.Hour), p.WindowStartedAt; !have.Equal(want) {
+		t.Errorf("unexpected window start. want=%s have=%s", want, have)
+	}
+	if want, have := 2, p.PublishedInWindow; have != want {
+		t.Errorf("unexpected published count. want=%d have=%d", want, have)
+	}
+
+	p.PausedAt = start.Add(48 * time.Hour)
+	if p.TryPublish(start.Add(72 * time.Hour)) {
+		t.Error("paused policy allowed publishing")
+	}
+}
+
+func TestBatchChangeRolloutPolicy_PauseReasonFor(t *testing.T) {
+	rate := func(r float64) *float64 { return &r }
+
+	for name, tc := range map[string]struct {
+		policy    BatchChangeRolloutPolicy
+		stats     RolloutStats
+		wantPause bool
+	}{
+		"no thresholds": {
+			stats: RolloutStats{Published: 100, ChecksFailed: 100},
+		},
+		"merge rate above minimum": {
+			policy: BatchChangeRolloutPolicy{MinMergeRate: rate(0.5)},
+			stats:  RolloutStats{Published: 10, Merged: 5},
+		},
+		"merge rate below minimum": {
+			policy:    BatchChangeRolloutPolicy{MinMergeRate: rate(0.5)},
+			stats:     RolloutStats{Published: 10, Merged: 4},
+			wantPause: true,
+		},
+		"merge rate below minimum with too few changesets": {
+			policy: BatchChangeRolloutPolicy{MinMergeRate: rate(0.5)},
+			stats:  RolloutStats{Published: MinRolloutSampleSize - 1},
+		},
+		"CI failure rate below maximum": {
+			policy: BatchChangeRolloutPolicy{MaxCIFailureRate: rate(0.2)},
+			stats:  RolloutStats{Published: 20, ChecksPassed: 8, ChecksFailed: 2},
+		},
+		"CI failure rate above maximum": {
+			policy:    BatchChangeRolloutPolicy{MaxCIFailureRate: rate(0.2)},
+			stats:     RolloutStats{Published: 20, ChecksPassed: 7, ChecksFailed: 3},
+			wantPause: true,
+		},
+		"CI failure rate above maximum with too few completed checks": {
+			policy: BatchChangeRolloutPolicy{MaxCIFailureRate: rate(0.2)},
+			stats:  RolloutStats{Published: 20, ChecksPassed: 1, ChecksFailed: 8},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			reason := tc.policy.PauseReasonFor(tc.stats)
+			if have := reason != ""; have != tc.wantPause {
+				t.Errorf("unexpected pause. want=%t have=%t (reason %q)", tc.wantPause, have, reason)
+			}
+		})
+	}
+}
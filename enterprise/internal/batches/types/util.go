@@ -24,7 +24,7 @@ type CodehostCapabilities map[CodehostCapability]bool
 func GetSupportedExternalServices() map[string]CodehostCapabilities {
 	supportedExternalServices := map[string]CodehostCapabilities{
 		extsvc.TypeGitHub:          {CodehostCapabilityLabels: true, CodehostCapabilityDraftChangesets: true},
-		extsvc.TypeBitbucketServer: {},
+		extsvc.TypeBitbucketServer: {CodehostCapabilityDraftChangesets: true},
 		extsvc.TypeGitLab:          {CodehostCapabilityLabels: true, CodehostCapabilityDraftChangesets: true},
 		extsvc.TypeBitbucketCloud:  {},
 		extsvc.TypeAzureDevOps:     {CodehostCapabilityDraftChangesets: true},
@@ -7,6 +7,7 @@ const (
 	BatchChangeClose      = "batch_change:close"
 	BatchChangeDelete     = "batch_change:delete"
 	ChangesetClose        = "changeset:close"
+	ChangesetMerge        = "changeset:merge"
 	ChangesetPublish      = "changeset:publish"
 	ChangesetPublishError = "changeset:publish_error"
 	ChangesetUpdate       = "changeset:update"
@@ -34,6 +35,11 @@ func init() {
 		Description: "sent when a changeset is closed",
 	})
 
+	outbound.RegisterEventType(outbound.EventType{
+		Key:         ChangesetMerge,
+		Description: "sent when a changeset is merged on the code host",
+	})
+
 	outbound.RegisterEventType(outbound.EventType{
 		Key:         ChangesetPublish,
 		Description: "sent when a changeset is published to the code host",
@@ -0,0 +1,5 @@
+package types
+
+// ArtifactsDir is the directory, relative to the working directory of a job, that steps write
+// artifacts to. The executor uploads every file in this directory once the job finished.
+const ArtifactsDir = ".sourcegraph-executor/artifacts"
@@ -1,3 +1,18 @@
 package types
 
 var ValidQueueNames = []string{"batches", "codeintel"}
+
+const (
+	// PriorityInteractive is the priority class of queues with jobs that users wait on, such as
+	// batch spec previews.
+	PriorityInteractive = "interactive"
+	// PriorityBulk is the priority class of queues with background work, such as auto-indexing.
+	PriorityBulk = "bulk"
+)
+
+// DefaultQueuePriorities are the priority classes of queues that are not configured in the
+// executors.multiqueue site configuration.
+var DefaultQueuePriorities = map[string]string{
+	"batches":   PriorityInteractive,
+	"codeintel": PriorityBulk,
+}
@@ -75,11 +75,12 @@ func GetBackgroundJobs(ctx context.Context, logger log.Logger, mainAppDB databas
 	if !disableHistorical {
 		searchRateLimiter := limiter.SearchQueryRate()
 		historicRateLimiter := limiter.HistoricalWorkRate()
+		commitClient := gitserver.NewGitCommitClient()
 		backfillConfig := pipeline.BackfillerConfig{
 			CompressionPlan:         compression.NewGitserverFilter(logger),
 			SearchHandlers:          queryrunner.GetSearchHandlers(),
 			InsightStore:            insightsStore,
-			CommitClient:            gitserver.NewGitCommitClient(),
+			CommitClient:            commitClient,
 			SearchPlanWorkerLimit:   1,
 			SearchRunnerWorkerLimit: 1, // TODO: this can scale with the number of searcher endpoints
 			SearchRateLimiter:       searchRateLimiter,
@@ -104,6 +105,7 @@ func GetBackgroundJobs(ctx context.Context, logger log.Logger, mainAppDB databas
 				}),
 			CostAnalyzer:      priority.DefaultQueryAnalyzer(),
 			RepoQueryExecutor: query.NewStreamingRepoQueryExecutor(logger.Scoped("StreamingRepoExecutor", "execute repo search in background workers")),
+			GitClient:         commitClient,
 		}
 
 		// Add the backfill v2 workers
@@ -138,32 +138,10 @@ func (ie *InsightEnqueuer) EnqueueSingle(
 	mode store.PersistMode,
 	stampFunc func(ctx context.Context, insightSeries types.InsightSeries) (types.InsightSeries, error),
 ) error {
-	// Construct the search query that will generate data for this repository and time (revision) tuple.
-	defaultQueryParams := querybuilder.CodeInsightsQueryDefaults(len(series.Repositories) == 0)
 	seriesID := series.SeriesID
-	var err error
-
-	basicQuery := querybuilder.BasicQuery(series.Query)
-	var modifiedQuery querybuilder.BasicQuery
-	var finalQuery string
-
-	if series.RepositoryCriteria != nil {
-		modifiedQuery, err = querybuilder.MakeQueryWithRepoFilters(*series.RepositoryCriteria, basicQuery, true, querybuilder.CodeInsightsQueryDefaults(true)...)
-	} else if len(series.Repositories) > 0 {
-		modifiedQuery, err = querybuilder.MultiRepoQuery(basicQuery, series.Repositories, defaultQueryParams)
-	} else {
-		modifiedQuery, err = querybuilder.GlobalQuery(basicQuery, defaultQueryParams)
-	}
+	finalQuery, err := seriesQuery(series)
 	if err != nil {
-		return errors.Wrapf(err, "GlobalQuery series_id:%s", seriesID)
-	}
-	finalQuery = modifiedQuery.String()
-	if series.GroupBy != nil {
-		computeQuery, err := querybuilder.ComputeInsightCommandQuery(modifiedQuery, querybuilder.MapType(*series.GroupBy))
-		if err != nil {
-			return errors.Wrapf(err, "ComputeInsightCommandQuery series_id:%s", seriesID)
-		}
-		finalQuery = computeQuery.String()
+		return err
 	}
 
 	err = ie.enqueueQueryRunnerJob(ctx, &queryrunner.Job{
@@ -191,3 +169,40 @@ func (ie *InsightEnqueuer) EnqueueSingle(
 	ie.logger.Info("queued global search for insight", log.String("persist mode", string(mode)), log.String("seriesID", series.SeriesID))
 	return nil
 }
+
+// seriesQuery returns the query a query runner job for the series executes.
+func seriesQuery(series types.InsightSeries) (string, error) {
+	// Computed series don't run searches, the query runner evaluates their expression instead.
+	if series.GenerationMethod == types.Computed {
+		return series.Query, nil
+	}
+
+	// Construct the search query that will generate data for this repository and time (revision) tuple.
+	defaultQueryParams := querybuilder.CodeInsightsQueryDefaults(len(series.Repositories) == 0)
+	seriesID := series.SeriesID
+	var err error
+
+	basicQuery := querybuilder.BasicQuery(series.Query)
+	var modifiedQuery querybuilder.BasicQuery
+	var finalQuery string
+
+	if series.RepositoryCriteria != nil {
+		modifiedQuery, err = querybuilder.MakeQueryWithRepoFilters(*series.RepositoryCriteria, basicQuery, true, querybuilder.CodeInsightsQueryDefaults(true)...)
+	} else if len(series.Repositories) > 0 {
+		modifiedQuery, err = querybuilder.MultiRepoQuery(basicQuery, series.Repositories, defaultQueryParams)
+	} else {
+		modifiedQuery, err = querybuilder.GlobalQuery(basicQuery, defaultQueryParams)
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "GlobalQuery series_id:%s", seriesID)
+	}
+	finalQuery = modifiedQuery.String()
+	if series.GroupBy != nil {
+		computeQuery, err := querybuilder.ComputeInsightCommandQuery(modifiedQuery, querybuilder.MapType(*series.GroupBy))
+		if err != nil {
+			return "", errors.Wrapf(err, "ComputeInsightCommandQuery series_id:%s", seriesID)
+		}
+		finalQuery = computeQuery.String()
+	}
+	return finalQuery, nil
+}
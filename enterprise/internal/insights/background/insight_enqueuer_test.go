@@ -185,3 +185,25 @@ func Test_discoverAndEnqueueInsights(t *testing.T) {
   }
 ]`).Equal(t, string(enqueuedJSON))
 }
+
+func Test_seriesQuery(t *testing.T) {
+	t.Run("search", func(t *testing.T) {
+		query, err := seriesQuery(types.InsightSeries{SeriesID: "series1", Query: "query1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		autogold.Expect("fork:no archived:no patterntype:literal count:99999999 query1").Equal(t, query)
+	})
+
+	t.Run("computed", func(t *testing.T) {
+		query, err := seriesQuery(types.InsightSeries{
+			SeriesID:         "series1",
+			Query:            "precise_indexed_repos / repos * 100",
+			GenerationMethod: types.Computed,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		autogold.Expect("precise_indexed_repos / repos * 100").Equal(t, query)
+	})
+}
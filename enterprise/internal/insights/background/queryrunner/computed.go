@@ -0,0 +1,46 @@
+package queryrunner
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/computed"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// makeComputedHandler returns a handler that records a single point per job
+// for computed series. The value is calculated from the expression stored as
+// the query of the series, using metrics read from the main database.
+func makeComputedHandler(db *basestore.Store) InsightsHandler {
+	return func(ctx context.Context, job *SearchJob, series *types.InsightSeries, recordTime time.Time) ([]store.RecordSeriesPointArgs, error) {
+		expr, err := computed.Parse(series.Query)
+		if err != nil {
+			return nil, errors.Wrap(err, "computed.Parse")
+		}
+		value, err := computed.Evaluate(ctx, db, expr)
+		if err != nil {
+			return nil, errors.Wrap(err, "computed.Evaluate")
+		}
+
+		args := make([]store.RecordSeriesPointArgs, 0, len(job.DependentFrames)+1)
+		base := store.RecordSeriesPointArgs{
+			SeriesID: job.SeriesID,
+			Point: store.SeriesPoint{
+				SeriesID: job.SeriesID,
+				Time:     recordTime,
+				Value:    value,
+			},
+			PersistMode: store.PersistMode(job.PersistMode),
+		}
+		args = append(args, base)
+		for _, dependent := range job.DependentFrames {
+			arg := base
+			arg.Point.Time = dependent
+			args = append(args, arg)
+		}
+		return args, nil
+	}
+}
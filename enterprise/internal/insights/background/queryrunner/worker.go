@@ -57,6 +57,11 @@ func NewWorker(ctx context.Context, logger log.Logger, workerStore *workerStoreE
 
 	sharedCache := make(map[string]*types.InsightSeries)
 
+	// Computed series read their metrics from the main database, which also
+	// stores the worker state.
+	searchHandlers := GetSearchHandlers()
+	searchHandlers[types.Computed] = makeComputedHandler(basestore.NewWithHandle(workerStore.Handle()))
+
 	prometheus.DefaultRegisterer.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
 		Name: "src_query_runner_worker_total",
 		Help: "Total number of jobs in the queued state.",
@@ -76,7 +81,7 @@ func NewWorker(ctx context.Context, logger log.Logger, workerStore *workerStoreE
 		limiter:         limiter,
 		metadadataStore: store.NewInsightStoreWith(insightsStore),
 		seriesCache:     sharedCache,
-		searchHandlers:  GetSearchHandlers(),
+		searchHandlers:  searchHandlers,
 		logger:          log.Scoped("insights.queryRunner.Handler", ""),
 	}, options)
 }
@@ -0,0 +1,270 @@
+// Package computed implements computed insight series, whose points are
+// calculated from an expression over metrics of the Sourcegraph instance
+// instead of from search results.
+package computed
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Expr is a parsed computed series expression. Expressions combine metrics
+// and numbers with the operators +, -, *, / and parentheses, for example
+// `precise_indexed_repos / repos * 100`.
+type Expr interface {
+	// Eval evaluates the expression given the values of its metrics.
+	Eval(values map[string]float64) (float64, error)
+
+	String() string
+}
+
+// Parse parses a computed series expression. All metrics referenced by the
+// expression must be known.
+func Parse(input string) (Expr, error) {
+	p := &parser{input: input}
+	p.next()
+	expr, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokenEOF {
+		return nil, errors.Newf("unexpected %q at position %d", p.tok.text, p.tok.pos)
+	}
+	for _, name := range MetricNames(expr) {
+		if _, ok := LookupMetric(name); !ok {
+			return nil, errors.Newf("unknown metric %q", name)
+		}
+	}
+	return expr, nil
+}
+
+// MetricNames returns the sorted, distinct names of the metrics referenced
+// by the expression.
+func MetricNames(expr Expr) []string {
+	seen := map[string]struct{}{}
+	var walk func(Expr)
+	walk = func(e Expr) {
+		switch e := e.(type) {
+		case metricExpr:
+			seen[string(e)] = struct{}{}
+		case binaryExpr:
+			walk(e.left)
+			walk(e.right)
+		case negateExpr:
+			walk(e.operand)
+		}
+	}
+	walk(expr)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type numberExpr float64
+
+func (e numberExpr) Eval(map[string]float64) (float64, error) { return float64(e), nil }
+func (e numberExpr) String() string                           { return strconv.FormatFloat(float64(e), 'g', -1, 64) }
+
+type metricExpr string
+
+func (e metricExpr) Eval(values map[string]float64) (float64, error) {
+	v, ok := values[string(e)]
+	if !ok {
+		return 0, errors.Newf("no value for metric %q", string(e))
+	}
+	return v, nil
+}
+
+func (e metricExpr) String() string { return string(e) }
+
+type negateExpr struct {
+	operand Expr
+}
+
+func (e negateExpr) Eval(values map[string]float64) (float64, error) {
+	v, err := e.operand.Eval(values)
+	return -v, err
+}
+
+func (e negateExpr) String() string { return fmt.Sprintf("-%s", e.operand) }
+
+type binaryExpr struct {
+	op          byte
+	left, right Expr
+}
+
+func (e binaryExpr) Eval(values map[string]float64) (float64, error) {
+	l, err := e.left.Eval(values)
+	if err != nil {
+		return 0, err
+	}
+	r, err := e.right.Eval(values)
+	if err != nil {
+		return 0, err
+	}
+	switch e.op {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	case '/':
+		// Dividing by zero yields zero, so that ratios over empty sets of
+		// repositories don't fail the whole series.
+		if r == 0 {
+			return 0, nil
+		}
+		return l / r, nil
+	}
+	return 0, errors.Newf("unknown operator %q", e.op)
+}
+
+func (e binaryExpr) String() string {
+	return fmt.Sprintf("(%s %c %s)", e.left, e.op, e.right)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenInvalid
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type parser struct {
+	input string
+	pos   int
+	tok   token
+}
+
+func (p *parser) next() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.input) {
+		p.tok = token{kind: tokenEOF, pos: start}
+		return
+	}
+
+	c := p.input[p.pos]
+	switch {
+	case c == '+' || c == '-' || c == '*' || c == '/':
+		p.pos++
+		p.tok = token{kind: tokenOperator, text: string(c), pos: start}
+	case c == '(':
+		p.pos++
+		p.tok = token{kind: tokenLeftParen, text: "(", pos: start}
+	case c == ')':
+		p.pos++
+		p.tok = token{kind: tokenRightParen, text: ")", pos: start}
+	case c == '.' || (c >= '0' && c <= '9'):
+		for p.pos < len(p.input) && (p.input[p.pos] == '.' || (p.input[p.pos] >= '0' && p.input[p.pos] <= '9')) {
+			p.pos++
+		}
+		p.tok = token{kind: tokenNumber, text: p.input[start:p.pos], pos: start}
+	case c == '_' || unicode.IsLetter(rune(c)):
+		for p.pos < len(p.input) && (p.input[p.pos] == '_' || unicode.IsLetter(rune(p.input[p.pos])) || unicode.IsDigit(rune(p.input[p.pos]))) {
+			p.pos++
+		}
+		p.tok = token{kind: tokenIdent, text: strings.ToLower(p.input[start:p.pos]), pos: start}
+	default:
+		p.pos++
+		p.tok = token{kind: tokenInvalid, text: string(c), pos: start}
+	}
+}
+
+// parseSum parses terms separated by + and -.
+func (p *parser) parseSum() (Expr, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokenOperator && (p.tok.text == "+" || p.tok.text == "-") {
+		op := p.tok.text[0]
+		p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseProduct parses factors separated by * and /.
+func (p *parser) parseProduct() (Expr, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokenOperator && (p.tok.text == "*" || p.tok.text == "/") {
+		op := p.tok.text[0]
+		p.next()
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseFactor() (Expr, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenNumber:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, errors.Newf("invalid number %q at position %d", tok.text, tok.pos)
+		}
+		p.next()
+		return numberExpr(v), nil
+	case tokenIdent:
+		p.next()
+		return metricExpr(tok.text), nil
+	case tokenOperator:
+		if tok.text == "-" {
+			p.next()
+			operand, err := p.parseFactor()
+			if err != nil {
+				return nil, err
+			}
+			return negateExpr{operand: operand}, nil
+		}
+	case tokenLeftParen:
+		p.next()
+		expr, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokenRightParen {
+			return nil, errors.Newf("expected ) at position %d", p.tok.pos)
+		}
+		p.next()
+		return expr, nil
+	case tokenEOF:
+		return nil, errors.New("unexpected end of expression")
+	}
+	return nil, errors.Newf("unexpected %q at position %d", tok.text, tok.pos)
+}
@@ -0,0 +1,59 @@
+package computed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	values := map[string]float64{
+		"repos":                 200,
+		"precise_indexed_repos": 50,
+		"repos_with_codeowners": 20,
+	}
+
+	for _, tc := range []struct {
+		input   string
+		want    float64
+		metrics []string
+	}{
+		{input: "repos", want: 200, metrics: []string{"repos"}},
+		{input: "42", want: 42, metrics: []string{}},
+		{input: "precise_indexed_repos / repos * 100", want: 25, metrics: []string{"precise_indexed_repos", "repos"}},
+		{input: "repos - precise_indexed_repos - repos_with_codeowners", want: 130, metrics: []string{"precise_indexed_repos", "repos", "repos_with_codeowners"}},
+		{input: "(repos - precise_indexed_repos) / 2", want: 75, metrics: []string{"precise_indexed_repos", "repos"}},
+		{input: "-repos + 1.5 * 2", want: -197, metrics: []string{"repos"}},
+		{input: "  REPOS  ", want: 200, metrics: []string{"repos"}},
+		{input: "repos / 0", want: 0, metrics: []string{"repos"}},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			expr, err := Parse(tc.input)
+			require.NoError(t, err)
+
+			have, err := expr.Eval(values)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, have)
+			assert.Equal(t, tc.metrics, MetricNames(expr))
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"repos +",
+		"(repos",
+		"repos)",
+		"repos repos",
+		"unknown_metric",
+		"repos % 2",
+		"1..2",
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := Parse(input)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package computed
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Metric is a value of the Sourcegraph instance that computed series
+// expressions can refer to by name.
+type Metric struct {
+	Name        string
+	Description string
+
+	query string
+}
+
+// Metrics lists the metrics available to computed series expressions.
+var Metrics = []Metric{
+	{
+		Name:        "repos",
+		Description: "Number of repositories.",
+		query:       `SELECT COUNT(*) FROM repo WHERE deleted_at IS NULL AND blocked IS NULL`,
+	},
+	{
+		Name:        "repos_with_codeowners",
+		Description: "Number of repositories with an uploaded CODEOWNERS file.",
+		query: `
+SELECT COUNT(*) FROM codeowners
+JOIN repo ON repo.id = codeowners.repo_id
+WHERE repo.deleted_at IS NULL AND repo.blocked IS NULL`,
+	},
+	{
+		Name:        "precise_indexed_repos",
+		Description: "Number of repositories with at least one completed precise code intelligence upload.",
+		query: `
+SELECT COUNT(DISTINCT u.repository_id) FROM lsif_uploads u
+JOIN repo ON repo.id = u.repository_id
+WHERE u.state = 'completed' AND repo.deleted_at IS NULL AND repo.blocked IS NULL`,
+	},
+	{
+		Name:        "embedded_repos",
+		Description: "Number of repositories with at least one completed embeddings job.",
+		query: `
+SELECT COUNT(DISTINCT j.repo_id) FROM repo_embedding_jobs j
+JOIN repo ON repo.id = j.repo_id
+WHERE j.state = 'completed' AND repo.deleted_at IS NULL AND repo.blocked IS NULL`,
+	},
+	{
+		Name:        "users",
+		Description: "Number of users.",
+		query:       `SELECT COUNT(*) FROM users WHERE deleted_at IS NULL`,
+	},
+	{
+		Name:        "batch_changes",
+		Description: "Number of open batch changes.",
+		query:       `SELECT COUNT(*) FROM batch_changes WHERE closed_at IS NULL AND last_applied_at IS NOT NULL`,
+	},
+}
+
+// LookupMetric returns the metric with the given name.
+func LookupMetric(name string) (Metric, bool) {
+	for _, m := range Metrics {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Metric{}, false
+}
+
+// Evaluate evaluates the expression against the current values of its
+// metrics, which are read from the main Sourcegraph database.
+func Evaluate(ctx context.Context, db *basestore.Store, expr Expr) (float64, error) {
+	values := map[string]float64{}
+	for _, name := range MetricNames(expr) {
+		metric, ok := LookupMetric(name)
+		if !ok {
+			return 0, errors.Newf("unknown metric %q", name)
+		}
+		value, _, err := basestore.ScanFirstFloat(db.Query(ctx, sqlf.Sprintf(metric.query)))
+		if err != nil {
+			return 0, errors.Wrapf(err, "metric %q", name)
+		}
+		values[name] = value
+	}
+	return expr.Eval(values)
+}
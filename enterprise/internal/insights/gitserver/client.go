@@ -32,3 +32,15 @@ func (g *GitCommitClient) RecentCommits(ctx context.Context, repoName api.RepoNa
 	}
 	return g.Gitclient.Commits(ctx, authz.DefaultSubRepoPermsChecker, repoName, options)
 }
+
+// ListTags returns all tags of the repository.
+func (g *GitCommitClient) ListTags(ctx context.Context, repoName api.RepoName) ([]*gitdomain.Tag, error) {
+	return g.Gitclient.ListTags(ctx, repoName)
+}
+
+// CommitsBefore returns up to n commits of the default branch created before
+// the target time, newest first.
+func (g *GitCommitClient) CommitsBefore(ctx context.Context, repoName api.RepoName, target time.Time, n uint) ([]*gitdomain.Commit, error) {
+	options := gitserver.CommitsOptions{N: n, Before: target.Format(time.RFC3339), DateOrder: true}
+	return g.Gitclient.Commits(ctx, authz.DefaultSubRepoPermsChecker, repoName, options)
+}
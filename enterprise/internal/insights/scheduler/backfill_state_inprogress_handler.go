@@ -17,7 +17,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/pipeline"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/scheduler/iterator"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
-	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/timeseries"
 	itypes "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -357,10 +356,25 @@ func (h *inProgressHandler) load(ctx context.Context, logger log.Logger, backfil
 		return nil, errors.Wrap(err, "repoIterator")
 	}
 
-	sampleTimes := timeseries.BuildSampleTimes(12, timeseries.TimeInterval{
-		Unit:  itypes.IntervalUnit(series.SampleIntervalUnit),
-		Value: series.SampleIntervalValue,
-	}, series.CreatedAt.Truncate(time.Minute))
+	var sampleTimes []time.Time
+	if series.SamplingStrategy.RequiresRepositories() {
+		// Sample times that depend on the history of repositories were
+		// determined when the backfill was created. Recomputing them could
+		// yield different times if new tags or commits were pushed since.
+		createdAt := series.CreatedAt.Truncate(time.Minute)
+		recordingTimes, err := h.insightsStore.GetInsightSeriesRecordingTimes(ctx, series.ID, store.SeriesPointsOpts{To: &createdAt})
+		if err != nil {
+			return nil, errors.Wrap(err, "GetInsightSeriesRecordingTimes")
+		}
+		for _, t := range recordingTimes.RecordingTimes {
+			sampleTimes = append(sampleTimes, t.Timestamp)
+		}
+	} else {
+		sampleTimes, err = buildSampleTimes(ctx, nil, series)
+		if err != nil {
+			return nil, errors.Wrap(err, "buildSampleTimes")
+		}
+	}
 
 	return &backfillExecution{
 		series:      series,
@@ -35,6 +35,7 @@ type newBackfillHandler struct {
 	repoIterator    discovery.SeriesRepoIterator
 	costAnalyzer    priority.QueryAnalyzer
 	timeseriesStore store.Interface
+	gitClient       SamplingGitClient
 }
 
 // makeNewBackfillWorker makes a new Worker, Resetter and Store to handle the queue of Backfill jobs that are in the state of "New"
@@ -64,6 +65,7 @@ func makeNewBackfillWorker(ctx context.Context, config JobMonitorConfig) (*worke
 		repoIterator:    discovery.NewSeriesRepoIterator(config.AllRepoIterator, config.RepoStore, config.RepoQueryExecutor),
 		costAnalyzer:    *config.CostAnalyzer,
 		timeseriesStore: config.InsightStore,
+		gitClient:       config.GitClient,
 	}
 
 	worker := dbworker.NewWorker(ctx, workerStore, workerutil.Handler[*BaseJob](&task), workerutil.WorkerOptions{
@@ -138,10 +140,10 @@ func (h *newBackfillHandler) Handle(ctx context.Context, logger log.Logger, job
 		return errors.Wrap(err, "backfill.SetScope")
 	}
 
-	sampleTimes := timeseries.BuildSampleTimes(12, timeseries.TimeInterval{
-		Unit:  types.IntervalUnit(series.SampleIntervalUnit),
-		Value: series.SampleIntervalValue,
-	}, series.CreatedAt.Truncate(time.Minute))
+	sampleTimes, err := buildSampleTimes(ctx, h.gitClient, series)
+	if err != nil {
+		return errors.Wrap(err, "buildSampleTimes")
+	}
 
 	if err := h.timeseriesStore.SetInsightSeriesRecordingTimes(ctx, []types.InsightSeriesRecordingTimes{
 		{
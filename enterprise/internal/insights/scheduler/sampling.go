@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/timeseries"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// backfillSamplePoints is the number of points a backfill records.
+const backfillSamplePoints = 12
+
+// SamplingGitClient is the subset of the insights git client that is used to
+// determine the sample times of series that are sampled by repository history.
+type SamplingGitClient interface {
+	ListTags(ctx context.Context, repoName api.RepoName) ([]*gitdomain.Tag, error)
+	CommitsBefore(ctx context.Context, repoName api.RepoName, target time.Time, n uint) ([]*gitdomain.Commit, error)
+}
+
+// buildSampleTimes returns the times a backfill of the series records points
+// at, according to the sampling strategy of the series.
+func buildSampleTimes(ctx context.Context, gitClient SamplingGitClient, series *types.InsightSeries) ([]time.Time, error) {
+	now := series.CreatedAt.Truncate(time.Minute)
+
+	switch series.SamplingStrategy {
+	case types.SamplingMonthly:
+		return timeseries.BuildMonthlySampleTimes(backfillSamplePoints, now), nil
+
+	case types.SamplingReleaseTags, types.SamplingCommits:
+		if gitClient == nil {
+			return nil, errors.Newf("no git client available to sample series by %q", series.SamplingStrategy)
+		}
+		var pattern *regexp.Regexp
+		if series.SamplingTagPattern != nil && *series.SamplingTagPattern != "" {
+			var err error
+			if pattern, err = regexp.Compile(*series.SamplingTagPattern); err != nil {
+				return nil, errors.Wrap(err, "regexp.Compile")
+			}
+		}
+
+		// The creation time of the series is always sampled, even if none of
+		// the repositories has any matching history.
+		sets := [][]time.Time{{now}}
+		for _, repo := range series.Repositories {
+			var times []time.Time
+			if series.SamplingStrategy == types.SamplingReleaseTags {
+				tags, err := gitClient.ListTags(ctx, api.RepoName(repo))
+				if err != nil {
+					return nil, errors.Wrap(err, "ListTags")
+				}
+				times = timeseries.BuildReleaseTagSampleTimes(backfillSamplePoints, tags, pattern, now)
+			} else {
+				n := uint(series.SamplingCommitInterval * backfillSamplePoints)
+				commits, err := gitClient.CommitsBefore(ctx, api.RepoName(repo), now, n)
+				if err != nil {
+					return nil, errors.Wrap(err, "CommitsBefore")
+				}
+				times = timeseries.BuildCommitSampleTimes(backfillSamplePoints, commits, series.SamplingCommitInterval, now)
+			}
+			sets = append(sets, times)
+		}
+		return timeseries.MergeSampleTimes(backfillSamplePoints, sets...), nil
+
+	default:
+		return timeseries.BuildSampleTimes(backfillSamplePoints, timeseries.TimeInterval{
+			Unit:  types.IntervalUnit(series.SampleIntervalUnit),
+			Value: series.SampleIntervalValue,
+		}, now), nil
+	}
+}
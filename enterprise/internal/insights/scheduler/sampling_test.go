@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hexops/autogold/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+)
+
+type fakeSamplingGitClient struct {
+	tags map[api.RepoName][]*gitdomain.Tag
+}
+
+func (f *fakeSamplingGitClient) ListTags(_ context.Context, repoName api.RepoName) ([]*gitdomain.Tag, error) {
+	return f.tags[repoName], nil
+}
+
+func (f *fakeSamplingGitClient) CommitsBefore(context.Context, api.RepoName, time.Time, uint) ([]*gitdomain.Commit, error) {
+	return nil, nil
+}
+
+func TestBuildSampleTimes(t *testing.T) {
+	ctx := context.Background()
+	createdAt := time.Date(2021, 12, 1, 10, 30, 15, 0, time.UTC)
+	tag := func(name string, month time.Month) *gitdomain.Tag {
+		return &gitdomain.Tag{Name: name, CreatorDate: time.Date(2021, month, 5, 0, 0, 0, 0, time.UTC)}
+	}
+	gitClient := &fakeSamplingGitClient{tags: map[api.RepoName][]*gitdomain.Tag{
+		"repo1": {tag("v1.0.0", 1), tag("v1.1.0", 3)},
+		"repo2": {tag("v2.0.0", 2), tag("latest", 4)},
+	}}
+
+	format := func(times []time.Time) (formatted []string) {
+		for _, t := range times {
+			formatted = append(formatted, t.String())
+		}
+		return formatted
+	}
+
+	t.Run("interval", func(t *testing.T) {
+		times, err := buildSampleTimes(ctx, nil, &types.InsightSeries{
+			CreatedAt:           createdAt,
+			SampleIntervalUnit:  string(types.Month),
+			SampleIntervalValue: 1,
+		})
+		require.NoError(t, err)
+		require.Len(t, times, backfillSamplePoints)
+		autogold.Expect("2021-12-01 10:30:00 +0000 UTC").Equal(t, times[len(times)-1].String())
+	})
+
+	t.Run("release tags", func(t *testing.T) {
+		times, err := buildSampleTimes(ctx, gitClient, &types.InsightSeries{
+			CreatedAt:        createdAt,
+			Repositories:     []string{"repo1", "repo2"},
+			SamplingStrategy: types.SamplingReleaseTags,
+		})
+		require.NoError(t, err)
+		autogold.Expect([]string{
+			"2021-01-05 00:00:00 +0000 UTC", "2021-02-05 00:00:00 +0000 UTC",
+			"2021-03-05 00:00:00 +0000 UTC",
+			"2021-12-01 10:30:00 +0000 UTC",
+		}).Equal(t, format(times))
+	})
+
+	t.Run("repository strategy without git client", func(t *testing.T) {
+		_, err := buildSampleTimes(ctx, nil, &types.InsightSeries{
+			CreatedAt:        createdAt,
+			Repositories:     []string{"repo1"},
+			SamplingStrategy: types.SamplingCommits,
+		})
+		require.Error(t, err)
+	})
+}
@@ -118,6 +118,9 @@ type JobMonitorConfig struct {
 	AllRepoIterator   *discovery.AllReposIterator
 	CostAnalyzer      *priority.QueryAnalyzer
 	RepoQueryExecutor query.RepoQueryExecutor
+	// GitClient is used to sample series by the history of their
+	// repositories. It is only required for such series.
+	GitClient SamplingGitClient
 }
 
 func NewBackgroundJobMonitor(ctx context.Context, config JobMonitorConfig) *BackgroundJobMonitor {
@@ -13,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/timeseries"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -414,6 +415,9 @@ func scanDataSeries(rows *sql.Rows, queryErr error) (_ []types.InsightSeries, er
 			&temp.BackfillAttempts,
 			&temp.SupportsAugmentation,
 			&temp.RepositoryCriteria,
+			&temp.SamplingStrategy,
+			&temp.SamplingTagPattern,
+			&dbutil.NullInt{N: &temp.SamplingCommitInterval},
 		); err != nil {
 			return []types.InsightSeries{}, err
 		}
@@ -713,6 +717,9 @@ func (s *InsightStore) CreateSeries(ctx context.Context, series types.InsightSer
 	if series.NextSnapshotAfter.IsZero() {
 		series.NextSnapshotAfter = NextSnapshot(s.Now())
 	}
+	if series.SamplingStrategy == "" {
+		series.SamplingStrategy = types.SamplingInterval
+	}
 	if series.OldestHistoricalAt.IsZero() {
 		// TODO(insights): this value should probably somewhere more discoverable / obvious than here
 		series.OldestHistoricalAt = s.Now().Add(-time.Hour * 24 * 7 * 26)
@@ -734,6 +741,9 @@ func (s *InsightStore) CreateSeries(ctx context.Context, series types.InsightSer
 		series.GenerationMethod,
 		series.GroupBy,
 		series.RepositoryCriteria,
+		series.SamplingStrategy,
+		series.SamplingTagPattern,
+		dbutil.NewNullInt(series.SamplingCommitInterval),
 	))
 	var id int
 	err := row.Scan(&id)
@@ -820,6 +830,8 @@ type MatchSeriesArgs struct {
 	StepIntervalValue         int
 	GenerateFromCaptureGroups bool
 	GroupBy                   *string
+	// SamplingStrategy defaults to types.SamplingInterval.
+	SamplingStrategy types.SamplingStrategy
 }
 
 func (s *InsightStore) FindMatchingSeries(ctx context.Context, args MatchSeriesArgs) (_ types.InsightSeries, found bool, _ error) {
@@ -827,9 +839,13 @@ func (s *InsightStore) FindMatchingSeries(ctx context.Context, args MatchSeriesA
 	if args.GroupBy != nil {
 		groupByClause = sqlf.Sprintf("group_by = %s", *args.GroupBy)
 	}
+	samplingStrategy := args.SamplingStrategy
+	if samplingStrategy == "" {
+		samplingStrategy = types.SamplingInterval
+	}
 	where := sqlf.Sprintf(
-		"(repositories = '{}' OR repositories is NULL) AND query = %s AND sample_interval_unit = %s AND sample_interval_value = %s AND generated_from_capture_groups = %s AND %s",
-		args.Query, args.StepIntervalUnit, args.StepIntervalValue, args.GenerateFromCaptureGroups, groupByClause,
+		"(repositories = '{}' OR repositories is NULL) AND query = %s AND sample_interval_unit = %s AND sample_interval_value = %s AND generated_from_capture_groups = %s AND %s AND sampling_strategy = %s",
+		args.Query, args.StepIntervalUnit, args.StepIntervalValue, args.GenerateFromCaptureGroups, groupByClause, samplingStrategy,
 	)
 
 	q := sqlf.Sprintf(getInsightDataSeriesSql, where)
@@ -969,8 +985,9 @@ const createInsightSeriesSql = `
 INSERT INTO insight_series (series_id, query, created_at, oldest_historical_at, last_recorded_at,
                             next_recording_after, last_snapshot_at, next_snapshot_after, repositories,
 							sample_interval_unit, sample_interval_value, generated_from_capture_groups,
-							just_in_time, generation_method, group_by, needs_migration, repository_criteria)
-VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, false, %s)
+							just_in_time, generation_method, group_by, needs_migration, repository_criteria,
+							sampling_strategy, sampling_tag_pattern, sampling_commit_interval)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, false, %s, %s, %s, %s)
 RETURNING id;`
 
 const getInsightByViewSql = `
@@ -1009,7 +1026,8 @@ const getInsightDataSeriesSql = `
 SELECT id, series_id, query, created_at, oldest_historical_at, last_recorded_at, next_recording_after,
 last_snapshot_at, next_snapshot_after, (CASE WHEN deleted_at IS NULL THEN TRUE ELSE FALSE END) AS enabled,
 sample_interval_unit, sample_interval_value, generated_from_capture_groups,
-just_in_time, generation_method, repositories, group_by, backfill_attempts, supports_augmentation, repository_criteria
+just_in_time, generation_method, repositories, group_by, backfill_attempts, supports_augmentation, repository_criteria,
+sampling_strategy, sampling_tag_pattern, sampling_commit_interval
 FROM insight_series
 WHERE %s
 `
@@ -1209,6 +1209,7 @@ func TestCreateSeries(t *testing.T) {
 			GroupBy:              &groupByRepo,
 			SupportsAugmentation: true,
 			RepositoryCriteria:   &repoCriteria,
+			SamplingStrategy:     types.SamplingInterval,
 		}
 
 		if diff := cmp.Diff(want, got); diff != "" {
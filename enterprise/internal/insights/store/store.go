@@ -888,6 +888,8 @@ type SeriesPointForExport struct {
 	RepoName         *string
 	Value            int
 	Capture          *string
+	SeriesID         string
+	SamplingStrategy types.SamplingStrategy
 }
 
 type ExportOpts struct {
@@ -954,6 +956,8 @@ func (s *Store) GetAllDataForInsightViewID(ctx context.Context, opts ExportOpts)
 			&tmp.RepoName,
 			&tmp.Value,
 			&tmp.Capture,
+			&tmp.SeriesID,
+			&tmp.SamplingStrategy,
 		); err != nil {
 			return err
 		}
@@ -980,7 +984,7 @@ func (s *Store) GetAllDataForInsightViewID(ctx context.Context, opts ExportOpts)
 }
 
 const exportCodeInsightsDataSql = `
-select iv.title, ivs.label, i.query, isrt.recording_time, rn.name, coalesce(sp.value, 0) as value, sp.capture 
+select iv.title, ivs.label, i.query, isrt.recording_time, rn.name, coalesce(sp.value, 0) as value, sp.capture, i.series_id, i.sampling_strategy
 from %s isrt
     join insight_series i on i.id = isrt.insight_series_id
     join insight_view_series ivs ON i.id = ivs.insight_series_id
@@ -1041,6 +1041,8 @@ func TestGetAllDataForInsightViewId(t *testing.T) {
 		for i, rt := range recordingTimes.RecordingTimes {
 			autogold.Expect(view.Title).Equal(t, got[i].InsightViewTitle)
 			autogold.Expect(series.Query).Equal(t, got[i].SeriesQuery)
+			autogold.Expect(series.SeriesID).Equal(t, got[i].SeriesID)
+			autogold.Expect(types.SamplingInterval).Equal(t, got[i].SamplingStrategy)
 			autogold.Expect("label").Equal(t, got[i].SeriesLabel)
 			autogold.Expect(0).Equal(t, got[i].Value)
 			autogold.Expect(rt.Timestamp).Equal(t, got[i].RecordingTime.UTC())
@@ -0,0 +1,116 @@
+package timeseries
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+)
+
+// DefaultReleaseTagPattern matches tags that look like semantic versions, such
+// as v1.2.3 or 4.5.
+var DefaultReleaseTagPattern = regexp.MustCompile(`^v?\d+\.\d+(\.\d+)?$`)
+
+// BuildMonthlySampleTimes returns the first day of each of the numPoints-1
+// months preceding now, followed by now itself.
+func BuildMonthlySampleTimes(numPoints int, now time.Time) []time.Time {
+	if numPoints <= 0 {
+		return nil
+	}
+	times := make([]time.Time, 0, numPoints)
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if first.Equal(now) {
+		first = first.AddDate(0, -1, 0)
+	}
+	for i := numPoints - 2; i >= 0; i-- {
+		times = append(times, first.AddDate(0, -i, 0))
+	}
+	return append(times, now)
+}
+
+// BuildReleaseTagSampleTimes returns the creation times of the numPoints-1
+// most recent tags matching pattern that were created before now, followed by
+// now itself. If pattern is nil, DefaultReleaseTagPattern is used.
+func BuildReleaseTagSampleTimes(numPoints int, tags []*gitdomain.Tag, pattern *regexp.Regexp, now time.Time) []time.Time {
+	if pattern == nil {
+		pattern = DefaultReleaseTagPattern
+	}
+
+	var times []time.Time
+	for _, tag := range tags {
+		if !pattern.MatchString(tag.Name) || tag.CreatorDate.IsZero() || !tag.CreatorDate.Before(now) {
+			continue
+		}
+		times = append(times, tag.CreatorDate.UTC())
+	}
+	return limitSampleTimes(numPoints, times, now)
+}
+
+// BuildCommitSampleTimes returns the committer dates of every commitInterval-th
+// commit, starting with the most recent one, followed by now itself. commits
+// must be ordered newest first.
+func BuildCommitSampleTimes(numPoints int, commits []*gitdomain.Commit, commitInterval int, now time.Time) []time.Time {
+	if commitInterval <= 0 {
+		commitInterval = 1
+	}
+
+	var times []time.Time
+	for i := 0; i < len(commits); i += commitInterval {
+		date := commitDate(commits[i])
+		if date.IsZero() || !date.Before(now) {
+			continue
+		}
+		times = append(times, date.UTC())
+	}
+	return limitSampleTimes(numPoints, times, now)
+}
+
+// MergeSampleTimes merges sample times of several repositories into a single
+// sorted list of at most numPoints times, keeping the most recent ones.
+func MergeSampleTimes(numPoints int, sets ...[]time.Time) []time.Time {
+	var times []time.Time
+	for _, set := range sets {
+		times = append(times, set...)
+	}
+	return lastN(numPoints, dedupeSorted(times))
+}
+
+func limitSampleTimes(numPoints int, times []time.Time, now time.Time) []time.Time {
+	if numPoints <= 0 {
+		return nil
+	}
+	times = lastN(numPoints-1, dedupeSorted(times))
+	return append(times, now)
+}
+
+func dedupeSorted(times []time.Time) []time.Time {
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+	deduped := make([]time.Time, 0, len(times))
+	for _, t := range times {
+		if len(deduped) > 0 && deduped[len(deduped)-1].Equal(t) {
+			continue
+		}
+		deduped = append(deduped, t)
+	}
+	return deduped
+}
+
+func lastN(n int, times []time.Time) []time.Time {
+	if n <= 0 {
+		return []time.Time{}
+	}
+	if len(times) > n {
+		return times[len(times)-n:]
+	}
+	return times
+}
+
+func commitDate(commit *gitdomain.Commit) time.Time {
+	if commit.Committer != nil {
+		return commit.Committer.Date
+	}
+	return commit.Author.Date
+}
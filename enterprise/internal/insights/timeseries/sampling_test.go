@@ -0,0 +1,98 @@
+package timeseries
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hexops/autogold/v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+)
+
+func formatTimes(times []time.Time) (formatted []string) {
+	for _, t := range times {
+		formatted = append(formatted, t.String())
+	}
+	return formatted
+}
+
+func TestBuildMonthlySampleTimes(t *testing.T) {
+	t.Run("mid month", func(t *testing.T) {
+		now := time.Date(2021, 12, 15, 10, 0, 0, 0, time.UTC)
+		autogold.Expect([]string{
+			"2021-10-01 00:00:00 +0000 UTC",
+			"2021-11-01 00:00:00 +0000 UTC",
+			"2021-12-01 00:00:00 +0000 UTC",
+			"2021-12-15 10:00:00 +0000 UTC",
+		}).Equal(t, formatTimes(BuildMonthlySampleTimes(4, now)))
+	})
+
+	t.Run("first of month", func(t *testing.T) {
+		now := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+		autogold.Expect([]string{
+			"2021-10-01 00:00:00 +0000 UTC",
+			"2021-11-01 00:00:00 +0000 UTC",
+			"2021-12-01 00:00:00 +0000 UTC",
+		}).Equal(t, formatTimes(BuildMonthlySampleTimes(3, now)))
+	})
+}
+
+func TestBuildReleaseTagSampleTimes(t *testing.T) {
+	now := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	tag := func(name string, month time.Month) *gitdomain.Tag {
+		return &gitdomain.Tag{Name: name, CreatorDate: time.Date(2021, month, 5, 0, 0, 0, 0, time.UTC)}
+	}
+	tags := []*gitdomain.Tag{
+		tag("v1.2.0", 3),
+		tag("v1.0.0", 1),
+		tag("v1.1.0", 2),
+		tag("nightly", 4),
+		tag("v1.3.0-rc1", 5),
+		tag("2.0", 6),
+		tag("v3.0.0", 12),
+	}
+
+	t.Run("default pattern", func(t *testing.T) {
+		autogold.Expect([]string{
+			"2021-02-05 00:00:00 +0000 UTC",
+			"2021-03-05 00:00:00 +0000 UTC",
+			"2021-06-05 00:00:00 +0000 UTC",
+			"2021-12-01 00:00:00 +0000 UTC",
+		}).Equal(t, formatTimes(BuildReleaseTagSampleTimes(4, tags, nil, now)))
+	})
+
+	t.Run("custom pattern", func(t *testing.T) {
+		autogold.Expect([]string{
+			"2021-04-05 00:00:00 +0000 UTC",
+			"2021-12-01 00:00:00 +0000 UTC",
+		}).Equal(t, formatTimes(BuildReleaseTagSampleTimes(4, tags, regexp.MustCompile("^nightly$"), now)))
+	})
+}
+
+func TestBuildCommitSampleTimes(t *testing.T) {
+	now := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	var commits []*gitdomain.Commit
+	for i := 1; i <= 7; i++ {
+		commits = append(commits, &gitdomain.Commit{
+			Committer: &gitdomain.Signature{Date: now.AddDate(0, 0, -i)},
+		})
+	}
+
+	autogold.Expect([]string{
+		"2021-11-24 00:00:00 +0000 UTC",
+		"2021-11-27 00:00:00 +0000 UTC",
+		"2021-11-30 00:00:00 +0000 UTC",
+		"2021-12-01 00:00:00 +0000 UTC",
+	}).Equal(t, formatTimes(BuildCommitSampleTimes(12, commits, 3, now)))
+}
+
+func TestMergeSampleTimes(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2021, 12, d, 0, 0, 0, 0, time.UTC) }
+
+	autogold.Expect([]string{
+		"2021-12-03 00:00:00 +0000 UTC",
+		"2021-12-04 00:00:00 +0000 UTC",
+		"2021-12-10 00:00:00 +0000 UTC",
+	}).Equal(t, formatTimes(MergeSampleTimes(3, []time.Time{day(1), day(4), day(10)}, []time.Time{day(3), day(10)})))
+}
@@ -111,6 +111,9 @@ type InsightSeries struct {
 	BackfillAttempts           int32
 	SupportsAugmentation       bool
 	RepositoryCriteria         *string
+	SamplingStrategy           SamplingStrategy
+	SamplingTagPattern         *string
+	SamplingCommitInterval     int
 }
 
 type IntervalUnit string
@@ -131,8 +134,30 @@ const (
 	SearchCompute  GenerationMethod = "search-compute"
 	LanguageStats  GenerationMethod = "language-stats"
 	MappingCompute GenerationMethod = "mapping-compute"
+	// Computed series record the value of an expression over instance metrics, which is stored as their query.
+	Computed GenerationMethod = "computed"
 )
 
+// SamplingStrategy determines the points in time at which an insight series is sampled during its backfill.
+type SamplingStrategy string
+
+const (
+	// SamplingInterval samples the series at its step interval.
+	SamplingInterval SamplingStrategy = "interval"
+	// SamplingMonthly samples the series on the first day of every month.
+	SamplingMonthly SamplingStrategy = "monthly"
+	// SamplingReleaseTags samples the series at the release tags of its repositories.
+	SamplingReleaseTags SamplingStrategy = "release-tags"
+	// SamplingCommits samples the series every N commits in its repositories.
+	SamplingCommits SamplingStrategy = "commits"
+)
+
+// RequiresRepositories returns whether the strategy samples based on the git history of the repositories of a
+// series, which requires the series to have an explicit list of repositories.
+func (s SamplingStrategy) RequiresRepositories() bool {
+	return s == SamplingReleaseTags || s == SamplingCommits
+}
+
 type Dashboard struct {
 	ID           int
 	Title        string
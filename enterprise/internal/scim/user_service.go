@@ -25,6 +25,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/txemail"
 	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/users/dataexport"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -303,6 +304,9 @@ func (u *UserSCIMService) Delete(ctx context.Context, id string) error {
 		if err := tx.Users().HardDelete(ctx, int32(idInt)); err != nil {
 			return err
 		}
+		if _, err := dataexport.NewStore(tx).EnqueueDeletionReport(ctx, int32(idInt), nil); err != nil {
+			return err
+		}
 
 		// NOTE: Practically, we don't reuse the ID for any new users, and the situation of left-over pending permissions
 		// is possible but highly unlikely. Therefore, there is no need to roll back user deletion even if this step failed.
@@ -2,6 +2,7 @@ package search
 
 import (
 	ownsearch "github.com/sourcegraph/sourcegraph/internal/own/search"
+	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/job"
 	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
 )
@@ -10,7 +11,15 @@ func NewEnterpriseSearchJobs() jobutil.EnterpriseJobs {
 	return &enterpriseJobs{}
 }
 
-type enterpriseJobs struct{}
+// NewEnterpriseSearchJobsWithPreciseSymbols returns enterprise jobs that serve
+// symbol results from the precise indexes searched by the given searcher.
+func NewEnterpriseSearchJobsWithPreciseSymbols(searcher PreciseSymbolSearcher) jobutil.EnterpriseJobs {
+	return &enterpriseJobs{preciseSymbols: searcher}
+}
+
+type enterpriseJobs struct {
+	preciseSymbols PreciseSymbolSearcher
+}
 
 func (e *enterpriseJobs) FileHasOwnerJob(child job.Job, includeOwners, excludeOwners []string) job.Job {
 	return ownsearch.NewFileHasOwnersJob(child, includeOwners, excludeOwners)
@@ -19,3 +28,10 @@ func (e *enterpriseJobs) FileHasOwnerJob(child job.Job, includeOwners, excludeOw
 func (e *enterpriseJobs) SelectFileOwnerJob(child job.Job) job.Job {
 	return ownsearch.NewSelectOwnersJob(child)
 }
+
+func (e *enterpriseJobs) PreciseSymbolSearchJob(child job.Job, patternInfo *search.TextPatternInfo) job.Job {
+	if e.preciseSymbols == nil {
+		return child
+	}
+	return NewPreciseSymbolSearchJob(child, e.preciseSymbols, patternInfo)
+}
@@ -0,0 +1,263 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/grafana/regexp"
+	"github.com/sourcegraph/log"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// PreciseSymbolSearcher searches the symbols defined in precise code intelligence
+// indexes. It is implemented by *codenav.Service.
+type PreciseSymbolSearcher interface {
+	SearchSymbols(ctx context.Context, repositoryID int, commit string, pattern *regexp.Regexp, limit int) ([]codenav.PreciseSymbol, bool, error)
+}
+
+// NewPreciseSymbolSearchJob returns a job that replaces the ctags symbols streamed
+// by child with the symbols of the precise index of the same repository and
+// commit. Repositories, commits and files without a precise index keep their
+// ctags symbols.
+func NewPreciseSymbolSearchJob(child job.Job, searcher PreciseSymbolSearcher, patternInfo *search.TextPatternInfo) job.Job {
+	return &preciseSymbolSearchJob{
+		child:       child,
+		searcher:    searcher,
+		patternInfo: patternInfo,
+	}
+}
+
+type preciseSymbolSearchJob struct {
+	child       job.Job
+	searcher    PreciseSymbolSearcher
+	patternInfo *search.TextPatternInfo
+}
+
+type repoCommit struct {
+	repoID api.RepoID
+	commit api.CommitID
+}
+
+// preciseSymbols are the precise symbols of a single repository and commit.
+type preciseSymbols struct {
+	once      sync.Once
+	available bool
+	sent      bool
+	matches   result.Matches
+	paths     map[string]struct{}
+}
+
+func (j *preciseSymbolSearchJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	tr, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer finish(alert, err)
+
+	pattern, includePaths, excludePath, err := compilePreciseSymbolPatterns(j.patternInfo)
+	if err != nil {
+		// The ctags symbol search reports invalid patterns.
+		return j.child.Run(ctx, clients, stream)
+	}
+	matchesPath := func(path string) bool {
+		for _, re := range includePaths {
+			if !re.MatchString(path) {
+				return false
+			}
+		}
+		return excludePath == nil || !excludePath.MatchString(path)
+	}
+
+	var (
+		mu       sync.Mutex
+		byCommit = map[repoCommit]*preciseSymbols{}
+	)
+	lookup := func(fm *result.FileMatch) *preciseSymbols {
+		key := repoCommit{repoID: fm.Repo.ID, commit: fm.CommitID}
+
+		mu.Lock()
+		symbols, ok := byCommit[key]
+		if !ok {
+			symbols = &preciseSymbols{}
+			byCommit[key] = symbols
+		}
+		mu.Unlock()
+
+		symbols.once.Do(func() {
+			found, ok, err := j.searcher.SearchSymbols(ctx, int(fm.Repo.ID), string(fm.CommitID), pattern, int(j.patternInfo.FileMatchLimit))
+			if err != nil {
+				// Fall back to ctags symbols for this repository.
+				clients.Logger.Warn("failed to search precise symbols", log.String("repo", string(fm.Repo.Name)), log.Error(err))
+				tr.SetAttributes(fm.Repo.Name.Attr(), trace.Error(err))
+				return
+			}
+			if !ok {
+				return
+			}
+
+			filtered := found[:0]
+			for _, symbol := range found {
+				if matchesPath(symbol.Path) {
+					filtered = append(filtered, symbol)
+				}
+			}
+			symbols.available = true
+			symbols.matches, symbols.paths = preciseSymbolsToMatches(filtered, fm.File)
+		})
+		return symbols
+	}
+
+	filteredStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		var preciseMatches result.Matches
+		results := event.Results[:0]
+		for _, m := range event.Results {
+			fm, ok := m.(*result.FileMatch)
+			if !ok || len(fm.Symbols) == 0 {
+				results = append(results, m)
+				continue
+			}
+
+			symbols := lookup(fm)
+			if !symbols.available {
+				results = append(results, m)
+				continue
+			}
+
+			mu.Lock()
+			if !symbols.sent {
+				symbols.sent = true
+				preciseMatches = append(preciseMatches, symbols.matches...)
+			}
+			mu.Unlock()
+
+			// Files covered by the precise index have already been sent with their
+			// precise symbols.
+			if _, ok := symbols.paths[fm.Path]; !ok {
+				results = append(results, m)
+			}
+		}
+
+		event.Results = append(preciseMatches, results...)
+		stream.Send(event)
+	})
+
+	return j.child.Run(ctx, clients, filteredStream)
+}
+
+func (j *preciseSymbolSearchJob) Name() string {
+	return "PreciseSymbolSearchJob"
+}
+
+func (j *preciseSymbolSearchJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
+	switch v {
+	case job.VerbosityMax:
+		fallthrough
+	case job.VerbosityBasic:
+		res = append(res, trace.Scoped("patternInfo", j.patternInfo.Fields()...)...)
+	}
+	return res
+}
+
+func (j *preciseSymbolSearchJob) Children() []job.Describer {
+	return []job.Describer{j.child}
+}
+
+func (j *preciseSymbolSearchJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.child = job.Map(j.child, fn)
+	return &cp
+}
+
+// compilePreciseSymbolPatterns compiles the symbol name pattern and the file
+// path filters of the given pattern info, following the semantics of the ctags
+// symbol search.
+func compilePreciseSymbolPatterns(p *search.TextPatternInfo) (pattern *regexp.Regexp, includePaths []*regexp.Regexp, excludePath *regexp.Regexp, err error) {
+	expr := p.Pattern
+	if !p.IsRegExp {
+		expr = regexp.QuoteMeta(expr)
+	}
+	if !p.IsCaseSensitive {
+		expr = "(?i:" + expr + ")"
+	}
+	if pattern, err = regexp.Compile(expr); err != nil {
+		return nil, nil, nil, errors.Wrap(err, "invalid symbol pattern")
+	}
+
+	compilePath := func(expr string) (*regexp.Regexp, error) {
+		if !p.PathPatternsAreCaseSensitive {
+			expr = "(?i:" + expr + ")"
+		}
+		return regexp.Compile(expr)
+	}
+	for _, expr := range p.IncludePatterns {
+		re, err := compilePath(expr)
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "invalid file pattern")
+		}
+		includePaths = append(includePaths, re)
+	}
+	if p.ExcludePattern != "" {
+		if excludePath, err = compilePath(p.ExcludePattern); err != nil {
+			return nil, nil, nil, errors.Wrap(err, "invalid file pattern")
+		}
+	}
+
+	return pattern, includePaths, excludePath, nil
+}
+
+// preciseSymbolsToMatches groups the given precise symbols into file matches of
+// the repository and revision of the given file, and returns the set of paths of
+// these file matches.
+func preciseSymbolsToMatches(symbols []codenav.PreciseSymbol, base result.File) (result.Matches, map[string]struct{}) {
+	symbolsByPath := make(map[string][]codenav.PreciseSymbol)
+	for _, symbol := range symbols {
+		symbolsByPath[symbol.Path] = append(symbolsByPath[symbol.Path], symbol)
+	}
+
+	matches := make(result.Matches, 0, len(symbolsByPath))
+	paths := make(map[string]struct{}, len(symbolsByPath))
+	for path, symbols := range symbolsByPath {
+		paths[path] = struct{}{}
+
+		file := result.File{
+			Path:     path,
+			Repo:     base.Repo,
+			CommitID: base.CommitID,
+			InputRev: base.InputRev,
+		}
+
+		symbolMatches := make([]*result.SymbolMatch, 0, len(symbols))
+		for _, symbol := range symbols {
+			symbolMatches = append(symbolMatches, &result.SymbolMatch{
+				File: &file,
+				Symbol: result.Symbol{
+					Name:       symbol.Name,
+					Path:       symbol.Path,
+					Line:       symbol.Range.Start.Line + 1,
+					Character:  symbol.Range.Start.Character,
+					Kind:       symbol.Kind,
+					Parent:     symbol.ContainerName,
+					ParentKind: symbol.ContainerKind,
+					Signature:  symbol.Signature,
+					Precise:    true,
+				},
+			})
+		}
+
+		matches = append(matches, &result.FileMatch{
+			Symbols: symbolMatches,
+			File:    file,
+		})
+	}
+
+	// Make the results deterministic
+	sort.Sort(matches)
+	return matches, paths
+}
@@ -0,0 +1,147 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/grafana/regexp"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav"
+	codenavshared "github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type preciseSymbolSearcherFunc func(ctx context.Context, repositoryID int, commit string, pattern *regexp.Regexp, limit int) ([]codenav.PreciseSymbol, bool, error)
+
+func (f preciseSymbolSearcherFunc) SearchSymbols(ctx context.Context, repositoryID int, commit string, pattern *regexp.Regexp, limit int) ([]codenav.PreciseSymbol, bool, error) {
+	return f(ctx, repositoryID, commit, pattern, limit)
+}
+
+func TestPreciseSymbolSearchJob(t *testing.T) {
+	indexed := types.MinimalRepo{ID: 1, Name: "indexed"}
+	unindexed := types.MinimalRepo{ID: 2, Name: "unindexed"}
+	broken := types.MinimalRepo{ID: 3, Name: "broken"}
+
+	ctagsMatch := func(repo types.MinimalRepo, path, name string) *result.FileMatch {
+		file := result.File{Repo: repo, CommitID: "deadbeef", Path: path}
+		return &result.FileMatch{
+			File:    file,
+			Symbols: []*result.SymbolMatch{{File: &file, Symbol: result.Symbol{Name: name, Path: path, Kind: "func"}}},
+		}
+	}
+
+	child := mockjob.NewMockJob()
+	child.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+		s.Send(streaming.SearchEvent{Results: result.Matches{
+			ctagsMatch(indexed, "a.go", "Banana"),
+			ctagsMatch(indexed, "c.go", "BananaBread"),
+			ctagsMatch(unindexed, "a.go", "Banana"),
+		}})
+		s.Send(streaming.SearchEvent{Results: result.Matches{
+			ctagsMatch(indexed, "a.go", "banana"),
+			ctagsMatch(broken, "a.go", "Banana"),
+			&result.RepoMatch{Name: "other"},
+		}})
+		return nil, nil
+	})
+
+	calls := map[int]int{}
+	searcher := preciseSymbolSearcherFunc(func(_ context.Context, repositoryID int, commit string, pattern *regexp.Regexp, limit int) ([]codenav.PreciseSymbol, bool, error) {
+		calls[repositoryID]++
+		require.Equal(t, "deadbeef", commit)
+		require.True(t, pattern.MatchString("BANANA"))
+		require.Equal(t, 10, limit)
+
+		switch repositoryID {
+		case 1:
+			return []codenav.PreciseSymbol{
+				{Name: "Banana", Kind: "type", Path: "a.go", Signature: "type Banana struct", Range: codenavshared.Range{Start: codenavshared.Position{Line: 2, Character: 5}}},
+				{Name: "Peel", Kind: "method", ContainerName: "Banana", ContainerKind: "type", Path: "a.go", Range: codenavshared.Range{Start: codenavshared.Position{Line: 4, Character: 16}}},
+				{Name: "Banana", Kind: "variable", Path: "vendor/b.go"},
+			}, true, nil
+		case 2:
+			return nil, false, nil
+		default:
+			return nil, false, errors.New("codeintel-db is down")
+		}
+	})
+
+	patternInfo := &search.TextPatternInfo{
+		Pattern:        "banana",
+		IsRegExp:       true,
+		FileMatchLimit: 10,
+		ExcludePattern: "^vendor/",
+	}
+	stream := streaming.NewAggregatingStream()
+	_, err := NewPreciseSymbolSearchJob(child, searcher, patternInfo).Run(context.Background(), job.RuntimeClients{Logger: logtest.Scoped(t)}, stream)
+	require.NoError(t, err)
+	require.Equal(t, map[int]int{1: 1, 2: 1, 3: 1}, calls)
+
+	type symbol struct {
+		repo, path, name, kind, parent, signature string
+		line                                      int
+		precise                                   bool
+	}
+	var symbols []symbol
+	for _, m := range stream.Results {
+		fm, ok := m.(*result.FileMatch)
+		if !ok {
+			continue
+		}
+		for _, s := range fm.Symbols {
+			symbols = append(symbols, symbol{
+				repo:      string(fm.Repo.Name),
+				path:      fm.Path,
+				name:      s.Symbol.Name,
+				kind:      s.Symbol.Kind,
+				parent:    s.Symbol.Parent,
+				signature: s.Symbol.Signature,
+				line:      s.Symbol.Line,
+				precise:   s.Symbol.Precise,
+			})
+		}
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		if symbols[i].repo != symbols[j].repo {
+			return symbols[i].repo < symbols[j].repo
+		}
+		if symbols[i].path != symbols[j].path {
+			return symbols[i].path < symbols[j].path
+		}
+		return symbols[i].name < symbols[j].name
+	})
+
+	require.Equal(t, []symbol{
+		{repo: "broken", path: "a.go", name: "Banana", kind: "func"},
+		{repo: "indexed", path: "a.go", name: "Banana", kind: "type", signature: "type Banana struct", line: 3, precise: true},
+		{repo: "indexed", path: "a.go", name: "Peel", kind: "method", parent: "Banana", line: 5, precise: true},
+		{repo: "indexed", path: "c.go", name: "BananaBread", kind: "func"},
+		{repo: "unindexed", path: "a.go", name: "Banana", kind: "func"},
+	}, symbols)
+	require.Len(t, stream.Results, 5)
+}
+
+func TestCompilePreciseSymbolPatterns(t *testing.T) {
+	pattern, includePaths, excludePath, err := compilePreciseSymbolPatterns(&search.TextPatternInfo{
+		Pattern:         "Foo.Bar",
+		IncludePatterns: []string{`\.go$`},
+	})
+	require.NoError(t, err)
+	require.True(t, pattern.MatchString("foo.bar"))
+	require.False(t, pattern.MatchString("FooxBar"))
+	require.Len(t, includePaths, 1)
+	require.True(t, includePaths[0].MatchString("MAIN.GO"))
+	require.Nil(t, excludePath)
+
+	_, _, _, err = compilePreciseSymbolPatterns(&search.TextPatternInfo{Pattern: "(", IsRegExp: true})
+	require.Error(t, err)
+}
@@ -34,6 +34,7 @@ func SchedulePermsSync(ctx context.Context, logger log.Logger, db database.DB, r
 			Reason:            req.Reason,
 			TriggeredByUserID: req.TriggeredByUserID,
 			ProcessAfter:      req.ProcessAfter,
+			Coalesce:          req.Coalesce,
 		}
 		err := db.PermissionSyncJobs().CreateUserSyncJob(ctx, userID, opts)
 		if err != nil {
@@ -48,6 +49,7 @@ func SchedulePermsSync(ctx context.Context, logger log.Logger, db database.DB, r
 			Reason:            req.Reason,
 			TriggeredByUserID: req.TriggeredByUserID,
 			ProcessAfter:      req.ProcessAfter,
+			Coalesce:          req.Coalesce,
 		}
 		err := db.PermissionSyncJobs().CreateRepoSyncJob(ctx, repoID, opts)
 		if err != nil {
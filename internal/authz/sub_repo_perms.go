@@ -54,6 +54,41 @@ type SubRepoPermissionChecker interface {
 	EnabledForRepo(ctx context.Context, repo api.RepoName) (bool, error)
 }
 
+// SubRepoPermissionsExplainer is implemented by SubRepoPermissionCheckers that
+// can explain their decisions.
+type SubRepoPermissionsExplainer interface {
+	// ExplainPermissions returns the level of access the provided user has for the
+	// requested content, together with the rule that decided it.
+	ExplainPermissions(ctx context.Context, userID int32, content RepoContent) (SubRepoPermissionsExplanation, error)
+}
+
+// SubRepoPermissionsSource is what decided the sub-repo permissions of a path.
+type SubRepoPermissionsSource string
+
+const (
+	// SubRepoPermissionsSourceDisabled means sub-repo permissions are disabled, so
+	// the path is visible to everyone who can access the repository.
+	SubRepoPermissionsSourceDisabled SubRepoPermissionsSource = "disabled"
+	// SubRepoPermissionsSourcePathRule means a path rule from the site
+	// configuration matched the path.
+	SubRepoPermissionsSourcePathRule SubRepoPermissionsSource = "path_rule"
+	// SubRepoPermissionsSourceCodeHost means the permissions synced from the code
+	// host decided. If no rule matched, the path is hidden.
+	SubRepoPermissionsSourceCodeHost SubRepoPermissionsSource = "code_host"
+	// SubRepoPermissionsSourceNoRules means the repository has no sub-repo
+	// permissions for the user, so the whole repository is visible.
+	SubRepoPermissionsSourceNoRules SubRepoPermissionsSource = "no_rules"
+)
+
+// SubRepoPermissionsExplanation explains why a path is visible or hidden for a
+// user.
+type SubRepoPermissionsExplanation struct {
+	Perms  Perms
+	Source SubRepoPermissionsSource
+	// Rule is the rule that matched the path, if any.
+	Rule string
+}
+
 // DefaultSubRepoPermsChecker allows us to use a single instance with a shared
 // cache and database connection. Since we don't have a database connection at
 // initialisation time, services that require this client should initialise it in
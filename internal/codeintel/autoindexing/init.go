@@ -34,6 +34,7 @@ func NewService(
 	return newService(
 		scopedContext("service", observationCtx),
 		store,
+		depsSvc,
 		inferenceSvc,
 		repoUpdater,
 		db.Repos(),
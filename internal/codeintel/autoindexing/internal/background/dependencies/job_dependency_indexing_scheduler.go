@@ -15,6 +15,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/internal/inference"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/internal/store"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/shared"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/dependencies"
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -32,6 +34,8 @@ import (
 func NewDependencyIndexingScheduler(
 	dependencyIndexingStore dbworkerstore.Store[dependencyIndexingJob],
 	uploadSvc UploadService,
+	depsSvc DependenciesService,
+	store store.Store,
 	repoStore ReposStore,
 	externalServiceStore ExternalServiceStore,
 	gitserverRepoStore GitserverRepoStore,
@@ -44,6 +48,8 @@ func NewDependencyIndexingScheduler(
 
 	handler := &dependencyIndexingSchedulerHandler{
 		uploadsSvc:         uploadSvc,
+		depsSvc:            depsSvc,
+		store:              store,
 		repoStore:          repoStore,
 		extsvcStore:        externalServiceStore,
 		gitserverRepoStore: gitserverRepoStore,
@@ -64,6 +70,8 @@ func NewDependencyIndexingScheduler(
 
 type dependencyIndexingSchedulerHandler struct {
 	uploadsSvc         UploadService
+	depsSvc            DependenciesService
+	store              store.Store
 	repoStore          ReposStore
 	indexEnqueuer      IndexEnqueuer
 	extsvcStore        ExternalServiceStore
@@ -82,7 +90,9 @@ var _ workerutil.Handler[dependencyIndexingJob] = &dependencyIndexingSchedulerHa
 // Handle iterates all import monikers associated with a given upload that has
 // recently completed processing. Each moniker is interpreted according to its
 // scheme to determine the dependent repository and commit. A set of indexing
-// jobs are enqueued for each repository and commit pair.
+// jobs are enqueued for each repository and commit pair. Packages that are blocked
+// by package repo filters, above a pinned version, or further than the configured
+// maximum depth from a code host repository are skipped.
 func (h *dependencyIndexingSchedulerHandler) Handle(ctx context.Context, logger log.Logger, job dependencyIndexingJob) error {
 	if !autoIndexingEnabled() || disableIndexScheduler {
 		return nil
@@ -124,6 +134,16 @@ func (h *dependencyIndexingSchedulerHandler) Handle(ctx context.Context, logger
 		}
 	}
 
+	policy, err := newIndexingPolicy(ctx, logger, h.depsSvc)
+	if err != nil {
+		return err
+	}
+
+	depth, err := policy.dependencyDepth(ctx, h.store, job.UploadID)
+	if err != nil {
+		return err
+	}
+
 	scanner, err := h.uploadsSvc.ReferencesForUpload(ctx, job.UploadID)
 	if err != nil {
 		return errors.Wrap(err, "dbstore.ReferencesForUpload")
@@ -145,6 +165,15 @@ func (h *dependencyIndexingSchedulerHandler) Handle(ctx context.Context, logger
 			break
 		}
 
+		if status := policy.status(packageReference.Package, depth); status != shared.DependencyIndexingStatusScheduled {
+			logger.Debug("skipping dependency package",
+				log.String("scheme", packageReference.Scheme),
+				log.String("name", packageReference.Name),
+				log.String("version", packageReference.Version),
+				log.String("status", string(status)))
+			continue
+		}
+
 		pkg := dependencies.MinimialVersionedPackageRepo{
 			Scheme:  packageReference.Scheme,
 			Name:    reposource.PackageName(packageReference.Name),
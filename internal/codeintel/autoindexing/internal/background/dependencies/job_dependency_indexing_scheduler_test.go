@@ -64,6 +64,8 @@ func TestDependencyIndexingSchedulerHandler(t *testing.T) {
 
 	handler := &dependencyIndexingSchedulerHandler{
 		uploadsSvc:         mockUploadsSvc,
+		depsSvc:            NewMockDependenciesService(),
+		store:              NewMockStore(),
 		repoStore:          mockRepoStore,
 		indexEnqueuer:      indexEnqueuer,
 		extsvcStore:        mockExtSvcStore,
@@ -165,6 +167,8 @@ func TestDependencyIndexingSchedulerHandlerCustomer(t *testing.T) {
 
 	handler := &dependencyIndexingSchedulerHandler{
 		uploadsSvc:         mockUploadsSvc,
+		depsSvc:            NewMockDependenciesService(),
+		store:              NewMockStore(),
 		repoStore:          mockRepoStore,
 		indexEnqueuer:      indexEnqueuer,
 		extsvcStore:        mockExtSvcStore,
@@ -259,6 +263,8 @@ func TestDependencyIndexingSchedulerHandlerRequeueNotCloned(t *testing.T) {
 
 	handler := &dependencyIndexingSchedulerHandler{
 		uploadsSvc:         mockUploadsSvc,
+		depsSvc:            NewMockDependenciesService(),
+		store:              NewMockStore(),
 		repoStore:          mockRepoStore,
 		indexEnqueuer:      indexEnqueuer,
 		extsvcStore:        mockExtSvcStore,
@@ -321,6 +327,8 @@ func TestDependencyIndexingSchedulerHandlerSkipNonExistant(t *testing.T) {
 
 	handler := &dependencyIndexingSchedulerHandler{
 		uploadsSvc:         mockUploadsSvc,
+		depsSvc:            NewMockDependenciesService(),
+		store:              NewMockStore(),
 		indexEnqueuer:      indexEnqueuer,
 		extsvcStore:        mockExtSvcStore,
 		gitserverRepoStore: mockGitserverReposStore,
@@ -368,6 +376,8 @@ func TestDependencyIndexingSchedulerHandlerShouldSkipRepository(t *testing.T) {
 
 	handler := &dependencyIndexingSchedulerHandler{
 		uploadsSvc:         mockUploadsSvc,
+		depsSvc:            NewMockDependenciesService(),
+		store:              NewMockStore(),
 		indexEnqueuer:      indexEnqueuer,
 		extsvcStore:        mockExtSvcStore,
 		gitserverRepoStore: mockGitserverReposStore,
@@ -416,6 +426,8 @@ func TestDependencyIndexingSchedulerHandlerNoExtsvc(t *testing.T) {
 
 	handler := &dependencyIndexingSchedulerHandler{
 		uploadsSvc:         mockUploadsSvc,
+		depsSvc:            NewMockDependenciesService(),
+		store:              NewMockStore(),
 		indexEnqueuer:      indexEnqueuer,
 		extsvcStore:        mockExtSvcStore,
 		gitserverRepoStore: mockGitserverReposStore,
@@ -436,3 +448,88 @@ func TestDependencyIndexingSchedulerHandlerNoExtsvc(t *testing.T) {
 		t.Errorf("unexpected number of calls to QueueIndexesForPackage. want=%d have=%d", 0, len(indexEnqueuer.QueueIndexesForPackageFunc.History()))
 	}
 }
+
+func TestDependencyIndexingSchedulerHandlerPolicy(t *testing.T) {
+	mockUploadsSvc := NewMockUploadService()
+	mockDepsSvc := NewMockDependenciesService()
+	mockStore := NewMockStore()
+	mockRepoStore := NewMockReposStore()
+	mockExtSvcStore := NewMockExternalServiceStore()
+	mockScanner := NewMockPackageReferenceScanner()
+	mockWorkerStore := NewMockWorkerStore[dependencyIndexingJob]()
+
+	dependencyIndexingMaxDepth = func() int { return 2 }
+	dependencyVersionPins = func() map[string]string { return map[string]string{"gomod:https://github.com/sample/text": "v2.5.0"} }
+	t.Cleanup(func() {
+		dependencyIndexingMaxDepth = func() int { return -1 }
+		dependencyVersionPins = func() map[string]string { return nil }
+	})
+
+	mockDepsSvc.ListPackageRepoFiltersFunc.SetDefaultReturn([]dependencies.PackageRepoFilter{
+		{
+			Behaviour:     "BLOCK",
+			PackageScheme: "gomod",
+			NameFilter:    &struct{ PackageGlob string }{PackageGlob: "https://github.com/cheese/*"},
+		},
+	}, false, nil)
+	mockStore.GetDependencyIndexingDepthFunc.SetDefaultReturn(1, true, nil)
+
+	mockUploadsSvc.ReferencesForUploadFunc.SetDefaultReturn(mockScanner, nil)
+	mockScanner.NextFunc.PushReturn(shared.PackageReference{Package: shared.Package{DumpID: 42, Scheme: "gomod", Name: "https://github.com/sample/text", Version: "v2.2.0"}}, true, nil)
+	mockScanner.NextFunc.PushReturn(shared.PackageReference{Package: shared.Package{DumpID: 42, Scheme: "gomod", Name: "https://github.com/sample/text", Version: "v3.2.0"}}, true, nil)
+	mockScanner.NextFunc.PushReturn(shared.PackageReference{Package: shared.Package{DumpID: 42, Scheme: "gomod", Name: "https://github.com/cheese/burger", Version: "v3.2.2"}}, true, nil)
+	mockScanner.NextFunc.SetDefaultReturn(shared.PackageReference{}, false, nil)
+
+	mockRepoStore.ListMinimalReposFunc.PushReturn([]types.MinimalRepo{{Name: "github.com/sample/text"}}, nil)
+
+	mockGitserverReposStore := NewMockGitserverRepoStore()
+	mockGitserverReposStore.GetByNamesFunc.PushReturn(map[api.RepoName]*types.GitserverRepo{
+		"github.com/sample/text": {
+			CloneStatus: types.CloneStatusCloned,
+		},
+	}, nil)
+
+	indexEnqueuer := NewMockIndexEnqueuer()
+
+	envvar.MockSourcegraphDotComMode(false)
+
+	handler := &dependencyIndexingSchedulerHandler{
+		uploadsSvc:         mockUploadsSvc,
+		depsSvc:            mockDepsSvc,
+		store:              mockStore,
+		repoStore:          mockRepoStore,
+		indexEnqueuer:      indexEnqueuer,
+		extsvcStore:        mockExtSvcStore,
+		workerStore:        mockWorkerStore,
+		gitserverRepoStore: mockGitserverReposStore,
+	}
+
+	job := dependencyIndexingJob{UploadID: 42}
+	if err := handler.Handle(context.Background(), logtest.Scoped(t), job); err != nil {
+		t.Fatalf("unexpected error performing update: %s", err)
+	}
+
+	var packages []dependencies.MinimialVersionedPackageRepo
+	for _, call := range indexEnqueuer.QueueIndexesForPackageFunc.History() {
+		packages = append(packages, call.Arg1)
+	}
+	expectedPackages := []dependencies.MinimialVersionedPackageRepo{
+		{Scheme: "gomod", Name: "https://github.com/sample/text", Version: "v2.2.0"},
+	}
+	if diff := cmp.Diff(expectedPackages, packages); diff != "" {
+		t.Errorf("unexpected packages (-want +got):\n%s", diff)
+	}
+
+	// Dependencies of an upload at the maximum depth are not indexed
+	mockStore.GetDependencyIndexingDepthFunc.SetDefaultReturn(2, true, nil)
+	indexEnqueuer = NewMockIndexEnqueuer()
+	handler.indexEnqueuer = indexEnqueuer
+	mockScanner.NextFunc.PushReturn(shared.PackageReference{Package: shared.Package{DumpID: 42, Scheme: "gomod", Name: "https://github.com/sample/text", Version: "v2.2.0"}}, true, nil)
+
+	if err := handler.Handle(context.Background(), logtest.Scoped(t), job); err != nil {
+		t.Fatalf("unexpected error performing update: %s", err)
+	}
+	if len(indexEnqueuer.QueueIndexesForPackageFunc.History()) != 0 {
+		t.Errorf("unexpected number of calls to QueueIndexesForPackage. want=%d have=%d", 0, len(indexEnqueuer.QueueIndexesForPackageFunc.History()))
+	}
+}
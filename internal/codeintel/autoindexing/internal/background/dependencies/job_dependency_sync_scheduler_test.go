@@ -16,6 +16,8 @@ import (
 
 func init() {
 	autoIndexingEnabled = func() bool { return true }
+	dependencyIndexingMaxDepth = func() int { return -1 }
+	dependencyVersionPins = func() map[string]string { return nil }
 }
 
 func TestDependencySyncSchedulerJVM(t *testing.T) {
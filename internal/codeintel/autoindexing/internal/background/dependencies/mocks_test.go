@@ -1921,6 +1921,14 @@ func (c PackageReferenceScannerNextFuncCall) Results() []interface{} {
 // github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/internal/store)
 // used for unit testing.
 type MockStore struct {
+	// GetDependencyIndexingCandidatesFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// GetDependencyIndexingCandidates.
+	GetDependencyIndexingCandidatesFunc *StoreGetDependencyIndexingCandidatesFunc
+	// GetDependencyIndexingDepthFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// GetDependencyIndexingDepth.
+	GetDependencyIndexingDepthFunc *StoreGetDependencyIndexingDepthFunc
 	// GetIndexConfigurationByRepositoryIDFunc is an instance of a mock
 	// function object controlling the behavior of the method
 	// GetIndexConfigurationByRepositoryID.
@@ -1958,6 +1966,9 @@ type MockStore struct {
 	// QueueRepoRevFunc is an instance of a mock function object controlling
 	// the behavior of the method QueueRepoRev.
 	QueueRepoRevFunc *StoreQueueRepoRevFunc
+	// QueueRepoRevsFunc is an instance of a mock function object
+	// controlling the behavior of the method QueueRepoRevs.
+	QueueRepoRevsFunc *StoreQueueRepoRevsFunc
 	// RepositoryExceptionsFunc is an instance of a mock function object
 	// controlling the behavior of the method RepositoryExceptions.
 	RepositoryExceptionsFunc *StoreRepositoryExceptionsFunc
@@ -1995,6 +2006,16 @@ type MockStore struct {
 // return zero values for all results, unless overwritten.
 func NewMockStore() *MockStore {
 	return &MockStore{
+		GetDependencyIndexingCandidatesFunc: &StoreGetDependencyIndexingCandidatesFunc{
+			defaultHook: func(context.Context, int) (r0 []shared1.Package, r1 error) {
+				return
+			},
+		},
+		GetDependencyIndexingDepthFunc: &StoreGetDependencyIndexingDepthFunc{
+			defaultHook: func(context.Context, int, int) (r0 int, r1 bool, r2 error) {
+				return
+			},
+		},
 		GetIndexConfigurationByRepositoryIDFunc: &StoreGetIndexConfigurationByRepositoryIDFunc{
 			defaultHook: func(context.Context, int) (r0 shared2.IndexConfiguration, r1 bool, r2 error) {
 				return
@@ -2050,6 +2071,11 @@ func NewMockStore() *MockStore {
 				return
 			},
 		},
+		QueueRepoRevsFunc: &StoreQueueRepoRevsFunc{
+			defaultHook: func(context.Context, int, []string) (r0 error) {
+				return
+			},
+		},
 		RepositoryExceptionsFunc: &StoreRepositoryExceptionsFunc{
 			defaultHook: func(context.Context, int) (r0 bool, r1 bool, r2 error) {
 				return
@@ -2102,6 +2128,16 @@ func NewMockStore() *MockStore {
 // panic on invocation, unless overwritten.
 func NewStrictMockStore() *MockStore {
 	return &MockStore{
+		GetDependencyIndexingCandidatesFunc: &StoreGetDependencyIndexingCandidatesFunc{
+			defaultHook: func(context.Context, int) ([]shared1.Package, error) {
+				panic("unexpected invocation of MockStore.GetDependencyIndexingCandidates")
+			},
+		},
+		GetDependencyIndexingDepthFunc: &StoreGetDependencyIndexingDepthFunc{
+			defaultHook: func(context.Context, int, int) (int, bool, error) {
+				panic("unexpected invocation of MockStore.GetDependencyIndexingDepth")
+			},
+		},
 		GetIndexConfigurationByRepositoryIDFunc: &StoreGetIndexConfigurationByRepositoryIDFunc{
 			defaultHook: func(context.Context, int) (shared2.IndexConfiguration, bool, error) {
 				panic("unexpected invocation of MockStore.GetIndexConfigurationByRepositoryID")
@@ -2157,6 +2193,11 @@ func NewStrictMockStore() *MockStore {
 				panic("unexpected invocation of MockStore.QueueRepoRev")
 			},
 		},
+		QueueRepoRevsFunc: &StoreQueueRepoRevsFunc{
+			defaultHook: func(context.Context, int, []string) error {
+				panic("unexpected invocation of MockStore.QueueRepoRevs")
+			},
+		},
 		RepositoryExceptionsFunc: &StoreRepositoryExceptionsFunc{
 			defaultHook: func(context.Context, int) (bool, bool, error) {
 				panic("unexpected invocation of MockStore.RepositoryExceptions")
@@ -2209,6 +2250,12 @@ func NewStrictMockStore() *MockStore {
 // methods delegate to the given implementation, unless overwritten.
 func NewMockStoreFrom(i store.Store) *MockStore {
 	return &MockStore{
+		GetDependencyIndexingCandidatesFunc: &StoreGetDependencyIndexingCandidatesFunc{
+			defaultHook: i.GetDependencyIndexingCandidates,
+		},
+		GetDependencyIndexingDepthFunc: &StoreGetDependencyIndexingDepthFunc{
+			defaultHook: i.GetDependencyIndexingDepth,
+		},
 		GetIndexConfigurationByRepositoryIDFunc: &StoreGetIndexConfigurationByRepositoryIDFunc{
 			defaultHook: i.GetIndexConfigurationByRepositoryID,
 		},
@@ -2242,6 +2289,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		QueueRepoRevFunc: &StoreQueueRepoRevFunc{
 			defaultHook: i.QueueRepoRev,
 		},
+		QueueRepoRevsFunc: &StoreQueueRepoRevsFunc{
+			defaultHook: i.QueueRepoRevs,
+		},
 		RepositoryExceptionsFunc: &StoreRepositoryExceptionsFunc{
 			defaultHook: i.RepositoryExceptions,
 		},
@@ -2272,6 +2322,235 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 	}
 }
 
+// StoreGetDependencyIndexingCandidatesFunc describes the behavior when the
+// GetDependencyIndexingCandidates method of the parent MockStore instance
+// is invoked.
+type StoreGetDependencyIndexingCandidatesFunc struct {
+	defaultHook func(context.Context, int) ([]shared1.Package, error)
+	hooks       []func(context.Context, int) ([]shared1.Package, error)
+	history     []StoreGetDependencyIndexingCandidatesFuncCall
+	mutex       sync.Mutex
+}
+
+// GetDependencyIndexingCandidates delegates to the next hook function in
+// the queue and stores the parameter and result values of this invocation.
+func (m *MockStore) GetDependencyIndexingCandidates(v0 context.Context, v1 int) ([]shared1.Package, error) {
+	r0, r1 := m.GetDependencyIndexingCandidatesFunc.nextHook()(v0, v1)
+	m.GetDependencyIndexingCandidatesFunc.appendCall(StoreGetDependencyIndexingCandidatesFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// GetDependencyIndexingCandidates method of the parent MockStore instance
+// is invoked and the hook queue is empty.
+func (f *StoreGetDependencyIndexingCandidatesFunc) SetDefaultHook(hook func(context.Context, int) ([]shared1.Package, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetDependencyIndexingCandidates method of the parent MockStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *StoreGetDependencyIndexingCandidatesFunc) PushHook(hook func(context.Context, int) ([]shared1.Package, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetDependencyIndexingCandidatesFunc) SetDefaultReturn(r0 []shared1.Package, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) ([]shared1.Package, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetDependencyIndexingCandidatesFunc) PushReturn(r0 []shared1.Package, r1 error) {
+	f.PushHook(func(context.Context, int) ([]shared1.Package, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreGetDependencyIndexingCandidatesFunc) nextHook() func(context.Context, int) ([]shared1.Package, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetDependencyIndexingCandidatesFunc) appendCall(r0 StoreGetDependencyIndexingCandidatesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// StoreGetDependencyIndexingCandidatesFuncCall objects describing the
+// invocations of this function.
+func (f *StoreGetDependencyIndexingCandidatesFunc) History() []StoreGetDependencyIndexingCandidatesFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetDependencyIndexingCandidatesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetDependencyIndexingCandidatesFuncCall is an object that describes
+// an invocation of method GetDependencyIndexingCandidates on an instance of
+// MockStore.
+type StoreGetDependencyIndexingCandidatesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared1.Package
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetDependencyIndexingCandidatesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetDependencyIndexingCandidatesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// StoreGetDependencyIndexingDepthFunc describes the behavior when the
+// GetDependencyIndexingDepth method of the parent MockStore instance is
+// invoked.
+type StoreGetDependencyIndexingDepthFunc struct {
+	defaultHook func(context.Context, int, int) (int, bool, error)
+	hooks       []func(context.Context, int, int) (int, bool, error)
+	history     []StoreGetDependencyIndexingDepthFuncCall
+	mutex       sync.Mutex
+}
+
+// GetDependencyIndexingDepth delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockStore) GetDependencyIndexingDepth(v0 context.Context, v1 int, v2 int) (int, bool, error) {
+	r0, r1, r2 := m.GetDependencyIndexingDepthFunc.nextHook()(v0, v1, v2)
+	m.GetDependencyIndexingDepthFunc.appendCall(StoreGetDependencyIndexingDepthFuncCall{v0, v1, v2, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the
+// GetDependencyIndexingDepth method of the parent MockStore instance is
+// invoked and the hook queue is empty.
+func (f *StoreGetDependencyIndexingDepthFunc) SetDefaultHook(hook func(context.Context, int, int) (int, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetDependencyIndexingDepth method of the parent MockStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *StoreGetDependencyIndexingDepthFunc) PushHook(hook func(context.Context, int, int) (int, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetDependencyIndexingDepthFunc) SetDefaultReturn(r0 int, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, int) (int, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetDependencyIndexingDepthFunc) PushReturn(r0 int, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int, int) (int, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *StoreGetDependencyIndexingDepthFunc) nextHook() func(context.Context, int, int) (int, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetDependencyIndexingDepthFunc) appendCall(r0 StoreGetDependencyIndexingDepthFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreGetDependencyIndexingDepthFuncCall
+// objects describing the invocations of this function.
+func (f *StoreGetDependencyIndexingDepthFunc) History() []StoreGetDependencyIndexingDepthFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetDependencyIndexingDepthFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetDependencyIndexingDepthFuncCall is an object that describes an
+// invocation of method GetDependencyIndexingDepth on an instance of
+// MockStore.
+type StoreGetDependencyIndexingDepthFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetDependencyIndexingDepthFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetDependencyIndexingDepthFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // StoreGetIndexConfigurationByRepositoryIDFunc describes the behavior when
 // the GetIndexConfigurationByRepositoryID method of the parent MockStore
 // instance is invoked.
@@ -3499,6 +3778,113 @@ func (c StoreQueueRepoRevFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// StoreQueueRepoRevsFunc describes the behavior when the QueueRepoRevs
+// method of the parent MockStore instance is invoked.
+type StoreQueueRepoRevsFunc struct {
+	defaultHook func(context.Context, int, []string) error
+	hooks       []func(context.Context, int, []string) error
+	history     []StoreQueueRepoRevsFuncCall
+	mutex       sync.Mutex
+}
+
+// QueueRepoRevs delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockStore) QueueRepoRevs(v0 context.Context, v1 int, v2 []string) error {
+	r0 := m.QueueRepoRevsFunc.nextHook()(v0, v1, v2)
+	m.QueueRepoRevsFunc.appendCall(StoreQueueRepoRevsFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the QueueRepoRevs method
+// of the parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreQueueRepoRevsFunc) SetDefaultHook(hook func(context.Context, int, []string) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// QueueRepoRevs method of the parent MockStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreQueueRepoRevsFunc) PushHook(hook func(context.Context, int, []string) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreQueueRepoRevsFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int, []string) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreQueueRepoRevsFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int, []string) error {
+		return r0
+	})
+}
+
+func (f *StoreQueueRepoRevsFunc) nextHook() func(context.Context, int, []string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreQueueRepoRevsFunc) appendCall(r0 StoreQueueRepoRevsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreQueueRepoRevsFuncCall objects
+// describing the invocations of this function.
+func (f *StoreQueueRepoRevsFunc) History() []StoreQueueRepoRevsFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreQueueRepoRevsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreQueueRepoRevsFuncCall is an object that describes an invocation of
+// method QueueRepoRevs on an instance of MockStore.
+type StoreQueueRepoRevsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreQueueRepoRevsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreQueueRepoRevsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // StoreRepositoryExceptionsFunc describes the behavior when the
 // RepositoryExceptions method of the parent MockStore instance is invoked.
 type StoreRepositoryExceptionsFunc struct {
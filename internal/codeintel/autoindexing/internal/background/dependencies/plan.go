@@ -0,0 +1,166 @@
+package dependencies
+
+import (
+	"context"
+
+	"github.com/Masterminds/semver"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/internal/inference"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/internal/store"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/shared"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/dependencies"
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/packagefilters"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var (
+	dependencyIndexingMaxDepth = conf.CodeIntelAutoIndexingDependencyIndexingMaxDepth
+	dependencyVersionPins      = conf.CodeIntelAutoIndexingDependencyVersionPins
+)
+
+// indexingPolicy determines which dependency packages are auto-indexed. It combines the
+// package repo allow/block filters with the version pins and maximum transitive depth
+// from the site configuration.
+type indexingPolicy struct {
+	filters  packagefilters.PackageFilters
+	pins     map[string]*semver.Version
+	maxDepth int
+}
+
+// newIndexingPolicy reads the current package repo filters and site configuration. Version
+// pins that are not valid semantic versions are logged and ignored so that a single bad
+// entry does not halt dependency indexing.
+func newIndexingPolicy(ctx context.Context, logger log.Logger, depsSvc DependenciesService) (*indexingPolicy, error) {
+	pkgFilters, _, err := depsSvc.ListPackageRepoFilters(ctx, dependencies.ListPackageRepoRefFiltersOpts{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing package repo filters")
+	}
+
+	filters, err := packagefilters.NewFilterLists(pkgFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	pins := map[string]*semver.Version{}
+	for key, version := range dependencyVersionPins() {
+		pin, err := semver.NewVersion(version)
+		if err != nil {
+			logger.Warn("ignoring invalid dependency version pin",
+				log.String("package", key),
+				log.String("version", version),
+				log.Error(err))
+			continue
+		}
+		pins[key] = pin
+	}
+
+	return &indexingPolicy{
+		filters:  filters,
+		pins:     pins,
+		maxDepth: dependencyIndexingMaxDepth(),
+	}, nil
+}
+
+// dependencyDepth returns the depth of the dependencies referenced by the given upload,
+// which is one more than the depth of the upload itself. If the depth is not limited, no
+// lookup is performed and zero is returned. If the upload is further than the maximum
+// depth from any code host repository, a depth exceeding the maximum is returned.
+func (p *indexingPolicy) dependencyDepth(ctx context.Context, store store.Store, uploadID int) (int, error) {
+	if p.maxDepth < 0 {
+		return 0, nil
+	}
+
+	depth, ok, err := store.GetDependencyIndexingDepth(ctx, uploadID, p.maxDepth)
+	if err != nil {
+		return 0, errors.Wrap(err, "store.GetDependencyIndexingDepth")
+	}
+	if !ok {
+		return p.maxDepth + 1, nil
+	}
+
+	return depth + 1, nil
+}
+
+// status returns the status of the given package referenced at the given depth. Only the
+// package repo filters, version pins, and maximum depth are considered here; whether the
+// package can be resolved to a repository is determined separately by the caller.
+func (p *indexingPolicy) status(pkg uploadsshared.Package, depth int) shared.DependencyIndexingStatus {
+	// Rules are written against normalized package names (e.g. `junit:junit` rather than
+	// `maven/junit/junit`). Packages that fail to normalize are matched as referenced.
+	scheme, name, version := pkg.Scheme, pkg.Name, pkg.Version
+	if normalized, err := newPackage(pkg); err == nil {
+		scheme, name, version = normalized.Scheme, normalized.Name, normalized.Version
+	}
+
+	if !packagefilters.IsVersionedPackageAllowed(scheme, reposource.PackageName(name), version, p.filters) {
+		return shared.DependencyIndexingStatusBlocked
+	}
+
+	if pin, ok := p.pins[scheme+":"+name]; ok {
+		// Versions that can't be compared against the pin are treated as exceeding it
+		if v, err := semver.NewVersion(version); err != nil || v.GreaterThan(pin) {
+			return shared.DependencyIndexingStatusVersionPinned
+		}
+	}
+
+	if p.maxDepth >= 0 && depth > p.maxDepth {
+		return shared.DependencyIndexingStatusDepthExceeded
+	}
+
+	return shared.DependencyIndexingStatusScheduled
+}
+
+// GetDependencyIndexingPlan returns an entry for each package referenced by the most recent
+// uploads of the given repository describing whether the dependency indexing scheduler would
+// queue auto-indexing jobs for it.
+func GetDependencyIndexingPlan(ctx context.Context, logger log.Logger, store store.Store, depsSvc DependenciesService, repositoryID int) ([]shared.DependencyIndexingPlanEntry, error) {
+	policy, err := newIndexingPolicy(ctx, logger, depsSvc)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs, err := store.GetDependencyIndexingCandidates(ctx, repositoryID)
+	if err != nil {
+		return nil, errors.Wrap(err, "store.GetDependencyIndexingCandidates")
+	}
+
+	depths := map[int]int{}
+	entries := make([]shared.DependencyIndexingPlanEntry, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		depth, ok := depths[pkg.DumpID]
+		if !ok {
+			if depth, err = policy.dependencyDepth(ctx, store, pkg.DumpID); err != nil {
+				return nil, err
+			}
+			depths[pkg.DumpID] = depth
+		}
+
+		entry := shared.DependencyIndexingPlanEntry{
+			UploadID: pkg.DumpID,
+			Scheme:   pkg.Scheme,
+			Name:     pkg.Name,
+			Version:  pkg.Version,
+			Depth:    depth,
+			Status:   policy.status(pkg, depth),
+		}
+
+		repoName, _, ok := inference.InferRepositoryAndRevision(dependencies.MinimialVersionedPackageRepo{
+			Scheme:  pkg.Scheme,
+			Name:    reposource.PackageName(pkg.Name),
+			Version: pkg.Version,
+		})
+		if ok {
+			entry.RepositoryName = string(repoName)
+		} else if entry.Status == shared.DependencyIndexingStatusScheduled {
+			entry.Status = shared.DependencyIndexingStatusUnresolvable
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
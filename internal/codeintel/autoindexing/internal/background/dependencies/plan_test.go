@@ -0,0 +1,144 @@
+package dependencies
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+
+	autoindexingshared "github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/shared"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/dependencies"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+)
+
+func TestIndexingPolicyStatus(t *testing.T) {
+	dependencyIndexingMaxDepth = func() int { return 1 }
+	dependencyVersionPins = func() map[string]string {
+		return map[string]string{
+			"semanticdb:com.google.guava:guava": "31.0.0",
+			"npm:react":                         "17.0.2",
+			"npm:lodash":                        "not-a-version",
+		}
+	}
+	t.Cleanup(func() {
+		dependencyIndexingMaxDepth = func() int { return -1 }
+		dependencyVersionPins = func() map[string]string { return nil }
+	})
+
+	mockDepsSvc := NewMockDependenciesService()
+	mockDepsSvc.ListPackageRepoFiltersFunc.SetDefaultReturn([]dependencies.PackageRepoFilter{
+		{
+			Behaviour:     "BLOCK",
+			PackageScheme: "npm",
+			NameFilter:    &struct{ PackageGlob string }{PackageGlob: "@internal/*"},
+		},
+	}, false, nil)
+
+	// The invalid pin for lodash is ignored rather than failing the policy
+	policy, err := newIndexingPolicy(context.Background(), logtest.Scoped(t), mockDepsSvc)
+	if err != nil {
+		t.Fatalf("unexpected error creating indexing policy: %s", err)
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		pkg      shared.Package
+		depth    int
+		expected autoindexingshared.DependencyIndexingStatus
+	}{
+		{
+			name:     "semanticdb below pin",
+			pkg:      shared.Package{Scheme: "semanticdb", Name: "maven/com.google.guava/guava", Version: "30.1.0"},
+			expected: autoindexingshared.DependencyIndexingStatusScheduled,
+		},
+		{
+			name:     "semanticdb above pin",
+			pkg:      shared.Package{Scheme: "semanticdb", Name: "maven/com.google.guava/guava", Version: "32.0.0"},
+			expected: autoindexingshared.DependencyIndexingStatusVersionPinned,
+		},
+		{
+			name:     "npm at pin",
+			pkg:      shared.Package{Scheme: "npm", Name: "react", Version: "17.0.2"},
+			expected: autoindexingshared.DependencyIndexingStatusScheduled,
+		},
+		{
+			name:     "scip-typescript above npm pin",
+			pkg:      shared.Package{Scheme: "scip-typescript", Name: "react", Version: "18.2.0"},
+			expected: autoindexingshared.DependencyIndexingStatusVersionPinned,
+		},
+		{
+			name:     "npm with invalid pin",
+			pkg:      shared.Package{Scheme: "npm", Name: "lodash", Version: "4.17.21"},
+			expected: autoindexingshared.DependencyIndexingStatusScheduled,
+		},
+		{
+			name:     "npm blocked",
+			pkg:      shared.Package{Scheme: "npm", Name: "@internal/utils", Version: "1.0.0"},
+			expected: autoindexingshared.DependencyIndexingStatusBlocked,
+		},
+		{
+			name:     "npm name that fails normalization",
+			pkg:      shared.Package{Scheme: "npm", Name: "@automapper/classes/transformer-plugin", Version: "0.24.0"},
+			expected: autoindexingshared.DependencyIndexingStatusScheduled,
+		},
+		{
+			name:     "depth exceeded",
+			pkg:      shared.Package{Scheme: "npm", Name: "react", Version: "17.0.2"},
+			depth:    2,
+			expected: autoindexingshared.DependencyIndexingStatusDepthExceeded,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if status := policy.status(testCase.pkg, testCase.depth); status != testCase.expected {
+				t.Errorf("unexpected status. want=%s have=%s", testCase.expected, status)
+			}
+		})
+	}
+}
+
+func TestGetDependencyIndexingPlan(t *testing.T) {
+	dependencyIndexingMaxDepth = func() int { return 1 }
+	dependencyVersionPins = func() map[string]string {
+		return map[string]string{"npm:react": "17.0.2"}
+	}
+	t.Cleanup(func() {
+		dependencyIndexingMaxDepth = func() int { return -1 }
+		dependencyVersionPins = func() map[string]string { return nil }
+	})
+
+	mockDepsSvc := NewMockDependenciesService()
+	mockStore := NewMockStore()
+	mockStore.GetDependencyIndexingCandidatesFunc.SetDefaultReturn([]shared.Package{
+		{DumpID: 1, Scheme: "npm", Name: "react", Version: "17.0.2"},
+		{DumpID: 1, Scheme: "npm", Name: "react", Version: "18.2.0"},
+		{DumpID: 1, Scheme: "npm", Name: "@automapper/classes/transformer-plugin", Version: "0.24.0"},
+		{DumpID: 2, Scheme: "gomod", Name: "https://github.com/sample/text", Version: "v1.0.0"},
+	}, nil)
+	mockStore.GetDependencyIndexingDepthFunc.SetDefaultHook(func(_ context.Context, uploadID, _ int) (int, bool, error) {
+		if uploadID == 1 {
+			return 0, true, nil
+		}
+		return 0, false, nil
+	})
+
+	entries, err := GetDependencyIndexingPlan(context.Background(), logtest.Scoped(t), mockStore, mockDepsSvc, 50)
+	if err != nil {
+		t.Fatalf("unexpected error getting plan: %s", err)
+	}
+
+	expected := []autoindexingshared.DependencyIndexingPlanEntry{
+		{UploadID: 1, Scheme: "npm", Name: "react", Version: "17.0.2", RepositoryName: "npm/react", Depth: 1, Status: autoindexingshared.DependencyIndexingStatusScheduled},
+		{UploadID: 1, Scheme: "npm", Name: "react", Version: "18.2.0", RepositoryName: "npm/react", Depth: 1, Status: autoindexingshared.DependencyIndexingStatusVersionPinned},
+		{UploadID: 1, Scheme: "npm", Name: "@automapper/classes/transformer-plugin", Version: "0.24.0", Depth: 1, Status: autoindexingshared.DependencyIndexingStatusUnresolvable},
+		{UploadID: 2, Scheme: "gomod", Name: "https://github.com/sample/text", Version: "v1.0.0", RepositoryName: "github.com/sample/text", Depth: 2, Status: autoindexingshared.DependencyIndexingStatusDepthExceeded},
+	}
+	if diff := cmp.Diff(expected, entries); diff != "" {
+		t.Errorf("unexpected plan (-want +got):\n%s", diff)
+	}
+
+	// Depth is looked up once per upload
+	if calls := len(mockStore.GetDependencyIndexingDepthFunc.History()); calls != 2 {
+		t.Errorf("unexpected number of depth lookups. want=%d have=%d", 2, calls)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/redispubsub"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
 
@@ -32,6 +33,7 @@ var IndexWorkerStoreOptions = dbworkerstore.Options[uploadsshared.Index]{
 	OrderByExpression: sqlf.Sprintf("u.queued_at, u.id"),
 	StalledMaxAge:     stalledIndexMaxAge,
 	MaxNumResets:      indexMaxNumResets,
+	OnStateChange:     redispubsub.RecordStateChangeHook("lsif_indexes"),
 }
 
 var indexColumnsWithNullRank = []*sqlf.Query{
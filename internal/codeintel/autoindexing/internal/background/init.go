@@ -80,6 +80,8 @@ func NewDependencyIndexSchedulers(
 		dependencies.NewDependencyIndexingScheduler(
 			dependencyIndexingStore,
 			uploadSvc,
+			depsSvc,
+			store,
 			repoStore,
 			externalServiceStore,
 			gitserverRepoStore,
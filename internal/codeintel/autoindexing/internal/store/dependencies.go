@@ -5,9 +5,13 @@ import (
 	"time"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"go.opentelemetry.io/otel/attribute"
 
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 )
 
@@ -57,8 +61,121 @@ func (s *store) QueueRepoRev(ctx context.Context, repositoryID int, rev string)
 	})
 }
 
+// QueueRepoRevs places each of the given revisions of a repository on the on-demand
+// auto-indexing queue. All revisions are queued in a single transaction, so either
+// every revision not already queued is inserted or none are.
+func (s *store) QueueRepoRevs(ctx context.Context, repositoryID int, revs []string) (err error) {
+	ctx, _, endObservation := s.operations.queueRepoRevs.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("repositoryID", repositoryID),
+		attribute.Int("numRevs", len(revs)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.withTransaction(ctx, func(tx *store) error {
+		for _, rev := range revs {
+			isQueued, err := tx.IsQueued(ctx, repositoryID, rev)
+			if err != nil {
+				return err
+			}
+			if isQueued {
+				continue
+			}
+
+			if err := tx.db.Exec(ctx, sqlf.Sprintf(queueRepoRevQuery, repositoryID, rev)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 const queueRepoRevQuery = `
 INSERT INTO codeintel_autoindex_queue (repository_id, rev)
 VALUES (%s, %s)
 ON CONFLICT DO NOTHING
 `
+
+// packageRepositoryTypes are the external service types of repositories that are synced
+// from package hosts rather than code hosts. Uploads for these repositories are only ever
+// created by dependency indexing.
+var packageRepositoryTypes = []string{
+	extsvc.TypeGoModules,
+	extsvc.TypeJVMPackages,
+	extsvc.TypeNpmPackages,
+	extsvc.TypePythonPackages,
+	extsvc.TypeRubyPackages,
+	extsvc.TypeRustPackages,
+}
+
+// GetDependencyIndexingDepth returns the number of dependency hops between the given upload
+// and the nearest completed upload of a repository that is not a package repository. The
+// search follows package references backwards and gives up after maxDepth hops, in which
+// case the returned flag is false.
+func (s *store) GetDependencyIndexingDepth(ctx context.Context, uploadID, maxDepth int) (_ int, _ bool, err error) {
+	ctx, _, endObservation := s.operations.getDependencyIndexingDepth.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("uploadID", uploadID),
+		attribute.Int("maxDepth", maxDepth),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return basestore.ScanFirstInt(s.db.Query(ctx, sqlf.Sprintf(
+		getDependencyIndexingDepthQuery,
+		uploadID,
+		maxDepth,
+		pq.Array(packageRepositoryTypes),
+	)))
+}
+
+const getDependencyIndexingDepthQuery = `
+WITH RECURSIVE dependents(upload_id, depth) AS (
+	SELECT %s::integer, 0
+
+	UNION
+
+	SELECT r.dump_id, d.depth + 1
+	FROM dependents d
+	JOIN lsif_packages p ON p.dump_id = d.upload_id
+	JOIN lsif_references r ON r.scheme = p.scheme AND r.name = p.name AND r.version = p.version
+	WHERE d.depth < %s
+)
+SELECT d.depth
+FROM dependents d
+JOIN lsif_uploads u ON u.id = d.upload_id
+JOIN repo ON repo.id = u.repository_id
+WHERE
+	u.state = 'completed' AND
+	repo.deleted_at IS NULL AND
+	(repo.external_service_type IS NULL OR repo.external_service_type != ALL(%s))
+ORDER BY d.depth
+LIMIT 1
+`
+
+// GetDependencyIndexingCandidates returns the distinct set of packages referenced by the
+// most recent completed upload of each root and indexer in the given repository.
+func (s *store) GetDependencyIndexingCandidates(ctx context.Context, repositoryID int) (_ []uploadsshared.Package, err error) {
+	ctx, _, endObservation := s.operations.getDependencyIndexingCandidates.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("repositoryID", repositoryID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return scanPackages(s.db.Query(ctx, sqlf.Sprintf(getDependencyIndexingCandidatesQuery, repositoryID)))
+}
+
+const getDependencyIndexingCandidatesQuery = `
+WITH latest_uploads AS (
+	SELECT DISTINCT ON (u.root, u.indexer) u.id
+	FROM lsif_uploads u
+	WHERE u.repository_id = %s AND u.state = 'completed'
+	ORDER BY u.root, u.indexer, u.finished_at DESC
+)
+SELECT DISTINCT r.dump_id, r.scheme, r.manager, r.name, COALESCE(r.version, '')
+FROM lsif_references r
+WHERE r.dump_id IN (SELECT id FROM latest_uploads)
+ORDER BY r.scheme, r.name, r.version, r.dump_id
+`
+
+var scanPackages = basestore.NewSliceScanner(func(s dbutil.Scanner) (pkg uploadsshared.Package, err error) {
+	err = s.Scan(&pkg.DumpID, &pkg.Scheme, &pkg.Manager, &pkg.Name, &pkg.Version)
+	return pkg, err
+})
@@ -8,6 +8,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/log/logtest"
 
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -42,6 +43,98 @@ func TestInsertDependencyIndexingJob(t *testing.T) {
 	}
 }
 
+func TestGetDependencyIndexingDepth(t *testing.T) {
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	store := New(&observation.TestContext, db)
+
+	// 50 is a code host repository; 51 and 52 are npm package repositories
+	insertUploads(t, db,
+		upload{ID: 1, RepositoryID: 50},
+		upload{ID: 2, RepositoryID: 51},
+		upload{ID: 3, RepositoryID: 52},
+	)
+	if _, err := db.ExecContext(ctx, `UPDATE repo SET external_service_type = 'npmPackages' WHERE id IN (51, 52)`); err != nil {
+		t.Fatalf("unexpected error updating repos: %s", err)
+	}
+
+	// 1 -> 2 -> 3
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO lsif_packages (scheme, name, version, dump_id) VALUES ('npm', 'a', '1.0.0', 2), ('npm', 'b', '1.0.0', 3);
+		INSERT INTO lsif_references (scheme, name, version, dump_id) VALUES ('npm', 'a', '1.0.0', 1), ('npm', 'b', '1.0.0', 2);
+	`); err != nil {
+		t.Fatalf("unexpected error inserting packages: %s", err)
+	}
+
+	for _, testCase := range []struct {
+		uploadID      int
+		maxDepth      int
+		expectedDepth int
+		expectedOK    bool
+	}{
+		{uploadID: 1, maxDepth: 5, expectedDepth: 0, expectedOK: true},
+		{uploadID: 2, maxDepth: 5, expectedDepth: 1, expectedOK: true},
+		{uploadID: 3, maxDepth: 5, expectedDepth: 2, expectedOK: true},
+		{uploadID: 3, maxDepth: 1, expectedOK: false},
+	} {
+		depth, ok, err := store.GetDependencyIndexingDepth(ctx, testCase.uploadID, testCase.maxDepth)
+		if err != nil {
+			t.Fatalf("unexpected error getting dependency indexing depth: %s", err)
+		}
+		if ok != testCase.expectedOK || depth != testCase.expectedDepth {
+			t.Errorf("unexpected depth for upload %d (maxDepth=%d). want=%d,%v have=%d,%v", testCase.uploadID, testCase.maxDepth, testCase.expectedDepth, testCase.expectedOK, depth, ok)
+		}
+	}
+}
+
+func TestGetDependencyIndexingCandidates(t *testing.T) {
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	store := New(&observation.TestContext, db)
+
+	t1 := time.Unix(1587396557, 0).UTC()
+	t2 := t1.Add(time.Hour)
+
+	// Upload 2 supersedes upload 1 for the same root and indexer; upload 3 has a different
+	// root; upload 4 is not completed; upload 5 belongs to another repository.
+	insertUploads(t, db,
+		upload{ID: 1, RepositoryID: 50, Root: "a/", Indexer: "scip-typescript", FinishedAt: &t1},
+		upload{ID: 2, RepositoryID: 50, Root: "a/", Indexer: "scip-typescript", FinishedAt: &t2},
+		upload{ID: 3, RepositoryID: 50, Root: "b/", Indexer: "scip-java", FinishedAt: &t1},
+		upload{ID: 4, RepositoryID: 50, Root: "c/", Indexer: "scip-go", State: "queued"},
+		upload{ID: 5, RepositoryID: 51, Root: "a/", Indexer: "scip-typescript", FinishedAt: &t1},
+	)
+
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO lsif_references (scheme, manager, name, version, dump_id) VALUES
+			('npm', 'npm', 'left-pad', '1.0.0', 1),
+			('npm', 'npm', 'react', '18.2.0', 2),
+			('npm', 'npm', 'react', '18.2.0', 2),
+			('npm', 'npm', 'unversioned', NULL, 2),
+			('semanticdb', 'maven', 'maven/junit/junit', '4.2', 3),
+			('gomod', 'go', 'https://github.com/sample/text', 'v1.0.0', 4),
+			('npm', 'npm', 'lodash', '4.17.21', 5);
+	`); err != nil {
+		t.Fatalf("unexpected error inserting references: %s", err)
+	}
+
+	pkgs, err := store.GetDependencyIndexingCandidates(ctx, 50)
+	if err != nil {
+		t.Fatalf("unexpected error getting dependency indexing candidates: %s", err)
+	}
+
+	expected := []uploadsshared.Package{
+		{DumpID: 2, Scheme: "npm", Manager: "npm", Name: "react", Version: "18.2.0"},
+		{DumpID: 2, Scheme: "npm", Manager: "npm", Name: "unversioned", Version: ""},
+		{DumpID: 3, Scheme: "semanticdb", Manager: "maven", Name: "maven/junit/junit", Version: "4.2"},
+	}
+	if diff := cmp.Diff(expected, pkgs); diff != "" {
+		t.Errorf("unexpected packages (-want +got):\n%s", diff)
+	}
+}
+
 func TestGetQueuedRepoRev(t *testing.T) {
 	ctx := context.Background()
 	logger := logtest.Scoped(t)
@@ -83,3 +176,30 @@ func TestGetQueuedRepoRev(t *testing.T) {
 		t.Errorf("unexpected repo revs (-want +got):\n%s", diff)
 	}
 }
+
+func TestQueueRepoRevs(t *testing.T) {
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	store := New(&observation.TestContext, db)
+
+	if err := store.QueueRepoRevs(ctx, 50, []string{"deadbeef01", "deadbeef02", "deadbeef03"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := store.QueueRepoRevs(ctx, 51, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	repoRevs, err := store.GetQueuedRepoRev(ctx, 50)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := []RepoRev{
+		{1, 50, "deadbeef01"},
+		{2, 50, "deadbeef02"},
+		{3, 50, "deadbeef03"},
+	}
+	if diff := cmp.Diff(expected, repoRevs); diff != "" {
+		t.Errorf("unexpected repo revs (-want +got):\n%s", diff)
+	}
+}
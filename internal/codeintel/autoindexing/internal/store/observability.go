@@ -29,7 +29,10 @@ type operations struct {
 	isQueuedRootIndexer                    *observation.Operation
 	insertIndexes                          *observation.Operation
 	insertDependencyIndexingJob            *observation.Operation
+	getDependencyIndexingDepth             *observation.Operation
+	getDependencyIndexingCandidates        *observation.Operation
 	queueRepoRev                           *observation.Operation
+	queueRepoRevs                          *observation.Operation
 
 	indexesInserted prometheus.Counter
 }
@@ -85,7 +88,10 @@ func newOperations(observationCtx *observation.Context) *operations {
 		isQueuedRootIndexer:                    op("IsQueuedRootIndexer"),
 		insertIndexes:                          op("InsertIndexes"),
 		insertDependencyIndexingJob:            op("InsertDependencyIndexingJob"),
+		getDependencyIndexingDepth:             op("GetDependencyIndexingDepth"),
+		getDependencyIndexingCandidates:        op("GetDependencyIndexingCandidates"),
 		queueRepoRev:                           op("QueueRepoRev"),
+		queueRepoRevs:                          op("QueueRepoRevs"),
 
 		indexesInserted: indexesInsertedCounter,
 	}
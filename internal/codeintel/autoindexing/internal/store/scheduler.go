@@ -88,13 +88,27 @@ repositories AS (
 	FROM repositories_matching_policy rmp
 	LEFT JOIN lsif_last_index_scan lrs ON lrs.repository_id = rmp.id
 	WHERE
-		-- Records that have not been checked within the global reindex threshold are also eligible for
-		-- indexing. Note that condition here is true for a record that has never been indexed.
-		(%s - lrs.last_index_scan_at > (%s * '1 second'::interval)) IS DISTINCT FROM FALSE OR
-
-		-- Records that have received an update since their last scan are also eligible for re-indexing.
-		-- Note that last_changed is NULL unless the repository is attached to a policy for HEAD.
-		(rmp.last_changed > lrs.last_index_scan_at)
+		(
+			-- Records that have not been checked within the global reindex threshold are also eligible for
+			-- indexing. Note that condition here is true for a record that has never been indexed.
+			(%s - lrs.last_index_scan_at > (%s * '1 second'::interval)) IS DISTINCT FROM FALSE OR
+
+			-- Records that have received an update since their last scan are also eligible for re-indexing.
+			-- Note that last_changed is NULL unless the repository is attached to a policy for HEAD.
+			(rmp.last_changed > lrs.last_index_scan_at)
+		) AND
+
+		-- Archived repositories are not indexed again if the archival policy drops their code
+		-- intelligence data.
+		NOT EXISTS (
+			SELECT 1
+			FROM repo_archival ra
+			WHERE
+				ra.repo_id = rmp.id AND
+				ra.archived_at IS NOT NULL AND
+				-- The policy is read once per query rather than once per repository.
+				(SELECT rap.drop_codeintel FROM repo_archival_policy rap WHERE rap.id = 1)
+		)
 	ORDER BY
 		lrs.last_index_scan_at NULLS FIRST,
 		rmp.id -- tie breaker
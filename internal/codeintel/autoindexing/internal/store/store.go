@@ -45,7 +45,10 @@ type Store interface {
 
 	// Dependency indexing
 	InsertDependencyIndexingJob(ctx context.Context, uploadID int, externalServiceKind string, syncTime time.Time) (int, error)
+	GetDependencyIndexingDepth(ctx context.Context, uploadID, maxDepth int) (int, bool, error)
+	GetDependencyIndexingCandidates(ctx context.Context, repositoryID int) ([]uploadsshared.Package, error)
 	QueueRepoRev(ctx context.Context, repositoryID int, commit string) error
+	QueueRepoRevs(ctx context.Context, repositoryID int, revs []string) error
 }
 
 type RepoRev struct {
@@ -10,7 +10,9 @@ import (
 )
 
 type operations struct {
-	inferIndexConfiguration *observation.Operation
+	inferIndexConfiguration   *observation.Operation
+	queueRepoRevsForBackfill  *observation.Operation
+	getDependencyIndexingPlan *observation.Operation
 }
 
 var m = new(metrics.SingletonREDMetrics)
@@ -40,6 +42,8 @@ func newOperations(observationCtx *observation.Context) *operations {
 	}
 
 	return &operations{
-		inferIndexConfiguration: op("InferIndexConfiguration"),
+		inferIndexConfiguration:   op("InferIndexConfiguration"),
+		queueRepoRevsForBackfill:  op("QueueRepoRevsForBackfill"),
+		getDependencyIndexingPlan: op("GetDependencyIndexingPlan"),
 	}
 }
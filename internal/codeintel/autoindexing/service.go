@@ -4,11 +4,13 @@ import (
 	"context"
 	"time"
 
+	"github.com/gobwas/glob"
 	"github.com/sourcegraph/log"
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
+	dependenciesbackground "github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/internal/background/dependencies"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/internal/enqueuer"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/internal/inference"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/internal/jobselector"
@@ -24,6 +26,7 @@ import (
 
 type Service struct {
 	store           store.Store
+	depsSvc         DependenciesService
 	repoStore       database.RepoStore
 	gitserverClient gitserver.Client
 	indexEnqueuer   *enqueuer.IndexEnqueuer
@@ -34,6 +37,7 @@ type Service struct {
 func newService(
 	observationCtx *observation.Context,
 	store store.Store,
+	depsSvc DependenciesService,
 	inferenceSvc InferenceService,
 	repoUpdater RepoUpdaterClient,
 	repoStore database.RepoStore,
@@ -65,6 +69,7 @@ func newService(
 
 	return &Service{
 		store:           store,
+		depsSvc:         depsSvc,
 		repoStore:       repoStore,
 		gitserverClient: gitserverClient,
 		indexEnqueuer:   indexEnqueuer,
@@ -119,6 +124,117 @@ func (s *Service) QueueRepoRev(ctx context.Context, repositoryID int, rev string
 	return s.store.QueueRepoRev(ctx, repositoryID, rev)
 }
 
+// QueueRepoRevsForBackfill resolves the commit range and tag pattern described by the given
+// options into a set of commits and places each of them on the on-demand auto-indexing queue.
+// The on-demand scheduler drains that queue in bounded batches on a fixed interval, which
+// throttles the rate at which index jobs for historic commits are created.
+//
+// The selected commits are queued in batches of opts.BatchSize, each in its own transaction,
+// and returned. If a batch cannot be queued, the commits of the previous batches remain on
+// the queue. Queueing a commit that is already queued is a no-op, so the request can be
+// retried.
+func (s *Service) QueueRepoRevsForBackfill(ctx context.Context, repositoryID int, opts shared.BackfillOptions) (_ []string, err error) {
+	ctx, _, endObservation := s.operations.queueRepoRevsForBackfill.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("repositoryID", repositoryID),
+		attribute.String("fromRev", opts.FromRev),
+		attribute.String("toRev", opts.ToRev),
+		attribute.String("tagPattern", opts.TagPattern),
+		attribute.Int("limit", opts.Limit),
+		attribute.Int("batchSize", opts.BatchSize),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if opts.ToRev == "" && opts.TagPattern == "" {
+		return nil, errors.New("either a target revision or a tag pattern must be supplied")
+	}
+
+	limit := opts.Limit
+	if limit <= 0 || limit > shared.MaxBackfillCommits {
+		limit = shared.MaxBackfillCommits
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = shared.DefaultBackfillBatchSize
+	}
+
+	repo, err := s.repoStore.Get(ctx, api.RepoID(repositoryID))
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []string
+	seen := map[string]struct{}{}
+	addCommit := func(commit string) {
+		if _, ok := seen[commit]; ok || len(commits) >= limit {
+			return
+		}
+		seen[commit] = struct{}{}
+		commits = append(commits, commit)
+	}
+
+	if opts.TagPattern != "" {
+		pattern, err := glob.Compile(opts.TagPattern)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid tag pattern")
+		}
+
+		tags, err := s.gitserverClient.ListTags(ctx, repo.Name)
+		if err != nil {
+			return nil, errors.Wrap(err, "gitserver.ListTags")
+		}
+
+		for _, tag := range tags {
+			if pattern.Match(tag.Name) {
+				addCommit(string(tag.CommitID))
+			}
+		}
+	}
+
+	if opts.ToRev != "" {
+		revRange := opts.ToRev
+		if opts.FromRev != "" {
+			revRange = opts.FromRev + ".." + opts.ToRev
+		}
+
+		rangeCommits, err := s.gitserverClient.Commits(ctx, authz.DefaultSubRepoPermsChecker, repo.Name, gitserver.CommitsOptions{
+			Range: revRange,
+			N:     uint(limit),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "gitserver.Commits")
+		}
+
+		for _, commit := range rangeCommits {
+			addCommit(string(commit.ID))
+		}
+	}
+
+	for start := 0; start < len(commits); start += batchSize {
+		end := start + batchSize
+		if end > len(commits) {
+			end = len(commits)
+		}
+
+		if err := s.store.QueueRepoRevs(ctx, repositoryID, commits[start:end]); err != nil {
+			return nil, errors.Wrapf(err, "queued %d of %d commits", start, len(commits))
+		}
+	}
+
+	return commits, nil
+}
+
+// GetDependencyIndexingPlan returns the packages referenced by the most recent uploads of the
+// given repository along with whether or not each package would be queued for auto-indexing
+// under the current package repo filters and dependency indexing settings.
+func (s *Service) GetDependencyIndexingPlan(ctx context.Context, repositoryID int) (_ []shared.DependencyIndexingPlanEntry, err error) {
+	ctx, _, endObservation := s.operations.getDependencyIndexingPlan.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("repositoryID", repositoryID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return dependenciesbackground.GetDependencyIndexingPlan(ctx, log.Scoped("dependencyIndexingPlan", ""), s.store, s.depsSvc, repositoryID)
+}
+
 func (s *Service) SetInferenceScript(ctx context.Context, script string) error {
 	return s.store.SetInferenceScript(ctx, script)
 }
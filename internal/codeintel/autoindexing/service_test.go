@@ -5,21 +5,25 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/internal/jobselector"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/shared"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/dependencies"
 	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 	internaltypes "github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 func init() {
@@ -65,6 +69,7 @@ func TestQueueIndexesExplicit(t *testing.T) {
 	service := newService(
 		&observation.TestContext,
 		mockDBStore,
+		nil, // depsSvc
 		inferenceService,
 		nil,                    // repoUpdater
 		defaultMockRepoStore(), // repoStore
@@ -164,6 +169,7 @@ func TestQueueIndexesInDatabase(t *testing.T) {
 	service := newService(
 		&observation.TestContext,
 		mockDBStore,
+		nil, // depsSvc
 		inferenceService,
 		nil,                    // repoUpdater
 		defaultMockRepoStore(), // repoStore
@@ -268,6 +274,7 @@ func TestQueueIndexesInRepository(t *testing.T) {
 	service := newService(
 		&observation.TestContext,
 		mockDBStore,
+		nil, // depsSvc
 		inferenceService,
 		nil,                    // repoUpdater
 		defaultMockRepoStore(), // repoStore
@@ -355,6 +362,7 @@ func TestQueueIndexesInferred(t *testing.T) {
 	service := newService(
 		&observation.TestContext,
 		mockDBStore,
+		nil, // depsSvc
 		inferenceService,
 		nil,                    // repoUpdater
 		defaultMockRepoStore(), // repoStore
@@ -444,6 +452,7 @@ func TestQueueIndexesForPackage(t *testing.T) {
 	service := newService(
 		&observation.TestContext,
 		mockDBStore,
+		nil, // depsSvc
 		inferenceService,
 		mockRepoUpdater,        // repoUpdater
 		defaultMockRepoStore(), // repoStore
@@ -499,6 +508,196 @@ func TestQueueIndexesForPackage(t *testing.T) {
 	}
 }
 
+func TestQueueRepoRevsForBackfill(t *testing.T) {
+	newTestService := func() (*Service, *MockStore, *gitserver.MockClient) {
+		mockDBStore := NewMockStore()
+		gitserverClient := gitserver.NewMockClient()
+		gitserverClient.ListTagsFunc.SetDefaultReturn([]*gitdomain.Tag{
+			{Name: "v1.0.0", CommitID: "c1"},
+			{Name: "v1.1.0", CommitID: "c2"},
+			{Name: "v1.1.0-rc1", CommitID: "c2"},
+			{Name: "v2.0.0", CommitID: "c4"},
+			{Name: "nightly", CommitID: "c5"},
+		}, nil)
+		gitserverClient.CommitsFunc.SetDefaultHook(func(ctx context.Context, _ authz.SubRepoPermissionChecker, repo api.RepoName, opts gitserver.CommitsOptions) ([]*gitdomain.Commit, error) {
+			var commits []*gitdomain.Commit
+			for _, id := range []api.CommitID{"c4", "c3", "c2"} {
+				if len(commits) < int(opts.N) {
+					commits = append(commits, &gitdomain.Commit{ID: id})
+				}
+			}
+			return commits, nil
+		})
+
+		service := newService(
+			&observation.TestContext,
+			mockDBStore,
+			nil, // depsSvc
+			nil, // inferenceSvc
+			nil, // repoUpdater
+			defaultMockRepoStore(),
+			gitserverClient,
+		)
+
+		return service, mockDBStore, gitserverClient
+	}
+
+	testCases := []struct {
+		name             string
+		opts             shared.BackfillOptions
+		expectedCommits  []string
+		expectedBatches  [][]string
+		expectedRange    string
+		expectedLimit    uint
+		expectedListTags bool
+	}{
+		{
+			name:             "tag pattern",
+			opts:             shared.BackfillOptions{TagPattern: "v1.*"},
+			expectedCommits:  []string{"c1", "c2"}, // c2 is deduplicated
+			expectedListTags: true,
+		},
+		{
+			name:            "commit range",
+			opts:            shared.BackfillOptions{FromRev: "v1.0.0", ToRev: "v2.0.0"},
+			expectedCommits: []string{"c4", "c3", "c2"},
+			expectedRange:   "v1.0.0..v2.0.0",
+			expectedLimit:   shared.MaxBackfillCommits,
+		},
+		{
+			name:            "ancestors",
+			opts:            shared.BackfillOptions{ToRev: "v2.0.0", Limit: shared.MaxBackfillCommits * 2},
+			expectedCommits: []string{"c4", "c3", "c2"},
+			expectedRange:   "v2.0.0",
+			expectedLimit:   shared.MaxBackfillCommits,
+		},
+		{
+			name:             "tag pattern and commit range",
+			opts:             shared.BackfillOptions{FromRev: "v1.0.0", ToRev: "v2.0.0", TagPattern: "v*"},
+			expectedCommits:  []string{"c1", "c2", "c4", "c3"},
+			expectedRange:    "v1.0.0..v2.0.0",
+			expectedLimit:    shared.MaxBackfillCommits,
+			expectedListTags: true,
+		},
+		{
+			name:             "limit",
+			opts:             shared.BackfillOptions{FromRev: "v1.0.0", ToRev: "v2.0.0", TagPattern: "v*", Limit: 2},
+			expectedCommits:  []string{"c1", "c2"},
+			expectedRange:    "v1.0.0..v2.0.0",
+			expectedLimit:    2,
+			expectedListTags: true,
+		},
+		{
+			name:             "batch size",
+			opts:             shared.BackfillOptions{FromRev: "v1.0.0", ToRev: "v2.0.0", TagPattern: "v*", BatchSize: 3},
+			expectedCommits:  []string{"c1", "c2", "c4", "c3"},
+			expectedBatches:  [][]string{{"c1", "c2", "c4"}, {"c3"}},
+			expectedRange:    "v1.0.0..v2.0.0",
+			expectedLimit:    shared.MaxBackfillCommits,
+			expectedListTags: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service, mockDBStore, gitserverClient := newTestService()
+
+			commits, err := service.QueueRepoRevsForBackfill(context.Background(), 42, testCase.opts)
+			if err != nil {
+				t.Fatalf("unexpected error queueing commits: %s", err)
+			}
+			if diff := cmp.Diff(testCase.expectedCommits, commits); diff != "" {
+				t.Errorf("unexpected commits (-want +got):\n%s", diff)
+			}
+
+			expectedBatches := testCase.expectedBatches
+			if expectedBatches == nil {
+				expectedBatches = [][]string{testCase.expectedCommits}
+			}
+			var batches [][]string
+			for _, call := range mockDBStore.QueueRepoRevsFunc.History() {
+				if call.Arg1 != 42 {
+					t.Errorf("unexpected repository. want=%d have=%d", 42, call.Arg1)
+				}
+				batches = append(batches, call.Arg2)
+			}
+			if diff := cmp.Diff(expectedBatches, batches); diff != "" {
+				t.Errorf("unexpected queued batches (-want +got):\n%s", diff)
+			}
+			if len(mockDBStore.QueueRepoRevFunc.History()) != 0 {
+				t.Errorf("unexpected calls to QueueRepoRev")
+			}
+
+			if listTags := len(gitserverClient.ListTagsFunc.History()) != 0; listTags != testCase.expectedListTags {
+				t.Errorf("unexpected call to ListTags. want=%v have=%v", testCase.expectedListTags, listTags)
+			}
+
+			if history := gitserverClient.CommitsFunc.History(); testCase.expectedRange == "" {
+				if len(history) != 0 {
+					t.Errorf("unexpected call to Commits")
+				}
+			} else if len(history) != 1 {
+				t.Errorf("unexpected number of calls to Commits. want=%d have=%d", 1, len(history))
+			} else {
+				if history[0].Arg3.Range != testCase.expectedRange {
+					t.Errorf("unexpected range. want=%q have=%q", testCase.expectedRange, history[0].Arg3.Range)
+				}
+				if history[0].Arg3.N != testCase.expectedLimit {
+					t.Errorf("unexpected commit limit. want=%d have=%d", testCase.expectedLimit, history[0].Arg3.N)
+				}
+			}
+		})
+	}
+}
+
+func TestQueueRepoRevsForBackfillErrors(t *testing.T) {
+	mockDBStore := NewMockStore()
+	mockDBStore.QueueRepoRevsFunc.SetDefaultReturn(errors.New("database unavailable"))
+	gitserverClient := gitserver.NewMockClient()
+	gitserverClient.CommitsFunc.SetDefaultReturn([]*gitdomain.Commit{{ID: "c1"}}, nil)
+
+	service := newService(
+		&observation.TestContext,
+		mockDBStore,
+		nil, // depsSvc
+		nil, // inferenceSvc
+		nil, // repoUpdater
+		defaultMockRepoStore(),
+		gitserverClient,
+	)
+
+	if _, err := service.QueueRepoRevsForBackfill(context.Background(), 42, shared.BackfillOptions{FromRev: "v1.0.0"}); err == nil {
+		t.Fatalf("expected an error when neither toRev nor tagPattern is supplied")
+	}
+	if len(gitserverClient.CommitsFunc.History()) != 0 || len(mockDBStore.QueueRepoRevsFunc.History()) != 0 {
+		t.Errorf("unexpected calls for invalid options")
+	}
+
+	if _, err := service.QueueRepoRevsForBackfill(context.Background(), 42, shared.BackfillOptions{TagPattern: "v[1"}); err == nil {
+		t.Fatalf("expected an error for an invalid tag pattern")
+	}
+
+	if commits, err := service.QueueRepoRevsForBackfill(context.Background(), 42, shared.BackfillOptions{ToRev: "HEAD"}); err == nil {
+		t.Fatalf("expected an error when commits cannot be queued")
+	} else if commits != nil {
+		t.Errorf("unexpected commits on failure: %v", commits)
+	}
+
+	// A failing batch stops the backfill, but the previous batches stay queued.
+	mockDBStore.QueueRepoRevsFunc.PushReturn(nil)
+	gitserverClient.CommitsFunc.SetDefaultReturn([]*gitdomain.Commit{{ID: "c1"}, {ID: "c2"}, {ID: "c3"}}, nil)
+	calls := len(mockDBStore.QueueRepoRevsFunc.History())
+
+	if _, err := service.QueueRepoRevsForBackfill(context.Background(), 42, shared.BackfillOptions{ToRev: "HEAD", BatchSize: 1}); err == nil {
+		t.Fatalf("expected an error when a batch cannot be queued")
+	} else if !strings.Contains(err.Error(), "queued 1 of 3 commits") {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if history := mockDBStore.QueueRepoRevsFunc.History()[calls:]; len(history) != 2 {
+		t.Errorf("unexpected number of calls to QueueRepoRevs. want=%d have=%d", 2, len(history))
+	}
+}
+
 func defaultMockRepoStore() *database.MockRepoStore {
 	repoStore := database.NewMockRepoStore()
 	repoStore.GetFunc.SetDefaultHook(func(ctx context.Context, id api.RepoID) (*internaltypes.Repo, error) {
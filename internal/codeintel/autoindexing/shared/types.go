@@ -13,3 +13,64 @@ type InferenceResult struct {
 	IndexJobs       []config.IndexJob
 	InferenceOutput string
 }
+
+// MaxBackfillCommits is the maximum number of commits queued by a single backfill request.
+const MaxBackfillCommits = 1000
+
+// DefaultBackfillBatchSize is the number of commits inserted into the on-demand
+// auto-indexing queue per transaction when a backfill request does not specify one.
+const DefaultBackfillBatchSize = 100
+
+// BackfillOptions describes a set of historic commits of a repository that should be
+// queued for auto-indexing.
+type BackfillOptions struct {
+	// FromRev and ToRev describe the commit range FromRev..ToRev. If FromRev is empty,
+	// all ancestors of ToRev are considered. If ToRev is empty and no tag pattern is
+	// supplied, no commits are selected by range.
+	FromRev string
+	ToRev   string
+
+	// TagPattern is a glob pattern; the commits referenced by every matching tag are
+	// selected in addition to the commit range.
+	TagPattern string
+
+	// Limit is the maximum number of commits to queue. Values that are not positive or
+	// that exceed MaxBackfillCommits are replaced by MaxBackfillCommits.
+	Limit int
+
+	// BatchSize is the number of commits inserted into the queue per transaction. Values
+	// that are not positive are replaced by DefaultBackfillBatchSize.
+	BatchSize int
+}
+
+// DependencyIndexingStatus describes whether or not the dependency indexing scheduler
+// would queue auto-indexing jobs for a particular dependency package.
+type DependencyIndexingStatus string
+
+const (
+	DependencyIndexingStatusScheduled     DependencyIndexingStatus = "SCHEDULED"
+	DependencyIndexingStatusBlocked       DependencyIndexingStatus = "BLOCKED"
+	DependencyIndexingStatusVersionPinned DependencyIndexingStatus = "VERSION_PINNED"
+	DependencyIndexingStatusDepthExceeded DependencyIndexingStatus = "DEPTH_EXCEEDED"
+	DependencyIndexingStatusUnresolvable  DependencyIndexingStatus = "UNRESOLVABLE"
+)
+
+// DependencyIndexingPlanEntry describes how the dependency indexing scheduler treats a
+// single package referenced by a repository's most recent uploads.
+type DependencyIndexingPlanEntry struct {
+	UploadID int
+	Scheme   string
+	Name     string
+	Version  string
+
+	// RepositoryName is the name of the package repository the package resolves to. It is
+	// empty when the package cannot be resolved to a repository.
+	RepositoryName string
+
+	// Depth is the number of dependency hops between the package and the nearest code host
+	// repository. Direct dependencies of a code host repository have depth 1. The depth is
+	// only computed when a maximum dependency indexing depth is configured, and is zero
+	// otherwise.
+	Depth  int
+	Status DependencyIndexingStatus
+}
@@ -18,6 +18,10 @@ type AutoIndexingService interface {
 
 	// Inference
 	QueueIndexes(ctx context.Context, repositoryID int, rev, configuration string, force bool, bypassLimit bool) ([]uploadsshared.Index, error)
+	QueueRepoRevsForBackfill(ctx context.Context, repositoryID int, opts shared.BackfillOptions) ([]string, error)
 	InferIndexConfiguration(ctx context.Context, repositoryID int, commit string, localOverrideScript string, bypassLimit bool) (*shared.InferenceResult, error)
 	InferIndexJobsFromRepositoryStructure(ctx context.Context, repositoryID int, commit string, localOverrideScript string, bypassLimit bool) (*shared.InferenceResult, error)
+
+	// Dependency indexing
+	GetDependencyIndexingPlan(ctx context.Context, repositoryID int) ([]shared.DependencyIndexingPlanEntry, error)
 }
@@ -9,9 +9,11 @@ import (
 
 type operations struct {
 	codeIntelligenceInferenceScript       *observation.Operation
+	dependencyIndexingPlan                *observation.Operation
 	indexConfiguration                    *observation.Operation
 	inferAutoIndexJobsForRepo             *observation.Operation
 	queueAutoIndexJobsForRepo             *observation.Operation
+	queueAutoIndexJobsForCommitRange      *observation.Operation
 	updateCodeIntelligenceInferenceScript *observation.Operation
 	updateRepositoryIndexConfiguration    *observation.Operation
 }
@@ -34,9 +36,11 @@ func newOperations(observationCtx *observation.Context) *operations {
 
 	return &operations{
 		codeIntelligenceInferenceScript:       op("CodeIntelligenceInferenceScript"),
+		dependencyIndexingPlan:                op("DependencyIndexingPlan"),
 		indexConfiguration:                    op("IndexConfiguration"),
 		inferAutoIndexJobsForRepo:             op("InferAutoIndexJobsForRepo"),
 		queueAutoIndexJobsForRepo:             op("QueueAutoIndexJobsForRepo"),
+		queueAutoIndexJobsForCommitRange:      op("QueueAutoIndexJobsForCommitRange"),
 		updateCodeIntelligenceInferenceScript: op("UpdateCodeIntelligenceInferenceScript"),
 		updateRepositoryIndexConfiguration:    op("UpdateRepositoryIndexConfiguration"),
 	}
@@ -0,0 +1,83 @@
+package graphql
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/shared"
+	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// 🚨 SECURITY: Only site admins may view the dependency indexing plan
+func (r *rootResolver) DependencyIndexingPlan(ctx context.Context, args *resolverstubs.DependencyIndexingPlanArgs) (_ []resolverstubs.DependencyIndexingPlanEntryResolver, err error) {
+	ctx, _, endObservation := r.operations.dependencyIndexingPlan.WithErrors(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("repository", string(args.Repository)),
+	}})
+	endObservation.OnCancel(ctx, 1, observation.Args{})
+
+	if err := r.siteAdminChecker.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if !autoIndexingEnabled() {
+		return nil, errAutoIndexingNotEnabled
+	}
+
+	repositoryID, err := resolverstubs.UnmarshalID[int](args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := r.autoindexSvc.GetDependencyIndexingPlan(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]resolverstubs.DependencyIndexingPlanEntryResolver, 0, len(entries))
+	for _, entry := range entries {
+		resolvers = append(resolvers, &dependencyIndexingPlanEntryResolver{entry: entry})
+	}
+
+	return resolvers, nil
+}
+
+//
+//
+
+type dependencyIndexingPlanEntryResolver struct {
+	entry shared.DependencyIndexingPlanEntry
+}
+
+func (r *dependencyIndexingPlanEntryResolver) Scheme() string {
+	return r.entry.Scheme
+}
+
+func (r *dependencyIndexingPlanEntryResolver) Name() string {
+	return r.entry.Name
+}
+
+func (r *dependencyIndexingPlanEntryResolver) Version() string {
+	return r.entry.Version
+}
+
+func (r *dependencyIndexingPlanEntryResolver) Status() string {
+	return string(r.entry.Status)
+}
+
+func (r *dependencyIndexingPlanEntryResolver) RepositoryName() *string {
+	if r.entry.RepositoryName == "" {
+		return nil
+	}
+
+	return &r.entry.RepositoryName
+}
+
+func (r *dependencyIndexingPlanEntryResolver) Depth() *int32 {
+	if r.entry.Depth == 0 {
+		return nil
+	}
+
+	depth := int32(r.entry.Depth)
+	return &depth
+}
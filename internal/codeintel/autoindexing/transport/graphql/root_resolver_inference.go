@@ -9,6 +9,7 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/shared"
 	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
 	sharedresolvers "github.com/sourcegraph/sourcegraph/internal/codeintel/shared/resolvers"
 	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
@@ -125,6 +126,39 @@ func (r *rootResolver) QueueAutoIndexJobsForRepo(ctx context.Context, args *reso
 	return resolvers, nil
 }
 
+// 🚨 SECURITY: Only site admins may queue auto-index jobs
+func (r *rootResolver) QueueAutoIndexJobsForCommitRange(ctx context.Context, args *resolverstubs.QueueAutoIndexJobsForCommitRangeArgs) (_ []string, err error) {
+	ctx, _, endObservation := r.operations.queueAutoIndexJobsForCommitRange.WithErrors(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("repository", string(args.Repository)),
+		attribute.String("fromRev", pointers.Deref(args.FromRev, "")),
+		attribute.String("toRev", pointers.Deref(args.ToRev, "")),
+		attribute.String("tagPattern", pointers.Deref(args.TagPattern, "")),
+		attribute.Int("limit", int(pointers.Deref(args.Limit, 0))),
+		attribute.Int("batchSize", int(pointers.Deref(args.BatchSize, 0))),
+	}})
+	endObservation.OnCancel(ctx, 1, observation.Args{})
+
+	if err := r.siteAdminChecker.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if !autoIndexingEnabled() {
+		return nil, errAutoIndexingNotEnabled
+	}
+
+	repositoryID, err := resolverstubs.UnmarshalID[api.RepoID](args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.autoindexSvc.QueueRepoRevsForBackfill(ctx, int(repositoryID), shared.BackfillOptions{
+		FromRev:    pointers.Deref(args.FromRev, ""),
+		ToRev:      pointers.Deref(args.ToRev, ""),
+		TagPattern: pointers.Deref(args.TagPattern, ""),
+		Limit:      int(pointers.Deref(args.Limit, 0)),
+		BatchSize:  int(pointers.Deref(args.BatchSize, 0)),
+	})
+}
+
 //
 //
 
@@ -0,0 +1,93 @@
+package lsifstore
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/shared/ranges"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// GetSymbolDefinitions returns the definitions of the symbols within the given upload whose
+// names are accepted by the given filter. At most limit definitions are returned, ordered by
+// document path.
+func (s *store) GetSymbolDefinitions(ctx context.Context, uploadID int, filter func(symbolName string) bool, limit int) (_ []shared.SymbolDefinition, err error) {
+	ctx, trace, endObservation := s.operations.getSymbolDefinitions.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("uploadID", uploadID),
+		attribute.Int("limit", limit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	var definitions []shared.SymbolDefinition
+	scanner := basestore.NewCallbackScanner(func(dbs dbutil.Scanner) (bool, error) {
+		var (
+			symbolName        string
+			path              string
+			encodedDefinition []byte
+		)
+		if err := dbs.Scan(&symbolName, &path, &encodedDefinition); err != nil {
+			return false, err
+		}
+		if !filter(symbolName) {
+			return true, nil
+		}
+
+		definitionRanges, err := ranges.DecodeRanges(encodedDefinition)
+		if err != nil {
+			return false, err
+		}
+		for _, r := range definitionRanges {
+			definitions = append(definitions, shared.SymbolDefinition{
+				DumpID: uploadID,
+				Symbol: symbolName,
+				Path:   path,
+				Range:  translateRange(r),
+			})
+		}
+
+		return len(definitions) < limit, nil
+	})
+	if err := scanner(s.db.Query(ctx, sqlf.Sprintf(getSymbolDefinitionsQuery, uploadID, uploadID, uploadID))); err != nil {
+		return nil, err
+	}
+	trace.AddEvent("TODO Domain Owner", attribute.Int("numDefinitions", len(definitions)))
+
+	if len(definitions) > limit {
+		definitions = definitions[:limit]
+	}
+	return definitions, nil
+}
+
+const getSymbolDefinitionsQuery = `
+WITH RECURSIVE
+-- Reconstruct the full symbol names of the upload by walking the trie from its roots.
+symbol_names(id, name) AS (
+	SELECT ssn.id, ssn.name_segment
+	FROM codeintel_scip_symbol_names ssn
+	WHERE
+		ssn.upload_id = %s AND
+		ssn.prefix_id IS NULL
+	UNION ALL
+	SELECT ssn.id, sn.name || ssn.name_segment
+	FROM symbol_names sn
+	JOIN codeintel_scip_symbol_names ssn ON
+		ssn.upload_id = %s AND
+		ssn.prefix_id = sn.id
+)
+SELECT
+	sn.name,
+	sid.document_path,
+	ss.definition_ranges
+FROM codeintel_scip_symbols ss
+JOIN symbol_names sn ON sn.id = ss.symbol_id
+JOIN codeintel_scip_document_lookup sid ON sid.id = ss.document_lookup_id
+WHERE
+	ss.upload_id = %s AND
+	ss.definition_ranges IS NOT NULL
+ORDER BY sid.document_path, sn.name
+`
@@ -0,0 +1,46 @@
+package lsifstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGetSymbolDefinitions(t *testing.T) {
+	store := populateTestStore(t)
+
+	definitions, err := store.GetSymbolDefinitions(context.Background(), testSCIPUploadID, func(string) bool { return true }, 5)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(definitions) != 5 {
+		t.Fatalf("unexpected number of definitions. want=%d have=%d", 5, len(definitions))
+	}
+	for _, definition := range definitions {
+		if definition.DumpID != testSCIPUploadID || definition.Symbol == "" || definition.Path == "" {
+			t.Errorf("unexpected definition %+v", definition)
+		}
+	}
+
+	isProvider := func(symbolName string) bool { return strings.Contains(symbolName, "`providers.ts`/") }
+	definitions, err = store.GetSymbolDefinitions(context.Background(), testSCIPUploadID, isProvider, 100)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(definitions) == 0 {
+		t.Fatalf("expected definitions in providers.ts")
+	}
+	for _, definition := range definitions {
+		if definition.Path != "template/src/lsif/providers.ts" {
+			t.Errorf("unexpected definition path %q", definition.Path)
+		}
+	}
+
+	definitions, err = store.GetSymbolDefinitions(context.Background(), testSCIPUploadID, func(string) bool { return false }, 100)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(definitions) != 0 {
+		t.Errorf("unexpected definitions %+v", definitions)
+	}
+}
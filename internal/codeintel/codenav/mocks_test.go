@@ -79,6 +79,9 @@ type MockLsifStore struct {
 	// GetStencilFunc is an instance of a mock function object controlling
 	// the behavior of the method GetStencil.
 	GetStencilFunc *LsifStoreGetStencilFunc
+	// GetSymbolDefinitionsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetSymbolDefinitions.
+	GetSymbolDefinitionsFunc *LsifStoreGetSymbolDefinitionsFunc
 	// SCIPDocumentFunc is an instance of a mock function object controlling
 	// the behavior of the method SCIPDocument.
 	SCIPDocumentFunc *LsifStoreSCIPDocumentFunc
@@ -173,6 +176,11 @@ func NewMockLsifStore() *MockLsifStore {
 				return
 			},
 		},
+		GetSymbolDefinitionsFunc: &LsifStoreGetSymbolDefinitionsFunc{
+			defaultHook: func(context.Context, int, func(string) bool, int) (r0 []shared.SymbolDefinition, r1 error) {
+				return
+			},
+		},
 		SCIPDocumentFunc: &LsifStoreSCIPDocumentFunc{
 			defaultHook: func(context.Context, int, string) (r0 *scip.Document, r1 error) {
 				return
@@ -270,6 +278,11 @@ func NewStrictMockLsifStore() *MockLsifStore {
 				panic("unexpected invocation of MockLsifStore.GetStencil")
 			},
 		},
+		GetSymbolDefinitionsFunc: &LsifStoreGetSymbolDefinitionsFunc{
+			defaultHook: func(context.Context, int, func(string) bool, int) ([]shared.SymbolDefinition, error) {
+				panic("unexpected invocation of MockLsifStore.GetSymbolDefinitions")
+			},
+		},
 		SCIPDocumentFunc: &LsifStoreSCIPDocumentFunc{
 			defaultHook: func(context.Context, int, string) (*scip.Document, error) {
 				panic("unexpected invocation of MockLsifStore.SCIPDocument")
@@ -333,6 +346,9 @@ func NewMockLsifStoreFrom(i lsifstore.LsifStore) *MockLsifStore {
 		GetStencilFunc: &LsifStoreGetStencilFunc{
 			defaultHook: i.GetStencil,
 		},
+		GetSymbolDefinitionsFunc: &LsifStoreGetSymbolDefinitionsFunc{
+			defaultHook: i.GetSymbolDefinitions,
+		},
 		SCIPDocumentFunc: &LsifStoreSCIPDocumentFunc{
 			defaultHook: i.SCIPDocument,
 		},
@@ -2391,6 +2407,122 @@ func (c LsifStoreGetStencilFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// LsifStoreGetSymbolDefinitionsFunc describes the behavior when the
+// GetSymbolDefinitions method of the parent MockLsifStore instance is
+// invoked.
+type LsifStoreGetSymbolDefinitionsFunc struct {
+	defaultHook func(context.Context, int, func(string) bool, int) ([]shared.SymbolDefinition, error)
+	hooks       []func(context.Context, int, func(string) bool, int) ([]shared.SymbolDefinition, error)
+	history     []LsifStoreGetSymbolDefinitionsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetSymbolDefinitions delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLsifStore) GetSymbolDefinitions(v0 context.Context, v1 int, v2 func(string) bool, v3 int) ([]shared.SymbolDefinition, error) {
+	r0, r1 := m.GetSymbolDefinitionsFunc.nextHook()(v0, v1, v2, v3)
+	m.GetSymbolDefinitionsFunc.appendCall(LsifStoreGetSymbolDefinitionsFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetSymbolDefinitions
+// method of the parent MockLsifStore instance is invoked and the hook queue
+// is empty.
+func (f *LsifStoreGetSymbolDefinitionsFunc) SetDefaultHook(hook func(context.Context, int, func(string) bool, int) ([]shared.SymbolDefinition, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetSymbolDefinitions method of the parent MockLsifStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *LsifStoreGetSymbolDefinitionsFunc) PushHook(hook func(context.Context, int, func(string) bool, int) ([]shared.SymbolDefinition, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LsifStoreGetSymbolDefinitionsFunc) SetDefaultReturn(r0 []shared.SymbolDefinition, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, func(string) bool, int) ([]shared.SymbolDefinition, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LsifStoreGetSymbolDefinitionsFunc) PushReturn(r0 []shared.SymbolDefinition, r1 error) {
+	f.PushHook(func(context.Context, int, func(string) bool, int) ([]shared.SymbolDefinition, error) {
+		return r0, r1
+	})
+}
+
+func (f *LsifStoreGetSymbolDefinitionsFunc) nextHook() func(context.Context, int, func(string) bool, int) ([]shared.SymbolDefinition, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LsifStoreGetSymbolDefinitionsFunc) appendCall(r0 LsifStoreGetSymbolDefinitionsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LsifStoreGetSymbolDefinitionsFuncCall
+// objects describing the invocations of this function.
+func (f *LsifStoreGetSymbolDefinitionsFunc) History() []LsifStoreGetSymbolDefinitionsFuncCall {
+	f.mutex.Lock()
+	history := make([]LsifStoreGetSymbolDefinitionsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LsifStoreGetSymbolDefinitionsFuncCall is an object that describes an
+// invocation of method GetSymbolDefinitions on an instance of
+// MockLsifStore.
+type LsifStoreGetSymbolDefinitionsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 func(string) bool
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.SymbolDefinition
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LsifStoreGetSymbolDefinitionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LsifStoreGetSymbolDefinitionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // LsifStoreSCIPDocumentFunc describes the behavior when the SCIPDocument
 // method of the parent MockLsifStore instance is invoked.
 type LsifStoreSCIPDocumentFunc struct {
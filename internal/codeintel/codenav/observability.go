@@ -23,6 +23,8 @@ type operations struct {
 	getClosestDumpsForBlob *observation.Operation
 	snapshotForDocument    *observation.Operation
 	visibleUploadsForPath  *observation.Operation
+	searchSymbols          *observation.Operation
+	getCallGraph           *observation.Operation
 }
 
 var m = new(metrics.SingletonREDMetrics)
@@ -57,6 +59,8 @@ func newOperations(observationCtx *observation.Context) *operations {
 		getClosestDumpsForBlob: op("GetClosestDumpsForBlob"),
 		snapshotForDocument:    op("SnapshotForDocument"),
 		visibleUploadsForPath:  op("VisibleUploadsForPath"),
+		searchSymbols:          op("SearchSymbols"),
+		getCallGraph:           op("GetCallGraph"),
 	}
 }
 
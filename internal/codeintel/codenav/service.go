@@ -685,22 +685,49 @@ func (s *Service) GetImplementations(ctx context.Context, args PositionalRequest
 		attribute.Int("numImplementationMonikers", len(cursor.OrderedImplementationMonikers)),
 		attribute.String("implementationMonikers", monikersToString(cursor.OrderedImplementationMonikers)))
 
-	if cursor.OrderedExportMonikers == nil {
-		if cursor.OrderedExportMonikers, err = s.getOrderedMonikers(ctx, visibleUploads, "export"); err != nil {
+	// Gather the monikers of the packages that define or import the symbol at the requested
+	// position. Dependent repositories are found through the package reference data of these
+	// monikers, so that implementations of an interface defined in another package are found
+	// in every repository with an upload that depends on that package.
+	if cursor.OrderedRemoteMonikers == nil {
+		if cursor.OrderedRemoteMonikers, err = s.getOrderedMonikers(ctx, visibleUploads, "import", "export"); err != nil {
 			return nil, cursor, err
 		}
 	}
 	trace.AddEvent("TODO Domain Owner",
-		attribute.Int("numExportMonikers", len(cursor.OrderedExportMonikers)),
-		attribute.String("exportMonikers", monikersToString(cursor.OrderedExportMonikers)))
+		attribute.Int("numRemoteMonikers", len(cursor.OrderedRemoteMonikers)),
+		attribute.String("remoteMonikers", monikersToString(cursor.OrderedRemoteMonikers)))
+
+	// The uploads that define the imported packages are searched first, as they contain the
+	// implementations that live next to the definition of the symbol.
+	if cursor.RemoteCursor.UploadBatchIDs == nil {
+		cursor.RemoteCursor.UploadBatchIDs = []int{}
+		definitionUploads, err := s.getUploadsWithDefinitionsForMonikers(ctx, cursor.OrderedRemoteMonikers, requestState)
+		if err != nil {
+			return nil, cursor, err
+		}
+		for i := range definitionUploads {
+			if !isVisibleUpload(visibleUploads, definitionUploads[i].ID) {
+				cursor.RemoteCursor.UploadBatchIDs = append(cursor.RemoteCursor.UploadBatchIDs, definitionUploads[i].ID)
+			}
+		}
+	}
 
-	// Phase 1: Gather all "local" locations via LSIF graph traversal. We'll continue to request additional
-	// locations until we fill an entire page (the size of which is denoted by the given limit) or there are
-	// no more local results remaining.
+	// Local locations (via LSIF graph traversal) and remote locations in dependents (via moniker
+	// search) are interleaved: while both have results remaining, each page is split between them,
+	// so that implementations in dependents are not pushed back behind every local result. Once
+	// one side is exhausted, the other fills entire pages.
+	//
+	// Phase 2 (gathering all "dependencies" from a SCIP document) is skipped as it seems redundant.
 	var locations []shared.Location
 	if cursor.Phase == "local" {
-		for len(locations) < args.Limit {
-			localLocations, hasMore, err := s.getPageLocalLocations(ctx, s.lsifstore.GetImplementationLocations, visibleUploads, &cursor.LocalCursor, args.Limit-len(locations), trace)
+		localLimit := args.Limit
+		if !cursor.DependentsDone {
+			localLimit = (args.Limit + 1) / 2
+		}
+
+		for len(locations) < localLimit {
+			localLocations, hasMore, err := s.getPageLocalLocations(ctx, s.lsifstore.GetImplementationLocations, visibleUploads, &cursor.LocalCursor, localLimit-len(locations), trace)
 			if err != nil {
 				return nil, cursor, err
 			}
@@ -713,23 +740,41 @@ func (s *Service) GetImplementations(ctx context.Context, args PositionalRequest
 		}
 	}
 
-	// Phase 2: Is skipped as it seems redundant to gathering all "dependencies" from a SCIP document.
-	// Phase 3: Gather all "remote" locations in dependents via moniker search.
-	if cursor.Phase == "dependents" {
+	if (cursor.Phase == "local" || cursor.Phase == "dependents") && !cursor.DependentsDone {
 		for len(locations) < args.Limit {
-			remoteLocations, hasMore, err := s.getPageRemoteLocations(ctx, "implementations", visibleUploads, cursor.OrderedExportMonikers, &cursor.RemoteCursor, args.Limit-len(locations), trace, args.RequestArgs, requestState)
+			remoteLocations, hasMore, err := s.getPageRemoteLocations(ctx, "implementations", visibleUploads, cursor.OrderedRemoteMonikers, &cursor.RemoteCursor, args.Limit-len(locations), trace, args.RequestArgs, requestState)
 			if err != nil {
 				return nil, cursor, err
 			}
 			locations = append(locations, remoteLocations...)
 
 			if !hasMore {
-				cursor.Phase = "done"
+				cursor.DependentsDone = true
 				break
 			}
 		}
 	}
 
+	// Fill the rest of the page with local locations once the dependents are exhausted.
+	if cursor.Phase == "local" && cursor.DependentsDone {
+		for len(locations) < args.Limit {
+			localLocations, hasMore, err := s.getPageLocalLocations(ctx, s.lsifstore.GetImplementationLocations, visibleUploads, &cursor.LocalCursor, args.Limit-len(locations), trace)
+			if err != nil {
+				return nil, cursor, err
+			}
+			locations = append(locations, localLocations...)
+
+			if !hasMore {
+				cursor.Phase = "dependents"
+				break
+			}
+		}
+	}
+
+	if cursor.Phase == "dependents" && cursor.DependentsDone {
+		cursor.Phase = "done"
+	}
+
 	trace.AddEvent("TODO Domain Owner", attribute.Int("numLocations", len(locations)))
 
 	// Adjust the locations back to the appropriate range in the target commits. This adjusts
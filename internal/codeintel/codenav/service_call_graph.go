@@ -0,0 +1,421 @@
+package codenav
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/scip/bindings/go/scip"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+// MaxCallGraphDepth is the maximum number of calls followed from the requested symbol.
+const MaxCallGraphDepth = 5
+
+// maxCallGraphLocations is the maximum number of definitions or references of a single
+// symbol read while traversing the call graph.
+const maxCallGraphLocations = 1000
+
+// GetCallGraph returns the functions calling (or called by) the function or method at the given
+// position, following calls up to the requested depth. The graph is computed from the SCIP data
+// of the index providing the symbol: a call is a reference to a function or method occurring in
+// the body of another one.
+func (s *Service) GetCallGraph(ctx context.Context, args CallGraphArgs, requestState RequestState) (_ CallGraph, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getCallGraph, serviceObserverThreshold, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("repositoryID", args.RepositoryID),
+		attribute.String("commit", args.Commit),
+		attribute.String("path", args.Path),
+		attribute.Int("numUploads", len(requestState.GetCacheUploads())),
+		attribute.String("uploads", uploadIDsToString(requestState.GetCacheUploads())),
+		attribute.Int("line", args.Line),
+		attribute.Int("character", args.Character),
+		attribute.String("direction", string(args.Direction)),
+		attribute.Int("depth", args.Depth),
+		attribute.Int("limit", args.Limit),
+	}})
+	defer endObservation()
+
+	visibleUploads, err := s.getVisibleUploads(ctx, args.Line, args.Character, requestState)
+	if err != nil {
+		return CallGraph{}, err
+	}
+
+	// Traverse the graph of the first upload with a function or method at the requested position
+	for i := range visibleUploads {
+		document, err := s.lsifstore.SCIPDocument(ctx, visibleUploads[i].Upload.ID, visibleUploads[i].TargetPathWithoutRoot)
+		if err != nil {
+			return CallGraph{}, err
+		}
+		if document == nil {
+			continue
+		}
+
+		position := visibleUploads[i].TargetPosition
+		for _, occurrence := range scip.FindOccurrences(document.Occurrences, int32(position.Line), int32(position.Character)) {
+			if !isCallableSymbol(occurrence.Symbol) {
+				continue
+			}
+			trace.AddEvent("TODO Domain Owner",
+				attribute.Int("uploadID", visibleUploads[i].Upload.ID),
+				attribute.String("symbol", occurrence.Symbol))
+
+			t := &callGraphTraversal{
+				service:      s,
+				args:         args,
+				requestState: requestState,
+				upload:       visibleUploads[i].Upload,
+				documents:    map[string]*callGraphDocument{},
+			}
+			t.documents[visibleUploads[i].TargetPathWithoutRoot] = newCallGraphDocument(document)
+
+			graph, err := t.traverse(ctx, occurrence.Symbol)
+			if err != nil {
+				return CallGraph{}, err
+			}
+			trace.AddEvent("TODO Domain Owner",
+				attribute.Int("numNodes", len(graph.Nodes)),
+				attribute.Int("numEdges", len(graph.Edges)),
+				attribute.Bool("limitHit", graph.LimitHit))
+
+			return graph, nil
+		}
+	}
+
+	return CallGraph{}, nil
+}
+
+// callGraphTraversal holds the state of a breadth-first traversal of the call graph of a single
+// upload. Documents are cached as they are read repeatedly while following calls.
+type callGraphTraversal struct {
+	service      *Service
+	args         CallGraphArgs
+	requestState RequestState
+	upload       uploadsshared.Dump
+	documents    map[string]*callGraphDocument
+}
+
+// call is the set of calls from one function to another.
+type call struct {
+	caller string
+	callee string
+	sites  []shared.Location
+}
+
+func (t *callGraphTraversal) traverse(ctx context.Context, symbol string) (graph CallGraph, _ error) {
+	root, err := t.node(ctx, symbol, 0)
+	if err != nil {
+		return CallGraph{}, err
+	}
+	graph.Nodes = append(graph.Nodes, root)
+
+	seen := map[string]struct{}{symbol: {}}
+	frontier := []string{symbol}
+
+	for depth := 1; depth <= t.args.Depth && len(frontier) > 0; depth++ {
+		var next []string
+		for _, symbol := range frontier {
+			calls, err := t.calls(ctx, symbol)
+			if err != nil {
+				return CallGraph{}, err
+			}
+
+			for _, c := range calls {
+				// 🚨 SECURITY: Call sites are filtered by sub-repo permissions. As every call site
+				// of an edge is within the body of the caller, edges are dropped entirely when the
+				// caller is not visible to the user, so that the traversal doesn't continue from it.
+				callSites, err := t.service.getUploadLocations(ctx, t.args.RequestArgs, t.requestState, c.sites, true)
+				if err != nil {
+					return CallGraph{}, err
+				}
+				if len(callSites) == 0 {
+					continue
+				}
+
+				if len(graph.Edges) >= t.args.Limit {
+					graph.LimitHit = true
+					return graph, nil
+				}
+				graph.Edges = append(graph.Edges, CallGraphEdge{
+					Caller:    c.caller,
+					Callee:    c.callee,
+					CallSites: callSites,
+				})
+
+				other := c.callee
+				if t.args.Direction == CallGraphCallers {
+					other = c.caller
+				}
+				if _, ok := seen[other]; ok {
+					continue
+				}
+				seen[other] = struct{}{}
+
+				node, err := t.node(ctx, other, depth)
+				if err != nil {
+					return CallGraph{}, err
+				}
+				graph.Nodes = append(graph.Nodes, node)
+				next = append(next, other)
+			}
+		}
+
+		frontier = next
+	}
+
+	return graph, nil
+}
+
+// calls returns the calls to or from the given symbol, depending on the requested direction.
+func (t *callGraphTraversal) calls(ctx context.Context, symbol string) ([]*call, error) {
+	if t.args.Direction == CallGraphCallers {
+		return t.callers(ctx, symbol)
+	}
+	return t.callees(ctx, symbol)
+}
+
+// callers returns the calls to the given symbol, grouped by the function containing them.
+func (t *callGraphTraversal) callers(ctx context.Context, symbol string) ([]*call, error) {
+	references, err := t.locations(ctx, "references", symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []*call
+	callsByCaller := map[string]*call{}
+	for _, reference := range references {
+		document, err := t.document(ctx, reference.Path)
+		if err != nil {
+			return nil, err
+		}
+		if document == nil {
+			continue
+		}
+
+		// References outside of a function body (e.g. in variable initializers) are not calls
+		body, ok := document.enclosingBody(reference.Range.Start)
+		if !ok {
+			continue
+		}
+
+		c, ok := callsByCaller[body.symbol]
+		if !ok {
+			c = &call{caller: body.symbol, callee: symbol}
+			callsByCaller[body.symbol] = c
+			calls = append(calls, c)
+		}
+		c.sites = append(c.sites, reference)
+	}
+
+	return calls, nil
+}
+
+// callees returns the calls within the body of the given symbol, grouped by the function called.
+func (t *callGraphTraversal) callees(ctx context.Context, symbol string) ([]*call, error) {
+	definitions, err := t.locations(ctx, "definitions", symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []*call
+	callsByCallee := map[string]*call{}
+	for _, definition := range definitions {
+		document, err := t.document(ctx, definition.Path)
+		if err != nil {
+			return nil, err
+		}
+		if document == nil {
+			continue
+		}
+
+		for _, occurrence := range document.occurrences {
+			if scip.SymbolRole_Definition.Matches(occurrence) || !isCallableSymbol(occurrence.Symbol) {
+				continue
+			}
+
+			// Calls within nested functions are attributed to the nested function
+			r := translateSCIPRange(occurrence.Range)
+			if body, ok := document.enclosingBody(r.Start); !ok || body.symbol != symbol || body.definition.Start != definition.Range.Start {
+				continue
+			}
+
+			c, ok := callsByCallee[occurrence.Symbol]
+			if !ok {
+				c = &call{caller: symbol, callee: occurrence.Symbol}
+				callsByCallee[occurrence.Symbol] = c
+				calls = append(calls, c)
+			}
+			c.sites = append(c.sites, shared.Location{DumpID: t.upload.ID, Path: definition.Path, Range: r})
+		}
+	}
+
+	return calls, nil
+}
+
+// node returns the node of the graph for the given symbol.
+func (t *callGraphTraversal) node(ctx context.Context, symbol string, depth int) (CallGraphNode, error) {
+	node := CallGraphNode{
+		Symbol:      symbol,
+		DisplayName: symbolDisplayName(symbol),
+		Depth:       depth,
+	}
+
+	definitions, err := t.locations(ctx, "definitions", symbol)
+	if err != nil {
+		return CallGraphNode{}, err
+	}
+	if len(definitions) == 0 {
+		return node, nil
+	}
+
+	uploadLocations, err := t.service.getUploadLocations(ctx, t.args.RequestArgs, t.requestState, definitions[:1], true)
+	if err != nil {
+		return CallGraphNode{}, err
+	}
+	if len(uploadLocations) > 0 {
+		node.Definition = &uploadLocations[0]
+	}
+
+	return node, nil
+}
+
+// locations returns the definitions or references of the given symbol within the upload.
+func (t *callGraphTraversal) locations(ctx context.Context, tableName, symbol string) ([]shared.Location, error) {
+	locations, _, err := t.service.lsifstore.GetBulkMonikerLocations(ctx, tableName, []int{t.upload.ID}, []precise.MonikerData{{Identifier: symbol}}, maxCallGraphLocations, 0)
+	return locations, err
+}
+
+// document returns the given document of the upload, or nil if it does not exist.
+func (t *callGraphTraversal) document(ctx context.Context, path string) (*callGraphDocument, error) {
+	if document, ok := t.documents[path]; ok {
+		return document, nil
+	}
+
+	scipDocument, err := t.service.lsifstore.SCIPDocument(ctx, t.upload.ID, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var document *callGraphDocument
+	if scipDocument != nil {
+		document = newCallGraphDocument(scipDocument)
+	}
+	t.documents[path] = document
+	return document, nil
+}
+
+// callGraphDocument is a SCIP document along with the bodies of the functions defined in it.
+type callGraphDocument struct {
+	occurrences []*scip.Occurrence
+	bodies      []functionBody
+}
+
+// functionBody is the span of a document holding the body of a function or method. SCIP does
+// not record the extent of definitions, so the body is approximated as the span between the
+// definition of the function and the next definition that isn't nested within it.
+type functionBody struct {
+	symbol     string
+	definition shared.Range
+	end        shared.Position // exclusive
+}
+
+func newCallGraphDocument(document *scip.Document) *callGraphDocument {
+	var definitions []*scip.Occurrence
+	for _, occurrence := range document.Occurrences {
+		if scip.SymbolRole_Definition.Matches(occurrence) && occurrence.Symbol != "" && !scip.IsLocalSymbol(occurrence.Symbol) {
+			definitions = append(definitions, occurrence)
+		}
+	}
+	sort.SliceStable(definitions, func(i, j int) bool {
+		return comparePositions(translateSCIPRange(definitions[i].Range).Start, translateSCIPRange(definitions[j].Range).Start) < 0
+	})
+
+	var bodies []functionBody
+	for i, definition := range definitions {
+		if !isCallableSymbol(definition.Symbol) {
+			continue
+		}
+
+		body := functionBody{
+			symbol:     definition.Symbol,
+			definition: translateSCIPRange(definition.Range),
+			end:        shared.Position{Line: math.MaxInt32},
+		}
+		for _, next := range definitions[i+1:] {
+			// Parameters and nested functions are defined with a symbol prefixed by the function's
+			if !strings.HasPrefix(next.Symbol, definition.Symbol) {
+				body.end = translateSCIPRange(next.Range).Start
+				break
+			}
+		}
+		bodies = append(bodies, body)
+	}
+
+	return &callGraphDocument{
+		occurrences: document.Occurrences,
+		bodies:      bodies,
+	}
+}
+
+// enclosingBody returns the innermost function body containing the given position.
+func (d *callGraphDocument) enclosingBody(position shared.Position) (functionBody, bool) {
+	// Bodies are ordered by their start, so the last one containing the position is the innermost
+	for i := len(d.bodies) - 1; i >= 0; i-- {
+		if comparePositions(d.bodies[i].definition.Start, position) <= 0 && comparePositions(position, d.bodies[i].end) < 0 {
+			return d.bodies[i], true
+		}
+	}
+
+	return functionBody{}, false
+}
+
+// isCallableSymbol returns true if the given symbol names a function or method.
+func isCallableSymbol(symbolName string) bool {
+	if symbolName == "" || scip.IsLocalSymbol(symbolName) {
+		return false
+	}
+
+	symbol, err := scip.ParseSymbol(symbolName)
+	if err != nil || len(symbol.Descriptors) == 0 {
+		return false
+	}
+
+	return symbol.Descriptors[len(symbol.Descriptors)-1].Suffix == scip.Descriptor_Method
+}
+
+// symbolDisplayName returns the name of the given symbol, qualified by the type it's a member of.
+func symbolDisplayName(symbolName string) string {
+	descriptor, parent, ok := parseSymbolDescriptors(symbolName)
+	if !ok {
+		return symbolName
+	}
+	if parent != nil && parent.Suffix == scip.Descriptor_Type {
+		return parent.Name + "." + descriptor.Name
+	}
+
+	return descriptor.Name
+}
+
+func translateSCIPRange(r []int32) shared.Range {
+	scipRange := scip.NewRange(r)
+
+	return shared.Range{
+		Start: shared.Position{Line: int(scipRange.Start.Line), Character: int(scipRange.Start.Character)},
+		End:   shared.Position{Line: int(scipRange.End.Line), Character: int(scipRange.End.Character)},
+	}
+}
+
+// comparePositions returns a negative number if a precedes b, zero if they're equal, and a
+// positive number otherwise.
+func comparePositions(a, b shared.Position) int {
+	if a.Line != b.Line {
+		return a.Line - b.Line
+	}
+
+	return a.Character - b.Character
+}
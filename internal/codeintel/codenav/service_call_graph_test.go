@@ -0,0 +1,196 @@
+package codenav
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	sgtypes "github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+func TestGetCallGraph(t *testing.T) {
+	const (
+		mainSymbol      = "scip-go gomod example.com/shapes v1.0.0 `example.com/shapes`/Main()."
+		areaSymbol      = "scip-go gomod example.com/shapes v1.0.0 `example.com/shapes`/Area()."
+		areaParamSymbol = "scip-go gomod example.com/shapes v1.0.0 `example.com/shapes`/Area().(s)"
+		perimeterSymbol = "scip-go gomod example.com/shapes v1.0.0 `example.com/shapes`/Perimeter()."
+		sqrtSymbol      = "scip-go gomod example.com/shapes v1.0.0 `example.com/shapes`/sqrt()."
+		shapeSymbol     = "scip-go gomod example.com/shapes v1.0.0 `example.com/shapes`/Shape#"
+	)
+
+	definition := int32(scip.SymbolRole_Definition)
+
+	//  0: func Main() {
+	//  1:   Area(s)
+	//  2:   Perimeter(s)
+	//  3:   Area(s)
+	//  4: }
+	//  5: func Area(s Shape) {
+	//  6:   sqrt(1)
+	//  7: }
+	//  8: type Shape struct{}
+	//  9: func Perimeter() {
+	// 10:   sqrt(2)
+	// 11: }
+	// 12: func sqrt() {}
+	document := &scip.Document{
+		RelativePath: "main.go",
+		Occurrences: []*scip.Occurrence{
+			{Range: []int32{0, 5, 9}, Symbol: mainSymbol, SymbolRoles: definition},
+			{Range: []int32{1, 1, 5}, Symbol: areaSymbol},
+			{Range: []int32{2, 1, 10}, Symbol: perimeterSymbol},
+			{Range: []int32{3, 1, 5}, Symbol: areaSymbol},
+			{Range: []int32{5, 5, 9}, Symbol: areaSymbol, SymbolRoles: definition},
+			{Range: []int32{5, 10, 11}, Symbol: areaParamSymbol, SymbolRoles: definition},
+			{Range: []int32{5, 12, 17}, Symbol: shapeSymbol},
+			{Range: []int32{6, 1, 5}, Symbol: sqrtSymbol},
+			{Range: []int32{8, 5, 10}, Symbol: shapeSymbol, SymbolRoles: definition},
+			{Range: []int32{9, 5, 14}, Symbol: perimeterSymbol, SymbolRoles: definition},
+			{Range: []int32{10, 1, 5}, Symbol: sqrtSymbol},
+			{Range: []int32{12, 5, 9}, Symbol: sqrtSymbol, SymbolRoles: definition},
+		},
+	}
+
+	// Set up mocks
+	mockRepoStore := defaultMockRepoStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := gitserver.NewMockClient()
+	hunkCache, _ := NewHunkCache(50)
+
+	mockLsifStore.SCIPDocumentFunc.SetDefaultHook(func(_ context.Context, uploadID int, path string) (*scip.Document, error) {
+		if uploadID == 50 && path == "main.go" {
+			return document, nil
+		}
+		return nil, nil
+	})
+	mockLsifStore.GetBulkMonikerLocationsFunc.SetDefaultHook(func(_ context.Context, tableName string, uploadIDs []int, monikers []precise.MonikerData, limit, offset int) (locations []shared.Location, _ int, _ error) {
+		for _, occurrence := range document.Occurrences {
+			if occurrence.Symbol != monikers[0].Identifier || scip.SymbolRole_Definition.Matches(occurrence) != (tableName == "definitions") {
+				continue
+			}
+			locations = append(locations, shared.Location{DumpID: 50, Path: "main.go", Range: translateSCIPRange(occurrence.Range)})
+		}
+		return locations, len(locations), nil
+	})
+
+	// Init service
+	svc := newService(&observation.TestContext, mockRepoStore, mockLsifStore, mockUploadSvc, mockGitserverClient)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockRepoStore, mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitserverClient, &sgtypes.Repo{ID: 42}, mockCommit, mockPath, hunkCache)
+	upload := uploadsshared.Dump{ID: 50, Commit: "deadbeef", Root: "s1/"}
+	mockRequestState.SetUploadsDataLoader([]uploadsshared.Dump{upload})
+
+	location := func(line, startCharacter, endCharacter int) shared.UploadLocation {
+		return shared.UploadLocation{
+			Dump:         upload,
+			Path:         "s1/main.go",
+			TargetCommit: "deadbeef",
+			TargetRange: shared.Range{
+				Start: shared.Position{Line: line, Character: startCharacter},
+				End:   shared.Position{Line: line, Character: endCharacter},
+			},
+		}
+	}
+	node := func(symbol, name string, definition shared.UploadLocation, depth int) CallGraphNode {
+		return CallGraphNode{Symbol: symbol, DisplayName: name, Definition: &definition, Depth: depth}
+	}
+	request := func(line, character int, direction CallGraphDirection, depth, limit int) CallGraphArgs {
+		return CallGraphArgs{
+			PositionalRequestArgs: PositionalRequestArgs{
+				RequestArgs: RequestArgs{
+					RepositoryID: 42,
+					Commit:       mockCommit,
+					Limit:        limit,
+				},
+				Path:      mockPath,
+				Line:      line,
+				Character: character,
+			},
+			Direction: direction,
+			Depth:     depth,
+		}
+	}
+
+	t.Run("callees", func(t *testing.T) {
+		graph, err := svc.GetCallGraph(context.Background(), request(0, 6, CallGraphCallees, 2, 10), mockRequestState)
+		if err != nil {
+			t.Fatalf("unexpected error getting call graph: %s", err)
+		}
+
+		expectedGraph := CallGraph{
+			Nodes: []CallGraphNode{
+				node(mainSymbol, "Main", location(0, 5, 9), 0),
+				node(areaSymbol, "Area", location(5, 5, 9), 1),
+				node(perimeterSymbol, "Perimeter", location(9, 5, 14), 1),
+				node(sqrtSymbol, "sqrt", location(12, 5, 9), 2),
+			},
+			Edges: []CallGraphEdge{
+				{Caller: mainSymbol, Callee: areaSymbol, CallSites: []shared.UploadLocation{location(1, 1, 5), location(3, 1, 5)}},
+				{Caller: mainSymbol, Callee: perimeterSymbol, CallSites: []shared.UploadLocation{location(2, 1, 10)}},
+				{Caller: areaSymbol, Callee: sqrtSymbol, CallSites: []shared.UploadLocation{location(6, 1, 5)}},
+				{Caller: perimeterSymbol, Callee: sqrtSymbol, CallSites: []shared.UploadLocation{location(10, 1, 5)}},
+			},
+		}
+		if diff := cmp.Diff(expectedGraph, graph); diff != "" {
+			t.Errorf("unexpected call graph (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("callers", func(t *testing.T) {
+		graph, err := svc.GetCallGraph(context.Background(), request(12, 6, CallGraphCallers, 2, 10), mockRequestState)
+		if err != nil {
+			t.Fatalf("unexpected error getting call graph: %s", err)
+		}
+
+		expectedGraph := CallGraph{
+			Nodes: []CallGraphNode{
+				node(sqrtSymbol, "sqrt", location(12, 5, 9), 0),
+				node(areaSymbol, "Area", location(5, 5, 9), 1),
+				node(perimeterSymbol, "Perimeter", location(9, 5, 14), 1),
+				node(mainSymbol, "Main", location(0, 5, 9), 2),
+			},
+			Edges: []CallGraphEdge{
+				{Caller: areaSymbol, Callee: sqrtSymbol, CallSites: []shared.UploadLocation{location(6, 1, 5)}},
+				{Caller: perimeterSymbol, Callee: sqrtSymbol, CallSites: []shared.UploadLocation{location(10, 1, 5)}},
+				{Caller: mainSymbol, Callee: areaSymbol, CallSites: []shared.UploadLocation{location(1, 1, 5), location(3, 1, 5)}},
+				{Caller: mainSymbol, Callee: perimeterSymbol, CallSites: []shared.UploadLocation{location(2, 1, 10)}},
+			},
+		}
+		if diff := cmp.Diff(expectedGraph, graph); diff != "" {
+			t.Errorf("unexpected call graph (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		graph, err := svc.GetCallGraph(context.Background(), request(0, 6, CallGraphCallees, 1, 1), mockRequestState)
+		if err != nil {
+			t.Fatalf("unexpected error getting call graph: %s", err)
+		}
+
+		if len(graph.Edges) != 1 || !graph.LimitHit {
+			t.Errorf("unexpected call graph. want 1 edge with limit hit, have %d edges with limitHit=%v", len(graph.Edges), graph.LimitHit)
+		}
+	})
+
+	t.Run("not a function", func(t *testing.T) {
+		graph, err := svc.GetCallGraph(context.Background(), request(8, 6, CallGraphCallers, 1, 10), mockRequestState)
+		if err != nil {
+			t.Fatalf("unexpected error getting call graph: %s", err)
+		}
+
+		if diff := cmp.Diff(CallGraph{}, graph); diff != "" {
+			t.Errorf("unexpected call graph (-want +got):\n%s", diff)
+		}
+	})
+}
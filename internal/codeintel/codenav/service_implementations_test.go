@@ -196,6 +196,7 @@ func TestImplementationsRemote(t *testing.T) {
 		{ID: 252, Commit: "deadbeef3", Root: "sub3/"},
 		{ID: 253, Commit: "deadbeef4", Root: "sub4/"},
 	}
+	mockUploadSvc.GetDumpsByIDsFunc.PushReturn(nil, nil) // empty
 	mockUploadSvc.GetDumpsByIDsFunc.PushReturn(referenceUploads[:2], nil)
 	mockUploadSvc.GetDumpsByIDsFunc.PushReturn(referenceUploads[2:], nil)
 
@@ -271,10 +272,151 @@ func TestImplementationsRemote(t *testing.T) {
 		{Dump: uploads[1], Path: "sub2/c.go", TargetCommit: "deadbeef", TargetRange: testRange5},
 		{Dump: uploads[3], Path: "sub4/a.go", TargetCommit: "deadbeef", TargetRange: testRange1},
 		{Dump: uploads[3], Path: "sub4/b.go", TargetCommit: "deadbeef", TargetRange: testRange2},
+		{Dump: uploads[3], Path: "sub4/a.go", TargetCommit: "deadbeef", TargetRange: testRange3},
+		{Dump: uploads[3], Path: "sub4/b.go", TargetCommit: "deadbeef", TargetRange: testRange4},
+		{Dump: uploads[3], Path: "sub4/c.go", TargetCommit: "deadbeef", TargetRange: testRange5},
 	}
 	if diff := cmp.Diff(expectedLocations, adjustedLocations); diff != "" {
 		t.Errorf("unexpected locations (-want +got):\n%s", diff)
 	}
+
+	if history := mockUploadSvc.GetDumpsWithDefinitionsForMonikersFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected call count for dbstore.DefinitionDump. want=%d have=%d", 1, len(history))
+	} else {
+		expectedMonikers := []precise.QualifiedMonikerData{
+			{MonikerData: monikers[1], PackageInformationData: packageInformation2},
+		}
+		if diff := cmp.Diff(expectedMonikers, history[0].Arg1); diff != "" {
+			t.Errorf("unexpected monikers (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestImplementationsInterleaved(t *testing.T) {
+	// Set up mocks
+	mockRepoStore := defaultMockRepoStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := gitserver.NewMockClient()
+	hunkCache, _ := NewHunkCache(50)
+
+	// Init service
+	svc := newService(&observation.TestContext, mockRepoStore, mockLsifStore, mockUploadSvc, mockGitserverClient)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockRepoStore, mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitserverClient, &sgtypes.Repo{ID: 42}, mockCommit, mockPath, hunkCache)
+	uploads := []uploadsshared.Dump{
+		{ID: 51, Commit: "deadbeef", Root: "sub2/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	// upload #250 belongs to a repository depending on the package defining the interface
+	dependentUpload := uploadsshared.Dump{ID: 250, RepositoryID: 50, Commit: "cafebabe", Root: "lib/"}
+	mockUploadSvc.GetUploadIDsWithReferencesFunc.SetDefaultHook(func(_ context.Context, _ []precise.QualifiedMonikerData, _ []int, _ int, _ string, _ int, offset int) ([]int, int, int, error) {
+		if offset == 0 {
+			return []int{dependentUpload.ID}, 1, 1, nil
+		}
+		return nil, 0, 0, nil
+	})
+	mockUploadSvc.GetDumpsByIDsFunc.SetDefaultHook(func(_ context.Context, ids []int) (dumps []uploadsshared.Dump, _ error) {
+		for _, id := range ids {
+			if id == dependentUpload.ID {
+				dumps = append(dumps, dependentUpload)
+			}
+		}
+		return dumps, nil
+	})
+	mockGitserverClient.CommitsExistFunc.SetDefaultHook(func(ctx context.Context, _ authz.SubRepoPermissionChecker, rcs []api.RepoCommit) (exists []bool, _ error) {
+		for range rcs {
+			exists = append(exists, true)
+		}
+		return
+	})
+
+	moniker := precise.MonikerData{Kind: "import", Scheme: "gomod", Identifier: "shapes:Shape", PackageInformationID: "51"}
+	mockLsifStore.GetMonikersByPositionFunc.SetDefaultReturn([][]precise.MonikerData{{moniker}}, nil)
+	mockLsifStore.GetPackageInformationFunc.SetDefaultReturn(precise.PackageInformationData{Name: "shapes", Version: "v1.0.0"}, true, nil)
+
+	localLocations := []shared.Location{
+		{DumpID: 51, Path: "a.go", Range: testRange1},
+		{DumpID: 51, Path: "b.go", Range: testRange2},
+		{DumpID: 51, Path: "a.go", Range: testRange3},
+		{DumpID: 51, Path: "b.go", Range: testRange4},
+		{DumpID: 51, Path: "c.go", Range: testRange5},
+	}
+	mockLsifStore.GetImplementationLocationsFunc.SetDefaultHook(func(_ context.Context, _ int, _ string, _, _, limit, offset int) ([]shared.Location, int, error) {
+		return paginateLocations(localLocations, limit, offset), len(localLocations), nil
+	})
+
+	remoteLocations := []shared.Location{
+		{DumpID: 250, Path: "circle.go", Range: testRange1},
+		{DumpID: 250, Path: "square.go", Range: testRange2},
+		{DumpID: 250, Path: "triangle.go", Range: testRange3},
+	}
+	mockLsifStore.GetBulkMonikerLocationsFunc.SetDefaultHook(func(_ context.Context, _ string, _ []int, _ []precise.MonikerData, limit, offset int) ([]shared.Location, int, error) {
+		return paginateLocations(remoteLocations, limit, offset), len(remoteLocations), nil
+	})
+
+	mockRequest := PositionalRequestArgs{
+		RequestArgs: RequestArgs{
+			RepositoryID: 42,
+			Commit:       mockCommit,
+			Limit:        4,
+		},
+		Path:      mockPath,
+		Line:      10,
+		Character: 20,
+	}
+
+	// Each page is split between local and remote locations while both remain
+	adjustedLocations, cursor, err := svc.GetImplementations(context.Background(), mockRequest, mockRequestState, ImplementationsCursor{Phase: "local"})
+	if err != nil {
+		t.Fatalf("unexpected error querying implementations: %s", err)
+	}
+
+	expectedLocations := []shared.UploadLocation{
+		{Dump: uploads[0], Path: "sub2/a.go", TargetCommit: "deadbeef", TargetRange: testRange1},
+		{Dump: uploads[0], Path: "sub2/b.go", TargetCommit: "deadbeef", TargetRange: testRange2},
+		{Dump: dependentUpload, Path: "lib/circle.go", TargetCommit: "cafebabe", TargetRange: testRange1},
+		{Dump: dependentUpload, Path: "lib/square.go", TargetCommit: "cafebabe", TargetRange: testRange2},
+	}
+	if diff := cmp.Diff(expectedLocations, adjustedLocations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+	if cursor.Phase != "local" || cursor.DependentsDone {
+		t.Errorf("unexpected cursor. want phase=local dependentsDone=false have phase=%s dependentsDone=%v", cursor.Phase, cursor.DependentsDone)
+	}
+
+	// Once the dependents are exhausted, local locations fill the rest of the page
+	adjustedLocations, cursor, err = svc.GetImplementations(context.Background(), mockRequest, mockRequestState, cursor)
+	if err != nil {
+		t.Fatalf("unexpected error querying implementations: %s", err)
+	}
+
+	expectedLocations = []shared.UploadLocation{
+		{Dump: uploads[0], Path: "sub2/a.go", TargetCommit: "deadbeef", TargetRange: testRange3},
+		{Dump: uploads[0], Path: "sub2/b.go", TargetCommit: "deadbeef", TargetRange: testRange4},
+		{Dump: dependentUpload, Path: "lib/triangle.go", TargetCommit: "cafebabe", TargetRange: testRange3},
+		{Dump: uploads[0], Path: "sub2/c.go", TargetCommit: "deadbeef", TargetRange: testRange5},
+	}
+	if diff := cmp.Diff(expectedLocations, adjustedLocations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+	if cursor.Phase != "done" {
+		t.Errorf("unexpected cursor phase. want=%s have=%s", "done", cursor.Phase)
+	}
+}
+
+func paginateLocations(locations []shared.Location, limit, offset int) []shared.Location {
+	if offset > len(locations) {
+		offset = len(locations)
+	}
+	if offset+limit < len(locations) {
+		return locations[offset : offset+limit]
+	}
+	return locations[offset:]
 }
 
 func TestImplementationsRemoteWithSubRepoPermissions(t *testing.T) {
@@ -327,6 +469,7 @@ func TestImplementationsRemoteWithSubRepoPermissions(t *testing.T) {
 		{ID: 252, Commit: "deadbeef3", Root: "sub3/"},
 		{ID: 253, Commit: "deadbeef4", Root: "sub4/"},
 	}
+	mockUploadSvc.GetDumpsByIDsFunc.PushReturn(nil, nil) // empty
 	mockUploadSvc.GetDumpsByIDsFunc.PushReturn(referenceUploads[:2], nil)
 	mockUploadSvc.GetDumpsByIDsFunc.PushReturn(referenceUploads[2:], nil)
 
@@ -399,6 +542,7 @@ func TestImplementationsRemoteWithSubRepoPermissions(t *testing.T) {
 		{Dump: uploads[1], Path: "sub2/a.go", TargetCommit: "deadbeef", TargetRange: testRange1},
 		{Dump: uploads[1], Path: "sub2/a.go", TargetCommit: "deadbeef", TargetRange: testRange3},
 		{Dump: uploads[3], Path: "sub4/a.go", TargetCommit: "deadbeef", TargetRange: testRange1},
+		{Dump: uploads[3], Path: "sub4/a.go", TargetCommit: "deadbeef", TargetRange: testRange3},
 	}
 	if diff := cmp.Diff(expectedLocations, adjustedLocations); diff != "" {
 		t.Errorf("unexpected locations (-want +got):\n%s", diff)
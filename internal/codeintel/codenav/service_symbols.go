@@ -0,0 +1,195 @@
+package codenav
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grafana/regexp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// SearchSymbols returns the definitions of the symbols whose names match the given pattern
+// from the precise indexes uploaded for exactly the given commit of the given repository. The
+// returned flag is false if there is no such index, in which case callers should fall back to
+// imprecise symbol data.
+func (s *Service) SearchSymbols(ctx context.Context, repositoryID int, commit string, pattern *regexp.Regexp, limit int) (symbols []PreciseSymbol, _ bool, err error) {
+	ctx, trace, endObservation := s.operations.searchSymbols.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("repositoryID", repositoryID),
+		attribute.String("commit", commit),
+		attribute.String("pattern", pattern.String()),
+		attribute.Int("limit", limit),
+	}})
+	defer func() {
+		endObservation(1, observation.Args{Attrs: []attribute.KeyValue{
+			attribute.Int("numSymbols", len(symbols)),
+		}})
+	}()
+
+	candidates, err := s.uploadSvc.InferClosestUploads(ctx, repositoryID, commit, "", false, "")
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Only indexes of the requested commit are considered: the ranges of an index for a
+	// nearby commit may not match the requested file contents.
+	dumps := make([]uploadsshared.Dump, 0, len(candidates))
+	for _, dump := range candidates {
+		if dump.Commit == commit {
+			dumps = append(dumps, dump)
+		}
+	}
+	trace.AddEvent("TODO Domain Owner",
+		attribute.Int("numCandidates", len(candidates)),
+		attribute.String("dumps", uploadIDsToString(dumps)))
+	if len(dumps) == 0 {
+		return nil, false, nil
+	}
+
+	filter := func(symbolName string) bool {
+		descriptor, _, ok := parseSymbolDescriptors(symbolName)
+		return ok && pattern.MatchString(descriptor.Name)
+	}
+
+	for _, dump := range dumps {
+		if len(symbols) >= limit {
+			break
+		}
+
+		definitions, err := s.lsifstore.GetSymbolDefinitions(ctx, dump.ID, filter, limit-len(symbols))
+		if err != nil {
+			return nil, false, err
+		}
+
+		signatures, err := s.getSignatures(ctx, dump.ID, definitions)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, definition := range definitions {
+			descriptor, parent, ok := parseSymbolDescriptors(definition.Symbol)
+			if !ok {
+				continue
+			}
+
+			symbol := PreciseSymbol{
+				Name:      descriptor.Name,
+				Kind:      descriptorKind(descriptor, parent),
+				Signature: signatures[definition.Symbol],
+				Path:      dump.Root + definition.Path,
+				Range:     definition.Range,
+			}
+			if parent != nil {
+				symbol.ContainerName = parent.Name
+				symbol.ContainerKind = descriptorKind(parent, nil)
+			}
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	return symbols, true, nil
+}
+
+// getSignatures returns the signatures of the symbols of the given definitions, read from the
+// documentation attached to each symbol in the SCIP document that defines it.
+func (s *Service) getSignatures(ctx context.Context, uploadID int, definitions []shared.SymbolDefinition) (map[string]string, error) {
+	signatures := map[string]string{}
+	seenPaths := map[string]struct{}{}
+
+	for _, definition := range definitions {
+		if _, ok := seenPaths[definition.Path]; ok {
+			continue
+		}
+		seenPaths[definition.Path] = struct{}{}
+
+		document, err := s.lsifstore.SCIPDocument(ctx, uploadID, definition.Path)
+		if err != nil {
+			return nil, err
+		}
+		if document == nil {
+			continue
+		}
+
+		for _, info := range document.Symbols {
+			if len(info.Documentation) > 0 {
+				signatures[info.Symbol] = signatureFromDocumentation(info.Documentation[0])
+			}
+		}
+	}
+
+	return signatures, nil
+}
+
+// signatureFromDocumentation returns the contents of the code block that indexers emit as the
+// first documentation entry of a symbol.
+func signatureFromDocumentation(documentation string) string {
+	if !strings.HasPrefix(documentation, "```") {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimSpace(documentation), "\n")
+	code := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "```") {
+			code = append(code, strings.TrimSpace(line))
+		}
+	}
+	return strings.Join(code, " ")
+}
+
+// parseSymbolDescriptors returns the descriptor naming the given symbol as well as the
+// descriptor of its container, if any. The returned flag is false for local symbols and for
+// symbols that are not useful as symbol search results (e.g. parameters).
+func parseSymbolDescriptors(symbolName string) (descriptor, parent *scip.Descriptor, _ bool) {
+	if strings.HasPrefix(symbolName, "local ") {
+		return nil, nil, false
+	}
+
+	symbol, err := scip.ParseSymbol(symbolName)
+	if err != nil || len(symbol.Descriptors) == 0 {
+		return nil, nil, false
+	}
+
+	descriptor = symbol.Descriptors[len(symbol.Descriptors)-1]
+	if len(symbol.Descriptors) > 1 {
+		parent = symbol.Descriptors[len(symbol.Descriptors)-2]
+	}
+	if descriptor.Name == "" || descriptorKind(descriptor, parent) == "" {
+		return nil, nil, false
+	}
+
+	return descriptor, parent, true
+}
+
+// descriptorKind returns the symbol kind of the given descriptor. The kinds match those
+// reported by ctags so that clients do not need to distinguish between the two sources.
+func descriptorKind(descriptor, parent *scip.Descriptor) string {
+	memberOfType := parent != nil && parent.Suffix == scip.Descriptor_Type
+
+	switch descriptor.Suffix {
+	case scip.Descriptor_Namespace:
+		return "namespace"
+	case scip.Descriptor_Type:
+		return "type"
+	case scip.Descriptor_Method:
+		if memberOfType {
+			return "method"
+		}
+		return "function"
+	case scip.Descriptor_Term:
+		if memberOfType {
+			return "field"
+		}
+		return "variable"
+	case scip.Descriptor_TypeParameter:
+		return "type parameter"
+	case scip.Descriptor_Macro:
+		return "macro"
+	}
+
+	return ""
+}
@@ -0,0 +1,110 @@
+package codenav
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/regexp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestSearchSymbols(t *testing.T) {
+	const (
+		bananaSymbol = "scip-go gomod github.com/sourcegraph/banter v4.2.0 `github.com/sourcegraph/banter/food`/Banana#"
+		peelSymbol   = "scip-go gomod github.com/sourcegraph/banter v4.2.0 `github.com/sourcegraph/banter/food`/Banana#Peel()."
+		bakeSymbol   = "scip-go gomod github.com/sourcegraph/banter v4.2.0 `github.com/sourcegraph/banter/food`/BakeBanana()."
+		localSymbol  = "local 1"
+	)
+
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	svc := newService(&observation.TestContext, defaultMockRepoStore(), mockLsifStore, mockUploadSvc, gitserver.NewMockClient())
+
+	mockUploadSvc.InferClosestUploadsFunc.SetDefaultReturn([]uploadsshared.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "sub/"},
+		{ID: 51, Commit: "cafebabe", Root: "sub/"},
+	}, nil)
+	mockLsifStore.GetSymbolDefinitionsFunc.SetDefaultHook(func(_ context.Context, uploadID int, filter func(string) bool, limit int) ([]shared.SymbolDefinition, error) {
+		if uploadID != 50 {
+			t.Fatalf("unexpected upload %d", uploadID)
+		}
+
+		var definitions []shared.SymbolDefinition
+		for i, symbol := range []string{bananaSymbol, peelSymbol, bakeSymbol, localSymbol} {
+			if filter(symbol) && len(definitions) < limit {
+				definitions = append(definitions, shared.SymbolDefinition{
+					DumpID: uploadID,
+					Symbol: symbol,
+					Path:   "food/banana.go",
+					Range:  shared.Range{Start: shared.Position{Line: i, Character: 5}, End: shared.Position{Line: i, Character: 11}},
+				})
+			}
+		}
+		return definitions, nil
+	})
+	mockLsifStore.SCIPDocumentFunc.SetDefaultReturn(&scip.Document{
+		RelativePath: "food/banana.go",
+		Symbols: []*scip.SymbolInformation{
+			{Symbol: bananaSymbol, Documentation: []string{"```go\ntype Banana struct\n```", "Banana is a fruit."}},
+			{Symbol: peelSymbol, Documentation: []string{"```go\nfunc (Banana) Peel() error\n```"}},
+		},
+	}, nil)
+
+	symbols, ok, err := svc.SearchSymbols(context.Background(), 42, "deadbeef", regexp.MustCompile("(?i)banana"), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !ok {
+		t.Fatalf("expected precise index to be available")
+	}
+
+	expected := []PreciseSymbol{
+		{
+			Name:          "Banana",
+			Kind:          "type",
+			ContainerName: "github.com/sourcegraph/banter/food",
+			ContainerKind: "namespace",
+			Signature:     "type Banana struct",
+			Path:          "sub/food/banana.go",
+			Range:         shared.Range{Start: shared.Position{Line: 0, Character: 5}, End: shared.Position{Line: 0, Character: 11}},
+		},
+		{
+			Name:          "BakeBanana",
+			Kind:          "function",
+			ContainerName: "github.com/sourcegraph/banter/food",
+			ContainerKind: "namespace",
+			Path:          "sub/food/banana.go",
+			Range:         shared.Range{Start: shared.Position{Line: 2, Character: 5}, End: shared.Position{Line: 2, Character: 11}},
+		},
+	}
+	if diff := cmp.Diff(expected, symbols); diff != "" {
+		t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+	}
+
+	_, ok, err = svc.SearchSymbols(context.Background(), 42, "f00dface", regexp.MustCompile("Banana"), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ok {
+		t.Errorf("expected no precise index for an unindexed commit")
+	}
+}
+
+func TestSignatureFromDocumentation(t *testing.T) {
+	testCases := map[string]string{
+		"```go\nfunc Peel() error\n```":                         "func Peel() error",
+		"```ts\nfunction peel(\n  banana: Banana\n): void\n```": "function peel( banana: Banana ): void",
+		"Peel removes the skin.":                                "",
+	}
+	for documentation, expected := range testCases {
+		if signature := signatureFromDocumentation(documentation); signature != expected {
+			t.Errorf("unexpected signature for %q. want=%q have=%q", documentation, expected, signature)
+		}
+	}
+}
@@ -44,6 +44,15 @@ type SnapshotData struct {
 	AdditionalData []string
 }
 
+// SymbolDefinition is the location of the definition of a SCIP symbol within a
+// particular dump.
+type SymbolDefinition struct {
+	DumpID int
+	Symbol string
+	Path   string
+	Range  Range
+}
+
 type Range struct {
 	Start Position
 	End   Position
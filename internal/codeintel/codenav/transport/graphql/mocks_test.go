@@ -178,6 +178,9 @@ func (c AutoIndexingServiceQueueRepoRevFuncCall) Results() []interface{} {
 // github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/transport/graphql)
 // used for unit testing.
 type MockCodeNavService struct {
+	// GetCallGraphFunc is an instance of a mock function object controlling
+	// the behavior of the method GetCallGraph.
+	GetCallGraphFunc *CodeNavServiceGetCallGraphFunc
 	// GetClosestDumpsForBlobFunc is an instance of a mock function object
 	// controlling the behavior of the method GetClosestDumpsForBlob.
 	GetClosestDumpsForBlobFunc *CodeNavServiceGetClosestDumpsForBlobFunc
@@ -217,6 +220,11 @@ type MockCodeNavService struct {
 // All methods return zero values for all results, unless overwritten.
 func NewMockCodeNavService() *MockCodeNavService {
 	return &MockCodeNavService{
+		GetCallGraphFunc: &CodeNavServiceGetCallGraphFunc{
+			defaultHook: func(context.Context, codenav.CallGraphArgs, codenav.RequestState) (r0 codenav.CallGraph, r1 error) {
+				return
+			},
+		},
 		GetClosestDumpsForBlobFunc: &CodeNavServiceGetClosestDumpsForBlobFunc{
 			defaultHook: func(context.Context, int, string, string, bool, string) (r0 []shared.Dump, r1 error) {
 				return
@@ -279,6 +287,11 @@ func NewMockCodeNavService() *MockCodeNavService {
 // interface. All methods panic on invocation, unless overwritten.
 func NewStrictMockCodeNavService() *MockCodeNavService {
 	return &MockCodeNavService{
+		GetCallGraphFunc: &CodeNavServiceGetCallGraphFunc{
+			defaultHook: func(context.Context, codenav.CallGraphArgs, codenav.RequestState) (codenav.CallGraph, error) {
+				panic("unexpected invocation of MockCodeNavService.GetCallGraph")
+			},
+		},
 		GetClosestDumpsForBlobFunc: &CodeNavServiceGetClosestDumpsForBlobFunc{
 			defaultHook: func(context.Context, int, string, string, bool, string) ([]shared.Dump, error) {
 				panic("unexpected invocation of MockCodeNavService.GetClosestDumpsForBlob")
@@ -342,6 +355,9 @@ func NewStrictMockCodeNavService() *MockCodeNavService {
 // overwritten.
 func NewMockCodeNavServiceFrom(i CodeNavService) *MockCodeNavService {
 	return &MockCodeNavService{
+		GetCallGraphFunc: &CodeNavServiceGetCallGraphFunc{
+			defaultHook: i.GetCallGraph,
+		},
 		GetClosestDumpsForBlobFunc: &CodeNavServiceGetClosestDumpsForBlobFunc{
 			defaultHook: i.GetClosestDumpsForBlob,
 		},
@@ -378,6 +394,118 @@ func NewMockCodeNavServiceFrom(i CodeNavService) *MockCodeNavService {
 	}
 }
 
+// CodeNavServiceGetCallGraphFunc describes the behavior when the
+// GetCallGraph method of the parent MockCodeNavService instance is
+// invoked.
+type CodeNavServiceGetCallGraphFunc struct {
+	defaultHook func(context.Context, codenav.CallGraphArgs, codenav.RequestState) (codenav.CallGraph, error)
+	hooks       []func(context.Context, codenav.CallGraphArgs, codenav.RequestState) (codenav.CallGraph, error)
+	history     []CodeNavServiceGetCallGraphFuncCall
+	mutex       sync.Mutex
+}
+
+// GetCallGraph delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetCallGraph(v0 context.Context, v1 codenav.CallGraphArgs, v2 codenav.RequestState) (codenav.CallGraph, error) {
+	r0, r1 := m.GetCallGraphFunc.nextHook()(v0, v1, v2)
+	m.GetCallGraphFunc.appendCall(CodeNavServiceGetCallGraphFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetCallGraph
+// method of the parent MockCodeNavService instance is invoked and the hook
+// queue is empty.
+func (f *CodeNavServiceGetCallGraphFunc) SetDefaultHook(hook func(context.Context, codenav.CallGraphArgs, codenav.RequestState) (codenav.CallGraph, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetCallGraph method of the parent MockCodeNavService instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetCallGraphFunc) PushHook(hook func(context.Context, codenav.CallGraphArgs, codenav.RequestState) (codenav.CallGraph, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetCallGraphFunc) SetDefaultReturn(r0 codenav.CallGraph, r1 error) {
+	f.SetDefaultHook(func(context.Context, codenav.CallGraphArgs, codenav.RequestState) (codenav.CallGraph, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetCallGraphFunc) PushReturn(r0 codenav.CallGraph, r1 error) {
+	f.PushHook(func(context.Context, codenav.CallGraphArgs, codenav.RequestState) (codenav.CallGraph, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeNavServiceGetCallGraphFunc) nextHook() func(context.Context, codenav.CallGraphArgs, codenav.RequestState) (codenav.CallGraph, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetCallGraphFunc) appendCall(r0 CodeNavServiceGetCallGraphFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetCallGraphFuncCall
+// objects describing the invocations of this function.
+func (f *CodeNavServiceGetCallGraphFunc) History() []CodeNavServiceGetCallGraphFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetCallGraphFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetCallGraphFuncCall is an object that describes an
+// invocation of method GetCallGraph on an instance of MockCodeNavService.
+type CodeNavServiceGetCallGraphFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 codenav.CallGraphArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 codenav.CallGraph
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetCallGraphFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetCallGraphFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // CodeNavServiceGetClosestDumpsForBlobFunc describes the behavior when the
 // GetClosestDumpsForBlob method of the parent MockCodeNavService instance
 // is invoked.
@@ -0,0 +1,129 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav"
+	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/shared/resolvers/gitresolvers"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// DefaultCallGraphLimit is the maximum number of edges of a call graph when no limit is supplied.
+const DefaultCallGraphLimit = 100
+
+// ErrIllegalDepth occurs when the user requests a call graph deeper than supported.
+var ErrIllegalDepth = errors.Newf("illegal depth: must be between 1 and %d", codenav.MaxCallGraphDepth)
+
+func (r *gitBlobLSIFDataResolver) CallGraph(ctx context.Context, args *resolverstubs.LSIFCallGraphArgs) (_ resolverstubs.CallGraphResolver, err error) {
+	limit := int(pointers.Deref(args.First, DefaultCallGraphLimit))
+	if limit <= 0 {
+		return nil, ErrIllegalLimit
+	}
+
+	depth := int(pointers.Deref(args.Depth, 1))
+	if depth <= 0 || depth > codenav.MaxCallGraphDepth {
+		return nil, ErrIllegalDepth
+	}
+
+	var direction codenav.CallGraphDirection
+	switch args.Direction {
+	case "CALLERS":
+		direction = codenav.CallGraphCallers
+	case "CALLEES":
+		direction = codenav.CallGraphCallees
+	default:
+		return nil, errors.Newf("unknown call graph direction %q", args.Direction)
+	}
+
+	requestArgs := codenav.CallGraphArgs{
+		PositionalRequestArgs: codenav.PositionalRequestArgs{
+			RequestArgs: codenav.RequestArgs{
+				RepositoryID: r.requestState.RepositoryID,
+				Commit:       r.requestState.Commit,
+				Limit:        limit,
+			},
+			Path:      r.requestState.Path,
+			Line:      int(args.Line),
+			Character: int(args.Character),
+		},
+		Direction: direction,
+		Depth:     depth,
+	}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.callGraph, time.Second, getObservationArgs(requestArgs.PositionalRequestArgs))
+	defer endObservation()
+
+	graph, err := r.codeNavSvc.GetCallGraph(ctx, requestArgs, r.requestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetCallGraph")
+	}
+
+	return &callGraphResolver{graph: graph, locationResolver: r.locationResolver}, nil
+}
+
+//
+//
+
+type callGraphResolver struct {
+	graph            codenav.CallGraph
+	locationResolver *gitresolvers.CachedLocationResolver
+}
+
+func (r *callGraphResolver) Nodes() []resolverstubs.CallGraphNodeResolver {
+	resolvers := make([]resolverstubs.CallGraphNodeResolver, 0, len(r.graph.Nodes))
+	for _, node := range r.graph.Nodes {
+		resolvers = append(resolvers, &callGraphNodeResolver{node: node, locationResolver: r.locationResolver})
+	}
+
+	return resolvers
+}
+
+func (r *callGraphResolver) Edges() []resolverstubs.CallGraphEdgeResolver {
+	resolvers := make([]resolverstubs.CallGraphEdgeResolver, 0, len(r.graph.Edges))
+	for _, edge := range r.graph.Edges {
+		resolvers = append(resolvers, &callGraphEdgeResolver{edge: edge, locationResolver: r.locationResolver})
+	}
+
+	return resolvers
+}
+
+func (r *callGraphResolver) LimitHit() bool {
+	return r.graph.LimitHit
+}
+
+//
+//
+
+type callGraphNodeResolver struct {
+	node             codenav.CallGraphNode
+	locationResolver *gitresolvers.CachedLocationResolver
+}
+
+func (r *callGraphNodeResolver) Symbol() string      { return r.node.Symbol }
+func (r *callGraphNodeResolver) DisplayName() string { return r.node.DisplayName }
+func (r *callGraphNodeResolver) Depth() int32        { return int32(r.node.Depth) }
+
+func (r *callGraphNodeResolver) Definition(ctx context.Context) (resolverstubs.LocationResolver, error) {
+	if r.node.Definition == nil {
+		return nil, nil
+	}
+
+	return resolveLocation(ctx, r.locationResolver, *r.node.Definition)
+}
+
+//
+//
+
+type callGraphEdgeResolver struct {
+	edge             codenav.CallGraphEdge
+	locationResolver *gitresolvers.CachedLocationResolver
+}
+
+func (r *callGraphEdgeResolver) Caller() string { return r.edge.Caller }
+func (r *callGraphEdgeResolver) Callee() string { return r.edge.Callee }
+
+func (r *callGraphEdgeResolver) CallSites(ctx context.Context) ([]resolverstubs.LocationResolver, error) {
+	return resolveLocations(ctx, r.locationResolver, r.edge.CallSites)
+}
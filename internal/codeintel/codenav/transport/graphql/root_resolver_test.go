@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	mockrequire "github.com/derision-test/go-mockgen/testutil/require"
+	"github.com/google/go-cmp/cmp"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav"
@@ -217,6 +218,91 @@ func TestReferencesDefaultIllegalLimit(t *testing.T) {
 	}
 }
 
+func TestCallGraph(t *testing.T) {
+	mockCodeNavService := NewMockCodeNavService()
+	mockRequestState := codenav.RequestState{
+		RepositoryID: 1,
+		Commit:       "deadbeef1",
+		Path:         "/src/main",
+	}
+	mockOperations := newOperations(&observation.TestContext)
+
+	resolver := newGitBlobLSIFDataResolver(
+		mockCodeNavService,
+		nil,
+		mockRequestState,
+		nil,
+		nil,
+		nil,
+		mockOperations,
+	)
+
+	depth := int32(3)
+	args := &resolverstubs.LSIFCallGraphArgs{
+		Line:      10,
+		Character: 15,
+		Direction: "CALLERS",
+		Depth:     &depth,
+	}
+
+	if _, err := resolver.CallGraph(context.Background(), args); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(mockCodeNavService.GetCallGraphFunc.History()) != 1 {
+		t.Fatalf("unexpected call count. want=%d have=%d", 1, len(mockCodeNavService.GetCallGraphFunc.History()))
+	}
+	expectedArgs := codenav.CallGraphArgs{
+		PositionalRequestArgs: codenav.PositionalRequestArgs{
+			RequestArgs: codenav.RequestArgs{
+				RepositoryID: 1,
+				Commit:       "deadbeef1",
+				Limit:        DefaultCallGraphLimit,
+			},
+			Path:      "/src/main",
+			Line:      10,
+			Character: 15,
+		},
+		Direction: codenav.CallGraphCallers,
+		Depth:     3,
+	}
+	if diff := cmp.Diff(expectedArgs, mockCodeNavService.GetCallGraphFunc.History()[0].Arg1); diff != "" {
+		t.Errorf("unexpected args (-want +got):\n%s", diff)
+	}
+}
+
+func TestCallGraphIllegalDepth(t *testing.T) {
+	mockCodeNavService := NewMockCodeNavService()
+	mockRequestState := codenav.RequestState{
+		RepositoryID: 1,
+		Commit:       "deadbeef1",
+		Path:         "/src/main",
+	}
+	mockOperations := newOperations(&observation.TestContext)
+
+	resolver := newGitBlobLSIFDataResolver(
+		mockCodeNavService,
+		nil,
+		mockRequestState,
+		nil,
+		nil,
+		nil,
+		mockOperations,
+	)
+
+	depth := int32(codenav.MaxCallGraphDepth + 1)
+	args := &resolverstubs.LSIFCallGraphArgs{
+		Line:      10,
+		Character: 15,
+		Direction: "CALLEES",
+		Depth:     &depth,
+	}
+
+	if _, err := resolver.CallGraph(context.Background(), args); err != ErrIllegalDepth {
+		t.Fatalf("unexpected error. want=%q have=%q", ErrIllegalDepth, err)
+	}
+}
+
 func TestHover(t *testing.T) {
 	mockCodeNavService := NewMockCodeNavService()
 	mockRequestState := codenav.RequestState{
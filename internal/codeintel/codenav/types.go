@@ -172,7 +172,9 @@ type ImplementationsCursor struct {
 	CursorsToVisibleUploads       []CursorToVisibleUpload        `json:"visibleUploads"`
 	OrderedImplementationMonikers []precise.QualifiedMonikerData `json:"orderedImplementationMonikers"`
 	OrderedExportMonikers         []precise.QualifiedMonikerData `json:"orderedExportMonikers"`
+	OrderedRemoteMonikers         []precise.QualifiedMonikerData `json:"orderedRemoteMonikers"`
 	Phase                         string                         `json:"phase"`
+	DependentsDone                bool                           `json:"dependentsDone"`
 	LocalCursor                   LocalCursor                    `json:"localCursor"`
 	RemoteCursor                  RemoteCursor                   `json:"remoteCursor"`
 }
@@ -199,3 +201,54 @@ type RemoteCursor struct {
 	// The location offset within the associated batch of uploads.
 	LocationOffset int `json:"locationOffset"`
 }
+
+// PreciseSymbol is a symbol definition read from a precise code intelligence index.
+type PreciseSymbol struct {
+	Name          string
+	Kind          string
+	ContainerName string
+	ContainerKind string
+	Signature     string
+	Path          string
+	Range         shared.Range
+}
+
+// CallGraphDirection determines whether a call graph is made of the callers or the callees of
+// the requested symbol.
+type CallGraphDirection string
+
+const (
+	CallGraphCallers CallGraphDirection = "callers"
+	CallGraphCallees CallGraphDirection = "callees"
+)
+
+// CallGraphArgs are the arguments of a call graph request. The limit of the request args is
+// the maximum number of edges in the graph.
+type CallGraphArgs struct {
+	PositionalRequestArgs
+	Direction CallGraphDirection
+	Depth     int
+}
+
+// CallGraph is the graph of calls to or from a function or method.
+type CallGraph struct {
+	Nodes    []CallGraphNode
+	Edges    []CallGraphEdge
+	LimitHit bool
+}
+
+// CallGraphNode is a function or method of a call graph. The depth is the number of calls
+// between the requested symbol and this one.
+type CallGraphNode struct {
+	Symbol      string
+	DisplayName string
+	Definition  *shared.UploadLocation
+	Depth       int
+}
+
+// CallGraphEdge is the set of calls from one function or method to another.
+type CallGraphEdge struct {
+	Caller    string
+	Callee    string
+	CallSites []shared.UploadLocation
+}
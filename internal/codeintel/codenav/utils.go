@@ -51,6 +51,17 @@ func isSourceLocation(visibleUploads []visibleUpload, location shared.Location)
 	return false
 }
 
+// isVisibleUpload returns true if the upload with the given identifier is one of the visible uploads.
+func isVisibleUpload(visibleUploads []visibleUpload, uploadID int) bool {
+	for i := range visibleUploads {
+		if visibleUploads[i].Upload.ID == uploadID {
+			return true
+		}
+	}
+
+	return false
+}
+
 // rangeContainsPosition returns true if the given range encloses the given position.
 func rangeContainsPosition(r shared.Range, pos shared.Position) bool {
 	if pos.Line < r.Start.Line {
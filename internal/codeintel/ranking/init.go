@@ -7,6 +7,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/background/janitor"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/background/mapper"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/background/reducer"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/background/signals"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/lsifstore"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/store"
 	codeintelshared "github.com/sourcegraph/sourcegraph/internal/codeintel/shared"
@@ -14,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
 )
 
 func NewService(
@@ -35,6 +37,7 @@ var (
 	MapperConfigInst      = &mapper.Config{}
 	ReducerConfigInst     = &reducer.Config{}
 	JanitorConfigInst     = &janitor.Config{}
+	SignalsConfigInst     = &signals.Config{}
 )
 
 func NewSymbolExporter(observationCtx *observation.Context, rankingService *Service) goroutine.BackgroundRoutine {
@@ -78,6 +81,15 @@ func NewSymbolJanitor(observationCtx *observation.Context, rankingService *Servi
 	)
 }
 
+func NewSignalExporter(observationCtx *observation.Context, rankingService *Service, uploadStore uploadstore.Store) goroutine.BackgroundRoutine {
+	return background.NewSignalExporter(
+		scopedContext("signals", observationCtx),
+		rankingService.store,
+		uploadStore,
+		SignalsConfigInst,
+	)
+}
+
 func scopedContext(component string, observationCtx *observation.Context) *observation.Context {
 	return observation.ScopedContext("codeintel", "ranking", component, observationCtx)
 }
@@ -6,10 +6,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/background/janitor"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/background/mapper"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/background/reducer"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/background/signals"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/lsifstore"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/store"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
 )
 
 func NewSymbolExporter(observationCtx *observation.Context, store store.Store, lsifstore lsifstore.Store, config *exporter.Config) goroutine.BackgroundRoutine {
@@ -42,3 +44,7 @@ func NewSymbolJanitor(observationCtx *observation.Context, store store.Store, co
 		janitor.NewRankJanitor(observationCtx, store, config),
 	}
 }
+
+func NewSignalExporter(observationCtx *observation.Context, store store.Store, uploadStore uploadstore.Store, config *signals.Config) goroutine.BackgroundRoutine {
+	return signals.NewSignalExporter(observationCtx, store, uploadStore, config)
+}
@@ -0,0 +1,26 @@
+package signals
+
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+// MaxSymbolsPerRepository is the maximum number of symbol records exported for a single
+// repository. This limit applies both to the periodic export written to the upload store
+// and to the on-demand export served by the frontend.
+var MaxSymbolsPerRepository = env.MustGetInt("CODEINTEL_RANKING_SIGNAL_EXPORTER_MAX_SYMBOLS_PER_REPOSITORY", 10000, "The maximum number of symbol records to export for a single repository.")
+
+type Config struct {
+	env.BaseConfig
+
+	Interval  time.Duration
+	ObjectKey string
+	BatchSize int
+}
+
+func (c *Config) Load() {
+	c.Interval = c.GetInterval("CODEINTEL_RANKING_SIGNAL_EXPORTER_INTERVAL", "24h", "How frequently to export ranking signals to the upload store.")
+	c.ObjectKey = c.Get("CODEINTEL_RANKING_SIGNAL_EXPORTER_OBJECT_KEY", "ranking-signals/latest.jsonl", "The key of the upload store object containing the most recent ranking signal export.")
+	c.BatchSize = c.GetInt("CODEINTEL_RANKING_SIGNAL_EXPORTER_BATCH_SIZE", "100", "The number of repositories whose symbol reference counts are computed at once.")
+}
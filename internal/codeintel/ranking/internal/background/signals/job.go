@@ -0,0 +1,160 @@
+package signals
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	rankingshared "github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/shared"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/store"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/shared"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/shared/background"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+)
+
+func NewSignalExporter(
+	observationCtx *observation.Context,
+	store store.Store,
+	uploadStore uploadstore.Store,
+	config *Config,
+) goroutine.BackgroundRoutine {
+	name := "codeintel.ranking.signal-exporter"
+
+	return background.NewPipelineJob(context.Background(), background.PipelineOptions{
+		Name:        name,
+		Description: "Exports document and symbol reference counts as JSONL to the upload store.",
+		Interval:    config.Interval,
+		Metrics:     background.NewPipelineMetrics(observationCtx, name),
+		ProcessFunc: func(ctx context.Context) (numRecordsProcessed int, numRecordsAltered background.TaggedCounts, err error) {
+			numRepositories, numSignals, err := exportRankingSignals(ctx, store, uploadStore, config.ObjectKey, config.BatchSize, MaxSymbolsPerRepository)
+			return numRepositories, background.NewSingleCount(numSignals), err
+		},
+	})
+}
+
+func exportRankingSignals(
+	ctx context.Context,
+	s store.Store,
+	uploadStore uploadstore.Store,
+	objectKey string,
+	batchSize int,
+	maxSymbolsPerRepository int,
+) (numRepositories, numSignals int, err error) {
+	if enabled := conf.CodeIntelRankingDocumentReferenceCountsEnabled(); !enabled {
+		return 0, 0, nil
+	}
+
+	repoNames, err := s.GetRepositoriesWithRanks(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(repoNames) == 0 {
+		return 0, 0, nil
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		var err error
+		defer func() { pw.CloseWithError(err) }()
+
+		for _, batch := range batchRepoNames(repoNames, batchSize) {
+			var n int
+			n, err = writeRankingSignals(ctx, s, batch, maxSymbolsPerRepository, pw)
+			if err != nil {
+				return
+			}
+
+			// assignment to outer scope
+			numSignals += n
+		}
+	}()
+
+	if _, err := uploadStore.Upload(ctx, objectKey, pr); err != nil {
+		// Unblock the writer goroutine if the upload failed early
+		_ = pr.CloseWithError(err)
+		return 0, 0, err
+	}
+
+	return len(repoNames), numSignals, nil
+}
+
+// WriteRankingSignals writes the document and symbol reference counts of the given repository
+// to w as a stream of newline-delimited JSON objects. At most maxSymbols symbol records (the
+// most referenced ones) are written. The number of records written is returned.
+func WriteRankingSignals(ctx context.Context, s store.Store, repoName api.RepoName, maxSymbols int, w io.Writer) (int, error) {
+	return writeRankingSignals(ctx, s, []api.RepoName{repoName}, maxSymbols, w)
+}
+
+// writeRankingSignals writes the ranking signals of each of the given repositories, in order.
+// The symbol reference counts of all repositories are fetched with a single query so that the
+// references of the ranking graph are scanned once per batch rather than once per repository.
+func writeRankingSignals(ctx context.Context, s store.Store, repoNames []api.RepoName, maxSymbols int, w io.Writer) (int, error) {
+	symbolCounts, err := s.GetSymbolReferenceCounts(ctx, rankingshared.GraphKey(), repoNames, maxSymbols)
+	if err != nil {
+		return 0, err
+	}
+
+	encoder := json.NewEncoder(w)
+
+	numSignals := 0
+	for _, repoName := range repoNames {
+		documentRanks, _, err := s.GetDocumentRanks(ctx, repoName)
+		if err != nil {
+			return 0, err
+		}
+
+		paths := make([]string, 0, len(documentRanks))
+		for path := range documentRanks {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			if err := encoder.Encode(shared.RankingSignal{
+				Type:           shared.RankingSignalTypeDocument,
+				Repository:     string(repoName),
+				Path:           path,
+				ReferenceCount: documentRanks[path],
+			}); err != nil {
+				return 0, err
+			}
+		}
+
+		for _, symbolCount := range symbolCounts[repoName] {
+			if err := encoder.Encode(shared.RankingSignal{
+				Type:           shared.RankingSignalTypeSymbol,
+				Repository:     string(repoName),
+				Path:           symbolCount.DocumentPath,
+				SymbolChecksum: symbolCount.SymbolChecksum,
+				ReferenceCount: float64(symbolCount.ReferenceCount),
+			}); err != nil {
+				return 0, err
+			}
+		}
+
+		numSignals += len(documentRanks) + len(symbolCounts[repoName])
+	}
+
+	return numSignals, nil
+}
+
+func batchRepoNames(repoNames []api.RepoName, batchSize int) (batches [][]api.RepoName) {
+	if batchSize <= 0 {
+		batchSize = len(repoNames)
+	}
+
+	for len(repoNames) > batchSize {
+		batches = append(batches, repoNames[:batchSize])
+		repoNames = repoNames[batchSize:]
+	}
+	if len(repoNames) > 0 {
+		batches = append(batches, repoNames)
+	}
+
+	return batches
+}
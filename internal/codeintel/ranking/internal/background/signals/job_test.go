@@ -0,0 +1,195 @@
+package signals
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/shared"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	uploadstoremocks "github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestWriteRankingSignals(t *testing.T) {
+	mockStore := newMockRankingStore()
+
+	var buf bytes.Buffer
+	n, err := writeRankingSignals(context.Background(), mockStore, []api.RepoName{"foo", "bar"}, 10, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error writing ranking signals: %s", err)
+	}
+	if n != 5 {
+		t.Errorf("unexpected number of signals. want=%d have=%d", 5, n)
+	}
+
+	expected := strings.Join([]string{
+		`{"type":"document","repository":"foo","path":"a.go","referenceCount":3}`,
+		`{"type":"document","repository":"foo","path":"b.go","referenceCount":1}`,
+		`{"type":"symbol","repository":"foo","path":"a.go","symbolChecksum":"c0ffee","referenceCount":2}`,
+		`{"type":"document","repository":"bar","path":"c.go","referenceCount":4}`,
+		`{"type":"symbol","repository":"bar","path":"c.go","symbolChecksum":"decade","referenceCount":4}`,
+	}, "\n") + "\n"
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+
+	// Symbol counts are fetched for the entire batch at once
+	if history := mockStore.GetSymbolReferenceCountsFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of GetSymbolReferenceCounts calls. want=%d have=%d", 1, len(history))
+	} else {
+		if diff := cmp.Diff([]api.RepoName{"foo", "bar"}, history[0].Arg2); diff != "" {
+			t.Errorf("unexpected repository names (-want +got):\n%s", diff)
+		}
+		if history[0].Arg3 != 10 {
+			t.Errorf("unexpected limit. want=%d have=%d", 10, history[0].Arg3)
+		}
+	}
+}
+
+func TestExportRankingSignals(t *testing.T) {
+	mockRankingEnabled(t)
+	mockStore := newMockRankingStore()
+	mockStore.GetRepositoriesWithRanksFunc.SetDefaultReturn([]api.RepoName{"foo", "bar", "baz"}, nil)
+	mockUploadStore := uploadstoremocks.NewMockStore()
+
+	var buf bytes.Buffer
+	mockUploadStore.UploadFunc.SetDefaultHook(func(ctx context.Context, key string, r io.Reader) (int64, error) {
+		if key != "ranking-signals/test.jsonl" {
+			t.Errorf("unexpected object key %q", key)
+		}
+		return io.Copy(&buf, r)
+	})
+
+	numRepositories, numSignals, err := exportRankingSignals(context.Background(), mockStore, mockUploadStore, "ranking-signals/test.jsonl", 2, 10)
+	if err != nil {
+		t.Fatalf("unexpected error exporting ranking signals: %s", err)
+	}
+	if numRepositories != 3 {
+		t.Errorf("unexpected number of repositories. want=%d have=%d", 3, numRepositories)
+	}
+	if numSignals != 5 {
+		t.Errorf("unexpected number of signals. want=%d have=%d", 5, numSignals)
+	}
+	if numLines := strings.Count(buf.String(), "\n"); numLines != numSignals {
+		t.Errorf("unexpected number of uploaded lines. want=%d have=%d", numSignals, numLines)
+	}
+
+	var batches [][]api.RepoName
+	for _, call := range mockStore.GetSymbolReferenceCountsFunc.History() {
+		batches = append(batches, call.Arg2)
+	}
+	if diff := cmp.Diff([][]api.RepoName{{"foo", "bar"}, {"baz"}}, batches); diff != "" {
+		t.Errorf("unexpected batches (-want +got):\n%s", diff)
+	}
+}
+
+func TestExportRankingSignalsDisabled(t *testing.T) {
+	conf.Mock(&conf.Unified{})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	mockStore := newMockRankingStore()
+	mockUploadStore := uploadstoremocks.NewMockStore()
+
+	if _, _, err := exportRankingSignals(context.Background(), mockStore, mockUploadStore, "ranking-signals/test.jsonl", 2, 10); err != nil {
+		t.Fatalf("unexpected error exporting ranking signals: %s", err)
+	}
+	if len(mockStore.GetRepositoriesWithRanksFunc.History()) != 0 {
+		t.Errorf("unexpected call to GetRepositoriesWithRanks")
+	}
+	if len(mockUploadStore.UploadFunc.History()) != 0 {
+		t.Errorf("unexpected call to Upload")
+	}
+}
+
+func TestExportRankingSignalsUploadError(t *testing.T) {
+	mockRankingEnabled(t)
+	mockStore := newMockRankingStore()
+	mockStore.GetRepositoriesWithRanksFunc.SetDefaultReturn([]api.RepoName{"foo", "bar"}, nil)
+	mockUploadStore := uploadstoremocks.NewMockStore()
+
+	// Fail without consuming the reader; the writer goroutine must not block forever
+	mockUploadStore.UploadFunc.SetDefaultReturn(0, errors.New("bucket unavailable"))
+
+	if _, _, err := exportRankingSignals(context.Background(), mockStore, mockUploadStore, "ranking-signals/test.jsonl", 1, 10); err == nil || !strings.Contains(err.Error(), "bucket unavailable") {
+		t.Fatalf("unexpected error. want=%q have=%v", "bucket unavailable", err)
+	}
+}
+
+func TestExportRankingSignalsWriteError(t *testing.T) {
+	mockRankingEnabled(t)
+	mockStore := newMockRankingStore()
+	mockStore.GetRepositoriesWithRanksFunc.SetDefaultReturn([]api.RepoName{"foo", "bar"}, nil)
+	mockStore.GetSymbolReferenceCountsFunc.SetDefaultReturn(nil, errors.New("database unavailable"))
+	mockUploadStore := uploadstoremocks.NewMockStore()
+	mockUploadStore.UploadFunc.SetDefaultHook(func(ctx context.Context, key string, r io.Reader) (int64, error) {
+		return io.Copy(io.Discard, r)
+	})
+
+	if _, _, err := exportRankingSignals(context.Background(), mockStore, mockUploadStore, "ranking-signals/test.jsonl", 1, 10); err == nil || !strings.Contains(err.Error(), "database unavailable") {
+		t.Fatalf("unexpected error. want=%q have=%v", "database unavailable", err)
+	}
+}
+
+func TestBatchRepoNames(t *testing.T) {
+	repoNames := []api.RepoName{"a", "b", "c", "d", "e"}
+
+	testCases := []struct {
+		batchSize int
+		expected  [][]api.RepoName
+	}{
+		{batchSize: 2, expected: [][]api.RepoName{{"a", "b"}, {"c", "d"}, {"e"}}},
+		{batchSize: 5, expected: [][]api.RepoName{{"a", "b", "c", "d", "e"}}},
+		{batchSize: 0, expected: [][]api.RepoName{{"a", "b", "c", "d", "e"}}},
+	}
+
+	for _, testCase := range testCases {
+		if diff := cmp.Diff(testCase.expected, batchRepoNames(repoNames, testCase.batchSize)); diff != "" {
+			t.Errorf("unexpected batches for batch size %d (-want +got):\n%s", testCase.batchSize, diff)
+		}
+	}
+}
+
+func newMockRankingStore() *MockStore {
+	documentRanks := map[api.RepoName]map[string]float64{
+		"foo": {"b.go": 1, "a.go": 3},
+		"bar": {"c.go": 4},
+	}
+	symbolCounts := map[api.RepoName][]shared.SymbolReferenceCount{
+		"foo": {{DocumentPath: "a.go", SymbolChecksum: "c0ffee", ReferenceCount: 2}},
+		"bar": {{DocumentPath: "c.go", SymbolChecksum: "decade", ReferenceCount: 4}},
+	}
+
+	mockStore := NewMockStore()
+	mockStore.GetDocumentRanksFunc.SetDefaultHook(func(ctx context.Context, repoName api.RepoName) (map[string]float64, bool, error) {
+		ranks, ok := documentRanks[repoName]
+		return ranks, ok, nil
+	})
+	mockStore.GetSymbolReferenceCountsFunc.SetDefaultHook(func(ctx context.Context, graphKey string, repoNames []api.RepoName, limit int) (map[api.RepoName][]shared.SymbolReferenceCount, error) {
+		counts := map[api.RepoName][]shared.SymbolReferenceCount{}
+		for _, repoName := range repoNames {
+			if c, ok := symbolCounts[repoName]; ok {
+				counts[repoName] = c
+			}
+		}
+		return counts, nil
+	})
+
+	return mockStore
+}
+
+func mockRankingEnabled(t *testing.T) {
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			CodeIntelRankingDocumentReferenceCountsEnabled: pointers.Ptr(true),
+		},
+	})
+	t.Cleanup(func() { conf.Mock(nil) })
+}
@@ -14,6 +14,8 @@ type operations struct {
 	getReferenceCountStatistics    *observation.Operation
 	coverageCounts                 *observation.Operation
 	lastUpdatedAt                  *observation.Operation
+	getRepositoriesWithRanks       *observation.Operation
+	getSymbolReferenceCounts       *observation.Operation
 	getUploadsForRanking           *observation.Operation
 	vacuumAbandonedExportedUploads *observation.Operation
 	softDeleteStaleExportedUploads *observation.Operation
@@ -61,6 +63,8 @@ func newOperations(observationCtx *observation.Context) *operations {
 		getReferenceCountStatistics:    op("GetReferenceCountStatistics"),
 		coverageCounts:                 op("CoverageCounts"),
 		lastUpdatedAt:                  op("LastUpdatedAt"),
+		getRepositoriesWithRanks:       op("GetRepositoriesWithRanks"),
+		getSymbolReferenceCounts:       op("GetSymbolReferenceCounts"),
 		getUploadsForRanking:           op("GetUploadsForRanking"),
 		vacuumAbandonedExportedUploads: op("VacuumAbandonedExportedUploads"),
 		softDeleteStaleExportedUploads: op("SoftDeleteStaleExportedUploads"),
@@ -2,11 +2,13 @@ package store
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"time"
 
 	"github.com/keegancsmith/sqlf"
 	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/shared"
@@ -218,3 +220,130 @@ var scanLastUpdatedAtPairs = basestore.NewMapScanner(func(s dbutil.Scanner) (rep
 	err := s.Scan(&repoID, &t)
 	return repoID, t, err
 })
+
+func (s *store) GetRepositoriesWithRanks(ctx context.Context) (_ []api.RepoName, err error) {
+	ctx, _, endObservation := s.operations.getRepositoriesWithRanks.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	names, err := basestore.ScanStrings(s.db.Query(ctx, sqlf.Sprintf(getRepositoriesWithRanksQuery)))
+	if err != nil {
+		return nil, err
+	}
+
+	repoNames := make([]api.RepoName, 0, len(names))
+	for _, name := range names {
+		repoNames = append(repoNames, api.RepoName(name))
+	}
+
+	return repoNames, nil
+}
+
+const getRepositoriesWithRanksQuery = `
+WITH
+last_completed_progress AS (
+	SELECT crp.graph_key
+	FROM codeintel_ranking_progress crp
+	WHERE crp.reducer_completed_at IS NOT NULL
+	ORDER BY crp.reducer_completed_at DESC
+	LIMIT 1
+)
+SELECT r.name
+FROM codeintel_path_ranks pr
+JOIN repo r ON r.id = pr.repository_id
+WHERE
+	pr.graph_key IN (SELECT graph_key FROM last_completed_progress) AND
+	r.deleted_at IS NULL AND
+	r.blocked IS NULL
+ORDER BY r.name
+`
+
+func (s *store) GetSymbolReferenceCounts(ctx context.Context, graphKey string, repoNames []api.RepoName, limit int) (_ map[api.RepoName][]shared.SymbolReferenceCount, err error) {
+	ctx, _, endObservation := s.operations.getSymbolReferenceCounts.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("graphKey", graphKey),
+		attribute.Int("numRepoNames", len(repoNames)),
+		attribute.Int("limit", limit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if len(repoNames) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(repoNames))
+	for _, repoName := range repoNames {
+		names = append(names, string(repoName))
+	}
+
+	return scanSymbolReferenceCounts(s.db.Query(ctx, sqlf.Sprintf(
+		getSymbolReferenceCountsQuery,
+		graphKey,
+		pq.Array(names),
+		graphKey,
+		limit,
+	)))
+}
+
+// getSymbolReferenceCountsQuery counts the references to the symbols defined in a batch
+// of repositories. The references of the graph are scanned once for the entire batch
+// rather than once per repository; the most referenced symbols of each repository are
+// then selected independently.
+const getSymbolReferenceCountsQuery = `
+WITH
+definitions AS (
+	SELECT
+		r.name AS repository_name,
+		rd.symbol_checksum,
+		rd.document_path
+	FROM codeintel_ranking_definitions rd
+	JOIN codeintel_ranking_exports re ON re.id = rd.exported_upload_id
+	JOIN lsif_uploads u ON u.id = re.upload_id
+	JOIN repo r ON r.id = u.repository_id
+	WHERE
+		rd.graph_key = %s AND
+		re.deleted_at IS NULL AND
+		r.name = ANY(%s) AND
+		r.deleted_at IS NULL AND
+		r.blocked IS NULL
+),
+reference_counts AS (
+	SELECT
+		checksum,
+		COUNT(*) AS count
+	FROM codeintel_ranking_references rr
+	JOIN codeintel_ranking_exports re ON re.id = rr.exported_upload_id
+	CROSS JOIN LATERAL unnest(rr.symbol_checksums) AS checksum
+	WHERE
+		rr.graph_key = %s AND
+		re.deleted_at IS NULL AND
+		checksum IN (SELECT symbol_checksum FROM definitions)
+	GROUP BY checksum
+),
+ranked_definitions AS (
+	SELECT
+		d.repository_name,
+		d.document_path,
+		d.symbol_checksum,
+		COALESCE(rc.count, 0) AS count,
+		ROW_NUMBER() OVER (
+			PARTITION BY d.repository_name
+			ORDER BY COALESCE(rc.count, 0) DESC, d.document_path, d.symbol_checksum
+		) AS rank
+	FROM definitions d
+	LEFT JOIN reference_counts rc ON rc.checksum = d.symbol_checksum
+)
+SELECT
+	rd.repository_name,
+	rd.document_path,
+	rd.symbol_checksum,
+	rd.count
+FROM ranked_definitions rd
+WHERE rd.rank <= %s
+ORDER BY rd.repository_name, rd.rank
+`
+
+var scanSymbolReferenceCounts = basestore.NewMapSliceScanner(func(s dbutil.Scanner) (repoName api.RepoName, c shared.SymbolReferenceCount, _ error) {
+	var checksum []byte
+	err := s.Scan(&repoName, &c.DocumentPath, &checksum, &c.ReferenceCount)
+	c.SymbolChecksum = hex.EncodeToString(checksum)
+	return repoName, c, err
+})
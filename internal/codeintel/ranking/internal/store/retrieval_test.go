@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"encoding/hex"
 	"math"
 	"testing"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	rankingshared "github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/shared"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/shared"
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
@@ -122,6 +124,154 @@ func TestDocumentRanks(t *testing.T) {
 	}
 }
 
+func TestGetRepositoriesWithRanks(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	ctx := context.Background()
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	store := New(&observation.TestContext, db)
+
+	key := rankingshared.NewDerivativeGraphKey(mockRankingGraphKey, "123")
+	staleKey := rankingshared.NewDerivativeGraphKey(mockRankingGraphKey, "122")
+
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO codeintel_ranking_progress(graph_key, max_export_id, mappers_started_at, reducer_completed_at)
+		VALUES
+			($1, 1000, NOW() - '1 day'::interval, NOW() - '1 day'::interval),
+			($2, 1000, NOW(), NOW())
+	`,
+		staleKey,
+		key,
+	); err != nil {
+		t.Fatalf("failed to insert metadata: %s", err)
+	}
+
+	insertRepo(t, db, 50, "foo")
+	insertRepo(t, db, 51, "bar")
+	insertRepo(t, db, 52, "DELETED-baz")
+	insertRepo(t, db, 53, "bonk")
+
+	for repoName, graphKey := range map[api.RepoName]string{
+		"foo":         key,
+		"bar":         key,
+		"DELETED-baz": key,      // deleted repository
+		"bonk":        staleKey, // only ranked by a previous graph
+	} {
+		if err := setDocumentRanks(ctx, basestore.NewWithHandle(db.Handle()), repoName, map[string]float64{"main.go": 1}, graphKey); err != nil {
+			t.Fatalf("unexpected error setting document ranks: %s", err)
+		}
+	}
+
+	repoNames, err := store.GetRepositoriesWithRanks(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error getting repositories with ranks: %s", err)
+	}
+	if diff := cmp.Diff([]api.RepoName{"bar", "foo"}, repoNames); diff != "" {
+		t.Errorf("unexpected repositories (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetSymbolReferenceCounts(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	ctx := context.Background()
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	store := New(&observation.TestContext, db)
+
+	insertUploads(t, db,
+		uploadsshared.Upload{ID: 1, RepositoryID: 50, RepositoryName: "foo"},
+		uploadsshared.Upload{ID: 2, RepositoryID: 51, RepositoryName: "bar"},
+		uploadsshared.Upload{ID: 3, RepositoryID: 52, RepositoryName: "baz"},
+	)
+
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO codeintel_ranking_exports (id, upload_id, graph_key, upload_key)
+		VALUES
+			(101, 1, $1, md5('key-1')),
+			(102, 2, $1, md5('key-2')),
+			(103, 3, $1, md5('key-3'))
+	`,
+		mockRankingGraphKey,
+	); err != nil {
+		t.Fatalf("unexpected error inserting exported upload record: %s", err)
+	}
+
+	mockDefinitions := make(chan shared.RankingDefinitions, 4)
+	mockDefinitions <- shared.RankingDefinitions{UploadID: 1, ExportedUploadID: 101, SymbolChecksum: hash("a"), DocumentPath: "a.go"}
+	mockDefinitions <- shared.RankingDefinitions{UploadID: 1, ExportedUploadID: 101, SymbolChecksum: hash("b"), DocumentPath: "b.go"}
+	mockDefinitions <- shared.RankingDefinitions{UploadID: 2, ExportedUploadID: 102, SymbolChecksum: hash("c"), DocumentPath: "c.go"}
+	mockDefinitions <- shared.RankingDefinitions{UploadID: 3, ExportedUploadID: 103, SymbolChecksum: hash("d"), DocumentPath: "d.go"}
+	close(mockDefinitions)
+	if err := store.InsertDefinitionsForRanking(ctx, mockRankingGraphKey, mockDefinitions); err != nil {
+		t.Fatalf("unexpected error inserting definitions: %s", err)
+	}
+
+	for exportedUploadID, checksums := range map[int][][16]byte{
+		102: {hash("a")},
+		103: {hash("a"), hash("b"), hash("c")},
+	} {
+		mockReferences := make(chan [16]byte, len(checksums))
+		for _, checksum := range checksums {
+			mockReferences <- checksum
+		}
+		close(mockReferences)
+		if err := store.InsertReferencesForRanking(ctx, mockRankingGraphKey, mockRankingBatchSize, exportedUploadID, mockReferences); err != nil {
+			t.Fatalf("unexpected error inserting references: %s", err)
+		}
+	}
+
+	symbolCount := func(path, symbolName string, count int) shared.SymbolReferenceCount {
+		checksum := hash(symbolName)
+		return shared.SymbolReferenceCount{DocumentPath: path, SymbolChecksum: hex.EncodeToString(checksum[:]), ReferenceCount: count}
+	}
+
+	testCases := []struct {
+		repoNames []api.RepoName
+		limit     int
+		expected  map[api.RepoName][]shared.SymbolReferenceCount
+	}{
+		{
+			repoNames: []api.RepoName{"foo", "bar"},
+			limit:     10,
+			expected: map[api.RepoName][]shared.SymbolReferenceCount{
+				"foo": {symbolCount("a.go", "a", 2), symbolCount("b.go", "b", 1)},
+				"bar": {symbolCount("c.go", "c", 1)},
+			},
+		},
+		{
+			// limit applies to each repository independently
+			repoNames: []api.RepoName{"foo", "bar", "baz"},
+			limit:     1,
+			expected: map[api.RepoName][]shared.SymbolReferenceCount{
+				"foo": {symbolCount("a.go", "a", 2)},
+				"bar": {symbolCount("c.go", "c", 1)},
+				"baz": {symbolCount("d.go", "d", 0)},
+			},
+		},
+		{
+			repoNames: []api.RepoName{"quux"},
+			limit:     10,
+			expected:  map[api.RepoName][]shared.SymbolReferenceCount{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		symbolCounts, err := store.GetSymbolReferenceCounts(ctx, mockRankingGraphKey, testCase.repoNames, testCase.limit)
+		if err != nil {
+			t.Fatalf("unexpected error getting symbol reference counts: %s", err)
+		}
+		if diff := cmp.Diff(testCase.expected, symbolCounts); diff != "" {
+			t.Errorf("unexpected symbol reference counts for %v (-want +got):\n%s", testCase.repoNames, diff)
+		}
+	}
+}
+
 func TestGetReferenceCountStatistics(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -26,6 +26,8 @@ type Store interface {
 	GetReferenceCountStatistics(ctx context.Context) (logmean float64, _ error)
 	CoverageCounts(ctx context.Context, graphKey string) (_ shared.CoverageCounts, err error)
 	LastUpdatedAt(ctx context.Context, repoIDs []api.RepoID) (map[api.RepoID]time.Time, error)
+	GetRepositoriesWithRanks(ctx context.Context) ([]api.RepoName, error)
+	GetSymbolReferenceCounts(ctx context.Context, graphKey string, repoNames []api.RepoName, limit int) (map[api.RepoName][]shared.SymbolReferenceCount, error)
 
 	// Export uploads (metadata tracking) + cleanup
 	GetUploadsForRanking(ctx context.Context, graphKey, objectPrefix string, batchSize int) ([]uploadsshared.ExportedUpload, error)
@@ -46,9 +46,15 @@ type MockStore struct {
 	// object controlling the behavior of the method
 	// GetReferenceCountStatistics.
 	GetReferenceCountStatisticsFunc *StoreGetReferenceCountStatisticsFunc
+	// GetRepositoriesWithRanksFunc is an instance of a mock function object
+	// controlling the behavior of the method GetRepositoriesWithRanks.
+	GetRepositoriesWithRanksFunc *StoreGetRepositoriesWithRanksFunc
 	// GetStarRankFunc is an instance of a mock function object controlling
 	// the behavior of the method GetStarRank.
 	GetStarRankFunc *StoreGetStarRankFunc
+	// GetSymbolReferenceCountsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetSymbolReferenceCounts.
+	GetSymbolReferenceCountsFunc *StoreGetSymbolReferenceCountsFunc
 	// GetUploadsForRankingFunc is an instance of a mock function object
 	// controlling the behavior of the method GetUploadsForRanking.
 	GetUploadsForRankingFunc *StoreGetUploadsForRankingFunc
@@ -148,11 +154,21 @@ func NewMockStore() *MockStore {
 				return
 			},
 		},
+		GetRepositoriesWithRanksFunc: &StoreGetRepositoriesWithRanksFunc{
+			defaultHook: func(context.Context) (r0 []api.RepoName, r1 error) {
+				return
+			},
+		},
 		GetStarRankFunc: &StoreGetStarRankFunc{
 			defaultHook: func(context.Context, api.RepoName) (r0 float64, r1 error) {
 				return
 			},
 		},
+		GetSymbolReferenceCountsFunc: &StoreGetSymbolReferenceCountsFunc{
+			defaultHook: func(context.Context, string, []api.RepoName, int) (r0 map[api.RepoName][]shared.SymbolReferenceCount, r1 error) {
+				return
+			},
+		},
 		GetUploadsForRankingFunc: &StoreGetUploadsForRankingFunc{
 			defaultHook: func(context.Context, string, string, int) (r0 []shared1.ExportedUpload, r1 error) {
 				return
@@ -280,11 +296,21 @@ func NewStrictMockStore() *MockStore {
 				panic("unexpected invocation of MockStore.GetReferenceCountStatistics")
 			},
 		},
+		GetRepositoriesWithRanksFunc: &StoreGetRepositoriesWithRanksFunc{
+			defaultHook: func(context.Context) ([]api.RepoName, error) {
+				panic("unexpected invocation of MockStore.GetRepositoriesWithRanks")
+			},
+		},
 		GetStarRankFunc: &StoreGetStarRankFunc{
 			defaultHook: func(context.Context, api.RepoName) (float64, error) {
 				panic("unexpected invocation of MockStore.GetStarRank")
 			},
 		},
+		GetSymbolReferenceCountsFunc: &StoreGetSymbolReferenceCountsFunc{
+			defaultHook: func(context.Context, string, []api.RepoName, int) (map[api.RepoName][]shared.SymbolReferenceCount, error) {
+				panic("unexpected invocation of MockStore.GetSymbolReferenceCounts")
+			},
+		},
 		GetUploadsForRankingFunc: &StoreGetUploadsForRankingFunc{
 			defaultHook: func(context.Context, string, string, int) ([]shared1.ExportedUpload, error) {
 				panic("unexpected invocation of MockStore.GetUploadsForRanking")
@@ -398,9 +424,15 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		GetReferenceCountStatisticsFunc: &StoreGetReferenceCountStatisticsFunc{
 			defaultHook: i.GetReferenceCountStatistics,
 		},
+		GetRepositoriesWithRanksFunc: &StoreGetRepositoriesWithRanksFunc{
+			defaultHook: i.GetRepositoriesWithRanks,
+		},
 		GetStarRankFunc: &StoreGetStarRankFunc{
 			defaultHook: i.GetStarRank,
 		},
+		GetSymbolReferenceCountsFunc: &StoreGetSymbolReferenceCountsFunc{
+			defaultHook: i.GetSymbolReferenceCounts,
+		},
 		GetUploadsForRankingFunc: &StoreGetUploadsForRankingFunc{
 			defaultHook: i.GetUploadsForRanking,
 		},
@@ -1205,6 +1237,113 @@ func (c StoreGetReferenceCountStatisticsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// StoreGetRepositoriesWithRanksFunc describes the behavior when the
+// GetRepositoriesWithRanks method of the parent MockStore instance is
+// invoked.
+type StoreGetRepositoriesWithRanksFunc struct {
+	defaultHook func(context.Context) ([]api.RepoName, error)
+	hooks       []func(context.Context) ([]api.RepoName, error)
+	history     []StoreGetRepositoriesWithRanksFuncCall
+	mutex       sync.Mutex
+}
+
+// GetRepositoriesWithRanks delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockStore) GetRepositoriesWithRanks(v0 context.Context) ([]api.RepoName, error) {
+	r0, r1 := m.GetRepositoriesWithRanksFunc.nextHook()(v0)
+	m.GetRepositoriesWithRanksFunc.appendCall(StoreGetRepositoriesWithRanksFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// GetRepositoriesWithRanks method of the parent MockStore instance is
+// invoked and the hook queue is empty.
+func (f *StoreGetRepositoriesWithRanksFunc) SetDefaultHook(hook func(context.Context) ([]api.RepoName, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetRepositoriesWithRanks method of the parent MockStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *StoreGetRepositoriesWithRanksFunc) PushHook(hook func(context.Context) ([]api.RepoName, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetRepositoriesWithRanksFunc) SetDefaultReturn(r0 []api.RepoName, r1 error) {
+	f.SetDefaultHook(func(context.Context) ([]api.RepoName, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetRepositoriesWithRanksFunc) PushReturn(r0 []api.RepoName, r1 error) {
+	f.PushHook(func(context.Context) ([]api.RepoName, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreGetRepositoriesWithRanksFunc) nextHook() func(context.Context) ([]api.RepoName, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetRepositoriesWithRanksFunc) appendCall(r0 StoreGetRepositoriesWithRanksFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreGetRepositoriesWithRanksFuncCall
+// objects describing the invocations of this function.
+func (f *StoreGetRepositoriesWithRanksFunc) History() []StoreGetRepositoriesWithRanksFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetRepositoriesWithRanksFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetRepositoriesWithRanksFuncCall is an object that describes an
+// invocation of method GetRepositoriesWithRanks on an instance of
+// MockStore.
+type StoreGetRepositoriesWithRanksFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []api.RepoName
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetRepositoriesWithRanksFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetRepositoriesWithRanksFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // StoreGetStarRankFunc describes the behavior when the GetStarRank method
 // of the parent MockStore instance is invoked.
 type StoreGetStarRankFunc struct {
@@ -1312,6 +1451,122 @@ func (c StoreGetStarRankFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// StoreGetSymbolReferenceCountsFunc describes the behavior when the
+// GetSymbolReferenceCounts method of the parent MockStore instance is
+// invoked.
+type StoreGetSymbolReferenceCountsFunc struct {
+	defaultHook func(context.Context, string, []api.RepoName, int) (map[api.RepoName][]shared.SymbolReferenceCount, error)
+	hooks       []func(context.Context, string, []api.RepoName, int) (map[api.RepoName][]shared.SymbolReferenceCount, error)
+	history     []StoreGetSymbolReferenceCountsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetSymbolReferenceCounts delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockStore) GetSymbolReferenceCounts(v0 context.Context, v1 string, v2 []api.RepoName, v3 int) (map[api.RepoName][]shared.SymbolReferenceCount, error) {
+	r0, r1 := m.GetSymbolReferenceCountsFunc.nextHook()(v0, v1, v2, v3)
+	m.GetSymbolReferenceCountsFunc.appendCall(StoreGetSymbolReferenceCountsFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// GetSymbolReferenceCounts method of the parent MockStore instance is
+// invoked and the hook queue is empty.
+func (f *StoreGetSymbolReferenceCountsFunc) SetDefaultHook(hook func(context.Context, string, []api.RepoName, int) (map[api.RepoName][]shared.SymbolReferenceCount, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetSymbolReferenceCounts method of the parent MockStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *StoreGetSymbolReferenceCountsFunc) PushHook(hook func(context.Context, string, []api.RepoName, int) (map[api.RepoName][]shared.SymbolReferenceCount, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetSymbolReferenceCountsFunc) SetDefaultReturn(r0 map[api.RepoName][]shared.SymbolReferenceCount, r1 error) {
+	f.SetDefaultHook(func(context.Context, string, []api.RepoName, int) (map[api.RepoName][]shared.SymbolReferenceCount, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetSymbolReferenceCountsFunc) PushReturn(r0 map[api.RepoName][]shared.SymbolReferenceCount, r1 error) {
+	f.PushHook(func(context.Context, string, []api.RepoName, int) (map[api.RepoName][]shared.SymbolReferenceCount, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreGetSymbolReferenceCountsFunc) nextHook() func(context.Context, string, []api.RepoName, int) (map[api.RepoName][]shared.SymbolReferenceCount, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetSymbolReferenceCountsFunc) appendCall(r0 StoreGetSymbolReferenceCountsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreGetSymbolReferenceCountsFuncCall
+// objects describing the invocations of this function.
+func (f *StoreGetSymbolReferenceCountsFunc) History() []StoreGetSymbolReferenceCountsFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetSymbolReferenceCountsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetSymbolReferenceCountsFuncCall is an object that describes an
+// invocation of method GetSymbolReferenceCounts on an instance of
+// MockStore.
+type StoreGetSymbolReferenceCountsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []api.RepoName
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 map[api.RepoName][]shared.SymbolReferenceCount
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetSymbolReferenceCountsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetSymbolReferenceCountsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // StoreGetUploadsForRankingFunc describes the behavior when the
 // GetUploadsForRanking method of the parent MockStore instance is invoked.
 type StoreGetUploadsForRankingFunc struct {
@@ -8,8 +8,9 @@ import (
 )
 
 type operations struct {
-	getRepoRank      *observation.Operation
-	getDocumentRanks *observation.Operation
+	getRepoRank          *observation.Operation
+	getDocumentRanks     *observation.Operation
+	exportRankingSignals *observation.Operation
 }
 
 var (
@@ -35,7 +36,8 @@ func newOperations(observationCtx *observation.Context) *operations {
 	}
 
 	return &operations{
-		getRepoRank:      op("GetRepoRank"),
-		getDocumentRanks: op("GetDocumentRanks"),
+		getRepoRank:          op("GetRepoRank"),
+		getDocumentRanks:     op("GetDocumentRanks"),
+		exportRankingSignals: op("ExportRankingSignals"),
 	}
 }
@@ -2,12 +2,14 @@ package ranking
 
 import (
 	"context"
+	"io"
 	"math"
 	"time"
 
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/background/signals"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/lsifstore"
 	internalshared "github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/shared"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/store"
@@ -121,6 +123,16 @@ func (s *Service) GetDocumentRanks(ctx context.Context, repoName api.RepoName) (
 	}, nil
 }
 
+// ExportRankingSignals writes the document and symbol reference counts of the given repository
+// to w as newline-delimited JSON. See shared.RankingSignal for the format of each record.
+func (s *Service) ExportRankingSignals(ctx context.Context, repoName api.RepoName, w io.Writer) (err error) {
+	ctx, _, endObservation := s.operations.exportRankingSignals.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	_, err = signals.WriteRankingSignals(ctx, s.store, repoName, signals.MaxSymbolsPerRepository, w)
+	return err
+}
+
 func (s *Service) Summaries(ctx context.Context) ([]shared.Summary, error) {
 	return s.store.Summaries(ctx)
 }
@@ -35,3 +35,30 @@ type RankingReferences struct {
 	ExportedUploadID int
 	SymbolChecksums  [][16]byte
 }
+
+// SymbolReferenceCount is the number of references to a symbol defined in a particular
+// document. Symbols are identified by the hex-encoded checksum of their version-less name.
+type SymbolReferenceCount struct {
+	DocumentPath   string
+	SymbolChecksum string
+	ReferenceCount int
+}
+
+// RankingSignalType distinguishes the kinds of records in a ranking signal export.
+type RankingSignalType string
+
+const (
+	RankingSignalTypeDocument RankingSignalType = "document"
+	RankingSignalTypeSymbol   RankingSignalType = "symbol"
+)
+
+// RankingSignal is a single line of a ranking signal export. An export is a JSONL stream of
+// these records; see doc/dev/background-information/architecture/precise-ranking.md for the
+// format.
+type RankingSignal struct {
+	Type           RankingSignalType `json:"type"`
+	Repository     string            `json:"repository"`
+	Path           string            `json:"path"`
+	SymbolChecksum string            `json:"symbolChecksum,omitempty"`
+	ReferenceCount float64           `json:"referenceCount"`
+}
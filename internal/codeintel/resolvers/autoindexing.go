@@ -18,6 +18,10 @@ type AutoindexingServiceResolver interface {
 	// Inference
 	InferAutoIndexJobsForRepo(ctx context.Context, args *InferAutoIndexJobsForRepoArgs) (InferAutoIndexJobsResultResolver, error)
 	QueueAutoIndexJobsForRepo(ctx context.Context, args *QueueAutoIndexJobsForRepoArgs) ([]PreciseIndexResolver, error)
+	QueueAutoIndexJobsForCommitRange(ctx context.Context, args *QueueAutoIndexJobsForCommitRangeArgs) ([]string, error)
+
+	// Dependency indexing
+	DependencyIndexingPlan(ctx context.Context, args *DependencyIndexingPlanArgs) ([]DependencyIndexingPlanEntryResolver, error)
 }
 
 type UpdateCodeIntelligenceInferenceScriptArgs struct {
@@ -41,6 +45,15 @@ type QueueAutoIndexJobsForRepoArgs struct {
 	Configuration *string
 }
 
+type QueueAutoIndexJobsForCommitRangeArgs struct {
+	Repository graphql.ID
+	FromRev    *string
+	ToRev      *string
+	TagPattern *string
+	Limit      *int32
+	BatchSize  *int32
+}
+
 type IndexConfigurationResolver interface {
 	Configuration(ctx context.Context) (*string, error)
 	ParsedConfiguration(ctx context.Context) (*[]AutoIndexJobDescriptionResolver, error)
@@ -58,6 +71,19 @@ type InferAutoIndexJobsResultResolver interface {
 	InferenceOutput() string
 }
 
+type DependencyIndexingPlanArgs struct {
+	Repository graphql.ID
+}
+
+type DependencyIndexingPlanEntryResolver interface {
+	Scheme() string
+	Name() string
+	Version() string
+	RepositoryName() *string
+	Depth() *int32
+	Status() string
+}
+
 type (
 	RepositoriesWithErrorsArgs                             = PagedConnectionArgs
 	RepositoriesWithConfigurationArgs                      = PagedConnectionArgs
@@ -36,6 +36,7 @@ type GitBlobLSIFDataResolver interface {
 	Hover(ctx context.Context, args *LSIFQueryPositionArgs) (HoverResolver, error)
 	VisibleIndexes(ctx context.Context) (_ *[]PreciseIndexResolver, err error)
 	Snapshot(ctx context.Context, args *struct{ IndexID graphql.ID }) (_ *[]SnapshotDataResolver, err error)
+	CallGraph(ctx context.Context, args *LSIFCallGraphArgs) (CallGraphResolver, error)
 }
 
 type SnapshotDataResolver interface {
@@ -44,6 +45,33 @@ type SnapshotDataResolver interface {
 	Additional() *[]string
 }
 
+type LSIFCallGraphArgs struct {
+	Line      int32
+	Character int32
+	Direction string
+	Depth     *int32
+	First     *int32
+}
+
+type CallGraphResolver interface {
+	Nodes() []CallGraphNodeResolver
+	Edges() []CallGraphEdgeResolver
+	LimitHit() bool
+}
+
+type CallGraphNodeResolver interface {
+	Symbol() string
+	DisplayName() string
+	Definition(ctx context.Context) (LocationResolver, error)
+	Depth() int32
+}
+
+type CallGraphEdgeResolver interface {
+	Caller() string
+	Callee() string
+	CallSites(ctx context.Context) ([]LocationResolver, error)
+}
+
 type LSIFRangesArgs struct {
 	StartLine int32
 	EndLine   int32
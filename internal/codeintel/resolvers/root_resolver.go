@@ -149,6 +149,14 @@ func (r *Resolver) QueueAutoIndexJobsForRepo(ctx context.Context, args *QueueAut
 	return r.autoIndexingRootResolver.QueueAutoIndexJobsForRepo(ctx, args)
 }
 
+func (r *Resolver) QueueAutoIndexJobsForCommitRange(ctx context.Context, args *QueueAutoIndexJobsForCommitRangeArgs) (_ []string, err error) {
+	return r.autoIndexingRootResolver.QueueAutoIndexJobsForCommitRange(ctx, args)
+}
+
+func (r *Resolver) DependencyIndexingPlan(ctx context.Context, args *DependencyIndexingPlanArgs) (_ []DependencyIndexingPlanEntryResolver, err error) {
+	return r.autoIndexingRootResolver.DependencyIndexingPlan(ctx, args)
+}
+
 func (r *Resolver) InferAutoIndexJobsForRepo(ctx context.Context, args *InferAutoIndexJobsForRepoArgs) (_ InferAutoIndexJobsResultResolver, err error) {
 	return r.autoIndexingRootResolver.InferAutoIndexJobsForRepo(ctx, args)
 }
@@ -185,6 +193,10 @@ func (r *Resolver) RepositorySummary(ctx context.Context, id graphql.ID) (_ Code
 	return r.uploadsRootResolver.RepositorySummary(ctx, id)
 }
 
+func (r *Resolver) RepositoryCoverage(ctx context.Context, id graphql.ID) (_ CodeIntelRepositoryCoverageResolver, err error) {
+	return r.uploadsRootResolver.RepositoryCoverage(ctx, id)
+}
+
 func (r *Resolver) IndexConfiguration(ctx context.Context, id graphql.ID) (_ IndexConfigurationResolver, err error) {
 	return r.autoIndexingRootResolver.IndexConfiguration(ctx, id)
 }
@@ -26,6 +26,7 @@ type UploadsServiceResolver interface {
 	// Coverage
 	CodeIntelSummary(ctx context.Context) (CodeIntelSummaryResolver, error)
 	RepositorySummary(ctx context.Context, id graphql.ID) (CodeIntelRepositorySummaryResolver, error)
+	RepositoryCoverage(ctx context.Context, id graphql.ID) (CodeIntelRepositoryCoverageResolver, error)
 }
 
 type PreciseIndexesQueryArgs struct {
@@ -185,6 +186,22 @@ type CodeIntelRepositorySummaryResolver interface {
 	LimitError() *string
 }
 
+type CodeIntelRepositoryCoverageResolver interface {
+	PreciseCoveragePercentage() float64
+	NewestUploadAt() *gqlutil.DateTime
+	Projects() []CodeIntelCoverageProjectResolver
+	LimitError() *string
+}
+
+type CodeIntelCoverageProjectResolver interface {
+	Root() string
+	Indexer() CodeIntelIndexerResolver
+	Status() string
+	Inferred() bool
+	Commit() *string
+	UploadedAt() *gqlutil.DateTime
+}
+
 type InferredAvailableIndexersResolver interface {
 	Indexer() CodeIntelIndexerResolver
 	Roots() []string
@@ -27,16 +27,36 @@ type Config struct {
 	GCSProjectID               string
 	GCSCredentialsFile         string
 	GCSCredentialsFileContents string
+
+	AzureAccountName        string
+	AzureAccountKey         string
+	AzureSASToken           string
+	AzureUseManagedIdentity bool
+	AzureEndpoint           string
+
+	// Cold storage tier. Tiering is disabled when ColdBackend is empty.
+	ColdBackend            string
+	ColdManageBucket       bool
+	ColdBucket             string
+	ColdTTL                time.Duration
+	ColdS3Region           string
+	ColdS3Endpoint         string
+	ColdS3AccessKeyID      string
+	ColdS3SecretAccessKey  string
+	ColdGCSProjectID       string
+	ColdGCSCredentialsFile string
+	TieringMaxAge          time.Duration
+	TieringInterval        time.Duration
 }
 
 func (c *Config) Load() {
-	c.Backend = strings.ToLower(c.Get("PRECISE_CODE_INTEL_UPLOAD_BACKEND", "blobstore", "The target file service for code intelligence uploads. S3, GCS, and Blobstore are supported."))
+	c.Backend = strings.ToLower(c.Get("PRECISE_CODE_INTEL_UPLOAD_BACKEND", "blobstore", "The target file service for code intelligence uploads. S3, GCS, Azure, and Blobstore are supported."))
 	c.ManageBucket = c.GetBool("PRECISE_CODE_INTEL_UPLOAD_MANAGE_BUCKET", "false", "Whether or not the client should manage the target bucket configuration.")
 	c.Bucket = c.Get("PRECISE_CODE_INTEL_UPLOAD_BUCKET", "lsif-uploads", "The name of the bucket to store LSIF uploads in.")
 	c.TTL = c.GetInterval("PRECISE_CODE_INTEL_UPLOAD_TTL", "168h", "The maximum age of an upload before deletion.")
 
-	if c.Backend != "blobstore" && c.Backend != "s3" && c.Backend != "gcs" {
-		c.AddError(errors.Errorf("invalid backend %q for PRECISE_CODE_INTEL_UPLOAD_BACKEND: must be S3, GCS, or Blobstore", c.Backend))
+	if c.Backend != "blobstore" && c.Backend != "s3" && c.Backend != "gcs" && c.Backend != "azure" {
+		c.AddError(errors.Errorf("invalid backend %q for PRECISE_CODE_INTEL_UPLOAD_BACKEND: must be S3, GCS, Azure, or Blobstore", c.Backend))
 	}
 
 	if c.Backend == "blobstore" || c.Backend == "s3" {
@@ -54,5 +74,34 @@ func (c *Config) Load() {
 		c.GCSProjectID = c.Get("PRECISE_CODE_INTEL_UPLOAD_GCP_PROJECT_ID", "", "The project containing the GCS bucket.")
 		c.GCSCredentialsFile = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_GOOGLE_APPLICATION_CREDENTIALS_FILE", "The path to a service account key file with access to GCS.")
 		c.GCSCredentialsFileContents = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_GOOGLE_APPLICATION_CREDENTIALS_FILE_CONTENT", "The contents of a service account key file with access to GCS.")
+	} else if c.Backend == "azure" {
+		c.AzureAccountName = c.Get("PRECISE_CODE_INTEL_UPLOAD_AZURE_ACCOUNT_NAME", "", "The Azure storage account containing the container.")
+		c.AzureAccountKey = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_AZURE_ACCOUNT_KEY", "A shared key of the Azure storage account. Required to hand out signed URLs.")
+		c.AzureSASToken = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_AZURE_SAS_TOKEN", "A shared access signature with access to the container, used if no account key is set.")
+		c.AzureUseManagedIdentity = c.GetBool("PRECISE_CODE_INTEL_UPLOAD_AZURE_USE_MANAGED_IDENTITY", "false", "Whether to authenticate as the managed identity of the VM or pod if neither an account key nor a SAS token is set.")
+		c.AzureEndpoint = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_AZURE_ENDPOINT", "The blob service endpoint. Defaults to https://<account>.blob.core.windows.net.")
+	}
+
+	c.ColdBackend = strings.ToLower(c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_COLD_BACKEND", "The target file service for code intelligence uploads moved out of the primary upload store. S3 and GCS are supported. Tiering is disabled when unset."))
+	if c.ColdBackend == "" {
+		return
+	}
+
+	c.ColdManageBucket = c.GetBool("PRECISE_CODE_INTEL_UPLOAD_COLD_MANAGE_BUCKET", "false", "Whether or not the client should manage the cold storage bucket configuration.")
+	c.ColdBucket = c.Get("PRECISE_CODE_INTEL_UPLOAD_COLD_BUCKET", "lsif-uploads-cold", "The name of the bucket to move aged LSIF uploads into.")
+	c.ColdTTL = c.GetInterval("PRECISE_CODE_INTEL_UPLOAD_COLD_TTL", "2160h", "The maximum age of an upload in cold storage before deletion.")
+	c.TieringMaxAge = c.GetInterval("PRECISE_CODE_INTEL_UPLOAD_TIERING_MAX_AGE", "72h", "The age at which uploads are moved from the primary upload store into cold storage.")
+	c.TieringInterval = c.GetInterval("PRECISE_CODE_INTEL_UPLOAD_TIERING_INTERVAL", "1h", "The frequency at which to move aged uploads into cold storage.")
+
+	if c.ColdBackend == "s3" {
+		c.ColdS3Region = c.Get("PRECISE_CODE_INTEL_UPLOAD_COLD_AWS_REGION", "us-east-1", "The target AWS region of the cold storage bucket.")
+		c.ColdS3Endpoint = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_COLD_AWS_ENDPOINT", "The target AWS endpoint of the cold storage bucket.")
+		c.ColdS3AccessKeyID = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_COLD_AWS_ACCESS_KEY_ID", "An AWS access key associated with a user with access to the cold storage bucket.")
+		c.ColdS3SecretAccessKey = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_COLD_AWS_SECRET_ACCESS_KEY", "An AWS secret key associated with a user with access to the cold storage bucket.")
+	} else if c.ColdBackend == "gcs" {
+		c.ColdGCSProjectID = c.Get("PRECISE_CODE_INTEL_UPLOAD_COLD_GCP_PROJECT_ID", "", "The project containing the cold storage GCS bucket.")
+		c.ColdGCSCredentialsFile = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_COLD_GOOGLE_APPLICATION_CREDENTIALS_FILE", "The path to a service account key file with access to the cold storage GCS bucket.")
+	} else {
+		c.AddError(errors.Errorf("invalid backend %q for PRECISE_CODE_INTEL_UPLOAD_COLD_BACKEND: must be S3 or GCS", c.ColdBackend))
 	}
 }
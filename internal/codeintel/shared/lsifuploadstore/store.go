@@ -3,11 +3,55 @@ package lsifuploadstore
 import (
 	"context"
 
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
 )
 
+// New creates an upload store from the given configuration. If a cold storage tier is
+// configured, the returned store transparently reads objects that have been moved into
+// cold storage. Uploads are encrypted with the code intel upload key of the keyring, if
+// one is configured.
 func New(ctx context.Context, observationCtx *observation.Context, conf *Config) (uploadstore.Store, error) {
+	hot, cold, err := newTiers(ctx, observationCtx, conf)
+	if err != nil {
+		return nil, err
+	}
+	if cold == nil {
+		return encrypted(hot), nil
+	}
+
+	return encrypted(uploadstore.NewTieredStore(hot, cold, conf.TieringMaxAge)), nil
+}
+
+// NewWithTierer is like New, but also returns a background routine that periodically moves
+// objects with the given prefix older than the configured tiering age from the primary upload
+// store into cold storage. The store and the routine share the same underlying clients. If no
+// cold storage tier is configured, a nil routine is returned.
+func NewWithTierer(ctx context.Context, observationCtx *observation.Context, conf *Config, prefix string) (uploadstore.Store, goroutine.BackgroundRoutine, error) {
+	hot, cold, err := newTiers(ctx, observationCtx, conf)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cold == nil {
+		return encrypted(hot), nil, nil
+	}
+
+	// The tierer moves encrypted objects between the tiers as is.
+	store := encrypted(uploadstore.NewTieredStore(hot, cold, conf.TieringMaxAge))
+	tierer := uploadstore.NewTierer(ctx, hot, cold, prefix, conf.TieringMaxAge, conf.TieringInterval)
+	return store, tierer, nil
+}
+
+func encrypted(store uploadstore.Store) *uploadstore.EncryptedStore {
+	return uploadstore.NewEncryptedStore(store, func() encryption.Key {
+		return keyring.Default().CodeIntelUploadKey
+	})
+}
+
+func newTiers(ctx context.Context, observationCtx *observation.Context, conf *Config) (hot, cold uploadstore.Store, err error) {
 	c := uploadstore.Config{
 		Backend:      conf.Backend,
 		ManageBucket: conf.ManageBucket,
@@ -26,7 +70,41 @@ func New(ctx context.Context, observationCtx *observation.Context, conf *Config)
 			CredentialsFile:         conf.GCSCredentialsFile,
 			CredentialsFileContents: conf.GCSCredentialsFileContents,
 		},
+		Azure: uploadstore.AzureConfig{
+			AccountName:        conf.AzureAccountName,
+			AccountKey:         conf.AzureAccountKey,
+			SASToken:           conf.AzureSASToken,
+			UseManagedIdentity: conf.AzureUseManagedIdentity,
+			Endpoint:           conf.AzureEndpoint,
+		},
+	}
+
+	hot, err = uploadstore.CreateLazy(ctx, c, uploadstore.NewOperations(observationCtx, "codeintel", "uploadstore"))
+	if err != nil || conf.ColdBackend == "" {
+		return hot, nil, err
+	}
+
+	coldConfig := uploadstore.Config{
+		Backend:      conf.ColdBackend,
+		ManageBucket: conf.ColdManageBucket,
+		Bucket:       conf.ColdBucket,
+		TTL:          conf.ColdTTL,
+		S3: uploadstore.S3Config{
+			Region:          conf.ColdS3Region,
+			Endpoint:        conf.ColdS3Endpoint,
+			AccessKeyID:     conf.ColdS3AccessKeyID,
+			SecretAccessKey: conf.ColdS3SecretAccessKey,
+		},
+		GCS: uploadstore.GCSConfig{
+			ProjectID:       conf.ColdGCSProjectID,
+			CredentialsFile: conf.ColdGCSCredentialsFile,
+		},
+	}
+
+	cold, err = uploadstore.CreateLazy(ctx, coldConfig, uploadstore.NewOperations(observationCtx, "codeintel", "uploadstore_cold"))
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return uploadstore.CreateLazy(ctx, c, uploadstore.NewOperations(observationCtx, "codeintel", "uploadstore"))
+	return hot, cold, nil
 }
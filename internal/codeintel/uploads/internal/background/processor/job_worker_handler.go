@@ -342,6 +342,10 @@ func (h *handler) HandleRawUpload(ctx context.Context, logger log.Logger, upload
 				return errors.Wrap(err, "store.MarkRepositoryAsDirty")
 			}
 
+			if err := tx.PublishUploadProcessedEvent(ctx, upload); err != nil {
+				return errors.Wrap(err, "store.PublishUploadProcessedEvent")
+			}
+
 			return nil
 		})
 	})
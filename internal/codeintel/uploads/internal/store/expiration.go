@@ -57,7 +57,10 @@ const repositoryIDsForRetentionScanQuery = `
 WITH candidate_repositories AS (
 	SELECT DISTINCT u.repository_id AS id
 	FROM lsif_uploads u
-	WHERE u.state = 'completed'
+	WHERE
+		u.state = 'completed' AND
+		-- Uploads of repositories on legal hold do not expire
+		NOT EXISTS (SELECT 1 FROM repo_legal_holds h WHERE h.repo_id = u.repository_id)
 ),
 repositories AS (
 	SELECT cr.id
@@ -184,7 +187,11 @@ WITH
 expired_uploads AS (
 	SELECT u.id
 	FROM lsif_uploads u
-	WHERE u.state = 'completed' AND u.expired
+	WHERE
+		u.state = 'completed' AND
+		u.expired AND
+		-- Uploads of repositories on legal hold are never deleted
+		NOT EXISTS (SELECT 1 FROM repo_legal_holds h WHERE h.repo_id = u.repository_id)
 	ORDER BY u.last_referenced_scan_at NULLS FIRST, u.finished_at, u.id
 	LIMIT %s
 ),
@@ -370,7 +377,11 @@ root_upload_and_packages AS (
 			` + packageRankingQueryFragment + ` AS rank
 		FROM lsif_uploads u
 		LEFT JOIN lsif_packages p ON p.dump_id = u.id
-		WHERE u.state = 'completed' AND u.expired
+		WHERE
+			u.state = 'completed' AND
+			u.expired AND
+			-- Uploads of repositories on legal hold are never deleted
+			NOT EXISTS (SELECT 1 FROM repo_legal_holds h WHERE h.repo_id = u.repository_id)
 	) s
 
 	WHERE s.rank = 1 AND EXISTS (
@@ -460,9 +471,15 @@ updated AS (
 
 		-- Delete all of the upload we've traversed if and only if we've identified the entire
 		-- relevant subgraph (we didn't hit our LIMIT above) and every upload of the subgraph is
-		-- expired. If this is not the case, we leave the state the same for all uploads.
+		-- expired. If this is not the case, we leave the state the same for all uploads. The
+		-- same applies if any upload of the subgraph belongs to a repository on legal hold.
 		state = CASE
-			WHEN (SELECT bool_and(d.expired) AND COUNT(*) <= %s FROM candidates d) THEN 'deleting'
+			WHEN (SELECT bool_and(d.expired) AND COUNT(*) <= %s FROM candidates d) AND NOT EXISTS (
+				SELECT 1
+				FROM candidates d
+				JOIN lsif_uploads du ON du.id = d.id
+				JOIN repo_legal_holds h ON h.repo_id = du.repository_id
+			) THEN 'deleting'
 			ELSE 'completed'
 		END
 	WHERE u.id IN (SELECT id FROM locked_uploads)
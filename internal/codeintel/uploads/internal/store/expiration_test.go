@@ -94,6 +94,40 @@ func TestSoftDeleteExpiredUploads(t *testing.T) {
 	}
 }
 
+func TestSoftDeleteExpiredUploadsLegalHold(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	store := New(&observation.TestContext, db)
+	ctx := context.Background()
+
+	insertUploads(t, db,
+		shared.Upload{ID: 50, RepositoryID: 100, State: "completed"},
+		shared.Upload{ID: 51, RepositoryID: 101, State: "completed"},
+	)
+	if err := store.UpdateUploadRetention(ctx, []int{}, []int{50, 51}); err != nil {
+		t.Fatalf("unexpected error marking uploads as expired: %s", err)
+	}
+	if _, err := db.RepoLegalHolds().Create(ctx, 101, "litigation", 0); err != nil {
+		t.Fatalf("unexpected error placing legal hold: %s", err)
+	}
+
+	if _, count, err := store.SoftDeleteExpiredUploads(ctx, 100); err != nil {
+		t.Fatalf("unexpected error soft deleting uploads: %s", err)
+	} else if count != 1 {
+		t.Fatalf("unexpected number of uploads deleted: want=%d have=%d", 1, count)
+	}
+
+	expectedStates := map[int]string{
+		50: "deleting",
+		51: "completed",
+	}
+	if states, err := getUploadStates(db, 50, 51); err != nil {
+		t.Fatalf("unexpected error getting states: %s", err)
+	} else if diff := cmp.Diff(expectedStates, states); diff != "" {
+		t.Errorf("unexpected upload states (-want +got):\n%s", diff)
+	}
+}
+
 func TestSoftDeleteExpiredUploadsViaTraversal(t *testing.T) {
 	logger := logtest.Scoped(t)
 	db := database.NewDB(logger, dbtest.NewDB(logger, t))
@@ -2,13 +2,17 @@ package store
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/keegancsmith/sqlf"
 	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
 // HasRepository determines if there is LSIF data for the given repository.
@@ -69,3 +73,25 @@ const insertDependencySyncingJobQuery = `
 INSERT INTO lsif_dependency_syncing_jobs (upload_id) VALUES (%s)
 RETURNING id
 `
+
+// PublishUploadProcessedEvent records in the transactional outbox that the given upload was processed.
+// It should be called in the transaction that commits the processed data of the upload.
+func (s *store) PublishUploadProcessedEvent(ctx context.Context, upload shared.Upload) (err error) {
+	ctx, _, endObservation := s.operations.publishUploadProcessedEvent.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("uploadID", upload.ID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return database.OutboxEventsWith(s.db).Publish(ctx, database.OutboxEventArgs{
+		EventType:     types.OutboxEventUploadProcessed,
+		AggregateType: "upload",
+		AggregateID:   strconv.Itoa(upload.ID),
+		Payload: map[string]any{
+			"upload_id":     upload.ID,
+			"repository_id": upload.RepositoryID,
+			"commit":        upload.Commit,
+			"root":          upload.Root,
+			"indexer":       upload.Indexer,
+		},
+	})
+}
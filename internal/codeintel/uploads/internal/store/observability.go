@@ -81,6 +81,9 @@ type operations struct {
 	// Dependencies
 	insertDependencySyncingJob *observation.Operation
 
+	// Events
+	publishUploadProcessedEvent *observation.Operation
+
 	reindexUploads                 *observation.Operation
 	reindexUploadByID              *observation.Operation
 	deleteIndexesWithoutRepository *observation.Operation
@@ -188,6 +191,9 @@ func newOperations(observationCtx *observation.Context) *operations {
 		// Dependencies
 		insertDependencySyncingJob: op("InsertDependencySyncingJob"),
 
+		// Events
+		publishUploadProcessedEvent: op("PublishUploadProcessedEvent"),
+
 		reindexUploads:                 op("ReindexUploads"),
 		reindexUploadByID:              op("ReindexUploadByID"),
 		deleteIndexesWithoutRepository: op("DeleteIndexesWithoutRepository"),
@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/redispubsub"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
 
@@ -231,4 +232,5 @@ var UploadWorkerStoreOptions = dbworkerstore.Options[shared.Upload]{
 	`),
 	StalledMaxAge: stalledUploadMaxAge,
 	MaxNumResets:  uploadMaxNumResets,
+	OnStateChange: redispubsub.RecordStateChangeHook("lsif_uploads"),
 }
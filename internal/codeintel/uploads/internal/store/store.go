@@ -101,6 +101,9 @@ type Store interface {
 	HasRepository(ctx context.Context, repositoryID int) (bool, error)
 	HasCommit(ctx context.Context, repositoryID int, commit string) (bool, error)
 	InsertDependencySyncingJob(ctx context.Context, uploadID int) (int, error)
+
+	// Events
+	PublishUploadProcessedEvent(ctx context.Context, upload shared.Upload) error
 }
 
 type SourcedCommits struct {
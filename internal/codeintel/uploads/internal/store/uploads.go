@@ -42,6 +42,31 @@ func (s *store) GetUploads(ctx context.Context, opts shared.GetUploadsOptions) (
 	} else {
 		orderExpression = sqlf.Sprintf("uploaded_at DESC, id")
 	}
+	limitExpression := sqlf.Sprintf("LIMIT %d OFFSET %d", opts.Limit, opts.Offset)
+
+	selectConds := conds
+	if opts.After != nil {
+		p := &database.KeysetPagination{
+			Columns: []database.KeysetColumn{
+				{Field: "u.uploaded_at", Descending: !opts.OldestFirst},
+				{Field: "u.id", Descending: opts.OldestFirst},
+			},
+			Limit: opts.Limit,
+		}
+		if *opts.After != (shared.UploadCursor{}) {
+			p.After = []any{opts.After.UploadedAt, opts.After.ID}
+		}
+		args := p.SQL()
+
+		if args.Where != nil {
+			selectConds = append(selectConds[:len(selectConds):len(selectConds)], args.Where)
+		}
+		orderExpression = args.Order
+		limitExpression = sqlf.Sprintf("")
+		if args.Limit != nil {
+			limitExpression = args.Limit
+		}
+	}
 
 	var a []shared.Upload
 	var b int
@@ -50,10 +75,9 @@ func (s *store) GetUploads(ctx context.Context, opts shared.GetUploadsOptions) (
 			getUploadsSelectQuery,
 			buildCTEPrefix(cte),
 			tableExpr,
-			sqlf.Join(conds, " AND "),
+			sqlf.Join(selectConds, " AND "),
 			orderExpression,
-			opts.Limit,
-			opts.Offset,
+			limitExpression,
 		)
 		uploads, err = scanUploadComplete(tx.db.Query(ctx, query))
 		if err != nil {
@@ -117,7 +141,7 @@ ON u.id = s.id
 JOIN repo ON repo.id = u.repository_id
 WHERE %s
 ORDER BY %s
-LIMIT %d OFFSET %d
+%s
 `
 
 const getUploadsCountQuery = `
@@ -1202,6 +1226,7 @@ func buildGetUploadsLogFields(opts shared.GetUploadsOptions) []attribute.KeyValu
 		attribute.Bool("oldestFirst", opts.OldestFirst),
 		attribute.Int("limit", opts.Limit),
 		attribute.Int("offset", opts.Offset),
+		attribute.Bool("keyset", opts.After != nil),
 	}
 }
 
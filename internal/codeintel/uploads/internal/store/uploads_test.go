@@ -214,6 +214,41 @@ func TestGetUploads(t *testing.T) {
 		}
 	}
 
+	t.Run("keyset pagination", func(t *testing.T) {
+		for _, oldestFirst := range []bool{false, true} {
+			var ids []int
+			after := shared.UploadCursor{}
+			for {
+				uploads, _, err := store.GetUploads(ctx, shared.GetUploadsOptions{
+					OldestFirst: oldestFirst,
+					Limit:       3,
+					After:       &after,
+				})
+				if err != nil {
+					t.Fatalf("unexpected error getting uploads: %s", err)
+				}
+
+				page, hasNextPage := database.KeysetPage(uploads, 3)
+				for _, upload := range page {
+					ids = append(ids, upload.ID)
+				}
+				if !hasNextPage {
+					break
+				}
+				last := page[len(page)-1]
+				after = shared.UploadCursor{UploadedAt: last.UploadedAt, ID: last.ID}
+			}
+
+			expectedIDs := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+			if oldestFirst {
+				expectedIDs = []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+			}
+			if diff := cmp.Diff(expectedIDs, ids); diff != "" {
+				t.Errorf("unexpected upload ids (oldestFirst=%v) (-want +got):\n%s", oldestFirst, diff)
+			}
+		}
+	})
+
 	t.Run("enforce repository permissions", func(t *testing.T) {
 		// Enable permissions user mapping forces checking repository permissions
 		// against permissions tables in the database, which should effectively block
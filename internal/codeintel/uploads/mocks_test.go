@@ -182,6 +182,10 @@ type MockStore struct {
 	// object controlling the behavior of the method
 	// ProcessStaleSourcedCommits.
 	ProcessStaleSourcedCommitsFunc *StoreProcessStaleSourcedCommitsFunc
+	// PublishUploadProcessedEventFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// PublishUploadProcessedEvent.
+	PublishUploadProcessedEventFunc *StorePublishUploadProcessedEventFunc
 	// ReconcileCandidatesFunc is an instance of a mock function object
 	// controlling the behavior of the method ReconcileCandidates.
 	ReconcileCandidatesFunc *StoreReconcileCandidatesFunc
@@ -474,6 +478,11 @@ func NewMockStore() *MockStore {
 				return
 			},
 		},
+		PublishUploadProcessedEventFunc: &StorePublishUploadProcessedEventFunc{
+			defaultHook: func(context.Context, shared.Upload) (r0 error) {
+				return
+			},
+		},
 		ReconcileCandidatesFunc: &StoreReconcileCandidatesFunc{
 			defaultHook: func(context.Context, int) (r0 []int, r1 error) {
 				return
@@ -801,6 +810,11 @@ func NewStrictMockStore() *MockStore {
 				panic("unexpected invocation of MockStore.ProcessStaleSourcedCommits")
 			},
 		},
+		PublishUploadProcessedEventFunc: &StorePublishUploadProcessedEventFunc{
+			defaultHook: func(context.Context, shared.Upload) error {
+				panic("unexpected invocation of MockStore.PublishUploadProcessedEvent")
+			},
+		},
 		ReconcileCandidatesFunc: &StoreReconcileCandidatesFunc{
 			defaultHook: func(context.Context, int) ([]int, error) {
 				panic("unexpected invocation of MockStore.ReconcileCandidates")
@@ -1038,6 +1052,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		ProcessStaleSourcedCommitsFunc: &StoreProcessStaleSourcedCommitsFunc{
 			defaultHook: i.ProcessStaleSourcedCommits,
 		},
+		PublishUploadProcessedEventFunc: &StorePublishUploadProcessedEventFunc{
+			defaultHook: i.PublishUploadProcessedEvent,
+		},
 		ReconcileCandidatesFunc: &StoreReconcileCandidatesFunc{
 			defaultHook: i.ReconcileCandidates,
 		},
@@ -6150,6 +6167,114 @@ func (c StoreProcessStaleSourcedCommitsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// StorePublishUploadProcessedEventFunc describes the behavior when the
+// PublishUploadProcessedEvent method of the parent MockStore instance is
+// invoked.
+type StorePublishUploadProcessedEventFunc struct {
+	defaultHook func(context.Context, shared.Upload) error
+	hooks       []func(context.Context, shared.Upload) error
+	history     []StorePublishUploadProcessedEventFuncCall
+	mutex       sync.Mutex
+}
+
+// PublishUploadProcessedEvent delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockStore) PublishUploadProcessedEvent(v0 context.Context, v1 shared.Upload) error {
+	r0 := m.PublishUploadProcessedEventFunc.nextHook()(v0, v1)
+	m.PublishUploadProcessedEventFunc.appendCall(StorePublishUploadProcessedEventFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// PublishUploadProcessedEvent method of the parent MockStore instance is
+// invoked and the hook queue is empty.
+func (f *StorePublishUploadProcessedEventFunc) SetDefaultHook(hook func(context.Context, shared.Upload) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// PublishUploadProcessedEvent method of the parent MockStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *StorePublishUploadProcessedEventFunc) PushHook(hook func(context.Context, shared.Upload) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StorePublishUploadProcessedEventFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, shared.Upload) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StorePublishUploadProcessedEventFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, shared.Upload) error {
+		return r0
+	})
+}
+
+func (f *StorePublishUploadProcessedEventFunc) nextHook() func(context.Context, shared.Upload) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StorePublishUploadProcessedEventFunc) appendCall(r0 StorePublishUploadProcessedEventFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StorePublishUploadProcessedEventFuncCall
+// objects describing the invocations of this function.
+func (f *StorePublishUploadProcessedEventFunc) History() []StorePublishUploadProcessedEventFuncCall {
+	f.mutex.Lock()
+	history := make([]StorePublishUploadProcessedEventFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StorePublishUploadProcessedEventFuncCall is an object that describes an
+// invocation of method PublishUploadProcessedEvent on an instance of
+// MockStore.
+type StorePublishUploadProcessedEventFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared.Upload
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StorePublishUploadProcessedEventFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StorePublishUploadProcessedEventFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // StoreReconcileCandidatesFunc describes the behavior when the
 // ReconcileCandidates method of the parent MockStore instance is invoked.
 type StoreReconcileCandidatesFunc struct {
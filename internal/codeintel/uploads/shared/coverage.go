@@ -0,0 +1,103 @@
+package shared
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+// CoverageEntry describes the code navigation coverage of a single project (a root and
+// indexer pair) of a repository.
+type CoverageEntry struct {
+	Root    string
+	Indexer string
+
+	// Precise is true when a completed upload for this project is visible from the tip of
+	// the default branch. Otherwise, code navigation for the project is search-based only.
+	Precise bool
+
+	// Inferred is true when auto-indexing inference produces an index job for this project.
+	// Precise projects that are not inferred are only kept fresh by uploads from outside of
+	// auto-indexing (e.g. CI).
+	Inferred bool
+
+	// Commit and UploadedAt describe the newest upload for this project visible from the tip
+	// of the default branch. Both are empty when the project has no precise coverage.
+	Commit     string
+	UploadedAt *time.Time
+}
+
+// RepositoryCoverage summarizes the code navigation coverage of a repository.
+type RepositoryCoverage struct {
+	Entries        []CoverageEntry
+	NewestUploadAt *time.Time
+}
+
+// PreciseRatio returns the fraction of projects with precise coverage. A repository with no
+// known projects has a ratio of zero.
+func (c RepositoryCoverage) PreciseRatio() float64 {
+	if len(c.Entries) == 0 {
+		return 0
+	}
+
+	precise := 0
+	for _, entry := range c.Entries {
+		if entry.Precise {
+			precise++
+		}
+	}
+
+	return float64(precise) / float64(len(c.Entries))
+}
+
+// SummarizeRepositoryCoverage combines the completed uploads visible from the tip of the default
+// branch with the index jobs inferred for the same commit into a coverage summary. Projects are
+// matched by root and indexer name, ignoring indexer image tags and digests.
+func SummarizeRepositoryCoverage(visibleUploads []Upload, inferredJobs []config.IndexJob) RepositoryCoverage {
+	var coverage RepositoryCoverage
+	entries := map[string]*CoverageEntry{}
+
+	for _, upload := range visibleUploads {
+		key := GetKeyForLookup(upload.Indexer, upload.Root)
+		entry, ok := entries[key]
+		if !ok {
+			entry = &CoverageEntry{Root: upload.Root, Indexer: upload.Indexer, Precise: true}
+			entries[key] = entry
+		}
+
+		uploadedAt := upload.UploadedAt
+		if entry.UploadedAt == nil || uploadedAt.After(*entry.UploadedAt) {
+			entry.Commit = upload.Commit
+			entry.UploadedAt = &uploadedAt
+		}
+		if coverage.NewestUploadAt == nil || uploadedAt.After(*coverage.NewestUploadAt) {
+			coverage.NewestUploadAt = &uploadedAt
+		}
+	}
+
+	for _, job := range inferredJobs {
+		key := GetKeyForLookup(job.GetIndexerName(), job.GetRoot())
+		entry, ok := entries[key]
+		if !ok {
+			entry = &CoverageEntry{Root: job.GetRoot(), Indexer: job.GetIndexerName()}
+			entries[key] = entry
+		}
+
+		entry.Inferred = true
+	}
+
+	coverage.Entries = make([]CoverageEntry, 0, len(entries))
+	for _, entry := range entries {
+		coverage.Entries = append(coverage.Entries, *entry)
+	}
+	sort.Slice(coverage.Entries, func(i, j int) bool {
+		if coverage.Entries[i].Root != coverage.Entries[j].Root {
+			return coverage.Entries[i].Root < coverage.Entries[j].Root
+		}
+
+		return coverage.Entries[i].Indexer < coverage.Entries[j].Indexer
+	})
+
+	return coverage
+}
@@ -0,0 +1,59 @@
+package shared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func TestSummarizeRepositoryCoverage(t *testing.T) {
+	t1 := time.Unix(1587396557, 0).UTC()
+	t2 := t1.Add(time.Hour)
+	t3 := t1.Add(time.Hour * 2)
+
+	uploads := []Upload{
+		{ID: 1, Root: "", Indexer: "scip-go", Commit: "deadbeef01", UploadedAt: t1},
+		{ID: 2, Root: "", Indexer: "scip-go", Commit: "deadbeef02", UploadedAt: t2},
+		{ID: 3, Root: "web/", Indexer: "scip-typescript", Commit: "deadbeef03", UploadedAt: t3},
+	}
+	jobs := []config.IndexJob{
+		{Root: "", Indexer: "sourcegraph/scip-go@sha256:123456"},
+		{Root: "client/", Indexer: "sourcegraph/scip-typescript:latest"},
+	}
+
+	coverage := SummarizeRepositoryCoverage(uploads, jobs)
+
+	expectedEntries := []CoverageEntry{
+		{Root: "", Indexer: "scip-go", Precise: true, Inferred: true, Commit: "deadbeef02", UploadedAt: &t2},
+		{Root: "client/", Indexer: "scip-typescript", Precise: false, Inferred: true},
+		{Root: "web/", Indexer: "scip-typescript", Precise: true, Inferred: false, Commit: "deadbeef03", UploadedAt: &t3},
+	}
+	if diff := cmp.Diff(expectedEntries, coverage.Entries); diff != "" {
+		t.Errorf("unexpected entries (-want +got):\n%s", diff)
+	}
+
+	if coverage.NewestUploadAt == nil || !coverage.NewestUploadAt.Equal(t3) {
+		t.Errorf("unexpected newest upload time. want=%s have=%v", t3, coverage.NewestUploadAt)
+	}
+
+	if ratio := coverage.PreciseRatio(); ratio != 2.0/3.0 {
+		t.Errorf("unexpected precise ratio. want=%f have=%f", 2.0/3.0, ratio)
+	}
+}
+
+func TestSummarizeRepositoryCoverageEmpty(t *testing.T) {
+	coverage := SummarizeRepositoryCoverage(nil, nil)
+
+	if len(coverage.Entries) != 0 {
+		t.Errorf("unexpected entries. want=none have=%v", coverage.Entries)
+	}
+	if coverage.NewestUploadAt != nil {
+		t.Errorf("unexpected newest upload time. want=nil have=%s", coverage.NewestUploadAt)
+	}
+	if ratio := coverage.PreciseRatio(); ratio != 0 {
+		t.Errorf("unexpected precise ratio. want=%f have=%f", 0.0, ratio)
+	}
+}
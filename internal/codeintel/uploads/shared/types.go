@@ -167,12 +167,25 @@ type GetUploadsOptions struct {
 	Limit                   int
 	Offset                  int
 
+	// After, if set, lists the uploads sorted after the given upload instead of
+	// skipping Offset uploads. A zero cursor requests the first page. GetUploads
+	// then fetches one upload more than Limit so that callers can tell whether
+	// there is a next page.
+	After *UploadCursor
+
 	// InCommitGraph ensures that the repository commit graph was updated strictly
 	// after this upload was processed. This condition helps us filter out new uploads
 	// that we might later mistake for unreachable.
 	InCommitGraph bool
 }
 
+// UploadCursor identifies the position of an upload in the ordering of GetUploads.
+// The zero value precedes all uploads.
+type UploadCursor struct {
+	UploadedAt time.Time
+	ID         int
+}
+
 type ReindexUploadsOptions struct {
 	States       []string
 	IndexerNames []string
@@ -16,6 +16,7 @@ type operations struct {
 	preciseIndexes        *observation.Operation
 	reindexPreciseIndex   *observation.Operation
 	reindexPreciseIndexes *observation.Operation
+	repositoryCoverage    *observation.Operation
 	repositorySummary     *observation.Operation
 }
 
@@ -44,6 +45,7 @@ func newOperations(observationCtx *observation.Context) *operations {
 		preciseIndexes:        op("PreciseIndexes"),
 		reindexPreciseIndex:   op("ReindexPreciseIndex"),
 		reindexPreciseIndexes: op("ReindexPreciseIndexes"),
+		repositoryCoverage:    op("RepositoryCoverage"),
 		repositorySummary:     op("RepositorySummary"),
 	}
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -154,6 +155,53 @@ func (r *rootResolver) RepositorySummary(ctx context.Context, repoID graphql.ID)
 	), nil
 }
 
+// maxCoverageUploads is the maximum number of uploads visible from the tip of the default
+// branch considered when summarizing the coverage of a single repository.
+const maxCoverageUploads = 1000
+
+func (r *rootResolver) RepositoryCoverage(ctx context.Context, repoID graphql.ID) (_ resolverstubs.CodeIntelRepositoryCoverageResolver, err error) {
+	ctx, _, endObservation := r.operations.repositoryCoverage.WithErrors(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("repoID", string(repoID)),
+	}})
+	endObservation.OnCancel(ctx, 1, observation.Args{})
+
+	id, err := resolverstubs.UnmarshalID[int](repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	uploads, _, err := r.uploadSvc.GetUploads(ctx, uploadsShared.GetUploadsOptions{
+		RepositoryID: id,
+		State:        "completed",
+		VisibleAtTip: true,
+		Limit:        maxCoverageUploads,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var limitErr error
+	var inferredJobs []config.IndexJob
+
+	if autoIndexingEnabled() {
+		result, err := r.autoindexSvc.InferIndexJobsFromRepositoryStructure(ctx, id, "HEAD", "", false)
+		if err != nil {
+			if !autoindexing.IsLimitError(err) {
+				return nil, err
+			}
+
+			limitErr = err
+		} else {
+			inferredJobs = result.IndexJobs
+		}
+	}
+
+	return &repositoryCoverageResolver{
+		coverage: uploadsShared.SummarizeRepositoryCoverage(uploads, inferredJobs),
+		limitErr: limitErr,
+	}, nil
+}
+
 //
 //
 
@@ -472,3 +520,73 @@ func comparisonKey(root, indexer string) string {
 	_, _ = hash.Write([]byte(strings.Join([]string{root, indexer}, "\x00")))
 	return base64.URLEncoding.EncodeToString(hash.Sum(nil))
 }
+
+//
+//
+
+type repositoryCoverageResolver struct {
+	coverage uploadsShared.RepositoryCoverage
+	limitErr error
+}
+
+func (r *repositoryCoverageResolver) PreciseCoveragePercentage() float64 {
+	return r.coverage.PreciseRatio() * 100
+}
+
+func (r *repositoryCoverageResolver) NewestUploadAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.coverage.NewestUploadAt)
+}
+
+func (r *repositoryCoverageResolver) Projects() []resolverstubs.CodeIntelCoverageProjectResolver {
+	resolvers := make([]resolverstubs.CodeIntelCoverageProjectResolver, 0, len(r.coverage.Entries))
+	for _, entry := range r.coverage.Entries {
+		resolvers = append(resolvers, &coverageProjectResolver{entry: entry})
+	}
+
+	return resolvers
+}
+
+func (r *repositoryCoverageResolver) LimitError() *string {
+	if r.limitErr != nil {
+		m := r.limitErr.Error()
+		return &m
+	}
+
+	return nil
+}
+
+type coverageProjectResolver struct {
+	entry uploadsShared.CoverageEntry
+}
+
+func (r *coverageProjectResolver) Root() string {
+	return r.entry.Root
+}
+
+func (r *coverageProjectResolver) Indexer() resolverstubs.CodeIntelIndexerResolver {
+	return NewCodeIntelIndexerResolver(r.entry.Indexer, "")
+}
+
+func (r *coverageProjectResolver) Status() string {
+	if r.entry.Precise {
+		return "PRECISE"
+	}
+
+	return "SEARCH_BASED"
+}
+
+func (r *coverageProjectResolver) Inferred() bool {
+	return r.entry.Inferred
+}
+
+func (r *coverageProjectResolver) Commit() *string {
+	if r.entry.Commit == "" {
+		return nil
+	}
+
+	return &r.entry.Commit
+}
+
+func (r *coverageProjectResolver) UploadedAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.entry.UploadedAt)
+}
@@ -13,6 +13,7 @@ import (
 	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
 	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -29,7 +30,10 @@ func (r *rootResolver) PreciseIndexes(ctx context.Context, args *resolverstubs.P
 	if args.First != nil {
 		pageSize = int(*args.First)
 	}
-	uploadOffset := 0
+	// The cursor of a page is made of the keyset cursor of the last upload and the
+	// offset of the last index on the page, separated by a colon.
+	uploadCursor := ""
+	uploadAfter := uploadsshared.UploadCursor{}
 	indexOffset := 0
 	if args.After != nil {
 		parts := strings.Split(*args.After, ":")
@@ -38,12 +42,11 @@ func (r *rootResolver) PreciseIndexes(ctx context.Context, args *resolverstubs.P
 		}
 
 		if parts[0] != "" {
-			v, err := strconv.Atoi(parts[0])
-			if err != nil {
-				return nil, errors.New("invalid cursor")
+			if err := database.DecodeKeysetCursor(parts[0], &uploadAfter.UploadedAt, &uploadAfter.ID); err != nil {
+				return nil, err
 			}
 
-			uploadOffset = v
+			uploadCursor = parts[0]
 		}
 		if parts[1] != "" {
 			v, err := strconv.Atoi(parts[1])
@@ -124,11 +127,12 @@ func (r *rootResolver) PreciseIndexes(ctx context.Context, args *resolverstubs.P
 			AllowDeletedUpload: args.IncludeDeleted != nil && *args.IncludeDeleted,
 			IndexerNames:       indexerNames,
 			Limit:              pageSize,
-			Offset:             uploadOffset,
+			After:              &uploadAfter,
 		}); err != nil {
 			return nil, err
 		}
 	}
+	uploads, moreUploads := database.KeysetPage(uploads, pageSize)
 
 	var indexes []uploadsshared.Index
 	totalIndexCount := 0
@@ -175,8 +179,14 @@ func (r *rootResolver) PreciseIndexes(ctx context.Context, args *resolverstubs.P
 	}
 
 	cursor := ""
-	if newUploadOffset := uploadOffset + uIdx; newUploadOffset < totalUploadCount {
-		cursor += strconv.Itoa(newUploadOffset)
+	if uIdx < len(uploads) || moreUploads {
+		if uIdx > 0 {
+			last := uploads[uIdx-1]
+			if uploadCursor, err = database.EncodeKeysetCursor(last.UploadedAt, last.ID); err != nil {
+				return nil, err
+			}
+		}
+		cursor += uploadCursor
 	}
 	cursor += ":"
 	if newIndexOffset := indexOffset + iIdx; newIndexOffset < totalIndexCount {
@@ -1,12 +1,14 @@
 package graphql
 
 import (
+	"context"
 	"strconv"
 	"strings"
 
 	"github.com/graph-gophers/graphql-go"
 
 	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+	"github.com/sourcegraph/sourcegraph/internal/redispubsub"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -19,6 +21,24 @@ func UnmarshalPreciseIndexGQLID(id graphql.ID) (uploadID, indexID int, err error
 	return uploadID, indexID, errors.Wrap(err, "unexpected precise index ID")
 }
 
+// PreciseIndexStateTopics returns the redispubsub topics on which changes to
+// the state of the upload and index of the given precise index are published.
+func PreciseIndexStateTopics(_ context.Context, id graphql.ID) ([]string, error) {
+	uploadID, indexID, err := UnmarshalPreciseIndexGQLID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var topics []string
+	if uploadID != 0 {
+		topics = append(topics, redispubsub.RecordTopic("lsif_uploads", uploadID))
+	}
+	if indexID != 0 {
+		topics = append(topics, redispubsub.RecordTopic("lsif_indexes", indexID))
+	}
+	return topics, nil
+}
+
 var errExpectedPairs = errors.New("expected pairs of `U:<id>`, `I:<id>`")
 
 func unmarshalRawPreciseIndexGQLID(id graphql.ID) (uploadID, indexID int, err error) {
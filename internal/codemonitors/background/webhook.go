@@ -7,27 +7,101 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"text/template"
+	"time"
 
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/webhooks/outbound"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
-func sendWebhookNotification(ctx context.Context, url string, args actionArgs) error {
-	return postWebhook(ctx, httpcli.ExternalDoer, url, generateWebhookPayload(args))
+const (
+	// maxWebhookDeliveryAttempts is the number of times we try to deliver a
+	// webhook before recording it in the dead-letter queue.
+	maxWebhookDeliveryAttempts = 5
+
+	// webhookRetryAfter is how long we wait before retrying a failed
+	// delivery.
+	webhookRetryAfter = time.Minute
+
+	webhookSignatureHeader = outbound.HeaderSignature
+)
+
+// webhookDelivery describes where and how a webhook payload is delivered.
+type webhookDelivery struct {
+	URL string
+
+	// PayloadTemplate, if set, is rendered over the payload to build the
+	// request body instead of sending the payload as JSON.
+	PayloadTemplate *string
+
+	// SigningSecret, if set, is used to sign the request body with an
+	// HMAC-SHA256 sent in the X-Sourcegraph-Webhook-Signature header.
+	SigningSecret *string
 }
 
-func postWebhook(ctx context.Context, doer httpcli.Doer, url string, payload webhookPayload) error {
-	raw, err := json.Marshal(payload)
+func webhookDeliveryFromAction(w *database.WebhookAction) webhookDelivery {
+	return webhookDelivery{
+		URL:             w.URL,
+		PayloadTemplate: w.PayloadTemplate,
+		SigningSecret:   w.SigningSecret,
+	}
+}
+
+// webhookTemplateFuncs are the functions available to webhook payload
+// templates in addition to the text/template builtins.
+var webhookTemplateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// ValidateWebhookPayloadTemplate returns an error if the given webhook payload
+// template cannot be parsed or cannot be rendered over a sample payload.
+func ValidateWebhookPayloadTemplate(tmpl string) error {
+	_, err := renderWebhookBody(&tmpl, generateWebhookPayload(actionArgs{
+		ExternalURL:        &url.URL{},
+		MonitorDescription: "test monitor",
+		Query:              "test query",
+		IncludeResults:     true,
+	}))
+	return err
+}
+
+// renderWebhookBody builds the request body for the given payload. The payload
+// is marshalled to JSON unless a template is provided.
+func renderWebhookBody(tmpl *string, payload webhookPayload) ([]byte, error) {
+	if tmpl == nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal failed")
+		}
+		return raw, nil
+	}
+
+	t, err := template.New("webhook").Funcs(webhookTemplateFuncs).Option("missingkey=error").Parse(*tmpl)
 	if err != nil {
-		return errors.Wrap(err, "marshal failed")
+		return nil, errors.Wrap(err, "invalid payload template")
 	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, payload); err != nil {
+		return nil, errors.Wrap(err, "failed to render payload template")
+	}
+	return buf.Bytes(), nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(raw))
+func postWebhook(ctx context.Context, doer httpcli.Doer, d webhookDelivery, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
 	if err != nil {
 		return errors.Wrap(err, "failed new request")
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if d.SigningSecret != nil && *d.SigningSecret != "" {
+		req.Header.Set(webhookSignatureHeader, outbound.Sign(*d.SigningSecret, body))
+	}
 
 	resp, err := doer.Do(req)
 	if err != nil {
@@ -47,13 +121,17 @@ func postWebhook(ctx context.Context, doer httpcli.Doer, url string, payload web
 	return nil
 }
 
-func SendTestWebhook(ctx context.Context, doer httpcli.Doer, description string, u string) error {
+func SendTestWebhook(ctx context.Context, doer httpcli.Doer, description string, u string, payloadTemplate, signingSecret *string) error {
 	args := actionArgs{
 		ExternalURL:        &url.URL{},
 		MonitorDescription: description,
 		Query:              "test query",
 	}
-	return postWebhook(ctx, httpcli.ExternalDoer, u, generateWebhookPayload(args))
+	body, err := renderWebhookBody(payloadTemplate, generateWebhookPayload(args))
+	if err != nil {
+		return err
+	}
+	return postWebhook(ctx, doer, webhookDelivery{URL: u, PayloadTemplate: payloadTemplate, SigningSecret: signingSecret}, body)
 }
 
 type webhookPayload struct {
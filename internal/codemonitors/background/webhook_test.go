@@ -2,6 +2,9 @@ package background
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -37,8 +40,11 @@ func TestWebhook(t *testing.T) {
 		}))
 		defer s.Close()
 
+		body, err := renderWebhookBody(nil, generateWebhookPayload(action))
+		require.NoError(t, err)
+
 		client := s.Client()
-		err := postWebhook(context.Background(), client, s.URL, generateWebhookPayload(action))
+		err = postWebhook(context.Background(), client, webhookDelivery{URL: s.URL}, body)
 		require.NoError(t, err)
 	})
 
@@ -63,8 +69,11 @@ func TestWebhook(t *testing.T) {
 		}))
 		defer s.Close()
 
+		body, err := renderWebhookBody(nil, generateWebhookPayload(action))
+		require.NoError(t, err)
+
 		client := s.Client()
-		err := postWebhook(context.Background(), client, s.URL, generateWebhookPayload(action))
+		err = postWebhook(context.Background(), client, webhookDelivery{URL: s.URL}, body)
 		require.Error(t, err)
 	})
 }
@@ -79,6 +88,52 @@ func TestTriggerTestWebhookAction(t *testing.T) {
 	defer s.Close()
 
 	client := s.Client()
-	err := SendTestWebhook(context.Background(), client, "My test monitor", s.URL)
+	err := SendTestWebhook(context.Background(), client, "My test monitor", s.URL, nil, nil)
+	require.NoError(t, err)
+}
+
+func TestWebhookPayloadTemplate(t *testing.T) {
+	eu, err := url.Parse("https://sourcegraph.com")
+	require.NoError(t, err)
+
+	payload := generateWebhookPayload(actionArgs{
+		MonitorDescription: "My \"quoted\" monitor",
+		ExternalURL:        eu,
+		MonitorID:          42,
+		Query:              "repo:camdentest -file:id_rsa.pub BEGIN",
+		Results:            []*result.CommitMatch{&diffResultMock, &commitResultMock},
+		IncludeResults:     true,
+	})
+
+	t.Run("renders result fields", func(t *testing.T) {
+		tmpl := `{"text": {{ json .MonitorDescription }}, "repos": [{{ range $i, $r := .Results }}{{ if $i }},{{ end }}{{ json $r.Repository }}{{ end }}]}`
+		body, err := renderWebhookBody(&tmpl, payload)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"text": "My \"quoted\" monitor", "repos": ["github.com/test/test", "github.com/test/test"]}`, string(body))
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		require.Error(t, ValidateWebhookPayloadTemplate(`{{ .MonitorDescription`))
+		require.Error(t, ValidateWebhookPayloadTemplate(`{{ .DoesNotExist }}`))
+		require.NoError(t, ValidateWebhookPayloadTemplate(`{{ json .Query }}`))
+	})
+}
+
+func TestWebhookSignature(t *testing.T) {
+	secret := "hunter2"
+	body := []byte(`{"query":"test"}`)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(b)
+		require.Equal(t, hex.EncodeToString(mac.Sum(nil)), r.Header.Get(webhookSignatureHeader))
+		w.WriteHeader(200)
+	}))
+	defer s.Close()
+
+	err := postWebhook(context.Background(), s.Client(), webhookDelivery{URL: s.URL, SigningSecret: &secret}, body)
 	require.NoError(t, err)
 }
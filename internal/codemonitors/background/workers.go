@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/graph-gophers/graphql-go/relay"
 	"github.com/keegancsmith/sqlf"
 
 	"github.com/sourcegraph/log"
@@ -13,11 +14,15 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/codemonitors"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/featureflag"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/notifications"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
@@ -41,7 +46,12 @@ func newTriggerQueryRunner(ctx context.Context, observationCtx *observation.Cont
 
 	store := createDBWorkerStoreForTriggerJobs(observationCtx, db)
 
-	worker := dbworker.NewWorker[*database.TriggerJob](ctx, store, &queryRunner{db: db, enterpriseJobs: enterpriseJobs}, options)
+	runner := &queryRunner{
+		db:             db,
+		enterpriseJobs: enterpriseJobs,
+		notifier:       notifications.NewNotifier(observationCtx.Logger, db),
+	}
+	worker := dbworker.NewWorker[*database.TriggerJob](ctx, store, runner, options)
 	return worker
 }
 
@@ -145,8 +155,10 @@ func createDBWorkerStoreForActionJobs(observationCtx *observation.Context, s dat
 		ColumnExpressions: database.ActionJobColumns,
 		Scan:              dbworkerstore.BuildWorkerScan(database.ScanActionJob),
 		StalledMaxAge:     60 * time.Second,
-		RetryAfter:        10 * time.Second,
-		MaxNumRetries:     3,
+		// Failed webhook deliveries are retried by the store, and are only
+		// dead-lettered by the handler on their last attempt.
+		RetryAfter:        webhookRetryAfter,
+		MaxNumRetries:     maxWebhookDeliveryAttempts,
 		OrderByExpression: sqlf.Sprintf("id"),
 	})
 }
@@ -154,6 +166,7 @@ func createDBWorkerStoreForActionJobs(observationCtx *observation.Context, s dat
 type queryRunner struct {
 	db             database.DB
 	enterpriseJobs jobutil.EnterpriseJobs
+	notifier       *notifications.Notifier
 }
 
 func (r *queryRunner) Handle(ctx context.Context, logger log.Logger, triggerJob *database.TriggerJob) (err error) {
@@ -206,6 +219,12 @@ func (r *queryRunner) Handle(ctx context.Context, logger log.Logger, triggerJob
 		if err != nil {
 			return errors.Wrap(err, "store.EnqueueActionJobsForQuery")
 		}
+
+		r.notifier.Notify(ctx, notifications.Notification{
+			Kind:  types.NotificationKindCodeMonitor,
+			Title: fmt.Sprintf("Code monitor %q found %d new %s", m.Description, len(results), pluralize("result", len(results))),
+			URL:   fmt.Sprintf("/code-monitoring/%s", relay.MarshalID(MonitorKind, m.ID)),
+		}, m.UserID)
 	}
 	return nil
 }
@@ -325,7 +344,33 @@ func (r *actionRunner) handleWebhook(ctx context.Context, j *database.ActionJob)
 		IncludeResults:     w.IncludeResults,
 	}
 
-	return sendWebhookNotification(ctx, w.URL, args)
+	body, err := renderWebhookBody(w.PayloadTemplate, generateWebhookPayload(args))
+	if err != nil {
+		// Rendering is deterministic, so retrying would not help.
+		return r.deadLetterWebhook(ctx, j, w, nil, err)
+	}
+
+	if err := postWebhook(ctx, httpcli.ExternalDoer, webhookDeliveryFromAction(w), body); err != nil {
+		return r.handleWebhookDeliveryError(ctx, j, w, body, err)
+	}
+	return nil
+}
+
+// handleWebhookDeliveryError returns the delivery error so that the job is
+// retried by the worker store. Once all attempts are exhausted, the delivery is
+// moved to the dead-letter queue and the job is marked as failed.
+func (r *actionRunner) handleWebhookDeliveryError(ctx context.Context, j *database.ActionJob, w *database.WebhookAction, body []byte, deliveryErr error) error {
+	if j.NumFailures+1 >= maxWebhookDeliveryAttempts {
+		return r.deadLetterWebhook(ctx, j, w, body, deliveryErr)
+	}
+	return deliveryErr
+}
+
+func (r *actionRunner) deadLetterWebhook(ctx context.Context, j *database.ActionJob, w *database.WebhookAction, body []byte, deliveryErr error) error {
+	if _, err := r.CodeMonitorStore.CreateWebhookDeadLetter(ctx, w.ID, j.ID, string(body), deliveryErr.Error(), j.NumFailures+1); err != nil {
+		return errors.Append(deliveryErr, errors.Wrap(err, "CreateWebhookDeadLetter"))
+	}
+	return errcode.MakeNonRetryable(deliveryErr)
 }
 
 func (r *actionRunner) handleSlackWebhook(ctx context.Context, j *database.ActionJob) error {
@@ -12,7 +12,9 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 func TestActionRunner(t *testing.T) {
@@ -110,3 +112,34 @@ func TestActionRunner(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleWebhookDeliveryError(t *testing.T) {
+	ctx := context.Background()
+	deliveryErr := errors.New("status 500")
+	w := &database.WebhookAction{ID: 7}
+
+	t.Run("retried", func(t *testing.T) {
+		s := database.NewMockCodeMonitorStore()
+		a := actionRunner{s}
+
+		err := a.handleWebhookDeliveryError(ctx, &database.ActionJob{ID: 1, NumFailures: maxWebhookDeliveryAttempts - 2}, w, []byte("{}"), deliveryErr)
+		require.ErrorIs(t, err, deliveryErr)
+		require.False(t, errcode.IsNonRetryable(err))
+		require.Empty(t, s.CreateWebhookDeadLetterFunc.History())
+	})
+
+	t.Run("dead-lettered after the last attempt", func(t *testing.T) {
+		s := database.NewMockCodeMonitorStore()
+		a := actionRunner{s}
+
+		err := a.handleWebhookDeliveryError(ctx, &database.ActionJob{ID: 1, NumFailures: maxWebhookDeliveryAttempts - 1}, w, []byte("{}"), deliveryErr)
+		require.EqualError(t, err, "status 500")
+		require.True(t, errcode.IsNonRetryable(err))
+
+		history := s.CreateWebhookDeadLetterFunc.History()
+		require.Len(t, history, 1)
+		require.Equal(t, int64(7), history[0].Arg1)
+		require.Equal(t, "{}", history[0].Arg3)
+		require.Equal(t, int32(maxWebhookDeliveryAttempts), history[0].Arg5)
+	})
+}
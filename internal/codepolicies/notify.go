@@ -0,0 +1,104 @@
+package codepolicies
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/webhooks/outbound"
+)
+
+type violationNotifier interface {
+	// Notify notifies the webhook of the policy of the violations that were
+	// opened and resolved by a run of its query.
+	Notify(ctx context.Context, p *Policy, opened, resolved []*Violation) error
+}
+
+type webhookNotifier struct {
+	doer httpcli.Doer
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, p *Policy, opened, resolved []*Violation) error {
+	externalURL, err := url.Parse(conf.ExternalURL())
+	if err != nil {
+		return err
+	}
+	doer := n.doer
+	if doer == nil {
+		doer = httpcli.ExternalDoer
+	}
+	return outbound.PostJSON(ctx, doer, *p.WebhookURL, newWebhookPayload(externalURL, p, opened, resolved))
+}
+
+type webhookPayload struct {
+	PolicyID string             `json:"policyID"`
+	Name     string             `json:"name"`
+	Query    string             `json:"query"`
+	Opened   []webhookViolation `json:"opened"`
+	Resolved []webhookViolation `json:"resolved"`
+}
+
+type webhookViolation struct {
+	ID         string     `json:"id"`
+	Repository string     `json:"repository"`
+	Path       string     `json:"path,omitempty"`
+	Commit     string     `json:"commit,omitempty"`
+	LineNumber int32      `json:"lineNumber,omitempty"`
+	Preview    string     `json:"preview,omitempty"`
+	URL        string     `json:"url"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+}
+
+func newWebhookPayload(externalURL *url.URL, p *Policy, opened, resolved []*Violation) webhookPayload {
+	toWebhookViolations := func(vs []*Violation) []webhookViolation {
+		out := make([]webhookViolation, 0, len(vs))
+		for _, v := range vs {
+			out = append(out, webhookViolation{
+				ID:         string(relay.MarshalID("CodePolicyViolation", v.ID)),
+				Repository: string(v.RepoName),
+				Path:       v.Path,
+				Commit:     v.Commit,
+				LineNumber: v.LineNumber,
+				Preview:    v.Preview,
+				URL:        externalURL.ResolveReference(v.URL()).String(),
+				ResolvedAt: v.ResolvedAt,
+			})
+		}
+		return out
+	}
+
+	return webhookPayload{
+		PolicyID: string(relay.MarshalID("CodePolicy", p.ID)),
+		Name:     p.Name,
+		Query:    p.Query,
+		Opened:   toWebhookViolations(opened),
+		Resolved: toWebhookViolations(resolved),
+	}
+}
+
+// URL returns the URL of the location the violation was last seen at, relative
+// to the external URL.
+func (v *Violation) URL() *url.URL {
+	switch {
+	case v.Path != "":
+		repoPath := string(v.RepoName)
+		if v.Commit != "" {
+			repoPath += "@" + v.Commit
+		}
+		u := &url.URL{Path: path.Join("/", repoPath, "-/blob", v.Path)}
+		if v.LineNumber > 0 {
+			u.RawQuery = "L" + strconv.Itoa(int(v.LineNumber))
+		}
+		return u
+	case v.Commit != "":
+		return &url.URL{Path: path.Join("/", string(v.RepoName), "-/commit", v.Commit)}
+	default:
+		return &url.URL{Path: path.Join("/", string(v.RepoName))}
+	}
+}
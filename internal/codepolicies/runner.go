@@ -0,0 +1,274 @@
+// Package codepolicies runs policy queries continuously over the repositories
+// they apply to, and tracks their results as violations.
+package codepolicies
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	// policiesPerRun is the maximum number of policies that are run per
+	// iteration of the runner.
+	policiesPerRun = 20
+
+	// maxSearchDuration is the maximum time a single policy query may run.
+	maxSearchDuration = 5 * time.Minute
+
+	// maxFindingsPerRun is the maximum number of violations recorded per run
+	// of a policy query.
+	maxFindingsPerRun = 10000
+
+	// maxPreviewLength is the maximum length of the preview of a violation.
+	maxPreviewLength = 200
+)
+
+// NewRunner returns a background routine that runs the queries of due
+// policies and records their violations.
+func NewRunner(observationCtx *observation.Context, db database.DB, enterpriseJobs jobutil.EnterpriseJobs) goroutine.BackgroundRoutine {
+	r := &runner{
+		logger:   observationCtx.Logger.Scoped("CodePolicyRunner", "runs code policy queries"),
+		db:       db,
+		store:    newStore(db),
+		search:   newSearchFunc(observationCtx.Logger, db, enterpriseJobs),
+		notifier: &webhookNotifier{},
+	}
+	return goroutine.NewPeriodicGoroutine(
+		context.Background(),
+		goroutine.HandlerFunc(r.runDue),
+		goroutine.WithName("codepolicies.runner"),
+		goroutine.WithDescription("runs code policy queries and tracks their violations"),
+		goroutine.WithInterval(time.Minute),
+	)
+}
+
+// searchFunc runs query to completion and returns its results and the
+// statistics of the search.
+type searchFunc func(ctx context.Context, query string) (result.Matches, streaming.Stats, error)
+
+func newSearchFunc(logger log.Logger, db database.DB, enterpriseJobs jobutil.EnterpriseJobs) searchFunc {
+	searchClient := client.New(logger, db, enterpriseJobs)
+	plan := func(ctx context.Context, query string) (*search.Inputs, error) {
+		return searchClient.Plan(ctx, "V3", nil, query, search.Precise, search.Streaming)
+	}
+
+	return func(ctx context.Context, query string) (result.Matches, streaming.Stats, error) {
+		inputs, err := plan(ctx, query)
+		if err != nil {
+			return nil, streaming.Stats{}, errcode.MakeNonRetryable(err)
+		}
+		if inputs.Query.Count() == nil {
+			// Violations that are not returned would be resolved, so the
+			// query must not be limited to the default number of results.
+			if inputs, err = plan(ctx, query+" count:all"); err != nil {
+				return nil, streaming.Stats{}, errcode.MakeNonRetryable(err)
+			}
+		}
+
+		agg := streaming.NewAggregatingStream()
+		if _, err := searchClient.Execute(ctx, agg, inputs); err != nil {
+			return nil, streaming.Stats{}, err
+		}
+		return agg.Results, agg.Stats, nil
+	}
+}
+
+type runner struct {
+	logger   log.Logger
+	db       database.DB
+	store    *store
+	search   searchFunc
+	notifier violationNotifier
+}
+
+func (r *runner) runDue(ctx context.Context) error {
+	policies, err := r.store.listDue(ctx, policiesPerRun)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range policies {
+		runErr := r.run(ctx, p)
+		if runErr != nil {
+			r.logger.Warn("code policy run failed",
+				log.Int32("policyID", p.ID),
+				log.Error(runErr))
+		}
+		if err := r.store.recordRun(ctx, p.ID, runErr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run runs the query of the policy, records its violations and notifies the
+// webhook of the policy of the violations that were opened or resolved.
+func (r *runner) run(ctx context.Context, p *Policy) error {
+	if p.CreatorID == nil {
+		return errors.New("the creator of the code policy was deleted")
+	}
+
+	// 🚨 SECURITY: The query runs as the creator of the policy, so that it only
+	// matches repositories they have access to. Only site admins may create
+	// policies.
+	user, err := r.db.Users().GetByID(ctx, *p.CreatorID)
+	if err != nil {
+		return err
+	}
+	if !user.SiteAdmin {
+		return errors.New("the creator of the code policy is no longer a site admin")
+	}
+	searchCtx, cancel := context.WithTimeout(actor.WithActor(ctx, actor.FromUser(user.ID)), maxSearchDuration)
+	defer cancel()
+
+	matches, stats, err := r.search(searchCtx, policyQuery(p))
+	if err != nil {
+		return err
+	}
+
+	findings := findingsFromMatches(matches)
+	if len(findings) > maxFindingsPerRun {
+		findings = findings[:maxFindingsPerRun]
+		// Violations beyond the limit are not known, so none are resolved.
+		stats.IsLimitHit = true
+	}
+	opened, resolved, err := r.store.syncViolations(ctx, p.ID, findings, completelySearchedRepos(stats))
+	if err != nil {
+		return err
+	}
+
+	if len(opened) == 0 && len(resolved) == 0 || p.WebhookURL == nil || *p.WebhookURL == "" {
+		return nil
+	}
+	return errors.Wrap(r.notifier.Notify(ctx, p, opened, resolved), "notifying webhook")
+}
+
+// policyQuery returns the search query that matches the violations of p in the
+// repositories it applies to.
+func policyQuery(p *Policy) string {
+	if p.RepoQuery == "" {
+		return p.Query
+	}
+	return p.RepoQuery + " " + p.Query
+}
+
+// completelySearchedRepos returns the repositories that were searched without
+// hitting a limit, timing out or missing. Violations in these repositories
+// that the search did not find anymore are resolved.
+func completelySearchedRepos(stats streaming.Stats) []api.RepoID {
+	if stats.IsLimitHit || stats.BackendsMissing > 0 {
+		return nil
+	}
+
+	incomplete := search.RepoStatusCloning | search.RepoStatusMissing | search.RepoStatusLimitHit | search.RepoStatusTimedout
+	repos := make([]api.RepoID, 0, len(stats.Repos))
+	for id := range stats.Repos {
+		if stats.Status.Get(id)&incomplete == 0 {
+			repos = append(repos, id)
+		}
+	}
+	return repos
+}
+
+// findingsFromMatches returns a finding per matched line of file matches, per
+// symbol of symbol matches, and per other match.
+func findingsFromMatches(matches result.Matches) []Finding {
+	var findings []Finding
+	seen := make(map[string]struct{}, len(matches))
+	add := func(f Finding) {
+		k := fmt.Sprintf("%d:%s", f.RepoID, f.Fingerprint)
+		if _, ok := seen[k]; ok {
+			return
+		}
+		seen[k] = struct{}{}
+		findings = append(findings, f)
+	}
+
+	for _, m := range matches {
+		switch m := m.(type) {
+		case *result.FileMatch:
+			base := Finding{RepoID: m.Repo.ID, Path: m.Path, Commit: string(m.CommitID)}
+
+			// The fingerprint of a line includes its content and the number of
+			// identical lines above it, so that violations are not reopened when
+			// unrelated lines of the file change.
+			occurrences := map[string]int{}
+			for _, lm := range m.ChunkMatches.AsLineMatches() {
+				if len(lm.OffsetAndLengths) == 0 {
+					continue
+				}
+				line := strings.TrimSpace(lm.Preview)
+				f := base
+				f.LineNumber = lm.LineNumber + 1
+				f.Preview = truncatePreview(line)
+				f.Fingerprint = fingerprint("line", m.Path, line, fmt.Sprint(occurrences[line]))
+				occurrences[line]++
+				add(f)
+			}
+			for _, sym := range m.Symbols {
+				f := base
+				f.LineNumber = int32(sym.Symbol.Line)
+				f.Preview = truncatePreview(sym.Symbol.Name)
+				f.Fingerprint = fingerprint("symbol", m.Path, sym.Symbol.Name, sym.Symbol.Kind)
+				add(f)
+			}
+			if len(m.ChunkMatches) == 0 && len(m.Symbols) == 0 {
+				base.Fingerprint = fingerprint("path", m.Path)
+				add(base)
+			}
+		case *result.CommitMatch:
+			add(Finding{
+				RepoID:      m.Repo.ID,
+				Commit:      string(m.Commit.ID),
+				Preview:     truncatePreview(m.Commit.Message.Subject()),
+				Fingerprint: fingerprint("commit", string(m.Commit.ID)),
+			})
+		case *result.CommitDiffMatch:
+			add(Finding{
+				RepoID:      m.Repo.ID,
+				Path:        m.Path(),
+				Commit:      string(m.Commit.ID),
+				Preview:     truncatePreview(m.Commit.Message.Subject()),
+				Fingerprint: fingerprint("diff", string(m.Commit.ID), m.Path()),
+			})
+		case *result.RepoMatch:
+			add(Finding{RepoID: m.ID, Fingerprint: fingerprint("repo")})
+		}
+	}
+	return findings
+}
+
+func fingerprint(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func truncatePreview(s string) string {
+	if len(s) <= maxPreviewLength {
+		return s
+	}
+	return strings.ToValidUTF8(s[:maxPreviewLength], "")
+}
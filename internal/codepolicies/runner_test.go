@@ -0,0 +1,166 @@
+package codepolicies
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/webhooks/outbound"
+)
+
+func TestFindingsFromMatches(t *testing.T) {
+	repo := types.MinimalRepo{ID: 1, Name: "r"}
+	fileMatch := func(commit api.CommitID, content string, line int) *result.FileMatch {
+		return &result.FileMatch{
+			File: result.File{Repo: repo, Path: "a.go", CommitID: commit},
+			ChunkMatches: result.ChunkMatches{{
+				Content:      content,
+				ContentStart: result.Location{Line: line},
+				Ranges: result.Ranges{{
+					Start: result.Location{Line: line, Column: 1},
+					End:   result.Location{Line: line, Column: 4},
+				}},
+			}},
+		}
+	}
+
+	findings := findingsFromMatches(result.Matches{
+		fileMatch("c1", "\tmd5.New()", 9),
+		&result.CommitMatch{Repo: repo, Commit: gitdomain.Commit{ID: "c1", Message: "Use md5\n\nBody"}},
+		&result.RepoMatch{ID: repo.ID, Name: repo.Name},
+		&result.RepoMatch{ID: repo.ID, Name: repo.Name, Rev: "main"},
+	})
+	require.Len(t, findings, 3)
+	require.Equal(t, Finding{
+		RepoID:      1,
+		Fingerprint: fingerprint("line", "a.go", "md5.New()", "0"),
+		Path:        "a.go",
+		Commit:      "c1",
+		LineNumber:  10,
+		Preview:     "md5.New()",
+	}, findings[0])
+	require.Equal(t, "Use md5", findings[1].Preview)
+
+	// The same line at another commit and line number is the same violation.
+	moved := findingsFromMatches(result.Matches{fileMatch("c2", "md5.New()", 20)})
+	require.Equal(t, findings[0].Fingerprint, moved[0].Fingerprint)
+	require.Equal(t, int32(21), moved[0].LineNumber)
+}
+
+func TestCompletelySearchedRepos(t *testing.T) {
+	stats := streaming.Stats{Repos: map[api.RepoID]struct{}{1: {}, 2: {}}}
+	stats.Status.Update(2, search.RepoStatusTimedout)
+	require.Equal(t, []api.RepoID{1}, completelySearchedRepos(stats))
+
+	stats.IsLimitHit = true
+	require.Empty(t, completelySearchedRepos(stats))
+}
+
+type fakeNotifier struct {
+	opened, resolved []*Violation
+}
+
+func (f *fakeNotifier) Notify(_ context.Context, _ *Policy, opened, resolved []*Violation) error {
+	f.opened = append(f.opened, opened...)
+	f.resolved = append(f.resolved, resolved...)
+	return nil
+}
+
+func TestRunnerRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+
+	admin, err := db.Users().Create(ctx, database.NewUser{Username: "admin"})
+	require.NoError(t, err)
+	require.NoError(t, db.Users().SetIsSiteAdmin(ctx, admin.ID, true))
+	require.NoError(t, db.Repos().Create(ctx, &types.Repo{Name: "r"}))
+	repo, err := db.Repos().GetByName(ctx, "r")
+	require.NoError(t, err)
+
+	s := newStore(db)
+	webhook := "https://example.com/hook"
+	p, err := s.CreatePolicy(ctx, &Policy{
+		Name:       "no-md5",
+		Query:      "md5.New",
+		RepoQuery:  "repo:^r$",
+		WebhookURL: &webhook,
+		Enabled:    true,
+		Interval:   time.Hour,
+		CreatorID:  &admin.ID,
+	})
+	require.NoError(t, err)
+
+	var (
+		gotQuery string
+		matches  result.Matches
+	)
+	n := &fakeNotifier{}
+	r := &runner{
+		logger: logger,
+		db:     db,
+		store:  s,
+		search: func(_ context.Context, query string) (result.Matches, streaming.Stats, error) {
+			gotQuery = query
+			return matches, streaming.Stats{Repos: map[api.RepoID]struct{}{repo.ID: {}}}, nil
+		},
+		notifier: n,
+	}
+
+	matches = result.Matches{&result.FileMatch{
+		File: result.File{Repo: types.MinimalRepo{ID: repo.ID, Name: repo.Name}, Path: "a.go"},
+	}}
+	require.NoError(t, r.runDue(ctx))
+	require.Equal(t, "repo:^r$ md5.New", gotQuery)
+	require.Len(t, n.opened, 1)
+	require.Equal(t, "a.go", n.opened[0].Path)
+
+	matches = nil
+	require.NoError(t, r.run(ctx, p))
+	require.Len(t, n.resolved, 1)
+
+	require.NoError(t, db.Users().SetIsSiteAdmin(ctx, admin.ID, false))
+	require.Error(t, r.run(ctx, p))
+}
+
+func TestPostWebhookPayload(t *testing.T) {
+	var got webhookPayload
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer s.Close()
+
+	externalURL, err := url.Parse("https://sourcegraph.example.com")
+	require.NoError(t, err)
+
+	payload := newWebhookPayload(externalURL, &Policy{ID: 1, Name: "no-md5", Query: "md5.New"}, []*Violation{
+		{ID: 1, RepoName: "r", Path: "a.go", Commit: "c1", LineNumber: 10},
+	}, []*Violation{
+		{ID: 2, RepoName: "r", Commit: "c2"},
+	})
+	require.NoError(t, outbound.PostJSON(context.Background(), http.DefaultClient, s.URL, payload))
+
+	require.Equal(t, "no-md5", got.Name)
+	require.Equal(t, "https://sourcegraph.example.com/r@c1/-/blob/a.go?L10", got.Opened[0].URL)
+	require.Equal(t, "https://sourcegraph.example.com/r/-/commit/c2", got.Resolved[0].URL)
+}
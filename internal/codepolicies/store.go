@@ -0,0 +1,540 @@
+package codepolicies
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var (
+	// ErrPolicyNotFound is returned when a policy does not exist.
+	ErrPolicyNotFound = errors.New("code policy not found")
+	// ErrViolationNotFound is returned when a violation does not exist.
+	ErrViolationNotFound = errors.New("code policy violation not found")
+)
+
+// Policy is a search query that is run continuously over a set of
+// repositories. Every result of the query is a violation of the policy.
+type Policy struct {
+	ID          int32
+	Name        string
+	Description string
+	// Query is the search query that matches the violations of the policy.
+	Query string
+	// RepoQuery are the repository filters that select the repositories the
+	// policy applies to. Empty means all repositories.
+	RepoQuery string
+	// WebhookURL is notified whenever violations are opened or resolved.
+	WebhookURL *string
+	Enabled    bool
+	// Interval is the time between two runs of the policy query.
+	Interval time.Duration
+	// CreatorID is the site admin that created the policy, or nil if they were
+	// deleted. The policy query runs as the creator.
+	CreatorID *int32
+
+	NextRunAt time.Time
+	// LastRunAt is the time of the last successful run, or nil if the policy
+	// query has not been run successfully yet.
+	LastRunAt *time.Time
+	LastError *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ViolationState is the state of a violation.
+type ViolationState string
+
+const (
+	// ViolationStateOpen violations are matched by the last run of the policy
+	// query.
+	ViolationStateOpen ViolationState = "open"
+	// ViolationStateResolved violations are no longer matched by the policy
+	// query, or were resolved by a user.
+	ViolationStateResolved ViolationState = "resolved"
+)
+
+// Violation is a result of a policy query, tracked across runs.
+type Violation struct {
+	ID       int32
+	PolicyID int32
+	RepoID   api.RepoID
+	RepoName api.RepoName
+	// Fingerprint identifies the violation across runs.
+	Fingerprint string
+	Path        string
+	// Commit and LineNumber are where the violation was last seen. LineNumber
+	// is 1-based, and 0 for violations that do not refer to a line.
+	Commit     string
+	LineNumber int32
+	Preview    string
+	State      ViolationState
+
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+	ResolvedAt  *time.Time
+	// ResolvedByID is the user that resolved the violation, or nil if it was
+	// resolved because the policy query no longer matches it.
+	ResolvedByID *int32
+}
+
+// Finding is a violation found by a run of a policy query.
+type Finding struct {
+	RepoID      api.RepoID
+	Fingerprint string
+	Path        string
+	Commit      string
+	LineNumber  int32
+	Preview     string
+}
+
+// ListViolationsOpts filters the violations returned by ListViolations. Zero
+// values do not filter.
+type ListViolationsOpts struct {
+	PolicyID int32
+	RepoID   api.RepoID
+	State    ViolationState
+	// Limit is the maximum number of violations to return.
+	Limit int
+}
+
+// Store manages code policies and their violations.
+type Store interface {
+	// CreatePolicy creates the policy. Its first run is due immediately.
+	CreatePolicy(ctx context.Context, p *Policy) (*Policy, error)
+	// UpdatePolicy updates the settings of the policy with the ID of p.
+	UpdatePolicy(ctx context.Context, p *Policy) (*Policy, error)
+	// DeletePolicy deletes the policy and its violations.
+	DeletePolicy(ctx context.Context, id int32) error
+	// GetPolicy returns the policy with the given ID, or ErrPolicyNotFound.
+	GetPolicy(ctx context.Context, id int32) (*Policy, error)
+	// ListPolicies returns all policies, ordered by name.
+	ListPolicies(ctx context.Context) ([]*Policy, error)
+
+	// ListViolations returns the violations matching opts, the most recently
+	// seen first.
+	ListViolations(ctx context.Context, opts ListViolationsOpts) ([]*Violation, error)
+	// CountViolations returns the number of violations matching opts,
+	// ignoring its limit.
+	CountViolations(ctx context.Context, opts ListViolationsOpts) (int, error)
+	// ResolveViolation resolves the violation on behalf of the user. Resolved
+	// violations stay resolved for as long as the policy query keeps matching
+	// them.
+	ResolveViolation(ctx context.Context, id, userID int32) (*Violation, error)
+}
+
+type store struct {
+	*basestore.Store
+}
+
+var _ Store = (*store)(nil)
+
+// NewStore returns a Store backed by the given database.
+func NewStore(db database.DB) Store {
+	return newStore(db)
+}
+
+func newStore(db database.DB) *store {
+	return &store{Store: basestore.NewWithHandle(db.Handle())}
+}
+
+func (s *store) transact(ctx context.Context) (*store, error) {
+	tx, err := s.Store.Transact(ctx)
+	return &store{Store: tx}, err
+}
+
+func validatePolicy(p *Policy) error {
+	if p.Name == "" {
+		return errors.New("the name of a code policy must not be empty")
+	}
+	if p.Query == "" {
+		return errors.New("the query of a code policy must not be empty")
+	}
+	if p.Interval < time.Minute {
+		return errors.New("the interval of a code policy must be at least one minute")
+	}
+	return nil
+}
+
+const policyColumns = `
+	id,
+	name,
+	description,
+	query,
+	repo_query,
+	webhook_url,
+	enabled,
+	interval_minutes,
+	creator_id,
+	next_run_at,
+	last_run_at,
+	last_error,
+	created_at,
+	updated_at
+`
+
+const createPolicyFmtstr = `
+INSERT INTO code_policies (name, description, query, repo_query, webhook_url, enabled, interval_minutes, creator_id)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
+RETURNING ` + policyColumns
+
+func (s *store) CreatePolicy(ctx context.Context, p *Policy) (*Policy, error) {
+	if err := validatePolicy(p); err != nil {
+		return nil, err
+	}
+	policy, _, err := scanFirstPolicy(s.Query(ctx, sqlf.Sprintf(
+		createPolicyFmtstr,
+		p.Name,
+		p.Description,
+		p.Query,
+		p.RepoQuery,
+		p.WebhookURL,
+		p.Enabled,
+		int(p.Interval/time.Minute),
+		p.CreatorID,
+	)))
+	return policy, err
+}
+
+const updatePolicyFmtstr = `
+UPDATE code_policies SET
+	name = %s,
+	description = %s,
+	query = %s,
+	repo_query = %s,
+	webhook_url = %s,
+	enabled = %s,
+	interval_minutes = %s,
+	-- Run changed queries immediately, so that the violations match the
+	-- policy.
+	next_run_at = CASE WHEN query <> %s OR repo_query <> %s THEN NOW() ELSE next_run_at END,
+	updated_at = NOW()
+WHERE id = %s
+RETURNING ` + policyColumns
+
+func (s *store) UpdatePolicy(ctx context.Context, p *Policy) (*Policy, error) {
+	if err := validatePolicy(p); err != nil {
+		return nil, err
+	}
+	policy, ok, err := scanFirstPolicy(s.Query(ctx, sqlf.Sprintf(
+		updatePolicyFmtstr,
+		p.Name,
+		p.Description,
+		p.Query,
+		p.RepoQuery,
+		p.WebhookURL,
+		p.Enabled,
+		int(p.Interval/time.Minute),
+		p.Query,
+		p.RepoQuery,
+		p.ID,
+	)))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPolicyNotFound
+	}
+	return policy, nil
+}
+
+func (s *store) DeletePolicy(ctx context.Context, id int32) error {
+	return s.Exec(ctx, sqlf.Sprintf("DELETE FROM code_policies WHERE id = %s", id))
+}
+
+const policiesFmtstr = `
+SELECT ` + policyColumns + `
+FROM code_policies
+WHERE %s
+ORDER BY %s
+%s
+`
+
+func (s *store) GetPolicy(ctx context.Context, id int32) (*Policy, error) {
+	policy, ok, err := scanFirstPolicy(s.Query(ctx, sqlf.Sprintf(policiesFmtstr, sqlf.Sprintf("id = %s", id), sqlf.Sprintf("id"), sqlf.Sprintf(""))))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPolicyNotFound
+	}
+	return policy, nil
+}
+
+func (s *store) ListPolicies(ctx context.Context) ([]*Policy, error) {
+	return scanPolicies(s.Query(ctx, sqlf.Sprintf(policiesFmtstr, sqlf.Sprintf("TRUE"), sqlf.Sprintf("name"), sqlf.Sprintf(""))))
+}
+
+// listDue returns up to limit enabled policies whose query is due to run, the
+// most overdue first.
+func (s *store) listDue(ctx context.Context, limit int) ([]*Policy, error) {
+	return scanPolicies(s.Query(ctx, sqlf.Sprintf(
+		policiesFmtstr,
+		sqlf.Sprintf("enabled AND next_run_at <= NOW()"),
+		sqlf.Sprintf("next_run_at, id"),
+		sqlf.Sprintf("LIMIT %s", limit),
+	)))
+}
+
+const recordRunFmtstr = `
+UPDATE code_policies SET
+	last_run_at = CASE WHEN %s THEN NOW() ELSE last_run_at END,
+	next_run_at = NOW() + interval_minutes * INTERVAL '1 minute',
+	last_error = %s
+WHERE id = %s
+`
+
+// recordRun records the outcome of a run of the policy query and schedules the
+// next run. runErr is nil if the run succeeded.
+func (s *store) recordRun(ctx context.Context, id int32, runErr error) error {
+	var lastError *string
+	if runErr != nil {
+		msg := runErr.Error()
+		lastError = &msg
+	}
+	return s.Exec(ctx, sqlf.Sprintf(recordRunFmtstr, runErr == nil, lastError, id))
+}
+
+const violationColumns = `
+	v.id,
+	v.policy_id,
+	v.repo_id,
+	r.name,
+	v.fingerprint,
+	v.path,
+	v.commit,
+	v.line_number,
+	v.preview,
+	v.state,
+	v.first_seen_at,
+	v.last_seen_at,
+	v.resolved_at,
+	v.resolved_by_id
+`
+
+const violationsFmtstr = `
+SELECT ` + violationColumns + `
+FROM code_policy_violations v
+JOIN repo r ON r.id = v.repo_id
+WHERE r.deleted_at IS NULL AND %s
+ORDER BY v.last_seen_at DESC, v.id DESC
+%s
+`
+
+func (opts ListViolationsOpts) conds() *sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if opts.PolicyID != 0 {
+		conds = append(conds, sqlf.Sprintf("v.policy_id = %s", opts.PolicyID))
+	}
+	if opts.RepoID != 0 {
+		conds = append(conds, sqlf.Sprintf("v.repo_id = %s", opts.RepoID))
+	}
+	if opts.State != "" {
+		conds = append(conds, sqlf.Sprintf("v.state = %s", opts.State))
+	}
+	return sqlf.Join(conds, "AND")
+}
+
+func (s *store) ListViolations(ctx context.Context, opts ListViolationsOpts) ([]*Violation, error) {
+	limitClause := sqlf.Sprintf("")
+	if opts.Limit > 0 {
+		limitClause = sqlf.Sprintf("LIMIT %s", opts.Limit)
+	}
+	return scanViolations(s.Query(ctx, sqlf.Sprintf(violationsFmtstr, opts.conds(), limitClause)))
+}
+
+const countViolationsFmtstr = `
+SELECT COUNT(*)
+FROM code_policy_violations v
+JOIN repo r ON r.id = v.repo_id
+WHERE r.deleted_at IS NULL AND %s
+`
+
+func (s *store) CountViolations(ctx context.Context, opts ListViolationsOpts) (int, error) {
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(countViolationsFmtstr, opts.conds())))
+	return count, err
+}
+
+const resolveViolationFmtstr = `
+UPDATE code_policy_violations SET
+	state = 'resolved',
+	resolved_at = NOW(),
+	resolved_by_id = %s
+WHERE id = %s AND state = 'open'
+`
+
+func (s *store) ResolveViolation(ctx context.Context, id, userID int32) (*Violation, error) {
+	if err := s.Exec(ctx, sqlf.Sprintf(resolveViolationFmtstr, userID, id)); err != nil {
+		return nil, err
+	}
+	violations, err := s.violationsByID(ctx, []int32{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(violations) == 0 {
+		return nil, ErrViolationNotFound
+	}
+	return violations[0], nil
+}
+
+func (s *store) violationsByID(ctx context.Context, ids []int32) ([]*Violation, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return scanViolations(s.Query(ctx, sqlf.Sprintf(violationsFmtstr, sqlf.Sprintf("v.id = ANY(%s)", pq.Array(ids)), sqlf.Sprintf(""))))
+}
+
+const upsertViolationFmtstr = `
+WITH previous AS (
+	SELECT state FROM code_policy_violations
+	WHERE policy_id = %s AND repo_id = %s AND fingerprint = %s
+)
+INSERT INTO code_policy_violations (policy_id, repo_id, fingerprint, path, commit, line_number, preview)
+VALUES (%s, %s, %s, %s, %s, %s, %s)
+ON CONFLICT (policy_id, repo_id, fingerprint) DO UPDATE SET
+	path = EXCLUDED.path,
+	commit = EXCLUDED.commit,
+	line_number = EXCLUDED.line_number,
+	preview = EXCLUDED.preview,
+	last_seen_at = NOW(),
+	-- Violations resolved by a user stay resolved, the others are reopened.
+	state = CASE WHEN code_policy_violations.resolved_by_id IS NULL THEN 'open' ELSE code_policy_violations.state END,
+	resolved_at = CASE WHEN code_policy_violations.resolved_by_id IS NULL THEN NULL ELSE code_policy_violations.resolved_at END
+RETURNING id, state = 'open' AND COALESCE((SELECT state FROM previous), '') <> 'open'
+`
+
+const resolveMissingViolationsFmtstr = `
+UPDATE code_policy_violations SET
+	state = 'resolved',
+	resolved_at = NOW()
+WHERE
+	policy_id = %s AND
+	state = 'open' AND
+	repo_id = ANY(%s) AND
+	-- NOW() is the start of the transaction, so violations that were found by
+	-- this run were last seen at NOW().
+	last_seen_at < NOW()
+RETURNING id
+`
+
+// syncViolations records the findings of a run of the policy query. Findings
+// that are not open violations yet are opened, and open violations in the
+// searched repositories that were not found are resolved. It returns the
+// violations that were opened and resolved.
+func (s *store) syncViolations(ctx context.Context, policyID int32, findings []Finding, searched []api.RepoID) (opened, resolved []*Violation, err error) {
+	tx, err := s.transact(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	var openedIDs []int32
+	for _, f := range findings {
+		u, _, err := scanFirstUpsertedViolation(tx.Query(ctx, sqlf.Sprintf(
+			upsertViolationFmtstr,
+			policyID, f.RepoID, f.Fingerprint,
+			policyID, f.RepoID, f.Fingerprint, f.Path, f.Commit, f.LineNumber, f.Preview,
+		)))
+		if err != nil {
+			return nil, nil, err
+		}
+		if u.opened {
+			openedIDs = append(openedIDs, u.id)
+		}
+	}
+
+	repoIDs := make([]int32, 0, len(searched))
+	for _, id := range searched {
+		repoIDs = append(repoIDs, int32(id))
+	}
+	resolvedIDs, err := basestore.ScanInt32s(tx.Query(ctx, sqlf.Sprintf(resolveMissingViolationsFmtstr, policyID, pq.Array(repoIDs))))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opened, err = tx.violationsByID(ctx, openedIDs); err != nil {
+		return nil, nil, err
+	}
+	if resolved, err = tx.violationsByID(ctx, resolvedIDs); err != nil {
+		return nil, nil, err
+	}
+	return opened, resolved, nil
+}
+
+// upsertedViolation is the outcome of recording a finding. opened is true if
+// the violation was not open before.
+type upsertedViolation struct {
+	id     int32
+	opened bool
+}
+
+func scanUpsertedViolation(sc dbutil.Scanner) (u upsertedViolation, err error) {
+	err = sc.Scan(&u.id, &u.opened)
+	return u, err
+}
+
+func scanPolicy(sc dbutil.Scanner) (*Policy, error) {
+	var (
+		p               Policy
+		intervalMinutes int
+	)
+	if err := sc.Scan(
+		&p.ID,
+		&p.Name,
+		&p.Description,
+		&p.Query,
+		&p.RepoQuery,
+		&p.WebhookURL,
+		&p.Enabled,
+		&intervalMinutes,
+		&p.CreatorID,
+		&p.NextRunAt,
+		&p.LastRunAt,
+		&p.LastError,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	p.Interval = time.Duration(intervalMinutes) * time.Minute
+	return &p, nil
+}
+
+func scanViolation(sc dbutil.Scanner) (*Violation, error) {
+	var v Violation
+	if err := sc.Scan(
+		&v.ID,
+		&v.PolicyID,
+		&v.RepoID,
+		&v.RepoName,
+		&v.Fingerprint,
+		&v.Path,
+		&v.Commit,
+		&v.LineNumber,
+		&v.Preview,
+		&v.State,
+		&v.FirstSeenAt,
+		&v.LastSeenAt,
+		&v.ResolvedAt,
+		&v.ResolvedByID,
+	); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+var (
+	scanPolicies    = basestore.NewSliceScanner(scanPolicy)
+	scanFirstPolicy = basestore.NewFirstScanner(scanPolicy)
+	scanViolations  = basestore.NewSliceScanner(scanViolation)
+
+	scanFirstUpsertedViolation = basestore.NewFirstScanner(scanUpsertedViolation)
+)
@@ -0,0 +1,122 @@
+package codepolicies
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+
+	user, err := db.Users().Create(ctx, database.NewUser{Username: "admin"})
+	require.NoError(t, err)
+	require.NoError(t, db.Repos().Create(ctx, &types.Repo{Name: "r1"}, &types.Repo{Name: "r2"}))
+	r1, err := db.Repos().GetByName(ctx, "r1")
+	require.NoError(t, err)
+	r2, err := db.Repos().GetByName(ctx, "r2")
+	require.NoError(t, err)
+
+	s := newStore(db)
+
+	_, err = s.CreatePolicy(ctx, &Policy{Name: "no-md5", Query: "md5.New", Interval: time.Second})
+	require.Error(t, err)
+
+	p, err := s.CreatePolicy(ctx, &Policy{
+		Name:      "no-md5",
+		Query:     "md5.New",
+		RepoQuery: "repo:^r",
+		Enabled:   true,
+		Interval:  time.Hour,
+		CreatorID: &user.ID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, p.Interval)
+	assert.Nil(t, p.LastRunAt)
+
+	due, err := s.listDue(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+
+	require.NoError(t, s.recordRun(ctx, p.ID, nil))
+	due, err = s.listDue(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, due)
+	p, err = s.GetPolicy(ctx, p.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, p.LastRunAt)
+
+	// Changing the query of the policy runs it again immediately.
+	p.Query = "md5.Sum"
+	_, err = s.UpdatePolicy(ctx, p)
+	require.NoError(t, err)
+	due, err = s.listDue(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+
+	t.Run("syncViolations", func(t *testing.T) {
+		a := Finding{RepoID: r1.ID, Fingerprint: "a", Path: "a.go", LineNumber: 1}
+		b := Finding{RepoID: r1.ID, Fingerprint: "b", Path: "b.go", LineNumber: 2}
+		c := Finding{RepoID: r2.ID, Fingerprint: "c", Path: "c.go", LineNumber: 3}
+
+		opened, resolved, err := s.syncViolations(ctx, p.ID, []Finding{a, b, c}, []api.RepoID{r1.ID, r2.ID})
+		require.NoError(t, err)
+		assert.Len(t, opened, 3)
+		assert.Empty(t, resolved)
+
+		// Only missing violations in completely searched repositories are
+		// resolved.
+		opened, resolved, err = s.syncViolations(ctx, p.ID, []Finding{a}, []api.RepoID{r1.ID})
+		require.NoError(t, err)
+		assert.Empty(t, opened)
+		require.Len(t, resolved, 1)
+		assert.Equal(t, "b", resolved[0].Fingerprint)
+		assert.Equal(t, api.RepoName("r1"), resolved[0].RepoName)
+		assert.NotNil(t, resolved[0].ResolvedAt)
+
+		// Violations that are found again are reopened.
+		opened, _, err = s.syncViolations(ctx, p.ID, []Finding{a, b}, []api.RepoID{r1.ID})
+		require.NoError(t, err)
+		require.Len(t, opened, 1)
+		assert.Equal(t, "b", opened[0].Fingerprint)
+
+		count, err := s.CountViolations(ctx, ListViolationsOpts{PolicyID: p.ID, State: ViolationStateOpen})
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+		violations, err := s.ListViolations(ctx, ListViolationsOpts{RepoID: r2.ID})
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+		assert.Equal(t, "c", violations[0].Fingerprint)
+
+		// Violations resolved by a user stay resolved.
+		v, err := s.ResolveViolation(ctx, violations[0].ID, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, ViolationStateResolved, v.State)
+		assert.Equal(t, user.ID, *v.ResolvedByID)
+		opened, _, err = s.syncViolations(ctx, p.ID, []Finding{c}, []api.RepoID{r2.ID})
+		require.NoError(t, err)
+		assert.Empty(t, opened)
+
+		_, err = s.ResolveViolation(ctx, 12345, user.ID)
+		require.ErrorIs(t, err, ErrViolationNotFound)
+	})
+
+	require.NoError(t, s.DeletePolicy(ctx, p.ID))
+	_, err = s.GetPolicy(ctx, p.ID)
+	require.ErrorIs(t, err, ErrPolicyNotFound)
+}
@@ -0,0 +1,283 @@
+package cody
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+var contextFilterDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_cody_context_filter_decisions_total",
+	Help: "Number of decisions of the Cody context filters on whether the content of a repository may be sent to the LLM.",
+}, []string{"where", "decision"})
+
+// ContextFilters decide whether the content of a repository may be sent to the LLM
+// as context for Cody. They combine the filters of the site configuration with the
+// filters of the organizations of the current user, and a repository must pass all
+// of them.
+type ContextFilters struct {
+	logger  log.Logger
+	db      database.DB
+	filters []contextFilter
+
+	mu sync.Mutex
+	// denied holds the repositories whose denial has been recorded already.
+	denied map[api.RepoName]struct{}
+}
+
+type contextFilter struct {
+	// name identifies where the filter is configured, such as "site" or "org:acme".
+	name    string
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// GetContextFilters returns the context filters that apply to the actor of the
+// context. Unauthenticated actors are only subject to the filters of the site
+// configuration.
+func GetContextFilters(ctx context.Context, db database.DB, logger log.Logger) (*ContextFilters, error) {
+	logger = logger.Scoped("contextFilters", "decides which repositories may be sent as context")
+	f := &ContextFilters{logger: logger, db: db, denied: map[api.RepoName]struct{}{}}
+
+	if cfg := conf.Get().SiteConfig().CodyContextFilters; cfg != nil {
+		f.add(logger, "site", cfg.Include, cfg.Exclude)
+	}
+
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return f, nil
+	}
+
+	// Org filters are read from the settings of each organization rather than
+	// the settings cascade, so that users cannot override them in their own
+	// settings.
+	orgs, err := db.Orgs().GetByUserID(ctx, a.UID)
+	if err != nil {
+		return nil, err
+	}
+	if len(orgs) == 0 {
+		return f, nil
+	}
+	orgIDs := make([]int32, 0, len(orgs))
+	for _, org := range orgs {
+		orgIDs = append(orgIDs, org.ID)
+	}
+	settingsIDs, err := db.Settings().GetLatestOrgIDs(ctx, orgIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, org := range orgs {
+		settingsID, ok := settingsIDs[org.ID]
+		if !ok {
+			continue
+		}
+		filter, err := orgContextFilters.get(ctx, db, logger, org.ID, settingsID)
+		if err != nil {
+			return nil, err
+		}
+		if filter != nil {
+			filter.name = "org:" + org.Name
+			f.filters = append(f.filters, *filter)
+		}
+	}
+
+	return f, nil
+}
+
+// orgContextFilters caches the context filters compiled from the settings of
+// each organization. Every completion and context request is subject to the
+// filters of all organizations of the user, so their settings are only read
+// and compiled again once they have changed.
+var orgContextFilters = newOrgContextFiltersCache()
+
+type orgContextFiltersCache struct {
+	mu    sync.Mutex
+	byOrg map[int32]cachedOrgContextFilter
+}
+
+type cachedOrgContextFilter struct {
+	// settingsID is the ID of the settings the filter was compiled from. The
+	// settings of an organization get a new ID whenever they are changed.
+	settingsID int32
+	// filter is nil if the settings have no context filters.
+	filter *contextFilter
+}
+
+func newOrgContextFiltersCache() *orgContextFiltersCache {
+	return &orgContextFiltersCache{byOrg: map[int32]cachedOrgContextFilter{}}
+}
+
+// get returns a copy of the context filter of the organization, compiled from
+// its settings with the given ID, or nil if the settings have no context
+// filters.
+func (c *orgContextFiltersCache) get(ctx context.Context, db database.DB, logger log.Logger, orgID, settingsID int32) (*contextFilter, error) {
+	c.mu.Lock()
+	cached, ok := c.byOrg[orgID]
+	c.mu.Unlock()
+	if !ok || cached.settingsID != settingsID {
+		settings, err := db.Settings().GetLatest(ctx, api.SettingsSubject{Org: &orgID})
+		if err != nil {
+			return nil, err
+		}
+		cached = cachedOrgContextFilter{settingsID: settingsID}
+		if settings != nil {
+			var orgSettings schema.Settings
+			if err := jsonc.Unmarshal(settings.Contents, &orgSettings); err != nil {
+				return nil, err
+			}
+			if cfg := orgSettings.CodyContextFilters; cfg != nil {
+				filter := compileContextFilter(logger, fmt.Sprintf("org:%d", orgID), cfg.Include, cfg.Exclude)
+				cached.filter = &filter
+			}
+			// The settings may have changed since their ID was read.
+			cached.settingsID = settings.ID
+		}
+
+		c.mu.Lock()
+		c.byOrg[orgID] = cached
+		c.mu.Unlock()
+	}
+
+	if cached.filter == nil {
+		return nil, nil
+	}
+	filter := *cached.filter
+	return &filter, nil
+}
+
+func (f *ContextFilters) add(logger log.Logger, name string, include, exclude []string) {
+	f.filters = append(f.filters, compileContextFilter(logger, name, include, exclude))
+}
+
+func compileContextFilter(logger log.Logger, name string, include, exclude []string) contextFilter {
+	compile := func(patterns []string) []*regexp.Regexp {
+		var res []*regexp.Regexp
+		for _, p := range patterns {
+			pattern, err := regexp.Compile(p)
+			if err != nil {
+				logger.Warn("skipping invalid context filter pattern", log.String("filter", name), log.Error(err))
+				continue
+			}
+			res = append(res, pattern)
+		}
+		return res
+	}
+
+	filter := contextFilter{name: name, include: compile(include), exclude: compile(exclude)}
+	if len(include) > 0 && len(filter.include) == 0 {
+		// None of the include patterns are valid. Include nothing rather than
+		// everything.
+		filter.include = []*regexp.Regexp{regexp.MustCompile(`$^`)}
+	}
+	return filter
+}
+
+// deniedBy returns the name of the first filter that denies the repository, or the
+// empty string if the repository is allowed.
+func (f *ContextFilters) deniedBy(repo api.RepoName) string {
+	for _, filter := range f.filters {
+		if len(filter.include) > 0 && !matchAny(filter.include, string(repo)) {
+			return filter.name
+		}
+		if matchAny(filter.exclude, string(repo)) {
+			return filter.name
+		}
+	}
+	return ""
+}
+
+func matchAny(patterns []*regexp.Regexp, s string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allowed reports whether the content of the repository may be sent to the LLM.
+// Where describes the request path making the decision, such as "context" or
+// "completions". Decisions are counted, and every denied repository is recorded as
+// a security event once.
+func (f *ContextFilters) Allowed(ctx context.Context, where string, repo api.RepoName) bool {
+	if len(f.filters) == 0 {
+		contextFilterDecisions.WithLabelValues(where, "allowed").Inc()
+		return true
+	}
+
+	filter := f.deniedBy(repo)
+	if filter == "" {
+		contextFilterDecisions.WithLabelValues(where, "allowed").Inc()
+		f.logger.Debug("repository allowed", log.String("where", where), log.String("repo", string(repo)))
+		return true
+	}
+
+	contextFilterDecisions.WithLabelValues(where, "denied").Inc()
+	f.logger.Debug("repository denied",
+		log.String("where", where),
+		log.String("repo", string(repo)),
+		log.String("filter", filter),
+	)
+	f.mu.Lock()
+	_, recorded := f.denied[repo]
+	f.denied[repo] = struct{}{}
+	f.mu.Unlock()
+	if !recorded {
+		f.recordDenial(ctx, where, repo, filter)
+	}
+	return false
+}
+
+// FilterRepos returns the repositories whose content may be sent to the LLM.
+func (f *ContextFilters) FilterRepos(ctx context.Context, where string, repos []types.RepoIDName) []types.RepoIDName {
+	allowed := make([]types.RepoIDName, 0, len(repos))
+	for _, repo := range repos {
+		if f.Allowed(ctx, where, repo.Name) {
+			allowed = append(allowed, repo)
+		}
+	}
+	return allowed
+}
+
+func (f *ContextFilters) recordDenial(ctx context.Context, where string, repo api.RepoName, filter string) {
+	argument, err := json.Marshal(struct {
+		Where  string `json:"where"`
+		Repo   string `json:"repo"`
+		Filter string `json:"filter"`
+	}{where, string(repo), filter})
+	if err != nil {
+		f.logger.Warn("failed to marshal context filter decision", log.Error(err))
+		return
+	}
+
+	a := actor.FromContext(ctx)
+	event := &database.SecurityEvent{
+		Name:      database.SecurityEventCodyContextFiltered,
+		UserID:    uint32(a.UID),
+		Argument:  argument,
+		Source:    "BACKEND",
+		Timestamp: time.Now(),
+	}
+	if !a.IsAuthenticated() {
+		event.AnonymousUserID = a.AnonymousUID
+		if event.AnonymousUserID == "" {
+			event.AnonymousUserID = "backend"
+		}
+	}
+	f.db.SecurityEventLogs().LogEvent(ctx, event)
+}
@@ -0,0 +1,105 @@
+package cody
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestContextFilters(t *testing.T) {
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			CodyContextFilters: &schema.CodyContextFilters{
+				Include: []string{`^github\.com/acme/`},
+				Exclude: []string{`^github\.com/acme/secrets-`},
+			},
+		},
+	})
+	t.Cleanup(func() { conf.Mock(nil) })
+	orgContextFilters = newOrgContextFiltersCache()
+
+	orgs := database.NewMockOrgStore()
+	orgs.GetByUserIDFunc.SetDefaultReturn([]*types.Org{{ID: 1, Name: "legal"}, {ID: 2, Name: "open"}}, nil)
+	settings := database.NewMockSettingsStore()
+	settings.GetLatestOrgIDsFunc.SetDefaultReturn(map[int32]int32{1: 10}, nil)
+	settings.GetLatestFunc.SetDefaultHook(func(_ context.Context, subject api.SettingsSubject) (*api.Settings, error) {
+		if *subject.Org == 1 {
+			return &api.Settings{ID: 10, Contents: `{"cody.contextFilters": {"exclude": ["contracts"]}}`}, nil
+		}
+		return nil, nil
+	})
+	securityEvents := database.NewMockSecurityEventLogsStore()
+	db := database.NewMockDB()
+	db.OrgsFunc.SetDefaultReturn(orgs)
+	db.SettingsFunc.SetDefaultReturn(settings)
+	db.SecurityEventLogsFunc.SetDefaultReturn(securityEvents)
+
+	logger := logtest.Scoped(t)
+
+	t.Run("authenticated", func(t *testing.T) {
+		ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+		filters, err := GetContextFilters(ctx, db, logger)
+		require.NoError(t, err)
+
+		assert.True(t, filters.Allowed(ctx, "context", "github.com/acme/api"))
+		assert.False(t, filters.Allowed(ctx, "context", "github.com/other/api"))
+		assert.False(t, filters.Allowed(ctx, "context", "github.com/acme/secrets-prod"))
+		assert.False(t, filters.Allowed(ctx, "context", "github.com/acme/contracts"))
+
+		repos := []types.RepoIDName{{ID: 1, Name: "github.com/acme/api"}, {ID: 2, Name: "github.com/acme/contracts"}}
+		assert.Equal(t, repos[:1], filters.FilterRepos(ctx, "context", repos))
+
+		// Every denied repository is recorded once.
+		assert.Len(t, securityEvents.LogEventFunc.History(), 3)
+	})
+
+	t.Run("org filters are cached until the settings change", func(t *testing.T) {
+		ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+		calls := len(settings.GetLatestFunc.History())
+
+		filters, err := GetContextFilters(ctx, db, logger)
+		require.NoError(t, err)
+		assert.False(t, filters.Allowed(ctx, "context", "github.com/acme/contracts"))
+		assert.Len(t, settings.GetLatestFunc.History(), calls)
+
+		settings.GetLatestOrgIDsFunc.PushReturn(map[int32]int32{1: 11}, nil)
+		settings.GetLatestFunc.PushReturn(&api.Settings{ID: 11, Contents: `{}`}, nil)
+		filters, err = GetContextFilters(ctx, db, logger)
+		require.NoError(t, err)
+		assert.True(t, filters.Allowed(ctx, "context", "github.com/acme/contracts"))
+		assert.Len(t, settings.GetLatestFunc.History(), calls+1)
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		ctx := actor.WithActor(context.Background(), actor.FromAnonymousUser("anon"))
+		filters, err := GetContextFilters(ctx, db, logger)
+		require.NoError(t, err)
+
+		assert.True(t, filters.Allowed(ctx, "context", "github.com/acme/contracts"))
+		assert.False(t, filters.Allowed(ctx, "context", "github.com/acme/secrets-prod"))
+	})
+
+	t.Run("invalid include patterns", func(t *testing.T) {
+		conf.Mock(&conf.Unified{
+			SiteConfiguration: schema.SiteConfiguration{
+				CodyContextFilters: &schema.CodyContextFilters{Include: []string{`(`}},
+			},
+		})
+
+		ctx := actor.WithActor(context.Background(), actor.FromAnonymousUser("anon"))
+		filters, err := GetContextFilters(ctx, db, logger)
+		require.NoError(t, err)
+
+		assert.False(t, filters.Allowed(ctx, "context", "github.com/acme/api"))
+	})
+}
@@ -0,0 +1,337 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/sourcegraph/conc/pool"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// CodeGraphClient looks up the neighbors of symbols in the precise code graph.
+type CodeGraphClient interface {
+	// Neighbors returns chunks containing the callers and callees of the symbol defined
+	// at the given (zero-based) position, at most limit of them.
+	Neighbors(ctx context.Context, repo types.RepoIDName, commit api.CommitID, path string, line, character, limit int) ([]FileChunkContext, error)
+}
+
+// symbolDefinition is the location of the definition of a symbol mentioned in the prompt.
+type symbolDefinition struct {
+	repo      types.RepoIDName
+	commit    api.CommitID
+	path      string
+	line      int
+	character int
+}
+
+// getCodeGraphContext returns the definitions of the symbols mentioned in the query,
+// followed by their callers and callees.
+func (c *CodyContextClient) getCodeGraphContext(ctx context.Context, args GetContextArgs, maxSymbols int) (_ []FileChunkContext, err error) {
+	ctx, _, endObservation := c.getCodeGraphContextOp.With(ctx, &err, observation.Args{Attrs: args.Attrs()})
+	defer endObservation(1, observation.Args{})
+
+	if len(args.Repos) == 0 || args.CodeResultsCount == 0 {
+		return nil, nil
+	}
+
+	names := symbolCandidates(args.Query, maxSymbols)
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	definitions, err := c.findDefinitions(ctx, args.Repos, names)
+	if err != nil || len(definitions) == 0 {
+		return nil, err
+	}
+
+	// Share the result budget between the symbols. Each symbol also contributes the
+	// chunk of its definition.
+	limit := max(1, int(args.CodeResultsCount)/len(definitions)-1)
+
+	results := make([][]FileChunkContext, len(definitions))
+	p := pool.New().WithMaxGoroutines(4).WithContext(ctx)
+	for i, definition := range definitions {
+		i, definition := i, definition
+		p.Go(func(ctx context.Context) error {
+			neighbors, err := c.codeGraphClient.Neighbors(ctx, definition.repo, definition.commit, definition.path, definition.line, definition.character, limit)
+			if err != nil {
+				return err
+			}
+
+			results[i] = append(
+				[]FileChunkContext{chunkAround(definition.repo, definition.commit, definition.path, definition.line)},
+				neighbors...,
+			)
+			return nil
+		})
+	}
+	if err := p.Wait(); err != nil {
+		return nil, err
+	}
+
+	// Interleave the results of the symbols, so that every symbol is represented among
+	// the best ranks.
+	var interleaved []FileChunkContext
+	for rank := 0; ; rank++ {
+		visited := false
+		for _, chunks := range results {
+			if rank < len(chunks) {
+				interleaved = append(interleaved, chunks[rank])
+				visited = true
+			}
+		}
+		if !visited {
+			return interleaved, nil
+		}
+	}
+}
+
+// findDefinitions uses symbol search to find the definitions of the given symbols. At most
+// one definition is returned per symbol, in the order of the given names.
+func (c *CodyContextClient) findDefinitions(ctx context.Context, repos []types.RepoIDName, names []string) ([]symbolDefinition, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	regexEscapedRepoNames := make([]string, len(repos))
+	for i, repo := range repos {
+		regexEscapedRepoNames[i] = regexp.QuoteMeta(string(repo.Name))
+	}
+	symbolQuery := fmt.Sprintf(`repo:^%s$ type:symbol count:%d ^%s$`, query.UnionRegExps(regexEscapedRepoNames), 10*len(names), query.UnionRegExps(names))
+
+	patternTypeRegexp := "regexp"
+	plan, err := c.searchClient.Plan(
+		ctx,
+		"V3",
+		&patternTypeRegexp,
+		symbolQuery,
+		search.Precise,
+		search.Streaming,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		wanted[name] = struct{}{}
+	}
+
+	var (
+		mu    sync.Mutex
+		found = map[string]symbolDefinition{}
+	)
+	stream := streaming.StreamFunc(func(e streaming.SearchEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, res := range e.Results {
+			fm, ok := res.(*result.FileMatch)
+			if !ok {
+				continue
+			}
+
+			for _, sm := range fm.Symbols {
+				if _, ok := wanted[sm.Symbol.Name]; !ok {
+					continue
+				}
+				if _, ok := found[sm.Symbol.Name]; ok {
+					continue
+				}
+				found[sm.Symbol.Name] = symbolDefinition{
+					repo:      types.RepoIDName{ID: fm.Repo.ID, Name: fm.Repo.Name},
+					commit:    fm.CommitID,
+					path:      fm.Path,
+					line:      sm.Symbol.Line - 1,
+					character: sm.Symbol.Character,
+				}
+			}
+		}
+		if len(found) >= len(names) {
+			cancel()
+		}
+	})
+
+	alert, err := c.searchClient.Execute(ctx, stream, plan)
+	if err != nil && ctx.Err() == nil {
+		return nil, err
+	}
+	if alert != nil {
+		c.obsCtx.Logger.Warn("received alert from symbol search execution",
+			log.String("title", alert.Title),
+			log.String("description", alert.Description),
+		)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	definitions := make([]symbolDefinition, 0, len(found))
+	for _, name := range names {
+		if definition, ok := found[name]; ok {
+			definitions = append(definitions, definition)
+		}
+	}
+	return definitions, nil
+}
+
+var identifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// symbolCandidates returns the words of the query that look like the names of symbols,
+// at most limit of them. Plain words are skipped; only words written in camel case or
+// snake case, or that are followed by a call, are considered symbol names.
+func symbolCandidates(q string, limit int) []string {
+	var candidates []string
+	seen := map[string]struct{}{}
+	for _, loc := range identifierPattern.FindAllStringIndex(q, -1) {
+		if len(candidates) >= limit {
+			break
+		}
+
+		word := q[loc[0]:loc[1]]
+		if len(word) < 3 {
+			continue
+		}
+		if _, ok := seen[word]; ok {
+			continue
+		}
+
+		isCall := strings.HasPrefix(q[loc[1]:], "(")
+		if !isCall && !strings.Contains(strings.Trim(word, "_"), "_") && !hasInnerUpper(word) {
+			continue
+		}
+
+		seen[word] = struct{}{}
+		candidates = append(candidates, word)
+	}
+	return candidates
+}
+
+// hasInnerUpper returns true if a letter other than the first one is upper case.
+func hasInnerUpper(word string) bool {
+	for i, r := range word {
+		if i > 0 && unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// codeGraphNeighborLines is the number of lines after the definition of a symbol that
+// are searched for references to callees.
+const codeGraphNeighborLines = 50
+
+type codenavCodeGraphClient struct {
+	db              database.DB
+	codenavSvc      *codenav.Service
+	gitserverClient gitserver.Client
+	hunkCache       codenav.HunkCache
+}
+
+// NewCodenavCodeGraphClient returns a CodeGraphClient backed by the precise code
+// intelligence of the code navigation service.
+func NewCodenavCodeGraphClient(db database.DB, codenavSvc *codenav.Service, gitserverClient gitserver.Client) (CodeGraphClient, error) {
+	hunkCache, err := codenav.NewHunkCache(1000)
+	if err != nil {
+		return nil, err
+	}
+
+	return &codenavCodeGraphClient{
+		db:              db,
+		codenavSvc:      codenavSvc,
+		gitserverClient: gitserverClient,
+		hunkCache:       hunkCache,
+	}, nil
+}
+
+func (c *codenavCodeGraphClient) Neighbors(ctx context.Context, repo types.RepoIDName, commit api.CommitID, path string, line, character, limit int) ([]FileChunkContext, error) {
+	uploads, err := c.codenavSvc.GetClosestDumpsForBlob(ctx, int(repo.ID), string(commit), path, true, "")
+	if err != nil || len(uploads) == 0 {
+		return nil, err
+	}
+
+	requestState := codenav.NewRequestState(
+		uploads,
+		c.db.Repos(),
+		authz.DefaultSubRepoPermsChecker,
+		c.gitserverClient,
+		&types.Repo{ID: repo.ID, Name: repo.Name},
+		string(commit),
+		path,
+		500,
+		c.hunkCache,
+	)
+	args := codenav.PositionalRequestArgs{
+		RequestArgs: codenav.RequestArgs{
+			RepositoryID: int(repo.ID),
+			Commit:       string(commit),
+			Limit:        limit,
+		},
+		Path:      path,
+		Line:      line,
+		Character: character,
+	}
+
+	// The callers of the symbol are its references.
+	references, _, err := c.codenavSvc.GetReferences(ctx, args, requestState, codenav.ReferencesCursor{Phase: "local"})
+	if err != nil {
+		return nil, err
+	}
+
+	// The callees of the symbol are the definitions of the symbols referenced after its
+	// definition. The body of the symbol is not known, so the following lines are used
+	// as an approximation.
+	ranges, err := c.codenavSvc.GetRanges(ctx, args, requestState, line, line+codeGraphNeighborLines)
+	if err != nil {
+		return nil, err
+	}
+
+	var callers, callees []FileChunkContext
+	for _, reference := range references {
+		if reference.Path == path && reference.TargetRange.Start.Line == line {
+			// Skip the definition itself.
+			continue
+		}
+		callers = append(callers, uploadLocationChunk(reference.Dump.RepositoryID, reference.Dump.RepositoryName, reference.TargetCommit, reference.Path, reference.TargetRange.Start.Line))
+	}
+	for _, r := range ranges {
+		for _, definition := range r.Definitions {
+			if definition.Path == path && definition.TargetRange.Start.Line >= line && definition.TargetRange.Start.Line <= line+codeGraphNeighborLines {
+				// Skip definitions within the symbol itself.
+				continue
+			}
+			callees = append(callees, uploadLocationChunk(definition.Dump.RepositoryID, definition.Dump.RepositoryName, definition.TargetCommit, definition.Path, definition.TargetRange.Start.Line))
+		}
+	}
+
+	// Alternate between callers and callees, as both are equally relevant.
+	var neighbors []FileChunkContext
+	for i := 0; len(neighbors) < limit && (i < len(callers) || i < len(callees)); i++ {
+		if i < len(callers) {
+			neighbors = append(neighbors, callers[i])
+		}
+		if i < len(callees) && len(neighbors) < limit {
+			neighbors = append(neighbors, callees[i])
+		}
+	}
+	return neighbors, nil
+}
+
+func uploadLocationChunk(repoID int, repoName, commit, path string, line int) FileChunkContext {
+	return chunkAround(types.RepoIDName{ID: api.RepoID(repoID), Name: api.RepoName(repoName)}, api.CommitID(commit), path, line)
+}
@@ -14,6 +14,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/cody"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/embeddings"
 	"github.com/sourcegraph/sourcegraph/internal/embeddings/embed"
@@ -36,7 +38,9 @@ type FileChunkContext struct {
 	EndLine   int
 }
 
-func NewCodyContextClient(obsCtx *observation.Context, db database.DB, embeddingsClient embeddings.Client, searchClient client.SearchClient) *CodyContextClient {
+// NewCodyContextClient returns a client that retrieves context for Cody. The code graph
+// client is optional; without it, no code graph results are retrieved.
+func NewCodyContextClient(obsCtx *observation.Context, db database.DB, embeddingsClient embeddings.Client, searchClient client.SearchClient, codeGraphClient CodeGraphClient) *CodyContextClient {
 	redMetrics := metrics.NewREDMetrics(
 		obsCtx.Registerer,
 		"codycontext_client",
@@ -58,11 +62,13 @@ func NewCodyContextClient(obsCtx *observation.Context, db database.DB, embedding
 		db:               db,
 		embeddingsClient: embeddingsClient,
 		searchClient:     searchClient,
+		codeGraphClient:  codeGraphClient,
 
 		obsCtx:                 obsCtx,
 		getCodyContextOp:       op("getCodyContext"),
 		getEmbeddingsContextOp: op("getEmbeddingsContext"),
 		getKeywordContextOp:    op("getKeywordContext"),
+		getCodeGraphContextOp:  op("getCodeGraphContext"),
 	}
 }
 
@@ -70,11 +76,13 @@ type CodyContextClient struct {
 	db               database.DB
 	embeddingsClient embeddings.Client
 	searchClient     client.SearchClient
+	codeGraphClient  CodeGraphClient
 
 	obsCtx                 *observation.Context
 	getCodyContextOp       *observation.Operation
 	getEmbeddingsContextOp *observation.Operation
 	getKeywordContextOp    *observation.Operation
+	getCodeGraphContextOp  *observation.Operation
 }
 
 type GetContextArgs struct {
@@ -97,51 +105,93 @@ func (c *CodyContextClient) GetCodyContext(ctx context.Context, args GetContextA
 	ctx, _, endObservation := c.getCodyContextOp.With(ctx, &err, observation.Args{Attrs: args.Attrs()})
 	defer endObservation(1, observation.Args{})
 
-	embeddingRepos, keywordRepos, err := c.partitionRepos(ctx, args.Repos)
+	retrievalConfig := getRetrievalConfig(conf.Get().CodyContextRetrieval)
+
+	// 🚨 SECURITY: The content of repos denied by the context filters must never be
+	// sent to the LLM, so they are neither searched nor returned.
+	filters, err := cody.GetContextFilters(ctx, c.db, c.obsCtx.Logger)
 	if err != nil {
 		return nil, err
 	}
+	args.Repos = filters.FilterRepos(ctx, "context", args.Repos)
 
-	// NOTE: We use a pretty simple heuristic for combining results from
-	// embeddings and keyword search. We use the ratio of repos with embeddings
-	// to decide how many results out of our limit should be reserved for
-	// embeddings results. We can't easily compare the scores between embeddings
-	// and keyword search.
-	embeddingsResultRatio := float32(len(embeddingRepos)) / float32(len(args.Repos))
-
-	embeddingsArgs := GetContextArgs{
-		Repos:            embeddingRepos,
-		Query:            args.Query,
-		CodeResultsCount: int32(float32(args.CodeResultsCount) * embeddingsResultRatio),
-		TextResultsCount: int32(float32(args.TextResultsCount) * embeddingsResultRatio),
-	}
-	keywordArgs := GetContextArgs{
-		Repos: keywordRepos,
-		Query: args.Query,
-		// Assign the remaining result budget to keyword search
-		CodeResultsCount: args.CodeResultsCount - embeddingsArgs.CodeResultsCount,
-		TextResultsCount: args.TextResultsCount - embeddingsArgs.TextResultsCount,
+	embeddingRepos, _, err := c.partitionRepos(ctx, args.Repos)
+	if err != nil {
+		return nil, err
 	}
 
-	var embeddingsResults, keywordResults []FileChunkContext
+	// Every source is asked for the full result budget, and their results are merged
+	// afterwards. This way the best results of each source make the cut, no matter how
+	// many repos have embeddings. Scores of the different sources are not comparable,
+	// so results are merged by their rank within each source instead.
+	embeddingsResults := sourceResults{source: SourceEmbeddings, weight: retrievalConfig.embeddingsWeight}
+	keywordResults := sourceResults{source: SourceKeyword, weight: retrievalConfig.keywordWeight}
+	codeGraphResults := sourceResults{source: SourceCodeGraph, weight: retrievalConfig.codeGraphWeight}
 
-	// Fetch keyword results and embeddings results concurrently
+	// Fetch the results of all enabled sources concurrently
 	p := pool.New().WithErrors()
-	p.Go(func() (err error) {
-		embeddingsResults, err = c.getEmbeddingsContext(ctx, embeddingsArgs)
-		return err
-	})
-	p.Go(func() (err error) {
-		keywordResults, err = c.getKeywordContext(ctx, keywordArgs)
-		return err
-	})
+	if embeddingsResults.weight > 0 {
+		p.Go(func() error {
+			return embeddingsResults.fetch(func() ([]FileChunkContext, []FileChunkContext, error) {
+				return c.getEmbeddingsContext(ctx, GetContextArgs{
+					Repos:            embeddingRepos,
+					Query:            args.Query,
+					CodeResultsCount: args.CodeResultsCount,
+					TextResultsCount: args.TextResultsCount,
+				})
+			})
+		})
+	}
+	if keywordResults.weight > 0 {
+		p.Go(func() error {
+			return keywordResults.fetch(func() ([]FileChunkContext, []FileChunkContext, error) {
+				return c.getKeywordContext(ctx, args)
+			})
+		})
+	}
+	if codeGraphResults.weight > 0 && c.codeGraphClient != nil {
+		p.Go(func() error {
+			err := codeGraphResults.fetch(func() ([]FileChunkContext, []FileChunkContext, error) {
+				code, err := c.getCodeGraphContext(ctx, args, retrievalConfig.codeGraphMaxSymbols)
+				return code, nil, err
+			})
+			if err != nil {
+				// Precise code intelligence is not available for most repositories, so
+				// code graph results are best-effort and never fail the request.
+				c.obsCtx.Logger.Warn("failed to fetch code graph context", log.Error(err))
+			}
+			return nil
+		})
+	}
 
 	err = p.Wait()
 	if err != nil {
 		return nil, err
 	}
 
-	return append(embeddingsResults, keywordResults...), nil
+	sources := []*sourceResults{&embeddingsResults, &keywordResults, &codeGraphResults}
+	for _, r := range sources {
+		// Code graph results may come from repos other than the requested ones, such
+		// as the repos of dependencies.
+		r.code = filterChunks(ctx, filters, r.code)
+		r.text = filterChunks(ctx, filters, r.text)
+	}
+	code := fuseResults(int(args.CodeResultsCount), sources, func(r *sourceResults) []FileChunkContext { return r.code })
+	text := fuseResults(int(args.TextResultsCount), sources, func(r *sourceResults) []FileChunkContext { return r.text })
+	c.recordRetrieval(args, sources, code, text)
+
+	return append(code.chunks, text.chunks...), nil
+}
+
+// filterChunks returns the chunks of the repos allowed by the context filters.
+func filterChunks(ctx context.Context, filters *cody.ContextFilters, chunks []FileChunkContext) []FileChunkContext {
+	allowed := chunks[:0]
+	for _, chunk := range chunks {
+		if filters.Allowed(ctx, "context", chunk.RepoName) {
+			allowed = append(allowed, chunk)
+		}
+	}
+	return allowed
 }
 
 // partitionRepos splits a set of repos into repos with embeddings and repos without embeddings
@@ -161,13 +211,13 @@ func (c *CodyContextClient) partitionRepos(ctx context.Context, input []types.Re
 	return embedded, notEmbedded, nil
 }
 
-func (c *CodyContextClient) getEmbeddingsContext(ctx context.Context, args GetContextArgs) (_ []FileChunkContext, err error) {
+func (c *CodyContextClient) getEmbeddingsContext(ctx context.Context, args GetContextArgs) (code, text []FileChunkContext, err error) {
 	ctx, _, endObservation := c.getEmbeddingsContextOp.With(ctx, &err, observation.Args{Attrs: args.Attrs()})
 	defer endObservation(1, observation.Args{})
 
 	if len(args.Repos) == 0 || (args.CodeResultsCount == 0 && args.TextResultsCount == 0) {
 		// Don't bother doing an API request if we can't actually have any results.
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	repoNames := make([]api.RepoName, len(args.Repos))
@@ -185,7 +235,7 @@ func (c *CodyContextClient) getEmbeddingsContext(ctx context.Context, args GetCo
 		TextResultsCount: int(args.TextResultsCount),
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	idsByName := make(map[api.RepoName]api.RepoID)
@@ -193,18 +243,21 @@ func (c *CodyContextClient) getEmbeddingsContext(ctx context.Context, args GetCo
 		idsByName[repoName] = repoIDs[i]
 	}
 
-	res := make([]FileChunkContext, 0, len(results.CodeResults)+len(results.TextResults))
-	for _, result := range append(results.CodeResults, results.TextResults...) {
-		res = append(res, FileChunkContext{
-			RepoName:  result.RepoName,
-			RepoID:    idsByName[result.RepoName],
-			CommitID:  result.Revision,
-			Path:      result.FileName,
-			StartLine: result.StartLine,
-			EndLine:   result.EndLine,
-		})
+	toChunks := func(results embeddings.EmbeddingSearchResults) []FileChunkContext {
+		res := make([]FileChunkContext, 0, len(results))
+		for _, result := range results {
+			res = append(res, FileChunkContext{
+				RepoName:  result.RepoName,
+				RepoID:    idsByName[result.RepoName],
+				CommitID:  result.Revision,
+				Path:      result.FileName,
+				StartLine: result.StartLine,
+				EndLine:   result.EndLine,
+			})
+		}
+		return res
 	}
-	return res, nil
+	return toChunks(results.CodeResults), toChunks(results.TextResults), nil
 }
 
 var textFileFilter = func() string {
@@ -216,7 +269,7 @@ var textFileFilter = func() string {
 }()
 
 // getKeywordContext uses keyword search to find relevant bits of context for Cody
-func (c *CodyContextClient) getKeywordContext(ctx context.Context, args GetContextArgs) (_ []FileChunkContext, err error) {
+func (c *CodyContextClient) getKeywordContext(ctx context.Context, args GetContextArgs) (code, text []FileChunkContext, err error) {
 	ctx, _, endObservation := c.getKeywordContextOp.With(ctx, &err, observation.Args{Attrs: args.Attrs()})
 	defer endObservation(1, observation.Args{})
 
@@ -224,7 +277,7 @@ func (c *CodyContextClient) getKeywordContext(ctx context.Context, args GetConte
 		// TODO(camdencheek): for some reason the search query `repo:^$`
 		// returns all repos, not zero repos, causing searches over zero repos
 		// to break in unexpected ways.
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	// mini-HACK: pass in the scope using repo: filters. In an ideal world, we
@@ -302,10 +355,10 @@ func (c *CodyContextClient) getKeywordContext(ctx context.Context, args GetConte
 	})
 	results, err := p.Wait()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return append(results[0], results[1]...), nil
+	return results[0], results[1], nil
 }
 
 func fileMatchToContextMatches(fm *result.FileMatch) []FileChunkContext {
@@ -315,20 +368,26 @@ func fileMatchToContextMatches(fm *result.FileMatch) []FileChunkContext {
 
 	// To provide some context variety, we just use the top-ranked
 	// chunk (the first chunk) from each file
+	return []FileChunkContext{
+		chunkAround(types.RepoIDName{ID: fm.Repo.ID, Name: fm.Repo.Name}, fm.CommitID, fm.Path, fm.ChunkMatches[0].ContentStart.Line),
+	}
+}
 
+// chunkAround returns the chunk of the file around the given (zero-based) line.
+func chunkAround(repo types.RepoIDName, commit api.CommitID, path string, line int) FileChunkContext {
 	// 4 lines of leading context, clamped to zero
-	startLine := max(0, fm.ChunkMatches[0].ContentStart.Line-4)
+	startLine := max(0, line-4)
 	// depend on content fetching to trim to the end of the file
 	endLine := startLine + 8
 
-	return []FileChunkContext{{
-		RepoName:  fm.Repo.Name,
-		RepoID:    fm.Repo.ID,
-		CommitID:  fm.CommitID,
-		Path:      fm.Path,
+	return FileChunkContext{
+		RepoName:  repo.Name,
+		RepoID:    repo.ID,
+		CommitID:  commit,
+		Path:      path,
 		StartLine: startLine,
 		EndLine:   endLine,
-	}}
+	}
 }
 
 func max(vals ...int) int {
@@ -0,0 +1,201 @@
+package context
+
+import (
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// The sources of context for Cody.
+const (
+	SourceEmbeddings = "embeddings"
+	SourceKeyword    = "keyword"
+	SourceCodeGraph  = "codeGraph"
+)
+
+type retrievalConfig struct {
+	embeddingsWeight    float64
+	keywordWeight       float64
+	codeGraphWeight     float64
+	codeGraphMaxSymbols int
+}
+
+// getRetrievalConfig returns the site configuration of context retrieval, with defaults
+// applied to unset values.
+func getRetrievalConfig(c *schema.CodyContextRetrieval) retrievalConfig {
+	config := retrievalConfig{
+		embeddingsWeight:    1,
+		keywordWeight:       1,
+		codeGraphWeight:     0.5,
+		codeGraphMaxSymbols: 5,
+	}
+	if c == nil {
+		return config
+	}
+	if c.EmbeddingsWeight != nil {
+		config.embeddingsWeight = *c.EmbeddingsWeight
+	}
+	if c.KeywordWeight != nil {
+		config.keywordWeight = *c.KeywordWeight
+	}
+	if c.CodeGraphWeight != nil {
+		config.codeGraphWeight = *c.CodeGraphWeight
+	}
+	if c.CodeGraphMaxSymbols != 0 {
+		config.codeGraphMaxSymbols = c.CodeGraphMaxSymbols
+	}
+	return config
+}
+
+// sourceResults are the results of a single source of context, ranked from best to worst.
+type sourceResults struct {
+	source   string
+	weight   float64
+	code     []FileChunkContext
+	text     []FileChunkContext
+	duration time.Duration
+}
+
+func (r *sourceResults) fetch(f func() (code, text []FileChunkContext, err error)) (err error) {
+	start := time.Now()
+	r.code, r.text, err = f()
+	r.duration = time.Since(start)
+	return err
+}
+
+// rrfK dampens the advantage of the top ranks in reciprocal rank fusion. 60 is the
+// constant proposed by Cormack et al., which works well across a wide range of inputs.
+const rrfK = 60
+
+type fusedChunk struct {
+	chunk FileChunkContext
+	score float64
+	// source is the source that contributed the most to the score.
+	source      string
+	sourceScore float64
+}
+
+type fusionResult struct {
+	chunks []FileChunkContext
+	// selected is the number of returned chunks attributed to each source.
+	selected map[string]int
+	// duplicates is the number of chunks that overlapped a chunk of a better rank.
+	duplicates int
+}
+
+// fuseResults merges the ranked results of several sources with weighted reciprocal rank
+// fusion: a chunk scores weight/(rrfK+rank) for every source that returned it. Chunks of
+// the same file with overlapping lines are considered the same chunk, and only the first
+// one seen is kept. The limit best chunks are returned.
+func fuseResults(limit int, sources []*sourceResults, chunks func(*sourceResults) []FileChunkContext) fusionResult {
+	type fileKey struct {
+		repoID api.RepoID
+		path   string
+	}
+
+	res := fusionResult{selected: map[string]int{}}
+	var fused []*fusedChunk
+	byFile := map[fileKey][]*fusedChunk{}
+
+	add := func(source *sourceResults, rank int, chunk FileChunkContext) {
+		score := source.weight / float64(rrfK+rank+1)
+		key := fileKey{repoID: chunk.RepoID, path: chunk.Path}
+
+		for _, f := range byFile[key] {
+			if f.chunk.StartLine <= chunk.EndLine && chunk.StartLine <= f.chunk.EndLine {
+				res.duplicates++
+				f.score += score
+				if score > f.sourceScore {
+					f.source, f.sourceScore = source.source, score
+				}
+				return
+			}
+		}
+
+		f := &fusedChunk{chunk: chunk, score: score, source: source.source, sourceScore: score}
+		fused = append(fused, f)
+		byFile[key] = append(byFile[key], f)
+	}
+
+	// Visit the chunks rank by rank, so that ties are broken in favor of the better
+	// rank and then in the order of the sources.
+	for rank := 0; ; rank++ {
+		visited := false
+		for _, source := range sources {
+			if source.weight <= 0 {
+				continue
+			}
+			if sourceChunks := chunks(source); rank < len(sourceChunks) {
+				add(source, rank, sourceChunks[rank])
+				visited = true
+			}
+		}
+		if !visited {
+			break
+		}
+	}
+
+	sort.SliceStable(fused, func(i, j int) bool { return fused[i].score > fused[j].score })
+
+	for _, f := range truncate(fused, limit) {
+		res.chunks = append(res.chunks, f.chunk)
+		res.selected[f.source]++
+	}
+	return res
+}
+
+var (
+	retrievedResults = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_codycontext_retrieved_results_total",
+		Help: "The number of context results retrieved from each source.",
+	}, []string{"source", "type"})
+	selectedResults = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_codycontext_selected_results_total",
+		Help: "The number of context results of each source that were returned after merging.",
+	}, []string{"source", "type"})
+	duplicateResults = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_codycontext_duplicate_results_total",
+		Help: "The number of context results that overlapped a better ranked result of any source.",
+	}, []string{"type"})
+)
+
+// recordRetrieval logs and records metrics for evaluating the sources of a context
+// request, e.g. how often results of a source make the cut.
+func (c *CodyContextClient) recordRetrieval(args GetContextArgs, sources []*sourceResults, code, text fusionResult) {
+	fields := []log.Field{
+		log.Int("numRepos", len(args.Repos)),
+		log.Int("codeResultsCount", int(args.CodeResultsCount)),
+		log.Int("textResultsCount", int(args.TextResultsCount)),
+		log.Int("codeDuplicates", code.duplicates),
+		log.Int("textDuplicates", text.duplicates),
+	}
+	for _, source := range sources {
+		if source.weight <= 0 {
+			continue
+		}
+
+		retrievedResults.WithLabelValues(source.source, "code").Add(float64(len(source.code)))
+		retrievedResults.WithLabelValues(source.source, "text").Add(float64(len(source.text)))
+		selectedResults.WithLabelValues(source.source, "code").Add(float64(code.selected[source.source]))
+		selectedResults.WithLabelValues(source.source, "text").Add(float64(text.selected[source.source]))
+
+		fields = append(fields, log.Object(source.source,
+			log.Float64("weight", source.weight),
+			log.Duration("duration", source.duration),
+			log.Int("retrievedCode", len(source.code)),
+			log.Int("retrievedText", len(source.text)),
+			log.Int("selectedCode", code.selected[source.source]),
+			log.Int("selectedText", text.selected[source.source]),
+		))
+	}
+	duplicateResults.WithLabelValues("code").Add(float64(code.duplicates))
+	duplicateResults.WithLabelValues("text").Add(float64(text.duplicates))
+
+	c.obsCtx.Logger.Debug("retrieved cody context", fields...)
+}
@@ -0,0 +1,58 @@
+package context
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestFuseResults(t *testing.T) {
+	chunk := func(path string, startLine int) FileChunkContext {
+		return FileChunkContext{RepoID: 1, Path: path, StartLine: startLine, EndLine: startLine + 8}
+	}
+	code := func(r *sourceResults) []FileChunkContext { return r.code }
+
+	embeddings := &sourceResults{source: SourceEmbeddings, weight: 1, code: []FileChunkContext{chunk("a.go", 0), chunk("b.go", 0)}}
+	keyword := &sourceResults{source: SourceKeyword, weight: 1, code: []FileChunkContext{chunk("c.go", 0), chunk("b.go", 4)}}
+	codeGraph := &sourceResults{source: SourceCodeGraph, weight: 0, code: []FileChunkContext{chunk("d.go", 0)}}
+
+	t.Run("overlapping chunks are merged", func(t *testing.T) {
+		res := fuseResults(3, []*sourceResults{embeddings, keyword, codeGraph}, code)
+		// b.go is returned by both sources, which moves it to the top.
+		require.Equal(t, []FileChunkContext{chunk("b.go", 0), chunk("a.go", 0), chunk("c.go", 0)}, res.chunks)
+		require.Equal(t, 1, res.duplicates)
+		require.Equal(t, map[string]int{SourceEmbeddings: 2, SourceKeyword: 1}, res.selected)
+	})
+
+	t.Run("weights", func(t *testing.T) {
+		keyword := *keyword
+		keyword.weight = 3
+		res := fuseResults(2, []*sourceResults{embeddings, &keyword}, code)
+		require.Equal(t, []FileChunkContext{chunk("b.go", 0), chunk("c.go", 0)}, res.chunks)
+		require.Equal(t, map[string]int{SourceKeyword: 2}, res.selected)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		res := fuseResults(0, []*sourceResults{embeddings, keyword}, code)
+		require.Empty(t, res.chunks)
+	})
+}
+
+func TestGetRetrievalConfig(t *testing.T) {
+	require.Equal(t, retrievalConfig{embeddingsWeight: 1, keywordWeight: 1, codeGraphWeight: 0.5, codeGraphMaxSymbols: 5}, getRetrievalConfig(nil))
+
+	zero := 0.0
+	require.Equal(t, retrievalConfig{embeddingsWeight: 1, keywordWeight: 0, codeGraphWeight: 0.5, codeGraphMaxSymbols: 2}, getRetrievalConfig(&schema.CodyContextRetrieval{
+		KeywordWeight:       &zero,
+		CodeGraphMaxSymbols: 2,
+	}))
+}
+
+func TestSymbolCandidates(t *testing.T) {
+	q := "how does newWorker call handle_record() and what is NewStore? Also ok() and NewStore"
+	require.Equal(t, []string{"newWorker", "handle_record", "NewStore"}, symbolCandidates(q, 5))
+	require.Equal(t, []string{"newWorker"}, symbolCandidates(q, 1))
+	require.Empty(t, symbolCandidates("what does this code do", 5))
+}
@@ -99,6 +99,12 @@ func toFileReplacement(b []byte) (Result, error) {
 	return &r, errors.Wrap(err, "unmarshal JSON")
 }
 
+func toFileDiff(b []byte) (Result, error) {
+	var d FileDiff
+	err := json.Unmarshal(b, &d)
+	return &d, errors.Wrap(err, "unmarshal JSON")
+}
+
 func toOutput(b []byte) (Result, error) {
 	return &Output{Value: b}, nil
 }
@@ -235,6 +241,24 @@ func Replacements(ctx context.Context, args Args) (_ []*FileReplacement, err err
 	return matches, nil
 }
 
+// Diffs returns a unified diff of the rewrite for each file in which comby
+// finds matches.
+func Diffs(ctx context.Context, args Args) (_ []*FileDiff, err error) {
+	tr, ctx := trace.New(ctx, "comby.Diffs")
+	defer tr.FinishWithErr(&err)
+
+	args.ResultKind = Diff
+	results, err := Run(ctx, args, toFileDiff)
+	if err != nil {
+		return nil, err
+	}
+	var diffs []*FileDiff
+	for _, r := range results {
+		diffs = append(diffs, r.(*FileDiff))
+	}
+	return diffs, nil
+}
+
 // Outputs performs substitution of all variables captured in a match
 // pattern in a rewrite template and outputs the result, newline-sparated.
 func Outputs(ctx context.Context, args Args) (_ string, err error) {
@@ -200,6 +200,32 @@ func TestReplacements(t *testing.T) {
 	}
 }
 
+func TestDiffs(t *testing.T) {
+	// If we are not on CI skip the test if comby is not installed.
+	if os.Getenv("CI") == "" && !Exists() {
+		t.Skip("comby is not installed on the PATH. Try running 'bash <(curl -sL get.comby.dev)'.")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	diffs, err := Diffs(ctx, Args{
+		Input:           FileContent("yes\n"),
+		MatchTemplate:   "yes",
+		RewriteTemplate: "no",
+		Matcher:         ".go",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 1 {
+		t.Fatalf("got %d diffs, want 1", len(diffs))
+	}
+	if want := "--- /dev/null\n+++ /dev/null\n@@ -1,1 +1,1 @@\n-yes\n+no"; diffs[0].Diff != want {
+		t.Errorf("got %q, want %q", diffs[0].Diff, want)
+	}
+}
+
 func tempZipFromFiles(t *testing.T, files map[string]string) string {
 	t.Helper()
 
@@ -18,6 +18,10 @@ func Replacements(ctx context.Context, args Args) ([]*FileReplacement, error) {
 	return nil, errors.New("Comby is not supported on Windows")
 }
 
+func Diffs(ctx context.Context, args Args) ([]*FileDiff, error) {
+	return nil, errors.New("Comby is not supported on Windows")
+}
+
 func SetupCmdWithPipes(ctx context.Context, args Args) (cmd *exec.Cmd, stdin io.WriteCloser, stdout io.ReadCloser, stderr *bytes.Buffer, err error) {
 	return nil, nil, nil, nil, errors.New("Comby is not supported on Windows")
 }
@@ -0,0 +1,102 @@
+package commitsigning
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/sourcegraph/log"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func init() {
+	conf.ContributeValidator(func(c conftypes.SiteConfigQuerier) (problems conf.Problems) {
+		cfg := c.SiteConfig().GitCommitSignatures
+		if cfg == nil {
+			return nil
+		}
+		keys := &trustedKeys{}
+		for i, key := range cfg.TrustedKeys {
+			if err := keys.add(key); err != nil {
+				problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("git.commitSignatures.trustedKeys[%d]: %s", i, err)))
+			}
+		}
+		return problems
+	})
+}
+
+// cachedTrustedKeys returns the keys trusted by the site configuration.
+var cachedTrustedKeys = conf.Cached[*trustedKeys](func() *trustedKeys {
+	keys := &trustedKeys{}
+	cfg := conf.Get().GitCommitSignatures
+	if cfg == nil {
+		return keys
+	}
+	for _, key := range cfg.TrustedKeys {
+		if err := keys.add(key); err != nil {
+			// Skip invalid keys. A user-visible validation error will appear due to the
+			// ContributeValidator call above.
+			log.Scoped("commitsigning", "").Warn("invalid trusted signing key", log.String("name", key.Name), log.Error(err))
+		}
+	}
+	return keys
+})
+
+// trustedKeys are the keys trusted to sign commits and tags.
+type trustedKeys struct {
+	gpg openpgp.EntityList
+	// gpgNames maps the fingerprints of the primary keys in gpg to the name of the
+	// trusted key they come from.
+	gpgNames map[string]string
+	ssh      []trustedSSHKey
+}
+
+type trustedSSHKey struct {
+	name string
+	key  ssh.PublicKey
+}
+
+func (k *trustedKeys) add(key *schema.TrustedSigningKey) error {
+	if strings.HasPrefix(strings.TrimSpace(key.Key), "-----BEGIN PGP PUBLIC KEY BLOCK-----") {
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.Key))
+		if err != nil {
+			return errors.Wrap(err, "invalid OpenPGP public key")
+		}
+		if k.gpgNames == nil {
+			k.gpgNames = make(map[string]string)
+		}
+		for _, entity := range entities {
+			k.gpgNames[gpgFingerprint(entity)] = key.Name
+		}
+		k.gpg = append(k.gpg, entities...)
+		return nil
+	}
+
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key.Key))
+	if err != nil {
+		return errors.Wrap(err, "invalid SSH public key")
+	}
+	k.ssh = append(k.ssh, trustedSSHKey{name: key.Name, key: pub})
+	return nil
+}
+
+// sshKeyName returns the name of the given SSH key, and whether it is trusted.
+func (k *trustedKeys) sshKeyName(key ssh.PublicKey) (string, bool) {
+	for _, trusted := range k.ssh {
+		if bytes.Equal(trusted.key.Marshal(), key.Marshal()) {
+			return trusted.name, true
+		}
+	}
+	return "", false
+}
+
+func gpgFingerprint(entity *openpgp.Entity) string {
+	return strings.ToUpper(hex.EncodeToString(entity.PrimaryKey.Fingerprint))
+}
@@ -0,0 +1,95 @@
+package commitsigning
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/pem"
+	"hash"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// SSH signatures are made by `ssh-keygen -Y sign`, as described in
+// https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig.
+const (
+	sshsigMagic     = "SSHSIG"
+	sshsigVersion   = 1
+	sshsigNamespace = "git"
+)
+
+type sshSignature struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// sshSignedData is the data actually signed by the key, which embeds the hash of the
+// message.
+type sshSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+// parseSSHSignature parses an armored SSH signature.
+func parseSSHSignature(armored []byte) (*sshSignature, error) {
+	block, _ := pem.Decode(armored)
+	if block == nil || block.Type != "SSH SIGNATURE" {
+		return nil, errors.New("invalid SSH signature armor")
+	}
+	if !bytes.HasPrefix(block.Bytes, []byte(sshsigMagic)) {
+		return nil, errors.New("invalid SSH signature magic")
+	}
+
+	var sig sshSignature
+	if err := ssh.Unmarshal(block.Bytes[len(sshsigMagic):], &sig); err != nil {
+		return nil, errors.Wrap(err, "parsing SSH signature")
+	}
+	if sig.Version != sshsigVersion {
+		return nil, errors.Newf("unsupported SSH signature version %d", sig.Version)
+	}
+	return &sig, nil
+}
+
+// verify returns the key that made the signature, and an error if the signature is not
+// valid for the given message.
+func (sig *sshSignature) verify(message []byte) (ssh.PublicKey, error) {
+	key, err := ssh.ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing SSH public key")
+	}
+	if sig.Namespace != sshsigNamespace {
+		return key, errors.Newf("unexpected SSH signature namespace %q", sig.Namespace)
+	}
+
+	var h hash.Hash
+	switch sig.HashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return key, errors.Newf("unsupported SSH signature hash algorithm %q", sig.HashAlgorithm)
+	}
+	h.Write(message)
+
+	signed := append([]byte(sshsigMagic), ssh.Marshal(sshSignedData{
+		Namespace:     sig.Namespace,
+		Reserved:      sig.Reserved,
+		HashAlgorithm: sig.HashAlgorithm,
+		Hash:          h.Sum(nil),
+	})...)
+
+	var signature ssh.Signature
+	if err := ssh.Unmarshal(sig.Signature, &signature); err != nil {
+		return key, errors.Wrap(err, "parsing SSH signature blob")
+	}
+	return key, key.Verify(signed, &signature)
+}
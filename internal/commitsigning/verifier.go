@@ -0,0 +1,197 @@
+// Package commitsigning verifies the GPG and SSH signatures of commits and tags against
+// the keys trusted in the site configuration, or the verification status reported by
+// the code host.
+package commitsigning
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/sourcegraph/log"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/repos"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Status is the verification status of the signature of a commit or tag.
+type Status string
+
+const (
+	// StatusVerified is the status of signatures made with a trusted key, or verified
+	// by the code host.
+	StatusVerified Status = "verified"
+	// StatusUntrusted is the status of signatures made with a key that is not trusted.
+	StatusUntrusted Status = "untrusted"
+	// StatusInvalid is the status of signatures that do not match the signed object.
+	StatusInvalid Status = "invalid"
+	// StatusUnsupported is the status of signatures in a format that cannot be
+	// verified, such as X.509.
+	StatusUnsupported Status = "unsupported"
+	// StatusUnsigned is the status of objects that are not signed.
+	StatusUnsigned Status = "unsigned"
+)
+
+// Source is what the status of a signature was determined by.
+type Source string
+
+const (
+	SourceTrustedKeys Source = "trusted_keys"
+	SourceCodeHost    Source = "code_host"
+)
+
+// Result is the verification status of the signature of a commit or tag.
+type Result struct {
+	Status Status
+	// Format is the format of the signature, if signed.
+	Format gitdomain.SignatureFormat
+	// KeyID is the fingerprint of the key that made the signature, if known.
+	KeyID string
+	// Signer is the name of the trusted key that made the signature, if any.
+	Signer string
+	// VerifiedBy is what the status was determined by.
+	VerifiedBy Source
+	// Reason is the reason reported by the code host for the status, if any.
+	Reason string
+}
+
+// Verifier verifies the signatures of commits and tags.
+type Verifier struct {
+	logger log.Logger
+	db     database.DB
+}
+
+// NewVerifier returns a Verifier for the keys trusted in the site configuration.
+func NewVerifier(logger log.Logger, db database.DB) *Verifier {
+	return &Verifier{logger: logger.Scoped("commitsigning", "commit signature verification"), db: db}
+}
+
+// Verify returns the verification status of the signature of the commit or tag with
+// the given OID in repo. The signed object is nil if it is not signed.
+//
+// Signatures that are not made with a trusted key are verified by the code host if
+// enabled in the site configuration.
+func (v *Verifier) Verify(ctx context.Context, repo *types.Repo, oid string, obj *gitdomain.SignedObject) *Result {
+	if obj == nil {
+		return &Result{Status: StatusUnsigned, VerifiedBy: SourceTrustedKeys}
+	}
+
+	res := cachedTrustedKeys().verify(obj)
+	if res.Status == StatusVerified {
+		return res
+	}
+	if cfg := conf.Get().GitCommitSignatures; cfg == nil || !cfg.CodeHostVerification {
+		return res
+	}
+
+	hostRes, err := v.verifyWithCodeHost(ctx, repo, oid, obj)
+	if err != nil {
+		// Code host outages should not hide the signature status we know of.
+		v.logger.Warn("verifying signature with code host", log.String("repo", string(repo.Name)), log.String("oid", oid), log.Error(err))
+		return res
+	}
+	if hostRes == nil {
+		return res
+	}
+	if hostRes.KeyID == "" {
+		hostRes.KeyID = res.KeyID
+	}
+	return hostRes
+}
+
+func (k *trustedKeys) verify(obj *gitdomain.SignedObject) *Result {
+	res := &Result{Format: obj.Format, VerifiedBy: SourceTrustedKeys}
+
+	switch obj.Format {
+	case gitdomain.SignatureFormatGPG:
+		signer, err := openpgp.CheckArmoredDetachedSignature(k.gpg, bytes.NewReader(obj.Payload), bytes.NewReader(obj.Signature), nil)
+		switch {
+		case err == nil:
+			res.Status = StatusVerified
+			res.KeyID = gpgFingerprint(signer)
+			res.Signer = k.gpgNames[res.KeyID]
+		case errors.Is(err, pgperrors.ErrUnknownIssuer):
+			res.Status = StatusUntrusted
+		default:
+			res.Status = StatusInvalid
+			if signer != nil {
+				res.KeyID = gpgFingerprint(signer)
+			}
+		}
+
+	case gitdomain.SignatureFormatSSH:
+		sig, err := parseSSHSignature(obj.Signature)
+		if err != nil {
+			res.Status = StatusInvalid
+			return res
+		}
+		key, err := sig.verify(obj.Payload)
+		if key != nil {
+			res.KeyID = ssh.FingerprintSHA256(key)
+		}
+		if err != nil {
+			res.Status = StatusInvalid
+			return res
+		}
+		if name, ok := k.sshKeyName(key); ok {
+			res.Status = StatusVerified
+			res.Signer = name
+		} else {
+			res.Status = StatusUntrusted
+		}
+
+	default:
+		res.Status = StatusUnsupported
+	}
+
+	return res
+}
+
+// verifyWithCodeHost returns the verification status of the signature as reported by
+// the code host of repo, or nil if the code host does not support it.
+func (v *Verifier) verifyWithCodeHost(ctx context.Context, repo *types.Repo, oid string, obj *gitdomain.SignedObject) (*Result, error) {
+	if repo.ExternalRepo.ServiceType != extsvc.TypeGitHub {
+		return nil, nil
+	}
+	metadata, ok := repo.Metadata.(*github.Repository)
+	if !ok {
+		return nil, nil
+	}
+
+	svcs, err := v.db.ExternalServices().List(ctx, database.ExternalServicesListOptions{IDs: repo.ExternalServiceIDs()})
+	if err != nil {
+		return nil, err
+	}
+	if len(svcs) == 0 {
+		return nil, nil
+	}
+
+	src, err := repos.NewGitHubSource(ctx, v.logger, svcs[0], httpcli.ExternalClientFactory)
+	if err != nil {
+		return nil, err
+	}
+	verification, err := src.GetObjectVerification(ctx, metadata.NameWithOwner, string(obj.Type), oid)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Format: obj.Format, VerifiedBy: SourceCodeHost, Reason: verification.Reason}
+	switch {
+	case verification.Verified:
+		res.Status = StatusVerified
+	case verification.Reason == "invalid" || verification.Reason == "malformed_signature":
+		res.Status = StatusInvalid
+	default:
+		res.Status = StatusUntrusted
+	}
+	return res, nil
+}
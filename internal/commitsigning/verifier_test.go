@@ -0,0 +1,164 @@
+package commitsigning
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const payload = "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+	"author Alice <alice@example.com> 1600000000 +0000\n" +
+	"committer Alice <alice@example.com> 1600000000 +0000\n" +
+	"\nmessage\n"
+
+func TestTrustedKeys_Verify_SSH(t *testing.T) {
+	trusted, trustedKey := newSSHSigner(t)
+	untrusted, _ := newSSHSigner(t)
+
+	keys := &trustedKeys{}
+	require.NoError(t, keys.add(&schema.TrustedSigningKey{Name: "alice", Key: trustedKey}))
+
+	t.Run("trusted key", func(t *testing.T) {
+		res := keys.verify(sshSignedObject(t, trusted, payload, payload))
+		assert.Equal(t, StatusVerified, res.Status)
+		assert.Equal(t, "alice", res.Signer)
+		assert.Equal(t, ssh.FingerprintSHA256(trusted.PublicKey()), res.KeyID)
+	})
+
+	t.Run("untrusted key", func(t *testing.T) {
+		res := keys.verify(sshSignedObject(t, untrusted, payload, payload))
+		assert.Equal(t, StatusUntrusted, res.Status)
+		assert.Empty(t, res.Signer)
+		assert.Equal(t, ssh.FingerprintSHA256(untrusted.PublicKey()), res.KeyID)
+	})
+
+	t.Run("tampered object", func(t *testing.T) {
+		res := keys.verify(sshSignedObject(t, trusted, payload, strings.Replace(payload, "message", "tampered", 1)))
+		assert.Equal(t, StatusInvalid, res.Status)
+	})
+
+	t.Run("malformed signature", func(t *testing.T) {
+		res := keys.verify(&gitdomain.SignedObject{
+			Format:    gitdomain.SignatureFormatSSH,
+			Signature: []byte("-----BEGIN SSH SIGNATURE-----\nAAAA\n-----END SSH SIGNATURE-----\n"),
+			Payload:   []byte(payload),
+		})
+		assert.Equal(t, StatusInvalid, res.Status)
+	})
+}
+
+func TestTrustedKeys_Verify_GPG(t *testing.T) {
+	trusted, trustedKey := newGPGEntity(t)
+	untrusted, _ := newGPGEntity(t)
+
+	keys := &trustedKeys{}
+	require.NoError(t, keys.add(&schema.TrustedSigningKey{Name: "alice", Key: trustedKey}))
+
+	t.Run("trusted key", func(t *testing.T) {
+		res := keys.verify(gpgSignedObject(t, trusted, payload, payload))
+		assert.Equal(t, StatusVerified, res.Status)
+		assert.Equal(t, "alice", res.Signer)
+		assert.Equal(t, gpgFingerprint(trusted), res.KeyID)
+	})
+
+	t.Run("untrusted key", func(t *testing.T) {
+		res := keys.verify(gpgSignedObject(t, untrusted, payload, payload))
+		assert.Equal(t, StatusUntrusted, res.Status)
+	})
+
+	t.Run("tampered object", func(t *testing.T) {
+		res := keys.verify(gpgSignedObject(t, trusted, payload, strings.Replace(payload, "message", "tampered", 1)))
+		assert.Equal(t, StatusInvalid, res.Status)
+	})
+}
+
+func TestTrustedKeys_Verify_Unsupported(t *testing.T) {
+	res := (&trustedKeys{}).verify(&gitdomain.SignedObject{
+		Format:    gitdomain.SignatureFormatX509,
+		Signature: []byte("-----BEGIN SIGNED MESSAGE-----\n-----END SIGNED MESSAGE-----\n"),
+		Payload:   []byte(payload),
+	})
+	assert.Equal(t, StatusUnsupported, res.Status)
+}
+
+func TestTrustedKeys_Add(t *testing.T) {
+	keys := &trustedKeys{}
+	assert.Error(t, keys.add(&schema.TrustedSigningKey{Key: "not a key"}))
+}
+
+func newSSHSigner(t *testing.T) (ssh.Signer, string) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+	return signer, string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+}
+
+// sshSignedObject signs message like `ssh-keygen -Y sign -n git`, and returns it as
+// the signature of object.
+func sshSignedObject(t *testing.T, signer ssh.Signer, message, object string) *gitdomain.SignedObject {
+	t.Helper()
+	h := sha512.Sum512([]byte(message))
+	signed := append([]byte(sshsigMagic), ssh.Marshal(sshSignedData{
+		Namespace:     sshsigNamespace,
+		HashAlgorithm: "sha512",
+		Hash:          h[:],
+	})...)
+	signature, err := signer.Sign(rand.Reader, signed)
+	require.NoError(t, err)
+
+	blob := append([]byte(sshsigMagic), ssh.Marshal(sshSignature{
+		Version:       sshsigVersion,
+		PublicKey:     signer.PublicKey().Marshal(),
+		Namespace:     sshsigNamespace,
+		HashAlgorithm: "sha512",
+		Signature:     ssh.Marshal(signature),
+	})...)
+	return &gitdomain.SignedObject{
+		Type:      gitdomain.ObjectTypeCommit,
+		Format:    gitdomain.SignatureFormatSSH,
+		Signature: pem.EncodeToMemory(&pem.Block{Type: "SSH SIGNATURE", Bytes: blob}),
+		Payload:   []byte(object),
+	}
+}
+
+func newGPGEntity(t *testing.T) (*openpgp.Entity, string) {
+	t.Helper()
+	entity, err := openpgp.NewEntity("Alice", "", "alice@example.com", nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+	return entity, buf.String()
+}
+
+// gpgSignedObject signs message like `gpg --detach-sign --armor`, and returns it as
+// the signature of object.
+func gpgSignedObject(t *testing.T, entity *openpgp.Entity, message, object string) *gitdomain.SignedObject {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, openpgp.ArmoredDetachSign(&buf, entity, strings.NewReader(message), nil))
+	return &gitdomain.SignedObject{
+		Type:      gitdomain.ObjectTypeCommit,
+		Format:    gitdomain.SignatureFormatGPG,
+		Signature: buf.Bytes(),
+		Payload:   []byte(object),
+	}
+}
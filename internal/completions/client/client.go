@@ -24,6 +24,8 @@ func getBasic(endpoint string, provider conftypes.CompletionsProviderName, acces
 		return anthropic.NewClient(httpcli.ExternalDoer, endpoint, accessToken), nil
 	case conftypes.CompletionsProviderNameOpenAI:
 		return openai.NewClient(httpcli.ExternalDoer, endpoint, accessToken), nil
+	case conftypes.CompletionsProviderNameAzureOpenAI:
+		return openai.NewAzureClient(httpcli.ExternalDoer, endpoint, accessToken), nil
 	case conftypes.CompletionsProviderNameSourcegraph:
 		return codygateway.NewClient(httpcli.ExternalDoer, endpoint, accessToken)
 	default:
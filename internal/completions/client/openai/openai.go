@@ -20,10 +20,24 @@ func NewClient(cli httpcli.Doer, endpoint, accessToken string) types.Completions
 	}
 }
 
+// NewAzureClient returns a client for the Azure OpenAI service. The endpoint is the
+// chat completions URL of a deployment, which determines the model.
+func NewAzureClient(cli httpcli.Doer, endpoint, accessToken string) types.CompletionsClient {
+	return &openAIChatCompletionStreamClient{
+		cli:         cli,
+		accessToken: accessToken,
+		endpoint:    endpoint,
+		azure:       true,
+	}
+}
+
 type openAIChatCompletionStreamClient struct {
 	cli         httpcli.Doer
 	accessToken string
 	endpoint    string
+	// azure is true for the Azure OpenAI service, which authenticates requests with
+	// an API key header instead of a bearer token.
+	azure bool
 }
 
 func (c *openAIChatCompletionStreamClient) Complete(
@@ -150,7 +164,11 @@ func (c *openAIChatCompletionStreamClient) makeRequest(ctx context.Context, requ
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	if c.azure {
+		req.Header.Set("api-key", c.accessToken)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
 
 	resp, err := c.cli.Do(req)
 	if err != nil {
@@ -158,6 +176,9 @@ func (c *openAIChatCompletionStreamClient) makeRequest(ctx context.Context, requ
 	}
 
 	if resp.StatusCode != http.StatusOK {
+		if c.azure {
+			return nil, types.NewErrStatusNotOK("Azure OpenAI", resp)
+		}
 		return nil, types.NewErrStatusNotOK("OpenAI", resp)
 	}
 
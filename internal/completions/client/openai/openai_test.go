@@ -51,3 +51,22 @@ func TestErrStatusNotOK(t *testing.T) {
 		assert.True(t, ok)
 	})
 }
+
+func TestAzureClientAuthentication(t *testing.T) {
+	var header http.Header
+	mockClient := NewAzureClient(&mockDoer{
+		func(r *http.Request) (*http.Response, error) {
+			header = r.Header
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader([]byte(`{"choices":[{"content":"hello"}]}`))),
+			}, nil
+		},
+	}, "https://example.openai.azure.com", "secret")
+
+	resp, err := mockClient.Complete(context.Background(), types.CompletionsFeatureChat, types.CompletionRequestParameters{})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", resp.Completion)
+	assert.Equal(t, "secret", header.Get("api-key"))
+	assert.Empty(t, header.Get("Authorization"))
+}
@@ -0,0 +1,394 @@
+package guardrails
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/completions/types"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/requestclient"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Rule is the name of a guardrail.
+type Rule string
+
+const (
+	RuleSecretRedaction    Rule = "secret-redaction"
+	RulePolicy             Rule = "policy"
+	RuleSnippetAttribution Rule = "snippet-attribution"
+)
+
+// Action is what a guardrail did when it was triggered.
+type Action string
+
+const (
+	ActionRedact Action = "redact"
+	ActionRefuse Action = "refuse"
+	ActionAudit  Action = "audit"
+	ActionBlock  Action = "block"
+)
+
+const (
+	snippetAttributionOff   = "off"
+	snippetAttributionAudit = "audit"
+	snippetAttributionBlock = "block"
+
+	defaultSnippetAttributionMinLines = 10
+
+	// snippetAttributionLimit is the maximum number of repositories recorded for a
+	// snippet.
+	snippetAttributionLimit = 5
+)
+
+var triggeredGuardrails = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_completions_guardrails_triggered_total",
+	Help: "The number of times a guardrail was triggered by a completions request.",
+}, []string{"rule", "action"})
+
+// SnippetAttributionSearcher searches the indexed code for snippets of code.
+type SnippetAttributionSearcher interface {
+	// SnippetAttribution returns the names of at most limit repositories containing
+	// the snippet.
+	SnippetAttribution(ctx context.Context, snippet string, limit int) ([]string, error)
+}
+
+// RefusalError is returned for requests refused by a guardrail. Its message is meant
+// to be shown to the user.
+type RefusalError struct {
+	Rule    Rule
+	Message string
+}
+
+func (e *RefusalError) Error() string {
+	return e.Message
+}
+
+// Guardrails enforces the guardrails configured in the site configuration on
+// completions requests.
+type Guardrails struct {
+	logger      log.Logger
+	db          database.DB
+	attribution SnippetAttributionSearcher
+	getConfig   func() *schema.CompletionsGuardrails
+}
+
+// NewGuardrails returns guardrails enforcing the current site configuration. If
+// attribution is nil, the snippets of completions are never searched for.
+func NewGuardrails(logger log.Logger, db database.DB, attribution SnippetAttributionSearcher) *Guardrails {
+	return &Guardrails{
+		logger:      logger.Scoped("guardrails", "completions guardrails"),
+		db:          db,
+		attribution: attribution,
+		getConfig: func() *schema.CompletionsGuardrails {
+			completions := conf.Get().SiteConfig().Completions
+			if completions == nil {
+				return nil
+			}
+			return completions.Guardrails
+		},
+	}
+}
+
+// CheckPrompt enforces the guardrails on the prompt of a request before it is sent to
+// the provider. Secrets are redacted from params in place. A RefusalError is returned
+// if a policy refuses the request.
+func (g *Guardrails) CheckPrompt(ctx context.Context, intent types.CompletionIntent, params *types.CompletionRequestParameters) error {
+	cfg := g.getConfig()
+	if cfg == nil {
+		return nil
+	}
+
+	// Policies are matched before secrets are redacted, as they may be about secrets
+	// too.
+	for _, policy := range cfg.Policies {
+		pattern, err := regexp.Compile(policy.Pattern)
+		if err != nil {
+			g.logger.Warn("skipping guardrail policy with invalid pattern", log.String("policy", policy.Name), log.Error(err))
+			continue
+		}
+		if !matchesPrompt(pattern, params) {
+			continue
+		}
+
+		g.record(ctx, trigger{Rule: RulePolicy, Action: ActionRefuse, Intent: intent, Policy: policy.Name})
+		message := policy.Message
+		if message == "" {
+			message = fmt.Sprintf("the request was refused by the policy %q", policy.Name)
+		}
+		return &RefusalError{Rule: RulePolicy, Message: message}
+	}
+
+	if cfg.RedactSecrets {
+		patterns := builtinSecretPatterns
+		for _, p := range cfg.SecretPatterns {
+			pattern, err := regexp.Compile(p)
+			if err != nil {
+				g.logger.Warn("skipping invalid secret pattern", log.Error(err))
+				continue
+			}
+			patterns = append(patterns[:len(patterns):len(patterns)], pattern)
+		}
+
+		var count, n int
+		params.Prompt, n = redactSecrets(params.Prompt, patterns)
+		count += n
+		for i := range params.Messages {
+			params.Messages[i].Text, n = redactSecrets(params.Messages[i].Text, patterns)
+			count += n
+		}
+		if count > 0 {
+			g.record(ctx, trigger{Rule: RuleSecretRedaction, Action: ActionRedact, Intent: intent, Count: count})
+		}
+	}
+
+	return nil
+}
+
+func matchesPrompt(pattern *regexp.Regexp, params *types.CompletionRequestParameters) bool {
+	if pattern.MatchString(params.Prompt) {
+		return true
+	}
+	for _, m := range params.Messages {
+		if pattern.MatchString(m.Text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Client wraps a completions client to enforce the guardrails on the completions it
+// returns.
+func (g *Guardrails) Client(intent types.CompletionIntent, client types.CompletionsClient) types.CompletionsClient {
+	return &guardedClient{CompletionsClient: client, guardrails: g, intent: intent}
+}
+
+type guardedClient struct {
+	types.CompletionsClient
+	guardrails *Guardrails
+	intent     types.CompletionIntent
+}
+
+func (c *guardedClient) Stream(ctx context.Context, feature types.CompletionsFeature, params types.CompletionRequestParameters, sendEvent types.SendCompletionEvent) error {
+	mode, minLines := c.guardrails.snippetAttributionConfig()
+	switch mode {
+	case snippetAttributionAudit:
+		var completion string
+		err := c.CompletionsClient.Stream(ctx, feature, params, func(event types.CompletionResponse) error {
+			completion = event.Completion
+			return sendEvent(event)
+		})
+		if err == nil {
+			c.guardrails.auditSnippets(ctx, c.intent, completion, minLines)
+		}
+		return err
+
+	case snippetAttributionBlock:
+		// Every event contains the completion so far, so only the last one is sent
+		// once the completion was checked.
+		var last *types.CompletionResponse
+		err := c.CompletionsClient.Stream(ctx, feature, params, func(event types.CompletionResponse) error {
+			last = &event
+			return nil
+		})
+		if err != nil || last == nil {
+			return err
+		}
+		if err := c.guardrails.checkSnippets(ctx, c.intent, last.Completion, minLines); err != nil {
+			return err
+		}
+		return sendEvent(*last)
+
+	default:
+		return c.CompletionsClient.Stream(ctx, feature, params, sendEvent)
+	}
+}
+
+func (c *guardedClient) Complete(ctx context.Context, feature types.CompletionsFeature, params types.CompletionRequestParameters) (*types.CompletionResponse, error) {
+	resp, err := c.CompletionsClient.Complete(ctx, feature, params)
+	if err != nil {
+		return nil, err
+	}
+
+	mode, minLines := c.guardrails.snippetAttributionConfig()
+	switch mode {
+	case snippetAttributionAudit:
+		c.guardrails.auditSnippets(ctx, c.intent, resp.Completion, minLines)
+	case snippetAttributionBlock:
+		if err := c.guardrails.checkSnippets(ctx, c.intent, resp.Completion, minLines); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// snippetAttributionConfig returns the snippet attribution mode and the minimum
+// number of lines of the snippets to search for.
+func (g *Guardrails) snippetAttributionConfig() (string, int) {
+	cfg := g.getConfig()
+	if cfg == nil || g.attribution == nil || cfg.SnippetAttribution == "" {
+		return snippetAttributionOff, 0
+	}
+	minLines := cfg.SnippetAttributionMinLines
+	if minLines <= 0 {
+		minLines = defaultSnippetAttributionMinLines
+	}
+	return cfg.SnippetAttribution, minLines
+}
+
+// auditSnippets records the snippets of the completion found in the indexed code
+// without delaying the response.
+func (g *Guardrails) auditSnippets(ctx context.Context, intent types.CompletionIntent, completion string, minLines int) {
+	ctx = detach(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		if repos := g.attributeSnippets(ctx, completion, minLines); len(repos) > 0 {
+			g.record(ctx, trigger{Rule: RuleSnippetAttribution, Action: ActionAudit, Intent: intent, Repositories: repos})
+		}
+	}()
+}
+
+// checkSnippets returns a RefusalError if a snippet of the completion is found in
+// the indexed code.
+func (g *Guardrails) checkSnippets(ctx context.Context, intent types.CompletionIntent, completion string, minLines int) error {
+	repos := g.attributeSnippets(ctx, completion, minLines)
+	if len(repos) == 0 {
+		return nil
+	}
+
+	g.record(ctx, trigger{Rule: RuleSnippetAttribution, Action: ActionBlock, Intent: intent, Repositories: repos})
+	return &RefusalError{
+		Rule:    RuleSnippetAttribution,
+		Message: fmt.Sprintf("the completion was withheld because it matches existing code in %s", strings.Join(repos, ", ")),
+	}
+}
+
+// attributeSnippets returns the repositories containing the code snippets of the
+// completion. Snippets that cannot be searched for are let through, so that an
+// unavailable search does not break completions.
+func (g *Guardrails) attributeSnippets(ctx context.Context, completion string, minLines int) []string {
+	var repos []string
+	seen := map[string]struct{}{}
+	for _, snippet := range codeSnippets(completion, minLines) {
+		names, err := g.attribution.SnippetAttribution(ctx, snippet, snippetAttributionLimit)
+		if err != nil {
+			g.logger.Warn("failed to search for completion snippet", log.Error(err))
+			continue
+		}
+		for _, name := range names {
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			repos = append(repos, name)
+		}
+	}
+	return repos
+}
+
+// codeSnippets returns the code snippets of the completion with at least minLines
+// non-blank lines. The snippets of completions containing Markdown code blocks are
+// the contents of the code blocks, other completions are a snippet on their own.
+func codeSnippets(completion string, minLines int) []string {
+	var snippets []string
+	if strings.Contains(completion, "```") {
+		var (
+			block   []string
+			inBlock bool
+		)
+		for _, line := range strings.Split(completion, "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "```") {
+				if inBlock {
+					snippets = append(snippets, strings.Join(block, "\n"))
+					block = nil
+				}
+				inBlock = !inBlock
+				continue
+			}
+			if inBlock {
+				block = append(block, line)
+			}
+		}
+		if inBlock {
+			// The completion ended in the middle of a code block.
+			snippets = append(snippets, strings.Join(block, "\n"))
+		}
+	} else {
+		snippets = []string{completion}
+	}
+
+	filtered := snippets[:0]
+	for _, snippet := range snippets {
+		if countNonBlankLines(snippet) >= minLines {
+			filtered = append(filtered, strings.TrimSpace(snippet))
+		}
+	}
+	return filtered
+}
+
+func countNonBlankLines(s string) int {
+	count := 0
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// trigger is the audit record of a triggered guardrail.
+type trigger struct {
+	Rule         Rule                   `json:"rule"`
+	Action       Action                 `json:"action"`
+	Intent       types.CompletionIntent `json:"intent"`
+	Policy       string                 `json:"policy,omitempty"`
+	Count        int                    `json:"count,omitempty"`
+	Repositories []string               `json:"repositories,omitempty"`
+}
+
+// record records the triggered guardrail as a security event.
+func (g *Guardrails) record(ctx context.Context, t trigger) {
+	triggeredGuardrails.WithLabelValues(string(t.Rule), string(t.Action)).Inc()
+
+	argument, err := json.Marshal(t)
+	if err != nil {
+		g.logger.Warn("failed to marshal guardrail trigger", log.Error(err))
+		return
+	}
+
+	a := actor.FromContext(ctx)
+	event := &database.SecurityEvent{
+		Name:      database.SecurityEventCodyGuardrailTriggered,
+		UserID:    uint32(a.UID),
+		Argument:  argument,
+		Source:    "BACKEND",
+		Timestamp: time.Now(),
+	}
+	if !a.IsAuthenticated() {
+		event.AnonymousUserID = a.AnonymousUID
+		if event.AnonymousUserID == "" {
+			event.AnonymousUserID = "backend"
+		}
+	}
+	g.db.SecurityEventLogs().LogEvent(ctx, event)
+}
+
+// detach returns a context carrying the actor and request client of ctx, which is
+// not canceled with it.
+func detach(ctx context.Context) context.Context {
+	a := actor.FromContext(ctx)
+	client := requestclient.FromContext(ctx)
+	return requestclient.WithClient(actor.WithActor(context.Background(), a), client)
+}
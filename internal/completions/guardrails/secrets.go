@@ -0,0 +1,63 @@
+package guardrails
+
+import (
+	"regexp"
+	"strings"
+)
+
+// redactedSecret replaces the secrets redacted from prompts.
+const redactedSecret = "[REDACTED]"
+
+// builtinSecretPatterns match well-known kinds of secrets. If a pattern has a capture
+// group, only the text of the first group is the secret.
+var builtinSecretPatterns = []*regexp.Regexp{
+	// Private keys.
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+	// AWS access key IDs.
+	regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	// GitHub tokens.
+	regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`),
+	// GitLab personal access tokens.
+	regexp.MustCompile(`\bglpat-[A-Za-z0-9_\-]{20,}`),
+	// Sourcegraph access tokens.
+	regexp.MustCompile(`\bsgp_(?:[a-fA-F0-9]{16}_)?[a-fA-F0-9]{40}\b`),
+	// Slack tokens.
+	regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9\-]{10,}`),
+	// OpenAI and Anthropic API keys.
+	regexp.MustCompile(`\bsk-(?:ant-)?[A-Za-z0-9_\-]{20,}`),
+	// Values assigned to keys named like secrets.
+	regexp.MustCompile(`(?i)(?:api[_-]?key|secret|passw(?:or)?d|token)["']?\s*[:=]\s*["']?([^\s"']{8,})`),
+}
+
+// redactSecrets replaces the secrets matched by the patterns in the text, and returns
+// the number of redacted secrets.
+func redactSecrets(text string, patterns []*regexp.Regexp) (string, int) {
+	count := 0
+	for _, pattern := range patterns {
+		matches := pattern.FindAllStringSubmatchIndex(text, -1)
+		if len(matches) == 0 {
+			continue
+		}
+
+		var b strings.Builder
+		last := 0
+		for _, m := range matches {
+			start, end := m[0], m[1]
+			if len(m) >= 4 && m[2] >= 0 {
+				// Only redact the first capture group.
+				start, end = m[2], m[3]
+			}
+			if text[start:end] == redactedSecret {
+				continue
+			}
+
+			b.WriteString(text[last:start])
+			b.WriteString(redactedSecret)
+			last = end
+			count++
+		}
+		b.WriteString(text[last:])
+		text = b.String()
+	}
+	return text, count
+}
@@ -7,25 +7,34 @@ import (
 
 	"github.com/sourcegraph/log"
 
+	"github.com/sourcegraph/sourcegraph/internal/completions/guardrails"
 	"github.com/sourcegraph/sourcegraph/internal/completions/types"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/redispool"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 // NewCodeCompletionsHandler is an http handler which sends back code completion results
-func NewCodeCompletionsHandler(logger log.Logger, db database.DB) http.Handler {
+func NewCodeCompletionsHandler(logger log.Logger, db database.DB, guard *guardrails.Guardrails) http.Handler {
 	logger = logger.Scoped("code", "code completions handler")
 
 	rl := NewRateLimiter(db, redispool.Store, types.CompletionsFeatureCode)
-	return newCompletionsHandler(rl, "code", func(requestParams types.CodyCompletionRequestParameters, c *conftypes.CompletionsConfig) string {
+	return newCompletionsHandler(logger, db, rl, NewTokenQuota(redispool.Store), guard, "code", types.CompletionIntentAutocomplete, func(requestParams types.CodyCompletionRequestParameters, c *conftypes.CompletionsConfig) string {
 		// No user defined models for now.
 		// TODO(eseliger): Look into reviving this, but it was unused so far.
 		return c.CompletionModel
 	}, func(ctx context.Context, requestParams types.CompletionRequestParameters, cc types.CompletionsClient, w http.ResponseWriter) {
 		completion, err := cc.Complete(ctx, types.CompletionsFeatureCode, requestParams)
 		if err != nil {
+			var refusal *guardrails.RefusalError
+			if errors.As(err, &refusal) {
+				// The completion was withheld by a guardrail, which is not an error.
+				http.Error(w, refusal.Error(), http.StatusForbidden)
+				return
+			}
+
 			logFields := []log.Field{log.Error(err)}
 
 			// Propagate the upstream headers to the client if available.
@@ -8,11 +8,18 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/cody"
 	"github.com/sourcegraph/sourcegraph/internal/completions/client"
+	"github.com/sourcegraph/sourcegraph/internal/completions/guardrails"
+	"github.com/sourcegraph/sourcegraph/internal/completions/promptlog"
 	"github.com/sourcegraph/sourcegraph/internal/completions/types"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 // maxRequestDuration is the maximum amount of time a request can take before
@@ -20,8 +27,13 @@ import (
 const maxRequestDuration = time.Minute
 
 func newCompletionsHandler(
+	logger log.Logger,
+	db database.DB,
 	rl RateLimiter,
+	quota TokenQuota,
+	guard *guardrails.Guardrails,
 	traceFamily string,
+	defaultIntent types.CompletionIntent,
 	getModel func(types.CodyCompletionRequestParameters, *conftypes.CompletionsConfig) string,
 	handle func(context.Context, types.CompletionRequestParameters, types.CompletionsClient, http.ResponseWriter),
 ) http.Handler {
@@ -42,6 +54,7 @@ func newCompletionsHandler(
 		completionsConfig := conf.GetCompletionsConfig(conf.Get().SiteConfig())
 		if completionsConfig == nil {
 			http.Error(w, "completions are not configured or disabled", http.StatusInternalServerError)
+			return
 		}
 
 		var requestParams types.CodyCompletionRequestParameters
@@ -50,8 +63,18 @@ func newCompletionsHandler(
 			return
 		}
 
+		intent := requestParams.Intent
+		if intent == "" {
+			intent = defaultIntent
+		}
+		if !intent.IsValid() {
+			http.Error(w, fmt.Sprintf("unsupported intent %q", intent), http.StatusBadRequest)
+			return
+		}
+
 		// TODO: Model is not configurable but technically allowed in the request body right now.
-		requestParams.Model = getModel(requestParams, completionsConfig)
+		route := RouteCompletions(completionsConfig, intent, getModel(requestParams, completionsConfig))
+		requestParams.Model = route.Model
 
 		var err error
 		ctx, done := Trace(ctx, traceFamily, requestParams.Model).
@@ -60,10 +83,24 @@ func newCompletionsHandler(
 			Build()
 		defer done()
 
+		// 🚨 SECURITY: Refuse prompts including the content of repositories denied by
+		// the context filters.
+		filters, err := cody.GetContextFilters(ctx, db, logger)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, repo := range requestParams.Repositories {
+			if !filters.Allowed(ctx, "completions", api.RepoName(repo)) {
+				http.Error(w, fmt.Sprintf("the content of the repository %q may not be sent to Cody", repo), http.StatusForbidden)
+				return
+			}
+		}
+
 		completionClient, err := client.Get(
-			completionsConfig.Endpoint,
-			completionsConfig.Provider,
-			completionsConfig.AccessToken,
+			route.Endpoint,
+			route.Provider,
+			route.AccessToken,
 		)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -81,15 +118,66 @@ func newCompletionsHandler(
 			return
 		}
 
+		// Check token quota.
+		err = quota.Check(ctx)
+		if err != nil {
+			if unwrap, ok := err.(TokenQuotaExceededError); ok {
+				respondLimitExceeded(w, unwrap.Limit, unwrap.Used, unwrap.RetryAfter, unwrap)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Enforce guardrails on the prompt.
+		err = guard.CheckPrompt(ctx, intent, &requestParams.CompletionRequestParameters)
+		if err != nil {
+			respondGuardrailError(w, err)
+			return
+		}
+
+		completionClient = NewUsageTrackingClient(logger, db, quota, intent, route.Provider, completionClient)
+		completionClient = promptlog.NewLoggingClient(logger, db, intent, route.Provider, completionClient)
+		completionClient = guard.Client(intent, completionClient)
 		handle(ctx, requestParams.CompletionRequestParameters, completionClient, w)
 	})
 }
 
+// RouteCompletions returns the endpoint serving the completions requests of the
+// intent. Intents without a configured route are served by the default provider with
+// the given model.
+func RouteCompletions(cfg *conftypes.CompletionsConfig, intent types.CompletionIntent, defaultModel string) conftypes.CompletionsRoute {
+	if route, ok := cfg.Routes[string(intent)]; ok {
+		return route
+	}
+	return conftypes.CompletionsRoute{
+		Provider:    cfg.Provider,
+		Endpoint:    cfg.Endpoint,
+		AccessToken: cfg.AccessToken,
+		Model:       defaultModel,
+	}
+}
+
+// respondGuardrailError responds with the message of a guardrail refusing the
+// request, and with an internal error otherwise.
+func respondGuardrailError(w http.ResponseWriter, err error) {
+	var refusal *guardrails.RefusalError
+	if errors.As(err, &refusal) {
+		http.Error(w, refusal.Error(), http.StatusForbidden)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
 func respondRateLimited(w http.ResponseWriter, err RateLimitExceededError) {
-	// Rate limit exceeded, write well known headers and return correct status code.
-	w.Header().Set("x-ratelimit-limit", strconv.Itoa(err.Limit))
-	w.Header().Set("x-ratelimit-remaining", strconv.Itoa(max(err.Limit-err.Used, 0)))
-	w.Header().Set("retry-after", err.RetryAfter.Format(time.RFC1123))
+	respondLimitExceeded(w, err.Limit, err.Used, err.RetryAfter, err)
+}
+
+func respondLimitExceeded(w http.ResponseWriter, limit, used int, retryAfter time.Time, err error) {
+	// Limit exceeded, write well known headers and return correct status code.
+	w.Header().Set("x-ratelimit-limit", strconv.Itoa(limit))
+	w.Header().Set("x-ratelimit-remaining", strconv.Itoa(max(limit-used, 0)))
+	w.Header().Set("retry-after", retryAfter.Format(time.RFC1123))
 	http.Error(w, err.Error(), http.StatusTooManyRequests)
 }
 
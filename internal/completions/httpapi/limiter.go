@@ -61,17 +61,7 @@ func (r *rateLimiter) TryAcquire(ctx context.Context) (err error) {
 	key := userKey(a.UID, r.scope)
 	if !a.IsAuthenticated() {
 		// Fall back to the IP address, if provided in context (ie. this is a request handler).
-		req := requestclient.FromContext(ctx)
-		var ip string
-		if req != nil {
-			ip = req.IP
-			// Note: ForwardedFor header in general can be spoofed. For
-			// Sourcegraph.com we use a trusted value for this so this is a
-			// reliable value to rate limit with.
-			if req.ForwardedFor != "" {
-				ip = req.ForwardedFor
-			}
-		}
+		ip := clientIP(ctx)
 		if ip == "" {
 			return errors.Wrap(auth.ErrNotAuthenticated, "cannot claim rate limit for unauthenticated user without request context")
 		}
@@ -146,6 +136,22 @@ func (r *rateLimiter) TryAcquire(ctx context.Context) (err error) {
 	return nil
 }
 
+// clientIP returns the IP address of the client of the request in the context, or an
+// empty string if there is no request.
+func clientIP(ctx context.Context) string {
+	req := requestclient.FromContext(ctx)
+	if req == nil {
+		return ""
+	}
+	// Note: ForwardedFor header in general can be spoofed. For
+	// Sourcegraph.com we use a trusted value for this so this is a
+	// reliable value to rate limit with.
+	if req.ForwardedFor != "" {
+		return req.ForwardedFor
+	}
+	return req.IP
+}
+
 func userKey(userID int32, scope types.CompletionsFeature) string {
 	return fmt.Sprintf("user:%d:%s_requests", userID, scope)
 }
@@ -6,20 +6,22 @@ import (
 
 	"github.com/sourcegraph/log"
 
+	"github.com/sourcegraph/sourcegraph/internal/completions/guardrails"
 	"github.com/sourcegraph/sourcegraph/internal/completions/types"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/redispool"
 	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 // NewChatCompletionsStreamHandler is an http handler which streams back completions results.
-func NewChatCompletionsStreamHandler(logger log.Logger, db database.DB) http.Handler {
+func NewChatCompletionsStreamHandler(logger log.Logger, db database.DB, guard *guardrails.Guardrails) http.Handler {
 	logger = logger.Scoped("chat", "chat completions handler")
 	rl := NewRateLimiter(db, redispool.Store, types.CompletionsFeatureChat)
 
-	return newCompletionsHandler(rl, "chat", func(requestParams types.CodyCompletionRequestParameters, c *conftypes.CompletionsConfig) string {
+	return newCompletionsHandler(logger, db, rl, NewTokenQuota(redispool.Store), guard, "chat", types.CompletionIntentChat, func(requestParams types.CodyCompletionRequestParameters, c *conftypes.CompletionsConfig) string {
 		// No user defined models for now.
 		if requestParams.Fast {
 			return c.FastChatModel
@@ -41,6 +43,12 @@ func NewChatCompletionsStreamHandler(logger log.Logger, db database.DB) http.Han
 			func(event types.CompletionResponse) error {
 				return eventWriter.Event("completion", event)
 			})
+		var refusal *guardrails.RefusalError
+		if errors.As(err, &refusal) {
+			// The completion was withheld by a guardrail, which is not an error.
+			_ = eventWriter.Event("error", map[string]string{"error": refusal.Error()})
+			return
+		}
 		if err != nil {
 			l := trace.Logger(ctx, logger)
 
@@ -0,0 +1,139 @@
+package httpapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// TokenQuota enforces the daily limit of tokens a user may use for completions of any
+// kind.
+type TokenQuota interface {
+	// Check returns a TokenQuotaExceededError if the current actor used up their
+	// token quota for the day.
+	Check(ctx context.Context) error
+	// Add counts the given number of tokens against the quota of the current actor.
+	Add(ctx context.Context, tokens int) error
+}
+
+type TokenQuotaExceededError struct {
+	Limit      int
+	Used       int
+	RetryAfter time.Time
+}
+
+func (e TokenQuotaExceededError) Error() string {
+	return fmt.Sprintf("you exceeded your daily quota of %d tokens for completions. Current usage: %d. Retry after %s", e.Limit, e.Used, e.RetryAfter.Truncate(time.Second))
+}
+
+func NewTokenQuota(rstore redispool.KeyValue) TokenQuota {
+	return &tokenQuota{
+		rstore: rstore,
+		getLimit: func() int {
+			cfg := conf.GetCompletionsConfig(conf.Get().SiteConfig())
+			if cfg == nil {
+				return 0
+			}
+			return cfg.PerUserDailyTokenLimit
+		},
+	}
+}
+
+type tokenQuota struct {
+	rstore   redispool.KeyValue
+	getLimit func() int
+}
+
+func (q *tokenQuota) Check(ctx context.Context) error {
+	limit := q.getLimit()
+	if limit <= 0 {
+		// Token quota disabled.
+		return nil
+	}
+
+	key, err := tokenQuotaKey(ctx)
+	if err != nil || key == "" {
+		return err
+	}
+
+	rstore := q.rstore.WithContext(ctx)
+
+	used, err := rstore.Get(key).Int()
+	if err != nil && err != redis.ErrNil {
+		return errors.Wrap(err, "failed to read token quota counter")
+	}
+	if used < limit {
+		return nil
+	}
+
+	ttl, err := rstore.TTL(key)
+	if err != nil {
+		return errors.Wrap(err, "failed to get TTL for token quota counter")
+	}
+	return TokenQuotaExceededError{
+		Limit:      limit,
+		Used:       used,
+		RetryAfter: time.Now().Add(time.Duration(ttl) * time.Second),
+	}
+}
+
+func (q *tokenQuota) Add(ctx context.Context, tokens int) error {
+	if q.getLimit() <= 0 || tokens <= 0 {
+		return nil
+	}
+
+	key, err := tokenQuotaKey(ctx)
+	if err != nil || key == "" {
+		return err
+	}
+
+	rstore := q.rstore.WithContext(ctx)
+
+	// The check of the quota and the counting of tokens are not atomic, and the tokens
+	// of a completion are only known once it finished. A user may therefore exceed the
+	// quota by the tokens of the requests in flight when the quota was reached, which
+	// is a fine trade-off.
+	if _, err := rstore.Incrby(key, tokens); err != nil {
+		return errors.Wrap(err, "failed to increment token quota counter")
+	}
+
+	// Like the request rate limits, the quota is reset one day after the first
+	// completion counted against it.
+	ttl, err := rstore.TTL(key)
+	if err != nil {
+		return errors.Wrap(err, "failed to get TTL for token quota counter")
+	}
+	if ttl < 0 {
+		if err := rstore.Expire(key, int(24*time.Hour/time.Second)); err != nil {
+			return errors.Wrap(err, "failed to set expiry for token quota counter")
+		}
+	}
+
+	return nil
+}
+
+// tokenQuotaKey returns the key counting the tokens of the current actor, or an empty
+// key if the actor has no quota.
+func tokenQuotaKey(ctx context.Context) (string, error) {
+	a := actor.FromContext(ctx)
+	if a.IsInternal() {
+		return "", nil
+	}
+	if a.IsAuthenticated() {
+		return fmt.Sprintf("user:%d:completions_tokens", a.UID), nil
+	}
+
+	ip := clientIP(ctx)
+	if ip == "" {
+		return "", errors.Wrap(auth.ErrNotAuthenticated, "cannot count tokens of unauthenticated user without request context")
+	}
+	return fmt.Sprintf("anon:%s:completions_tokens", ip), nil
+}
@@ -0,0 +1,82 @@
+package httpapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/completions/types"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
+	"github.com/sourcegraph/sourcegraph/internal/requestclient"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestTokenQuota(t *testing.T) {
+	limit := 100
+	quota := &tokenQuota{rstore: redispool.MemoryKeyValue(), getLimit: func() int { return limit }}
+
+	user1 := actor.WithActor(context.Background(), actor.FromUser(1))
+	user2 := actor.WithActor(context.Background(), actor.FromUser(2))
+
+	require.NoError(t, quota.Check(user1))
+	require.NoError(t, quota.Add(user1, 60))
+	require.NoError(t, quota.Check(user1))
+	require.NoError(t, quota.Add(user1, 60))
+
+	var exceeded TokenQuotaExceededError
+	require.True(t, errors.As(quota.Check(user1), &exceeded))
+	assert.Equal(t, 100, exceeded.Limit)
+	assert.Equal(t, 120, exceeded.Used)
+
+	// Quotas are per user.
+	require.NoError(t, quota.Check(user2))
+
+	// Anonymous users are identified by their IP.
+	anonymous := requestclient.WithClient(context.Background(), &requestclient.Client{IP: "192.168.0.1"})
+	require.NoError(t, quota.Add(anonymous, 200))
+	require.Error(t, quota.Check(anonymous))
+	require.Error(t, quota.Check(context.Background()))
+
+	// Internal actors have no quota.
+	internal := actor.WithInternalActor(context.Background())
+	require.NoError(t, quota.Add(internal, 200))
+	require.NoError(t, quota.Check(internal))
+
+	// A limit of 0 disables the quota.
+	limit = 0
+	require.NoError(t, quota.Check(user1))
+}
+
+func TestRouteCompletions(t *testing.T) {
+	cfg := &conftypes.CompletionsConfig{
+		Provider:    conftypes.CompletionsProviderNameAnthropic,
+		Endpoint:    "https://api.anthropic.com/v1/complete",
+		AccessToken: "anthropic-token",
+		Routes: map[string]conftypes.CompletionsRoute{
+			"autocomplete": {
+				Provider:    conftypes.CompletionsProviderNameOpenAI,
+				Endpoint:    "https://llm.example.com",
+				AccessToken: "token",
+				Model:       "starcoder",
+			},
+		},
+	}
+
+	assert.Equal(t, cfg.Routes["autocomplete"], RouteCompletions(cfg, types.CompletionIntentAutocomplete, "claude-instant-v1"))
+	assert.Equal(t, conftypes.CompletionsRoute{
+		Provider:    conftypes.CompletionsProviderNameAnthropic,
+		Endpoint:    "https://api.anthropic.com/v1/complete",
+		AccessToken: "anthropic-token",
+		Model:       "claude-v1",
+	}, RouteCompletions(cfg, types.CompletionIntentChat, "claude-v1"))
+}
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, estimateTokens(""))
+	assert.Equal(t, 1, estimateTokens("abc"))
+	assert.Equal(t, 3, estimateTokens("hello world!"))
+}
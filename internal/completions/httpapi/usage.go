@@ -0,0 +1,144 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/completions/types"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/requestclient"
+)
+
+// usageEventName is the name of the event logged for the usage of every completion.
+const usageEventName = "CodyCompletionsTokenUsage"
+
+var completionTokens = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_completions_tokens_total",
+	Help: "The estimated number of tokens used by completions.",
+}, []string{"intent", "provider", "model", "type"})
+
+// NewUsageTrackingClient wraps a completions client to count the tokens of every
+// completion against the token quota of the current actor, and to record the usage
+// for billing and telemetry.
+func NewUsageTrackingClient(
+	logger log.Logger,
+	db database.DB,
+	quota TokenQuota,
+	intent types.CompletionIntent,
+	provider conftypes.CompletionsProviderName,
+	client types.CompletionsClient,
+) types.CompletionsClient {
+	return &usageTrackingClient{
+		CompletionsClient: client,
+		logger:            logger.Scoped("usage", "completions usage tracking"),
+		db:                db,
+		quota:             quota,
+		intent:            intent,
+		provider:          provider,
+	}
+}
+
+type usageTrackingClient struct {
+	types.CompletionsClient
+	logger   log.Logger
+	db       database.DB
+	quota    TokenQuota
+	intent   types.CompletionIntent
+	provider conftypes.CompletionsProviderName
+}
+
+func (c *usageTrackingClient) Stream(ctx context.Context, feature types.CompletionsFeature, params types.CompletionRequestParameters, sendEvent types.SendCompletionEvent) error {
+	// Every event contains the completion so far.
+	var completion string
+	err := c.CompletionsClient.Stream(ctx, feature, params, func(event types.CompletionResponse) error {
+		completion = event.Completion
+		return sendEvent(event)
+	})
+	c.recordUsage(ctx, params, completion, err)
+	return err
+}
+
+func (c *usageTrackingClient) Complete(ctx context.Context, feature types.CompletionsFeature, params types.CompletionRequestParameters) (*types.CompletionResponse, error) {
+	resp, err := c.CompletionsClient.Complete(ctx, feature, params)
+	var completion string
+	if resp != nil {
+		completion = resp.Completion
+	}
+	c.recordUsage(ctx, params, completion, err)
+	return resp, err
+}
+
+// tokenUsage is the public argument of the usage event.
+type tokenUsage struct {
+	Intent           types.CompletionIntent            `json:"intent"`
+	Provider         conftypes.CompletionsProviderName `json:"provider"`
+	Model            string                            `json:"model"`
+	PromptTokens     int                               `json:"promptTokens"`
+	CompletionTokens int                               `json:"completionTokens"`
+}
+
+func (c *usageTrackingClient) recordUsage(ctx context.Context, params types.CompletionRequestParameters, completion string, err error) {
+	if err != nil && completion == "" {
+		// The request failed before producing anything.
+		return
+	}
+
+	// The request may have been canceled by now, but its usage must still be recorded.
+	a := actor.FromContext(ctx)
+	client := requestclient.FromContext(ctx)
+	ctx = requestclient.WithClient(actor.WithActor(context.Background(), a), client)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	prompt := params.Prompt
+	for _, m := range params.Messages {
+		prompt += m.Text
+	}
+	usage := tokenUsage{
+		Intent:           c.intent,
+		Provider:         c.provider,
+		Model:            params.Model,
+		PromptTokens:     estimateTokens(prompt),
+		CompletionTokens: estimateTokens(completion),
+	}
+
+	completionTokens.WithLabelValues(string(usage.Intent), string(usage.Provider), usage.Model, "prompt").Add(float64(usage.PromptTokens))
+	completionTokens.WithLabelValues(string(usage.Intent), string(usage.Provider), usage.Model, "completion").Add(float64(usage.CompletionTokens))
+
+	if err := c.quota.Add(ctx, usage.PromptTokens+usage.CompletionTokens); err != nil {
+		c.logger.Warn("failed to count tokens against quota", log.Error(err))
+	}
+
+	publicArgument, err := json.Marshal(usage)
+	if err != nil {
+		c.logger.Warn("failed to marshal token usage", log.Error(err))
+		return
+	}
+	event := &database.Event{
+		Name:           usageEventName,
+		UserID:         uint32(a.UID),
+		PublicArgument: publicArgument,
+		Source:         "BACKEND",
+		Timestamp:      time.Now(),
+	}
+	if !a.IsAuthenticated() {
+		event.AnonymousUserID = "backend"
+	}
+	if err := c.db.EventLogs().Insert(ctx, event); err != nil {
+		c.logger.Warn("failed to record token usage", log.Error(err))
+	}
+}
+
+// estimateTokens estimates the number of tokens of the text. Tokenizers are specific to
+// models, but most of them average about four characters of English text and code per
+// token.
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
@@ -0,0 +1,176 @@
+// Package promptlog records the prompts and responses of Cody completions requests
+// for audit purposes, if enabled in the site configuration.
+package promptlog
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/completions/types"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
+	itypes "github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// RedactionHook redacts personal data from a prompt or response before it is
+// recorded.
+type RedactionHook func(text string) string
+
+var (
+	redactionHooksMu sync.RWMutex
+	redactionHooks   []RedactionHook
+)
+
+// RegisterRedactionHook registers a hook applied to every prompt and response before
+// it is recorded, in addition to the redaction patterns of the site configuration.
+func RegisterRedactionHook(hook RedactionHook) {
+	redactionHooksMu.Lock()
+	defer redactionHooksMu.Unlock()
+	redactionHooks = append(redactionHooks, hook)
+}
+
+const redactedText = "[REDACTED]"
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// This matches the documented value in the site configuration schema.
+const defaultRetention = 30 * 24 * time.Hour
+
+// Retention returns how long records are retained.
+func Retention(cfg *schema.CodyPromptLogging) time.Duration {
+	if cfg == nil || cfg.Retention == "" {
+		return defaultRetention
+	}
+	retention, err := time.ParseDuration(cfg.Retention)
+	if err != nil {
+		return defaultRetention
+	}
+	if retention < time.Hour {
+		return time.Hour
+	}
+	return retention
+}
+
+// NewLoggingClient wraps a completions client to record the prompts and responses of
+// its completions while prompt logging is enabled.
+func NewLoggingClient(
+	logger log.Logger,
+	db database.DB,
+	intent types.CompletionIntent,
+	provider conftypes.CompletionsProviderName,
+	client types.CompletionsClient,
+) types.CompletionsClient {
+	return &loggingClient{
+		CompletionsClient: client,
+		logger:            logger.Scoped("promptlog", "records prompts and responses"),
+		db:                db,
+		intent:            intent,
+		provider:          provider,
+		getConfig:         func() *schema.CodyPromptLogging { return conf.Get().SiteConfig().CodyPromptLogging },
+	}
+}
+
+type loggingClient struct {
+	types.CompletionsClient
+	logger    log.Logger
+	db        database.DB
+	intent    types.CompletionIntent
+	provider  conftypes.CompletionsProviderName
+	getConfig func() *schema.CodyPromptLogging
+}
+
+func (c *loggingClient) Stream(ctx context.Context, feature types.CompletionsFeature, params types.CompletionRequestParameters, sendEvent types.SendCompletionEvent) error {
+	cfg := c.getConfig()
+	if cfg == nil || !cfg.Enabled {
+		return c.CompletionsClient.Stream(ctx, feature, params, sendEvent)
+	}
+
+	// Every event contains the completion so far.
+	var completion string
+	err := c.CompletionsClient.Stream(ctx, feature, params, func(event types.CompletionResponse) error {
+		completion = event.Completion
+		return sendEvent(event)
+	})
+	if err == nil || completion != "" {
+		c.record(ctx, cfg, params, completion)
+	}
+	return err
+}
+
+func (c *loggingClient) Complete(ctx context.Context, feature types.CompletionsFeature, params types.CompletionRequestParameters) (*types.CompletionResponse, error) {
+	resp, err := c.CompletionsClient.Complete(ctx, feature, params)
+	if cfg := c.getConfig(); err == nil && cfg != nil && cfg.Enabled {
+		c.record(ctx, cfg, params, resp.Completion)
+	}
+	return resp, err
+}
+
+func (c *loggingClient) record(ctx context.Context, cfg *schema.CodyPromptLogging, params types.CompletionRequestParameters, completion string) {
+	// The request may have been canceled by now, but it must still be recorded.
+	a := actor.FromContext(ctx)
+	ctx, cancel := context.WithTimeout(actor.WithActor(context.Background(), a), 10*time.Second)
+	defer cancel()
+
+	redact := newRedactor(c.logger, cfg)
+
+	var messages []itypes.CodyPromptMessage
+	if params.Prompt != "" {
+		messages = append(messages, itypes.CodyPromptMessage{Speaker: types.HUMAN_MESSAGE_SPEAKER, Text: redact(params.Prompt)})
+	}
+	for _, m := range params.Messages {
+		messages = append(messages, itypes.CodyPromptMessage{Speaker: m.Speaker, Text: redact(m.Text)})
+	}
+
+	entry := &itypes.CodyPromptLog{
+		UserID:   a.UID,
+		Intent:   string(c.intent),
+		Provider: string(c.provider),
+		Model:    params.Model,
+		Prompt:   itypes.NewUnencryptedCodyPrompt(messages),
+		Response: encryption.NewUnencrypted(redact(completion)),
+	}
+	if !a.IsAuthenticated() {
+		entry.AnonymousUserID = a.AnonymousUID
+	}
+
+	if err := c.db.CodyPromptLogs(keyring.Default().CodyPromptLogKey).Create(ctx, entry); err != nil {
+		c.logger.Error("failed to record prompt", log.Error(err))
+	}
+}
+
+// newRedactor returns a function redacting email addresses, the patterns of the
+// configuration, and the data redacted by the registered hooks.
+func newRedactor(logger log.Logger, cfg *schema.CodyPromptLogging) func(string) string {
+	patterns := []*regexp.Regexp{emailPattern}
+	for _, p := range cfg.RedactPatterns {
+		pattern, err := regexp.Compile(p)
+		if err != nil {
+			logger.Warn("skipping invalid redaction pattern", log.Error(err))
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	redactionHooksMu.RLock()
+	hooks := redactionHooks
+	redactionHooksMu.RUnlock()
+
+	return func(text string) string {
+		for _, pattern := range patterns {
+			text = pattern.ReplaceAllLiteralString(text, redactedText)
+		}
+		for _, hook := range hooks {
+			text = hook(text)
+		}
+		return text
+	}
+}
@@ -0,0 +1,101 @@
+package promptlog
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/completions/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	itypes "github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestRetention(t *testing.T) {
+	assert.Equal(t, 720*time.Hour, Retention(nil))
+	assert.Equal(t, 720*time.Hour, Retention(&schema.CodyPromptLogging{Retention: "invalid"}))
+	assert.Equal(t, 48*time.Hour, Retention(&schema.CodyPromptLogging{Retention: "48h"}))
+	assert.Equal(t, time.Hour, Retention(&schema.CodyPromptLogging{Retention: "5m"}))
+}
+
+type completionsClient struct {
+	completion string
+}
+
+func (c completionsClient) Stream(_ context.Context, _ types.CompletionsFeature, _ types.CompletionRequestParameters, sendEvent types.SendCompletionEvent) error {
+	for i := 1; i <= len(c.completion); i++ {
+		if err := sendEvent(types.CompletionResponse{Completion: c.completion[:i]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c completionsClient) Complete(context.Context, types.CompletionsFeature, types.CompletionRequestParameters) (*types.CompletionResponse, error) {
+	return &types.CompletionResponse{Completion: c.completion}, nil
+}
+
+func TestLoggingClient(t *testing.T) {
+	store := database.NewMockCodyPromptLogStore()
+	db := database.NewMockDB()
+	db.CodyPromptLogsFunc.SetDefaultReturn(store)
+
+	cfg := &schema.CodyPromptLogging{}
+	client := &loggingClient{
+		CompletionsClient: completionsClient{completion: "Ask Bob (bob@example.com) about ACME-1234."},
+		logger:            logtest.Scoped(t),
+		db:                db,
+		intent:            types.CompletionIntentChat,
+		provider:          "anthropic",
+		getConfig:         func() *schema.CodyPromptLogging { return cfg },
+	}
+
+	ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+	params := types.CompletionRequestParameters{
+		Model:    "claude-v1",
+		Messages: []types.Message{{Speaker: types.HUMAN_MESSAGE_SPEAKER, Text: "Who owns ACME-1234? I am alice@example.com."}},
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		_, err := client.Complete(ctx, types.CompletionsFeatureChat, params)
+		require.NoError(t, err)
+		assert.Empty(t, store.CreateFunc.History())
+	})
+
+	*cfg = schema.CodyPromptLogging{Enabled: true, RedactPatterns: []string{`ACME-[0-9]+`}}
+	RegisterRedactionHook(func(text string) string { return strings.ReplaceAll(text, "Bob", "[NAME]") })
+	t.Cleanup(func() { redactionHooks = nil })
+
+	for _, stream := range []bool{false, true} {
+		var err error
+		if stream {
+			err = client.Stream(ctx, types.CompletionsFeatureChat, params, func(types.CompletionResponse) error { return nil })
+		} else {
+			_, err = client.Complete(ctx, types.CompletionsFeatureChat, params)
+		}
+		require.NoError(t, err)
+
+		history := store.CreateFunc.History()
+		require.NotEmpty(t, history)
+		entry := history[len(history)-1].Arg1
+
+		assert.Equal(t, int32(1), entry.UserID)
+		assert.Equal(t, "chat", entry.Intent)
+		assert.Equal(t, "anthropic", entry.Provider)
+		assert.Equal(t, "claude-v1", entry.Model)
+
+		prompt, err := entry.Prompt.Decrypt(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []itypes.CodyPromptMessage{{Speaker: "human", Text: "Who owns [REDACTED]? I am [REDACTED]."}}, prompt)
+		response, err := entry.Response.Decrypt(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "Ask [NAME] ([REDACTED]) about [REDACTED].", response)
+	}
+	assert.Len(t, store.CreateFunc.History(), 2)
+}
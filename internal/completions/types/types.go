@@ -44,6 +44,35 @@ type CodyCompletionRequestParameters struct {
 	// When Fast is true, then it is used as a hint to prefer a model
 	// that is faster (but probably "dumber").
 	Fast bool
+
+	// Intent is what the completion is used for, which determines the model
+	// serving the request. Defaults to the intent of the endpoint.
+	Intent CompletionIntent `json:"intent,omitempty"`
+
+	// Repositories are the names of the repositories whose content is included in
+	// the prompt. Requests including the content of repositories denied by the
+	// context filters are refused.
+	Repositories []string `json:"repositories,omitempty"`
+}
+
+// CompletionIntent is what a completion is used for. Site admins can route the
+// requests of each intent to a different model.
+type CompletionIntent string
+
+const (
+	CompletionIntentChat         CompletionIntent = "chat"
+	CompletionIntentEdit         CompletionIntent = "edit"
+	CompletionIntentAutocomplete CompletionIntent = "autocomplete"
+)
+
+func (i CompletionIntent) IsValid() bool {
+	switch i {
+	case CompletionIntentChat,
+		CompletionIntentEdit,
+		CompletionIntentAutocomplete:
+		return true
+	}
+	return false
 }
 
 type CompletionRequestParameters struct {
@@ -2,6 +2,7 @@ package conf
 
 import (
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
@@ -10,6 +11,8 @@ import (
 func init() {
 	ContributeValidator(completionsConfigValidator)
 	ContributeValidator(embeddingsConfigValidator)
+	ContributeValidator(promptLoggingConfigValidator)
+	ContributeValidator(contextFiltersConfigValidator)
 }
 
 func completionsConfigValidator(q conftypes.SiteConfigQuerier) Problems {
@@ -23,6 +26,35 @@ func completionsConfigValidator(q conftypes.SiteConfigQuerier) Problems {
 		problems = append(problems, "'completions.enabled' has been superceded by 'cody.enabled', please migrate to the new configuration.")
 	}
 
+	if completionsConf.Provider == string(conftypes.CompletionsProviderNameAzureOpenAI) && completionsConf.Endpoint == "" {
+		problems = append(problems, "\"completions.endpoint\" is required for the provider \"azure-openai\"")
+	}
+
+	seenIntents := map[string]struct{}{}
+	for _, route := range completionsConf.Routes {
+		if _, ok := seenIntents[route.Intent]; ok {
+			problems = append(problems, fmt.Sprintf("\"completions.routes\" contains more than one route for the intent %q", route.Intent))
+		}
+		seenIntents[route.Intent] = struct{}{}
+
+		if route.Provider == string(conftypes.CompletionsProviderNameAzureOpenAI) && route.Endpoint == "" {
+			problems = append(problems, fmt.Sprintf("The endpoint of the \"completions.routes\" route for the intent %q is required for the provider \"azure-openai\"", route.Intent))
+		}
+	}
+
+	if guardrails := completionsConf.Guardrails; guardrails != nil {
+		for _, pattern := range guardrails.SecretPatterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				problems = append(problems, fmt.Sprintf("Could not parse the \"completions.guardrails.secretPatterns\" pattern %q. %s", pattern, err))
+			}
+		}
+		for _, policy := range guardrails.Policies {
+			if _, err := regexp.Compile(policy.Pattern); err != nil {
+				problems = append(problems, fmt.Sprintf("Could not parse the pattern of the \"completions.guardrails.policies\" policy %q. %s", policy.Name, err))
+			}
+		}
+	}
+
 	if len(problems) > 0 {
 		return NewSiteProblems(problems...)
 	}
@@ -61,3 +93,54 @@ func embeddingsConfigValidator(q conftypes.SiteConfigQuerier) Problems {
 
 	return nil
 }
+
+func promptLoggingConfigValidator(q conftypes.SiteConfigQuerier) Problems {
+	problems := []string{}
+	promptLoggingConf := q.SiteConfig().CodyPromptLogging
+	if promptLoggingConf == nil {
+		return nil
+	}
+
+	if retention := promptLoggingConf.Retention; retention != "" {
+		if _, err := time.ParseDuration(retention); err != nil {
+			problems = append(problems, fmt.Sprintf("Could not parse \"cody.promptLogging.retention: %s\". %s", retention, err))
+		}
+	}
+
+	for _, pattern := range promptLoggingConf.RedactPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			problems = append(problems, fmt.Sprintf("Could not parse the \"cody.promptLogging.redactPatterns\" pattern %q. %s", pattern, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return NewSiteProblems(problems...)
+	}
+
+	return nil
+}
+
+func contextFiltersConfigValidator(q conftypes.SiteConfigQuerier) Problems {
+	problems := []string{}
+	contextFiltersConf := q.SiteConfig().CodyContextFilters
+	if contextFiltersConf == nil {
+		return nil
+	}
+
+	for _, pattern := range contextFiltersConf.Include {
+		if _, err := regexp.Compile(pattern); err != nil {
+			problems = append(problems, fmt.Sprintf("Could not parse the \"cody.contextFilters.include\" pattern %q. %s", pattern, err))
+		}
+	}
+	for _, pattern := range contextFiltersConf.Exclude {
+		if _, err := regexp.Compile(pattern); err != nil {
+			problems = append(problems, fmt.Sprintf("Could not parse the \"cody.contextFilters.exclude\" pattern %q. %s", pattern, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return NewSiteProblems(problems...)
+	}
+
+	return nil
+}
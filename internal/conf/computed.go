@@ -279,6 +279,19 @@ func CodeIntelAutoIndexingPolicyRepositoryMatchLimit() int {
 	return *val
 }
 
+func CodeIntelAutoIndexingDependencyIndexingMaxDepth() int {
+	val := Get().CodeIntelAutoIndexingDependencyIndexingMaxDepth
+	if val == nil || *val < -1 {
+		return -1
+	}
+
+	return *val
+}
+
+func CodeIntelAutoIndexingDependencyVersionPins() map[string]string {
+	return Get().CodeIntelAutoIndexingDependencyVersionPins
+}
+
 func CodeIntelRankingDocumentReferenceCountsEnabled() bool {
 	if enabled := Get().CodeIntelRankingDocumentReferenceCountsEnabled; enabled != nil {
 		return *enabled
@@ -341,6 +354,13 @@ func IsAccessRequestEnabled() bool {
 	return authAccessRequest == nil || authAccessRequest.Enabled == nil || *authAccessRequest.Enabled
 }
 
+// IsExternalIdentityAccessRequestEnabled returns whether users signing in with an
+// auth provider that does not allow signup submit an access request.
+func IsExternalIdentityAccessRequestEnabled() bool {
+	authAccessRequest := Get().AuthAccessRequest
+	return IsAccessRequestEnabled() && authAccessRequest != nil && authAccessRequest.ExternalIdentities
+}
+
 // AuthPrimaryLoginProvidersCount returns the number of primary login providers
 // configured, or 3 (the default) if not explicitly configured.
 // This is only used for the UI
@@ -362,6 +382,20 @@ func SearchSymbolsParallelism() int {
 	return val
 }
 
+// SearchResultCacheTTL returns 1 hour, or the site config
+// "search.resultCacheTTLSeconds" value if configured. Zero means the cache is
+// disabled.
+func SearchResultCacheTTL() time.Duration {
+	val := Get().SearchResultCacheTTLSeconds
+	if val == nil {
+		return time.Hour
+	}
+	if *val <= 0 {
+		return 0
+	}
+	return time.Duration(*val) * time.Second
+}
+
 func EventLoggingEnabled() bool {
 	val := ExperimentalFeatures().EventLogging
 	if val == "" {
@@ -561,6 +595,17 @@ func GitLongCommandTimeout() time.Duration {
 	return time.Duration(val) * time.Second
 }
 
+// DeletedUsersRetention returns how long soft-deleted users are retained and
+// can be restored before they are permanently deleted. It returns 0 if deleted
+// users are retained indefinitely.
+func DeletedUsersRetention() time.Duration {
+	days := Get().AuthDeletedUsersRetentionDays
+	if days <= 0 {
+		return 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
 // GitMaxCodehostRequestsPerSecond returns maximum number of remote code host
 // git operations to be run per second per gitserver. If not set, it returns the
 // default value -1.
@@ -704,6 +749,12 @@ func GetCompletionsConfig(siteConfig schema.SiteConfiguration) (c *conftypes.Com
 		if completionsConfig.CompletionModel == "" {
 			completionsConfig.CompletionModel = "claude-instant-v1"
 		}
+	} else if completionsConfig.Provider == string(conftypes.CompletionsProviderNameAzureOpenAI) {
+		// Azure OpenAI endpoints are specific to a resource and deployment, so there
+		// are no defaults for the endpoint or the models.
+		if completionsConfig.Endpoint == "" || completionsConfig.AccessToken == "" {
+			return nil
+		}
 	}
 
 	// Make sure models are always treated case-insensitive.
@@ -741,11 +792,53 @@ func GetCompletionsConfig(siteConfig schema.SiteConfiguration) (c *conftypes.Com
 		Endpoint:                         completionsConfig.Endpoint,
 		PerUserDailyLimit:                completionsConfig.PerUserDailyLimit,
 		PerUserCodeCompletionsDailyLimit: completionsConfig.PerUserCodeCompletionsDailyLimit,
+		PerUserDailyTokenLimit:           completionsConfig.PerUserDailyTokenLimit,
+		Routes:                           getCompletionsRoutes(completionsConfig.Routes, siteConfig),
 	}
 
 	return computedConfig
 }
 
+// getCompletionsRoutes returns the completions routes by intent, with defaults applied.
+// Routes that cannot be used, e.g. because they lack an access token, are skipped.
+func getCompletionsRoutes(routes []*schema.CompletionsRoute, siteConfig schema.SiteConfiguration) map[string]conftypes.CompletionsRoute {
+	if len(routes) == 0 {
+		return nil
+	}
+
+	computed := make(map[string]conftypes.CompletionsRoute, len(routes))
+	for _, route := range routes {
+		r := conftypes.CompletionsRoute{
+			Provider:    conftypes.CompletionsProviderName(route.Provider),
+			Endpoint:    route.Endpoint,
+			AccessToken: route.AccessToken,
+			Model:       strings.ToLower(route.Model),
+		}
+
+		if r.Endpoint == "" {
+			switch r.Provider {
+			case conftypes.CompletionsProviderNameSourcegraph:
+				r.Endpoint = "https://cody-gateway.sourcegraph.com"
+			case conftypes.CompletionsProviderNameOpenAI:
+				r.Endpoint = "https://api.openai.com/v1/chat/completions"
+			case conftypes.CompletionsProviderNameAnthropic:
+				r.Endpoint = "https://api.anthropic.com/v1/complete"
+			default:
+				continue
+			}
+		}
+		if r.Provider == conftypes.CompletionsProviderNameSourcegraph {
+			r.AccessToken = getSourcegraphProviderAccessToken(r.AccessToken, siteConfig)
+		}
+		if r.AccessToken == "" || r.Model == "" {
+			continue
+		}
+
+		computed[route.Intent] = r
+	}
+	return computed
+}
+
 const embeddingsMaxFileSizeBytes = 1000000
 
 // GetEmbeddingsConfig evaluates a complete embeddings configuration based on
@@ -959,7 +1052,7 @@ func defaultMaxPromptTokens(provider conftypes.CompletionsProviderName, model st
 		return 9_000
 	case conftypes.CompletionsProviderNameAnthropic:
 		return anthropicDefaultMaxPromptTokens(model)
-	case conftypes.CompletionsProviderNameOpenAI:
+	case conftypes.CompletionsProviderNameOpenAI, conftypes.CompletionsProviderNameAzureOpenAI:
 		return openaiDefaultMaxPromptTokens(model)
 	}
 
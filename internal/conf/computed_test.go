@@ -417,6 +417,79 @@ func TestGetCompletionsConfig(t *testing.T) {
 				Endpoint:                 "https://api.anthropic.com/v1/complete",
 			},
 		},
+		{
+			name: "anthropic completions with routes",
+			siteConfig: schema.SiteConfiguration{
+				CodyEnabled: pointers.Ptr(true),
+				LicenseKey:  licenseKey,
+				Completions: &schema.Completions{
+					Provider:               "anthropic",
+					AccessToken:            "asdf",
+					PerUserDailyTokenLimit: 1000,
+					Routes: []*schema.CompletionsRoute{
+						{Intent: "autocomplete", Provider: "openai", Endpoint: "https://llm.example.com", AccessToken: "token", Model: "StarCoder"},
+						{Intent: "edit", Provider: "sourcegraph", Model: "anthropic/claude-v1"},
+						// Skipped, because there is no access token.
+						{Intent: "chat", Provider: "anthropic", Model: "claude-v1"},
+					},
+				},
+			},
+			wantConfig: &conftypes.CompletionsConfig{
+				ChatModel:                "claude-v1",
+				ChatModelMaxTokens:       9000,
+				FastChatModel:            "claude-instant-v1",
+				FastChatModelMaxTokens:   9000,
+				CompletionModel:          "claude-instant-v1",
+				CompletionModelMaxTokens: 9000,
+				AccessToken:              "asdf",
+				Provider:                 "anthropic",
+				Endpoint:                 "https://api.anthropic.com/v1/complete",
+				PerUserDailyTokenLimit:   1000,
+				Routes: map[string]conftypes.CompletionsRoute{
+					"autocomplete": {Provider: "openai", Endpoint: "https://llm.example.com", AccessToken: "token", Model: "starcoder"},
+					"edit":         {Provider: "sourcegraph", Endpoint: "https://cody-gateway.sourcegraph.com", AccessToken: licenseAccessToken, Model: "anthropic/claude-v1"},
+				},
+			},
+		},
+		{
+			name: "azure openai completions",
+			siteConfig: schema.SiteConfiguration{
+				CodyEnabled: pointers.Ptr(true),
+				Completions: &schema.Completions{
+					Provider:        "azure-openai",
+					AccessToken:     "asdf",
+					Endpoint:        "https://example.openai.azure.com/openai/deployments/chat/chat/completions?api-version=2023-05-15",
+					ChatModel:       "gpt-4",
+					FastChatModel:   "gpt-35-turbo",
+					CompletionModel: "gpt-35-turbo",
+				},
+			},
+			wantConfig: &conftypes.CompletionsConfig{
+				ChatModel:                "gpt-4",
+				ChatModelMaxTokens:       8000,
+				FastChatModel:            "gpt-35-turbo",
+				FastChatModelMaxTokens:   4000,
+				CompletionModel:          "gpt-35-turbo",
+				CompletionModelMaxTokens: 4000,
+				AccessToken:              "asdf",
+				Provider:                 "azure-openai",
+				Endpoint:                 "https://example.openai.azure.com/openai/deployments/chat/chat/completions?api-version=2023-05-15",
+			},
+		},
+		{
+			name: "azure openai completions without endpoint",
+			siteConfig: schema.SiteConfiguration{
+				CodyEnabled: pointers.Ptr(true),
+				Completions: &schema.Completions{
+					Provider:        "azure-openai",
+					AccessToken:     "asdf",
+					ChatModel:       "gpt-4",
+					FastChatModel:   "gpt-35-turbo",
+					CompletionModel: "gpt-35-turbo",
+				},
+			},
+			wantDisabled: true,
+		},
 		{
 			name: "anthropic completions, with only completions.enabled",
 			siteConfig: schema.SiteConfiguration{
@@ -17,6 +17,19 @@ type CompletionsConfig struct {
 	Endpoint                         string
 	PerUserDailyLimit                int
 	PerUserCodeCompletionsDailyLimit int
+	PerUserDailyTokenLimit           int
+
+	// Routes maps intents of completions requests to the endpoints serving them,
+	// instead of the provider and models above.
+	Routes map[string]CompletionsRoute
+}
+
+// CompletionsRoute is an endpoint serving the completions requests of an intent.
+type CompletionsRoute struct {
+	Provider    CompletionsProviderName
+	Endpoint    string
+	AccessToken string
+	Model       string
 }
 
 type CompletionsProviderName string
@@ -24,6 +37,7 @@ type CompletionsProviderName string
 const (
 	CompletionsProviderNameAnthropic   CompletionsProviderName = "anthropic"
 	CompletionsProviderNameOpenAI      CompletionsProviderName = "openai"
+	CompletionsProviderNameAzureOpenAI CompletionsProviderName = "azure-openai"
 	CompletionsProviderNameSourcegraph CompletionsProviderName = "sourcegraph"
 )
 
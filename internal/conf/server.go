@@ -54,6 +54,14 @@ func (s *Server) Write(ctx context.Context, input conftypes.RawUnified, lastID i
 		return err
 	}
 
+	s.Reload()
+	return nil
+}
+
+// Reload notifies the server that the configuration in its source was updated
+// by other means than Write, for example within a database transaction, and
+// waits until the new configuration has been read.
+func (s *Server) Reload() {
 	// Wait for the change to the configuration file to be detected. Otherwise
 	// we would return to the caller earlier than server.Raw() would return the
 	// new configuration.
@@ -63,8 +71,6 @@ func (s *Server) Write(ctx context.Context, input conftypes.RawUnified, lastID i
 	// Get notified that the update has been read (it gets closed) - don't write
 	// until this is done.
 	<-doneReading
-
-	return nil
 }
 
 // Start initializes the server instance.
@@ -207,6 +207,7 @@ var siteConfigSecrets = []struct {
 	editPaths []string
 }{
 	{readPath: `executors\.accessToken`, editPaths: []string{"executors.accessToken"}},
+	{readPath: `executors\.secretManagers.vault.token`, editPaths: []string{"executors.secretManagers", "vault", "token"}},
 	{readPath: `email\.smtp.username`, editPaths: []string{"email.smtp", "username"}},
 	{readPath: `email\.smtp.password`, editPaths: []string{"email.smtp", "password"}},
 	{readPath: `organizationInvitations.signingKey`, editPaths: []string{"organizationInvitations", "signingKey"}},
@@ -221,6 +222,8 @@ var siteConfigSecrets = []struct {
 	{readPath: `embeddings.accessToken`, editPaths: []string{"embeddings", "accessToken"}},
 	{readPath: `completions.accessToken`, editPaths: []string{"completions", "accessToken"}},
 	{readPath: `app.dotcomAuthToken`, editPaths: []string{"app", "dotcomAuthToken"}},
+	{readPath: `exportUsageTelemetry.sink.authorization`, editPaths: []string{"exportUsageTelemetry", "sink", "authorization"}},
+	{readPath: `exportUsageTelemetry.sink.secretAccessKey`, editPaths: []string{"exportUsageTelemetry", "sink", "secretAccessKey"}},
 }
 
 // UnredactSecrets unredacts unchanged secrets back to their original value for
@@ -280,6 +283,25 @@ func UnredactSecrets(input string, raw conftypes.RawUnified) (string, error) {
 		return input, errors.Wrap(err, `unredact "auth.providers"`)
 	}
 
+	// Completions routes are identified by their intent, which is unique.
+	if newCfg.Completions != nil && len(newCfg.Completions.Routes) > 0 {
+		oldRouteSecrets := map[string]string{}
+		if oldCfg.Completions != nil {
+			for _, route := range oldCfg.Completions.Routes {
+				oldRouteSecrets[route.Intent] = route.AccessToken
+			}
+		}
+		for _, route := range newCfg.Completions.Routes {
+			if route.AccessToken == redactedSecret {
+				route.AccessToken = oldRouteSecrets[route.Intent]
+			}
+		}
+		unredactedSite, err = jsonc.Edit(unredactedSite, newCfg.Completions.Routes, "completions", "routes")
+		if err != nil {
+			return input, errors.Wrap(err, `unredact "completions > routes"`)
+		}
+	}
+
 	for _, secret := range siteConfigSecrets {
 		v, err := jsonc.ReadProperty(unredactedSite, secret.editPaths...)
 		if err != nil {
@@ -389,6 +411,18 @@ func redactConfSecrets(raw conftypes.RawUnified, hashSecrets bool) (empty confty
 		}
 	}
 
+	if cfg.Completions != nil && len(cfg.Completions.Routes) > 0 {
+		for _, route := range cfg.Completions.Routes {
+			if route.AccessToken != "" {
+				route.AccessToken = getRedactedSecret(route.AccessToken)
+			}
+		}
+		redactedSite, err = jsonc.Edit(redactedSite, cfg.Completions.Routes, "completions", "routes")
+		if err != nil {
+			return empty, errors.Wrap(err, `redact "completions > routes"`)
+		}
+	}
+
 	for _, secret := range siteConfigSecrets {
 		v, err := jsonc.ReadProperty(redactedSite, secret.editPaths...)
 		if err != nil {
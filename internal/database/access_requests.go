@@ -10,6 +10,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -79,6 +80,13 @@ type AccessRequestStore interface {
 	Update(context.Context, *types.AccessRequest) (*types.AccessRequest, error)
 	GetByID(context.Context, int32) (*types.AccessRequest, error)
 	GetByEmail(context.Context, string) (*types.AccessRequest, error)
+	// GetByExternalAccount returns the request made with the given external identity.
+	GetByExternalAccount(context.Context, extsvc.AccountSpec) (*types.AccessRequest, error)
+	// BindExternalAccount binds the pending request with the email of the given
+	// request, which is not bound to an external identity yet, to the external
+	// account of the given request. The email must have been verified by the
+	// auth provider of the external account.
+	BindExternalAccount(context.Context, *types.AccessRequest) (*types.AccessRequest, error)
 	Count(context.Context, *AccessRequestsFilterArgs) (int, error)
 	List(context.Context, *AccessRequestsFilterArgs, *PaginationArgs) (_ []*types.AccessRequest, err error)
 	WithTransact(context.Context, func(AccessRequestStore) error) error
@@ -98,7 +106,7 @@ func AccessRequestsWith(other basestore.ShareableStore, logger log.Logger) Acces
 const (
 	accessRequestInsertQuery = `
 		INSERT INTO access_requests (%s)
-		VALUES ( %s, %s, %s, %s )
+		VALUES ( %s, %s, %s, %s, %s, %s, %s, %s, %s, %s )
 		RETURNING %s`
 	accessRequestListQuery = `
 		SELECT %s
@@ -106,9 +114,21 @@ const (
 		WHERE (%s)`
 	accessRequestUpdateQuery = `
 		UPDATE access_requests
-		SET status = %s, updated_at = NOW(), decision_by_user_id = %s
+		SET status = %s, updated_at = NOW(), decision_by_user_id = %s, user_id = COALESCE(%s, user_id)
 		WHERE id = %s
 		RETURNING %s`
+	accessRequestBindExternalAccountQuery = `
+		UPDATE access_requests
+		SET
+			updated_at = NOW(),
+			username = COALESCE(username, %s),
+			email_verified = TRUE,
+			external_service_type = %s,
+			external_service_id = %s,
+			external_client_id = %s,
+			external_account_id = %s
+		WHERE email = %s AND status = %s AND external_account_id IS NULL
+		RETURNING %s`
 )
 
 type AccessRequestListColumn string
@@ -127,12 +147,25 @@ var (
 		sqlf.Sprintf("status"),
 		sqlf.Sprintf("additional_info"),
 		sqlf.Sprintf("decision_by_user_id"),
+		sqlf.Sprintf("username"),
+		sqlf.Sprintf("email_verified"),
+		sqlf.Sprintf("external_service_type"),
+		sqlf.Sprintf("external_service_id"),
+		sqlf.Sprintf("external_client_id"),
+		sqlf.Sprintf("external_account_id"),
+		sqlf.Sprintf("user_id"),
 	}
 	accessRequestInsertColumns = []*sqlf.Query{
 		sqlf.Sprintf("name"),
 		sqlf.Sprintf("email"),
 		sqlf.Sprintf("additional_info"),
 		sqlf.Sprintf("status"),
+		sqlf.Sprintf("username"),
+		sqlf.Sprintf("email_verified"),
+		sqlf.Sprintf("external_service_type"),
+		sqlf.Sprintf("external_service_id"),
+		sqlf.Sprintf("external_client_id"),
+		sqlf.Sprintf("external_account_id"),
 	}
 )
 
@@ -161,6 +194,14 @@ func (s *accessRequestStore) Create(ctx context.Context, accessRequest *types.Ac
 		}
 
 		// Continue with creating the new access request.
+		// The client ID of an external account may be empty, but it is compared
+		// by GetByExternalAccount and must not be NULL for accounts.
+		var spec extsvc.AccountSpec
+		var clientID *string
+		if accessRequest.ExternalAccount != nil {
+			spec = *accessRequest.ExternalAccount
+			clientID = &spec.ClientID
+		}
 		createQuery := sqlf.Sprintf(
 			accessRequestInsertQuery,
 			sqlf.Join(accessRequestInsertColumns, ","),
@@ -168,6 +209,12 @@ func (s *accessRequestStore) Create(ctx context.Context, accessRequest *types.Ac
 			accessRequest.Email,
 			accessRequest.AdditionalInfo,
 			types.AccessRequestStatusPending,
+			dbutil.NewNullString(accessRequest.Username),
+			accessRequest.EmailVerified,
+			dbutil.NewNullString(spec.ServiceType),
+			dbutil.NewNullString(spec.ServiceID),
+			clientID,
+			dbutil.NewNullString(spec.AccountID),
 			sqlf.Join(accessRequestColumns, ","),
 		)
 		data, err := scanAccessRequest(tx.QueryRow(ctx, createQuery))
@@ -209,8 +256,57 @@ func (s *accessRequestStore) GetByEmail(ctx context.Context, email string) (*typ
 	return node, nil
 }
 
+func (s *accessRequestStore) GetByExternalAccount(ctx context.Context, spec extsvc.AccountSpec) (*types.AccessRequest, error) {
+	row := s.QueryRow(ctx, sqlf.Sprintf(
+		"SELECT %s FROM access_requests WHERE external_service_type = %s AND external_service_id = %s AND external_client_id = %s AND external_account_id = %s",
+		sqlf.Join(accessRequestColumns, ","),
+		spec.ServiceType,
+		spec.ServiceID,
+		spec.ClientID,
+		spec.AccountID,
+	))
+	node, err := scanAccessRequest(row)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &ErrAccessRequestNotFound{}
+		}
+		return nil, err
+	}
+
+	return node, nil
+}
+
+func (s *accessRequestStore) BindExternalAccount(ctx context.Context, accessRequest *types.AccessRequest) (*types.AccessRequest, error) {
+	if accessRequest.ExternalAccount == nil {
+		return nil, errors.New("access request has no external account")
+	}
+	spec := accessRequest.ExternalAccount
+
+	q := sqlf.Sprintf(
+		accessRequestBindExternalAccountQuery,
+		dbutil.NewNullString(accessRequest.Username),
+		spec.ServiceType,
+		spec.ServiceID,
+		spec.ClientID,
+		spec.AccountID,
+		accessRequest.Email,
+		types.AccessRequestStatusPending,
+		sqlf.Join(accessRequestColumns, ","),
+	)
+	bound, err := scanAccessRequest(s.QueryRow(ctx, q))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &ErrAccessRequestNotFound{Email: accessRequest.Email}
+		}
+		return nil, errors.Wrap(err, "scanning access_request")
+	}
+
+	return bound, nil
+}
+
 func (s *accessRequestStore) Update(ctx context.Context, accessRequest *types.AccessRequest) (*types.AccessRequest, error) {
-	q := sqlf.Sprintf(accessRequestUpdateQuery, accessRequest.Status, *accessRequest.DecisionByUserID, accessRequest.ID, sqlf.Join(accessRequestColumns, ","))
+	q := sqlf.Sprintf(accessRequestUpdateQuery, accessRequest.Status, *accessRequest.DecisionByUserID, accessRequest.UserID, accessRequest.ID, sqlf.Join(accessRequestColumns, ","))
 	updated, err := scanAccessRequest(s.QueryRow(ctx, q))
 
 	if err != nil {
@@ -265,9 +361,29 @@ func (s *accessRequestStore) WithTransact(ctx context.Context, f func(tx AccessR
 
 func scanAccessRequest(sc dbutil.Scanner) (*types.AccessRequest, error) {
 	var accessRequest types.AccessRequest
-	if err := sc.Scan(&accessRequest.ID, &accessRequest.CreatedAt, &accessRequest.UpdatedAt, &accessRequest.Name, &accessRequest.Email, &accessRequest.Status, &accessRequest.AdditionalInfo, &accessRequest.DecisionByUserID); err != nil {
+	var spec extsvc.AccountSpec
+	if err := sc.Scan(
+		&accessRequest.ID,
+		&accessRequest.CreatedAt,
+		&accessRequest.UpdatedAt,
+		&accessRequest.Name,
+		&accessRequest.Email,
+		&accessRequest.Status,
+		&accessRequest.AdditionalInfo,
+		&accessRequest.DecisionByUserID,
+		&dbutil.NullString{S: &accessRequest.Username},
+		&accessRequest.EmailVerified,
+		&dbutil.NullString{S: &spec.ServiceType},
+		&dbutil.NullString{S: &spec.ServiceID},
+		&dbutil.NullString{S: &spec.ClientID},
+		&dbutil.NullString{S: &spec.AccountID},
+		&accessRequest.UserID,
+	); err != nil {
 		return nil, err
 	}
+	if spec.AccountID != "" {
+		accessRequest.ExternalAccount = &spec
+	}
 
 	return &accessRequest, nil
 }
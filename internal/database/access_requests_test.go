@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
@@ -111,6 +113,110 @@ func TestAccessRequests_Update(t *testing.T) {
 	})
 }
 
+func TestAccessRequests_ExternalAccount(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	store := db.AccessRequests()
+	admin, err := db.Users().Create(ctx, NewUser{Username: "admin"})
+	assert.NoError(t, err)
+
+	spec := extsvc.AccountSpec{ServiceType: "saml", ServiceID: "https://idp.example.com", AccountID: "u1"}
+
+	_, err = store.GetByExternalAccount(ctx, spec)
+	assert.True(t, errcode.IsNotFound(err))
+
+	created, err := store.Create(ctx, &types.AccessRequest{
+		Email:           "u1@example.com",
+		Name:            "User One",
+		Username:        "u1",
+		EmailVerified:   true,
+		ExternalAccount: &spec,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", created.Username)
+	assert.True(t, created.EmailVerified)
+	assert.Equal(t, &spec, created.ExternalAccount)
+	assert.Nil(t, created.UserID)
+
+	got, err := store.GetByExternalAccount(ctx, spec)
+	assert.NoError(t, err)
+	assert.Equal(t, created.ID, got.ID)
+
+	user, err := db.Users().Create(ctx, NewUser{Username: "u1", Email: "u1@example.com", EmailIsVerified: true})
+	assert.NoError(t, err)
+	updated, err := store.Update(ctx, &types.AccessRequest{ID: created.ID, Status: types.AccessRequestStatusApproved, DecisionByUserID: &admin.ID, UserID: &user.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, &user.ID, updated.UserID)
+
+	// Later status changes keep the created account.
+	updated, err = store.Update(ctx, &types.AccessRequest{ID: created.ID, Status: types.AccessRequestStatusRejected, DecisionByUserID: &admin.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, &user.ID, updated.UserID)
+
+	// Requests made with the built-in form have no external account.
+	formRequest, err := store.Create(ctx, &types.AccessRequest{Email: "u2@example.com", Name: "User Two"})
+	assert.NoError(t, err)
+	assert.Nil(t, formRequest.ExternalAccount)
+}
+
+func TestAccessRequests_BindExternalAccount(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	store := db.AccessRequests()
+	admin, err := db.Users().Create(ctx, NewUser{Username: "admin"})
+	assert.NoError(t, err)
+
+	spec := extsvc.AccountSpec{ServiceType: "saml", ServiceID: "https://idp.example.com", AccountID: "u1"}
+	otherSpec := extsvc.AccountSpec{ServiceType: "saml", ServiceID: "https://idp.example.com", AccountID: "u2"}
+
+	// A request made with the built-in form is bound to the external account.
+	formRequest, err := store.Create(ctx, &types.AccessRequest{Email: "u1@example.com", Name: "User One"})
+	assert.NoError(t, err)
+	bound, err := store.BindExternalAccount(ctx, &types.AccessRequest{Email: "u1@example.com", Username: "u1", ExternalAccount: &spec})
+	assert.NoError(t, err)
+	assert.Equal(t, formRequest.ID, bound.ID)
+	assert.Equal(t, "User One", bound.Name)
+	assert.Equal(t, "u1", bound.Username)
+	assert.True(t, bound.EmailVerified)
+	assert.Equal(t, &spec, bound.ExternalAccount)
+
+	got, err := store.GetByExternalAccount(ctx, spec)
+	assert.NoError(t, err)
+	assert.Equal(t, formRequest.ID, got.ID)
+
+	// A request bound to an external account is not bound to another one.
+	_, err = store.BindExternalAccount(ctx, &types.AccessRequest{Email: "u1@example.com", Username: "u2", ExternalAccount: &otherSpec})
+	assert.True(t, errcode.IsNotFound(err))
+	got, err = store.GetByID(ctx, formRequest.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, &spec, got.ExternalAccount)
+
+	// Only pending requests are bound.
+	decided, err := store.Create(ctx, &types.AccessRequest{Email: "u2@example.com", Name: "User Two"})
+	assert.NoError(t, err)
+	_, err = store.Update(ctx, &types.AccessRequest{ID: decided.ID, Status: types.AccessRequestStatusRejected, DecisionByUserID: &admin.ID})
+	assert.NoError(t, err)
+	_, err = store.BindExternalAccount(ctx, &types.AccessRequest{Email: "u2@example.com", Username: "u2", ExternalAccount: &otherSpec})
+	assert.True(t, errcode.IsNotFound(err))
+
+	_, err = store.BindExternalAccount(ctx, &types.AccessRequest{Email: "u3@example.com", Username: "u3", ExternalAccount: &otherSpec})
+	assert.True(t, errcode.IsNotFound(err))
+}
+
 func TestAccessRequests_GetByID(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
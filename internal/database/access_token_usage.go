@@ -0,0 +1,395 @@
+package database
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// AccessTokenUsageLatencyBounds are the upper bounds, in milliseconds, of the
+// latency buckets of access token usage. Requests slower than the last bound
+// are counted in an additional, unbounded bucket.
+var AccessTokenUsageLatencyBounds = []float64{10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// AccessTokenUsageLatencyBucket returns the index of the latency bucket of a
+// request with the given duration.
+func AccessTokenUsageLatencyBucket(d time.Duration) int {
+	ms := float64(d) / float64(time.Millisecond)
+	for i, bound := range AccessTokenUsageLatencyBounds {
+		if ms <= bound {
+			return i
+		}
+	}
+	return len(AccessTokenUsageLatencyBounds)
+}
+
+// AccessTokenUsage is the usage of the API with an access token, aggregated
+// per endpoint and operation.
+type AccessTokenUsage struct {
+	AccessTokenID int64
+	SubjectUserID int32
+	// Endpoint is the name of the route of the requests, such as "graphql" or
+	// "rest.search".
+	Endpoint string
+	// Operation is the name of the GraphQL request, if any.
+	Operation     string
+	RequestCount  int
+	ErrorCount    int
+	ResponseBytes int64
+	// LatencyBuckets[i] is the number of requests in the i-th bucket of
+	// AccessTokenUsageLatencyBounds.
+	LatencyBuckets []int32
+}
+
+// LatencyPercentile returns an upper bound, in milliseconds, of the given
+// percentile (between 0 and 100) of the latencies of the requests. Requests in
+// the unbounded bucket are reported with the last bound.
+func (u *AccessTokenUsage) LatencyPercentile(p float64) float64 {
+	var total int64
+	for _, count := range u.LatencyBuckets {
+		total += int64(count)
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := p / 100 * float64(total)
+	var seen int64
+	for i, count := range u.LatencyBuckets {
+		seen += int64(count)
+		if float64(seen) >= rank && count > 0 {
+			if i >= len(AccessTokenUsageLatencyBounds) {
+				break
+			}
+			return AccessTokenUsageLatencyBounds[i]
+		}
+	}
+	return AccessTokenUsageLatencyBounds[len(AccessTokenUsageLatencyBounds)-1]
+}
+
+// AccessTokenUsageRecord is usage of the API recorded for the access token with
+// the given hash, during the hour starting at Bucket.
+type AccessTokenUsageRecord struct {
+	// TokenHash is the hash of the access token, as returned by AccessTokenHash.
+	TokenHash      []byte
+	Bucket         time.Time
+	Endpoint       string
+	Operation      string
+	RequestCount   int
+	ErrorCount     int
+	ResponseBytes  int64
+	LatencyBuckets []int32
+}
+
+// AccessTokenUsageListOptions contains options for listing access token usage
+// and usage alerts.
+type AccessTokenUsageListOptions struct {
+	// Since only includes usage and alerts of hours starting at or after this
+	// time.
+	Since time.Time
+	// AccessTokenID, if non-zero, only includes the given access token.
+	AccessTokenID int64
+	// SubjectUserID, if non-zero, only includes access tokens of the given user.
+	SubjectUserID int32
+	// Limit, if non-zero, limits the number of results.
+	Limit int
+}
+
+func (o AccessTokenUsageListOptions) sqlConds(table string) *sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf(table+".bucket >= %s", o.Since)}
+	if o.AccessTokenID != 0 {
+		conds = append(conds, sqlf.Sprintf(table+".access_token_id = %s", o.AccessTokenID))
+	}
+	if o.SubjectUserID != 0 {
+		conds = append(conds, sqlf.Sprintf("t.subject_user_id = %s", o.SubjectUserID))
+	}
+	return sqlf.Join(conds, "AND")
+}
+
+func (o AccessTokenUsageListOptions) limitSQL() *sqlf.Query {
+	if o.Limit == 0 {
+		return sqlf.Sprintf("")
+	}
+	return (&LimitOffset{Limit: o.Limit}).SQL()
+}
+
+// AccessTokenUsageAlert records that the hourly request count of an access
+// token was far above its usual hourly request count.
+type AccessTokenUsageAlert struct {
+	ID            int64
+	AccessTokenID int64
+	SubjectUserID int32
+	// Bucket is the start of the hour during which the requests were made.
+	Bucket       time.Time
+	RequestCount int
+	// BaselineRequestCount is the average hourly request count of the access
+	// token before Bucket.
+	BaselineRequestCount float64
+	CreatedAt            time.Time
+}
+
+// AccessTokenUsageStore records and aggregates the usage of the API with
+// access tokens, in hourly buckets.
+type AccessTokenUsageStore interface {
+	basestore.ShareableStore
+	With(basestore.ShareableStore) AccessTokenUsageStore
+	WithTransact(context.Context, func(AccessTokenUsageStore) error) error
+
+	// Record adds the given usage to the usage of the access tokens. Usage of
+	// access tokens that do not exist (anymore) is ignored.
+	Record(ctx context.Context, records ...AccessTokenUsageRecord) error
+
+	// List returns the usage matching the given options, aggregated per access
+	// token, endpoint and operation, from the highest request count.
+	List(ctx context.Context, opts AccessTokenUsageListOptions) ([]*AccessTokenUsage, error)
+
+	// CreateSpikeAlerts creates an alert for every access token that made at
+	// least minRequests requests in the hour starting at bucket, and at least
+	// factor times its average hourly request count over the preceding
+	// baselinePeriod. Access tokens created less than a day before bucket are
+	// ignored. An outbox event is published for every new alert, and the new
+	// alerts are returned. Alerts are created at most once per access token and
+	// bucket.
+	CreateSpikeAlerts(ctx context.Context, bucket time.Time, baselinePeriod time.Duration, factor float64, minRequests int) ([]*AccessTokenUsageAlert, error)
+
+	// ListAlerts returns the alerts matching the given options, most recent
+	// first.
+	ListAlerts(ctx context.Context, opts AccessTokenUsageListOptions) ([]*AccessTokenUsageAlert, error)
+
+	// DeleteBefore deletes the usage and alerts of hours starting before the
+	// given time.
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
+
+type accessTokenUsageStore struct {
+	*basestore.Store
+}
+
+// AccessTokenUsageWith instantiates and returns a new AccessTokenUsageStore
+// using the other store handle.
+func AccessTokenUsageWith(other basestore.ShareableStore) AccessTokenUsageStore {
+	return &accessTokenUsageStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *accessTokenUsageStore) With(other basestore.ShareableStore) AccessTokenUsageStore {
+	return &accessTokenUsageStore{Store: s.Store.With(other)}
+}
+
+func (s *accessTokenUsageStore) WithTransact(ctx context.Context, f func(AccessTokenUsageStore) error) error {
+	return s.Store.WithTransact(ctx, func(tx *basestore.Store) error {
+		return f(&accessTokenUsageStore{Store: tx})
+	})
+}
+
+func (s *accessTokenUsageStore) Record(ctx context.Context, records ...AccessTokenUsageRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	return s.Store.WithTransact(ctx, func(tx *basestore.Store) error {
+		for _, record := range records {
+			q := sqlf.Sprintf(
+				recordAccessTokenUsageQuery,
+				record.Bucket.UTC().Truncate(time.Hour),
+				record.Endpoint,
+				record.Operation,
+				record.RequestCount,
+				record.ErrorCount,
+				record.ResponseBytes,
+				pq.Array(record.LatencyBuckets),
+				record.TokenHash,
+			)
+			if err := tx.Exec(ctx, q); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+const recordAccessTokenUsageQuery = `
+INSERT INTO access_token_usage (access_token_id, bucket, endpoint, operation, request_count, error_count, response_bytes, latency_buckets)
+SELECT t.id, %s, %s, %s, %s, %s, %s, COALESCE(%s::integer[], '{}')
+FROM access_tokens t
+WHERE t.value_sha256 = %s AND t.deleted_at IS NULL
+ON CONFLICT (access_token_id, bucket, endpoint, operation) DO UPDATE SET
+	request_count = access_token_usage.request_count + EXCLUDED.request_count,
+	error_count = access_token_usage.error_count + EXCLUDED.error_count,
+	response_bytes = access_token_usage.response_bytes + EXCLUDED.response_bytes,
+	latency_buckets = (
+		SELECT array_agg(COALESCE(l.previous, 0) + COALESCE(l.recorded, 0) ORDER BY l.i)
+		FROM unnest(access_token_usage.latency_buckets, EXCLUDED.latency_buckets) WITH ORDINALITY AS l(previous, recorded, i)
+	)
+`
+
+func (s *accessTokenUsageStore) List(ctx context.Context, opts AccessTokenUsageListOptions) (usage []*AccessTokenUsage, err error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(listAccessTokenUsageQuery, opts.sqlConds("u"), opts.limitSQL()))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	for rows.Next() {
+		var u AccessTokenUsage
+		if err := rows.Scan(
+			&u.AccessTokenID,
+			&u.SubjectUserID,
+			&u.Endpoint,
+			&u.Operation,
+			&u.RequestCount,
+			&u.ErrorCount,
+			&u.ResponseBytes,
+			pq.Array(&u.LatencyBuckets),
+		); err != nil {
+			return nil, err
+		}
+		usage = append(usage, &u)
+	}
+	return usage, nil
+}
+
+const listAccessTokenUsageQuery = `
+WITH usage AS (
+	SELECT u.*, t.subject_user_id
+	FROM access_token_usage u
+	JOIN access_tokens t ON t.id = u.access_token_id
+	WHERE %s
+),
+latencies AS (
+	SELECT access_token_id, endpoint, operation, array_agg(total ORDER BY i) AS latency_buckets
+	FROM (
+		SELECT usage.access_token_id, usage.endpoint, usage.operation, l.i, SUM(l.count)::integer AS total
+		FROM usage, unnest(usage.latency_buckets) WITH ORDINALITY AS l(count, i)
+		GROUP BY usage.access_token_id, usage.endpoint, usage.operation, l.i
+	) per_bucket
+	GROUP BY access_token_id, endpoint, operation
+)
+SELECT
+	usage.access_token_id,
+	usage.subject_user_id,
+	usage.endpoint,
+	usage.operation,
+	SUM(usage.request_count),
+	SUM(usage.error_count),
+	SUM(usage.response_bytes),
+	COALESCE(latencies.latency_buckets, '{}')
+FROM usage
+LEFT JOIN latencies USING (access_token_id, endpoint, operation)
+GROUP BY usage.access_token_id, usage.subject_user_id, usage.endpoint, usage.operation, latencies.latency_buckets
+ORDER BY SUM(usage.request_count) DESC, usage.access_token_id, usage.endpoint, usage.operation
+%s
+`
+
+func (s *accessTokenUsageStore) CreateSpikeAlerts(ctx context.Context, bucket time.Time, baselinePeriod time.Duration, factor float64, minRequests int) (alerts []*AccessTokenUsageAlert, err error) {
+	bucket = bucket.UTC().Truncate(time.Hour)
+	baselineStart := bucket.Add(-baselinePeriod)
+
+	err = s.Store.WithTransact(ctx, func(tx *basestore.Store) error {
+		rows, err := tx.Query(ctx, sqlf.Sprintf(
+			createAccessTokenUsageSpikeAlertsQuery,
+			bucket,
+			minRequests,
+			baselineStart,
+			bucket,
+			bucket,
+			baselineStart,
+			bucket,
+			bucket,
+			factor,
+		))
+		alerts, err = scanAccessTokenUsageAlerts(rows, err)
+		if err != nil {
+			return err
+		}
+
+		events := make([]OutboxEventArgs, 0, len(alerts))
+		for _, alert := range alerts {
+			events = append(events, OutboxEventArgs{
+				EventType:     types.OutboxEventAccessTokenUsageSpike,
+				AggregateType: "access_token",
+				AggregateID:   strconv.FormatInt(alert.AccessTokenID, 10),
+				Payload: map[string]any{
+					"access_token_id":        alert.AccessTokenID,
+					"user_id":                alert.SubjectUserID,
+					"bucket":                 alert.Bucket,
+					"request_count":          alert.RequestCount,
+					"baseline_request_count": alert.BaselineRequestCount,
+				},
+			})
+		}
+		return OutboxEventsWith(tx).Publish(ctx, events...)
+	})
+	return alerts, err
+}
+
+const createAccessTokenUsageSpikeAlertsQuery = `
+WITH current_usage AS (
+	SELECT access_token_id, SUM(request_count) AS request_count
+	FROM access_token_usage
+	WHERE bucket = %s
+	GROUP BY access_token_id
+	HAVING SUM(request_count) >= %s
+),
+baselines AS (
+	SELECT
+		c.access_token_id,
+		c.request_count,
+		COALESCE((
+			SELECT SUM(u.request_count)
+			FROM access_token_usage u
+			WHERE u.access_token_id = c.access_token_id AND u.bucket >= %s AND u.bucket < %s
+		), 0) / GREATEST(1, EXTRACT(EPOCH FROM (%s::timestamptz - GREATEST(t.created_at, %s::timestamptz))) / 3600) AS baseline_request_count
+	FROM current_usage c
+	JOIN access_tokens t ON t.id = c.access_token_id
+	WHERE t.deleted_at IS NULL AND t.created_at <= %s::timestamptz - interval '1 day'
+),
+inserted AS (
+	INSERT INTO access_token_usage_alerts (access_token_id, bucket, request_count, baseline_request_count)
+	SELECT access_token_id, %s, request_count, baseline_request_count
+	FROM baselines
+	WHERE request_count >= %s * GREATEST(baseline_request_count, 1)
+	ON CONFLICT (access_token_id, bucket) DO NOTHING
+	RETURNING *
+)
+SELECT a.id, a.access_token_id, t.subject_user_id, a.bucket, a.request_count, a.baseline_request_count, a.created_at
+FROM inserted a
+JOIN access_tokens t ON t.id = a.access_token_id
+ORDER BY a.id
+`
+
+func (s *accessTokenUsageStore) ListAlerts(ctx context.Context, opts AccessTokenUsageListOptions) ([]*AccessTokenUsageAlert, error) {
+	return scanAccessTokenUsageAlerts(s.Query(ctx, sqlf.Sprintf(listAccessTokenUsageAlertsQuery, opts.sqlConds("a"), opts.limitSQL())))
+}
+
+const listAccessTokenUsageAlertsQuery = `
+SELECT a.id, a.access_token_id, t.subject_user_id, a.bucket, a.request_count, a.baseline_request_count, a.created_at
+FROM access_token_usage_alerts a
+JOIN access_tokens t ON t.id = a.access_token_id
+WHERE %s
+ORDER BY a.bucket DESC, a.id DESC
+%s
+`
+
+func (s *accessTokenUsageStore) DeleteBefore(ctx context.Context, before time.Time) error {
+	return s.Exec(ctx, sqlf.Sprintf(deleteAccessTokenUsageBeforeQuery, before, before))
+}
+
+const deleteAccessTokenUsageBeforeQuery = `
+WITH deleted_alerts AS (
+	DELETE FROM access_token_usage_alerts WHERE bucket < %s
+)
+DELETE FROM access_token_usage WHERE bucket < %s
+`
+
+var scanAccessTokenUsageAlerts = basestore.NewSliceScanner(func(s dbutil.Scanner) (*AccessTokenUsageAlert, error) {
+	var a AccessTokenUsageAlert
+	err := s.Scan(&a.ID, &a.AccessTokenID, &a.SubjectUserID, &a.Bucket, &a.RequestCount, &a.BaselineRequestCount, &a.CreatedAt)
+	return &a, err
+})
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestAccessTokenUsage(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+	store := db.AccessTokenUsage()
+
+	user, err := db.Users().Create(ctx, NewUser{Username: "u"})
+	require.NoError(t, err)
+	tokenID, token, err := db.AccessTokens().Create(ctx, user.ID, []string{"a"}, "n", user.ID)
+	require.NoError(t, err)
+	tokenHash, err := AccessTokenHash(token)
+	require.NoError(t, err)
+
+	// Pretend that the access token is a week old.
+	_, err = db.ExecContext(ctx, "UPDATE access_tokens SET created_at = now() - interval '7 days'")
+	require.NoError(t, err)
+
+	bucket := time.Now().UTC().Truncate(time.Hour)
+	record := func(bucket time.Time, endpoint string, requests int, latencyBuckets ...int32) AccessTokenUsageRecord {
+		return AccessTokenUsageRecord{
+			TokenHash:      tokenHash,
+			Bucket:         bucket,
+			Endpoint:       endpoint,
+			RequestCount:   requests,
+			ResponseBytes:  int64(requests * 100),
+			LatencyBuckets: latencyBuckets,
+		}
+	}
+
+	require.NoError(t, store.Record(ctx,
+		record(bucket.Add(-2*time.Hour), "graphql", 2, 1, 1),
+		record(bucket.Add(-time.Hour), "graphql", 3, 0, 3),
+		record(bucket.Add(-time.Hour), "rest.search", 1, 0, 0, 1),
+		// Usage of unknown access tokens is ignored.
+		AccessTokenUsageRecord{TokenHash: []byte("unknown"), Bucket: bucket, Endpoint: "graphql", RequestCount: 1},
+	))
+
+	t.Run("List", func(t *testing.T) {
+		usage, err := store.List(ctx, AccessTokenUsageListOptions{Since: bucket.Add(-24 * time.Hour), SubjectUserID: user.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []*AccessTokenUsage{
+			{AccessTokenID: tokenID, SubjectUserID: user.ID, Endpoint: "graphql", RequestCount: 5, ResponseBytes: 500, LatencyBuckets: []int32{1, 4}},
+			{AccessTokenID: tokenID, SubjectUserID: user.ID, Endpoint: "rest.search", RequestCount: 1, ResponseBytes: 100, LatencyBuckets: []int32{0, 0, 1}},
+		}, usage)
+
+		usage, err = store.List(ctx, AccessTokenUsageListOptions{Since: bucket.Add(-time.Hour), Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, usage, 1)
+		assert.Equal(t, 3, usage[0].RequestCount)
+
+		usage, err = store.List(ctx, AccessTokenUsageListOptions{Since: bucket.Add(-24 * time.Hour), SubjectUserID: user.ID + 1})
+		require.NoError(t, err)
+		assert.Empty(t, usage)
+	})
+
+	t.Run("CreateSpikeAlerts", func(t *testing.T) {
+		require.NoError(t, store.Record(ctx, record(bucket, "graphql", 1000)))
+
+		alerts, err := store.CreateSpikeAlerts(ctx, bucket, 7*24*time.Hour, 10, 2000)
+		require.NoError(t, err)
+		assert.Empty(t, alerts, "below the minimum request count")
+
+		alerts, err = store.CreateSpikeAlerts(ctx, bucket, 7*24*time.Hour, 10, 100)
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, tokenID, alerts[0].AccessTokenID)
+		assert.Equal(t, user.ID, alerts[0].SubjectUserID)
+		assert.Equal(t, 1000, alerts[0].RequestCount)
+
+		// Alerts are only created once per bucket.
+		alerts, err = store.CreateSpikeAlerts(ctx, bucket, 7*24*time.Hour, 10, 100)
+		require.NoError(t, err)
+		assert.Empty(t, alerts)
+
+		listed, err := store.ListAlerts(ctx, AccessTokenUsageListOptions{Since: bucket, AccessTokenID: tokenID})
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		assert.Equal(t, 1000, listed[0].RequestCount)
+
+		events, err := db.OutboxEvents().ListAfter(ctx, OutboxOffset{}, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		assert.Equal(t, types.OutboxEventUserCreated, events[0].EventType)
+		assert.Equal(t, types.OutboxEventAuditLog, events[1].EventType)
+		assert.Equal(t, types.OutboxEventAccessTokenUsageSpike, events[2].EventType)
+	})
+
+	t.Run("DeleteBefore", func(t *testing.T) {
+		require.NoError(t, store.DeleteBefore(ctx, bucket))
+
+		usage, err := store.List(ctx, AccessTokenUsageListOptions{Since: bucket.Add(-24 * time.Hour)})
+		require.NoError(t, err)
+		require.Len(t, usage, 1)
+		assert.Equal(t, 1000, usage[0].RequestCount)
+	})
+}
+
+func TestAccessTokenUsageLatencyPercentile(t *testing.T) {
+	usage := &AccessTokenUsage{LatencyBuckets: make([]int32, len(AccessTokenUsageLatencyBounds)+1)}
+	assert.Equal(t, float64(0), usage.LatencyPercentile(50))
+
+	usage.LatencyBuckets[AccessTokenUsageLatencyBucket(5*time.Millisecond)] = 50
+	usage.LatencyBuckets[AccessTokenUsageLatencyBucket(200*time.Millisecond)] = 40
+	usage.LatencyBuckets[AccessTokenUsageLatencyBucket(time.Minute)] = 10
+
+	assert.Equal(t, float64(10), usage.LatencyPercentile(50))
+	assert.Equal(t, float64(250), usage.LatencyPercentile(90))
+	assert.Equal(t, float64(10000), usage.LatencyPercentile(99))
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/hashutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -177,7 +179,13 @@ func (s *accessTokenStore) createToken(ctx context.Context, subjectUserID int32,
 		return 0, "", errors.New("access tokens without scopes are not supported")
 	}
 
-	if err := s.Handle().QueryRowContext(ctx,
+	tx, err := s.Store.Transact(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	if err := tx.Handle().QueryRowContext(ctx,
 		// Include users table query (with "FOR UPDATE") to ensure that subject/creator users have
 		// not been deleted. If they were deleted, the query will return an error.
 		`
@@ -200,6 +208,20 @@ INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_u
 
 	// only log access tokens created by users
 	if !internal {
+		err = AuditLogEventsWith(tx).Record(ctx, AuditLogEventArgs{
+			Action:     types.AuditLogActionAccessTokenCreated,
+			EntityType: "access_token",
+			EntityID:   strconv.FormatInt(id, 10),
+			Argument: struct {
+				SubjectUserID int32    `json:"subjectUserID"`
+				Scopes        []string `json:"scopes"`
+				Note          string   `json:"note"`
+			}{subjectUserID, scopes, note},
+		})
+		if err != nil {
+			return 0, "", err
+		}
+
 		arg, err := json.Marshal(struct {
 			SubjectUserId int32    `json:"subject_user_id"`
 			CreatorUserId int32    `json:"creator_user_id"`
@@ -443,6 +465,12 @@ func (s *accessTokenStore) delete(ctx context.Context, cond *sqlf.Query) error {
 	return nil
 }
 
+// AccessTokenHash returns the hash under which the given access token is
+// stored, which identifies the access token without revealing its value.
+func AccessTokenHash(token string) ([]byte, error) {
+	return tokenSHA256Hash(token)
+}
+
 // tokenSHA256Hash returns the 32-byte long SHA-256 hash of its hex-encoded value
 // (after stripping the "sgp_" token prefix, if present).
 func tokenSHA256Hash(token string) ([]byte, error) {
@@ -0,0 +1,218 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/requestclient"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// AuditLogEventStore records changes to organizations, code host connections,
+// repository permissions and access tokens, together with the actor that made
+// them.
+//
+// Every recorded event is also published to the transactional outbox, so that
+// it can be streamed to external systems such as a SIEM.
+type AuditLogEventStore interface {
+	basestore.ShareableStore
+	With(basestore.ShareableStore) AuditLogEventStore
+
+	// Record records an event for the actor and request client in ctx. It should
+	// be called with a store sharing the transaction that commits the change.
+	Record(ctx context.Context, args AuditLogEventArgs) error
+
+	// List returns the events matching opts, newest first.
+	List(ctx context.Context, opts AuditLogEventsListOptions) ([]*types.AuditLogEvent, error)
+
+	// Count returns the number of events matching opts.
+	Count(ctx context.Context, opts AuditLogEventsListOptions) (int, error)
+}
+
+// AuditLogEventArgs describes an event to record in the audit log.
+type AuditLogEventArgs struct {
+	Action     string
+	EntityType string
+	EntityID   string
+	// OrgID is the organization the change belongs to, if any.
+	OrgID *int32
+	// Argument is marshalled to JSON.
+	Argument any
+}
+
+// AuditLogEventsListOptions filters the events returned by List and Count.
+type AuditLogEventsListOptions struct {
+	*LimitOffset
+
+	Actions     []string
+	EntityType  string
+	OrgID       int32
+	ActorUserID int32
+	// Since and Until restrict the events to the ones created in [Since, Until).
+	Since time.Time
+	Until time.Time
+}
+
+func (opts AuditLogEventsListOptions) where() *sqlf.Query {
+	preds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+
+	if len(opts.Actions) > 0 {
+		actions := make([]*sqlf.Query, 0, len(opts.Actions))
+		for _, action := range opts.Actions {
+			actions = append(actions, sqlf.Sprintf("%s", action))
+		}
+		preds = append(preds, sqlf.Sprintf("action IN (%s)", sqlf.Join(actions, ",")))
+	}
+	if opts.EntityType != "" {
+		preds = append(preds, sqlf.Sprintf("entity_type = %s", opts.EntityType))
+	}
+	if opts.OrgID != 0 {
+		preds = append(preds, sqlf.Sprintf("org_id = %s", opts.OrgID))
+	}
+	if opts.ActorUserID != 0 {
+		preds = append(preds, sqlf.Sprintf("actor_user_id = %s", opts.ActorUserID))
+	}
+	if !opts.Since.IsZero() {
+		preds = append(preds, sqlf.Sprintf("created_at >= %s", opts.Since))
+	}
+	if !opts.Until.IsZero() {
+		preds = append(preds, sqlf.Sprintf("created_at < %s", opts.Until))
+	}
+
+	return sqlf.Join(preds, "AND")
+}
+
+type auditLogEventStore struct {
+	*basestore.Store
+}
+
+// AuditLogEventsWith instantiates and returns a new AuditLogEventStore using
+// the other store handle.
+func AuditLogEventsWith(other basestore.ShareableStore) AuditLogEventStore {
+	return &auditLogEventStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *auditLogEventStore) With(other basestore.ShareableStore) AuditLogEventStore {
+	return &auditLogEventStore{Store: s.Store.With(other)}
+}
+
+func (s *auditLogEventStore) Record(ctx context.Context, args AuditLogEventArgs) error {
+	argument, err := json.Marshal(args.Argument)
+	if err != nil {
+		return err
+	}
+	if string(argument) == "null" {
+		argument = []byte("{}")
+	}
+
+	var actorUserID *int32
+	if a := actor.FromContext(ctx); a.IsAuthenticated() {
+		actorUserID = &a.UID
+	}
+	var ip, forwardedFor string
+	if client := requestclient.FromContext(ctx); client != nil {
+		ip, forwardedFor = client.IP, client.ForwardedFor
+	}
+
+	return s.WithTransact(ctx, func(tx *basestore.Store) error {
+		event, err := scanAuditLogEvent(tx.QueryRow(ctx, sqlf.Sprintf(
+			recordAuditLogEventQuery,
+			args.Action,
+			args.EntityType,
+			args.EntityID,
+			args.OrgID,
+			actorUserID,
+			dbutil.NewNullString(ip),
+			dbutil.NewNullString(forwardedFor),
+			string(argument),
+			sqlf.Join(auditLogEventColumns, ", "),
+		)))
+		if err != nil {
+			return err
+		}
+
+		return OutboxEventsWith(tx).Publish(ctx, OutboxEventArgs{
+			EventType:     types.OutboxEventAuditLog,
+			AggregateType: event.EntityType,
+			AggregateID:   event.EntityID,
+			Payload:       event,
+		})
+	})
+}
+
+const recordAuditLogEventQuery = `
+INSERT INTO audit_log_events (action, entity_type, entity_id, org_id, actor_user_id, actor_ip, actor_forwarded_for, argument)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
+RETURNING %s
+`
+
+func (s *auditLogEventStore) List(ctx context.Context, opts AuditLogEventsListOptions) ([]*types.AuditLogEvent, error) {
+	return scanAuditLogEvents(s.Query(ctx, sqlf.Sprintf(
+		listAuditLogEventsQuery,
+		sqlf.Join(auditLogEventColumns, ", "),
+		opts.where(),
+		opts.LimitOffset.SQL(),
+	)))
+}
+
+const listAuditLogEventsQuery = `
+SELECT %s
+FROM audit_log_events
+WHERE %s
+ORDER BY created_at DESC, id DESC
+%s
+`
+
+func (s *auditLogEventStore) Count(ctx context.Context, opts AuditLogEventsListOptions) (int, error) {
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(countAuditLogEventsQuery, opts.where())))
+	return count, err
+}
+
+const countAuditLogEventsQuery = `
+SELECT COUNT(*)
+FROM audit_log_events
+WHERE %s
+`
+
+var auditLogEventColumns = []*sqlf.Query{
+	sqlf.Sprintf("id"),
+	sqlf.Sprintf("action"),
+	sqlf.Sprintf("entity_type"),
+	sqlf.Sprintf("entity_id"),
+	sqlf.Sprintf("org_id"),
+	sqlf.Sprintf("actor_user_id"),
+	sqlf.Sprintf("actor_ip"),
+	sqlf.Sprintf("actor_forwarded_for"),
+	sqlf.Sprintf("argument"),
+	sqlf.Sprintf("created_at"),
+}
+
+var scanAuditLogEvents = basestore.NewSliceScanner(scanAuditLogEvent)
+
+func scanAuditLogEvent(sc dbutil.Scanner) (*types.AuditLogEvent, error) {
+	var event types.AuditLogEvent
+	var argument []byte
+	if err := sc.Scan(
+		&event.ID,
+		&event.Action,
+		&event.EntityType,
+		&event.EntityID,
+		&event.OrgID,
+		&event.ActorUserID,
+		&dbutil.NullString{S: &event.ActorIP},
+		&dbutil.NullString{S: &event.ActorForwardedFor},
+		&argument,
+		&event.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	event.Argument = argument
+
+	return &event, nil
+}
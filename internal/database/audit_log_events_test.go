@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/requestclient"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestAuditLogEvents(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+
+	admin, err := db.Users().Create(ctx, NewUser{Username: "admin"})
+	require.NoError(t, err)
+	member, err := db.Users().Create(ctx, NewUser{Username: "member"})
+	require.NoError(t, err)
+	org, err := db.Orgs().Create(ctx, "acme", nil)
+	require.NoError(t, err)
+
+	adminCtx := actor.WithActor(ctx, actor.FromUser(admin.ID))
+	adminCtx = requestclient.WithClient(adminCtx, &requestclient.Client{IP: "192.0.2.1", ForwardedFor: "198.51.100.1"})
+
+	_, err = db.OrgMembers().Create(adminCtx, org.ID, member.ID)
+	require.NoError(t, err)
+	require.NoError(t, db.OrgMembers().Remove(adminCtx, org.ID, member.ID))
+	// Removing a user that is not a member is not audited.
+	require.NoError(t, db.OrgMembers().Remove(adminCtx, org.ID, admin.ID))
+	// Internal changes have no actor.
+	_, err = db.OrgMembers().Create(actor.WithInternalActor(ctx), org.ID, admin.ID)
+	require.NoError(t, err)
+
+	store := db.AuditLogEvents()
+
+	events, err := store.List(ctx, AuditLogEventsListOptions{OrgID: org.ID})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	added, removed := events[2], events[1]
+	assert.Equal(t, types.AuditLogActionOrgMemberAdded, added.Action)
+	assert.Equal(t, "org_member", added.EntityType)
+	assert.Equal(t, &org.ID, added.OrgID)
+	assert.Equal(t, &admin.ID, added.ActorUserID)
+	assert.Equal(t, "192.0.2.1", added.ActorIP)
+	assert.Equal(t, "198.51.100.1", added.ActorForwardedFor)
+	assert.JSONEq(t, fmt.Sprintf(`{"userID": %d}`, member.ID), string(added.Argument))
+	assert.Equal(t, types.AuditLogActionOrgMemberRemoved, removed.Action)
+	assert.Nil(t, events[0].ActorUserID)
+	assert.Empty(t, events[0].ActorIP)
+
+	t.Run("filters", func(t *testing.T) {
+		count := func(opts AuditLogEventsListOptions) int {
+			count, err := store.Count(ctx, opts)
+			require.NoError(t, err)
+			return count
+		}
+
+		assert.Equal(t, 2, count(AuditLogEventsListOptions{Actions: []string{types.AuditLogActionOrgMemberAdded}}))
+		assert.Equal(t, 2, count(AuditLogEventsListOptions{ActorUserID: admin.ID}))
+		assert.Equal(t, 3, count(AuditLogEventsListOptions{EntityType: "org_member"}))
+		assert.Equal(t, 0, count(AuditLogEventsListOptions{EntityType: "external_service"}))
+		assert.Equal(t, 0, count(AuditLogEventsListOptions{OrgID: org.ID + 1}))
+		assert.Equal(t, 0, count(AuditLogEventsListOptions{Since: time.Now().Add(time.Hour)}))
+		assert.Equal(t, 3, count(AuditLogEventsListOptions{Until: time.Now().Add(time.Hour)}))
+
+		events, err := store.List(ctx, AuditLogEventsListOptions{LimitOffset: &LimitOffset{Limit: 1, Offset: 1}})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, removed.ID, events[0].ID)
+	})
+
+	t.Run("outbox", func(t *testing.T) {
+		outboxEvents, err := db.OutboxEvents().ListAfter(ctx, OutboxOffset{}, 10)
+		require.NoError(t, err)
+
+		var recorded []int64
+		for _, outboxEvent := range outboxEvents {
+			if outboxEvent.EventType != types.OutboxEventAuditLog {
+				continue
+			}
+			var event types.AuditLogEvent
+			require.NoError(t, json.Unmarshal(outboxEvent.Payload, &event))
+			recorded = append(recorded, event.ID)
+		}
+		assert.Equal(t, []int64{added.ID, removed.ID, events[0].ID}, recorded)
+	})
+}
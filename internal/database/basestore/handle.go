@@ -79,6 +79,7 @@ type dbHandle struct {
 	*sql.DB
 	txOptions sql.TxOptions
 	logger    log.Logger
+	replicas  ReplicaPicker
 }
 
 // Raw attempts to unwrap a raw sql.DB pointer from the given value.
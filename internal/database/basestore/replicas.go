@@ -0,0 +1,129 @@
+package basestore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgconn"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ReplicaPicker selects a read replica of the primary database.
+type ReplicaPicker interface {
+	// Pick returns a connection to a replica that may serve reads, or nil if
+	// reads must be served by the primary.
+	Pick() *sql.DB
+
+	// MarkUnhealthy excludes the given replica from being picked after a
+	// query to it failed to connect.
+	MarkUnhealthy(*sql.DB)
+}
+
+// NewHandleWithReplicas returns a new transactable database handle using the given
+// primary database connection. Stores using this handle may send read-only queries
+// to one of the given replicas via Store.Replica.
+func NewHandleWithReplicas(logger log.Logger, db *sql.DB, replicas ReplicaPicker, txOptions sql.TxOptions) TransactableHandle {
+	return &dbHandle{
+		DB:        db,
+		logger:    logger.Scoped("db-handle", "internal database"),
+		txOptions: txOptions,
+		replicas:  replicas,
+	}
+}
+
+type staleReadsKey struct{}
+
+// WithStaleReads returns a context in which read-only queries of stores that
+// support read replicas may be sent to a replica. Callers opt in only when they
+// tolerate results that miss recent writes, up to the staleness bound of the
+// replicas.
+func WithStaleReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, staleReadsKey{}, true)
+}
+
+func staleReadsAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(staleReadsKey{}).(bool)
+	return allowed
+}
+
+// Replica returns a store that sends queries to a read replica of the database
+// when the given context opted in with WithStaleReads and a replica within its
+// staleness bound is available. The returned store must only be used for
+// read-only queries.
+//
+// Otherwise, and always within a transaction, the store is returned unchanged
+// so that reads observe their own writes.
+func (s *Store) Replica(ctx context.Context) *Store {
+	if !staleReadsAllowed(ctx) || s.InTransaction() {
+		return s
+	}
+	h, ok := s.handle.(*dbHandle)
+	if !ok || h.replicas == nil {
+		return s
+	}
+	replica := h.replicas.Pick()
+	if replica == nil {
+		return s
+	}
+	return &Store{handle: &replicaHandle{primary: h, replica: replica}}
+}
+
+// replicaHandle sends queries to a read replica and retries them on the primary
+// if the replica cannot be reached. Writes and transactions always use the primary.
+type replicaHandle struct {
+	primary *dbHandle
+	replica *sql.DB
+}
+
+var _ TransactableHandle = (*replicaHandle)(nil)
+
+func (h *replicaHandle) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	rows, err := h.replica.QueryContext(ctx, query, args...)
+	if h.shouldFallback(ctx, err) {
+		return h.primary.QueryContext(ctx, query, args...)
+	}
+	return rows, err
+}
+
+func (h *replicaHandle) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	row := h.replica.QueryRowContext(ctx, query, args...)
+	if h.shouldFallback(ctx, row.Err()) {
+		return h.primary.QueryRowContext(ctx, query, args...)
+	}
+	return row
+}
+
+func (h *replicaHandle) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return h.primary.ExecContext(ctx, query, args...)
+}
+
+func (h *replicaHandle) InTransaction() bool {
+	return false
+}
+
+func (h *replicaHandle) Transact(ctx context.Context) (TransactableHandle, error) {
+	return h.primary.Transact(ctx)
+}
+
+func (h *replicaHandle) Done(err error) error {
+	return h.primary.Done(err)
+}
+
+// shouldFallback returns true if the given error was caused by the replica being
+// unreachable rather than by the query itself. In that case the replica is marked
+// unhealthy and the query is retried on the primary.
+func (h *replicaHandle) shouldFallback(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) || errors.Is(err, sql.ErrNoRows) {
+		return false
+	}
+
+	h.primary.replicas.MarkUnhealthy(h.replica)
+	h.primary.logger.Warn("falling back to primary after replica query failed", log.Error(err))
+	return true
+}
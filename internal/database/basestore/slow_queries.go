@@ -0,0 +1,224 @@
+package basestore
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/keegancsmith/sqlf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+var (
+	slowQueryThreshold   = env.MustGetDuration("SRC_DB_SLOW_QUERY_THRESHOLD", 0, "Log queries issued through a database store that take at least this long. Zero disables slow query logging.")
+	slowQueryLogInterval = env.MustGetDuration("SRC_DB_SLOW_QUERY_LOG_INTERVAL", time.Minute, "The minimum interval between two slow query logs of the same statement.")
+	slowQueryExplain     = env.MustGetBool("SRC_DB_SLOW_QUERY_EXPLAIN", true, "Include the query plan in slow query logs.")
+)
+
+var slowQueriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_basestore_slow_queries_total",
+	Help: "Total number of queries issued through a base store exceeding the slow query threshold, by calling function.",
+}, []string{"name"})
+
+// maxSlowQueryFingerprints is the maximum number of statements whose last slow query log
+// is remembered. Statements beyond the limit may be logged more frequently than the log
+// interval.
+const maxSlowQueryFingerprints = 1000
+
+// explainTimeout is the maximum time spent capturing the plan of a slow query.
+const explainTimeout = 5 * time.Second
+
+// slowQueries logs the slow queries of all base stores. It is replaced in tests.
+var slowQueries = newSlowQueryLogger(slowQueryThreshold, slowQueryLogInterval, slowQueryExplain)
+
+// slowQueryLogger logs queries that take longer than a threshold, along with their plan
+// and the name of the observed operation that issued them. Logs are rate limited per
+// statement fingerprint, so that a single slow statement issued in a loop does not flood
+// the logs.
+type slowQueryLogger struct {
+	threshold time.Duration
+	interval  time.Duration
+	explain   bool
+
+	// logger is created on first use, as this logger is created during package
+	// initialization. It is set in tests.
+	logger     log.Logger
+	loggerOnce sync.Once
+
+	mu           sync.Mutex
+	fingerprints *lru.Cache[uint64, *slowQueryFingerprint]
+}
+
+// slowQueryFingerprint tracks the slow query logs of a single statement.
+type slowQueryFingerprint struct {
+	lastLogged time.Time
+	suppressed int
+}
+
+func newSlowQueryLogger(threshold, interval time.Duration, explain bool) *slowQueryLogger {
+	fingerprints, _ := lru.New[uint64, *slowQueryFingerprint](maxSlowQueryFingerprints)
+
+	return &slowQueryLogger{
+		threshold:    threshold,
+		interval:     interval,
+		explain:      explain,
+		fingerprints: fingerprints,
+	}
+}
+
+// observe logs the given query if it took at least the configured threshold, unless the
+// same statement was logged within the configured interval.
+func (l *slowQueryLogger) observe(o *queryObservation, elapsed time.Duration, err error) {
+	if l.threshold <= 0 || elapsed < l.threshold {
+		return
+	}
+	slowQueriesTotal.WithLabelValues(o.name).Inc()
+
+	statement := sanitizeQuery(o.query.Query(sqlf.PostgresBindVar))
+	suppressed, ok := l.allow(fingerprint(statement), time.Now())
+	if !ok {
+		return
+	}
+
+	fields := []log.Field{
+		log.String("caller", o.name),
+		log.String("operation", observation.OperationName(o.ctx)),
+		log.String("method", o.method),
+		log.Int64("duration_ms", elapsed.Milliseconds()),
+		log.String("statement", statement),
+		log.Int("args", len(o.query.Args())),
+		log.Int("suppressed", suppressed),
+	}
+	if err != nil {
+		fields = append(fields, log.Error(err))
+	}
+	if l.explain {
+		fields = append(fields, l.explainField(o, err))
+	}
+
+	l.loggerOnce.Do(func() {
+		if l.logger == nil {
+			l.logger = log.Scoped("slowQueries", "logs slow queries issued through database stores")
+		}
+	})
+	l.logger.Warn("slow query", fields...)
+}
+
+// allow returns true if a slow query with the given fingerprint should be logged at the
+// given time, along with the number of slow queries with the same fingerprint that were
+// not logged since the last log.
+func (l *slowQueryLogger) allow(fingerprint uint64, now time.Time) (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, ok := l.fingerprints.Get(fingerprint)
+	if !ok {
+		l.fingerprints.Add(fingerprint, &slowQueryFingerprint{lastLogged: now})
+		return 0, true
+	}
+	if now.Sub(state.lastLogged) < l.interval {
+		state.suppressed++
+		return 0, false
+	}
+
+	suppressed := state.suppressed
+	state.lastLogged = now
+	state.suppressed = 0
+	return suppressed, true
+}
+
+// explainField returns a log field holding the plan of the given query, or the reason
+// that the plan could not be captured. Plans are captured with EXPLAIN without ANALYZE,
+// so the query is planned but never executed again.
+func (l *slowQueryLogger) explainField(o *queryObservation, err error) log.Field {
+	switch {
+	case err != nil:
+		return log.String("plan", "unavailable: query failed")
+	case o.handle == nil || o.handle.InTransaction():
+		// The connection of a transaction may still be busy with the results of
+		// the query, and a failing EXPLAIN would abort the transaction
+		return log.String("plan", "unavailable: query issued within a transaction")
+	case !explainable(o.query.Query(sqlf.PostgresBindVar)):
+		return log.String("plan", "unavailable: statement cannot be explained")
+	}
+
+	// The context of the query may be close to its deadline or canceled, which is
+	// a frequent consequence of a slow query
+	ctx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+	defer cancel()
+
+	plan, err := explain(ctx, o.handle, o.query)
+	if err != nil {
+		return log.String("plan", "unavailable: "+err.Error())
+	}
+	return log.String("plan", plan)
+}
+
+// explain returns the text plan of the given query.
+func explain(ctx context.Context, handle TransactableHandle, query *sqlf.Query) (_ string, err error) {
+	rows, err := handle.QueryContext(ctx, "EXPLAIN "+query.Query(sqlf.PostgresBindVar), query.Args()...)
+	if err != nil {
+		return "", err
+	}
+	defer func() { err = CloseRows(rows, err) }()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// explainableKeywords are the keywords starting the statements that EXPLAIN accepts.
+var explainableKeywords = []string{"SELECT", "INSERT", "UPDATE", "DELETE", "WITH", "VALUES"}
+
+// explainable returns true if the given query is a single statement that can be passed
+// to EXPLAIN.
+func explainable(query string) bool {
+	statement := strings.TrimSpace(sanitizeQuery(query))
+	if strings.Contains(strings.TrimSuffix(statement, ";"), ";") {
+		return false
+	}
+
+	keyword, _, _ := strings.Cut(statement, " ")
+	keyword = strings.ToUpper(strings.TrimLeft(keyword, "("))
+	for _, explainableKeyword := range explainableKeywords {
+		if keyword == explainableKeyword {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// bindVarListPattern matches lists of bind variables, e.g. "$1, $2, $3".
+	bindVarListPattern = lazyregexp.New(`\$\d+(\s*,\s*\$\d+)*`)
+	// tupleListPattern matches lists of tuples of normalized bind variables, e.g.
+	// "($?), ($?)".
+	tupleListPattern = lazyregexp.New(`\(\$\?\)(\s*,\s*\(\$\?\))*`)
+)
+
+// fingerprint identifies the given sanitized statement regardless of the number and
+// position of its bind variables, so that statements with a variable number of values
+// in an IN clause or VALUES list share a fingerprint.
+func fingerprint(statement string) uint64 {
+	normalized := bindVarListPattern.ReplaceAllString(statement, "$$?")
+	normalized = tupleListPattern.ReplaceAllString(normalized, "($$?)")
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(normalized))
+	return h.Sum64()
+}
@@ -0,0 +1,142 @@
+package basestore
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestSlowQueryLogging(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := dbtest.NewRawDB(logger, t)
+	setupStoreTest(t, db)
+	store := testStore(t, db)
+
+	slowLogger, exportLogs := logtest.Captured(t)
+	old := slowQueries
+	slowQueries = newSlowQueryLogger(time.Nanosecond, time.Hour, true)
+	slowQueries.logger = slowLogger
+	t.Cleanup(func() { slowQueries = old })
+
+	op := observation.TestContext.Operation(observation.Op{Name: "Test.SlowQuery"})
+	ctx, _, endObservation := op.With(context.Background(), nil, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	for i := 0; i < 3; i++ {
+		// The number of values differs, but the statement fingerprint is the same
+		values := make([]*sqlf.Query, 0, i+1)
+		for j := 0; j <= i; j++ {
+			values = append(values, sqlf.Sprintf("(%s, %s)", j, 42))
+		}
+		if err := store.Exec(ctx, sqlf.Sprintf("INSERT INTO store_counts_test VALUES %s", sqlf.Join(values, ", "))); err != nil {
+			t.Fatalf("unexpected error inserting counts: %s", err)
+		}
+	}
+
+	logs := exportLogs().Filter(func(l logtest.CapturedLog) bool { return l.Message == "slow query" })
+	if len(logs) != 1 {
+		t.Fatalf("unexpected number of slow query logs. want=%d have=%d", 1, len(logs))
+	}
+	fields := logs[0].Fields
+	if fields["operation"] != "Test.SlowQuery" {
+		t.Errorf("unexpected operation. want=%q have=%v", "Test.SlowQuery", fields["operation"])
+	}
+	if caller, _ := fields["caller"].(string); !strings.HasSuffix(caller, ".TestSlowQueryLogging") {
+		t.Errorf("unexpected caller %v", fields["caller"])
+	}
+	if plan, _ := fields["plan"].(string); !strings.Contains(plan, "Insert on store_counts_test") {
+		t.Errorf("unexpected plan %v", fields["plan"])
+	}
+
+	// Plans are not captured within transactions
+	tx, err := store.Transact(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error starting transaction: %s", err)
+	}
+	defer func() { _ = tx.Done(nil) }()
+	if err := tx.Exec(context.Background(), sqlf.Sprintf("DELETE FROM store_counts_test")); err != nil {
+		t.Fatalf("unexpected error deleting counts: %s", err)
+	}
+
+	logs = exportLogs().Filter(func(l logtest.CapturedLog) bool { return l.Message == "slow query" })
+	if len(logs) != 2 {
+		t.Fatalf("unexpected number of slow query logs. want=%d have=%d", 2, len(logs))
+	}
+	if plan := logs[1].Fields["plan"]; plan != "unavailable: query issued within a transaction" {
+		t.Errorf("unexpected plan %v", plan)
+	}
+}
+
+func TestSlowQueryLoggerAllow(t *testing.T) {
+	l := newSlowQueryLogger(time.Second, time.Minute, false)
+	now := time.Now()
+
+	if _, ok := l.allow(1, now); !ok {
+		t.Errorf("expected the first slow query to be logged")
+	}
+	for i := 0; i < 3; i++ {
+		if _, ok := l.allow(1, now.Add(time.Second)); ok {
+			t.Errorf("expected slow query within the interval to be suppressed")
+		}
+	}
+	if _, ok := l.allow(2, now.Add(time.Second)); !ok {
+		t.Errorf("expected a slow query of another statement to be logged")
+	}
+	if suppressed, ok := l.allow(1, now.Add(time.Minute)); !ok || suppressed != 3 {
+		t.Errorf("unexpected decision after the interval. want=(3, true) have=(%d, %v)", suppressed, ok)
+	}
+}
+
+func TestExplainable(t *testing.T) {
+	testCases := map[string]bool{
+		"SELECT 1":                                  true,
+		"-- source: foo\n  select id FROM repo":     true,
+		"WITH x AS (SELECT 1) SELECT * FROM x":      true,
+		"(SELECT 1) UNION (SELECT 2)":               true,
+		"UPDATE repo SET name = $1;":                true,
+		"CREATE INDEX foo ON repo(name)":            false,
+		"SELECT 1; SELECT 2":                        false,
+		"LOCK TABLE repo IN EXCLUSIVE MODE":         false,
+		"SELECT pg_advisory_xact_lock($1, $2)":      true,
+		"VACUUM ANALYZE repo":                       false,
+		"INSERT INTO repo (name) VALUES ($1)":       true,
+		"DELETE FROM repo WHERE id = ANY($1)":       true,
+		"VALUES ($1, $2), ($3, $4)":                 true,
+		"SET LOCAL statement_timeout = '1s'":        false,
+		"   \n  SELECT id\n\tFROM repo   ":          true,
+		"BEGIN":                                     false,
+		"COMMIT":                                    false,
+		"SAVEPOINT sp_1":                            false,
+		"SELECT * FROM repo FOR UPDATE SKIP LOCKED": true,
+	}
+
+	for query, expected := range testCases {
+		if have := explainable(query); have != expected {
+			t.Errorf("unexpected result for %q. want=%v have=%v", query, expected, have)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	same := [][2]string{
+		{"SELECT * FROM repo WHERE id IN ($1)", "SELECT * FROM repo WHERE id IN ($1, $2, $3)"},
+		{"INSERT INTO t VALUES ($1, $2)", "INSERT INTO t VALUES ($1, $2), ($3, $4), ($5, $6)"},
+		{"SELECT * FROM repo WHERE id = $2 AND name = $1", "SELECT * FROM repo WHERE id = $1 AND name = $2"},
+	}
+	for _, pair := range same {
+		if fingerprint(pair[0]) != fingerprint(pair[1]) {
+			t.Errorf("expected %q and %q to share a fingerprint", pair[0], pair[1])
+		}
+	}
+
+	if fingerprint("SELECT * FROM repo WHERE id = $1") == fingerprint("SELECT * FROM repo WHERE name = $1") {
+		t.Errorf("expected statements on different columns to have different fingerprints")
+	}
+}
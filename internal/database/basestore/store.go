@@ -81,27 +81,42 @@ func (s *Store) With(other ShareableStore) *Store {
 	return &Store{handle: other.Handle()}
 }
 
-// Query performs QueryContext on the underlying connection.
+// Query performs QueryContext on the underlying connection. The span of a traced
+// query covers the time until the first result is available; the number of rows is
+// not recorded as the rows are consumed by the caller.
 func (s *Store) Query(ctx context.Context, query *sqlf.Query) (*sql.Rows, error) {
+	ctx, observation := startQueryObservation(ctx, s.handle, "Query", query, 3)
 	rows, err := s.handle.QueryContext(ctx, query.Query(sqlf.PostgresBindVar), query.Args()...)
+	observation.finish(-1, err)
 	return rows, s.wrapError(query, err)
 }
 
 // QueryRow performs QueryRowContext on the underlying connection.
 func (s *Store) QueryRow(ctx context.Context, query *sqlf.Query) *sql.Row {
-	return s.handle.QueryRowContext(ctx, query.Query(sqlf.PostgresBindVar), query.Args()...)
+	ctx, observation := startQueryObservation(ctx, s.handle, "QueryRow", query, 3)
+	row := s.handle.QueryRowContext(ctx, query.Query(sqlf.PostgresBindVar), query.Args()...)
+	observation.finish(-1, row.Err())
+	return row
 }
 
 // Exec performs a query without returning any rows.
 func (s *Store) Exec(ctx context.Context, query *sqlf.Query) error {
-	_, err := s.ExecResult(ctx, query)
+	_, err := s.execResult(ctx, query, 4)
 	return err
 }
 
 // ExecResult performs a query without returning any rows, but includes the
 // result of the execution.
 func (s *Store) ExecResult(ctx context.Context, query *sqlf.Query) (sql.Result, error) {
+	return s.execResult(ctx, query, 4)
+}
+
+// execResult performs ExecContext on the underlying connection. The given skip value
+// identifies the frame of the store method issuing the query (see callerName).
+func (s *Store) execResult(ctx context.Context, query *sqlf.Query, skip int) (sql.Result, error) {
+	ctx, observation := startQueryObservation(ctx, s.handle, "Exec", query, skip)
 	res, err := s.handle.ExecContext(ctx, query.Query(sqlf.PostgresBindVar), query.Args()...)
+	observation.finish(rowsAffected(res), err)
 	return res, s.wrapError(query, err)
 }
 
@@ -259,6 +259,76 @@ func TestScanFirstString(t *testing.T) {
 	}
 }
 
+type testReplicaPicker struct {
+	replica   *sql.DB
+	unhealthy []*sql.DB
+}
+
+func (p *testReplicaPicker) Pick() *sql.DB            { return p.replica }
+func (p *testReplicaPicker) MarkUnhealthy(db *sql.DB) { p.unhealthy = append(p.unhealthy, db) }
+
+func TestReplica(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := dbtest.NewRawDB(logger, t)
+	setupStoreTest(t, db)
+
+	if _, err := db.ExecContext(context.Background(), `INSERT INTO store_counts_test VALUES (1, 42)`); err != nil {
+		t.Fatalf("unexpected error inserting count: %s", err)
+	}
+
+	// An unreachable replica: queries fail before reaching Postgres
+	unreachable := dbtest.NewRawDB(logger, t)
+	if err := unreachable.Close(); err != nil {
+		t.Fatalf("unexpected error closing replica: %s", err)
+	}
+
+	picker := &testReplicaPicker{replica: unreachable}
+	store := NewWithHandle(NewHandleWithReplicas(logger, db, picker, sql.TxOptions{}))
+	ctx := WithStaleReads(context.Background())
+
+	t.Run("not opted in", func(t *testing.T) {
+		require.Same(t, store, store.Replica(context.Background()))
+	})
+
+	t.Run("falls back to primary", func(t *testing.T) {
+		replica := store.Replica(ctx)
+		if replica == store {
+			t.Fatal("expected a replica store")
+		}
+
+		count, _, err := ScanFirstInt(replica.Query(ctx, sqlf.Sprintf(`SELECT value FROM store_counts_test WHERE id = 1`)))
+		require.NoError(t, err)
+		require.Equal(t, 42, count)
+		require.Equal(t, []*sql.DB{unreachable}, picker.unhealthy)
+	})
+
+	t.Run("query errors are not retried", func(t *testing.T) {
+		picker.replica = db
+		picker.unhealthy = nil
+
+		_, err := store.Replica(ctx).Query(ctx, sqlf.Sprintf(`SELECT missing FROM store_counts_test`))
+		require.Error(t, err)
+		require.Empty(t, picker.unhealthy)
+	})
+
+	t.Run("transactions use the primary", func(t *testing.T) {
+		tx, err := store.Transact(ctx)
+		require.NoError(t, err)
+		defer func() { _ = tx.Done(nil) }()
+
+		require.Same(t, tx, tx.Replica(ctx))
+
+		// Nor do stores sharing the handle of the transaction.
+		shared := NewWithHandle(tx.Handle())
+		require.Same(t, shared, shared.Replica(ctx))
+	})
+
+	t.Run("no replica available", func(t *testing.T) {
+		picker.replica = nil
+		require.Same(t, store, store.Replica(ctx))
+	})
+}
+
 func recurSavepoints(t *testing.T, store *Store, index, rollbackAt int) {
 	if index == 0 {
 		return
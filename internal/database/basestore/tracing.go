@@ -0,0 +1,163 @@
+package basestore
+
+import (
+	"context"
+	"database/sql"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/sourcegraph/sourcegraph/internal/debugbundle"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/trace/policy"
+)
+
+// queryTracer creates the spans of traced queries. It is replaced in tests.
+var queryTracer = trace.Tracer{}
+
+var queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "src_basestore_query_duration_seconds",
+	Help:    "Time spent executing queries issued through a base store, by calling function.",
+	Buckets: prometheus.ExponentialBuckets(0.001, 4, 8),
+}, []string{"name", "method"})
+
+// maxTracedStatementLength is the maximum length of the sanitized SQL statement attached
+// to a query span.
+const maxTracedStatementLength = 2048
+
+// queryObservation tracks a single query issued through a base store.
+type queryObservation struct {
+	ctx    context.Context
+	handle TransactableHandle
+	query  *sqlf.Query
+	name   string
+	method string
+	start  time.Time
+	trace  *trace.Trace
+
+	// recorder is set when the query is part of a request captured in a debug bundle.
+	recorder *debugbundle.Recorder
+}
+
+// startQueryObservation begins tracking a query issued by the store method skip frames
+// above callerName. A span correlated with the parent trace of ctx is started only when
+// database query tracing is enabled in site configuration and ctx already carries a span,
+// so that queries never start traces of their own. The query is also recorded in the
+// debug bundle being captured for ctx, if any, and logged if it is slow.
+func startQueryObservation(ctx context.Context, handle TransactableHandle, method string, query *sqlf.Query, skip int) (context.Context, *queryObservation) {
+	o := &queryObservation{
+		ctx:      ctx,
+		handle:   handle,
+		query:    query,
+		name:     callerName(skip),
+		method:   method,
+		start:    time.Now(),
+		recorder: debugbundle.FromContext(ctx),
+	}
+
+	if !policy.DatabaseQueryTracing() || !oteltrace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, o
+	}
+
+	o.trace, ctx = queryTracer.New(ctx, "basestore."+method,
+		attribute.String("db.operation.name", o.name),
+		attribute.String("db.statement", sanitizeQuery(query.Query(sqlf.PostgresBindVar))),
+		attribute.Int("db.args.count", len(query.Args())),
+	)
+	return ctx, o
+}
+
+// finish records the duration of the query, logs it if it is slow, and ends its span,
+// if any. A negative row count indicates that the number of rows is unknown.
+func (o *queryObservation) finish(rows int64, err error) {
+	elapsed := time.Since(o.start)
+	queryDuration.WithLabelValues(o.name, o.method).Observe(elapsed.Seconds())
+	o.record(rows, elapsed, err)
+	slowQueries.observe(o, elapsed, err)
+
+	if o.trace == nil {
+		return
+	}
+	if rows >= 0 {
+		o.trace.SetAttributes(attribute.Int64("db.rows", rows))
+	}
+	o.trace.SetErrorIfNotContext(err)
+	o.trace.Finish()
+}
+
+// record adds the query to the debug bundle it is part of, if any.
+func (o *queryObservation) record(rows int64, elapsed time.Duration, err error) {
+	if o.recorder == nil {
+		return
+	}
+
+	q := debugbundle.Query{
+		Caller:    o.name,
+		Statement: sanitizeQuery(o.query.Query(sqlf.PostgresBindVar)),
+		Args:      len(o.query.Args()),
+		Start:     o.start,
+		Duration:  debugbundle.Duration(elapsed),
+		Rows:      rows,
+	}
+	if err != nil {
+		q.Error = err.Error()
+	}
+	o.recorder.RecordQuery(q)
+}
+
+// rowsAffected returns the number of rows affected by the given result, or -1 if the
+// result is unavailable.
+func rowsAffected(res sql.Result) int64 {
+	if res == nil {
+		return -1
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// sanitizeQuery collapses whitespace and strips line comments from the given query and
+// truncates it to maxTracedStatementLength. Queries are always parameterized, so argument
+// values are never part of the statement.
+func sanitizeQuery(query string) string {
+	lines := strings.Split(query, "\n")
+	for i, line := range lines {
+		if idx := strings.Index(line, "--"); idx >= 0 {
+			lines[i] = line[:idx]
+		}
+	}
+
+	sanitized := strings.Join(strings.Fields(strings.Join(lines, " ")), " ")
+	if len(sanitized) > maxTracedStatementLength {
+		sanitized = sanitized[:maxTracedStatementLength] + "..."
+	}
+	return sanitized
+}
+
+// callerName returns the unqualified name of the function skip frames above callerName,
+// e.g. "store.(*store).GetUploads". This identifies the query without its text, and is
+// bounded by the number of call sites.
+func callerName(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return "unknown"
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "unknown"
+	}
+
+	name := fn.Name()
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return name
+}
@@ -0,0 +1,158 @@
+package basestore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/log/logtest"
+	"go.opentelemetry.io/otel/attribute"
+	oteltracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/debugbundle"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/trace/policy"
+)
+
+func TestQueryTracing(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := dbtest.NewRawDB(logger, t)
+	setupStoreTest(t, db)
+	store := testStore(t, db)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := oteltracesdk.NewTracerProvider(oteltracesdk.WithSpanProcessor(recorder))
+	queryTracer = trace.Tracer{TracerProvider: provider}
+	t.Cleanup(func() { queryTracer = trace.Tracer{} })
+
+	ctx := context.Background()
+	parentCtx, parent := provider.Tracer(t.Name()).Start(ctx, "parent")
+	defer parent.End()
+
+	insertQuery := sqlf.Sprintf(`
+		-- source: internal/database/basestore/tracing_test.go:TestQueryTracing
+		INSERT INTO store_counts_test VALUES (%s, %s), (%s, %s)
+	`, 1, 42, 2, 43)
+
+	// Disabled by site configuration
+	policy.SetDatabaseQueryTracing(false)
+	if err := store.Exec(parentCtx, insertQuery); err != nil {
+		t.Fatalf("unexpected error inserting counts: %s", err)
+	}
+	if spans := recorder.Ended(); len(spans) != 0 {
+		t.Fatalf("unexpected spans with query tracing disabled: %d", len(spans))
+	}
+
+	policy.SetDatabaseQueryTracing(true)
+	t.Cleanup(func() { policy.SetDatabaseQueryTracing(false) })
+
+	// No parent trace
+	if err := store.Exec(ctx, insertQuery); err != nil {
+		t.Fatalf("unexpected error inserting counts: %s", err)
+	}
+	if spans := recorder.Ended(); len(spans) != 0 {
+		t.Fatalf("unexpected spans without a parent trace: %d", len(spans))
+	}
+
+	if err := store.Exec(parentCtx, insertQuery); err != nil {
+		t.Fatalf("unexpected error inserting counts: %s", err)
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("unexpected number of spans. want=%d have=%d", 1, len(spans))
+	}
+	span := spans[0]
+
+	if span.Name() != "basestore.Exec" {
+		t.Errorf("unexpected span name. want=%q have=%q", "basestore.Exec", span.Name())
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("query span is not a child of the parent span")
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	if have, want := attrs["db.statement"].AsString(), "INSERT INTO store_counts_test VALUES ($1, $2), ($3, $4)"; have != want {
+		t.Errorf("unexpected statement. want=%q have=%q", want, have)
+	}
+	if have, want := attrs["db.args.count"].AsInt64(), int64(4); have != want {
+		t.Errorf("unexpected argument count. want=%d have=%d", want, have)
+	}
+	if have, want := attrs["db.rows"].AsInt64(), int64(2); have != want {
+		t.Errorf("unexpected row count. want=%d have=%d", want, have)
+	}
+	if have := attrs["db.operation.name"].AsString(); !strings.HasSuffix(have, ".TestQueryTracing") {
+		t.Errorf("unexpected operation name %q", have)
+	}
+}
+
+func TestQueryDebugBundle(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := dbtest.NewRawDB(logger, t)
+	setupStoreTest(t, db)
+	store := testStore(t, db)
+
+	recorder := debugbundle.NewRecorder("GET", "/search")
+	ctx := debugbundle.WithRecorder(context.Background(), recorder)
+
+	insertQuery := sqlf.Sprintf(`INSERT INTO store_counts_test VALUES (%s, %s), (%s, %s)`, 1, 42, 2, 43)
+	if err := store.Exec(ctx, insertQuery); err != nil {
+		t.Fatalf("unexpected error inserting counts: %s", err)
+	}
+	if err := store.Exec(ctx, sqlf.Sprintf(`SELECT * FROM missing_table`)); err == nil {
+		t.Fatalf("expected an error querying a missing table")
+	}
+
+	queries := recorder.Finish(200).Queries
+	if len(queries) != 2 {
+		t.Fatalf("unexpected number of queries. want=%d have=%d", 2, len(queries))
+	}
+	if have, want := queries[0].Statement, "INSERT INTO store_counts_test VALUES ($1, $2), ($3, $4)"; have != want {
+		t.Errorf("unexpected statement. want=%q have=%q", want, have)
+	}
+	if queries[0].Args != 4 || queries[0].Rows != 2 || queries[0].Error != "" {
+		t.Errorf("unexpected query. args=%d rows=%d error=%q", queries[0].Args, queries[0].Rows, queries[0].Error)
+	}
+	if !strings.HasSuffix(queries[0].Caller, ".TestQueryDebugBundle") {
+		t.Errorf("unexpected caller %q", queries[0].Caller)
+	}
+	if queries[1].Error == "" {
+		t.Errorf("expected the failed query to record its error")
+	}
+}
+
+func TestSanitizeQuery(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query:    "SELECT 1",
+			expected: "SELECT 1",
+		},
+		{
+			query: `
+				-- source: internal/database/repos.go:List
+				SELECT id, name
+				FROM repo   -- only live repositories
+				WHERE deleted_at IS NULL AND id = $1
+			`,
+			expected: "SELECT id, name FROM repo WHERE deleted_at IS NULL AND id = $1",
+		},
+		{
+			query:    "SELECT " + strings.Repeat("x", maxTracedStatementLength),
+			expected: ("SELECT " + strings.Repeat("x", maxTracedStatementLength))[:maxTracedStatementLength] + "...",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if have := sanitizeQuery(testCase.query); have != testCase.expected {
+			t.Errorf("unexpected sanitized query. want=%q have=%q", testCase.expected, have)
+		}
+	}
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
 )
 
 type WebhookAction struct {
@@ -18,17 +20,40 @@ type WebhookAction struct {
 	URL            string
 	IncludeResults bool
 
+	// PayloadTemplate, if set, is a Go template rendered over the monitor
+	// results that replaces the default JSON payload.
+	PayloadTemplate *string
+
+	// SigningSecret, if set, is used to sign each delivery. It is encrypted
+	// at rest with the webhook key and decrypted when the action is read.
+	SigningSecret *string
+
 	CreatedBy int32
 	CreatedAt time.Time
 	ChangedBy int32
 	ChangedAt time.Time
 }
 
+// WebhookActionArgs are the user-editable fields of a webhook action.
+type WebhookActionArgs struct {
+	Enabled         bool
+	IncludeResults  bool
+	URL             string
+	PayloadTemplate *string
+
+	// SigningSecret is left unchanged on update when nil, and cleared when
+	// set to the empty string.
+	SigningSecret *string
+}
+
 const updateWebhookActionQuery = `
 UPDATE cm_webhooks
 SET enabled = %s,
     include_results = %s,
 	url = %s,
+	payload_template = %s,
+	signing_secret = CASE WHEN %s::text IS NULL THEN signing_secret ELSE NULLIF(%s, '') END,
+	encryption_key_id = CASE WHEN %s::text IS NULL THEN encryption_key_id ELSE %s END,
 	changed_by = %s,
 	changed_at = %s
 WHERE
@@ -41,13 +66,23 @@ WHERE
 RETURNING %s;
 `
 
-func (s *codeMonitorStore) UpdateWebhookAction(ctx context.Context, id int64, enabled, includeResults bool, url string) (*WebhookAction, error) {
+func (s *codeMonitorStore) UpdateWebhookAction(ctx context.Context, id int64, args *WebhookActionArgs) (*WebhookAction, error) {
+	secret, keyID, err := s.encryptSigningSecret(ctx, args.SigningSecret)
+	if err != nil {
+		return nil, err
+	}
+
 	a := actor.FromContext(ctx)
 	q := sqlf.Sprintf(
 		updateWebhookActionQuery,
-		enabled,
-		includeResults,
-		url,
+		args.Enabled,
+		args.IncludeResults,
+		args.URL,
+		args.PayloadTemplate,
+		secret,
+		secret,
+		secret,
+		keyID,
 		a.UID,
 		s.Now(),
 		id,
@@ -56,25 +91,33 @@ func (s *codeMonitorStore) UpdateWebhookAction(ctx context.Context, id int64, en
 	)
 
 	row := s.QueryRow(ctx, q)
-	return scanWebhookAction(row)
+	return s.scanWebhookAction(ctx, row)
 }
 
 const createWebhookActionQuery = `
 INSERT INTO cm_webhooks
-(monitor, enabled, include_results, url, created_by, created_at, changed_by, changed_at)
-VALUES (%s,%s,%s,%s,%s,%s,%s,%s)
+(monitor, enabled, include_results, url, payload_template, signing_secret, encryption_key_id, created_by, created_at, changed_by, changed_at)
+VALUES (%s,%s,%s,%s,%s,NULLIF(%s, ''),%s,%s,%s,%s,%s)
 RETURNING %s;
 `
 
-func (s *codeMonitorStore) CreateWebhookAction(ctx context.Context, monitorID int64, enabled, includeResults bool, url string) (*WebhookAction, error) {
+func (s *codeMonitorStore) CreateWebhookAction(ctx context.Context, monitorID int64, args *WebhookActionArgs) (*WebhookAction, error) {
+	secret, keyID, err := s.encryptSigningSecret(ctx, args.SigningSecret)
+	if err != nil {
+		return nil, err
+	}
+
 	now := s.Now()
 	a := actor.FromContext(ctx)
 	q := sqlf.Sprintf(
 		createWebhookActionQuery,
 		monitorID,
-		enabled,
-		includeResults,
-		url,
+		args.Enabled,
+		args.IncludeResults,
+		args.URL,
+		args.PayloadTemplate,
+		secret,
+		keyID,
 		a.UID,
 		now,
 		a.UID,
@@ -83,7 +126,28 @@ func (s *codeMonitorStore) CreateWebhookAction(ctx context.Context, monitorID in
 	)
 
 	row := s.QueryRow(ctx, q)
-	return scanWebhookAction(row)
+	return s.scanWebhookAction(ctx, row)
+}
+
+// encryptSigningSecret encrypts a signing secret to be written by a create or
+// update query. A nil secret stays nil so that updates keep the existing value,
+// and an empty secret stays empty so that it clears the existing value.
+func (s *codeMonitorStore) encryptSigningSecret(ctx context.Context, secret *string) (_ *string, keyID string, err error) {
+	if secret == nil || *secret == "" {
+		return secret, "", nil
+	}
+
+	encrypted, keyID, err := encryption.MaybeEncrypt(ctx, webhookActionKey(), *secret)
+	if err != nil {
+		return nil, "", err
+	}
+	return &encrypted, keyID, nil
+}
+
+// webhookActionKey returns the key used to encrypt the signing secrets of
+// webhook actions.
+func webhookActionKey() encryption.Key {
+	return keyring.Default().WebhookKey
 }
 
 const deleteWebhookActionQuery = `
@@ -135,7 +199,7 @@ func (s *codeMonitorStore) GetWebhookAction(ctx context.Context, webhookID int64
 		webhookID,
 	)
 	row := s.QueryRow(ctx, q)
-	return scanWebhookAction(row)
+	return s.scanWebhookAction(ctx, row)
 }
 
 const listWebhookActionsQuery = `
@@ -158,7 +222,7 @@ func (s *codeMonitorStore) ListWebhookActions(ctx context.Context, opts ListActi
 		return nil, err
 	}
 	defer rows.Close()
-	return scanWebhookActions(rows)
+	return s.scanWebhookActions(ctx, rows)
 }
 
 // webhookActionColumns is the set of columns in the cm_webhooks table
@@ -169,16 +233,19 @@ var webhookActionColumns = []*sqlf.Query{
 	sqlf.Sprintf("cm_webhooks.enabled"),
 	sqlf.Sprintf("cm_webhooks.url"),
 	sqlf.Sprintf("cm_webhooks.include_results"),
+	sqlf.Sprintf("cm_webhooks.payload_template"),
+	sqlf.Sprintf("cm_webhooks.signing_secret"),
+	sqlf.Sprintf("cm_webhooks.encryption_key_id"),
 	sqlf.Sprintf("cm_webhooks.created_by"),
 	sqlf.Sprintf("cm_webhooks.created_at"),
 	sqlf.Sprintf("cm_webhooks.changed_by"),
 	sqlf.Sprintf("cm_webhooks.changed_at"),
 }
 
-func scanWebhookActions(rows *sql.Rows) ([]*WebhookAction, error) {
+func (s *codeMonitorStore) scanWebhookActions(ctx context.Context, rows *sql.Rows) ([]*WebhookAction, error) {
 	var ws []*WebhookAction
 	for rows.Next() {
-		w, err := scanWebhookAction(rows)
+		w, err := s.scanWebhookAction(ctx, rows)
 		if err != nil {
 			return nil, err
 		}
@@ -188,19 +255,36 @@ func scanWebhookActions(rows *sql.Rows) ([]*WebhookAction, error) {
 }
 
 // scanWebhookAction scans a WebhookAction from a *sql.Row or *sql.Rows.
-// It must be kept in sync with webhookActionColumns.
-func scanWebhookAction(scanner dbutil.Scanner) (*WebhookAction, error) {
-	var w WebhookAction
-	err := scanner.Scan(
+// It must be kept in sync with webhookActionColumns. The signing secret is
+// decrypted with the webhook key.
+func (s *codeMonitorStore) scanWebhookAction(ctx context.Context, scanner dbutil.Scanner) (*WebhookAction, error) {
+	var (
+		w     WebhookAction
+		keyID string
+	)
+	if err := scanner.Scan(
 		&w.ID,
 		&w.Monitor,
 		&w.Enabled,
 		&w.URL,
 		&w.IncludeResults,
+		&w.PayloadTemplate,
+		&w.SigningSecret,
+		&keyID,
 		&w.CreatedBy,
 		&w.CreatedAt,
 		&w.ChangedBy,
 		&w.ChangedAt,
-	)
-	return &w, err
+	); err != nil {
+		return &w, err
+	}
+
+	if w.SigningSecret != nil {
+		secret, err := encryption.MaybeDecrypt(ctx, webhookActionKey(), *w.SigningSecret, keyID)
+		if err != nil {
+			return nil, err
+		}
+		w.SigningSecret = &secret
+	}
+	return &w, nil
 }
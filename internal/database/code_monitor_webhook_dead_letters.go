@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// WebhookDeadLetter is a code monitor webhook delivery that kept failing
+// after all retries were exhausted.
+type WebhookDeadLetter struct {
+	ID             int64
+	Webhook        int64
+	ActionJob      *int32
+	Payload        string
+	FailureMessage string
+	NumAttempts    int32
+	CreatedAt      time.Time
+}
+
+// ListWebhookDeadLettersOpts is a struct that contains options for listing
+// and counting webhook dead letters.
+type ListWebhookDeadLettersOpts struct {
+	// WebhookID, if set, will filter to only the dead letters of the given
+	// webhook action. Refers to cm_webhooks(id)
+	WebhookID *int64
+
+	// First, if set, limits the number of dead letters returned to the
+	// first n.
+	First *int
+
+	// After, if set, begins listing dead letters after the given id
+	After *int64
+}
+
+func (o ListWebhookDeadLettersOpts) Conds() *sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.WebhookID != nil {
+		conds = append(conds, sqlf.Sprintf("webhook = %s", *o.WebhookID))
+	}
+	if o.After != nil {
+		conds = append(conds, sqlf.Sprintf("id > %s", *o.After))
+	}
+	return sqlf.Join(conds, "AND")
+}
+
+func (o ListWebhookDeadLettersOpts) Limit() *sqlf.Query {
+	if o.First == nil {
+		return sqlf.Sprintf("ALL")
+	}
+	return sqlf.Sprintf("%s", *o.First)
+}
+
+const createWebhookDeadLetterQuery = `
+INSERT INTO cm_webhook_dead_letters
+(webhook, action_job, payload, failure_message, num_attempts, created_at)
+VALUES (%s,%s,%s,%s,%s,%s)
+RETURNING %s;
+`
+
+// CreateWebhookDeadLetter records a delivery of the given webhook action that
+// will not be retried anymore.
+func (s *codeMonitorStore) CreateWebhookDeadLetter(ctx context.Context, webhookID int64, actionJobID int32, payload, failureMessage string, numAttempts int32) (*WebhookDeadLetter, error) {
+	q := sqlf.Sprintf(
+		createWebhookDeadLetterQuery,
+		webhookID,
+		actionJobID,
+		payload,
+		failureMessage,
+		numAttempts,
+		s.Now(),
+		sqlf.Join(webhookDeadLetterColumns, ","),
+	)
+	return scanWebhookDeadLetter(s.QueryRow(ctx, q))
+}
+
+const listWebhookDeadLettersQuery = `
+SELECT %s -- webhookDeadLetterColumns
+FROM cm_webhook_dead_letters
+WHERE %s
+ORDER BY id ASC
+LIMIT %s;
+`
+
+func (s *codeMonitorStore) ListWebhookDeadLetters(ctx context.Context, opts ListWebhookDeadLettersOpts) ([]*WebhookDeadLetter, error) {
+	q := sqlf.Sprintf(
+		listWebhookDeadLettersQuery,
+		sqlf.Join(webhookDeadLetterColumns, ","),
+		opts.Conds(),
+		opts.Limit(),
+	)
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanWebhookDeadLetters(rows)
+}
+
+const countWebhookDeadLettersQuery = `
+SELECT COUNT(*)
+FROM cm_webhook_dead_letters
+WHERE webhook = %s;
+`
+
+func (s *codeMonitorStore) CountWebhookDeadLetters(ctx context.Context, webhookID int64) (int32, error) {
+	var count int32
+	err := s.QueryRow(ctx, sqlf.Sprintf(countWebhookDeadLettersQuery, webhookID)).Scan(&count)
+	return count, err
+}
+
+// webhookDeadLetterColumns is the set of columns in the cm_webhook_dead_letters
+// table. This must be kept in sync with scanWebhookDeadLetter.
+var webhookDeadLetterColumns = []*sqlf.Query{
+	sqlf.Sprintf("cm_webhook_dead_letters.id"),
+	sqlf.Sprintf("cm_webhook_dead_letters.webhook"),
+	sqlf.Sprintf("cm_webhook_dead_letters.action_job"),
+	sqlf.Sprintf("cm_webhook_dead_letters.payload"),
+	sqlf.Sprintf("cm_webhook_dead_letters.failure_message"),
+	sqlf.Sprintf("cm_webhook_dead_letters.num_attempts"),
+	sqlf.Sprintf("cm_webhook_dead_letters.created_at"),
+}
+
+func scanWebhookDeadLetters(rows *sql.Rows) ([]*WebhookDeadLetter, error) {
+	var ds []*WebhookDeadLetter
+	for rows.Next() {
+		d, err := scanWebhookDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		ds = append(ds, d)
+	}
+	return ds, rows.Err()
+}
+
+// scanWebhookDeadLetter scans a WebhookDeadLetter from a *sql.Row or
+// *sql.Rows. It must be kept in sync with webhookDeadLetterColumns.
+func scanWebhookDeadLetter(scanner dbutil.Scanner) (*WebhookDeadLetter, error) {
+	var d WebhookDeadLetter
+	err := scanner.Scan(
+		&d.ID,
+		&d.Webhook,
+		&d.ActionJob,
+		&d.Payload,
+		&d.FailureMessage,
+		&d.NumAttempts,
+		&d.CreatedAt,
+	)
+	return &d, err
+}
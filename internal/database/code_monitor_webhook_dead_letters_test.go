@@ -0,0 +1,71 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+func TestCodeMonitorStoreWebhookDeadLetters(t *testing.T) {
+	ctx, db, s := newTestStore(t)
+	_, _, userCtx := newTestUser(ctx, t, db)
+	fixtures := s.insertTestMonitor(userCtx, t)
+
+	wa, err := s.CreateWebhookAction(userCtx, fixtures.monitor.ID, &WebhookActionArgs{
+		Enabled:         true,
+		URL:             "https://example.com/webhook",
+		PayloadTemplate: pointers.Ptr(`{"text": {{ json .MonitorDescription }}}`),
+		SigningSecret:   pointers.Ptr("hunter2"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", *wa.SigningSecret)
+
+	// Updating without a secret keeps the existing one.
+	wa, err = s.UpdateWebhookAction(userCtx, wa.ID, &WebhookActionArgs{Enabled: true, URL: wa.URL})
+	require.NoError(t, err)
+	require.Nil(t, wa.PayloadTemplate)
+	require.Equal(t, "hunter2", *wa.SigningSecret)
+
+	triggerJobs, err := s.EnqueueQueryTriggerJobs(ctx)
+	require.NoError(t, err)
+	actionJobs, err := s.EnqueueActionJobsForMonitor(ctx, fixtures.monitor.ID, triggerJobs[0].ID)
+	require.NoError(t, err)
+
+	var webhookJob *ActionJob
+	for _, j := range actionJobs {
+		if j.Webhook != nil {
+			webhookJob = j
+		}
+	}
+	require.NotNil(t, webhookJob)
+
+	t.Run("CreateListCount", func(t *testing.T) {
+		d1, err := s.CreateWebhookDeadLetter(ctx, wa.ID, webhookJob.ID, "payload 1", "status 500", 5)
+		require.NoError(t, err)
+		d2, err := s.CreateWebhookDeadLetter(ctx, wa.ID, webhookJob.ID, "payload 2", "status 502", 5)
+		require.NoError(t, err)
+
+		count, err := s.CountWebhookDeadLetters(ctx, wa.ID)
+		require.NoError(t, err)
+		require.Equal(t, int32(2), count)
+
+		got, err := s.ListWebhookDeadLetters(ctx, ListWebhookDeadLettersOpts{WebhookID: &wa.ID})
+		require.NoError(t, err)
+		require.Equal(t, []*WebhookDeadLetter{d1, d2}, got)
+
+		got, err = s.ListWebhookDeadLetters(ctx, ListWebhookDeadLettersOpts{WebhookID: &wa.ID, After: &d1.ID, First: pointers.Ptr(1)})
+		require.NoError(t, err)
+		require.Equal(t, []*WebhookDeadLetter{d2}, got)
+	})
+
+	t.Run("DeletedWithWebhook", func(t *testing.T) {
+		err := s.DeleteWebhookActions(ctx, fixtures.monitor.ID, wa.ID)
+		require.NoError(t, err)
+
+		count, err := s.CountWebhookDeadLetters(ctx, wa.ID)
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+}
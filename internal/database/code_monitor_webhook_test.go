@@ -4,12 +4,16 @@ import (
 	"context"
 	"testing"
 
+	"github.com/keegancsmith/sqlf"
 	"github.com/stretchr/testify/require"
 
 	"github.com/sourcegraph/log/logtest"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
+	et "github.com/sourcegraph/sourcegraph/internal/encryption/testing"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
 func TestCodeMonitorStoreWebhooks(t *testing.T) {
@@ -27,7 +31,7 @@ func TestCodeMonitorStoreWebhooks(t *testing.T) {
 		s := CodeMonitorsWith(db)
 		fixtures := s.insertTestMonitor(ctx, t)
 
-		action, err := s.CreateWebhookAction(ctx, fixtures.monitor.ID, true, false, url1)
+		action, err := s.CreateWebhookAction(ctx, fixtures.monitor.ID, &WebhookActionArgs{Enabled: true, IncludeResults: false, URL: url1})
 		require.NoError(t, err)
 
 		got, err := s.GetWebhookAction(ctx, action.ID)
@@ -44,10 +48,10 @@ func TestCodeMonitorStoreWebhooks(t *testing.T) {
 		s := CodeMonitorsWith(db)
 		fixtures := s.insertTestMonitor(ctx, t)
 
-		action, err := s.CreateWebhookAction(ctx, fixtures.monitor.ID, true, false, url1)
+		action, err := s.CreateWebhookAction(ctx, fixtures.monitor.ID, &WebhookActionArgs{Enabled: true, IncludeResults: false, URL: url1})
 		require.NoError(t, err)
 
-		updated, err := s.UpdateWebhookAction(ctx, action.ID, false, false, url2)
+		updated, err := s.UpdateWebhookAction(ctx, action.ID, &WebhookActionArgs{Enabled: false, IncludeResults: false, URL: url2})
 		require.NoError(t, err)
 		require.Equal(t, false, updated.Enabled)
 		require.Equal(t, url2, updated.URL)
@@ -64,7 +68,7 @@ func TestCodeMonitorStoreWebhooks(t *testing.T) {
 		_, _, ctx := newTestUser(ctx, t, db)
 		s := CodeMonitorsWith(db)
 
-		_, err := s.UpdateWebhookAction(ctx, 383838, false, false, url2)
+		_, err := s.UpdateWebhookAction(ctx, 383838, &WebhookActionArgs{Enabled: false, IncludeResults: false, URL: url2})
 		require.Error(t, err)
 	})
 
@@ -76,10 +80,10 @@ func TestCodeMonitorStoreWebhooks(t *testing.T) {
 		s := CodeMonitorsWith(db)
 		fixtures := s.insertTestMonitor(ctx, t)
 
-		action1, err := s.CreateWebhookAction(ctx, fixtures.monitor.ID, true, false, url1)
+		action1, err := s.CreateWebhookAction(ctx, fixtures.monitor.ID, &WebhookActionArgs{Enabled: true, IncludeResults: false, URL: url1})
 		require.NoError(t, err)
 
-		action2, err := s.CreateWebhookAction(ctx, fixtures.monitor.ID, true, false, url1)
+		action2, err := s.CreateWebhookAction(ctx, fixtures.monitor.ID, &WebhookActionArgs{Enabled: true, IncludeResults: false, URL: url1})
 		require.NoError(t, err)
 
 		err = s.DeleteWebhookActions(ctx, fixtures.monitor.ID, action1.ID)
@@ -104,7 +108,7 @@ func TestCodeMonitorStoreWebhooks(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 0, count)
 
-		_, err = s.CreateWebhookAction(ctx, fixtures.monitor.ID, true, false, url1)
+		_, err = s.CreateWebhookAction(ctx, fixtures.monitor.ID, &WebhookActionArgs{Enabled: true, IncludeResults: false, URL: url1})
 		require.NoError(t, err)
 
 		count, err = s.CountWebhookActions(ctx, fixtures.monitor.ID)
@@ -124,10 +128,10 @@ func TestCodeMonitorStoreWebhooks(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, actions, 0)
 
-		_, err = s.CreateWebhookAction(ctx, fixtures.monitor.ID, true, false, url1)
+		_, err = s.CreateWebhookAction(ctx, fixtures.monitor.ID, &WebhookActionArgs{Enabled: true, IncludeResults: false, URL: url1})
 		require.NoError(t, err)
 
-		_, err = s.CreateWebhookAction(ctx, fixtures.monitor.ID, true, false, url2)
+		_, err = s.CreateWebhookAction(ctx, fixtures.monitor.ID, &WebhookActionArgs{Enabled: true, IncludeResults: false, URL: url2})
 		require.NoError(t, err)
 
 		actions2, err := s.ListWebhookActions(ctx, ListActionsOpts{MonitorID: &fixtures.monitor.ID})
@@ -149,19 +153,42 @@ func TestCodeMonitorStoreWebhooks(t *testing.T) {
 		fixtures := s.insertTestMonitor(ctx1, t)
 		_ = s.insertTestMonitor(ctx2, t)
 
-		wa, err := s.CreateWebhookAction(ctx1, fixtures.monitor.ID, true, true, "https://true.com")
+		wa, err := s.CreateWebhookAction(ctx1, fixtures.monitor.ID, &WebhookActionArgs{Enabled: true, IncludeResults: true, URL: "https://true.com"})
 		require.NoError(t, err)
 
 		// User1 can update it
-		_, err = s.UpdateWebhookAction(ctx1, wa.ID, true, true, "https://false.com")
+		_, err = s.UpdateWebhookAction(ctx1, wa.ID, &WebhookActionArgs{Enabled: true, IncludeResults: true, URL: "https://false.com"})
 		require.NoError(t, err)
 
 		// User2 cannot update it
-		_, err = s.UpdateWebhookAction(ctx2, wa.ID, true, true, "https://truer.com")
+		_, err = s.UpdateWebhookAction(ctx2, wa.ID, &WebhookActionArgs{Enabled: true, IncludeResults: true, URL: "https://truer.com"})
 		require.Error(t, err)
 
 		wa, err = s.GetWebhookAction(ctx1, wa.ID)
 		require.NoError(t, err)
 		require.Equal(t, wa.URL, "https://false.com")
 	})
+	t.Run("EncryptedSigningSecret", func(t *testing.T) {
+		keyring.MockDefault(keyring.Ring{WebhookKey: et.TestKey{}})
+		t.Cleanup(func() { keyring.MockDefault(keyring.Ring{}) })
+
+		ctx, db, s := newTestStore(t)
+		_, _, userCtx := newTestUser(ctx, t, db)
+		fixtures := s.insertTestMonitor(userCtx, t)
+
+		wa, err := s.CreateWebhookAction(userCtx, fixtures.monitor.ID, &WebhookActionArgs{Enabled: true, URL: url1, SigningSecret: pointers.Ptr("hunter2")})
+		require.NoError(t, err)
+		require.Equal(t, "hunter2", *wa.SigningSecret)
+
+		var stored, keyID string
+		err = s.QueryRow(ctx, sqlf.Sprintf("SELECT signing_secret, encryption_key_id FROM cm_webhooks WHERE id = %s", wa.ID)).Scan(&stored, &keyID)
+		require.NoError(t, err)
+		require.NotEqual(t, "hunter2", stored)
+		require.Equal(t, testEncryptionKeyID(et.TestKey{}), keyID)
+
+		// Clearing the secret removes it.
+		wa, err = s.UpdateWebhookAction(userCtx, wa.ID, &WebhookActionArgs{Enabled: true, URL: url1, SigningSecret: pointers.Ptr("")})
+		require.NoError(t, err)
+		require.Nil(t, wa.SigningSecret)
+	})
 }
@@ -57,13 +57,17 @@ type CodeMonitorStore interface {
 	GetEmailAction(ctx context.Context, emailID int64) (*EmailAction, error)
 	ListEmailActions(context.Context, ListActionsOpts) ([]*EmailAction, error)
 
-	UpdateWebhookAction(_ context.Context, id int64, enabled, includeResults bool, url string) (*WebhookAction, error)
-	CreateWebhookAction(ctx context.Context, monitorID int64, enabled, includeResults bool, url string) (*WebhookAction, error)
+	UpdateWebhookAction(_ context.Context, id int64, _ *WebhookActionArgs) (*WebhookAction, error)
+	CreateWebhookAction(ctx context.Context, monitorID int64, _ *WebhookActionArgs) (*WebhookAction, error)
 	DeleteWebhookActions(ctx context.Context, monitorID int64, ids ...int64) error
 	CountWebhookActions(ctx context.Context, monitorID int64) (int, error)
 	GetWebhookAction(ctx context.Context, id int64) (*WebhookAction, error)
 	ListWebhookActions(context.Context, ListActionsOpts) ([]*WebhookAction, error)
 
+	CreateWebhookDeadLetter(ctx context.Context, webhookID int64, actionJobID int32, payload, failureMessage string, numAttempts int32) (*WebhookDeadLetter, error)
+	ListWebhookDeadLetters(context.Context, ListWebhookDeadLettersOpts) ([]*WebhookDeadLetter, error)
+	CountWebhookDeadLetters(ctx context.Context, webhookID int64) (int32, error)
+
 	UpdateSlackWebhookAction(_ context.Context, id int64, enabled, includeResults bool, url string) (*SlackWebhookAction, error)
 	CreateSlackWebhookAction(ctx context.Context, monitorID int64, enabled, includeResults bool, url string) (*SlackWebhookAction, error)
 	DeleteSlackWebhookActions(ctx context.Context, monitorID int64, ids ...int64) error
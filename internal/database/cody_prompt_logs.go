@@ -0,0 +1,223 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// CodyPromptLogStore stores the prompts and responses of Cody completions requests.
+type CodyPromptLogStore interface {
+	basestore.ShareableStore
+
+	Create(context.Context, *types.CodyPromptLog) error
+	List(context.Context, CodyPromptLogListOpts) ([]*types.CodyPromptLog, int64, error)
+	DeleteStale(context.Context, time.Duration) error
+}
+
+type codyPromptLogStore struct {
+	*basestore.Store
+	key encryption.Key
+}
+
+var _ CodyPromptLogStore = &codyPromptLogStore{}
+
+func CodyPromptLogsWith(other basestore.ShareableStore, key encryption.Key) CodyPromptLogStore {
+	return &codyPromptLogStore{
+		Store: basestore.NewWithHandle(other.Handle()),
+		key:   key,
+	}
+}
+
+func (s *codyPromptLogStore) Create(ctx context.Context, log *types.CodyPromptLog) error {
+	createdAt := log.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = timeutil.Now()
+	}
+
+	rawPrompt, _, err := log.Prompt.Encrypt(ctx, s.key)
+	if err != nil {
+		return err
+	}
+	rawResponse, keyID, err := log.Response.Encrypt(ctx, s.key)
+	if err != nil {
+		return err
+	}
+
+	q := sqlf.Sprintf(
+		codyPromptLogCreateQueryFmtstr,
+		dbutil.NullInt32Column(log.UserID),
+		dbutil.NewNullString(log.AnonymousUserID),
+		log.Intent,
+		log.Provider,
+		log.Model,
+		rawPrompt,
+		rawResponse,
+		keyID,
+		createdAt,
+		sqlf.Join(codyPromptLogColumns, ", "),
+	)
+
+	row := s.QueryRow(ctx, q)
+	if err := s.scanCodyPromptLog(log, row); err != nil {
+		return errors.Wrap(err, "scanning cody prompt log")
+	}
+
+	return nil
+}
+
+type CodyPromptLogListOpts struct {
+	// The maximum number of entries to return, and the cursor, if any. Like for
+	// webhook logs, the cursor is the ID of the next entry, as new entries are
+	// recorded while paging.
+	Limit  int
+	Cursor int64
+
+	// If set, only the records of this user are returned.
+	UserID *int32
+
+	Since *time.Time
+	Until *time.Time
+}
+
+func (opts *CodyPromptLogListOpts) predicates() []*sqlf.Query {
+	preds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if id := opts.UserID; id != nil {
+		preds = append(preds, sqlf.Sprintf("user_id = %s", *id))
+	}
+	if since := opts.Since; since != nil {
+		preds = append(preds, sqlf.Sprintf("created_at >= %s", *since))
+	}
+	if until := opts.Until; until != nil {
+		preds = append(preds, sqlf.Sprintf("created_at <= %s", *until))
+	}
+	if cursor := opts.Cursor; cursor != 0 {
+		preds = append(preds, sqlf.Sprintf("id <= %s", cursor))
+	}
+
+	return preds
+}
+
+// List returns the matching records, newest first, and the cursor of the next
+// page, which is 0 if there are no more records.
+func (s *codyPromptLogStore) List(ctx context.Context, opts CodyPromptLogListOpts) (_ []*types.CodyPromptLog, _ int64, err error) {
+	limit := sqlf.Sprintf("")
+	if opts.Limit != 0 {
+		limit = sqlf.Sprintf("LIMIT %s", opts.Limit+1)
+	}
+
+	q := sqlf.Sprintf(
+		codyPromptLogListQueryFmtstr,
+		sqlf.Join(codyPromptLogColumns, ", "),
+		sqlf.Join(opts.predicates(), " AND "),
+		limit,
+	)
+
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	logs := []*types.CodyPromptLog{}
+	for rows.Next() {
+		log := types.CodyPromptLog{}
+		if err := s.scanCodyPromptLog(&log, rows); err != nil {
+			return nil, 0, err
+		}
+		logs = append(logs, &log)
+	}
+
+	var next int64
+	if opts.Limit != 0 && len(logs) == opts.Limit+1 {
+		next = logs[len(logs)-1].ID
+		logs = logs[:len(logs)-1]
+	}
+
+	return logs, next, nil
+}
+
+// DeleteStale deletes the records older than the retention.
+func (s *codyPromptLogStore) DeleteStale(ctx context.Context, retention time.Duration) error {
+	return s.Exec(ctx, sqlf.Sprintf(codyPromptLogDeleteStaleQueryFmtstr, timeutil.Now().Add(-retention)))
+}
+
+var codyPromptLogColumns = []*sqlf.Query{
+	sqlf.Sprintf("id"),
+	sqlf.Sprintf("user_id"),
+	sqlf.Sprintf("anonymous_user_id"),
+	sqlf.Sprintf("intent"),
+	sqlf.Sprintf("provider"),
+	sqlf.Sprintf("model"),
+	sqlf.Sprintf("prompt"),
+	sqlf.Sprintf("response"),
+	sqlf.Sprintf("encryption_key_id"),
+	sqlf.Sprintf("created_at"),
+}
+
+const codyPromptLogCreateQueryFmtstr = `
+INSERT INTO
+	cody_prompt_logs (
+		user_id,
+		anonymous_user_id,
+		intent,
+		provider,
+		model,
+		prompt,
+		response,
+		encryption_key_id,
+		created_at
+	)
+	VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s)
+	RETURNING %s
+`
+
+const codyPromptLogListQueryFmtstr = `
+SELECT
+	%s
+FROM
+	cody_prompt_logs
+WHERE
+	%s
+ORDER BY
+	id DESC
+%s -- LIMIT
+`
+
+const codyPromptLogDeleteStaleQueryFmtstr = `
+DELETE FROM
+	cody_prompt_logs
+WHERE
+	created_at <= %s
+`
+
+func (s *codyPromptLogStore) scanCodyPromptLog(log *types.CodyPromptLog, sc dbutil.Scanner) error {
+	var prompt, response, keyID string
+
+	if err := sc.Scan(
+		&log.ID,
+		&dbutil.NullInt32{N: &log.UserID},
+		&dbutil.NullString{S: &log.AnonymousUserID},
+		&log.Intent,
+		&log.Provider,
+		&log.Model,
+		&prompt,
+		&response,
+		&keyID,
+		&log.CreatedAt,
+	); err != nil {
+		return err
+	}
+
+	log.Prompt = types.NewEncryptedCodyPrompt(prompt, keyID, s.key)
+	log.Response = encryption.NewEncrypted(response, keyID, s.key)
+	return nil
+}
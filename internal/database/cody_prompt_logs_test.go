@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	et "github.com/sourcegraph/sourcegraph/internal/encryption/testing"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestCodyPromptLogStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+
+	user, err := db.Users().Create(ctx, NewUser{Username: "alice"})
+	require.NoError(t, err)
+
+	store := db.CodyPromptLogs(et.TestKey{})
+
+	newLog := func(userID int32, createdAt time.Time) *types.CodyPromptLog {
+		return &types.CodyPromptLog{
+			UserID:   userID,
+			Intent:   "chat",
+			Provider: "anthropic",
+			Model:    "claude-v1",
+			Prompt: types.NewUnencryptedCodyPrompt([]types.CodyPromptMessage{
+				{Speaker: "human", Text: "What does this code do?"},
+			}),
+			Response:  encryption.NewUnencrypted("It prints hello world."),
+			CreatedAt: createdAt,
+		}
+	}
+
+	now := time.Now()
+	stale := newLog(user.ID, now.Add(-48*time.Hour))
+	require.NoError(t, store.Create(ctx, stale))
+	fresh := newLog(0, now)
+	fresh.AnonymousUserID = "anonymous"
+	require.NoError(t, store.Create(ctx, fresh))
+	assert.NotZero(t, fresh.ID)
+
+	// Prompts and responses are stored encrypted.
+	var rawResponse, keyID string
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT response, encryption_key_id FROM cody_prompt_logs WHERE id = $1", fresh.ID).Scan(&rawResponse, &keyID))
+	assert.NotEqual(t, "It prints hello world.", rawResponse)
+	assert.NotEmpty(t, keyID)
+
+	t.Run("List", func(t *testing.T) {
+		logs, next, err := store.List(ctx, CodyPromptLogListOpts{Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Equal(t, fresh.ID, logs[0].ID)
+		assert.Equal(t, "anonymous", logs[0].AnonymousUserID)
+		assert.Equal(t, stale.ID, next)
+
+		prompt, err := logs[0].Prompt.Decrypt(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []types.CodyPromptMessage{{Speaker: "human", Text: "What does this code do?"}}, prompt)
+		response, err := logs[0].Response.Decrypt(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "It prints hello world.", response)
+
+		logs, next, err = store.List(ctx, CodyPromptLogListOpts{Limit: 1, Cursor: next})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Equal(t, stale.ID, logs[0].ID)
+		assert.Equal(t, user.ID, logs[0].UserID)
+		assert.Zero(t, next)
+
+		logs, _, err = store.List(ctx, CodyPromptLogListOpts{UserID: &user.ID})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Equal(t, stale.ID, logs[0].ID)
+
+		since := now.Add(-time.Hour)
+		logs, _, err = store.List(ctx, CodyPromptLogListOpts{Since: &since})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Equal(t, fresh.ID, logs[0].ID)
+	})
+
+	t.Run("DeleteStale", func(t *testing.T) {
+		require.NoError(t, store.DeleteStale(ctx, 24*time.Hour))
+
+		logs, _, err := store.List(ctx, CodyPromptLogListOpts{})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Equal(t, fresh.ID, logs[0].ID)
+	})
+}
@@ -24,10 +24,13 @@ type DB interface {
 
 	AccessRequests() AccessRequestStore
 	AccessTokens() AccessTokenStore
+	AccessTokenUsage() AccessTokenUsageStore
+	AuditLogEvents() AuditLogEventStore
 	Authz() AuthzStore
 	BitbucketProjectPermissions() BitbucketProjectPermissionsStore
 	CodeMonitors() CodeMonitorStore
 	Codeowners() CodeownersStore
+	CodyPromptLogs(encryption.Key) CodyPromptLogStore
 	Conf() ConfStore
 	EventLogs() EventLogStore
 	SecurityEventLogs() SecurityEventLogsStore
@@ -39,12 +42,14 @@ type DB interface {
 	GlobalState() GlobalStateStore
 	NamespacePermissions() NamespacePermissionStore
 	Namespaces() NamespaceStore
+	Notifications() NotificationStore
 	OrgInvitations() OrgInvitationStore
 	OrgMembers() OrgMemberStore
 	Orgs() OrgStore
 	OutboundWebhooks(encryption.Key) OutboundWebhookStore
 	OutboundWebhookJobs(encryption.Key) OutboundWebhookJobStore
 	OutboundWebhookLogs(encryption.Key) OutboundWebhookLogStore
+	OutboxEvents() OutboxEventStore
 	OwnershipStats() OwnershipStatsStore
 	RecentContributionSignals() RecentContributionSignalStore
 	Perms() PermsStore
@@ -53,12 +58,15 @@ type DB interface {
 	Phabricator() PhabricatorStore
 	RedisKeyValue() RedisKeyValueStore
 	Repos() RepoStore
+	RepoArchival() RepoArchivalStore
 	RepoCommitsChangelists() RepoCommitsChangelistsStore
 	RepoKVPs() RepoKVPStore
+	RepoLegalHolds() RepoLegalHoldStore
 	RepoPaths() RepoPathStore
 	RolePermissions() RolePermissionStore
 	Roles() RoleStore
 	SavedSearches() SavedSearchStore
+	SavedSearchSubscriptions() SavedSearchSubscriptionStore
 	SearchContexts() SearchContextsStore
 	Settings() SettingsStore
 	SubRepoPerms() SubRepoPermsStore
@@ -93,6 +101,15 @@ func NewDB(logger log.Logger, inner *sql.DB) DB {
 	return &db{logger: logger, Store: basestore.NewWithHandle(basestore.NewHandleWithDB(logger, inner, sql.TxOptions{}))}
 }
 
+// NewDBWithReplicas creates a new DB like NewDB whose stores may send read-only
+// queries to the given read replicas. If replicas is nil, all queries go to inner.
+func NewDBWithReplicas(logger log.Logger, inner *sql.DB, replicas *dbconn.Replicas) DB {
+	if replicas == nil {
+		return NewDB(logger, inner)
+	}
+	return &db{logger: logger, Store: basestore.NewWithHandle(basestore.NewHandleWithReplicas(logger, inner, replicas, sql.TxOptions{}))}
+}
+
 func NewDBWith(logger log.Logger, other basestore.ShareableStore) DB {
 	return &db{logger: logger, Store: basestore.NewWithHandle(other.Handle())}
 }
@@ -136,6 +153,14 @@ func (d *db) AccessTokens() AccessTokenStore {
 	return AccessTokensWith(d.Store, d.logger.Scoped("AccessTokenStore", ""))
 }
 
+func (d *db) AccessTokenUsage() AccessTokenUsageStore {
+	return AccessTokenUsageWith(d.Store)
+}
+
+func (d *db) AuditLogEvents() AuditLogEventStore {
+	return AuditLogEventsWith(d.Store)
+}
+
 func (d *db) AccessRequests() AccessRequestStore {
 	return AccessRequestsWith(d.Store, d.logger.Scoped("AccessRequestStore", ""))
 }
@@ -156,6 +181,10 @@ func (d *db) Codeowners() CodeownersStore {
 	return CodeownersWith(basestore.NewWithHandle(d.Handle()))
 }
 
+func (d *db) CodyPromptLogs(key encryption.Key) CodyPromptLogStore {
+	return CodyPromptLogsWith(d.Store, key)
+}
+
 func (d *db) Conf() ConfStore {
 	return &confStore{
 		Store:  basestore.NewWithHandle(d.Handle()),
@@ -203,6 +232,10 @@ func (d *db) Namespaces() NamespaceStore {
 	return NamespacesWith(d.Store)
 }
 
+func (d *db) Notifications() NotificationStore {
+	return NotificationsWith(d.Store)
+}
+
 func (d *db) OrgInvitations() OrgInvitationStore {
 	return OrgInvitationsWith(d.Store)
 }
@@ -227,6 +260,10 @@ func (d *db) OutboundWebhookLogs(key encryption.Key) OutboundWebhookLogStore {
 	return OutboundWebhookLogsWith(d.Store, key)
 }
 
+func (d *db) OutboxEvents() OutboxEventStore {
+	return OutboxEventsWith(d.Store)
+}
+
 func (d *db) OwnershipStats() OwnershipStatsStore {
 	return &ownershipStats{d.Store}
 }
@@ -263,10 +300,18 @@ func (d *db) RepoCommitsChangelists() RepoCommitsChangelistsStore {
 	return RepoCommitsChangelistsWith(d.logger, d.Store)
 }
 
+func (d *db) RepoArchival() RepoArchivalStore {
+	return RepoArchivalWith(d.Store)
+}
+
 func (d *db) RepoKVPs() RepoKVPStore {
 	return &repoKVPStore{d.Store}
 }
 
+func (d *db) RepoLegalHolds() RepoLegalHoldStore {
+	return RepoLegalHoldsWith(d.Store)
+}
+
 func (d *db) RepoPaths() RepoPathStore {
 	return &repoPathStore{d.Store}
 }
@@ -283,6 +328,10 @@ func (d *db) SavedSearches() SavedSearchStore {
 	return SavedSearchesWith(d.Store)
 }
 
+func (d *db) SavedSearchSubscriptions() SavedSearchSubscriptionStore {
+	return SavedSearchSubscriptionsWith(d.Store)
+}
+
 func (d *db) SearchContexts() SearchContextsStore {
 	return SearchContextsWith(d.logger, d.Store)
 }
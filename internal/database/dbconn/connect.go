@@ -61,5 +61,9 @@ func ConnectInternal(logger log.Logger, dsn, appName, dbName string) (_ *sql.DB,
 		}
 	}
 
+	if failoverCheckInterval > 0 {
+		manager.getFailoverState(cfg).watch(logger, db, failoverCheckInterval)
+	}
+
 	return db, nil
 }
@@ -1,6 +1,7 @@
 package dbconn
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/jackc/pgx/v4"
@@ -9,6 +10,7 @@ import (
 
 var manager = &dbconnManager{
 	registeredConfig: make(map[string]*pgx.ConnConfig),
+	failoverStates:   make(map[string]*failoverState),
 }
 
 // dbconnManager is a global singleton that manages data source registration
@@ -24,6 +26,9 @@ type dbconnManager struct {
 	// registeredConnConfig0 -> ConnConfig{}
 	// registeredConnConfig1 -> ConnConfig{}
 	registeredConfig map[string]*pgx.ConnConfig
+	// failoverStates is a map of databases, identified by their host, port and name, to the
+	// failover state shared by all connections to that database.
+	failoverStates map[string]*failoverState
 }
 
 // registerConfig is a wrapper around stdlib.RegisterConnConfig.
@@ -45,3 +50,18 @@ func (m *dbconnManager) getConfig(name string) *pgx.ConnConfig {
 	}
 	return nil
 }
+
+// getFailoverState returns the failover state of the database connected to with the given
+// config. Configs of the same data source share the state, regardless of their other
+// connection parameters such as the application name.
+func (m *dbconnManager) getFailoverState(cfg *pgx.ConnConfig) *failoverState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := fmt.Sprintf("%s:%d/%s", cfg.Host, cfg.Port, cfg.Database)
+	state, ok := m.failoverStates[key]
+	if !ok {
+		state = &failoverState{}
+		m.failoverStates[key] = state
+	}
+	return state
+}
@@ -0,0 +1,239 @@
+package dbconn
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var failoverCheckInterval = env.MustGetDuration("SRC_PGSQL_FAILOVER_CHECK_INTERVAL", 5*time.Second, "Interval at which the primary database is checked for having been demoted by a failover. Set to 0 to disable the check.")
+
+// failoverDebounce is the minimum time between two detected failovers of the same database.
+// Connections that fail concurrently because of a single failover are attributed to it.
+const failoverDebounce = time.Second
+
+var (
+	failoverEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_pgsql_failover_events_total",
+		Help: "The number of detected failovers of the primary database, by the signal that detected them.",
+	}, []string{"reason"})
+	failoverReadRetries = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_pgsql_failover_read_retries_total",
+		Help: "The number of read-only queries retried on a new connection after a failover.",
+	})
+	failoverTransactionAborts = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_pgsql_failover_transaction_aborts_total",
+		Help: "The number of in-flight transactions that failed because of a failover.",
+	})
+)
+
+// FailoverError is returned for queries that failed because the primary database became
+// unavailable or was demoted by a failover. Only queries run with WithFailoverRetries are
+// retried automatically, as others may or may not have been applied before the failure.
+type FailoverError struct {
+	// InTransaction is true if the query was part of a transaction. The transaction has
+	// been aborted and must be retried from the beginning by the caller.
+	InTransaction bool
+	Err           error
+}
+
+func (e *FailoverError) Error() string {
+	if e.InTransaction {
+		return "transaction aborted by database failover: " + e.Err.Error()
+	}
+	return "query failed during database failover: " + e.Err.Error()
+}
+
+func (e *FailoverError) Unwrap() error {
+	return e.Err
+}
+
+// IsFailover returns true if the given error was caused by a failover of the primary database.
+func IsFailover(err error) bool {
+	var failoverErr *FailoverError
+	return errors.As(err, &failoverErr)
+}
+
+type retryReadsKey struct{}
+
+// WithFailoverRetries returns a context in which queries are retried on a new connection
+// if they fail because of a failover. Callers opt in only for queries that are read-only,
+// i.e. plain SELECTs without locking clauses or side effects, which are safe to run again.
+// Queries within a transaction are never retried.
+func WithFailoverRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryReadsKey{}, true)
+}
+
+func failoverRetriesAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(retryReadsKey{}).(bool)
+	return allowed
+}
+
+// failoverState tracks failovers of a single database. Each detected failover starts a new
+// generation: connections opened in an earlier generation are discarded by the pool instead of
+// being reused, so that new connections re-resolve the host of the database and connect to the
+// new primary.
+type failoverState struct {
+	generation   atomic.Uint64
+	lastFailover atomic.Int64 // unix nanoseconds
+
+	mu sync.Mutex
+	// handles are the open handles to the database monitored for being demoted. The monitor
+	// checks the database through the first one.
+	handles []*sql.DB
+	// stop stops the running monitor, or is nil if no monitor is running.
+	stop chan struct{}
+}
+
+// currentGeneration returns the generation new connections are opened in.
+func (s *failoverState) currentGeneration() uint64 {
+	return s.generation.Load()
+}
+
+// detected records a failover detected via the given signal and starts a new generation,
+// unless a failover was already recorded within failoverDebounce.
+func (s *failoverState) detected(reason string) {
+	now := time.Now().UnixNano()
+	last := s.lastFailover.Load()
+	if now-last < int64(failoverDebounce) || !s.lastFailover.CompareAndSwap(last, now) {
+		return
+	}
+
+	s.generation.Add(1)
+	failoverEvents.WithLabelValues(reason).Inc()
+	log.Scoped("failover", "Postgres failover detection").Warn("database failover detected, recycling connections", log.String("reason", reason))
+}
+
+// watch monitors the database for being demoted through the given handle. A single monitor
+// runs per database, however many handles are opened to it, until all of them are closed.
+func (s *failoverState) watch(logger log.Logger, db *sql.DB, interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handles = append(s.handles, db)
+	if s.stop == nil {
+		s.stop = make(chan struct{})
+		go s.monitor(logger, interval, s.stop)
+	}
+}
+
+// unwatch is called when the given handle is closed. It stops the monitor once no watched
+// handle is left.
+func (s *failoverState) unwatch(db *sql.DB) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, handle := range s.handles {
+		if handle == db {
+			s.handles = append(s.handles[:i], s.handles[i+1:]...)
+			break
+		}
+	}
+	if len(s.handles) == 0 && s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+// handle returns the handle the monitor checks the database through.
+func (s *failoverState) handle() *sql.DB {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.handles) == 0 {
+		return nil
+	}
+	return s.handles[0]
+}
+
+// monitor periodically checks whether the database has been demoted to a replica, which
+// happens when a failover promotes another server while existing connections are still
+// routed to the previous primary. Connection failures are detected by the queries themselves.
+// The monitor exits once stop is closed.
+func (s *failoverState) monitor(logger log.Logger, interval time.Duration, stop <-chan struct{}) {
+	logger = logger.Scoped("failover", "Postgres failover detection")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		db := s.handle()
+		if db == nil {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		var inRecovery bool
+		err := db.QueryRowContext(ctx, "SELECT pg_is_in_recovery()").Scan(&inRecovery)
+		cancel()
+
+		if err != nil {
+			select {
+			case <-stop:
+				// The handle was closed during the check.
+				return
+			default:
+			}
+			logger.Warn("failed to check database role", log.Error(err))
+			continue
+		}
+		if inRecovery {
+			s.detected("demoted")
+		}
+	}
+}
+
+// failoverReason returns the signal of a failover if the given error indicates that the
+// server is shutting down, was demoted to a read-only replica, or that the connection to it
+// was lost.
+func failoverReason(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgerrcode.ReadOnlySQLTransaction:
+			return "read_only", true
+		case pgerrcode.AdminShutdown, pgerrcode.CrashShutdown, pgerrcode.CannotConnectNow:
+			return "shutdown", true
+		}
+		return "", false
+	}
+
+	// A closed connection is reported by the driver with driver.ErrBadConn before anything
+	// is sent to the server, which database/sql already retries.
+	if errors.Is(err, driver.ErrBadConn) {
+		return "", false
+	}
+
+	var netErr net.Error
+	if errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		(errors.As(err, &netErr) && !netErr.Timeout()) {
+		return "connection", true
+	}
+
+	return "", false
+}
@@ -0,0 +1,152 @@
+package dbconn
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestFailoverReason(t *testing.T) {
+	testCases := []struct {
+		name   string
+		err    error
+		reason string
+	}{
+		{"read only", &pgconn.PgError{Code: pgerrcode.ReadOnlySQLTransaction}, "read_only"},
+		{"admin shutdown", errors.Wrap(&pgconn.PgError{Code: pgerrcode.AdminShutdown}, "query"), "shutdown"},
+		{"connection reset", errors.Wrap(syscall.ECONNRESET, "read"), "connection"},
+		{"unexpected EOF", io.ErrUnexpectedEOF, "connection"},
+		{"unique violation", &pgconn.PgError{Code: pgerrcode.UniqueViolation}, ""},
+		{"bad conn", driver.ErrBadConn, ""},
+		{"other", errors.New("oops"), ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			reason, ok := failoverReason(testCase.err)
+			if reason != testCase.reason || ok != (testCase.reason != "") {
+				t.Errorf("unexpected reason. want=%q have=%q (%v)", testCase.reason, reason, ok)
+			}
+		})
+	}
+}
+
+func TestWithFailoverRetries(t *testing.T) {
+	if failoverRetriesAllowed(context.Background()) {
+		t.Fatal("expected queries not to be retried by default")
+	}
+	if !failoverRetriesAllowed(WithFailoverRetries(context.Background())) {
+		t.Fatal("expected queries to be retried after opting in")
+	}
+}
+
+func TestFailoverMonitor(t *testing.T) {
+	state := &failoverState{}
+	open := func() *sql.DB {
+		connector := &proxyConnector{failover: state}
+		db := sql.OpenDB(connector)
+		connector.db = db
+		return db
+	}
+	logger := logtest.Scoped(t)
+
+	db1, db2 := open(), open()
+	state.watch(logger, db1, time.Hour)
+	stop := state.stop
+	state.watch(logger, db2, time.Hour)
+	if state.stop != stop {
+		t.Fatal("expected a single monitor per database")
+	}
+
+	if err := db1.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if state.stop == nil || state.handle() != db2 {
+		t.Fatal("expected the monitor to continue with the remaining handle")
+	}
+
+	if err := db2.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if state.stop != nil {
+		t.Fatal("expected the monitor to be stopped")
+	}
+	select {
+	case <-stop:
+	default:
+		t.Fatal("expected the stop channel to be closed")
+	}
+}
+
+func TestHandleFailover(t *testing.T) {
+	resetErr := errors.Wrap(syscall.ECONNRESET, "read")
+
+	t.Run("read-only query is retried", func(t *testing.T) {
+		conn := &extendedConn{failover: &failoverState{}}
+		if err := conn.handleFailover(resetErr, true); err != driver.ErrBadConn {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if conn.IsValid() {
+			t.Fatal("expected connection to be invalid")
+		}
+		if conn.failover.currentGeneration() != 1 {
+			t.Fatalf("expected a new generation, have %d", conn.failover.currentGeneration())
+		}
+	})
+
+	t.Run("write fails with typed error", func(t *testing.T) {
+		conn := &extendedConn{failover: &failoverState{}}
+		err := conn.handleFailover(resetErr, false)
+		var failoverErr *FailoverError
+		if !errors.As(err, &failoverErr) || failoverErr.InTransaction {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !errors.Is(err, syscall.ECONNRESET) {
+			t.Fatal("expected error to wrap the connection error")
+		}
+	})
+
+	t.Run("transaction is aborted", func(t *testing.T) {
+		conn := &extendedConn{failover: &failoverState{}, inTx: true}
+		err := conn.handleFailover(resetErr, true)
+		var failoverErr *FailoverError
+		if !errors.As(err, &failoverErr) || !failoverErr.InTransaction {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("other errors are unchanged", func(t *testing.T) {
+		conn := &extendedConn{failover: &failoverState{}}
+		err := &pgconn.PgError{Code: pgerrcode.UniqueViolation}
+		if have := conn.handleFailover(err, true); have != err {
+			t.Fatalf("unexpected error: %v", have)
+		}
+		if !conn.IsValid() {
+			t.Fatal("expected connection to be valid")
+		}
+	})
+
+	t.Run("stale connections are invalid", func(t *testing.T) {
+		state := &failoverState{}
+		stale := &extendedConn{failover: state, generation: state.currentGeneration()}
+
+		state.detected("demoted")
+		state.detected("demoted") // debounced
+		if state.currentGeneration() != 1 {
+			t.Fatalf("expected a single new generation, have %d", state.currentGeneration())
+		}
+		if stale.IsValid() {
+			t.Fatal("expected stale connection to be invalid")
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
+	"io"
 	"log"
 	"strconv"
 	"strings"
@@ -128,11 +129,19 @@ type extendedConn struct {
 
 	execerContext  driver.ExecerContext
 	queryerContext driver.QueryerContext
+
+	// failover is the failover state of the database, and generation the failover generation
+	// the connection was opened in. The connection is discarded once it is stale or bad.
+	failover   *failoverState
+	generation uint64
+	bad        bool
+	inTx       bool
 }
 
 var _ driver.Pinger = &extendedConn{}
 var _ driver.SessionResetter = &extendedConn{}
 var _ driver.NamedValueChecker = &extendedConn{}
+var _ driver.Validator = &extendedConn{}
 
 // Open returns a conn wrapped through extendedConn, implementing the
 // Ping, ResetSession and CheckNamedValue optional methods that the
@@ -142,18 +151,17 @@ func (d *extendedDriver) Open(str string) (driver.Conn, error) {
 		return nil, errors.New("sql driver is not a sqlhooks.Driver")
 	}
 
-	if pgConnectionUpdater != "" {
-		// Driver.Open() is called during after we first attempt to connect to the database
-		// during startup time in `dbconn.open()`, where the manager will persist the config internally,
-		// and also call the underlying pgx RegisterConnConfig() to register the config to pgx driver.
-		// Therefore, this should never be nil.
-		//
-		// We do not need this code path unless connection updater is enabled.
-		cfg := manager.getConfig(str)
-		if cfg == nil {
-			return nil, errors.Newf("no config found %q", str)
-		}
+	// Driver.Open() is called during after we first attempt to connect to the database
+	// during startup time in `dbconn.open()`, where the manager will persist the config internally,
+	// and also call the underlying pgx RegisterConnConfig() to register the config to pgx driver.
+	// Therefore, this should never be nil.
+	cfg := manager.getConfig(str)
+	if cfg == nil {
+		return nil, errors.Newf("no config found %q", str)
+	}
+	failover := manager.getFailoverState(cfg)
 
+	if pgConnectionUpdater != "" {
 		u, ok := connectionUpdaters[pgConnectionUpdater]
 		if !ok {
 			return nil, errors.Errorf("unknown connection updater %q", pgConnectionUpdater)
@@ -196,6 +204,8 @@ func (d *extendedDriver) Open(str string) (driver.Conn, error) {
 		ConnBeginTx:        c.(any).(driver.ConnBeginTx),
 		execerContext:      c.(any).(driver.ExecerContext),
 		queryerContext:     c.(any).(driver.QueryerContext),
+		failover:           failover,
+		generation:         failover.currentGeneration(),
 	}, nil
 }
 
@@ -211,24 +221,94 @@ func (n *extendedConn) Ping(ctx context.Context) error {
 }
 
 func (n *extendedConn) ResetSession(ctx context.Context) error {
+	if !n.IsValid() {
+		return driver.ErrBadConn
+	}
 	return n.rawConn().(driver.SessionResetter).ResetSession(ctx)
 }
 
+// IsValid returns false if the connection failed because of a failover, or if it was opened
+// before the latest detected failover and may still be connected to the previous primary.
+func (n *extendedConn) IsValid() bool {
+	return !n.bad && n.generation == n.failover.currentGeneration()
+}
+
 func (n *extendedConn) CheckNamedValue(namedValue *driver.NamedValue) error {
 	return n.rawConn().(driver.NamedValueChecker).CheckNamedValue(namedValue)
 }
 
 func (n *extendedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
 	ctx, query = instrumentQuery(ctx, query, len(args))
-	return n.execerContext.ExecContext(ctx, query, args)
+	result, err := n.execerContext.ExecContext(ctx, query, args)
+	return result, n.handleFailover(err, false)
 }
 
 func (n *extendedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	readOnly := failoverRetriesAllowed(ctx)
 	ctx, query = instrumentQuery(ctx, query, len(args))
-	return n.queryerContext.QueryContext(ctx, query, args)
+	rows, err := n.queryerContext.QueryContext(ctx, query, args)
+	return rows, n.handleFailover(err, readOnly)
 }
 
-func registerPostgresProxy() {
+func (n *extendedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	tx, err := n.ConnBeginTx.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, n.handleFailover(err, false)
+	}
+
+	n.inTx = true
+	return &extendedTx{Tx: tx, conn: n}, nil
+}
+
+// handleFailover marks the connection as bad if the given error was caused by a failover.
+// Queries that opted in with WithFailoverRetries outside of a transaction are retried by
+// database/sql on a new connection, which re-resolves the database host. Other queries fail
+// with a FailoverError.
+func (n *extendedConn) handleFailover(err error, readOnly bool) error {
+	if err == nil {
+		return nil
+	}
+	reason, ok := failoverReason(err)
+	if !ok {
+		return err
+	}
+
+	n.bad = true
+	n.failover.detected(reason)
+
+	if n.inTx {
+		failoverTransactionAborts.Inc()
+		return &FailoverError{InTransaction: true, Err: err}
+	}
+	if readOnly {
+		failoverReadRetries.Inc()
+		return driver.ErrBadConn
+	}
+	return &FailoverError{Err: err}
+}
+
+// extendedTx tracks the end of a transaction on an extendedConn.
+type extendedTx struct {
+	driver.Tx
+	conn *extendedConn
+}
+
+func (t *extendedTx) Commit() error {
+	err := t.conn.handleFailover(t.Tx.Commit(), false)
+	t.conn.inTx = false
+	return err
+}
+
+func (t *extendedTx) Rollback() error {
+	err := t.conn.handleFailover(t.Tx.Rollback(), false)
+	t.conn.inTx = false
+	return err
+}
+
+// proxyDriver is the instrumented driver that connections are opened with.
+var proxyDriver driver.Driver
+
+func initProxyDriver() {
 	m := promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "src_pgsql_request_total",
 		Help: "Total number of SQL requests to the database.",
@@ -240,21 +320,8 @@ func registerPostgresProxy() {
 			metricSQLErrorTotal:   m.WithLabelValues("error"),
 		},
 	))
-	sql.Register("postgres-proxy", &extendedDriver{dri})
-}
-
-var registerOnce sync.Once
-
-func open(cfg *pgx.ConnConfig) (*sql.DB, error) {
-	registerOnce.Do(registerPostgresProxy)
-	// this function is called once during startup time, and we register the db config
-	// to our own manager, and manager will also register the config to pgx driver by
-	// calling the underlying stdlib.RegisterConnConfig().
-	name := manager.registerConfig(cfg)
-
-	db, err := otelsql.Open(
-		"postgres-proxy",
-		name,
+	proxyDriver = otelsql.WrapDriver(
+		&extendedDriver{dri},
 		otelsql.WithTracerProvider(otel.GetTracerProvider()),
 		otelsql.WithSQLCommenter(true),
 		otelsql.WithSpanOptions(otelsql.SpanOptions{
@@ -265,9 +332,45 @@ func open(cfg *pgx.ConnConfig) (*sql.DB, error) {
 		}),
 		otelsql.WithAttributesGetter(argsAsAttributes),
 	)
-	if err != nil {
-		return nil, errors.Wrap(err, "postgresql open")
-	}
+}
+
+var initProxyDriverOnce sync.Once
+
+// proxyConnector opens connections to a registered config with the proxy driver. It is closed
+// by database/sql when the handle using it is closed, which stops monitoring the database for
+// failovers through that handle.
+type proxyConnector struct {
+	name     string
+	failover *failoverState
+	db       *sql.DB
+}
+
+var _ driver.Connector = &proxyConnector{}
+var _ io.Closer = &proxyConnector{}
+
+func (c *proxyConnector) Connect(context.Context) (driver.Conn, error) {
+	return proxyDriver.Open(c.name)
+}
+
+func (c *proxyConnector) Driver() driver.Driver {
+	return proxyDriver
+}
+
+func (c *proxyConnector) Close() error {
+	c.failover.unwatch(c.db)
+	return nil
+}
+
+func open(cfg *pgx.ConnConfig) (*sql.DB, error) {
+	initProxyDriverOnce.Do(initProxyDriver)
+	// this function is called once during startup time, and we register the db config
+	// to our own manager, and manager will also register the config to pgx driver by
+	// calling the underlying stdlib.RegisterConnConfig().
+	name := manager.registerConfig(cfg)
+
+	connector := &proxyConnector{name: name, failover: manager.getFailoverState(cfg)}
+	db := sql.OpenDB(connector)
+	connector.db = db
 
 	// Set max open and idle connections
 	maxOpen, _ := strconv.Atoi(cfg.RuntimeParams["max_conns"])
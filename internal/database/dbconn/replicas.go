@@ -0,0 +1,202 @@
+package dbconn
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var (
+	replicaDataSources = env.Get("SRC_PGSQL_REPLICA_DATASOURCES", "", "Comma-separated list of connection strings of Postgres read replicas of the frontend database. Read-only queries that tolerate stale data are routed to replicas when set.")
+	replicaMaxLag      = env.MustGetDuration("SRC_PGSQL_REPLICA_MAX_LAG", 5*time.Second, "Maximum replication lag of a read replica before reads fall back to the primary.")
+)
+
+// replicaLagCheckInterval is how often the replication lag of each replica is
+// refreshed.
+const replicaLagCheckInterval = 5 * time.Second
+
+var (
+	replicaLagSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "src_pgsql_replica_lag_seconds",
+		Help: "The last observed replication lag of each Postgres read replica.",
+	}, []string{"replica"})
+	replicaFallbacks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_pgsql_replica_fallbacks_total",
+		Help: "The number of reads sent to the primary because no replica was available.",
+	}, []string{"reason"})
+)
+
+// Replicas is a set of Postgres read replicas. Replicas are only picked while
+// they are reachable and their replication lag is within the configured
+// staleness bound, so that callers can fall back to the primary otherwise.
+type Replicas struct {
+	logger   log.Logger
+	replicas []*replica
+	maxLag   time.Duration
+	next     atomic.Uint64
+
+	mu          sync.Mutex
+	lastChecked time.Time
+	checking    bool
+}
+
+type replica struct {
+	name    string
+	db      *sql.DB
+	lag     atomic.Int64 // nanoseconds
+	healthy atomic.Bool
+}
+
+// ConnectReplicas connects to the read replicas configured with
+// SRC_PGSQL_REPLICA_DATASOURCES. It returns nil if no replica is configured.
+func ConnectReplicas(logger log.Logger, appName string) (_ *Replicas, err error) {
+	if replicaDataSources == "" {
+		return nil, nil
+	}
+
+	var dbs []*sql.DB
+	defer func() {
+		if err != nil {
+			for _, db := range dbs {
+				err = errors.Append(err, db.Close())
+			}
+		}
+	}()
+
+	for _, dsn := range strings.Split(replicaDataSources, ",") {
+		dsn = strings.TrimSpace(dsn)
+		if dsn == "" {
+			continue
+		}
+		cfg, err := buildConfig(logger, dsn, appName)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid replica connection string")
+		}
+		db, err := newWithConfig(cfg)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to connect to replica")
+		}
+		dbs = append(dbs, db)
+	}
+
+	return NewReplicas(logger, dbs, replicaMaxLag), nil
+}
+
+// NewReplicas returns a replica set over the given connections. Replicas are
+// not picked until a lag check succeeded, so reads go to the primary until the
+// first check completes.
+func NewReplicas(logger log.Logger, dbs []*sql.DB, maxLag time.Duration) *Replicas {
+	r := &Replicas{
+		logger: logger.Scoped("replicas", "Postgres read replicas"),
+		maxLag: maxLag,
+	}
+	for i, db := range dbs {
+		r.replicas = append(r.replicas, &replica{name: strconv.Itoa(i), db: db})
+	}
+	return r
+}
+
+// Pick returns a replica that is healthy and within the staleness bound, in
+// round-robin order. It returns nil if reads must go to the primary.
+func (r *Replicas) Pick() *sql.DB {
+	if r == nil || len(r.replicas) == 0 {
+		return nil
+	}
+	r.maybeRefresh()
+
+	start := r.next.Add(1)
+	for i := 0; i < len(r.replicas); i++ {
+		rep := r.replicas[(start+uint64(i))%uint64(len(r.replicas))]
+		if rep.healthy.Load() && time.Duration(rep.lag.Load()) <= r.maxLag {
+			return rep.db
+		}
+	}
+
+	replicaFallbacks.WithLabelValues("unavailable").Inc()
+	return nil
+}
+
+// MarkUnhealthy excludes the given replica until its next successful lag
+// check. It is called when a query to the replica fails to connect.
+func (r *Replicas) MarkUnhealthy(db *sql.DB) {
+	if r == nil {
+		return
+	}
+	for _, rep := range r.replicas {
+		if rep.db == db {
+			rep.healthy.Store(false)
+			replicaFallbacks.WithLabelValues("error").Inc()
+		}
+	}
+}
+
+// Close closes the connections to all replicas.
+func (r *Replicas) Close() (err error) {
+	if r == nil {
+		return nil
+	}
+	for _, rep := range r.replicas {
+		err = errors.Append(err, rep.db.Close())
+	}
+	return err
+}
+
+// maybeRefresh refreshes the replication lag of all replicas in the
+// background if it was last checked more than replicaLagCheckInterval ago.
+// Reads are never blocked on lag checks.
+func (r *Replicas) maybeRefresh() {
+	r.mu.Lock()
+	if r.checking || time.Since(r.lastChecked) < replicaLagCheckInterval {
+		r.mu.Unlock()
+		return
+	}
+	r.checking = true
+	r.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), replicaLagCheckInterval)
+		defer cancel()
+		r.refresh(ctx)
+
+		r.mu.Lock()
+		r.checking = false
+		r.lastChecked = time.Now()
+		r.mu.Unlock()
+	}()
+}
+
+// replicaLagQuery returns zero when the replica has replayed all of the WAL
+// it received, since pg_last_xact_replay_timestamp keeps growing on replicas
+// of an idle primary.
+const replicaLagQuery = `
+SELECT CASE
+	WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END
+`
+
+func (r *Replicas) refresh(ctx context.Context) {
+	for _, rep := range r.replicas {
+		var seconds float64
+		if err := rep.db.QueryRowContext(ctx, replicaLagQuery).Scan(&seconds); err != nil {
+			r.logger.Warn("failed to check replica lag", log.String("replica", rep.name), log.Error(err))
+			rep.healthy.Store(false)
+			continue
+		}
+
+		replicaLagSeconds.WithLabelValues(rep.name).Set(seconds)
+		rep.lag.Store(int64(seconds * float64(time.Second)))
+		rep.healthy.Store(true)
+	}
+}
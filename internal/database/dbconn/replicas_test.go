@@ -0,0 +1,42 @@
+package dbconn
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+)
+
+func TestReplicasPick(t *testing.T) {
+	db, err := sql.Open("pgx", "postgres://127.0.0.1:1/replica")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	r := NewReplicas(logtest.Scoped(t), []*sql.DB{db}, time.Second)
+	// Keep the lag checks from running in the background.
+	r.lastChecked = time.Now().Add(time.Hour)
+
+	if got := r.Pick(); got != nil {
+		t.Fatal("expected replicas not to be picked before their lag was checked")
+	}
+
+	rep := r.replicas[0]
+	rep.healthy.Store(true)
+	if got := r.Pick(); got != db {
+		t.Fatal("expected a healthy replica to be picked")
+	}
+
+	rep.lag.Store(int64(2 * time.Second))
+	if got := r.Pick(); got != nil {
+		t.Fatal("expected a lagging replica not to be picked")
+	}
+
+	rep.lag.Store(0)
+	r.MarkUnhealthy(db)
+	if got := r.Pick(); got != nil {
+		t.Fatal("expected an unhealthy replica not to be picked")
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 type RecordEncrypter struct {
@@ -23,15 +24,17 @@ func NewRecordEncrypter(db DB) *RecordEncrypter {
 func (s *RecordEncrypter) Count(ctx context.Context, config EncryptionConfig) (numEncrypted int, numUnencrypted int, _ error) {
 	countQuery := sqlf.Sprintf(`
 		SELECT
-			(SELECT COUNT(*) FROM %s WHERE %s NOT IN ('', %s)) AS   encrypted,
-			(SELECT COUNT(*) FROM %s WHERE %s     IN ('', %s)) AS unencrypted
+			(SELECT COUNT(*) FROM %s WHERE %s NOT IN ('', %s) AND %s) AS   encrypted,
+			(SELECT COUNT(*) FROM %s WHERE %s     IN ('', %s) AND %s) AS unencrypted
 		`,
 		quote(config.TableName),
-		quote(config.KeyIDFieldName),
+		keyIDField(config),
 		encryption.UnmigratedEncryptionKeyID,
+		presentCondition(config),
 		quote(config.TableName),
-		quote(config.KeyIDFieldName),
+		keyIDField(config),
 		encryption.UnmigratedEncryptionKeyID,
+		presentCondition(config),
 	)
 	if err := s.QueryRow(ctx, countQuery).Scan(&numEncrypted, &numUnencrypted); err != nil {
 		return 0, 0, err
@@ -40,6 +43,54 @@ func (s *RecordEncrypter) Count(ctx context.Context, config EncryptionConfig) (n
 	return numEncrypted, numUnencrypted, nil
 }
 
+// EncryptionProgress describes how the records of an encrypted table are
+// stored relative to the currently configured key.
+type EncryptionProgress struct {
+	TableName string
+	// Unencrypted is the number of records stored in plaintext.
+	Unencrypted int
+	// Outdated is the number of records encrypted with a key version other
+	// than the current one, which are re-encrypted by RotateBatch.
+	Outdated int
+	// Current is the number of records encrypted with the current key version.
+	Current int
+}
+
+// Progress counts the records of the given table by how they are encrypted. If
+// no key is configured, all encrypted records are counted as outdated.
+func (s *RecordEncrypter) Progress(ctx context.Context, config EncryptionConfig) (EncryptionProgress, error) {
+	currentKeyID, err := currentKeyID(ctx, config)
+	if err != nil {
+		return EncryptionProgress{}, err
+	}
+
+	progress := EncryptionProgress{TableName: config.TableName}
+	if err := s.QueryRow(ctx, sqlf.Sprintf(`
+		SELECT
+			COUNT(*) FILTER (WHERE %s IN ('', %s)),
+			COUNT(*) FILTER (WHERE %s NOT IN ('', %s, %s)),
+			COUNT(*) FILTER (WHERE %s NOT IN ('', %s) AND %s = %s)
+		FROM %s
+		WHERE %s
+		`,
+		keyIDField(config),
+		encryption.UnmigratedEncryptionKeyID,
+		keyIDField(config),
+		encryption.UnmigratedEncryptionKeyID,
+		currentKeyID,
+		keyIDField(config),
+		encryption.UnmigratedEncryptionKeyID,
+		keyIDField(config),
+		currentKeyID,
+		quote(config.TableName),
+		presentCondition(config),
+	)).Scan(&progress.Unencrypted, &progress.Outdated, &progress.Current); err != nil {
+		return EncryptionProgress{}, err
+	}
+
+	return progress, nil
+}
+
 func (s *RecordEncrypter) EncryptBatch(ctx context.Context, config EncryptionConfig) (count int, err error) {
 	key := config.Key()
 	if key == nil {
@@ -53,11 +104,12 @@ func (s *RecordEncrypter) EncryptBatch(ctx context.Context, config EncryptionCon
 	defer func() { err = tx.Done(err) }()
 
 	values, err := config.Scan(tx.Query(ctx, sqlf.Sprintf(
-		"SELECT %s FROM %s WHERE %s IN ('', %s) ORDER BY %s ASC LIMIT %s FOR UPDATE SKIP LOCKED",
+		"SELECT %s FROM %s WHERE %s IN ('', %s) AND %s ORDER BY %s ASC LIMIT %s FOR UPDATE SKIP LOCKED",
 		fields(config),
 		quote(config.TableName),
-		quote(config.KeyIDFieldName),
+		keyIDField(config),
 		encryption.UnmigratedEncryptionKeyID,
+		presentCondition(config),
 		quote(config.IDFieldName),
 		config.Limit,
 	)))
@@ -74,16 +126,58 @@ func (s *RecordEncrypter) EncryptBatch(ctx context.Context, config EncryptionCon
 		return 0, err
 	}
 
-	for id, ev := range encryptedValues {
-		if err := tx.Exec(ctx, sqlf.Sprintf(
-			"UPDATE %s SET %s WHERE %s = %s",
-			quote(config.TableName),
-			updatePairs(config, ev),
-			quote(config.IDFieldName),
-			id,
-		)); err != nil {
-			return 0, err
-		}
+	if err := updateValues(ctx, tx, config, encryptedValues); err != nil {
+		return 0, err
+	}
+
+	return len(encryptedValues), nil
+}
+
+// RotateBatch re-encrypts a batch of records that were encrypted with a
+// previous version of the configured key with its current version. The key
+// must still be able to decrypt values encrypted with its previous versions.
+func (s *RecordEncrypter) RotateBatch(ctx context.Context, config EncryptionConfig) (count int, err error) {
+	key := config.Key()
+	if key == nil {
+		return 0, nil
+	}
+	currentKeyID, err := currentKeyID(ctx, config)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	values, err := config.Scan(tx.Query(ctx, sqlf.Sprintf(
+		"SELECT %s FROM %s WHERE %s NOT IN ('', %s, %s) AND %s ORDER BY %s ASC LIMIT %s FOR UPDATE SKIP LOCKED",
+		fields(config),
+		quote(config.TableName),
+		keyIDField(config),
+		encryption.UnmigratedEncryptionKeyID,
+		currentKeyID,
+		presentCondition(config),
+		quote(config.IDFieldName),
+		config.Limit,
+	)))
+	if err != nil {
+		return 0, err
+	}
+
+	decryptedValues, err := decryptValues(ctx, key, values)
+	if err != nil {
+		return 0, err
+	}
+	encryptedValues, err := encryptValues(ctx, key, decryptedValues)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := updateValues(ctx, tx, config, encryptedValues); err != nil {
+		return 0, err
 	}
 
 	return len(encryptedValues), nil
@@ -97,11 +191,12 @@ func (s *RecordEncrypter) DecryptBatch(ctx context.Context, config EncryptionCon
 	defer func() { err = tx.Done(err) }()
 
 	values, err := config.Scan(tx.Query(ctx, sqlf.Sprintf(
-		"SELECT %s FROM %s WHERE %s NOT IN ('', %s) ORDER BY %s ASC LIMIT %s FOR UPDATE SKIP LOCKED",
+		"SELECT %s FROM %s WHERE %s NOT IN ('', %s) AND %s ORDER BY %s ASC LIMIT %s FOR UPDATE SKIP LOCKED",
 		fields(config),
 		quote(config.TableName),
-		quote(config.KeyIDFieldName),
+		keyIDField(config),
 		encryption.UnmigratedEncryptionKeyID,
+		presentCondition(config),
 		quote(config.IDFieldName),
 		config.Limit,
 	)))
@@ -114,19 +209,65 @@ func (s *RecordEncrypter) DecryptBatch(ctx context.Context, config EncryptionCon
 		return 0, err
 	}
 
+	plaintextValues := make(map[int]Encrypted, len(decryptedValues))
 	for id, vs := range decryptedValues {
+		plaintextValues[id] = Encrypted{Values: vs}
+	}
+	if err := updateValues(ctx, tx, config, plaintextValues); err != nil {
+		return 0, err
+	}
+
+	return len(decryptedValues), nil
+}
+
+func updateValues(ctx context.Context, tx *basestore.Store, config EncryptionConfig, values map[int]Encrypted) error {
+	for id, ev := range values {
 		if err := tx.Exec(ctx, sqlf.Sprintf(
 			"UPDATE %s SET %s WHERE %s = %s",
 			quote(config.TableName),
-			updatePairs(config, Encrypted{Values: vs}),
+			updatePairs(config, ev),
 			quote(config.IDFieldName),
 			id,
 		)); err != nil {
-			return 0, err
+			return err
 		}
 	}
 
-	return len(decryptedValues), nil
+	return nil
+}
+
+// currentKeyID returns the identifier that values encrypted with the current
+// version of the configured key are stored with, or the empty string if no
+// key is configured.
+func currentKeyID(ctx context.Context, config EncryptionConfig) (string, error) {
+	key := config.Key()
+	if key == nil {
+		return "", nil
+	}
+
+	version, err := key.Version(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get encryption key version")
+	}
+	return version.JSON(), nil
+}
+
+func keyIDField(c EncryptionConfig) *sqlf.Query {
+	return sqlf.Sprintf("COALESCE(%s, '')", quote(c.KeyIDFieldName))
+}
+
+// presentCondition matches the records with a value to encrypt. For nullable
+// configs, these are the records where any encrypted field is not NULL.
+func presentCondition(c EncryptionConfig) *sqlf.Query {
+	if !c.Nullable {
+		return sqlf.Sprintf("TRUE")
+	}
+
+	conds := make([]*sqlf.Query, 0, len(c.EncryptedFieldNames))
+	for _, name := range c.EncryptedFieldNames {
+		conds = append(conds, sqlf.Sprintf("%s IS NOT NULL", quote(name)))
+	}
+	return sqlf.Sprintf("(%s)", sqlf.Join(conds, " OR "))
 }
 
 func fields(c EncryptionConfig) *sqlf.Query {
@@ -16,6 +16,10 @@ type EncryptionConfig struct {
 	Scan                func(basestore.Rows, error) (map[int]Encrypted, error)
 	Key                 func() encryption.Key
 	Limit               int
+
+	// Nullable skips rows whose encrypted fields are all NULL, so that a
+	// missing value is not encrypted into a present but empty one.
+	Nullable bool
 }
 
 var EncryptionConfigs = []EncryptionConfig{
@@ -26,6 +30,9 @@ var EncryptionConfigs = []EncryptionConfig{
 	webhooklogsEncryptionConfig,
 	executorSecretsEncryptionConfig,
 	outboundWebhooksEncryptionConfig,
+	webhooksEncryptionConfig,
+	codeMonitorWebhooksEncryptionConfig,
+	codyPromptLogsEncryptionConfig,
 }
 
 var externalServicesEncryptionConfig = EncryptionConfig{
@@ -80,6 +87,16 @@ var webhooklogsEncryptionConfig = EncryptionConfig{
 	Limit:               5,
 }
 
+var codyPromptLogsEncryptionConfig = EncryptionConfig{
+	TableName:           "cody_prompt_logs",
+	IDFieldName:         "id",
+	KeyIDFieldName:      "encryption_key_id",
+	EncryptedFieldNames: []string{"prompt", "response"},
+	Scan:                basestore.NewMapScanner(scanEncryptedStringPair),
+	Key:                 func() encryption.Key { return keyring.Default().CodyPromptLogKey },
+	Limit:               100,
+}
+
 var executorSecretsEncryptionConfig = EncryptionConfig{
 	TableName:           "executor_secrets",
 	IDFieldName:         "id",
@@ -101,6 +118,28 @@ var outboundWebhooksEncryptionConfig = EncryptionConfig{
 	Limit:               5,
 }
 
+var webhooksEncryptionConfig = EncryptionConfig{
+	TableName:           "webhooks",
+	IDFieldName:         "id",
+	KeyIDFieldName:      "encryption_key_id",
+	EncryptedFieldNames: []string{"secret"},
+	Nullable:            true,
+	Scan:                basestore.NewMapScanner(scanEncryptedNullString),
+	Key:                 func() encryption.Key { return keyring.Default().WebhookKey },
+	Limit:               5,
+}
+
+var codeMonitorWebhooksEncryptionConfig = EncryptionConfig{
+	TableName:           "cm_webhooks",
+	IDFieldName:         "id",
+	KeyIDFieldName:      "encryption_key_id",
+	EncryptedFieldNames: []string{"signing_secret"},
+	Nullable:            true,
+	Scan:                basestore.NewMapScanner(scanEncryptedNullString),
+	Key:                 func() encryption.Key { return keyring.Default().WebhookKey },
+	Limit:               5,
+}
+
 func scanEncryptedString(scanner dbutil.Scanner) (id int, e Encrypted, err error) {
 	e.Values = make([]string, 1)
 	err = scanner.Scan(&id, &e.KeyID, &e.Values[0])
@@ -113,6 +152,12 @@ func scanEncryptedStringPair(scanner dbutil.Scanner) (id int, e Encrypted, err e
 	return
 }
 
+func scanEncryptedNullString(scanner dbutil.Scanner) (id int, e Encrypted, err error) {
+	e.Values = make([]string, 1)
+	err = scanner.Scan(&id, &dbutil.NullString{S: &e.KeyID}, &dbutil.NullString{S: &e.Values[0]})
+	return
+}
+
 func scanEncryptedBytea(scanner dbutil.Scanner) (id int, e Encrypted, err error) {
 	var bs []byte
 	err = scanner.Scan(&id, &e.KeyID, &bs)
@@ -137,13 +137,112 @@ func TestRecordEncrypter(t *testing.T) {
 	}
 }
 
-type base64Key struct{}
+func TestRecordEncrypterRotate(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	key := &base64Key{}
+	encrypter := NewRecordEncrypter(db)
+
+	if err := encrypter.Exec(ctx, sqlf.Sprintf("CREATE TABLE test_encryptable (id int, encryption_key_id text, data text)")); err != nil {
+		t.Fatalf("failed to create test table: %s", err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := encrypter.Exec(ctx, sqlf.Sprintf("INSERT INTO test_encryptable VALUES (%s, '', %s)", i+1, fmt.Sprintf("data-%d", i))); err != nil {
+			t.Fatalf("failed to insert test data: %s", err)
+		}
+	}
+	// A row without a value is neither encrypted nor counted.
+	if err := encrypter.Exec(ctx, sqlf.Sprintf("INSERT INTO test_encryptable VALUES (11, NULL, NULL)")); err != nil {
+		t.Fatalf("failed to insert test data: %s", err)
+	}
+
+	config := EncryptionConfig{
+		TableName:           "test_encryptable",
+		IDFieldName:         "id",
+		KeyIDFieldName:      "encryption_key_id",
+		EncryptedFieldNames: []string{"data"},
+		Nullable:            true,
+		Scan:                basestore.NewMapScanner(scanEncryptedNullString),
+		Key:                 func() encryption.Key { return key },
+		Limit:               5,
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := encrypter.EncryptBatch(ctx, config); err != nil {
+			t.Fatalf("unexpected error encrypting batch: %s", err)
+		}
+	}
+
+	progress, err := encrypter.Progress(ctx, config)
+	if err != nil {
+		t.Fatalf("unexpected error getting progress: %s", err)
+	}
+	if diff := cmp.Diff(EncryptionProgress{TableName: "test_encryptable", Current: 10}, progress); diff != "" {
+		t.Errorf("unexpected progress (-want +got):\n%s", diff)
+	}
+
+	// Rotate the key: all records are now encrypted with an outdated version.
+	key.version = "1-test"
+
+	progress, err = encrypter.Progress(ctx, config)
+	if err != nil {
+		t.Fatalf("unexpected error getting progress: %s", err)
+	}
+	if diff := cmp.Diff(EncryptionProgress{TableName: "test_encryptable", Outdated: 10}, progress); diff != "" {
+		t.Errorf("unexpected progress (-want +got):\n%s", diff)
+	}
+
+	for i := 0; i < 2; i++ {
+		count, err := encrypter.RotateBatch(ctx, config)
+		if err != nil {
+			t.Fatalf("unexpected error rotating batch: %s", err)
+		}
+		if count != 5 {
+			t.Errorf("unexpected count. want=%d have=%d", 5, count)
+		}
+	}
+	if count, err := encrypter.RotateBatch(ctx, config); err != nil || count != 0 {
+		t.Errorf("unexpected rotation after all records were rotated. count=%d err=%v", count, err)
+	}
+
+	progress, err = encrypter.Progress(ctx, config)
+	if err != nil {
+		t.Fatalf("unexpected error getting progress: %s", err)
+	}
+	if diff := cmp.Diff(EncryptionProgress{TableName: "test_encryptable", Current: 10}, progress); diff != "" {
+		t.Errorf("unexpected progress (-want +got):\n%s", diff)
+	}
+
+	encryptionKeyIDs, err := basestore.ScanStrings(encrypter.Query(ctx, sqlf.Sprintf("SELECT encryption_key_id FROM test_encryptable WHERE data IS NOT NULL")))
+	if err != nil {
+		t.Fatalf("failed to query encryption keys: %s", err)
+	}
+	for _, keyID := range encryptionKeyIDs {
+		if want := testEncryptionKeyID(key); keyID != want {
+			t.Errorf("unexpected key identifier. want=%q have=%q", want, keyID)
+		}
+	}
+}
+
+type base64Key struct {
+	version string
+}
 
 func (k *base64Key) Version(ctx context.Context) (encryption.KeyVersion, error) {
+	version := k.version
+	if version == "" {
+		version = "0-test"
+	}
+
 	return encryption.KeyVersion{
 		Type:    "base64",
 		Name:    "base64",
-		Version: "0-test",
+		Version: version,
 	}, nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -563,21 +564,42 @@ func (e *externalServiceStore) Create(ctx context.Context, confGet func() *conf.
 		return err
 	}
 
-	return e.QueryRow(
-		ctx,
-		sqlf.Sprintf(
-			createExternalServiceQueryFmtstr,
-			es.Kind,
-			es.DisplayName,
-			encryptedConfig,
-			keyID,
-			es.CreatedAt,
-			es.UpdatedAt,
-			es.Unrestricted,
-			es.CloudDefault,
-			es.HasWebhooks,
-		),
-	).Scan(&es.ID)
+	return e.Store.WithTransact(ctx, func(tx *basestore.Store) error {
+		err := tx.QueryRow(
+			ctx,
+			sqlf.Sprintf(
+				createExternalServiceQueryFmtstr,
+				es.Kind,
+				es.DisplayName,
+				encryptedConfig,
+				keyID,
+				es.CreatedAt,
+				es.UpdatedAt,
+				es.Unrestricted,
+				es.CloudDefault,
+				es.HasWebhooks,
+			),
+		).Scan(&es.ID)
+		if err != nil {
+			return err
+		}
+		return recordExternalServiceEvent(ctx, tx, types.AuditLogActionExternalServiceCreated, es.ID, map[string]any{
+			"kind":        es.Kind,
+			"displayName": es.DisplayName,
+		})
+	})
+}
+
+// recordExternalServiceEvent records a change to a code host connection in the
+// audit log. The argument must never contain the configuration, as it holds
+// the credentials of the code host.
+func recordExternalServiceEvent(ctx context.Context, store basestore.ShareableStore, action string, id int64, argument map[string]any) error {
+	return AuditLogEventsWith(store).Record(ctx, AuditLogEventArgs{
+		Action:     action,
+		EntityType: "external_service",
+		EntityID:   strconv.FormatInt(id, 10),
+		Argument:   argument,
+	})
 }
 
 const createExternalServiceQueryFmtstr = `
@@ -900,19 +922,30 @@ func (e *externalServiceStore) Update(ctx context.Context, ps []schema.AuthProvi
 		return nil
 	}
 
-	q := sqlf.Sprintf("UPDATE external_services SET %s, updated_at = NOW() WHERE id = %d AND deleted_at IS NULL", sqlf.Join(updates, ","), id)
-	res, err := e.Store.Handle().ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
-	if err != nil {
-		return err
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
-		return externalServiceNotFoundError{id: id}
-	}
-	return nil
+	return e.Store.WithTransact(ctx, func(tx *basestore.Store) error {
+		q := sqlf.Sprintf("UPDATE external_services SET %s, updated_at = NOW() WHERE id = %d AND deleted_at IS NULL", sqlf.Join(updates, ","), id)
+		res, err := tx.Handle().ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return externalServiceNotFoundError{id: id}
+		}
+
+		// Only changes made by users are audited, not the sync bookkeeping.
+		if update.DisplayName == nil && update.Config == nil {
+			return nil
+		}
+		argument := map[string]any{"configChanged": update.Config != nil}
+		if update.DisplayName != nil {
+			argument["displayName"] = *update.DisplayName
+		}
+		return recordExternalServiceEvent(ctx, tx, types.AuditLogActionExternalServiceUpdated, id, argument)
+	})
 }
 
 type externalServiceNotFoundError struct {
@@ -1030,7 +1063,7 @@ CREATE TEMPORARY TABLE IF NOT EXISTS
 	if nrows == 0 {
 		return externalServiceNotFoundError{id: id}
 	}
-	return nil
+	return recordExternalServiceEvent(ctx, tx, types.AuditLogActionExternalServiceDeleted, id, nil)
 }
 
 // selectForUpdate loads an external service with FOR UPDATE with the given ID
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/keegancsmith/sqlf"
 	"golang.org/x/sync/errgroup"
@@ -11,6 +12,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	ff "github.com/sourcegraph/sourcegraph/internal/featureflag"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
 var clearRedisCache = ff.ClearEvaluatedFlagFromCache
@@ -63,8 +65,12 @@ func (f *featureFlagStore) CreateFeatureFlag(ctx context.Context, flag *ff.Featu
 			flag_name,
 			flag_type,
 			bool_value,
-			rollout
+			rollout,
+			rollout_bucket_by,
+			targeting_rules
 		) VALUES (
+			%s,
+			%s,
 			%s,
 			%s,
 			%s,
@@ -74,33 +80,33 @@ func (f *featureFlagStore) CreateFeatureFlag(ctx context.Context, flag *ff.Featu
 			flag_type,
 			bool_value,
 			rollout,
+			rollout_bucket_by,
+			targeting_rules,
 			created_at,
 			updated_at,
 			deleted_at
 		;
 	`
-	var (
-		flagType string
-		boolVal  *bool
-		rollout  *int32
-	)
-	switch {
-	case flag.Bool != nil:
-		flagType = "bool"
-		boolVal = &flag.Bool.Value
-	case flag.Rollout != nil:
-		flagType = "rollout"
-		rollout = &flag.Rollout.Rollout
-	default:
-		return nil, errors.New("feature flag must have exactly one type")
+	cols, err := featureFlagColumnValues(flag)
+	if err != nil {
+		return nil, err
+	}
+	rolloutBucketBy, targetingRules := cols.rolloutBucketBy, cols.targetingRules
+	if rolloutBucketBy == nil {
+		rolloutBucketBy = pointers.Ptr(string(ff.RolloutBucketByUser))
+	}
+	if targetingRules == nil {
+		targetingRules = pointers.Ptr("[]")
 	}
 
 	row := f.QueryRow(ctx, sqlf.Sprintf(
 		newFeatureFlagFmtStr,
 		flag.Name,
-		flagType,
-		boolVal,
-		rollout))
+		cols.flagType,
+		cols.boolVal,
+		cols.rollout,
+		rolloutBucketBy,
+		targetingRules))
 	return scanFeatureFlag(row)
 }
 
@@ -111,6 +117,9 @@ func (f *featureFlagStore) UpdateFeatureFlag(ctx context.Context, flag *ff.Featu
 			flag_type = %s,
 			bool_value = %s,
 			rollout = %s,
+			-- Keep the rollout bucket and the targeting rules if none are given
+			rollout_bucket_by = COALESCE(%s, rollout_bucket_by),
+			targeting_rules = COALESCE(%s::jsonb, targeting_rules),
 			updated_at = NOW()
 		WHERE flag_name = %s
 		RETURNING
@@ -118,37 +127,77 @@ func (f *featureFlagStore) UpdateFeatureFlag(ctx context.Context, flag *ff.Featu
 			flag_type,
 			bool_value,
 			rollout,
+			rollout_bucket_by,
+			targeting_rules,
 			created_at,
 			updated_at,
 			deleted_at
 		;
 	`
-	var (
-		flagType string
-		boolVal  *bool
-		rollout  *int32
-	)
-	switch {
-	case flag.Bool != nil:
-		flagType = "bool"
-		boolVal = &flag.Bool.Value
-	case flag.Rollout != nil:
-		flagType = "rollout"
-		rollout = &flag.Rollout.Rollout
-	default:
-		return nil, errors.New("feature flag must have exactly one type")
+	cols, err := featureFlagColumnValues(flag)
+	if err != nil {
+		return nil, err
 	}
 
 	row := f.QueryRow(ctx, sqlf.Sprintf(
 		updateFeatureFlagFmtStr,
-		flagType,
-		boolVal,
-		rollout,
+		cols.flagType,
+		cols.boolVal,
+		cols.rollout,
+		cols.rolloutBucketBy,
+		cols.targetingRules,
 		flag.Name,
 	))
 	return scanFeatureFlag(row)
 }
 
+type featureFlagColumns struct {
+	flagType string
+	boolVal  *bool
+	rollout  *int32
+	// rolloutBucketBy and targetingRules are nil if they are not set on the flag, in
+	// which case updates keep the current ones.
+	rolloutBucketBy *string
+	targetingRules  *string
+}
+
+// featureFlagColumnValues returns the values of the columns of the feature_flags
+// table for the given flag.
+func featureFlagColumnValues(flag *ff.FeatureFlag) (cols featureFlagColumns, _ error) {
+	switch {
+	case flag.Bool != nil:
+		cols.flagType = "bool"
+		cols.boolVal = &flag.Bool.Value
+	case flag.Rollout != nil:
+		cols.flagType = "rollout"
+		cols.rollout = &flag.Rollout.Rollout
+		switch flag.Rollout.BucketBy {
+		case "":
+		case ff.RolloutBucketByUser, ff.RolloutBucketByOrg:
+			cols.rolloutBucketBy = pointers.Ptr(string(flag.Rollout.BucketBy))
+		default:
+			return cols, errors.Newf("invalid rollout bucket %q", flag.Rollout.BucketBy)
+		}
+	default:
+		return cols, errors.New("feature flag must have exactly one type")
+	}
+
+	if flag.TargetingRules != nil {
+		for _, rule := range flag.TargetingRules {
+			if (rule.OrgID == nil) == (rule.Role == "") {
+				return cols, errors.New("targeting rules must have exactly one of an organization or a role")
+			}
+		}
+		rules, err := json.Marshal(flag.TargetingRules)
+		if err != nil {
+			return cols, err
+		}
+		cols.targetingRules = pointers.Ptr(string(rules))
+	}
+
+	return cols, nil
+}
+
 func (f *featureFlagStore) DeleteFeatureFlag(ctx context.Context, name string) error {
 	const deleteFeatureFlagFmtStr = `
 		UPDATE feature_flags
@@ -188,6 +237,8 @@ func scanFeatureFlagAndOverride(scanner dbutil.Scanner) (*ff.FeatureFlag, *bool,
 		flagType string
 		boolVal  *bool
 		rollout  *int32
+		bucketBy string
+		rules    []byte
 		override *bool
 	)
 	err := scanner.Scan(
@@ -195,6 +246,8 @@ func scanFeatureFlagAndOverride(scanner dbutil.Scanner) (*ff.FeatureFlag, *bool,
 		&flagType,
 		&boolVal,
 		&rollout,
+		&bucketBy,
+		&rules,
 		&res.CreatedAt,
 		&res.UpdatedAt,
 		&res.DeletedAt,
@@ -217,12 +270,19 @@ func scanFeatureFlagAndOverride(scanner dbutil.Scanner) (*ff.FeatureFlag, *bool,
 			return nil, nil, ErrInvalidColumnState
 		}
 		res.Rollout = &ff.FeatureFlagRollout{
-			Rollout: *rollout,
+			Rollout:  *rollout,
+			BucketBy: ff.RolloutBucketBy(bucketBy),
 		}
 	default:
 		return nil, nil, ErrInvalidColumnState
 	}
 
+	if err := json.Unmarshal(rules, &res.TargetingRules); err != nil {
+		return nil, nil, err
+	} else if len(res.TargetingRules) == 0 {
+		res.TargetingRules = nil
+	}
+
 	return &res, override, nil
 }
 
@@ -232,12 +292,16 @@ func scanFeatureFlag(scanner dbutil.Scanner) (*ff.FeatureFlag, error) {
 		flagType string
 		boolVal  *bool
 		rollout  *int32
+		bucketBy string
+		rules    []byte
 	)
 	err := scanner.Scan(
 		&res.Name,
 		&flagType,
 		&boolVal,
 		&rollout,
+		&bucketBy,
+		&rules,
 		&res.CreatedAt,
 		&res.UpdatedAt,
 		&res.DeletedAt,
@@ -259,12 +323,19 @@ func scanFeatureFlag(scanner dbutil.Scanner) (*ff.FeatureFlag, error) {
 			return nil, ErrInvalidColumnState
 		}
 		res.Rollout = &ff.FeatureFlagRollout{
-			Rollout: *rollout,
+			Rollout:  *rollout,
+			BucketBy: ff.RolloutBucketBy(bucketBy),
 		}
 	default:
 		return nil, ErrInvalidColumnState
 	}
 
+	if err := json.Unmarshal(rules, &res.TargetingRules); err != nil {
+		return nil, err
+	} else if len(res.TargetingRules) == 0 {
+		res.TargetingRules = nil
+	}
+
 	return &res, nil
 }
 
@@ -275,6 +346,8 @@ func (f *featureFlagStore) GetFeatureFlag(ctx context.Context, flagName string)
 			flag_type,
 			bool_value,
 			rollout,
+			rollout_bucket_by,
+			targeting_rules,
 			created_at,
 			updated_at,
 			deleted_at
@@ -294,6 +367,8 @@ func (f *featureFlagStore) GetFeatureFlags(ctx context.Context) ([]*ff.FeatureFl
 			flag_type,
 			bool_value,
 			rollout,
+			rollout_bucket_by,
+			targeting_rules,
 			created_at,
 			updated_at,
 			deleted_at
@@ -547,6 +622,8 @@ func (f *featureFlagStore) GetUserFlags(ctx context.Context, userID int32) (map[
 			flag_type,
 			bool_value,
 			rollout,
+			rollout_bucket_by,
+			targeting_rules,
 			created_at,
 			updated_at,
 			deleted_at,
@@ -558,6 +635,11 @@ func (f *featureFlagStore) GetUserFlags(ctx context.Context, userID int32) (map[
 		LEFT JOIN user_overrides uo ON ff.flag_name = uo.flag_name
 		WHERE deleted_at IS NULL
 	`
+	subject, err := f.getFlagSubject(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	rows, err := f.Query(ctx, sqlf.Sprintf(listUserOverridesFmtString, userID, userID))
 	if err != nil {
 		return nil, err
@@ -573,12 +655,40 @@ func (f *featureFlagStore) GetUserFlags(ctx context.Context, userID int32) (map[
 		if override != nil {
 			res[flag.Name] = *override
 		} else {
-			res[flag.Name] = flag.EvaluateForUser(userID)
+			res[flag.Name] = flag.EvaluateForSubject(subject)
 		}
 	}
 	return res, rows.Err()
 }
 
+// getFlagSubject returns the organizations and roles of the given user, which
+// targeting rules and org-bucketed rollouts are evaluated against.
+func (f *featureFlagStore) getFlagSubject(ctx context.Context, userID int32) (ff.Subject, error) {
+	const getOrgIDsFmtStr = `
+		SELECT org_id
+		FROM org_members
+		WHERE user_id = %s
+		ORDER BY org_id
+	`
+	const getRoleNamesFmtStr = `
+		SELECT roles.name
+		FROM user_roles
+		JOIN roles ON roles.id = user_roles.role_id
+		WHERE user_roles.user_id = %s
+	`
+
+	orgIDs, err := basestore.ScanInt32s(f.Query(ctx, sqlf.Sprintf(getOrgIDsFmtStr, userID)))
+	if err != nil {
+		return ff.Subject{}, err
+	}
+	roles, err := basestore.ScanStrings(f.Query(ctx, sqlf.Sprintf(getRoleNamesFmtStr, userID)))
+	if err != nil {
+		return ff.Subject{}, err
+	}
+
+	return ff.Subject{UserID: userID, OrgIDs: orgIDs, Roles: roles}, nil
+}
+
 // GetAnonymousUserFlags returns the calculated values for feature flags for the given anonymousUID
 func (f *featureFlagStore) GetAnonymousUserFlags(ctx context.Context, anonymousUID string) (map[string]bool, error) {
 	flags, err := f.GetFeatureFlags(ctx)
@@ -637,7 +747,7 @@ func (f *featureFlagStore) GetOrgFeatureFlag(ctx context.Context, orgID int32, f
 	if override != nil {
 		return override.Value, nil
 	} else if globalFlag != nil {
-		return globalFlag.Bool.Value, nil
+		return globalFlag.EvaluateForSubject(ff.Subject{OrgIDs: []int32{orgID}}), nil
 	}
 
 	return false, nil
@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 	ff "github.com/sourcegraph/sourcegraph/internal/featureflag"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
 func TestFeatureFlagStore(t *testing.T) {
@@ -74,13 +75,34 @@ func testNewFeatureFlagRoundtrip(t *testing.T) {
 			flag: &ff.FeatureFlag{Name: "bool_false", Bool: &ff.FeatureFlagBool{Value: false}},
 		},
 		{
-			flag: &ff.FeatureFlag{Name: "min_rollout", Rollout: &ff.FeatureFlagRollout{Rollout: 0}},
+			flag: &ff.FeatureFlag{Name: "min_rollout", Rollout: &ff.FeatureFlagRollout{Rollout: 0, BucketBy: ff.RolloutBucketByUser}},
 		},
 		{
-			flag: &ff.FeatureFlag{Name: "mid_rollout", Rollout: &ff.FeatureFlagRollout{Rollout: 3124}},
+			flag: &ff.FeatureFlag{Name: "mid_rollout", Rollout: &ff.FeatureFlagRollout{Rollout: 3124, BucketBy: ff.RolloutBucketByUser}},
 		},
 		{
-			flag: &ff.FeatureFlag{Name: "max_rollout", Rollout: &ff.FeatureFlagRollout{Rollout: 10000}},
+			flag: &ff.FeatureFlag{Name: "max_rollout", Rollout: &ff.FeatureFlagRollout{Rollout: 10000, BucketBy: ff.RolloutBucketByUser}},
+		},
+		{
+			flag: &ff.FeatureFlag{Name: "org_rollout", Rollout: &ff.FeatureFlagRollout{Rollout: 3124, BucketBy: ff.RolloutBucketByOrg}},
+		},
+		{
+			flag: &ff.FeatureFlag{
+				Name: "targeted",
+				Bool: &ff.FeatureFlagBool{Value: false},
+				TargetingRules: []ff.TargetingRule{
+					{OrgID: pointers.Ptr(int32(1)), Value: true},
+					{Role: "USER", Value: true},
+				},
+			},
+		},
+		{
+			flag:      &ff.FeatureFlag{Name: "err_invalid_bucket", Rollout: &ff.FeatureFlagRollout{Rollout: 1, BucketBy: "repo"}},
+			assertErr: errorContains(`invalid rollout bucket "repo"`),
+		},
+		{
+			flag:      &ff.FeatureFlag{Name: "err_invalid_rule", Bool: &ff.FeatureFlagBool{Value: true}, TargetingRules: []ff.TargetingRule{{Value: true}}},
+			assertErr: errorContains(`targeting rules must have exactly one of an organization or a role`),
 		},
 		{
 			flag:      &ff.FeatureFlag{Name: "err_too_high_rollout", Rollout: &ff.FeatureFlagRollout{Rollout: 10001}},
@@ -110,6 +132,7 @@ func testNewFeatureFlagRoundtrip(t *testing.T) {
 			require.Equal(t, tc.flag.Name, res.Name)
 			require.Equal(t, tc.flag.Bool, res.Bool)
 			require.Equal(t, tc.flag.Rollout, res.Rollout)
+			require.Equal(t, tc.flag.TargetingRules, res.TargetingRules)
 		})
 	}
 }
@@ -123,8 +146,8 @@ func testListFeatureFlags(t *testing.T) {
 
 	flag1 := &ff.FeatureFlag{Name: "bool_true", Bool: &ff.FeatureFlagBool{Value: true}}
 	flag2 := &ff.FeatureFlag{Name: "bool_false", Bool: &ff.FeatureFlagBool{Value: false}}
-	flag3 := &ff.FeatureFlag{Name: "mid_rollout", Rollout: &ff.FeatureFlagRollout{Rollout: 3124}}
-	flag4 := &ff.FeatureFlag{Name: "deletable", Rollout: &ff.FeatureFlagRollout{Rollout: 3125}}
+	flag3 := &ff.FeatureFlag{Name: "mid_rollout", Rollout: &ff.FeatureFlagRollout{Rollout: 3124, BucketBy: ff.RolloutBucketByUser}}
+	flag4 := &ff.FeatureFlag{Name: "deletable", Rollout: &ff.FeatureFlagRollout{Rollout: 3125, BucketBy: ff.RolloutBucketByUser}}
 	flags := []*ff.FeatureFlag{flag1, flag2, flag3, flag4}
 
 	for _, flag := range flags {
@@ -525,6 +548,62 @@ func testUserFlags(t *testing.T) {
 		require.Equal(t, expected, got)
 	})
 
+	t.Run("targeting rules", func(t *testing.T) {
+		t.Cleanup(cleanup(t, db))
+		o1 := mkOrg("o1")
+		o2 := mkOrg("o2")
+		u1 := mkUser("u1", o1.ID)
+		u2 := mkUser("u2", o2.ID)
+		_, err := flagStore.CreateFeatureFlag(ctx, &ff.FeatureFlag{
+			Name:           "f1",
+			Bool:           &ff.FeatureFlagBool{Value: false},
+			TargetingRules: []ff.TargetingRule{{OrgID: &o1.ID, Value: true}},
+		})
+		require.NoError(t, err)
+		role, err := db.Roles().Create(ctx, "beta-testers", false)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Roles().Delete(ctx, DeleteRoleOpts{ID: role.ID}) })
+		require.NoError(t, db.UserRoles().Assign(ctx, AssignUserRoleOpts{UserID: u1.ID, RoleID: role.ID}))
+		_, err = flagStore.CreateFeatureFlag(ctx, &ff.FeatureFlag{
+			Name:           "f2",
+			Bool:           &ff.FeatureFlagBool{Value: false},
+			TargetingRules: []ff.TargetingRule{{Role: "beta-testers", Value: true}},
+		})
+		require.NoError(t, err)
+		mkUserOverride(u2.ID, "f1", true)
+
+		got, err := flagStore.GetUserFlags(ctx, u1.ID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]bool{"f1": true, "f2": true}, got)
+
+		got, err = flagStore.GetUserFlags(ctx, u2.ID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]bool{"f1": true, "f2": false}, got)
+	})
+
+	t.Run("org rollouts", func(t *testing.T) {
+		t.Cleanup(cleanup(t, db))
+		o1 := mkOrg("o1")
+		u1 := mkUser("u1", o1.ID)
+		u2 := mkUser("u2", o1.ID)
+		u3 := mkUser("u3")
+		_, err := flagStore.CreateFeatureFlag(ctx, &ff.FeatureFlag{
+			Name:    "f1",
+			Rollout: &ff.FeatureFlagRollout{Rollout: 10000, BucketBy: ff.RolloutBucketByOrg},
+		})
+		require.NoError(t, err)
+
+		for _, u := range []*types.User{u1, u2} {
+			got, err := flagStore.GetUserFlags(ctx, u.ID)
+			require.NoError(t, err)
+			require.Equal(t, map[string]bool{"f1": true}, got)
+		}
+
+		got, err := flagStore.GetUserFlags(ctx, u3.ID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]bool{"f1": false}, got)
+	})
+
 	t.Run("delete flag with override", func(t *testing.T) {
 		t.Cleanup(cleanup(t, db))
 		o1 := mkOrg("o1")
@@ -778,4 +857,30 @@ func testUpdateFeatureFlag(t *testing.T) {
 		assert.Equal(t, expectedValue, updatedFlag.Rollout.Rollout)
 		assert.Greater(t, updatedFlag.UpdatedAt, rolloutFlag.UpdatedAt)
 	})
+	t.Run("keeps rollout bucket and targeting rules if not set", func(t *testing.T) {
+		rules := []ff.TargetingRule{{Role: "USER", Value: true}}
+		_, err := flagStore.CreateFeatureFlag(ctx, &ff.FeatureFlag{
+			Name:           "update-test-targeted-flag",
+			Rollout:        &ff.FeatureFlagRollout{Rollout: 42, BucketBy: ff.RolloutBucketByOrg},
+			TargetingRules: rules,
+		})
+		require.NoError(t, err)
+
+		updatedFlag, err := flagStore.UpdateFeatureFlag(ctx, &ff.FeatureFlag{
+			Name:    "update-test-targeted-flag",
+			Rollout: &ff.FeatureFlagRollout{Rollout: 1337},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &ff.FeatureFlagRollout{Rollout: 1337, BucketBy: ff.RolloutBucketByOrg}, updatedFlag.Rollout)
+		assert.Equal(t, rules, updatedFlag.TargetingRules)
+
+		updatedFlag, err = flagStore.UpdateFeatureFlag(ctx, &ff.FeatureFlag{
+			Name:           "update-test-targeted-flag",
+			Rollout:        &ff.FeatureFlagRollout{Rollout: 1337, BucketBy: ff.RolloutBucketByUser},
+			TargetingRules: []ff.TargetingRule{},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ff.RolloutBucketByUser, updatedFlag.Rollout.BucketBy)
+		assert.Empty(t, updatedFlag.TargetingRules)
+	})
 }
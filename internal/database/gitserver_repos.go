@@ -231,6 +231,8 @@ JOIN gitserver_repos gr ON repo.id = gr.repo_id
 WHERE
 	(%s OR repo.blocked IS NOT NULL)
 	AND gr.clone_status = %s
+	-- The clones of repositories on legal hold must be kept.
+	AND NOT EXISTS (SELECT 1 FROM repo_legal_holds h WHERE h.repo_id = repo.id)
 ORDER BY deleted_at ASC
 `
 
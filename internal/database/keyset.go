@@ -0,0 +1,157 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ErrInvalidKeysetCursor is returned when a cursor cannot be decoded into the
+// values of the ordering columns it was requested for.
+var ErrInvalidKeysetCursor = errors.New("invalid cursor")
+
+// KeysetColumn is a column of a keyset pagination ordering.
+type KeysetColumn struct {
+	// Field is the column or expression to order by. It must not be nullable.
+	Field string
+	// Descending orders by the column in descending order.
+	Descending bool
+}
+
+// KeysetPagination paginates a query by seeking past the values of the last row
+// of the previous page, rather than by skipping rows with OFFSET, which makes
+// Postgres scan and discard every skipped row and degrades linearly with the
+// page number.
+//
+// The ordering must be total for pages to be stable: the last column should be
+// unique, typically the primary key.
+type KeysetPagination struct {
+	// Columns is the ordering of the paginated rows.
+	Columns []KeysetColumn
+	// After holds the values of the ordering columns of the last row of the
+	// previous page, or nil for the first page.
+	After []any
+	// Limit is the size of a page. If zero, all remaining rows are returned.
+	Limit int
+}
+
+// SQL returns the conditions, ordering and limit of the page. The limit is one
+// more than the page size so that KeysetPage can detect whether a next page
+// exists without a separate count.
+func (p *KeysetPagination) SQL() *QueryArgs {
+	queryArgs := &QueryArgs{Order: p.orderSQL()}
+	if len(p.After) > 0 {
+		queryArgs.Where = p.afterSQL()
+	}
+	if p.Limit > 0 {
+		queryArgs.Limit = sqlf.Sprintf("LIMIT %d", p.Limit+1)
+	}
+	return queryArgs
+}
+
+func (p *KeysetPagination) orderSQL() *sqlf.Query {
+	columns := make([]*sqlf.Query, 0, len(p.Columns))
+	for _, column := range p.Columns {
+		if column.Descending {
+			columns = append(columns, sqlf.Sprintf(column.Field+" DESC"))
+		} else {
+			columns = append(columns, sqlf.Sprintf(column.Field+" ASC"))
+		}
+	}
+	return sqlf.Join(columns, ", ")
+}
+
+// afterSQL returns the condition selecting the rows that sort strictly after
+// the cursor values. Orderings in a single direction use a row comparison,
+// which Postgres can answer with a multi-column index. Mixed directions expand
+// to the equivalent disjunction:
+//
+//	(a > x) OR (a = x AND b < y) OR (a = x AND b = y AND c > z)
+func (p *KeysetPagination) afterSQL() *sqlf.Query {
+	if p.sameDirection() {
+		fields := make([]string, 0, len(p.Columns))
+		for _, column := range p.Columns {
+			fields = append(fields, column.Field)
+		}
+		op := ">"
+		if p.Columns[0].Descending {
+			op = "<"
+		}
+		return sqlf.Sprintf("(%s) "+op+" (%s)", sqlf.Sprintf(strings.Join(fields, ", ")), sqlf.Join(valueQueries(p.After), ", "))
+	}
+
+	disjuncts := make([]*sqlf.Query, 0, len(p.Columns))
+	for i, column := range p.Columns {
+		conjuncts := make([]*sqlf.Query, 0, i+1)
+		for j := 0; j < i; j++ {
+			conjuncts = append(conjuncts, sqlf.Sprintf(p.Columns[j].Field+" = %s", p.After[j]))
+		}
+		op := ">"
+		if column.Descending {
+			op = "<"
+		}
+		conjuncts = append(conjuncts, sqlf.Sprintf(column.Field+" "+op+" %s", p.After[i]))
+		disjuncts = append(disjuncts, sqlf.Sprintf("(%s)", sqlf.Join(conjuncts, " AND ")))
+	}
+	return sqlf.Sprintf("(%s)", sqlf.Join(disjuncts, " OR "))
+}
+
+func (p *KeysetPagination) sameDirection() bool {
+	for _, column := range p.Columns[1:] {
+		if column.Descending != p.Columns[0].Descending {
+			return false
+		}
+	}
+	return true
+}
+
+func valueQueries(values []any) []*sqlf.Query {
+	queries := make([]*sqlf.Query, 0, len(values))
+	for _, value := range values {
+		queries = append(queries, sqlf.Sprintf("%s", value))
+	}
+	return queries
+}
+
+// KeysetPage trims the extra row fetched by a KeysetPagination query with the
+// given limit and reports whether there is a next page.
+func KeysetPage[T any](rows []T, limit int) ([]T, bool) {
+	if limit <= 0 || len(rows) <= limit {
+		return rows, false
+	}
+	return rows[:limit], true
+}
+
+// EncodeKeysetCursor returns an opaque cursor holding the values of the ordering
+// columns of the last row of a page.
+func EncodeKeysetCursor(values ...any) (string, error) {
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// DecodeKeysetCursor decodes a cursor created by EncodeKeysetCursor into the
+// given destinations, which must match the number and types of the encoded values.
+func DecodeKeysetCursor(cursor string, dst ...any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidKeysetCursor
+	}
+
+	var values []json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil || len(values) != len(dst) {
+		return ErrInvalidKeysetCursor
+	}
+	for i, value := range values {
+		if err := json.Unmarshal(value, dst[i]); err != nil {
+			return ErrInvalidKeysetCursor
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
+)
+
+func TestKeysetPagination(t *testing.T) {
+	render := func(q *sqlf.Query) string {
+		if q == nil {
+			return ""
+		}
+		return q.Query(sqlf.PostgresBindVar)
+	}
+
+	testCases := []struct {
+		name       string
+		pagination KeysetPagination
+		wantWhere  string
+		wantOrder  string
+		wantLimit  string
+		wantArgs   []any
+	}{
+		{
+			name: "first page",
+			pagination: KeysetPagination{
+				Columns: []KeysetColumn{{Field: "id"}},
+				Limit:   10,
+			},
+			wantOrder: "id ASC",
+			wantLimit: "LIMIT $1",
+			wantArgs:  []any{11},
+		},
+		{
+			name: "same direction",
+			pagination: KeysetPagination{
+				Columns: []KeysetColumn{{Field: "name", Descending: true}, {Field: "id", Descending: true}},
+				After:   []any{"foo", 42},
+			},
+			wantWhere: "(name, id) < ($1, $2)",
+			wantOrder: "name DESC, id DESC",
+			wantArgs:  []any{"foo", 42},
+		},
+		{
+			name: "mixed directions",
+			pagination: KeysetPagination{
+				Columns: []KeysetColumn{{Field: "uploaded_at", Descending: true}, {Field: "id"}},
+				After:   []any{"t", 42},
+			},
+			wantWhere: "((uploaded_at < $1) OR (uploaded_at = $2 AND id > $3))",
+			wantOrder: "uploaded_at DESC, id ASC",
+			wantArgs:  []any{"t", "t", 42},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := tc.pagination.SQL()
+
+			if diff := cmp.Diff(tc.wantWhere, render(args.Where)); diff != "" {
+				t.Errorf("unexpected where (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantOrder, render(args.Order)); diff != "" {
+				t.Errorf("unexpected order (-want +got):\n%s", diff)
+			}
+
+			var gotArgs []any
+			for _, q := range []*sqlf.Query{args.Where, args.Limit} {
+				if q != nil {
+					gotArgs = append(gotArgs, q.Args()...)
+				}
+			}
+			if diff := cmp.Diff(tc.wantArgs, gotArgs); diff != "" {
+				t.Errorf("unexpected args (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestKeysetCursor(t *testing.T) {
+	createdAt := time.Date(2023, 7, 1, 12, 30, 0, 123456000, time.UTC)
+
+	cursor, err := EncodeKeysetCursor(createdAt, int32(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotCreatedAt time.Time
+	var gotID int32
+	if err := DecodeKeysetCursor(cursor, &gotCreatedAt, &gotID); err != nil {
+		t.Fatal(err)
+	}
+	if !gotCreatedAt.Equal(createdAt) || gotID != 42 {
+		t.Errorf("unexpected cursor values: %v, %d", gotCreatedAt, gotID)
+	}
+
+	for _, invalid := range []string{"", "not base64!", cursor + "x"} {
+		if err := DecodeKeysetCursor(invalid, &gotCreatedAt, &gotID); err != ErrInvalidKeysetCursor {
+			t.Errorf("expected invalid cursor error for %q, got %v", invalid, err)
+		}
+	}
+
+	var onlyID int32
+	if err := DecodeKeysetCursor(cursor, &onlyID); err != ErrInvalidKeysetCursor {
+		t.Errorf("expected invalid cursor error for mismatched values, got %v", err)
+	}
+}
+
+func TestKeysetPage(t *testing.T) {
+	page, hasNextPage := KeysetPage([]int{1, 2, 3, 4}, 3)
+	if diff := cmp.Diff([]int{1, 2, 3}, page); diff != "" || !hasNextPage {
+		t.Errorf("unexpected page (hasNextPage=%v) (-want +got):\n%s", hasNextPage, diff)
+	}
+
+	page, hasNextPage = KeysetPage([]int{1, 2}, 3)
+	if diff := cmp.Diff([]int{1, 2}, page); diff != "" || hasNextPage {
+		t.Errorf("unexpected page (hasNextPage=%v) (-want +got):\n%s", hasNextPage, diff)
+	}
+}
@@ -47,6 +47,11 @@ func DownTo(commandName string, factory RunnerFactory, outFactory OutputFactory,
 		Usage: "Ignore a single pending migration attempt if it will be immediately retried by this operation.",
 		Value: development,
 	}
+	dryRunFlag := &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Print the statements of the migrations that would be run and their estimated lock impact, but do not execute them.",
+		Value: false,
+	}
 
 	makeOptions := func(cmd *cli.Context, out *output.Output, versions []int) (runner.Options, error) {
 		privilegedMode, err := getPivilegedModeFromFlags(cmd, out, unprivilegedOnlyFlag, noopPrivilegedFlag)
@@ -88,6 +93,16 @@ func DownTo(commandName string, factory RunnerFactory, outFactory OutputFactory,
 			return err
 		}
 
+		if dryRunFlag.Get(cmd) {
+			plans, err := r.Plan(ctx, options)
+			if err != nil {
+				return err
+			}
+
+			printMigrationPlans(out, plans)
+			return nil
+		}
+
 		return r.Run(ctx, options)
 	})
 
@@ -105,6 +120,7 @@ func DownTo(commandName string, factory RunnerFactory, outFactory OutputFactory,
 			privilegedHashFlag,
 			ignoreSingleDirtyLogFlag,
 			ignoreSinglePendingLogFlag,
+			dryRunFlag,
 		},
 	}
 }
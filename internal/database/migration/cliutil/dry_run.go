@@ -0,0 +1,55 @@
+package cliutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/runner"
+	"github.com/sourcegraph/sourcegraph/lib/output"
+)
+
+// printMigrationPlans prints the statements of each of the given migration plans along with
+// their estimated lock impact, without executing them.
+func printMigrationPlans(out *output.Output, plans []runner.MigrationPlan) {
+	for _, plan := range plans {
+		if len(plan.Definitions) == 0 {
+			out.WriteLine(output.Emojif(output.EmojiSuccess, "Schema %q is up-to-date, no migrations would be run", plan.SchemaName))
+			continue
+		}
+
+		verb := "apply"
+		if !plan.Up {
+			verb = "unapply"
+		}
+		out.WriteLine(output.Emojif(output.EmojiInfo, "Would %s %d migration(s) to schema %q", verb, len(plan.Definitions), plan.SchemaName))
+
+		statements := plan.Statements()
+		for _, def := range plan.Definitions {
+			var notes []string
+			if def.Privileged {
+				notes = append(notes, "privileged")
+			}
+			if def.IsCreateIndexConcurrently {
+				notes = append(notes, "runs outside of a transaction")
+			}
+			header := fmt.Sprintf("-- Migration %d (%s)", def.ID, def.Name)
+			if len(notes) > 0 {
+				header += fmt.Sprintf(" [%s]", strings.Join(notes, ", "))
+			}
+			out.WriteLine(output.Styled(output.StyleBold, header))
+
+			for _, statement := range statements[def.ID] {
+				out.Writef("%s;", statement.Query)
+
+				style := output.StyleSuggestion
+				if statement.LockImpact.BlocksReads || statement.LockImpact.Scope == runner.LockScopeTableRewrite {
+					style = output.StyleWarning
+				}
+				out.WriteLine(output.Linef("", style, "--   lock: %s", statement.LockImpact))
+			}
+			out.Write("")
+		}
+	}
+
+	out.WriteLine(output.Emoji(output.EmojiInfo, "Dry run: no migrations were executed. Lock impact is a static estimate and does not account for table sizes."))
+}
@@ -50,6 +50,11 @@ func Up(commandName string, factory RunnerFactory, outFactory OutputFactory, dev
 		Usage: "Ignore a single pending migration attempt if it will be immediately retried by this operation.",
 		Value: development,
 	}
+	dryRunFlag := &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Print the statements of the migrations that would be run and their estimated lock impact, but do not execute them.",
+		Value: false,
+	}
 	skipUpgradeValidationFlag := &cli.BoolFlag{
 		Name:  "skip-upgrade-validation",
 		Usage: "Do not attempt to compare the previous instance version with the target instance version for upgrade compatibility. Please refer to https://docs.sourcegraph.com/admin/updates#update-policy for our instance upgrade compatibility policy.",
@@ -110,6 +115,16 @@ func Up(commandName string, factory RunnerFactory, outFactory OutputFactory, dev
 			return err
 		}
 
+		if dryRunFlag.Get(cmd) {
+			plans, err := r.Plan(ctx, options)
+			if err != nil {
+				return err
+			}
+
+			printMigrationPlans(out, plans)
+			return nil
+		}
+
 		db, err := store.ExtractDatabase(ctx, r)
 		if err != nil {
 			return err
@@ -160,6 +175,7 @@ func Up(commandName string, factory RunnerFactory, outFactory OutputFactory, dev
 			privilegedHashesFlag,
 			ignoreSingleDirtyLogFlag,
 			ignoreSinglePendingLogFlag,
+			dryRunFlag,
 			skipUpgradeValidationFlag,
 			skipOutOfBandMigrationValidationFlag,
 		},
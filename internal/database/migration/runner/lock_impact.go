@@ -0,0 +1,228 @@
+package runner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LockScope describes how long a lock is held relative to the size of the locked table.
+type LockScope int
+
+const (
+	// LockScopeNone indicates that no lock is taken on existing tables.
+	LockScopeNone LockScope = iota
+	// LockScopeBrief indicates that the lock is held for a short time independent of table size.
+	LockScopeBrief
+	// LockScopeRows indicates that the lock is held while the affected rows are modified.
+	LockScopeRows
+	// LockScopeTableScan indicates that the lock is held while the whole table is scanned.
+	LockScopeTableScan
+	// LockScopeTableRewrite indicates that the lock is held while the whole table is rewritten.
+	LockScopeTableRewrite
+	// LockScopeUnknown indicates that the impact of the statement could not be estimated.
+	LockScopeUnknown
+)
+
+func (s LockScope) String() string {
+	switch s {
+	case LockScopeNone:
+		return "none"
+	case LockScopeBrief:
+		return "brief"
+	case LockScopeRows:
+		return "affected rows"
+	case LockScopeTableScan:
+		return "full table scan"
+	case LockScopeTableRewrite:
+		return "full table rewrite"
+	}
+
+	return "unknown"
+}
+
+// LockImpact is a static estimate of the table-level locks taken by a SQL statement.
+type LockImpact struct {
+	// Mode is the strongest Postgres table-level lock mode taken by the statement on an
+	// existing table, or empty if the statement does not lock existing tables.
+	Mode string
+	// BlocksReads is true if the lock conflicts with SELECT queries.
+	BlocksReads bool
+	// BlocksWrites is true if the lock conflicts with INSERT, UPDATE and DELETE queries.
+	BlocksWrites bool
+	// Scope describes how long the lock is held.
+	Scope LockScope
+}
+
+func (i LockImpact) String() string {
+	if i.Scope == LockScopeUnknown {
+		return "unknown lock impact"
+	}
+	if i.Mode == "" {
+		return "no locks on existing tables"
+	}
+
+	blocks := "does not block reads or writes"
+	if i.BlocksReads {
+		blocks = "blocks reads and writes"
+	} else if i.BlocksWrites {
+		blocks = "blocks writes"
+	}
+
+	return fmt.Sprintf("%s lock (%s, %s)", i.Mode, blocks, i.Scope)
+}
+
+const (
+	accessExclusive      = "ACCESS EXCLUSIVE"
+	shareRowExclusive    = "SHARE ROW EXCLUSIVE"
+	share                = "SHARE"
+	shareUpdateExclusive = "SHARE UPDATE EXCLUSIVE"
+	rowExclusive         = "ROW EXCLUSIVE"
+)
+
+var (
+	whitespacePattern         = regexp.MustCompile(`\s+`)
+	noLockStatementPattern    = regexp.MustCompile(`^(BEGIN|COMMIT|SET|CREATE (OR REPLACE )?(FUNCTION|PROCEDURE|TYPE|SEQUENCE|SCHEMA|EXTENSION|VIEW|TEMP|TEMPORARY)|CREATE (UNLOGGED )?TABLE|DROP (FUNCTION|PROCEDURE|TYPE|SEQUENCE|SCHEMA|EXTENSION)|ALTER (FUNCTION|PROCEDURE|TYPE|SEQUENCE|EXTENSION))\b`)
+	volatileDefaultPattern    = regexp.MustCompile(`\bDEFAULT\b.*\b(GEN_RANDOM_UUID|RANDOM|CLOCK_TIMESTAMP|NEXTVAL|UUID_GENERATE_V4)\s*\(`)
+	columnTypeChangePattern   = regexp.MustCompile(`\bALTER (COLUMN )?\S+ (SET DATA )?TYPE\b`)
+	serialColumnPattern       = regexp.MustCompile(`\bADD (COLUMN )?(IF NOT EXISTS )?\S+ (BIG|SMALL)?SERIAL\b`)
+	indexBackedConstraintExpr = regexp.MustCompile(`\bADD (CONSTRAINT \S+ )?(PRIMARY KEY|UNIQUE|EXCLUDE)\b`)
+)
+
+// EstimateLockImpact statically estimates the locks taken by the given SQL statement when it
+// is run against existing tables. The estimate is based on the statement text only, and does
+// not account for the size of the affected tables, whether columns are already populated, or
+// the behavior of DO blocks and functions.
+func EstimateLockImpact(statement string) LockImpact {
+	s := strings.ToUpper(strings.TrimSpace(whitespacePattern.ReplaceAllString(stripComments(statement), " ")))
+	s = strings.TrimSuffix(s, ";")
+
+	switch {
+	case s == "" || noLockStatementPattern.MatchString(s):
+		return LockImpact{Scope: LockScopeNone}
+
+	case strings.HasPrefix(s, "CREATE INDEX CONCURRENTLY"), strings.HasPrefix(s, "CREATE UNIQUE INDEX CONCURRENTLY"):
+		return LockImpact{Mode: shareUpdateExclusive, Scope: LockScopeTableScan}
+	case strings.HasPrefix(s, "CREATE INDEX"), strings.HasPrefix(s, "CREATE UNIQUE INDEX"):
+		return LockImpact{Mode: share, BlocksWrites: true, Scope: LockScopeTableScan}
+	case strings.HasPrefix(s, "DROP INDEX CONCURRENTLY"):
+		return LockImpact{Mode: shareUpdateExclusive, Scope: LockScopeBrief}
+	case strings.HasPrefix(s, "REINDEX") && strings.Contains(s, "CONCURRENTLY"):
+		return LockImpact{Mode: shareUpdateExclusive, Scope: LockScopeTableScan}
+	case strings.HasPrefix(s, "REINDEX"):
+		return LockImpact{Mode: accessExclusive, BlocksReads: true, BlocksWrites: true, Scope: LockScopeTableScan}
+
+	case strings.HasPrefix(s, "CREATE TRIGGER"), strings.HasPrefix(s, "CREATE OR REPLACE TRIGGER"), strings.HasPrefix(s, "CREATE CONSTRAINT TRIGGER"):
+		return LockImpact{Mode: shareRowExclusive, BlocksWrites: true, Scope: LockScopeBrief}
+	case strings.HasPrefix(s, "CREATE MATERIALIZED VIEW"), strings.HasPrefix(s, "REFRESH MATERIALIZED VIEW CONCURRENTLY"):
+		return LockImpact{Mode: shareUpdateExclusive, Scope: LockScopeTableScan}
+	case strings.HasPrefix(s, "REFRESH MATERIALIZED VIEW"):
+		return LockImpact{Mode: accessExclusive, BlocksReads: true, BlocksWrites: true, Scope: LockScopeTableScan}
+	case strings.HasPrefix(s, "COMMENT ON"):
+		return LockImpact{Mode: shareUpdateExclusive, Scope: LockScopeBrief}
+	case strings.HasPrefix(s, "DROP"), strings.HasPrefix(s, "TRUNCATE"), strings.HasPrefix(s, "ALTER INDEX"), strings.HasPrefix(s, "ALTER VIEW"):
+		return LockImpact{Mode: accessExclusive, BlocksReads: true, BlocksWrites: true, Scope: LockScopeBrief}
+
+	case strings.HasPrefix(s, "ALTER TABLE"):
+		return estimateAlterTableLockImpact(s)
+
+	case strings.HasPrefix(s, "INSERT"):
+		return LockImpact{Mode: rowExclusive, Scope: LockScopeRows}
+	case strings.HasPrefix(s, "UPDATE"), strings.HasPrefix(s, "DELETE"), strings.HasPrefix(s, "WITH"):
+		return LockImpact{Mode: rowExclusive, Scope: LockScopeRows}
+	}
+
+	return LockImpact{Scope: LockScopeUnknown}
+}
+
+// estimateAlterTableLockImpact estimates the locks taken by a normalized ALTER TABLE statement.
+func estimateAlterTableLockImpact(s string) LockImpact {
+	switch {
+	case strings.Contains(s, " VALIDATE CONSTRAINT "):
+		return LockImpact{Mode: shareUpdateExclusive, Scope: LockScopeTableScan}
+
+	case strings.Contains(s, " FOREIGN KEY ") || strings.Contains(s, " REFERENCES "):
+		if strings.Contains(s, " NOT VALID") {
+			return LockImpact{Mode: shareRowExclusive, BlocksWrites: true, Scope: LockScopeBrief}
+		}
+		return LockImpact{Mode: shareRowExclusive, BlocksWrites: true, Scope: LockScopeTableScan}
+	}
+
+	impact := LockImpact{Mode: accessExclusive, BlocksReads: true, BlocksWrites: true, Scope: LockScopeBrief}
+
+	switch {
+	case columnTypeChangePattern.MatchString(s), volatileDefaultPattern.MatchString(s), serialColumnPattern.MatchString(s):
+		impact.Scope = LockScopeTableRewrite
+	case strings.Contains(s, " SET NOT NULL"), indexBackedConstraintExpr.MatchString(s):
+		impact.Scope = LockScopeTableScan
+	case strings.Contains(s, " ADD CONSTRAINT ") && !strings.Contains(s, " NOT VALID"):
+		impact.Scope = LockScopeTableScan
+	}
+
+	return impact
+}
+
+// splitStatements splits the given SQL text into individual statements on semicolons that
+// occur outside of quoted strings, quoted identifiers, dollar-quoted bodies and comments.
+// Statements consisting only of whitespace and comments are omitted.
+func splitStatements(query string) []string {
+	var statements []string
+	start := 0
+
+	add := func(end int) {
+		if statement := strings.TrimSpace(query[start:end]); strings.TrimSpace(stripComments(statement)) != "" {
+			statements = append(statements, statement)
+		}
+		start = end + 1
+	}
+
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '\'' || c == '"':
+			i = skipUntil(query, i+1, string(c))
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			i = skipUntil(query, i+2, "\n")
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			i = skipUntil(query, i+2, "*/") + 1
+		case c == '$':
+			if tag, ok := dollarQuoteTag(query[i:]); ok {
+				i = skipUntil(query, i+len(tag), tag) + len(tag) - 1
+			}
+		case c == ';':
+			add(i)
+		}
+	}
+	if start < len(query) {
+		add(len(query))
+	}
+
+	return statements
+}
+
+// skipUntil returns the index of the first byte of the given terminator at or after the given
+// offset, or the index of the last byte of the query if the terminator does not occur.
+func skipUntil(query string, offset int, terminator string) int {
+	if offset >= len(query) {
+		return len(query) - 1
+	}
+	if n := strings.Index(query[offset:], terminator); n >= 0 {
+		return offset + n
+	}
+	return len(query) - 1
+}
+
+var dollarQuoteTagPattern = regexp.MustCompile(`^\$[A-Za-z_]*\$`)
+
+// dollarQuoteTag returns the dollar-quote tag (e.g. `$$` or `$body$`) at the start of the given text.
+func dollarQuoteTag(s string) (string, bool) {
+	tag := dollarQuoteTagPattern.FindString(s)
+	return tag, tag != ""
+}
+
+var commentPattern = regexp.MustCompile(`(?s)--[^\n]*|/\*.*?\*/`)
+
+// stripComments removes SQL comments from the given statement. The statement is assumed to
+// not contain comment markers within string literals.
+func stripComments(statement string) string {
+	return commentPattern.ReplaceAllString(statement, "")
+}
@@ -0,0 +1,64 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEstimateLockImpact(t *testing.T) {
+	testCases := []struct {
+		statement string
+		expected  LockImpact
+	}{
+		{"CREATE TABLE foo (id serial PRIMARY KEY)", LockImpact{Scope: LockScopeNone}},
+		{"CREATE OR REPLACE FUNCTION f() RETURNS trigger AS $$ BEGIN RETURN NEW; END $$ LANGUAGE plpgsql", LockImpact{Scope: LockScopeNone}},
+		{"CREATE INDEX CONCURRENTLY IF NOT EXISTS foo_bar ON foo(bar)", LockImpact{Mode: "SHARE UPDATE EXCLUSIVE", Scope: LockScopeTableScan}},
+		{"create unique index foo_bar on foo(bar)", LockImpact{Mode: "SHARE", BlocksWrites: true, Scope: LockScopeTableScan}},
+		{"CREATE TRIGGER t AFTER INSERT ON foo FOR EACH ROW EXECUTE FUNCTION f()", LockImpact{Mode: "SHARE ROW EXCLUSIVE", BlocksWrites: true, Scope: LockScopeBrief}},
+		{"DROP TABLE IF EXISTS foo", LockImpact{Mode: "ACCESS EXCLUSIVE", BlocksReads: true, BlocksWrites: true, Scope: LockScopeBrief}},
+		{"ALTER TABLE foo ADD COLUMN bar text NOT NULL DEFAULT ''", LockImpact{Mode: "ACCESS EXCLUSIVE", BlocksReads: true, BlocksWrites: true, Scope: LockScopeBrief}},
+		{"ALTER TABLE foo ADD COLUMN bar uuid DEFAULT gen_random_uuid()", LockImpact{Mode: "ACCESS EXCLUSIVE", BlocksReads: true, BlocksWrites: true, Scope: LockScopeTableRewrite}},
+		{"ALTER TABLE foo ALTER COLUMN bar TYPE bigint", LockImpact{Mode: "ACCESS EXCLUSIVE", BlocksReads: true, BlocksWrites: true, Scope: LockScopeTableRewrite}},
+		{"ALTER TABLE foo ALTER COLUMN bar SET NOT NULL", LockImpact{Mode: "ACCESS EXCLUSIVE", BlocksReads: true, BlocksWrites: true, Scope: LockScopeTableScan}},
+		{"ALTER TABLE foo ADD CONSTRAINT bar_check CHECK (bar > 0) NOT VALID", LockImpact{Mode: "ACCESS EXCLUSIVE", BlocksReads: true, BlocksWrites: true, Scope: LockScopeBrief}},
+		{"ALTER TABLE foo VALIDATE CONSTRAINT bar_check", LockImpact{Mode: "SHARE UPDATE EXCLUSIVE", Scope: LockScopeTableScan}},
+		{"ALTER TABLE foo ADD CONSTRAINT foo_bar_fk FOREIGN KEY (bar_id) REFERENCES bar(id)", LockImpact{Mode: "SHARE ROW EXCLUSIVE", BlocksWrites: true, Scope: LockScopeTableScan}},
+		{"UPDATE foo SET bar = 1 WHERE bar IS NULL", LockImpact{Mode: "ROW EXCLUSIVE", Scope: LockScopeRows}},
+		{"DO $$ BEGIN PERFORM 1; END $$", LockImpact{Scope: LockScopeUnknown}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.statement, func(t *testing.T) {
+			if diff := cmp.Diff(testCase.expected, EstimateLockImpact(testCase.statement)); diff != "" {
+				t.Errorf("unexpected lock impact (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	query := `
+-- leading comment; with a semicolon
+CREATE TABLE foo (id int, name text DEFAULT 'a;b');
+
+CREATE OR REPLACE FUNCTION f() RETURNS trigger AS $body$
+BEGIN
+	RAISE NOTICE 'x;y'; -- inner
+	RETURN NEW;
+END;
+$body$ LANGUAGE plpgsql;
+
+/* block; comment */
+COMMENT ON COLUMN foo.name IS 'the "name";'
+`
+
+	expected := []string{
+		"-- leading comment; with a semicolon\nCREATE TABLE foo (id int, name text DEFAULT 'a;b')",
+		"CREATE OR REPLACE FUNCTION f() RETURNS trigger AS $body$\nBEGIN\n\tRAISE NOTICE 'x;y'; -- inner\n\tRETURN NEW;\nEND;\n$body$ LANGUAGE plpgsql",
+		"/* block; comment */\nCOMMENT ON COLUMN foo.name IS 'the \"name\";'",
+	}
+	if diff := cmp.Diff(expected, splitStatements(query)); diff != "" {
+		t.Errorf("unexpected statements (-want +got):\n%s", diff)
+	}
+}
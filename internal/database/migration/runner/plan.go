@@ -0,0 +1,109 @@
+package runner
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/definition"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// MigrationPlan is the ordered set of migrations that an operation would apply (or unapply)
+// on a single schema.
+type MigrationPlan struct {
+	SchemaName  string
+	Up          bool
+	Definitions []definition.Definition
+}
+
+// Statements returns the statements of each planned migration in the direction of the plan,
+// keyed by migration identifier, along with their estimated lock impact.
+func (p MigrationPlan) Statements() map[int][]PlannedStatement {
+	statements := make(map[int][]PlannedStatement, len(p.Definitions))
+	for _, def := range p.Definitions {
+		for _, statement := range splitStatements(renderQuery(def, p.Up)) {
+			statements[def.ID] = append(statements[def.ID], PlannedStatement{
+				Query:      statement,
+				LockImpact: EstimateLockImpact(statement),
+			})
+		}
+	}
+
+	return statements
+}
+
+// PlannedStatement is a single SQL statement of a planned migration.
+type PlannedStatement struct {
+	Query      string
+	LockImpact LockImpact
+}
+
+// Plan returns the migrations that Run would apply or unapply for each of the given operations,
+// in the order they would be run. The returned plans follow the order of the operations. Plan
+// does not take the migration lock and does not modify the database.
+//
+// Plan reflects the schema state at the time of the call. Failed or pending migration attempts
+// are included in the plan, but the validations performed by Run (dirty database detection,
+// privileged migration handling) are not.
+func (r *Runner) Plan(ctx context.Context, options Options) ([]MigrationPlan, error) {
+	schemaNames := make([]string, 0, len(options.Operations))
+	operationMap := make(map[string]MigrationOperation, len(options.Operations))
+	for _, operation := range options.Operations {
+		schemaNames = append(schemaNames, operation.SchemaName)
+		operationMap[operation.SchemaName] = operation
+	}
+	if len(operationMap) != len(options.Operations) {
+		return nil, errors.Newf("multiple operations defined on the same schema")
+	}
+
+	var mu sync.Mutex
+	planMap := make(map[string]MigrationPlan, len(schemaNames))
+
+	if err := r.forEachSchema(ctx, schemaNames, func(ctx context.Context, schemaContext schemaContext) error {
+		schemaName := schemaContext.schema.Name
+
+		plan, err := planSchema(schemaContext, operationMap[schemaName])
+		if err != nil {
+			return errors.Wrapf(err, "failed to plan migration for schema %q", schemaName)
+		}
+
+		mu.Lock()
+		planMap[schemaName] = plan
+		mu.Unlock()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	plans := make([]MigrationPlan, 0, len(schemaNames))
+	for _, schemaName := range schemaNames {
+		plans = append(plans, planMap[schemaName])
+	}
+
+	return plans, nil
+}
+
+// planSchema returns the migrations that runSchema would apply or unapply to fulfill the given operation.
+func planSchema(schemaContext schemaContext, operation MigrationOperation) (MigrationPlan, error) {
+	operation, err := desugarOperation(schemaContext, operation)
+	if err != nil {
+		return MigrationPlan{}, err
+	}
+
+	up := operation.Type == MigrationOperationTypeTargetedUp
+	gatherDefinitions := schemaContext.schema.Definitions.Up
+	if !up {
+		gatherDefinitions = schemaContext.schema.Definitions.Down
+	}
+
+	definitions, err := gatherDefinitions(schemaContext.initialSchemaVersion.appliedVersions, operation.TargetVersions)
+	if err != nil {
+		return MigrationPlan{}, err
+	}
+
+	return MigrationPlan{
+		SchemaName:  schemaContext.schema.Name,
+		Up:          up,
+		Definitions: filterAppliedDefinitions(schemaContext.initialSchemaVersion, operation, definitions),
+	}, nil
+}
@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPlan(t *testing.T) {
+	overrideSchemas(t)
+	ctx := context.Background()
+
+	t.Run("upgrade (partially applied)", func(t *testing.T) {
+		store := testStoreWithVersion(10002, false)
+
+		plans, err := makeTestRunner(t, store).Plan(ctx, Options{
+			Operations: []MigrationOperation{
+				{
+					SchemaName: "well-formed",
+					Type:       MigrationOperationTypeUpgrade,
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if len(plans) != 1 || !plans[0].Up {
+			t.Fatalf("unexpected plans: %v", plans)
+		}
+		if diff := cmp.Diff([]int{10003, 10004}, extractIDs(plans[0].Definitions)); diff != "" {
+			t.Errorf("unexpected planned migrations (-want +got):\n%s", diff)
+		}
+
+		mockassert.NotCalled(t, store.TryLockFunc)
+		mockassert.NotCalled(t, store.UpFunc)
+		mockassert.NotCalled(t, store.DownFunc)
+	})
+
+	t.Run("revert", func(t *testing.T) {
+		store := testStoreWithVersion(10003, false)
+
+		plans, err := makeTestRunner(t, store).Plan(ctx, Options{
+			Operations: []MigrationOperation{
+				{
+					SchemaName: "well-formed",
+					Type:       MigrationOperationTypeRevert,
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if len(plans) != 1 || plans[0].Up {
+			t.Fatalf("unexpected plans: %v", plans)
+		}
+		if diff := cmp.Diff([]int{10003}, extractIDs(plans[0].Definitions)); diff != "" {
+			t.Errorf("unexpected planned migrations (-want +got):\n%s", diff)
+		}
+
+		mockassert.NotCalled(t, store.UpFunc)
+		mockassert.NotCalled(t, store.DownFunc)
+	})
+}
@@ -0,0 +1,282 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// NotificationStore stores the notifications of users.
+type NotificationStore interface {
+	basestore.ShareableStore
+
+	Create(context.Context, *types.Notification) error
+	List(context.Context, NotificationListOpts) ([]*types.Notification, int64, error)
+	CountUnread(ctx context.Context, userID int32) (int, error)
+	MarkRead(ctx context.Context, userID int32, ids []int64) error
+	ListDigestUsers(ctx context.Context, before time.Time) ([]int32, error)
+	ListDigestPending(ctx context.Context, userID int32) ([]*types.Notification, error)
+	MarkDigested(ctx context.Context, ids []int64) error
+}
+
+type notificationStore struct {
+	*basestore.Store
+}
+
+var _ NotificationStore = &notificationStore{}
+
+func NotificationsWith(other basestore.ShareableStore) NotificationStore {
+	return &notificationStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *notificationStore) Create(ctx context.Context, n *types.Notification) error {
+	createdAt := n.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = timeutil.Now()
+	}
+
+	q := sqlf.Sprintf(
+		notificationCreateQueryFmtstr,
+		n.UserID,
+		n.Kind,
+		n.Title,
+		n.Body,
+		n.URL,
+		n.InApp,
+		n.DigestPending,
+		createdAt,
+		sqlf.Join(notificationColumns, ", "),
+	)
+
+	row := s.QueryRow(ctx, q)
+	if err := scanNotification(n, row); err != nil {
+		return errors.Wrap(err, "scanning notification")
+	}
+
+	return nil
+}
+
+type NotificationListOpts struct {
+	// The user whose notifications are returned.
+	UserID int32
+
+	// If set, only unread notifications are returned.
+	UnreadOnly bool
+
+	// The maximum number of entries to return, and the cursor, if any. The cursor
+	// is the ID of the next entry, as new notifications are created while paging.
+	Limit  int
+	Cursor int64
+}
+
+// List returns the notifications of the inbox of the user, newest first, and the
+// cursor of the next page, which is 0 if there are no more notifications.
+func (s *notificationStore) List(ctx context.Context, opts NotificationListOpts) (_ []*types.Notification, _ int64, err error) {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("user_id = %s", opts.UserID),
+		sqlf.Sprintf("in_app"),
+	}
+	if opts.UnreadOnly {
+		preds = append(preds, sqlf.Sprintf("read_at IS NULL"))
+	}
+	if opts.Cursor != 0 {
+		preds = append(preds, sqlf.Sprintf("id <= %s", opts.Cursor))
+	}
+
+	limit := sqlf.Sprintf("")
+	if opts.Limit != 0 {
+		limit = sqlf.Sprintf("LIMIT %s", opts.Limit+1)
+	}
+
+	notifications, err := s.list(ctx, sqlf.Join(preds, " AND "), limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var next int64
+	if opts.Limit != 0 && len(notifications) == opts.Limit+1 {
+		next = notifications[len(notifications)-1].ID
+		notifications = notifications[:len(notifications)-1]
+	}
+
+	return notifications, next, nil
+}
+
+// CountUnread returns the number of unread notifications of the inbox of the
+// user.
+func (s *notificationStore) CountUnread(ctx context.Context, userID int32) (int, error) {
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(notificationCountUnreadQueryFmtstr, userID)))
+	return count, err
+}
+
+// MarkRead marks the given notifications of the user as read, or all of them if
+// ids is nil.
+func (s *notificationStore) MarkRead(ctx context.Context, userID int32, ids []int64) error {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("user_id = %s", userID),
+		sqlf.Sprintf("read_at IS NULL"),
+	}
+	if ids != nil {
+		preds = append(preds, sqlf.Sprintf("id = ANY(%s)", pq.Array(ids)))
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(notificationMarkReadQueryFmtstr, timeutil.Now(), sqlf.Join(preds, " AND ")))
+}
+
+// ListDigestUsers returns the users with notifications pending for an email
+// digest that were created before the given time.
+func (s *notificationStore) ListDigestUsers(ctx context.Context, before time.Time) ([]int32, error) {
+	return basestore.ScanInt32s(s.Query(ctx, sqlf.Sprintf(notificationListDigestUsersQueryFmtstr, before)))
+}
+
+// ListDigestPending returns the notifications of the user pending for an email
+// digest, oldest first.
+func (s *notificationStore) ListDigestPending(ctx context.Context, userID int32) (_ []*types.Notification, err error) {
+	preds := sqlf.Sprintf("user_id = %s AND digest_pending", userID)
+	notifications, err := s.list(ctx, preds, sqlf.Sprintf(""))
+	if err != nil {
+		return nil, err
+	}
+
+	// Digests read chronologically.
+	for i, j := 0, len(notifications)-1; i < j; i, j = i+1, j-1 {
+		notifications[i], notifications[j] = notifications[j], notifications[i]
+	}
+	return notifications, nil
+}
+
+// MarkDigested marks the given notifications as sent in an email digest.
+func (s *notificationStore) MarkDigested(ctx context.Context, ids []int64) error {
+	return s.Exec(ctx, sqlf.Sprintf(notificationMarkDigestedQueryFmtstr, pq.Array(ids)))
+}
+
+func (s *notificationStore) list(ctx context.Context, preds, limit *sqlf.Query) (_ []*types.Notification, err error) {
+	q := sqlf.Sprintf(
+		notificationListQueryFmtstr,
+		sqlf.Join(notificationColumns, ", "),
+		preds,
+		limit,
+	)
+
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	notifications := []*types.Notification{}
+	for rows.Next() {
+		n := types.Notification{}
+		if err := scanNotification(&n, rows); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, &n)
+	}
+
+	return notifications, nil
+}
+
+var notificationColumns = []*sqlf.Query{
+	sqlf.Sprintf("id"),
+	sqlf.Sprintf("user_id"),
+	sqlf.Sprintf("kind"),
+	sqlf.Sprintf("title"),
+	sqlf.Sprintf("body"),
+	sqlf.Sprintf("url"),
+	sqlf.Sprintf("in_app"),
+	sqlf.Sprintf("digest_pending"),
+	sqlf.Sprintf("read_at"),
+	sqlf.Sprintf("created_at"),
+}
+
+const notificationCreateQueryFmtstr = `
+INSERT INTO
+	notifications (
+		user_id,
+		kind,
+		title,
+		body,
+		url,
+		in_app,
+		digest_pending,
+		created_at
+	)
+	VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
+	RETURNING %s
+`
+
+const notificationListQueryFmtstr = `
+SELECT
+	%s
+FROM
+	notifications
+WHERE
+	%s
+ORDER BY
+	id DESC
+%s -- LIMIT
+`
+
+const notificationCountUnreadQueryFmtstr = `
+SELECT
+	COUNT(*)
+FROM
+	notifications
+WHERE
+	user_id = %s
+	AND in_app
+	AND read_at IS NULL
+`
+
+const notificationMarkReadQueryFmtstr = `
+UPDATE
+	notifications
+SET
+	read_at = %s
+WHERE
+	%s
+`
+
+const notificationListDigestUsersQueryFmtstr = `
+SELECT DISTINCT
+	user_id
+FROM
+	notifications
+WHERE
+	digest_pending
+	AND created_at <= %s
+ORDER BY
+	user_id
+`
+
+const notificationMarkDigestedQueryFmtstr = `
+UPDATE
+	notifications
+SET
+	digest_pending = FALSE
+WHERE
+	id = ANY(%s)
+`
+
+func scanNotification(n *types.Notification, sc dbutil.Scanner) error {
+	return sc.Scan(
+		&n.ID,
+		&n.UserID,
+		&n.Kind,
+		&n.Title,
+		&n.Body,
+		&n.URL,
+		&n.InApp,
+		&n.DigestPending,
+		&n.ReadAt,
+		&n.CreatedAt,
+	)
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestNotificationStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+
+	alice, err := db.Users().Create(ctx, NewUser{Username: "alice"})
+	require.NoError(t, err)
+	bob, err := db.Users().Create(ctx, NewUser{Username: "bob"})
+	require.NoError(t, err)
+
+	store := db.Notifications()
+
+	now := time.Now()
+	newNotification := func(userID int32, inApp, digestPending bool, createdAt time.Time) *types.Notification {
+		n := &types.Notification{
+			UserID:        userID,
+			Kind:          types.NotificationKindCodeMonitor,
+			Title:         "New results for code monitor",
+			URL:           "/code-monitoring",
+			InApp:         inApp,
+			DigestPending: digestPending,
+			CreatedAt:     createdAt,
+		}
+		require.NoError(t, store.Create(ctx, n))
+		return n
+	}
+
+	old := newNotification(alice.ID, true, true, now.Add(-48*time.Hour))
+	recent := newNotification(alice.ID, true, false, now)
+	emailOnly := newNotification(alice.ID, false, true, now.Add(-time.Hour))
+	other := newNotification(bob.ID, true, true, now)
+
+	t.Run("List", func(t *testing.T) {
+		notifications, next, err := store.List(ctx, NotificationListOpts{UserID: alice.ID, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, recent.ID, notifications[0].ID)
+		assert.Equal(t, types.NotificationKindCodeMonitor, notifications[0].Kind)
+		assert.Equal(t, old.ID, next)
+
+		// Notifications that are not shown in the inbox are skipped.
+		notifications, next, err = store.List(ctx, NotificationListOpts{UserID: alice.ID, Limit: 1, Cursor: next})
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, old.ID, notifications[0].ID)
+		assert.Zero(t, next)
+	})
+
+	t.Run("MarkRead", func(t *testing.T) {
+		count, err := store.CountUnread(ctx, alice.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		require.NoError(t, store.MarkRead(ctx, alice.ID, []int64{old.ID, other.ID}))
+
+		notifications, _, err := store.List(ctx, NotificationListOpts{UserID: alice.ID, UnreadOnly: true})
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, recent.ID, notifications[0].ID)
+
+		// Users cannot mark the notifications of others as read.
+		count, err = store.CountUnread(ctx, bob.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		require.NoError(t, store.MarkRead(ctx, alice.ID, nil))
+		count, err = store.CountUnread(ctx, alice.ID)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("digests", func(t *testing.T) {
+		users, err := store.ListDigestUsers(ctx, now.Add(-24*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, []int32{alice.ID}, users)
+
+		pending, err := store.ListDigestPending(ctx, alice.ID)
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+		assert.Equal(t, old.ID, pending[0].ID)
+		assert.Equal(t, emailOnly.ID, pending[1].ID)
+
+		require.NoError(t, store.MarkDigested(ctx, []int64{old.ID, emailOnly.ID}))
+
+		users, err = store.ListDigestUsers(ctx, now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, []int32{bob.ID}, users)
+	})
+}
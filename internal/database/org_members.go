@@ -51,10 +51,16 @@ func (m *orgMemberStore) Create(ctx context.Context, orgID, userID int32) (*type
 		OrgID:  orgID,
 		UserID: userID,
 	}
-	err := m.Handle().QueryRowContext(
-		ctx,
-		"INSERT INTO org_members(org_id, user_id) VALUES($1, $2) RETURNING id, created_at, updated_at",
-		om.OrgID, om.UserID).Scan(&om.ID, &om.CreatedAt, &om.UpdatedAt)
+	err := m.Store.WithTransact(ctx, func(tx *basestore.Store) error {
+		err := tx.Handle().QueryRowContext(
+			ctx,
+			"INSERT INTO org_members(org_id, user_id) VALUES($1, $2) RETURNING id, created_at, updated_at",
+			om.OrgID, om.UserID).Scan(&om.ID, &om.CreatedAt, &om.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		return recordOrgMemberEvent(ctx, tx, types.AuditLogActionOrgMemberAdded, orgID, userID)
+	})
 	if err != nil {
 		var e *pgconn.PgError
 		if errors.As(err, &e) && e.ConstraintName == "org_members_org_id_user_id_key" {
@@ -65,6 +71,18 @@ func (m *orgMemberStore) Create(ctx context.Context, orgID, userID int32) (*type
 	return &om, nil
 }
 
+func recordOrgMemberEvent(ctx context.Context, store basestore.ShareableStore, action string, orgID, userID int32) error {
+	return AuditLogEventsWith(store).Record(ctx, AuditLogEventArgs{
+		Action:     action,
+		EntityType: "org_member",
+		EntityID:   fmt.Sprintf("%d:%d", orgID, userID),
+		OrgID:      &orgID,
+		Argument: struct {
+			UserID int32 `json:"userID"`
+		}{userID},
+	})
+}
+
 func (m *orgMemberStore) GetByUserID(ctx context.Context, userID int32) ([]*types.OrgMembership, error) {
 	return m.getBySQL(ctx, "INNER JOIN users ON org_members.user_id=users.id WHERE org_members.user_id=$1 AND users.deleted_at IS NULL", userID)
 }
@@ -86,8 +104,16 @@ func (m *orgMemberStore) MemberCount(ctx context.Context, orgID int32) (int, err
 }
 
 func (m *orgMemberStore) Remove(ctx context.Context, orgID, userID int32) error {
-	_, err := m.Handle().ExecContext(ctx, "DELETE FROM org_members WHERE (org_id=$1 AND user_id=$2)", orgID, userID)
-	return err
+	return m.Store.WithTransact(ctx, func(tx *basestore.Store) error {
+		res, err := tx.Handle().ExecContext(ctx, "DELETE FROM org_members WHERE (org_id=$1 AND user_id=$2)", orgID, userID)
+		if err != nil {
+			return err
+		}
+		if removed, err := res.RowsAffected(); err != nil || removed == 0 {
+			return err
+		}
+		return recordOrgMemberEvent(ctx, tx, types.AuditLogActionOrgMemberRemoved, orgID, userID)
+	})
 }
 
 // GetByOrgID returns a list of all members of a given organization.
@@ -22,6 +22,7 @@ type OutboundWebhookLogStore interface {
 
 	CountsForOutboundWebhook(ctx context.Context, outboundWebhookID int64) (total, errored int64, err error)
 	Create(context.Context, *types.OutboundWebhookLog) error
+	DeliveriesForJob(ctx context.Context, jobID int64) (map[int64]OutboundWebhookDelivery, error)
 	ListForOutboundWebhook(ctx context.Context, opts OutboundWebhookLogListOpts) ([]*types.OutboundWebhookLog, error)
 }
 
@@ -46,6 +47,15 @@ func (opts *OutboundWebhookLogListOpts) where() *sqlf.Query {
 	return sqlf.Join(preds, "AND")
 }
 
+// OutboundWebhookDelivery summarises the attempts to deliver the payload of an
+// outbound webhook job to a single outbound webhook.
+type OutboundWebhookDelivery struct {
+	// Attempts is the number of times the payload was sent.
+	Attempts int
+	// Delivered is true if any attempt got a response in the range [100, 399].
+	Delivered bool
+}
+
 type outboundWebhookLogStore struct {
 	*basestore.Store
 	key encryption.Key
@@ -120,6 +130,28 @@ func (s *outboundWebhookLogStore) Create(ctx context.Context, log *types.Outboun
 	return nil
 }
 
+func (s *outboundWebhookLogStore) DeliveriesForJob(ctx context.Context, jobID int64) (map[int64]OutboundWebhookDelivery, error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(outboundWebhookLogDeliveriesForJobQueryFmtstr, jobID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := map[int64]OutboundWebhookDelivery{}
+	for rows.Next() {
+		var (
+			outboundWebhookID int64
+			delivery          OutboundWebhookDelivery
+		)
+		if err := rows.Scan(&outboundWebhookID, &delivery.Attempts, &delivery.Delivered); err != nil {
+			return nil, err
+		}
+		deliveries[outboundWebhookID] = delivery
+	}
+
+	return deliveries, rows.Err()
+}
+
 func (s *outboundWebhookLogStore) ListForOutboundWebhook(ctx context.Context, opts OutboundWebhookLogListOpts) ([]*types.OutboundWebhookLog, error) {
 	q := sqlf.Sprintf(
 		outboundWebhookLogListForOutboundWebhookQueryFmtstr,
@@ -214,6 +246,20 @@ VALUES (%s, %s, %s, %s, %s, %s, %s)
 RETURNING %s
 `
 
+const outboundWebhookLogDeliveriesForJobQueryFmtstr = `
+-- source: internal/database/outbound_webhook_logs.go:DeliveriesForJob
+SELECT
+	outbound_webhook_id,
+	COUNT(*) AS attempts,
+	COALESCE(BOOL_OR(status_code BETWEEN 100 AND 399), FALSE) AS delivered
+FROM
+	outbound_webhook_logs
+WHERE
+	job_id = %s
+GROUP BY
+	outbound_webhook_id
+`
+
 const outboundWebhookLogListForOutboundWebhookQueryFmtstr = `
 -- source: internal/database/outbound_webhook_logs.go:ListForOutboundWebhook
 SELECT
@@ -95,6 +95,22 @@ func TestOutboundWebhookLogs(t *testing.T) {
 			})
 		})
 
+		t.Run("DeliveriesForJob", func(t *testing.T) {
+			t.Run("missing ID", func(t *testing.T) {
+				deliveries, err := store.DeliveriesForJob(ctx, 0)
+				assert.NoError(t, err)
+				assert.Empty(t, deliveries)
+			})
+
+			t.Run("valid ID", func(t *testing.T) {
+				deliveries, err := store.DeliveriesForJob(ctx, job.ID)
+				assert.NoError(t, err)
+				assert.Equal(t, map[int64]OutboundWebhookDelivery{
+					webhook.ID: {Attempts: 3, Delivered: true},
+				}, deliveries)
+			})
+		})
+
 		t.Run("ListForOutboundWebhook", func(t *testing.T) {
 			for name, tc := range map[string]struct {
 				opts OutboundWebhookLogListOpts
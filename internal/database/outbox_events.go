@@ -0,0 +1,201 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// OutboxEventStore records events in the transactional outbox, and tracks the
+// delivery of those events to consumer groups.
+//
+// Events published within a transaction become visible to consumers only once
+// the transaction commits, and are discarded if it rolls back. Consumer groups
+// read events in the order of the publishing transaction IDs, and only once all
+// older transactions have finished, so that events committed out of order are
+// never skipped.
+type OutboxEventStore interface {
+	basestore.ShareableStore
+	With(basestore.ShareableStore) OutboxEventStore
+
+	// Publish records the given events in the outbox. It must be called with a
+	// store sharing the transaction that commits the changes the events describe.
+	Publish(ctx context.Context, events ...OutboxEventArgs) error
+
+	// LockConsumerGroup returns the offset of the given consumer group, creating
+	// it if it does not exist, and locks it until the end of the transaction.
+	// The returned boolean is false if the group is locked by another transaction.
+	LockConsumerGroup(ctx context.Context, consumerGroup string) (OutboxOffset, bool, error)
+
+	// ListAfter returns up to limit events after the given offset, in delivery
+	// order. Events of transactions that may still be in progress are excluded.
+	ListAfter(ctx context.Context, offset OutboxOffset, limit int) ([]*types.OutboxEvent, error)
+
+	// AdvanceConsumerGroup records that the given consumer group has handled all
+	// events up to the given offset.
+	AdvanceConsumerGroup(ctx context.Context, consumerGroup string, offset OutboxOffset) error
+
+	// DeleteConsumedBefore deletes events created before the given time that were
+	// handled by every consumer group, and returns the number of deleted events.
+	DeleteConsumedBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+// OutboxEventArgs describes an event to publish to the outbox.
+type OutboxEventArgs struct {
+	EventType     string
+	AggregateType string
+	AggregateID   string
+	// Payload is marshalled to JSON.
+	Payload any
+}
+
+// OutboxOffset is the position of a consumer group in the outbox: the
+// transaction ID and event ID of the last handled event.
+type OutboxOffset struct {
+	TxID    int64
+	EventID int64
+}
+
+// OffsetOf returns the offset of the given event.
+func OffsetOf(event *types.OutboxEvent) OutboxOffset {
+	return OutboxOffset{TxID: event.TxID, EventID: event.ID}
+}
+
+type outboxEventStore struct {
+	*basestore.Store
+}
+
+// OutboxEventsWith instantiates and returns a new OutboxEventStore using the
+// other store handle.
+func OutboxEventsWith(other basestore.ShareableStore) OutboxEventStore {
+	return &outboxEventStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *outboxEventStore) With(other basestore.ShareableStore) OutboxEventStore {
+	return &outboxEventStore{Store: s.Store.With(other)}
+}
+
+func (s *outboxEventStore) Publish(ctx context.Context, events ...OutboxEventArgs) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	values := make([]*sqlf.Query, 0, len(events))
+	for _, event := range events {
+		payload, err := json.Marshal(event.Payload)
+		if err != nil {
+			return err
+		}
+		values = append(values, sqlf.Sprintf("(%s, %s, %s, %s)", event.EventType, event.AggregateType, event.AggregateID, string(payload)))
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(publishOutboxEventsQuery, sqlf.Join(values, ", ")))
+}
+
+const publishOutboxEventsQuery = `
+INSERT INTO outbox_events (event_type, aggregate_type, aggregate_id, payload)
+VALUES %s
+`
+
+func (s *outboxEventStore) LockConsumerGroup(ctx context.Context, consumerGroup string) (OutboxOffset, bool, error) {
+	if err := s.Exec(ctx, sqlf.Sprintf(createOutboxConsumerGroupQuery, consumerGroup)); err != nil {
+		return OutboxOffset{}, false, err
+	}
+
+	var offset OutboxOffset
+	if err := s.QueryRow(ctx, sqlf.Sprintf(lockOutboxConsumerGroupQuery, consumerGroup)).Scan(&offset.TxID, &offset.EventID); err != nil {
+		if err == sql.ErrNoRows {
+			return OutboxOffset{}, false, nil
+		}
+		return OutboxOffset{}, false, err
+	}
+
+	return offset, true, nil
+}
+
+const createOutboxConsumerGroupQuery = `
+INSERT INTO outbox_consumer_offsets (consumer_group)
+VALUES (%s)
+ON CONFLICT (consumer_group) DO NOTHING
+`
+
+const lockOutboxConsumerGroupQuery = `
+SELECT last_txid, last_event_id
+FROM outbox_consumer_offsets
+WHERE consumer_group = %s
+FOR UPDATE SKIP LOCKED
+`
+
+func (s *outboxEventStore) ListAfter(ctx context.Context, offset OutboxOffset, limit int) ([]*types.OutboxEvent, error) {
+	return scanOutboxEvents(s.Query(ctx, sqlf.Sprintf(listOutboxEventsAfterQuery, offset.TxID, offset.EventID, limit)))
+}
+
+// Transactions with an ID lower than the xmin of the current snapshot have
+// finished, so no event with a lower (txid, id) can be published later on.
+const listOutboxEventsAfterQuery = `
+SELECT id, txid, event_type, aggregate_type, aggregate_id, payload, created_at
+FROM outbox_events
+WHERE
+	(txid, id) > (%s, %s) AND
+	txid < txid_snapshot_xmin(txid_current_snapshot())
+ORDER BY txid, id
+LIMIT %s
+`
+
+func (s *outboxEventStore) AdvanceConsumerGroup(ctx context.Context, consumerGroup string, offset OutboxOffset) error {
+	return s.Exec(ctx, sqlf.Sprintf(advanceOutboxConsumerGroupQuery, offset.TxID, offset.EventID, consumerGroup))
+}
+
+const advanceOutboxConsumerGroupQuery = `
+UPDATE outbox_consumer_offsets
+SET last_txid = %s, last_event_id = %s, updated_at = now()
+WHERE consumer_group = %s
+`
+
+func (s *outboxEventStore) DeleteConsumedBefore(ctx context.Context, before time.Time) (int, error) {
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(deleteConsumedOutboxEventsQuery, before)))
+	return count, err
+}
+
+const deleteConsumedOutboxEventsQuery = `
+WITH deleted AS (
+	DELETE FROM outbox_events e
+	WHERE
+		e.created_at < %s AND
+		NOT EXISTS (
+			SELECT 1
+			FROM outbox_consumer_offsets o
+			WHERE (o.last_txid, o.last_event_id) < (e.txid, e.id)
+		)
+	RETURNING 1
+)
+SELECT COUNT(*) FROM deleted
+`
+
+var scanOutboxEvents = basestore.NewSliceScanner(scanOutboxEvent)
+
+func scanOutboxEvent(sc dbutil.Scanner) (*types.OutboxEvent, error) {
+	var event types.OutboxEvent
+	var payload []byte
+	if err := sc.Scan(
+		&event.ID,
+		&event.TxID,
+		&event.EventType,
+		&event.AggregateType,
+		&event.AggregateID,
+		&payload,
+		&event.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	event.Payload = payload
+
+	return &event, nil
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestOutboxEvents(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+	store := db.OutboxEvents()
+
+	userEvent := func(id int32) OutboxEventArgs {
+		return OutboxEventArgs{
+			EventType:     types.OutboxEventUserDeactivated,
+			AggregateType: "user",
+			AggregateID:   "1",
+			Payload:       map[string]int32{"user_id": id},
+		}
+	}
+
+	require.NoError(t, store.Publish(ctx, userEvent(1), userEvent(2)))
+
+	// Events of rolled back transactions are discarded.
+	rollback := errors.New("rollback")
+	err := db.WithTransact(ctx, func(tx DB) error {
+		require.NoError(t, tx.OutboxEvents().Publish(ctx, userEvent(3)))
+		return rollback
+	})
+	require.ErrorIs(t, err, rollback)
+
+	require.NoError(t, store.Publish(ctx, userEvent(4)))
+
+	listAfter := func(offset OutboxOffset, limit int) (payloads []string) {
+		events, err := store.ListAfter(ctx, offset, limit)
+		require.NoError(t, err)
+		for _, event := range events {
+			payloads = append(payloads, string(event.Payload))
+		}
+		return payloads
+	}
+	assert.Equal(t, []string{`{"user_id": 1}`, `{"user_id": 2}`, `{"user_id": 4}`}, listAfter(OutboxOffset{}, 10))
+
+	t.Run("consumer groups", func(t *testing.T) {
+		err := db.WithTransact(ctx, func(tx DB) error {
+			offset, ok, err := tx.OutboxEvents().LockConsumerGroup(ctx, "test")
+			require.NoError(t, err)
+			require.True(t, ok)
+			assert.Equal(t, OutboxOffset{}, offset)
+
+			events, err := tx.OutboxEvents().ListAfter(ctx, offset, 2)
+			require.NoError(t, err)
+			require.Len(t, events, 2)
+			return tx.OutboxEvents().AdvanceConsumerGroup(ctx, "test", OffsetOf(events[1]))
+		})
+		require.NoError(t, err)
+
+		err = db.WithTransact(ctx, func(tx DB) error {
+			offset, ok, err := tx.OutboxEvents().LockConsumerGroup(ctx, "test")
+			require.NoError(t, err)
+			require.True(t, ok)
+			assert.Equal(t, []string{`{"user_id": 4}`}, listAfter(offset, 10))
+
+			// The group is locked by this transaction.
+			_, ok, err = store.LockConsumerGroup(ctx, "test")
+			require.NoError(t, err)
+			assert.False(t, ok)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("delete consumed events", func(t *testing.T) {
+		// Events are kept until they are consumed by every group.
+		count, err := store.DeleteConsumedBefore(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, []string{`{"user_id": 4}`}, listAfter(OutboxOffset{}, 10))
+
+		// Events are kept until the retention period has passed.
+		count, err = store.DeleteConsumedBefore(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"strconv"
 	"strings"
 	"time"
@@ -96,6 +97,9 @@ var ReasonGroupToReasons = map[PermissionsSyncJobReasonGroup][]PermissionsSyncJo
 		ReasonGitHubOrgMemberRemovedEvent,
 		ReasonGitHubRepoEvent,
 		ReasonGitHubRepoMadePrivateEvent,
+		ReasonGitLabMemberAddedEvent,
+		ReasonGitLabMemberRemovedEvent,
+		ReasonGitLabMemberUpdatedEvent,
 	},
 	PermissionsSyncJobReasonGroupSchedule: {
 		ReasonUserOutdatedPermissions,
@@ -150,7 +154,10 @@ func (r PermissionsSyncJobReason) ResolveGroup() PermissionsSyncJobReasonGroup {
 		ReasonGitHubOrgMemberAddedEvent,
 		ReasonGitHubOrgMemberRemovedEvent,
 		ReasonGitHubRepoEvent,
-		ReasonGitHubRepoMadePrivateEvent:
+		ReasonGitHubRepoMadePrivateEvent,
+		ReasonGitLabMemberAddedEvent,
+		ReasonGitLabMemberRemovedEvent,
+		ReasonGitLabMemberUpdatedEvent:
 		return PermissionsSyncJobReasonGroupWebhook
 	case ReasonUserOutdatedPermissions,
 		ReasonUserNoPermissions,
@@ -205,6 +212,9 @@ const (
 	ReasonGitHubOrgMemberRemovedEvent      PermissionsSyncJobReason = "REASON_GITHUB_ORG_MEMBER_REMOVED_EVENT"
 	ReasonGitHubRepoEvent                  PermissionsSyncJobReason = "REASON_GITHUB_REPO_EVENT"
 	ReasonGitHubRepoMadePrivateEvent       PermissionsSyncJobReason = "REASON_GITHUB_REPO_MADE_PRIVATE_EVENT"
+	ReasonGitLabMemberAddedEvent           PermissionsSyncJobReason = "REASON_GITLAB_MEMBER_ADDED_EVENT"
+	ReasonGitLabMemberRemovedEvent         PermissionsSyncJobReason = "REASON_GITLAB_MEMBER_REMOVED_EVENT"
+	ReasonGitLabMemberUpdatedEvent         PermissionsSyncJobReason = "REASON_GITLAB_MEMBER_UPDATED_EVENT"
 
 	// ReasonManualRepoSync and below are reasons of permission syncs triggered
 	// manually.
@@ -219,6 +229,11 @@ type PermissionSyncJobOpts struct {
 	Reason            PermissionsSyncJobReason
 	TriggeredByUserID int32
 	NoPerms           bool
+	// Coalesce merges a delayed job into a queued delayed job for the same user or
+	// repository, if any, instead of inserting it. The queued job is postponed to
+	// the later ProcessAfter of both jobs, so that bursts of events result in a
+	// single sync after the last one.
+	Coalesce bool
 }
 
 type PermissionSyncJobStore interface {
@@ -280,7 +295,7 @@ func (s *permissionSyncJobStore) CreateUserSyncJob(ctx context.Context, user int
 	if !opts.ProcessAfter.IsZero() {
 		job.ProcessAfter = opts.ProcessAfter
 	}
-	return s.createSyncJob(ctx, job)
+	return s.createSyncJob(ctx, job, opts.Coalesce)
 }
 
 func (s *permissionSyncJobStore) CreateRepoSyncJob(ctx context.Context, repo api.RepoID, opts PermissionSyncJobOpts) error {
@@ -295,7 +310,7 @@ func (s *permissionSyncJobStore) CreateRepoSyncJob(ctx context.Context, repo api
 	if !opts.ProcessAfter.IsZero() {
 		job.ProcessAfter = opts.ProcessAfter
 	}
-	return s.createSyncJob(ctx, job)
+	return s.createSyncJob(ctx, job, opts.Coalesce)
 }
 
 const permissionSyncJobCreateQueryFmtstr = `
@@ -324,15 +339,70 @@ RETURNING %s
 `
 
 // createSyncJob inserts a postponed (`process_after IS NOT NULL`) sync job right
-// away and checks new sync jobs without provided delay for duplicates.
-func (s *permissionSyncJobStore) createSyncJob(ctx context.Context, job *PermissionSyncJob) error {
+// away, unless coalesce is set, and checks new sync jobs without provided delay
+// for duplicates.
+func (s *permissionSyncJobStore) createSyncJob(ctx context.Context, job *PermissionSyncJob, coalesce bool) error {
 	if job.ProcessAfter.IsZero() {
 		// sync jobs without delay are checked for duplicates
 		return s.checkDuplicateAndCreateSyncJob(ctx, job)
 	}
+	if coalesce {
+		return s.coalesceOrCreateSyncJob(ctx, job)
+	}
 	return s.create(ctx, job)
 }
 
+const permissionSyncJobCoalesceQueryFmtstr = `
+UPDATE permission_sync_jobs
+SET
+	process_after = GREATEST(process_after, %s),
+	priority = GREATEST(priority, %s),
+	invalidate_caches = invalidate_caches OR %s
+WHERE id = (
+	SELECT id
+	FROM permission_sync_jobs
+	WHERE
+		%s
+		AND state = 'queued'
+		AND cancel IS FALSE
+		AND process_after IS NOT NULL
+	ORDER BY id DESC
+	LIMIT 1
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING %s
+`
+
+// coalesceOrCreateSyncJob merges the postponed job into the latest queued
+// postponed job for the same user/repo ID, or inserts it if there is none.
+func (s *permissionSyncJobStore) coalesceOrCreateSyncJob(ctx context.Context, job *PermissionSyncJob) (err error) {
+	tx, err := s.transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = tx.Done(err)
+	}()
+
+	cond := sqlf.Sprintf("user_id = %s", job.UserID)
+	if job.RepositoryID != 0 {
+		cond = sqlf.Sprintf("repository_id = %s", job.RepositoryID)
+	}
+	q := sqlf.Sprintf(
+		permissionSyncJobCoalesceQueryFmtstr,
+		job.ProcessAfter,
+		job.Priority,
+		job.InvalidateCaches,
+		cond,
+		sqlf.Join(PermissionSyncJobColumns, ", "),
+	)
+	err = scanPermissionSyncJob(job, tx.QueryRow(ctx, q))
+	if errors.Is(err, sql.ErrNoRows) {
+		return tx.create(ctx, job)
+	}
+	return err
+}
+
 func (s *permissionSyncJobStore) create(ctx context.Context, job *PermissionSyncJob) error {
 	q := sqlf.Sprintf(
 		permissionSyncJobCreateQueryFmtstr,
@@ -547,6 +547,62 @@ func TestPermissionSyncJobs_Deduplication(t *testing.T) {
 	require.Len(t, allUser1Jobs, 5)
 }
 
+func TestPermissionSyncJobs_Coalesce(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	clock := timeutil.NewFakeClock(time.Now(), 0)
+
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	user, err := db.Users().Create(ctx, NewUser{Username: "horse"})
+	require.NoError(t, err)
+
+	store := PermissionSyncJobsWith(logger, db)
+
+	oneMinuteLater := clock.Now().Add(time.Minute).Truncate(time.Microsecond)
+	fiveMinutesLater := clock.Now().Add(5 * time.Minute).Truncate(time.Microsecond)
+
+	// 1) The first coalesced job is inserted.
+	err = store.CreateUserSyncJob(ctx, user.ID, PermissionSyncJobOpts{ProcessAfter: fiveMinutesLater, Reason: ReasonGitLabMemberAddedEvent, Coalesce: true})
+	require.NoError(t, err)
+
+	// 2) Later coalesced jobs are merged into it, postponing it and keeping the
+	// highest priority and cache invalidation.
+	err = store.CreateUserSyncJob(ctx, user.ID, PermissionSyncJobOpts{ProcessAfter: oneMinuteLater, Priority: HighPriorityPermissionsSync, Reason: ReasonGitLabMemberRemovedEvent, Coalesce: true})
+	require.NoError(t, err)
+	err = store.CreateUserSyncJob(ctx, user.ID, PermissionSyncJobOpts{ProcessAfter: oneMinuteLater, InvalidateCaches: true, Reason: ReasonGitLabMemberRemovedEvent, Coalesce: true})
+	require.NoError(t, err)
+
+	jobs, err := store.List(ctx, ListPermissionSyncJobOpts{UserID: int(user.ID)})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.True(t, fiveMinutesLater.Equal(jobs[0].ProcessAfter), "want process_after %s, got %s", fiveMinutesLater, jobs[0].ProcessAfter)
+	require.Equal(t, HighPriorityPermissionsSync, jobs[0].Priority)
+	require.True(t, jobs[0].InvalidateCaches)
+
+	// 3) Jobs that are no longer queued are not coalesced.
+	_, err = db.ExecContext(ctx, "UPDATE permission_sync_jobs SET state='processing' WHERE id=$1", jobs[0].ID)
+	require.NoError(t, err)
+
+	err = store.CreateUserSyncJob(ctx, user.ID, PermissionSyncJobOpts{ProcessAfter: oneMinuteLater, Reason: ReasonGitLabMemberAddedEvent, Coalesce: true})
+	require.NoError(t, err)
+
+	jobs, err = store.List(ctx, ListPermissionSyncJobOpts{UserID: int(user.ID)})
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+
+	// 4) Jobs without Coalesce are always inserted.
+	err = store.CreateUserSyncJob(ctx, user.ID, PermissionSyncJobOpts{ProcessAfter: oneMinuteLater, Reason: ReasonManualUserSync})
+	require.NoError(t, err)
+
+	jobs, err = store.List(ctx, ListPermissionSyncJobOpts{UserID: int(user.ID)})
+	require.NoError(t, err)
+	require.Len(t, jobs, 3)
+}
+
 func TestPermissionSyncJobs_CancelQueuedJob(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
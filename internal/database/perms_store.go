@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -43,6 +44,10 @@ type PermsStore interface {
 	// LoadUserPermissions returns user permissions. An empty slice
 	// is returned when there are no valid permissions available.
 	LoadUserPermissions(ctx context.Context, userID int32) (p []authz.Permission, err error)
+	// LoadUserRepoPermissions returns the permissions of the user on the
+	// repository, one for each external account that granted access. An empty
+	// slice is returned when the user has no permissions on the repository.
+	LoadUserRepoPermissions(ctx context.Context, userID int32, repoID api.RepoID) ([]authz.Permission, error)
 	// FetchReposByExternalAccount fetches repo ids that the originate from the given external account.
 	FetchReposByExternalAccount(ctx context.Context, accountID int32) ([]api.RepoID, error)
 	// LoadRepoPermissions returns stored repository permissions.
@@ -245,6 +250,19 @@ func (s *permsStore) LoadUserPermissions(ctx context.Context, userID int32) (p [
 	return s.loadUserRepoPermissions(ctx, userID, 0, 0)
 }
 
+func (s *permsStore) LoadUserRepoPermissions(ctx context.Context, userID int32, repoID api.RepoID) (p []authz.Permission, err error) {
+	ctx, save := s.observe(ctx, "LoadUserRepoPermissions")
+	defer func() {
+		tracingFields := []attribute.KeyValue{}
+		for _, perm := range p {
+			tracingFields = append(tracingFields, perm.Attrs()...)
+		}
+		save(&err, tracingFields...)
+	}()
+
+	return s.loadUserRepoPermissions(ctx, userID, 0, int32(repoID))
+}
+
 var scanRepoIDs = basestore.NewSliceScanner(basestore.ScanAny[api.RepoID])
 
 func (s *permsStore) FetchReposByExternalAccount(ctx context.Context, accountID int32) (ids []api.RepoID, err error) {
@@ -328,7 +346,33 @@ func (s *permsStore) SetRepoPerms(ctx context.Context, repoID int32, userIDs []a
 		RepoID: repoID,
 	}
 
-	return s.setUserRepoPermissions(ctx, p, entity, source, true)
+	// Permissions set through the API override the ones synced from code
+	// hosts, so they are audited.
+	if source != authz.SourceAPI {
+		return s.setUserRepoPermissions(ctx, p, entity, source, true)
+	}
+
+	var result *SetPermissionsResult
+	err := s.Store.WithTransact(ctx, func(tx *basestore.Store) (err error) {
+		result, err = s.With(tx).(*permsStore).setUserRepoPermissions(ctx, p, entity, source, true)
+		if err != nil {
+			return err
+		}
+
+		userIDs := make([]int32, 0, len(p))
+		for _, perm := range p {
+			userIDs = append(userIDs, perm.UserID)
+		}
+		return AuditLogEventsWith(tx).Record(ctx, AuditLogEventArgs{
+			Action:     types.AuditLogActionRepoPermissionsSet,
+			EntityType: "repo",
+			EntityID:   strconv.Itoa(int(repoID)),
+			Argument: struct {
+				UserIDs []int32 `json:"userIDs"`
+			}{userIDs},
+		})
+	})
+	return result, err
 }
 
 // setUserRepoPermissions performs a full update for p, new rows for pairs of user_id, repo_id
@@ -617,9 +661,29 @@ func (s *permsStore) SetRepoPermissionsUnrestricted(ctx context.Context, ids []i
 	}
 
 	if !unrestricted {
-		return txs.unsetRepoPermissionsUnrestricted(ctx, ids)
+		err = txs.unsetRepoPermissionsUnrestricted(ctx, ids)
+	} else {
+		err = txs.setRepoPermissionsUnrestricted(ctx, ids)
+	}
+	if err != nil {
+		return err
 	}
-	return txs.setRepoPermissionsUnrestricted(ctx, ids)
+
+	auditLog := AuditLogEventsWith(txs)
+	for _, id := range ids {
+		err = auditLog.Record(ctx, AuditLogEventArgs{
+			Action:     types.AuditLogActionRepoPermissionsUnrestricted,
+			EntityType: "repo",
+			EntityID:   strconv.Itoa(int(id)),
+			Argument: struct {
+				Unrestricted bool `json:"unrestricted"`
+			}{unrestricted},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *permsStore) unsetRepoPermissionsUnrestricted(ctx context.Context, ids []int32) error {
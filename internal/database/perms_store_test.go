@@ -174,6 +174,44 @@ func TestPermsStore_LoadUserPermissions(t *testing.T) {
 	})
 }
 
+func TestPermsStore_LoadUserRepoPermissions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	testDb := dbtest.NewDB(logger, t)
+	db := NewDB(logger, testDb)
+	ctx := context.Background()
+
+	s := perms(logger, db, clock)
+	t.Cleanup(func() {
+		cleanupPermsTables(t, s)
+		cleanupUsersTable(t, s)
+		cleanupReposTable(t, s)
+	})
+
+	setupPermsRelatedEntities(t, s, []authz.Permission{{UserID: 1, RepoID: 1}, {UserID: 1, RepoID: 2}, {UserID: 2, RepoID: 1}})
+
+	if _, err := s.SetRepoPerms(ctx, 1, []authz.UserIDWithExternalAccountID{{UserID: 1}, {UserID: 2}}, authz.SourceRepoSync); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SetRepoPerms(ctx, 2, []authz.UserIDWithExternalAccountID{{UserID: 2}}, authz.SourceRepoSync); err != nil {
+		t.Fatal(err)
+	}
+
+	up, err := s.LoadUserRepoPermissions(ctx, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, up, 1)
+	equal(t, "UserID", int32(1), up[0].UserID)
+	equal(t, "RepoID", int32(1), up[0].RepoID)
+	equal(t, "Source", authz.SourceRepoSync, up[0].Source)
+
+	up, err = s.LoadUserRepoPermissions(ctx, 1, 2)
+	require.NoError(t, err)
+	equal(t, "No permissions", 0, len(up))
+}
+
 func TestPermsStore_LoadRepoPermissions(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
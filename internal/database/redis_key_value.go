@@ -3,9 +3,12 @@ package database
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/keegancsmith/sqlf"
+
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -19,8 +22,9 @@ type RedisKeyValueStore interface {
 	basestore.ShareableStore
 	WithTransact(context.Context, func(RedisKeyValueStore) error) error
 	Get(ctx context.Context, namespace, key string) (value []byte, ok bool, err error)
-	Set(ctx context.Context, namespace, key string, value []byte) (err error)
+	Set(ctx context.Context, namespace, key string, value []byte, expiresAt time.Time) (err error)
 	Delete(ctx context.Context, namespace, key string) (err error)
+	DeleteExpired(ctx context.Context) (deleted int, err error)
 }
 
 type redisKeyValueStore struct {
@@ -37,7 +41,14 @@ func (f *redisKeyValueStore) WithTransact(ctx context.Context, fn func(RedisKeyV
 
 func (s *redisKeyValueStore) Get(ctx context.Context, namespace, key string) ([]byte, bool, error) {
 	// redispool will often follow up a Get with a Set (eg for implementing
-	// redis INCR). As such we need to lock the row with FOR UPDATE.
+	// redis INCR). As such we need to lock the row with FOR UPDATE. The row
+	// may not exist yet, so we additionally take an advisory lock on the key.
+	// This serializes updates of the same key across processes, which is
+	// needed when postgres replaces redis for all services.
+	if err := s.Exec(ctx, sqlf.Sprintf(`SELECT pg_advisory_xact_lock(hashtext(%s), hashtext(%s))`, namespace, key)); err != nil {
+		return nil, false, err
+	}
+
 	q := sqlf.Sprintf(`
 	SELECT value FROM redis_key_value
 	WHERE namespace = %s AND key = %s
@@ -56,7 +67,7 @@ func (s *redisKeyValueStore) Get(ctx context.Context, namespace, key string) ([]
 	}
 }
 
-func (s *redisKeyValueStore) Set(ctx context.Context, namespace, key string, value []byte) error {
+func (s *redisKeyValueStore) Set(ctx context.Context, namespace, key string, value []byte, expiresAt time.Time) error {
 	// value schema does not allow null, nor do we need to preserve nil. So
 	// convert to empty string for robustness. This invariant is documented in
 	// redispool.DBStore and enforced by tests.
@@ -65,10 +76,10 @@ func (s *redisKeyValueStore) Set(ctx context.Context, namespace, key string, val
 	}
 
 	q := sqlf.Sprintf(`
-	INSERT INTO redis_key_value (namespace, key, value)
-	VALUES (%s, %s, %s)
-	ON CONFLICT (namespace, key) DO UPDATE SET value = EXCLUDED.value
-	`, namespace, key, value)
+	INSERT INTO redis_key_value (namespace, key, value, expires_at)
+	VALUES (%s, %s, %s, %s)
+	ON CONFLICT (namespace, key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at
+	`, namespace, key, value, dbutil.NullTimeColumn(expiresAt))
 	return s.Exec(ctx, q)
 }
 
@@ -79,3 +90,14 @@ func (s *redisKeyValueStore) Delete(ctx context.Context, namespace, key string)
 	`, namespace, key)
 	return s.Exec(ctx, q)
 }
+
+// DeleteExpired deletes the values of all namespaces that have expired. Reads
+// already ignore expired values, so this only reclaims their storage.
+func (s *redisKeyValueStore) DeleteExpired(ctx context.Context) (int, error) {
+	res, err := s.ExecResult(ctx, sqlf.Sprintf(`DELETE FROM redis_key_value WHERE expires_at < NOW()`))
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
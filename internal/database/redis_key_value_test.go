@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/log/logtest"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
@@ -37,11 +38,11 @@ func TestRedisKeyValue(t *testing.T) {
 
 	// get on missing, set, then get works
 	requireMissing("namespace", "key")
-	require.NoError(kv.Set(ctx, "namespace", "key", []byte("value")))
+	require.NoError(kv.Set(ctx, "namespace", "key", []byte("value"), time.Time{}))
 	requireValue("namespace", "key", "value")
 
 	// set on existing key updates it
-	require.NoError(kv.Set(ctx, "namespace", "key", []byte("horsegraph")))
+	require.NoError(kv.Set(ctx, "namespace", "key", []byte("horsegraph"), time.Time{}))
 	requireValue("namespace", "key", "horsegraph")
 
 	// delete makes the following get missing
@@ -53,12 +54,22 @@ func TestRedisKeyValue(t *testing.T) {
 
 	// test binary data
 	binary := string([]byte{0, 1, 0}) // use string to ensure we don't mutate in Set.
-	require.NoError(kv.Set(ctx, "namespace", "binary", []byte(binary)))
+	require.NoError(kv.Set(ctx, "namespace", "binary", []byte(binary), time.Time{}))
 	requireValue("namespace", "binary", binary)
 
 	// nil should be treated like an empty slice
-	require.NoError(kv.Set(ctx, "namespace", "nil", nil))
-	require.NoError(kv.Set(ctx, "namespace", "empty", []byte{}))
+	require.NoError(kv.Set(ctx, "namespace", "nil", nil, time.Time{}))
+	require.NoError(kv.Set(ctx, "namespace", "empty", []byte{}, time.Time{}))
 	requireValue("namespace", "nil", "")
 	requireValue("namespace", "empty", "")
+
+	// only expired values are deleted
+	require.NoError(kv.Set(ctx, "namespace", "expired", []byte("value"), time.Now().Add(-time.Minute)))
+	require.NoError(kv.Set(ctx, "namespace", "unexpired", []byte("value"), time.Now().Add(time.Hour)))
+	deleted, err := kv.DeleteExpired(ctx)
+	require.NoError(err)
+	require.Equal(1, deleted)
+	requireMissing("namespace", "expired")
+	requireValue("namespace", "unexpired", "value")
+	requireValue("namespace", "empty", "")
 }
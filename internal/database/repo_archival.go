@@ -0,0 +1,379 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// RepoArchivalPolicy is the site-wide policy that archives repositories that
+// have been inactive for a number of months. Archived repositories are excluded
+// from search by default, and, depending on the policy, are synced less often
+// and have their embeddings and code intelligence data dropped.
+type RepoArchivalPolicy struct {
+	Enabled             bool
+	InactiveMonths      int32
+	ReduceSyncFrequency bool
+	DropEmbeddings      bool
+	DropCodeIntel       bool
+	UpdatedBy           *int32
+	UpdatedAt           time.Time
+}
+
+// RepoArchivalOverride overrides the archival policy for a single repository.
+type RepoArchivalOverride string
+
+const (
+	// RepoArchivalOverrideNever prevents the repository from being archived.
+	RepoArchivalOverrideNever RepoArchivalOverride = "never"
+	// RepoArchivalOverrideAlways archives the repository regardless of its
+	// activity.
+	RepoArchivalOverrideAlways RepoArchivalOverride = "always"
+)
+
+// RepoArchival is the archival state of a repository. Repositories without a
+// RepoArchival have never been archived and have no override.
+type RepoArchival struct {
+	RepoID     api.RepoID
+	Override   RepoArchivalOverride
+	ArchivedAt *time.Time
+	RestoredAt *time.Time
+	UpdatedBy  *int32
+	UpdatedAt  time.Time
+}
+
+// Archived returns true if the repository is currently archived.
+func (a *RepoArchival) Archived() bool {
+	return a != nil && a.ArchivedAt != nil
+}
+
+type RepoArchivalStore interface {
+	basestore.ShareableStore
+	With(basestore.ShareableStore) RepoArchivalStore
+
+	// GetPolicy returns the archival policy.
+	GetPolicy(ctx context.Context) (*RepoArchivalPolicy, error)
+	// UpdatePolicy replaces the archival policy.
+	UpdatePolicy(ctx context.Context, policy RepoArchivalPolicy, updatedBy int32) (*RepoArchivalPolicy, error)
+
+	// Get returns the archival state of the repository, or nil if it has never
+	// been archived and has no override.
+	Get(ctx context.Context, repoID api.RepoID) (*RepoArchival, error)
+	// SetOverride sets the override of the repository. An empty override clears
+	// it.
+	SetOverride(ctx context.Context, repoID api.RepoID, override RepoArchivalOverride, updatedBy int32) (*RepoArchival, error)
+	// Archive marks the repositories as archived.
+	Archive(ctx context.Context, repoIDs ...api.RepoID) error
+	// Restore restores the repository if it is archived. Restored repositories
+	// are not archived again before they have been inactive for the period of
+	// the policy. An "always" override is cleared, as it would archive the
+	// repository again right away.
+	Restore(ctx context.Context, repoID api.RepoID, restoredBy int32) (*RepoArchival, error)
+
+	// ListArchivable returns up to limit repositories that should be archived:
+	// the repositories whose last change and last restoration are older than
+	// inactiveSince and the repositories with an "always" override.
+	ListArchivable(ctx context.Context, inactiveSince time.Time, limit int) ([]api.RepoID, error)
+	// ListReactivated returns the archived repositories that should be restored,
+	// because they changed after they were archived or they have a "never"
+	// override.
+	ListReactivated(ctx context.Context) ([]api.RepoID, error)
+	// ListArchivedIDs returns the IDs of all archived repositories.
+	ListArchivedIDs(ctx context.Context) ([]api.RepoID, error)
+	// LastUpdatedAt returns the time at which the archival state of a
+	// repository was last updated, or nil if no repository was ever archived
+	// or overridden. It is cheap to call and is used to find out whether the
+	// result of ListArchivedIDs may have changed.
+	LastUpdatedAt(ctx context.Context) (*time.Time, error)
+
+	// DeleteEmbeddings deletes the embedding jobs of the repositories, which
+	// makes their embeddings unavailable.
+	DeleteEmbeddings(ctx context.Context, repoIDs ...api.RepoID) error
+	// DeleteCodeIntelData marks the precise code intelligence uploads of the
+	// repositories for deletion.
+	DeleteCodeIntelData(ctx context.Context, repoIDs ...api.RepoID) error
+}
+
+type repoArchivalStore struct {
+	*basestore.Store
+}
+
+var _ RepoArchivalStore = (*repoArchivalStore)(nil)
+
+// RepoArchivalWith instantiates and returns a new RepoArchivalStore using the
+// other store handle.
+func RepoArchivalWith(other basestore.ShareableStore) RepoArchivalStore {
+	return &repoArchivalStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *repoArchivalStore) With(other basestore.ShareableStore) RepoArchivalStore {
+	return &repoArchivalStore{Store: s.Store.With(other)}
+}
+
+const repoArchivalPolicyColumns = `enabled, inactive_months, reduce_sync_frequency, drop_embeddings, drop_codeintel, updated_by, updated_at`
+
+const getRepoArchivalPolicyQuery = `
+SELECT ` + repoArchivalPolicyColumns + `
+FROM repo_archival_policy
+WHERE id = 1
+`
+
+func (s *repoArchivalStore) GetPolicy(ctx context.Context) (*RepoArchivalPolicy, error) {
+	return scanRepoArchivalPolicy(s.QueryRow(ctx, sqlf.Sprintf(getRepoArchivalPolicyQuery)))
+}
+
+const updateRepoArchivalPolicyQuery = `
+INSERT INTO repo_archival_policy (id, enabled, inactive_months, reduce_sync_frequency, drop_embeddings, drop_codeintel, updated_by, updated_at)
+VALUES (1, %s, %s, %s, %s, %s, %s, NOW())
+ON CONFLICT (id) DO UPDATE SET
+	enabled = EXCLUDED.enabled,
+	inactive_months = EXCLUDED.inactive_months,
+	reduce_sync_frequency = EXCLUDED.reduce_sync_frequency,
+	drop_embeddings = EXCLUDED.drop_embeddings,
+	drop_codeintel = EXCLUDED.drop_codeintel,
+	updated_by = EXCLUDED.updated_by,
+	updated_at = EXCLUDED.updated_at
+RETURNING ` + repoArchivalPolicyColumns + `
+`
+
+func (s *repoArchivalStore) UpdatePolicy(ctx context.Context, policy RepoArchivalPolicy, updatedBy int32) (*RepoArchivalPolicy, error) {
+	if policy.InactiveMonths <= 0 {
+		return nil, errors.New("the number of inactive months must be positive")
+	}
+	return scanRepoArchivalPolicy(s.QueryRow(ctx, sqlf.Sprintf(
+		updateRepoArchivalPolicyQuery,
+		policy.Enabled,
+		policy.InactiveMonths,
+		policy.ReduceSyncFrequency,
+		policy.DropEmbeddings,
+		policy.DropCodeIntel,
+		dbutil.NullInt32Column(updatedBy),
+	)))
+}
+
+const repoArchivalColumns = `repo_id, override, archived_at, restored_at, updated_by, updated_at`
+
+const getRepoArchivalQuery = `
+SELECT ` + repoArchivalColumns + `
+FROM repo_archival
+WHERE repo_id = %s
+`
+
+func (s *repoArchivalStore) Get(ctx context.Context, repoID api.RepoID) (*RepoArchival, error) {
+	archival, _, err := scanFirstRepoArchival(s.Query(ctx, sqlf.Sprintf(getRepoArchivalQuery, repoID)))
+	return archival, err
+}
+
+const setRepoArchivalOverrideQuery = `
+INSERT INTO repo_archival (repo_id, override, updated_by)
+VALUES (%s, %s, %s)
+ON CONFLICT (repo_id) DO UPDATE SET
+	override = EXCLUDED.override,
+	updated_by = EXCLUDED.updated_by,
+	updated_at = NOW()
+RETURNING ` + repoArchivalColumns + `
+`
+
+func (s *repoArchivalStore) SetOverride(ctx context.Context, repoID api.RepoID, override RepoArchivalOverride, updatedBy int32) (*RepoArchival, error) {
+	switch override {
+	case "", RepoArchivalOverrideNever, RepoArchivalOverrideAlways:
+	default:
+		return nil, errors.Errorf("invalid archival override %q", override)
+	}
+
+	archival, ok, err := scanFirstRepoArchival(s.Query(ctx, sqlf.Sprintf(
+		setRepoArchivalOverrideQuery,
+		repoID,
+		dbutil.NewNullString(string(override)),
+		dbutil.NullInt32Column(updatedBy),
+	)))
+	if err != nil {
+		if dbutil.IsPostgresError(err, "23503") {
+			return nil, errors.New("repository not found")
+		}
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("repository not found")
+	}
+	return archival, nil
+}
+
+const archiveReposQuery = `
+INSERT INTO repo_archival (repo_id, archived_at)
+SELECT id, NOW() FROM unnest(%s::integer[]) AS id
+ON CONFLICT (repo_id) DO UPDATE SET
+	archived_at = COALESCE(repo_archival.archived_at, EXCLUDED.archived_at),
+	updated_at = NOW()
+`
+
+func (s *repoArchivalStore) Archive(ctx context.Context, repoIDs ...api.RepoID) error {
+	if len(repoIDs) == 0 {
+		return nil
+	}
+	return s.Exec(ctx, sqlf.Sprintf(archiveReposQuery, pq.Array(repoIDs)))
+}
+
+const restoreRepoQuery = `
+UPDATE repo_archival
+SET
+	archived_at = NULL,
+	restored_at = CASE WHEN archived_at IS NULL THEN restored_at ELSE NOW() END,
+	override = NULLIF(override, 'always'),
+	updated_by = COALESCE(%s, updated_by),
+	updated_at = NOW()
+WHERE repo_id = %s
+RETURNING ` + repoArchivalColumns + `
+`
+
+func (s *repoArchivalStore) Restore(ctx context.Context, repoID api.RepoID, restoredBy int32) (*RepoArchival, error) {
+	archival, _, err := scanFirstRepoArchival(s.Query(ctx, sqlf.Sprintf(restoreRepoQuery, dbutil.NullInt32Column(restoredBy), repoID)))
+	return archival, err
+}
+
+const listArchivableReposQuery = `
+SELECT r.id
+FROM repo r
+JOIN gitserver_repos gr ON gr.repo_id = r.id
+LEFT JOIN repo_archival ra ON ra.repo_id = r.id
+WHERE
+	r.deleted_at IS NULL AND
+	ra.archived_at IS NULL AND
+	ra.override IS DISTINCT FROM 'never' AND
+	-- The data of repositories on legal hold must be kept.
+	NOT EXISTS (SELECT 1 FROM repo_legal_holds h WHERE h.repo_id = r.id) AND
+	(
+		ra.override = 'always' OR
+		(
+			-- Repositories that are not cloned yet have no meaningful last change.
+			gr.clone_status = 'cloned' AND
+			gr.last_changed < %s AND
+			COALESCE(ra.restored_at, '-infinity') < %s
+		)
+	)
+ORDER BY r.id
+LIMIT %s
+`
+
+func (s *repoArchivalStore) ListArchivable(ctx context.Context, inactiveSince time.Time, limit int) ([]api.RepoID, error) {
+	return scanRepoIDs(s.Query(ctx, sqlf.Sprintf(listArchivableReposQuery, inactiveSince, inactiveSince, limit)))
+}
+
+const listReactivatedReposQuery = `
+SELECT ra.repo_id
+FROM repo_archival ra
+JOIN gitserver_repos gr ON gr.repo_id = ra.repo_id
+WHERE
+	ra.archived_at IS NOT NULL AND
+	(
+		ra.override = 'never' OR
+		(ra.override IS NULL AND gr.last_changed > ra.archived_at)
+	)
+ORDER BY ra.repo_id
+`
+
+func (s *repoArchivalStore) ListReactivated(ctx context.Context) ([]api.RepoID, error) {
+	return scanRepoIDs(s.Query(ctx, sqlf.Sprintf(listReactivatedReposQuery)))
+}
+
+const listArchivedRepoIDsQuery = `
+SELECT repo_id
+FROM repo_archival
+WHERE archived_at IS NOT NULL
+ORDER BY repo_id
+`
+
+func (s *repoArchivalStore) ListArchivedIDs(ctx context.Context) ([]api.RepoID, error) {
+	return scanRepoIDs(s.Query(ctx, sqlf.Sprintf(listArchivedRepoIDsQuery)))
+}
+
+const repoArchivalLastUpdatedAtQuery = `
+SELECT MAX(updated_at)
+FROM repo_archival
+`
+
+func (s *repoArchivalStore) LastUpdatedAt(ctx context.Context) (*time.Time, error) {
+	updatedAt, _, err := basestore.ScanFirstNullTime(s.Query(ctx, sqlf.Sprintf(repoArchivalLastUpdatedAtQuery)))
+	return updatedAt, err
+}
+
+const deleteRepoEmbeddingJobsQuery = `
+DELETE FROM repo_embedding_jobs j
+WHERE
+	j.repo_id = ANY(%s) AND
+	-- The embeddings of repositories on legal hold must be kept.
+	NOT EXISTS (SELECT 1 FROM repo_legal_holds h WHERE h.repo_id = j.repo_id)
+`
+
+func (s *repoArchivalStore) DeleteEmbeddings(ctx context.Context, repoIDs ...api.RepoID) error {
+	if len(repoIDs) == 0 {
+		return nil
+	}
+	return s.Exec(ctx, sqlf.Sprintf(deleteRepoEmbeddingJobsQuery, pq.Array(repoIDs)))
+}
+
+const deleteRepoCodeIntelUploadsQuery = `
+WITH deleted AS (
+	UPDATE lsif_uploads u
+	SET state = CASE WHEN u.state = 'completed' THEN 'deleting' ELSE 'deleted' END
+	WHERE
+		u.repository_id = ANY(%s) AND
+		u.state NOT IN ('deleting', 'deleted') AND
+		-- The uploads of repositories on legal hold must be kept.
+		NOT EXISTS (SELECT 1 FROM repo_legal_holds h WHERE h.repo_id = u.repository_id)
+	RETURNING u.repository_id
+)
+INSERT INTO lsif_dirty_repositories (repository_id, dirty_token, update_token)
+SELECT DISTINCT repository_id, 1, 0 FROM deleted
+ON CONFLICT (repository_id) DO UPDATE SET
+	dirty_token = lsif_dirty_repositories.dirty_token + 1,
+	set_dirty_at = CASE
+		WHEN lsif_dirty_repositories.update_token = lsif_dirty_repositories.dirty_token THEN NOW()
+		ELSE lsif_dirty_repositories.set_dirty_at
+	END
+`
+
+func (s *repoArchivalStore) DeleteCodeIntelData(ctx context.Context, repoIDs ...api.RepoID) error {
+	if len(repoIDs) == 0 {
+		return nil
+	}
+	return s.WithTransact(ctx, func(tx *basestore.Store) error {
+		// The reason is recorded in the audit log of the uploads.
+		unset, err := tx.SetLocal(ctx, "codeintel.lsif_uploads_audit.reason", "repository archived")
+		if err != nil {
+			return err
+		}
+		defer unset(ctx)
+
+		// The repositories are marked as dirty so that their commit graph is
+		// updated, after which the uploads are removed by the janitor.
+		return tx.Exec(ctx, sqlf.Sprintf(deleteRepoCodeIntelUploadsQuery, pq.Array(repoIDs)))
+	})
+}
+
+func scanRepoArchivalPolicy(sc dbutil.Scanner) (*RepoArchivalPolicy, error) {
+	var p RepoArchivalPolicy
+	if err := sc.Scan(&p.Enabled, &p.InactiveMonths, &p.ReduceSyncFrequency, &p.DropEmbeddings, &p.DropCodeIntel, &p.UpdatedBy, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func scanRepoArchival(sc dbutil.Scanner) (*RepoArchival, error) {
+	var a RepoArchival
+	var override string
+	if err := sc.Scan(&a.RepoID, &dbutil.NullString{S: &override}, &a.ArchivedAt, &a.RestoredAt, &a.UpdatedBy, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	a.Override = RepoArchivalOverride(override)
+	return &a, nil
+}
+
+var scanFirstRepoArchival = basestore.NewFirstScanner(scanRepoArchival)
@@ -0,0 +1,178 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestRepoArchival(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+	archival := db.RepoArchival()
+
+	user, err := db.Users().Create(ctx, NewUser{Username: "admin"})
+	require.NoError(t, err)
+
+	now := time.Now()
+	yearAgo := now.AddDate(-1, 0, 0)
+	createRepo := func(name string, lastChanged time.Time) api.RepoID {
+		t.Helper()
+		repo := &types.Repo{Name: api.RepoName(name)}
+		require.NoError(t, db.Repos().Create(ctx, repo))
+		_, err := db.ExecContext(ctx, "UPDATE gitserver_repos SET clone_status = 'cloned', last_changed = $1 WHERE repo_id = $2", lastChanged, repo.ID)
+		require.NoError(t, err)
+		return repo.ID
+	}
+
+	inactive := createRepo("inactive", yearAgo)
+	active := createRepo("active", now)
+	pinned := createRepo("pinned", yearAgo)
+	forced := createRepo("forced", now)
+	held := createRepo("held", yearAgo)
+	_, err = db.RepoLegalHolds().Create(ctx, held, "litigation", user.ID)
+	require.NoError(t, err)
+
+	t.Run("policy", func(t *testing.T) {
+		policy, err := archival.GetPolicy(ctx)
+		require.NoError(t, err)
+		require.False(t, policy.Enabled)
+		require.Equal(t, int32(12), policy.InactiveMonths)
+		require.Nil(t, policy.UpdatedBy)
+
+		policy, err = archival.UpdatePolicy(ctx, RepoArchivalPolicy{Enabled: true, InactiveMonths: 6, DropCodeIntel: true}, user.ID)
+		require.NoError(t, err)
+		require.True(t, policy.Enabled)
+		require.Equal(t, int32(6), policy.InactiveMonths)
+		require.False(t, policy.DropEmbeddings)
+		require.True(t, policy.DropCodeIntel)
+		require.Equal(t, user.ID, *policy.UpdatedBy)
+
+		_, err = archival.UpdatePolicy(ctx, RepoArchivalPolicy{InactiveMonths: 0}, user.ID)
+		require.Error(t, err)
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		updatedAt, err := archival.LastUpdatedAt(ctx)
+		require.NoError(t, err)
+		require.Nil(t, updatedAt)
+
+		a, err := archival.Get(ctx, pinned)
+		require.NoError(t, err)
+		require.Nil(t, a)
+
+		a, err = archival.SetOverride(ctx, pinned, RepoArchivalOverrideNever, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, RepoArchivalOverrideNever, a.Override)
+		require.False(t, a.Archived())
+
+		_, err = archival.SetOverride(ctx, forced, RepoArchivalOverrideAlways, user.ID)
+		require.NoError(t, err)
+
+		_, err = archival.SetOverride(ctx, forced, "sometimes", user.ID)
+		require.Error(t, err)
+	})
+
+	t.Run("archive and restore", func(t *testing.T) {
+		ids, err := archival.ListArchivable(ctx, now.AddDate(0, -6, 0), 10)
+		require.NoError(t, err)
+		require.Equal(t, []api.RepoID{inactive, forced}, ids)
+
+		lastUpdatedAt, err := archival.LastUpdatedAt(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, lastUpdatedAt)
+
+		require.NoError(t, archival.Archive(ctx, ids...))
+		archived, err := archival.ListArchivedIDs(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []api.RepoID{inactive, forced}, archived)
+
+		// Archiving changes the last update time, which invalidates the cached
+		// sets of archived repositories.
+		updatedAt, err := archival.LastUpdatedAt(ctx)
+		require.NoError(t, err)
+		require.True(t, updatedAt.After(*lastUpdatedAt))
+
+		// Archived repositories are excluded from the repositories that are not
+		// archived.
+		repos, err := db.Repos().List(ctx, ReposListOptions{NoArchived: true})
+		require.NoError(t, err)
+		require.Len(t, repos, 3)
+		for _, repo := range repos {
+			require.Contains(t, []api.RepoID{active, pinned, held}, repo.ID)
+		}
+		repos, err = db.Repos().List(ctx, ReposListOptions{OnlyArchived: true})
+		require.NoError(t, err)
+		require.Len(t, repos, 2)
+
+		// Archived repositories are not archivable again.
+		ids, err = archival.ListArchivable(ctx, now.AddDate(0, -6, 0), 10)
+		require.NoError(t, err)
+		require.Empty(t, ids)
+
+		// Restoring clears the "always" override.
+		a, err := archival.Restore(ctx, forced, user.ID)
+		require.NoError(t, err)
+		require.False(t, a.Archived())
+		require.NotNil(t, a.RestoredAt)
+		require.Empty(t, a.Override)
+
+		// Restored repositories are not archived again until they have been
+		// inactive since their restoration.
+		ids, err = archival.ListArchivable(ctx, now.AddDate(0, -6, 0), 10)
+		require.NoError(t, err)
+		require.Empty(t, ids)
+	})
+
+	t.Run("reactivated", func(t *testing.T) {
+		ids, err := archival.ListReactivated(ctx)
+		require.NoError(t, err)
+		require.Empty(t, ids)
+
+		_, err = db.ExecContext(ctx, "UPDATE gitserver_repos SET last_changed = NOW() + interval '1 minute' WHERE repo_id = $1", inactive)
+		require.NoError(t, err)
+
+		ids, err = archival.ListReactivated(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []api.RepoID{inactive}, ids)
+	})
+
+	t.Run("drop data", func(t *testing.T) {
+		require.NoError(t, archival.DeleteEmbeddings(ctx, inactive))
+		require.NoError(t, archival.DeleteCodeIntelData(ctx, inactive))
+	})
+
+	t.Run("legal hold", func(t *testing.T) {
+		// Repositories on legal hold are never archivable, even when forced.
+		_, err := archival.SetOverride(ctx, held, RepoArchivalOverrideAlways, user.ID)
+		require.NoError(t, err)
+		ids, err := archival.ListArchivable(ctx, now.AddDate(0, -6, 0), 10)
+		require.NoError(t, err)
+		require.NotContains(t, ids, held)
+
+		_, err = db.ExecContext(ctx, "INSERT INTO repo_embedding_jobs (state, repo_id, revision) VALUES ('completed', $1, 'HEAD')", held)
+		require.NoError(t, err)
+		_, err = db.ExecContext(ctx, "INSERT INTO lsif_uploads (repository_id, commit, indexer, num_parts, uploaded_parts, state) VALUES ($1, $2, 'idx', 1, '{}', 'completed')", held, strings.Repeat("0", 40))
+		require.NoError(t, err)
+
+		// The data of repositories on legal hold is kept when it is dropped.
+		require.NoError(t, archival.DeleteEmbeddings(ctx, held))
+		require.NoError(t, archival.DeleteCodeIntelData(ctx, held))
+
+		var numJobs int
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM repo_embedding_jobs WHERE repo_id = $1", held).Scan(&numJobs))
+		require.Equal(t, 1, numJobs)
+		var state string
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT state FROM lsif_uploads WHERE repository_id = $1", held).Scan(&state))
+		require.Equal(t, "completed", state)
+	})
+}
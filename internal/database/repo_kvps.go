@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
@@ -25,6 +26,8 @@ type RepoKVPStore interface {
 	Create(context.Context, api.RepoID, KeyValuePair) error
 	Update(context.Context, api.RepoID, KeyValuePair) (KeyValuePair, error)
 	Delete(context.Context, api.RepoID, string) error
+	// Set replaces all the key-value pairs of the repo with the given ones.
+	Set(context.Context, api.RepoID, []KeyValuePair) error
 }
 type repoKVPStore struct {
 	*basestore.Store
@@ -179,6 +182,37 @@ func (s *repoKVPStore) Delete(ctx context.Context, repoID api.RepoID, key string
 	return s.Exec(ctx, sqlf.Sprintf(q, repoID, key))
 }
 
+func (s *repoKVPStore) Set(ctx context.Context, repoID api.RepoID, kvps []KeyValuePair) error {
+	keys := make([]string, 0, len(kvps))
+	for _, kvp := range kvps {
+		keys = append(keys, kvp.Key)
+	}
+
+	return s.Store.WithTransact(ctx, func(tx *basestore.Store) error {
+		q := `
+		DELETE FROM repo_kvps
+		WHERE repo_id = %s
+			AND NOT key = ANY(%s)
+		`
+		if err := tx.Exec(ctx, sqlf.Sprintf(q, repoID, pq.Array(keys))); err != nil {
+			return err
+		}
+
+		for _, kvp := range kvps {
+			q := `
+			INSERT INTO repo_kvps (repo_id, key, value)
+			VALUES (%s, %s, %s)
+			ON CONFLICT (repo_id, key) DO UPDATE
+			SET value = EXCLUDED.value
+			`
+			if err := tx.Exec(ctx, sqlf.Sprintf(q, repoID, kvp.Key, kvp.Value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func scanKVP(scanner dbutil.Scanner) (KeyValuePair, error) {
 	var kvp KeyValuePair
 	return kvp, scanner.Scan(&kvp.Key, &kvp.Value)
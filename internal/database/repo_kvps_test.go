@@ -207,6 +207,32 @@ func TestRepoKVPs(t *testing.T) {
 		})
 	})
 
+	t.Run("Set", func(t *testing.T) {
+		err := kvps.Set(ctx, repo.ID, []KeyValuePair{
+			{Key: "key1", Value: pointers.Ptr("value4")},
+			{Key: "tag1", Value: nil},
+		})
+		require.NoError(t, err)
+
+		kvp, err := kvps.Get(ctx, repo.ID, "key1")
+		require.NoError(t, err)
+		require.Equal(t, KeyValuePair{Key: "key1", Value: pointers.Ptr("value4")}, kvp)
+
+		kvp, err = kvps.Get(ctx, repo.ID, "tag1")
+		require.NoError(t, err)
+		require.Equal(t, KeyValuePair{Key: "tag1", Value: nil}, kvp)
+
+		err = kvps.Set(ctx, repo.ID, []KeyValuePair{{Key: "key1", Value: pointers.Ptr("value3")}})
+		require.NoError(t, err)
+
+		kvp, err = kvps.Get(ctx, repo.ID, "key1")
+		require.NoError(t, err)
+		require.Equal(t, KeyValuePair{Key: "key1", Value: pointers.Ptr("value3")}, kvp)
+
+		_, err = kvps.Get(ctx, repo.ID, "tag1")
+		require.Error(t, err)
+	})
+
 	t.Run("Delete", func(t *testing.T) {
 		t.Run("normal", func(t *testing.T) {
 			err := kvps.Delete(ctx, repo.ID, "key1")
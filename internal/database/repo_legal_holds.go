@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// RepoLegalHold is a legal hold placed on a repository by a site admin. While a
+// repository is on legal hold, it is not deleted, its clone is not garbage
+// collected or removed from gitserver, its code intelligence data does not expire,
+// and all accesses to it are recorded in the audit log.
+type RepoLegalHold struct {
+	RepoID    api.RepoID
+	RepoName  api.RepoName
+	Reason    string
+	CreatedBy *int32
+	CreatedAt time.Time
+}
+
+type RepoLegalHoldStore interface {
+	basestore.ShareableStore
+	With(basestore.ShareableStore) RepoLegalHoldStore
+	// Create places the repository on legal hold. It fails if the repository is
+	// already on legal hold.
+	Create(ctx context.Context, repoID api.RepoID, reason string, createdBy int32) (*RepoLegalHold, error)
+	// Delete releases the legal hold on the repository. It is a no-op if the
+	// repository is not on legal hold.
+	Delete(ctx context.Context, repoID api.RepoID) error
+	// Get returns the legal hold on the repository, or nil if it is not on legal
+	// hold.
+	Get(ctx context.Context, repoID api.RepoID) (*RepoLegalHold, error)
+	// List returns all legal holds, most recent first.
+	List(ctx context.Context) ([]*RepoLegalHold, error)
+	// ListRepoNames returns the names of all repositories on legal hold.
+	ListRepoNames(ctx context.Context) ([]api.RepoName, error)
+}
+
+type repoLegalHoldStore struct {
+	*basestore.Store
+}
+
+var _ RepoLegalHoldStore = (*repoLegalHoldStore)(nil)
+
+// RepoLegalHoldsWith instantiates and returns a new RepoLegalHoldStore using
+// the other store handle.
+func RepoLegalHoldsWith(other basestore.ShareableStore) RepoLegalHoldStore {
+	return &repoLegalHoldStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *repoLegalHoldStore) With(other basestore.ShareableStore) RepoLegalHoldStore {
+	return &repoLegalHoldStore{Store: s.Store.With(other)}
+}
+
+const createRepoLegalHoldQuery = `
+WITH inserted AS (
+	INSERT INTO repo_legal_holds (repo_id, reason, created_by)
+	VALUES (%s, %s, %s)
+	RETURNING repo_id, reason, created_by, created_at
+)
+SELECT i.repo_id, r.name, i.reason, i.created_by, i.created_at
+FROM inserted i
+JOIN repo r ON r.id = i.repo_id
+`
+
+func (s *repoLegalHoldStore) Create(ctx context.Context, repoID api.RepoID, reason string, createdBy int32) (*RepoLegalHold, error) {
+	hold, ok, err := scanFirstRepoLegalHold(s.Query(ctx, sqlf.Sprintf(createRepoLegalHoldQuery, repoID, reason, dbutil.NullInt32Column(createdBy))))
+	if err != nil {
+		if dbutil.IsPostgresError(err, "23505") {
+			return nil, errors.New("repository is already on legal hold")
+		}
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("repository not found")
+	}
+	return hold, nil
+}
+
+const deleteRepoLegalHoldQuery = `
+DELETE FROM repo_legal_holds WHERE repo_id = %s
+`
+
+func (s *repoLegalHoldStore) Delete(ctx context.Context, repoID api.RepoID) error {
+	return s.Exec(ctx, sqlf.Sprintf(deleteRepoLegalHoldQuery, repoID))
+}
+
+const repoLegalHoldsQuery = `
+SELECT h.repo_id, r.name, h.reason, h.created_by, h.created_at
+FROM repo_legal_holds h
+JOIN repo r ON r.id = h.repo_id
+`
+
+func (s *repoLegalHoldStore) Get(ctx context.Context, repoID api.RepoID) (*RepoLegalHold, error) {
+	hold, _, err := scanFirstRepoLegalHold(s.Query(ctx, sqlf.Sprintf(repoLegalHoldsQuery+"WHERE h.repo_id = %s", repoID)))
+	return hold, err
+}
+
+func (s *repoLegalHoldStore) List(ctx context.Context) ([]*RepoLegalHold, error) {
+	return scanRepoLegalHolds(s.Query(ctx, sqlf.Sprintf(repoLegalHoldsQuery+"ORDER BY h.created_at DESC, h.repo_id")))
+}
+
+const listRepoLegalHoldNamesQuery = `
+SELECT r.name
+FROM repo_legal_holds h
+JOIN repo r ON r.id = h.repo_id
+`
+
+func (s *repoLegalHoldStore) ListRepoNames(ctx context.Context) ([]api.RepoName, error) {
+	names, err := basestore.ScanStrings(s.Query(ctx, sqlf.Sprintf(listRepoLegalHoldNamesQuery)))
+	if err != nil {
+		return nil, err
+	}
+	repoNames := make([]api.RepoName, 0, len(names))
+	for _, name := range names {
+		repoNames = append(repoNames, api.RepoName(name))
+	}
+	return repoNames, nil
+}
+
+func scanRepoLegalHold(sc dbutil.Scanner) (*RepoLegalHold, error) {
+	var h RepoLegalHold
+	if err := sc.Scan(&h.RepoID, &h.RepoName, &h.Reason, &h.CreatedBy, &h.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+var (
+	scanRepoLegalHolds     = basestore.NewSliceScanner(scanRepoLegalHold)
+	scanFirstRepoLegalHold = basestore.NewFirstScanner(scanRepoLegalHold)
+)
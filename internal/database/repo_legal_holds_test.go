@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestRepoLegalHolds(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+	holds := db.RepoLegalHolds()
+
+	user, err := db.Users().Create(ctx, NewUser{Username: "admin"})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Repos().Create(ctx, &types.Repo{Name: "held"}, &types.Repo{Name: "other"}))
+	held, err := db.Repos().GetByName(ctx, "held")
+	require.NoError(t, err)
+	other, err := db.Repos().GetByName(ctx, "other")
+	require.NoError(t, err)
+
+	t.Run("Create", func(t *testing.T) {
+		hold, err := holds.Create(ctx, held.ID, "litigation", user.ID)
+		require.NoError(t, err)
+		require.Equal(t, held.ID, hold.RepoID)
+		require.Equal(t, held.Name, hold.RepoName)
+		require.Equal(t, "litigation", hold.Reason)
+		require.Equal(t, user.ID, *hold.CreatedBy)
+
+		_, err = holds.Create(ctx, held.ID, "again", user.ID)
+		require.Error(t, err)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		hold, err := holds.Get(ctx, held.ID)
+		require.NoError(t, err)
+		require.NotNil(t, hold)
+		require.Equal(t, "litigation", hold.Reason)
+
+		hold, err = holds.Get(ctx, other.ID)
+		require.NoError(t, err)
+		require.Nil(t, hold)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		all, err := holds.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, held.ID, all[0].RepoID)
+
+		names, err := holds.ListRepoNames(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []api.RepoName{held.Name}, names)
+	})
+
+	t.Run("repos on legal hold are not deleted", func(t *testing.T) {
+		require.NoError(t, db.Repos().Delete(ctx, held.ID, other.ID))
+
+		_, err := db.Repos().Get(ctx, held.ID)
+		require.NoError(t, err)
+		_, err = db.Repos().Get(ctx, other.ID)
+		require.True(t, errcode.IsNotFound(err))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, holds.Delete(ctx, held.ID))
+
+		hold, err := holds.Get(ctx, held.ID)
+		require.NoError(t, err)
+		require.Nil(t, hold)
+
+		require.NoError(t, db.Repos().Delete(ctx, held.ID))
+		_, err = db.Repos().Get(ctx, held.ID)
+		require.True(t, errcode.IsNotFound(err))
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
@@ -316,6 +317,7 @@ func (s *repoStore) Count(ctx context.Context, opt ReposListOptions) (ct int, er
 	opt.Select = []string{"COUNT(*)"}
 	opt.OrderBy = nil
 	opt.LimitOffset = nil
+	opt.useReplica = true
 
 	err = s.list(ctx, tr, opt, func(rows *sql.Rows) error {
 		return rows.Scan(&ct)
@@ -346,6 +348,7 @@ func (s *repoStore) Metadata(ctx context.Context, ids ...api.RepoID) (_ []*types
 		},
 		// Required so gr.last_fetched is select-able
 		joinGitserverRepos: true,
+		useReplica:         true,
 	}
 
 	res := make([]*types.SearchedRepo, 0, len(ids))
@@ -716,6 +719,10 @@ type ReposListOptions struct {
 	// with the table alias "gr".
 	joinGitserverRepos bool
 
+	// useReplica, if true, will send the query to a read replica when one is available
+	// and the context opted in with basestore.WithStaleReads.
+	useReplica bool
+
 	// ExcludeSources, if true, will NULL out the Sources field on repo. Computing it is relatively costly
 	// and if it doesn't end up being used this is wasted compute.
 	ExcludeSources bool
@@ -723,6 +730,11 @@ type ReposListOptions struct {
 	// cursor-based pagination args
 	PaginationArgs *PaginationArgs
 
+	// Keyset, if set, paginates repositories after the cursor values it holds. It
+	// takes precedence over PaginationArgs, and over LimitOffset unless the keyset
+	// pagination has no limit.
+	Keyset *KeysetPagination
+
 	*LimitOffset
 }
 
@@ -800,6 +812,7 @@ func (s *repoStore) List(ctx context.Context, opt ReposListOptions) (results []*
 	tr, ctx := trace.New(ctx, "repos.List")
 	defer tr.FinishWithErr(&err)
 
+	opt.useReplica = true
 	if len(opt.OrderBy) == 0 {
 		opt.OrderBy = append(opt.OrderBy, RepoListSort{Field: RepoListID})
 	}
@@ -813,6 +826,7 @@ func (s *repoStore) StreamMinimalRepos(ctx context.Context, opt ReposListOptions
 	defer tr.FinishWithErr(&err)
 
 	opt.Select = minimalRepoColumns
+	opt.useReplica = true
 	if len(opt.OrderBy) == 0 {
 		opt.OrderBy = append(opt.OrderBy, RepoListSort{Field: RepoListID})
 	}
@@ -904,7 +918,14 @@ func (s *repoStore) list(ctx context.Context, tr *trace.Trace, opt ReposListOpti
 		return err
 	}
 
-	rows, err := s.Query(ctx, q)
+	store := s.Store
+	if opt.useReplica {
+		store = s.Replica(ctx)
+		// The listing is a plain SELECT that can safely run again after a failover.
+		ctx = dbconn.WithFailoverRetries(ctx)
+	}
+
+	rows, err := store.Query(ctx, q)
 	if err != nil {
 		if e, ok := err.(*net.OpError); ok && e.Timeout() {
 			return errors.Wrapf(context.DeadlineExceeded, "RepoStore.list: %s", err.Error())
@@ -938,6 +959,21 @@ func (s *repoStore) listSQL(ctx context.Context, tr *trace.Trace, opt ReposListO
 		querySuffix = p.AppendLimitToQuery(querySuffix)
 	}
 
+	if opt.Keyset != nil {
+		p := opt.Keyset.SQL()
+
+		if p.Where != nil {
+			where = append(where, p.Where)
+		}
+
+		querySuffix = p.AppendOrderToQuery(&sqlf.Query{})
+		if p.Limit != nil {
+			querySuffix = p.AppendLimitToQuery(querySuffix)
+		} else {
+			querySuffix = sqlf.Sprintf("%s %s", querySuffix, opt.LimitOffset.SQL())
+		}
+	}
+
 	// Cursor-based pagination requires parsing a handful of extra fields, which
 	// may result in additional query conditions.
 	if len(opt.Cursors) > 0 {
@@ -1025,11 +1061,16 @@ func (s *repoStore) listSQL(ctx context.Context, tr *trace.Trace, opt ReposListO
 	if opt.OnlyForks {
 		where = append(where, sqlf.Sprintf("fork"))
 	}
+	// Repositories archived by the archival policy are treated like repositories
+	// archived on their code host. Both conditions only read the partial index
+	// of the archived repositories: the first one is planned as an anti-join,
+	// and the uncorrelated IN of the second one, which cannot be a semi-join
+	// because of the OR, as a hashed subplan that is built once.
 	if opt.NoArchived {
-		where = append(where, sqlf.Sprintf("NOT archived"))
+		where = append(where, sqlf.Sprintf("NOT archived AND NOT EXISTS (SELECT 1 FROM repo_archival ra WHERE ra.repo_id = repo.id AND ra.archived_at IS NOT NULL)"))
 	}
 	if opt.OnlyArchived {
-		where = append(where, sqlf.Sprintf("archived"))
+		where = append(where, sqlf.Sprintf("(archived OR repo.id IN (SELECT ra.repo_id FROM repo_archival ra WHERE ra.archived_at IS NOT NULL))"))
 	}
 	// Since https://github.com/sourcegraph/sourcegraph/pull/35633 there is no need to do an anti-join
 	// with gitserver_repos table (checking for such repos that are present in repo but absent in gitserver_repos
@@ -1089,6 +1130,7 @@ func (s *repoStore) listSQL(ctx context.Context, tr *trace.Trace, opt ReposListO
 
 			sqlf.Sprintf("EXISTS (SELECT 1 FROM gitserver_repos gr WHERE gr.repo_id = repo.id AND gr.last_changed >= %s)", opt.MinLastChanged),
 			sqlf.Sprintf("COALESCE(repo.updated_at, repo.created_at) >= %s", opt.MinLastChanged),
+			sqlf.Sprintf("EXISTS (SELECT 1 FROM repo_archival ra WHERE ra.repo_id = repo.id AND ra.updated_at >= %s)", opt.MinLastChanged),
 			sqlf.Sprintf("EXISTS (SELECT 1 FROM search_context_repos scr LEFT JOIN search_contexts sc ON scr.search_context_id = sc.id WHERE scr.repo_id = repo.id AND sc.updated_at >= %s)", opt.MinLastChanged),
 		}
 		where = append(where, sqlf.Sprintf("(%s)", sqlf.Join(conds, " OR ")))
@@ -1136,8 +1178,13 @@ func (s *repoStore) listSQL(ctx context.Context, tr *trace.Trace, opt ReposListO
 	} else if len(opt.ExternalServiceIDs) != 0 {
 		where = append(where, sqlf.Sprintf("EXISTS (SELECT 1 FROM external_service_repos esr WHERE repo.id = esr.repo_id AND esr.external_service_id = ANY (%s))", pq.Array(opt.ExternalServiceIDs)))
 	} else if opt.SearchContextID != 0 {
-		// Joining on distinct search context repos to avoid returning duplicates
-		joins = append(joins, sqlf.Sprintf(`JOIN (SELECT DISTINCT repo_id, search_context_id FROM search_context_repos) dscr ON repo.id = dscr.repo_id`))
+		// Joining on distinct search context repos to avoid returning duplicates. Composed
+		// search contexts list their repos in their materialized repos instead.
+		joins = append(joins, sqlf.Sprintf(`JOIN (
+			SELECT repo_id, search_context_id FROM search_context_repos
+			UNION
+			SELECT repo_id, search_context_id FROM %s m
+		) dscr ON repo.id = dscr.repo_id`, materializedSearchContextReposQuery))
 		where = append(where, sqlf.Sprintf("dscr.search_context_id = %d", opt.SearchContextID))
 	} else if opt.UserID != 0 {
 		userReposCTE := sqlf.Sprintf(userReposCTEFmtstr, opt.UserID)
@@ -1149,7 +1196,8 @@ func (s *repoStore) listSQL(ctx context.Context, tr *trace.Trace, opt ReposListO
 	}
 
 	if opt.NoCloned || opt.OnlyCloned || opt.FailedFetch || opt.OnlyCorrupted || opt.joinGitserverRepos ||
-		opt.CloneStatus != types.CloneStatusUnknown || containsSizeField(opt.OrderBy) || (opt.PaginationArgs != nil && containsOrderBySizeField(opt.PaginationArgs.OrderBy)) {
+		opt.CloneStatus != types.CloneStatusUnknown || containsSizeField(opt.OrderBy) || (opt.PaginationArgs != nil && containsOrderBySizeField(opt.PaginationArgs.OrderBy)) ||
+		containsKeysetSizeField(opt.Keyset) {
 		joins = append(joins, sqlf.Sprintf("JOIN gitserver_repos gr ON gr.repo_id = repo.id"))
 	}
 	if opt.OnlyIndexed || opt.NoIndexed {
@@ -1274,6 +1322,18 @@ func containsOrderBySizeField(orderBy OrderBy) bool {
 	return false
 }
 
+func containsKeysetSizeField(p *KeysetPagination) bool {
+	if p == nil {
+		return false
+	}
+	for _, column := range p.Columns {
+		if column.Field == string(RepoListSize) {
+			return true
+		}
+	}
+	return false
+}
+
 const userReposCTEFmtstr = `
 SELECT repo_id as id FROM external_service_repos WHERE user_id = %d
 `
@@ -1601,6 +1661,7 @@ SELECT id FROM inserted_repos_with_ids;
 `
 
 // Delete deletes repos associated with the given ids and their associated sources.
+// Repos on legal hold are not deleted.
 func (s *repoStore) Delete(ctx context.Context, ids ...api.RepoID) error {
 	if len(ids) == 0 {
 		return nil
@@ -1634,6 +1695,8 @@ SET
   deleted_at = COALESCE(deleted_at, transaction_timestamp())
 FROM repo_ids
 WHERE repo.id = repo_ids.id::int
+  -- Repositories on legal hold must not be deleted.
+  AND NOT EXISTS (SELECT 1 FROM repo_legal_holds h WHERE h.repo_id = repo.id)
 `
 
 const getFirstRepoNamesByCloneURLQueryFmtstr = `
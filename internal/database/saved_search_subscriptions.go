@@ -0,0 +1,281 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// SavedSearchSubscription is the subscription of a user to a saved search. The
+// saved search is run on a schedule, and the user is notified of the results
+// that were not returned by the previous run.
+type SavedSearchSubscription struct {
+	ID            int32
+	SavedSearchID int32
+	UserID        int32
+
+	NotifyEmail     bool
+	WebhookURL      *string
+	SlackWebhookURL *string
+
+	// Interval is the time between two runs of the saved search.
+	Interval time.Duration
+	// DigestInterval is the minimum time between two notifications. New results
+	// found in between are delivered together in the next notification. Zero
+	// means that new results are delivered after every run.
+	DigestInterval time.Duration
+	// Muted subscriptions keep track of the results of the saved search, but
+	// do not deliver notifications.
+	Muted bool
+
+	// LastResultKeys are the keys of the results of the last successful run.
+	LastResultKeys []string
+	// PendingResults are the new results that have not been delivered yet.
+	PendingResults []SavedSearchSubscriptionResult
+
+	NextRunAt time.Time
+	// LastRunAt is the time of the last successful run, or nil if the saved
+	// search has not been run successfully yet.
+	LastRunAt      *time.Time
+	LastNotifiedAt *time.Time
+	LastError      *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// SavedSearchSubscriptionResult is a search result that a subscription
+// notifies about.
+type SavedSearchSubscriptionResult struct {
+	Repository string `json:"repository"`
+	Commit     string `json:"commit,omitempty"`
+	Path       string `json:"path,omitempty"`
+	// URL is the path of the result relative to the external URL.
+	URL string `json:"url"`
+}
+
+// SavedSearchSubscriptionRun is the outcome of a run of the saved search of a
+// subscription. If Error is set, only the error is recorded.
+type SavedSearchSubscriptionRun struct {
+	ResultKeys     []string
+	PendingResults []SavedSearchSubscriptionResult
+	Notified       bool
+	Error          string
+}
+
+type SavedSearchSubscriptionStore interface {
+	basestore.ShareableStore
+	With(basestore.ShareableStore) SavedSearchSubscriptionStore
+	// Upsert creates the subscription of the user to the saved search, or
+	// updates its settings if it already exists.
+	Upsert(ctx context.Context, s *SavedSearchSubscription) (*SavedSearchSubscription, error)
+	// Delete deletes the subscription of the user to the saved search. It is a
+	// no-op if the user is not subscribed.
+	Delete(ctx context.Context, savedSearchID, userID int32) error
+	// Get returns the subscription of the user to the saved search, or nil if
+	// the user is not subscribed.
+	Get(ctx context.Context, savedSearchID, userID int32) (*SavedSearchSubscription, error)
+	// ListByUser returns the subscriptions of the user.
+	ListByUser(ctx context.Context, userID int32) ([]*SavedSearchSubscription, error)
+	// ListDue returns up to limit subscriptions whose saved search is due to
+	// run, the most overdue first.
+	ListDue(ctx context.Context, limit int) ([]*SavedSearchSubscription, error)
+	// RecordRun records the outcome of a run of the subscription and schedules
+	// its next run.
+	RecordRun(ctx context.Context, id int32, run SavedSearchSubscriptionRun) error
+}
+
+type savedSearchSubscriptionStore struct {
+	*basestore.Store
+}
+
+var _ SavedSearchSubscriptionStore = (*savedSearchSubscriptionStore)(nil)
+
+// SavedSearchSubscriptionsWith instantiates and returns a new
+// SavedSearchSubscriptionStore using the other store handle.
+func SavedSearchSubscriptionsWith(other basestore.ShareableStore) SavedSearchSubscriptionStore {
+	return &savedSearchSubscriptionStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *savedSearchSubscriptionStore) With(other basestore.ShareableStore) SavedSearchSubscriptionStore {
+	return &savedSearchSubscriptionStore{Store: s.Store.With(other)}
+}
+
+const savedSearchSubscriptionColumns = `
+	id,
+	saved_search_id,
+	user_id,
+	notify_email,
+	webhook_url,
+	slack_webhook_url,
+	interval_minutes,
+	digest_interval_minutes,
+	muted,
+	last_result_keys,
+	pending_results,
+	next_run_at,
+	last_run_at,
+	last_notified_at,
+	last_error,
+	created_at,
+	updated_at
+`
+
+const upsertSavedSearchSubscriptionQuery = `
+INSERT INTO saved_search_subscriptions (
+	saved_search_id,
+	user_id,
+	notify_email,
+	webhook_url,
+	slack_webhook_url,
+	interval_minutes,
+	digest_interval_minutes,
+	muted
+)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
+ON CONFLICT (saved_search_id, user_id) DO UPDATE SET
+	notify_email = EXCLUDED.notify_email,
+	webhook_url = EXCLUDED.webhook_url,
+	slack_webhook_url = EXCLUDED.slack_webhook_url,
+	interval_minutes = EXCLUDED.interval_minutes,
+	digest_interval_minutes = EXCLUDED.digest_interval_minutes,
+	-- Results found while muted are not delivered after unmuting.
+	pending_results = CASE WHEN EXCLUDED.muted THEN '[]' ELSE saved_search_subscriptions.pending_results END,
+	muted = EXCLUDED.muted,
+	updated_at = NOW()
+RETURNING ` + savedSearchSubscriptionColumns
+
+func (s *savedSearchSubscriptionStore) Upsert(ctx context.Context, sub *SavedSearchSubscription) (*SavedSearchSubscription, error) {
+	if sub.Interval < time.Minute {
+		return nil, errors.New("the interval of a saved search subscription must be at least one minute")
+	}
+	if sub.DigestInterval < 0 {
+		return nil, errors.New("the digest interval of a saved search subscription must not be negative")
+	}
+	subscription, _, err := scanFirstSavedSearchSubscription(s.Query(ctx, sqlf.Sprintf(
+		upsertSavedSearchSubscriptionQuery,
+		sub.SavedSearchID,
+		sub.UserID,
+		sub.NotifyEmail,
+		sub.WebhookURL,
+		sub.SlackWebhookURL,
+		int(sub.Interval/time.Minute),
+		int(sub.DigestInterval/time.Minute),
+		sub.Muted,
+	)))
+	return subscription, err
+}
+
+const deleteSavedSearchSubscriptionQuery = `
+DELETE FROM saved_search_subscriptions WHERE saved_search_id = %s AND user_id = %s
+`
+
+func (s *savedSearchSubscriptionStore) Delete(ctx context.Context, savedSearchID, userID int32) error {
+	return s.Exec(ctx, sqlf.Sprintf(deleteSavedSearchSubscriptionQuery, savedSearchID, userID))
+}
+
+const savedSearchSubscriptionsQuery = `
+SELECT ` + savedSearchSubscriptionColumns + `
+FROM saved_search_subscriptions
+`
+
+func (s *savedSearchSubscriptionStore) Get(ctx context.Context, savedSearchID, userID int32) (*SavedSearchSubscription, error) {
+	subscription, _, err := scanFirstSavedSearchSubscription(s.Query(ctx, sqlf.Sprintf(savedSearchSubscriptionsQuery+"WHERE saved_search_id = %s AND user_id = %s", savedSearchID, userID)))
+	return subscription, err
+}
+
+func (s *savedSearchSubscriptionStore) ListByUser(ctx context.Context, userID int32) ([]*SavedSearchSubscription, error) {
+	return scanSavedSearchSubscriptions(s.Query(ctx, sqlf.Sprintf(savedSearchSubscriptionsQuery+"WHERE user_id = %s ORDER BY id", userID)))
+}
+
+func (s *savedSearchSubscriptionStore) ListDue(ctx context.Context, limit int) ([]*SavedSearchSubscription, error) {
+	return scanSavedSearchSubscriptions(s.Query(ctx, sqlf.Sprintf(savedSearchSubscriptionsQuery+"WHERE next_run_at <= NOW() ORDER BY next_run_at, id LIMIT %s", limit)))
+}
+
+const recordSavedSearchSubscriptionErrorQuery = `
+UPDATE saved_search_subscriptions SET
+	next_run_at = NOW() + interval_minutes * INTERVAL '1 minute',
+	last_error = %s
+WHERE id = %s
+`
+
+const recordSavedSearchSubscriptionRunQuery = `
+UPDATE saved_search_subscriptions SET
+	last_result_keys = %s,
+	pending_results = %s,
+	last_run_at = NOW(),
+	next_run_at = NOW() + interval_minutes * INTERVAL '1 minute',
+	last_notified_at = CASE WHEN %s THEN NOW() ELSE last_notified_at END,
+	last_error = NULL
+WHERE id = %s
+`
+
+func (s *savedSearchSubscriptionStore) RecordRun(ctx context.Context, id int32, run SavedSearchSubscriptionRun) error {
+	if run.Error != "" {
+		return s.Exec(ctx, sqlf.Sprintf(recordSavedSearchSubscriptionErrorQuery, run.Error, id))
+	}
+
+	pending := run.PendingResults
+	if pending == nil {
+		pending = []SavedSearchSubscriptionResult{}
+	}
+	rawPending, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+	return s.Exec(ctx, sqlf.Sprintf(
+		recordSavedSearchSubscriptionRunQuery,
+		pq.Array(run.ResultKeys),
+		rawPending,
+		run.Notified,
+		id,
+	))
+}
+
+func scanSavedSearchSubscription(sc dbutil.Scanner) (*SavedSearchSubscription, error) {
+	var (
+		s                              SavedSearchSubscription
+		intervalMinutes, digestMinutes int
+		rawPending                     []byte
+		keys                           pq.StringArray
+	)
+	if err := sc.Scan(
+		&s.ID,
+		&s.SavedSearchID,
+		&s.UserID,
+		&s.NotifyEmail,
+		&s.WebhookURL,
+		&s.SlackWebhookURL,
+		&intervalMinutes,
+		&digestMinutes,
+		&s.Muted,
+		&keys,
+		&rawPending,
+		&s.NextRunAt,
+		&s.LastRunAt,
+		&s.LastNotifiedAt,
+		&s.LastError,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	s.Interval = time.Duration(intervalMinutes) * time.Minute
+	s.DigestInterval = time.Duration(digestMinutes) * time.Minute
+	s.LastResultKeys = []string(keys)
+	if err := json.Unmarshal(rawPending, &s.PendingResults); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+var (
+	scanSavedSearchSubscriptions     = basestore.NewSliceScanner(scanSavedSearchSubscription)
+	scanFirstSavedSearchSubscription = basestore.NewFirstScanner(scanSavedSearchSubscription)
+)
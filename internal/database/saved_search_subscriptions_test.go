@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestSavedSearchSubscriptions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+	subscriptions := db.SavedSearchSubscriptions()
+
+	user, err := db.Users().Create(ctx, NewUser{Username: "u"})
+	require.NoError(t, err)
+	ss, err := db.SavedSearches().Create(ctx, &types.SavedSearch{
+		Query:       "type:diff TODO",
+		Description: "new TODOs",
+		UserID:      &user.ID,
+	})
+	require.NoError(t, err)
+
+	t.Run("Upsert", func(t *testing.T) {
+		_, err := subscriptions.Upsert(ctx, &SavedSearchSubscription{SavedSearchID: ss.ID, UserID: user.ID})
+		require.Error(t, err, "interval must be at least one minute")
+
+		sub, err := subscriptions.Upsert(ctx, &SavedSearchSubscription{
+			SavedSearchID: ss.ID,
+			UserID:        user.ID,
+			NotifyEmail:   true,
+			Interval:      time.Hour,
+		})
+		require.NoError(t, err)
+		require.True(t, sub.NotifyEmail)
+		require.Equal(t, time.Hour, sub.Interval)
+		require.Zero(t, sub.DigestInterval)
+		require.Nil(t, sub.LastRunAt)
+		require.Empty(t, sub.PendingResults)
+
+		webhook := "https://example.com/hook"
+		updated, err := subscriptions.Upsert(ctx, &SavedSearchSubscription{
+			SavedSearchID:  ss.ID,
+			UserID:         user.ID,
+			WebhookURL:     &webhook,
+			Interval:       time.Hour,
+			DigestInterval: 24 * time.Hour,
+		})
+		require.NoError(t, err)
+		require.Equal(t, sub.ID, updated.ID)
+		require.False(t, updated.NotifyEmail)
+		require.Equal(t, webhook, *updated.WebhookURL)
+		require.Equal(t, 24*time.Hour, updated.DigestInterval)
+	})
+
+	sub, err := subscriptions.Get(ctx, ss.ID, user.ID)
+	require.NoError(t, err)
+	require.NotNil(t, sub)
+
+	t.Run("ListDue", func(t *testing.T) {
+		due, err := subscriptions.ListDue(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		require.Equal(t, sub.ID, due[0].ID)
+	})
+
+	t.Run("RecordRun", func(t *testing.T) {
+		pending := []SavedSearchSubscriptionResult{{Repository: "r", Path: "a.go", URL: "/r/-/blob/a.go"}}
+		require.NoError(t, subscriptions.RecordRun(ctx, sub.ID, SavedSearchSubscriptionRun{
+			ResultKeys:     []string{"a", "b"},
+			PendingResults: pending,
+		}))
+
+		got, err := subscriptions.Get(ctx, ss.ID, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b"}, got.LastResultKeys)
+		require.Equal(t, pending, got.PendingResults)
+		require.NotNil(t, got.LastRunAt)
+		require.Nil(t, got.LastNotifiedAt)
+		require.True(t, got.NextRunAt.After(time.Now()))
+
+		due, err := subscriptions.ListDue(ctx, 10)
+		require.NoError(t, err)
+		require.Empty(t, due)
+
+		// Errors do not overwrite the results of the last successful run.
+		require.NoError(t, subscriptions.RecordRun(ctx, sub.ID, SavedSearchSubscriptionRun{Error: "boom"}))
+		got, err = subscriptions.Get(ctx, ss.ID, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b"}, got.LastResultKeys)
+		require.Equal(t, "boom", *got.LastError)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, subscriptions.Delete(ctx, ss.ID, user.ID))
+		got, err := subscriptions.Get(ctx, ss.ID, user.ID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+
+		list, err := subscriptions.ListByUser(ctx, user.ID)
+		require.NoError(t, err)
+		require.Empty(t, list)
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -37,6 +38,10 @@ type SearchContextsStore interface {
 	GetSearchContextRepositoryRevisions(context.Context, int64) ([]*types.SearchContextRepositoryRevisions, error)
 	ListSearchContexts(context.Context, ListSearchContextsPageOptions, ListSearchContextsOptions) ([]*types.SearchContext, error)
 	GetAllQueries(context.Context) ([]string, error)
+	GetMaterializedSearchContextRepositoryRevisions(context.Context, int64) ([]*types.SearchContextRepositoryRevisions, error)
+	GetSearchContextIncludes(context.Context) (map[int64][]int64, error)
+	SetIncludedSearchContexts(ctx context.Context, searchContextID int64, includedSearchContextIDs []int64) error
+	SetMaterializedSearchContextRepositoryRevisions(ctx context.Context, searchContextID int64, version int32, repositoryRevisions []*types.SearchContextRepositoryRevisions) error
 	SetSearchContextRepositoryRevisions(context.Context, int64, []*types.SearchContextRepositoryRevisions) error
 	Transact(context.Context) (SearchContextsStore, error)
 	UpdateSearchContextWithRepositoryRevisions(context.Context, *types.SearchContext, []*types.SearchContextRepositoryRevisions) (*types.SearchContext, error)
@@ -91,6 +96,10 @@ const searchContextQueryFmtStr = `
 		NULL as namespace_org_id,
 		TIMESTAMP WITH TIME ZONE 'epoch' as updated_at, -- Timestamp is not used for global context, but we need to return something.
 		NULL as query,
+		NULL::text[] as repository_queries,
+		NULL::bigint[] as included_search_context_ids,
+		0 as version,
+		0 as materialized_version,
 		NULL as namespace_name,
 		NULL as namespace_username,
 		NULL as namespace_org_name,
@@ -107,6 +116,15 @@ const searchContextQueryFmtStr = `
 		sc.namespace_org_id as namespace_org_id,
 		sc.updated_at as updated_at,
 		sc.query as query,
+		NULLIF(sc.repository_queries, '{}') as repository_queries,
+		NULLIF(ARRAY(
+			SELECT sci.included_search_context_id
+			FROM search_context_includes sci
+			WHERE sci.search_context_id = sc.id
+			ORDER BY sci.included_search_context_id
+		), '{}') as included_search_context_ids,
+		sc.version as version,
+		sc.materialized_version as materialized_version,
 		COALESCE(u.username, o.name) as namespace_name,
 		u.username as namespace_username,
 		o.name as namespace_org_name,
@@ -132,6 +150,10 @@ SELECT
 	namespace_org_id,
 	updated_at,
 	query,
+	repository_queries,
+	included_search_context_ids,
+	version,
+	materialized_version,
 	namespace_username,
 	namespace_org_name,
 	user_default,
@@ -188,6 +210,8 @@ type ListSearchContextsOptions struct {
 	NamespaceOrgIDs []int32
 	// NoNamespace matches search contexts without a namespace ("instance-level contexts").
 	NoNamespace bool
+	// OnlyComposed matches search contexts that include other search contexts or repository queries.
+	OnlyComposed bool
 	// OrderBy specifies the ordering option for search contexts. Search contexts are ordered using SearchContextsOrderByID by default.
 	// SearchContextsOrderBySpec option sorts contexts by coallesced namespace names first
 	// (user name and org name) and then by context name. SearchContextsOrderByUpdatedAt option sorts
@@ -261,6 +285,10 @@ func getSearchContextsQueryConditions(opts ListSearchContextsOptions) []*sqlf.Qu
 		conds = append(conds, sqlf.Sprintf("COALESCE(namespace_username, namespace_org_name, '') ILIKE %s", "%"+opts.NamespaceName+"%"))
 	}
 
+	if opts.OnlyComposed {
+		conds = append(conds, sqlf.Sprintf("(repository_queries IS NOT NULL OR included_search_context_ids IS NOT NULL)"))
+	}
+
 	if len(conds) == 0 {
 		// If no conditions are present, append a catch-all condition to avoid a SQL syntax error
 		conds = append(conds, sqlf.Sprintf("1 = 1"))
@@ -274,7 +302,7 @@ func (s *searchContextsStore) listSearchContexts(ctx context.Context, cond *sqlf
 	authenticatedUserId := actor.FromContext(ctx).UID
 
 	query := sqlf.Sprintf(listSearchContextsFmtStr, authenticatedUserId, authenticatedUserId, authenticatedUserId, permissionsCond, cond, orderBy, limit, offset)
-	rows, err := s.Query(ctx, query)
+	rows, err := s.Replica(ctx).Query(dbconn.WithFailoverRetries(ctx), query)
 	if err != nil {
 		return nil, err
 	}
@@ -295,7 +323,7 @@ func (s *searchContextsStore) CountSearchContexts(ctx context.Context, opts List
 
 	var count int32
 	query := sqlf.Sprintf(countSearchContextsFmtStr, authenticatedUserId, authenticatedUserId, authenticatedUserId, permissionsCond, sqlf.Join(conds, "\n AND "))
-	err := s.QueryRow(ctx, query).Scan(&count)
+	err := s.Replica(ctx).QueryRow(dbconn.WithFailoverRetries(ctx), query).Scan(&count)
 	if err != nil {
 		return -1, err
 	}
@@ -303,6 +331,8 @@ func (s *searchContextsStore) CountSearchContexts(ctx context.Context, opts List
 }
 
 type GetSearchContextOptions struct {
+	// ID, if non zero, looks up the search context by ID. The other options are ignored.
+	ID              int64
 	Name            string
 	NamespaceUserID int32
 	NamespaceOrgID  int32
@@ -310,7 +340,9 @@ type GetSearchContextOptions struct {
 
 func (s *searchContextsStore) GetSearchContext(ctx context.Context, opts GetSearchContextOptions) (*types.SearchContext, error) {
 	conds := []*sqlf.Query{}
-	if opts.NamespaceUserID == 0 && opts.NamespaceOrgID == 0 {
+	if opts.ID != 0 {
+		conds = append(conds, sqlf.Sprintf("id = %d", opts.ID))
+	} else if opts.NamespaceUserID == 0 && opts.NamespaceOrgID == 0 {
 		conds = append(conds, sqlf.Sprintf("namespace_user_id IS NULL"), sqlf.Sprintf("namespace_org_id IS NULL"))
 	} else {
 		namespaceConds, err := getSearchContextNamespaceQueryConditions(opts.NamespaceUserID, opts.NamespaceOrgID)
@@ -319,7 +351,9 @@ func (s *searchContextsStore) GetSearchContext(ctx context.Context, opts GetSear
 		}
 		conds = append(conds, namespaceConds...)
 	}
-	conds = append(conds, sqlf.Sprintf("context_name = %s", opts.Name))
+	if opts.ID == 0 {
+		conds = append(conds, sqlf.Sprintf("context_name = %s", opts.Name))
+	}
 
 	permissionsCond := searchContextsPermissionsCondition(ctx)
 	authenticatedUserId := actor.FromContext(ctx).UID
@@ -349,14 +383,24 @@ DELETE FROM search_contexts WHERE id = %d
 `
 
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin or has permission to delete the search context.
-func (s *searchContextsStore) DeleteSearchContext(ctx context.Context, searchContextID int64) error {
-	return s.Exec(ctx, sqlf.Sprintf(deleteSearchContextFmtStr, searchContextID))
+func (s *searchContextsStore) DeleteSearchContext(ctx context.Context, searchContextID int64) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	// The search contexts including the deleted one lose its repositories.
+	if err := tx.Exec(ctx, sqlf.Sprintf(incrementIncludingSearchContextVersionsFmtStr, searchContextID)); err != nil {
+		return err
+	}
+	return tx.Exec(ctx, sqlf.Sprintf(deleteSearchContextFmtStr, searchContextID))
 }
 
 const insertSearchContextFmtStr = `
 INSERT INTO search_contexts
-(name, description, public, namespace_user_id, namespace_org_id, query)
-VALUES (%s, %s, %s, %s, %s, %s)
+(name, description, public, namespace_user_id, namespace_org_id, query, repository_queries)
+VALUES (%s, %s, %s, %s, %s, %s, COALESCE(%s::text[], '{}'))
 `
 
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin or has permission to create the search context.
@@ -376,7 +420,16 @@ func (s *searchContextsStore) CreateSearchContextWithRepositoryRevisions(ctx con
 	if err != nil {
 		return nil, err
 	}
-	return createdSearchContext, nil
+
+	if len(searchContext.IncludedSearchContextIDs) == 0 {
+		return createdSearchContext, nil
+	}
+
+	err = tx.SetIncludedSearchContexts(ctx, createdSearchContext.ID, searchContext.IncludedSearchContextIDs)
+	if err != nil {
+		return nil, err
+	}
+	return tx.GetSearchContext(ctx, GetSearchContextOptions{ID: createdSearchContext.ID})
 }
 
 const updateSearchContextFmtStr = `
@@ -386,6 +439,7 @@ SET
 	description = %s,
 	public = %s,
 	query = %s,
+	repository_queries = COALESCE(%s::text[], '{}'),
 	updated_at = now()
 WHERE id = %d
 `
@@ -407,7 +461,19 @@ func (s *searchContextsStore) UpdateSearchContextWithRepositoryRevisions(ctx con
 	if err != nil {
 		return nil, err
 	}
-	return updatedSearchContext, nil
+
+	err = tx.SetIncludedSearchContexts(ctx, updatedSearchContext.ID, searchContext.IncludedSearchContextIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate the materialized repositories of the updated search context and of
+	// all the search contexts including it.
+	err = tx.Exec(ctx, sqlf.Sprintf(incrementIncludingSearchContextVersionsFmtStr, updatedSearchContext.ID))
+	if err != nil {
+		return nil, err
+	}
+	return tx.GetSearchContext(ctx, GetSearchContextOptions{ID: updatedSearchContext.ID})
 }
 
 func (s *searchContextsStore) SetSearchContextRepositoryRevisions(ctx context.Context, searchContextID int64, repositoryRevisions []*types.SearchContextRepositoryRevisions) (err error) {
@@ -442,6 +508,112 @@ func (s *searchContextsStore) SetSearchContextRepositoryRevisions(ctx context.Co
 	))
 }
 
+const incrementIncludingSearchContextVersionsFmtStr = `
+WITH RECURSIVE including(id) AS (
+	SELECT %d::bigint
+	UNION
+	SELECT sci.search_context_id
+	FROM search_context_includes sci
+	JOIN including i ON sci.included_search_context_id = i.id
+)
+UPDATE search_contexts SET version = version + 1
+WHERE id IN (SELECT id FROM including)
+`
+
+// SetIncludedSearchContexts replaces the search contexts included in the given search context.
+//
+// 🚨 SECURITY: The caller must ensure that the actor has permission to update the search context
+// and to read the included search contexts.
+func (s *searchContextsStore) SetIncludedSearchContexts(ctx context.Context, searchContextID int64, includedSearchContextIDs []int64) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	err = tx.Exec(ctx, sqlf.Sprintf("DELETE FROM search_context_includes WHERE search_context_id = %d", searchContextID))
+	if err != nil {
+		return err
+	}
+
+	if len(includedSearchContextIDs) == 0 {
+		return nil
+	}
+
+	values := make([]*sqlf.Query, 0, len(includedSearchContextIDs))
+	for _, includedID := range includedSearchContextIDs {
+		values = append(values, sqlf.Sprintf("(%s, %s)", searchContextID, includedID))
+	}
+
+	return tx.Exec(ctx, sqlf.Sprintf(
+		"INSERT INTO search_context_includes (search_context_id, included_search_context_id) VALUES %s ON CONFLICT DO NOTHING",
+		sqlf.Join(values, ","),
+	))
+}
+
+// GetSearchContextIncludes returns the IDs of the search contexts included in each search
+// context that includes other search contexts.
+func (s *searchContextsStore) GetSearchContextIncludes(ctx context.Context) (_ map[int64][]int64, err error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(
+		"SELECT search_context_id, included_search_context_id FROM search_context_includes ORDER BY search_context_id, included_search_context_id",
+	))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	includes := map[int64][]int64{}
+	for rows.Next() {
+		var searchContextID, includedID int64
+		if err := rows.Scan(&searchContextID, &includedID); err != nil {
+			return nil, err
+		}
+		includes[searchContextID] = append(includes[searchContextID], includedID)
+	}
+	return includes, nil
+}
+
+// SetMaterializedSearchContextRepositoryRevisions replaces the materialized repositories and
+// revisions of the given search context, resolved for the given version of the search context.
+func (s *searchContextsStore) SetMaterializedSearchContextRepositoryRevisions(ctx context.Context, searchContextID int64, version int32, repositoryRevisions []*types.SearchContextRepositoryRevisions) (err error) {
+	if a := actor.FromContext(ctx); !a.IsInternal() {
+		return errors.New("SetMaterializedSearchContextRepositoryRevisions can only be accessed by an internal actor")
+	}
+
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	err = tx.Exec(ctx, sqlf.Sprintf("DELETE FROM search_context_materialized_repos WHERE search_context_id = %d", searchContextID))
+	if err != nil {
+		return err
+	}
+
+	values := []*sqlf.Query{}
+	for _, repoRev := range repositoryRevisions {
+		for _, revision := range repoRev.Revisions {
+			values = append(values, sqlf.Sprintf("(%s, %s, %s, %s)", searchContextID, version, repoRev.Repo.ID, revision))
+		}
+	}
+	if len(values) > 0 {
+		err = tx.Exec(ctx, sqlf.Sprintf(
+			"INSERT INTO search_context_materialized_repos (search_context_id, version, repo_id, revision) VALUES %s ON CONFLICT DO NOTHING",
+			sqlf.Join(values, ","),
+		))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Exec(ctx, sqlf.Sprintf(
+		"UPDATE search_contexts SET materialized_version = %s, materialized_at = now() WHERE id = %d",
+		version,
+		searchContextID,
+	))
+}
+
 func createSearchContext(ctx context.Context, s SearchContextsStore, searchContext *types.SearchContext) (*types.SearchContext, error) {
 	q := sqlf.Sprintf(
 		insertSearchContextFmtStr,
@@ -451,6 +623,7 @@ func createSearchContext(ctx context.Context, s SearchContextsStore, searchConte
 		dbutil.NullInt32Column(searchContext.NamespaceUserID),
 		dbutil.NullInt32Column(searchContext.NamespaceOrgID),
 		dbutil.NullStringColumn(searchContext.Query),
+		pq.Array(searchContext.RepositoryQueries),
 	)
 	_, err := s.Handle().ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
 	if err != nil {
@@ -470,6 +643,7 @@ func updateSearchContext(ctx context.Context, s SearchContextsStore, searchConte
 		searchContext.Description,
 		searchContext.Public,
 		dbutil.NullStringColumn(searchContext.Query),
+		pq.Array(searchContext.RepositoryQueries),
 		searchContext.ID,
 	)
 	_, err := s.Handle().ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
@@ -508,6 +682,10 @@ func scanSearchContexts(rows *sql.Rows) ([]*types.SearchContext, error) {
 			&dbutil.NullInt32{N: &sc.NamespaceOrgID},
 			&sc.UpdatedAt,
 			&dbutil.NullString{S: &sc.Query},
+			pq.Array(&sc.RepositoryQueries),
+			pq.Array(&sc.IncludedSearchContextIDs),
+			&sc.Version,
+			&sc.MaterializedVersion,
 			&dbutil.NullString{S: &sc.NamespaceUserName},
 			&dbutil.NullString{S: &sc.NamespaceOrgName},
 			&sc.Default,
@@ -527,7 +705,7 @@ SELECT
 	sc.revision,
 	r.name
 FROM
-	search_context_repos sc
+	%s sc
 JOIN
 	(
 		SELECT
@@ -545,6 +723,25 @@ WHERE sc.search_context_id = %d
 `
 
 func (s *searchContextsStore) GetSearchContextRepositoryRevisions(ctx context.Context, searchContextID int64) ([]*types.SearchContextRepositoryRevisions, error) {
+	return s.getRepositoryRevisions(ctx, sqlf.Sprintf("search_context_repos"), searchContextID)
+}
+
+// materializedSearchContextReposQuery selects the materialized repositories of the
+// current version of each search context.
+var materializedSearchContextReposQuery = sqlf.Sprintf(`(
+	SELECT m.search_context_id, m.repo_id, m.revision
+	FROM search_context_materialized_repos m
+	JOIN search_contexts c ON c.id = m.search_context_id AND c.version = m.version
+)`)
+
+// GetMaterializedSearchContextRepositoryRevisions returns the repositories and revisions
+// of the given composed search context, as resolved for its current version by
+// SetMaterializedSearchContextRepositoryRevisions.
+func (s *searchContextsStore) GetMaterializedSearchContextRepositoryRevisions(ctx context.Context, searchContextID int64) ([]*types.SearchContextRepositoryRevisions, error) {
+	return s.getRepositoryRevisions(ctx, materializedSearchContextReposQuery, searchContextID)
+}
+
+func (s *searchContextsStore) getRepositoryRevisions(ctx context.Context, table *sqlf.Query, searchContextID int64) ([]*types.SearchContextRepositoryRevisions, error) {
 	authzConds, err := AuthzQueryConds(ctx, NewDBWith(s.logger, s))
 	if err != nil {
 		return nil, err
@@ -552,6 +749,7 @@ func (s *searchContextsStore) GetSearchContextRepositoryRevisions(ctx context.Co
 
 	rows, err := s.Query(ctx, sqlf.Sprintf(
 		getSearchContextRepositoryRevisionsFmtStr,
+		table,
 		authzConds,
 		searchContextID,
 	))
@@ -599,7 +797,11 @@ SELECT DISTINCT
 	scr.repo_id,
 	scr.revision
 FROM
-	search_context_repos scr
+	(
+		SELECT repo_id, revision FROM search_context_repos
+		UNION ALL
+		SELECT repo_id, revision FROM %s m
+	) scr
 WHERE
 	scr.repo_id = ANY (%s)
 ORDER BY
@@ -619,6 +821,7 @@ func (s *searchContextsStore) GetAllRevisionsForRepos(ctx context.Context, repoI
 
 	q := sqlf.Sprintf(
 		getAllRevisionsForReposFmtStr,
+		materializedSearchContextReposQuery,
 		pq.Array(repoIDs),
 	)
 
@@ -148,6 +148,8 @@ func TestSearchContexts_Update(t *testing.T) {
 
 			// Ignore updatedAt change
 			updated.UpdatedAt = tt.updated.UpdatedAt
+			// Updates invalidate the materialized repositories
+			tt.updated.Version++
 			if diff := cmp.Diff(tt.updated, updated); diff != "" {
 				t.Fatalf("unexpected result: %s", diff)
 			}
@@ -427,6 +429,120 @@ func TestSearchContexts_CreateAndSetRepositoryRevisions(t *testing.T) {
 	}
 }
 
+func TestSearchContexts_Composition(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	t.Parallel()
+	ctx := actor.WithInternalActor(context.Background())
+	sc := db.SearchContexts()
+	r := db.Repos()
+
+	err := r.Create(ctx, &types.Repo{Name: "testA", URI: "https://example.com/a"}, &types.Repo{Name: "testB", URI: "https://example.com/b"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	repoA, err := r.GetByName(ctx, "testA")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	repoB, err := r.GetByName(ctx, "testB")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	base, err := sc.CreateSearchContextWithRepositoryRevisions(
+		ctx,
+		&types.SearchContext{Name: "base", Public: true},
+		[]*types.SearchContextRepositoryRevisions{{Repo: types.MinimalRepo{ID: repoA.ID, Name: repoA.Name}, Revisions: []string{"HEAD"}}},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	composed, err := sc.CreateSearchContextWithRepositoryRevisions(
+		ctx,
+		&types.SearchContext{Name: "composed", Public: true, IncludedSearchContextIDs: []int64{base.ID}, RepositoryQueries: []string{"repo:^testB$"}},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if diff := cmp.Diff([]int64{base.ID}, composed.IncludedSearchContextIDs); diff != "" {
+		t.Fatalf("unexpected included search contexts (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"repo:^testB$"}, composed.RepositoryQueries); diff != "" {
+		t.Fatalf("unexpected repository queries (-want +got):\n%s", diff)
+	}
+
+	includes, err := sc.GetSearchContextIncludes(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if diff := cmp.Diff(map[int64][]int64{composed.ID: {base.ID}}, includes); diff != "" {
+		t.Fatalf("unexpected includes (-want +got):\n%s", diff)
+	}
+
+	gotComposed, err := sc.ListSearchContexts(ctx, ListSearchContextsPageOptions{First: 10}, ListSearchContextsOptions{OnlyComposed: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if diff := cmp.Diff([]string{"composed"}, getSearchContextNames(gotComposed)); diff != "" {
+		t.Fatalf("unexpected composed search contexts (-want +got):\n%s", diff)
+	}
+
+	materialized := []*types.SearchContextRepositoryRevisions{
+		{Repo: types.MinimalRepo{ID: repoA.ID, Name: repoA.Name}, Revisions: []string{"HEAD"}},
+		{Repo: types.MinimalRepo{ID: repoB.ID, Name: repoB.Name}, Revisions: []string{"HEAD"}},
+	}
+	if err := sc.SetMaterializedSearchContextRepositoryRevisions(ctx, composed.ID, composed.Version, materialized); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	gotMaterialized, err := sc.GetMaterializedSearchContextRepositoryRevisions(ctx, composed.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if diff := cmp.Diff(materialized, gotMaterialized); diff != "" {
+		t.Fatalf("unexpected materialized repositories (-want +got):\n%s", diff)
+	}
+	repos, err := r.ListMinimalRepos(ctx, ReposListOptions{SearchContextID: composed.ID})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("wanted 2 repositories in the composed search context, got %v", repos)
+	}
+
+	// Updating an included search context invalidates the materialized repositories
+	// of the search contexts including it.
+	if _, err := sc.UpdateSearchContextWithRepositoryRevisions(ctx, base, nil); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	updatedComposed, err := sc.GetSearchContext(ctx, GetSearchContextOptions{ID: composed.ID})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if updatedComposed.Version != composed.Version+1 || updatedComposed.MaterializedVersion != composed.Version {
+		t.Fatalf("unexpected versions: version=%d materialized=%d", updatedComposed.Version, updatedComposed.MaterializedVersion)
+	}
+	gotMaterialized, err = sc.GetMaterializedSearchContextRepositoryRevisions(ctx, composed.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(gotMaterialized) != 0 {
+		t.Fatalf("wanted no materialized repositories for the new version, got %v", gotMaterialized)
+	}
+
+	if err := sc.DeleteSearchContext(ctx, base.ID); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	deletedComposed, err := sc.GetSearchContext(ctx, GetSearchContextOptions{ID: composed.ID})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if deletedComposed.Version != updatedComposed.Version+1 || len(deletedComposed.IncludedSearchContextIDs) != 0 {
+		t.Fatalf("unexpected search context after deleting the included one: %+v", deletedComposed)
+	}
+}
+
 func TestSearchContexts_Permissions(t *testing.T) {
 	logger := logtest.Scoped(t)
 	db := NewDB(logger, dbtest.NewDB(logger, t))
@@ -46,12 +46,28 @@ const (
 
 	SecurityEventNameAccessGranted SecurityEventName = "AccessGranted"
 
+	SecurityEventNameRepoLegalHoldPlaced   SecurityEventName = "RepoLegalHoldPlaced"
+	SecurityEventNameRepoLegalHoldReleased SecurityEventName = "RepoLegalHoldReleased"
+
+	SecurityEventNameRepoArchivalPolicyUpdated   SecurityEventName = "RepoArchivalPolicyUpdated"
+	SecurityEventNameRepoArchivalOverrideUpdated SecurityEventName = "RepoArchivalOverrideUpdated"
+	SecurityEventNameRepoArchivalRestored        SecurityEventName = "RepoArchivalRestored"
+
+	SecurityEventNameRepoCloneBoosted SecurityEventName = "RepoCloneBoosted"
+
+	SecurityEventNameUserDataExportRequested SecurityEventName = "UserDataExportRequested"
+
+	SecurityEventNameBackgroundRoutinePaused  SecurityEventName = "BackgroundRoutinePaused"
+	SecurityEventNameBackgroundRoutineDrained SecurityEventName = "BackgroundRoutineDrained"
+	SecurityEventNameBackgroundRoutineResumed SecurityEventName = "BackgroundRoutineResumed"
+
 	SecurityEventAccessTokenCreated             SecurityEventName = "AccessTokenCreated"
 	SecurityEventAccessTokenDeleted             SecurityEventName = "AccessTokenDeleted"
 	SecurityEventAccessTokenHardDeleted         SecurityEventName = "AccessTokenHardDeleted"
 	SecurityEventAccessTokenImpersonated        SecurityEventName = "AccessTokenImpersonated"
 	SecurityEventAccessTokenInvalid             SecurityEventName = "AccessTokenInvalid"
 	SecurityEventAccessTokenSubjectNotSiteAdmin SecurityEventName = "AccessTokenSubjectNotSiteAdmin"
+	SecurityEventAccessTokenUsageSpike          SecurityEventName = "AccessTokenUsageSpike"
 
 	SecurityEventGitHubAuthSucceeded SecurityEventName = "GitHubAuthSucceeded"
 	SecurityEventGitHubAuthFailed    SecurityEventName = "GitHubAuthFailed"
@@ -67,6 +83,13 @@ const (
 
 	SecurityEventOIDCLoginSucceeded SecurityEventName = "SecurityEventOIDCLoginSucceeded"
 	SecurityEventOIDCLoginFailed    SecurityEventName = "SecurityEventOIDCLoginFailed"
+
+	SecurityEventBatchChangeApproversChanged SecurityEventName = "BatchChangeApproversChanged"
+	SecurityEventBatchSpecApproved           SecurityEventName = "BatchSpecApproved"
+	SecurityEventBatchChangePublishDenied    SecurityEventName = "BatchChangePublishDenied"
+
+	SecurityEventCodyGuardrailTriggered SecurityEventName = "CodyGuardrailTriggered"
+	SecurityEventCodyContextFiltered    SecurityEventName = "CodyContextFiltered"
 )
 
 // SecurityEvent contains information needed for logging a security-relevant event.
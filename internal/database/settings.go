@@ -6,10 +6,13 @@ import (
 	"strings"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -21,6 +24,9 @@ type SettingsStore interface {
 	Done(error) error
 	GetLatestSchemaSettings(context.Context, api.SettingsSubject) (*schema.Settings, error)
 	GetLatest(context.Context, api.SettingsSubject) (*api.Settings, error)
+	// GetLatestOrgIDs returns the ID of the latest settings of each of the given
+	// organizations. Organizations without settings are omitted.
+	GetLatestOrgIDs(ctx context.Context, orgIDs []int32) (map[int32]int32, error)
 	ListAll(ctx context.Context, impreciseSubstring string) ([]*api.Settings, error)
 	Transact(context.Context) (SettingsStore, error)
 	With(basestore.ShareableStore) SettingsStore
@@ -86,6 +92,13 @@ func (o *settingsStore) CreateIfUpToDate(ctx context.Context, subject api.Settin
 }
 
 func (o *settingsStore) GetLatest(ctx context.Context, subject api.SettingsSubject) (*api.Settings, error) {
+	return o.getLatest(ctx, o.Store, subject)
+}
+
+// getLatest returns the latest settings of the given subject using the given store. Callers
+// that edit settings must read from the primary, as edits are rejected unless they are based
+// on the latest settings ID.
+func (o *settingsStore) getLatest(ctx context.Context, store *basestore.Store, subject api.SettingsSubject) (*api.Settings, error) {
 	var cond *sqlf.Query
 	switch {
 	case subject.Org != nil:
@@ -102,7 +115,7 @@ func (o *settingsStore) GetLatest(ctx context.Context, subject api.SettingsSubje
 		LEFT JOIN users ON users.id=s.author_user_id
 		WHERE %s
 		ORDER BY id DESC LIMIT 1`, cond)
-	rows, err := o.Query(ctx, q)
+	rows, err := store.Query(dbconn.WithFailoverRetries(ctx), q)
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +131,7 @@ func (o *settingsStore) GetLatest(ctx context.Context, subject api.SettingsSubje
 }
 
 func (o *settingsStore) GetLatestSchemaSettings(ctx context.Context, subject api.SettingsSubject) (*schema.Settings, error) {
-	apiSettings, err := o.GetLatest(ctx, subject)
+	apiSettings, err := o.getLatest(ctx, o.Replica(ctx), subject)
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +150,25 @@ func (o *settingsStore) GetLatestSchemaSettings(ctx context.Context, subject api
 
 }
 
+const getLatestOrgIDsQuery = `
+SELECT org_id, MAX(id)
+FROM settings
+WHERE org_id = ANY(%s)
+GROUP BY org_id
+`
+
+func (o *settingsStore) GetLatestOrgIDs(ctx context.Context, orgIDs []int32) (map[int32]int32, error) {
+	if len(orgIDs) == 0 {
+		return map[int32]int32{}, nil
+	}
+	return scanLatestOrgIDs(o.Query(ctx, sqlf.Sprintf(getLatestOrgIDsQuery, pq.Array(orgIDs))))
+}
+
+var scanLatestOrgIDs = basestore.NewMapScanner(func(s dbutil.Scanner) (orgID, settingsID int32, err error) {
+	err = s.Scan(&orgID, &settingsID)
+	return orgID, settingsID, err
+})
+
 // ListAll lists ALL settings (across all users, orgs, etc).
 //
 // If impreciseSubstring is given, only settings whose raw JSONC string contains the substring are
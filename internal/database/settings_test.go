@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
@@ -146,3 +147,28 @@ func TestGetLatestSchemaSettings(t *testing.T) {
 		t.Errorf("Got invalid settings: %+v", settings)
 	}
 }
+
+func TestGetLatestOrgIDs(t *testing.T) {
+	t.Parallel()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+
+	withSettings, err := db.Orgs().Create(ctx, "with-settings", nil)
+	require.NoError(t, err)
+	withoutSettings, err := db.Orgs().Create(ctx, "without-settings", nil)
+	require.NoError(t, err)
+
+	first, err := db.Settings().CreateIfUpToDate(ctx, api.SettingsSubject{Org: &withSettings.ID}, nil, nil, `{}`)
+	require.NoError(t, err)
+	latest, err := db.Settings().CreateIfUpToDate(ctx, api.SettingsSubject{Org: &withSettings.ID}, &first.ID, nil, `{"experimentalFeatures": {}}`)
+	require.NoError(t, err)
+
+	ids, err := db.Settings().GetLatestOrgIDs(ctx, []int32{withSettings.ID, withoutSettings.ID})
+	require.NoError(t, err)
+	require.Equal(t, map[int32]int32{withSettings.ID: latest.ID}, ids)
+
+	ids, err = db.Settings().GetLatestOrgIDs(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+}
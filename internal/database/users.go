@@ -81,7 +81,9 @@ type UserStore interface {
 	ListForSCIM(context.Context, *UsersListOptions) (_ []*types.UserForSCIM, err error)
 	ListDates(context.Context) ([]types.UserDates, error)
 	ListByOrg(ctx context.Context, orgID int32, paginationArgs *PaginationArgs, query *string) ([]*types.User, error)
+	ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]int32, error)
 	RandomizePasswordAndClearPasswordResetRateLimit(context.Context, int32) error
+	ReassignOwnership(ctx context.Context, from, to int32) error
 	RecoverUsersList(context.Context, []int32) (_ []int32, err error)
 	RenewPasswordResetCode(context.Context, int32) (string, error)
 	SetIsSiteAdmin(ctx context.Context, id int32, isSiteAdmin bool) error
@@ -408,6 +410,15 @@ func (u *userStore) CreateInTransaction(ctx context.Context, info NewUser, spec
 		}
 	}
 
+	if err := OutboxEventsWith(u).Publish(ctx, OutboxEventArgs{
+		EventType:     types.OutboxEventUserCreated,
+		AggregateType: "user",
+		AggregateID:   strconv.Itoa(int(user.ID)),
+		Payload:       map[string]any{"user_id": user.ID, "username": user.Username},
+	}); err != nil {
+		return nil, err
+	}
+
 	{
 		// Run hooks.
 		//
@@ -648,6 +659,19 @@ func (u *userStore) DeleteList(ctx context.Context, ids []int32) (err error) {
 		return err
 	}
 
+	events := make([]OutboxEventArgs, 0, len(ids))
+	for _, id := range ids {
+		events = append(events, OutboxEventArgs{
+			EventType:     types.OutboxEventUserDeactivated,
+			AggregateType: "user",
+			AggregateID:   strconv.Itoa(int(id)),
+			Payload:       map[string]any{"user_id": id},
+		})
+	}
+	if err := OutboxEventsWith(tx).Publish(ctx, events...); err != nil {
+		return err
+	}
+
 	logUserDeletionEvents(ctx, NewDBWith(u.logger, u), ids, SecurityEventNameAccountDeleted)
 
 	return nil
@@ -784,7 +808,9 @@ func logUserDeletionEvents(ctx context.Context, db DB, ids []int32, name Securit
 	_ = db.EventLogs().BulkInsert(ctx, logEvents)
 }
 
-// RecoverUsersList recovers a list of users by their IDs.
+// RecoverUsersList recovers a list of users by their IDs. Users that were
+// deleted longer ago than the configured retention period are not recovered
+// and are omitted from the returned IDs.
 func (u *userStore) RecoverUsersList(ctx context.Context, ids []int32) (_ []int32, err error) {
 	tx, err := u.transact(ctx)
 	if err != nil {
@@ -792,6 +818,19 @@ func (u *userStore) RecoverUsersList(ctx context.Context, ids []int32) (_ []int3
 	}
 	defer func() { err = tx.Done(err) }()
 
+	// Users deleted before the retention period are about to be permanently
+	// deleted and cannot be recovered.
+	if retention := conf.DeletedUsersRetention(); retention > 0 {
+		ids, err = basestore.ScanInt32s(tx.Query(ctx, sqlf.Sprintf(
+			"SELECT id FROM users WHERE id = ANY(%s) AND (deleted_at IS NULL OR deleted_at > %s)",
+			pq.Array(ids),
+			time.Now().Add(-retention),
+		)))
+		if err != nil || len(ids) == 0 {
+			return nil, err
+		}
+	}
+
 	userIDs := make([]*sqlf.Query, len(ids))
 	for i := range ids {
 		userIDs[i] = sqlf.Sprintf("%d", ids[i])
@@ -862,6 +901,66 @@ func (u *userStore) RecoverUsersList(ctx context.Context, ids []int32) (_ []int3
 	return updateIds, nil
 }
 
+// ListDeletedBefore returns the IDs of up to limit users that were soft-deleted
+// before the given time.
+func (u *userStore) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]int32, error) {
+	return basestore.ScanInt32s(u.Query(ctx, sqlf.Sprintf(
+		"SELECT id FROM users WHERE deleted_at IS NOT NULL AND deleted_at < %s ORDER BY deleted_at, id LIMIT %s",
+		before,
+		limit,
+	)))
+}
+
+// ReassignOwnership transfers the batch changes, code monitors and access
+// tokens owned or created by the user with ID from to the user with ID to, so
+// that they are retained when the former is hard deleted. Access tokens that
+// authenticate as the user itself are not transferred.
+//
+// Batch changes are moved into the namespace of the new owner, which fails if
+// the new owner already has a batch change with the same name.
+func (u *userStore) ReassignOwnership(ctx context.Context, from, to int32) (err error) {
+	if from == to {
+		return errors.New("cannot reassign ownership to the same user")
+	}
+
+	tx, err := u.transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	for _, q := range reassignOwnershipQueries {
+		if err := tx.Exec(ctx, sqlf.Sprintf(q, to, from)); err != nil {
+			return errors.Wrap(err, "reassigning ownership")
+		}
+	}
+	return nil
+}
+
+// reassignOwnershipQueries each set a user ID column from the user with the
+// second argument to the user with the first argument.
+var reassignOwnershipQueries = []string{
+	"UPDATE batch_changes SET namespace_user_id = %s WHERE namespace_user_id = %s",
+	"UPDATE batch_changes SET creator_id = %s WHERE creator_id = %s",
+	"UPDATE batch_changes SET last_applier_id = %s WHERE last_applier_id = %s",
+	"UPDATE batch_specs SET namespace_user_id = %s WHERE namespace_user_id = %s",
+	"UPDATE batch_specs SET user_id = %s WHERE user_id = %s",
+	"UPDATE changeset_specs SET user_id = %s WHERE user_id = %s",
+	"UPDATE cm_monitors SET namespace_user_id = %s WHERE namespace_user_id = %s",
+	"UPDATE cm_monitors SET created_by = %s WHERE created_by = %s",
+	"UPDATE cm_monitors SET changed_by = %s WHERE changed_by = %s",
+	"UPDATE cm_queries SET created_by = %s WHERE created_by = %s",
+	"UPDATE cm_queries SET changed_by = %s WHERE changed_by = %s",
+	"UPDATE cm_emails SET created_by = %s WHERE created_by = %s",
+	"UPDATE cm_emails SET changed_by = %s WHERE changed_by = %s",
+	"UPDATE cm_webhooks SET created_by = %s WHERE created_by = %s",
+	"UPDATE cm_webhooks SET changed_by = %s WHERE changed_by = %s",
+	"UPDATE cm_slack_webhooks SET created_by = %s WHERE created_by = %s",
+	"UPDATE cm_slack_webhooks SET changed_by = %s WHERE changed_by = %s",
+	"UPDATE cm_recipients SET namespace_user_id = %s WHERE namespace_user_id = %s",
+	"UPDATE access_tokens SET creator_user_id = %s WHERE creator_user_id = %s AND subject_user_id <> creator_user_id",
+}
+
 // SetIsSiteAdmin sets the user with the given ID to be or not to be the site admin. It also assigns the role `SITE_ADMINISTRATOR`
 // to the user when `isSiteAdmin` is true and revokes the role when false.
 func (u *userStore) SetIsSiteAdmin(ctx context.Context, id int32, isSiteAdmin bool) error {
@@ -1045,6 +1144,8 @@ type UsersListOptions struct {
 	Usernames []string
 	// Only show users inside this org
 	OrgID int32
+	// SiteAdminsOnly, if set, only includes site admins.
+	SiteAdminsOnly bool
 
 	// InactiveSince filters out users that have had an eventlog entry with a
 	// `timestamp` greater-than-or-equal to the given timestamp.
@@ -1064,9 +1165,24 @@ type UsersListOptions struct {
 	// internally there are valid reasons to include them.
 	includeDeleted bool
 
+	// Cursor, if set, paginates users in ID order after the given cursor instead
+	// of with LimitOffset.Offset. An empty cursor requests the first page. List
+	// then fetches one user more than LimitOffset.Limit, so that callers can tell
+	// whether there is a next page with KeysetPage. Cursors are created with
+	// UserKeysetCursor. A non-zero LimitOffset.Offset skips users after the
+	// cursor, which is only used to resume from legacy offset cursors.
+	Cursor *string
+
 	*LimitOffset
 }
 
+var usersKeysetColumns = []KeysetColumn{{Field: "u.id"}}
+
+// UserKeysetCursor returns the cursor of the page of users following the given user.
+func UserKeysetCursor(user *types.User) (string, error) {
+	return EncodeKeysetCursor(user.ID)
+}
+
 func (u *userStore) List(ctx context.Context, opt *UsersListOptions) (_ []*types.User, err error) {
 	tr, ctx := trace.New(ctx, "database.Users.List", attribute.String("opt", fmt.Sprintf("%+v", opt)))
 	defer tr.FinishWithErr(&err)
@@ -1076,6 +1192,30 @@ func (u *userStore) List(ctx context.Context, opt *UsersListOptions) (_ []*types
 	}
 	conds := u.listSQL(*opt)
 
+	if opt.Cursor != nil {
+		p := &KeysetPagination{Columns: usersKeysetColumns}
+		if opt.LimitOffset != nil {
+			p.Limit = opt.Limit
+		}
+		if *opt.Cursor != "" {
+			var afterID int32
+			if err := DecodeKeysetCursor(*opt.Cursor, &afterID); err != nil {
+				return nil, err
+			}
+			p.After = []any{afterID}
+		}
+
+		args := p.SQL()
+		if args.Where != nil {
+			conds = append(conds, args.Where)
+		}
+		q := args.AppendLimitToQuery(args.AppendOrderToQuery(sqlf.Sprintf("WHERE %s", sqlf.Join(conds, "AND"))))
+		if opt.LimitOffset != nil && opt.Offset > 0 {
+			q = sqlf.Sprintf("%v OFFSET %d", q, opt.Offset)
+		}
+		return u.getBySQL(ctx, q)
+	}
+
 	q := sqlf.Sprintf("WHERE %s ORDER BY id ASC %s", sqlf.Join(conds, "AND"), opt.LimitOffset.SQL())
 	return u.getBySQL(ctx, q)
 }
@@ -1219,6 +1359,9 @@ func (*userStore) listSQL(opt UsersListOptions) (conds []*sqlf.Query) {
 	if opt.OrgID != 0 {
 		conds = append(conds, sqlf.Sprintf(orgMembershipCond, opt.OrgID))
 	}
+	if opt.SiteAdminsOnly {
+		conds = append(conds, sqlf.Sprintf("u.site_admin"))
+	}
 
 	if !opt.InactiveSince.IsZero() {
 		conds = append(conds, sqlf.Sprintf(listUsersInactiveCond, opt.InactiveSince))
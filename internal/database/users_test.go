@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/keegancsmith/sqlf"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -18,11 +19,13 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 // usernamesForTests is a list of test cases containing valid and invalid usernames.
@@ -1034,6 +1037,71 @@ func TestUsers_RecoverUsers(t *testing.T) {
 	})
 }
 
+func TestUsers_DeletedUsersRetention(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := actor.WithInternalActor(context.Background())
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{AuthDeletedUsersRetentionDays: 30}})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	expired, err := db.Users().Create(ctx, NewUser{Username: "expired"})
+	require.NoError(t, err)
+	recent, err := db.Users().Create(ctx, NewUser{Username: "recent"})
+	require.NoError(t, err)
+	require.NoError(t, db.Users().DeleteList(ctx, []int32{expired.ID, recent.ID}))
+	require.NoError(t, db.Users().Exec(ctx, sqlf.Sprintf("UPDATE users SET deleted_at = now() - interval '31 days' WHERE id = %s", expired.ID)))
+
+	ids, err := db.Users().ListDeletedBefore(ctx, time.Now().Add(-conf.DeletedUsersRetention()), 10)
+	require.NoError(t, err)
+	require.Equal(t, []int32{expired.ID}, ids)
+
+	recovered, err := db.Users().RecoverUsersList(ctx, []int32{expired.ID, recent.ID})
+	require.NoError(t, err)
+	require.Equal(t, []int32{recent.ID}, recovered)
+}
+
+func TestUsers_ReassignOwnership(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := actor.WithInternalActor(context.Background())
+
+	from, err := db.Users().Create(ctx, NewUser{Username: "from"})
+	require.NoError(t, err)
+	to, err := db.Users().Create(ctx, NewUser{Username: "to"})
+	require.NoError(t, err)
+	serviceAccount, err := db.Users().Create(ctx, NewUser{Username: "service-account"})
+	require.NoError(t, err)
+
+	s := CodeMonitorsWith(db)
+	fixtures := s.insertTestMonitor(actor.WithActor(ctx, actor.FromUser(from.ID)), t)
+
+	_, _, err = db.AccessTokens().Create(ctx, from.ID, []string{"user:all"}, "own token", from.ID)
+	require.NoError(t, err)
+	serviceTokenID, _, err := db.AccessTokens().Create(ctx, serviceAccount.ID, []string{"user:all"}, "service token", from.ID)
+	require.NoError(t, err)
+
+	require.Error(t, db.Users().ReassignOwnership(ctx, from.ID, from.ID))
+	require.NoError(t, db.Users().ReassignOwnership(ctx, from.ID, to.ID))
+	require.NoError(t, db.Users().HardDelete(ctx, from.ID))
+
+	monitor, err := s.GetMonitor(ctx, fixtures.monitor.ID)
+	require.NoError(t, err)
+	require.Equal(t, to.ID, monitor.UserID)
+	require.Equal(t, to.ID, monitor.CreatedBy)
+
+	serviceToken, err := db.AccessTokens().GetByID(ctx, serviceTokenID)
+	require.NoError(t, err)
+	require.Equal(t, to.ID, serviceToken.CreatorUserID)
+}
+
 func TestUsers_InvalidateSessions(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
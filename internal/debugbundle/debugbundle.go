@@ -0,0 +1,200 @@
+// Package debugbundle captures the operations, SQL queries, and logs of a single request
+// into a bundle that can be downloaded by site admins, e.g. to attach to a support
+// escalation.
+package debugbundle
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// RequestHeader is the header with which a site admin requests that a debug bundle
+	// is captured for a request.
+	RequestHeader = "X-Sourcegraph-Debug-Bundle"
+
+	// IDHeader is the response header that carries the ID of the captured bundle.
+	IDHeader = "X-Sourcegraph-Debug-Bundle-ID"
+)
+
+// maxEntries is the maximum number of operations, queries, and log entries recorded
+// each in a single bundle. Entries beyond the limit are counted in Bundle.Dropped.
+const maxEntries = 1000
+
+// Bundle describes everything recorded while serving a single request.
+type Bundle struct {
+	ID         string      `json:"id"`
+	TraceID    string      `json:"traceID,omitempty"`
+	UserID     int32       `json:"userID"`
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Status     int         `json:"status"`
+	Start      time.Time   `json:"start"`
+	Duration   Duration    `json:"durationMs"`
+	Operations []Operation `json:"operations"`
+	Queries    []Query     `json:"queries"`
+	Logs       []LogEntry  `json:"logs"`
+	Dropped    int         `json:"dropped,omitempty"`
+}
+
+// Operation is an observed operation invoked while serving the request.
+type Operation struct {
+	Name     string    `json:"name"`
+	Start    time.Time `json:"start"`
+	Duration Duration  `json:"durationMs"`
+	Error    string    `json:"error,omitempty"`
+	SpanID   string    `json:"spanID,omitempty"`
+}
+
+// Query is a SQL query issued through a base store while serving the request.
+type Query struct {
+	// Caller is the store method that issued the query.
+	Caller string `json:"caller"`
+	// Statement is the sanitized query text. Argument values are never recorded.
+	Statement string    `json:"statement"`
+	Args      int       `json:"args"`
+	Start     time.Time `json:"start"`
+	Duration  Duration  `json:"durationMs"`
+	// Rows is the number of rows affected by the query, or -1 if unknown.
+	Rows  int64  `json:"rows"`
+	Error string `json:"error,omitempty"`
+}
+
+// LogEntry is a message logged in the context of the request.
+type LogEntry struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// Duration is a time.Duration that is encoded as a number of milliseconds.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)), nil
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	ms, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return err
+	}
+	*d = Duration(ms * float64(time.Millisecond))
+	return nil
+}
+
+// Recorder accumulates the bundle of a single request. A nil Recorder records nothing,
+// so callers do not have to check whether a bundle was requested.
+type Recorder struct {
+	mu     sync.Mutex
+	bundle Bundle
+}
+
+// NewRecorder creates a Recorder for a request with the given method and URL.
+func NewRecorder(method, url string) *Recorder {
+	return &Recorder{bundle: Bundle{
+		ID:     uuid.NewString(),
+		Method: method,
+		URL:    url,
+		Start:  time.Now(),
+	}}
+}
+
+// ID returns the ID of the bundle being recorded.
+func (r *Recorder) ID() string {
+	return r.bundle.ID
+}
+
+// SetUser sets the user who made the request.
+func (r *Recorder) SetUser(userID int32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bundle.UserID = userID
+}
+
+// SetTraceID sets the ID of the trace of the request.
+func (r *Recorder) SetTraceID(traceID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bundle.TraceID = traceID
+}
+
+// RecordOperation records an operation invoked while serving the request.
+func (r *Recorder) RecordOperation(op Operation) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.bundle.Operations) >= maxEntries {
+		r.bundle.Dropped++
+		return
+	}
+	r.bundle.Operations = append(r.bundle.Operations, op)
+}
+
+// RecordQuery records a SQL query issued while serving the request.
+func (r *Recorder) RecordQuery(q Query) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.bundle.Queries) >= maxEntries {
+		r.bundle.Dropped++
+		return
+	}
+	r.bundle.Queries = append(r.bundle.Queries, q)
+}
+
+// RecordLog records a message logged while serving the request.
+func (r *Recorder) RecordLog(entry LogEntry) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.bundle.Logs) >= maxEntries {
+		r.bundle.Dropped++
+		return
+	}
+	r.bundle.Logs = append(r.bundle.Logs, entry)
+}
+
+// Finish returns the recorded bundle of a request that completed with the given status.
+// Entries recorded afterwards, e.g. by goroutines outliving the request, are not part
+// of the returned bundle.
+func (r *Recorder) Finish(status int) *Bundle {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bundle := r.bundle
+	bundle.Status = status
+	bundle.Duration = Duration(time.Since(bundle.Start))
+	bundle.Operations = append([]Operation(nil), bundle.Operations...)
+	bundle.Queries = append([]Query(nil), bundle.Queries...)
+	bundle.Logs = append([]LogEntry(nil), bundle.Logs...)
+	return &bundle
+}
+
+type recorderKey struct{}
+
+// WithRecorder returns a context that records into the given Recorder.
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// FromContext returns the Recorder of the given context, or nil if no debug bundle is
+// being captured.
+func FromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
@@ -0,0 +1,104 @@
+package debugbundle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log"
+	"github.com/sourcegraph/log/logtest"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder("POST", "/.api/graphql?Search")
+	ctx := WithRecorder(context.Background(), recorder)
+	if FromContext(ctx) != recorder {
+		t.Fatalf("expected the recorder of the context")
+	}
+
+	for i := 0; i < maxEntries+2; i++ {
+		FromContext(ctx).RecordQuery(Query{Statement: "SELECT 1"})
+	}
+	FromContext(ctx).RecordOperation(Operation{Name: "Search"})
+
+	bundle := recorder.Finish(200)
+	if bundle.ID == "" || bundle.Method != "POST" || bundle.URL != "/.api/graphql?Search" || bundle.Status != 200 {
+		t.Errorf("unexpected bundle. id=%q method=%q url=%q status=%d", bundle.ID, bundle.Method, bundle.URL, bundle.Status)
+	}
+	if len(bundle.Queries) != maxEntries || bundle.Dropped != 2 {
+		t.Errorf("unexpected queries. want=%d have=%d dropped=%d", maxEntries, len(bundle.Queries), bundle.Dropped)
+	}
+	if len(bundle.Operations) != 1 || bundle.Operations[0].Name != "Search" {
+		t.Errorf("unexpected operations: %v", bundle.Operations)
+	}
+
+	// Entries recorded after the request finished are not part of the bundle
+	recorder.RecordOperation(Operation{Name: "Late"})
+	if len(bundle.Operations) != 1 {
+		t.Errorf("unexpected operations after finish: %v", bundle.Operations)
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	recorder := FromContext(context.Background())
+	if recorder != nil {
+		t.Fatalf("unexpected recorder without a bundle")
+	}
+
+	// Must not panic
+	recorder.RecordQuery(Query{})
+	recorder.RecordOperation(Operation{})
+	recorder.RecordLog(LogEntry{})
+
+	logger := logtest.Scoped(t)
+	if Logger(context.Background(), logger) != logger {
+		t.Errorf("expected the logger to be returned as-is without a bundle")
+	}
+}
+
+func TestLogger(t *testing.T) {
+	recorder := NewRecorder("GET", "/search")
+	ctx := WithRecorder(context.Background(), recorder)
+
+	logger, exportLogs := logtest.Captured(t)
+	scoped := Logger(ctx, logger).Scoped("search", "").With(log.String("query", "repo:foo"))
+	scoped.Warn("slow search", log.Int("results", 3))
+	scoped.With(log.Bool("retry", true)).Error("search failed")
+
+	if logs := exportLogs(); len(logs) != 2 {
+		t.Fatalf("expected messages to be logged by the underlying logger. want=%d have=%d", 2, len(logs))
+	}
+
+	entries := recorder.Finish(200).Logs
+	if len(entries) != 2 {
+		t.Fatalf("unexpected number of log entries. want=%d have=%d", 2, len(entries))
+	}
+	if entries[0].Level != "warn" || entries[0].Message != "slow search" {
+		t.Errorf("unexpected log entry. level=%q message=%q", entries[0].Level, entries[0].Message)
+	}
+	if entries[0].Fields["query"] != "repo:foo" || entries[0].Fields["results"] != int64(3) {
+		t.Errorf("unexpected log fields: %v", entries[0].Fields)
+	}
+	if entries[1].Level != "error" || entries[1].Fields["retry"] != true || entries[1].Fields["query"] != "repo:foo" {
+		t.Errorf("unexpected log entry. level=%q fields=%v", entries[1].Level, entries[1].Fields)
+	}
+}
+
+func TestDurationJSON(t *testing.T) {
+	data, err := json.Marshal(Duration(1500 * time.Microsecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "1.500" {
+		t.Errorf("unexpected encoding. want=%q have=%q", "1.500", string(data))
+	}
+
+	var d Duration
+	if err := json.Unmarshal(data, &d); err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(d) != 1500*time.Microsecond {
+		t.Errorf("unexpected decoded duration. want=%s have=%s", 1500*time.Microsecond, time.Duration(d))
+	}
+}
@@ -0,0 +1,95 @@
+package debugbundle
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger returns a logger that records every message logged through it into the bundle
+// being captured for ctx, in addition to logging it with the given logger. The given
+// logger is returned as-is if no bundle is being captured.
+func Logger(ctx context.Context, logger log.Logger) log.Logger {
+	r := FromContext(ctx)
+	if r == nil {
+		return logger
+	}
+
+	return &recordingLogger{Logger: logger, recorder: r}
+}
+
+type recordingLogger struct {
+	log.Logger
+	recorder *Recorder
+	fields   []log.Field
+}
+
+func (l *recordingLogger) wrap(logger log.Logger, fields ...log.Field) log.Logger {
+	return &recordingLogger{
+		Logger:   logger,
+		recorder: l.recorder,
+		fields:   append(append([]log.Field(nil), l.fields...), fields...),
+	}
+}
+
+func (l *recordingLogger) Scoped(scope, description string) log.Logger {
+	return l.wrap(l.Logger.Scoped(scope, description))
+}
+
+func (l *recordingLogger) With(fields ...log.Field) log.Logger {
+	return l.wrap(l.Logger.With(fields...), fields...)
+}
+
+func (l *recordingLogger) WithTrace(tc log.TraceContext) log.Logger {
+	return l.wrap(l.Logger.WithTrace(tc))
+}
+
+func (l *recordingLogger) AddCallerSkip(skip int) log.Logger {
+	return l.wrap(l.Logger.AddCallerSkip(skip))
+}
+
+func (l *recordingLogger) IncreaseLevel(scope, description string, level log.Level) log.Logger {
+	return l.wrap(l.Logger.IncreaseLevel(scope, description, level))
+}
+
+func (l *recordingLogger) Debug(message string, fields ...log.Field) {
+	l.record("debug", message, fields)
+	l.Logger.AddCallerSkip(1).Debug(message, fields...)
+}
+
+func (l *recordingLogger) Info(message string, fields ...log.Field) {
+	l.record("info", message, fields)
+	l.Logger.AddCallerSkip(1).Info(message, fields...)
+}
+
+func (l *recordingLogger) Warn(message string, fields ...log.Field) {
+	l.record("warn", message, fields)
+	l.Logger.AddCallerSkip(1).Warn(message, fields...)
+}
+
+func (l *recordingLogger) Error(message string, fields ...log.Field) {
+	l.record("error", message, fields)
+	l.Logger.AddCallerSkip(1).Error(message, fields...)
+}
+
+// record adds a log entry to the bundle. Messages are recorded regardless of the level of
+// the underlying logger, so that debug logs are part of the bundle even when they are
+// not output.
+func (l *recordingLogger) record(level, message string, fields []log.Field) {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range l.fields {
+		field.AddTo(enc)
+	}
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	l.recorder.RecordLog(LogEntry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: message,
+		Fields:  enc.Fields,
+	})
+}
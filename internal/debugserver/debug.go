@@ -12,6 +12,7 @@ import (
 
 	"github.com/felixge/fgprof"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/sourcegraph/sourcegraph/internal/env"
@@ -120,7 +121,7 @@ func NewServerRoutine(ready <-chan struct{}, extra ...Endpoint) goroutine.Backgr
 		router.Handle("/debug/pprof/profile", http.HandlerFunc(pprof.Profile))
 		router.Handle("/debug/pprof/symbol", http.HandlerFunc(pprof.Symbol))
 		router.Handle("/debug/pprof/trace", http.HandlerFunc(pprof.Trace))
-		router.Handle("/metrics", promhttp.Handler())
+		router.Handle("/metrics", metricsHandler())
 
 		// This path acts as a wildcard and should appear after more specific entries.
 		router.PathPrefix("/debug/pprof").HandlerFunc(pprof.Index)
@@ -137,3 +138,15 @@ func NewServerRoutine(ready <-chan struct{}, extra ...Endpoint) goroutine.Backgr
 
 	return httpserver.NewFromAddr(addr, &http.Server{Handler: handler})
 }
+
+// metricsHandler is equivalent to promhttp.Handler, but negotiates the OpenMetrics
+// exposition format with scrapers that request it. Exemplars, which link duration
+// histograms to traces, are only exposed in the OpenMetrics format.
+func metricsHandler() http.Handler {
+	return promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
+			EnableOpenMetrics: true,
+		}),
+	)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/redispubsub"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -79,6 +80,10 @@ func scanRepoEmbeddingJob(s dbutil.Scanner) (*RepoEmbeddingJob, error) {
 	return &job, nil
 }
 
+// RepoEmbeddingJobMaxNumRetries is the maximum number of attempts of a repo
+// embedding job. Jobs are not retried.
+const RepoEmbeddingJobMaxNumRetries = 1
+
 func NewRepoEmbeddingJobWorkerStore(observationCtx *observation.Context, dbHandle basestore.TransactableHandle) dbworkerstore.Store[*RepoEmbeddingJob] {
 	return dbworkerstore.New(observationCtx, dbHandle, dbworkerstore.Options[*RepoEmbeddingJob]{
 		Name:              "repo_embedding_job_worker",
@@ -88,7 +93,8 @@ func NewRepoEmbeddingJobWorkerStore(observationCtx *observation.Context, dbHandl
 		OrderByExpression: sqlf.Sprintf("repo_embedding_jobs.queued_at, repo_embedding_jobs.id"),
 		StalledMaxAge:     time.Second * 60,
 		MaxNumResets:      5,
-		MaxNumRetries:     1,
+		MaxNumRetries:     RepoEmbeddingJobMaxNumRetries,
+		OnStateChange:     publishRepoEmbeddingJobStateChange,
 	})
 }
 
@@ -182,7 +188,19 @@ repositories_matching_policy AS (
 SELECT DISTINCT ON (rmp.id) rmp.id, rmp.last_changed
 FROM repositories_matching_policy rmp
 LEFT JOIN last_queued_jobs lqj ON lqj.repo_id = rmp.id
-WHERE lqj.queued_at IS NULL OR lqj.queued_at < current_timestamp - (%s * '1 second'::interval);
+WHERE
+	(lqj.queued_at IS NULL OR lqj.queued_at < current_timestamp - (%s * '1 second'::interval)) AND
+	-- The embeddings of archived repositories are not recomputed if the archival
+	-- policy drops them.
+	NOT EXISTS (
+		SELECT 1
+		FROM repo_archival ra
+		WHERE
+			ra.repo_id = rmp.id AND
+			ra.archived_at IS NOT NULL AND
+			-- Does not depend on the repository, so it is only evaluated once.
+			(SELECT rap.drop_embeddings FROM repo_archival_policy rap WHERE rap.id = 1)
+	);
 `
 
 type EmbeddableRepoOpts struct {
@@ -373,9 +391,17 @@ func (s *repoEmbeddingJobsStore) UpdateRepoEmbeddingJobStats(ctx context.Context
 		stats.TextIndexStats.BytesEmbedded,
 	)
 
-	return s.Exec(ctx, q)
+	if err := s.Exec(ctx, q); err != nil {
+		return err
+	}
+
+	// Notify subscribers of the job's progress.
+	publishRepoEmbeddingJobStateChange(ctx, jobID)
+	return nil
 }
 
+var publishRepoEmbeddingJobStateChange = redispubsub.RecordStateChangeHook("repo_embedding_jobs")
+
 const getLastFinishedRepoEmbeddingJob = `
 SELECT %s
 FROM repo_embedding_jobs
@@ -65,6 +65,10 @@ func (s *noOpUploadStore) ExpireObjects(ctx context.Context, prefix string, maxA
 	return nil
 }
 
+func (s *noOpUploadStore) ListOlderThan(ctx context.Context, prefix string, maxAge time.Duration) (*iterator.Iterator[string], error) {
+	return nil, nil
+}
+
 type mockUploadStore struct {
 	files map[string][]byte
 }
@@ -115,6 +119,10 @@ func (s *mockUploadStore) ExpireObjects(ctx context.Context, prefix string, maxA
 	return nil
 }
 
+func (s *mockUploadStore) ListOlderThan(ctx context.Context, prefix string, maxAge time.Duration) (*iterator.Iterator[string], error) {
+	return nil, nil
+}
+
 func TestRepoEmbeddingIndexStorage(t *testing.T) {
 	index := &RepoEmbeddingIndex{
 		RepoName: api.RepoName("repo"),
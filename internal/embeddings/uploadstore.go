@@ -3,10 +3,13 @@ package embeddings
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf/deploy"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
@@ -29,12 +32,17 @@ type EmbeddingsUploadStoreConfig struct {
 	GCSProjectID               string
 	GCSCredentialsFile         string
 	GCSCredentialsFileContents string
+
+	// KeyRotationInterval is how often indexes are rewrapped with the current
+	// version of the embeddings index key.
+	KeyRotationInterval time.Duration
 }
 
 func (c *EmbeddingsUploadStoreConfig) Load() {
 	c.Backend = strings.ToLower(c.Get("EMBEDDINGS_UPLOAD_BACKEND", "blobstore", "The target file service for embeddings. S3, GCS, and Blobstore are supported."))
 	c.ManageBucket = c.GetBool("EMBEDDINGS_UPLOAD_MANAGE_BUCKET", "false", "Whether or not the client should manage the target bucket configuration.")
 	c.Bucket = c.Get("EMBEDDINGS_UPLOAD_BUCKET", "embeddings", "The name of the bucket to store embeddings in.")
+	c.KeyRotationInterval = c.GetInterval("EMBEDDINGS_UPLOAD_KEY_ROTATION_INTERVAL", "24h", "How often to re-encrypt the data keys of embeddings indexes that are not encrypted with the current version of the embeddings index key.")
 
 	if c.Backend != "blobstore" && c.Backend != "s3" && c.Backend != "gcs" {
 		c.AddError(errors.Errorf("invalid backend %q for EMBEDDINGS_UPLOAD_BACKEND: must be S3, GCS, or Blobstore", c.Backend))
@@ -60,7 +68,9 @@ func (c *EmbeddingsUploadStoreConfig) Load() {
 
 var EmbeddingsUploadStoreConfigInst = &EmbeddingsUploadStoreConfig{}
 
-func NewEmbeddingsUploadStore(ctx context.Context, observationCtx *observation.Context, conf *EmbeddingsUploadStoreConfig) (uploadstore.Store, error) {
+// NewEmbeddingsUploadStore returns the upload store configured by conf. Indexes are
+// encrypted with the embeddings index key of the keyring, if one is configured.
+func NewEmbeddingsUploadStore(ctx context.Context, observationCtx *observation.Context, conf *EmbeddingsUploadStoreConfig) (*uploadstore.EncryptedStore, error) {
 	c := uploadstore.Config{
 		Backend:      conf.Backend,
 		ManageBucket: conf.ManageBucket,
@@ -79,5 +89,12 @@ func NewEmbeddingsUploadStore(ctx context.Context, observationCtx *observation.C
 			CredentialsFileContents: conf.GCSCredentialsFileContents,
 		},
 	}
-	return uploadstore.CreateLazy(ctx, c, uploadstore.NewOperations(observationCtx, "embeddings", "uploadstore"))
+	store, err := uploadstore.CreateLazy(ctx, c, uploadstore.NewOperations(observationCtx, "embeddings", "uploadstore"))
+	if err != nil {
+		return nil, err
+	}
+
+	return uploadstore.NewEncryptedStore(store, func() encryption.Key {
+		return keyring.Default().EmbeddingsIndexKey
+	}), nil
 }
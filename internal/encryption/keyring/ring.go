@@ -92,6 +92,27 @@ func NewRing(ctx context.Context, keyConfig *schema.EncryptionKeys) (*Ring, erro
 		}
 	}
 
+	if keyConfig.CodeIntelUploadKey != nil {
+		r.CodeIntelUploadKey, err = NewKey(ctx, keyConfig.CodeIntelUploadKey, keyConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if keyConfig.CodyPromptLogKey != nil {
+		r.CodyPromptLogKey, err = NewKey(ctx, keyConfig.CodyPromptLogKey, keyConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if keyConfig.EmbeddingsIndexKey != nil {
+		r.EmbeddingsIndexKey, err = NewKey(ctx, keyConfig.EmbeddingsIndexKey, keyConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if keyConfig.ExternalServiceKey != nil {
 		r.ExternalServiceKey, err = NewKey(ctx, keyConfig.ExternalServiceKey, keyConfig)
 		if err != nil {
@@ -139,6 +160,9 @@ func NewRing(ctx context.Context, keyConfig *schema.EncryptionKeys) (*Ring, erro
 
 type Ring struct {
 	BatchChangesCredentialKey encryption.Key
+	CodeIntelUploadKey        encryption.Key
+	CodyPromptLogKey          encryption.Key
+	EmbeddingsIndexKey        encryption.Key
 	ExternalServiceKey        encryption.Key
 	GitHubAppKey              encryption.Key
 	OutboundWebhookKey        encryption.Key
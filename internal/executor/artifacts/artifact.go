@@ -0,0 +1,81 @@
+// Package artifacts stores named files, such as logs, SARIF reports or coverage
+// data, that executor jobs upload while they run, and serves them through signed
+// download links until they expire.
+package artifacts
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/grafana/regexp"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// Artifact is a file uploaded by an executor job.
+type Artifact struct {
+	ID int
+	// Queue and JobID identify the job that uploaded the artifact.
+	Queue string
+	JobID int
+	// Name is unique per job. Uploading an artifact with the name of an existing
+	// artifact of the job replaces it.
+	Name        string
+	ContentType string
+	Size        int64
+	// ObjectKey is the key of the artifact in the upload store.
+	ObjectKey string
+	// SigningSecret signs the download links of the artifact.
+	SigningSecret []byte
+	CreatedAt     time.Time
+	// ExpiresAt is the time after which the artifact is deleted.
+	ExpiresAt time.Time
+}
+
+var nameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// ValidName reports whether name can be used as the name of an artifact. Names
+// are used in object keys and file names, so they are restricted to at most 128
+// letters, digits, dots, dashes and underscores.
+func ValidName(name string) bool {
+	return nameRegexp.MatchString(name)
+}
+
+// objectKey returns the key of the artifact with the given name of the job in
+// the upload store.
+func objectKey(queue string, jobID int, name string) string {
+	return queue + "/" + strconv.Itoa(jobID) + "/" + name
+}
+
+var artifactColumns = []*sqlf.Query{
+	sqlf.Sprintf("id"),
+	sqlf.Sprintf("queue"),
+	sqlf.Sprintf("job_id"),
+	sqlf.Sprintf("name"),
+	sqlf.Sprintf("content_type"),
+	sqlf.Sprintf("size"),
+	sqlf.Sprintf("object_key"),
+	sqlf.Sprintf("signing_secret"),
+	sqlf.Sprintf("created_at"),
+	sqlf.Sprintf("expires_at"),
+}
+
+func scanArtifact(s dbutil.Scanner) (*Artifact, error) {
+	var a Artifact
+	if err := s.Scan(
+		&a.ID,
+		&a.Queue,
+		&a.JobID,
+		&a.Name,
+		&a.ContentType,
+		&a.Size,
+		&a.ObjectKey,
+		&a.SigningSecret,
+		&a.CreatedAt,
+		&a.ExpiresAt,
+	); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,44 @@
+package artifacts
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/signedlink"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DownloadURL returns a link to download the artifact, signed with the secret of
+// the artifact and valid until expires. Anyone with the link can download the
+// artifact, so it must only be handed to users that may access the job.
+func DownloadURL(externalURL *url.URL, artifact *Artifact, expires time.Time) string {
+	return signedlink.URL(externalURL, "/.api/executors/artifacts", artifact.ID, artifact.SigningSecret, expires)
+}
+
+// NewDownloadHandler returns the handler serving the signed download links of
+// artifacts created by DownloadURL.
+func NewDownloadHandler(logger log.Logger, db database.DB, uploadStore uploadstore.Store) http.Handler {
+	store := NewStore(db)
+	return signedlink.NewHandler(logger, "executor job artifact", uploadStore, func(ctx context.Context, id int) (*signedlink.Object, error) {
+		artifact, err := store.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return nil, signedlink.ErrNotFound
+			}
+			return nil, err
+		}
+		return &signedlink.Object{
+			ID:            artifact.ID,
+			SigningSecret: artifact.SigningSecret,
+			ObjectKey:     artifact.ObjectKey,
+			ContentType:   artifact.ContentType,
+			Filename:      artifact.Name,
+		}, nil
+	})
+}
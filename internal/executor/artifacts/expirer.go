@@ -0,0 +1,44 @@
+package artifacts
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// expireBatchSize is the maximum number of artifacts deleted per expirer run.
+const expireBatchSize = 500
+
+// NewExpirer returns a background routine that deletes the artifacts that
+// exceeded their retention from the database and the upload store.
+func NewExpirer(ctx context.Context, db database.DB, uploadStore uploadstore.Store, interval time.Duration) goroutine.BackgroundRoutine {
+	store := NewStore(db)
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		goroutine.HandlerFunc(func(ctx context.Context) error {
+			return expire(ctx, store, uploadStore, time.Now())
+		}),
+		goroutine.WithName("executors.artifacts.expirer"),
+		goroutine.WithDescription("deletes executor job artifacts that exceeded their retention"),
+		goroutine.WithInterval(interval),
+	)
+}
+
+func expire(ctx context.Context, store Store, uploadStore uploadstore.Store, now time.Time) error {
+	expired, err := store.DeleteExpired(ctx, now, expireBatchSize)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, artifact := range expired {
+		if err := uploadStore.Delete(ctx, artifact.ObjectKey); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "deleting artifact %d", artifact.ID))
+		}
+	}
+	return errs
+}
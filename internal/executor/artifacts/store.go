@@ -0,0 +1,120 @@
+package artifacts
+
+import (
+	"context"
+	"crypto/rand"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ErrNotFound is returned when an artifact does not exist.
+var ErrNotFound = errors.New("executor job artifact not found")
+
+// Store records the artifacts uploaded by executor jobs.
+type Store interface {
+	// Upsert records the artifact uploaded by the job, replacing an artifact of
+	// the job with the same name.
+	Upsert(ctx context.Context, queue string, jobID int, name, contentType string, size int64, expiresAt time.Time) (*Artifact, error)
+
+	// GetByID returns the artifact with the given ID, or ErrNotFound.
+	GetByID(ctx context.Context, id int) (*Artifact, error)
+
+	// ListByJob returns the artifacts of the job, ordered by name.
+	ListByJob(ctx context.Context, queue string, jobID int) ([]*Artifact, error)
+
+	// DeleteExpired deletes at most limit artifacts that expired before now and
+	// returns them, so that their objects can be removed from the upload store.
+	DeleteExpired(ctx context.Context, now time.Time, limit int) ([]*Artifact, error)
+}
+
+type store struct {
+	*basestore.Store
+}
+
+// NewStore returns a Store backed by the given database.
+func NewStore(db database.DB) Store {
+	return &store{Store: basestore.NewWithHandle(db.Handle())}
+}
+
+const upsertFmtstr = `
+INSERT INTO executor_job_artifacts (queue, job_id, name, content_type, size, object_key, signing_secret, expires_at)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
+ON CONFLICT (queue, job_id, name) DO UPDATE SET
+	content_type = EXCLUDED.content_type,
+	size = EXCLUDED.size,
+	created_at = NOW(),
+	expires_at = EXCLUDED.expires_at
+RETURNING %s
+`
+
+func (s *store) Upsert(ctx context.Context, queue string, jobID int, name, contentType string, size int64, expiresAt time.Time) (*Artifact, error) {
+	if !ValidName(name) {
+		return nil, errors.Newf("invalid artifact name %q", name)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	return scanArtifact(s.QueryRow(ctx, sqlf.Sprintf(
+		upsertFmtstr,
+		queue, jobID, name, contentType, size, objectKey(queue, jobID, name), secret, expiresAt,
+		sqlf.Join(artifactColumns, ","),
+	)))
+}
+
+const listFmtstr = `
+SELECT %s
+FROM executor_job_artifacts
+WHERE %s
+ORDER BY name
+`
+
+func (s *store) GetByID(ctx context.Context, id int) (*Artifact, error) {
+	artifacts, err := s.list(ctx, sqlf.Sprintf("id = %s", id))
+	if err != nil {
+		return nil, err
+	}
+	if len(artifacts) == 0 {
+		return nil, ErrNotFound
+	}
+	return artifacts[0], nil
+}
+
+func (s *store) ListByJob(ctx context.Context, queue string, jobID int) ([]*Artifact, error) {
+	return s.list(ctx, sqlf.Sprintf("queue = %s AND job_id = %s", queue, jobID))
+}
+
+func (s *store) list(ctx context.Context, cond *sqlf.Query) ([]*Artifact, error) {
+	return basestore.NewSliceScanner(scanArtifact)(s.Query(ctx, sqlf.Sprintf(
+		listFmtstr,
+		sqlf.Join(artifactColumns, ","),
+		cond,
+	)))
+}
+
+const deleteExpiredFmtstr = `
+DELETE FROM executor_job_artifacts
+WHERE id IN (
+	SELECT id FROM executor_job_artifacts
+	WHERE expires_at < %s
+	ORDER BY expires_at
+	LIMIT %s
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING %s
+`
+
+func (s *store) DeleteExpired(ctx context.Context, now time.Time, limit int) ([]*Artifact, error) {
+	return basestore.NewSliceScanner(scanArtifact)(s.Query(ctx, sqlf.Sprintf(
+		deleteExpiredFmtstr,
+		now, limit,
+		sqlf.Join(artifactColumns, ","),
+	)))
+}
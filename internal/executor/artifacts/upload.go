@@ -0,0 +1,79 @@
+package artifacts
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+)
+
+// NewUploadHandler returns the handler executors upload the artifacts of their
+// jobs to. The route must provide the queueName and name variables, and must be
+// authenticated with the token of the job identified by the X-Sourcegraph-Job-ID
+// header.
+func NewUploadHandler(logger log.Logger, db database.DB, uploadStore uploadstore.Store, config *UploadStoreConfig) http.Handler {
+	return &uploadHandler{
+		logger:      logger,
+		store:       NewStore(db),
+		uploadStore: uploadStore,
+		retention:   config.Retention,
+		maxSize:     config.MaxSize,
+	}
+}
+
+type uploadHandler struct {
+	logger      log.Logger
+	store       Store
+	uploadStore uploadstore.Store
+	retention   time.Duration
+	maxSize     int64
+}
+
+func (h *uploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	queue := mux.Vars(r)["queueName"]
+	name := mux.Vars(r)["name"]
+	if !ValidName(name) {
+		http.Error(w, "invalid artifact name: names may only contain letters, digits, '.', '-' and '_'", http.StatusBadRequest)
+		return
+	}
+	// The job ID was checked against the job token by the authentication middleware.
+	jobID, err := strconv.Atoi(r.Header.Get("X-Sourcegraph-Job-ID"))
+	if err != nil {
+		http.Error(w, "invalid job ID", http.StatusBadRequest)
+		return
+	}
+	if h.maxSize > 0 && r.ContentLength > h.maxSize {
+		http.Error(w, "artifact is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	body := io.Reader(r.Body)
+	if h.maxSize > 0 {
+		body = http.MaxBytesReader(w, r.Body, h.maxSize)
+	}
+	size, err := h.uploadStore.Upload(r.Context(), objectKey(queue, jobID, name), body)
+	if err != nil {
+		h.logger.Error("failed to upload executor job artifact", log.String("queue", queue), log.Int("jobID", jobID), log.String("name", name), log.Error(err))
+		http.Error(w, "failed to upload artifact", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := h.store.Upsert(r.Context(), queue, jobID, name, contentType, size, time.Now().Add(h.retention)); err != nil {
+		h.logger.Error("failed to record executor job artifact", log.String("queue", queue), log.Int("jobID", jobID), log.String("name", name), log.Error(err))
+		http.Error(w, "failed to upload artifact", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,80 @@
+package artifacts
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+)
+
+func TestValidName(t *testing.T) {
+	for _, name := range []string{"results.sarif", "coverage-report.xml", "build_log.txt", "a"} {
+		assert.True(t, ValidName(name), name)
+	}
+	for _, name := range []string{"", ".hidden", "../etc/passwd", "dir/file.txt", "with space", strings.Repeat("a", 129)} {
+		assert.False(t, ValidName(name), name)
+	}
+}
+
+type upsertedArtifact struct {
+	queue, name, contentType string
+	jobID                    int
+	size                     int64
+}
+
+type recordingStore struct {
+	Store
+	upserted []upsertedArtifact
+}
+
+func (s *recordingStore) Upsert(_ context.Context, queue string, jobID int, name, contentType string, size int64, _ time.Time) (*Artifact, error) {
+	s.upserted = append(s.upserted, upsertedArtifact{queue: queue, name: name, contentType: contentType, jobID: jobID, size: size})
+	return &Artifact{}, nil
+}
+
+func TestUploadHandler(t *testing.T) {
+	uploaded := map[string]string{}
+	uploadStore := mocks.NewMockStore()
+	uploadStore.UploadFunc.SetDefaultHook(func(_ context.Context, key string, r io.Reader) (int64, error) {
+		b, err := io.ReadAll(r)
+		uploaded[key] = string(b)
+		return int64(len(b)), err
+	})
+	store := &recordingStore{}
+
+	router := mux.NewRouter()
+	router.Path("/artifacts/{queueName}/{name}").Handler(&uploadHandler{
+		logger:      logtest.Scoped(t),
+		store:       store,
+		uploadStore: uploadStore,
+		retention:   time.Hour,
+		maxSize:     16,
+	})
+	put := func(name, jobID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/artifacts/batches/"+name, strings.NewReader(body))
+		req.Header.Set("X-Sourcegraph-Job-ID", jobID)
+		req.Header.Set("Content-Type", "text/plain")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := put("build.log", "42", "all good")
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, map[string]string{"batches/42/build.log": "all good"}, uploaded)
+	assert.Equal(t, []upsertedArtifact{{queue: "batches", name: "build.log", contentType: "text/plain", jobID: 42, size: 8}}, store.upserted)
+
+	assert.Equal(t, http.StatusBadRequest, put(".build.log", "42", "all good").Code)
+	assert.Equal(t, http.StatusBadRequest, put("build.log", "", "all good").Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, put("build.log", "42", strings.Repeat("x", 17)).Code)
+}
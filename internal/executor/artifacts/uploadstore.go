@@ -0,0 +1,37 @@
+package artifacts
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/signedlink"
+)
+
+// UploadStoreConfig configures the upload store that executor job artifacts are
+// uploaded to through the frontend, and how long they are retained.
+type UploadStoreConfig struct {
+	*signedlink.UploadStoreConfig
+
+	// Retention is how long artifacts are kept after they were uploaded.
+	Retention time.Duration
+	// MaxSize is the maximum size of a single artifact in bytes.
+	MaxSize int64
+}
+
+func (c *UploadStoreConfig) Load() {
+	c.UploadStoreConfig.Load()
+	c.Retention = c.GetInterval("EXECUTOR_ARTIFACTS_RETENTION", "720h", "How long executor job artifacts are kept after they were uploaded.")
+	c.MaxSize = int64(c.GetInt("EXECUTOR_ARTIFACTS_MAX_SIZE_BYTES", "104857600", "The maximum size of a single executor job artifact in bytes."))
+}
+
+// UploadStoreConfigInst is loaded by the services that read or write executor job artifacts.
+var UploadStoreConfigInst = &UploadStoreConfig{
+	UploadStoreConfig: signedlink.NewUploadStoreConfig("EXECUTOR_ARTIFACTS", "executor job artifacts", "executor-artifacts"),
+}
+
+// NewUploadStore returns the upload store configured by conf.
+func NewUploadStore(ctx context.Context, observationCtx *observation.Context, conf *UploadStoreConfig) (uploadstore.Store, error) {
+	return signedlink.NewUploadStore(ctx, observationCtx, conf.UploadStoreConfig, "executor_artifacts")
+}
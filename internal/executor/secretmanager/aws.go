@@ -0,0 +1,121 @@
+package secretmanager
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// awsProvider reads secrets with the GetSecretValue action of the AWS Secrets
+// Manager JSON API. Values of AWS Secrets Manager are not leased, so there is
+// nothing to revoke.
+type awsProvider struct {
+	endpoint    string
+	region      string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	doer        httpcli.Doer
+}
+
+func newAWSProvider(ctx context.Context, c *schema.AWSSecretsManager) (*awsProvider, error) {
+	var opts []func(*config.LoadOptions) error
+	if c.Region != "" {
+		opts = append(opts, config.WithRegion(c.Region))
+	}
+	if c.CredentialsFile != "" {
+		opts = append(opts, config.WithSharedCredentialsFiles([]string{c.CredentialsFile}))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading AWS config")
+	}
+	if cfg.Region == "" {
+		return nil, errors.New("executors.secretManagers.awsSecretsManager.region is not set and there is no default AWS region")
+	}
+
+	return &awsProvider{
+		endpoint:    fmt.Sprintf("https://secretsmanager.%s.amazonaws.com/", cfg.Region),
+		region:      cfg.Region,
+		credentials: cfg.Credentials,
+		signer:      v4.NewSigner(),
+		doer:        httpcli.ExternalDoer,
+	}, nil
+}
+
+type awsGetSecretValueResponse struct {
+	SecretString string `json:"SecretString"`
+	SecretBinary string `json:"SecretBinary"`
+}
+
+func (p *awsProvider) Resolve(ctx context.Context, ref Reference) (*Secret, error) {
+	payload, err := json.Marshal(map[string]string{"SecretId": ref.Path})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	credentials, err := p.credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieving AWS credentials")
+	}
+	hash := sha256.Sum256(payload)
+	if err := p.signer.SignHTTP(ctx, credentials, req, hex.EncodeToString(hash[:]), "secretsmanager", p.region, time.Now()); err != nil {
+		return nil, errors.Wrap(err, "signing AWS request")
+	}
+
+	resp, err := p.doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		content, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, errors.Newf("AWS secret %q: unexpected status code %d: %s", ref.Path, resp.StatusCode, strings.TrimSpace(string(content)))
+	}
+
+	var result awsGetSecretValueResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	value := result.SecretString
+	if value == "" && result.SecretBinary != "" {
+		b, err := base64.StdEncoding.DecodeString(result.SecretBinary)
+		if err != nil {
+			return nil, err
+		}
+		value = string(b)
+	}
+
+	value, err = selectJSONField(value, ref.Field)
+	if err != nil {
+		return nil, errors.Wrapf(err, "AWS secret %q", ref.Path)
+	}
+	return &Secret{Value: value}, nil
+}
+
+func (p *awsProvider) Revoke(context.Context, string) error {
+	return nil
+}
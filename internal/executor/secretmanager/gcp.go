@@ -0,0 +1,49 @@
+package secretmanager
+
+import (
+	"context"
+
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/api/option"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// gcpProvider reads secret versions from Google Cloud Secret Manager. Secret
+// versions are not leased, so there is nothing to revoke.
+type gcpProvider struct {
+	credentialsFile string
+}
+
+func newGCPProvider(c *schema.GCPSecretManager) *gcpProvider {
+	return &gcpProvider{credentialsFile: c.CredentialsFile}
+}
+
+func (p *gcpProvider) Resolve(ctx context.Context, ref Reference) (*Secret, error) {
+	var opts []option.ClientOption
+	if p.credentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(p.credentialsFile))
+	}
+	client, err := secretmanager.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating GCP Secret Manager client")
+	}
+	defer client.Close()
+
+	resp, err := client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{Name: ref.Path})
+	if err != nil {
+		return nil, errors.Wrapf(err, "GCP secret %q", ref.Path)
+	}
+
+	value, err := selectJSONField(string(resp.GetPayload().GetData()), ref.Field)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GCP secret %q", ref.Path)
+	}
+	return &Secret{Value: value}, nil
+}
+
+func (p *gcpProvider) Revoke(context.Context, string) error {
+	return nil
+}
@@ -0,0 +1,96 @@
+package secretmanager
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Secret is a secret value resolved from a secret manager.
+type Secret struct {
+	Value string
+	// LeaseID identifies the lease of the value in the secret manager. It is
+	// empty if the value is not leased and does not need to be revoked.
+	LeaseID string
+	// ExpiresAt is when the lease expires in the secret manager, if known.
+	ExpiresAt *time.Time
+}
+
+// Provider resolves secrets from a secret manager.
+type Provider interface {
+	// Resolve returns the value of the referenced secret.
+	Resolve(ctx context.Context, ref Reference) (*Secret, error)
+
+	// Revoke revokes the lease with the given ID, so that the value resolved with
+	// it can no longer be used.
+	Revoke(ctx context.Context, leaseID string) error
+}
+
+// NewProvider returns the provider of the given name configured in the site
+// configuration.
+func NewProvider(ctx context.Context, name string, config *schema.ExecutorsSecretManagers) (Provider, error) {
+	if config == nil {
+		config = &schema.ExecutorsSecretManagers{}
+	}
+
+	switch name {
+	case ProviderVault:
+		if config.Vault == nil {
+			return nil, errors.New("executors.secretManagers.vault is not configured")
+		}
+		return newVaultProvider(config.Vault), nil
+	case ProviderAWS:
+		if config.AwsSecretsManager == nil {
+			return nil, errors.New("executors.secretManagers.awsSecretsManager is not configured")
+		}
+		return newAWSProvider(ctx, config.AwsSecretsManager)
+	case ProviderGCP:
+		if config.GcpSecretManager == nil {
+			return nil, errors.New("executors.secretManagers.gcpSecretManager is not configured")
+		}
+		return newGCPProvider(config.GcpSecretManager), nil
+	default:
+		return nil, errors.Newf("unknown secret manager %q", name)
+	}
+}
+
+// selectField returns the value of the field of a secret with multiple fields.
+func selectField(fields map[string]any, field string) (string, error) {
+	if field == "" {
+		if len(fields) != 1 {
+			return "", errors.New("the secret has multiple fields, select one with #<field>")
+		}
+		for f := range fields {
+			field = f
+		}
+	}
+
+	v, ok := fields[field]
+	if !ok {
+		return "", errors.Newf("the secret has no field %q", field)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// selectJSONField returns the field of a secret whose value is a JSON object, or
+// the whole value if field is empty.
+func selectJSONField(value, field string) (string, error) {
+	if field == "" {
+		return value, nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", errors.Wrap(err, "the secret is not a JSON object")
+	}
+	return selectField(fields, field)
+}
@@ -0,0 +1,56 @@
+// Package secretmanager resolves executor secrets whose value references a secret
+// in an external secret manager, such as HashiCorp Vault, AWS Secrets Manager or
+// Google Cloud Secret Manager, when a job is dequeued. Leases of resolved secrets
+// are recorded and revoked once the job completed.
+package secretmanager
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	ProviderVault = "vault"
+	ProviderAWS   = "awssm"
+	ProviderGCP   = "gcpsm"
+)
+
+// Reference identifies a secret in an external secret manager.
+type Reference struct {
+	// Provider is the scheme of the reference, one of ProviderVault, ProviderAWS
+	// and ProviderGCP.
+	Provider string
+	// Path identifies the secret in the secret manager: the API path for Vault,
+	// the secret ID for AWS and the secret version resource name for GCP.
+	Path string
+	// Field selects a single field of a secret with multiple fields. It is empty
+	// to use the whole secret.
+	Field string
+}
+
+// ParseReference parses the value of an executor secret of the form
+// <provider>://<path>#<field>. It returns false if the value does not reference
+// a secret in a secret manager, in which case the value is used as is.
+func ParseReference(value string) (*Reference, bool, error) {
+	scheme, rest, ok := strings.Cut(value, "://")
+	if !ok {
+		return nil, false, nil
+	}
+	switch scheme {
+	case ProviderVault, ProviderAWS, ProviderGCP:
+	default:
+		return nil, false, nil
+	}
+
+	path, field, _ := strings.Cut(rest, "#")
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil, true, errors.Newf("invalid %s secret reference: missing path", scheme)
+	}
+	if scheme == ProviderGCP && !strings.Contains(path, "/versions/") {
+		path += "/versions/latest"
+	}
+
+	return &Reference{Provider: scheme, Path: path, Field: field}, true, nil
+}
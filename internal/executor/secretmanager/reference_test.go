@@ -0,0 +1,74 @@
+package secretmanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    *Reference
+		wantRef bool
+		wantErr bool
+	}{
+		{value: "plain-secret"},
+		{value: "https://example.com/token"},
+		{
+			value:   "vault://database/creds/readonly#password",
+			want:    &Reference{Provider: ProviderVault, Path: "database/creds/readonly", Field: "password"},
+			wantRef: true,
+		},
+		{
+			value:   "awssm://prod/npm-token",
+			want:    &Reference{Provider: ProviderAWS, Path: "prod/npm-token"},
+			wantRef: true,
+		},
+		{
+			value:   "gcpsm://projects/p/secrets/npm-token",
+			want:    &Reference{Provider: ProviderGCP, Path: "projects/p/secrets/npm-token/versions/latest"},
+			wantRef: true,
+		},
+		{
+			value:   "gcpsm://projects/p/secrets/npm-token/versions/3#token",
+			want:    &Reference{Provider: ProviderGCP, Path: "projects/p/secrets/npm-token/versions/3", Field: "token"},
+			wantRef: true,
+		},
+		{value: "vault://#password", wantRef: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			ref, ok, err := ParseReference(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantRef, ok)
+			assert.Equal(t, tt.want, ref)
+		})
+	}
+}
+
+func TestSelectJSONField(t *testing.T) {
+	value, err := selectJSONField(`{"username":"admin","password":"hunter2","port":5432}`, "password")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", value)
+
+	value, err = selectJSONField(`{"port":5432}`, "port")
+	require.NoError(t, err)
+	assert.Equal(t, "5432", value)
+
+	value, err = selectJSONField("not json", "")
+	require.NoError(t, err)
+	assert.Equal(t, "not json", value)
+
+	_, err = selectJSONField("not json", "password")
+	require.Error(t, err)
+
+	_, err = selectJSONField(`{"username":"admin"}`, "password")
+	require.Error(t, err)
+}
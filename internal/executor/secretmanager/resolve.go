@@ -0,0 +1,75 @@
+package secretmanager
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Resolve returns the value of an executor secret for the job. Values that
+// reference a secret in an external secret manager are resolved with the secret
+// manager configured in the site configuration, and the lease of the resolved
+// value is recorded so that it can be revoked once the job completed. Other
+// values are returned as is.
+func Resolve(ctx context.Context, db database.DB, queue string, jobID int, value string) (string, error) {
+	ref, ok, err := ParseReference(value)
+	if err != nil || !ok {
+		return value, err
+	}
+
+	provider, err := NewProvider(ctx, ref.Provider, conf.Get().ExecutorsSecretManagers)
+	if err != nil {
+		return "", err
+	}
+	secret, err := provider.Resolve(ctx, *ref)
+	if err != nil {
+		return "", err
+	}
+
+	if secret.LeaseID != "" {
+		if err := NewStore(db).Create(ctx, queue, jobID, ref.Provider, secret.LeaseID, secret.ExpiresAt); err != nil {
+			// Without a record the lease would never be revoked, so revoke it right away.
+			return "", errors.Append(errors.Wrap(err, "recording secret lease"), provider.Revoke(ctx, secret.LeaseID))
+		}
+	}
+	return secret.Value, nil
+}
+
+// RevokeJob revokes the leases of the secrets resolved for the job.
+func RevokeJob(ctx context.Context, db database.DB, queue string, jobID int) error {
+	store := NewStore(db)
+	leases, err := store.ListByJob(ctx, queue, jobID)
+	if err != nil {
+		return err
+	}
+	return revoke(ctx, store, leases)
+}
+
+func revoke(ctx context.Context, store Store, leases []*Lease) error {
+	providers := map[string]Provider{}
+
+	var errs error
+	for _, lease := range leases {
+		provider, ok := providers[lease.Provider]
+		if !ok {
+			p, err := NewProvider(ctx, lease.Provider, conf.Get().ExecutorsSecretManagers)
+			if err != nil {
+				errs = errors.Append(errs, errors.Wrapf(err, "revoking secret lease %d", lease.ID))
+				continue
+			}
+			provider = p
+			providers[lease.Provider] = p
+		}
+
+		if err := provider.Revoke(ctx, lease.LeaseID); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "revoking secret lease %d", lease.ID))
+			continue
+		}
+		if err := store.Delete(ctx, lease.ID); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "deleting secret lease %d", lease.ID))
+		}
+	}
+	return errs
+}
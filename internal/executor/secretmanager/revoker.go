@@ -0,0 +1,33 @@
+package secretmanager
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// revokeBatchSize is the maximum number of leases revoked per revoker run.
+const revokeBatchSize = 500
+
+// NewRevoker returns a background routine that revokes the leases of jobs that
+// are no longer queued or processing. Leases are revoked when their job is
+// marked as completed, errored or failed; the revoker catches the leases of
+// jobs that were reset or deleted, and retries failed revocations.
+func NewRevoker(ctx context.Context, db database.DB, interval time.Duration) goroutine.BackgroundRoutine {
+	store := NewStore(db)
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		goroutine.HandlerFunc(func(ctx context.Context) error {
+			leases, err := store.ListRevocable(ctx, time.Now(), revokeBatchSize)
+			if err != nil {
+				return err
+			}
+			return revoke(ctx, store, leases)
+		}),
+		goroutine.WithName("executors.secret-lease-revoker"),
+		goroutine.WithDescription("revokes the leases of secrets resolved for executor jobs that finished"),
+		goroutine.WithInterval(interval),
+	)
+}
@@ -0,0 +1,133 @@
+package secretmanager
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// Lease is the lease of a secret resolved for an executor job.
+type Lease struct {
+	ID int
+	// Queue and JobID identify the job the secret was resolved for.
+	Queue    string
+	JobID    int
+	Provider string
+	// LeaseID is the ID of the lease in the secret manager.
+	LeaseID   string
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+}
+
+// Store records the leases of secrets resolved for executor jobs until they are
+// revoked.
+type Store interface {
+	// Create records the lease of a secret resolved for the job.
+	Create(ctx context.Context, queue string, jobID int, provider, leaseID string, expiresAt *time.Time) error
+
+	// ListByJob returns the leases of the job.
+	ListByJob(ctx context.Context, queue string, jobID int) ([]*Lease, error)
+
+	// ListRevocable returns at most limit leases of jobs that are no longer
+	// queued or processing, or that expired before now.
+	ListRevocable(ctx context.Context, now time.Time, limit int) ([]*Lease, error)
+
+	// Delete deletes the lease with the given ID once it has been revoked.
+	Delete(ctx context.Context, id int) error
+}
+
+type store struct {
+	*basestore.Store
+}
+
+// NewStore returns a Store backed by the given database.
+func NewStore(db database.DB) Store {
+	return &store{Store: basestore.NewWithHandle(db.Handle())}
+}
+
+const createFmtstr = `
+INSERT INTO executor_secret_leases (queue, job_id, provider, lease_id, expires_at)
+VALUES (%s, %s, %s, %s, %s)
+`
+
+func (s *store) Create(ctx context.Context, queue string, jobID int, provider, leaseID string, expiresAt *time.Time) error {
+	return s.Exec(ctx, sqlf.Sprintf(createFmtstr, queue, jobID, provider, leaseID, dbutil.NullTime{Time: expiresAt}))
+}
+
+const listFmtstr = `
+SELECT %s
+FROM executor_secret_leases l
+WHERE %s
+ORDER BY l.id
+%s
+`
+
+func (s *store) ListByJob(ctx context.Context, queue string, jobID int) ([]*Lease, error) {
+	return s.list(ctx, sqlf.Sprintf("l.queue = %s AND l.job_id = %s", queue, jobID), sqlf.Sprintf(""))
+}
+
+// jobTables are the tables of the jobs of each queue. Leases of jobs of other
+// queues are only revoked once they expire.
+var jobTables = map[string]string{
+	"batches":   "batch_spec_workspace_execution_jobs",
+	"codeintel": "lsif_indexes",
+}
+
+const finishedJobFmtstr = `
+(l.queue = %s AND NOT EXISTS (
+	SELECT 1 FROM %s j
+	WHERE j.id = l.job_id AND j.state IN ('queued', 'processing')
+))
+`
+
+func (s *store) ListRevocable(ctx context.Context, now time.Time, limit int) ([]*Lease, error) {
+	conds := []*sqlf.Query{sqlf.Sprintf("l.expires_at < %s", now)}
+	for queue, table := range jobTables {
+		conds = append(conds, sqlf.Sprintf(finishedJobFmtstr, queue, sqlf.Sprintf(table)))
+	}
+	return s.list(ctx, sqlf.Join(conds, "OR"), sqlf.Sprintf("LIMIT %s", limit))
+}
+
+func (s *store) list(ctx context.Context, cond, limit *sqlf.Query) ([]*Lease, error) {
+	return basestore.NewSliceScanner(scanLease)(s.Query(ctx, sqlf.Sprintf(
+		listFmtstr,
+		sqlf.Join(leaseColumns, ","),
+		cond,
+		limit,
+	)))
+}
+
+func (s *store) Delete(ctx context.Context, id int) error {
+	return s.Exec(ctx, sqlf.Sprintf("DELETE FROM executor_secret_leases WHERE id = %s", id))
+}
+
+var leaseColumns = []*sqlf.Query{
+	sqlf.Sprintf("l.id"),
+	sqlf.Sprintf("l.queue"),
+	sqlf.Sprintf("l.job_id"),
+	sqlf.Sprintf("l.provider"),
+	sqlf.Sprintf("l.lease_id"),
+	sqlf.Sprintf("l.created_at"),
+	sqlf.Sprintf("l.expires_at"),
+}
+
+func scanLease(s dbutil.Scanner) (*Lease, error) {
+	var l Lease
+	if err := s.Scan(
+		&l.ID,
+		&l.Queue,
+		&l.JobID,
+		&l.Provider,
+		&l.LeaseID,
+		&l.CreatedAt,
+		&l.ExpiresAt,
+	); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
@@ -0,0 +1,108 @@
+package secretmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type vaultProvider struct {
+	address   string
+	token     string
+	namespace string
+	doer      httpcli.Doer
+}
+
+func newVaultProvider(config *schema.VaultSecretManager) *vaultProvider {
+	return &vaultProvider{
+		address:   strings.TrimSuffix(config.Address, "/"),
+		token:     config.Token,
+		namespace: config.Namespace,
+		doer:      httpcli.ExternalDoer,
+	}
+}
+
+type vaultSecretResponse struct {
+	LeaseID       string         `json:"lease_id"`
+	LeaseDuration int            `json:"lease_duration"`
+	Data          map[string]any `json:"data"`
+}
+
+// Resolve reads the secret at the referenced path. Secrets of KV version 2
+// engines nest their fields in data.data, all other engines return them in data.
+func (p *vaultProvider) Resolve(ctx context.Context, ref Reference) (*Secret, error) {
+	var resp vaultSecretResponse
+	if err := p.do(ctx, http.MethodGet, ref.Path, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	fields := resp.Data
+	if data, ok := fields["data"].(map[string]any); ok {
+		if _, ok := fields["metadata"]; ok {
+			fields = data
+		}
+	}
+	value, err := selectField(fields, ref.Field)
+	if err != nil {
+		return nil, errors.Wrapf(err, "vault secret %q", ref.Path)
+	}
+
+	secret := &Secret{Value: value, LeaseID: resp.LeaseID}
+	if resp.LeaseID != "" && resp.LeaseDuration > 0 {
+		expiresAt := time.Now().Add(time.Duration(resp.LeaseDuration) * time.Second)
+		secret.ExpiresAt = &expiresAt
+	}
+	return secret, nil
+}
+
+func (p *vaultProvider) Revoke(ctx context.Context, leaseID string) error {
+	return p.do(ctx, http.MethodPut, "sys/leases/revoke", map[string]string{"lease_id": leaseID}, nil)
+}
+
+func (p *vaultProvider) do(ctx context.Context, method, path string, payload, result any) error {
+	var body io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/v1/%s", p.address, path), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.doer.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Vault error responses never contain secret values.
+		content, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Newf("vault %s %s: unexpected status code %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(content)))
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
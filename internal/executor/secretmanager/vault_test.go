@@ -0,0 +1,74 @@
+package secretmanager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestVaultProvider(t *testing.T) {
+	var revoked []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" || r.Header.Get("X-Vault-Namespace") != "team" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1/database/creds/readonly":
+			_, _ = w.Write([]byte(`{"lease_id":"database/creds/readonly/abc","lease_duration":3600,"data":{"username":"v-token","password":"hunter2"}}`))
+		case "GET /v1/secret/data/npm":
+			_, _ = w.Write([]byte(`{"data":{"data":{"token":"npm-token"},"metadata":{"version":1}}}`))
+		case "PUT /v1/sys/leases/revoke":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			revoked = append(revoked, body["lease_id"])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	p := newVaultProvider(&schema.VaultSecretManager{Address: srv.URL + "/", Token: "root", Namespace: "team"})
+	p.doer = srv.Client()
+	ctx := context.Background()
+
+	t.Run("dynamic secret", func(t *testing.T) {
+		secret, err := p.Resolve(ctx, Reference{Provider: ProviderVault, Path: "database/creds/readonly", Field: "password"})
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", secret.Value)
+		assert.Equal(t, "database/creds/readonly/abc", secret.LeaseID)
+		require.NotNil(t, secret.ExpiresAt)
+	})
+
+	t.Run("kv v2 secret", func(t *testing.T) {
+		secret, err := p.Resolve(ctx, Reference{Provider: ProviderVault, Path: "secret/data/npm"})
+		require.NoError(t, err)
+		assert.Equal(t, "npm-token", secret.Value)
+		assert.Empty(t, secret.LeaseID)
+		assert.Nil(t, secret.ExpiresAt)
+	})
+
+	t.Run("ambiguous field", func(t *testing.T) {
+		_, err := p.Resolve(ctx, Reference{Provider: ProviderVault, Path: "database/creds/readonly"})
+		require.Error(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := p.Resolve(ctx, Reference{Provider: ProviderVault, Path: "secret/data/missing"})
+		require.Error(t, err)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		require.NoError(t, p.Revoke(ctx, "database/creds/readonly/abc"))
+		assert.Equal(t, []string{"database/creds/readonly/abc"}, revoked)
+	})
+}
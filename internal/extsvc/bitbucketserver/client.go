@@ -477,6 +477,9 @@ type UpdatePullRequestInput struct {
 	Description string     `json:"description"`
 	ToRef       Ref        `json:"toRef"`
 	Reviewers   []Reviewer `json:"reviewers"`
+	// Draft is only supported by Bitbucket Data Center 8.18 and later. When
+	// nil, the draft state of the pull request is left untouched.
+	Draft *bool `json:"draft,omitempty"`
 }
 
 func (c *Client) UpdatePullRequest(ctx context.Context, in *UpdatePullRequestInput) (*PullRequest, error) {
@@ -503,6 +506,8 @@ func (e ErrAlreadyExists) Error() string {
 }
 
 // CreatePullRequest creates the given PullRequest returning an error in case of failure.
+// The users in pr.Reviewers are requested as reviewers in addition to the
+// default reviewers of the target repository.
 func (c *Client) CreatePullRequest(ctx context.Context, pr *PullRequest) error {
 	for _, namedRef := range [...]struct {
 		name string
@@ -539,6 +544,7 @@ func (c *Client) CreatePullRequest(ctx context.Context, pr *PullRequest) error {
 		ToRef       Ref        `json:"toRef"`
 		Locked      bool       `json:"locked"`
 		Reviewers   []reviewer `json:"reviewers"`
+		Draft       bool       `json:"draft,omitempty"`
 	}
 
 	defaultReviewers, err := c.FetchDefaultReviewers(ctx, pr)
@@ -549,11 +555,24 @@ func (c *Client) CreatePullRequest(ctx context.Context, pr *PullRequest) error {
 		// return errors.Wrap(err, "fetching default reviewers")
 	}
 
-	reviewers := make([]reviewer, 0, len(defaultReviewers))
-	for _, r := range defaultReviewers {
+	names := make([]string, 0, len(pr.Reviewers)+len(defaultReviewers))
+	for _, r := range pr.Reviewers {
+		if r.User != nil {
+			names = append(names, r.User.Name)
+		}
+	}
+	names = append(names, defaultReviewers...)
+
+	seen := make(map[string]struct{}, len(names))
+	reviewers := make([]reviewer, 0, len(names))
+	for _, name := range names {
+		if _, ok := seen[name]; ok || name == "" {
+			continue
+		}
+		seen[name] = struct{}{}
 		reviewers = append(reviewers, reviewer{User: struct {
 			Name string `json:"name"`
-		}{Name: r}})
+		}{Name: name}})
 	}
 
 	// Bitbucket Server doesn't support GFM taskitems. But since we might add
@@ -571,6 +590,7 @@ func (c *Client) CreatePullRequest(ctx context.Context, pr *PullRequest) error {
 		ToRef:       pr.ToRef,
 		Locked:      false,
 		Reviewers:   reviewers,
+		Draft:       pr.Draft,
 	}
 
 	path := fmt.Sprintf(
@@ -1250,6 +1270,7 @@ type PullRequest struct {
 	FromRef      Ref               `json:"fromRef"`
 	ToRef        Ref               `json:"toRef"`
 	Locked       bool              `json:"locked"`
+	Draft        bool              `json:"draft,omitempty"`
 	Author       PullRequestAuthor `json:"author"`
 	Reviewers    []Reviewer        `json:"reviewers"`
 	Participants []Participant     `json:"participants"`
@@ -1602,7 +1623,28 @@ func (c *Client) CreatePullRequestComment(ctx context.Context, pr *PullRequest,
 	return err
 }
 
+// Merge strategies supported by Bitbucket Server. Which of them are enabled
+// can be configured per project and repository.
+const (
+	MergeStrategyNoFastForward       = "no-ff"
+	MergeStrategyFastForward         = "ff"
+	MergeStrategyFastForwardOnly     = "ff-only"
+	MergeStrategySquash              = "squash"
+	MergeStrategySquashFastForward   = "squash-ff-only"
+	MergeStrategyRebaseNoFastForward = "rebase-no-ff"
+	MergeStrategyRebaseFastForward   = "rebase-ff-only"
+)
+
+// MergePullRequest merges the pull request using the default merge strategy
+// of the repository.
 func (c *Client) MergePullRequest(ctx context.Context, pr *PullRequest) error {
+	return c.MergePullRequestWithStrategy(ctx, pr, "")
+}
+
+// MergePullRequestWithStrategy merges the pull request using the merge strategy
+// with the given ID. If strategyID is empty, the default merge strategy of the
+// repository is used.
+func (c *Client) MergePullRequestWithStrategy(ctx context.Context, pr *PullRequest, strategyID string) error {
 	if pr.ToRef.Repository.Slug == "" {
 		return errors.New("repository slug empty")
 	}
@@ -1620,7 +1662,12 @@ func (c *Client) MergePullRequest(ctx context.Context, pr *PullRequest) error {
 
 	qry := url.Values{"version": {strconv.Itoa(pr.Version)}}
 
-	_, err := c.send(ctx, "POST", path, qry, nil, pr)
+	var payload any
+	if strategyID != "" {
+		payload = map[string]string{"strategyId": strategyID}
+	}
+
+	_, err := c.send(ctx, "POST", path, qry, payload, pr)
 	if err != nil {
 		return err
 	}
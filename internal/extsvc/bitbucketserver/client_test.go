@@ -591,6 +591,51 @@ func TestClient_CreatePullRequest(t *testing.T) {
 	}
 }
 
+func TestClient_CreatePullRequest_Reviewers(t *testing.T) {
+	ctx := context.Background()
+
+	var body struct {
+		Reviewers []struct {
+			User struct {
+				Name string `json:"name"`
+			} `json:"user"`
+		} `json:"reviewers"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/default-reviewers/1.0/projects/SOUR/repos/vegeta/reviewers":
+			_, _ = w.Write([]byte(`[{"name":"bob"},{"name":"carol"}]`))
+		case "/rest/api/1.0/projects/SOUR/repos/vegeta/pull-requests":
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			_, _ = w.Write([]byte(`{"id":8,"version":0,"state":"OPEN"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("test", &schema.BitbucketServerConnection{Url: srv.URL}, nil)
+	require.NoError(t, err)
+
+	pr := &PullRequest{Title: "This is a test PR"}
+	pr.ToRef.Repository.Slug = "vegeta"
+	pr.ToRef.Repository.ID = 10070
+	pr.ToRef.Repository.Project.Key = "SOUR"
+	pr.ToRef.ID = "refs/heads/master"
+	pr.FromRef = pr.ToRef
+	pr.FromRef.ID = "refs/heads/test-pr"
+	pr.Reviewers = []Reviewer{{User: &User{Name: "alice"}}, {User: &User{Name: "bob"}}}
+
+	require.NoError(t, client.CreatePullRequest(ctx, pr))
+	assert.Equal(t, 8, pr.ID)
+
+	var names []string
+	for _, r := range body.Reviewers {
+		names = append(names, r.User.Name)
+	}
+	assert.Equal(t, []string{"alice", "bob", "carol"}, names)
+}
+
 func TestClient_FetchDefaultReviewers(t *testing.T) {
 	instanceURL := os.Getenv("BITBUCKET_SERVER_URL")
 	if instanceURL == "" {
@@ -1057,6 +1102,46 @@ func TestClient_MergePullRequest(t *testing.T) {
 	}
 }
 
+func TestClient_MergePullRequestWithStrategy(t *testing.T) {
+	ctx := context.Background()
+
+	var body map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/projects/SOUR/repos/vegeta/pull-requests/7/merge" || r.URL.Query().Get("version") != "3" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = nil
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"id":7,"version":4,"state":"MERGED"}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("test", &schema.BitbucketServerConnection{Url: srv.URL}, nil)
+	require.NoError(t, err)
+
+	newPR := func() *PullRequest {
+		pr := &PullRequest{ID: 7, Version: 3}
+		pr.ToRef.Repository.Slug = "vegeta"
+		pr.ToRef.Repository.Project.Key = "SOUR"
+		return pr
+	}
+
+	t.Run("default strategy", func(t *testing.T) {
+		pr := newPR()
+		require.NoError(t, client.MergePullRequest(ctx, pr))
+		assert.Nil(t, body)
+		assert.Equal(t, "MERGED", pr.State)
+	})
+
+	t.Run("squash", func(t *testing.T) {
+		pr := newPR()
+		require.NoError(t, client.MergePullRequestWithStrategy(ctx, pr, MergeStrategySquash))
+		assert.Equal(t, map[string]string{"strategyId": "squash"}, body)
+		assert.Equal(t, "MERGED", pr.State)
+	})
+}
+
 // NOTE: This test validates that correct repository IDs are returned from the
 // roaring bitmap permissions endpoint. Therefore, the expected results are
 // dependent on the user token supplied. The current golden files are generated
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -39,6 +40,38 @@ func (c *client) GetChange(ctx context.Context, changeID string) (*Change, error
 	return &change, nil
 }
 
+// ListChanges lists the changes matching the query of opts.
+func (c *client) ListChanges(ctx context.Context, opts ListChangesArgs) (changes []Change, nextPage bool, err error) {
+	if opts.Cursor == nil {
+		opts.Cursor = &Pagination{PerPage: 100, Page: 1}
+	}
+
+	query := make(url.Values)
+	query.Set("q", opts.Query)
+	query.Set("n", strconv.Itoa(opts.Cursor.PerPage))
+	if opts.Cursor.Skip > 0 {
+		query.Set("S", strconv.Itoa(opts.Cursor.Skip))
+	} else {
+		query.Set("S", strconv.Itoa((opts.Cursor.Page-1)*opts.Cursor.PerPage))
+	}
+	if opts.CurrentRevision {
+		query.Set("o", "CURRENT_REVISION")
+	}
+
+	reqURL := url.URL{Path: "a/changes/", RawQuery: query.Encode()}
+	req, err := http.NewRequest("GET", reqURL.String(), nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if _, err = c.do(ctx, req, &changes); err != nil {
+		return nil, false, err
+	}
+
+	// Gerrit flags the last change of the page if there are more changes.
+	return changes, len(changes) > 0 && changes[len(changes)-1].MoreChanges, nil
+}
+
 // AbandonChange abandons a Gerrit change.
 func (c *client) AbandonChange(ctx context.Context, changeID string) (*Change, error) {
 	pathStr, err := url.JoinPath("a/changes", url.PathEscape(changeID), "abandon")
@@ -243,6 +276,42 @@ func (c *client) GetChangeReviews(ctx context.Context, changeID string) (*[]Revi
 	return &reviewers, nil
 }
 
+// AddReviewer adds a reviewer to a Gerrit change. The reviewer can be a user,
+// identified by their username, email address or account ID, or a group.
+func (c *client) AddReviewer(ctx context.Context, changeID string, input AddReviewerPayload) error {
+	pathStr, err := url.JoinPath("a/changes", url.PathEscape(changeID), "reviewers")
+	if err != nil {
+		return err
+	}
+	reqURL := url.URL{Path: pathStr}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", reqURL.String(), bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var result AddReviewerResult
+	resp, err := c.do(ctx, req, &result)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	// Older Gerrit versions report reviewers that cannot be added with a
+	// successful status code.
+	if result.Error != "" {
+		return errors.New(result.Error)
+	}
+	return nil
+}
+
 // MoveChange moves a Gerrit change to a different destination branch.
 func (c *client) MoveChange(ctx context.Context, changeID string, input MoveChangePayload) (*Change, error) {
 
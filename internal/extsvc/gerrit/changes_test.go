@@ -2,6 +2,10 @@ package gerrit
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -178,3 +182,45 @@ func TestClient_SetCommitMessage(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestClient_AddReviewer(t *testing.T) {
+	ctx := context.Background()
+
+	var got AddReviewerPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/a/changes/I52bede3e6dd80b9048924d0416e5d1a7bf49cf5b/reviewers" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch got.Reviewer {
+		case "alice":
+			_, _ = w.Write([]byte(")]}'\n{\"input\":\"alice\",\"reviewers\":[{\"_account_id\":1000001}]}"))
+		case "unknown-old":
+			_, _ = w.Write([]byte(")]}'\n{\"input\":\"unknown-old\",\"error\":\"unknown-old does not identify a registered user or group\"}"))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(")]}'\n{\"input\":\"unknown\",\"error\":\"unknown does not identify a registered user or group\"}"))
+		}
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli, err := NewClient("urn", u, &AccountCredentials{Username: "admin", Password: "secret"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changeID := "I52bede3e6dd80b9048924d0416e5d1a7bf49cf5b"
+	assert.NoError(t, cli.AddReviewer(ctx, changeID, AddReviewerPayload{Reviewer: "alice"}))
+	assert.Equal(t, "alice", got.Reviewer)
+
+	assert.Error(t, cli.AddReviewer(ctx, changeID, AddReviewerPayload{Reviewer: "unknown"}))
+	assert.Error(t, cli.AddReviewer(ctx, changeID, AddReviewerPayload{Reviewer: "unknown-old"}))
+}
@@ -38,6 +38,7 @@ type Client interface {
 	GetAuthenticatedUserAccount(ctx context.Context) (*Account, error)
 	GetGroup(ctx context.Context, groupName string) (Group, error)
 	ListProjects(ctx context.Context, opts ListProjectsArgs) (projects ListProjectsResponse, nextPage bool, err error)
+	ListChanges(ctx context.Context, opts ListChangesArgs) (changes []Change, nextPage bool, err error)
 	GetChange(ctx context.Context, changeID string) (*Change, error)
 	AbandonChange(ctx context.Context, changeID string) (*Change, error)
 	DeleteChange(ctx context.Context, changeID string) error
@@ -45,6 +46,7 @@ type Client interface {
 	RestoreChange(ctx context.Context, changeID string) (*Change, error)
 	WriteReviewComment(ctx context.Context, changeID string, comment ChangeReviewComment) error
 	GetChangeReviews(ctx context.Context, changeID string) (*[]Reviewer, error)
+	AddReviewer(ctx context.Context, changeID string, input AddReviewerPayload) error
 	SetWIP(ctx context.Context, changeID string) error
 	SetReadyForReview(ctx context.Context, changeID string) error
 	MoveChange(ctx context.Context, changeID string, input MoveChangePayload) (*Change, error)
@@ -26,6 +26,15 @@ type ListProjectsArgs struct {
 // ListProjectsResponse defines a response struct returned from ListProjects method calls.
 type ListProjectsResponse map[string]*Project
 
+// ListChangesArgs defines options to be set on ListChanges method calls.
+type ListChangesArgs struct {
+	Cursor *Pagination
+	// Query is a Gerrit change search query, such as "status:open project:foo".
+	Query string
+	// If true, the current revision of each change is included.
+	CurrentRevision bool
+}
+
 type Change struct {
 	ID             string       `json:"id"`
 	Project        string       `json:"project"`
@@ -45,6 +54,28 @@ type Change struct {
 		Email    string `json:"email"`
 		Username string `json:"username"`
 	} `json:"owner"`
+	// CurrentRevision and Revisions are only set when requested.
+	CurrentRevision string              `json:"current_revision,omitempty"`
+	Revisions       map[string]Revision `json:"revisions,omitempty"`
+	// MoreChanges is set on the last change of a page of ListChanges results when
+	// there are more changes.
+	MoreChanges bool `json:"_more_changes,omitempty"`
+}
+
+// Revision is a patch set of a change.
+type Revision struct {
+	Number int `json:"_number"`
+	// Ref is the Git reference of the patch set, such as refs/changes/34/1234/2.
+	Ref string `json:"ref"`
+}
+
+// ChangeRef maps a change to the Git reference of its current patch set.
+type ChangeRef struct {
+	ChangeID string `json:"change_id"`
+	Number   int    `json:"number"`
+	// Revision is the commit ID of the current patch set.
+	Revision string `json:"revision"`
+	Ref      string `json:"ref"`
 }
 
 func (c *Change) UnmarshalJSON(data []byte) error {
@@ -149,6 +180,21 @@ type Project struct {
 	State       string            `json:"state"`
 	Branches    map[string]string `json:"branches"`
 	Labels      map[string]Label  `json:"labels"`
+
+	// OpenChanges are the open changes of the project, ordered by number. They
+	// are not returned by the Gerrit API, but set by Sourcegraph when syncing
+	// open changes is enabled.
+	OpenChanges []ChangeRef `json:"open_changes,omitempty"`
+}
+
+// ChangeRef returns the reference of the open change with the given Change-Id.
+func (p *Project) ChangeRef(changeID string) (ChangeRef, bool) {
+	for _, c := range p.OpenChanges {
+		if c.ChangeID == changeID {
+			return c, true
+		}
+	}
+	return ChangeRef{}, false
 }
 
 type Label struct {
@@ -156,6 +202,15 @@ type Label struct {
 	DefaultValue string            `json:"default_value"`
 }
 
+type AddReviewerPayload struct {
+	Reviewer string `json:"reviewer"`
+}
+
+type AddReviewerResult struct {
+	Input string `json:"input"`
+	Error string `json:"error,omitempty"`
+}
+
 type MoveChangePayload struct {
 	DestinationBranch string `json:"destination_branch"`
 }
@@ -832,6 +832,20 @@ func (c *V3Client) GetRef(ctx context.Context, owner, repo, ref string) (*restCo
 	return &commit, nil
 }
 
+// GetObjectVerification gets the signature verification status of a commit or annotated
+// tag, as determined by GitHub. The objectType must be either "commit" or "tag".
+//
+// API docs: https://docs.github.com/en/rest/git/commits#get-a-commit-object
+func (c *V3Client) GetObjectVerification(ctx context.Context, owner, repo, objectType, sha string) (*Verification, error) {
+	var object struct {
+		Verification Verification `json:"verification"`
+	}
+	if _, err := c.get(ctx, "repos/"+owner+"/"+repo+"/git/"+objectType+"s/"+sha, &object); err != nil {
+		return nil, err
+	}
+	return &object.Verification, nil
+}
+
 // CreateCommit creates a commit in the given repository based on a tree object.
 func (c *V3Client) CreateCommit(ctx context.Context, owner, repo, message, tree string, parents []string, author, committer *restAuthorCommiter) (*RestCommit, error) {
 	payload := struct {
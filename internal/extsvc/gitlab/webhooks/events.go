@@ -34,6 +34,51 @@ type PushEvent struct {
 	} `json:"repository"`
 }
 
+// MemberEvent represents a user being added to, removed from, or having their
+// access updated in a group or project. Unlike other events, member events have no
+// object_kind, and are identified by their event_name.
+// https://docs.gitlab.com/ee/user/project/integrations/webhook_events.html#group-member-events
+// https://docs.gitlab.com/ee/administration/system_hooks.html
+type MemberEvent struct {
+	EventName    string `json:"event_name"`
+	UserID       int32  `json:"user_id"`
+	UserUsername string `json:"user_username"`
+
+	// GroupID and GroupPath are set for group member events.
+	GroupID   int    `json:"group_id"`
+	GroupPath string `json:"group_path"`
+
+	// ProjectID and ProjectPathWithNamespace are set for project member events.
+	ProjectID                int    `json:"project_id"`
+	ProjectPathWithNamespace string `json:"project_path_with_namespace"`
+}
+
+// Member event names.
+const (
+	EventNameUserAddToGroup      = "user_add_to_group"
+	EventNameUserRemoveFromGroup = "user_remove_from_group"
+	EventNameUserUpdateForGroup  = "user_update_for_group"
+	EventNameUserAddToTeam       = "user_add_to_team"
+	EventNameUserRemoveFromTeam  = "user_remove_from_team"
+	EventNameUserUpdateForTeam   = "user_update_for_team"
+)
+
+// EventKind returns the kind of the event in the given JSON, which is its
+// object_kind, or its event_name for events without an object_kind.
+func EventKind(data []byte) (string, error) {
+	var event struct {
+		ObjectKind string `json:"object_kind"`
+		EventName  string `json:"event_name"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return "", errors.Wrap(err, "determining object kind")
+	}
+	if event.ObjectKind == "" {
+		return event.EventName, nil
+	}
+	return event.ObjectKind, nil
+}
+
 var ErrObjectKindUnknown = errors.New("unknown object kind")
 
 type downcaster interface {
@@ -41,7 +86,7 @@ type downcaster interface {
 }
 
 // UnmarshalEvent unmarshals the given JSON into an event type. Possible return
-// types are *MergeRequestEvent.
+// types are *MergeRequestEvent, *PipelineEvent, *PushEvent and *MemberEvent.
 //
 // Errors caused by a valid payload being of an unknown type may be
 // distinguished from other errors by checking for ErrObjectKindUnknown in the
@@ -54,25 +99,27 @@ func UnmarshalEvent(data []byte) (any, error) {
 	// Since we only care about the object_kind field, we'll start by
 	// unmarshalling into a minimal type that only has that field. We use
 	// object_kind instead of event_type because not all GitLab webhook types
-	// include event_type, whereas object_kind is generally reliable.
-	var event struct {
-		ObjectKind string `json:"object_kind"`
-	}
-	if err := json.Unmarshal(data, &event); err != nil {
-		return nil, errors.Wrap(err, "determining object kind")
+	// include event_type, whereas object_kind is generally reliable, except for
+	// member events which only have an event_name.
+	kind, err := EventKind(data)
+	if err != nil {
+		return nil, err
 	}
 
 	// Now we can set up the typed event that we'll unmarshal into.
 	var typedEvent any
-	switch event.ObjectKind {
+	switch kind {
 	case "merge_request":
 		typedEvent = &mergeRequestEvent{}
 	case "pipeline":
 		typedEvent = &PipelineEvent{}
 	case "push":
 		typedEvent = &PushEvent{}
+	case EventNameUserAddToGroup, EventNameUserRemoveFromGroup, EventNameUserUpdateForGroup,
+		EventNameUserAddToTeam, EventNameUserRemoveFromTeam, EventNameUserUpdateForTeam:
+		typedEvent = &MemberEvent{}
 	default:
-		return nil, errors.Wrapf(ErrObjectKindUnknown, "kind: %s", event.ObjectKind)
+		return nil, errors.Wrapf(ErrObjectKindUnknown, "kind: %s", kind)
 	}
 
 	// Let's perform the real unmarshal.
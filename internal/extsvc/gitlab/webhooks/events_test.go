@@ -109,4 +109,47 @@ func TestUnmarshalEvent(t *testing.T) {
 			t.Errorf("unexpected IID: have %d; want %d", pe.Pipeline.ID, want)
 		}
 	})
+
+	t.Run("valid member event", func(t *testing.T) {
+		event, err := UnmarshalEvent([]byte(`
+			{
+				"event_name": "user_add_to_team",
+				"access_level": "Developer",
+				"project_id": 74,
+				"project_path_with_namespace": "jsmith/storecloud",
+				"user_username": "johnsmith",
+				"user_id": 41
+			}
+		`))
+		if event == nil {
+			t.Fatal("unexpected nil event")
+		}
+		if err != nil {
+			t.Errorf("unexpected error: %+v", err)
+		}
+
+		me := event.(*MemberEvent)
+		if want := int32(41); me.UserID != want {
+			t.Errorf("unexpected user ID: have %d; want %d", me.UserID, want)
+		}
+		if want := 74; me.ProjectID != want {
+			t.Errorf("unexpected project ID: have %d; want %d", me.ProjectID, want)
+		}
+	})
+}
+
+func TestEventKind(t *testing.T) {
+	for payload, want := range map[string]string{
+		`{"object_kind":"push","event_name":"push"}`: "push",
+		`{"event_name":"user_remove_from_group"}`:    "user_remove_from_group",
+		`{}`: "",
+	} {
+		have, err := EventKind([]byte(payload))
+		if err != nil {
+			t.Errorf("unexpected error for %s: %+v", payload, err)
+		}
+		if have != want {
+			t.Errorf("unexpected kind for %s: have %q; want %q", payload, have, want)
+		}
+	}
 }
@@ -57,7 +57,8 @@ func getFlagCacheKey(name string) string {
 	return "ff_" + name
 }
 
-// Clears stored evaluated feature flags from Redis
+// Clears stored evaluated feature flags and evaluation events from Redis
 func ClearEvaluatedFlagFromCache(flagName string) {
 	_ = evalStore.Del(getFlagCacheKey(flagName))
+	_ = evalStore.Del(getEvaluationEventsKey(flagName))
 }
@@ -0,0 +1,75 @@
+package featureflag
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// EvaluationEvent records that a feature flag was evaluated for a user, and thus that
+// the user was exposed to the flag. Evaluation events are used to analyze the
+// exposure of percentage rollouts.
+type EvaluationEvent struct {
+	FlagName     string    `json:"flagName"`
+	Value        bool      `json:"value"`
+	UserID       int32     `json:"userID,omitempty"`
+	AnonymousUID string    `json:"anonymousUID,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// maxEvaluationEvents is the number of most recent evaluation events kept per flag.
+const maxEvaluationEvents = 1000
+
+func getEvaluationEventsKey(flagName string) string {
+	return "ff_evaluations_" + flagName
+}
+
+// recordEvaluationEvent appends an evaluation event to the stream of the flag. Failures
+// are ignored, as they must not affect the evaluation of the flag.
+func recordEvaluationEvent(a *actor.Actor, flagName string, value bool) {
+	if a == nil || (!a.IsAuthenticated() && a.AnonymousUID == "") {
+		return
+	}
+
+	event, err := json.Marshal(EvaluationEvent{
+		FlagName:     flagName,
+		Value:        value,
+		UserID:       a.UID,
+		AnonymousUID: a.AnonymousUID,
+		Timestamp:    time.Now(),
+	})
+	if err != nil {
+		return
+	}
+
+	key := getEvaluationEventsKey(flagName)
+	if err := evalStore.LPush(key, event); err != nil {
+		return
+	}
+	_ = evalStore.LTrim(key, 0, maxEvaluationEvents-1)
+}
+
+// GetEvaluationEvents returns the most recent evaluation events of the given flag,
+// newest first.
+func GetEvaluationEvents(flagName string, limit int) ([]EvaluationEvent, error) {
+	if limit <= 0 || limit > maxEvaluationEvents {
+		limit = maxEvaluationEvents
+	}
+
+	raw, err := evalStore.LRange(getEvaluationEventsKey(flagName), 0, limit-1).ByteSlices()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading evaluation events")
+	}
+
+	events := make([]EvaluationEvent, 0, len(raw))
+	for _, r := range raw {
+		var event EvaluationEvent
+		if err := json.Unmarshal(r, &event); err != nil {
+			return nil, errors.Wrap(err, "deserializing evaluation event")
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -3,6 +3,7 @@ package featureflag
 import (
 	"encoding/binary"
 	"hash/fnv"
+	"strings"
 	"time"
 )
 
@@ -14,18 +15,56 @@ type FeatureFlag struct {
 	Bool    *FeatureFlagBool
 	Rollout *FeatureFlagRollout
 
+	// TargetingRules set the value of the flag for the members of an organization or
+	// the holders of a role, ahead of Bool or Rollout. The first matching rule wins.
+	TargetingRules []TargetingRule
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
 }
 
+// Subject is who a feature flag is evaluated for: a user, along with the
+// organizations they are a member of and the roles they hold.
+type Subject struct {
+	UserID int32
+	OrgIDs []int32
+	Roles  []string
+}
+
 // EvaluateForUser evaluates the feature flag for a userID.
 func (f *FeatureFlag) EvaluateForUser(userID int32) bool {
+	return f.EvaluateForSubject(Subject{UserID: userID})
+}
+
+// EvaluateForSubject evaluates the feature flag for a user, their organizations and
+// their roles: the first matching targeting rule wins, and otherwise the value or the
+// rollout of the flag applies.
+//
+// Rollouts are sticky: a user or an organization is always in the same bucket for a
+// given flag, so increasing the rollout only adds users to the ones who already had
+// the flag enabled.
+func (f *FeatureFlag) EvaluateForSubject(s Subject) bool {
+	for _, rule := range f.TargetingRules {
+		if rule.Matches(s) {
+			return rule.Value
+		}
+	}
+
 	switch {
 	case f.Bool != nil:
 		return f.Bool.Value
 	case f.Rollout != nil:
-		return hashUserAndFlag(userID, f.Name)%10000 < uint32(f.Rollout.Rollout)
+		if f.Rollout.BucketBy == RolloutBucketByOrg {
+			// A user is in the rollout if any of their organizations is.
+			for _, orgID := range s.OrgIDs {
+				if hashOrgAndFlag(orgID, f.Name)%10000 < uint32(f.Rollout.Rollout) {
+					return true
+				}
+			}
+			return false
+		}
+		return hashUserAndFlag(s.UserID, f.Name)%10000 < uint32(f.Rollout.Rollout)
 	}
 	panic("one of Bool or Rollout must be set")
 }
@@ -37,12 +76,27 @@ func hashUserAndFlag(userID int32, flagName string) uint32 {
 	return h.Sum32()
 }
 
+func hashOrgAndFlag(orgID int32, flagName string) uint32 {
+	h := fnv.New32()
+	// Prefix the hash so that the organization and the user with the same ID are not
+	// always in the same bucket.
+	h.Write([]byte("org:"))
+	binary.Write(h, binary.LittleEndian, orgID)
+	h.Write([]byte(flagName))
+	return h.Sum32()
+}
+
 // EvaluateForAnonymousUser evaluates the feature flag for an anonymous user ID.
+// Anonymous users are not members of any organization, so they are never in a
+// rollout bucketed by organization.
 func (f *FeatureFlag) EvaluateForAnonymousUser(anonymousUID string) bool {
 	switch {
 	case f.Bool != nil:
 		return f.Bool.Value
 	case f.Rollout != nil:
+		if f.Rollout.BucketBy == RolloutBucketByOrg {
+			return false
+		}
 		return hashAnonymousUserAndFlag(anonymousUID, f.Name)%10000 < uint32(f.Rollout.Rollout)
 	}
 	panic("one of Bool or Rollout must be set")
@@ -76,6 +130,46 @@ type FeatureFlagRollout struct {
 	// users for which this feature flag will evaluate to 'true' in increments
 	// of 0.01%
 	Rollout int32
+	// BucketBy is whether users are in the rollout by their own ID, or by the IDs
+	// of their organizations. Defaults to RolloutBucketByUser.
+	BucketBy RolloutBucketBy
+}
+
+type RolloutBucketBy string
+
+const (
+	// RolloutBucketByUser puts each user in the rollout on their own.
+	RolloutBucketByUser RolloutBucketBy = "user"
+	// RolloutBucketByOrg puts all the members of an organization in the rollout
+	// together, so that they see the same features.
+	RolloutBucketByOrg RolloutBucketBy = "org"
+)
+
+// TargetingRule sets the value of a feature flag for the members of an organization,
+// or for the holders of a role. Exactly one of OrgID or Role is set.
+type TargetingRule struct {
+	OrgID *int32 `json:"orgID,omitempty"`
+	Role  string `json:"role,omitempty"`
+	Value bool   `json:"value"`
+}
+
+// Matches returns true if the rule applies to the given subject.
+func (r TargetingRule) Matches(s Subject) bool {
+	switch {
+	case r.OrgID != nil:
+		for _, orgID := range s.OrgIDs {
+			if orgID == *r.OrgID {
+				return true
+			}
+		}
+	case r.Role != "":
+		for _, role := range s.Roles {
+			if strings.EqualFold(role, r.Role) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 type Override struct {
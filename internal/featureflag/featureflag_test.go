@@ -0,0 +1,70 @@
+package featureflag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateForSubject(t *testing.T) {
+	orgID := int32(7)
+
+	t.Run("targeting rules win over the value", func(t *testing.T) {
+		flag := &FeatureFlag{
+			Name: "f",
+			Bool: &FeatureFlagBool{Value: false},
+			TargetingRules: []TargetingRule{
+				{Role: "ADMIN", Value: false},
+				{OrgID: &orgID, Value: true},
+				{Role: "beta-testers", Value: true},
+			},
+		}
+
+		require.False(t, flag.EvaluateForSubject(Subject{UserID: 1}))
+		require.True(t, flag.EvaluateForSubject(Subject{UserID: 1, OrgIDs: []int32{3, orgID}}))
+		require.True(t, flag.EvaluateForSubject(Subject{UserID: 1, Roles: []string{"BETA-TESTERS"}}))
+		// The first matching rule wins.
+		require.False(t, flag.EvaluateForSubject(Subject{UserID: 1, OrgIDs: []int32{orgID}, Roles: []string{"admin"}}))
+	})
+
+	t.Run("user rollouts are sticky", func(t *testing.T) {
+		flag := &FeatureFlag{Name: "f", Rollout: &FeatureFlagRollout{Rollout: 2500}}
+
+		enabled := map[int32]bool{}
+		for userID := int32(1); userID <= 1000; userID++ {
+			enabled[userID] = flag.EvaluateForSubject(Subject{UserID: userID})
+		}
+
+		// Increasing the rollout only adds users.
+		flag.Rollout.Rollout = 5000
+		for userID, wasEnabled := range enabled {
+			if wasEnabled {
+				require.True(t, flag.EvaluateForSubject(Subject{UserID: userID}))
+			}
+		}
+	})
+
+	t.Run("org rollouts put the members of an org together", func(t *testing.T) {
+		flag := &FeatureFlag{Name: "f", Rollout: &FeatureFlagRollout{Rollout: 5000, BucketBy: RolloutBucketByOrg}}
+
+		var inOrg, outOrg int32
+		for orgID := int32(1); inOrg == 0 || outOrg == 0; orgID++ {
+			if flag.EvaluateForSubject(Subject{OrgIDs: []int32{orgID}}) {
+				inOrg = orgID
+			} else {
+				outOrg = orgID
+			}
+		}
+
+		for userID := int32(1); userID <= 100; userID++ {
+			require.True(t, flag.EvaluateForSubject(Subject{UserID: userID, OrgIDs: []int32{inOrg}}))
+			require.False(t, flag.EvaluateForSubject(Subject{UserID: userID, OrgIDs: []int32{outOrg}}))
+			// Users are in the rollout if any of their organizations is.
+			require.True(t, flag.EvaluateForSubject(Subject{UserID: userID, OrgIDs: []int32{outOrg, inOrg}}))
+			// Users without organizations are never in the rollout.
+			require.False(t, flag.EvaluateForSubject(Subject{UserID: userID}))
+		}
+
+		require.False(t, flag.EvaluateForAnonymousUser("anonymous"))
+	})
+}
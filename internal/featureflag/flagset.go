@@ -45,6 +45,7 @@ func (f *FlagSet) GetBool(flag string) (bool, bool) {
 	v, ok := f.flags[flag]
 	if ok {
 		setEvaluatedFlagToCache(f.actor, flag, v)
+		recordEvaluationEvent(f.actor, flag, v)
 	}
 	return v, ok
 }
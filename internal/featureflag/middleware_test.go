@@ -96,10 +96,30 @@ func TestContextFlags_GetBool(t *testing.T) {
 		mockrequire.CalledN(t, mockStore.GetUserFlagsFunc, 4)
 	})
 
+	t.Run("Records evaluation events", func(t *testing.T) {
+		events, err := GetEvaluationEvents("user1", 0)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		for _, event := range events {
+			require.Equal(t, "user1", event.FlagName)
+			require.True(t, event.Value)
+			require.Equal(t, int32(1), event.UserID)
+		}
+
+		events, err = GetEvaluationEvents("user2", 1)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, int32(2), events[0].UserID)
+	})
+
 	t.Run("Clears Redis", func(t *testing.T) {
 		require.Equal(t, EvaluatedFlagSet{"user1": true}, GetEvaluatedFlagSet(ctx))
 		ClearEvaluatedFlagFromCache("user1")
 		require.Equal(t, EvaluatedFlagSet{}, GetEvaluatedFlagSet(ctx))
+
+		events, err := GetEvaluationEvents("user1", 0)
+		require.NoError(t, err)
+		require.Empty(t, events)
 	})
 }
 
@@ -168,6 +188,7 @@ func TestContextFlags_GetBoolOr(t *testing.T) {
 
 func setupRedisTest(t *testing.T) {
 	cache := map[string][]byte{}
+	lists := map[string][][]byte{}
 
 	mockConn := redigomock.NewConn()
 
@@ -184,8 +205,35 @@ func setupRedisTest(t *testing.T) {
 
 	mockConn.GenericCommand("DEL").Handle(func(args []interface{}) (interface{}, error) {
 		delete(cache, args[0].(string))
+		delete(lists, args[0].(string))
 		return nil, nil
 	})
 
+	mockConn.GenericCommand("LPUSH").Handle(func(args []interface{}) (interface{}, error) {
+		key := args[0].(string)
+		lists[key] = append([][]byte{args[1].([]byte)}, lists[key]...)
+		return int64(len(lists[key])), nil
+	})
+
+	mockConn.GenericCommand("LTRIM").Handle(func(args []interface{}) (interface{}, error) {
+		key := args[0].(string)
+		if stop := args[2].(int); stop+1 < len(lists[key]) {
+			lists[key] = lists[key][:stop+1]
+		}
+		return "OK", nil
+	})
+
+	mockConn.GenericCommand("LRANGE").Handle(func(args []interface{}) (interface{}, error) {
+		key := args[0].(string)
+		var res []interface{}
+		for i, v := range lists[key] {
+			if i > args[2].(int) {
+				break
+			}
+			res = append(res, v)
+		}
+		return res, nil
+	})
+
 	evalStore = redispool.RedisKeyValue(&redis.Pool{Dial: func() (redis.Conn, error) { return mockConn, nil }, MaxIdle: 10})
 }
@@ -258,6 +258,17 @@ type Client interface {
 	// GetObject fetches git object data in the supplied repo
 	GetObject(ctx context.Context, repo api.RepoName, objectName string) (*gitdomain.GitObject, error)
 
+	// ObjectSignature returns the signature of the commit or annotated tag with the
+	// given OID, along with the data it signs. It returns nil if the object is not
+	// signed.
+	ObjectSignature(ctx context.Context, checker authz.SubRepoPermissionChecker, repo api.RepoName, oid string) (*gitdomain.SignedObject, error)
+
+	// LFSContent returns the content of the file stored in Git LFS with the given
+	// OID and size, fetched from the LFS server of the repository's code host. It
+	// fails if fetching LFS content is disabled in the site configuration or the
+	// content is larger than configured. The caller must close the returned reader.
+	LFSContent(ctx context.Context, repo api.RepoName, oid string, size int64) (io.ReadCloser, error)
+
 	// HasCommitAfter indicates the staleness of a repository. It returns a boolean indicating if a repository
 	// contains a commit past a specified date.
 	HasCommitAfter(ctx context.Context, checker authz.SubRepoPermissionChecker, repo api.RepoName, date string, revspec string) (bool, error)
@@ -268,6 +279,11 @@ type Client interface {
 	// ListRefs returns a list of all refs in the repository.
 	ListRefs(ctx context.Context, repo api.RepoName) ([]gitdomain.Ref, error)
 
+	// ListRefsFrom returns a list of all refs in the clone of the repository on the
+	// given gitserver, which need not be the gitserver the repository is assigned to.
+	// Like any command, it starts cloning the repository if it is not cloned there.
+	ListRefsFrom(ctx context.Context, repo api.RepoName, from string) ([]gitdomain.Ref, error)
+
 	// ListBranches returns a list of all branches in the repository.
 	ListBranches(ctx context.Context, repo api.RepoName, opt BranchesOptions) ([]*gitdomain.Branch, error)
 
@@ -319,9 +335,25 @@ type Client interface {
 	// update won't happen.
 	RequestRepoUpdate(context.Context, api.RepoName, time.Duration) (*protocol.RepoUpdateResponse, error)
 
+	// RequestRepoMigrate is a synchronous request to clone the repository onto the
+	// gitserver to from the gitserver from, which currently owns it. If the repository
+	// is already cloned onto to, it is updated from its code host instead.
+	RequestRepoMigrate(ctx context.Context, repo api.RepoName, from, to string) (*protocol.RepoUpdateResponse, error)
+
 	// RequestRepoClone is an asynchronous request to clone a repository.
 	RequestRepoClone(context.Context, api.RepoName) (*protocol.RepoCloneResponse, error)
 
+	// CloneQueue returns the repositories waiting in the clone queue of each
+	// gitserver, keyed by the address of the gitserver. If we fail to fetch the
+	// clone queue of a gitserver, it won't be in the returned map and the error
+	// will be appended to err.
+	CloneQueue(context.Context) (map[string][]protocol.CloneQueueJob, error)
+
+	// BoostClone moves the repository to the front of the clone queue of its
+	// gitserver, so that it is cloned next. It returns false if the repository
+	// is not waiting in the clone queue.
+	BoostClone(context.Context, api.RepoName) (bool, error)
+
 	// Search executes a search as specified by args, streaming the results as
 	// it goes by calling onMatches with each set of results it receives in
 	// response.
@@ -1203,6 +1235,33 @@ func (c *clientImplementor) RequestRepoUpdate(ctx context.Context, repo api.Repo
 	}
 }
 
+func (c *clientImplementor) RequestRepoMigrate(ctx context.Context, repo api.RepoName, from, to string) (*protocol.RepoUpdateResponse, error) {
+	b, err := json.Marshal(&protocol.RepoUpdateRequest{
+		Repo:           repo,
+		CloneFromShard: "http://" + from,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, repo, "POST", "http://"+to+"/repo-update", b)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &url.Error{
+			URL: resp.Request.URL.String(),
+			Op:  "RepoMigrate",
+			Err: errors.Errorf("RepoMigrate: http status %d: %s", resp.StatusCode, readResponseBody(io.LimitReader(resp.Body, 200))),
+		}
+	}
+
+	var info protocol.RepoUpdateResponse
+	err = json.NewDecoder(resp.Body).Decode(&info)
+	return &info, err
+}
+
 // RequestRepoClone requests that the gitserver does an asynchronous clone of the repository.
 func (c *clientImplementor) RequestRepoClone(ctx context.Context, repo api.RepoName) (*protocol.RepoCloneResponse, error) {
 	if internalgrpc.IsGRPCEnabled(ctx) {
@@ -1248,6 +1307,60 @@ func (c *clientImplementor) RequestRepoClone(ctx context.Context, repo api.RepoN
 	}
 }
 
+// CloneQueue is only available over HTTP, as there is no gRPC counterpart of the
+// clone-queue endpoint yet.
+func (c *clientImplementor) CloneQueue(ctx context.Context) (map[string][]protocol.CloneQueueJob, error) {
+	queues := map[string][]protocol.CloneQueueJob{}
+	var allErr error
+
+	for _, addr := range c.Addrs() {
+		jobs, err := c.doCloneQueue(ctx, addr)
+		if err != nil {
+			allErr = errors.Append(allErr, err)
+		} else {
+			queues[addr] = jobs
+		}
+	}
+
+	return queues, allErr
+}
+
+func (c *clientImplementor) doCloneQueue(ctx context.Context, addr string) ([]protocol.CloneQueueJob, error) {
+	resp, err := c.do(ctx, "", "GET", fmt.Sprintf("http://%s/clone-queue", addr), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("CloneQueue: http status %d: %s", resp.StatusCode, readResponseBody(io.LimitReader(resp.Body, 200)))
+	}
+
+	var queue protocol.CloneQueueResponse
+	if err := json.NewDecoder(resp.Body).Decode(&queue); err != nil {
+		return nil, err
+	}
+	return queue.Jobs, nil
+}
+
+// BoostClone is only available over HTTP, as there is no gRPC counterpart of the
+// clone-queue/boost endpoint yet.
+func (c *clientImplementor) BoostClone(ctx context.Context, repo api.RepoName) (bool, error) {
+	resp, err := c.httpPost(ctx, repo, "clone-queue/boost", &protocol.CloneQueueBoostRequest{Repo: repo})
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, errors.Errorf("BoostClone: http status %d: %s", resp.StatusCode, readResponseBody(io.LimitReader(resp.Body, 200)))
+	}
+
+	var boost protocol.CloneQueueBoostResponse
+	if err := json.NewDecoder(resp.Body).Decode(&boost); err != nil {
+		return false, err
+	}
+	return boost.Boosted, nil
+}
+
 // MockIsRepoCloneable mocks (*Client).IsRepoCloneable for tests.
 var MockIsRepoCloneable func(api.RepoName) error
 
@@ -1550,6 +1663,72 @@ func (c *clientImplementor) RemoveFrom(ctx context.Context, repo api.RepoName, f
 	return nil
 }
 
+func (c *clientImplementor) ListRefsFrom(ctx context.Context, repo api.RepoName, from string) (_ []gitdomain.Ref, err error) {
+	ctx, _, endObservation := c.operations.listRefs.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		repo.Attr(),
+		attribute.String("from", from),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	b, err := json.Marshal(&protocol.ExecRequest{
+		Repo: repo,
+		Args: []string{"show-ref"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, repo, "POST", "http://"+from+"/exec", b)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, &gitdomain.RepoNotExistError{Repo: repo}
+	default:
+		return nil, errors.Errorf("ListRefsFrom: http status %d: %s", resp.StatusCode, readResponseBody(io.LimitReader(resp.Body, 200)))
+	}
+
+	out, err := io.ReadAll(&cmdReader{rc: resp.Body, trailer: resp.Trailer})
+	if err != nil {
+		// Exit status of 1 and no output means there were no
+		// results. This is not a fatal error.
+		if v := (&CommandStatusError{}); errors.As(err, &v) && v.StatusCode == 1 && len(out) == 0 {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "reading exec output")
+	}
+	return parseShowRefOutput(out)
+}
+
+// LFSContent is only available over HTTP, as there is no gRPC counterpart of the
+// lfs-content endpoint yet.
+func (c *clientImplementor) LFSContent(ctx context.Context, repo api.RepoName, oid string, size int64) (_ io.ReadCloser, err error) {
+	ctx, _, endObservation := c.operations.lfsContent.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		repo.Attr(),
+		attribute.String("oid", oid),
+		attribute.Int64("size", size),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	resp, err := c.httpPost(ctx, repo, "lfs-content", &protocol.LFSContentRequest{
+		Repo: repo,
+		OID:  oid,
+		Size: size,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, errors.Errorf("LFSContent: http status %d: %s", resp.StatusCode, readResponseBody(io.LimitReader(resp.Body, 200)))
+	}
+	return resp.Body, nil
+}
+
 // httpPost will apply the MD5 hashing scheme on the repo name to determine the gitserver instance
 // to which the HTTP POST request is sent.
 func (c *clientImplementor) httpPost(ctx context.Context, repo api.RepoName, op string, payload any) (resp *http.Response, err error) {
@@ -1723,18 +1902,7 @@ func (c *clientImplementor) ResolveRevisions(ctx context.Context, repo api.RepoN
 }
 
 func revsToGitArgs(revSpecs []protocol.RevisionSpecifier) []string {
-	args := make([]string, 0, len(revSpecs))
-	for _, r := range revSpecs {
-		if r.RevSpec != "" {
-			args = append(args, r.RevSpec)
-		} else if r.RefGlob != "" {
-			args = append(args, "--glob="+r.RefGlob)
-		} else if r.ExcludeRefGlob != "" {
-			args = append(args, "--exclude="+r.ExcludeRefGlob)
-		} else {
-			args = append(args, "HEAD")
-		}
-	}
+	args := protocol.RevisionArgs(revSpecs)
 
 	// If revSpecs is empty, git treats it as equivalent to HEAD
 	if len(revSpecs) == 0 {
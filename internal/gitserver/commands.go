@@ -772,25 +772,53 @@ func streamBlameFileCmd(ctx context.Context, checker authz.SubRepoPermissionChec
 	if !hasAccess {
 		return nil, errUnauthorizedStreamBlame{Repo: repo}
 	}
+	args, err := blameArgs(opt, path, true)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := command(args)
+	// Blaming large files can take longer than the default command timeout. Hunks are
+	// streamed as git produces them, so the command is instead bound by the lifetime of
+	// ctx, which callers cancel once they stop reading.
+	cmd.DisableTimeout()
+	rc, err := cmd.StdoutReader(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed", args))
+	}
+
+	return newBlameHunkReader(rc), nil
+}
+
+// blameArgs returns the arguments to git blame the given file, in the incremental
+// format if incremental is true.
+func blameArgs(opt *BlameOptions, path string, incremental bool) ([]string, error) {
 	if opt == nil {
 		opt = &BlameOptions{}
 	}
 	if err := checkSpecArgSafety(string(opt.NewestCommit)); err != nil {
 		return nil, err
 	}
-
-	args := []string{"blame", "-w", "--porcelain", "--incremental"}
-	if opt.StartLine != 0 || opt.EndLine != 0 {
-		args = append(args, fmt.Sprintf("-L%d,%d", opt.StartLine, opt.EndLine))
+	if opt.StartLine < 0 || opt.EndLine < 0 || (opt.EndLine != 0 && opt.EndLine < opt.StartLine) {
+		return nil, errors.Newf("invalid line range %d-%d", opt.StartLine, opt.EndLine)
 	}
-	args = append(args, string(opt.NewestCommit), "--", filepath.ToSlash(path))
 
-	rc, err := command(args).StdoutReader(ctx)
-	if err != nil {
-		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed", args))
+	args := []string{"blame", "-w", "--porcelain"}
+	if incremental {
+		args = append(args, "--incremental")
 	}
-
-	return newBlameHunkReader(rc), nil
+	if opt.StartLine != 0 || opt.EndLine != 0 {
+		// Lines are 1-indexed, and an empty end means the end of the file.
+		start, end := opt.StartLine, ""
+		if start == 0 {
+			start = 1
+		}
+		if opt.EndLine != 0 {
+			end = strconv.Itoa(opt.EndLine)
+		}
+		args = append(args, fmt.Sprintf("-L%d,%s", start, end))
+	}
+	return append(args, string(opt.NewestCommit), "--", filepath.ToSlash(path)), nil
 }
 
 // BlameFile returns Git blame information about a file.
@@ -811,19 +839,11 @@ func blameFileCmd(ctx context.Context, checker authz.SubRepoPermissionChecker, c
 	if hasAccess, err := authz.FilterActorPath(ctx, checker, a, repo, path); err != nil || !hasAccess {
 		return nil, err
 	}
-	if opt == nil {
-		opt = &BlameOptions{}
-	}
-	if err := checkSpecArgSafety(string(opt.NewestCommit)); err != nil {
+	args, err := blameArgs(opt, path, false)
+	if err != nil {
 		return nil, err
 	}
 
-	args := []string{"blame", "-w", "--porcelain"}
-	if opt.StartLine != 0 || opt.EndLine != 0 {
-		args = append(args, fmt.Sprintf("-L%d,%d", opt.StartLine, opt.EndLine))
-	}
-	args = append(args, string(opt.NewestCommit), "--", filepath.ToSlash(path))
-
 	out, err := command(args).Output(ctx)
 	if err != nil {
 		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", args, out))
@@ -2489,6 +2509,42 @@ func (c *clientImplementor) CommitDate(ctx context.Context, checker authz.SubRep
 	return parts[0], duration, true, nil
 }
 
+// ObjectSignature returns the signature of the commit or annotated tag with the given
+// OID, along with the data it signs. It returns nil if the object is not signed.
+func (c *clientImplementor) ObjectSignature(ctx context.Context, checker authz.SubRepoPermissionChecker, repo api.RepoName, oid string) (_ *gitdomain.SignedObject, err error) {
+	ctx, _, endObservation := c.operations.objectSignature.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		repo.Attr(),
+		attribute.String("oid", oid),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if err := gitdomain.EnsureAbsoluteCommit(api.CommitID(oid)); err != nil {
+		return nil, err
+	}
+
+	obj, err := c.GetObject(ctx, repo, oid)
+	if err != nil {
+		return nil, err
+	}
+	if obj.Type != gitdomain.ObjectTypeCommit && obj.Type != gitdomain.ObjectTypeTag {
+		return nil, errors.Newf("object %s is a %s, not a commit or tag", oid, obj.Type)
+	}
+
+	if obj.Type == gitdomain.ObjectTypeCommit && authz.SubRepoEnabled(checker) {
+		// GetCommit to validate that the user has permissions to access it.
+		if _, err := c.GetCommit(ctx, checker, repo, api.CommitID(oid), ResolveRevisionOptions{}); err != nil {
+			return nil, err
+		}
+	}
+
+	cmd := c.gitCommand(repo, "cat-file", string(obj.Type), oid)
+	out, err := cmd.Output(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed", cmd.Args()))
+	}
+	return gitdomain.ParseSignedObject(obj.Type, out), nil
+}
+
 type ArchiveFormat string
 
 const (
@@ -2819,6 +2875,11 @@ func (c *clientImplementor) showRef(ctx context.Context, repo api.RepoName, args
 		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args(), out))
 	}
 
+	return parseShowRefOutput(out)
+}
+
+// parseShowRefOutput parses the output of `git show-ref`.
+func parseShowRefOutput(out []byte) ([]gitdomain.Ref, error) {
 	out = bytes.TrimSuffix(out, []byte("\n")) // remove trailing newline
 	lines := bytes.Split(out, []byte("\n"))
 	sort.Sort(byteSlices(lines)) // sort for consistency
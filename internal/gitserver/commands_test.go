@@ -3193,6 +3193,77 @@ func TestStreamBlameFile(t *testing.T) {
 	})
 }
 
+func TestBlameArgs(t *testing.T) {
+	tests := map[string]struct {
+		opt     *BlameOptions
+		want    []string
+		wantErr bool
+	}{
+		"whole file": {
+			opt:  &BlameOptions{NewestCommit: "abc"},
+			want: []string{"blame", "-w", "--porcelain", "--incremental", "abc", "--", "a/b.go"},
+		},
+		"line range": {
+			opt:  &BlameOptions{NewestCommit: "abc", StartLine: 10, EndLine: 20},
+			want: []string{"blame", "-w", "--porcelain", "--incremental", "-L10,20", "abc", "--", "a/b.go"},
+		},
+		"start line only": {
+			opt:  &BlameOptions{NewestCommit: "abc", StartLine: 10},
+			want: []string{"blame", "-w", "--porcelain", "--incremental", "-L10,", "abc", "--", "a/b.go"},
+		},
+		"end line only": {
+			opt:  &BlameOptions{NewestCommit: "abc", EndLine: 20},
+			want: []string{"blame", "-w", "--porcelain", "--incremental", "-L1,20", "abc", "--", "a/b.go"},
+		},
+		"end before start": {
+			opt:     &BlameOptions{NewestCommit: "abc", StartLine: 20, EndLine: 10},
+			wantErr: true,
+		},
+		"negative line": {
+			opt:     &BlameOptions{NewestCommit: "abc", StartLine: -1},
+			wantErr: true,
+		},
+		"unsafe commit": {
+			opt:     &BlameOptions{NewestCommit: "-x"},
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			args, err := blameArgs(test.opt, "a/b.go", true)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got args %v", args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(test.want, args); d != "" {
+				t.Fatalf("unexpected args (-want, +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestReadAllHunks(t *testing.T) {
+	hunks, err := ReadAllHunks(NewMockHunkReader([]*Hunk{
+		{StartLine: 5, EndLine: 10},
+		{StartLine: 1, EndLine: 5},
+	}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]*Hunk{{StartLine: 1, EndLine: 5}, {StartLine: 5, EndLine: 10}}, hunks); d != "" {
+		t.Fatalf("unexpected hunks (-want, +got):\n%s", d)
+	}
+
+	if _, err := ReadAllHunks(NewMockHunkReader(nil, errors.New("boom"))); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
 func TestBlameHunkReader(t *testing.T) {
 	t.Run("OK matching hunks", func(t *testing.T) {
 		rc := io.NopCloser(strings.NewReader(testGitBlameOutputIncremental))
@@ -0,0 +1,121 @@
+package gitdomain
+
+import (
+	"bytes"
+)
+
+// SignatureFormat is the format of the signature of a commit or tag.
+type SignatureFormat string
+
+const (
+	SignatureFormatGPG  SignatureFormat = "gpg"
+	SignatureFormatSSH  SignatureFormat = "ssh"
+	SignatureFormatX509 SignatureFormat = "x509"
+)
+
+var signatureHeaders = map[string]SignatureFormat{
+	"-----BEGIN PGP SIGNATURE-----":  SignatureFormatGPG,
+	"-----BEGIN PGP MESSAGE-----":    SignatureFormatGPG,
+	"-----BEGIN SSH SIGNATURE-----":  SignatureFormatSSH,
+	"-----BEGIN SIGNED MESSAGE-----": SignatureFormatX509,
+}
+
+// SignedObject is the signature of a commit or tag, along with the data it signs.
+type SignedObject struct {
+	// Type is the type of the signed object, either a commit or a tag.
+	Type ObjectType
+	// Format is the format of the signature.
+	Format SignatureFormat
+	// Signature is the armored signature.
+	Signature []byte
+	// Payload is the object the signature was made over, which is the raw object
+	// without its signature.
+	Payload []byte
+}
+
+// ParseSignedObject extracts the signature from the raw contents of a commit or tag, as
+// printed by `git cat-file`. It returns nil if the object is not signed.
+//
+// Commits carry their signature in a gpgsig header, while tags have it appended to
+// their message.
+func ParseSignedObject(typ ObjectType, raw []byte) *SignedObject {
+	switch typ {
+	case ObjectTypeCommit:
+		return parseSignedCommit(raw)
+	case ObjectTypeTag:
+		return parseSignedTag(raw)
+	default:
+		return nil
+	}
+}
+
+func parseSignedCommit(raw []byte) *SignedObject {
+	var payload, signature []byte
+	inSignature := false
+
+	rest := raw
+	for len(rest) > 0 {
+		line := rest
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			line = rest[:i+1]
+		}
+		rest = rest[len(line):]
+
+		if inSignature {
+			if bytes.HasPrefix(line, []byte(" ")) {
+				signature = append(signature, line[1:]...)
+				continue
+			}
+			inSignature = false
+		}
+		if bytes.Equal(line, []byte("\n")) {
+			// The headers end at the first empty line, which starts the message
+			payload = append(payload, line...)
+			payload = append(payload, rest...)
+			break
+		}
+		if signature == nil && bytes.HasPrefix(line, []byte("gpgsig ")) {
+			signature = append(signature, bytes.TrimPrefix(line, []byte("gpgsig "))...)
+			inSignature = true
+			continue
+		}
+		payload = append(payload, line...)
+	}
+
+	if signature == nil {
+		return nil
+	}
+	format, ok := signatureFormat(signature)
+	if !ok {
+		return nil
+	}
+	return &SignedObject{Type: ObjectTypeCommit, Format: format, Signature: signature, Payload: payload}
+}
+
+func parseSignedTag(raw []byte) *SignedObject {
+	// The signature starts at the last line that looks like an armor header, since the
+	// message itself may quote one.
+	start := -1
+	for offset := 0; offset < len(raw); {
+		end := bytes.IndexByte(raw[offset:], '\n')
+		if end < 0 {
+			end = len(raw) - offset
+		}
+		if _, ok := signatureFormat(raw[offset : offset+end]); ok {
+			start = offset
+		}
+		offset += end + 1
+	}
+	if start < 0 {
+		return nil
+	}
+
+	format, _ := signatureFormat(raw[start:])
+	return &SignedObject{Type: ObjectTypeTag, Format: format, Signature: raw[start:], Payload: raw[:start]}
+}
+
+func signatureFormat(signature []byte) (SignatureFormat, bool) {
+	firstLine, _, _ := bytes.Cut(signature, []byte("\n"))
+	format, ok := signatureHeaders[string(bytes.TrimRight(firstLine, "\r"))]
+	return format, ok
+}
@@ -0,0 +1,65 @@
+package gitdomain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSignedObject(t *testing.T) {
+	const commitHeaders = "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+		"author Alice <alice@example.com> 1600000000 +0000\n" +
+		"committer Alice <alice@example.com> 1600000000 +0000\n"
+	const signature = "-----BEGIN SSH SIGNATURE-----\nU1NIU0lH\n-----END SSH SIGNATURE-----\n"
+
+	for _, tc := range []struct {
+		name string
+		typ  ObjectType
+		raw  string
+		want *SignedObject
+	}{
+		{
+			name: "unsigned commit",
+			typ:  ObjectTypeCommit,
+			raw:  commitHeaders + "\nmessage\n",
+		},
+		{
+			name: "signed commit",
+			typ:  ObjectTypeCommit,
+			raw: commitHeaders +
+				"gpgsig -----BEGIN SSH SIGNATURE-----\n U1NIU0lH\n -----END SSH SIGNATURE-----\n" +
+				"\nmessage\n gpgsig in message\n",
+			want: &SignedObject{
+				Type:      ObjectTypeCommit,
+				Format:    SignatureFormatSSH,
+				Signature: []byte(signature),
+				Payload:   []byte(commitHeaders + "\nmessage\n gpgsig in message\n"),
+			},
+		},
+		{
+			name: "unsigned tag",
+			typ:  ObjectTypeTag,
+			raw:  "object 4b825dc642cb6eb9a060e54bf8d69288fbee4904\ntype commit\ntag v1\n\nmessage\n",
+		},
+		{
+			name: "signed tag",
+			typ:  ObjectTypeTag,
+			raw:  "object 4b825dc642cb6eb9a060e54bf8d69288fbee4904\ntype commit\ntag v1\n\nmessage\n" + signature,
+			want: &SignedObject{
+				Type:      ObjectTypeTag,
+				Format:    SignatureFormatSSH,
+				Signature: []byte(signature),
+				Payload:   []byte("object 4b825dc642cb6eb9a060e54bf8d69288fbee4904\ntype commit\ntag v1\n\nmessage\n"),
+			},
+		},
+		{
+			name: "blob",
+			typ:  ObjectTypeBlob,
+			raw:  signature,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ParseSignedObject(tc.typ, []byte(tc.raw)))
+		})
+	}
+}
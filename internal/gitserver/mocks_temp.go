@@ -40,9 +40,15 @@ type MockClient struct {
 	// BlameFileFunc is an instance of a mock function object controlling
 	// the behavior of the method BlameFile.
 	BlameFileFunc *ClientBlameFileFunc
+	// BoostCloneFunc is an instance of a mock function object controlling
+	// the behavior of the method BoostClone.
+	BoostCloneFunc *ClientBoostCloneFunc
 	// BranchesContainingFunc is an instance of a mock function object
 	// controlling the behavior of the method BranchesContaining.
 	BranchesContainingFunc *ClientBranchesContainingFunc
+	// CloneQueueFunc is an instance of a mock function object controlling
+	// the behavior of the method CloneQueue.
+	CloneQueueFunc *ClientCloneQueueFunc
 	// CommitDateFunc is an instance of a mock function object controlling
 	// the behavior of the method CommitDate.
 	CommitDateFunc *ClientCommitDateFunc
@@ -106,6 +112,9 @@ type MockClient struct {
 	// IsRepoCloneableFunc is an instance of a mock function object
 	// controlling the behavior of the method IsRepoCloneable.
 	IsRepoCloneableFunc *ClientIsRepoCloneableFunc
+	// LFSContentFunc is an instance of a mock function object controlling
+	// the behavior of the method LFSContent.
+	LFSContentFunc *ClientLFSContentFunc
 	// ListBranchesFunc is an instance of a mock function object controlling
 	// the behavior of the method ListBranches.
 	ListBranchesFunc *ClientListBranchesFunc
@@ -118,6 +127,9 @@ type MockClient struct {
 	// ListRefsFunc is an instance of a mock function object controlling the
 	// behavior of the method ListRefs.
 	ListRefsFunc *ClientListRefsFunc
+	// ListRefsFromFunc is an instance of a mock function object controlling
+	// the behavior of the method ListRefsFrom.
+	ListRefsFromFunc *ClientListRefsFromFunc
 	// ListTagsFunc is an instance of a mock function object controlling the
 	// behavior of the method ListTags.
 	ListTagsFunc *ClientListTagsFunc
@@ -133,6 +145,9 @@ type MockClient struct {
 	// NewFileReaderFunc is an instance of a mock function object
 	// controlling the behavior of the method NewFileReader.
 	NewFileReaderFunc *ClientNewFileReaderFunc
+	// ObjectSignatureFunc is an instance of a mock function object
+	// controlling the behavior of the method ObjectSignature.
+	ObjectSignatureFunc *ClientObjectSignatureFunc
 	// P4ExecFunc is an instance of a mock function object controlling the
 	// behavior of the method P4Exec.
 	P4ExecFunc *ClientP4ExecFunc
@@ -163,6 +178,9 @@ type MockClient struct {
 	// RequestRepoCloneFunc is an instance of a mock function object
 	// controlling the behavior of the method RequestRepoClone.
 	RequestRepoCloneFunc *ClientRequestRepoCloneFunc
+	// RequestRepoMigrateFunc is an instance of a mock function object
+	// controlling the behavior of the method RequestRepoMigrate.
+	RequestRepoMigrateFunc *ClientRequestRepoMigrateFunc
 	// RequestRepoUpdateFunc is an instance of a mock function object
 	// controlling the behavior of the method RequestRepoUpdate.
 	RequestRepoUpdateFunc *ClientRequestRepoUpdateFunc
@@ -215,11 +233,21 @@ func NewMockClient() *MockClient {
 				return
 			},
 		},
+		BoostCloneFunc: &ClientBoostCloneFunc{
+			defaultHook: func(context.Context, api.RepoName) (r0 bool, r1 error) {
+				return
+			},
+		},
 		BranchesContainingFunc: &ClientBranchesContainingFunc{
 			defaultHook: func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) (r0 []string, r1 error) {
 				return
 			},
 		},
+		CloneQueueFunc: &ClientCloneQueueFunc{
+			defaultHook: func(context.Context) (r0 map[string][]protocol.CloneQueueJob, r1 error) {
+				return
+			},
+		},
 		CommitDateFunc: &ClientCommitDateFunc{
 			defaultHook: func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) (r0 string, r1 time.Time, r2 bool, r3 error) {
 				return
@@ -325,6 +353,11 @@ func NewMockClient() *MockClient {
 				return
 			},
 		},
+		LFSContentFunc: &ClientLFSContentFunc{
+			defaultHook: func(context.Context, api.RepoName, string, int64) (r0 io.ReadCloser, r1 error) {
+				return
+			},
+		},
 		ListBranchesFunc: &ClientListBranchesFunc{
 			defaultHook: func(context.Context, api.RepoName, BranchesOptions) (r0 []*gitdomain.Branch, r1 error) {
 				return
@@ -345,6 +378,11 @@ func NewMockClient() *MockClient {
 				return
 			},
 		},
+		ListRefsFromFunc: &ClientListRefsFromFunc{
+			defaultHook: func(context.Context, api.RepoName, string) (r0 []gitdomain.Ref, r1 error) {
+				return
+			},
+		},
 		ListTagsFunc: &ClientListTagsFunc{
 			defaultHook: func(context.Context, api.RepoName, ...string) (r0 []*gitdomain.Tag, r1 error) {
 				return
@@ -370,6 +408,11 @@ func NewMockClient() *MockClient {
 				return
 			},
 		},
+		ObjectSignatureFunc: &ClientObjectSignatureFunc{
+			defaultHook: func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (r0 *gitdomain.SignedObject, r1 error) {
+				return
+			},
+		},
 		P4ExecFunc: &ClientP4ExecFunc{
 			defaultHook: func(context.Context, string, string, string, ...string) (r0 io.ReadCloser, r1 http.Header, r2 error) {
 				return
@@ -420,6 +463,11 @@ func NewMockClient() *MockClient {
 				return
 			},
 		},
+		RequestRepoMigrateFunc: &ClientRequestRepoMigrateFunc{
+			defaultHook: func(context.Context, api.RepoName, string, string) (r0 *protocol.RepoUpdateResponse, r1 error) {
+				return
+			},
+		},
 		RequestRepoUpdateFunc: &ClientRequestRepoUpdateFunc{
 			defaultHook: func(context.Context, api.RepoName, time.Duration) (r0 *protocol.RepoUpdateResponse, r1 error) {
 				return
@@ -487,11 +535,21 @@ func NewStrictMockClient() *MockClient {
 				panic("unexpected invocation of MockClient.BlameFile")
 			},
 		},
+		BoostCloneFunc: &ClientBoostCloneFunc{
+			defaultHook: func(context.Context, api.RepoName) (bool, error) {
+				panic("unexpected invocation of MockClient.BoostClone")
+			},
+		},
 		BranchesContainingFunc: &ClientBranchesContainingFunc{
 			defaultHook: func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) ([]string, error) {
 				panic("unexpected invocation of MockClient.BranchesContaining")
 			},
 		},
+		CloneQueueFunc: &ClientCloneQueueFunc{
+			defaultHook: func(context.Context) (map[string][]protocol.CloneQueueJob, error) {
+				panic("unexpected invocation of MockClient.CloneQueue")
+			},
+		},
 		CommitDateFunc: &ClientCommitDateFunc{
 			defaultHook: func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID) (string, time.Time, bool, error) {
 				panic("unexpected invocation of MockClient.CommitDate")
@@ -597,6 +655,11 @@ func NewStrictMockClient() *MockClient {
 				panic("unexpected invocation of MockClient.IsRepoCloneable")
 			},
 		},
+		LFSContentFunc: &ClientLFSContentFunc{
+			defaultHook: func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error) {
+				panic("unexpected invocation of MockClient.LFSContent")
+			},
+		},
 		ListBranchesFunc: &ClientListBranchesFunc{
 			defaultHook: func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error) {
 				panic("unexpected invocation of MockClient.ListBranches")
@@ -617,6 +680,11 @@ func NewStrictMockClient() *MockClient {
 				panic("unexpected invocation of MockClient.ListRefs")
 			},
 		},
+		ListRefsFromFunc: &ClientListRefsFromFunc{
+			defaultHook: func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error) {
+				panic("unexpected invocation of MockClient.ListRefsFrom")
+			},
+		},
 		ListTagsFunc: &ClientListTagsFunc{
 			defaultHook: func(context.Context, api.RepoName, ...string) ([]*gitdomain.Tag, error) {
 				panic("unexpected invocation of MockClient.ListTags")
@@ -642,6 +710,11 @@ func NewStrictMockClient() *MockClient {
 				panic("unexpected invocation of MockClient.NewFileReader")
 			},
 		},
+		ObjectSignatureFunc: &ClientObjectSignatureFunc{
+			defaultHook: func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error) {
+				panic("unexpected invocation of MockClient.ObjectSignature")
+			},
+		},
 		P4ExecFunc: &ClientP4ExecFunc{
 			defaultHook: func(context.Context, string, string, string, ...string) (io.ReadCloser, http.Header, error) {
 				panic("unexpected invocation of MockClient.P4Exec")
@@ -692,6 +765,11 @@ func NewStrictMockClient() *MockClient {
 				panic("unexpected invocation of MockClient.RequestRepoClone")
 			},
 		},
+		RequestRepoMigrateFunc: &ClientRequestRepoMigrateFunc{
+			defaultHook: func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error) {
+				panic("unexpected invocation of MockClient.RequestRepoMigrate")
+			},
+		},
 		RequestRepoUpdateFunc: &ClientRequestRepoUpdateFunc{
 			defaultHook: func(context.Context, api.RepoName, time.Duration) (*protocol.RepoUpdateResponse, error) {
 				panic("unexpected invocation of MockClient.RequestRepoUpdate")
@@ -749,9 +827,15 @@ func NewMockClientFrom(i Client) *MockClient {
 		BlameFileFunc: &ClientBlameFileFunc{
 			defaultHook: i.BlameFile,
 		},
+		BoostCloneFunc: &ClientBoostCloneFunc{
+			defaultHook: i.BoostClone,
+		},
 		BranchesContainingFunc: &ClientBranchesContainingFunc{
 			defaultHook: i.BranchesContaining,
 		},
+		CloneQueueFunc: &ClientCloneQueueFunc{
+			defaultHook: i.CloneQueue,
+		},
 		CommitDateFunc: &ClientCommitDateFunc{
 			defaultHook: i.CommitDate,
 		},
@@ -815,6 +899,9 @@ func NewMockClientFrom(i Client) *MockClient {
 		IsRepoCloneableFunc: &ClientIsRepoCloneableFunc{
 			defaultHook: i.IsRepoCloneable,
 		},
+		LFSContentFunc: &ClientLFSContentFunc{
+			defaultHook: i.LFSContent,
+		},
 		ListBranchesFunc: &ClientListBranchesFunc{
 			defaultHook: i.ListBranches,
 		},
@@ -827,6 +914,9 @@ func NewMockClientFrom(i Client) *MockClient {
 		ListRefsFunc: &ClientListRefsFunc{
 			defaultHook: i.ListRefs,
 		},
+		ListRefsFromFunc: &ClientListRefsFromFunc{
+			defaultHook: i.ListRefsFrom,
+		},
 		ListTagsFunc: &ClientListTagsFunc{
 			defaultHook: i.ListTags,
 		},
@@ -842,6 +932,9 @@ func NewMockClientFrom(i Client) *MockClient {
 		NewFileReaderFunc: &ClientNewFileReaderFunc{
 			defaultHook: i.NewFileReader,
 		},
+		ObjectSignatureFunc: &ClientObjectSignatureFunc{
+			defaultHook: i.ObjectSignature,
+		},
 		P4ExecFunc: &ClientP4ExecFunc{
 			defaultHook: i.P4Exec,
 		},
@@ -872,6 +965,9 @@ func NewMockClientFrom(i Client) *MockClient {
 		RequestRepoCloneFunc: &ClientRequestRepoCloneFunc{
 			defaultHook: i.RequestRepoClone,
 		},
+		RequestRepoMigrateFunc: &ClientRequestRepoMigrateFunc{
+			defaultHook: i.RequestRepoMigrate,
+		},
 		RequestRepoUpdateFunc: &ClientRequestRepoUpdateFunc{
 			defaultHook: i.RequestRepoUpdate,
 		},
@@ -1431,6 +1527,113 @@ func (c ClientBlameFileFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// ClientBoostCloneFunc describes the behavior when the BoostClone method of
+// the parent MockClient instance is invoked.
+type ClientBoostCloneFunc struct {
+	defaultHook func(context.Context, api.RepoName) (bool, error)
+	hooks       []func(context.Context, api.RepoName) (bool, error)
+	history     []ClientBoostCloneFuncCall
+	mutex       sync.Mutex
+}
+
+// BoostClone delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockClient) BoostClone(v0 context.Context, v1 api.RepoName) (bool, error) {
+	r0, r1 := m.BoostCloneFunc.nextHook()(v0, v1)
+	m.BoostCloneFunc.appendCall(ClientBoostCloneFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the BoostClone method of
+// the parent MockClient instance is invoked and the hook queue is empty.
+func (f *ClientBoostCloneFunc) SetDefaultHook(hook func(context.Context, api.RepoName) (bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// BoostClone method of the parent MockClient instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *ClientBoostCloneFunc) PushHook(hook func(context.Context, api.RepoName) (bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ClientBoostCloneFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName) (bool, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ClientBoostCloneFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName) (bool, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientBoostCloneFunc) nextHook() func(context.Context, api.RepoName) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientBoostCloneFunc) appendCall(r0 ClientBoostCloneFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientBoostCloneFuncCall objects describing
+// the invocations of this function.
+func (f *ClientBoostCloneFunc) History() []ClientBoostCloneFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientBoostCloneFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientBoostCloneFuncCall is an object that describes an invocation of
+// method BoostClone on an instance of MockClient.
+type ClientBoostCloneFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientBoostCloneFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientBoostCloneFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ClientBranchesContainingFunc describes the behavior when the
 // BranchesContaining method of the parent MockClient instance is invoked.
 type ClientBranchesContainingFunc struct {
@@ -1545,6 +1748,110 @@ func (c ClientBranchesContainingFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// ClientCloneQueueFunc describes the behavior when the CloneQueue method of
+// the parent MockClient instance is invoked.
+type ClientCloneQueueFunc struct {
+	defaultHook func(context.Context) (map[string][]protocol.CloneQueueJob, error)
+	hooks       []func(context.Context) (map[string][]protocol.CloneQueueJob, error)
+	history     []ClientCloneQueueFuncCall
+	mutex       sync.Mutex
+}
+
+// CloneQueue delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockClient) CloneQueue(v0 context.Context) (map[string][]protocol.CloneQueueJob, error) {
+	r0, r1 := m.CloneQueueFunc.nextHook()(v0)
+	m.CloneQueueFunc.appendCall(ClientCloneQueueFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the CloneQueue method of
+// the parent MockClient instance is invoked and the hook queue is empty.
+func (f *ClientCloneQueueFunc) SetDefaultHook(hook func(context.Context) (map[string][]protocol.CloneQueueJob, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CloneQueue method of the parent MockClient instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *ClientCloneQueueFunc) PushHook(hook func(context.Context) (map[string][]protocol.CloneQueueJob, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ClientCloneQueueFunc) SetDefaultReturn(r0 map[string][]protocol.CloneQueueJob, r1 error) {
+	f.SetDefaultHook(func(context.Context) (map[string][]protocol.CloneQueueJob, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ClientCloneQueueFunc) PushReturn(r0 map[string][]protocol.CloneQueueJob, r1 error) {
+	f.PushHook(func(context.Context) (map[string][]protocol.CloneQueueJob, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientCloneQueueFunc) nextHook() func(context.Context) (map[string][]protocol.CloneQueueJob, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientCloneQueueFunc) appendCall(r0 ClientCloneQueueFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientCloneQueueFuncCall objects describing
+// the invocations of this function.
+func (f *ClientCloneQueueFunc) History() []ClientCloneQueueFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientCloneQueueFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientCloneQueueFuncCall is an object that describes an invocation of
+// method CloneQueue on an instance of MockClient.
+type ClientCloneQueueFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 map[string][]protocol.CloneQueueJob
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientCloneQueueFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientCloneQueueFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ClientCommitDateFunc describes the behavior when the CommitDate method of
 // the parent MockClient instance is invoked.
 type ClientCommitDateFunc struct {
@@ -3916,34 +4223,34 @@ func (c ClientIsRepoCloneableFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
-// ClientListBranchesFunc describes the behavior when the ListBranches
-// method of the parent MockClient instance is invoked.
-type ClientListBranchesFunc struct {
-	defaultHook func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error)
-	hooks       []func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error)
-	history     []ClientListBranchesFuncCall
+// ClientLFSContentFunc describes the behavior when the LFSContent method of
+// the parent MockClient instance is invoked.
+type ClientLFSContentFunc struct {
+	defaultHook func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error)
+	hooks       []func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error)
+	history     []ClientLFSContentFuncCall
 	mutex       sync.Mutex
 }
 
-// ListBranches delegates to the next hook function in the queue and stores
+// LFSContent delegates to the next hook function in the queue and stores
 // the parameter and result values of this invocation.
-func (m *MockClient) ListBranches(v0 context.Context, v1 api.RepoName, v2 BranchesOptions) ([]*gitdomain.Branch, error) {
-	r0, r1 := m.ListBranchesFunc.nextHook()(v0, v1, v2)
-	m.ListBranchesFunc.appendCall(ClientListBranchesFuncCall{v0, v1, v2, r0, r1})
+func (m *MockClient) LFSContent(v0 context.Context, v1 api.RepoName, v2 string, v3 int64) (io.ReadCloser, error) {
+	r0, r1 := m.LFSContentFunc.nextHook()(v0, v1, v2, v3)
+	m.LFSContentFunc.appendCall(ClientLFSContentFuncCall{v0, v1, v2, v3, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the ListBranches method
-// of the parent MockClient instance is invoked and the hook queue is empty.
-func (f *ClientListBranchesFunc) SetDefaultHook(hook func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error)) {
+// SetDefaultHook sets function that is called when the LFSContent method of
+// the parent MockClient instance is invoked and the hook queue is empty.
+func (f *ClientLFSContentFunc) SetDefaultHook(hook func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// ListBranches method of the parent MockClient instance invokes the hook at
+// LFSContent method of the parent MockClient instance invokes the hook at
 // the front of the queue and discards it. After the queue is empty, the
 // default hook function is invoked for any future action.
-func (f *ClientListBranchesFunc) PushHook(hook func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error)) {
+func (f *ClientLFSContentFunc) PushHook(hook func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -3951,20 +4258,20 @@ func (f *ClientListBranchesFunc) PushHook(hook func(context.Context, api.RepoNam
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *ClientListBranchesFunc) SetDefaultReturn(r0 []*gitdomain.Branch, r1 error) {
-	f.SetDefaultHook(func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error) {
+func (f *ClientLFSContentFunc) SetDefaultReturn(r0 io.ReadCloser, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *ClientListBranchesFunc) PushReturn(r0 []*gitdomain.Branch, r1 error) {
-	f.PushHook(func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error) {
+func (f *ClientLFSContentFunc) PushReturn(r0 io.ReadCloser, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error) {
 		return r0, r1
 	})
 }
 
-func (f *ClientListBranchesFunc) nextHook() func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error) {
+func (f *ClientLFSContentFunc) nextHook() func(context.Context, api.RepoName, string, int64) (io.ReadCloser, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -3977,26 +4284,26 @@ func (f *ClientListBranchesFunc) nextHook() func(context.Context, api.RepoName,
 	return hook
 }
 
-func (f *ClientListBranchesFunc) appendCall(r0 ClientListBranchesFuncCall) {
+func (f *ClientLFSContentFunc) appendCall(r0 ClientLFSContentFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of ClientListBranchesFuncCall objects
-// describing the invocations of this function.
-func (f *ClientListBranchesFunc) History() []ClientListBranchesFuncCall {
+// History returns a sequence of ClientLFSContentFuncCall objects describing
+// the invocations of this function.
+func (f *ClientLFSContentFunc) History() []ClientLFSContentFuncCall {
 	f.mutex.Lock()
-	history := make([]ClientListBranchesFuncCall, len(f.history))
+	history := make([]ClientLFSContentFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// ClientListBranchesFuncCall is an object that describes an invocation of
-// method ListBranches on an instance of MockClient.
-type ClientListBranchesFuncCall struct {
+// ClientLFSContentFuncCall is an object that describes an invocation of
+// method LFSContent on an instance of MockClient.
+type ClientLFSContentFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -4005,10 +4312,13 @@ type ClientListBranchesFuncCall struct {
 	Arg1 api.RepoName
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
-	Arg2 BranchesOptions
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int64
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []*gitdomain.Branch
+	Result0 io.ReadCloser
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -4016,31 +4326,141 @@ type ClientListBranchesFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c ClientListBranchesFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+func (c ClientLFSContentFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c ClientListBranchesFuncCall) Results() []interface{} {
+func (c ClientLFSContentFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// ClientListDirectoryChildrenFunc describes the behavior when the
-// ListDirectoryChildren method of the parent MockClient instance is
-// invoked.
-type ClientListDirectoryChildrenFunc struct {
-	defaultHook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID, []string) (map[string][]string, error)
-	hooks       []func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID, []string) (map[string][]string, error)
-	history     []ClientListDirectoryChildrenFuncCall
+// ClientListBranchesFunc describes the behavior when the ListBranches
+// method of the parent MockClient instance is invoked.
+type ClientListBranchesFunc struct {
+	defaultHook func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error)
+	hooks       []func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error)
+	history     []ClientListBranchesFuncCall
 	mutex       sync.Mutex
 }
 
-// ListDirectoryChildren delegates to the next hook function in the queue
-// and stores the parameter and result values of this invocation.
-func (m *MockClient) ListDirectoryChildren(v0 context.Context, v1 authz.SubRepoPermissionChecker, v2 api.RepoName, v3 api.CommitID, v4 []string) (map[string][]string, error) {
-	r0, r1 := m.ListDirectoryChildrenFunc.nextHook()(v0, v1, v2, v3, v4)
-	m.ListDirectoryChildrenFunc.appendCall(ClientListDirectoryChildrenFuncCall{v0, v1, v2, v3, v4, r0, r1})
+// ListBranches delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockClient) ListBranches(v0 context.Context, v1 api.RepoName, v2 BranchesOptions) ([]*gitdomain.Branch, error) {
+	r0, r1 := m.ListBranchesFunc.nextHook()(v0, v1, v2)
+	m.ListBranchesFunc.appendCall(ClientListBranchesFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ListBranches method
+// of the parent MockClient instance is invoked and the hook queue is empty.
+func (f *ClientListBranchesFunc) SetDefaultHook(hook func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListBranches method of the parent MockClient instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *ClientListBranchesFunc) PushHook(hook func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ClientListBranchesFunc) SetDefaultReturn(r0 []*gitdomain.Branch, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ClientListBranchesFunc) PushReturn(r0 []*gitdomain.Branch, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientListBranchesFunc) nextHook() func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientListBranchesFunc) appendCall(r0 ClientListBranchesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientListBranchesFuncCall objects
+// describing the invocations of this function.
+func (f *ClientListBranchesFunc) History() []ClientListBranchesFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientListBranchesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientListBranchesFuncCall is an object that describes an invocation of
+// method ListBranches on an instance of MockClient.
+type ClientListBranchesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 BranchesOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*gitdomain.Branch
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientListBranchesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientListBranchesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// ClientListDirectoryChildrenFunc describes the behavior when the
+// ListDirectoryChildren method of the parent MockClient instance is
+// invoked.
+type ClientListDirectoryChildrenFunc struct {
+	defaultHook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID, []string) (map[string][]string, error)
+	hooks       []func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, api.CommitID, []string) (map[string][]string, error)
+	history     []ClientListDirectoryChildrenFuncCall
+	mutex       sync.Mutex
+}
+
+// ListDirectoryChildren delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockClient) ListDirectoryChildren(v0 context.Context, v1 authz.SubRepoPermissionChecker, v2 api.RepoName, v3 api.CommitID, v4 []string) (map[string][]string, error) {
+	r0, r1 := m.ListDirectoryChildrenFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.ListDirectoryChildrenFunc.appendCall(ClientListDirectoryChildrenFuncCall{v0, v1, v2, v3, v4, r0, r1})
 	return r0, r1
 }
 
@@ -4367,6 +4787,116 @@ func (c ClientListRefsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// ClientListRefsFromFunc describes the behavior when the ListRefsFrom
+// method of the parent MockClient instance is invoked.
+type ClientListRefsFromFunc struct {
+	defaultHook func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error)
+	hooks       []func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error)
+	history     []ClientListRefsFromFuncCall
+	mutex       sync.Mutex
+}
+
+// ListRefsFrom delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockClient) ListRefsFrom(v0 context.Context, v1 api.RepoName, v2 string) ([]gitdomain.Ref, error) {
+	r0, r1 := m.ListRefsFromFunc.nextHook()(v0, v1, v2)
+	m.ListRefsFromFunc.appendCall(ClientListRefsFromFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ListRefsFrom method
+// of the parent MockClient instance is invoked and the hook queue is empty.
+func (f *ClientListRefsFromFunc) SetDefaultHook(hook func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListRefsFrom method of the parent MockClient instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *ClientListRefsFromFunc) PushHook(hook func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ClientListRefsFromFunc) SetDefaultReturn(r0 []gitdomain.Ref, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ClientListRefsFromFunc) PushReturn(r0 []gitdomain.Ref, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientListRefsFromFunc) nextHook() func(context.Context, api.RepoName, string) ([]gitdomain.Ref, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientListRefsFromFunc) appendCall(r0 ClientListRefsFromFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientListRefsFromFuncCall objects
+// describing the invocations of this function.
+func (f *ClientListRefsFromFunc) History() []ClientListRefsFromFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientListRefsFromFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientListRefsFromFuncCall is an object that describes an invocation of
+// method ListRefsFrom on an instance of MockClient.
+type ClientListRefsFromFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []gitdomain.Ref
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientListRefsFromFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientListRefsFromFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ClientListTagsFunc describes the behavior when the ListTags method of the
 // parent MockClient instance is invoked.
 type ClientListTagsFunc struct {
@@ -4950,6 +5480,120 @@ func (c ClientNewFileReaderFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// ClientObjectSignatureFunc describes the behavior when the ObjectSignature
+// method of the parent MockClient instance is invoked.
+type ClientObjectSignatureFunc struct {
+	defaultHook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error)
+	hooks       []func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error)
+	history     []ClientObjectSignatureFuncCall
+	mutex       sync.Mutex
+}
+
+// ObjectSignature delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockClient) ObjectSignature(v0 context.Context, v1 authz.SubRepoPermissionChecker, v2 api.RepoName, v3 string) (*gitdomain.SignedObject, error) {
+	r0, r1 := m.ObjectSignatureFunc.nextHook()(v0, v1, v2, v3)
+	m.ObjectSignatureFunc.appendCall(ClientObjectSignatureFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ObjectSignature
+// method of the parent MockClient instance is invoked and the hook queue is
+// empty.
+func (f *ClientObjectSignatureFunc) SetDefaultHook(hook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ObjectSignature method of the parent MockClient instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *ClientObjectSignatureFunc) PushHook(hook func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ClientObjectSignatureFunc) SetDefaultReturn(r0 *gitdomain.SignedObject, r1 error) {
+	f.SetDefaultHook(func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ClientObjectSignatureFunc) PushReturn(r0 *gitdomain.SignedObject, r1 error) {
+	f.PushHook(func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientObjectSignatureFunc) nextHook() func(context.Context, authz.SubRepoPermissionChecker, api.RepoName, string) (*gitdomain.SignedObject, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientObjectSignatureFunc) appendCall(r0 ClientObjectSignatureFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientObjectSignatureFuncCall objects
+// describing the invocations of this function.
+func (f *ClientObjectSignatureFunc) History() []ClientObjectSignatureFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientObjectSignatureFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientObjectSignatureFuncCall is an object that describes an invocation
+// of method ObjectSignature on an instance of MockClient.
+type ClientObjectSignatureFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 authz.SubRepoPermissionChecker
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 api.RepoName
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *gitdomain.SignedObject
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientObjectSignatureFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientObjectSignatureFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ClientP4ExecFunc describes the behavior when the P4Exec method of the
 // parent MockClient instance is invoked.
 type ClientP4ExecFunc struct {
@@ -6081,6 +6725,120 @@ func (c ClientRequestRepoCloneFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// ClientRequestRepoMigrateFunc describes the behavior when the
+// RequestRepoMigrate method of the parent MockClient instance is invoked.
+type ClientRequestRepoMigrateFunc struct {
+	defaultHook func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error)
+	hooks       []func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error)
+	history     []ClientRequestRepoMigrateFuncCall
+	mutex       sync.Mutex
+}
+
+// RequestRepoMigrate delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockClient) RequestRepoMigrate(v0 context.Context, v1 api.RepoName, v2 string, v3 string) (*protocol.RepoUpdateResponse, error) {
+	r0, r1 := m.RequestRepoMigrateFunc.nextHook()(v0, v1, v2, v3)
+	m.RequestRepoMigrateFunc.appendCall(ClientRequestRepoMigrateFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the RequestRepoMigrate
+// method of the parent MockClient instance is invoked and the hook queue is
+// empty.
+func (f *ClientRequestRepoMigrateFunc) SetDefaultHook(hook func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RequestRepoMigrate method of the parent MockClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *ClientRequestRepoMigrateFunc) PushHook(hook func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ClientRequestRepoMigrateFunc) SetDefaultReturn(r0 *protocol.RepoUpdateResponse, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ClientRequestRepoMigrateFunc) PushReturn(r0 *protocol.RepoUpdateResponse, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientRequestRepoMigrateFunc) nextHook() func(context.Context, api.RepoName, string, string) (*protocol.RepoUpdateResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientRequestRepoMigrateFunc) appendCall(r0 ClientRequestRepoMigrateFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientRequestRepoMigrateFuncCall objects
+// describing the invocations of this function.
+func (f *ClientRequestRepoMigrateFunc) History() []ClientRequestRepoMigrateFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientRequestRepoMigrateFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientRequestRepoMigrateFuncCall is an object that describes an
+// invocation of method RequestRepoMigrate on an instance of MockClient.
+type ClientRequestRepoMigrateFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *protocol.RepoUpdateResponse
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientRequestRepoMigrateFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientRequestRepoMigrateFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ClientRequestRepoUpdateFunc describes the behavior when the
 // RequestRepoUpdate method of the parent MockClient instance is invoked.
 type ClientRequestRepoUpdateFunc struct {
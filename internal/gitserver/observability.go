@@ -26,12 +26,14 @@ type operations struct {
 	getCommit        *observation.Operation
 	getCommits       *observation.Operation
 	hasCommitAfter   *observation.Operation
+	lfsContent       *observation.Operation
 	listBranches     *observation.Operation
 	listRefs         *observation.Operation
 	listTags         *observation.Operation
 	lstat            *observation.Operation
 	mergeBase        *observation.Operation
 	newFileReader    *observation.Operation
+	objectSignature  *observation.Operation
 	p4Exec           *observation.Operation
 	readDir          *observation.Operation
 	readFile         *observation.Operation
@@ -101,12 +103,14 @@ func newOperations(observationCtx *observation.Context) *operations {
 		getCommit:        op("GetCommit"),
 		getCommits:       op("GetCommits"),
 		hasCommitAfter:   op("HasCommitAfter"),
+		lfsContent:       op("LFSContent"),
 		listBranches:     op("ListBranches"),
 		listRefs:         op("ListRefs"),
 		listTags:         op("ListTags"),
 		lstat:            subOp("lStat"),
 		mergeBase:        op("MergeBase"),
 		newFileReader:    op("NewFileReader"),
+		objectSignature:  op("ObjectSignature"),
 		p4Exec:           op("P4Exec"),
 		readDir:          op("ReadDir"),
 		readFile:         op("ReadFile"),
@@ -75,6 +75,34 @@ type RevisionSpecifier struct {
 	ExcludeRefGlob string
 }
 
+// RevisionArgs returns the arguments selecting the given revisions for git log
+// and git rev-parse. An empty RevisionSpecifier selects HEAD.
+//
+// git only applies an --exclude to the --glob arguments that follow it, so each
+// ref glob is preceded by the exclude globs that come after it in revs. This
+// matches the ordered semantics of gitdomain.CompileRefGlobs.
+func RevisionArgs(revs []RevisionSpecifier) []string {
+	args := make([]string, 0, len(revs))
+	for i, rev := range revs {
+		switch {
+		case rev.RevSpec != "":
+			args = append(args, rev.RevSpec)
+		case rev.RefGlob != "":
+			for _, later := range revs[i+1:] {
+				if later.RevSpec == "" && later.RefGlob == "" && later.ExcludeRefGlob != "" {
+					args = append(args, "--exclude="+later.ExcludeRefGlob)
+				}
+			}
+			args = append(args, "--glob="+rev.RefGlob)
+		case rev.ExcludeRefGlob != "":
+			// Applied to the preceding ref globs above.
+		default:
+			args = append(args, "HEAD")
+		}
+	}
+	return args
+}
+
 func (r *RevisionSpecifier) ToProto() *proto.RevisionSpecifier {
 	return &proto.RevisionSpecifier{
 		RevSpec:        r.RevSpec,
@@ -495,6 +523,34 @@ func (r *RepoCloneResponse) FromProto(p *proto.RepoCloneResponse) {
 	}
 }
 
+// CloneQueueJob is a repository waiting in the clone queue of a gitserver.
+type CloneQueueJob struct {
+	Repo api.RepoName `json:"repo"`
+	// Priority is one of "low", "normal", "high" and "boosted".
+	Priority string `json:"priority"`
+	// Position is the 1-based position of the repository in the clone queue.
+	Position int       `json:"position"`
+	QueuedAt time.Time `json:"queuedAt"`
+}
+
+// CloneQueueResponse lists the repositories waiting in the clone queue of a
+// gitserver, in the order in which they will be cloned.
+type CloneQueueResponse struct {
+	Jobs []CloneQueueJob `json:"jobs"`
+}
+
+// CloneQueueBoostRequest is a request to move a repository to the front of the
+// clone queue.
+type CloneQueueBoostRequest struct {
+	Repo api.RepoName `json:"repo"`
+}
+
+// CloneQueueBoostResponse reports whether the repository was waiting in the
+// clone queue and has been moved to its front.
+type CloneQueueBoostResponse struct {
+	Boosted bool `json:"boosted"`
+}
+
 type NotFoundPayload struct {
 	CloneInProgress bool `json:"cloneInProgress"` // If true, exec returned with noop because clone is in progress.
 
@@ -502,6 +558,17 @@ type NotFoundPayload struct {
 	CloneProgress string `json:"cloneProgress,omitempty"`
 }
 
+// LFSContentRequest is a request for the content of a file stored in Git LFS,
+// which is fetched from the LFS server of the repository's code host.
+type LFSContentRequest struct {
+	// Repo is the repository the pointer was read from.
+	Repo api.RepoName `json:"repo"`
+	// OID is the SHA-256 hash of the content, as found in the pointer.
+	OID string `json:"oid"`
+	// Size is the size of the content in bytes, as found in the pointer.
+	Size int64 `json:"size"`
+}
+
 // IsRepoCloneableRequest is a request to determine if a repo is cloneable.
 type IsRepoCloneableRequest struct {
 	// Repo is the repository to check.
@@ -0,0 +1,101 @@
+package relocator
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/executor"
+)
+
+const (
+	tableName = "gitserver_relocator_jobs"
+	viewName  = "gitserver_relocator_jobs_with_repo_name"
+)
+
+// Job moves a repository from the gitserver it is cloned onto to another gitserver.
+type Job struct {
+	ID              int
+	State           string
+	FailureMessage  *string
+	QueuedAt        time.Time
+	StartedAt       *time.Time
+	FinishedAt      *time.Time
+	ProcessAfter    *time.Time
+	NumResets       int
+	NumFailures     int
+	LastHeartbeatAt time.Time
+	ExecutionLogs   []executor.ExecutionLogEntry
+	WorkerHostname  string
+	RepoID          api.RepoID
+	RepoName        api.RepoName
+
+	// SourceHostname is the address of the gitserver the repository is moved from.
+	SourceHostname string
+	// DestHostname is the address of the gitserver the repository is moved to.
+	DestHostname string
+	// DeleteSource is true if the clone on the source gitserver is deleted once the
+	// repository has been moved.
+	DeleteSource bool
+}
+
+func (j *Job) RecordID() int {
+	return j.ID
+}
+
+func (j *Job) RecordUID() string {
+	return strconv.Itoa(j.ID)
+}
+
+var jobColumns = []*sqlf.Query{
+	sqlf.Sprintf("id"),
+	sqlf.Sprintf("state"),
+	sqlf.Sprintf("failure_message"),
+	sqlf.Sprintf("queued_at"),
+	sqlf.Sprintf("started_at"),
+	sqlf.Sprintf("finished_at"),
+	sqlf.Sprintf("process_after"),
+	sqlf.Sprintf("num_resets"),
+	sqlf.Sprintf("num_failures"),
+	sqlf.Sprintf("last_heartbeat_at"),
+	sqlf.Sprintf("execution_logs"),
+	sqlf.Sprintf("worker_hostname"),
+	sqlf.Sprintf("repo_id"),
+	sqlf.Sprintf("repo_name"),
+	sqlf.Sprintf("source_hostname"),
+	sqlf.Sprintf("dest_hostname"),
+	sqlf.Sprintf("delete_source"),
+}
+
+func scanJob(s dbutil.Scanner) (*Job, error) {
+	var job Job
+	var executionLogs []executor.ExecutionLogEntry
+
+	if err := s.Scan(
+		&job.ID,
+		&job.State,
+		&job.FailureMessage,
+		&job.QueuedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+		&job.ProcessAfter,
+		&job.NumResets,
+		&job.NumFailures,
+		&dbutil.NullTime{Time: &job.LastHeartbeatAt},
+		pq.Array(&executionLogs),
+		&job.WorkerHostname,
+		&job.RepoID,
+		&job.RepoName,
+		&job.SourceHostname,
+		&job.DestHostname,
+		&job.DeleteSource,
+	); err != nil {
+		return nil, err
+	}
+	job.ExecutionLogs = append(job.ExecutionLogs, executionLogs...)
+	return &job, nil
+}
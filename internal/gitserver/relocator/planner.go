@@ -0,0 +1,111 @@
+package relocator
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+// RepoSize is the size of a cloned repository.
+type RepoSize struct {
+	ID        api.RepoID
+	Name      api.RepoName
+	SizeBytes int64
+}
+
+// Move is a planned move of a repository between gitservers.
+type Move struct {
+	Repo           RepoSize
+	SourceHostname string
+	DestHostname   string
+}
+
+// balancedRatio is the difference between the disk usage of the most and least used
+// gitservers, relative to the mean disk usage, below which gitservers are considered
+// balanced.
+const balancedRatio = 0.05
+
+// Plan returns at most maxMoves moves of the given repositories that even out the disk
+// usage of the gitservers in addrs. The disk usage of a gitserver is the total size of
+// the repositories assigned to it.
+//
+// Moves are planned greedily: each move takes the repository from the most used
+// gitserver to the least used one whose size is closest to half the difference of
+// their usage, until gitservers are balanced or no move lowers the difference.
+func Plan(addrs gitserver.GitserverAddresses, repos []RepoSize, maxMoves int) []Move {
+	if len(addrs.Addresses) < 2 {
+		return nil
+	}
+
+	usage := make(map[string]int64, len(addrs.Addresses))
+	assigned := make(map[string][]RepoSize, len(addrs.Addresses))
+	for _, addr := range addrs.Addresses {
+		usage[addr] = 0
+	}
+	var total int64
+	for _, repo := range repos {
+		addr := addrs.AddrForRepo("relocator", repo.Name)
+		if _, ok := usage[addr]; !ok {
+			// Pinned to a gitserver that is no longer in use
+			continue
+		}
+		usage[addr] += repo.SizeBytes
+		assigned[addr] = append(assigned[addr], repo)
+		total += repo.SizeBytes
+	}
+	mean := float64(total) / float64(len(addrs.Addresses))
+
+	var moves []Move
+	for len(moves) < maxMoves {
+		most, least := extremes(addrs.Addresses, usage)
+		diff := usage[most] - usage[least]
+		if float64(diff) <= mean*balancedRatio {
+			break
+		}
+
+		// Any repository smaller than diff lowers the difference, the one closest to
+		// half of it lowers it most.
+		best := -1
+		for i, repo := range assigned[most] {
+			if repo.SizeBytes <= 0 || repo.SizeBytes >= diff {
+				continue
+			}
+			if best < 0 || abs(diff-2*repo.SizeBytes) < abs(diff-2*assigned[most][best].SizeBytes) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		// Moved repositories are not planned to be moved again
+		repo := assigned[most][best]
+		assigned[most] = append(assigned[most][:best], assigned[most][best+1:]...)
+		usage[most] -= repo.SizeBytes
+		usage[least] += repo.SizeBytes
+		moves = append(moves, Move{Repo: repo, SourceHostname: most, DestHostname: least})
+	}
+
+	return moves
+}
+
+// extremes returns the most and least used of the given gitservers. Ties are broken by
+// the order of addrs, so that plans are deterministic.
+func extremes(addrs []string, usage map[string]int64) (most, least string) {
+	most, least = addrs[0], addrs[0]
+	for _, addr := range addrs[1:] {
+		if usage[addr] > usage[most] {
+			most = addr
+		}
+		if usage[addr] < usage[least] {
+			least = addr
+		}
+	}
+	return most, least
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,88 @@
+package relocator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+func TestPlan(t *testing.T) {
+	addrs := gitserver.GitserverAddresses{Addresses: []string{"gitserver-0", "gitserver-1", "gitserver-2"}}
+
+	// Pin repositories so that their gitserver is known
+	var repos []RepoSize
+	pin := func(addr string, sizes ...int64) {
+		for _, size := range sizes {
+			name := api.RepoName(fmt.Sprintf("github.com/sourcegraph/repo-%d", len(repos)))
+			repos = append(repos, RepoSize{ID: api.RepoID(len(repos) + 1), Name: name, SizeBytes: size})
+			if addrs.PinnedServers == nil {
+				addrs.PinnedServers = map[string]string{}
+			}
+			addrs.PinnedServers[string(name)] = addr
+		}
+	}
+
+	t.Run("balanced", func(t *testing.T) {
+		repos, addrs.PinnedServers = nil, nil
+		pin("gitserver-0", 100)
+		pin("gitserver-1", 100)
+		pin("gitserver-2", 98)
+
+		assert.Empty(t, Plan(addrs, repos, 10))
+	})
+
+	t.Run("new gitserver", func(t *testing.T) {
+		repos, addrs.PinnedServers = nil, nil
+		pin("gitserver-0", 100, 50)
+		pin("gitserver-1", 100, 50)
+
+		moves := Plan(addrs, repos, 10)
+		assert.Len(t, moves, 2)
+		usage := usageAfter(addrs, repos, moves)
+		assert.Equal(t, map[string]int64{"gitserver-0": 100, "gitserver-1": 100, "gitserver-2": 100}, usage)
+	})
+
+	t.Run("max moves", func(t *testing.T) {
+		repos, addrs.PinnedServers = nil, nil
+		pin("gitserver-0", 10, 10, 10, 10, 10, 10)
+
+		assert.Len(t, Plan(addrs, repos, 2), 2)
+		assert.Len(t, Plan(addrs, repos, 10), 4)
+	})
+
+	t.Run("repositories too large to move", func(t *testing.T) {
+		repos, addrs.PinnedServers = nil, nil
+		pin("gitserver-0", 300)
+		pin("gitserver-1", 10)
+
+		assert.Empty(t, Plan(addrs, repos, 10))
+	})
+
+	t.Run("single gitserver", func(t *testing.T) {
+		repos, addrs.PinnedServers = nil, nil
+		pin("gitserver-0", 10, 20)
+
+		assert.Empty(t, Plan(gitserver.GitserverAddresses{Addresses: []string{"gitserver-0"}}, repos, 10))
+	})
+}
+
+func usageAfter(addrs gitserver.GitserverAddresses, repos []RepoSize, moves []Move) map[string]int64 {
+	moved := map[api.RepoID]string{}
+	for _, move := range moves {
+		moved[move.Repo.ID] = move.DestHostname
+	}
+
+	usage := map[string]int64{}
+	for _, repo := range repos {
+		addr := addrs.AddrForRepo("test", repo.Name)
+		if dest, ok := moved[repo.ID]; ok {
+			addr = dest
+		}
+		usage[addr] += repo.SizeBytes
+	}
+	return usage
+}
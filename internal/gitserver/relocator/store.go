@@ -0,0 +1,143 @@
+package relocator
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ErrMoveInProgress is returned when enqueueing the move of a repository that is
+// already being moved.
+var ErrMoveInProgress = errors.New("repository is already being moved")
+
+// Store enqueues and lists the moves of repositories between gitservers.
+type Store interface {
+	// Enqueue enqueues the move of the given repository from the gitserver source to
+	// the gitserver dest. ErrMoveInProgress is returned if the repository is already
+	// being moved.
+	Enqueue(ctx context.Context, repoID api.RepoID, source, dest string, deleteSource bool) (*Job, error)
+
+	// List returns the most recently enqueued moves.
+	List(ctx context.Context, opts ListOptions) ([]*Job, error)
+
+	// ListRepoSizes returns the size of all cloned repositories that are not being
+	// moved, as used by Plan.
+	ListRepoSizes(ctx context.Context) ([]RepoSize, error)
+}
+
+// ListOptions filters the moves returned by Store.List.
+type ListOptions struct {
+	// State, if set, only returns moves in the given state.
+	State string
+	// Limit, if positive, is the maximum number of moves to return.
+	Limit int
+}
+
+type store struct {
+	*basestore.Store
+}
+
+// NewStore returns a Store backed by the given database.
+func NewStore(db database.DB) Store {
+	return &store{Store: basestore.NewWithHandle(db.Handle())}
+}
+
+// activeStates are the states of moves that have not yet completed.
+var activeStates = []*sqlf.Query{
+	sqlf.Sprintf("'queued'"),
+	sqlf.Sprintf("'processing'"),
+	sqlf.Sprintf("'errored'"),
+}
+
+const enqueueFmtstr = `
+INSERT INTO gitserver_relocator_jobs (repo_id, source_hostname, dest_hostname, delete_source)
+SELECT %s, %s, %s, %s
+WHERE NOT EXISTS (
+	SELECT 1 FROM gitserver_relocator_jobs
+	WHERE repo_id = %s AND state IN (%s)
+)
+RETURNING id
+`
+
+func (s *store) Enqueue(ctx context.Context, repoID api.RepoID, source, dest string, deleteSource bool) (*Job, error) {
+	if source == dest {
+		return nil, errors.Newf("repository is already cloned onto %s", dest)
+	}
+
+	id, ok, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(
+		enqueueFmtstr,
+		repoID, source, dest, deleteSource,
+		repoID, sqlf.Join(activeStates, ","),
+	)))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrMoveInProgress
+	}
+
+	jobs, err := s.list(ctx, sqlf.Sprintf("id = %s", id), 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, errors.Newf("repository %d not found", repoID)
+	}
+	return jobs[0], nil
+}
+
+func (s *store) List(ctx context.Context, opts ListOptions) ([]*Job, error) {
+	cond := sqlf.Sprintf("TRUE")
+	if opts.State != "" {
+		cond = sqlf.Sprintf("state = %s", opts.State)
+	}
+	return s.list(ctx, cond, opts.Limit)
+}
+
+const listFmtstr = `
+SELECT %s
+FROM gitserver_relocator_jobs_with_repo_name
+WHERE %s
+ORDER BY id DESC
+%s
+`
+
+func (s *store) list(ctx context.Context, cond *sqlf.Query, limit int) ([]*Job, error) {
+	limitClause := sqlf.Sprintf("")
+	if limit > 0 {
+		limitClause = sqlf.Sprintf("LIMIT %s", limit)
+	}
+	return basestore.NewSliceScanner(scanJob)(s.Query(ctx, sqlf.Sprintf(
+		listFmtstr,
+		sqlf.Join(jobColumns, ","),
+		cond,
+		limitClause,
+	)))
+}
+
+const listRepoSizesFmtstr = `
+SELECT r.id, r.name, gr.repo_size_bytes
+FROM repo r
+JOIN gitserver_repos gr ON gr.repo_id = r.id
+WHERE
+	r.deleted_at IS NULL AND
+	gr.clone_status = 'cloned' AND
+	gr.repo_size_bytes IS NOT NULL AND
+	NOT EXISTS (
+		SELECT 1 FROM gitserver_relocator_jobs j
+		WHERE j.repo_id = r.id AND j.state IN (%s)
+	)
+`
+
+func (s *store) ListRepoSizes(ctx context.Context) ([]RepoSize, error) {
+	return basestore.NewSliceScanner(func(sc dbutil.Scanner) (r RepoSize, err error) {
+		err = sc.Scan(&r.ID, &r.Name, &r.SizeBytes)
+		return r, err
+	})(s.Query(ctx, sqlf.Sprintf(listRepoSizesFmtstr, sqlf.Join(activeStates, ","))))
+}
@@ -0,0 +1,82 @@
+package relocator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+
+	var repos []*types.Repo
+	for i, name := range []api.RepoName{"github.com/sourcegraph/a", "github.com/sourcegraph/b", "github.com/sourcegraph/c"} {
+		repo := &types.Repo{Name: name}
+		require.NoError(t, db.Repos().Create(ctx, repo))
+		if i < 2 {
+			require.NoError(t, db.GitserverRepos().SetCloneStatus(ctx, name, types.CloneStatusCloned, "gitserver-0"))
+			require.NoError(t, db.GitserverRepos().SetRepoSize(ctx, name, int64(100*(i+1)), "gitserver-0"))
+		}
+		repos = append(repos, repo)
+	}
+
+	store := NewStore(db)
+
+	sizes, err := store.ListRepoSizes(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []RepoSize{
+		{ID: repos[0].ID, Name: repos[0].Name, SizeBytes: 100},
+		{ID: repos[1].ID, Name: repos[1].Name, SizeBytes: 200},
+	}, sizes)
+
+	job, err := store.Enqueue(ctx, repos[0].ID, "gitserver-0", "gitserver-1", true)
+	require.NoError(t, err)
+	assert.Equal(t, "queued", job.State)
+	assert.Equal(t, repos[0].Name, job.RepoName)
+	assert.Equal(t, "gitserver-0", job.SourceHostname)
+	assert.Equal(t, "gitserver-1", job.DestHostname)
+	assert.True(t, job.DeleteSource)
+
+	// Repositories are moved one at a time
+	_, err = store.Enqueue(ctx, repos[0].ID, "gitserver-0", "gitserver-2", true)
+	assert.ErrorIs(t, err, ErrMoveInProgress)
+
+	_, err = store.Enqueue(ctx, repos[1].ID, "gitserver-0", "gitserver-0", true)
+	assert.Error(t, err)
+
+	// Repositories being moved are not planned to be moved again
+	sizes, err = store.ListRepoSizes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []RepoSize{{ID: repos[1].ID, Name: repos[1].Name, SizeBytes: 200}}, sizes)
+
+	_, err = store.Enqueue(ctx, repos[1].ID, "gitserver-0", "gitserver-1", false)
+	require.NoError(t, err)
+
+	jobs, err := store.List(ctx, ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, repos[1].ID, jobs[0].RepoID)
+	assert.Equal(t, repos[0].ID, jobs[1].RepoID)
+
+	jobs, err = store.List(ctx, ListOptions{Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+
+	jobs, err = store.List(ctx, ListOptions{State: "completed"})
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+}
@@ -0,0 +1,222 @@
+package relocator
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var (
+	concurrency        = env.MustGetInt("SRC_GITSERVER_RELOCATOR_CONCURRENCY", 2, "The maximum number of repositories moved between gitservers at the same time.")
+	routingGracePeriod = env.MustGetDuration("SRC_GITSERVER_RELOCATOR_GRACE_PERIOD", time.Minute, "The time to wait after a moved repository has been routed to its new gitserver before deleting it from the old one, so that all services route to the new gitserver.")
+)
+
+// routingTimeout is the maximum time to wait for the site configuration routing a moved
+// repository to its new gitserver to be picked up.
+const routingTimeout = 5 * time.Minute
+
+// NewWorker returns the routines moving repositories between gitservers: a worker
+// processing the moves enqueued through the Store and its resetter.
+func NewWorker(ctx context.Context, observationCtx *observation.Context, db database.DB, client gitserver.Client) []goroutine.BackgroundRoutine {
+	workerStore := dbworkerstore.New(observationCtx, db.Handle(), dbworkerstore.Options[*Job]{
+		Name:              "gitserver_relocator_worker_store",
+		TableName:         tableName,
+		ViewName:          viewName,
+		ColumnExpressions: jobColumns,
+		Scan:              dbworkerstore.BuildWorkerScan(scanJob),
+		OrderByExpression: sqlf.Sprintf("id"),
+		MaxNumResets:      5,
+		StalledMaxAge:     time.Minute,
+		RetryAfter:        time.Minute,
+		MaxNumRetries:     5,
+	})
+
+	h := &handler{
+		db:     db,
+		client: client,
+	}
+
+	worker := dbworker.NewWorker[*Job](ctx, workerStore, h, workerutil.WorkerOptions{
+		Name:              "gitserver_relocator_worker",
+		Description:       "moves repositories between gitservers",
+		NumHandlers:       concurrency,
+		Interval:          10 * time.Second,
+		HeartbeatInterval: 15 * time.Second,
+		Metrics:           workerutil.NewMetrics(observationCtx, "gitserver_relocator_worker"),
+	})
+
+	resetter := dbworker.NewResetter(observationCtx.Logger.Scoped("resetter", ""), workerStore, dbworker.ResetterOptions{
+		Name:     "gitserver_relocator_worker_resetter",
+		Interval: time.Minute,
+		Metrics:  dbworker.NewResetterMetrics(observationCtx, "gitserver_relocator_worker"),
+	})
+
+	return []goroutine.BackgroundRoutine{worker, resetter}
+}
+
+type handler struct {
+	db     database.DB
+	client gitserver.Client
+}
+
+var _ workerutil.Handler[*Job] = &handler{}
+
+// Handle moves a repository in four steps, none of which makes it unavailable:
+//
+//  1. The repository is cloned onto the destination gitserver from the source one.
+//  2. The refs of both clones are compared.
+//  3. The repository is routed to the destination gitserver through the
+//     experimentalFeatures.gitServerPinnedRepos site configuration.
+//  4. Once all services route to the destination gitserver, the source clone is deleted.
+//
+// Each step can be retried. Once the repository has been routed to the destination
+// gitserver, retries skip the first three steps.
+func (h *handler) Handle(ctx context.Context, logger log.Logger, job *Job) error {
+	repo := protocol.NormalizeRepo(job.RepoName)
+	logger = logger.With(
+		log.String("repo", string(repo)),
+		log.String("source", job.SourceHostname),
+		log.String("dest", job.DestHostname),
+	)
+
+	if job.SourceHostname == job.DestHostname {
+		return errcode.MakeNonRetryable(errors.New("source and destination gitservers are the same"))
+	}
+
+	if current := h.client.AddrForRepo(repo); current != job.DestHostname {
+		if current != job.SourceHostname {
+			return errcode.MakeNonRetryable(errors.Newf("repository is assigned to gitserver %s, not %s", current, job.SourceHostname))
+		}
+
+		logger.Info("copying repository")
+		resp, err := h.client.RequestRepoMigrate(ctx, repo, job.SourceHostname, job.DestHostname)
+		if err != nil {
+			return errors.Wrap(err, "copy repository")
+		}
+		if resp.Error != "" {
+			return errors.Newf("copy repository: %s", resp.Error)
+		}
+
+		if err := h.verify(ctx, repo, job.SourceHostname, job.DestHostname); err != nil {
+			return err
+		}
+
+		logger.Info("routing repository to destination")
+		if err := routeRepo(ctx, h.db, repo, job.DestHostname); err != nil {
+			return errors.Wrap(err, "route repository")
+		}
+		if err := h.awaitRouting(ctx, repo, job.DestHostname); err != nil {
+			return err
+		}
+	}
+
+	// The source may have fetched changes since the copy was made
+	if resp, err := h.client.RequestRepoUpdate(ctx, repo, 0); err != nil {
+		return errors.Wrap(err, "update repository")
+	} else if resp.Error != "" {
+		logger.Warn("failed to update moved repository", log.String("error", resp.Error))
+	}
+
+	if !job.DeleteSource {
+		return nil
+	}
+
+	// Services other than the worker may not have picked up the routing yet
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(routingGracePeriod):
+	}
+
+	logger.Info("deleting repository from source")
+	return errors.Wrap(h.client.RemoveFrom(ctx, repo, job.SourceHostname), "delete repository from source")
+}
+
+// verify returns an error if any ref of the clone of the repository on the gitserver
+// source is missing from the clone on the gitserver dest, or points to another commit.
+func (h *handler) verify(ctx context.Context, repo api.RepoName, source, dest string) error {
+	sourceRefs, err := h.client.ListRefsFrom(ctx, repo, source)
+	if err != nil {
+		return errors.Wrap(err, "list source refs")
+	}
+	destRefs, err := h.client.ListRefsFrom(ctx, repo, dest)
+	if err != nil {
+		return errors.Wrap(err, "list destination refs")
+	}
+
+	commits := make(map[string]api.CommitID, len(destRefs))
+	for _, ref := range destRefs {
+		commits[ref.Name] = ref.CommitID
+	}
+	for _, ref := range sourceRefs {
+		if commits[ref.Name] != ref.CommitID {
+			return errors.Newf("destination clone differs from source clone: ref %s is at %q instead of %q", ref.Name, commits[ref.Name], ref.CommitID)
+		}
+	}
+	return nil
+}
+
+// awaitRouting waits until the gitserver client routes the repository to the given
+// gitserver.
+func (h *handler) awaitRouting(ctx context.Context, repo api.RepoName, addr string) error {
+	ctx, cancel := context.WithTimeout(ctx, routingTimeout)
+	defer cancel()
+
+	for h.client.AddrForRepo(repo) != addr {
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "waiting for the site configuration to route the repository")
+		case <-time.After(time.Second):
+		}
+	}
+	return nil
+}
+
+// routeRepo edits the site configuration so that the repository is routed to the
+// given gitserver. The repository is pinned to the gitserver, unless it is the
+// gitserver the repository is assigned to without pinning, in which case any pin is
+// removed.
+func routeRepo(ctx context.Context, db database.DB, repo api.RepoName, addr string) error {
+	latest, err := db.Conf().SiteGetLatest(ctx)
+	if err != nil {
+		return err
+	}
+
+	path := []string{"experimentalFeatures", "gitServerPinnedRepos", string(repo)}
+	unpinned := gitserver.GitserverAddresses{Addresses: conf.Get().ServiceConnectionConfig.GitServers}
+	var contents string
+	if len(unpinned.Addresses) > 0 && unpinned.AddrForRepo("relocator", repo) == addr {
+		if _, err := jsonc.ReadProperty(latest.Contents, path...); err != nil {
+			// Not pinned
+			return nil
+		}
+		contents, err = jsonc.Remove(latest.Contents, path...)
+	} else {
+		contents, err = jsonc.Edit(latest.Contents, addr, path...)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Fails if the site configuration was edited concurrently, in which case the job
+	// is retried.
+	_, err = db.Conf().SiteCreateIfUpToDate(ctx, &latest.ID, 0, contents, false)
+	return err
+}
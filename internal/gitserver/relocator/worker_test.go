@@ -0,0 +1,165 @@
+package relocator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	gitserver0 = "gitserver-0:3178"
+	gitserver1 = "gitserver-1:3178"
+)
+
+func TestHandler_Handle(t *testing.T) {
+	mockGitservers(t)
+	old := routingGracePeriod
+	routingGracePeriod = 0
+	t.Cleanup(func() { routingGracePeriod = old })
+
+	ctx := context.Background()
+	repo := api.RepoName("github.com/sourcegraph/sourcegraph")
+	unpinned := gitserver.GitserverAddresses{Addresses: []string{gitserver0, gitserver1}}
+	source := unpinned.AddrForRepo("test", repo)
+	dest := gitserver0
+	if source == gitserver0 {
+		dest = gitserver1
+	}
+	job := &Job{RepoName: repo, SourceHostname: source, DestHostname: dest, DeleteSource: true}
+
+	setup := func(destRefs []gitdomain.Ref) (*handler, *gitserver.MockClient, *database.MockConfStore) {
+		site := &database.SiteConfig{ID: 1, Contents: `{}`}
+		confStore := database.NewMockConfStore()
+		confStore.SiteGetLatestFunc.SetDefaultHook(func(context.Context) (*database.SiteConfig, error) {
+			return site, nil
+		})
+		confStore.SiteCreateIfUpToDateFunc.SetDefaultHook(func(_ context.Context, lastID *int32, _ int32, contents string, _ bool) (*database.SiteConfig, error) {
+			site = &database.SiteConfig{ID: *lastID + 1, Contents: contents}
+			return site, nil
+		})
+		db := database.NewMockDB()
+		db.ConfFunc.SetDefaultReturn(confStore)
+
+		client := gitserver.NewMockClient()
+		client.AddrForRepoFunc.SetDefaultHook(func(repo api.RepoName) string {
+			pinned, _ := jsonc.ReadProperty(site.Contents, "experimentalFeatures", "gitServerPinnedRepos", string(repo))
+			if addr, ok := pinned.(string); ok {
+				return addr
+			}
+			return source
+		})
+		client.RequestRepoMigrateFunc.SetDefaultReturn(&protocol.RepoUpdateResponse{}, nil)
+		client.RequestRepoUpdateFunc.SetDefaultReturn(&protocol.RepoUpdateResponse{}, nil)
+		client.ListRefsFromFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, from string) ([]gitdomain.Ref, error) {
+			if from == dest {
+				return destRefs, nil
+			}
+			return []gitdomain.Ref{{Name: "refs/heads/main", CommitID: "deadbeef"}}, nil
+		})
+
+		return &handler{db: db, client: client}, client, confStore
+	}
+
+	t.Run("success", func(t *testing.T) {
+		h, client, confStore := setup([]gitdomain.Ref{{Name: "refs/heads/main", CommitID: "deadbeef"}})
+		require.NoError(t, h.Handle(ctx, logtest.Scoped(t), job))
+
+		require.Len(t, client.RequestRepoMigrateFunc.History(), 1)
+		assert.Equal(t, source, client.RequestRepoMigrateFunc.History()[0].Arg2)
+		assert.Equal(t, dest, client.RequestRepoMigrateFunc.History()[0].Arg3)
+		require.Len(t, confStore.SiteCreateIfUpToDateFunc.History(), 1)
+		require.Len(t, client.RemoveFromFunc.History(), 1)
+		assert.Equal(t, source, client.RemoveFromFunc.History()[0].Arg2)
+		assert.Equal(t, dest, h.client.AddrForRepo(repo))
+
+		// Retries of moved repositories only delete the source
+		require.NoError(t, h.Handle(ctx, logtest.Scoped(t), job))
+		assert.Len(t, client.RequestRepoMigrateFunc.History(), 1)
+		assert.Len(t, confStore.SiteCreateIfUpToDateFunc.History(), 1)
+		assert.Len(t, client.RemoveFromFunc.History(), 2)
+	})
+
+	t.Run("verification failure", func(t *testing.T) {
+		h, client, confStore := setup([]gitdomain.Ref{{Name: "refs/heads/main", CommitID: "cafebabe"}})
+		require.Error(t, h.Handle(ctx, logtest.Scoped(t), job))
+
+		assert.Empty(t, confStore.SiteCreateIfUpToDateFunc.History())
+		assert.Empty(t, client.RemoveFromFunc.History())
+		assert.Equal(t, source, h.client.AddrForRepo(repo))
+	})
+
+	t.Run("copy failure", func(t *testing.T) {
+		h, client, confStore := setup(nil)
+		client.RequestRepoMigrateFunc.SetDefaultReturn(nil, errors.New("boom"))
+		require.Error(t, h.Handle(ctx, logtest.Scoped(t), job))
+
+		assert.Empty(t, client.ListRefsFromFunc.History())
+		assert.Empty(t, confStore.SiteCreateIfUpToDateFunc.History())
+	})
+
+	t.Run("unexpected source", func(t *testing.T) {
+		h, client, _ := setup(nil)
+		err := h.Handle(ctx, logtest.Scoped(t), &Job{RepoName: repo, SourceHostname: "gitserver-2:3178", DestHostname: dest})
+		require.Error(t, err)
+		assert.Empty(t, client.RequestRepoMigrateFunc.History())
+	})
+}
+
+func TestRouteRepo(t *testing.T) {
+	mockGitservers(t)
+
+	ctx := context.Background()
+	repo := api.RepoName("github.com/sourcegraph/sourcegraph")
+	unpinned := gitserver.GitserverAddresses{Addresses: []string{gitserver0, gitserver1}}.AddrForRepo("test", repo)
+	other := gitserver0
+	if unpinned == gitserver0 {
+		other = gitserver1
+	}
+
+	site := &database.SiteConfig{ID: 1, Contents: `{"experimentalFeatures": {}}`}
+	confStore := database.NewMockConfStore()
+	confStore.SiteGetLatestFunc.SetDefaultHook(func(context.Context) (*database.SiteConfig, error) {
+		return site, nil
+	})
+	confStore.SiteCreateIfUpToDateFunc.SetDefaultHook(func(_ context.Context, lastID *int32, _ int32, contents string, _ bool) (*database.SiteConfig, error) {
+		site = &database.SiteConfig{ID: *lastID + 1, Contents: contents}
+		return site, nil
+	})
+	db := database.NewMockDB()
+	db.ConfFunc.SetDefaultReturn(confStore)
+
+	pinned := func() any {
+		v, _ := jsonc.ReadProperty(site.Contents, "experimentalFeatures", "gitServerPinnedRepos", string(repo))
+		return v
+	}
+
+	// Repositories moved away from their gitserver are pinned
+	require.NoError(t, routeRepo(ctx, db, repo, other))
+	assert.Equal(t, other, pinned())
+
+	// Repositories moved back to their gitserver are unpinned
+	require.NoError(t, routeRepo(ctx, db, repo, unpinned))
+	assert.Nil(t, pinned())
+}
+
+func mockGitservers(t *testing.T) {
+	conf.Mock(&conf.Unified{
+		ServiceConnectionConfig: conftypes.ServiceConnections{
+			GitServers: []string{gitserver0, gitserver1},
+		},
+	})
+	t.Cleanup(func() { conf.Mock(nil) })
+}
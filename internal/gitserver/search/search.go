@@ -264,19 +264,7 @@ func (cs *CommitSearcher) runJobs(ctx context.Context, jobs chan job) error {
 }
 
 func revsToGitArgs(revs []protocol.RevisionSpecifier) []string {
-	revArgs := make([]string, 0, len(revs))
-	for _, rev := range revs {
-		if rev.RevSpec != "" {
-			revArgs = append(revArgs, rev.RevSpec)
-		} else if rev.RefGlob != "" {
-			revArgs = append(revArgs, "--glob="+rev.RefGlob)
-		} else if rev.ExcludeRefGlob != "" {
-			revArgs = append(revArgs, "--exclude="+rev.ExcludeRefGlob)
-		} else {
-			revArgs = append(revArgs, "HEAD")
-		}
-	}
-	return revArgs
+	return protocol.RevisionArgs(revs)
 }
 
 // RawCommit is a shallow parse of the output of git log
@@ -716,6 +716,42 @@ func (authorNameGenerator) Generate(rand *rand.Rand, size int) reflect.Value {
 	return reflect.ValueOf(buf)
 }
 
+func TestSearchRefGlobs(t *testing.T) {
+	const commit = "GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_AUTHOR_NAME=a GIT_AUTHOR_EMAIL=a@a.com git commit --allow-empty -m "
+	dir := initGitRepository(t,
+		commit+"base",
+		"git checkout -b feature",
+		commit+"feature",
+		"git checkout -b cc/skip",
+		commit+"skip",
+	)
+
+	tree, err := ToMatchTree(protocol.NewAnd())
+	require.NoError(t, err)
+	searcher := &CommitSearcher{
+		RepoDir: dir,
+		Query:   tree,
+		Revisions: []protocol.RevisionSpecifier{
+			{RefGlob: "refs/heads/*"},
+			{ExcludeRefGlob: "refs/heads/cc/*"},
+		},
+	}
+	var matches []*protocol.CommitMatch
+	err = searcher.Search(context.Background(), func(match *protocol.CommitMatch) {
+		matches = append(matches, match)
+	})
+	require.NoError(t, err)
+
+	// Commits reachable from several refs are only returned once, attributed
+	// to one of the refs they are reachable from.
+	require.Len(t, matches, 2)
+	require.Equal(t, "feature", matches[0].Message.Content)
+	require.Equal(t, []string{"refs/heads/feature"}, matches[0].SourceRefs)
+	require.Equal(t, "base", matches[1].Message.Content)
+	require.Len(t, matches[1].SourceRefs, 1)
+	require.NotEqual(t, "refs/heads/cc/skip", matches[1].SourceRefs[0])
+}
+
 func Test_revsToGitArgs(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -745,8 +781,25 @@ func Test_revsToGitArgs(t *testing.T) {
 			ExcludeRefGlob: "refs/heads/cc/*",
 		}},
 		expected: []string{
+			"--exclude=refs/heads/cc/*",
 			"--glob=refs/heads/*",
+		},
+	}, {
+		name: "exclude only applies to preceding globs",
+		revSpecs: []protocol.RevisionSpecifier{{
+			RefGlob: "refs/heads/*",
+		}, {
+			ExcludeRefGlob: "refs/heads/cc/*",
+		}, {
+			RefGlob: "refs/heads/cc/keep/*",
+		}, {
+			RevSpec: "v1.0",
+		}},
+		expected: []string{
 			"--exclude=refs/heads/cc/*",
+			"--glob=refs/heads/*",
+			"--glob=refs/heads/cc/keep/*",
+			"v1.0",
 		},
 	}}
 
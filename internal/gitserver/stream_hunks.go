@@ -3,6 +3,7 @@ package gitserver
 import (
 	"bufio"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -104,6 +105,25 @@ func (br *blameHunkReader) Close() error {
 	return br.rc.Close()
 }
 
+// ReadAllHunks reads all the hunks from r, and returns them ordered by line. It does
+// not close r.
+func ReadAllHunks(r HunkReader) ([]*Hunk, error) {
+	var hunks []*Hunk
+	for {
+		h, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		hunks = append(hunks, h)
+	}
+	// Incremental blame returns hunks in the order their commits are found, rather
+	// than in the order of the file.
+	sort.Slice(hunks, func(i, j int) bool { return hunks[i].StartLine < hunks[j].StartLine })
+	return hunks, nil
+}
+
 // parseEntry turns a `67b7b725a7ff913da520b997d71c840230351e30 10 20 1` line from
 // git blame into a hunk.
 func parseEntry(rev string, content string) (*Hunk, error) {
@@ -378,6 +378,13 @@ func (r *PeriodicGoroutine) getNextInterval(tryReinvoke bool) time.Duration {
 }
 
 func (r *PeriodicGoroutine) runHandler(ctx context.Context) error {
+	if r.recorder != nil && r.recorder.Paused(r) {
+		// A site admin paused or drained this routine: skip runs until it is resumed,
+		// but keep reporting that it is alive so that it is not reported as stuck
+		go r.recorder.LogHeartbeat(r)
+		return nil
+	}
+
 	return r.withOperation(ctx, func(ctx context.Context) error {
 		return r.withRecorder(ctx, r.handler.Handle)
 	})
@@ -403,7 +410,9 @@ func (r *PeriodicGoroutine) withRecorder(ctx context.Context, f func(ctx context
 	}
 
 	start := time.Now()
-	err := f(ctx)
+	go r.recorder.LogRunStart(r, start)
+
+	err := f(recorder.WithHeartbeat(ctx, r.recorder, r))
 	duration := time.Since(start)
 
 	go func() {
@@ -24,6 +24,7 @@ type RoutineInfo struct {
 	Instances   []RoutineInstanceInfo
 	RecentRuns  []RoutineRun
 	Stats       RoutineRunStats
+	Control     *RoutineControl // nil if the routine runs normally
 }
 
 // serializableRoutineInfo represents a single routine in a job, and is used for serialization in Redis.
@@ -38,11 +39,79 @@ type serializableRoutineInfo struct {
 // RoutineInstanceInfo contains information about a routine instance.
 // That is, a single version that's running (or ran) on a single node.
 type RoutineInstanceInfo struct {
-	HostName      string     `json:"hostName"`
-	LastStartedAt *time.Time `json:"lastStartedAt"`
-	LastStoppedAt *time.Time `json:"LastStoppedAt"`
+	HostName          string      `json:"hostName"`
+	LastStartedAt     *time.Time  `json:"lastStartedAt"`
+	LastStoppedAt     *time.Time  `json:"LastStoppedAt"`
+	LastHeartbeatAt   *time.Time  `json:"lastHeartbeatAt"`
+	LastRunStartedAt  *time.Time  `json:"lastRunStartedAt"`
+	LastRunFinishedAt *time.Time  `json:"lastRunFinishedAt"`
+	LastError         *RoutineRun `json:"lastError"`
+	InFlight          *int        `json:"inFlight"` // nil if the routine does not report it
 }
 
+// Running returns true if the instance was started and has not stopped since.
+func (i RoutineInstanceInfo) Running() bool {
+	return i.LastStartedAt != nil && (i.LastStoppedAt == nil || i.LastStoppedAt.Before(*i.LastStartedAt))
+}
+
+// CurrentRunStartedAt returns the start time of the run the instance is currently
+// performing, or nil if it is not performing a run.
+func (i RoutineInstanceInfo) CurrentRunStartedAt() *time.Time {
+	if !i.Running() || i.LastRunStartedAt == nil {
+		return nil
+	}
+	if i.LastRunFinishedAt != nil && !i.LastRunFinishedAt.Before(*i.LastRunStartedAt) {
+		return nil
+	}
+	return i.LastRunStartedAt
+}
+
+// Busy returns true if the instance is running and performing work: the number of units
+// of work in flight it last reported, or else whether it is performing a run.
+func (i RoutineInstanceInfo) Busy() bool {
+	if !i.Running() {
+		return false
+	}
+	if i.InFlight != nil {
+		return *i.InFlight > 0
+	}
+	return i.CurrentRunStartedAt() != nil
+}
+
+// Stuck returns true if the instance is running but has not shown any sign of progress,
+// such as starting or finishing a run or sending a heartbeat, for longer than
+// StuckThreshold of the given interval. Instances that never sent a heartbeat are never
+// considered stuck, as their liveness is unknown.
+func (i RoutineInstanceInfo) Stuck(now time.Time, interval time.Duration) bool {
+	if !i.Running() || i.LastHeartbeatAt == nil {
+		return false
+	}
+
+	lastActivity := *i.LastHeartbeatAt
+	if i.LastStartedAt.After(lastActivity) {
+		lastActivity = *i.LastStartedAt
+	}
+	return now.Sub(lastActivity) > StuckThreshold(interval)
+}
+
+// StuckThreshold returns the time without any sign of progress after which an instance
+// of a routine running at the given interval is considered stuck.
+func StuckThreshold(interval time.Duration) time.Duration {
+	if threshold := stuckIntervalMultiple * interval; threshold > minStuckThreshold {
+		return threshold
+	}
+	return minStuckThreshold
+}
+
+const (
+	// stuckIntervalMultiple is the number of intervals without any sign of progress
+	// after which an instance is considered stuck.
+	stuckIntervalMultiple = 3
+	// minStuckThreshold is the minimum time without any sign of progress after which
+	// an instance is considered stuck.
+	minStuckThreshold = 10 * time.Minute
+)
+
 // RoutineRun contains information about a single run of a routine.
 // That is, a single action that a running instance of a routine performed.
 type RoutineRun struct {
@@ -143,3 +143,162 @@ func (r *RoutineMock) Interval() time.Duration {
 func (r *RoutineMock) RegisterRecorder(*Recorder) {
 	// Do nothing
 }
+
+func TestLivenessOfRoutineInstances(t *testing.T) {
+	rcache.SetupForTest(t)
+
+	c := rcache.NewWithTTL(keyPrefix, 1)
+	recorder := New(log.NoOp(), "test", c)
+
+	routine := newRoutineMock("routine-1", "a routine", 2*time.Minute)
+	routine.SetJobName("job-1")
+	recorder.Register(routine)
+	recorder.RegistrationDone()
+	recorder.LogStart(routine)
+
+	getInstance := func() RoutineInstanceInfo {
+		jobInfos, err := GetBackgroundJobInfos(c, "", 5, 7)
+		assert.NoError(t, err)
+		return jobInfos[0].Routines[0].Instances[0]
+	}
+
+	// No runs yet
+	instance := getInstance()
+	assert.Nil(t, instance.LastHeartbeatAt)
+	assert.Nil(t, instance.CurrentRunStartedAt())
+	assert.Nil(t, instance.LastError)
+
+	// A run is in progress
+	runStart := time.Now()
+	recorder.LogRunStart(routine, runStart)
+	instance = getInstance()
+	assert.NotNil(t, instance.LastHeartbeatAt)
+	if assert.NotNil(t, instance.CurrentRunStartedAt()) {
+		assert.True(t, runStart.Equal(*instance.CurrentRunStartedAt()))
+	}
+
+	// The run failed
+	recorder.LogRun(routine, time.Millisecond, errors.New("test error"))
+	instance = getInstance()
+	assert.Nil(t, instance.CurrentRunStartedAt())
+	if assert.NotNil(t, instance.LastError) {
+		assert.Equal(t, "test error", instance.LastError.ErrorMessage)
+	}
+
+	// The last error survives successful runs
+	recorder.LogRun(routine, time.Millisecond, nil)
+	instance = getInstance()
+	if assert.NotNil(t, instance.LastError) {
+		assert.Equal(t, "test error", instance.LastError.ErrorMessage)
+	}
+}
+
+func TestRoutineInstanceInfoStuck(t *testing.T) {
+	now := time.Now()
+	at := func(ago time.Duration) *time.Time {
+		v := now.Add(-ago)
+		return &v
+	}
+
+	testCases := []struct {
+		name     string
+		instance RoutineInstanceInfo
+		interval time.Duration
+		stuck    bool
+	}{
+		{
+			name:     "no heartbeat",
+			instance: RoutineInstanceInfo{LastStartedAt: at(time.Hour)},
+			interval: time.Minute,
+			stuck:    false,
+		},
+		{
+			name:     "stopped",
+			instance: RoutineInstanceInfo{LastStartedAt: at(2 * time.Hour), LastStoppedAt: at(time.Hour), LastHeartbeatAt: at(2 * time.Hour)},
+			interval: time.Minute,
+			stuck:    false,
+		},
+		{
+			name:     "recent heartbeat",
+			instance: RoutineInstanceInfo{LastStartedAt: at(time.Hour), LastHeartbeatAt: at(time.Minute)},
+			interval: time.Minute,
+			stuck:    false,
+		},
+		{
+			name:     "old heartbeat",
+			instance: RoutineInstanceInfo{LastStartedAt: at(time.Hour), LastHeartbeatAt: at(30 * time.Minute)},
+			interval: time.Minute,
+			stuck:    true,
+		},
+		{
+			name:     "old heartbeat within long interval",
+			instance: RoutineInstanceInfo{LastStartedAt: at(time.Hour), LastHeartbeatAt: at(30 * time.Minute)},
+			interval: time.Hour,
+			stuck:    false,
+		},
+		{
+			name:     "restarted after heartbeat",
+			instance: RoutineInstanceInfo{LastStartedAt: at(time.Minute), LastHeartbeatAt: at(time.Hour)},
+			interval: time.Minute,
+			stuck:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.stuck, tc.instance.Stuck(now, tc.interval))
+		})
+	}
+}
+
+func TestRoutineControl(t *testing.T) {
+	rcache.SetupForTest(t)
+
+	c := rcache.NewWithTTL(keyPrefix, 1)
+	recorder := New(log.NoOp(), "test", c)
+
+	routine := newRoutineMock("routine-1", "a routine", 2*time.Minute)
+	routine.SetJobName("job-1")
+	recorder.Register(routine)
+	recorder.RegistrationDone()
+	recorder.LogStart(routine)
+
+	getRoutine := func() RoutineInfo {
+		jobInfos, err := GetBackgroundJobInfos(c, "", 5, 7)
+		assert.NoError(t, err)
+		return jobInfos[0].Routines[0]
+	}
+	// Bypass the control cache of the recorder
+	paused := func() bool {
+		recorder.controls.fetchedAt = time.Time{}
+		return recorder.Paused(routine)
+	}
+
+	assert.Equal(t, ControlStateRunning, getRoutine().ControlState())
+	assert.False(t, paused())
+
+	// Paused
+	assert.NoError(t, SetRoutineControl(c, "job-1", "routine-1", ControlStatePaused, 1))
+	info := getRoutine()
+	assert.Equal(t, ControlStatePaused, info.ControlState())
+	if assert.NotNil(t, info.Control) {
+		assert.Equal(t, int32(1), info.Control.UserID)
+	}
+	assert.True(t, paused())
+
+	// Draining while a run is in progress, drained once it finished
+	recorder.LogRunStart(routine, time.Now())
+	assert.NoError(t, SetRoutineControl(c, "job-1", "routine-1", ControlStateDraining, 1))
+	assert.Equal(t, ControlStateDraining, getRoutine().ControlState())
+	assert.True(t, paused())
+	recorder.LogRun(routine, time.Millisecond, nil)
+	assert.Equal(t, ControlStateDrained, getRoutine().ControlState())
+
+	// Resumed
+	assert.NoError(t, SetRoutineControl(c, "job-1", "routine-1", ControlStateRunning, 1))
+	assert.Equal(t, ControlStateRunning, getRoutine().ControlState())
+	assert.Nil(t, getRoutine().Control)
+	assert.False(t, paused())
+
+	assert.Error(t, SetRoutineControl(c, "job-1", "routine-1", ControlStateDrained, 1))
+}
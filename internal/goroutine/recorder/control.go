@@ -0,0 +1,139 @@
+package recorder
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ControlState is the state a site admin put a routine in.
+type ControlState string
+
+const (
+	// ControlStateRunning is the default state: the routine runs normally.
+	ControlStateRunning ControlState = "RUNNING"
+	// ControlStatePaused means that the routine does not start new runs or pick up new
+	// work until it is resumed.
+	ControlStatePaused ControlState = "PAUSED"
+	// ControlStateDraining means that the routine finishes the work it is performing but
+	// does not pick up new work until it is resumed. Once no instance performs work
+	// anymore, the routine is reported as drained.
+	ControlStateDraining ControlState = "DRAINING"
+	// ControlStateDrained is reported for a draining routine that no instance performs
+	// work for anymore. It cannot be set.
+	ControlStateDrained ControlState = "DRAINED"
+)
+
+// RoutineControl is the state a site admin put a routine in, along with who did it and
+// when.
+type RoutineControl struct {
+	State  ControlState `json:"state"`
+	UserID int32        `json:"userID"`
+	At     time.Time    `json:"at"`
+}
+
+// controlsKey is the Redis hash that holds the control state of every routine that is not
+// running normally, by "jobName:routineName".
+const controlsKey = "routineControls"
+
+// controlCacheTTL is how long routines keep using the control states read from Redis
+// before reading them again. Pausing or draining a routine thus takes effect within this
+// time on every host.
+const controlCacheTTL = 5 * time.Second
+
+// SetRoutineControl puts the given routine in the given state on all hosts. Setting the
+// state to ControlStateRunning resumes the routine.
+func SetRoutineControl(c *rcache.Cache, jobName string, routineName string, state ControlState, userID int32) error {
+	field := jobName + ":" + routineName
+
+	switch state {
+	case ControlStateRunning:
+		_, err := c.DeleteHashItem(controlsKey, field)
+		return errors.Wrap(err, "delete routine control")
+	case ControlStatePaused, ControlStateDraining:
+	default:
+		return errors.Newf("unknown control state %q", state)
+	}
+
+	controlJson, err := json.Marshal(RoutineControl{State: state, UserID: userID, At: time.Now()})
+	if err != nil {
+		return errors.Wrap(err, "serialize routine control")
+	}
+	return errors.Wrap(c.SetHashItem(controlsKey, field, string(controlJson)), "save routine control")
+}
+
+// getRoutineControls returns the control state of every routine that is not running
+// normally, by "jobName:routineName".
+func getRoutineControls(c *rcache.Cache) (map[string]RoutineControl, error) {
+	rawItems, err := c.GetHashAll(controlsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	controls := make(map[string]RoutineControl, len(rawItems))
+	for field, rawItem := range rawItems {
+		var control RoutineControl
+		if err := json.Unmarshal([]byte(rawItem), &control); err != nil {
+			return nil, errors.Wrap(err, "deserialize routine control")
+		}
+		controls[field] = control
+	}
+	return controls, nil
+}
+
+type controlCache struct {
+	mu        sync.Mutex
+	controls  map[string]RoutineControl
+	fetchedAt time.Time
+}
+
+// Paused returns true if a site admin paused or drained the given routine, in which case
+// it must not start new runs or pick up new work. Failures to read the control states are
+// logged, and the last known states are used in the meantime.
+func (m *Recorder) Paused(r Recordable) bool {
+	now := time.Now()
+
+	m.controls.mu.Lock()
+	defer m.controls.mu.Unlock()
+
+	if now.Sub(m.controls.fetchedAt) >= controlCacheTTL {
+		controls, err := getRoutineControls(m.rcache)
+		if err != nil {
+			m.logger.Error("failed to read routine controls", log.Error(err))
+		} else {
+			m.controls.controls = controls
+		}
+		m.controls.fetchedAt = now
+	}
+
+	control, ok := m.controls.controls[r.JobName()+":"+r.Name()]
+	return ok && control.State != ControlStateRunning
+}
+
+// ControlState returns the state a site admin put the routine in, reporting a draining
+// routine as drained once none of its running instances performs work anymore.
+func (i RoutineInfo) ControlState() ControlState {
+	if i.Control == nil {
+		return ControlStateRunning
+	}
+	if i.Control.State != ControlStateDraining {
+		return i.Control.State
+	}
+	for _, instance := range i.Instances {
+		if instance.Busy() {
+			return ControlStateDraining
+		}
+	}
+	return ControlStateDrained
+}
+
+// inFlightReporter is implemented by routines that process several units of work
+// concurrently, such as DB-backed workers, and that can tell how many they are processing.
+type inFlightReporter interface {
+	InFlight() int
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/rcache"
@@ -52,12 +53,20 @@ func GetBackgroundJobInfo(c *rcache.Cache, jobName string, recentRunCount int, d
 		return JobInfo{}, err
 	}
 
+	controls, err := getRoutineControls(c)
+	if err != nil {
+		return JobInfo{}, errors.Wrap(err, "get routine controls")
+	}
+
 	routineInfos := make([]RoutineInfo, 0, len(routines))
 	for _, r := range routines {
 		routineInfo, err := getRoutineInfo(c, r, allHostNames, recentRunCount, dayCountForStats)
 		if err != nil {
 			return JobInfo{}, err
 		}
+		if control, ok := controls[r.JobName+":"+r.Name]; ok {
+			routineInfo.Control = &control
+		}
 
 		routineInfos = append(routineInfos, routineInfo)
 	}
@@ -221,32 +230,48 @@ func getRoutineInfo(c *rcache.Cache, r serializableRoutineInfo, allHostNames []s
 
 // getRoutineInstanceInfo returns the info for a single routine instance.
 func getRoutineInstanceInfo(c *rcache.Cache, jobName string, routineName string, hostName string) (RoutineInstanceInfo, error) {
-	var lastStart *time.Time
-	var lastStop *time.Time
-
-	lastStartBytes, ok := c.Get(jobName + ":" + routineName + ":" + hostName + ":" + "lastStart")
-	if ok {
-		t, err := time.Parse(time.RFC3339, string(lastStartBytes))
+	keyPrefix := jobName + ":" + routineName + ":" + hostName + ":"
+	info := RoutineInstanceInfo{HostName: hostName}
+
+	for _, field := range []struct {
+		key   string
+		value **time.Time
+	}{
+		{key: "lastStart", value: &info.LastStartedAt},
+		{key: "lastStop", value: &info.LastStoppedAt},
+		{key: "lastHeartbeat", value: &info.LastHeartbeatAt},
+		{key: "lastRunStart", value: &info.LastRunStartedAt},
+		{key: "lastRunFinish", value: &info.LastRunFinishedAt},
+	} {
+		raw, ok := c.Get(keyPrefix + field.key)
+		if !ok {
+			continue
+		}
+		// RFC3339 also parses the fractional seconds of RFC3339Nano
+		t, err := time.Parse(time.RFC3339, string(raw))
 		if err != nil {
-			return RoutineInstanceInfo{}, errors.Wrap(err, "parse last start")
+			return RoutineInstanceInfo{}, errors.Wrapf(err, "parse %s", field.key)
 		}
-		lastStart = &t
+		*field.value = &t
 	}
 
-	lastStopBytes, ok := c.Get(jobName + ":" + routineName + ":" + hostName + ":" + "lastStop")
-	if ok {
-		t, err := time.Parse(time.RFC3339, string(lastStopBytes))
+	if raw, ok := c.Get(keyPrefix + "inFlight"); ok {
+		inFlight, err := strconv.Atoi(string(raw))
 		if err != nil {
-			return RoutineInstanceInfo{}, errors.Wrap(err, "parse last stop")
+			return RoutineInstanceInfo{}, errors.Wrap(err, "parse inFlight")
+		}
+		info.InFlight = &inFlight
+	}
+
+	if raw, ok := c.Get(keyPrefix + "lastError"); ok {
+		var run RoutineRun
+		if err := json.Unmarshal(raw, &run); err != nil {
+			return RoutineInstanceInfo{}, errors.Wrap(err, "deserialize last error")
 		}
-		lastStop = &t
+		info.LastError = &run
 	}
 
-	return RoutineInstanceInfo{
-		HostName:      hostName,
-		LastStartedAt: lastStart,
-		LastStoppedAt: lastStop,
-	}, nil
+	return info, nil
 }
 
 // loadRecentRuns loads the recent runs for a routine, in no particular order.
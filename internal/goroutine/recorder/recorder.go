@@ -1,7 +1,10 @@
 package recorder
 
 import (
+	"context"
 	"encoding/json"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/sourcegraph/log"
@@ -26,6 +29,11 @@ type Recorder struct {
 	logger      log.Logger
 	recordables []Recordable
 	hostName    string
+
+	heartbeatsMu sync.Mutex
+	heartbeats   map[string]time.Time // last heartbeat saved, by routine key
+
+	controls controlCache
 }
 
 // seenTimeout is the maximum time we allow no activity for each host, job, and routine.
@@ -37,6 +45,9 @@ const keyPrefix = "background-job-logger"
 // maxRecentRunsLength is the maximum number of recent runs we want to store for each routine.
 const maxRecentRunsLength = 100
 
+// heartbeatInterval is the minimum time between two heartbeats of a routine saved in Redis.
+const heartbeatInterval = 10 * time.Second
+
 // New creates a new recorder.
 func New(logger log.Logger, hostName string, cache *rcache.Cache) *Recorder {
 	return &Recorder{rcache: cache, logger: logger, hostName: hostName}
@@ -123,6 +134,65 @@ func (m *Recorder) LogStart(r Recordable) {
 	m.logger.Debug("Routine just started! 🚀", log.String("routine", r.Name()))
 }
 
+// LogRunStart logs the start of a single run of a routine at the given time.
+func (m *Recorder) LogRunStart(r Recordable, at time.Time) {
+	m.rcache.Set(r.JobName()+":"+r.Name()+":"+m.hostName+":"+"lastRunStart", []byte(at.Format(time.RFC3339Nano)))
+	m.saveHeartbeat(r, at)
+}
+
+// LogHeartbeat records that a routine is alive and making progress. Routines that
+// perform long runs or wait for work for longer than their interval should call this
+// regularly, so that they are not reported as stuck. Heartbeats are saved at most once
+// every heartbeatInterval per routine.
+func (m *Recorder) LogHeartbeat(r Recordable) {
+	now := time.Now()
+	key := r.JobName() + ":" + r.Name()
+
+	m.heartbeatsMu.Lock()
+	if last, ok := m.heartbeats[key]; ok && now.Sub(last) < heartbeatInterval {
+		m.heartbeatsMu.Unlock()
+		return
+	}
+	if m.heartbeats == nil {
+		m.heartbeats = map[string]time.Time{}
+	}
+	m.heartbeats[key] = now
+	m.heartbeatsMu.Unlock()
+
+	m.saveHeartbeat(r, now)
+}
+
+type heartbeatKey struct{}
+
+type heartbeatTarget struct {
+	recorder   *Recorder
+	recordable Recordable
+}
+
+// WithHeartbeat returns a context through which Heartbeat records heartbeats of the given
+// routine.
+func WithHeartbeat(ctx context.Context, m *Recorder, r Recordable) context.Context {
+	return context.WithValue(ctx, heartbeatKey{}, heartbeatTarget{recorder: m, recordable: r})
+}
+
+// Heartbeat records that the routine performing the run of the given context is alive.
+// Long-running routines should call this regularly so that they are not reported as
+// stuck. It does nothing if the context does not belong to a recorded routine.
+func Heartbeat(ctx context.Context) {
+	if target, ok := ctx.Value(heartbeatKey{}).(heartbeatTarget); ok {
+		target.recorder.LogHeartbeat(target.recordable)
+	}
+}
+
+// saveHeartbeat saves the time of the last heartbeat of a routine, along with the number
+// of units of work it is processing if it reports them.
+func (m *Recorder) saveHeartbeat(r Recordable, at time.Time) {
+	m.rcache.Set(r.JobName()+":"+r.Name()+":"+m.hostName+":"+"lastHeartbeat", []byte(at.Format(time.RFC3339Nano)))
+	if reporter, ok := r.(inFlightReporter); ok {
+		m.rcache.Set(r.JobName()+":"+r.Name()+":"+m.hostName+":"+"inFlight", []byte(strconv.Itoa(reporter.InFlight())))
+	}
+}
+
 // LogStop logs the stop of a routine.
 func (m *Recorder) LogStop(r Recordable) {
 	m.rcache.Set(r.JobName()+":"+r.Name()+":"+m.hostName+":"+"lastStop", []byte(time.Now().Format(time.RFC3339)))
@@ -138,6 +208,11 @@ func (m *Recorder) LogRun(r Recordable, duration time.Duration, runErr error) {
 		m.logger.Error("failed to save run", log.Error(err))
 	}
 
+	// Save the end of the run, which also serves as a heartbeat
+	now := time.Now()
+	m.rcache.Set(r.JobName()+":"+r.Name()+":"+m.hostName+":"+"lastRunFinish", []byte(now.Format(time.RFC3339Nano)))
+	m.saveHeartbeat(r, now)
+
 	// Save run stats
 	err = saveRunStats(m.rcache, r.JobName(), r.Name(), durationMs, runErr != nil)
 	if err != nil {
@@ -151,7 +226,9 @@ func (m *Recorder) LogRun(r Recordable, duration time.Duration, runErr error) {
 	m.logger.Debug("Hello from " + r.Name() + "! 😄")
 }
 
-// saveRun saves a run in the Redis list under the "*:recentRuns" key.
+// saveRun saves a run in the Redis list under the "*:recentRuns" key. A failed run is
+// also saved under the "*:lastError" key, so that it remains visible once it is no
+// longer among the recent runs.
 func (m *Recorder) saveRun(jobName string, routineName string, hostName string, durationMs int32, err error) error {
 	errorMessage := ""
 	if err != nil {
@@ -178,6 +255,10 @@ func (m *Recorder) saveRun(jobName string, routineName string, hostName string,
 		return errors.Wrap(err, "save run")
 	}
 
+	if errorMessage != "" {
+		m.rcache.Set(jobName+":"+routineName+":"+hostName+":"+"lastError", runJson)
+	}
+
 	return nil
 }
 
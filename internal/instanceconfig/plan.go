@@ -0,0 +1,420 @@
+package instanceconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/codepolicies"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ResourceKind is the kind of configuration a change applies to.
+type ResourceKind string
+
+const (
+	ResourceKindSiteConfiguration ResourceKind = "SITE_CONFIGURATION"
+	ResourceKindAuthProvider      ResourceKind = "AUTH_PROVIDER"
+	ResourceKindExternalService   ResourceKind = "EXTERNAL_SERVICE"
+	ResourceKindCodePolicy        ResourceKind = "CODE_POLICY"
+)
+
+// Action is what a change does to a resource.
+type Action string
+
+const (
+	ActionCreate Action = "CREATE"
+	ActionUpdate Action = "UPDATE"
+	ActionDelete Action = "DELETE"
+)
+
+// Change describes a change to a resource of the configuration. Changes never
+// include configuration values, which may be secret.
+type Change struct {
+	Kind   ResourceKind
+	Action Action
+	// Name identifies the resource.
+	Name string
+	// Fields are the names of the fields an update changes.
+	Fields []string
+}
+
+// State is the current configuration of an instance.
+type State struct {
+	// SiteConfiguration is the raw site configuration.
+	SiteConfiguration string
+	ExternalServices  []*ExternalServiceState
+	CodePolicies      []*codepolicies.Policy
+}
+
+// ExternalServiceState is an existing code host connection.
+type ExternalServiceState struct {
+	ID          int64
+	Kind        string
+	DisplayName string
+	// Config is the decrypted configuration of the code host connection.
+	Config string
+}
+
+// ExternalServiceUpdate replaces the configuration of a code host connection.
+type ExternalServiceUpdate struct {
+	ID     int64
+	Config string
+}
+
+// Plan is the list of changes that converge a State to a Snapshot, along with
+// the values to write.
+type Plan struct {
+	Changes []Change
+
+	// SiteConfiguration is the new site configuration, or nil if it does not
+	// change.
+	SiteConfiguration *string
+
+	CreateExternalServices []ExternalService
+	UpdateExternalServices []ExternalServiceUpdate
+	DeleteExternalServices []int64
+
+	CreateCodePolicies []*codepolicies.Policy
+	UpdateCodePolicies []*codepolicies.Policy
+	DeleteCodePolicies []int32
+}
+
+// NewPlan computes the changes that converge the given state to the snapshot.
+// The plan of a state that matches the snapshot has no changes.
+func NewPlan(state *State, snapshot *Snapshot) (*Plan, error) {
+	p := &Plan{}
+	if err := p.planSiteConfiguration(state.SiteConfiguration, snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.ExternalServices != nil {
+		if err := p.planExternalServices(state.ExternalServices, snapshot.ExternalServices); err != nil {
+			return nil, err
+		}
+	}
+	if snapshot.CodePolicies != nil {
+		p.planCodePolicies(state.CodePolicies, snapshot.CodePolicies)
+	}
+	return p, nil
+}
+
+func (p *Plan) planSiteConfiguration(current string, snapshot *Snapshot) error {
+	if snapshot.SiteConfiguration == nil && snapshot.AuthProviders == nil {
+		return nil
+	}
+
+	desired := current
+	if snapshot.SiteConfiguration != nil {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, snapshot.SiteConfiguration, "", "  "); err != nil {
+			return err
+		}
+		desired = buf.String()
+	}
+	if snapshot.AuthProviders != nil {
+		var err error
+		if desired, err = jsonc.Edit(desired, snapshot.AuthProviders, "auth.providers"); err != nil {
+			return errors.Wrap(err, "setting auth.providers")
+		}
+	}
+
+	var currentValues, desiredValues map[string]any
+	if err := jsonc.Unmarshal(current, &currentValues); err != nil {
+		return errors.Wrap(err, "parsing current site configuration")
+	}
+	if err := jsonc.Unmarshal(desired, &desiredValues); err != nil {
+		return errors.Wrap(err, "parsing site configuration")
+	}
+
+	var fields []string
+	for key := range union(currentValues, desiredValues) {
+		if key != "auth.providers" && !reflect.DeepEqual(currentValues[key], desiredValues[key]) {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+	if len(fields) > 0 {
+		p.Changes = append(p.Changes, Change{
+			Kind:   ResourceKindSiteConfiguration,
+			Action: ActionUpdate,
+			Name:   "site",
+			Fields: fields,
+		})
+	}
+
+	providerChanges, err := planAuthProviders(currentValues["auth.providers"], desiredValues["auth.providers"])
+	if err != nil {
+		return err
+	}
+	p.Changes = append(p.Changes, providerChanges...)
+
+	if len(fields) > 0 || len(providerChanges) > 0 {
+		p.SiteConfiguration = &desired
+	}
+	return nil
+}
+
+// planAuthProviders returns the changes between the given values of the
+// auth.providers property, identifying providers by their type and configID.
+func planAuthProviders(current, desired any) ([]Change, error) {
+	currentProviders, err := authProvidersByKey(current)
+	if err != nil {
+		return nil, errors.Wrap(err, "current site configuration")
+	}
+	desiredProviders, err := authProvidersByKey(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for _, key := range sortedKeys(union(currentProviders, desiredProviders)) {
+		c, inCurrent := currentProviders[key]
+		d, inDesired := desiredProviders[key]
+		switch {
+		case !inCurrent:
+			changes = append(changes, Change{Kind: ResourceKindAuthProvider, Action: ActionCreate, Name: key})
+		case !inDesired:
+			changes = append(changes, Change{Kind: ResourceKindAuthProvider, Action: ActionDelete, Name: key})
+		default:
+			if fields := changedFields(c, d); len(fields) > 0 {
+				changes = append(changes, Change{Kind: ResourceKindAuthProvider, Action: ActionUpdate, Name: key, Fields: fields})
+			}
+		}
+	}
+	return changes, nil
+}
+
+func authProvidersByKey(value any) (map[string]map[string]any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	providers, ok := value.([]any)
+	if !ok {
+		return nil, errors.New("auth.providers must be an array")
+	}
+
+	byKey := make(map[string]map[string]any, len(providers))
+	for _, provider := range providers {
+		data, err := json.Marshal(provider)
+		if err != nil {
+			return nil, err
+		}
+		key, err := authProviderKey(data)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := byKey[key]; ok {
+			return nil, errors.Errorf("duplicate auth provider %q, set a unique configID", key)
+		}
+		byKey[key] = provider.(map[string]any)
+	}
+	return byKey, nil
+}
+
+// authProviderKey returns the key that identifies the given auth provider: its
+// type, followed by its configID if it has one.
+func authProviderKey(provider json.RawMessage) (string, error) {
+	var p struct {
+		Type     string `json:"type"`
+		ConfigID string `json:"configID"`
+	}
+	if err := json.Unmarshal(provider, &p); err != nil || p.Type == "" {
+		return "", errors.New("auth providers must be JSON objects with a type")
+	}
+	if p.ConfigID == "" {
+		return p.Type, nil
+	}
+	return p.Type + ":" + p.ConfigID, nil
+}
+
+func (p *Plan) planExternalServices(current []*ExternalServiceState, desired []ExternalService) error {
+	currentByKey := make(map[string]*ExternalServiceState, len(current))
+	for _, svc := range current {
+		key := externalServiceKey(svc.Kind, svc.DisplayName)
+		if _, ok := currentByKey[key]; ok {
+			return errors.Errorf("external service %q is ambiguous, give the existing external services unique display names", key)
+		}
+		currentByKey[key] = svc
+	}
+	desiredByKey := make(map[string]ExternalService, len(desired))
+	for _, svc := range desired {
+		desiredByKey[externalServiceKey(svc.Kind, svc.DisplayName)] = svc
+	}
+
+	for _, key := range sortedKeys(union(currentByKey, desiredByKey)) {
+		c, inCurrent := currentByKey[key]
+		d, inDesired := desiredByKey[key]
+		switch {
+		case !inCurrent:
+			d.Kind = strings.ToUpper(d.Kind)
+			d.Config = indent(d.Config)
+			p.CreateExternalServices = append(p.CreateExternalServices, d)
+			p.Changes = append(p.Changes, Change{Kind: ResourceKindExternalService, Action: ActionCreate, Name: key})
+
+		case !inDesired:
+			p.DeleteExternalServices = append(p.DeleteExternalServices, c.ID)
+			p.Changes = append(p.Changes, Change{Kind: ResourceKindExternalService, Action: ActionDelete, Name: key})
+
+		default:
+			var currentConfig, desiredConfig map[string]any
+			if err := jsonc.Unmarshal(c.Config, &currentConfig); err != nil {
+				return errors.Wrapf(err, "parsing the config of external service %q", key)
+			}
+			if err := json.Unmarshal(d.Config, &desiredConfig); err != nil {
+				return errors.Wrapf(err, "parsing the config of external service %q", key)
+			}
+			if fields := changedFields(currentConfig, desiredConfig); len(fields) > 0 {
+				p.UpdateExternalServices = append(p.UpdateExternalServices, ExternalServiceUpdate{ID: c.ID, Config: string(indent(d.Config))})
+				p.Changes = append(p.Changes, Change{Kind: ResourceKindExternalService, Action: ActionUpdate, Name: key, Fields: prefixed("config.", fields)})
+			}
+		}
+	}
+	return nil
+}
+
+// externalServiceKey returns the key that identifies a code host connection.
+func externalServiceKey(kind, displayName string) string {
+	return strings.ToUpper(kind) + "/" + displayName
+}
+
+func (p *Plan) planCodePolicies(current []*codepolicies.Policy, desired []CodePolicy) {
+	currentByName := make(map[string]*codepolicies.Policy, len(current))
+	for _, policy := range current {
+		currentByName[policy.Name] = policy
+	}
+	desiredByName := make(map[string]*codepolicies.Policy, len(desired))
+	for _, policy := range desired {
+		desiredByName[strings.TrimSpace(policy.Name)] = policy.toPolicy()
+	}
+
+	for _, name := range sortedKeys(union(currentByName, desiredByName)) {
+		c, inCurrent := currentByName[name]
+		d, inDesired := desiredByName[name]
+		switch {
+		case !inCurrent:
+			p.CreateCodePolicies = append(p.CreateCodePolicies, d)
+			p.Changes = append(p.Changes, Change{Kind: ResourceKindCodePolicy, Action: ActionCreate, Name: name})
+
+		case !inDesired:
+			p.DeleteCodePolicies = append(p.DeleteCodePolicies, c.ID)
+			p.Changes = append(p.Changes, Change{Kind: ResourceKindCodePolicy, Action: ActionDelete, Name: name})
+
+		default:
+			if fields := changedPolicyFields(c, d); len(fields) > 0 {
+				d.ID = c.ID
+				p.UpdateCodePolicies = append(p.UpdateCodePolicies, d)
+				p.Changes = append(p.Changes, Change{Kind: ResourceKindCodePolicy, Action: ActionUpdate, Name: name, Fields: fields})
+			}
+		}
+	}
+}
+
+func (p CodePolicy) toPolicy() *codepolicies.Policy {
+	enabled := true
+	if p.Enabled != nil {
+		enabled = *p.Enabled
+	}
+	intervalMinutes := int32(60)
+	if p.IntervalMinutes != nil {
+		intervalMinutes = *p.IntervalMinutes
+	}
+	webhookURL := p.WebhookURL
+	if webhookURL != nil && *webhookURL == "" {
+		webhookURL = nil
+	}
+
+	return &codepolicies.Policy{
+		Name:        strings.TrimSpace(p.Name),
+		Description: p.Description,
+		Query:       strings.TrimSpace(p.Query),
+		RepoQuery:   strings.TrimSpace(p.RepositoryQuery),
+		WebhookURL:  webhookURL,
+		Enabled:     enabled,
+		Interval:    time.Duration(intervalMinutes) * time.Minute,
+	}
+}
+
+// changedPolicyFields returns the names of the fields of the policies that
+// differ, as named in snapshots.
+func changedPolicyFields(current, desired *codepolicies.Policy) []string {
+	var fields []string
+	if current.Description != desired.Description {
+		fields = append(fields, "description")
+	}
+	if current.Query != desired.Query {
+		fields = append(fields, "query")
+	}
+	if current.RepoQuery != desired.RepoQuery {
+		fields = append(fields, "repositoryQuery")
+	}
+	if stringOrEmpty(current.WebhookURL) != stringOrEmpty(desired.WebhookURL) {
+		fields = append(fields, "webhookURL")
+	}
+	if current.Enabled != desired.Enabled {
+		fields = append(fields, "enabled")
+	}
+	if current.Interval != desired.Interval {
+		fields = append(fields, "intervalMinutes")
+	}
+	return fields
+}
+
+// changedFields returns the sorted top-level properties whose values differ
+// between the given objects.
+func changedFields(current, desired map[string]any) []string {
+	var fields []string
+	for key := range union(current, desired) {
+		if !reflect.DeepEqual(current[key], desired[key]) {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func union[V, W any](a map[string]V, b map[string]W) map[string]struct{} {
+	keys := make(map[string]struct{}, len(a)+len(b))
+	for key := range a {
+		keys[key] = struct{}{}
+	}
+	for key := range b {
+		keys[key] = struct{}{}
+	}
+	return keys
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func prefixed(prefix string, values []string) []string {
+	prefixedValues := make([]string, len(values))
+	for i, value := range values {
+		prefixedValues[i] = prefix + value
+	}
+	return prefixedValues
+}
+
+func indent(value json.RawMessage) json.RawMessage {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, value, "", "  "); err != nil {
+		return value
+	}
+	return buf.Bytes()
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
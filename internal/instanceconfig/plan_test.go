@@ -0,0 +1,233 @@
+package instanceconfig
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/codepolicies"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+)
+
+func TestNewPlan(t *testing.T) {
+	state := &State{
+		SiteConfiguration: `{
+  // The URL of the instance
+  "externalURL": "https://sourcegraph.example.com",
+  "auth.providers": [
+    {"type": "builtin", "allowSignup": false},
+    {"type": "github", "configID": "github", "url": "https://github.com", "clientSecret": "s3cr3t"},
+  ],
+}`,
+		ExternalServices: []*ExternalServiceState{
+			{ID: 1, Kind: "GITHUB", DisplayName: "GitHub", Config: `{"url": "https://github.com", "token": "abc", "orgs": ["sourcegraph"]}`},
+			{ID: 2, Kind: "GITLAB", DisplayName: "GitLab", Config: `{"url": "https://gitlab.com", "token": "abc"}`},
+		},
+		CodePolicies: []*codepolicies.Policy{
+			{ID: 1, Name: "no md5", Query: "md5.New lang:go", Enabled: true, Interval: time.Hour},
+			{ID: 2, Name: "no sha1", Query: "sha1.New lang:go", Enabled: true, Interval: time.Hour},
+		},
+	}
+
+	t.Run("matching snapshot", func(t *testing.T) {
+		snapshot, err := ParseSnapshot(`{
+			"authProviders": [
+				{"allowSignup": false, "type": "builtin"},
+				{"type": "github", "configID": "github", "url": "https://github.com", "clientSecret": "s3cr3t"},
+			],
+			"externalServices": [
+				{"kind": "github", "displayName": "GitHub", "config": {"orgs": ["sourcegraph"], "token": "abc", "url": "https://github.com"}},
+				{"kind": "GITLAB", "displayName": "GitLab", "config": {"url": "https://gitlab.com", "token": "abc"}},
+			],
+			"codePolicies": [
+				{"name": "no md5", "query": "md5.New lang:go"},
+				{"name": "no sha1", "query": "sha1.New lang:go", "enabled": true, "intervalMinutes": 60},
+			],
+		}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		plan, err := NewPlan(state, snapshot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(&Plan{}, plan); diff != "" {
+			t.Errorf("unexpected plan (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		snapshot, err := ParseSnapshot(`{
+			"authProviders": [
+				{"type": "builtin", "allowSignup": true},
+				{"type": "gitlab", "configID": "gitlab", "url": "https://gitlab.com"},
+			],
+			"externalServices": [
+				{"kind": "GITHUB", "displayName": "GitHub", "config": {"url": "https://github.com", "token": "abc", "orgs": ["sourcegraph", "other"]}},
+				{"kind": "BITBUCKETCLOUD", "displayName": "Bitbucket", "config": {"url": "https://bitbucket.org"}},
+			],
+			"codePolicies": [
+				{"name": "no md5", "query": "md5.New lang:go", "enabled": false},
+				{"name": "no des", "query": "des.NewCipher lang:go", "intervalMinutes": 30},
+			],
+		}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		plan, err := NewPlan(state, snapshot)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wantChanges := []Change{
+			{Kind: ResourceKindAuthProvider, Action: ActionUpdate, Name: "builtin", Fields: []string{"allowSignup"}},
+			{Kind: ResourceKindAuthProvider, Action: ActionDelete, Name: "github:github"},
+			{Kind: ResourceKindAuthProvider, Action: ActionCreate, Name: "gitlab:gitlab"},
+			{Kind: ResourceKindExternalService, Action: ActionCreate, Name: "BITBUCKETCLOUD/Bitbucket"},
+			{Kind: ResourceKindExternalService, Action: ActionUpdate, Name: "GITHUB/GitHub", Fields: []string{"config.orgs"}},
+			{Kind: ResourceKindExternalService, Action: ActionDelete, Name: "GITLAB/GitLab"},
+			{Kind: ResourceKindCodePolicy, Action: ActionCreate, Name: "no des"},
+			{Kind: ResourceKindCodePolicy, Action: ActionUpdate, Name: "no md5", Fields: []string{"enabled"}},
+			{Kind: ResourceKindCodePolicy, Action: ActionDelete, Name: "no sha1"},
+		}
+		if diff := cmp.Diff(wantChanges, plan.Changes); diff != "" {
+			t.Errorf("unexpected changes (-want +got):\n%s", diff)
+		}
+
+		// Only auth.providers is replaced, the rest of the site configuration
+		// is preserved.
+		if plan.SiteConfiguration == nil {
+			t.Fatal("expected site configuration to change")
+		}
+		if !strings.Contains(*plan.SiteConfiguration, "// The URL of the instance") {
+			t.Errorf("expected comments to be preserved, got %s", *plan.SiteConfiguration)
+		}
+		var siteConfiguration map[string]any
+		if err := jsonc.Unmarshal(*plan.SiteConfiguration, &siteConfiguration); err != nil {
+			t.Fatal(err)
+		}
+		wantSiteConfiguration := map[string]any{
+			"externalURL": "https://sourcegraph.example.com",
+			"auth.providers": []any{
+				map[string]any{"type": "builtin", "allowSignup": true},
+				map[string]any{"type": "gitlab", "configID": "gitlab", "url": "https://gitlab.com"},
+			},
+		}
+		if diff := cmp.Diff(wantSiteConfiguration, siteConfiguration); diff != "" {
+			t.Errorf("unexpected site configuration (-want +got):\n%s", diff)
+		}
+
+		if diff := cmp.Diff([]int64{2}, plan.DeleteExternalServices); diff != "" {
+			t.Errorf("unexpected deleted external services (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]ExternalServiceUpdate{{ID: 1, Config: "{\n  \"url\": \"https://github.com\",\n  \"token\": \"abc\",\n  \"orgs\": [\n    \"sourcegraph\",\n    \"other\"\n  ]\n}"}}, plan.UpdateExternalServices); diff != "" {
+			t.Errorf("unexpected updated external services (-want +got):\n%s", diff)
+		}
+
+		wantCreatedPolicies := []*codepolicies.Policy{
+			{Name: "no des", Query: "des.NewCipher lang:go", Enabled: true, Interval: 30 * time.Minute},
+		}
+		if diff := cmp.Diff(wantCreatedPolicies, plan.CreateCodePolicies); diff != "" {
+			t.Errorf("unexpected created code policies (-want +got):\n%s", diff)
+		}
+		wantUpdatedPolicies := []*codepolicies.Policy{
+			{ID: 1, Name: "no md5", Query: "md5.New lang:go", Enabled: false, Interval: time.Hour},
+		}
+		if diff := cmp.Diff(wantUpdatedPolicies, plan.UpdateCodePolicies); diff != "" {
+			t.Errorf("unexpected updated code policies (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]int32{2}, plan.DeleteCodePolicies); diff != "" {
+			t.Errorf("unexpected deleted code policies (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("site configuration", func(t *testing.T) {
+		snapshot, err := ParseSnapshot(`{
+			"siteConfiguration": {
+				"externalURL": "https://sourcegraph.example.org",
+				"disableAutoGitUpdates": true,
+				"auth.providers": [
+					{"type": "builtin", "allowSignup": false},
+					{"type": "github", "configID": "github", "url": "https://github.com", "clientSecret": "s3cr3t"},
+				],
+			},
+		}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		plan, err := NewPlan(state, snapshot)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Unmanaged sections are left as is.
+		wantChanges := []Change{
+			{Kind: ResourceKindSiteConfiguration, Action: ActionUpdate, Name: "site", Fields: []string{"disableAutoGitUpdates", "externalURL"}},
+		}
+		if diff := cmp.Diff(wantChanges, plan.Changes); diff != "" {
+			t.Errorf("unexpected changes (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("empty sections delete everything", func(t *testing.T) {
+		snapshot, err := ParseSnapshot(`{"externalServices": [], "codePolicies": []}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		plan, err := NewPlan(state, snapshot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]int64{1, 2}, plan.DeleteExternalServices); diff != "" {
+			t.Errorf("unexpected deleted external services (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]int32{1, 2}, plan.DeleteCodePolicies); diff != "" {
+			t.Errorf("unexpected deleted code policies (-want +got):\n%s", diff)
+		}
+		if plan.SiteConfiguration != nil {
+			t.Error("expected site configuration not to change")
+		}
+	})
+
+	t.Run("ambiguous external services", func(t *testing.T) {
+		state := &State{
+			ExternalServices: []*ExternalServiceState{
+				{ID: 1, Kind: "GITHUB", DisplayName: "GitHub", Config: `{}`},
+				{ID: 2, Kind: "GITHUB", DisplayName: "GitHub", Config: `{}`},
+			},
+		}
+		snapshot, err := ParseSnapshot(`{"externalServices": []}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewPlan(state, snapshot); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestParseSnapshot(t *testing.T) {
+	for name, snapshot := range map[string]string{
+		"unknown section":            `{"users": []}`,
+		"site configuration array":   `{"siteConfiguration": []}`,
+		"auth provider without type": `{"authProviders": [{"configID": "x"}]}`,
+		"external service config":    `{"externalServices": [{"kind": "GITHUB", "displayName": "GitHub", "config": "{}"}]}`,
+		"duplicate external service": `{"externalServices": [{"kind": "GITHUB", "displayName": "GitHub", "config": {}}, {"kind": "github", "displayName": "GitHub", "config": {}}]}`,
+		"code policy without query":  `{"codePolicies": [{"name": "x"}]}`,
+		"duplicate code policy":      `{"codePolicies": [{"name": "x", "query": "a"}, {"name": "x ", "query": "b"}]}`,
+		"invalid webhook URL":        `{"codePolicies": [{"name": "x", "query": "a", "webhookURL": "ftp://example.com"}]}`,
+		"invalid interval":           `{"codePolicies": [{"name": "x", "query": "a", "intervalMinutes": 0}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseSnapshot(snapshot); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Package instanceconfig computes the changes that converge the configuration of
+// an instance to a declarative snapshot, so that instances can be managed with
+// configuration stored in version control.
+package instanceconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Snapshot is a declarative snapshot of the configuration of an instance. Every
+// section of the snapshot replaces the corresponding configuration of the
+// instance entirely. Sections that are omitted are not managed by the snapshot
+// and left as is.
+type Snapshot struct {
+	// SiteConfiguration is the site configuration, a JSON object.
+	SiteConfiguration json.RawMessage `json:"siteConfiguration,omitempty"`
+	// AuthProviders replace the auth.providers property of the site
+	// configuration.
+	AuthProviders []json.RawMessage `json:"authProviders,omitempty"`
+	// ExternalServices are the code host connections of the instance,
+	// identified by their kind and display name.
+	ExternalServices []ExternalService `json:"externalServices,omitempty"`
+	// CodePolicies are the code policies of the instance, identified by their
+	// name.
+	CodePolicies []CodePolicy `json:"codePolicies,omitempty"`
+}
+
+// ExternalService is a code host connection of a snapshot.
+type ExternalService struct {
+	Kind        string `json:"kind"`
+	DisplayName string `json:"displayName"`
+	// Config is the configuration of the code host connection, a JSON object.
+	Config json.RawMessage `json:"config"`
+}
+
+// CodePolicy is a code policy of a snapshot. Optional fields have the same
+// defaults as when creating a code policy through the API.
+type CodePolicy struct {
+	Name            string  `json:"name"`
+	Description     string  `json:"description"`
+	Query           string  `json:"query"`
+	RepositoryQuery string  `json:"repositoryQuery"`
+	WebhookURL      *string `json:"webhookURL"`
+	Enabled         *bool   `json:"enabled"`
+	IntervalMinutes *int32  `json:"intervalMinutes"`
+}
+
+// ParseSnapshot parses and validates the given snapshot. Comments and trailing
+// commas are allowed.
+func ParseSnapshot(text string) (*Snapshot, error) {
+	var raw map[string]json.RawMessage
+	if err := jsonc.Unmarshal(text, &raw); err != nil {
+		return nil, errors.Wrap(err, "invalid snapshot")
+	}
+
+	var s Snapshot
+	for key, value := range raw {
+		var err error
+		switch key {
+		case "siteConfiguration":
+			if !isObject(value) {
+				return nil, errors.New("siteConfiguration must be a JSON object")
+			}
+			s.SiteConfiguration = value
+		case "authProviders":
+			s.AuthProviders = []json.RawMessage{}
+			err = json.Unmarshal(value, &s.AuthProviders)
+		case "externalServices":
+			s.ExternalServices = []ExternalService{}
+			err = json.Unmarshal(value, &s.ExternalServices)
+		case "codePolicies":
+			s.CodePolicies = []CodePolicy{}
+			err = json.Unmarshal(value, &s.CodePolicies)
+		default:
+			return nil, errors.Errorf("unknown snapshot section %q", key)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", key)
+		}
+	}
+
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *Snapshot) validate() error {
+	for _, provider := range s.AuthProviders {
+		if _, err := authProviderKey(provider); err != nil {
+			return err
+		}
+	}
+
+	externalServices := map[string]struct{}{}
+	for _, svc := range s.ExternalServices {
+		if svc.Kind == "" || svc.DisplayName == "" {
+			return errors.New("external services must have a kind and a display name")
+		}
+		if !isObject(svc.Config) {
+			return errors.Errorf("the config of external service %q must be a JSON object", svc.DisplayName)
+		}
+		key := externalServiceKey(svc.Kind, svc.DisplayName)
+		if _, ok := externalServices[key]; ok {
+			return errors.Errorf("duplicate external service %q", key)
+		}
+		externalServices[key] = struct{}{}
+	}
+
+	codePolicies := map[string]struct{}{}
+	for _, p := range s.CodePolicies {
+		name := strings.TrimSpace(p.Name)
+		if name == "" || strings.TrimSpace(p.Query) == "" {
+			return errors.New("code policies must have a name and a query")
+		}
+		if _, ok := codePolicies[name]; ok {
+			return errors.Errorf("duplicate code policy %q", name)
+		}
+		codePolicies[name] = struct{}{}
+
+		if p.WebhookURL != nil && *p.WebhookURL != "" {
+			if parsed, err := url.Parse(*p.WebhookURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+				return errors.Errorf("invalid webhook URL %q of code policy %q", *p.WebhookURL, name)
+			}
+		}
+		if p.IntervalMinutes != nil && *p.IntervalMinutes < 1 {
+			return errors.Errorf("the intervalMinutes of code policy %q must be at least 1", name)
+		}
+	}
+
+	return nil
+}
+
+func isObject(value json.RawMessage) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(value), []byte("{"))
+}
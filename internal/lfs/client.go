@@ -0,0 +1,162 @@
+package lfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// mediaType is the media type of requests to and responses from the LFS batch API.
+const mediaType = "application/vnd.git-lfs+json"
+
+// ErrUnsupportedRemote is returned when fetching content for a repository whose
+// remote is not an HTTP(S) URL. SSH remotes need git-lfs-authenticate to obtain
+// credentials for the LFS server, which we do not support.
+var ErrUnsupportedRemote = errors.New("fetching LFS content is only supported for HTTP(S) remotes")
+
+// ErrTooLarge is returned when the content of a pointer is larger than the
+// maximum size it may be fetched with.
+var ErrTooLarge = errors.New("LFS content exceeds the maximum size")
+
+type batchRequest struct {
+	Operation string        `json:"operation"`
+	Transfers []string      `json:"transfers"`
+	Objects   []batchObject `json:"objects"`
+}
+
+type batchObject struct {
+	OID     string                 `json:"oid"`
+	Size    int64                  `json:"size"`
+	Actions map[string]batchAction `json:"actions,omitempty"`
+	Error   *batchError            `json:"error,omitempty"`
+}
+
+type batchAction struct {
+	Href   string            `json:"href"`
+	Header map[string]string `json:"header,omitempty"`
+}
+
+type batchError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type batchResponse struct {
+	Objects []batchObject `json:"objects"`
+}
+
+// Endpoint returns the URL of the LFS server of the repository with the given
+// remote URL, as git-lfs derives it when no lfs.url is configured.
+func Endpoint(remoteURL *url.URL) *url.URL {
+	u := *remoteURL
+	u.User = nil
+	if !strings.HasSuffix(u.Path, ".git") {
+		u.Path = strings.TrimSuffix(u.Path, "/") + ".git"
+	}
+	u.Path += "/info/lfs"
+	u.RawPath = ""
+	return &u
+}
+
+// Fetch fetches the content of the given pointer from the LFS server of the
+// repository with the given remote URL, using the credentials in the remote URL.
+// It returns ErrTooLarge if the content is larger than maxSize. The caller must
+// close the returned reader.
+func Fetch(ctx context.Context, doer httpcli.Doer, remoteURL *url.URL, p Pointer, maxSize int64) (io.ReadCloser, error) {
+	if remoteURL.Scheme != "http" && remoteURL.Scheme != "https" {
+		return nil, ErrUnsupportedRemote
+	}
+	if p.Size > maxSize {
+		return nil, ErrTooLarge
+	}
+
+	action, err := batchDownload(ctx, doer, remoteURL, p)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, action.Href, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range action.Header {
+		req.Header.Set(k, v)
+	}
+	resp, err := doer.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "downloading LFS object")
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Errorf("downloading LFS object: unexpected status %d", resp.StatusCode)
+	}
+	if resp.ContentLength > maxSize {
+		resp.Body.Close()
+		return nil, ErrTooLarge
+	}
+
+	// The size of the pointer is only what the committer claimed, so we limit
+	// the content to it in case the server returns more than that.
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(resp.Body, p.Size), resp.Body}, nil
+}
+
+// batchDownload requests the download action of the pointer from the batch API
+// of the LFS server.
+func batchDownload(ctx context.Context, doer httpcli.Doer, remoteURL *url.URL, p Pointer) (*batchAction, error) {
+	body, err := json.Marshal(batchRequest{
+		Operation: "download",
+		Transfers: []string{"basic"},
+		Objects:   []batchObject{{OID: p.OID, Size: p.Size}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := Endpoint(remoteURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String()+"/objects/batch", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", mediaType)
+	req.Header.Set("Content-Type", mediaType)
+	if remoteURL.User != nil {
+		password, _ := remoteURL.User.Password()
+		req.SetBasicAuth(remoteURL.User.Username(), password)
+	}
+
+	resp, err := doer.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "requesting LFS batch API")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("requesting LFS batch API: unexpected status %d", resp.StatusCode)
+	}
+
+	var res batchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, errors.Wrap(err, "decoding LFS batch API response")
+	}
+	for _, obj := range res.Objects {
+		if obj.OID != p.OID {
+			continue
+		}
+		if obj.Error != nil {
+			return nil, errors.Errorf("LFS object %s: %s (code %d)", p.OID, obj.Error.Message, obj.Error.Code)
+		}
+		if action, ok := obj.Actions["download"]; ok {
+			return &action, nil
+		}
+	}
+	return nil, errors.Errorf("LFS batch API returned no download action for object %s", p.OID)
+}
@@ -0,0 +1,23 @@
+package lfs
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// defaultMaxContentSize is the maximum size of LFS content that is fetched if
+// none is configured.
+const defaultMaxContentSize = 10 * 1024 * 1024
+
+// FetchContentEnabled returns whether the content of LFS files should be fetched
+// from the LFS server of their code host, and the maximum size of content that is
+// fetched.
+func FetchContentEnabled() (maxSize int64, enabled bool) {
+	c := conf.Get().GitLFS
+	if c == nil || !c.FetchContent {
+		return 0, false
+	}
+	if c.MaxContentSize != nil {
+		return int64(*c.MaxContentSize), true
+	}
+	return defaultMaxContentSize, true
+}
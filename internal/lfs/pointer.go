@@ -0,0 +1,63 @@
+// Package lfs implements support for files stored in Git Large File Storage
+// (LFS): parsing the pointers that are committed in their place, and fetching
+// their content from the LFS server of the code host.
+package lfs
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+)
+
+var (
+	// oid sha256:d4653571a605ece26e88b83cfcfa2697968ee4b8e97ecf37c9d2715e5f94f5ac
+	oidRe = lazyregexp.New(`(?m)^oid sha256:([0-9a-f]{64})$`)
+	// size 902
+	sizeRe = lazyregexp.New(`(?m)^size (\d+)$`)
+)
+
+// PointerSizeCutoff is the size from which a blob is never a pointer. It is the
+// same size used by git-lfs to determine if it is worth parsing a file as a
+// pointer.
+const PointerSizeCutoff = 1024
+
+// Pointer is the pointer that is committed in place of a file stored in LFS.
+type Pointer struct {
+	// OID is the SHA-256 hash of the content of the file.
+	OID string
+	// Size is the size of the content of the file in bytes.
+	Size int64
+}
+
+// ParsePointer parses the content of a blob as an LFS pointer. It returns nil if
+// the blob is not a pointer.
+func ParsePointer(b string) *Pointer {
+	if len(b) >= PointerSizeCutoff {
+		return nil
+	}
+
+	if !strings.HasPrefix(b, "version https://git-lfs.github.com/spec/v1") {
+		return nil
+	}
+
+	oid := oidRe.FindStringSubmatch(b)
+	if len(oid) < 2 {
+		return nil
+	}
+
+	match := sizeRe.FindStringSubmatch(b)
+	if len(match) < 2 {
+		return nil
+	}
+
+	size, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	return &Pointer{
+		OID:  oid[1],
+		Size: size,
+	}
+}
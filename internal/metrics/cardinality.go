@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// OverflowLabelValue is recorded in place of a label value that is rejected by a label
+// allowlist or that would exceed the cardinality limit of a label.
+const OverflowLabelValue = "_other"
+
+var rejectedLabelValues = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "src",
+	Name:      "metrics_rejected_label_values_total",
+	Help:      "Total number of metric label values replaced due to an allowlist or cardinality limit.",
+}, []string{"label"})
+
+func init() {
+	prometheus.MustRegister(rejectedLabelValues)
+}
+
+// labelGuard validates label values before they are used to select a metric series. It
+// protects against a call site accidentally supplying an unbounded set of values (such as
+// repository names or user IDs) for a label that is meant to have a small, fixed set.
+type labelGuard struct {
+	labels     []string
+	allowlists []map[string]struct{}
+	limits     []int
+
+	mu   sync.Mutex
+	seen []map[string]struct{}
+}
+
+// newLabelGuard returns a guard for the given ordered labels. The cardinality limit of a
+// label in labelLimits takes precedence over maxCardinality. This returns nil if neither
+// an allowlist nor a cardinality limit applies to any label.
+func newLabelGuard(labels []string, allowlists map[string][]string, maxCardinality int, labelLimits map[string]int) *labelGuard {
+	if len(allowlists) == 0 && maxCardinality <= 0 && len(labelLimits) == 0 {
+		return nil
+	}
+
+	g := &labelGuard{
+		labels:     labels,
+		allowlists: make([]map[string]struct{}, len(labels)),
+		limits:     make([]int, len(labels)),
+		seen:       make([]map[string]struct{}, len(labels)),
+	}
+
+	for i, label := range labels {
+		if values, ok := allowlists[label]; ok {
+			allowed := make(map[string]struct{}, len(values))
+			for _, value := range values {
+				allowed[value] = struct{}{}
+			}
+			g.allowlists[i] = allowed
+		}
+
+		g.limits[i] = maxCardinality
+		if limit, ok := labelLimits[label]; ok {
+			g.limits[i] = limit
+		}
+
+		g.seen[i] = map[string]struct{}{}
+	}
+
+	return g
+}
+
+// sanitize returns the given label values with every rejected value replaced by
+// OverflowLabelValue. The input slice is not modified.
+func (g *labelGuard) sanitize(lvals []string) []string {
+	if g == nil {
+		return lvals
+	}
+
+	var sanitized []string
+	replace := func(i int) {
+		if sanitized == nil {
+			sanitized = make([]string, len(lvals))
+			copy(sanitized, lvals)
+		}
+		sanitized[i] = OverflowLabelValue
+		rejectedLabelValues.WithLabelValues(g.labels[i]).Inc()
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for i, value := range lvals {
+		// Mismatched label counts are reported by the underlying vector
+		if i >= len(g.labels) {
+			break
+		}
+
+		if allowed := g.allowlists[i]; allowed != nil {
+			if _, ok := allowed[value]; !ok {
+				replace(i)
+			}
+			continue
+		}
+
+		if g.limits[i] > 0 {
+			if _, ok := g.seen[i][value]; !ok {
+				if len(g.seen[i]) >= g.limits[i] {
+					replace(i)
+					continue
+				}
+				g.seen[i][value] = struct{}{}
+			}
+		}
+	}
+
+	if sanitized == nil {
+		return lvals
+	}
+	return sanitized
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestREDMetricsLabelAllowlist(t *testing.T) {
+	m := NewREDMetrics(prometheus.NewRegistry(), "test_allowlist",
+		WithLabels("op", "result"),
+		WithLabelAllowlist("result", "hit", "miss"),
+	)
+
+	for _, lvals := range [][]string{
+		{"Get", "hit"},
+		{"Get", "miss"},
+		{"Get", "github.com/sourcegraph/sourcegraph"},
+		{"Get", "github.com/sourcegraph/zoekt"},
+	} {
+		m.Observe(1, 1, nil, lvals...)
+	}
+
+	for _, testCase := range []struct {
+		result   string
+		expected float64
+	}{
+		{"hit", 1},
+		{"miss", 1},
+		{OverflowLabelValue, 2},
+	} {
+		if value := testutil.ToFloat64(m.Count.WithLabelValues("Get", testCase.result)); value != testCase.expected {
+			t.Errorf("unexpected count for %q. want=%v have=%v", testCase.result, testCase.expected, value)
+		}
+	}
+}
+
+func TestREDMetricsMaxLabelCardinality(t *testing.T) {
+	m := NewREDMetrics(prometheus.NewRegistry(), "test_cardinality",
+		WithLabels("op"),
+		WithMaxLabelCardinality(2),
+	)
+
+	for _, op := range []string{"a", "b", "a", "c", "d", "b"} {
+		m.Observe(1, 1, nil, op)
+	}
+
+	if count := testutil.CollectAndCount(m.Count); count != 3 {
+		t.Errorf("unexpected number of series. want=%d have=%d", 3, count)
+	}
+
+	for _, testCase := range []struct {
+		op       string
+		expected float64
+	}{
+		{"a", 2},
+		{"b", 2},
+		{OverflowLabelValue, 2},
+	} {
+		if value := testutil.ToFloat64(m.Count.WithLabelValues(testCase.op)); value != testCase.expected {
+			t.Errorf("unexpected count for %q. want=%v have=%v", testCase.op, testCase.expected, value)
+		}
+	}
+}
+
+func TestREDMetricsLabelCardinalityLimit(t *testing.T) {
+	m := NewREDMetrics(prometheus.NewRegistry(), "test_label_cardinality",
+		WithLabels("op", "actor"),
+		WithMaxLabelCardinality(10),
+		WithLabelCardinalityLimit("actor", 1),
+	)
+
+	for _, lvals := range [][]string{
+		{"a", "u1"},
+		{"b", "u1"},
+		{"c", "u2"},
+	} {
+		m.Observe(1, 1, nil, lvals...)
+	}
+
+	for _, testCase := range []struct {
+		op       string
+		actor    string
+		expected float64
+	}{
+		{"a", "u1", 1},
+		{"b", "u1", 1},
+		{"c", OverflowLabelValue, 1},
+	} {
+		if value := testutil.ToFloat64(m.Count.WithLabelValues(testCase.op, testCase.actor)); value != testCase.expected {
+			t.Errorf("unexpected count for %q/%q. want=%v have=%v", testCase.op, testCase.actor, testCase.expected, value)
+		}
+	}
+}
+
+func TestLabelGuardSanitizeDoesNotModifyInput(t *testing.T) {
+	g := newLabelGuard([]string{"op"}, map[string][]string{"op": {"a"}}, 0, nil)
+
+	lvals := []string{"b"}
+	if diff := cmp.Diff([]string{OverflowLabelValue}, g.sanitize(lvals)); diff != "" {
+		t.Errorf("unexpected label values (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"b"}, lvals); diff != "" {
+		t.Errorf("unexpected input label values (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDExemplarLabel is the exemplar label holding the ID of the trace an observation
+// belongs to. Grafana links exemplars to traces through this label.
+const TraceIDExemplarLabel = "trace_id"
+
+// TraceExemplar returns exemplar labels referencing the trace of the span in the given
+// context. Nil is returned if the context carries no span, or if the span is not sampled
+// and would therefore not be found in the tracing backend.
+func TraceExemplar(ctx context.Context) prometheus.Labels {
+	if spanContext := oteltrace.SpanContextFromContext(ctx); spanContext.IsValid() && spanContext.IsSampled() {
+		return prometheus.Labels{TraceIDExemplarLabel: spanContext.TraceID().String()}
+	}
+
+	return nil
+}
+
+// ObserveWithExemplar records the given value on the given observer, attaching the given
+// exemplar if it is non-empty and the observer supports exemplars.
+func ObserveWithExemplar(observer prometheus.Observer, value float64, exemplar prometheus.Labels) {
+	if len(exemplar) != 0 {
+		if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok {
+			exemplarObserver.ObserveWithExemplar(value, exemplar)
+			return
+		}
+	}
+
+	observer.Observe(value)
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceExemplar(t *testing.T) {
+	if exemplar := TraceExemplar(context.Background()); exemplar != nil {
+		t.Errorf("unexpected exemplar without a span: %v", exemplar)
+	}
+
+	if exemplar := TraceExemplar(contextWithSpan(false)); exemplar != nil {
+		t.Errorf("unexpected exemplar for an unsampled span: %v", exemplar)
+	}
+
+	expected := prometheus.Labels{TraceIDExemplarLabel: testTraceID.String()}
+	if diff := cmp.Diff(expected, TraceExemplar(contextWithSpan(true))); diff != "" {
+		t.Errorf("unexpected exemplar (-want +got):\n%s", diff)
+	}
+}
+
+func TestREDMetricsObserveWithExemplar(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := NewREDMetrics(registry, "test_exemplar",
+		WithLabels("op"),
+		WithDurationBuckets([]float64{1, 10}),
+	)
+
+	m.ObserveWithExemplar(0.5, 1, nil, TraceExemplar(contextWithSpan(true)), "a")
+	m.ObserveWithExemplar(5, 1, nil, nil, "a")
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error gathering metrics: %s", err)
+	}
+
+	var buckets []*dto.Bucket
+	for _, family := range families {
+		if family.GetName() == "src_test_exemplar_duration_seconds" {
+			buckets = family.GetMetric()[0].GetHistogram().GetBucket()
+		}
+	}
+	if len(buckets) != 2 {
+		t.Fatalf("unexpected number of buckets. want=%d have=%d", 2, len(buckets))
+	}
+
+	exemplar := buckets[0].GetExemplar()
+	if exemplar == nil {
+		t.Fatalf("expected an exemplar in the first bucket")
+	}
+	if len(exemplar.GetLabel()) != 1 || exemplar.GetLabel()[0].GetName() != TraceIDExemplarLabel || exemplar.GetLabel()[0].GetValue() != testTraceID.String() {
+		t.Errorf("unexpected exemplar labels: %v", exemplar.GetLabel())
+	}
+	if exemplar.GetValue() != 0.5 {
+		t.Errorf("unexpected exemplar value. want=%v have=%v", 0.5, exemplar.GetValue())
+	}
+
+	if exemplar := buckets[1].GetExemplar(); exemplar != nil {
+		t.Errorf("unexpected exemplar in the second bucket: %v", exemplar)
+	}
+}
+
+var testTraceID = oteltrace.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10}
+
+func contextWithSpan(sampled bool) context.Context {
+	var flags oteltrace.TraceFlags
+	if sampled {
+		flags = oteltrace.FlagsSampled
+	}
+
+	return oteltrace.ContextWithSpanContext(context.Background(), oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    testTraceID,
+		SpanID:     oteltrace.SpanID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		TraceFlags: flags,
+	}))
+}
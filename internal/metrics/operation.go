@@ -20,19 +20,30 @@ type REDMetrics struct {
 	Count    *prometheus.CounterVec   // How many things were processed?
 	Errors   *prometheus.CounterVec   // How many errors occurred?
 	Duration *prometheus.HistogramVec // How long did it take?
+
+	labelGuard *labelGuard
 }
 
 // Observe registers an observation of a single operation.
 func (m *REDMetrics) Observe(secs, count float64, err *error, lvals ...string) {
+	m.ObserveWithExemplar(secs, count, err, nil, lvals...)
+}
+
+// ObserveWithExemplar registers an observation of a single operation. The duration of a
+// successful operation is recorded with the given exemplar, which should identify a
+// representative trace of the operation (see TraceExemplar).
+func (m *REDMetrics) ObserveWithExemplar(secs, count float64, err *error, exemplar prometheus.Labels, lvals ...string) {
 	if m == nil {
 		return
 	}
 
+	lvals = m.labelGuard.sanitize(lvals)
+
 	if err != nil && *err != nil {
 		m.Errors.WithLabelValues(lvals...).Inc()
 		m.Count.WithLabelValues(lvals...).Add(0)
 	} else {
-		m.Duration.WithLabelValues(lvals...).Observe(secs)
+		ObserveWithExemplar(m.Duration.WithLabelValues(lvals...), secs, exemplar)
 		m.Count.WithLabelValues(lvals...).Add(count)
 	}
 }
@@ -44,6 +55,9 @@ type redMetricOptions struct {
 	errorsHelp      string
 	labels          []string
 	durationBuckets []float64
+	allowlists      map[string][]string
+	maxCardinality  int
+	labelLimits     map[string]int
 }
 
 // REDMetricsOption alter the default behavior of NewREDMetrics.
@@ -83,6 +97,37 @@ func WithLabels(labels ...string) REDMetricsOption {
 	return func(o *redMetricOptions) { o.labels = labels }
 }
 
+// WithLabelAllowlist restricts the values recorded for the given label to the given set.
+// Any other value is recorded as OverflowLabelValue. This should be used for labels whose
+// values are derived from call sites rather than from a fixed enumeration.
+func WithLabelAllowlist(label string, values ...string) REDMetricsOption {
+	return func(o *redMetricOptions) {
+		if o.allowlists == nil {
+			o.allowlists = map[string][]string{}
+		}
+		o.allowlists[label] = append(o.allowlists[label], values...)
+	}
+}
+
+// WithMaxLabelCardinality limits the number of distinct values recorded for each label.
+// Once a label has been observed with the given number of distinct values, new values are
+// recorded as OverflowLabelValue. A non-positive limit disables the check.
+func WithMaxLabelCardinality(limit int) REDMetricsOption {
+	return func(o *redMetricOptions) { o.maxCardinality = limit }
+}
+
+// WithLabelCardinalityLimit limits the number of distinct values recorded for the given
+// label, overriding WithMaxLabelCardinality for that label. A non-positive limit disables
+// the check for the label.
+func WithLabelCardinalityLimit(label string, limit int) REDMetricsOption {
+	return func(o *redMetricOptions) {
+		if o.labelLimits == nil {
+			o.labelLimits = map[string]int{}
+		}
+		o.labelLimits[label] = limit
+	}
+}
+
 // NewREDMetrics creates an REDMetrics value. The metrics will be
 // immediately registered to the given registerer. This method panics on registration
 // error. The supplied metricPrefix should be underscore_cased as it is used in the
@@ -136,9 +181,10 @@ func NewREDMetrics(r prometheus.Registerer, metricPrefix string, fns ...REDMetri
 	errors = MustRegisterIgnoreDuplicate(r, errors)
 
 	return &REDMetrics{
-		Duration: duration,
-		Count:    count,
-		Errors:   errors,
+		Duration:   duration,
+		Count:      count,
+		Errors:     errors,
+		labelGuard: newLabelGuard(options.labels, options.allowlists, options.maxCardinality, options.labelLimits),
 	}
 }
 
@@ -0,0 +1,71 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DigestInterval is how long notifications are collected before they are sent
+// in an email digest.
+const DigestInterval = 24 * time.Hour
+
+// SendDigests sends an email digest to every user whose oldest notification
+// pending for a digest is older than the digest interval.
+func (n *Notifier) SendDigests(ctx context.Context) error {
+	userIDs, err := n.db.Notifications().ListDigestUsers(ctx, time.Now().Add(-DigestInterval))
+	if err != nil {
+		return errors.Wrap(err, "listing users with pending digests")
+	}
+
+	for _, userID := range userIDs {
+		if err := n.sendDigest(ctx, userID); err != nil {
+			n.logger.Warn("failed to send notification digest", log.Int32("userID", userID), log.Error(err))
+		}
+	}
+	return nil
+}
+
+func (n *Notifier) sendDigest(ctx context.Context, userID int32) error {
+	pending, err := n.db.Notifications().ListDigestPending(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	data := digestTemplateData{SettingsURL: absoluteURL(settingsURL)}
+	ids := make([]int64, 0, len(pending))
+	for _, notification := range pending {
+		data.Notifications = append(data.Notifications, notificationTemplateData{
+			Title: notification.Title,
+			Body:  notification.Body,
+			URL:   absoluteURL(notification.URL),
+		})
+		ids = append(ids, notification.ID)
+	}
+
+	// The notifications of users without a verified email address are marked
+	// as digested all the same, so that they are not collected forever. Other
+	// failures are retried on the next run.
+	email, ok, err := n.primaryEmail(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if ok {
+		if err := n.sendEmail(ctx, txtypes.Message{
+			To:       []string{email},
+			Template: digestEmailTemplate,
+			Data:     data,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return n.db.Notifications().MarkDigested(ctx, ids)
+}
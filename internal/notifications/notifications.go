@@ -0,0 +1,229 @@
+// Package notifications delivers the notifications of Sourcegraph features to
+// users: in their notifications inbox, by email and on Slack, according to the
+// notification settings of each user.
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/api/internalapi"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/slack"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Notification is a notification to deliver.
+type Notification struct {
+	Kind  types.NotificationKind
+	Title string
+	Body  string
+	// URL is the URL of the subject of the notification, relative to the
+	// external URL, such as "/code-monitoring/...".
+	URL string
+}
+
+// Email delivery settings of a notification preference.
+const (
+	emailOff       = "off"
+	emailImmediate = "immediate"
+	emailDigest    = "digest"
+)
+
+// Notifier delivers notifications.
+type Notifier struct {
+	logger log.Logger
+	db     database.DB
+
+	sendEmail func(ctx context.Context, message txtypes.Message) error
+	postSlack func(ctx context.Context, webhookURL string, payload *slack.Payload) error
+}
+
+// NewNotifier returns a notifier delivering notifications with the given
+// database.
+func NewNotifier(logger log.Logger, db database.DB) *Notifier {
+	return &Notifier{
+		logger: logger.Scoped("notifications", "delivers notifications to users"),
+		db:     db,
+		sendEmail: func(ctx context.Context, message txtypes.Message) error {
+			return internalapi.Client.SendEmail(ctx, "notifications", message)
+		},
+		postSlack: func(ctx context.Context, webhookURL string, payload *slack.Payload) error {
+			return slack.New(webhookURL).Post(ctx, payload)
+		},
+	}
+}
+
+// Notify delivers the notification to the given users. Failures are logged
+// rather than returned, so that failing to notify users never fails the work
+// they are notified about.
+func (n *Notifier) Notify(ctx context.Context, notification Notification, userIDs ...int32) {
+	for _, userID := range userIDs {
+		if err := n.notify(ctx, notification, userID); err != nil {
+			n.logger.Warn("failed to notify user",
+				log.Int32("userID", userID),
+				log.String("kind", string(notification.Kind)),
+				log.Error(err),
+			)
+		}
+	}
+}
+
+// NotifySiteAdmins delivers the notification to all site admins.
+func (n *Notifier) NotifySiteAdmins(ctx context.Context, notification Notification) {
+	admins, err := n.db.Users().List(ctx, &database.UsersListOptions{SiteAdminsOnly: true})
+	if err != nil {
+		n.logger.Warn("failed to list site admins", log.Error(err))
+		return
+	}
+
+	userIDs := make([]int32, 0, len(admins))
+	for _, admin := range admins {
+		userIDs = append(userIDs, admin.ID)
+	}
+	n.Notify(ctx, notification, userIDs...)
+}
+
+func (n *Notifier) notify(ctx context.Context, notification Notification, userID int32) error {
+	settings, err := n.userSettings(ctx, userID)
+	if err != nil {
+		return errors.Wrap(err, "getting notification settings")
+	}
+	pref := preference(settings, notification.Kind)
+
+	if *pref.InApp || pref.Email == emailDigest {
+		if err := n.db.Notifications().Create(ctx, &types.Notification{
+			UserID:        userID,
+			Kind:          notification.Kind,
+			Title:         notification.Title,
+			Body:          notification.Body,
+			URL:           notification.URL,
+			InApp:         *pref.InApp,
+			DigestPending: pref.Email == emailDigest,
+		}); err != nil {
+			return errors.Wrap(err, "creating notification")
+		}
+	}
+
+	var errs error
+	if pref.Email == emailImmediate {
+		errs = errors.Append(errs, n.email(ctx, userID, notificationEmailTemplate, notificationTemplateData{
+			Title:       notification.Title,
+			Body:        notification.Body,
+			URL:         absoluteURL(notification.URL),
+			SettingsURL: absoluteURL(settingsURL),
+		}))
+	}
+	if pref.Slack && settings != nil && settings.SlackWebhookURL != "" {
+		errs = errors.Append(errs, n.postSlack(ctx, settings.SlackWebhookURL, &slack.Payload{
+			Text: slackText(notification),
+		}))
+	}
+	return errs
+}
+
+func (n *Notifier) userSettings(ctx context.Context, userID int32) (*schema.SettingsNotifications, error) {
+	settings, err := n.db.Settings().GetLatest(ctx, api.SettingsSubject{User: &userID})
+	if err != nil || settings == nil {
+		return nil, err
+	}
+
+	var userSettings schema.Settings
+	if err := jsonc.Unmarshal(settings.Contents, &userSettings); err != nil {
+		return nil, err
+	}
+	return userSettings.Notifications, nil
+}
+
+// preference returns the delivery preference of the user for the kind of
+// notification, with the documented defaults applied.
+func preference(settings *schema.SettingsNotifications, kind types.NotificationKind) schema.NotificationPreference {
+	var pref *schema.NotificationPreference
+	if settings != nil {
+		switch kind {
+		case types.NotificationKindCodeMonitor:
+			pref = settings.CodeMonitors
+		case types.NotificationKindBatchChange:
+			pref = settings.BatchChanges
+		case types.NotificationKindPermissionSync:
+			pref = settings.PermissionSync
+		case types.NotificationKindEmbeddingJob:
+			pref = settings.EmbeddingJobs
+		}
+	}
+
+	res := schema.NotificationPreference{}
+	if pref != nil {
+		res = *pref
+	}
+	if res.InApp == nil {
+		inApp := true
+		res.InApp = &inApp
+	}
+	if res.Email == "" {
+		res.Email = emailOff
+	}
+	return res
+}
+
+// email sends an email to the verified primary email address of the user.
+func (n *Notifier) email(ctx context.Context, userID int32, template txtypes.Templates, data any) error {
+	email, ok, err := n.primaryEmail(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("the user has no verified primary email address")
+	}
+
+	return n.sendEmail(ctx, txtypes.Message{
+		To:       []string{email},
+		Template: template,
+		Data:     data,
+	})
+}
+
+// primaryEmail returns the primary email address of the user, and whether it
+// exists and is verified.
+func (n *Notifier) primaryEmail(ctx context.Context, userID int32) (string, bool, error) {
+	email, verified, err := n.db.UserEmails().GetPrimaryEmail(ctx, userID)
+	if errcode.IsNotFound(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, errors.Wrap(err, "getting primary email address")
+	}
+	return email, verified, nil
+}
+
+const settingsURL = "/user/settings"
+
+func absoluteURL(path string) string {
+	externalURL, err := url.Parse(conf.ExternalURL())
+	if err != nil {
+		return path
+	}
+	ref, err := url.Parse(path)
+	if err != nil {
+		return path
+	}
+	return externalURL.ResolveReference(ref).String()
+}
+
+func slackText(notification Notification) string {
+	text := fmt.Sprintf("*<%s|%s>*", absoluteURL(notification.URL), notification.Title)
+	if notification.Body != "" {
+		text += "\n" + notification.Body
+	}
+	return text
+}
@@ -0,0 +1,105 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/slack"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestNotifier(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{ExternalURL: "https://sourcegraph.example.com"}})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	userSettings := map[int32]string{
+		// No settings: in-app only.
+		1: ``,
+		// Immediate email and Slack for code monitors.
+		2: `{"notifications": {"slackWebhookURL": "https://hooks.slack.com/x", "codeMonitors": {"inApp": false, "email": "immediate", "slack": true}}}`,
+		// Email digest for code monitors.
+		3: `{"notifications": {"codeMonitors": {"email": "digest"}}}`,
+	}
+
+	settings := database.NewMockSettingsStore()
+	settings.GetLatestFunc.SetDefaultHook(func(_ context.Context, subject api.SettingsSubject) (*api.Settings, error) {
+		contents := userSettings[*subject.User]
+		if contents == "" {
+			return nil, nil
+		}
+		return &api.Settings{Contents: contents}, nil
+	})
+	userEmails := database.NewMockUserEmailsStore()
+	userEmails.GetPrimaryEmailFunc.SetDefaultReturn("user@example.com", true, nil)
+	store := database.NewMockNotificationStore()
+	db := database.NewMockDB()
+	db.SettingsFunc.SetDefaultReturn(settings)
+	db.UserEmailsFunc.SetDefaultReturn(userEmails)
+	db.NotificationsFunc.SetDefaultReturn(store)
+
+	var emails []txtypes.Message
+	var slackPayloads []*slack.Payload
+	n := &Notifier{
+		logger: logtest.Scoped(t),
+		db:     db,
+		sendEmail: func(_ context.Context, message txtypes.Message) error {
+			emails = append(emails, message)
+			return nil
+		},
+		postSlack: func(_ context.Context, webhookURL string, payload *slack.Payload) error {
+			assert.Equal(t, "https://hooks.slack.com/x", webhookURL)
+			slackPayloads = append(slackPayloads, payload)
+			return nil
+		},
+	}
+
+	n.Notify(context.Background(), Notification{
+		Kind:  types.NotificationKindCodeMonitor,
+		Title: "Code monitor found new results",
+		URL:   "/code-monitoring/1",
+	}, 1, 2, 3)
+
+	history := store.CreateFunc.History()
+	require.Len(t, history, 2)
+	assert.Equal(t, int32(1), history[0].Arg1.UserID)
+	assert.True(t, history[0].Arg1.InApp)
+	assert.False(t, history[0].Arg1.DigestPending)
+	assert.Equal(t, int32(3), history[1].Arg1.UserID)
+	assert.True(t, history[1].Arg1.InApp)
+	assert.True(t, history[1].Arg1.DigestPending)
+
+	require.Len(t, emails, 1)
+	assert.Equal(t, []string{"user@example.com"}, emails[0].To)
+	assert.Equal(t, "https://sourcegraph.example.com/code-monitoring/1", emails[0].Data.(notificationTemplateData).URL)
+
+	require.Len(t, slackPayloads, 1)
+	assert.Equal(t, "*<https://sourcegraph.example.com/code-monitoring/1|Code monitor found new results>*", slackPayloads[0].Text)
+
+	t.Run("digests", func(t *testing.T) {
+		emails = nil
+		store.ListDigestUsersFunc.SetDefaultReturn([]int32{3}, nil)
+		store.ListDigestPendingFunc.SetDefaultReturn([]*types.Notification{
+			{ID: 1, Title: "First", URL: "/first"},
+			{ID: 2, Title: "Second", URL: "/second"},
+		}, nil)
+
+		require.NoError(t, n.SendDigests(context.Background()))
+
+		require.Len(t, emails, 1)
+		data := emails[0].Data.(digestTemplateData)
+		require.Len(t, data.Notifications, 2)
+		assert.Equal(t, "https://sourcegraph.example.com/first", data.Notifications[0].URL)
+
+		require.Len(t, store.MarkDigestedFunc.History(), 1)
+		assert.Equal(t, []int64{1, 2}, store.MarkDigestedFunc.History()[0].Arg1)
+	})
+}
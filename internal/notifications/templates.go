@@ -0,0 +1,58 @@
+package notifications
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+)
+
+type notificationTemplateData struct {
+	Title       string
+	Body        string
+	URL         string
+	SettingsURL string
+}
+
+var notificationEmailTemplate = txemail.MustValidate(txtypes.Templates{
+	Subject: `{{.Title}}`,
+	Text: `
+{{.Title}}
+{{if .Body}}
+{{.Body}}
+{{end}}
+{{.URL}}
+
+To change which notifications you receive by email, update the "notifications" setting in your user settings: {{.SettingsURL}}
+`,
+	HTML: `
+<p><a href="{{.URL}}"><strong>{{.Title}}</strong></a></p>
+{{if .Body}}<p>{{.Body}}</p>{{end}}
+
+<p><small>To change which notifications you receive by email, update the "notifications" setting in your <a href="{{.SettingsURL}}">user settings</a>.</small></p>
+`,
+})
+
+type digestTemplateData struct {
+	Notifications []notificationTemplateData
+	SettingsURL   string
+}
+
+var digestEmailTemplate = txemail.MustValidate(txtypes.Templates{
+	Subject: `Your Sourcegraph notifications ({{len .Notifications}})`,
+	Text: `
+Here are your Sourcegraph notifications since the last digest.
+{{range .Notifications}}
+- {{.Title}}{{if .Body}}: {{.Body}}{{end}}
+  {{.URL}}
+{{end}}
+To change which notifications you receive by email, update the "notifications" setting in your user settings: {{.SettingsURL}}
+`,
+	HTML: `
+<p>Here are your Sourcegraph notifications since the last digest.</p>
+
+<ul>
+{{range .Notifications}}<li><a href="{{.URL}}"><strong>{{.Title}}</strong></a>{{if .Body}}<br>{{.Body}}{{end}}</li>
+{{end}}</ul>
+
+<p><small>To change which notifications you receive by email, update the "notifications" setting in your <a href="{{.SettingsURL}}">user settings</a>.</small></p>
+`,
+})
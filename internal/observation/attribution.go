@@ -0,0 +1,108 @@
+package observation
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+)
+
+// actorAttributionLimit is the maximum number of distinct actors recorded by the actor
+// attribution of contexts created with NewContext. A non-positive value disables actor
+// attribution.
+var actorAttributionLimit, _ = strconv.Atoi(os.Getenv("SRC_OBSERVATION_ACTOR_ATTRIBUTION_LIMIT"))
+
+// actorAttributionSalt is the key used to anonymize the actors recorded by the actor
+// attribution of contexts created with NewContext. It should be shared by all replicas of
+// a service so that they record the same value for the same actor.
+var actorAttributionSalt = os.Getenv("SRC_OBSERVATION_ACTOR_ATTRIBUTION_SALT")
+
+var (
+	defaultActorAttributionOnce sync.Once
+	defaultActorAttribution     *ActorAttribution
+)
+
+// getDefaultActorAttribution returns the actor attribution shared by all contexts created
+// with NewContext, so that the actor cardinality limit applies to the process as a whole.
+// This returns nil if actor attribution is disabled.
+func getDefaultActorAttribution() *ActorAttribution {
+	if actorAttributionLimit <= 0 {
+		return nil
+	}
+
+	defaultActorAttributionOnce.Do(func() {
+		defaultActorAttribution = NewActorAttribution(prometheus.DefaultRegisterer, actorAttributionSalt, actorAttributionLimit)
+	})
+	return defaultActorAttribution
+}
+
+// ActorAttribution records the number and duration of invocations of operations created
+// with Op.AttributeActors, labelled by operation and by an anonymized actor. This answers
+// which users are generating the load on an operation without revealing who they are.
+type ActorAttribution struct {
+	metrics *metrics.REDMetrics
+	salt    []byte
+}
+
+// NewActorAttribution creates an ActorAttribution whose metrics are registered with the
+// given registerer. Users are recorded as the truncated HMAC of their identifier keyed by
+// the given salt. An empty salt is replaced by a random one, in which case the recorded
+// values change whenever the process restarts. At most maxActors distinct actor values,
+// including "internal", "anonymous", and "none", are recorded; all others are recorded as
+// metrics.OverflowLabelValue.
+func NewActorAttribution(r prometheus.Registerer, salt string, maxActors int) *ActorAttribution {
+	key := []byte(salt)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		_, _ = rand.Read(key)
+	}
+	if maxActors <= 0 {
+		maxActors = 1
+	}
+
+	return &ActorAttribution{
+		metrics: metrics.NewREDMetrics(r, "observation_actor",
+			metrics.WithLabels("op", "actor"),
+			metrics.WithLabelCardinalityLimit("actor", maxActors),
+			metrics.WithDurationHelp("Time in seconds spent performing successful operations, by anonymized actor."),
+			metrics.WithCountHelp("Total number of successful operations, by anonymized actor."),
+			metrics.WithErrorsHelp("Total number of operations resulting in an unexpected error, by anonymized actor."),
+		),
+		salt: key,
+	}
+}
+
+// observe records a single invocation of the given operation by the actor of ctx.
+func (a *ActorAttribution) observe(ctx context.Context, opName string, secs, count float64, err *error) {
+	if a == nil {
+		return
+	}
+
+	a.metrics.Observe(secs, count, err, opName, a.actorLabel(actor.FromContext(ctx)))
+}
+
+// actorLabel returns the label value recorded for the given actor. Internal, anonymous,
+// and missing actors are recorded as such, and users by an anonymized identifier.
+func (a *ActorAttribution) actorLabel(act *actor.Actor) string {
+	switch {
+	case act.IsInternal():
+		return "internal"
+	case act.IsAuthenticated():
+		mac := hmac.New(sha256.New, a.salt)
+		_, _ = mac.Write([]byte(act.UIDString()))
+		return "u_" + hex.EncodeToString(mac.Sum(nil))[:12]
+	case act.AnonymousUID != "":
+		return "anonymous"
+	default:
+		return "none"
+	}
+}
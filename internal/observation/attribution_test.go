@@ -0,0 +1,83 @@
+package observation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestActorAttributionActorLabel(t *testing.T) {
+	a := NewActorAttribution(prometheus.NewRegistry(), "salt", 10)
+	b := NewActorAttribution(prometheus.NewRegistry(), "pepper", 10)
+
+	for _, testCase := range []struct {
+		actor    *actor.Actor
+		expected string
+	}{
+		{actor: &actor.Actor{}, expected: "none"},
+		{actor: &actor.Actor{Internal: true}, expected: "internal"},
+		{actor: actor.FromAnonymousUser("c0ffee"), expected: "anonymous"},
+	} {
+		if label := a.actorLabel(testCase.actor); label != testCase.expected {
+			t.Errorf("unexpected label. want=%q have=%q", testCase.expected, label)
+		}
+	}
+
+	user := a.actorLabel(actor.FromUser(42))
+	if !strings.HasPrefix(user, "u_") || len(user) != 14 || strings.Contains(user, "42") {
+		t.Errorf("unexpected user label %q", user)
+	}
+	if other := a.actorLabel(actor.FromUser(42)); other != user {
+		t.Errorf("expected stable user label. want=%q have=%q", user, other)
+	}
+	if other := a.actorLabel(actor.FromUser(43)); other == user {
+		t.Errorf("expected distinct users to have distinct labels")
+	}
+	if other := b.actorLabel(actor.FromUser(42)); other == user {
+		t.Errorf("expected label to depend on the salt")
+	}
+}
+
+func TestActorAttribution(t *testing.T) {
+	attribution := NewActorAttribution(prometheus.NewRegistry(), "salt", 2)
+	observationCtx := TestContextTB(t).Clone(AttributeActors(attribution))
+
+	op := observationCtx.Operation(Op{Name: "Test.Attributed", AttributeActors: true})
+	unattributedOp := observationCtx.Operation(Op{Name: "Test.Unattributed"})
+
+	observe := func(op *Operation, a *actor.Actor, err error) {
+		_, _, endObservation := op.With(actor.WithActor(context.Background(), a), &err, Args{})
+		endObservation(1, Args{})
+	}
+	observe(op, actor.FromUser(1), nil)
+	observe(op, actor.FromUser(1), errors.New("oops"))
+	observe(op, actor.FromUser(2), nil)
+	observe(op, actor.FromUser(3), nil)
+	observe(unattributedOp, actor.FromUser(1), nil)
+
+	user1 := attribution.actorLabel(actor.FromUser(1))
+	user2 := attribution.actorLabel(actor.FromUser(2))
+
+	for _, testCase := range []struct {
+		collector prometheus.Collector
+		actor     string
+		expected  float64
+	}{
+		{attribution.metrics.Count.WithLabelValues("Test.Attributed", user1), user1, 1},
+		{attribution.metrics.Errors.WithLabelValues("Test.Attributed", user1), user1, 1},
+		{attribution.metrics.Count.WithLabelValues("Test.Attributed", user2), user2, 1},
+		{attribution.metrics.Count.WithLabelValues("Test.Attributed", metrics.OverflowLabelValue), metrics.OverflowLabelValue, 1},
+		{attribution.metrics.Count.WithLabelValues("Test.Unattributed", user1), user1, 0},
+	} {
+		if value := testutil.ToFloat64(testCase.collector); value != testCase.expected {
+			t.Errorf("unexpected value for %q. want=%v have=%v", testCase.actor, testCase.expected, value)
+		}
+	}
+}
@@ -23,14 +23,22 @@ type Context struct {
 	Tracer       *trace.Tracer
 	Registerer   prometheus.Registerer
 	HoneyDataset *honey.Dataset
+	// WideEventSink receives the wide events of operations created with Op.WideEvents.
+	WideEventSink WideEventSink
+	// ActorAttribution records the invocations of operations created with
+	// Op.AttributeActors by actor.
+	ActorAttribution *ActorAttribution
 }
 
 func (c *Context) Clone(opts ...Opt) *Context {
 	c1 := &Context{
-		Logger:       c.Logger,
-		Tracer:       c.Tracer,
-		Registerer:   c.Registerer,
-		HoneyDataset: c.HoneyDataset,
+		Logger:        c.Logger,
+		Tracer:        c.Tracer,
+		Registerer:    c.Registerer,
+		HoneyDataset:  c.HoneyDataset,
+		WideEventSink: c.WideEventSink,
+
+		ActorAttribution: c.ActorAttribution,
 	}
 
 	for _, opt := range opts {
@@ -55,10 +63,13 @@ func TestContextTB(t testing.TB) *Context {
 // ContextWithLogger creates a live Context with the given logger instance.
 func ContextWithLogger(logger log.Logger, parent *Context) *Context {
 	return &Context{
-		Logger:       logger,
-		Tracer:       parent.Tracer,
-		Registerer:   parent.Registerer,
-		HoneyDataset: parent.HoneyDataset,
+		Logger:        logger,
+		Tracer:        parent.Tracer,
+		Registerer:    parent.Registerer,
+		HoneyDataset:  parent.HoneyDataset,
+		WideEventSink: parent.WideEventSink,
+
+		ActorAttribution: parent.ActorAttribution,
 	}
 }
 
@@ -81,6 +92,20 @@ func (c *Context) Operation(args Op) *Operation {
 		// Create a new logger.
 		logger = log.Scoped(args.Name, args.Description)
 	}
+
+	var wideEvents WideEventSink
+	if args.WideEvents {
+		wideEvents = c.WideEventSink
+		if wideEvents == nil {
+			wideEvents = NewLogWideEventSink(logger)
+		}
+	}
+
+	var actorAttribution *ActorAttribution
+	if args.AttributeActors {
+		actorAttribution = c.ActorAttribution
+	}
+
 	return &Operation{
 		context:      c,
 		metrics:      args.Metrics,
@@ -89,6 +114,11 @@ func (c *Context) Operation(args Op) *Operation {
 		metricLabels: args.MetricLabelValues,
 		attributes:   args.Attrs,
 		errorFilter:  args.ErrorFilter,
+		sampleRate:   args.SampleRate,
+		attrGuard:    newAttributeGuard(args.AttributeCardinalityLimit),
+		wideEvents:   wideEvents,
+
+		actorAttribution: actorAttribution,
 
 		Logger: logger.With(attributesToLogFields(args.Attrs)...),
 	}
@@ -99,6 +129,8 @@ func NewContext(logger log.Logger, opts ...Opt) *Context {
 		Logger:     logger,
 		Tracer:     &trace.Tracer{TracerProvider: otel.GetTracerProvider()},
 		Registerer: prometheus.DefaultRegisterer,
+
+		ActorAttribution: getDefaultActorAttribution(),
 	}
 
 	for _, opt := range opts {
@@ -127,3 +159,15 @@ func Honeycomb(dataset *honey.Dataset) Opt {
 		ctx.HoneyDataset = dataset
 	}
 }
+
+func WideEvents(sink WideEventSink) Opt {
+	return func(ctx *Context) {
+		ctx.WideEventSink = sink
+	}
+}
+
+func AttributeActors(attribution *ActorAttribution) Opt {
+	return func(ctx *Context) {
+		ctx.ActorAttribution = attribution
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/sourcegraph/log"
 	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/sourcegraph/sourcegraph/internal/debugbundle"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/hostname"
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
@@ -66,6 +67,29 @@ type Op struct {
 	// an unexpected value in metrics and traces but should be handled higher up in
 	// the stack.
 	ErrorFilter func(err error) ErrorFilterBehaviour
+	// SampleRate is the fraction of invocations, in the range (0, 1], for which a trace
+	// span and Honeycomb event are emitted. Operations invoked within an invocation that
+	// was not sampled emit neither, so that sampled traces are never fragmented. Metrics
+	// and error logs are emitted for every invocation regardless of sampling. A zero value
+	// emits traces for every invocation.
+	SampleRate float64
+	// AttributeCardinalityLimit is the maximum number of distinct values recorded on trace
+	// spans and Honeycomb events for each attribute key supplied to this operation via Args.
+	// Once the limit for a key is reached, new values are replaced with
+	// metrics.OverflowLabelValue. Logs always receive the original values. A zero value
+	// disables the limit.
+	AttributeCardinalityLimit int
+	// WideEvents emits a single WideEvent describing each invocation of this operation,
+	// including all of its attributes, its duration, its error, and its actor, to the
+	// WideEventSink of the observation context. Operations observed by a context without
+	// a sink write each event as an "operation.event" log line instead. Wide events are
+	// emitted for every invocation regardless of sampling, and are subject to the
+	// EmitForLogs error filter.
+	WideEvents bool
+	// AttributeActors records the count and duration of each invocation of this operation
+	// labelled by the anonymized actor of the invocation, using the ActorAttribution of
+	// the observation context. This does nothing if the context has no ActorAttribution.
+	AttributeActors bool
 }
 
 // Operation represents an interesting section of code that can be invoked. It has an
@@ -78,6 +102,11 @@ type Operation struct {
 	kebabName    string
 	metricLabels []string
 	attributes   []attribute.KeyValue
+	sampleRate   float64
+	attrGuard    *attributeGuard
+	wideEvents   WideEventSink
+
+	actorAttribution *ActorAttribution
 
 	// Logger is a logger scoped to this operation. Must not be nil.
 	log.Logger
@@ -245,11 +274,20 @@ func (op *Operation) WithErrorsAndLogger(ctx context.Context, root *error, args
 func (op *Operation) With(ctx context.Context, err *error, args Args) (context.Context, TraceLogger, FinishFunc) {
 	parentTraceContext := trace.Context(ctx)
 	start := time.Now()
-	tr, ctx := op.startTrace(ctx)
+
+	ctx = context.WithValue(ctx, operationNameContextKey{}, op.name)
+
+	var tr *trace.Trace
+	sampled := op.sampled(ctx)
+	if sampled {
+		tr, ctx = op.startTrace(ctx)
+	} else {
+		ctx = withUnsampled(ctx)
+	}
 
 	event := honey.NoopEvent()
 	snakecaseOpName := toSnakeCase(op.name)
-	if op.context.HoneyDataset != nil {
+	if sampled && op.context.HoneyDataset != nil {
 		event = op.context.HoneyDataset.EventWithFields(map[string]any{
 			"operation":     snakecaseOpName,
 			"meta.hostname": hostname.Get(),
@@ -267,6 +305,7 @@ func (op *Operation) With(ctx context.Context, err *error, args Args) (context.C
 		}
 		logger = logger.WithTrace(traceContext)
 	}
+	logger = debugbundle.Logger(ctx, logger)
 
 	trLogger := &traceLogger{
 		context: op.context,
@@ -276,7 +315,7 @@ func (op *Operation) With(ctx context.Context, err *error, args Args) (context.C
 		Logger:  logger,
 	}
 
-	if mergedFields := mergeAttrs(op.attributes, args.Attrs); len(mergedFields) > 0 {
+	if mergedFields := mergeAttrs(op.attributes, op.attrGuard.apply(args.Attrs)); len(mergedFields) > 0 {
 		trLogger.initWithTags(mergedFields...)
 	}
 
@@ -286,13 +325,19 @@ func (op *Operation) With(ctx context.Context, err *error, args Args) (context.C
 		elapsedMs := since.Milliseconds()
 		defaultFinishFields := []attribute.KeyValue{attribute.Float64("count", count), attribute.Float64("elapsed", elapsed)}
 		finishAttrs := mergeAttrs(defaultFinishFields, finishArgs.Attrs)
+		// Only span and Honeycomb attributes are subject to the cardinality limit
+		guardedAttrs := op.attrGuard.apply(finishArgs.Attrs)
+		traceAttrs := mergeAttrs(defaultFinishFields, guardedAttrs)
 		metricLabels := mergeLabels(op.metricLabels, args.MetricLabelValues, finishArgs.MetricLabelValues)
+		eventAttrs := mergeAttrs(op.attributes, args.Attrs, finishArgs.Attrs)
 
 		if multi := new(ErrCollector); err != nil && errors.As(*err, &multi) {
 			if multi.errs == nil {
 				err = nil
 			}
 			finishAttrs = append(finishAttrs, multi.extraAttrs...)
+			traceAttrs = append(traceAttrs, multi.extraAttrs...)
+			eventAttrs = append(eventAttrs, multi.extraAttrs...)
 		}
 
 		var (
@@ -307,14 +352,27 @@ func (op *Operation) With(ctx context.Context, err *error, args Args) (context.C
 		// already has all the other log fields
 		op.emitErrorLogs(trLogger, logErr, finishAttrs, emitToSentry)
 		// op. and args.LogFields already added at start
-		op.emitHoneyEvent(honeyErr, snakecaseOpName, event, finishArgs.Attrs, elapsedMs)
+		op.emitHoneyEvent(honeyErr, snakecaseOpName, event, guardedAttrs, elapsedMs)
 
-		op.emitMetrics(metricsErr, count, elapsed, metricLabels)
+		op.emitMetrics(ctx, metricsErr, count, elapsed, metricLabels)
+		op.actorAttribution.observe(ctx, op.name, elapsed, count, metricsErr)
 
-		op.finishTrace(traceErr, tr, finishAttrs)
+		op.emitWideEvent(ctx, logErr, start, since, count, eventAttrs)
+		op.recordDebugBundle(ctx, logErr, start, since)
+
+		op.finishTrace(traceErr, tr, traceAttrs)
 	}
 }
 
+type operationNameContextKey struct{}
+
+// OperationName returns the name of the innermost operation invoked with the given
+// context, or an empty string if the context is not part of an observed invocation.
+func OperationName(ctx context.Context) string {
+	name, _ := ctx.Value(operationNameContextKey{}).(string)
+	return name
+}
+
 // startTrace creates a new Trace object and returns the wrapped context. This returns
 // an unmodified context and a nil startTrace if no tracer was supplied on the observation context.
 func (op *Operation) startTrace(ctx context.Context) (*trace.Trace, context.Context) {
@@ -361,13 +419,35 @@ func (op *Operation) emitHoneyEvent(err *error, opName string, event honey.Event
 }
 
 // emitMetrics will emit observe the duration, operation/result, and error counter metrics
-// for this operation. This does nothing if no metric was supplied to the observation.
-func (op *Operation) emitMetrics(err *error, count, elapsed float64, labels []string) {
+// for this operation. The duration is recorded with an exemplar referencing the trace of
+// the operation, if it was sampled. This does nothing if no metric was supplied to the
+// observation.
+func (op *Operation) emitMetrics(ctx context.Context, err *error, count, elapsed float64, labels []string) {
 	if op.metrics == nil {
 		return
 	}
 
-	op.metrics.Observe(elapsed, count, err, labels...)
+	op.metrics.ObserveWithExemplar(elapsed, count, err, metrics.TraceExemplar(ctx), labels...)
+}
+
+// recordDebugBundle adds the invocation to the debug bundle being captured for the request
+// it is part of. This does nothing if no bundle is being captured.
+func (op *Operation) recordDebugBundle(ctx context.Context, err *error, start time.Time, elapsed time.Duration) {
+	recorder := debugbundle.FromContext(ctx)
+	if recorder == nil {
+		return
+	}
+
+	operation := debugbundle.Operation{
+		Name:     op.name,
+		Start:    start,
+		Duration: debugbundle.Duration(elapsed),
+		SpanID:   trace.Context(ctx).SpanID,
+	}
+	if err != nil && *err != nil {
+		operation.Error = (*err).Error()
+	}
+	recorder.RecordOperation(operation)
 }
 
 // finishTrace will set the error value, log additional fields supplied after the operation's
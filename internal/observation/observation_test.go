@@ -0,0 +1,27 @@
+package observation
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOperationName(t *testing.T) {
+	if name := OperationName(context.Background()); name != "" {
+		t.Errorf("unexpected operation name outside of an invocation: %q", name)
+	}
+
+	outer := TestContext.Operation(Op{Name: "Test.Outer"})
+	inner := TestContext.Operation(Op{Name: "Test.Inner"})
+
+	outerCtx, _, endOuter := outer.With(context.Background(), nil, Args{})
+	defer endOuter(1, Args{})
+	innerCtx, _, endInner := inner.With(outerCtx, nil, Args{})
+	defer endInner(1, Args{})
+
+	if name := OperationName(outerCtx); name != "Test.Outer" {
+		t.Errorf("unexpected operation name. want=%q have=%q", "Test.Outer", name)
+	}
+	if name := OperationName(innerCtx); name != "Test.Inner" {
+		t.Errorf("unexpected operation name. want=%q have=%q", "Test.Inner", name)
+	}
+}
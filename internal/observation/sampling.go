@@ -0,0 +1,100 @@
+package observation
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+)
+
+// randFloat64 is replaced in tests to make sampling decisions deterministic.
+var randFloat64 = rand.Float64
+
+type unsampledContextKey struct{}
+
+// withUnsampled marks the given context as belonging to an invocation that was not
+// sampled. Operations invoked with the returned context are not sampled either, as their
+// spans would otherwise be attached to an unrelated ancestor.
+func withUnsampled(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unsampledContextKey{}, true)
+}
+
+// sampled returns true if a trace span and Honeycomb event should be emitted for the
+// current invocation of this operation.
+func (op *Operation) sampled(ctx context.Context) bool {
+	if unsampled, _ := ctx.Value(unsampledContextKey{}).(bool); unsampled {
+		return false
+	}
+
+	if op.sampleRate <= 0 || op.sampleRate >= 1 {
+		return true
+	}
+
+	return randFloat64() < op.sampleRate
+}
+
+// attributeGuard limits the number of distinct values recorded for each attribute key
+// of a single operation.
+type attributeGuard struct {
+	limit int
+
+	mu   sync.Mutex
+	seen map[attribute.Key]map[string]struct{}
+}
+
+// newAttributeGuard returns a guard with the given limit. This returns nil if the limit
+// is not positive.
+func newAttributeGuard(limit int) *attributeGuard {
+	if limit <= 0 {
+		return nil
+	}
+
+	return &attributeGuard{
+		limit: limit,
+		seen:  map[attribute.Key]map[string]struct{}{},
+	}
+}
+
+// apply returns the given attributes with the value of every attribute exceeding the
+// cardinality limit of its key replaced by metrics.OverflowLabelValue. The input slice
+// is not modified.
+func (g *attributeGuard) apply(attrs []attribute.KeyValue) []attribute.KeyValue {
+	if g == nil || len(attrs) == 0 {
+		return attrs
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var guarded []attribute.KeyValue
+	for i, attr := range attrs {
+		values, ok := g.seen[attr.Key]
+		if !ok {
+			values = map[string]struct{}{}
+			g.seen[attr.Key] = values
+		}
+
+		value := attr.Value.Emit()
+		if _, ok := values[value]; ok {
+			continue
+		}
+		if len(values) < g.limit {
+			values[value] = struct{}{}
+			continue
+		}
+
+		if guarded == nil {
+			guarded = make([]attribute.KeyValue, len(attrs))
+			copy(guarded, attrs)
+		}
+		guarded[i] = attribute.String(string(attr.Key), metrics.OverflowLabelValue)
+	}
+
+	if guarded == nil {
+		return attrs
+	}
+	return guarded
+}
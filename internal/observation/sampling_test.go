@@ -0,0 +1,109 @@
+package observation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestOperationSampled(t *testing.T) {
+	old := randFloat64
+	t.Cleanup(func() { randFloat64 = old })
+
+	for _, testCase := range []struct {
+		sampleRate float64
+		roll       float64
+		expected   bool
+	}{
+		{sampleRate: 0, roll: 0.99, expected: true},
+		{sampleRate: 1, roll: 0.99, expected: true},
+		{sampleRate: 0.25, roll: 0.1, expected: true},
+		{sampleRate: 0.25, roll: 0.25, expected: false},
+		{sampleRate: 0.25, roll: 0.9, expected: false},
+	} {
+		randFloat64 = func() float64 { return testCase.roll }
+
+		op := TestContext.Operation(Op{Name: "Test.Sampled", SampleRate: testCase.sampleRate})
+		if sampled := op.sampled(context.Background()); sampled != testCase.expected {
+			t.Errorf("unexpected sampling decision for rate=%v roll=%v. want=%v have=%v", testCase.sampleRate, testCase.roll, testCase.expected, sampled)
+		}
+	}
+}
+
+func TestOperationSampledPropagatesToDescendants(t *testing.T) {
+	old := randFloat64
+	t.Cleanup(func() { randFloat64 = old })
+	randFloat64 = func() float64 { return 0.9 }
+
+	parent := TestContext.Operation(Op{Name: "Test.Parent", SampleRate: 0.5})
+	child := TestContext.Operation(Op{Name: "Test.Child"})
+
+	ctx, _, endObservation := parent.With(context.Background(), nil, Args{})
+	defer endObservation(1, Args{})
+
+	if child.sampled(ctx) {
+		t.Errorf("expected descendant of unsampled operation to be unsampled")
+	}
+	if !child.sampled(context.Background()) {
+		t.Errorf("expected operation without a sample rate to be sampled")
+	}
+}
+
+func TestAttributeCardinalityLimitDoesNotApplyToLogs(t *testing.T) {
+	logger, exportLogs := logtest.Captured(t)
+	observationCtx := &Context{Logger: logger, Registerer: metrics.TestRegisterer}
+	op := observationCtx.Operation(Op{Name: "Test.Guarded", AttributeCardinalityLimit: 1})
+
+	for _, repo := range []string{"a", "b"} {
+		err := errors.New("oops")
+		_, _, endObservation := op.With(context.Background(), &err, Args{})
+		endObservation(1, Args{Attrs: []attribute.KeyValue{attribute.String("repo", repo)}})
+	}
+
+	var repos []any
+	for _, entry := range exportLogs() {
+		repos = append(repos, entry.Fields["repo"])
+	}
+	if diff := cmp.Diff([]any{"a", "b"}, repos); diff != "" {
+		t.Errorf("unexpected logged attribute values (-want +got):\n%s", diff)
+	}
+}
+
+func TestAttributeGuard(t *testing.T) {
+	g := newAttributeGuard(2)
+
+	var have [][]attribute.KeyValue
+	for _, repo := range []string{"a", "b", "a", "c"} {
+		have = append(have, g.apply([]attribute.KeyValue{
+			attribute.String("repo", repo),
+			attribute.Int("shard", 1),
+		}))
+	}
+
+	want := [][]attribute.KeyValue{
+		{attribute.String("repo", "a"), attribute.Int("shard", 1)},
+		{attribute.String("repo", "b"), attribute.Int("shard", 1)},
+		{attribute.String("repo", "a"), attribute.Int("shard", 1)},
+		{attribute.String("repo", metrics.OverflowLabelValue), attribute.Int("shard", 1)},
+	}
+	if diff := cmp.Diff(want, have, cmp.Comparer(func(a, b attribute.KeyValue) bool { return a == b })); diff != "" {
+		t.Errorf("unexpected attributes (-want +got):\n%s", diff)
+	}
+}
+
+func TestAttributeGuardDisabled(t *testing.T) {
+	if g := newAttributeGuard(0); g != nil {
+		t.Fatalf("expected nil guard")
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("repo", "a")}
+	if have := (*attributeGuard)(nil).apply(attrs); len(have) != 1 || have[0] != attrs[0] {
+		t.Errorf("unexpected attributes: %v", have)
+	}
+}
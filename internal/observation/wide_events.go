@@ -0,0 +1,143 @@
+package observation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sourcegraph/log"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// WideEvent describes a single invocation of an operation. It is emitted once the
+// invocation finishes, for operations created with Op.WideEvents set.
+type WideEvent struct {
+	// Operation is the name of the operation, e.g. Store.GetRepoByID.
+	Operation string
+	// Start is the time at which the invocation started.
+	Start time.Time
+	// Duration is the time taken by the invocation.
+	Duration time.Duration
+	// Count is the count supplied to the finish function of the invocation.
+	Count float64
+	// Error is the message of the error returned by the invocation, if any.
+	Error string
+	// ErrorClass is a low-cardinality description of the error returned by the
+	// invocation, if any. See errorClass.
+	ErrorClass string
+	// ActorType is one of "user", "internal", "anonymous", or "none".
+	ActorType string
+	// ActorUID is the identifier of the user the invocation was made on behalf of, if any.
+	ActorUID int32
+	// TraceID and SpanID identify the trace span of the invocation, if it was traced.
+	TraceID string
+	SpanID  string
+	// Attrs contains all attributes supplied to the operation, to the invocation, and
+	// to its finish function.
+	Attrs []attribute.KeyValue
+}
+
+// WideEventSink receives the wide events emitted by operations.
+type WideEventSink interface {
+	Emit(ctx context.Context, event WideEvent)
+}
+
+// WideEventSinkFunc adapts a function into a WideEventSink.
+type WideEventSinkFunc func(ctx context.Context, event WideEvent)
+
+func (f WideEventSinkFunc) Emit(ctx context.Context, event WideEvent) { f(ctx, event) }
+
+// NewLogWideEventSink returns a WideEventSink that writes each event as a single
+// "operation.event" log line to the given logger. This is the sink used for operations
+// observed by a Context without a WideEventSink.
+func NewLogWideEventSink(logger log.Logger) WideEventSink {
+	return &logWideEventSink{logger: logger}
+}
+
+type logWideEventSink struct {
+	logger log.Logger
+}
+
+func (s *logWideEventSink) Emit(_ context.Context, event WideEvent) {
+	fields := []log.Field{
+		log.String("operation", event.Operation),
+		log.Time("start", event.Start),
+		log.Int64("duration_ms", event.Duration.Milliseconds()),
+		log.Float64("count", event.Count),
+		log.String("actor.type", event.ActorType),
+		log.Int32("actor.uid", event.ActorUID),
+	}
+	if event.Error != "" {
+		fields = append(fields, log.String("error", event.Error), log.String("error.class", event.ErrorClass))
+	}
+
+	logger := s.logger
+	if event.TraceID != "" {
+		logger = logger.WithTrace(log.TraceContext{TraceID: event.TraceID, SpanID: event.SpanID})
+	}
+	logger.Info("operation.event", append(fields, log.Object("attrs", attributesToLogFields(event.Attrs)...))...)
+}
+
+// emitWideEvent sends a wide event describing the finished invocation to the sink of
+// the operation. This does nothing if the operation does not emit wide events.
+func (op *Operation) emitWideEvent(ctx context.Context, err *error, start time.Time, elapsed time.Duration, count float64, attrs []attribute.KeyValue) {
+	if op.wideEvents == nil {
+		return
+	}
+
+	event := WideEvent{
+		Operation: op.name,
+		Start:     start,
+		Duration:  elapsed,
+		Count:     count,
+		Attrs:     attrs,
+	}
+	if err != nil && *err != nil {
+		event.Error = (*err).Error()
+		event.ErrorClass = errorClass(*err)
+	}
+
+	a := actor.FromContext(ctx)
+	switch {
+	case a.IsInternal():
+		event.ActorType = "internal"
+	case a.IsAuthenticated():
+		event.ActorType = "user"
+		event.ActorUID = a.UID
+	case a.AnonymousUID != "":
+		// The anonymous identifier is never recorded
+		event.ActorType = "anonymous"
+	default:
+		event.ActorType = "none"
+	}
+
+	if traceContext := trace.Context(ctx); traceContext.TraceID != "" {
+		event.TraceID = traceContext.TraceID
+		event.SpanID = traceContext.SpanID
+	}
+
+	op.wideEvents.Emit(ctx, event)
+}
+
+// errorClass returns a low-cardinality description of the given error suitable for
+// grouping wide events: "canceled" or "deadline_exceeded" for context errors, and the
+// type of the innermost wrapped error otherwise. Errors gathered by an ErrCollector are
+// classified by the errors it collected.
+func errorClass(err error) string {
+	if multi := new(ErrCollector); errors.As(err, &multi) && multi.errs != nil {
+		err = multi.errs
+	}
+
+	switch {
+	case errors.IsDeadlineExceeded(err):
+		return "deadline_exceeded"
+	case errors.IsContextCanceled(err):
+		return "canceled"
+	}
+
+	return fmt.Sprintf("%T", errors.UnwrapAll(err))
+}
@@ -0,0 +1,123 @@
+package observation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+func TestWideEvents(t *testing.T) {
+	var events []WideEvent
+	observationCtx := TestContextTB(t).Clone(WideEvents(WideEventSinkFunc(func(ctx context.Context, event WideEvent) {
+		events = append(events, event)
+	})))
+
+	op := observationCtx.Operation(Op{
+		Name:       "Test.WideEvents",
+		Attrs:      []attribute.KeyValue{attribute.String("op", "a")},
+		WideEvents: true,
+	})
+	silentOp := observationCtx.Operation(Op{Name: "Test.Silent"})
+
+	ctx := actor.WithActor(context.Background(), actor.FromUser(42))
+	err := errors.Wrap(context.DeadlineExceeded, "fetching")
+	func() {
+		_, _, endObservation := op.With(ctx, &err, Args{Attrs: []attribute.KeyValue{attribute.Int("args", 1)}})
+		defer endObservation(3, Args{Attrs: []attribute.KeyValue{attribute.Bool("finish", true)}})
+	}()
+	func() {
+		_, _, endObservation := silentOp.With(ctx, &err, Args{})
+		defer endObservation(1, Args{})
+	}()
+
+	if len(events) != 1 {
+		t.Fatalf("unexpected number of events. want=%d have=%d", 1, len(events))
+	}
+	event := events[0]
+
+	if event.Operation != "Test.WideEvents" {
+		t.Errorf("unexpected operation. want=%q have=%q", "Test.WideEvents", event.Operation)
+	}
+	if event.Count != 3 {
+		t.Errorf("unexpected count. want=%v have=%v", 3, event.Count)
+	}
+	if event.Start.IsZero() || event.Duration < 0 {
+		t.Errorf("unexpected timing. start=%s duration=%s", event.Start, event.Duration)
+	}
+	if event.Error != "fetching: context deadline exceeded" || event.ErrorClass != "deadline_exceeded" {
+		t.Errorf("unexpected error. error=%q class=%q", event.Error, event.ErrorClass)
+	}
+	if event.ActorType != "user" || event.ActorUID != 42 {
+		t.Errorf("unexpected actor. type=%q uid=%d", event.ActorType, event.ActorUID)
+	}
+
+	expectedAttrs := []attribute.KeyValue{
+		attribute.String("op", "a"),
+		attribute.Int("args", 1),
+		attribute.Bool("finish", true),
+	}
+	if diff := cmp.Diff(expectedAttrs, event.Attrs, cmp.AllowUnexported(attribute.Value{})); diff != "" {
+		t.Errorf("unexpected attributes (-want +got):\n%s", diff)
+	}
+}
+
+func TestWideEventsLogSink(t *testing.T) {
+	logger, exportLogs := logtest.Captured(t)
+	observationCtx := &Context{Logger: logger, Registerer: metrics.TestRegisterer}
+	op := observationCtx.Operation(Op{Name: "Test.WideEvents", WideEvents: true})
+
+	ctx := actor.WithInternalActor(context.Background())
+	_, _, endObservation := op.With(ctx, nil, Args{Attrs: []attribute.KeyValue{attribute.String("repo", "github.com/sourcegraph/sourcegraph")}})
+	endObservation(1, Args{})
+
+	logs := exportLogs().Filter(func(l logtest.CapturedLog) bool { return l.Message == "operation.event" })
+	if len(logs) != 1 {
+		t.Fatalf("unexpected number of wide event logs. want=%d have=%d", 1, len(logs))
+	}
+	fields := logs[0].Fields
+	if fields["operation"] != "Test.WideEvents" {
+		t.Errorf("unexpected operation field: %v", fields["operation"])
+	}
+	if fields["actor.type"] != "internal" {
+		t.Errorf("unexpected actor type field: %v", fields["actor.type"])
+	}
+	if _, ok := fields["error"]; ok {
+		t.Errorf("unexpected error field: %v", fields["error"])
+	}
+	if attrs, ok := fields["attrs"].(map[string]any); !ok || attrs["repo"] != "github.com/sourcegraph/sourcegraph" {
+		t.Errorf("unexpected attrs field: %v", fields["attrs"])
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	collector := NewErrorCollector()
+	collector.Collect(pointers.Ptr(context.Canceled))
+
+	testCases := []struct {
+		err      error
+		expected string
+	}{
+		{err: context.Canceled, expected: "canceled"},
+		{err: errors.Wrap(context.DeadlineExceeded, "fetching"), expected: "deadline_exceeded"},
+		{err: collector, expected: "canceled"},
+		{err: errors.Wrap(&testError{}, "fetching"), expected: "*observation.testError"},
+	}
+
+	for _, testCase := range testCases {
+		if class := errorClass(testCase.err); class != testCase.expected {
+			t.Errorf("unexpected error class for %q. want=%q have=%q", testCase.err, testCase.expected, class)
+		}
+	}
+}
+
+type testError struct{}
+
+func (e *testError) Error() string { return "test" }
@@ -0,0 +1,114 @@
+// Package outbox delivers events recorded in the transactional outbox to
+// consumer groups.
+//
+// Events are published with database.OutboxEventStore in the same transaction
+// as the changes they describe, so that an event is published if and only if
+// the changes are committed. The relay then hands the events to each
+// registered consumer group in order.
+package outbox
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Consumer handles the events of a consumer group.
+type Consumer interface {
+	// Handle is called for each event in the order they were published. It is
+	// called within the transaction that advances the offset of the consumer
+	// group, so changes made through tx are applied exactly once. Side effects
+	// outside of the database are applied at least once, as the transaction
+	// may roll back after Handle returns.
+	//
+	// Returning an error aborts the batch, and the event is retried on the
+	// next run.
+	Handle(ctx context.Context, tx database.DB, event *types.OutboxEvent) error
+}
+
+// ConsumerFunc adapts a function to the Consumer interface.
+type ConsumerFunc func(ctx context.Context, tx database.DB, event *types.OutboxEvent) error
+
+func (f ConsumerFunc) Handle(ctx context.Context, tx database.DB, event *types.OutboxEvent) error {
+	return f(ctx, tx, event)
+}
+
+var registeredConsumers = struct {
+	sync.RWMutex
+	consumers map[string]Consumer
+}{consumers: map[string]Consumer{}}
+
+// RegisterConsumer registers the consumer of the given consumer group. A new
+// consumer group starts with the oldest event still in the outbox.
+func RegisterConsumer(consumerGroup string, consumer Consumer) {
+	registeredConsumers.Lock()
+	defer registeredConsumers.Unlock()
+
+	if _, ok := registeredConsumers.consumers[consumerGroup]; ok {
+		panic("outbox consumer group registered twice: " + consumerGroup)
+	}
+	registeredConsumers.consumers[consumerGroup] = consumer
+}
+
+// ConsumerGroups returns the names of the registered consumer groups, sorted.
+func ConsumerGroups() []string {
+	registeredConsumers.RLock()
+	defer registeredConsumers.RUnlock()
+
+	groups := make([]string, 0, len(registeredConsumers.consumers))
+	for group := range registeredConsumers.consumers {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// GetConsumer returns the consumer registered for the given consumer group.
+func GetConsumer(consumerGroup string) (Consumer, bool) {
+	registeredConsumers.RLock()
+	defer registeredConsumers.RUnlock()
+
+	consumer, ok := registeredConsumers.consumers[consumerGroup]
+	return consumer, ok
+}
+
+// Relay hands up to batchSize events after the offset of the given consumer
+// group to the consumer, and advances the offset past the handled events. It
+// returns the number of handled events. Concurrent calls for the same group do
+// not block: all but one of them return without handling any event.
+func Relay(ctx context.Context, db database.DB, consumerGroup string, consumer Consumer, batchSize int) (handled int, err error) {
+	err = db.WithTransact(ctx, func(tx database.DB) error {
+		store := tx.OutboxEvents()
+
+		offset, ok, err := store.LockConsumerGroup(ctx, consumerGroup)
+		if err != nil || !ok {
+			return errors.Wrap(err, "locking consumer group")
+		}
+
+		events, err := store.ListAfter(ctx, offset, batchSize)
+		if err != nil || len(events) == 0 {
+			return errors.Wrap(err, "listing events")
+		}
+
+		for _, event := range events {
+			if err := consumer.Handle(ctx, tx, event); err != nil {
+				return errors.Wrapf(err, "handling event %d (%s)", event.ID, event.EventType)
+			}
+		}
+
+		if err := store.AdvanceConsumerGroup(ctx, consumerGroup, database.OffsetOf(events[len(events)-1])); err != nil {
+			return errors.Wrap(err, "advancing consumer group")
+		}
+		handled = len(events)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return handled, nil
+}
@@ -0,0 +1,31 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestRegisterConsumer(t *testing.T) {
+	noop := ConsumerFunc(func(context.Context, database.DB, *types.OutboxEvent) error { return nil })
+
+	RegisterConsumer("test", noop)
+	t.Cleanup(func() {
+		registeredConsumers.Lock()
+		delete(registeredConsumers.consumers, "test")
+		registeredConsumers.Unlock()
+	})
+
+	assert.Equal(t, []string{OutboundWebhooksConsumerGroup, "test"}, ConsumerGroups())
+
+	_, ok := GetConsumer("test")
+	assert.True(t, ok)
+	_, ok = GetConsumer("unknown")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { RegisterConsumer("test", noop) })
+}
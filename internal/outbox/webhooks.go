@@ -0,0 +1,53 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/webhooks/outbound"
+)
+
+// OutboundWebhooksConsumerGroup is the consumer group that sends outbox events
+// to the outbound webhooks subscribed to them.
+const OutboundWebhooksConsumerGroup = "outbound-webhooks"
+
+func init() {
+	outbound.RegisterEventType(outbound.EventType{
+		Key:         types.OutboxEventAccessTokenUsageSpike,
+		Description: "sent when the hourly usage of an access token is far above its usual hourly usage",
+	})
+
+	outbound.RegisterEventType(outbound.EventType{
+		Key:         types.OutboxEventAuditLog,
+		Description: "sent when an event is recorded in the audit log, for example to stream it to a SIEM",
+	})
+
+	outbound.RegisterEventType(outbound.EventType{
+		Key:         types.OutboxEventRepoCreated,
+		Description: "sent when a repository is added from a code host connection",
+	})
+
+	outbound.RegisterEventType(outbound.EventType{
+		Key:         types.OutboxEventUploadProcessed,
+		Description: "sent when a precise code intelligence upload has been processed",
+	})
+
+	outbound.RegisterEventType(outbound.EventType{
+		Key:         types.OutboxEventUserCreated,
+		Description: "sent when a user is created",
+	})
+
+	outbound.RegisterEventType(outbound.EventType{
+		Key:         types.OutboxEventUserDeactivated,
+		Description: "sent when a user is deleted",
+	})
+
+	RegisterConsumer(OutboundWebhooksConsumerGroup, ConsumerFunc(enqueueOutboundWebhook))
+}
+
+// enqueueOutboundWebhook enqueues an outbound webhook job for the event. The
+// job is created within the relay transaction, so each event is enqueued once.
+func enqueueOutboundWebhook(ctx context.Context, tx database.DB, event *types.OutboxEvent) error {
+	return outbound.NewOutboundWebhookService(tx, nil).Enqueue(ctx, event.EventType, nil, event.Payload)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/own"
+	codeownerspb "github.com/sourcegraph/sourcegraph/internal/own/codeowners/v1"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -72,17 +73,22 @@ func (r *analyticsIndexer) indexRepo(ctx context.Context, repoId api.RepoID, che
 	if err != nil {
 		return errcode.MakeNonRetryable(errors.Wrapf(err, "cannot resolve HEAD"))
 	}
-	isOwnedViaCodeowners := r.codeowners(ctx, repo, commitID)
+	codeownersOwners := r.codeowners(ctx, repo, commitID)
 	isOwnedViaAssignedOwnership := r.assignedOwners(ctx, repo, commitID)
 	var totalCount int
 	var ownCounts database.PathAggregateCounts
+	individualCounts := map[string]int{}
 	for _, f := range files {
 		totalCount++
-		countCodeowners := isOwnedViaCodeowners(f)
+		owners := codeownersOwners(f)
+		countCodeowners := len(owners) > 0
 		countAssignedOwnership := isOwnedViaAssignedOwnership(f)
 		if countCodeowners {
 			ownCounts.CodeownedFileCount++
 		}
+		for _, o := range owners {
+			individualCounts[ownerReference(o)]++
+		}
 		if countAssignedOwnership {
 			ownCounts.AssignedOwnershipFileCount++
 		}
@@ -107,25 +113,58 @@ func (r *analyticsIndexer) indexRepo(ctx context.Context, repoId api.RepoID, che
 	if rowCount == 0 {
 		return errors.New("expected CODEOWNERS-owned file count update")
 	}
+	if err := r.updateIndividualCounts(ctx, repo.ID, individualCounts, timestamp); err != nil {
+		return err
+	}
 	ownAnalyticsFilesCounter.Add(float64(len(files)))
 	return nil
 }
 
-// codeowners pulls a path matcher for repo HEAD.
-// If result function is nil, then no CODEOWNERS file was found.
-func (r *analyticsIndexer) codeowners(ctx context.Context, repo *types.Repo, commitID api.CommitID) func(string) bool {
+// updateIndividualCounts persists the number of files owned by each CODEOWNERS
+// owner reference in the repository. References that no longer own any files
+// are reset to zero.
+func (r *analyticsIndexer) updateIndividualCounts(ctx context.Context, repoID api.RepoID, counts map[string]int, timestamp time.Time) error {
+	previous, err := r.db.OwnershipStats().QueryIndividualCounts(ctx, database.TreeLocationOpts{RepoID: repoID}, nil)
+	if err != nil {
+		return errors.Wrap(err, "QueryIndividualCounts")
+	}
+	update := make(rootIndividualCounts, 0, len(counts)+len(previous))
+	for reference, count := range counts {
+		update = append(update, database.PathCodeownersCounts{CodeownersReference: reference, CodeownedFileCount: count})
+	}
+	for _, p := range previous {
+		if _, ok := counts[p.CodeownersReference]; !ok && p.CodeownedFileCount > 0 {
+			update = append(update, database.PathCodeownersCounts{CodeownersReference: p.CodeownersReference})
+		}
+	}
+	if _, err := r.db.OwnershipStats().UpdateIndividualCounts(ctx, repoID, update, timestamp); err != nil {
+		return errors.Wrap(err, "UpdateIndividualCounts")
+	}
+	return nil
+}
+
+// codeowners pulls a path matcher for repo HEAD, which returns
+// the CODEOWNERS owners of a given path.
+func (r *analyticsIndexer) codeowners(ctx context.Context, repo *types.Repo, commitID api.CommitID) func(string) []*codeownerspb.Owner {
 	ownService := own.NewService(r.client, r.db)
 	ruleset, err := ownService.RulesetForRepo(ctx, repo.Name, repo.ID, commitID)
 	if ruleset == nil || err != nil {
 		// TODO(#53155): Return error in case there is an issue,
 		// but return noRuleset and no error if CODEOWNERS is not found.
-		return noOwners
+		return func(string) []*codeownerspb.Owner { return nil }
 	}
-	return func(path string) bool {
+	return func(path string) []*codeownerspb.Owner {
 		rule := ruleset.Match(path)
-		owners := rule.GetOwner()
-		return len(owners) > 0
+		return rule.GetOwner()
+	}
+}
+
+// ownerReference returns the text that refers to the owner in a CODEOWNERS file.
+func ownerReference(o *codeownerspb.Owner) string {
+	if h := o.GetHandle(); h != "" {
+		return "@" + h
 	}
+	return o.GetEmail()
 }
 
 func (r *analyticsIndexer) assignedOwners(ctx context.Context, repo *types.Repo, commitID api.CommitID) func(string) bool {
@@ -161,3 +200,15 @@ type rootPathIterator[T any] struct {
 func (i rootPathIterator[T]) Iterate(f func(path string, value T) error) error {
 	return f("", i.value)
 }
+
+// rootIndividualCounts are the individual owner counts for the repository root.
+type rootIndividualCounts []database.PathCodeownersCounts
+
+func (cs rootIndividualCounts) Iterate(f func(path string, counts database.PathCodeownersCounts) error) error {
+	for _, c := range cs {
+		if err := f("", c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -91,6 +91,20 @@ func TestAnalyticsIndexerSuccess(t *testing.T) {
 		UpdatedAt:                  defaultTime,
 	}
 	assert.Equal(t, wantCounts, gotCounts)
+
+	gotIndividualCounts, err := db.OwnershipStats().QueryIndividualCounts(ctx, database.TreeLocationOpts{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []database.PathCodeownersCounts{{CodeownersReference: "@owner", CodeownedFileCount: 3}}, gotIndividualCounts)
+
+	// Owners that no longer own files are reset on the next run.
+	client.fileContents["CODEOWNERS"] = "/owned/file1.go other@example.com"
+	require.NoError(t, newAnalyticsIndexer(client, db, rcache.New("test_own_signal"), logger).indexRepo(ctx, repoID, checker))
+	gotIndividualCounts, err = db.OwnershipStats().QueryIndividualCounts(ctx, database.TreeLocationOpts{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []database.PathCodeownersCounts{
+		{CodeownersReference: "other@example.com", CodeownedFileCount: 1},
+		{CodeownersReference: "@owner", CodeownedFileCount: 0},
+	}, gotIndividualCounts)
 }
 
 func TestAnalyticsIndexerSkipsReposWithSubRepoPerms(t *testing.T) {
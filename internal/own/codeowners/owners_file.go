@@ -0,0 +1,217 @@
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"sort"
+	"strings"
+
+	codeownerspb "github.com/sourcegraph/sourcegraph/internal/own/codeowners/v1"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// OwnersFileName is the name of Chromium and Gerrit style OWNERS files.
+const OwnersFileName = "OWNERS"
+
+// ParseOwnersFiles parses Chromium and Gerrit style OWNERS files, given by their
+// path relative to the repository root, and returns the proto representation
+// of the equivalent CODEOWNERS rules.
+//
+// Unlike a CODEOWNERS file, an OWNERS file applies to the directory it is in,
+// and the owners listed in the OWNERS files of parent directories are inherited
+// unless the file contains `set noparent`. The supported syntax is:
+//
+//	# Comment.
+//	alice@example.com
+//	@octo-org/octocats
+//	set noparent
+//	per-file *.md,*.txt=docs@example.com,@doctocat
+//
+// `file:` and `include` directives as well as the `*` wildcard owner are
+// ignored.
+func ParseOwnersFiles(files map[string][]byte) (*codeownerspb.File, error) {
+	parsed := make([]*ownersFile, 0, len(files))
+	byDir := make(map[string]*ownersFile, len(files))
+	for filePath, content := range files {
+		if path.Base(filePath) != OwnersFileName {
+			return nil, errors.Errorf("not an %s file: %s", OwnersFileName, filePath)
+		}
+		f, err := parseOwnersFile(content)
+		if err != nil {
+			return nil, errors.Wrap(err, filePath)
+		}
+		if f.dir = strings.TrimPrefix(path.Dir(filePath), "/"); f.dir == "." {
+			f.dir = ""
+		}
+		parsed = append(parsed, f)
+		byDir[f.dir] = f
+	}
+
+	// Parent directories need to be handled first, as their owners are
+	// inherited, and because later rules take precedence.
+	sort.Slice(parsed, func(i, j int) bool {
+		if di, dj := parsed[i].depth(), parsed[j].depth(); di != dj {
+			return di < dj
+		}
+		return parsed[i].dir < parsed[j].dir
+	})
+
+	var rs []*codeownerspb.Rule
+	for _, f := range parsed {
+		if !f.noparent {
+			if parent := nearestOwnersFile(byDir, f.dir); parent != nil {
+				f.owners = appendOwners(f.owners, parent.owners...)
+			}
+		}
+
+		pattern := "*"
+		if f.dir != "" {
+			pattern = "/" + f.dir + "/"
+		}
+		rs = append(rs, &codeownerspb.Rule{
+			Pattern:    pattern,
+			Owner:      toProtoOwners(f.owners),
+			LineNumber: f.lineNumber,
+		})
+
+		// Per-file owners are owners in addition to the owners of the directory.
+		for _, pf := range f.perFile {
+			owners := pf.owners
+			if !pf.noparent {
+				owners = appendOwners(owners, f.owners...)
+			}
+			for _, glob := range pf.globs {
+				rs = append(rs, &codeownerspb.Rule{
+					Pattern:    path.Join("/", f.dir, glob),
+					Owner:      toProtoOwners(owners),
+					LineNumber: pf.lineNumber,
+				})
+			}
+		}
+	}
+	return &codeownerspb.File{Rule: rs}, nil
+}
+
+// ownersFile is a single parsed OWNERS file.
+type ownersFile struct {
+	// dir is the directory of the file, "" for the repository root.
+	dir      string
+	owners   []string
+	noparent bool
+	perFile  []perFileOwners
+	// lineNumber is the line of the first owner, or 1 if there are no owners.
+	lineNumber int32
+}
+
+func (f *ownersFile) depth() int {
+	if f.dir == "" {
+		return 0
+	}
+	return strings.Count(f.dir, "/") + 1
+}
+
+type perFileOwners struct {
+	globs  []string
+	owners []string
+	// noparent is true if the directory owners do not own the files.
+	noparent   bool
+	lineNumber int32
+}
+
+func parseOwnersFile(content []byte) (*ownersFile, error) {
+	f := &ownersFile{lineNumber: 1}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	lineNumber := int32(0)
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if i := strings.IndexRune(line, commentStart); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "", line == "*":
+			continue
+		case strings.HasPrefix(line, "file:"), strings.HasPrefix(line, "include "):
+			// Including owners from other files is not supported.
+			continue
+		case strings.HasPrefix(line, "set "):
+			if strings.TrimSpace(strings.TrimPrefix(line, "set ")) != "noparent" {
+				return nil, errors.Errorf("line %d: unsupported directive: %s", lineNumber, line)
+			}
+			f.noparent = true
+		case strings.HasPrefix(line, "per-file "):
+			globs, owners, ok := strings.Cut(strings.TrimPrefix(line, "per-file "), "=")
+			if !ok {
+				return nil, errors.Errorf("line %d: per-file directive without owners: %s", lineNumber, line)
+			}
+			pf := perFileOwners{lineNumber: lineNumber}
+			for _, glob := range strings.Split(globs, ",") {
+				if glob = strings.TrimSpace(glob); glob != "" {
+					pf.globs = append(pf.globs, glob)
+				}
+			}
+			for _, owner := range strings.Split(owners, ",") {
+				switch owner = strings.TrimSpace(owner); {
+				case owner == "set noparent":
+					pf.noparent = true
+				case owner != "" && owner != "*" && !strings.HasPrefix(owner, "file:"):
+					pf.owners = appendOwners(pf.owners, owner)
+				}
+			}
+			f.perFile = append(f.perFile, pf)
+		default:
+			if len(f.owners) == 0 {
+				f.lineNumber = lineNumber
+			}
+			f.owners = appendOwners(f.owners, strings.Fields(line)...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// nearestOwnersFile returns the OWNERS file of the closest parent directory of
+// dir, or nil if there is none.
+func nearestOwnersFile(byDir map[string]*ownersFile, dir string) *ownersFile {
+	for dir != "" {
+		dir = path.Dir(dir)
+		if dir == "." {
+			dir = ""
+		}
+		if f, ok := byDir[dir]; ok {
+			return f
+		}
+	}
+	return nil
+}
+
+// appendOwners appends the owners not already in the list.
+func appendOwners(owners []string, more ...string) []string {
+	out := append([]string(nil), owners...)
+	for _, o := range more {
+		found := false
+		for _, existing := range out {
+			if existing == o {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+func toProtoOwners(owners []string) []*codeownerspb.Owner {
+	pbOwners := make([]*codeownerspb.Owner, 0, len(owners))
+	for _, o := range owners {
+		pbOwners = append(pbOwners, ParseOwner(o))
+	}
+	return pbOwners
+}
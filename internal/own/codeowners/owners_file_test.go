@@ -0,0 +1,89 @@
+package codeowners_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/own/codeowners"
+	codeownerspb "github.com/sourcegraph/sourcegraph/internal/own/codeowners/v1"
+)
+
+func TestParseOwnersFiles(t *testing.T) {
+	got, err := codeowners.ParseOwnersFiles(map[string][]byte{
+		"OWNERS": []byte(`# Owners of the whole repository.
+root@example.com
+@octo-org/octocats
+per-file *.md=docs@example.com
+`),
+		"src/OWNERS": []byte(`src@example.com
+include /build/OWNERS
+per-file BUILD.bazel=set noparent,@build-team
+`),
+		"src/third_party/OWNERS": []byte(`set noparent
+*
+vendor@example.com # Vendored code.
+`),
+	})
+	require.NoError(t, err)
+
+	want := &codeownerspb.File{
+		Rule: []*codeownerspb.Rule{
+			{
+				Pattern: "*",
+				Owner: []*codeownerspb.Owner{
+					{Email: "root@example.com"},
+					{Handle: "octo-org/octocats"},
+				},
+				LineNumber: 2,
+			},
+			{
+				Pattern: "/*.md",
+				Owner: []*codeownerspb.Owner{
+					{Email: "docs@example.com"},
+					{Email: "root@example.com"},
+					{Handle: "octo-org/octocats"},
+				},
+				LineNumber: 4,
+			},
+			{
+				Pattern: "/src/",
+				Owner: []*codeownerspb.Owner{
+					{Email: "src@example.com"},
+					{Email: "root@example.com"},
+					{Handle: "octo-org/octocats"},
+				},
+				LineNumber: 1,
+			},
+			{
+				Pattern:    "/src/BUILD.bazel",
+				Owner:      []*codeownerspb.Owner{{Handle: "build-team"}},
+				LineNumber: 3,
+			},
+			{
+				Pattern:    "/src/third_party/",
+				Owner:      []*codeownerspb.Owner{{Email: "vendor@example.com"}},
+				LineNumber: 3,
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+
+	ruleset := codeowners.NewRuleset(codeowners.GitRulesetSource{}, got)
+	assert.Equal(t, "/src/", ruleset.Match("src/main/main.go").GetPattern())
+	assert.Equal(t, "/*.md", ruleset.Match("README.md").GetPattern())
+	assert.Equal(t, "/src/", ruleset.Match("src/README.md").GetPattern())
+	assert.Equal(t, "/src/third_party/", ruleset.Match("src/third_party/lib/lib.go").GetPattern())
+}
+
+func TestParseOwnersFilesErrors(t *testing.T) {
+	_, err := codeowners.ParseOwnersFiles(map[string][]byte{"CODEOWNERS": nil})
+	require.ErrorContains(t, err, "not an OWNERS file")
+
+	_, err = codeowners.ParseOwnersFiles(map[string][]byte{"OWNERS": []byte("set foo")})
+	require.ErrorContains(t, err, "unsupported directive")
+
+	_, err = codeowners.ParseOwnersFiles(map[string][]byte{"OWNERS": []byte("per-file foo.go")})
+	require.ErrorContains(t, err, "per-file directive without owners")
+}
@@ -147,7 +147,8 @@ func EmptyBag() *bag {
 // ByTextReference returns a Bag of all the forms (users, persons, teams)
 // that can be referred to by given text (name or email alike).
 // This can be used in search to find relevant owners by different identifiers
-// that the database reveals.
+// that the database reveals. Teams are expanded: the bag also contains
+// the teams (and their parent teams) that resolved users are members of.
 // TODO(#52141): Search by code host handle.
 // TODO(#52246): ByTextReference uses fewer queries.
 func ByTextReference(ctx context.Context, db database.DB, text ...string) Bag {
@@ -164,6 +165,7 @@ func ByTextReference(ctx context.Context, db database.DB, text ...string) Bag {
 		}
 	}
 	b.Resolve(ctx, db)
+	b.expandTeams(ctx, db)
 	return b
 }
 
@@ -211,6 +213,13 @@ func (b bag) Contains(ref Reference) bool {
 			return true
 		}
 	}
+	// Code-host team handles can be namespaced by the organization,
+	// while the team is known by the team name only.
+	if name, ok := unqualifiedTeamName(ref.Handle); ok {
+		if refCtx, ok := b.references[refKey{handle: name}]; ok && refCtx.resolvedTeamID != 0 {
+			return true
+		}
+	}
 	return false
 }
 
@@ -345,6 +354,49 @@ func (b *bag) Resolve(ctx context.Context, db database.DB) {
 	}
 }
 
+// expandTeams adds the teams that resolved users are members of to the bag,
+// together with all their ancestor teams. This way files owned by a team
+// are considered owned by all the members of the team.
+func (b *bag) expandTeams(ctx context.Context, db database.DB) {
+	for userID, userRefs := range b.resolvedUsers {
+		teams, _, err := db.Teams().ListTeams(ctx, database.ListTeamsOpts{ForUserMember: userID})
+		if err != nil {
+			userRefs.appendErr(errors.Wrap(err, "Teams.ListTeams"))
+			continue
+		}
+		for _, team := range teams {
+			for team != nil {
+				if _, ok := b.resolvedTeams[team.ID]; ok {
+					// Team and its ancestors were already added.
+					break
+				}
+				teamRefs := &teamReferences{team: team}
+				b.resolvedTeams[team.ID] = teamRefs
+				teamRefs.linkBack(b)
+
+				if team.ParentTeamID == 0 {
+					break
+				}
+				team, err = findTeamByID(ctx, db, team.ParentTeamID)
+				if err != nil {
+					userRefs.appendErr(err)
+					break
+				}
+			}
+		}
+	}
+}
+
+// unqualifiedTeamName returns the team name without the organization or group
+// namespace of code-host team handles like "sourcegraph/own".
+func unqualifiedTeamName(handle string) (string, bool) {
+	i := strings.LastIndex(handle, "/")
+	if i < 0 || i == len(handle)-1 {
+		return "", false
+	}
+	return handle[i+1:], true
+}
+
 // userReferences represents all the references found for a given user in the database.
 // Every valid `userReferences` object has an `id`
 type userReferences struct {
@@ -584,6 +636,15 @@ func (k refKey) fetch(ctx context.Context, db database.DB) (*userReferences, *te
 		if t != nil {
 			return nil, &teamReferences{t}, nil
 		}
+		if name, ok := unqualifiedTeamName(k.handle); ok {
+			t, err := findTeamByName(ctx, db, name)
+			if err != nil {
+				return nil, nil, err
+			}
+			if t != nil {
+				return nil, &teamReferences{t}, nil
+			}
+		}
 	}
 	if k.email != "" {
 		u, err := findUserByEmail(ctx, db, k.email)
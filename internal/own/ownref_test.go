@@ -314,6 +314,60 @@ func TestBagRetrievesTeamsByName(t *testing.T) {
 	assert.True(t, bag.Contains(ref), "%s contains %s", bag, ref)
 }
 
+func TestBagExpandsTeams(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+	user, err := db.Users().Create(ctx, database.NewUser{
+		Email:           "john.doe@example.com",
+		Username:        "jdoe",
+		EmailIsVerified: true,
+	})
+	require.NoError(t, err)
+	parent, err := db.Teams().CreateTeam(ctx, &types.Team{Name: "search"})
+	require.NoError(t, err)
+	team, err := db.Teams().CreateTeam(ctx, &types.Team{Name: "search-core", ParentTeamID: parent.ID})
+	require.NoError(t, err)
+	other, err := db.Teams().CreateTeam(ctx, &types.Team{Name: "batches"})
+	require.NoError(t, err)
+	require.NoError(t, db.Teams().CreateTeamMember(ctx, &types.TeamMember{TeamID: team.ID, UserID: user.ID}))
+
+	bag := ByTextReference(ctx, db, "jdoe")
+	for _, ref := range []Reference{
+		{TeamID: team.ID},
+		{TeamID: parent.ID},
+		{Handle: "search-core"},
+		// CODEOWNERS handles of code-host teams are namespaced by organization.
+		{Handle: "@sourcegraph/search"},
+	} {
+		assert.True(t, bag.Contains(ref), "%s contains %s", bag, ref)
+	}
+	for _, ref := range []Reference{
+		{TeamID: other.ID},
+		{Handle: "batches"},
+		{Handle: "sourcegraph/batches"},
+	} {
+		assert.False(t, bag.Contains(ref), "%s does not contain %s", bag, ref)
+	}
+}
+
+func TestBagRetrievesNamespacedTeams(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+	team, err := db.Teams().CreateTeam(ctx, &types.Team{Name: "own"})
+	require.NoError(t, err)
+	bag := ByTextReference(ctx, db, "@sourcegraph/own")
+	ref := Reference{TeamID: team.ID}
+	assert.True(t, bag.Contains(ref), "%s contains %s", bag, ref)
+}
+
 func TestBagManyUsers(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
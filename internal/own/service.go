@@ -11,14 +11,16 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/own/codeowners"
 )
 
 // Service gives access to code ownership data.
-// At this point only data from CODEOWNERS file is presented, if available.
+// At this point only data from CODEOWNERS or OWNERS files is presented, if available.
 type Service interface {
 	// RulesetForRepo returns a CODEOWNERS file ruleset from a given repository at given commit ID.
 	// If a CODEOWNERS file has been manually ingested for the repository, it will prioritise returning that file.
+	// If there is no CODEOWNERS file, the rules are built from the OWNERS files in the repository, if any.
 	// In the case the file cannot be found, `nil` `*codeownerspb.File` and `nil` `error` is returned.
 	RulesetForRepo(context.Context, api.RepoName, api.RepoID, api.CommitID) (*codeowners.Ruleset, error)
 
@@ -134,6 +136,12 @@ func (s *service) RulesetForRepo(ctx context.Context, repoName api.RepoName, rep
 			return nil, err
 		}
 	}
+	if rs == nil {
+		rs, err = s.ownersFilesRuleset(ctx, repoName, repoID, commitID)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if rs == nil {
 		return nil, nil
 	}
@@ -147,6 +155,54 @@ func (s *service) RulesetForRepo(ctx context.Context, repoName api.RepoName, rep
 	return rs, nil
 }
 
+// ownersFilesRuleset returns a ruleset built from all the Chromium and Gerrit style
+// OWNERS files in the repository, or nil if there are none. It is only used if the
+// repository has no CODEOWNERS file.
+func (s *service) ownersFilesRuleset(ctx context.Context, repoName api.RepoName, repoID api.RepoID, commitID api.CommitID) (*codeowners.Ruleset, error) {
+	paths, err := s.gitserverClient.LsFiles(
+		ctx,
+		authz.DefaultSubRepoPermsChecker,
+		repoName,
+		commitID,
+		gitdomain.PathspecLiteral(codeowners.OwnersFileName),
+		gitdomain.PathspecSuffix("/"+codeowners.OwnersFileName),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte, len(paths))
+	for _, path := range paths {
+		if !strings.HasSuffix("/"+path, "/"+codeowners.OwnersFileName) {
+			continue
+		}
+		content, err := s.gitserverClient.ReadFile(ctx, authz.DefaultSubRepoPermsChecker, repoName, commitID, path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		files[path] = content
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	pbfile, err := codeowners.ParseOwnersFiles(files)
+	if err != nil {
+		return nil, err
+	}
+	// Rules reference the OWNERS file closest to the repository root.
+	var sourcePath string
+	for path := range files {
+		if sourcePath == "" || strings.Count(path, "/") < strings.Count(sourcePath, "/") ||
+			(strings.Count(path, "/") == strings.Count(sourcePath, "/") && path < sourcePath) {
+			sourcePath = path
+		}
+	}
+	return codeowners.NewRuleset(codeowners.GitRulesetSource{Repo: repoID, Commit: commitID, Path: sourcePath}, pbfile), nil
+}
+
 func (s *service) AssignedOwnership(ctx context.Context, repoID api.RepoID, _ api.CommitID) (AssignedOwners, error) {
 	summaries, err := s.db.AssignedOwners().ListAssignedOwnersForRepo(ctx, repoID)
 	if err != nil {
@@ -87,6 +87,31 @@ func TestOwnersServesFilesAtVariousLocations(t *testing.T) {
 	}
 }
 
+func TestOwnersFromOwnersFiles(t *testing.T) {
+	repo := repoFiles{
+		{"repo", "SHA", "OWNERS"}:     "root@example.com",
+		{"repo", "SHA", "src/OWNERS"}: "set noparent\n@src-team",
+	}
+	git := gitserver.NewMockClient()
+	git.ReadFileFunc.SetDefaultHook(repo.ReadFile)
+	git.LsFilesFunc.SetDefaultReturn([]string{"src/OWNERS", "OWNERS"}, nil)
+
+	reposStore := database.NewMockRepoStore()
+	reposStore.GetFunc.SetDefaultReturn(&types2.Repo{ExternalRepo: api.ExternalRepoSpec{ServiceType: "github"}}, nil)
+	codeownersStore := database.NewMockCodeownersStore()
+	codeownersStore.GetCodeownersForRepoFunc.SetDefaultReturn(nil, nil)
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(reposStore)
+	db.CodeownersFunc.SetDefaultReturn(codeownersStore)
+
+	got, err := NewService(git, db).RulesetForRepo(context.Background(), "repo", 1, "SHA")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, codeowners.GitRulesetSource{Repo: 1, Commit: "SHA", Path: "OWNERS"}, got.GetSource())
+	assert.Equal(t, []*codeownerspb.Owner{{Email: "root@example.com"}}, got.Match("README.md").GetOwner())
+	assert.Equal(t, []*codeownerspb.Owner{{Handle: "src-team"}}, got.Match("src/main.go").GetOwner())
+}
+
 func TestOwnersCannotFindFile(t *testing.T) {
 	codeownersFile := codeowners.NewRuleset(
 		codeowners.IngestedRulesetSource{},
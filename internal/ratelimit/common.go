@@ -18,3 +18,12 @@ func normaliseURL(rawURL string) string {
 	}
 	return parsed.String()
 }
+
+// codeHost returns the host of the given normalised URL.
+func codeHost(normalisedURL string) string {
+	parsed, err := url.Parse(normalisedURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Host
+}
@@ -0,0 +1,270 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/deploy"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var distributedRateLimits = env.MustGetBool("SRC_DISTRIBUTED_RATE_LIMITS", true, "Share the budgets of rate limiters and the rate limits reported by code hosts across all replicas through Redis.")
+
+// sharedPool returns the Redis pool through which rate limits are shared across all
+// replicas of all services. If it returns false, rate limits are shared through
+// sharedKeyValue instead. It is replaced in tests.
+var sharedPool = func() (*redis.Pool, bool) {
+	if !distributedRateLimits {
+		return nil, false
+	}
+	return redispool.Store.Pool()
+}
+
+// sharedKeyValue returns the key-value store through which rate limits are shared if
+// Redis is replaced by Postgres. If both it and sharedPool return false, rate limits are
+// only enforced within the current process, e.g. in the single binary, which runs all
+// services in one process. It is replaced in tests.
+var sharedKeyValue = func() (redispool.UpdaterKeyValue, bool) {
+	if !distributedRateLimits || deploy.IsSingleBinary() {
+		return nil, false
+	}
+	kv, ok := redispool.Store.(redispool.UpdaterKeyValue)
+	return kv, ok
+}
+
+const (
+	bucketKeyPrefix  = "ratelimit:bucket:"
+	monitorKeyPrefix = "ratelimit:monitor:"
+)
+
+// reserveScript reserves tokens from a token bucket shared by all replicas. The bucket
+// is stored as a hash holding the number of tokens it contained at a point in time, in
+// milliseconds. Tokens are reserved even if the bucket does not hold enough of them, in
+// which case the number of tokens becomes negative and the caller waits until the
+// bucket has been refilled, the same way rate.Limiter.Reserve does. The time of the
+// Redis server is used so that the clocks of the replicas do not matter.
+//
+// KEYS[1]: the key of the bucket
+// ARGV[1]: the rate at which the bucket is refilled, in tokens per second
+// ARGV[2]: the size of the bucket
+// ARGV[3]: the number of tokens to reserve
+// ARGV[4]: the maximum time to wait for the tokens, in milliseconds, or -1
+//
+// Returns the time to wait before the tokens can be used, in milliseconds, or -1 if the
+// wait would exceed the maximum, in which case no tokens are reserved.
+var reserveScript = redis.NewScript(1, `
+redis.replicate_commands()
+
+local limit = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local max_wait = tonumber(ARGV[4])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'at')
+local tokens = tonumber(state[1])
+local at = tonumber(state[2])
+if tokens == nil or at == nil then
+	tokens = burst
+	at = now
+end
+if now > at then
+	tokens = math.min(burst, tokens + (now - at) * limit / 1000)
+	at = now
+end
+
+tokens = tokens - n
+local wait = 0
+if tokens < 0 then
+	wait = math.ceil(-tokens * 1000 / limit)
+end
+if max_wait >= 0 and wait > max_wait then
+	return -1
+end
+
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'at', tostring(at))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / limit) + wait + 1000)
+return wait
+`)
+
+// reserveShared reserves n tokens from the bucket shared by all limiters with the given
+// URN, and returns the time to wait before they can be used. If the wait would exceed
+// maxWait, no tokens are reserved and false is returned. A negative maxWait means that
+// the wait is unbounded.
+func reserveShared(ctx context.Context, pool *redis.Pool, urn string, limit rate.Limit, burst, n int, maxWait time.Duration) (time.Duration, bool, error) {
+	conn, err := pool.GetContext(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer conn.Close()
+
+	maxWaitMs := int64(-1)
+	if maxWait >= 0 {
+		maxWaitMs = maxWait.Milliseconds()
+	}
+
+	waitMs, err := redis.Int64(reserveScript.Do(conn, bucketKeyPrefix+urn, float64(limit), burst, n, maxWaitMs))
+	if err != nil {
+		return 0, false, err
+	}
+	if waitMs < 0 {
+		return 0, false, nil
+	}
+	return time.Duration(waitMs) * time.Millisecond, true, nil
+}
+
+// bucketState is the state of a token bucket shared through sharedKeyValue. It holds the
+// same values as the hash updated by reserveScript.
+type bucketState struct {
+	Tokens float64 `json:"tokens"`
+	// At is the time the bucket held the tokens, in milliseconds.
+	At int64 `json:"at"`
+}
+
+// errExceedsMaxWait is returned by the update of a bucket to leave it unchanged.
+var errExceedsMaxWait = errors.New("wait exceeds maximum")
+
+// reserveSharedKeyValue is like reserveShared, but reserves the tokens through a
+// key-value store that is not backed by Redis. It implements reserveScript in Go, using
+// the clock of the current machine.
+func reserveSharedKeyValue(ctx context.Context, kv redispool.UpdaterKeyValue, urn string, limit rate.Limit, burst, n int, maxWait time.Duration) (time.Duration, bool, error) {
+	if kvCtx, ok := kv.WithContext(ctx).(redispool.UpdaterKeyValue); ok {
+		kv = kvCtx
+	}
+	now := time.Now().UnixMilli()
+
+	var waitMs int64
+	err := kv.Update(bucketKeyPrefix+urn, func(before []byte, found bool) ([]byte, int, error) {
+		state := bucketState{Tokens: float64(burst), At: now}
+		if found {
+			if err := json.Unmarshal(before, &state); err != nil {
+				return nil, 0, err
+			}
+		}
+		if now > state.At {
+			state.Tokens = math.Min(float64(burst), state.Tokens+float64(now-state.At)*float64(limit)/1000)
+			state.At = now
+		}
+
+		state.Tokens -= float64(n)
+		waitMs = 0
+		if state.Tokens < 0 {
+			waitMs = int64(math.Ceil(-state.Tokens * 1000 / float64(limit)))
+		}
+		if maxWait >= 0 && waitMs > maxWait.Milliseconds() {
+			return nil, 0, errExceedsMaxWait
+		}
+
+		after, err := json.Marshal(state)
+		if err != nil {
+			return nil, 0, err
+		}
+		ttl := math.Ceil(float64(burst)/float64(limit)) + math.Ceil(float64(waitMs)/1000) + 1
+		return after, int(ttl), nil
+	})
+	if err != nil {
+		if errors.Is(err, errExceedsMaxWait) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return time.Duration(waitMs) * time.Millisecond, true, nil
+}
+
+// monitorState is the rate limit of a code host and token last reported to any replica.
+type monitorState struct {
+	Known      bool      `json:"known"`
+	Limit      int       `json:"limit"`
+	Remaining  int       `json:"remaining"`
+	Reset      time.Time `json:"reset"`
+	Retry      time.Time `json:"retry"`
+	ObservedAt time.Time `json:"observedAt"`
+}
+
+// minMonitorStateTTL is the minimum time a shared monitor state is kept in Redis.
+const minMonitorStateTTL = time.Minute
+
+// putSharedMonitorState shares the given state of the monitor with the given key with
+// all replicas. The state is kept until the rate limit resets.
+func putSharedMonitorState(key string, state monitorState) error {
+	pool, poolOK := sharedPool()
+	kv, kvOK := sharedKeyValue()
+	if !poolOK && !kvOK {
+		return nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	ttl := minMonitorStateTTL
+	for _, until := range []time.Time{state.Reset, state.Retry} {
+		if d := time.Until(until) + minMonitorStateTTL; d > ttl {
+			ttl = d
+		}
+	}
+
+	if !poolOK {
+		return kv.SetEx(monitorKeyPrefix+key, int(math.Ceil(ttl.Seconds())), data)
+	}
+
+	conn := pool.Get()
+	defer conn.Close()
+	_, err = conn.Do("SET", monitorKeyPrefix+key, data, "PX", ttl.Milliseconds())
+	return err
+}
+
+// getSharedMonitorState returns the state of the monitor with the given key last shared
+// by any replica.
+func getSharedMonitorState(key string) (monitorState, bool, error) {
+	pool, poolOK := sharedPool()
+	kv, kvOK := sharedKeyValue()
+	if !poolOK && !kvOK {
+		return monitorState{}, false, nil
+	}
+
+	var data []byte
+	var err error
+	if poolOK {
+		conn := pool.Get()
+		defer conn.Close()
+		data, err = redis.Bytes(conn.Do("GET", monitorKeyPrefix+key))
+	} else {
+		data, err = kv.Get(monitorKeyPrefix + key).Bytes()
+	}
+	if err != nil {
+		if errors.Is(err, redis.ErrNil) {
+			return monitorState{}, false, nil
+		}
+		return monitorState{}, false, err
+	}
+
+	var state monitorState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return monitorState{}, false, err
+	}
+	return state, true, nil
+}
+
+var metricSharedErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_internal_rate_limit_shared_errors_total",
+	Help: "Total number of errors sharing rate limits across replicas, by kind. Limiters fall back to in-process rate limits on error.",
+}, []string{"kind"})
+
+var metricExternalWaitDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "src_external_rate_limit_wait_duration",
+	Help:    "Time spent waiting for the rate limit reported by a code host to reset",
+	Buckets: []float64{1, 5, 10, 30, 60, 300, 900, 1800, 3600},
+}, []string{"code_host"})
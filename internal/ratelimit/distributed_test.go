@@ -0,0 +1,187 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
+)
+
+func TestReserveShared(t *testing.T) {
+	pool := setupRedisForTest(t)
+	ctx := context.Background()
+	urn := "extsvc:github:" + t.Name()
+
+	// The bucket starts full
+	for i := 0; i < 2; i++ {
+		wait, ok, err := reserveShared(ctx, pool, urn, rate.Limit(1), 2, 1, -1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Zero(t, wait)
+	}
+
+	// An empty bucket is refilled at the limit
+	wait, ok, err := reserveShared(ctx, pool, urn, rate.Limit(1), 2, 1, -1)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.InDelta(t, time.Second, wait, float64(100*time.Millisecond))
+
+	// Reservations exceeding the maximum wait are not made
+	_, ok, err = reserveShared(ctx, pool, urn, rate.Limit(1), 2, 1, time.Second)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	wait, ok, err = reserveShared(ctx, pool, urn, rate.Limit(1), 2, 1, -1)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.InDelta(t, 2*time.Second, wait, float64(100*time.Millisecond))
+}
+
+func TestReserveSharedKeyValue(t *testing.T) {
+	kv := setupKeyValueForTest(t)
+	ctx := context.Background()
+	urn := "extsvc:github:" + t.Name()
+
+	// The bucket starts full
+	for i := 0; i < 2; i++ {
+		wait, ok, err := reserveSharedKeyValue(ctx, kv, urn, rate.Limit(1), 2, 1, -1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Zero(t, wait)
+	}
+
+	// An empty bucket is refilled at the limit
+	wait, ok, err := reserveSharedKeyValue(ctx, kv, urn, rate.Limit(1), 2, 1, -1)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.InDelta(t, time.Second, wait, float64(100*time.Millisecond))
+
+	// Reservations exceeding the maximum wait are not made
+	_, ok, err = reserveSharedKeyValue(ctx, kv, urn, rate.Limit(1), 2, 1, time.Second)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	wait, ok, err = reserveSharedKeyValue(ctx, kv, urn, rate.Limit(1), 2, 1, -1)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.InDelta(t, 2*time.Second, wait, float64(100*time.Millisecond))
+}
+
+func TestInstrumentedLimiter_SharedBudgetKeyValue(t *testing.T) {
+	setupKeyValueForTest(t)
+	urn := "extsvc:github:" + t.Name()
+
+	limiter1 := NewInstrumentedLimiter(urn, rate.NewLimiter(rate.Limit(1), 1))
+	limiter2 := NewInstrumentedLimiter(urn, rate.NewLimiter(rate.Limit(1), 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	assert.NoError(t, limiter1.Wait(ctx))
+	assert.Error(t, limiter2.Wait(ctx))
+}
+
+func TestInstrumentedLimiter_SharedBudget(t *testing.T) {
+	setupRedisForTest(t)
+	urn := "extsvc:github:" + t.Name()
+
+	// Two limiters with the same URN, e.g. in two replicas, share a single budget
+	limiter1 := NewInstrumentedLimiter(urn, rate.NewLimiter(rate.Limit(1), 1))
+	limiter2 := NewInstrumentedLimiter(urn, rate.NewLimiter(rate.Limit(1), 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	assert.NoError(t, limiter1.Wait(ctx))
+	assert.Error(t, limiter2.Wait(ctx))
+}
+
+func TestMonitor_Shared(t *testing.T) {
+	setupRedisForTest(t)
+	key := "https://github.com/:" + t.Name()
+
+	// Two monitors with the same key, e.g. in two replicas
+	m1 := &Monitor{HeaderPrefix: "X-"}
+	m1.share(key, "github.com")
+	m2 := &Monitor{HeaderPrefix: "X-"}
+	m2.share(key, "github.com")
+
+	reset := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	h := http.Header{}
+	h.Add("X-RateLimit-Limit", "5000")
+	h.Add("X-RateLimit-Remaining", "0")
+	h.Add("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	m1.Update(h)
+
+	// The rate limit reported to one replica is shared with the other
+	assert.Eventually(t, func() bool {
+		m2.mu.Lock()
+		m2.lastSync = time.Time{}
+		m2.mu.Unlock()
+		return m2.calcRateLimitWaitTime(1) > 0
+	}, 5*time.Second, 50*time.Millisecond)
+
+	remaining, _, _, known := m2.Get()
+	assert.True(t, known)
+	assert.Equal(t, 0, remaining)
+}
+
+// setupRedisForTest makes rate limits shared through a Redis server on localhost, and
+// returns its pool. The test is skipped if Redis is unavailable outside of CI.
+func setupRedisForTest(t *testing.T) *redis.Pool {
+	t.Helper()
+
+	pool := &redis.Pool{
+		MaxIdle:     3,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+	}
+	t.Cleanup(func() { _ = pool.Close() })
+
+	conn := pool.Get()
+	defer conn.Close()
+	if _, err := conn.Do("PING"); err != nil {
+		if os.Getenv("CI") == "" {
+			t.Skip("could not connect to redis", err)
+		}
+		t.Fatal(err)
+	}
+	for _, prefix := range []string{bucketKeyPrefix, monitorKeyPrefix} {
+		keys, err := redis.Strings(conn.Do("KEYS", prefix+"*"+t.Name()+"*"))
+		require.NoError(t, err)
+		for _, key := range keys {
+			_, err := conn.Do("DEL", key)
+			require.NoError(t, err)
+		}
+	}
+
+	old := sharedPool
+	sharedPool = func() (*redis.Pool, bool) { return pool, true }
+	t.Cleanup(func() { sharedPool = old })
+
+	return pool
+}
+
+// setupKeyValueForTest shares rate limits through an in-memory key-value store, the
+// way they are shared through Postgres if Redis is replaced.
+func setupKeyValueForTest(t *testing.T) redispool.UpdaterKeyValue {
+	kv := redispool.MemoryKeyValue().(redispool.UpdaterKeyValue)
+
+	oldPool, oldKeyValue := sharedPool, sharedKeyValue
+	sharedPool = func() (*redis.Pool, bool) { return nil, false }
+	sharedKeyValue = func() (redispool.UpdaterKeyValue, bool) { return kv, true }
+	t.Cleanup(func() { sharedPool, sharedKeyValue = oldPool, oldKeyValue })
+
+	return kv
+}
@@ -41,6 +41,7 @@ func (r *MonitorRegistry) GetOrSet(baseURL, authHash, resource string, monitor *
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if _, ok := r.monitors[key]; !ok {
+		monitor.share(key, codeHost(baseURL))
 		r.monitors[key] = monitor
 	}
 	return r.monitors[key]
@@ -75,9 +76,89 @@ type Monitor struct {
 	retry     time.Time         // deadline based on Retry-After HTTP response header value
 	collector *MetricsCollector // metrics collector
 
+	// The rate limit information is shared with all replicas under sharedKey, if set.
+	sharedKey  string
+	codeHost   string    // host of the code host, used as a metric label
+	observedAt time.Time // time at which the rate limit information was last updated
+	lastSync   time.Time // time at which the shared rate limit information was last read
+
 	clock func() time.Time
 }
 
+// monitorSyncInterval is the minimum time between two reads of the rate limit
+// information shared by other replicas.
+const monitorSyncInterval = time.Second
+
+// share makes the monitor share its rate limit information with all replicas under the
+// given key. Replicas share a budget if they use the same token for the same code host,
+// so the rate limit reported to any of them applies to all.
+func (c *Monitor) share(key, codeHost string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sharedKey = key
+	c.codeHost = codeHost
+}
+
+// sync updates the rate limit information from the one last shared by any replica, if
+// it is more recent.
+func (c *Monitor) sync() {
+	c.mu.Lock()
+	key := c.sharedKey
+	now := c.now()
+	due := key != "" && now.Sub(c.lastSync) >= monitorSyncInterval
+	if due {
+		c.lastSync = now
+	}
+	c.mu.Unlock()
+	if !due {
+		return
+	}
+
+	state, ok, err := getSharedMonitorState(key)
+	if err != nil {
+		metricSharedErrors.WithLabelValues("monitor").Inc()
+		return
+	}
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if state.Retry.After(c.retry) {
+		c.retry = state.Retry
+	}
+	if state.Known && state.ObservedAt.After(c.observedAt) {
+		c.known = true
+		c.limit = state.Limit
+		c.remaining = state.Remaining
+		c.reset = state.Reset
+		c.observedAt = state.ObservedAt
+	}
+}
+
+// publish shares the rate limit information of the monitor with all replicas. It must
+// be called with c.mu held.
+func (c *Monitor) publish() {
+	if c.sharedKey == "" || (!c.known && c.retry.IsZero()) {
+		return
+	}
+
+	key, state := c.sharedKey, monitorState{
+		Known:      c.known,
+		Limit:      c.limit,
+		Remaining:  c.remaining,
+		Reset:      c.reset,
+		Retry:      c.retry,
+		ObservedAt: c.observedAt,
+	}
+	go func() {
+		if err := putSharedMonitorState(key, state); err != nil {
+			metricSharedErrors.WithLabelValues("monitor").Inc()
+		}
+	}()
+}
+
 // Get reports the client's rate limit status (as of the last API response it received).
 func (c *Monitor) Get() (remaining int, reset, retry time.Duration, known bool) {
 	c.mu.Lock()
@@ -112,6 +193,8 @@ func (c *Monitor) Get() (remaining int, reset, retry time.Duration, known bool)
 //
 // See https://developer.github.com/v4/guides/resource-limitations/#rate-limit.
 func (c *Monitor) RecommendedWaitForBackgroundOp(cost int) (timeRemaining time.Duration) {
+	c.sync()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -163,6 +246,8 @@ func (c *Monitor) RecommendedWaitForBackgroundOp(cost int) (timeRemaining time.D
 }
 
 func (c *Monitor) calcRateLimitWaitTime(cost int) time.Duration {
+	c.sync()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -207,6 +292,11 @@ func (c *Monitor) WaitForRateLimit(ctx context.Context, cost int) bool {
 		return false
 	}
 
+	c.mu.Lock()
+	host := c.codeHost
+	c.mu.Unlock()
+	metricExternalWaitDuration.WithLabelValues(host).Observe(sleepDuration.Seconds())
+
 	timeutil.SleepWithContext(ctx, sleepDuration)
 	return true
 }
@@ -220,6 +310,7 @@ func (c *Monitor) Update(h http.Header) {
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	defer c.publish()
 
 	retry, _ := strconv.ParseInt(h.Get("Retry-After"), 10, 64)
 	if retry > 0 {
@@ -246,6 +337,7 @@ func (c *Monitor) Update(h http.Header) {
 	c.limit = limit
 	c.remaining = remaining
 	c.reset = time.Unix(resetAtSeconds, 0)
+	c.observedAt = c.now()
 
 	if c.known && c.collector != nil && c.collector.Remaining != nil {
 		c.collector.Remaining(float64(c.remaining))
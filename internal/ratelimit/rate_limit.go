@@ -143,7 +143,7 @@ func (i *InstrumentedLimiter) WaitN(ctx context.Context, n int) error {
 	}
 
 	start := time.Now()
-	err := i.Limiter.WaitN(ctx, n)
+	err := i.waitN(ctx, n)
 	d := time.Since(start)
 	failedLabel := "false"
 	if err != nil {
@@ -154,6 +154,65 @@ func (i *InstrumentedLimiter) WaitN(ctx context.Context, n int) error {
 	return err
 }
 
+// waitN waits for n tokens of the bucket shared by all limiters with the same URN across
+// all replicas, so that they respect a single budget. It falls back to the in-process
+// limiter if the limiter has no URN, its limit is infinite, or rate limits are not
+// shared.
+func (i *InstrumentedLimiter) waitN(ctx context.Context, n int) error {
+	limit, burst := i.Limit(), i.Burst()
+	if i.urn == "" || limit == rate.Inf || limit <= 0 {
+		return i.Limiter.WaitN(ctx, n)
+	}
+	pool, poolOK := sharedPool()
+	kv, kvOK := sharedKeyValue()
+	if !poolOK && !kvOK {
+		return i.Limiter.WaitN(ctx, n)
+	}
+
+	if n > burst {
+		return errors.Errorf("rate: Wait(n=%d) exceeds limiter's burst %d", n, burst)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	maxWait := time.Duration(-1)
+	if deadline, ok := ctx.Deadline(); ok {
+		maxWait = time.Until(deadline)
+	}
+
+	var wait time.Duration
+	var ok bool
+	var err error
+	if poolOK {
+		wait, ok, err = reserveShared(ctx, pool, i.urn, limit, burst, n, maxWait)
+	} else {
+		wait, ok, err = reserveSharedKeyValue(ctx, kv, i.urn, limit, burst, n, maxWait)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		metricSharedErrors.WithLabelValues("bucket").Inc()
+		return i.Limiter.WaitN(ctx, n)
+	}
+	if !ok {
+		return errors.Errorf("rate: Wait(n=%d) would exceed context deadline", n)
+	}
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // SetBurst is calling SetBurstAt(time.Now(), newBurst) method of the wrapped *rate.Limiter.
 func (i *InstrumentedLimiter) SetBurst(newBurst int) {
 	i.Limiter.SetBurstAt(time.Now(), newBurst)
@@ -2,8 +2,8 @@ package redispool
 
 import (
 	"context"
-	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -15,21 +15,23 @@ import (
 // We do not directly import that interface since that introduces
 // complications around dependency graphs.
 //
-// Note: DBKeyValue uses a coarse global mutex for all transactions on-top of
-// whatever transaction DBStoreTransact provides. The intention of these
-// interfaces is to be used in a single process application (like Sourcegraph
-// App). We would need to change the design of NaiveKeyValueStore to allow for
-// retries to smoothly avoid global mutexes.
+// Note: DBKeyValue relies on Get locking the key for the rest of the
+// transaction DBStoreTransact provides. This serializes all updates of a key,
+// even across processes, so that DBKeyValue can be used by all services of a
+// deployment without redis.
 type DBStore interface {
 	// Get returns the value for (namespace, key). ok is false if the
 	// (namespace, key) has not been set.
 	//
-	// Note: We recommend using "SELECT ... FOR UPDATE" since this call is
-	// often followed by Set in the same transaction.
+	// Note: Get must lock (namespace, key) until the end of the transaction,
+	// even if it has not been set, since this call is often followed by Set
+	// in the same transaction.
 	Get(ctx context.Context, namespace, key string) (value []byte, ok bool, err error)
 	// Set will upsert value for (namespace, key). If value is nil it should
-	// be persisted as an empty byte slice.
-	Set(ctx context.Context, namespace, key string, value []byte) (err error)
+	// be persisted as an empty byte slice. expiresAt is when the value
+	// expires, or the zero time if it does not. It is only used to delete
+	// expired values, Get must still return them.
+	Set(ctx context.Context, namespace, key string, value []byte, expiresAt time.Time) (err error)
 	// Delete will remove (namespace, key). If (namespace, key) is not in the
 	// store, the delete is a noop.
 	Delete(ctx context.Context, namespace, key string) (err error)
@@ -42,6 +44,10 @@ type DBStoreTransact func(ctx context.Context, f func(DBStore) error) error
 
 var dbStoreTransact atomic.Value
 
+// ErrDBStoreAlreadyRegistered is returned if DBRegisterStore is called more
+// than once.
+var ErrDBStoreAlreadyRegistered = errors.New("redispool.DBRegisterStore has already been called")
+
 // DBRegisterStore registers our database with the redispool package. Until
 // this is called all KeyValue operations against a DB backed KeyValue will
 // fail with an error. As such this function should be called early on (as
@@ -51,28 +57,16 @@ var dbStoreTransact atomic.Value
 func DBRegisterStore(transact DBStoreTransact) error {
 	ok := dbStoreTransact.CompareAndSwap(nil, transact)
 	if !ok {
-		return errors.New("redispool.DBRegisterStore has already been called")
+		return ErrDBStoreAlreadyRegistered
 	}
 	return nil
 }
 
-// dbMu protects _all_ possible interactions with the database in DBKeyValue.
-// This is to avoid concurrent get/sets on the same key resulting in one of
-// the sets failing due to serializability.
-var dbMu sync.Mutex
-
 // DBKeyValue returns a KeyValue with namespace. Namespaces allow us to have
 // distinct KeyValue stores, but still use the same underlying DBStore
 // storage.
-//
-// Note: This is designed for use in a single process application like
-// Sourcegraph App. All transactions are additionally protected by a global
-// mutex to avoid the need to handle database serializability errors.
 func DBKeyValue(namespace string) KeyValue {
 	store := func(ctx context.Context, key string, f NaiveUpdater) error {
-		dbMu.Lock()
-		defer dbMu.Unlock()
-
 		transact := dbStoreTransact.Load()
 		if transact == nil {
 			return errors.New("redispool.DBRegisterStore has not been called")
@@ -93,7 +87,7 @@ func DBKeyValue(namespace string) KeyValue {
 					}
 				}
 			} else if before != after {
-				if err := store.Set(ctx, namespace, key, []byte(after)); err != nil {
+				if err := store.Set(ctx, namespace, key, []byte(after), naiveValueDeadline(after)); err != nil {
 					return errors.Wrapf(err, "redispool.DBKeyValue failed to set %q in namespace %q", key, namespace)
 				}
 			}
@@ -103,3 +97,13 @@ func DBKeyValue(namespace string) KeyValue {
 
 	return FromNaiveKeyValueStore(store)
 }
+
+// naiveValueDeadline returns when the value expires, or the zero time if it
+// does not expire.
+func naiveValueDeadline(v NaiveValue) time.Time {
+	var value redisValue
+	if err := value.Unmarshal([]byte(v)); err != nil || value.DeadlineUnix == 0 {
+		return time.Time{}
+	}
+	return time.Unix(value.DeadlineUnix, 0)
+}
@@ -0,0 +1,31 @@
+// Package dbstore registers the frontend database as the storage of the
+// postgres backed KeyValues of redispool. It is separate from redispool since
+// redispool and database avoid depending on each other.
+package dbstore
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Register registers db with redispool. Every service with access to the
+// frontend database should call this as soon as it has a DB connection, so
+// that it works when redis is replaced by postgres (see SRC_KEY_VALUE_BACKEND).
+//
+// Registering more than once is not an error, since all services of the
+// single binary share the process and the database.
+func Register(db database.DB) error {
+	kvNoTX := db.RedisKeyValue()
+	err := redispool.DBRegisterStore(func(ctx context.Context, f func(redispool.DBStore) error) error {
+		return kvNoTX.WithTransact(ctx, func(tx database.RedisKeyValueStore) error {
+			return f(tx)
+		})
+	})
+	if errors.Is(err, redispool.ErrDBStoreAlreadyRegistered) {
+		return nil
+	}
+	return err
+}
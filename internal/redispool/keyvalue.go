@@ -54,6 +54,20 @@ type KeyValue interface {
 	Pool() (pool *redis.Pool, ok bool)
 }
 
+// UpdaterKeyValue is implemented by the KeyValues that are not backed by
+// redis, such as the ones returned by DBKeyValue and MemoryKeyValue. Where
+// redis users would run a Lua script to atomically read and update a value,
+// users of these KeyValues can use Update instead.
+type UpdaterKeyValue interface {
+	KeyValue
+
+	// Update atomically replaces the string value of key with the value
+	// returned by f. found is false if key is not set. The new value expires
+	// after ttlSeconds, unless ttlSeconds is 0. If f returns an error, the
+	// value is not changed and the error is returned.
+	Update(key string, f func(before []byte, found bool) (after []byte, ttlSeconds int, err error)) error
+}
+
 // Value is a response from an operation on KeyValue. It provides convenient
 // methods to get at the underlying value of the reply.
 //
@@ -42,6 +42,8 @@ func FromNaiveKeyValueStore(store NaiveKeyValueStore) KeyValue {
 	}
 }
 
+var _ UpdaterKeyValue = &naiveKeyValue{}
+
 // naiveKeyValue wraps a store to provide the KeyValue interface. Nearly all
 // operations go via maybeUpdateGroup method, sink your teeth into that first
 // to fully understand how to expand the set of methods provided.
@@ -345,6 +347,31 @@ func clampRangeOffset(low, high, offset int) int {
 	return offset
 }
 
+func (kv *naiveKeyValue) Update(key string, f func(before []byte, found bool) ([]byte, int, error)) error {
+	return kv.maybeUpdateGroup(redisGroupString, key, func(value redisValue, found bool) (redisValue, updaterOp, error) {
+		var before []byte
+		if found {
+			var err error
+			if before, err = redis.Bytes(value.Reply, nil); err != nil {
+				return value, readOnly, err
+			}
+		}
+		after, ttlSeconds, err := f(before, found)
+		if err != nil {
+			return value, readOnly, err
+		}
+
+		value = redisValue{
+			Group: redisGroupString,
+			Reply: after,
+		}
+		if ttlSeconds > 0 {
+			value.DeadlineUnix = time.Now().UTC().Unix() + int64(ttlSeconds)
+		}
+		return value, write, nil
+	}).err
+}
+
 func (kv *naiveKeyValue) WithContext(ctx context.Context) KeyValue {
 	return &naiveKeyValue{
 		store: kv.store,
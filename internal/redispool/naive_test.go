@@ -66,3 +66,34 @@ func dbStoreTransact(t *testing.T) redispool.DBStoreTransact {
 		})
 	}
 }
+
+func TestNaiveKeyValueUpdate(t *testing.T) {
+	kv := redispool.MemoryKeyValue().(redispool.UpdaterKeyValue)
+	incr := func(ttlSeconds int) func([]byte, bool) ([]byte, int, error) {
+		return func(before []byte, found bool) ([]byte, int, error) {
+			if !found {
+				return []byte("1"), ttlSeconds, nil
+			}
+			return append(before, '1'), ttlSeconds, nil
+		}
+	}
+
+	require := require{TB: t}
+	require.Works(kv.Update("counter", incr(0)))
+	require.Works(kv.Update("counter", incr(60)))
+	require.Equal(kv.Get("counter"), "11")
+	require.TTL(kv, "counter", 60)
+
+	// errors leave the value unchanged
+	err := kv.Update("counter", func([]byte, bool) ([]byte, int, error) { return nil, 0, errors.New("oops") })
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	require.Equal(kv.Get("counter"), "11")
+
+	// other types are not updated
+	require.Works(kv.LPush("list", "1"))
+	if err := kv.Update("list", incr(0)); err == nil {
+		t.Fatal("expected WRONGTYPE error")
+	}
+}
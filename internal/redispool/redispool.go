@@ -13,10 +13,25 @@ import (
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
+// The backends that can be selected with SRC_KEY_VALUE_BACKEND.
+const (
+	// BackendRedis stores the cache and the store in redis.
+	BackendRedis = "redis"
+	// BackendPostgres stores the cache and the store in the frontend
+	// database, so that small deployments can run without redis.
+	BackendPostgres = "postgres"
+	// BackendMemory stores the cache in the memory of each process and the
+	// store in the frontend database.
+	BackendMemory = "memory"
+)
+
+var backend = env.Get("SRC_KEY_VALUE_BACKEND", BackendRedis, "Where to store cache data and persistent key-value data: redis, postgres (no redis required) or memory (cache in process memory, persistent data in postgres).")
+
 // Set addresses. We do it as a function closure to ensure the addresses are
 // set before we create Store and Cache. Prefer in this order:
 // * Specific envvar REDIS_${NAME}_ENDPOINT
 // * Fallback envvar REDIS_ENDPOINT
+// * Backend selected by SRC_KEY_VALUE_BACKEND
 // * Default
 //
 // Additionally keep this logic in sync with cmd/server/redis.go
@@ -43,7 +58,8 @@ var addresses = func() struct {
 	for _, addr := range []string{
 		env.Get("REDIS_CACHE_ENDPOINT", "", "redis used for cache data. Default redis-cache:6379"),
 		fallback,
-		maybe(deploy.IsSingleBinary(), MemoryKeyValueURI),
+		maybe(backend == BackendPostgres, DBKeyValueURI("cache")),
+		maybe(backend == BackendMemory || deploy.IsSingleBinary(), MemoryKeyValueURI),
 		"redis-cache:6379",
 	} {
 		if addr != "" {
@@ -56,7 +72,7 @@ var addresses = func() struct {
 	for _, addr := range []string{
 		env.Get("REDIS_STORE_ENDPOINT", "", "redis used for persistent stores (eg HTTP sessions). Default redis-store:6379"),
 		fallback,
-		maybe(deploy.IsSingleBinary(), DBKeyValueURI("store")),
+		maybe(backend != BackendRedis || deploy.IsSingleBinary(), DBKeyValueURI("store")),
 		"redis-store:6379",
 	} {
 		if addr != "" {
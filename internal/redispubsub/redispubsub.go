@@ -0,0 +1,109 @@
+// Package redispubsub notifies other services of changes over Redis, so that they
+// can react to the change instead of polling for it.
+//
+// Notifications carry no payload: subscribers are expected to read the current
+// state of whatever changed from its source of truth. Notifications are best
+// effort, and are lost if no subscriber is connected when they are published.
+package redispubsub
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// channelPrefix namespaces the Redis channels of topics.
+const channelPrefix = "pubsub:"
+
+// ErrUnavailable is returned when subscribing without a Redis backed store,
+// for example in single-binary deployments.
+var ErrUnavailable = errors.New("redispubsub: notifications require Redis")
+
+// RecordTopic returns the topic on which changes to the record with the given
+// ID in the given table are published.
+func RecordTopic(tableName string, id int) string {
+	return fmt.Sprintf("%s:%d", tableName, id)
+}
+
+// Publish notifies the subscribers of the given topic.
+func Publish(topic string) error {
+	pool, ok := redispool.Store.Pool()
+	if !ok {
+		return nil
+	}
+
+	c := pool.Get()
+	defer c.Close()
+
+	_, err := c.Do("PUBLISH", channelPrefix+topic, "")
+	return err
+}
+
+// RecordStateChangeHook returns a function publishing changes to records of
+// the given table, suitable for the OnStateChange option of dbworker stores.
+func RecordStateChangeHook(tableName string) func(ctx context.Context, id int) {
+	logger := log.Scoped("redispubsub", "change notifications over Redis")
+
+	return func(_ context.Context, id int) {
+		if err := Publish(RecordTopic(tableName, id)); err != nil {
+			logger.Warn("failed to publish record state change", log.String("table", tableName), log.Int("id", id), log.Error(err))
+		}
+	}
+}
+
+// Subscribe returns a channel receiving a value after any of the given topics
+// has been published to. Notifications published while the previous value has
+// not been received yet are coalesced. The channel is closed once the context
+// is canceled or the connection to Redis is lost.
+func Subscribe(ctx context.Context, topics ...string) (<-chan struct{}, error) {
+	pool, ok := redispool.Store.Pool()
+	if !ok {
+		return nil, ErrUnavailable
+	}
+
+	channels := make([]any, 0, len(topics))
+	for _, topic := range topics {
+		channels = append(channels, channelPrefix+topic)
+	}
+
+	conn := redis.PubSubConn{Conn: pool.Get()}
+	if err := conn.Subscribe(channels...); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		// Closing the connection unblocks Receive below.
+		conn.Close()
+	}()
+
+	notifications := make(chan struct{}, 1)
+	go func() {
+		defer close(notifications)
+		defer close(done)
+
+		for {
+			switch conn.Receive().(type) {
+			case redis.Message:
+				select {
+				case notifications <- struct{}{}:
+				default:
+				}
+			case error:
+				return
+			}
+		}
+	}()
+
+	return notifications, nil
+}
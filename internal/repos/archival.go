@@ -0,0 +1,111 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// archivalBatchSize is the maximum number of repositories archived per run.
+const archivalBatchSize = 1000
+
+// NewRepositoryArchiver returns a background routine that applies the
+// repository archival policy: it archives the repositories that have been
+// inactive for the period of the policy, restores the archived repositories that
+// changed since, and reduces the sync frequency of the archived repositories in
+// the scheduler.
+func NewRepositoryArchiver(ctx context.Context, logger log.Logger, db database.DB, scheduler *UpdateScheduler) goroutine.BackgroundRoutine {
+	archiver := &repositoryArchiver{
+		logger:    logger.Scoped("RepositoryArchiver", "archives inactive repositories"),
+		store:     db.RepoArchival(),
+		scheduler: scheduler,
+	}
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		archiver,
+		goroutine.WithName("repo-updater.repository-archiver"),
+		goroutine.WithDescription("archives inactive repositories"),
+		goroutine.WithInterval(time.Hour),
+	)
+}
+
+type repositoryArchiver struct {
+	logger    log.Logger
+	store     database.RepoArchivalStore
+	scheduler interface{ SetArchived([]api.RepoID) }
+}
+
+var _ goroutine.Handler = &repositoryArchiver{}
+
+func (a *repositoryArchiver) Handle(ctx context.Context) error {
+	policy, err := a.store.GetPolicy(ctx)
+	if err != nil {
+		return errors.Wrap(err, "getting archival policy")
+	}
+
+	// Archived repositories are still synced, so a repository that becomes
+	// active again is restored automatically.
+	reactivated, err := a.store.ListReactivated(ctx)
+	if err != nil {
+		return errors.Wrap(err, "listing reactivated repositories")
+	}
+	for _, id := range reactivated {
+		if _, err := a.store.Restore(ctx, id, 0); err != nil {
+			return errors.Wrapf(err, "restoring repository %d", id)
+		}
+		archivalRestored.Inc()
+	}
+
+	if policy.Enabled {
+		inactiveSince := timeNow().AddDate(0, -int(policy.InactiveMonths), 0)
+		ids, err := a.store.ListArchivable(ctx, inactiveSince, archivalBatchSize)
+		if err != nil {
+			return errors.Wrap(err, "listing archivable repositories")
+		}
+		if err := a.archive(ctx, policy, ids); err != nil {
+			return err
+		}
+		if len(ids) > 0 || len(reactivated) > 0 {
+			a.logger.Info("applied archival policy", log.Int("archived", len(ids)), log.Int("restored", len(reactivated)))
+		}
+	}
+
+	var archived []api.RepoID
+	if policy.ReduceSyncFrequency {
+		if archived, err = a.store.ListArchivedIDs(ctx); err != nil {
+			return errors.Wrap(err, "listing archived repositories")
+		}
+	}
+	a.scheduler.SetArchived(archived)
+
+	return nil
+}
+
+func (a *repositoryArchiver) archive(ctx context.Context, policy *database.RepoArchivalPolicy, ids []api.RepoID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := a.store.Archive(ctx, ids...); err != nil {
+		return errors.Wrap(err, "archiving repositories")
+	}
+	archivalArchived.Add(float64(len(ids)))
+
+	if policy.DropEmbeddings {
+		if err := a.store.DeleteEmbeddings(ctx, ids...); err != nil {
+			return errors.Wrap(err, "deleting embeddings")
+		}
+	}
+	if policy.DropCodeIntel {
+		if err := a.store.DeleteCodeIntelData(ctx, ids...); err != nil {
+			return errors.Wrap(err, "deleting code intelligence data")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package repos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+type fakeArchivedSetter struct {
+	archived []api.RepoID
+}
+
+func (f *fakeArchivedSetter) SetArchived(ids []api.RepoID) { f.archived = ids }
+
+func TestRepositoryArchiver(t *testing.T) {
+	ctx := context.Background()
+	mockTime(defaultTime)
+
+	newArchiver := func(policy database.RepoArchivalPolicy) (*repositoryArchiver, *database.MockRepoArchivalStore, *fakeArchivedSetter) {
+		store := database.NewMockRepoArchivalStore()
+		store.GetPolicyFunc.SetDefaultReturn(&policy, nil)
+		store.ListReactivatedFunc.SetDefaultReturn([]api.RepoID{3}, nil)
+		store.ListArchivableFunc.SetDefaultReturn([]api.RepoID{1, 2}, nil)
+		store.ListArchivedIDsFunc.SetDefaultReturn([]api.RepoID{1, 2}, nil)
+		scheduler := &fakeArchivedSetter{}
+		return &repositoryArchiver{logger: logtest.Scoped(t), store: store, scheduler: scheduler}, store, scheduler
+	}
+
+	t.Run("disabled policy", func(t *testing.T) {
+		archiver, store, scheduler := newArchiver(database.RepoArchivalPolicy{InactiveMonths: 12})
+		require.NoError(t, archiver.Handle(ctx))
+
+		require.Len(t, store.ArchiveFunc.History(), 0)
+		// Reactivated repositories are restored even if the policy is disabled.
+		require.Len(t, store.RestoreFunc.History(), 1)
+		require.Equal(t, api.RepoID(3), store.RestoreFunc.History()[0].Arg1)
+		require.Empty(t, scheduler.archived)
+	})
+
+	t.Run("enabled policy", func(t *testing.T) {
+		archiver, store, scheduler := newArchiver(database.RepoArchivalPolicy{
+			Enabled:             true,
+			InactiveMonths:      6,
+			ReduceSyncFrequency: true,
+			DropCodeIntel:       true,
+		})
+		require.NoError(t, archiver.Handle(ctx))
+
+		require.Len(t, store.ListArchivableFunc.History(), 1)
+		require.Equal(t, defaultTime.AddDate(0, -6, 0), store.ListArchivableFunc.History()[0].Arg1)
+		require.Len(t, store.ArchiveFunc.History(), 1)
+		require.Equal(t, []api.RepoID{1, 2}, store.ArchiveFunc.History()[0].Arg1)
+		require.Len(t, store.DeleteEmbeddingsFunc.History(), 0)
+		require.Len(t, store.DeleteCodeIntelDataFunc.History(), 1)
+		require.Equal(t, []api.RepoID{1, 2}, scheduler.archived)
+	})
+
+	t.Run("nothing to archive", func(t *testing.T) {
+		archiver, store, _ := newArchiver(database.RepoArchivalPolicy{Enabled: true, InactiveMonths: 6, DropEmbeddings: true})
+		store.ListArchivableFunc.SetDefaultReturn(nil, nil)
+		require.NoError(t, archiver.Handle(ctx))
+
+		require.Len(t, store.ArchiveFunc.History(), 0)
+		require.Len(t, store.DeleteEmbeddingsFunc.History(), 0)
+	})
+}
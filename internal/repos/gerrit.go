@@ -2,6 +2,7 @@ package repos
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"path"
 	"sort"
@@ -28,6 +29,7 @@ type GerritSource struct {
 	perPage         int
 	private         bool
 	allowedProjects map[string]struct{}
+	syncOpenChanges bool
 }
 
 // NewGerritSource returns a new GerritSource from the given external service.
@@ -75,6 +77,7 @@ func NewGerritSource(ctx context.Context, svc *types.ExternalService, cf *httpcl
 		serviceID:       extsvc.NormalizeBaseURL(cli.GetURL()).String(),
 		perPage:         100,
 		private:         c.Authorization != nil,
+		syncOpenChanges: c.SyncOpenChanges,
 	}, nil
 }
 
@@ -116,6 +119,14 @@ func (s *GerritSource) ListRepos(ctx context.Context, results chan SourceResult)
 				}
 			}
 
+			if s.syncOpenChanges {
+				page[p].OpenChanges, err = s.listOpenChanges(ctx, p)
+				if err != nil {
+					results <- SourceResult{Source: s, Err: err}
+					return
+				}
+			}
+
 			repo, err := s.makeRepo(p, page[p])
 			if err != nil {
 				results <- SourceResult{Source: s, Err: err}
@@ -132,6 +143,45 @@ func (s *GerritSource) ListRepos(ctx context.Context, results chan SourceResult)
 	}
 }
 
+// listOpenChanges returns the references of the current patch sets of the open
+// changes of the given project, ordered by change number.
+func (s *GerritSource) listOpenChanges(ctx context.Context, projectName string) ([]gerrit.ChangeRef, error) {
+	args := gerrit.ListChangesArgs{
+		Cursor:          &gerrit.Pagination{PerPage: s.perPage, Page: 1},
+		Query:           fmt.Sprintf("status:open project:%q", projectName),
+		CurrentRevision: true,
+	}
+
+	var refs []gerrit.ChangeRef
+	for {
+		changes, nextPage, err := s.cli.ListChanges(ctx, args)
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing open changes of project %q", projectName)
+		}
+
+		for _, c := range changes {
+			rev, ok := c.Revisions[c.CurrentRevision]
+			if !ok {
+				continue
+			}
+			refs = append(refs, gerrit.ChangeRef{
+				ChangeID: c.ChangeID,
+				Number:   c.ChangeNumber,
+				Revision: c.CurrentRevision,
+				Ref:      rev.Ref,
+			})
+		}
+
+		if !nextPage {
+			break
+		}
+		args.Cursor.Page++
+	}
+
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Number < refs[j].Number })
+	return refs, nil
+}
+
 // ExternalServices returns a singleton slice containing the external service.
 func (s *GerritSource) ExternalServices() types.ExternalServices {
 	return types.ExternalServices{s.svc}
@@ -378,6 +378,17 @@ func (s *GitHubSource) GetRepo(ctx context.Context, nameWithOwner string) (*type
 	return s.makeRepo(r), nil
 }
 
+// GetObjectVerification returns the signature verification status of the commit or
+// annotated tag with the given SHA in the given repository ("org/repo-name"), as
+// determined by GitHub.
+func (s *GitHubSource) GetObjectVerification(ctx context.Context, nameWithOwner, objectType, sha string) (*github.Verification, error) {
+	owner, name, err := github.SplitRepositoryNameWithOwner(nameWithOwner)
+	if err != nil {
+		return nil, err
+	}
+	return s.v3Client.GetObjectVerification(ctx, owner, name, objectType, sha)
+}
+
 func sanitizeToUTF8(s string) string {
 	return strings.ToValidUTF8(strings.ReplaceAll(s, "\x00", ""), "")
 }
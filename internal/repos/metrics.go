@@ -62,6 +62,16 @@ var (
 		Help: "Incremented each time we try and fail to remove a repository clone.",
 	})
 
+	archivalArchived = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_repoupdater_archival_archived",
+		Help: "Incremented each time a repository is archived by the archival policy.",
+	})
+
+	archivalRestored = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_repoupdater_archival_restored",
+		Help: "Incremented each time an archived repository is restored because it changed.",
+	})
+
 	schedError = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "src_repoupdater_sched_error",
 		Help: "Incremented each time we encounter an error updating a repository.",
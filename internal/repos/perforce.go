@@ -2,15 +2,20 @@ package repos
 
 import (
 	"context"
+	"database/sql"
 	"net/url"
 	"strings"
 
+	"github.com/sourcegraph/log"
+
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/vcs"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -82,6 +87,65 @@ func (s PerforceSource) ListRepos(ctx context.Context, results chan SourceResult
 	}
 }
 
+// DepotProgress returns the import progress of the depots of the Perforce
+// connection that have been synced to Sourcegraph.
+func (s PerforceSource) DepotProgress(ctx context.Context, logger log.Logger, store Store) ([]protocol.PerforceDepotProgress, error) {
+	rs, err := store.RepoStore().List(ctx, database.ReposListOptions{
+		ExternalServiceIDs: []int64{s.svc.ID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changelists := database.RepoCommitsChangelistsWith(logger, store)
+	syncer := server.PerforceDepotSyncer{}
+
+	progress := make([]protocol.PerforceDepotProgress, 0, len(rs))
+	for _, r := range rs {
+		depot, ok := r.Metadata.(*perforce.Depot)
+		if !ok {
+			continue
+		}
+		p := protocol.PerforceDepotProgress{
+			Depot: depot.Depot,
+			Repo:  r.Name,
+		}
+
+		gr, err := store.GitserverReposStore().GetByID(ctx, r.ID)
+		if err != nil {
+			return nil, err
+		}
+		p.CloneStatus = gr.CloneStatus
+		p.LastFetched = gr.LastFetched
+		p.Error = gr.LastError
+
+		latest, err := changelists.GetLatestForRepo(ctx, r.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		if latest != nil {
+			p.ImportedChangelist = latest.PerforceChangelistID
+		}
+
+		u := url.URL{
+			Scheme: "perforce",
+			Host:   s.config.P4Port,
+			Path:   depot.Depot,
+			User:   url.UserPassword(s.config.P4User, s.config.P4Passwd),
+		}
+		p4Url, err := vcs.ParseURL(u.String())
+		if err != nil {
+			return nil, err
+		}
+		if p.LatestChangelist, err = syncer.LatestChangelist(ctx, p4Url); err != nil {
+			p.Error = err.Error()
+		}
+
+		progress = append(progress, p)
+	}
+	return progress, nil
+}
+
 // composePerforceCloneURL composes a clone URL for a Perforce depot based on
 // given information. e.g.
 // perforce://ssl:111.222.333.444:1666//Sourcegraph/
@@ -234,6 +234,14 @@ func RunPhabricatorRepositorySyncWorker(ctx context.Context, db database.DB, log
 			phabricatorUpdateTime.WithLabelValues(
 				cfg.(*schema.PhabricatorConnection).Url,
 			).Set(float64(time.Now().Unix()))
+
+			// Phabricator connections are excluded from the periodic sync jobs, so
+			// those that also sync repositories to Sourcegraph are enqueued here.
+			if cfg.(*schema.PhabricatorConnection).SyncRepositories {
+				if err := s.EnqueueSingleSyncJob(ctx, phab.ID); err != nil {
+					logger.Error("failed to enqueue Phabricator sync job", log.Int64("id", phab.ID), log.Error(err))
+				}
+			}
 		}
 
 		time.Sleep(ConfRepoListUpdateInterval())
@@ -88,6 +88,11 @@ const (
 
 	// maxDelay is the maximum amount of time between scheduled updates for a single repository.
 	maxDelay = 8 * time.Hour
+
+	// defaultArchivedDelay is the default amount of time between scheduled updates for a
+	// single repository archived by the archival policy. It is overridden by the
+	// repoSyncScheduling.archivedIntervalHours site configuration setting.
+	defaultArchivedDelay = 7 * 24 * time.Hour
 )
 
 // UpdateScheduler schedules repo update (or clone) requests to gitserver.
@@ -175,7 +180,7 @@ func (s *UpdateScheduler) runSchedule() {
 		}
 
 		schedAutoFetch.Inc()
-		s.updateQueue.enqueue(repoUpdate.Repo, priorityLow)
+		s.updateQueue.enqueue(repoUpdate.Repo, scheduledPriority(conf.Get(), repoUpdate))
 		repoUpdate.Due = timeNow().Add(repoUpdate.Interval)
 		heap.Fix(s.schedule, 0)
 	}
@@ -234,6 +239,11 @@ func (s *UpdateScheduler) runUpdateLoop(ctx context.Context) {
 					return
 				}
 
+				if s.schedule.isArchived(repo) {
+					s.schedule.updateInterval(repo, archivedDelay(conf.Get()))
+					return
+				}
+
 				if err != nil || (resp != nil && resp.Error != "") {
 					// On error we will double the current interval so that we back off and don't
 					// get stuck with problematic repos with low intervals.
@@ -400,9 +410,41 @@ func (s *UpdateScheduler) UpdateOnce(id api.RepoID, name api.RepoName) {
 		Name: name,
 	}
 	schedManualFetch.Inc()
+	s.schedule.markActive(repo)
 	s.updateQueue.enqueue(repo, priorityHigh)
 }
 
+// SetArchived sets the repos archived by the archival policy whose sync
+// frequency is reduced. They are updated once every archivedDelay, unless they
+// are updated on demand.
+func (s *UpdateScheduler) SetArchived(ids []api.RepoID) {
+	s.schedule.setArchived(ids)
+}
+
+// scheduledPriority returns the priority of a scheduled update of the repo.
+// Updates of repos that were recently accessed are queued ahead of the others.
+func scheduledPriority(c *conf.Unified, update *scheduledRepoUpdate) priority {
+	recentlyActive := 60 * time.Minute
+	if c != nil && c.RepoSyncScheduling != nil && c.RepoSyncScheduling.RecentlyActiveMinutes != nil {
+		recentlyActive = time.Duration(*c.RepoSyncScheduling.RecentlyActiveMinutes) * time.Minute
+	}
+	if recentlyActive > 0 && !update.LastActive.IsZero() && timeNow().Sub(update.LastActive) < recentlyActive {
+		return priorityHigh
+	}
+	return priorityLow
+}
+
+// archivedDelay returns the amount of time between scheduled updates of the
+// repos archived by the archival policy.
+func archivedDelay(c *conf.Unified) time.Duration {
+	if c != nil && c.RepoSyncScheduling != nil && c.RepoSyncScheduling.ArchivedIntervalHours != nil {
+		if hours := *c.RepoSyncScheduling.ArchivedIntervalHours; hours > 0 {
+			return time.Duration(hours) * time.Hour
+		}
+	}
+	return defaultArchivedDelay
+}
+
 // DebugDump returns the state of the update scheduler for debugging.
 func (s *UpdateScheduler) DebugDump(ctx context.Context) any {
 	data := struct {
@@ -690,6 +732,9 @@ type schedule struct {
 	randGenerator interface {
 		Int63n(n int64) int64
 	}
+
+	// archived is the set of repos whose sync frequency is reduced.
+	archived map[api.RepoID]struct{}
 }
 
 // scheduledRepoUpdate is the update schedule for a single repo.
@@ -697,7 +742,9 @@ type scheduledRepoUpdate struct {
 	Repo     configuredRepo // the repo to update
 	Interval time.Duration  // how regularly the repo is updated
 	Due      time.Time      // the next time that the repo will be enqueued for a update
-	Index    int            `json:"-"` // the index in the heap
+	// LastActive is the last time the repo was accessed, i.e. updated on demand.
+	LastActive time.Time
+	Index      int `json:"-"` // the index in the heap
 }
 
 // upsert inserts or updates a repo in the schedule.
@@ -805,9 +852,14 @@ func (s *schedule) updateInterval(repo configuredRepo, interval time.Duration) {
 
 	s.mu.Lock()
 	if update := s.index[repo.ID]; update != nil {
+		maxInterval := maxDelay
+		if _, ok := s.archived[repo.ID]; ok {
+			maxInterval = archivedDelay(conf.Get())
+		}
+
 		switch {
-		case interval > maxDelay:
-			update.Interval = maxDelay
+		case interval > maxInterval:
+			update.Interval = maxInterval
 		case interval < minDelay:
 			update.Interval = minDelay
 		default:
@@ -829,6 +881,27 @@ func (s *schedule) updateInterval(repo configuredRepo, interval time.Duration) {
 	s.mu.Unlock()
 }
 
+// setArchived replaces the set of repos whose sync frequency is reduced.
+func (s *schedule) setArchived(ids []api.RepoID) {
+	archived := make(map[api.RepoID]struct{}, len(ids))
+	for _, id := range ids {
+		archived[id] = struct{}{}
+	}
+
+	s.mu.Lock()
+	s.archived = archived
+	s.mu.Unlock()
+}
+
+// isArchived returns true if the sync frequency of the repo is reduced.
+func (s *schedule) isArchived(repo configuredRepo) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.archived[repo.ID]
+	return ok
+}
+
 // getCurrentInterval gets the current interval for the supplied repo and a bool
 // indicating whether it was found.
 func (s *schedule) getCurrentInterval(repo configuredRepo) (time.Duration, bool) {
@@ -842,6 +915,16 @@ func (s *schedule) getCurrentInterval(repo configuredRepo) (time.Duration, bool)
 	return update.Interval, true
 }
 
+// markActive records that the repo was accessed.
+func (s *schedule) markActive(repo configuredRepo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if update := s.index[repo.ID]; update != nil {
+		update.LastActive = timeNow()
+	}
+}
+
 // remove removes a repo from the schedule.
 func (s *schedule) remove(repo configuredRepo) (removed bool) {
 	if repo.ID == 0 {
@@ -899,6 +899,7 @@ func TestSchedule_updateInterval(t *testing.T) {
 	tests := []struct {
 		name                string
 		initialSchedule     []*scheduledRepoUpdate
+		archived            []api.RepoID
 		updateCalls         []*updateCall
 		finalSchedule       []*scheduledRepoUpdate
 		timeAfterFuncDelays []time.Duration
@@ -988,6 +989,33 @@ func TestSchedule_updateInterval(t *testing.T) {
 			timeAfterFuncDelays: []time.Duration{maxDelay},
 			wakeupNotifications: 1,
 		},
+		{
+			name: "maximum interval of archived repos",
+			initialSchedule: []*scheduledRepoUpdate{
+				{
+					Repo:     a,
+					Interval: minDelay,
+					Due:      defaultTime.Add(minDelay),
+				},
+			},
+			archived: []api.RepoID{a.ID},
+			updateCalls: []*updateCall{
+				{
+					repo:     a,
+					time:     defaultTime,
+					interval: 365 * 25 * time.Hour,
+				},
+			},
+			finalSchedule: []*scheduledRepoUpdate{
+				{
+					Repo:     a,
+					Interval: defaultArchivedDelay,
+					Due:      defaultTime.Add(defaultArchivedDelay),
+				},
+			},
+			timeAfterFuncDelays: []time.Duration{defaultArchivedDelay},
+			wakeupNotifications: 1,
+		},
 		{
 			name: "update later",
 			initialSchedule: []*scheduledRepoUpdate{
@@ -1050,6 +1078,7 @@ func TestSchedule_updateInterval(t *testing.T) {
 			s := NewUpdateScheduler(logtest.Scoped(t), database.NewMockDB())
 			setupInitialSchedule(s, test.initialSchedule)
 			s.schedule.randGenerator = &mockRandomGenerator{}
+			s.SetArchived(test.archived)
 
 			for _, call := range test.updateCalls {
 				mockTime(call.time)
@@ -1266,6 +1295,25 @@ func TestUpdateScheduler_runSchedule(t *testing.T) {
 				return []chan struct{}{s.updateQueue.notifyEnqueue, s.schedule.wakeup}
 			},
 		},
+		{
+			name: "recently active update due, queued with high priority",
+			initialSchedule: []*scheduledRepoUpdate{
+				{Repo: a, Interval: 11 * time.Second, Due: defaultTime, LastActive: defaultTime.Add(-time.Minute)},
+				{Repo: b, Interval: 22 * time.Second, Due: defaultTime, LastActive: defaultTime.Add(-2 * time.Hour)},
+			},
+			finalSchedule: []*scheduledRepoUpdate{
+				{Repo: a, Interval: 11 * time.Second, Due: defaultTime.Add(11 * time.Second), LastActive: defaultTime.Add(-time.Minute)},
+				{Repo: b, Interval: 22 * time.Second, Due: defaultTime.Add(22 * time.Second), LastActive: defaultTime.Add(-2 * time.Hour)},
+			},
+			finalQueue: []*repoUpdate{
+				{Repo: a, Priority: priorityHigh, Seq: 1},
+				{Repo: b, Priority: priorityLow, Seq: 2},
+			},
+			timeAfterFuncDelays: []time.Duration{11 * time.Second},
+			expectedNotifications: func(s *UpdateScheduler) []chan struct{} {
+				return []chan struct{}{s.updateQueue.notifyEnqueue, s.updateQueue.notifyEnqueue, s.schedule.wakeup}
+			},
+		},
 		{
 			name: "all updates due",
 			initialSchedule: []*scheduledRepoUpdate{
@@ -1612,3 +1660,46 @@ func TestGetCustomInterval(t *testing.T) {
 		})
 	}
 }
+
+func TestArchivedDelay(t *testing.T) {
+	hours := func(n int) *conf.Unified {
+		return &conf.Unified{
+			SiteConfiguration: schema.SiteConfiguration{
+				RepoSyncScheduling: &schema.RepoSyncScheduling{ArchivedIntervalHours: &n},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name string
+		c    *conf.Unified
+		want time.Duration
+	}{
+		{
+			name: "Nil config",
+			c:    nil,
+			want: defaultArchivedDelay,
+		},
+		{
+			name: "Unset",
+			c:    &conf.Unified{SiteConfiguration: schema.SiteConfiguration{RepoSyncScheduling: &schema.RepoSyncScheduling{}}},
+			want: defaultArchivedDelay,
+		},
+		{
+			name: "Configured",
+			c:    hours(24),
+			want: 24 * time.Hour,
+		},
+		{
+			name: "Invalid",
+			c:    hours(0),
+			want: defaultArchivedDelay,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := archivedDelay(tc.c); got != tc.want {
+				t.Fatalf("Want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/keegancsmith/sqlf"
@@ -388,11 +389,24 @@ func (s *store) CreateExternalServiceRepo(ctx context.Context, svc *types.Extern
 		return err
 	}
 
-	return s.Exec(ctx, sqlf.Sprintf(upsertExternalServiceRepoQuery,
+	if err := s.Exec(ctx, sqlf.Sprintf(upsertExternalServiceRepoQuery,
 		svc.ID,
 		r.ID,
 		src.CloneURL,
-	))
+	)); err != nil {
+		return err
+	}
+
+	return database.OutboxEventsWith(s).Publish(ctx, database.OutboxEventArgs{
+		EventType:     types.OutboxEventRepoCreated,
+		AggregateType: "repo",
+		AggregateID:   strconv.Itoa(int(r.ID)),
+		Payload: map[string]any{
+			"repo_id":             r.ID,
+			"name":                r.Name,
+			"external_service_id": svc.ID,
+		},
+	})
 }
 
 const createRepoQuery = `
@@ -624,7 +638,8 @@ func (s *store) EnqueueSyncJobs(ctx context.Context, isCloud bool) (err error) {
 }
 
 // We ignore Phabricator repos here as they are currently synced using
-// RunPhabricatorRepositorySyncWorker
+// RunPhabricatorRepositorySyncWorker, which also enqueues the sync jobs of the
+// connections with syncRepositories enabled.
 const enqueueSyncJobsQueryFmtstr = `
 WITH due AS (
     SELECT id
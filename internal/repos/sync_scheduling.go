@@ -0,0 +1,263 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// codeHostSyncRule is a parsed repoSyncScheduling.codeHosts site configuration
+// entry.
+type codeHostSyncRule struct {
+	// kind is the upper case external service kind.
+	kind string
+	// url is the normalized code host URL, or empty if the rule matches all
+	// external services of kind.
+	url                string
+	maxConcurrentSyncs int
+	windows            []syncWindow
+}
+
+// syncWindow is a daily time window, as offsets from midnight UTC. end is before
+// start if the window spans midnight.
+type syncWindow struct {
+	start, end time.Duration
+}
+
+// contains returns whether t is in the window.
+func (w syncWindow) contains(t time.Time) bool {
+	offset := t.Sub(midnight(t))
+	if w.start <= w.end {
+		return offset >= w.start && offset < w.end
+	}
+	return offset >= w.start || offset < w.end
+}
+
+// nextStart returns the first start of the window after t.
+func (w syncWindow) nextStart(t time.Time) time.Time {
+	start := midnight(t).Add(w.start)
+	if !start.After(t) {
+		start = start.AddDate(0, 0, 1)
+	}
+	return start
+}
+
+func midnight(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// parseClock parses a time of the day in the format HH:MM into an offset from
+// midnight.
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid time of day %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func parseCodeHostSyncRule(c *schema.RepoSyncCodeHost) (codeHostSyncRule, error) {
+	rule := codeHostSyncRule{
+		kind:               strings.ToUpper(c.Kind),
+		maxConcurrentSyncs: c.MaxConcurrentSyncs,
+	}
+	if c.Url != "" {
+		u, err := url.Parse(c.Url)
+		if err != nil {
+			return rule, errors.Wrapf(err, "invalid code host URL %q", c.Url)
+		}
+		rule.url = extsvc.NormalizeBaseURL(u).String()
+	}
+	for _, w := range c.Windows {
+		start, err := parseClock(w.Start)
+		if err != nil {
+			return rule, err
+		}
+		end, err := parseClock(w.End)
+		if err != nil {
+			return rule, err
+		}
+		rule.windows = append(rule.windows, syncWindow{start: start, end: end})
+	}
+	return rule, nil
+}
+
+// confCodeHostSyncRules returns the valid code host sync rules of c, in order.
+func confCodeHostSyncRules(logger log.Logger, c *conf.Unified) []codeHostSyncRule {
+	if c == nil || c.RepoSyncScheduling == nil {
+		return nil
+	}
+	rules := make([]codeHostSyncRule, 0, len(c.RepoSyncScheduling.CodeHosts))
+	for _, rc := range c.RepoSyncScheduling.CodeHosts {
+		rule, err := parseCodeHostSyncRule(rc)
+		if err != nil {
+			logger.Warn("error parsing repoSyncScheduling rule", log.String("kind", rc.Kind), log.Error(err))
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// matchCodeHostSyncRule returns the index of the first rule that matches svc, or
+// -1 if none does.
+func matchCodeHostSyncRule(ctx context.Context, rules []codeHostSyncRule, svc *types.ExternalService) (int, error) {
+	var id string
+	for i, rule := range rules {
+		if !strings.EqualFold(rule.kind, svc.Kind) {
+			continue
+		}
+		if rule.url == "" {
+			return i, nil
+		}
+		if id == "" {
+			config, err := svc.Config.Decrypt(ctx)
+			if err != nil {
+				return -1, err
+			}
+			if id, err = extsvc.UniqueCodeHostIdentifier(svc.Kind, config); err != nil {
+				return -1, err
+			}
+		}
+		if rule.url == id {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// nextWindowStart returns the first time at or after t that is in one of the
+// windows of the rule. It returns t if the rule has no windows.
+func (r codeHostSyncRule) nextWindowStart(t time.Time) time.Time {
+	if len(r.windows) == 0 {
+		return t
+	}
+	var next time.Time
+	for _, w := range r.windows {
+		if w.contains(t) {
+			return t
+		}
+		if start := w.nextStart(t); next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return next
+}
+
+// syncWindowStart delays nextSyncAt, the time of the next periodic sync of svc,
+// to the start of the next sync window of the rule that matches svc, if any.
+func syncWindowStart(ctx context.Context, logger log.Logger, svc *types.ExternalService, nextSyncAt time.Time) time.Time {
+	rules := confCodeHostSyncRules(logger, conf.Get())
+	i, err := matchCodeHostSyncRule(ctx, rules, svc)
+	if err != nil {
+		logger.Warn("error matching repoSyncScheduling rules", log.Error(err))
+		return nextSyncAt
+	}
+	if i < 0 {
+		return nextSyncAt
+	}
+	return rules[i].nextWindowStart(nextSyncAt)
+}
+
+// syncConcurrencyLimiter limits the number of concurrent syncs of the external
+// services that match a code host sync rule with maxConcurrentSyncs set.
+type syncConcurrencyLimiter struct {
+	mu sync.Mutex
+	// rules maps external service IDs to the key of the rule they matched when
+	// jobs were last dequeued.
+	rules map[int64]string
+	// syncing maps the IDs of the external services being synced to the key of
+	// their rule.
+	syncing map[int64]string
+	// running is the number of running syncs per rule key.
+	running map[string]int
+}
+
+func newSyncConcurrencyLimiter() *syncConcurrencyLimiter {
+	return &syncConcurrencyLimiter{
+		rules:   map[int64]string{},
+		syncing: map[int64]string{},
+		running: map[string]int{},
+	}
+}
+
+func (r codeHostSyncRule) key() string {
+	return r.kind + " " + r.url
+}
+
+// exclusions returns the dequeue conditions that exclude the external services
+// whose rule has reached its maximum number of concurrent syncs.
+func (l *syncConcurrencyLimiter) exclusions(ctx context.Context, store database.ExternalServiceStore, rules []codeHostSyncRule) ([]*sqlf.Query, error) {
+	var kinds []string
+	for _, rule := range rules {
+		if rule.maxConcurrentSyncs > 0 {
+			kinds = append(kinds, rule.kind)
+		}
+	}
+	if len(kinds) == 0 {
+		l.mu.Lock()
+		l.rules = map[int64]string{}
+		l.mu.Unlock()
+		return nil, nil
+	}
+
+	svcs, err := store.List(ctx, database.ExternalServicesListOptions{Kinds: kinds})
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rules = make(map[int64]string, len(svcs))
+	var excluded []int64
+	for _, svc := range svcs {
+		i, err := matchCodeHostSyncRule(ctx, rules, svc)
+		if err != nil || i < 0 || rules[i].maxConcurrentSyncs == 0 {
+			continue
+		}
+		key := rules[i].key()
+		l.rules[svc.ID] = key
+		if l.running[key] >= rules[i].maxConcurrentSyncs {
+			excluded = append(excluded, svc.ID)
+		}
+	}
+	if len(excluded) == 0 {
+		return nil, nil
+	}
+	return []*sqlf.Query{sqlf.Sprintf("NOT (external_service_id = ANY(%s))", pq.Array(excluded))}, nil
+}
+
+// acquire records the start of a sync of the external service.
+func (l *syncConcurrencyLimiter) acquire(externalServiceID int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if key, ok := l.rules[externalServiceID]; ok {
+		l.syncing[externalServiceID] = key
+		l.running[key]++
+	}
+}
+
+// release records the end of a sync of the external service.
+func (l *syncConcurrencyLimiter) release(externalServiceID int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if key, ok := l.syncing[externalServiceID]; ok {
+		delete(l.syncing, externalServiceID)
+		l.running[key]--
+	}
+}
@@ -0,0 +1,112 @@
+package repos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestCodeHostSyncRuleNextWindowStart(t *testing.T) {
+	rule, err := parseCodeHostSyncRule(&schema.RepoSyncCodeHost{
+		Kind: extsvc.KindGitHub,
+		Windows: []*schema.RepoSyncWindow{
+			{Start: "22:00", End: "06:00"},
+			{Start: "12:00", End: "13:00"},
+		},
+	})
+	require.NoError(t, err)
+
+	day := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		at   time.Duration
+		want time.Time
+	}{
+		{at: 23 * time.Hour, want: day.Add(23 * time.Hour)},
+		{at: 5 * time.Hour, want: day.Add(5 * time.Hour)},
+		{at: 6 * time.Hour, want: day.Add(12 * time.Hour)},
+		{at: 12*time.Hour + 30*time.Minute, want: day.Add(12*time.Hour + 30*time.Minute)},
+		{at: 13 * time.Hour, want: day.Add(22 * time.Hour)},
+	} {
+		assert.Equal(t, tc.want, rule.nextWindowStart(day.Add(tc.at)), "at %s", tc.at)
+	}
+
+	_, err = parseCodeHostSyncRule(&schema.RepoSyncCodeHost{
+		Kind:    extsvc.KindGitHub,
+		Windows: []*schema.RepoSyncWindow{{Start: "25:00", End: "06:00"}},
+	})
+	assert.Error(t, err)
+}
+
+func TestMatchCodeHostSyncRule(t *testing.T) {
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+
+	rules := confCodeHostSyncRules(logger, &conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		RepoSyncScheduling: &schema.RepoSyncScheduling{
+			CodeHosts: []*schema.RepoSyncCodeHost{
+				{Kind: "github", Url: "https://ghe.example.com"},
+				{Kind: extsvc.KindGitLab},
+			},
+		},
+	}})
+	require.Len(t, rules, 2)
+
+	ghe := &types.ExternalService{
+		Kind:   extsvc.KindGitHub,
+		Config: extsvc.NewUnencryptedConfig(`{"url": "https://ghe.example.com/", "token": "abc", "repositoryQuery": ["none"]}`),
+	}
+	dotcom := &types.ExternalService{
+		Kind:   extsvc.KindGitHub,
+		Config: extsvc.NewUnencryptedConfig(`{"url": "https://github.com", "token": "abc", "repositoryQuery": ["none"]}`),
+	}
+	gitlab := &types.ExternalService{
+		Kind:   extsvc.KindGitLab,
+		Config: extsvc.NewUnencryptedConfig(`{"url": "https://gitlab.com", "token": "abc", "projectQuery": ["none"]}`),
+	}
+
+	for svc, want := range map[*types.ExternalService]int{ghe: 0, dotcom: -1, gitlab: 1} {
+		i, err := matchCodeHostSyncRule(ctx, rules, svc)
+		require.NoError(t, err)
+		assert.Equal(t, want, i)
+	}
+}
+
+func TestSyncConcurrencyLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	svcs := []*types.ExternalService{
+		{ID: 1, Kind: extsvc.KindGitHub, Config: extsvc.NewUnencryptedConfig(`{}`)},
+		{ID: 2, Kind: extsvc.KindGitHub, Config: extsvc.NewUnencryptedConfig(`{}`)},
+	}
+	store := database.NewMockExternalServiceStore()
+	store.ListFunc.SetDefaultReturn(svcs, nil)
+
+	rules := []codeHostSyncRule{{kind: extsvc.KindGitHub, maxConcurrentSyncs: 1}}
+	l := newSyncConcurrencyLimiter()
+
+	conds, err := l.exclusions(ctx, store, rules)
+	require.NoError(t, err)
+	assert.Empty(t, conds)
+
+	l.acquire(1)
+	conds, err = l.exclusions(ctx, store, rules)
+	require.NoError(t, err)
+	require.Len(t, conds, 1)
+	assert.Equal(t, []any{pq.Array([]int64{1, 2})}, conds[0].Args())
+
+	l.release(1)
+	conds, err = l.exclusions(ctx, store, rules)
+	require.NoError(t, err)
+	assert.Empty(t, conds)
+}
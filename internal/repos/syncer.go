@@ -24,6 +24,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -79,6 +80,7 @@ func (s *Syncer) Run(ctx context.Context, store Store, opts RunOptions) error {
 			syncer:          s,
 			store:           store,
 			minSyncInterval: opts.MinSyncInterval,
+			limiter:         newSyncConcurrencyLimiter(),
 		}, SyncWorkerOptions{
 			WorkerInterval: opts.DequeueInterval,
 			NumHandlers:    ConfRepoConcurrentExternalServiceSyncers(),
@@ -109,6 +111,30 @@ type syncHandler struct {
 	syncer          *Syncer
 	store           Store
 	minSyncInterval func() time.Duration
+	limiter         *syncConcurrencyLimiter
+}
+
+var (
+	_ workerutil.WithPreDequeue      = &syncHandler{}
+	_ workerutil.WithHooks[*SyncJob] = &syncHandler{}
+)
+
+// PreDequeue excludes the external services whose code host sync rule has
+// reached its maximum number of concurrent syncs from being dequeued.
+func (s *syncHandler) PreDequeue(ctx context.Context, logger log.Logger) (bool, any, error) {
+	conditions, err := s.limiter.exclusions(ctx, s.store.ExternalServiceStore(), confCodeHostSyncRules(logger, conf.Get()))
+	if err != nil {
+		return false, nil, err
+	}
+	return true, conditions, nil
+}
+
+func (s *syncHandler) PreHandle(_ context.Context, _ log.Logger, sj *SyncJob) {
+	s.limiter.acquire(sj.ExternalServiceID)
+}
+
+func (s *syncHandler) PostHandle(_ context.Context, _ log.Logger, sj *SyncJob) {
+	s.limiter.release(sj.ExternalServiceID)
 }
 
 func (s *syncHandler) Handle(ctx context.Context, _ log.Logger, sj *SyncJob) (err error) {
@@ -526,7 +552,8 @@ func (s *Syncer) SyncExternalService(
 		now := s.Now()
 		interval := calcSyncInterval(now, svc.LastSyncAt, minSyncInterval, modified, err)
 
-		nextSyncAt := now.Add(interval)
+		// Periodic syncs only start in the sync windows of the code host, if any.
+		nextSyncAt := syncWindowStart(ctx, logger, svc, now.Add(interval))
 		lastSyncAt := now
 
 		// We call Update here instead of Upsert, because upsert stores all fields of the external
@@ -427,6 +427,22 @@ func (c *Client) ExternalServiceRepositories(ctx context.Context, args protocol.
 	return result, err
 }
 
+// PerforceDepotProgress retrieves the import progress of the depots of the given
+// Perforce external service. It is only served over HTTP.
+func (c *Client) PerforceDepotProgress(ctx context.Context, args protocol.PerforceDepotProgressArgs) (result *protocol.PerforceDepotProgressResult, err error) {
+	resp, err := c.httpPost(ctx, "perforce-depot-progress", args)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err == nil && result != nil && result.Error != "" {
+		err = errors.New(result.Error)
+	}
+	return result, err
+}
+
 func (c *Client) httpPost(ctx context.Context, method string, payload any) (resp *http.Response, err error) {
 	reqBody, err := json.Marshal(payload)
 	if err != nil {
@@ -411,6 +411,9 @@ type PermsSyncRequest struct {
 	Reason            database.PermissionsSyncJobReason `json:"reason"`
 	TriggeredByUserID int32                             `json:"triggered_by_user_id"`
 	ProcessAfter      time.Time                         `json:"process_after"`
+	// Coalesce merges the requested syncs into queued syncs for the same users or
+	// repos, if any. It only applies to requests with a ProcessAfter.
+	Coalesce bool `json:"coalesce"`
 }
 
 // PermsSyncResponse is a response to sync permissions.
@@ -520,3 +523,34 @@ func ExternalServiceRepositoriesResultFromProto(p *proto.ExternalServiceReposito
 	}
 	return &ExternalServiceRepositoriesResult{Repos: repos}
 }
+
+// PerforceDepotProgressArgs are the arguments to the perforce-depot-progress
+// endpoint.
+type PerforceDepotProgressArgs struct {
+	// ExternalServiceID is the ID of the Perforce external service.
+	ExternalServiceID int64
+}
+
+// PerforceDepotProgressResult is the import progress of the depots of a
+// Perforce external service.
+type PerforceDepotProgressResult struct {
+	Depots []PerforceDepotProgress
+	Error  string `json:",omitempty"`
+}
+
+// PerforceDepotProgress is the import progress of a single Perforce depot.
+type PerforceDepotProgress struct {
+	Depot       string
+	Repo        api.RepoName
+	CloneStatus types.CloneStatus
+	// ImportedChangelist is the latest changelist imported into the repository,
+	// or 0 if none has been imported yet.
+	ImportedChangelist int64
+	// LatestChangelist is the latest submitted changelist of the depot on the
+	// Perforce server, or 0 if it could not be determined.
+	LatestChangelist int64
+	LastFetched      time.Time
+	// Error is the last error of the clone or fetch of the depot, or the error
+	// that occurred while determining its latest changelist.
+	Error string `json:",omitempty"`
+}
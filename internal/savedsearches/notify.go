@@ -0,0 +1,203 @@
+package savedsearches
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/slack-go/slack"
+
+	"github.com/sourcegraph/sourcegraph/internal/api/internalapi"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+	"github.com/sourcegraph/sourcegraph/internal/webhooks/outbound"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// maxDisplayedResults is the maximum number of results listed in emails and
+// Slack messages. Webhook payloads include all results.
+const maxDisplayedResults = 10
+
+// notification is a notification about the new results of a saved search.
+type notification struct {
+	SavedSearchID int32
+	Description   string
+	Query         string
+	Results       []database.SavedSearchSubscriptionResult
+}
+
+type subscriptionNotifier interface {
+	// Notify delivers n to the subscriber of sub on all of the channels of sub.
+	Notify(ctx context.Context, sub *database.SavedSearchSubscription, n notification) error
+}
+
+type notifier struct {
+	db   database.DB
+	doer httpcli.Doer
+}
+
+func (nt *notifier) Notify(ctx context.Context, sub *database.SavedSearchSubscription, n notification) error {
+	externalURL, err := url.Parse(conf.ExternalURL())
+	if err != nil {
+		return err
+	}
+	doer := nt.doer
+	if doer == nil {
+		doer = httpcli.ExternalDoer
+	}
+
+	var errs error
+	if sub.NotifyEmail {
+		if err := nt.sendEmail(ctx, sub.UserID, newEmailData(externalURL, n)); err != nil {
+			errs = errors.Append(errs, errors.Wrap(err, "email"))
+		}
+	}
+	if sub.WebhookURL != nil && *sub.WebhookURL != "" {
+		if err := outbound.PostJSON(ctx, doer, *sub.WebhookURL, newWebhookPayload(externalURL, n)); err != nil {
+			errs = errors.Append(errs, errors.Wrap(err, "webhook"))
+		}
+	}
+	if sub.SlackWebhookURL != nil && *sub.SlackWebhookURL != "" {
+		if err := outbound.PostJSON(ctx, doer, *sub.SlackWebhookURL, newSlackMessage(externalURL, n)); err != nil {
+			errs = errors.Append(errs, errors.Wrap(err, "Slack"))
+		}
+	}
+	return errs
+}
+
+var newResultsEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `Sourcegraph saved search {{.Description}} found {{.TotalCount}} new {{if eq .TotalCount 1}}result{{else}}results{{end}}`,
+	Text: `
+Your saved search "{{.Description}}" found {{.TotalCount}} new {{if eq .TotalCount 1}}result{{else}}results{{end}}:
+{{range .Results}}
+- {{.Repository}}{{if .Path}} {{.Path}}{{end}}{{if .Commit}} ({{.Commit}}){{end}}: {{.URL}}
+{{- end}}
+{{if .TruncatedCount}}
+...and {{.TruncatedCount}} more.
+{{end}}
+View all results: {{.SearchURL}}
+`,
+	HTML: `
+<p>Your saved search <strong>{{.Description}}</strong> found {{.TotalCount}} new {{if eq .TotalCount 1}}result{{else}}results{{end}}:</p>
+<ul>
+{{range .Results}}
+<li><a href="{{.URL}}">{{.Repository}}{{if .Path}} {{.Path}}{{end}}{{if .Commit}} ({{.Commit}}){{end}}</a></li>
+{{end}}
+</ul>
+{{if .TruncatedCount}}<p>...and {{.TruncatedCount}} more.</p>{{end}}
+<p><a href="{{.SearchURL}}">View all results</a></p>
+`,
+})
+
+type emailData struct {
+	Description    string
+	SearchURL      string
+	Results        []database.SavedSearchSubscriptionResult
+	TotalCount     int
+	TruncatedCount int
+}
+
+func newEmailData(externalURL *url.URL, n notification) *emailData {
+	results := absoluteResults(externalURL, n.Results)
+	truncated := 0
+	if len(results) > maxDisplayedResults {
+		truncated = len(results) - maxDisplayedResults
+		results = results[:maxDisplayedResults]
+	}
+	return &emailData{
+		Description:    n.Description,
+		SearchURL:      searchURL(externalURL, n.Query),
+		Results:        results,
+		TotalCount:     len(n.Results),
+		TruncatedCount: truncated,
+	}
+}
+
+func (nt *notifier) sendEmail(ctx context.Context, userID int32, data *emailData) error {
+	email, verified, err := nt.db.UserEmails().GetPrimaryEmail(ctx, userID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return errors.Errorf("unable to send email to user ID %d with unknown email address", userID)
+		}
+		return err
+	}
+	if !verified {
+		return errors.Newf("unable to send email to user ID %d's unverified primary email address", userID)
+	}
+
+	return internalapi.Client.SendEmail(ctx, "saved-search-subscription", txtypes.Message{
+		To:       []string{email},
+		Template: newResultsEmailTemplates,
+		Data:     data,
+	})
+}
+
+type webhookPayload struct {
+	SavedSearchID string                                   `json:"savedSearchID"`
+	Description   string                                   `json:"description"`
+	Query         string                                   `json:"query"`
+	SearchURL     string                                   `json:"searchURL"`
+	Results       []database.SavedSearchSubscriptionResult `json:"results"`
+}
+
+func newWebhookPayload(externalURL *url.URL, n notification) webhookPayload {
+	return webhookPayload{
+		SavedSearchID: string(relay.MarshalID("SavedSearch", n.SavedSearchID)),
+		Description:   n.Description,
+		Query:         n.Query,
+		SearchURL:     searchURL(externalURL, n.Query),
+		Results:       absoluteResults(externalURL, n.Results),
+	}
+}
+
+func newSlackMessage(externalURL *url.URL, n notification) *slack.WebhookMessage {
+	newMarkdownSection := func(s string) slack.Block {
+		return slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", s, false, false), nil, nil)
+	}
+
+	blocks := []slack.Block{
+		newMarkdownSection(fmt.Sprintf("Sourcegraph saved search *%s* found *%d* new results.", n.Description, len(n.Results))),
+	}
+	results := absoluteResults(externalURL, n.Results)
+	for i, res := range results {
+		if i == maxDisplayedResults {
+			blocks = append(blocks, newMarkdownSection(fmt.Sprintf(
+				"...and <%s|%d more>.", searchURL(externalURL, n.Query), len(results)-maxDisplayedResults)))
+			break
+		}
+		label := res.Repository
+		if res.Path != "" {
+			label += " " + res.Path
+		}
+		if res.Commit != "" {
+			label += fmt.Sprintf(" (%.7s)", res.Commit)
+		}
+		blocks = append(blocks, newMarkdownSection(fmt.Sprintf("<%s|%s>", res.URL, label)))
+	}
+	return &slack.WebhookMessage{Blocks: &slack.Blocks{BlockSet: blocks}}
+}
+
+// absoluteResults returns a copy of results with URLs resolved against
+// externalURL.
+func absoluteResults(externalURL *url.URL, results []database.SavedSearchSubscriptionResult) []database.SavedSearchSubscriptionResult {
+	out := make([]database.SavedSearchSubscriptionResult, len(results))
+	for i, res := range results {
+		u, err := url.Parse(res.URL)
+		if err == nil {
+			res.URL = externalURL.ResolveReference(u).String()
+		}
+		out[i] = res
+	}
+	return out
+}
+
+func searchURL(externalURL *url.URL, query string) string {
+	u := externalURL.ResolveReference(&url.URL{Path: "/search"})
+	u.RawQuery = url.Values{"q": []string{query}}.Encode()
+	return u.String()
+}
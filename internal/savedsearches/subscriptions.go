@@ -0,0 +1,262 @@
+// Package savedsearches runs the saved searches that users subscribed to and
+// notifies them of new results.
+package savedsearches
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	// subscriptionsPerRun is the maximum number of subscriptions that are run
+	// per iteration of the runner.
+	subscriptionsPerRun = 50
+
+	// maxSearchDuration is the maximum time a single saved search may run.
+	maxSearchDuration = time.Minute
+
+	// maxPendingResults is the maximum number of new results that are kept
+	// for the next notification of a subscription.
+	maxPendingResults = 1000
+)
+
+// NewSubscriptionRunner returns a background routine that runs the saved
+// searches of due subscriptions and notifies the subscribers of new results.
+func NewSubscriptionRunner(observationCtx *observation.Context, db database.DB, enterpriseJobs jobutil.EnterpriseJobs) goroutine.BackgroundRoutine {
+	r := &subscriptionRunner{
+		logger:   observationCtx.Logger.Scoped("SubscriptionRunner", "runs saved search subscriptions"),
+		db:       db,
+		search:   newSearchFunc(observationCtx.Logger, db, enterpriseJobs),
+		notifier: &notifier{db: db},
+	}
+	return goroutine.NewPeriodicGoroutine(
+		context.Background(),
+		goroutine.HandlerFunc(r.runDue),
+		goroutine.WithName("saved_searches.subscription_runner"),
+		goroutine.WithDescription("runs saved search subscriptions and notifies subscribers of new results"),
+		goroutine.WithInterval(time.Minute),
+	)
+}
+
+// searchFunc runs query and returns its results.
+type searchFunc func(ctx context.Context, query string) (result.Matches, error)
+
+func newSearchFunc(logger log.Logger, db database.DB, enterpriseJobs jobutil.EnterpriseJobs) searchFunc {
+	searchClient := client.New(logger, db, enterpriseJobs)
+	return func(ctx context.Context, query string) (result.Matches, error) {
+		inputs, err := searchClient.Plan(ctx, "V3", nil, query, search.Precise, search.Streaming)
+		if err != nil {
+			return nil, errcode.MakeNonRetryable(err)
+		}
+		agg := streaming.NewAggregatingStream()
+		if _, err := searchClient.Execute(ctx, agg, inputs); err != nil {
+			return nil, err
+		}
+		return agg.Results, nil
+	}
+}
+
+type subscriptionRunner struct {
+	logger   log.Logger
+	db       database.DB
+	search   searchFunc
+	notifier subscriptionNotifier
+}
+
+func (r *subscriptionRunner) runDue(ctx context.Context) error {
+	subscriptions, err := r.db.SavedSearchSubscriptions().ListDue(ctx, subscriptionsPerRun)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subscriptions {
+		run := r.run(ctx, sub)
+		if run.Error != "" {
+			r.logger.Warn("saved search subscription failed",
+				log.Int32("subscriptionID", sub.ID),
+				log.Int32("savedSearchID", sub.SavedSearchID),
+				log.String("error", run.Error))
+		}
+		if err := r.db.SavedSearchSubscriptions().RecordRun(ctx, sub.ID, run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run runs the saved search of the subscription and delivers the new results
+// if they are due.
+func (r *subscriptionRunner) run(ctx context.Context, sub *database.SavedSearchSubscription) database.SavedSearchSubscriptionRun {
+	ss, err := r.db.SavedSearches().GetByID(ctx, sub.SavedSearchID)
+	if err != nil {
+		return database.SavedSearchSubscriptionRun{Error: err.Error()}
+	}
+
+	// 🚨 SECURITY: The subscriber may have lost access to the saved search since
+	// they subscribed to it, and the search must only return results the
+	// subscriber has access to.
+	if err := r.checkAccess(ctx, ss.Config, sub.UserID); err != nil {
+		return database.SavedSearchSubscriptionRun{Error: err.Error()}
+	}
+	searchCtx, cancel := context.WithTimeout(actor.WithActor(ctx, actor.FromUser(sub.UserID)), maxSearchDuration)
+	defer cancel()
+
+	matches, err := r.search(searchCtx, ss.Config.Query)
+	if err != nil {
+		return database.SavedSearchSubscriptionRun{Error: err.Error()}
+	}
+
+	keys, newResults := diffResults(sub.LastResultKeys, matches)
+	run := database.SavedSearchSubscriptionRun{ResultKeys: keys}
+
+	switch {
+	case sub.LastRunAt == nil:
+		// The first run only records the current results, all of which would
+		// otherwise be new.
+	case sub.Muted:
+		// Results found while muted are never delivered.
+	default:
+		run.PendingResults = append(sub.PendingResults, newResults...)
+		if len(run.PendingResults) > maxPendingResults {
+			run.PendingResults = run.PendingResults[:maxPendingResults]
+		}
+	}
+
+	if len(run.PendingResults) == 0 || !digestDue(sub, time.Now()) {
+		return run
+	}
+
+	n := notification{
+		SavedSearchID: sub.SavedSearchID,
+		Description:   ss.Config.Description,
+		Query:         ss.Config.Query,
+		Results:       run.PendingResults,
+	}
+	if err := r.notifier.Notify(ctx, sub, n); err != nil {
+		// Keep the results pending to deliver them after the next run.
+		run.Error = errors.Wrap(err, "notifying subscriber").Error()
+		return run
+	}
+	run.PendingResults = nil
+	run.Notified = true
+	return run
+}
+
+func (r *subscriptionRunner) checkAccess(ctx context.Context, ss api.ConfigSavedQuery, userID int32) error {
+	switch {
+	case ss.UserID != nil:
+		if *ss.UserID != userID {
+			return errors.New("subscriber does not own the saved search")
+		}
+	case ss.OrgID != nil:
+		if _, err := r.db.OrgMembers().GetByOrgIDAndUserID(ctx, *ss.OrgID, userID); err != nil {
+			if errcode.IsNotFound(err) {
+				return errors.New("subscriber is not a member of the organization that owns the saved search")
+			}
+			return err
+		}
+	default:
+		return errors.New("no Org ID or User ID associated with saved search")
+	}
+	return nil
+}
+
+// digestDue reports whether new results of the subscription may be delivered
+// at now, according to its digest interval.
+func digestDue(sub *database.SavedSearchSubscription, now time.Time) bool {
+	if sub.DigestInterval == 0 {
+		return true
+	}
+	last := sub.CreatedAt
+	if sub.LastNotifiedAt != nil {
+		last = *sub.LastNotifiedAt
+	}
+	return now.Sub(last) >= sub.DigestInterval
+}
+
+// diffResults returns the keys of matches, and the matches whose keys are not
+// in previousKeys.
+func diffResults(previousKeys []string, matches result.Matches) (keys []string, newResults []database.SavedSearchSubscriptionResult) {
+	previous := make(map[string]struct{}, len(previousKeys))
+	for _, k := range previousKeys {
+		previous[k] = struct{}{}
+	}
+
+	seen := make(map[string]struct{}, len(matches))
+	for _, m := range matches {
+		k := resultKey(m)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		keys = append(keys, k)
+
+		if _, ok := previous[k]; !ok {
+			newResults = append(newResults, toSubscriptionResult(m))
+		}
+	}
+	return keys, newResults
+}
+
+// resultKey returns a key that identifies a match across runs. Unlike
+// (result.Match).Key, it does not include the commit of file matches, so that
+// files which keep matching are not reported again whenever their repository
+// changes.
+func resultKey(m result.Match) string {
+	k := m.Key()
+	switch m.(type) {
+	case *result.CommitMatch:
+		return fmt.Sprintf("commit:%s@%s", k.Repo, k.Commit)
+	case *result.CommitDiffMatch:
+		return fmt.Sprintf("diff:%s@%s:%s", k.Repo, k.Commit, k.Path)
+	case *result.FileMatch:
+		return fmt.Sprintf("file:%s@%s:%s", k.Repo, k.Rev, k.Path)
+	case *result.OwnerMatch:
+		return fmt.Sprintf("owner:%s", k.OwnerMetadata)
+	default:
+		return fmt.Sprintf("repo:%s@%s", k.Repo, k.Rev)
+	}
+}
+
+func toSubscriptionResult(m result.Match) database.SavedSearchSubscriptionResult {
+	k := m.Key()
+	res := database.SavedSearchSubscriptionResult{Repository: string(k.Repo)}
+
+	repoPath := string(k.Repo)
+	if k.Rev != "" {
+		repoPath += "@" + k.Rev
+	}
+	switch m := m.(type) {
+	case *result.CommitMatch:
+		res.Commit = string(m.Commit.ID)
+		res.URL = (&url.URL{Path: path.Join("/", string(k.Repo), "-/commit", string(m.Commit.ID))}).String()
+	case *result.CommitDiffMatch:
+		res.Commit = string(m.Commit.ID)
+		res.Path = m.Path()
+		res.URL = (&url.URL{Path: path.Join("/", string(k.Repo), "-/commit", string(m.Commit.ID))}).String()
+	case *result.FileMatch:
+		res.Path = m.Path
+		res.URL = (&url.URL{Path: path.Join("/", repoPath, "-/blob", m.Path)}).String()
+	default:
+		res.URL = (&url.URL{Path: path.Join("/", repoPath)}).String()
+	}
+	return res
+}
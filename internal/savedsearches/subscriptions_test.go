@@ -0,0 +1,157 @@
+package savedsearches
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/webhooks/outbound"
+)
+
+func TestDiffResults(t *testing.T) {
+	fileMatch := func(repo, path string, commit api.CommitID) *result.FileMatch {
+		return &result.FileMatch{File: result.File{Repo: types.MinimalRepo{Name: api.RepoName(repo)}, Path: path, CommitID: commit}}
+	}
+	commitMatch := &result.CommitMatch{
+		Repo:   types.MinimalRepo{Name: "r"},
+		Commit: gitdomain.Commit{ID: "deadbeef"},
+	}
+
+	keys, newResults := diffResults(nil, result.Matches{fileMatch("r", "a.go", "c1"), commitMatch})
+	require.Equal(t, []string{"file:r@:a.go", "commit:r@deadbeef"}, keys)
+	require.Equal(t, []database.SavedSearchSubscriptionResult{
+		{Repository: "r", Path: "a.go", URL: "/r/-/blob/a.go"},
+		{Repository: "r", Commit: "deadbeef", URL: "/r/-/commit/deadbeef"},
+	}, newResults)
+
+	// Files that keep matching at a new commit are not new.
+	keys, newResults = diffResults(keys, result.Matches{fileMatch("r", "a.go", "c2"), fileMatch("r", "b.go", "c2")})
+	require.Equal(t, []string{"file:r@:a.go", "file:r@:b.go"}, keys)
+	require.Equal(t, []database.SavedSearchSubscriptionResult{
+		{Repository: "r", Path: "b.go", URL: "/r/-/blob/b.go"},
+	}, newResults)
+}
+
+func TestDigestDue(t *testing.T) {
+	now := time.Now()
+	lastNotified := now.Add(-time.Hour)
+
+	require.True(t, digestDue(&database.SavedSearchSubscription{}, now))
+	require.False(t, digestDue(&database.SavedSearchSubscription{DigestInterval: 24 * time.Hour, CreatedAt: now.Add(-time.Minute)}, now))
+	require.False(t, digestDue(&database.SavedSearchSubscription{DigestInterval: 24 * time.Hour, LastNotifiedAt: &lastNotified}, now))
+	require.True(t, digestDue(&database.SavedSearchSubscription{DigestInterval: time.Hour, LastNotifiedAt: &lastNotified}, now))
+}
+
+type fakeNotifier struct {
+	notifications []notification
+	err           error
+}
+
+func (f *fakeNotifier) Notify(_ context.Context, _ *database.SavedSearchSubscription, n notification) error {
+	f.notifications = append(f.notifications, n)
+	return f.err
+}
+
+func TestSubscriptionRunnerRun(t *testing.T) {
+	userID := int32(1)
+	savedSearches := database.NewMockSavedSearchStore()
+	savedSearches.GetByIDFunc.SetDefaultReturn(&api.SavedQuerySpecAndConfig{
+		Config: api.ConfigSavedQuery{Description: "TODOs", Query: "TODO", UserID: &userID},
+	}, nil)
+	db := database.NewMockDB()
+	db.SavedSearchesFunc.SetDefaultReturn(savedSearches)
+
+	matches := result.Matches{&result.FileMatch{File: result.File{Repo: types.MinimalRepo{Name: "r"}, Path: "a.go"}}}
+	newRunner := func(n subscriptionNotifier) *subscriptionRunner {
+		return &subscriptionRunner{
+			logger:   logtest.Scoped(t),
+			db:       db,
+			search:   func(context.Context, string) (result.Matches, error) { return matches, nil },
+			notifier: n,
+		}
+	}
+	lastRun := time.Now().Add(-time.Hour)
+
+	t.Run("first run only records results", func(t *testing.T) {
+		n := &fakeNotifier{}
+		run := newRunner(n).run(context.Background(), &database.SavedSearchSubscription{UserID: userID})
+		require.Empty(t, run.Error)
+		require.Equal(t, []string{"file:r@:a.go"}, run.ResultKeys)
+		require.Empty(t, run.PendingResults)
+		require.Empty(t, n.notifications)
+	})
+
+	t.Run("new results are delivered", func(t *testing.T) {
+		n := &fakeNotifier{}
+		run := newRunner(n).run(context.Background(), &database.SavedSearchSubscription{UserID: userID, LastRunAt: &lastRun})
+		require.Empty(t, run.Error)
+		require.True(t, run.Notified)
+		require.Empty(t, run.PendingResults)
+		require.Len(t, n.notifications, 1)
+		require.Equal(t, "TODOs", n.notifications[0].Description)
+	})
+
+	t.Run("muted", func(t *testing.T) {
+		n := &fakeNotifier{}
+		run := newRunner(n).run(context.Background(), &database.SavedSearchSubscription{UserID: userID, LastRunAt: &lastRun, Muted: true})
+		require.False(t, run.Notified)
+		require.Empty(t, run.PendingResults)
+		require.Empty(t, n.notifications)
+	})
+
+	t.Run("digest not due", func(t *testing.T) {
+		n := &fakeNotifier{}
+		run := newRunner(n).run(context.Background(), &database.SavedSearchSubscription{
+			UserID:         userID,
+			LastRunAt:      &lastRun,
+			LastNotifiedAt: &lastRun,
+			DigestInterval: 24 * time.Hour,
+		})
+		require.False(t, run.Notified)
+		require.Len(t, run.PendingResults, 1)
+		require.Empty(t, n.notifications)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		n := &fakeNotifier{}
+		run := newRunner(n).run(context.Background(), &database.SavedSearchSubscription{UserID: 2, LastRunAt: &lastRun})
+		require.NotEmpty(t, run.Error)
+		require.Empty(t, n.notifications)
+	})
+}
+
+func TestPostWebhookPayload(t *testing.T) {
+	var got webhookPayload
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer s.Close()
+
+	externalURL, err := url.Parse("https://sourcegraph.example.com")
+	require.NoError(t, err)
+
+	payload := newWebhookPayload(externalURL, notification{
+		SavedSearchID: 1,
+		Description:   "TODOs",
+		Query:         "TODO",
+		Results:       []database.SavedSearchSubscriptionResult{{Repository: "r", Path: "a.go", URL: "/r/-/blob/a.go"}},
+	})
+	require.NoError(t, outbound.PostJSON(context.Background(), http.DefaultClient, s.URL, payload))
+
+	require.Equal(t, "TODOs", got.Description)
+	require.Equal(t, "https://sourcegraph.example.com/search?q=TODO", got.SearchURL)
+	require.Equal(t, "https://sourcegraph.example.com/r/-/blob/a.go", got.Results[0].URL)
+}
@@ -143,6 +143,16 @@ func AlertForStalePermissions() *Alert {
 	}
 }
 
+// AlertForRateLimited returns an alert for queries that were not run because
+// the user exceeded the search.limits.userQueriesPerMinute limit.
+func AlertForRateLimited() *Alert {
+	return &Alert{
+		PrometheusType: "rate_limited",
+		Title:          "Search rate limit exceeded",
+		Description:    "You have run too many searches in a short period of time. Please wait a moment and try again.",
+	}
+}
+
 func AlertForStructuralSearchNotSet(queryString string) *Alert {
 	return &Alert{
 		PrometheusType: "structural_search_not_set",
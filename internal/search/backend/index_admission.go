@@ -0,0 +1,229 @@
+package backend
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// IndexPressure is the memory and disk pressure reported periodically by an
+// instance of zoekt-sourcegraph-indexserver.
+type IndexPressure struct {
+	// Hostname is the hostname of the indexserver, as sent when listing the
+	// repositories to index.
+	Hostname string
+
+	MemoryUsedBytes  int64
+	MemoryTotalBytes int64
+	DiskUsedBytes    int64
+	DiskTotalBytes   int64
+
+	// IndexCosts is the peak memory in bytes used to index repositories since
+	// the previous report, by repository ID. It replaces the estimates based
+	// on the size of the repositories.
+	IndexCosts map[api.RepoID]int64 `json:",omitempty"`
+
+	// ReportedAt is set when the report is received.
+	ReportedAt time.Time
+}
+
+// usagePercent returns the higher of the memory and disk usage in percent.
+func (p *IndexPressure) usagePercent() float64 {
+	usage := func(used, total int64) float64 {
+		if total <= 0 {
+			return 0
+		}
+		return 100 * float64(used) / float64(total)
+	}
+	mem, disk := usage(p.MemoryUsedBytes, p.MemoryTotalBytes), usage(p.DiskUsedBytes, p.DiskTotalBytes)
+	if disk > mem {
+		return disk
+	}
+	return mem
+}
+
+// freeBytes returns the lower of the free memory and disk.
+func (p *IndexPressure) freeBytes() int64 {
+	mem, disk := p.MemoryTotalBytes-p.MemoryUsedBytes, p.DiskTotalBytes-p.DiskUsedBytes
+	if p.DiskTotalBytes > 0 && disk < mem {
+		return disk
+	}
+	return mem
+}
+
+const (
+	// indexPressureTTL is how long a pressure report is used. If an
+	// indexserver stops reporting its pressure, all repositories are admitted
+	// again.
+	indexPressureTTL = 5 * 60
+	// indexCostTTL is how long the observed cost of indexing a repository
+	// is used instead of an estimate.
+	indexCostTTL = 30 * 24 * 60 * 60
+
+	defaultIndexThrottlePercent      = 75
+	defaultIndexPostponePercent      = 90
+	defaultIndexSmallRepoSizeMB      = 100
+	defaultIndexMaxPostponementHours = 24
+)
+
+type indexAdmissionDecision string
+
+const (
+	indexAdmitted        indexAdmissionDecision = "admitted"
+	indexAdmittedSmall   indexAdmissionDecision = "small"
+	indexAdmittedOverdue indexAdmissionDecision = "overdue"
+	indexPostponed       indexAdmissionDecision = "postponed"
+)
+
+var metricIndexAdmission = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_search_index_admission_total",
+	Help: "The total number of indexing admission decisions made for indexservers under pressure, by decision.",
+}, []string{"decision"})
+
+// IndexAdmission decides whether zoekt-sourcegraph-indexserver may index a
+// repository now, or should postpone indexing it because it is under memory
+// or disk pressure. Its state is stored in Redis, so that all frontend
+// replicas make the same decisions.
+//
+// Small repositories are always admitted, so that they are not starved by
+// large repositories while an indexserver is under pressure. Large
+// repositories are admitted regardless of the pressure once they have been
+// postponed for too long.
+type IndexAdmission struct {
+	pressure  *rcache.Cache
+	costs     *rcache.Cache
+	postponed *rcache.Cache
+
+	now func() time.Time
+}
+
+func NewIndexAdmission() *IndexAdmission {
+	return &IndexAdmission{
+		pressure:  rcache.NewWithTTL("search_index_pressure", indexPressureTTL),
+		costs:     rcache.NewWithTTL("search_index_cost", indexCostTTL),
+		postponed: rcache.New("search_index_postponed"),
+		now:       time.Now,
+	}
+}
+
+// ReportPressure records the pressure reported by an indexserver.
+func (a *IndexAdmission) ReportPressure(p IndexPressure) error {
+	p.ReportedAt = a.now()
+	for id, cost := range p.IndexCosts {
+		a.costs.Set(strconv.Itoa(int(id)), []byte(strconv.FormatInt(cost, 10)))
+	}
+	p.IndexCosts = nil
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	a.pressure.Set(p.Hostname, b)
+	return nil
+}
+
+// Pressure returns the last pressure reported by the indexserver with the
+// given hostname, if it reported it recently.
+func (a *IndexAdmission) Pressure(hostname string) (*IndexPressure, bool) {
+	b, ok := a.pressure.Get(hostname)
+	if !ok {
+		return nil, false
+	}
+	var p IndexPressure
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, false
+	}
+	return &p, true
+}
+
+// EstimateCost returns the estimated cost in bytes of indexing a repository:
+// the memory observed the last time it was indexed, or else its size on disk.
+func (a *IndexAdmission) EstimateCost(repoID api.RepoID, sizeBytes int64) int64 {
+	if b, ok := a.costs.Get(strconv.Itoa(int(repoID))); ok {
+		if cost, err := strconv.ParseInt(string(b), 10, 64); err == nil {
+			return cost
+		}
+	}
+	return sizeBytes
+}
+
+// Admit returns whether the indexserver under the given pressure may index
+// the repository now. If it returns false, the reason is a message for the
+// indexserver. A nil pressure admits all repositories.
+func (a *IndexAdmission) Admit(c *schema.SearchIndexAdmissionControl, pressure *IndexPressure, repoID api.RepoID, sizeBytes int64) (admitted bool, reason string) {
+	if c == nil || !c.Enabled || pressure == nil {
+		return true, ""
+	}
+
+	key := strconv.Itoa(int(repoID))
+	var postponedSince time.Time
+	if b, ok := a.postponed.Get(key); ok {
+		if unix, err := strconv.ParseInt(string(b), 10, 64); err == nil {
+			postponedSince = time.Unix(unix, 0)
+		}
+	}
+
+	cost := a.EstimateCost(repoID, sizeBytes)
+	decision := decideIndexAdmission(c, pressure, cost, postponedSince, a.now())
+	metricIndexAdmission.WithLabelValues(string(decision)).Inc()
+
+	if decision != indexPostponed {
+		if !postponedSince.IsZero() {
+			a.postponed.Delete(key)
+		}
+		return true, ""
+	}
+
+	if postponedSince.IsZero() {
+		// Forget about repositories that are not requested anymore, for example
+		// because they were deleted.
+		ttl := 2 * maxIndexPostponement(c)
+		a.postponed.SetWithTTL(key, []byte(strconv.FormatInt(a.now().Unix(), 10)), int(ttl.Seconds()))
+	}
+	return false, "indexserver " + pressure.Hostname + " is under memory or disk pressure"
+}
+
+func decideIndexAdmission(c *schema.SearchIndexAdmissionControl, pressure *IndexPressure, cost int64, postponedSince, now time.Time) indexAdmissionDecision {
+	smallRepoSizeMB := defaultIndexSmallRepoSizeMB
+	if c.SmallRepoSizeMB != nil {
+		smallRepoSizeMB = *c.SmallRepoSizeMB
+	}
+	if cost <= int64(smallRepoSizeMB)<<20 {
+		return indexAdmittedSmall
+	}
+
+	throttlePercent, postponePercent := c.ThrottlePercent, c.PostponePercent
+	if throttlePercent <= 0 {
+		throttlePercent = defaultIndexThrottlePercent
+	}
+	if postponePercent <= 0 {
+		postponePercent = defaultIndexPostponePercent
+	}
+
+	usage := pressure.usagePercent()
+	switch {
+	case usage < float64(throttlePercent):
+		return indexAdmitted
+	case usage < float64(postponePercent) && cost <= pressure.freeBytes():
+		return indexAdmitted
+	case !postponedSince.IsZero() && now.Sub(postponedSince) >= maxIndexPostponement(c):
+		return indexAdmittedOverdue
+	default:
+		return indexPostponed
+	}
+}
+
+func maxIndexPostponement(c *schema.SearchIndexAdmissionControl) time.Duration {
+	hours := c.MaxPostponementHours
+	if hours <= 0 {
+		hours = defaultIndexMaxPostponementHours
+	}
+	return time.Duration(hours) * time.Hour
+}
@@ -0,0 +1,135 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestDecideIndexAdmission(t *testing.T) {
+	const gb = int64(1) << 30
+
+	now := time.Now()
+	config := &schema.SearchIndexAdmissionControl{
+		Enabled:              true,
+		SmallRepoSizeMB:      pointers.Ptr(100),
+		MaxPostponementHours: 12,
+	}
+	pressure := func(memUsedGB, diskUsedGB int64) *IndexPressure {
+		return &IndexPressure{
+			MemoryUsedBytes:  memUsedGB * gb,
+			MemoryTotalBytes: 10 * gb,
+			DiskUsedBytes:    diskUsedGB * gb,
+			DiskTotalBytes:   100 * gb,
+		}
+	}
+
+	cases := []struct {
+		name           string
+		pressure       *IndexPressure
+		cost           int64
+		postponedSince time.Time
+		want           indexAdmissionDecision
+	}{{
+		name:     "no pressure",
+		pressure: pressure(2, 10),
+		cost:     5 * gb,
+		want:     indexAdmitted,
+	}, {
+		name:     "small repo under pressure",
+		pressure: pressure(10, 100),
+		cost:     100 << 20,
+		want:     indexAdmittedSmall,
+	}, {
+		name:     "throttled repo fits into free memory",
+		pressure: pressure(8, 10),
+		cost:     gb,
+		want:     indexAdmitted,
+	}, {
+		name:     "throttled repo does not fit into free memory",
+		pressure: pressure(8, 10),
+		cost:     3 * gb,
+		want:     indexPostponed,
+	}, {
+		name:     "disk pressure",
+		pressure: pressure(2, 95),
+		cost:     gb,
+		want:     indexPostponed,
+	}, {
+		name:           "postponed recently",
+		pressure:       pressure(10, 10),
+		cost:           gb,
+		postponedSince: now.Add(-time.Hour),
+		want:           indexPostponed,
+	}, {
+		name:           "postponed for too long",
+		pressure:       pressure(10, 10),
+		cost:           gb,
+		postponedSince: now.Add(-12 * time.Hour),
+		want:           indexAdmittedOverdue,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := decideIndexAdmission(config, tc.pressure, tc.cost, tc.postponedSince, now)
+			if got != tc.want {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIndexAdmission(t *testing.T) {
+	rcache.SetupForTest(t)
+
+	now := time.Now()
+	a := NewIndexAdmission()
+	a.now = func() time.Time { return now }
+
+	config := &schema.SearchIndexAdmissionControl{Enabled: true, MaxPostponementHours: 1}
+	const repo = api.RepoID(1)
+	const size = int64(1) << 30
+
+	if _, ok := a.Pressure("indexserver-0"); ok {
+		t.Fatal("expected no pressure before the first report")
+	}
+	if admitted, _ := a.Admit(config, nil, repo, size); !admitted {
+		t.Fatal("expected repository to be admitted without a pressure report")
+	}
+
+	err := a.ReportPressure(IndexPressure{
+		Hostname:         "indexserver-0",
+		MemoryUsedBytes:  95,
+		MemoryTotalBytes: 100,
+		IndexCosts:       map[api.RepoID]int64{2: 1 << 20},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pressure, ok := a.Pressure("indexserver-0")
+	if !ok {
+		t.Fatal("expected pressure to be reported")
+	}
+
+	if admitted, reason := a.Admit(config, pressure, repo, size); admitted || reason == "" {
+		t.Fatalf("expected repository to be postponed, got admitted=%v reason=%q", admitted, reason)
+	}
+
+	// The observed cost of indexing repository 2 makes it a small repository.
+	if cost := a.EstimateCost(2, size); cost != 1<<20 {
+		t.Fatalf("got cost %d, want observed cost", cost)
+	}
+	if admitted, _ := a.Admit(config, pressure, 2, size); !admitted {
+		t.Fatal("expected small repository to be admitted")
+	}
+
+	// The repository is admitted once it has been postponed for too long.
+	now = now.Add(time.Hour)
+	if admitted, _ := a.Admit(config, pressure, repo, size); !admitted {
+		t.Fatal("expected overdue repository to be admitted")
+	}
+}
@@ -146,6 +146,10 @@ type RepoIndexOptions struct {
 	// Archived is true if the repository is archived.
 	Archived bool
 
+	// Revisions are additional revisions to index, such as the refs of the open
+	// changes of Gerrit projects.
+	Revisions []string
+
 	// GetVersion is used to resolve revisions for a repo. If it fails, the
 	// error is encoded in the body. If the revision is missing, an empty
 	// string should be returned rather than an error.
@@ -224,6 +228,10 @@ func getIndexOptions(
 		}
 	}
 
+	for _, rev := range opts.Revisions {
+		branches[rev] = struct{}{}
+	}
+
 	// Add all branches that are referenced by search contexts
 	revs, err := getSearchContextRevisions(opts.RepoID)
 	if err != nil {
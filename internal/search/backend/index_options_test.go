@@ -43,6 +43,7 @@ func TestGetIndexOptions(t *testing.T) {
 		FORK
 		ARCHIVED
 		RANKED
+		REVISIONS
 	)
 
 	name := func(repo api.RepoID) string {
@@ -254,6 +255,20 @@ func TestGetIndexOptions(t *testing.T) {
 			DocumentRanksVersion: "ranked",
 			LanguageMap:          ctags_config.DefaultEngines,
 		},
+	}, {
+		name: "with repo revisions",
+		conf: schema.SiteConfiguration{},
+		repo: REVISIONS,
+		want: ZoektIndexOptions{
+			RepoID:  9,
+			Name:    "repo-09",
+			Symbols: true,
+			Branches: []zoekt.RepositoryBranch{
+				{Name: "HEAD", Version: "!HEAD"},
+				{Name: "refs/changes/34/1234/2", Version: "!refs/changes/34/1234/2"},
+			},
+			LanguageMap: ctags_config.DefaultEngines,
+		},
 	}}
 
 	{
@@ -292,13 +307,18 @@ func TestGetIndexOptions(t *testing.T) {
 		if repo == RANKED {
 			documentRanksVersion = "ranked"
 		}
+		var revisions []string
+		if repo == REVISIONS {
+			revisions = []string{"refs/changes/34/1234/2"}
+		}
 		return &RepoIndexOptions{
-			RepoID:   repo,
-			Name:     name(repo),
-			Public:   repo == PUBLIC,
-			Fork:     repo == FORK,
-			Archived: repo == ARCHIVED,
-			Priority: priority,
+			RepoID:    repo,
+			Name:      name(repo),
+			Public:    repo == PUBLIC,
+			Fork:      repo == FORK,
+			Archived:  repo == ARCHIVED,
+			Priority:  priority,
+			Revisions: revisions,
 			GetVersion: func(branch string) (string, error) {
 				return "!" + branch, nil
 			},
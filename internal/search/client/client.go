@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/grafana/regexp"
 	"github.com/prometheus/client_golang/prometheus"
@@ -23,6 +24,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/job"
 	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
+	"github.com/sourcegraph/sourcegraph/internal/search/limits"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
@@ -162,14 +164,37 @@ func (s *searchClient) Execute(
 	tr, ctx := trace.New(ctx, "Execute")
 	defer tr.FinishWithErr(&err)
 
+	cost := 1
+	for _, b := range inputs.Plan {
+		if b.IsStructural() {
+			cost = limits.StructuralQueryCost
+			break
+		}
+	}
+	if !limits.AllowUserQuery(ctx, cost) {
+		tr.AddEvent("user query rate limit exceeded")
+		return search.AlertForRateLimited(), nil
+	}
+
 	planJob, err := jobutil.NewPlanJob(inputs, inputs.Plan, s.enterpriseJobs)
 	if err != nil {
 		return nil, err
 	}
+	planJob = jobutil.NewQueryLimitsJob(queryLimits(limits.SearchLimits(conf.Get())), planJob)
 
 	return planJob.Run(ctx, s.JobClients(), stream)
 }
 
+// queryLimits returns the resource limits of a single query configured in
+// search.limits.
+func queryLimits(l schema.SearchLimits) jobutil.QueryLimits {
+	return jobutil.QueryLimits{
+		MaxReposScanned: l.MaxReposScanned,
+		MaxDuration:     time.Duration(l.MaxQueryDurationSeconds) * time.Second,
+		MaxResultBytes:  l.MaxResultBytes,
+	}
+}
+
 func (s *searchClient) JobClients() job.RuntimeClients {
 	return job.RuntimeClients{
 		Logger:                      s.logger,
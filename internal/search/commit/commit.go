@@ -248,11 +248,19 @@ func QueryToGitQuery(b query.Basic, diff bool) gitprotocol.Node {
 	return gitprotocol.Reduce(gitprotocol.NewAnd(res...))
 }
 
+// searchRevsToGitserverRevs converts the resolved revisions of a repository
+// to gitserver revisions. Ref globs are preserved by the repository resolver
+// for commit search (see RepoOptions.PreserveRefGlobs), so that gitserver
+// walks the commits of all matching refs in a single git log, instead of
+// passing it one revision per ref.
 func searchRevsToGitserverRevs(in []string) []gitprotocol.RevisionSpecifier {
 	out := make([]gitprotocol.RevisionSpecifier, 0, len(in))
 	for _, rev := range in {
+		spec := query.ParseRevisionSpecifier(rev)
 		out = append(out, gitprotocol.RevisionSpecifier{
-			RevSpec: rev,
+			RevSpec:        spec.RevSpec,
+			RefGlob:        spec.RefGlob,
+			ExcludeRefGlob: spec.ExcludeRefGlob,
 		})
 	}
 	return out
@@ -325,6 +333,9 @@ func queryParameterToPredicate(parameter query.Parameter, caseSensitive, diff bo
 	case query.FieldAfter:
 		t, _ := query.ParseGitDate(parameter.Value, time.Now) // field already validated
 		newPred = &gitprotocol.CommitAfter{Time: t}
+	case query.FieldDuring:
+		start, end, _ := query.ParseDatePeriod(parameter.Value, time.Now) // field already validated
+		newPred = gitprotocol.NewAnd(&gitprotocol.CommitAfter{Time: start}, &gitprotocol.CommitBefore{Time: end})
 	case query.FieldMessage:
 		newPred = &gitprotocol.MessageMatches{Expr: parameter.Value, IgnoreCase: !caseSensitive}
 	case query.FieldContent:
@@ -376,5 +387,6 @@ func protocolMatchToCommitMatch(repo types.MinimalRepo, diff bool, in protocol.C
 		Diff:           structuredDiff,
 		MessagePreview: messagePreview,
 		ModifiedFiles:  in.ModifiedFiles,
+		SourceRefs:     in.SourceRefs,
 	}
 }
@@ -22,6 +22,9 @@ func TestQueryToGitQuery(t *testing.T) {
 		output protocol.Node
 	}
 
+	lastYearStart, lastYearEnd, err := query.ParseDatePeriod("lastyear", time.Now)
+	require.NoError(t, err)
+
 	cases := []testCase{{
 		name: "negated repo does not result in nil node (#26032)",
 		input: query.Basic{
@@ -63,6 +66,18 @@ func TestQueryToGitQuery(t *testing.T) {
 			&protocol.MessageMatches{Expr: "message2", IgnoreCase: true},
 			&protocol.DiffModifiesFile{Expr: "file", IgnoreCase: true},
 		),
+	}, {
+		name: "during is converted to a date range",
+		input: query.Basic{
+			Parameters: []query.Parameter{{Field: query.FieldDuring, Value: "lastyear"}},
+			Pattern:    query.Pattern{Value: "a"},
+		},
+		diff: false,
+		output: protocol.NewAnd(
+			&protocol.CommitAfter{Time: lastYearStart},
+			&protocol.CommitBefore{Time: lastYearEnd},
+			&protocol.MessageMatches{Expr: "a", IgnoreCase: true},
+		),
 	}}
 
 	for _, tc := range cases {
@@ -106,3 +121,12 @@ func TestExpandUsernamesToEmails(t *testing.T) {
 		t.Errorf("got %q, want %q", x, want)
 	}
 }
+
+func TestSearchRevsToGitserverRevs(t *testing.T) {
+	got := searchRevsToGitserverRevs([]string{"HEAD", "*refs/heads/*", "*!refs/heads/dependabot/*"})
+	require.Equal(t, []protocol.RevisionSpecifier{
+		{RevSpec: "HEAD"},
+		{RefGlob: "refs/heads/*"},
+		{ExcludeRefGlob: "refs/heads/dependabot/*"},
+	}, got)
+}
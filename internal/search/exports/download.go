@@ -0,0 +1,52 @@
+package exports
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/signedlink"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DownloadURL returns a link to download the results of the completed export,
+// signed with the secret of the export and valid until expires. Anyone with the
+// link can download the export, so it must only be handed to its owner.
+func DownloadURL(externalURL *url.URL, job *Job, expires time.Time) string {
+	return signedlink.URL(externalURL, "/.api/search/export", job.ID, job.SigningSecret, expires)
+}
+
+// NewDownloadHandler returns the handler serving the signed download links of
+// search exports created by DownloadURL.
+func NewDownloadHandler(logger log.Logger, db database.DB, uploadStore uploadstore.Store) http.Handler {
+	store := NewStore(db)
+	return signedlink.NewHandler(logger, "search export", uploadStore, func(ctx context.Context, id int) (*signedlink.Object, error) {
+		job, err := store.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return nil, signedlink.ErrNotFound
+			}
+			return nil, err
+		}
+
+		object := &signedlink.Object{
+			ID:            job.ID,
+			SigningSecret: job.SigningSecret,
+			ContentType:   "text/csv",
+			Filename:      fmt.Sprintf("search-export-%d.%s", job.ID, job.Format),
+		}
+		if job.Format == FormatJSONL {
+			object.ContentType = "application/x-ndjson"
+		}
+		if job.ObjectKey != nil {
+			object.ObjectKey = *job.ObjectKey
+		}
+		return object, nil
+	})
+}
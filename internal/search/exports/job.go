@@ -0,0 +1,120 @@
+package exports
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/executor"
+)
+
+const tableName = "search_export_jobs"
+
+// Format is the file format of an export.
+type Format string
+
+const (
+	// FormatCSV exports one row per result line, preceded by a header row.
+	FormatCSV Format = "csv"
+	// FormatJSONL exports one JSON object per result line.
+	FormatJSONL Format = "jsonl"
+)
+
+// Valid reports whether f is a supported format.
+func (f Format) Valid() bool {
+	return f == FormatCSV || f == FormatJSONL
+}
+
+// Job runs a search to completion on behalf of a user and uploads its results to
+// the upload store.
+type Job struct {
+	ID              int
+	State           string
+	FailureMessage  *string
+	QueuedAt        time.Time
+	StartedAt       *time.Time
+	FinishedAt      *time.Time
+	ProcessAfter    *time.Time
+	NumResets       int
+	NumFailures     int
+	LastHeartbeatAt time.Time
+	ExecutionLogs   []executor.ExecutionLogEntry
+	WorkerHostname  string
+
+	// UserID is the user the search is run as.
+	UserID int32
+	Query  string
+	Format Format
+	// ResultCount is the number of exported records, once the export completed.
+	ResultCount int
+	// ObjectKey is the key of the export in the upload store, or nil until the
+	// export completed.
+	ObjectKey *string
+	// SigningSecret signs the download links of the export.
+	SigningSecret []byte
+	CreatedAt     time.Time
+}
+
+func (j *Job) RecordID() int {
+	return j.ID
+}
+
+func (j *Job) RecordUID() string {
+	return strconv.Itoa(j.ID)
+}
+
+var jobColumns = []*sqlf.Query{
+	sqlf.Sprintf("id"),
+	sqlf.Sprintf("state"),
+	sqlf.Sprintf("failure_message"),
+	sqlf.Sprintf("queued_at"),
+	sqlf.Sprintf("started_at"),
+	sqlf.Sprintf("finished_at"),
+	sqlf.Sprintf("process_after"),
+	sqlf.Sprintf("num_resets"),
+	sqlf.Sprintf("num_failures"),
+	sqlf.Sprintf("last_heartbeat_at"),
+	sqlf.Sprintf("execution_logs"),
+	sqlf.Sprintf("worker_hostname"),
+	sqlf.Sprintf("user_id"),
+	sqlf.Sprintf("query"),
+	sqlf.Sprintf("format"),
+	sqlf.Sprintf("result_count"),
+	sqlf.Sprintf("object_key"),
+	sqlf.Sprintf("signing_secret"),
+	sqlf.Sprintf("created_at"),
+}
+
+func scanJob(s dbutil.Scanner) (*Job, error) {
+	var job Job
+	var executionLogs []executor.ExecutionLogEntry
+
+	if err := s.Scan(
+		&job.ID,
+		&job.State,
+		&job.FailureMessage,
+		&job.QueuedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+		&job.ProcessAfter,
+		&job.NumResets,
+		&job.NumFailures,
+		&dbutil.NullTime{Time: &job.LastHeartbeatAt},
+		pq.Array(&executionLogs),
+		&job.WorkerHostname,
+		&job.UserID,
+		&job.Query,
+		&job.Format,
+		&job.ResultCount,
+		&job.ObjectKey,
+		&job.SigningSecret,
+		&job.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	job.ExecutionLogs = append(job.ExecutionLogs, executionLogs...)
+	return &job, nil
+}
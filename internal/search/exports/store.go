@@ -0,0 +1,126 @@
+package exports
+
+import (
+	"context"
+	"crypto/rand"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// maxActiveJobsPerUser is the maximum number of exports a user may have queued
+// or running at the same time.
+const maxActiveJobsPerUser = 5
+
+var (
+	// ErrNotFound is returned when an export does not exist.
+	ErrNotFound = errors.New("search export not found")
+	// ErrTooManyActiveJobs is returned when enqueueing an export for a user that
+	// already has maxActiveJobsPerUser exports queued or running.
+	ErrTooManyActiveJobs = errors.Newf("at most %d search exports may be queued or running at the same time", maxActiveJobsPerUser)
+)
+
+// Store enqueues and lists search exports.
+type Store interface {
+	// Enqueue enqueues the export of the results of the query, run as the given
+	// user. ErrTooManyActiveJobs is returned if the user has too many exports
+	// queued or running.
+	Enqueue(ctx context.Context, userID int32, query string, format Format) (*Job, error)
+
+	// GetByID returns the export with the given ID, or ErrNotFound.
+	GetByID(ctx context.Context, id int) (*Job, error)
+
+	// ListByUser returns the most recently enqueued exports of the user.
+	ListByUser(ctx context.Context, userID int32, limit int) ([]*Job, error)
+}
+
+type store struct {
+	*basestore.Store
+}
+
+// NewStore returns a Store backed by the given database.
+func NewStore(db database.DB) Store {
+	return &store{Store: basestore.NewWithHandle(db.Handle())}
+}
+
+const enqueueFmtstr = `
+INSERT INTO search_export_jobs (user_id, query, format, signing_secret)
+SELECT %s, %s, %s, %s
+WHERE (
+	SELECT COUNT(*) FROM search_export_jobs
+	WHERE user_id = %s AND state IN ('queued', 'processing', 'errored')
+) < %s
+RETURNING id
+`
+
+func (s *store) Enqueue(ctx context.Context, userID int32, query string, format Format) (*Job, error) {
+	if !format.Valid() {
+		return nil, errors.Newf("unsupported search export format %q", format)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	id, ok, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(
+		enqueueFmtstr,
+		userID, query, format, secret,
+		userID, maxActiveJobsPerUser,
+	)))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTooManyActiveJobs
+	}
+	return s.GetByID(ctx, id)
+}
+
+func (s *store) GetByID(ctx context.Context, id int) (*Job, error) {
+	jobs, err := s.list(ctx, sqlf.Sprintf("id = %s", id), 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, ErrNotFound
+	}
+	return jobs[0], nil
+}
+
+func (s *store) ListByUser(ctx context.Context, userID int32, limit int) ([]*Job, error) {
+	return s.list(ctx, sqlf.Sprintf("user_id = %s", userID), limit)
+}
+
+const listFmtstr = `
+SELECT %s
+FROM search_export_jobs
+WHERE %s
+ORDER BY id DESC
+%s
+`
+
+func (s *store) list(ctx context.Context, cond *sqlf.Query, limit int) ([]*Job, error) {
+	limitClause := sqlf.Sprintf("")
+	if limit > 0 {
+		limitClause = sqlf.Sprintf("LIMIT %s", limit)
+	}
+	return basestore.NewSliceScanner(scanJob)(s.Query(ctx, sqlf.Sprintf(
+		listFmtstr,
+		sqlf.Join(jobColumns, ","),
+		cond,
+		limitClause,
+	)))
+}
+
+const setResultFmtstr = `
+UPDATE search_export_jobs SET object_key = %s, result_count = %s WHERE id = %s
+`
+
+// setResult records the upload of the results of the export.
+func (s *store) setResult(ctx context.Context, id int, objectKey string, resultCount int) error {
+	return s.Exec(ctx, sqlf.Sprintf(setResultFmtstr, objectKey, resultCount, id))
+}
@@ -0,0 +1,62 @@
+package exports
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+
+	user, err := db.Users().Create(ctx, database.NewUser{Username: "alice"})
+	require.NoError(t, err)
+
+	s := NewStore(db)
+
+	_, err = s.Enqueue(ctx, user.ID, "TODO", "xml")
+	require.Error(t, err)
+
+	job, err := s.Enqueue(ctx, user.ID, "TODO", FormatCSV)
+	require.NoError(t, err)
+	assert.Equal(t, "queued", job.State)
+	assert.Equal(t, user.ID, job.UserID)
+	assert.Equal(t, "TODO", job.Query)
+	assert.Equal(t, FormatCSV, job.Format)
+	assert.Nil(t, job.ObjectKey)
+	assert.Len(t, job.SigningSecret, 32)
+
+	for i := 1; i < maxActiveJobsPerUser; i++ {
+		_, err := s.Enqueue(ctx, user.ID, "TODO", FormatJSONL)
+		require.NoError(t, err)
+	}
+	_, err = s.Enqueue(ctx, user.ID, "TODO", FormatJSONL)
+	require.ErrorIs(t, err, ErrTooManyActiveJobs)
+
+	require.NoError(t, s.(*store).setResult(ctx, job.ID, "search-exports/1.csv", 42))
+	job, err = s.GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, job.ObjectKey)
+	assert.Equal(t, "search-exports/1.csv", *job.ObjectKey)
+	assert.Equal(t, 42, job.ResultCount)
+
+	jobs, err := s.ListByUser(ctx, user.ID, 2)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Greater(t, jobs[0].ID, jobs[1].ID)
+
+	_, err = s.GetByID(ctx, 12345)
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,19 @@
+package exports
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/signedlink"
+)
+
+// UploadStoreConfigInst configures the upload store that search exports are
+// written to by the worker and downloaded from through the frontend. It is
+// loaded by the services that read or write search exports.
+var UploadStoreConfigInst = signedlink.NewUploadStoreConfig("SEARCH_EXPORTS", "search exports", "search-exports")
+
+// NewUploadStore returns the upload store configured by conf.
+func NewUploadStore(ctx context.Context, observationCtx *observation.Context, conf *signedlink.UploadStoreConfig) (uploadstore.Store, error) {
+	return signedlink.NewUploadStore(ctx, observationCtx, conf, "search_exports")
+}
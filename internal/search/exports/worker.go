@@ -0,0 +1,218 @@
+package exports
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var (
+	concurrency = env.MustGetInt("SEARCH_EXPORTS_CONCURRENCY", 2, "The maximum number of search exports run at the same time.")
+	maxDuration = env.MustGetDuration("SEARCH_EXPORTS_MAX_DURATION", time.Hour, "The maximum time the search of a search export may run.")
+	retention   = env.MustGetDuration("SEARCH_EXPORTS_RETENTION", 7*24*time.Hour, "The time search exports are kept in the upload store.")
+)
+
+// objectKeyPrefix is the prefix of the keys of all exports in the upload store.
+const objectKeyPrefix = "search-exports/"
+
+// NewWorker returns the routines running search exports: a worker processing
+// the exports enqueued through the Store, its resetter, and an expirer deleting
+// exports older than SEARCH_EXPORTS_RETENTION from the upload store.
+func NewWorker(ctx context.Context, observationCtx *observation.Context, db database.DB, uploadStore uploadstore.Store, enterpriseJobs jobutil.EnterpriseJobs) []goroutine.BackgroundRoutine {
+	workerStore := dbworkerstore.New(observationCtx, db.Handle(), dbworkerstore.Options[*Job]{
+		Name:              "search_exports_worker_store",
+		TableName:         tableName,
+		ColumnExpressions: jobColumns,
+		Scan:              dbworkerstore.BuildWorkerScan(scanJob),
+		OrderByExpression: sqlf.Sprintf("id"),
+		MaxNumResets:      5,
+		StalledMaxAge:     time.Minute,
+		RetryAfter:        time.Minute,
+		MaxNumRetries:     3,
+	})
+
+	h := &handler{
+		store:       &store{Store: basestore.NewWithHandle(db.Handle())},
+		uploadStore: uploadStore,
+		search:      newSearchFunc(observationCtx.Logger, db, enterpriseJobs),
+	}
+
+	worker := dbworker.NewWorker[*Job](ctx, workerStore, h, workerutil.WorkerOptions{
+		Name:              "search_exports_worker",
+		Description:       "runs searches to completion and uploads their results",
+		NumHandlers:       concurrency,
+		Interval:          10 * time.Second,
+		HeartbeatInterval: 15 * time.Second,
+		Metrics:           workerutil.NewMetrics(observationCtx, "search_exports_worker"),
+	})
+
+	resetter := dbworker.NewResetter(observationCtx.Logger.Scoped("resetter", ""), workerStore, dbworker.ResetterOptions{
+		Name:     "search_exports_worker_resetter",
+		Interval: time.Minute,
+		Metrics:  dbworker.NewResetterMetrics(observationCtx, "search_exports_worker"),
+	})
+
+	expirer := uploadstore.NewExpirer(ctx, uploadStore, objectKeyPrefix, retention, time.Hour)
+
+	return []goroutine.BackgroundRoutine{worker, resetter, expirer}
+}
+
+// searchFunc runs query to completion and sends its results to stream.
+type searchFunc func(ctx context.Context, query string, stream streaming.Sender) error
+
+func newSearchFunc(logger log.Logger, db database.DB, enterpriseJobs jobutil.EnterpriseJobs) searchFunc {
+	searchClient := client.New(logger, db, enterpriseJobs)
+	plan := func(ctx context.Context, query string) (*search.Inputs, error) {
+		return searchClient.Plan(ctx, "V3", nil, query, search.Precise, search.Streaming)
+	}
+
+	return func(ctx context.Context, query string, stream streaming.Sender) error {
+		inputs, err := plan(ctx, query)
+		if err != nil {
+			return errcode.MakeNonRetryable(err)
+		}
+		if inputs.Query.Count() == nil {
+			// Unlike searches in the UI, exports are not limited to the default
+			// number of results.
+			if inputs, err = plan(ctx, query+" count:all"); err != nil {
+				return errcode.MakeNonRetryable(err)
+			}
+		}
+
+		alert, err := searchClient.Execute(ctx, stream, inputs)
+		if err != nil {
+			return err
+		}
+		if alert != nil && alert.PrometheusType == "rate_limited" {
+			// Retried after the rate limit has been replenished.
+			return errors.New(alert.Title)
+		}
+		return nil
+	}
+}
+
+type handler struct {
+	store       *store
+	uploadStore uploadstore.Store
+	search      searchFunc
+}
+
+var _ workerutil.Handler[*Job] = &handler{}
+
+// Handle runs the search of the export as the user who requested it, and
+// streams its results to the upload store while they are found.
+func (h *handler) Handle(ctx context.Context, logger log.Logger, job *Job) error {
+	externalURL, err := url.Parse(conf.ExternalURL())
+	if err != nil {
+		return err
+	}
+
+	// 🚨 SECURITY: The search must only return results the user who requested
+	// the export has access to.
+	ctx = actor.WithActor(ctx, actor.FromUser(job.UserID))
+	ctx, cancel := context.WithTimeout(ctx, maxDuration)
+	defer cancel()
+
+	key := fmt.Sprintf("%s%d.%s", objectKeyPrefix, job.ID, job.Format)
+	count, err := h.export(ctx, externalURL, job, key)
+	if err != nil {
+		return err
+	}
+	logger.Info("exported search results", log.String("key", key), log.Int("records", count))
+	return h.store.setResult(ctx, job.ID, key, count)
+}
+
+// export uploads the results of the search of the job to the upload store at
+// key, and returns the number of exported records.
+func (h *handler) export(ctx context.Context, externalURL *url.URL, job *Job, key string) (int, error) {
+	pr, pw := io.Pipe()
+	uploadErr := make(chan error, 1)
+	go func() {
+		_, err := h.uploadStore.Upload(ctx, key, pr)
+		// Unblocks writeResults if the upload failed
+		pr.CloseWithError(err)
+		uploadErr <- err
+	}()
+
+	count, err := writeResults(ctx, h.search, job, externalURL, pw)
+	// A nil error completes the upload
+	pw.CloseWithError(err)
+	if uerr := <-uploadErr; err == nil && uerr != nil {
+		err = errors.Wrap(uerr, "upload")
+	}
+	return count, err
+}
+
+// writeResults runs the search of the job and writes its results to w in the
+// format of the job.
+func writeResults(ctx context.Context, search searchFunc, job *Job, externalURL *url.URL, w io.Writer) (int, error) {
+	rw, err := newRecordWriter(job.Format, w)
+	if err != nil {
+		return 0, errcode.MakeNonRetryable(err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		count    int
+		writeErr error
+	)
+	stream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if writeErr != nil {
+			return
+		}
+		for _, m := range event.Results {
+			for _, r := range toRecords(externalURL, m) {
+				if err := rw.Write(r); err != nil {
+					writeErr = err
+					// Writing to w fails once the upload failed, so there is
+					// no point in searching any further.
+					cancel()
+					return
+				}
+				count++
+			}
+		}
+	})
+
+	err = search(ctx, job.Query, stream)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if writeErr != nil {
+		return 0, writeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	return count, rw.Flush()
+}
@@ -0,0 +1,70 @@
+package exports
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestHandlerExport(t *testing.T) {
+	externalURL, err := url.Parse("https://sourcegraph.example.com")
+	require.NoError(t, err)
+	job := &Job{ID: 1, Query: "repo:r", Format: FormatJSONL}
+
+	search := func(_ context.Context, query string, stream streaming.Sender) error {
+		require.Equal(t, "repo:r", query)
+		for i := 0; i < 3; i++ {
+			stream.Send(streaming.SearchEvent{Results: result.Matches{&result.RepoMatch{Name: "r"}}})
+		}
+		return nil
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var uploaded bytes.Buffer
+		uploadStore := mocks.NewMockStore()
+		uploadStore.UploadFunc.SetDefaultHook(func(_ context.Context, key string, r io.Reader) (int64, error) {
+			require.Equal(t, "search-exports/1.jsonl", key)
+			return io.Copy(&uploaded, r)
+		})
+
+		h := &handler{uploadStore: uploadStore, search: search}
+		count, err := h.export(context.Background(), externalURL, job, "search-exports/1.jsonl")
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+		require.Equal(t, 3, bytes.Count(uploaded.Bytes(), []byte("\n")))
+	})
+
+	t.Run("upload fails", func(t *testing.T) {
+		uploadStore := mocks.NewMockStore()
+		uploadStore.UploadFunc.SetDefaultReturn(0, errors.New("bucket is gone"))
+
+		h := &handler{uploadStore: uploadStore, search: search}
+		_, err := h.export(context.Background(), externalURL, job, "search-exports/1.jsonl")
+		require.ErrorContains(t, err, "bucket is gone")
+	})
+
+	t.Run("search fails", func(t *testing.T) {
+		uploadStore := mocks.NewMockStore()
+		uploadStore.UploadFunc.SetDefaultHook(func(_ context.Context, _ string, r io.Reader) (int64, error) {
+			return io.Copy(io.Discard, r)
+		})
+
+		h := &handler{
+			uploadStore: uploadStore,
+			search: func(context.Context, string, streaming.Sender) error {
+				return errors.New("search timed out")
+			},
+		}
+		_, err := h.export(context.Background(), externalURL, job, "search-exports/1.jsonl")
+		require.ErrorContains(t, err, "search timed out")
+	})
+}
@@ -0,0 +1,167 @@
+package exports
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// record is a row of an export. File matches are exported as one record per
+// matched line or symbol, all other matches as one record each.
+type record struct {
+	Type       string `json:"type"`
+	Repository string `json:"repository"`
+	Revision   string `json:"revision,omitempty"`
+	Commit     string `json:"commit,omitempty"`
+	Path       string `json:"path,omitempty"`
+	// Line is the 1-based line number of the match, or 0 if the match does not
+	// belong to a line.
+	Line    int    `json:"line,omitempty"`
+	Preview string `json:"preview,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+var csvHeader = []string{"type", "repository", "revision", "commit", "path", "line", "preview", "url"}
+
+func (r record) csvRow() []string {
+	line := ""
+	if r.Line > 0 {
+		line = strconv.Itoa(r.Line)
+	}
+	return []string{r.Type, r.Repository, r.Revision, r.Commit, r.Path, line, r.Preview, r.URL}
+}
+
+// toRecords converts the match to the records it is exported as. URLs are
+// resolved against externalURL.
+func toRecords(externalURL *url.URL, m result.Match) []record {
+	k := m.Key()
+	base := record{Repository: string(k.Repo), Revision: k.Rev}
+	withURL := func(r record, u *url.URL) record {
+		r.URL = externalURL.ResolveReference(u).String()
+		return r
+	}
+
+	switch m := m.(type) {
+	case *result.FileMatch:
+		base.Commit = string(m.CommitID)
+		base.Path = m.Path
+		if m.IsPathMatch() {
+			r := base
+			r.Type = "path"
+			return []record{withURL(r, m.File.URL())}
+		}
+
+		var records []record
+		for _, lm := range m.ChunkMatches.AsLineMatches() {
+			if len(lm.OffsetAndLengths) == 0 {
+				// Context lines of multiline matches
+				continue
+			}
+			r := base
+			r.Type = "content"
+			r.Line = int(lm.LineNumber) + 1
+			r.Preview = lm.Preview
+			u := m.File.URL()
+			u.RawQuery = fmt.Sprintf("L%d", r.Line)
+			records = append(records, withURL(r, u))
+		}
+		for _, sm := range m.Symbols {
+			r := base
+			r.Type = "symbol"
+			r.Line = sm.Symbol.Line
+			r.Preview = sm.Symbol.Name
+			records = append(records, withURL(r, sm.URL()))
+		}
+		return records
+
+	case *result.CommitMatch:
+		r := base
+		r.Type = "commit"
+		r.Commit = string(m.Commit.ID)
+		r.Preview = m.Commit.Message.Subject()
+		return []record{withURL(r, m.URL())}
+
+	case *result.CommitDiffMatch:
+		r := base
+		r.Type = "diff"
+		r.Commit = string(m.Commit.ID)
+		r.Path = m.Path()
+		u := (&result.RepoMatch{Name: m.Repo.Name, ID: m.Repo.ID}).URL()
+		u.Path += "/-/commit/" + string(m.Commit.ID)
+		return []record{withURL(r, u)}
+
+	case *result.RepoMatch:
+		r := base
+		r.Type = "repo"
+		return []record{withURL(r, m.URL())}
+
+	case *result.OwnerMatch:
+		r := base
+		r.Type = "owner"
+		r.Commit = string(m.CommitID)
+		if m.ResolvedOwner != nil {
+			r.Preview = m.ResolvedOwner.Identifier()
+		}
+		return []record{r}
+
+	default:
+		return nil
+	}
+}
+
+// recordWriter encodes records in the format of an export.
+type recordWriter interface {
+	Write(record) error
+	// Flush writes any buffered records to the underlying writer.
+	Flush() error
+}
+
+func newRecordWriter(format Format, w io.Writer) (recordWriter, error) {
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return nil, err
+		}
+		return &csvRecordWriter{w: cw}, nil
+	case FormatJSONL:
+		bw := bufio.NewWriter(w)
+		return &jsonlRecordWriter{w: bw, enc: json.NewEncoder(bw)}, nil
+	default:
+		return nil, errors.Newf("unsupported search export format %q", format)
+	}
+}
+
+type csvRecordWriter struct {
+	w *csv.Writer
+}
+
+func (w *csvRecordWriter) Write(r record) error {
+	return w.w.Write(r.csvRow())
+}
+
+func (w *csvRecordWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type jsonlRecordWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (w *jsonlRecordWriter) Write(r record) error {
+	// Encode terminates each value with a newline.
+	return w.enc.Encode(r)
+}
+
+func (w *jsonlRecordWriter) Flush() error {
+	return w.w.Flush()
+}
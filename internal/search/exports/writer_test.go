@@ -0,0 +1,92 @@
+package exports
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestToRecords(t *testing.T) {
+	externalURL, err := url.Parse("https://sourcegraph.example.com")
+	require.NoError(t, err)
+	repo := types.MinimalRepo{Name: "github.com/sourcegraph/sourcegraph"}
+
+	fileMatch := &result.FileMatch{
+		File: result.File{Repo: repo, Path: "main.go", CommitID: "c1"},
+		ChunkMatches: result.ChunkMatches{{
+			Content:      "// TODO: a\nfunc main() {}",
+			ContentStart: result.Location{Line: 2},
+			Ranges: result.Ranges{{
+				Start: result.Location{Offset: 3, Line: 2, Column: 3},
+				End:   result.Location{Offset: 7, Line: 2, Column: 7},
+			}},
+		}},
+	}
+	require.Equal(t, []record{{
+		Type:       "content",
+		Repository: "github.com/sourcegraph/sourcegraph",
+		Commit:     "c1",
+		Path:       "main.go",
+		Line:       3,
+		Preview:    "// TODO: a",
+		URL:        "https://sourcegraph.example.com/github.com/sourcegraph/sourcegraph/-/blob/main.go?L3",
+	}}, toRecords(externalURL, fileMatch))
+
+	pathMatch := &result.FileMatch{File: result.File{Repo: repo, Path: "README.md", InputRev: strPtr("main")}}
+	require.Equal(t, []record{{
+		Type:       "path",
+		Repository: "github.com/sourcegraph/sourcegraph",
+		Revision:   "main",
+		Path:       "README.md",
+		URL:        "https://sourcegraph.example.com/github.com/sourcegraph/sourcegraph@main/-/blob/README.md",
+	}}, toRecords(externalURL, pathMatch))
+
+	commitMatch := &result.CommitMatch{
+		Repo:   repo,
+		Commit: gitdomain.Commit{ID: "deadbeef", Message: "Fix a bug\n\nDetails"},
+	}
+	require.Equal(t, []record{{
+		Type:       "commit",
+		Repository: "github.com/sourcegraph/sourcegraph",
+		Commit:     "deadbeef",
+		Preview:    "Fix a bug",
+		URL:        "https://sourcegraph.example.com/github.com/sourcegraph/sourcegraph/-/commit/deadbeef",
+	}}, toRecords(externalURL, commitMatch))
+}
+
+func TestRecordWriter(t *testing.T) {
+	records := []record{
+		{Type: "content", Repository: "r", Path: "a.go", Line: 1, Preview: `x := "a, b"`, URL: "https://example.com/r/-/blob/a.go?L1"},
+		{Type: "repo", Repository: "r", URL: "https://example.com/r"},
+	}
+	write := func(format Format) string {
+		var buf bytes.Buffer
+		w, err := newRecordWriter(format, &buf)
+		require.NoError(t, err)
+		for _, r := range records {
+			require.NoError(t, w.Write(r))
+		}
+		require.NoError(t, w.Flush())
+		return buf.String()
+	}
+
+	require.Equal(t, `type,repository,revision,commit,path,line,preview,url
+content,r,,,a.go,1,"x := ""a, b""",https://example.com/r/-/blob/a.go?L1
+repo,r,,,,,,https://example.com/r
+`, write(FormatCSV))
+
+	require.Equal(t, `{"type":"content","repository":"r","path":"a.go","line":1,"preview":"x := \"a, b\"","url":"https://example.com/r/-/blob/a.go?L1"}
+{"type":"repo","repository":"r","url":"https://example.com/r"}
+`, write(FormatJSONL))
+
+	_, err := newRecordWriter("xml", &bytes.Buffer{})
+	require.Error(t, err)
+}
+
+func strPtr(s string) *string { return &s }
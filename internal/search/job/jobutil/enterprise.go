@@ -14,6 +14,9 @@ import (
 type EnterpriseJobs interface {
 	FileHasOwnerJob(child job.Job, includeOwners, excludeOwners []string) job.Job
 	SelectFileOwnerJob(child job.Job) job.Job
+	// PreciseSymbolSearchJob wraps a job returning symbol results so that symbols
+	// are served from precise code intelligence indexes where available.
+	PreciseSymbolSearchJob(child job.Job, patternInfo *search.TextPatternInfo) job.Job
 }
 
 func NewUnimplementedEnterpriseJobs() EnterpriseJobs {
@@ -30,6 +33,12 @@ func (e *enterpriseJobs) SelectFileOwnerJob(_ job.Job) job.Job {
 	return NewUnimplementedJob("`select:file.owners` searches are not available on this instance")
 }
 
+// PreciseSymbolSearchJob returns child as is: symbol results always come from
+// ctags on this instance.
+func (e *enterpriseJobs) PreciseSymbolSearchJob(child job.Job, _ *search.TextPatternInfo) job.Job {
+	return child
+}
+
 func NewUnimplementedJob(msg string) *UnimplementedJob {
 	return &UnimplementedJob{msg: msg}
 }
@@ -1,6 +1,7 @@
 package jobutil
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -158,6 +159,7 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic, enterpriseJobs Enterprise
 			diff := resultTypes.Has(result.TypeDiff)
 			repoOptionsCopy := repoOptions
 			repoOptionsCopy.OnlyCloned = true
+			repoOptionsCopy.PreserveRefGlobs = query.ContainsRefGlobs(b.ToParseTree())
 			addJob(&commit.SearchJob{
 				Query:                commit.QueryToGitQuery(originalQuery, diff),
 				RepoOpts:             repoOptionsCopy,
@@ -186,6 +188,12 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic, enterpriseJobs Enterprise
 
 	basicJob := NewParallelJob(children...)
 
+	{ // Serve symbol results from precise indexes where available
+		if resultTypes := computeResultTypes(b, inputs.PatternType); resultTypes == result.TypeSymbol && isPreciseSymbolPattern(b.Pattern) {
+			basicJob = enterpriseJobs.PreciseSymbolSearchJob(basicJob, toTextPatternInfo(b, resultTypes, inputs.Protocol))
+		}
+	}
+
 	{ // Apply file:contains.content() post-filter
 		if len(fileContainsPatterns) > 0 {
 			var err error
@@ -196,6 +204,15 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic, enterpriseJobs Enterprise
 		}
 	}
 
+	{ // Cache the results of searches scoped to a single repository at a commit
+		resultTypes := computeResultTypes(b, inputs.PatternType)
+		repoOptions := toRepoOptions(b, inputs.UserSettings)
+		if repo, commit, ok := resultCacheScope(originalQuery, resultTypes, repoOptions); ok && (inputs.Features == nil || !inputs.Features.Debug) {
+			key := fmt.Sprintf("%s\x00%s\x00%s\x00%s", inputs.PatternType, inputs.Protocol, inputs.Features, originalQuery)
+			basicJob = NewResultCacheJob(repo, commit, key, basicJob)
+		}
+	}
+
 	{ // Apply code ownership post-search filter
 		if includeOwners, excludeOwners, ok := isOwnershipSearch(b); ok {
 			basicJob = enterpriseJobs.FileHasOwnerJob(basicJob, includeOwners, excludeOwners)
@@ -387,13 +404,17 @@ func NewFlatJob(searchInputs *search.Inputs, f query.Flat) (job.Job, error) {
 				Features:        *searchInputs.Features,
 			}
 
-			addJob(&structural.SearchJob{
+			var structuralJob job.Job = &structural.SearchJob{
 				SearcherArgs:     searcherArgs,
 				UseIndex:         f.Index(),
 				ContainsRefGlobs: query.ContainsRefGlobs(f.ToBasic().ToParseTree()),
 				RepoOpts:         repoOptions,
 				BatchRetry:       searchInputs.Protocol == search.Batch,
-			})
+			}
+			if rewrite := f.FindValue(query.FieldRewrite); rewrite != "" {
+				structuralJob = structural.NewRewritePreviewJob(structuralJob, patternInfo, rewrite)
+			}
+			addJob(structuralJob)
 		}
 
 		if resultTypes.Has(result.TypeRepo) {
@@ -687,6 +708,17 @@ func toTextPatternInfo(b query.Basic, resultTypes result.Types, p search.Protoco
 	}
 }
 
+// isPreciseSymbolPattern returns true if symbol results for the given pattern
+// can be served from precise indexes, which only support a single positive
+// pattern.
+func isPreciseSymbolPattern(pattern query.Node) bool {
+	if pattern == nil {
+		return true
+	}
+	p, ok := pattern.(query.Pattern)
+	return ok && !p.Negated
+}
+
 // computeResultTypes returns result types based three inputs: `type:...` in the query,
 // the `pattern`, and top-level `searchType` (coming from a GQL value).
 func computeResultTypes(b query.Basic, searchType query.SearchType) result.Types {
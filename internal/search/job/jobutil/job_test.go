@@ -788,6 +788,28 @@ func TestNewPlanJob(t *testing.T) {
             (patternInfo.pattern . (:[_]))
             (patternInfo.isStructural . true)
             (patternInfo.fileMatchLimit . 500)))))))`),
+		}, {
+			query:      `(...) rewrite:"[...]"`,
+			protocol:   search.Streaming,
+			searchType: query.SearchTypeStructural,
+			want: autogold.Expect(`
+(LOG
+  (ALERT
+    (query . )
+    (originalQuery . )
+    (patternType . structural)
+    (TIMEOUT
+      (timeout . 20s)
+      (LIMIT
+        (limit . 500)
+        (PARALLEL
+          REPOSCOMPUTEEXCLUDED
+          (STRUCTURALREWRITEPREVIEW
+            (rewriteTemplate . [...])
+            (STRUCTURALSEARCH
+              (patternInfo.pattern . (:[_]))
+              (patternInfo.isStructural . true)
+              (patternInfo.fileMatchLimit . 500))))))))`),
 		},
 	}
 
@@ -0,0 +1,211 @@
+package jobutil
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/atomic"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// QueryLimits are the resource limits of a single query. Zero values mean
+// unlimited.
+type QueryLimits struct {
+	// MaxReposScanned is the maximum number of repositories the query searches.
+	MaxReposScanned int
+	// MaxDuration is the maximum wall time of the query.
+	MaxDuration time.Duration
+	// MaxResultBytes is the maximum size of the content of the results the
+	// query returns.
+	MaxResultBytes int
+}
+
+// NewQueryLimitsJob creates a job that enforces the given resource limits on
+// all of its child job tree. Unlike the LimitJob and TimeoutJob of a basic
+// query, the limits are shared by all the basic queries of a plan. Limit hits
+// are reported with stats events.
+func NewQueryLimitsJob(limits QueryLimits, child job.Job) job.Job {
+	if limits == (QueryLimits{}) {
+		return child
+	}
+	return &QueryLimitsJob{
+		limits: limits,
+		child:  child,
+	}
+}
+
+type QueryLimitsJob struct {
+	limits QueryLimits
+	child  job.Job
+}
+
+func (l *QueryLimitsJob) Run(ctx context.Context, clients job.RuntimeClients, s streaming.Sender) (alert *search.Alert, err error) {
+	tr, ctx, s, finish := job.StartSpan(ctx, s, l)
+	defer func() { finish(alert, err) }()
+
+	if l.limits.MaxReposScanned > 0 {
+		ctx = withReposScannedBudget(ctx, int64(l.limits.MaxReposScanned))
+	}
+
+	var durationCtx context.Context = ctx
+	if l.limits.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		durationCtx, cancel = context.WithTimeout(ctx, l.limits.MaxDuration)
+		defer cancel()
+	}
+
+	childCtx, cancel := context.WithCancel(durationCtx)
+	defer cancel()
+
+	if l.limits.MaxResultBytes > 0 {
+		s = newResultBytesLimitStream(int64(l.limits.MaxResultBytes), s, func() {
+			tr.AddEvent("result bytes limit hit, canceling child context")
+			cancel()
+		})
+	}
+
+	alert, err = l.child.Run(childCtx, clients, s)
+
+	// Only report the duration limit if it was our deadline that expired, not
+	// one of the parent context.
+	if ctx.Err() == nil && errors.Is(durationCtx.Err(), context.DeadlineExceeded) {
+		s.Send(streaming.SearchEvent{Stats: streaming.Stats{DurationLimitHit: true}})
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = nil
+		}
+	}
+	if ctx.Err() == nil && errors.Is(err, context.Canceled) {
+		// Ignore context canceled errors caused by hitting the result bytes
+		// limit.
+		err = nil
+	}
+	return alert, err
+}
+
+func (l *QueryLimitsJob) Name() string {
+	return "QueryLimitsJob"
+}
+
+func (l *QueryLimitsJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
+	switch v {
+	case job.VerbosityMax:
+		fallthrough
+	case job.VerbosityBasic:
+		res = append(res,
+			attribute.Int("maxReposScanned", l.limits.MaxReposScanned),
+			attribute.Stringer("maxDuration", l.limits.MaxDuration),
+			attribute.Int("maxResultBytes", l.limits.MaxResultBytes),
+		)
+	}
+	return res
+}
+
+func (l *QueryLimitsJob) Children() []job.Describer {
+	return []job.Describer{l.child}
+}
+
+func (l *QueryLimitsJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *l
+	cp.child = job.Map(l.child, fn)
+	return &cp
+}
+
+type reposScannedBudgetKey struct{}
+
+// withReposScannedBudget returns a context that carries the number of
+// repositories the repo pager jobs of a query may still search.
+func withReposScannedBudget(ctx context.Context, budget int64) context.Context {
+	return context.WithValue(ctx, reposScannedBudgetKey{}, atomic.NewInt64(budget))
+}
+
+// takeReposScannedBudget takes up to n repositories from the budget of ctx and
+// returns how many may be searched. It returns n if ctx has no budget.
+func takeReposScannedBudget(ctx context.Context, n int) int {
+	budget, ok := ctx.Value(reposScannedBudgetKey{}).(*atomic.Int64)
+	if !ok {
+		return n
+	}
+	after := budget.Sub(int64(n))
+	if after >= 0 {
+		return n
+	}
+	before := after + int64(n)
+	if before <= 0 {
+		return 0
+	}
+	return int(before)
+}
+
+type resultBytesLimitStream struct {
+	s          streaming.Sender
+	onLimitHit context.CancelFunc
+	remaining  atomic.Int64
+}
+
+func newResultBytesLimitStream(limit int64, s streaming.Sender, onLimitHit context.CancelFunc) streaming.Sender {
+	stream := &resultBytesLimitStream{onLimitHit: onLimitHit, s: s}
+	stream.remaining.Store(limit)
+	return stream
+}
+
+func (s *resultBytesLimitStream) Send(event streaming.SearchEvent) {
+	var size int64
+	for _, match := range event.Results {
+		size += int64(resultBytes(match))
+	}
+
+	// Avoid limit checks if there are no results.
+	if size == 0 {
+		s.s.Send(event)
+		return
+	}
+
+	after := s.remaining.Sub(size)
+	before := after + size
+
+	// Once the limit was hit, results that were already in flight are dropped,
+	// but their stats are still sent.
+	if before <= 0 {
+		event.Results = nil
+	}
+	s.s.Send(event)
+
+	// Send the limit hit event and call cancel exactly once, when the size of
+	// an event causes us to cross the zero-remaining threshold.
+	if after <= 0 && before > 0 {
+		s.s.Send(streaming.SearchEvent{Stats: streaming.Stats{ResultBytesLimitHit: true}})
+		s.onLimitHit()
+	}
+}
+
+// resultBytes returns the size of the content of a match that counts towards
+// the maxResultBytes limit.
+func resultBytes(match result.Match) int {
+	switch m := match.(type) {
+	case *result.FileMatch:
+		size := len(m.Path)
+		for _, cm := range m.ChunkMatches {
+			size += len(cm.Content)
+		}
+		for _, sm := range m.Symbols {
+			size += len(sm.Symbol.Name)
+		}
+		return size
+	case *result.CommitMatch:
+		size := len(m.Commit.Message)
+		if m.DiffPreview != nil {
+			size += len(m.DiffPreview.Content)
+		}
+		return size
+	case *result.RepoMatch:
+		return len(m.Name)
+	default:
+		return 0
+	}
+}
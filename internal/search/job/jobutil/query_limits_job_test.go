@@ -0,0 +1,67 @@
+package jobutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+)
+
+func TestQueryLimitsJob(t *testing.T) {
+	t.Run("no limits", func(t *testing.T) {
+		mockJob := mockjob.NewMockJob()
+		require.Equal(t, job.Job(mockJob), NewQueryLimitsJob(QueryLimits{}, mockJob))
+	})
+
+	t.Run("max duration", func(t *testing.T) {
+		mockJob := mockjob.NewMockJob()
+		mockJob.RunFunc.SetDefaultHook(func(ctx context.Context, _ job.RuntimeClients, _ streaming.Sender) (*search.Alert, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+		agg := streaming.NewAggregatingStream()
+		_, err := NewQueryLimitsJob(QueryLimits{MaxDuration: 10 * time.Millisecond}, mockJob).Run(context.Background(), job.RuntimeClients{}, agg)
+		require.NoError(t, err)
+		require.True(t, agg.Stats.DurationLimitHit)
+	})
+
+	t.Run("max result bytes", func(t *testing.T) {
+		mockJob := mockjob.NewMockJob()
+		mockJob.RunFunc.SetDefaultHook(func(ctx context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+			for i := 0; i < 10; i++ {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				default:
+				}
+				s.Send(streaming.SearchEvent{
+					Results: []result.Match{&result.FileMatch{File: result.File{Path: "0123456789"}}},
+				})
+			}
+			return nil, nil
+		})
+
+		agg := streaming.NewAggregatingStream()
+		_, err := NewQueryLimitsJob(QueryLimits{MaxResultBytes: 25}, mockJob).Run(context.Background(), job.RuntimeClients{}, agg)
+		require.NoError(t, err)
+		require.True(t, agg.Stats.ResultBytesLimitHit)
+		require.Len(t, agg.Results, 3)
+	})
+}
+
+func TestTakeReposScannedBudget(t *testing.T) {
+	require.Equal(t, 10, takeReposScannedBudget(context.Background(), 10))
+
+	ctx := withReposScannedBudget(context.Background(), 15)
+	require.Equal(t, 10, takeReposScannedBudget(ctx, 10))
+	require.Equal(t, 5, takeReposScannedBudget(ctx, 10))
+	require.Equal(t, 0, takeReposScannedBudget(ctx, 10))
+}
@@ -89,6 +89,19 @@ func (p *repoPagerJob) Run(ctx context.Context, clients job.RuntimeClients, stre
 	for it.Next() {
 		page := it.Current()
 		page.MaybeSendStats(stream)
+
+		// Respect the maxReposScanned limit of the query, which is shared by all
+		// repo pager jobs of the query.
+		limitHit := false
+		if n := takeReposScannedBudget(ctx, len(page.RepoRevs)); n < len(page.RepoRevs) {
+			page.RepoRevs = page.RepoRevs[:n]
+			limitHit = true
+			stream.Send(streaming.SearchEvent{Stats: streaming.Stats{ReposScannedLimitHit: true}})
+		}
+		if limitHit && len(page.RepoRevs) == 0 {
+			break
+		}
+
 		indexed, unindexed, err := zoekt.PartitionRepos(
 			ctx,
 			clients.Logger,
@@ -110,6 +123,10 @@ func (p *repoPagerJob) Run(ctx context.Context, clients job.RuntimeClients, stre
 		if err != nil {
 			return maxAlerter.Alert, err
 		}
+
+		if limitHit {
+			break
+		}
 	}
 
 	return maxAlerter.Alert, it.Err()
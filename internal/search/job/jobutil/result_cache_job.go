@@ -0,0 +1,338 @@
+package jobutil
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp/syntax" //nolint:depguard // we need the literal of the repo filter
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	searchrepos "github.com/sourcegraph/sourcegraph/internal/search/repos"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// maxResultCacheEntrySize is the maximum size of the results of a single query
+// that are cached. Larger results are not cached.
+const maxResultCacheEntrySize = 1 << 20
+
+var metricResultCache = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_search_result_cache_total",
+	Help: "The total number of lookups of the search result cache, by result.",
+}, []string{"result"})
+
+// resultCache stores the cached results. It is implemented by rcache.Cache.
+type resultCache interface {
+	Get(key string) ([]byte, bool)
+	SetWithTTL(key string, b []byte, ttl int)
+}
+
+var defaultResultCache resultCache = rcache.New("search_result_cache")
+
+// NewResultCacheJob creates a job that caches the results of its child job,
+// which searches the given repository at the given commit ID. Since the
+// commit is immutable, the results only change when the repository is
+// updated, e.g. when it is reindexed or force-pushed. Cached results are keyed
+// by the last time the repository changed, so that updates invalidate them.
+//
+// This greatly reduces the load search-based code navigation puts on the
+// search backends, as it runs the same definition and reference searches for
+// every hover on a file.
+func NewResultCacheJob(repo api.RepoName, commit api.CommitID, key string, child job.Job) job.Job {
+	return &resultCacheJob{
+		repo:   repo,
+		commit: commit,
+		key:    key,
+		child:  child,
+		cache:  defaultResultCache,
+	}
+}
+
+type resultCacheJob struct {
+	repo   api.RepoName
+	commit api.CommitID
+	key    string // identifies the query, along with the repo and commit
+	child  job.Job
+
+	cache resultCache
+}
+
+// resultCacheEntry is the cached results of a query. Only file matches are
+// cached.
+type resultCacheEntry struct {
+	Files    []json.RawMessage
+	LimitHit bool
+}
+
+// cachedFileMatch is a cached result.FileMatch. The repository is not part of
+// it, as it is resolved for every lookup.
+type cachedFileMatch struct {
+	CommitID     api.CommitID
+	InputRev     *string
+	Path         string
+	ChunkMatches result.ChunkMatches
+	PathMatches  []result.Range
+	Symbols      []result.Symbol
+	LimitHit     bool
+}
+
+func (j *resultCacheJob) Run(ctx context.Context, clients job.RuntimeClients, s streaming.Sender) (alert *search.Alert, err error) {
+	tr, ctx, s, finish := job.StartSpan(ctx, s, j)
+	defer func() { finish(alert, err) }()
+
+	ttl := conf.SearchResultCacheTTL()
+	if ttl <= 0 {
+		return j.child.Run(ctx, clients, s)
+	}
+
+	// 🚨 SECURITY: The repository is resolved for the current actor before
+	// cached results are returned, so that they are only returned to users with
+	// access to the repository. Sub-repository permissions are applied to the
+	// results by a parent job.
+	repo, err := clients.DB.Repos().GetByName(ctx, j.repo)
+	if err != nil {
+		// Let the child job report the error.
+		return j.child.Run(ctx, clients, s)
+	}
+	gitserverRepo, err := clients.DB.GitserverRepos().GetByName(ctx, repo.Name)
+	if err != nil {
+		return j.child.Run(ctx, clients, s)
+	}
+
+	key := resultCacheKey(repo.ID, gitserverRepo.LastChanged, j.commit, j.key)
+	if b, ok := j.cache.Get(key); ok {
+		event, err := decodeResultCacheEntry(b, types.MinimalRepo{ID: repo.ID, Name: repo.Name, Stars: repo.Stars})
+		if err == nil {
+			metricResultCache.WithLabelValues("hit").Inc()
+			tr.AddEvent("cache hit")
+			s.Send(event)
+			return nil, nil
+		}
+		tr.AddEvent("discarding invalid cache entry", attribute.String("error", err.Error()))
+	}
+	metricResultCache.WithLabelValues("miss").Inc()
+
+	recorder := &resultCacheRecorder{}
+	alert, err = j.child.Run(ctx, clients, streaming.StreamFunc(func(event streaming.SearchEvent) {
+		// Record the event before sending it, as the parent jobs may modify the
+		// results.
+		recorder.record(event)
+		s.Send(event)
+	}))
+	if err != nil || alert != nil || ctx.Err() != nil {
+		return alert, err
+	}
+
+	if b, ok := recorder.encode(); ok {
+		j.cache.SetWithTTL(key, b, int(ttl/time.Second))
+	}
+	return nil, nil
+}
+
+func (j *resultCacheJob) Name() string {
+	return "ResultCacheJob"
+}
+
+func (j *resultCacheJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
+	switch v {
+	case job.VerbosityMax:
+		fallthrough
+	case job.VerbosityBasic:
+		res = append(res,
+			j.repo.Attr(),
+			j.commit.Attr(),
+		)
+	}
+	return res
+}
+
+func (j *resultCacheJob) Children() []job.Describer {
+	return []job.Describer{j.child}
+}
+
+func (j *resultCacheJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.child = job.Map(j.child, fn)
+	return &cp
+}
+
+// resultCacheRecorder records the results of a query, as long as they are
+// complete and can be cached.
+type resultCacheRecorder struct {
+	mu       sync.Mutex
+	files    []json.RawMessage
+	size     int
+	limitHit bool
+	skip     bool
+}
+
+func (r *resultCacheRecorder) record(event streaming.SearchEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.skip {
+		return
+	}
+
+	stats := event.Stats
+	if stats.BackendsMissing > 0 ||
+		stats.Status.Any(search.RepoStatusCloning|search.RepoStatusMissing|search.RepoStatusTimedout) ||
+		stats.ReposScannedLimitHit || stats.DurationLimitHit || stats.ResultBytesLimitHit {
+		// The results are incomplete.
+		r.skip = true
+		return
+	}
+	if stats.IsLimitHit || stats.Status.Any(search.RepoStatusLimitHit) {
+		r.limitHit = true
+	}
+
+	for _, match := range event.Results {
+		fm, ok := match.(*result.FileMatch)
+		if !ok {
+			r.skip = true
+			return
+		}
+
+		cached := cachedFileMatch{
+			CommitID:     fm.CommitID,
+			InputRev:     fm.InputRev,
+			Path:         fm.Path,
+			ChunkMatches: fm.ChunkMatches,
+			PathMatches:  fm.PathMatches,
+			LimitHit:     fm.LimitHit,
+		}
+		for _, sm := range fm.Symbols {
+			cached.Symbols = append(cached.Symbols, sm.Symbol)
+		}
+
+		b, err := json.Marshal(cached)
+		if err != nil {
+			r.skip = true
+			return
+		}
+		r.size += len(b)
+		if r.size > maxResultCacheEntrySize {
+			r.skip = true
+			r.files = nil
+			return
+		}
+		r.files = append(r.files, b)
+	}
+}
+
+func (r *resultCacheRecorder) encode() ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.skip {
+		return nil, false
+	}
+	b, err := json.Marshal(resultCacheEntry{Files: r.files, LimitHit: r.limitHit})
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+func decodeResultCacheEntry(b []byte, repo types.MinimalRepo) (streaming.SearchEvent, error) {
+	var entry resultCacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return streaming.SearchEvent{}, err
+	}
+
+	matches := make(result.Matches, 0, len(entry.Files))
+	for _, raw := range entry.Files {
+		var cached cachedFileMatch
+		if err := json.Unmarshal(raw, &cached); err != nil {
+			return streaming.SearchEvent{}, err
+		}
+
+		fm := &result.FileMatch{
+			File: result.File{
+				Repo:     repo,
+				CommitID: cached.CommitID,
+				InputRev: cached.InputRev,
+				Path:     cached.Path,
+			},
+			ChunkMatches: cached.ChunkMatches,
+			PathMatches:  cached.PathMatches,
+			LimitHit:     cached.LimitHit,
+		}
+		for _, symbol := range cached.Symbols {
+			fm.Symbols = append(fm.Symbols, &result.SymbolMatch{
+				Symbol: symbol,
+				File:   &fm.File,
+			})
+		}
+		matches = append(matches, fm)
+	}
+
+	event := streaming.SearchEvent{
+		Results: matches,
+		Stats: streaming.Stats{
+			Repos:      map[api.RepoID]struct{}{repo.ID: {}},
+			IsLimitHit: entry.LimitHit,
+		},
+	}
+	if entry.LimitHit {
+		event.Stats.Status.Update(repo.ID, search.RepoStatusLimitHit)
+	}
+	return event, nil
+}
+
+func resultCacheKey(repoID api.RepoID, lastChanged time.Time, commit api.CommitID, key string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\x00%d\x00%s\x00%s", repoID, lastChanged.UnixNano(), commit, key)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// resultCacheScope returns the repository and commit ID a basic query is
+// scoped to, if its results can be cached. The results can be cached if the
+// query searches files, paths or symbols of a single repository at an absolute
+// commit ID, and doesn't depend on mutable repository metadata.
+func resultCacheScope(b query.Basic, resultTypes result.Types, repoOptions search.RepoOptions) (api.RepoName, api.CommitID, bool) {
+	if resultTypes&^(result.TypeFile|result.TypePath|result.TypeSymbol) != 0 || b.IsStructural() {
+		return "", "", false
+	}
+
+	if !searchrepos.ExactlyOneRepo(repoOptions.RepoFilters) ||
+		len(repoOptions.MinusRepoFilters) > 0 ||
+		len(repoOptions.DescriptionPatterns) > 0 ||
+		len(repoOptions.HasFileContent) > 0 ||
+		len(repoOptions.HasKVPs) > 0 ||
+		len(repoOptions.HasTopics) > 0 ||
+		repoOptions.CommitAfter != nil ||
+		(repoOptions.Visibility != "" && repoOptions.Visibility != query.Any) ||
+		(repoOptions.SearchContextSpec != "" && repoOptions.SearchContextSpec != "global") {
+		return "", "", false
+	}
+
+	filter := repoOptions.RepoFilters[0]
+	if len(filter.Revs) != 1 || filter.Revs[0].RefGlob != "" || filter.Revs[0].ExcludeRefGlob != "" || !gitdomain.IsAbsoluteRevision(filter.Revs[0].RevSpec) {
+		return "", "", false
+	}
+
+	// Invariant: ExactlyOneRepo ensures the filter is an anchored literal.
+	re, err := syntax.Parse(strings.TrimSuffix(strings.TrimPrefix(filter.Repo, "^"), "$"), syntax.ClassNL|syntax.PerlX|syntax.UnicodeGroups)
+	if err != nil || re.Op != syntax.OpLiteral {
+		return "", "", false
+	}
+
+	return api.RepoName(string(re.Rune)), api.CommitID(strings.ToLower(filter.Revs[0].RevSpec)), true
+}
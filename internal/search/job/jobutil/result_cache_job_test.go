@@ -0,0 +1,140 @@
+package jobutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type mapResultCache map[string][]byte
+
+func (c mapResultCache) Get(key string) ([]byte, bool) {
+	b, ok := c[key]
+	return b, ok
+}
+
+func (c mapResultCache) SetWithTTL(key string, b []byte, _ int) {
+	c[key] = b
+}
+
+func TestResultCacheJob(t *testing.T) {
+	ttl := 60
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{SearchResultCacheTTLSeconds: &ttl}})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	const commit = api.CommitID("0123456789abcdef0123456789abcdef01234567")
+	repo := types.MinimalRepo{ID: 1, Name: "github.com/sourcegraph/sourcegraph"}
+
+	repos := database.NewMockRepoStore()
+	repos.GetByNameFunc.SetDefaultReturn(&types.Repo{ID: repo.ID, Name: repo.Name}, nil)
+	lastChanged := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	gitserverRepos := database.NewMockGitserverRepoStore()
+	gitserverRepos.GetByNameFunc.SetDefaultHook(func(context.Context, api.RepoName) (*types.GitserverRepo, error) {
+		return &types.GitserverRepo{RepoID: repo.ID, LastChanged: lastChanged}, nil
+	})
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repos)
+	db.GitserverReposFunc.SetDefaultReturn(gitserverRepos)
+	clients := job.RuntimeClients{DB: db}
+
+	newMatch := func() *result.FileMatch {
+		fm := &result.FileMatch{
+			File: result.File{Repo: repo, CommitID: commit, Path: "main.go"},
+			ChunkMatches: result.ChunkMatches{{
+				Content: "func main() {",
+				Ranges:  result.Ranges{{Start: result.Location{Offset: 5, Column: 5}, End: result.Location{Offset: 9, Column: 9}}},
+			}},
+		}
+		fm.Symbols = []*result.SymbolMatch{{Symbol: result.Symbol{Name: "main", Path: "main.go", Kind: "function"}, File: &fm.File}}
+		return fm
+	}
+
+	collect := func(j job.Job) (matches result.Matches) {
+		_, err := j.Run(context.Background(), clients, streaming.StreamFunc(func(e streaming.SearchEvent) {
+			matches = append(matches, e.Results...)
+		}))
+		require.NoError(t, err)
+		return matches
+	}
+
+	t.Run("caches complete results", func(t *testing.T) {
+		child := mockjob.NewMockJob()
+		child.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+			s.Send(streaming.SearchEvent{Results: result.Matches{newMatch()}})
+			return nil, nil
+		})
+		j := NewResultCacheJob(repo.Name, commit, "main", child).(*resultCacheJob)
+		j.cache = mapResultCache{}
+
+		want := result.Matches{newMatch()}
+		require.Equal(t, want, collect(j))
+		require.Equal(t, want, collect(j))
+		require.Len(t, child.RunFunc.History(), 1)
+
+		// Updating the repository invalidates the cached results.
+		lastChanged = lastChanged.Add(time.Hour)
+		require.Equal(t, want, collect(j))
+		require.Len(t, child.RunFunc.History(), 2)
+	})
+
+	t.Run("does not cache incomplete results", func(t *testing.T) {
+		child := mockjob.NewMockJob()
+		child.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+			var status search.RepoStatusMap
+			status.Update(repo.ID, search.RepoStatusTimedout)
+			s.Send(streaming.SearchEvent{Results: result.Matches{newMatch()}, Stats: streaming.Stats{Status: status}})
+			return nil, nil
+		})
+		j := NewResultCacheJob(repo.Name, commit, "main", child).(*resultCacheJob)
+		j.cache = mapResultCache{}
+
+		collect(j)
+		collect(j)
+		require.Len(t, child.RunFunc.History(), 2)
+	})
+}
+
+func TestResultCacheScope(t *testing.T) {
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+
+	for _, tc := range []struct {
+		query     string
+		wantRepo  api.RepoName
+		cacheable bool
+	}{
+		{query: `repo:^github\.com/sourcegraph/sourcegraph$@` + commit + ` type:symbol ^main$`, wantRepo: "github.com/sourcegraph/sourcegraph", cacheable: true},
+		{query: `repo:^github\.com/sourcegraph/sourcegraph$@` + commit + ` \bmain\b case:yes file:\.go$`, wantRepo: "github.com/sourcegraph/sourcegraph", cacheable: true},
+		{query: `repo:^github\.com/sourcegraph/sourcegraph$@main type:symbol ^main$`},
+		{query: `repo:^github\.com/sourcegraph/sourcegraph$ type:symbol ^main$`},
+		{query: `repo:sourcegraph@` + commit + ` type:symbol ^main$`},
+		{query: `repo:^github\.com/sourcegraph/sourcegraph$@` + commit + ` type:commit main`},
+		{query: `repo:^github\.com/sourcegraph/sourcegraph$@` + commit + ` repo:has.topic(go) main`},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			plan, err := query.Pipeline(query.Init(tc.query, query.SearchTypeRegex))
+			require.NoError(t, err)
+			b := plan[0]
+
+			repo, gotCommit, ok := resultCacheScope(b, computeResultTypes(b, query.SearchTypeRegex), toRepoOptions(b, &schema.Settings{}))
+			require.Equal(t, tc.cacheable, ok)
+			if ok {
+				require.Equal(t, tc.wantRepo, repo)
+				require.Equal(t, api.CommitID(commit), gotCommit)
+			}
+		})
+	}
+}
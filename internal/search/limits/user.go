@@ -0,0 +1,83 @@
+package limits
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// StructuralQueryCost is the number of queries a structural search query
+// counts as for the userQueriesPerMinute limit, since structural search is
+// much more expensive for searcher than other searches.
+const StructuralQueryCost = 5
+
+// maxUserLimiters is the maximum number of users whose limiter is kept in
+// memory. Users whose limiter is evicted start over with a full bucket.
+const maxUserLimiters = 10000
+
+var (
+	userLimitersMu sync.Mutex
+	userLimiters   *lru.Cache[string, *userLimiter]
+)
+
+func init() {
+	userLimiters, _ = lru.New[string, *userLimiter](maxUserLimiters)
+}
+
+// userLimiter is the token bucket of a single user, along with the
+// configuration it was created with.
+type userLimiter struct {
+	perMinute int
+	burst     int
+	limiter   *rate.Limiter
+}
+
+// AllowUserQuery reports whether the actor of ctx may run a query that costs
+// the given number of queries now, according to the userQueriesPerMinute limit
+// of the search.limits site configuration. Internal actors are never limited.
+func AllowUserQuery(ctx context.Context, cost int) bool {
+	a := actor.FromContext(ctx)
+	if a.IsInternal() {
+		return true
+	}
+
+	limits := SearchLimits(conf.Get())
+	perMinute := limits.UserQueriesPerMinute
+	if perMinute <= 0 {
+		return true
+	}
+	burst := limits.UserQueryBurst
+	if burst <= 0 {
+		burst = perMinute
+	}
+	if burst < cost {
+		// Otherwise the query could never run.
+		burst = cost
+	}
+
+	// Anonymous users without an anonymous ID share a bucket.
+	key := "anonymous:" + a.AnonymousUID
+	if a.IsAuthenticated() {
+		key = "user:" + a.UIDString()
+	}
+
+	userLimitersMu.Lock()
+	defer userLimitersMu.Unlock()
+
+	l, ok := userLimiters.Get(key)
+	if !ok || l.perMinute != perMinute || l.burst != burst {
+		l = &userLimiter{
+			perMinute: perMinute,
+			burst:     burst,
+			limiter:   rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), burst),
+		}
+		userLimiters.Add(key, l)
+	}
+	return l.limiter.AllowN(time.Now(), cost)
+}
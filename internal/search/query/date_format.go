@@ -191,3 +191,46 @@ func parseSimpleDate(s string) (time.Time, error) {
 
 	return time.Date(year, monthNum, day, 0, 0, 0, 0, time.UTC), nil
 }
+
+var errInvalidPeriod = errors.New(`invalid period. Use one of today, yesterday, thisweek, lastweek, thismonth, lastmonth, thisquarter, lastquarter, thisyear or lastyear`)
+
+// ParseDatePeriod parses a named calendar period relative to now, such as
+// "lastquarter", for the during: filter. It returns the start (inclusive) and
+// end (exclusive) of the period. Spaces, dashes and underscores in the name are
+// ignored, so "last quarter" and "last-quarter" are accepted too. Weeks start
+// on Monday.
+func ParseDatePeriod(s string, now func() time.Time) (start, end time.Time, err error) {
+	name := strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(s))
+
+	n := now()
+	today := time.Date(n.Year(), n.Month(), n.Day(), 0, 0, 0, 0, n.Location())
+	thisWeek := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	thisMonth := time.Date(n.Year(), n.Month(), 1, 0, 0, 0, 0, n.Location())
+	thisQuarter := time.Date(n.Year(), n.Month()-(n.Month()-1)%3, 1, 0, 0, 0, 0, n.Location())
+	thisYear := time.Date(n.Year(), time.January, 1, 0, 0, 0, 0, n.Location())
+
+	switch name {
+	case "today":
+		return today, today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), today, nil
+	case "thisweek":
+		return thisWeek, thisWeek.AddDate(0, 0, 7), nil
+	case "lastweek":
+		return thisWeek.AddDate(0, 0, -7), thisWeek, nil
+	case "thismonth":
+		return thisMonth, thisMonth.AddDate(0, 1, 0), nil
+	case "lastmonth":
+		return thisMonth.AddDate(0, -1, 0), thisMonth, nil
+	case "thisquarter":
+		return thisQuarter, thisQuarter.AddDate(0, 3, 0), nil
+	case "lastquarter":
+		return thisQuarter.AddDate(0, -3, 0), thisQuarter, nil
+	case "thisyear":
+		return thisYear, thisYear.AddDate(1, 0, 0), nil
+	case "lastyear":
+		return thisYear.AddDate(-1, 0, 0), thisYear, nil
+	}
+
+	return time.Time{}, time.Time{}, errInvalidPeriod
+}
@@ -62,3 +62,55 @@ func TestParseGitDate(t *testing.T) {
 		}
 	})
 }
+
+func TestParseDatePeriod(t *testing.T) {
+	now := func() time.Time {
+		// A Friday in the second quarter.
+		return time.Date(1996, 6, 28, 13, 37, 0, 0, time.UTC)
+	}
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	cases := []struct {
+		input string
+		start time.Time
+		end   time.Time
+	}{
+		{"today", date(1996, 6, 28), date(1996, 6, 29)},
+		{"yesterday", date(1996, 6, 27), date(1996, 6, 28)},
+		{"thisweek", date(1996, 6, 24), date(1996, 7, 1)},
+		{"lastweek", date(1996, 6, 17), date(1996, 6, 24)},
+		{"thismonth", date(1996, 6, 1), date(1996, 7, 1)},
+		{"lastmonth", date(1996, 5, 1), date(1996, 6, 1)},
+		{"thisquarter", date(1996, 4, 1), date(1996, 7, 1)},
+		{"lastquarter", date(1996, 1, 1), date(1996, 4, 1)},
+		{"last quarter", date(1996, 1, 1), date(1996, 4, 1)},
+		{"Last-Quarter", date(1996, 1, 1), date(1996, 4, 1)},
+		{"thisyear", date(1996, 1, 1), date(1997, 1, 1)},
+		{"lastyear", date(1995, 1, 1), date(1996, 1, 1)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			start, end, err := ParseDatePeriod(tc.input, now)
+			require.NoError(t, err)
+			require.Equal(t, tc.start, start)
+			require.Equal(t, tc.end, end)
+		})
+	}
+
+	t.Run("first quarter", func(t *testing.T) {
+		start, end, err := ParseDatePeriod("lastquarter", func() time.Time { return date(1996, 2, 29) })
+		require.NoError(t, err)
+		require.Equal(t, date(1995, 10, 1), start)
+		require.Equal(t, date(1996, 1, 1), end)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []string{"", "2 weeks ago", "nextweek"} {
+			_, _, err := ParseDatePeriod(tc, now)
+			require.Error(t, err, tc)
+		}
+	})
+}
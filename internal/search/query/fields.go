@@ -23,6 +23,7 @@ const (
 	// For diff and commit search only:
 	FieldBefore    = "before"
 	FieldAfter     = "after"
+	FieldDuring    = "during"
 	FieldAuthor    = "author"
 	FieldCommitter = "committer"
 	FieldMessage   = "message"
@@ -32,6 +33,7 @@ const (
 	FieldCount     = "count" // Searches that specify `count:` will fetch at least that number of results, or the full result set
 	FieldTimeout   = "timeout"
 	FieldCombyRule = "rule"
+	FieldRewrite   = "rewrite"
 	FieldSelect    = "select"
 )
 
@@ -59,6 +61,7 @@ var allFields = map[string]struct{}{
 	"until":                 empty,
 	FieldAfter:              empty,
 	"since":                 empty,
+	FieldDuring:             empty,
 	FieldAuthor:             empty,
 	FieldCommitter:          empty,
 	FieldMessage:            empty,
@@ -68,6 +71,7 @@ var allFields = map[string]struct{}{
 	FieldCount:              empty,
 	FieldTimeout:            empty,
 	FieldCombyRule:          empty,
+	FieldRewrite:            empty,
 	FieldRev:                empty,
 	"revision":              empty,
 	FieldSelect:             empty,
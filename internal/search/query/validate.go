@@ -227,6 +227,11 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		return err
 	}
 
+	isValidDatePeriod := func() error {
+		_, _, err := ParseDatePeriod(value, time.Now)
+		return err
+	}
+
 	satisfies := func(fns ...func() error) error {
 		for _, fn := range fns {
 			if err := fn(); err != nil {
@@ -273,6 +278,9 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		FieldBefore,
 		FieldAfter:
 		return satisfies(isNotNegated, isValidGitDate)
+	case
+		FieldDuring:
+		return satisfies(isNotNegated, isValidDatePeriod)
 	case
 		FieldAuthor,
 		FieldCommitter,
@@ -287,7 +295,8 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		FieldCount:
 		return satisfies(isSingular, isNumber, isNotNegated)
 	case
-		FieldCombyRule:
+		FieldCombyRule,
+		FieldRewrite:
 		return satisfies(isSingular, isNotNegated)
 	case
 		FieldTimeout:
@@ -345,7 +354,7 @@ func validateCommitParameters(nodes []Node) error {
 	var seenCommitParam string
 	var typeCommitExists bool
 	VisitParameter(nodes, func(field, value string, _ bool, _ Annotation) {
-		if field == FieldAuthor || field == FieldBefore || field == FieldAfter || field == FieldMessage {
+		if field == FieldAuthor || field == FieldBefore || field == FieldAfter || field == FieldDuring || field == FieldMessage {
 			seenCommitParam = field
 		}
 		if field == FieldType && (value == "commit" || value == "diff") {
@@ -382,6 +391,27 @@ func validateTypeStructural(nodes []Node) error {
 	return nil
 }
 
+// validateRewriteStructural checks that a rewrite template is only specified
+// for structural search queries, since it is applied to the holes of the
+// structural search pattern.
+func validateRewriteStructural(nodes []Node) error {
+	seenRewrite := false
+	VisitField(nodes, FieldRewrite, func(_ string, _ bool, _ Annotation) {
+		seenRewrite = true
+	})
+	if !seenRewrite {
+		return nil
+	}
+	seenStructural := Exists(nodes, func(node Node) bool {
+		p, ok := node.(Pattern)
+		return ok && p.Annotation.Labels.IsSet(Structural)
+	})
+	if !seenStructural {
+		return errors.New("the query contains `rewrite:`, which requires a structural search pattern. Add patterntype:structural and a pattern to the query and try again")
+	}
+	return nil
+}
+
 func validateRefGlobs(nodes []Node) error {
 	if !ContainsRefGlobs(nodes) {
 		return nil
@@ -499,6 +529,7 @@ func validate(nodes []Node) error {
 		validateRepoHasFile,
 		validateCommitParameters,
 		validateTypeStructural,
+		validateRewriteStructural,
 		validateRefGlobs,
 	)
 }
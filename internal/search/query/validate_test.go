@@ -95,6 +95,14 @@ func TestValidation(t *testing.T) {
 			input: "repo:foo author:rob@saucegraph.com",
 			want:  `your query contains the field 'author', which requires type:commit or type:diff in the query`,
 		},
+		{
+			input: "repo:foo during:lastweek",
+			want:  `your query contains the field 'during', which requires type:commit or type:diff in the query`,
+		},
+		{
+			input: "type:commit during:nextweek",
+			want:  `invalid period. Use one of today, yesterday, thisweek, lastweek, thismonth, lastmonth, thisquarter, lastquarter, thisyear or lastyear`,
+		},
 		{
 			input: "repohasfile:README type:symbol yolo",
 			want:  "repohasfile is not compatible for type:symbol. Subscribe to https://github.com/sourcegraph/sourcegraph/issues/4610 for updates",
@@ -121,6 +129,15 @@ func TestValidation(t *testing.T) {
 			want:       "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents and is not currently supported for diff searches",
 			searchType: SearchTypeStructural,
 		},
+		{
+			input: `fmt.Println(:[x]) rewrite:"log.Println(:[x])"`,
+			want:  "the query contains `rewrite:`, which requires a structural search pattern. Add patterntype:structural and a pattern to the query and try again",
+		},
+		{
+			input:      "fmt.Println(:[x]) rewrite:a rewrite:b",
+			want:       `field "rewrite" may not be used more than once`,
+			searchType: SearchTypeStructural,
+		},
 	}
 	for _, c := range cases {
 		t.Run("validate and/or query", func(t *testing.T) {
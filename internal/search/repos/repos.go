@@ -186,6 +186,15 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (_ Resolv
 		options.SearchContextID = searchContext.ID
 		options.UserID = searchContext.NamespaceUserID
 		options.OrgID = searchContext.NamespaceOrgID
+
+		// Composed search contexts are materialized periodically, and whenever their
+		// definition changes.
+		if searchcontexts.IsComposedSearchContext(searchContext) && searchContext.MaterializedVersion != searchContext.Version {
+			tr.AddEvent("materializing search context")
+			if err := searchcontexts.MaterializeSearchContext(ctx, r.db, searchContext); err != nil {
+				return Resolved{}, errors.Wrap(err, "materialize search context")
+			}
+		}
 	}
 
 	tr.AddEvent("Repos.ListMinimalRepos - start")
@@ -226,7 +235,11 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (_ Resolv
 
 	var searchContextRepositoryRevisions map[api.RepoID]RepoRevSpecs
 	if !searchcontexts.IsAutoDefinedSearchContext(searchContext) && searchContext.Query == "" {
-		scRepoRevs, err := searchcontexts.GetRepositoryRevisions(ctx, r.db, searchContext.ID)
+		getRepositoryRevisions := searchcontexts.GetRepositoryRevisions
+		if searchcontexts.IsComposedSearchContext(searchContext) {
+			getRepositoryRevisions = searchcontexts.GetMaterializedRepositoryRevisions
+		}
+		scRepoRevs, err := getRepositoryRevisions(ctx, r.db, searchContext.ID)
 		if err != nil {
 			return Resolved{}, err
 		}
@@ -249,7 +262,9 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (_ Resolv
 	tr.AddEvent("completed rev association")
 
 	tr.AddEvent("starting glob expansion")
-	normalized, normalizedMissingRepoRevs, err := r.normalizeRefs(ctx, associatedRepoRevs)
+	// Repository filters on revisions need each ref a glob expands to.
+	preserveRefGlobs := op.PreserveRefGlobs && op.CommitAfter == nil && len(op.HasFileContent) == 0
+	normalized, normalizedMissingRepoRevs, err := r.normalizeRefs(ctx, associatedRepoRevs, preserveRefGlobs)
 	missingRepoRevs = append(missingRepoRevs, normalizedMissingRepoRevs...)
 	if err != nil {
 		return Resolved{}, errors.Wrap(err, "normalize refs")
@@ -344,10 +359,10 @@ func (r *Resolver) associateReposWithRevs(
 }
 
 // normalizeRefs handles three jobs:
-// 1) expanding each ref glob into a set of refs
+// 1) expanding each ref glob into a set of refs, unless preserveRefGlobs is set
 // 2) checking that every revision (except HEAD) exists
 // 3) expanding the empty string revision (which implicitly means HEAD) into an explicit "HEAD"
-func (r *Resolver) normalizeRefs(ctx context.Context, repoRevSpecs []RepoRevSpecs) ([]*search.RepositoryRevisions, []RepoRevSpecs, error) {
+func (r *Resolver) normalizeRefs(ctx context.Context, repoRevSpecs []RepoRevSpecs, preserveRefGlobs bool) ([]*search.RepositoryRevisions, []RepoRevSpecs, error) {
 	results := make([]*search.RepositoryRevisions, len(repoRevSpecs))
 
 	var (
@@ -364,7 +379,7 @@ func (r *Resolver) normalizeRefs(ctx context.Context, repoRevSpecs []RepoRevSpec
 	for i, repoRev := range repoRevSpecs {
 		i, repoRev := i, repoRev
 		p.Go(func(ctx context.Context) error {
-			expanded, err := r.normalizeRepoRefs(ctx, repoRev.Repo, repoRev.Revs, addMissing)
+			expanded, err := r.normalizeRepoRefs(ctx, repoRev.Repo, repoRev.Revs, preserveRefGlobs, addMissing)
 			if err != nil {
 				return err
 			}
@@ -395,6 +410,7 @@ func (r *Resolver) normalizeRepoRefs(
 	ctx context.Context,
 	repo types.MinimalRepo,
 	revSpecs []query.RevisionSpecifier,
+	preserveRefGlobs bool,
 	reportMissing func(RepoRevSpecs),
 ) ([]string, error) {
 	revs := make([]string, 0, len(revSpecs))
@@ -430,6 +446,19 @@ func (r *Resolver) normalizeRepoRefs(
 		return revs, nil
 	}
 
+	if preserveRefGlobs {
+		// Exclude globs on their own match no refs.
+		if !hasIncludeGlob(globs) {
+			return revs, nil
+		}
+		for _, rev := range revSpecs {
+			if rev.HasRefGlob() {
+				revs = append(revs, rev.String())
+			}
+		}
+		return revs, nil
+	}
+
 	rg, err := gitdomain.CompileRefGlobs(globs)
 	if err != nil {
 		return nil, err
@@ -450,6 +479,15 @@ func (r *Resolver) normalizeRepoRefs(
 
 }
 
+func hasIncludeGlob(globs []gitdomain.RefGlob) bool {
+	for _, g := range globs {
+		if g.Include != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // filterHasCommitAfter filters the revisions on each of a set of RepositoryRevisions to ensure that
 // any repo-level filters (e.g. `repo:contains.commit.after()`) apply to this repo/rev combo.
 func (r *Resolver) filterHasCommitAfter(
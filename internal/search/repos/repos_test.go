@@ -79,9 +79,10 @@ func TestRevisionValidation(t *testing.T) {
 	})
 
 	tests := []struct {
-		repoFilters  []string
-		wantRepoRevs []*search.RepositoryRevisions
-		wantErr      error
+		repoFilters      []string
+		preserveRefGlobs bool
+		wantRepoRevs     []*search.RepositoryRevisions
+		wantErr          error
 	}{
 		{
 			repoFilters: []string{"repoFoo@revBar:^revBas"},
@@ -97,6 +98,14 @@ func TestRevisionValidation(t *testing.T) {
 				Revs: []string{"revBar"},
 			}},
 		},
+		{
+			repoFilters:      []string{"repoFoo@revBar:*refs/heads/*:*!refs/heads/revBas"},
+			preserveRefGlobs: true,
+			wantRepoRevs: []*search.RepositoryRevisions{{
+				Repo: types.MinimalRepo{Name: "repoFoo"},
+				Revs: []string{"revBar", "*refs/heads/*", "*!refs/heads/revBas"},
+			}},
+		},
 		{
 			repoFilters: []string{"repoFoo@revBar:^revQux"},
 			wantRepoRevs: []*search.RepositoryRevisions{{
@@ -144,7 +153,10 @@ func TestRevisionValidation(t *testing.T) {
 			db := database.NewMockDB()
 			db.ReposFunc.SetDefaultReturn(repos)
 
-			op := search.RepoOptions{RepoFilters: toParsedRepoFilters(tt.repoFilters...)}
+			op := search.RepoOptions{
+				RepoFilters:      toParsedRepoFilters(tt.repoFilters...),
+				PreserveRefGlobs: tt.preserveRefGlobs,
+			}
 			repositoryResolver := NewResolver(logtest.Scoped(t), db, nil, nil, nil)
 			repositoryResolver.gitserver = mockGitserver
 			resolved, err := repositoryResolver.Resolve(context.Background(), op)
@@ -54,6 +54,11 @@ type FileMatch struct {
 	// Note: this is a pointer since usually this is unset. Pointer is 8 bytes
 	// vs an empty string which is 16 bytes.
 	Debug *string `json:"-"`
+
+	// RewriteDiff is optionally set with a unified diff previewing the
+	// rewrite of the file for structural search queries with a rewrite
+	// template.
+	RewriteDiff *string `json:"-"`
 }
 
 func (fm *FileMatch) RepoName() types.MinimalRepo {
@@ -26,6 +26,10 @@ type Symbol struct {
 	Signature  string
 
 	FileLimited bool
+
+	// Precise is true if the symbol was read from a precise code intelligence
+	// index rather than extracted by ctags.
+	Precise bool
 }
 
 // NewSymbolMatch returns a new SymbolMatch. Passing -1 as the character will make NewSymbolMatch infer
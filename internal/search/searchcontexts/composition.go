@@ -0,0 +1,339 @@
+package searchcontexts
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	maxIncludedSearchContexts = 50
+	maxRepositoryQueries      = 50
+
+	// materializeInterval is how often the repository queries of composed search
+	// contexts are re-resolved to pick up added and removed repositories.
+	materializeInterval = 10 * time.Minute
+)
+
+// IsComposedSearchContext returns true if the search context includes other search
+// contexts or repository queries. The repositories of composed search contexts are
+// materialized, see MaterializeSearchContext.
+func IsComposedSearchContext(searchContext *types.SearchContext) bool {
+	return len(searchContext.IncludedSearchContextIDs) > 0 || len(searchContext.RepositoryQueries) > 0
+}
+
+func validateSearchContextComposition(ctx context.Context, db database.DB, searchContext *types.SearchContext) error {
+	if searchContext.Query != "" && IsComposedSearchContext(searchContext) {
+		return errors.New("search context query is mutually exclusive with included search contexts and repository queries")
+	}
+
+	if len(searchContext.RepositoryQueries) > maxRepositoryQueries {
+		return errors.Errorf("search context can contain at most %d repository queries", maxRepositoryQueries)
+	}
+	for _, repositoryQuery := range searchContext.RepositoryQueries {
+		if err := validateSearchContextRepositoryQuery(repositoryQuery); err != nil {
+			return err
+		}
+	}
+
+	return validateSearchContextIncludes(ctx, db, searchContext)
+}
+
+// validateSearchContextRepositoryQuery validates that the repository query only
+// consists of the repository-level filters of search context queries.
+func validateSearchContextRepositoryQuery(repositoryQuery string) error {
+	if err := validateSearchContextQuery(repositoryQuery); err != nil {
+		return err
+	}
+
+	plan, err := query.Pipeline(query.Init(repositoryQuery, query.SearchTypeRegex))
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	hasRepo := false
+	query.VisitParameter(plan.ToQ(), func(field, value string, negated bool, a query.Annotation) {
+		switch field {
+		case query.FieldRepo:
+			if !negated {
+				hasRepo = true
+			}
+		case query.FieldFile, query.FieldLang:
+			errs = errors.Append(errs,
+				errors.Errorf("unsupported field in repository query: %q", field))
+		}
+	})
+	if errs == nil && !hasRepo {
+		errs = errors.Errorf("repository query %q must contain a repo: filter", repositoryQuery)
+	}
+	return errs
+}
+
+// validateSearchContextIncludes validates that the current user can read the
+// included search contexts, and that including them does not create a cycle.
+func validateSearchContextIncludes(ctx context.Context, db database.DB, searchContext *types.SearchContext) error {
+	if len(searchContext.IncludedSearchContextIDs) > maxIncludedSearchContexts {
+		return errors.Errorf("search context can include at most %d search contexts", maxIncludedSearchContexts)
+	}
+
+	seen := make(map[int64]struct{}, len(searchContext.IncludedSearchContextIDs))
+	for _, includedID := range searchContext.IncludedSearchContextIDs {
+		if includedID == 0 {
+			return errors.New("cannot include the global search context")
+		}
+		if includedID == searchContext.ID {
+			return errors.New("search context cannot include itself")
+		}
+		if _, ok := seen[includedID]; ok {
+			return errors.New("search context cannot include the same search context twice")
+		}
+		seen[includedID] = struct{}{}
+
+		_, err := db.SearchContexts().GetSearchContext(ctx, database.GetSearchContextOptions{ID: includedID})
+		if err != nil {
+			if err == database.ErrSearchContextNotFound {
+				return errors.Errorf("included search context %d not found", includedID)
+			}
+			return err
+		}
+	}
+
+	// A search context that does not exist yet cannot be included by other search
+	// contexts, and therefore cannot be part of a cycle.
+	if searchContext.ID == 0 || len(searchContext.IncludedSearchContextIDs) == 0 {
+		return nil
+	}
+
+	includes, err := db.SearchContexts().GetSearchContextIncludes(ctx)
+	if err != nil {
+		return err
+	}
+	includes[searchContext.ID] = searchContext.IncludedSearchContextIDs
+
+	if includesSearchContext(includes, searchContext.IncludedSearchContextIDs, searchContext.ID) {
+		return errors.New("including these search contexts would create a cycle")
+	}
+	return nil
+}
+
+// includesSearchContext returns true if any of the given search contexts includes
+// the target search context, directly or transitively.
+func includesSearchContext(includes map[int64][]int64, searchContextIDs []int64, targetID int64) bool {
+	visited := map[int64]struct{}{}
+	queue := append([]int64(nil), searchContextIDs...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		if id == targetID {
+			return true
+		}
+		if _, ok := visited[id]; ok {
+			continue
+		}
+		visited[id] = struct{}{}
+		queue = append(queue, includes[id]...)
+	}
+	return false
+}
+
+// MaterializeSearchContext resolves the repositories and revisions of the given composed
+// search context, and of all the search contexts it includes, and stores them for the
+// current version of the search context.
+func MaterializeSearchContext(ctx context.Context, db database.DB, searchContext *types.SearchContext) error {
+	// The materialized repositories are shared by all users of the search context:
+	// repository permissions are enforced when the repositories are searched.
+	ctx = actor.WithInternalActor(ctx)
+
+	r := &compositionResolver{
+		db:      db,
+		onPath:  map[int64]struct{}{},
+		visited: map[int64]struct{}{},
+		repos:   map[api.RepoID]types.MinimalRepo{},
+		revs:    map[api.RepoID]map[string]struct{}{},
+	}
+	if err := r.resolve(ctx, searchContext); err != nil {
+		return err
+	}
+
+	return db.SearchContexts().SetMaterializedSearchContextRepositoryRevisions(ctx, searchContext.ID, searchContext.Version, r.repositoryRevisions())
+}
+
+// GetMaterializedRepositoryRevisions returns the materialized repositories and revisions
+// of the given composed search context.
+func GetMaterializedRepositoryRevisions(ctx context.Context, db database.DB, searchContextID int64) ([]search.RepositoryRevisions, error) {
+	searchContextRepositoryRevisions, err := db.SearchContexts().GetMaterializedSearchContextRepositoryRevisions(ctx, searchContextID)
+	if err != nil {
+		return nil, err
+	}
+	return toRepositoryRevisions(searchContextRepositoryRevisions), nil
+}
+
+// compositionResolver collects the repositories and revisions of a composed search
+// context.
+type compositionResolver struct {
+	db database.DB
+
+	// onPath contains the search contexts being resolved, to detect cycles.
+	onPath  map[int64]struct{}
+	visited map[int64]struct{}
+
+	repos map[api.RepoID]types.MinimalRepo
+	revs  map[api.RepoID]map[string]struct{}
+}
+
+func (r *compositionResolver) resolve(ctx context.Context, searchContext *types.SearchContext) error {
+	if _, ok := r.onPath[searchContext.ID]; ok {
+		return errors.Errorf("search context %q includes itself", GetSearchContextSpec(searchContext))
+	}
+	if _, ok := r.visited[searchContext.ID]; ok {
+		return nil
+	}
+	r.visited[searchContext.ID] = struct{}{}
+	r.onPath[searchContext.ID] = struct{}{}
+	defer delete(r.onPath, searchContext.ID)
+
+	repositoryRevisions, err := r.db.SearchContexts().GetSearchContextRepositoryRevisions(ctx, searchContext.ID)
+	if err != nil {
+		return err
+	}
+	for _, repoRev := range repositoryRevisions {
+		r.add(repoRev.Repo, repoRev.Revisions)
+	}
+
+	repositoryQueries := searchContext.RepositoryQueries
+	if searchContext.Query != "" {
+		// Only the repository-level filters of an included query-based search context apply.
+		repositoryQueries = append(repositoryQueries[:len(repositoryQueries):len(repositoryQueries)], searchContext.Query)
+	}
+	for _, repositoryQuery := range repositoryQueries {
+		if err := r.resolveRepositoryQuery(ctx, repositoryQuery); err != nil {
+			return errors.Wrapf(err, "resolving repository query %q", repositoryQuery)
+		}
+	}
+
+	for _, includedID := range searchContext.IncludedSearchContextIDs {
+		included, err := r.db.SearchContexts().GetSearchContext(ctx, database.GetSearchContextOptions{ID: includedID})
+		if err != nil {
+			if err == database.ErrSearchContextNotFound {
+				// The included search context was deleted concurrently.
+				continue
+			}
+			return err
+		}
+		if err := r.resolve(ctx, included); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *compositionResolver) resolveRepositoryQuery(ctx context.Context, repositoryQuery string) error {
+	opts, err := ParseRepoOpts(repositoryQuery)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range opts {
+		repos, err := r.db.Repos().ListMinimalRepos(ctx, o.ReposListOptions)
+		if err != nil {
+			return err
+		}
+
+		revs := o.RevSpecs
+		if len(revs) == 0 {
+			revs = []string{"HEAD"}
+		}
+		for _, repo := range repos {
+			r.add(repo, revs)
+		}
+	}
+	return nil
+}
+
+func (r *compositionResolver) add(repo types.MinimalRepo, revs []string) {
+	r.repos[repo.ID] = repo
+	if r.revs[repo.ID] == nil {
+		r.revs[repo.ID] = map[string]struct{}{}
+	}
+	for _, rev := range revs {
+		r.revs[repo.ID][rev] = struct{}{}
+	}
+}
+
+func (r *compositionResolver) repositoryRevisions() []*types.SearchContextRepositoryRevisions {
+	out := make([]*types.SearchContextRepositoryRevisions, 0, len(r.repos))
+	for id, repo := range r.repos {
+		revs := make([]string, 0, len(r.revs[id]))
+		for rev := range r.revs[id] {
+			revs = append(revs, rev)
+		}
+		sort.Strings(revs)
+
+		out = append(out, &types.SearchContextRepositoryRevisions{Repo: repo, Revisions: revs})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Repo.ID < out[j].Repo.ID })
+	return out
+}
+
+// NewMaterializer returns a background routine that periodically re-materializes the
+// repositories of all composed search contexts.
+func NewMaterializer(observationCtx *observation.Context, db database.DB) goroutine.BackgroundRoutine {
+	m := &materializer{
+		logger: observationCtx.Logger.Scoped("SearchContextMaterializer", "materializes the repositories of composed search contexts"),
+		db:     db,
+	}
+	return goroutine.NewPeriodicGoroutine(
+		context.Background(),
+		goroutine.HandlerFunc(m.materializeAll),
+		goroutine.WithName("search_contexts.materializer"),
+		goroutine.WithDescription("re-resolves the repositories of search contexts composed from other search contexts and repository queries"),
+		goroutine.WithInterval(materializeInterval),
+	)
+}
+
+type materializer struct {
+	logger log.Logger
+	db     database.DB
+}
+
+func (m *materializer) materializeAll(ctx context.Context) error {
+	ctx = actor.WithInternalActor(ctx)
+
+	const pageSize = 100
+	for offset := int32(0); ; offset += pageSize {
+		searchContexts, err := m.db.SearchContexts().ListSearchContexts(ctx,
+			database.ListSearchContextsPageOptions{First: pageSize, After: offset},
+			database.ListSearchContextsOptions{OnlyComposed: true},
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, searchContext := range searchContexts {
+			if err := MaterializeSearchContext(ctx, m.db, searchContext); err != nil {
+				m.logger.Warn("failed to materialize search context",
+					log.Int64("searchContextID", searchContext.ID),
+					log.Error(err))
+			}
+		}
+
+		if len(searchContexts) < pageSize {
+			return nil
+		}
+	}
+}
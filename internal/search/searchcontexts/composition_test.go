@@ -0,0 +1,185 @@
+package searchcontexts
+
+import (
+	"context"
+	"testing"
+
+	mockrequire "github.com/derision-test/go-mockgen/testutil/require"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestIncludesSearchContext(t *testing.T) {
+	includes := map[int64][]int64{
+		1: {2, 3},
+		2: {4},
+		3: {4},
+		5: {1},
+	}
+
+	require.True(t, includesSearchContext(includes, []int64{1}, 4))
+	require.True(t, includesSearchContext(includes, []int64{5}, 3))
+	require.False(t, includesSearchContext(includes, []int64{2, 3}, 1))
+	require.False(t, includesSearchContext(includes, []int64{1}, 5))
+}
+
+func TestValidateSearchContextRepositoryQuery(t *testing.T) {
+	tests := []struct {
+		query   string
+		wantErr string
+	}{
+		{query: `repo:^github\.com/sourcegraph/ fork:yes archived:no`},
+		{query: `repo:^github\.com/sourcegraph/ -repo:sourcegraph$ visibility:public`},
+		{query: `repo:has.topic(go)`},
+		{query: `fork:yes`, wantErr: "must contain a repo: filter"},
+		{query: `repo:sourcegraph file:\.go$`, wantErr: `unsupported field in repository query: "file"`},
+		{query: `repo:sourcegraph lang:go`, wantErr: `unsupported field in repository query: "lang"`},
+		{query: `repo:sourcegraph type:diff`, wantErr: `unsupported field in search context query: "type"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			err := validateSearchContextRepositoryQuery(tt.query)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateSearchContextComposition(t *testing.T) {
+	sc := database.NewMockSearchContextsStore()
+	sc.GetSearchContextFunc.SetDefaultHook(func(_ context.Context, opts database.GetSearchContextOptions) (*types.SearchContext, error) {
+		if opts.ID == 404 {
+			return nil, database.ErrSearchContextNotFound
+		}
+		return &types.SearchContext{ID: opts.ID}, nil
+	})
+	sc.GetSearchContextIncludesFunc.SetDefaultHook(func(context.Context) (map[int64][]int64, error) {
+		return map[int64][]int64{2: {3}, 3: {1}}, nil
+	})
+	db := database.NewMockDB()
+	db.SearchContextsFunc.SetDefaultReturn(sc)
+
+	tests := []struct {
+		name          string
+		searchContext *types.SearchContext
+		wantErr       string
+	}{
+		{
+			name:          "valid",
+			searchContext: &types.SearchContext{ID: 1, IncludedSearchContextIDs: []int64{4}, RepositoryQueries: []string{"repo:a"}},
+		},
+		{
+			name:          "query is mutually exclusive",
+			searchContext: &types.SearchContext{ID: 1, Query: "repo:a", IncludedSearchContextIDs: []int64{4}},
+			wantErr:       "mutually exclusive",
+		},
+		{
+			name:          "global search context",
+			searchContext: &types.SearchContext{ID: 1, IncludedSearchContextIDs: []int64{0}},
+			wantErr:       "cannot include the global search context",
+		},
+		{
+			name:          "itself",
+			searchContext: &types.SearchContext{ID: 1, IncludedSearchContextIDs: []int64{1}},
+			wantErr:       "cannot include itself",
+		},
+		{
+			name:          "unknown search context",
+			searchContext: &types.SearchContext{ID: 1, IncludedSearchContextIDs: []int64{404}},
+			wantErr:       "included search context 404 not found",
+		},
+		{
+			name:          "cycle",
+			searchContext: &types.SearchContext{ID: 1, IncludedSearchContextIDs: []int64{2}},
+			wantErr:       "would create a cycle",
+		},
+		{
+			name:          "new search context cannot create a cycle",
+			searchContext: &types.SearchContext{IncludedSearchContextIDs: []int64{2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSearchContextComposition(context.Background(), db, tt.searchContext)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMaterializeSearchContext(t *testing.T) {
+	repoA := types.MinimalRepo{ID: 1, Name: "a"}
+	repoB := types.MinimalRepo{ID: 2, Name: "b"}
+	repoC := types.MinimalRepo{ID: 3, Name: "c"}
+
+	searchContexts := map[int64]*types.SearchContext{
+		2: {ID: 2, Name: "included", IncludedSearchContextIDs: []int64{3}},
+		3: {ID: 3, Name: "nested", RepositoryQueries: []string{"repo:^c$@v1"}},
+	}
+
+	sc := database.NewMockSearchContextsStore()
+	sc.GetSearchContextFunc.SetDefaultHook(func(_ context.Context, opts database.GetSearchContextOptions) (*types.SearchContext, error) {
+		return searchContexts[opts.ID], nil
+	})
+	sc.GetSearchContextRepositoryRevisionsFunc.SetDefaultHook(func(_ context.Context, id int64) ([]*types.SearchContextRepositoryRevisions, error) {
+		switch id {
+		case 1:
+			return []*types.SearchContextRepositoryRevisions{{Repo: repoA, Revisions: []string{"main"}}}, nil
+		case 2:
+			return []*types.SearchContextRepositoryRevisions{{Repo: repoA, Revisions: []string{"dev"}}}, nil
+		}
+		return nil, nil
+	})
+
+	repos := database.NewMockRepoStore()
+	repos.ListMinimalReposFunc.SetDefaultHook(func(ctx context.Context, opts database.ReposListOptions) ([]types.MinimalRepo, error) {
+		require.True(t, actor.FromContext(ctx).IsInternal())
+		switch opts.IncludePatterns[0] {
+		case "^b$":
+			return []types.MinimalRepo{repoB}, nil
+		case "^c$":
+			return []types.MinimalRepo{repoC}, nil
+		}
+		return nil, nil
+	})
+
+	db := database.NewMockDB()
+	db.SearchContextsFunc.SetDefaultReturn(sc)
+	db.ReposFunc.SetDefaultReturn(repos)
+
+	composed := &types.SearchContext{
+		ID:                       1,
+		Name:                     "composed",
+		Version:                  7,
+		IncludedSearchContextIDs: []int64{2},
+		RepositoryQueries:        []string{"repo:^b$"},
+	}
+	err := MaterializeSearchContext(context.Background(), db, composed)
+	require.NoError(t, err)
+
+	mockrequire.CalledOnce(t, sc.SetMaterializedSearchContextRepositoryRevisionsFunc)
+	call := sc.SetMaterializedSearchContextRepositoryRevisionsFunc.History()[0]
+	require.Equal(t, int64(1), call.Arg1)
+	require.Equal(t, int32(7), call.Arg2)
+	require.Equal(t, []*types.SearchContextRepositoryRevisions{
+		{Repo: repoA, Revisions: []string{"dev", "main"}},
+		{Repo: repoB, Revisions: []string{"HEAD"}},
+		{Repo: repoC, Revisions: []string{"v1"}},
+	}, call.Arg3)
+
+	// Cycles introduced concurrently with validation are detected.
+	searchContexts[3].IncludedSearchContextIDs = []int64{2}
+	err = MaterializeSearchContext(context.Background(), db, composed)
+	require.ErrorContains(t, err, "includes itself")
+}
@@ -304,6 +304,11 @@ func CreateSearchContextWithRepositoryRevisions(
 		return nil, err
 	}
 
+	err = validateSearchContextComposition(ctx, db, searchContext)
+	if err != nil {
+		return nil, err
+	}
+
 	err = validateSearchContextDoesNotExist(ctx, db, searchContext)
 	if err != nil {
 		return nil, err
@@ -350,6 +355,11 @@ func UpdateSearchContextWithRepositoryRevisions(ctx context.Context, db database
 		return nil, err
 	}
 
+	err = validateSearchContextComposition(ctx, db, searchContext)
+	if err != nil {
+		return nil, err
+	}
+
 	searchContext, err = db.SearchContexts().UpdateSearchContextWithRepositoryRevisions(ctx, searchContext, repositoryRevisions)
 	if err != nil {
 		return nil, err
@@ -508,7 +518,10 @@ func GetRepositoryRevisions(ctx context.Context, db database.DB, searchContextID
 	if err != nil {
 		return nil, err
 	}
+	return toRepositoryRevisions(searchContextRepositoryRevisions), nil
+}
 
+func toRepositoryRevisions(searchContextRepositoryRevisions []*types.SearchContextRepositoryRevisions) []search.RepositoryRevisions {
 	repositoryRevisions := make([]search.RepositoryRevisions, 0, len(searchContextRepositoryRevisions))
 	for _, searchContextRepositoryRevision := range searchContextRepositoryRevisions {
 		repositoryRevisions = append(repositoryRevisions, search.RepositoryRevisions{
@@ -516,7 +529,7 @@ func GetRepositoryRevisions(ctx context.Context, db database.DB, searchContextID
 			Revs: searchContextRepositoryRevision.Revisions,
 		})
 	}
-	return repositoryRevisions, nil
+	return repositoryRevisions
 }
 
 func IsAutoDefinedSearchContext(searchContext *types.SearchContext) bool {
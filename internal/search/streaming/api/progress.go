@@ -31,9 +31,34 @@ func BuildProgressEvent(stats ProgressStats, namer RepoNamer) Progress {
 		DurationMs:        stats.ElapsedMilliseconds,
 		Skipped:           skipped,
 		Trace:             stats.Trace,
+		RepositoryTimings: repositoryTimings(stats.RepoTimings, namer),
+		ResumeToken:       stats.ResumeToken,
 	}
 }
 
+func repositoryTimings(stats []RepoTimingStats, namer RepoNamer) []RepositoryTiming {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	ids := make([]api.RepoID, 0, len(stats))
+	for _, s := range stats {
+		ids = append(ids, s.RepoID)
+	}
+	names := namer(ids)
+
+	timings := make([]RepositoryTiming, 0, len(stats))
+	for i, s := range stats {
+		timings = append(timings, RepositoryTiming{
+			Repository: string(names[i]),
+			DurationMs: s.DurationMs,
+			MatchCount: s.MatchCount,
+			TimedOut:   s.TimedOut,
+		})
+	}
+	return timings
+}
+
 type ProgressStats struct {
 	MatchCount          int
 	ElapsedMilliseconds int
@@ -48,6 +73,11 @@ type ProgressStats struct {
 
 	LimitHit bool
 
+	// The query-level resource limits that were hit.
+	ReposScannedLimitHit bool
+	DurationLimitHit     bool
+	ResultBytesLimitHit  bool
+
 	// SuggestedLimit is what to suggest to the user for count if needed.
 	SuggestedLimit int
 
@@ -55,12 +85,27 @@ type ProgressStats struct {
 
 	DisplayLimit int
 
+	// RepoTimings are the timings of the repositories to include in the
+	// progress event.
+	RepoTimings []RepoTimingStats
+
+	// ResumeToken is the encoded resume token, if the search can be resumed.
+	ResumeToken string
+
 	// we smuggle in the namer via this field. Note: we don't calculate the
 	// name of every repository in Timedout, Missing, etc since we only need a
 	// subset of the names. As such we lazily calculate the names via namer.
 	namer RepoNamer
 }
 
+// RepoTimingStats is the timing of the search of a repository.
+type RepoTimingStats struct {
+	RepoID     api.RepoID
+	DurationMs int
+	MatchCount int
+	TimedOut   bool
+}
+
 func skippedReposHandler(repos []api.RepoID, namer RepoNamer, titleVerb, messageReason string, base Skipped) (Skipped, bool) {
 	if len(repos) == 0 {
 		return Skipped{}, false
@@ -212,13 +257,54 @@ func excludedArchiveHandler(resultsResolver ProgressStats) (Skipped, bool) {
 	}, true
 }
 
+func repositoryLimitHandler(resultsResolver ProgressStats) (Skipped, bool) {
+	if !resultsResolver.ReposScannedLimitHit {
+		return Skipped{}, false
+	}
+
+	return Skipped{
+		Reason:   RepositoryLimit,
+		Title:    "repository limit hit",
+		Message:  "Not all repositories have been searched due to hitting the limit for the number of repositories a single search may search. Reduce the scope of your query with `repo:`, `context:` or other filters.",
+		Severity: SeverityWarn,
+	}, true
+}
+
+func queryDurationLimitHandler(resultsResolver ProgressStats) (Skipped, bool) {
+	if !resultsResolver.DurationLimitHit {
+		return Skipped{}, false
+	}
+
+	return Skipped{
+		Reason:   QueryDurationLimit,
+		Title:    "time limit hit",
+		Message:  "Not all results have been returned due to hitting the limit for how long a single search may run. Reduce the scope of your query with `repo:`, `context:` or other filters.",
+		Severity: SeverityWarn,
+	}, true
+}
+
+func resultBytesLimitHandler(resultsResolver ProgressStats) (Skipped, bool) {
+	if !resultsResolver.ResultBytesLimitHit {
+		return Skipped{}, false
+	}
+
+	return Skipped{
+		Reason:   ResultBytesLimit,
+		Title:    "result size limit hit",
+		Message:  "Not all results have been returned due to hitting the limit for the size of the results of a single search. Make your query more specific.",
+		Severity: SeverityWarn,
+	}, true
+}
+
 // TODO implement all skipped reasons
 var skippedHandlers = []func(stats ProgressStats) (Skipped, bool){
 	repositoryMissingHandler,
 	repositoryCloningHandler,
 	// documentMatchLimitHandler,
 	shardMatchLimitHandler,
-	// repositoryLimitHandler,
+	repositoryLimitHandler,
+	queryDurationLimitHandler,
+	resultBytesLimitHandler,
 	shardTimeoutHandler,
 	backendsMissingHandler,
 	excludedForkHandler,
@@ -59,6 +59,12 @@ func TestSearchProgress(t *testing.T) {
 		"traced": {
 			Trace: "abcd",
 		},
+		"querylimits": {
+			ReposScannedLimitHit: true,
+			DurationLimitHit:     true,
+			ResultBytesLimitHit:  true,
+			DisplayLimit:         math.MaxInt32,
+		},
 	}
 
 	for name, c := range cases {
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// MaxResumeRepos is the maximum number of repositories a resume token can
+// capture. No resume token is issued for searches that timed out in more
+// repositories, since resuming them would silently skip repositories.
+const MaxResumeRepos = 1000
+
+// resumeTokenVersion is incremented whenever the encoding of resume tokens
+// changes.
+const resumeTokenVersion = 1
+
+// ResumeToken captures the repositories that a search did not search
+// completely because it timed out, so that clients can continue the search in
+// only those repositories rather than restarting it.
+type ResumeToken struct {
+	// Query is the query the token was issued for.
+	Query string
+	// Repos are the repositories that were not searched completely.
+	Repos []api.RepoID
+}
+
+type encodedResumeToken struct {
+	Version int          `json:"v"`
+	Query   string       `json:"q"`
+	Repos   []api.RepoID `json:"r"`
+}
+
+// NewResumeToken returns the encoded resume token for the repositories of a
+// search for query that timed out, or "" if no token can be issued.
+func NewResumeToken(query string, timedout []api.RepoID) string {
+	if len(timedout) == 0 || len(timedout) > MaxResumeRepos {
+		return ""
+	}
+	raw, err := json.Marshal(encodedResumeToken{
+		Version: resumeTokenVersion,
+		Query:   query,
+		Repos:   timedout,
+	})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// ParseResumeToken parses a token returned by NewResumeToken.
+func ParseResumeToken(s string) (*ResumeToken, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("malformed resume token")
+	}
+	var t encodedResumeToken
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return nil, errors.New("malformed resume token")
+	}
+	if t.Version != resumeTokenVersion {
+		return nil, errors.Errorf("unsupported resume token version %d", t.Version)
+	}
+	if len(t.Repos) == 0 || len(t.Repos) > MaxResumeRepos {
+		return nil, errors.Newf("resume token must contain between 1 and %d repositories", MaxResumeRepos)
+	}
+	return &ResumeToken{Query: t.Query, Repos: t.Repos}, nil
+}
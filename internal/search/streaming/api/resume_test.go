@@ -0,0 +1,27 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestResumeToken(t *testing.T) {
+	token := NewResumeToken("foo", []api.RepoID{1, 2})
+	require.NotEmpty(t, token)
+
+	got, err := ParseResumeToken(token)
+	require.NoError(t, err)
+	require.Equal(t, &ResumeToken{Query: "foo", Repos: []api.RepoID{1, 2}}, got)
+
+	require.Empty(t, NewResumeToken("foo", nil))
+	require.Empty(t, NewResumeToken("foo", make([]api.RepoID, MaxResumeRepos+1)))
+
+	for _, malformed := range []string{"!!", "bm90IGpzb24", strings.Repeat("A", 8)} {
+		_, err := ParseResumeToken(malformed)
+		require.Error(t, err, malformed)
+	}
+}
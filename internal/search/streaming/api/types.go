@@ -25,6 +25,28 @@ type Progress struct {
 
 	// Trace is the URL of an associated trace if the query is logging one.
 	Trace string `json:"trace,omitempty"`
+
+	// RepositoryTimings are the timings of the slowest repositories searched
+	// so far, the slowest first.
+	RepositoryTimings []RepositoryTiming `json:"repositoryTimings,omitempty"`
+
+	// ResumeToken is set on the final progress event of a search that timed
+	// out in some repositories. Passing it along with the same query
+	// continues the search in only those repositories.
+	ResumeToken string `json:"resumeToken,omitempty"`
+}
+
+// RepositoryTiming is how long the search of a repository took.
+type RepositoryTiming struct {
+	Repository string `json:"repository"`
+	// DurationMs is the wall clock time in milliseconds from the start of the
+	// search until the last results or status of the repository were
+	// received.
+	DurationMs int `json:"durationMs"`
+	// MatchCount is the number of matches found in the repository.
+	MatchCount int `json:"matchCount"`
+	// TimedOut is true if the repository was not searched completely in time.
+	TimedOut bool `json:"timedOut,omitempty"`
 }
 
 // Skipped is a description of shards or documents that were skipped.
@@ -85,6 +107,12 @@ const (
 	// ExcludedArchive is when we did not search a repository because it is
 	// archived.
 	ExcludedArchive SkippedReason = "excluded-archive"
+	// QueryDurationLimit is when we stopped searching because the query ran
+	// for longer than the maxQueryDurationSeconds site configuration allows.
+	QueryDurationLimit SkippedReason = "query-duration-limit"
+	// ResultBytesLimit is when we stopped searching because the results of
+	// the query were larger than the maxResultBytes site configuration allows.
+	ResultBytesLimit SkippedReason = "result-bytes-limit"
 )
 
 // SkippedSeverity is an enum for Skipped.Severity.
@@ -11,6 +11,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming/api"
 )
 
+// maxRepoTimings is the number of repositories whose timings are included in
+// progress events.
+const maxRepoTimings = 10
+
 type ProgressAggregator struct {
 	Start        time.Time
 	MatchCount   int
@@ -19,10 +23,16 @@ type ProgressAggregator struct {
 	DisplayLimit int
 	Trace        string // may be empty
 
+	// Query is the query of the search, used to issue resume tokens. No
+	// resume token is issued if it is empty.
+	Query string
+
 	RepoNamer api.RepoNamer
 
 	// Dirty is true if p has changed since the last call to Current.
 	Dirty bool
+
+	repoTimings map[sgapi.RepoID]*api.RepoTimingStats
 }
 
 func (p *ProgressAggregator) Update(event streaming.SearchEvent) {
@@ -36,6 +46,7 @@ func (p *ProgressAggregator) Update(event streaming.SearchEvent) {
 
 	p.Dirty = true
 	p.Stats.Update(&event.Stats)
+	elapsed := int(time.Since(p.Start).Milliseconds())
 	for _, match := range event.Results {
 		p.MatchCount += match.ResultCount()
 
@@ -44,7 +55,14 @@ func (p *ProgressAggregator) Update(event streaming.SearchEvent) {
 		// true. Rather than updating every backend to populate this field, we
 		// iterate over results and union in the result IDs.
 		p.Stats.Repos[match.RepoName().ID] = struct{}{}
+
+		if id := match.RepoName().ID; id != 0 {
+			p.repoTiming(id, elapsed).MatchCount += match.ResultCount()
+		}
 	}
+	event.Stats.Status.Filter(searchshared.RepoStatusTimedout, func(id sgapi.RepoID) {
+		p.repoTiming(id, elapsed).TimedOut = true
+	})
 
 	if p.MatchCount > p.Limit {
 		p.MatchCount = p.Limit
@@ -52,23 +70,61 @@ func (p *ProgressAggregator) Update(event streaming.SearchEvent) {
 	}
 }
 
+// repoTiming returns the timing of the repository, updated to the elapsed
+// time in milliseconds at which an event for the repository was received.
+func (p *ProgressAggregator) repoTiming(id sgapi.RepoID, elapsed int) *api.RepoTimingStats {
+	if p.repoTimings == nil {
+		p.repoTimings = map[sgapi.RepoID]*api.RepoTimingStats{}
+	}
+	t, ok := p.repoTimings[id]
+	if !ok {
+		t = &api.RepoTimingStats{RepoID: id}
+		p.repoTimings[id] = t
+	}
+	t.DurationMs = elapsed
+	return t
+}
+
+// slowestRepos returns the timings of the maxRepoTimings slowest
+// repositories, the slowest first.
+func (p *ProgressAggregator) slowestRepos() []api.RepoTimingStats {
+	timings := make([]api.RepoTimingStats, 0, len(p.repoTimings))
+	for _, t := range p.repoTimings {
+		timings = append(timings, *t)
+	}
+	slices.SortFunc(timings, func(a, b api.RepoTimingStats) bool {
+		if a.DurationMs != b.DurationMs {
+			return a.DurationMs > b.DurationMs
+		}
+		return a.RepoID < b.RepoID
+	})
+	if len(timings) > maxRepoTimings {
+		timings = timings[:maxRepoTimings]
+	}
+	return timings
+}
+
 func (p *ProgressAggregator) currentStats() api.ProgressStats {
 	// Suggest the next 1000 after rounding off.
 	suggestedLimit := (p.Limit + 1500) / 1000 * 1000
 
 	return api.ProgressStats{
-		MatchCount:          p.MatchCount,
-		ElapsedMilliseconds: int(time.Since(p.Start).Milliseconds()),
-		BackendsMissing:     p.Stats.BackendsMissing,
-		ExcludedArchived:    p.Stats.ExcludedArchived,
-		ExcludedForks:       p.Stats.ExcludedForks,
-		Timedout:            getRepos(p.Stats, searchshared.RepoStatusTimedout),
-		Missing:             getRepos(p.Stats, searchshared.RepoStatusMissing),
-		Cloning:             getRepos(p.Stats, searchshared.RepoStatusCloning),
-		LimitHit:            p.Stats.IsLimitHit,
-		SuggestedLimit:      suggestedLimit,
-		Trace:               p.Trace,
-		DisplayLimit:        p.DisplayLimit,
+		MatchCount:           p.MatchCount,
+		ElapsedMilliseconds:  int(time.Since(p.Start).Milliseconds()),
+		BackendsMissing:      p.Stats.BackendsMissing,
+		ExcludedArchived:     p.Stats.ExcludedArchived,
+		ExcludedForks:        p.Stats.ExcludedForks,
+		Timedout:             getRepos(p.Stats, searchshared.RepoStatusTimedout),
+		Missing:              getRepos(p.Stats, searchshared.RepoStatusMissing),
+		Cloning:              getRepos(p.Stats, searchshared.RepoStatusCloning),
+		LimitHit:             p.Stats.IsLimitHit,
+		ReposScannedLimitHit: p.Stats.ReposScannedLimitHit,
+		DurationLimitHit:     p.Stats.DurationLimitHit,
+		ResultBytesLimitHit:  p.Stats.ResultBytesLimitHit,
+		SuggestedLimit:       suggestedLimit,
+		Trace:                p.Trace,
+		DisplayLimit:         p.DisplayLimit,
+		RepoTimings:          p.slowestRepos(),
 	}
 }
 
@@ -91,6 +147,9 @@ func (p *ProgressAggregator) Final() api.Progress {
 	if c := len(p.Stats.Repos); c > 0 {
 		s.RepositoriesCount = intPtr(c)
 	}
+	if p.Query != "" {
+		s.ResumeToken = api.NewResumeToken(p.Query, s.Timedout)
+	}
 
 	event := api.BuildProgressEvent(s, p.RepoNamer)
 	event.Done = true
@@ -36,6 +36,7 @@ func NewRequestWithVersion(baseURL, query, version string) (*http.Request, error
 type FrontendStreamDecoder struct {
 	OnProgress func(*api.Progress)
 	OnMatches  func([]EventMatch)
+	OnGroups   func([]*EventGroup)
 	OnFilters  func([]*EventFilter)
 	OnAlert    func(*EventAlert)
 	OnError    func(*EventError)
@@ -71,6 +72,15 @@ func (rr FrontendStreamDecoder) ReadAll(r io.Reader) error {
 				m = append(m, e.EventMatch)
 			}
 			rr.OnMatches(m)
+		} else if bytes.Equal(event, []byte("groups")) {
+			if rr.OnGroups == nil {
+				continue
+			}
+			var d []*EventGroup
+			if err := json.Unmarshal(data, &d); err != nil {
+				return errors.Errorf("failed to decode groups payload: %w", err)
+			}
+			rr.OnGroups(d)
 		} else if bytes.Equal(event, []byte("filters")) {
 			if rr.OnFilters == nil {
 				continue
@@ -140,3 +150,21 @@ func (r *eventMatchUnmarshaller) UnmarshalJSON(b []byte) error {
 	}
 	return json.Unmarshal(b, r.EventMatch)
 }
+
+func (g *EventGroup) UnmarshalJSON(b []byte) error {
+	type eventGroup EventGroup
+	var d struct {
+		*eventGroup
+		Matches []eventMatchUnmarshaller `json:"matches"`
+	}
+	d.eventGroup = (*eventGroup)(g)
+	if err := json.Unmarshal(b, &d); err != nil {
+		return err
+	}
+
+	g.Matches = make([]EventMatch, 0, len(d.Matches))
+	for _, m := range d.Matches {
+		g.Matches = append(g.Matches, m.EventMatch)
+	}
+	return nil
+}
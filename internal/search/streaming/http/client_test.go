@@ -49,6 +49,24 @@ func TestFrontendClient(t *testing.T) {
 				Detail: "test",
 			},
 		},
+	}, {
+		Name: "groups",
+		Value: []*EventGroup{{
+			Type:       "file",
+			Repository: "test",
+			Path:       "test",
+			MatchCount: 2,
+			Matches: []EventMatch{
+				&EventContentMatch{
+					Type: ContentMatchType,
+					Path: "test",
+				},
+				&EventSymbolMatch{
+					Type: SymbolMatchType,
+					Path: "test",
+				},
+			},
+		}},
 	}, {
 		Name: "filters",
 		Value: []*EventFilter{{
@@ -99,6 +117,9 @@ func TestFrontendClient(t *testing.T) {
 		OnMatches: func(d []EventMatch) {
 			got = append(got, Event{Name: "matches", Value: d})
 		},
+		OnGroups: func(d []*EventGroup) {
+			got = append(got, Event{Name: "groups", Value: d})
+		},
 		OnFilters: func(d []*EventFilter) {
 			got = append(got, Event{Name: "filters", Value: d})
 		},
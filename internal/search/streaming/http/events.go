@@ -31,6 +31,7 @@ type EventContentMatch struct {
 	LineMatches     []EventLineMatch `json:"lineMatches,omitempty"`
 	ChunkMatches    []ChunkMatch     `json:"chunkMatches,omitempty"`
 	Debug           string           `json:"debug,omitempty"`
+	RewriteDiff     string           `json:"rewriteDiff,omitempty"`
 }
 
 func (e *EventContentMatch) eventMatch() {}
@@ -137,6 +138,11 @@ type Symbol struct {
 	ContainerName string `json:"containerName"`
 	Kind          string `json:"kind"`
 	Line          int32  `json:"line"`
+	Signature     string `json:"signature,omitempty"`
+
+	// Precise is true if the symbol was read from a precise code intelligence
+	// index rather than extracted by ctags.
+	Precise bool `json:"precise,omitempty"`
 }
 
 // EventCommitMatch is the generic results interface from GQL. There is a lot
@@ -200,6 +206,25 @@ type EventTeamMatch struct {
 
 func (e *EventTeamMatch) eventMatch() {}
 
+// EventGroup is a group of matches sharing a repository, file or symbol. Groups
+// are only sent when the client requests server-side grouping.
+type EventGroup struct {
+	// Type is the kind of group: "repo", "file" or "symbol".
+	Type string `json:"type"`
+
+	Repository   string `json:"repository,omitempty"`
+	RepositoryID int32  `json:"repositoryID,omitempty"`
+	Path         string `json:"path,omitempty"`
+	Symbol       string `json:"symbol,omitempty"`
+	SymbolKind   string `json:"symbolKind,omitempty"`
+
+	// MatchCount is the number of results in the group, as counted by the
+	// progress event.
+	MatchCount int `json:"matchCount"`
+
+	Matches []EventMatch `json:"matches"`
+}
+
 // EventFilter is a suggestion for a search filter. Currently has a 1-1
 // correspondance with the SearchFilter graphql type.
 type EventFilter struct {
@@ -33,6 +33,18 @@ type Stats struct {
 	// ExcludedArchived is the count of excluded archived repos because the
 	// search query doesn't apply to them, but that we want to know about.
 	ExcludedArchived int
+
+	// ReposScannedLimitHit is true if repositories were not searched because
+	// the query hit the maxReposScanned limit.
+	ReposScannedLimitHit bool
+
+	// DurationLimitHit is true if the query was stopped because it hit the
+	// maxQueryDurationSeconds limit.
+	DurationLimitHit bool
+
+	// ResultBytesLimitHit is true if the query was stopped because it hit the
+	// maxResultBytes limit.
+	ResultBytesLimitHit bool
 }
 
 // Update updates c with the other data, deduping as necessary. It modifies c but
@@ -58,6 +70,10 @@ func (c *Stats) Update(other *Stats) {
 	c.BackendsMissing += other.BackendsMissing
 	c.ExcludedForks += other.ExcludedForks
 	c.ExcludedArchived += other.ExcludedArchived
+
+	c.ReposScannedLimitHit = c.ReposScannedLimitHit || other.ReposScannedLimitHit
+	c.DurationLimitHit = c.DurationLimitHit || other.DurationLimitHit
+	c.ResultBytesLimitHit = c.ResultBytesLimitHit || other.ResultBytesLimitHit
 }
 
 // Zero returns true if stats is empty. IE calling Update will result in no
@@ -72,7 +88,10 @@ func (c *Stats) Zero() bool {
 		c.Status.Len() > 0 ||
 		c.BackendsMissing > 0 ||
 		c.ExcludedForks > 0 ||
-		c.ExcludedArchived > 0)
+		c.ExcludedArchived > 0 ||
+		c.ReposScannedLimitHit ||
+		c.DurationLimitHit ||
+		c.ResultBytesLimitHit)
 }
 
 func (c *Stats) String() string {
@@ -100,6 +119,15 @@ func (c *Stats) String() string {
 	if c.IsLimitHit {
 		parts = append(parts, "limitHit")
 	}
+	if c.ReposScannedLimitHit {
+		parts = append(parts, "reposScannedLimitHit")
+	}
+	if c.DurationLimitHit {
+		parts = append(parts, "durationLimitHit")
+	}
+	if c.ResultBytesLimitHit {
+		parts = append(parts, "resultBytesLimitHit")
+	}
 
 	return "Stats{" + strings.Join(parts, " ") + "}"
 }
@@ -0,0 +1,140 @@
+package structural
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/conc/pool"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/comby"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// rewritePreviewConcurrency is the number of files rewritten concurrently for
+// each event sent by the child job.
+const rewritePreviewConcurrency = 8
+
+// NewRewritePreviewJob creates a job which attaches a diff previewing the
+// rewrite of every file matched by the structural search child job, so that a
+// large-scale rewrite can be reviewed before creating a batch change for it.
+func NewRewritePreviewJob(child job.Job, patternInfo *search.TextPatternInfo, rewriteTemplate string) job.Job {
+	return &rewritePreviewJob{
+		child:           child,
+		patternInfo:     patternInfo,
+		rewriteTemplate: rewriteTemplate,
+	}
+}
+
+type rewritePreviewJob struct {
+	child job.Job
+
+	patternInfo     *search.TextPatternInfo
+	rewriteTemplate string
+}
+
+func (j *rewritePreviewJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	var (
+		mu   sync.Mutex
+		errs error
+	)
+
+	previewStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		p := pool.New().WithMaxGoroutines(rewritePreviewConcurrency)
+		for _, res := range event.Results {
+			fm, ok := res.(*result.FileMatch)
+			if !ok {
+				continue
+			}
+			p.Go(func() {
+				diff, err := j.rewriteDiff(ctx, clients.Gitserver, fm)
+				if err != nil {
+					mu.Lock()
+					errs = errors.Append(errs, err)
+					mu.Unlock()
+					return
+				}
+				if diff != "" {
+					fm.RewriteDiff = &diff
+				}
+			})
+		}
+		p.Wait()
+
+		stream.Send(event)
+	})
+
+	alert, err = j.child.Run(ctx, clients, previewStream)
+	if err != nil {
+		errs = errors.Append(errs, err)
+	}
+	return alert, errs
+}
+
+// rewriteDiff returns a unified diff of the rewrite of the file, or an empty
+// string if the rewrite does not change it.
+func (j *rewritePreviewJob) rewriteDiff(ctx context.Context, client gitserver.Client, fm *result.FileMatch) (string, error) {
+	content, err := client.ReadFile(ctx, authz.DefaultSubRepoPermsChecker, fm.Repo.Name, fm.CommitID, fm.Path)
+	if err != nil {
+		return "", err
+	}
+
+	matcher := comby.ExtensionToMatcher(filepath.Ext(fm.Path))
+	if len(j.patternInfo.Languages) > 0 {
+		matcher = comby.LookupMatcher(j.patternInfo.Languages[0])
+	}
+
+	diffs, err := comby.Diffs(ctx, comby.Args{
+		Input:           comby.FileContent(content),
+		MatchTemplate:   j.patternInfo.Pattern,
+		Rule:            j.patternInfo.CombyRule,
+		RewriteTemplate: j.rewriteTemplate,
+		Matcher:         matcher,
+		NumWorkers:      0, // Just a single file's content.
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "rewriting %s in %s", fm.Path, fm.Repo.Name)
+	}
+	if len(diffs) == 0 {
+		return "", nil
+	}
+
+	// Comby does not know the path of content passed on stdin.
+	diff := strings.TrimPrefix(diffs[0].Diff, "--- /dev/null\n+++ /dev/null\n")
+	return "--- " + fm.Path + "\n+++ " + fm.Path + "\n" + diff, nil
+}
+
+func (j *rewritePreviewJob) Name() string {
+	return "StructuralRewritePreviewJob"
+}
+
+func (j *rewritePreviewJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
+	switch v {
+	case job.VerbosityMax:
+		fallthrough
+	case job.VerbosityBasic:
+		res = append(res, attribute.String("rewriteTemplate", j.rewriteTemplate))
+	}
+	return res
+}
+
+func (j *rewritePreviewJob) Children() []job.Describer {
+	return []job.Describer{j.child}
+}
+
+func (j *rewritePreviewJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.child = job.Map(j.child, fn)
+	return &cp
+}
@@ -461,6 +461,11 @@ type RepoOptions struct {
 	ArchivedSet  bool
 	NoArchived   bool
 	OnlyArchived bool
+
+	// PreserveRefGlobs indicates that ref globs like `repo:foo@*refs/heads/*`
+	// are resolved to the globs themselves instead of one revision per
+	// matching ref, for backends which expand them on their own.
+	PreserveRefGlobs bool
 }
 
 func (op *RepoOptions) Attributes() []attribute.KeyValue {
@@ -563,6 +568,9 @@ func (op *RepoOptions) Attributes() []attribute.KeyValue {
 	if op.OnlyArchived {
 		add(attribute.Bool("onlyArchived", op.OnlyArchived))
 	}
+	if op.PreserveRefGlobs {
+		add(attribute.Bool("preserveRefGlobs", op.PreserveRefGlobs))
+	}
 	return res
 }
 
@@ -653,6 +661,9 @@ func (op *RepoOptions) String() string {
 	if op.OnlyArchived {
 		fmt.Fprintf(&b, "OnlyArchived: %t\n", op.OnlyArchived)
 	}
+	if op.PreserveRefGlobs {
+		fmt.Fprintf(&b, "PreserveRefGlobs: %t\n", op.PreserveRefGlobs)
+	}
 
 	return b.String()
 }
@@ -303,6 +303,16 @@ func SetRequestErrorCause(ctx context.Context, err error) {
 	}
 }
 
+// RouteName returns the name of the route of the request with the given
+// context, once the request has been routed. It returns "unknown" if the route
+// is not known.
+func RouteName(ctx context.Context) string {
+	if p, ok := ctx.Value(routeNameKey).(*string); ok {
+		return *p
+	}
+	return "unknown"
+}
+
 // SetRouteName manually sets the name for the route. This should only be used
 // for non-mux routed routes (ie middlewares).
 func SetRouteName(r *http.Request, routeName string) {
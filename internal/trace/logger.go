@@ -4,12 +4,15 @@ import (
 	"context"
 
 	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/debugbundle"
 )
 
 // Logger will set the TraceContext on l if ctx has one. This is an expanded
-// convenience function around l.WithTrace for the common case.
-//
+// convenience function around l.WithTrace for the common case. Messages logged
+// through the returned logger are also recorded in the debug bundle being
+// captured for ctx, if any.
 func Logger(ctx context.Context, l log.Logger) log.Logger {
 	// Attach any trace (WithTrace no-ops if empty trace is provided)
-	return l.WithTrace(Context(ctx))
+	return debugbundle.Logger(ctx, l.WithTrace(Context(ctx)))
 }
@@ -34,6 +34,20 @@ func GetTracePolicy() TracePolicy {
 	return TracePolicy(trPolicy.Load())
 }
 
+var databaseQueryTracing = atomic.NewBool(false)
+
+// SetDatabaseQueryTracing toggles the creation of spans for queries issued through
+// database stores.
+func SetDatabaseQueryTracing(enabled bool) {
+	databaseQueryTracing.Store(enabled)
+}
+
+// DatabaseQueryTracing returns true if spans should be created for queries issued
+// through database stores.
+func DatabaseQueryTracing() bool {
+	return databaseQueryTracing.Load()
+}
+
 type key int
 
 const shouldTraceKey key = iota
@@ -63,6 +77,10 @@ func WithShouldTrace(ctx context.Context, shouldTrace bool) context.Context {
 const (
 	traceHeader = "X-Sourcegraph-Should-Trace"
 	traceQuery  = "trace"
+
+	// debugBundleHeader requests a debug bundle, which includes the trace of the request.
+	// See the debugbundle package.
+	debugBundleHeader = "X-Sourcegraph-Debug-Bundle"
 )
 
 // Transport wraps an underlying HTTP RoundTripper, injecting the X-Sourcegraph-Should-Trace header
@@ -77,8 +95,11 @@ func (r *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 }
 
 // requestWantsTrace returns true if a request is opting into tracing either
-// via our HTTP Header or our URL Query.
+// via our HTTP Header or our URL Query, or by requesting a debug bundle.
 func RequestWantsTracing(r *http.Request) bool {
+	if b, _ := strconv.ParseBool(r.Header.Get(debugBundleHeader)); b {
+		return true
+	}
 	// Prefer header over query param.
 	if v := r.Header.Get(traceHeader); v != "" {
 		b, _ := strconv.ParseBool(v)
@@ -0,0 +1,67 @@
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// carrierPropagator serializes the trace context and baggage stored alongside queued work.
+// This is deliberately independent of the globally configured propagator so that values
+// written by one service version can be read by another.
+var carrierPropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+)
+
+// WithBaggage returns a context carrying the given baggage member in addition to any
+// existing baggage. Baggage is propagated to downstream services alongside the trace
+// context.
+func WithBaggage(ctx context.Context, key, value string) (context.Context, error) {
+	member, err := baggage.NewMember(key, value)
+	if err != nil {
+		return ctx, err
+	}
+
+	b, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx, err
+	}
+
+	return baggage.ContextWithBaggage(ctx, b), nil
+}
+
+// Baggage returns the value of the baggage member with the given key, or an empty string
+// if no such member is carried by the given context.
+func Baggage(ctx context.Context, key string) string {
+	return baggage.FromContext(ctx).Member(key).Value()
+}
+
+// Inject returns a serialized form of the trace context and baggage of the given context.
+// The result can be stored with an enqueued record and later passed to Extract by the
+// process that dequeues it.
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	carrierPropagator.Inject(ctx, carrier)
+	return carrier
+}
+
+// Extract returns a context carrying the baggage from a carrier created by Inject. The
+// span that created the carrier is linked to the next span started from the returned
+// context rather than becoming its parent, as queued work is commonly processed long after
+// the enqueueing request has completed.
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	remote := carrierPropagator.Extract(context.Background(), propagation.MapCarrier(carrier))
+
+	if b := baggage.FromContext(remote); b.Len() > 0 {
+		ctx = baggage.ContextWithBaggage(ctx, b)
+	}
+
+	if spanContext := oteltrace.SpanContextFromContext(remote); spanContext.IsValid() {
+		ctx = WithLinks(ctx, oteltrace.Link{SpanContext: spanContext})
+	}
+
+	return ctx
+}
@@ -0,0 +1,57 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaggage(t *testing.T) {
+	ctx, err := WithBaggage(context.Background(), "actor", "42")
+	require.NoError(t, err)
+	ctx, err = WithBaggage(ctx, "feature", "ranking")
+	require.NoError(t, err)
+
+	require.Equal(t, "42", Baggage(ctx, "actor"))
+	require.Equal(t, "ranking", Baggage(ctx, "feature"))
+	require.Equal(t, "", Baggage(ctx, "missing"))
+
+	_, err = WithBaggage(context.Background(), "invalid key", "value")
+	require.Error(t, err)
+}
+
+func TestInjectExtract(t *testing.T) {
+	tracer, recorder := newTestTracer()
+
+	producer, ctx := tracer.New(context.Background(), "enqueue")
+	ctx, err := WithBaggage(ctx, "actor", "42")
+	require.NoError(t, err)
+	carrier := Inject(ctx)
+	producer.Finish()
+
+	require.Contains(t, carrier, "traceparent")
+	require.Contains(t, carrier, "baggage")
+
+	ctx = Extract(context.Background(), carrier)
+	require.Equal(t, "42", Baggage(ctx, "actor"))
+	require.Empty(t, ID(ctx), "the remote span should not become the parent")
+
+	consumer, _ := tracer.New(ctx, "process")
+	consumer.Finish()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.NotEqual(t, spans[0].SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+
+	links := spans[1].Links()
+	require.Len(t, links, 1)
+	require.Equal(t, spans[0].SpanContext().SpanID(), links[0].SpanContext.SpanID())
+	require.Equal(t, spans[0].SpanContext().TraceID(), links[0].SpanContext.TraceID())
+}
+
+func TestExtractEmptyCarrier(t *testing.T) {
+	ctx := Extract(context.Background(), nil)
+	require.Equal(t, "", Baggage(ctx, "actor"))
+	require.Equal(t, startOptions{}, startOptionsFromContext(ctx))
+}
@@ -0,0 +1,67 @@
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Span kinds describe the relationship between a span and its remote parent or children.
+// See https://opentelemetry.io/docs/specs/otel/trace/api/#spankind.
+const (
+	SpanKindInternal = oteltrace.SpanKindInternal
+	SpanKindServer   = oteltrace.SpanKindServer
+	SpanKindClient   = oteltrace.SpanKindClient
+	SpanKindProducer = oteltrace.SpanKindProducer
+	SpanKindConsumer = oteltrace.SpanKindConsumer
+)
+
+const startOptionsKey = traceContextKey("startOptions")
+
+// startOptions are applied to the next span started from a context.
+type startOptions struct {
+	kind  oteltrace.SpanKind
+	links []oteltrace.Link
+}
+
+func startOptionsFromContext(ctx context.Context) startOptions {
+	opts, _ := ctx.Value(startOptionsKey).(startOptions)
+	return opts
+}
+
+// WithSpanKind returns a context that causes the next span started from it (e.g. by New
+// or by an observation operation) to have the given kind. Spans started further down the
+// call tree are unaffected.
+func WithSpanKind(ctx context.Context, kind oteltrace.SpanKind) context.Context {
+	opts := startOptionsFromContext(ctx)
+	opts.kind = kind
+	return context.WithValue(ctx, startOptionsKey, opts)
+}
+
+// WithLinks returns a context that causes the next span started from it to be linked to
+// the given spans. Links relate a span to spans that are causally related but are not its
+// parent, such as the span that enqueued the job a worker is processing. Spans started
+// further down the call tree are unaffected.
+func WithLinks(ctx context.Context, links ...oteltrace.Link) context.Context {
+	if len(links) == 0 {
+		return ctx
+	}
+
+	opts := startOptionsFromContext(ctx)
+	opts.links = append(append([]oteltrace.Link(nil), opts.links...), links...)
+	return context.WithValue(ctx, startOptionsKey, opts)
+}
+
+// Link returns a link to this span that can be supplied to WithLinks. A nil Trace returns
+// a link with an invalid span context, which is ignored by the SDK.
+func (t *Trace) Link(attrs ...attribute.KeyValue) oteltrace.Link {
+	if t == nil {
+		return oteltrace.Link{}
+	}
+
+	return oteltrace.Link{
+		SpanContext: t.oteltraceSpan.SpanContext(),
+		Attributes:  attrs,
+	}
+}
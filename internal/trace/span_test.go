@@ -0,0 +1,70 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	oteltracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func newTestTracer() (Tracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := oteltracesdk.NewTracerProvider(oteltracesdk.WithSpanProcessor(recorder))
+	return Tracer{TracerProvider: provider}, recorder
+}
+
+func TestWithSpanKind(t *testing.T) {
+	tracer, recorder := newTestTracer()
+
+	ctx := WithSpanKind(context.Background(), SpanKindClient)
+	parent, ctx := tracer.New(ctx, "parent")
+	child, _ := tracer.New(ctx, "child")
+	child.Finish()
+	parent.Finish()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, "child", spans[0].Name())
+	require.Equal(t, oteltrace.SpanKindInternal, spans[0].SpanKind(), "start options should not apply to descendants")
+	require.Equal(t, "parent", spans[1].Name())
+	require.Equal(t, oteltrace.SpanKindClient, spans[1].SpanKind())
+}
+
+func TestWithLinks(t *testing.T) {
+	tracer, recorder := newTestTracer()
+
+	producer, _ := tracer.New(context.Background(), "enqueue")
+	producer.Finish()
+
+	ctx := WithSpanKind(context.Background(), SpanKindConsumer)
+	ctx = WithLinks(ctx, producer.Link(attribute.String("queue", "test")))
+	consumer, ctx := tracer.New(ctx, "process")
+	child, _ := tracer.New(ctx, "child")
+	child.Finish()
+	consumer.Finish()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	links := spans[2].Links()
+	require.Len(t, links, 1)
+	require.Equal(t, producer.oteltraceSpan.SpanContext().SpanID(), links[0].SpanContext.SpanID())
+	require.Equal(t, []attribute.KeyValue{attribute.String("queue", "test")}, links[0].Attributes)
+	require.Equal(t, oteltrace.SpanKindConsumer, spans[2].SpanKind(), "links should not reset the span kind")
+
+	require.Empty(t, spans[1].Links(), "start options should not apply to descendants")
+}
+
+func TestWithLinksNoLinks(t *testing.T) {
+	ctx := context.Background()
+	require.Equal(t, ctx, WithLinks(ctx))
+}
+
+func TestTraceLinkNil(t *testing.T) {
+	var tr *Trace
+	require.False(t, tr.Link().SpanContext.IsValid())
+}
@@ -21,10 +21,18 @@ func (t Tracer) New(ctx context.Context, name string, attrs ...attribute.KeyValu
 		t.TracerProvider = otel.GetTracerProvider()
 	}
 
+	spanOpts := []oteltrace.SpanStartOption{oteltrace.WithAttributes(attrs...)}
+	if opts, ok := ctx.Value(startOptionsKey).(startOptions); ok {
+		spanOpts = append(spanOpts, oteltrace.WithSpanKind(opts.kind), oteltrace.WithLinks(opts.links...))
+
+		// Start options apply only to the next span, not to its descendants
+		ctx = context.WithValue(ctx, startOptionsKey, nil)
+	}
+
 	var otelSpan oteltrace.Span
 	ctx, otelSpan = t.TracerProvider.
 		Tracer("sourcegraph/internal/trace").
-		Start(ctx, name, oteltrace.WithAttributes(attrs...))
+		Start(ctx, name, spanOpts...)
 
 	trace := &Trace{oteltraceSpan: otelSpan}
 	return trace, contextWithTrace(ctx, trace)
@@ -59,9 +59,17 @@ func newOtelSpanProcessor(logger log.Logger, opts options, debug bool) (oteltrac
 
 	// If in debug mode, we use a synchronous span processor to force spans to get pushed
 	// immediately, otherwise we batch
+	var processor oteltracesdk.SpanProcessor
 	if debug {
 		logger.Warn("using synchronous span processor - disable 'observability.debug' to use something more suitable for production")
-		return oteltracesdk.NewSimpleSpanProcessor(exporter), nil
+		processor = oteltracesdk.NewSimpleSpanProcessor(exporter)
+	} else {
+		processor = oteltracesdk.NewBatchSpanProcessor(exporter)
 	}
-	return oteltracesdk.NewBatchSpanProcessor(exporter), nil
+
+	// Decide which traces to export once they have ended
+	if opts.tailSampling.Enabled {
+		return newTailSamplingProcessor(processor, opts.tailSampling), nil
+	}
+	return processor, nil
 }
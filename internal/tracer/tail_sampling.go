@@ -0,0 +1,243 @@
+package tracer
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/codes"
+	oteltracesdk "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const (
+	defaultTailSamplingLatencyThreshold = time.Second
+	defaultTailSamplingSampleRate       = 0.01
+
+	// maxBufferedTraces is the maximum number of traces held in memory while waiting for
+	// their local root span to end. Spans of traces beyond this limit are dropped.
+	maxBufferedTraces = 10000
+
+	// maxBufferedSpansPerTrace is the maximum number of spans held in memory for a single
+	// trace. Additional spans of the trace are dropped.
+	maxBufferedSpansPerTrace = 1000
+
+	// maxBufferedTraceAge is the time after which a buffered trace is decided upon even
+	// though its local root span has not ended, e.g. because it was started by a goroutine
+	// that outlives the root span.
+	maxBufferedTraceAge = time.Minute
+
+	// expireInterval is the minimum time between scans for buffered traces that exceeded
+	// maxBufferedTraceAge.
+	expireInterval = 10 * time.Second
+)
+
+var tailSamplingDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_tracing_tail_sampling_traces_total",
+	Help: "Total number of traces decided upon by tail-based sampling, by decision.",
+}, []string{"decision"})
+
+var tailSamplingDroppedSpans = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "src_tracing_tail_sampling_overflow_spans_total",
+	Help: "Total number of spans dropped because the tail-based sampling buffer was full.",
+})
+
+// tailSamplingPolicy determines which traces are exported by a tail sampling processor.
+// It must remain comparable, as it is part of options.
+type tailSamplingPolicy struct {
+	Enabled          bool
+	LatencyThreshold time.Duration
+	SampleRate       float64
+}
+
+// newTailSamplingPolicy returns the tail sampling policy of the given service, applying
+// its overrides, if any, to the global policy.
+func newTailSamplingPolicy(c *schema.TailSampling, service string) tailSamplingPolicy {
+	if c == nil {
+		return tailSamplingPolicy{}
+	}
+
+	p := tailSamplingPolicy{
+		Enabled:          c.Enabled,
+		LatencyThreshold: defaultTailSamplingLatencyThreshold,
+		SampleRate:       defaultTailSamplingSampleRate,
+	}
+	if c.LatencyThresholdMs != nil {
+		p.LatencyThreshold = time.Duration(*c.LatencyThresholdMs) * time.Millisecond
+	}
+	if c.SampleRate != nil {
+		p.SampleRate = *c.SampleRate
+	}
+
+	if override, ok := c.Services[service]; ok {
+		if override.Enabled != nil {
+			p.Enabled = *override.Enabled
+		}
+		if override.LatencyThresholdMs != nil {
+			p.LatencyThreshold = time.Duration(*override.LatencyThresholdMs) * time.Millisecond
+		}
+		if override.SampleRate != nil {
+			p.SampleRate = *override.SampleRate
+		}
+	}
+
+	if !p.Enabled {
+		return tailSamplingPolicy{}
+	}
+	return p
+}
+
+// tailSamplingProcessor buffers ended spans by trace until the local root span of the trace
+// ends, then forwards all spans of the trace to the wrapped processor if the trace contains
+// an error, lasted at least the latency threshold, or is selected at random.
+//
+// Each service decides independently, so a trace spanning several services may be exported
+// only partially. Error and slow spans are retained in every service regardless.
+type tailSamplingProcessor struct {
+	next   oteltracesdk.SpanProcessor
+	policy tailSamplingPolicy
+	sample func() float64
+	now    func() time.Time
+
+	mu          sync.Mutex
+	traces      map[oteltrace.TraceID]*bufferedTrace
+	lastExpired time.Time
+}
+
+type bufferedTrace struct {
+	spans    []oteltracesdk.ReadOnlySpan
+	hasError bool
+	start    time.Time
+	end      time.Time
+	buffered time.Time
+}
+
+var _ oteltracesdk.SpanProcessor = &tailSamplingProcessor{}
+
+func newTailSamplingProcessor(next oteltracesdk.SpanProcessor, policy tailSamplingPolicy) *tailSamplingProcessor {
+	return &tailSamplingProcessor{
+		next:   next,
+		policy: policy,
+		sample: rand.Float64,
+		now:    time.Now,
+		traces: map[oteltrace.TraceID]*bufferedTrace{},
+	}
+}
+
+func (p *tailSamplingProcessor) OnStart(parent context.Context, s oteltracesdk.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *tailSamplingProcessor) OnEnd(s oteltracesdk.ReadOnlySpan) {
+	var decided []*bufferedTrace
+	defer func() {
+		for _, t := range decided {
+			p.export(t)
+		}
+	}()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	traceID := s.SpanContext().TraceID()
+	t, ok := p.traces[traceID]
+	if !ok {
+		decided = p.expireLocked()
+		if len(p.traces) >= maxBufferedTraces {
+			tailSamplingDroppedSpans.Inc()
+			return
+		}
+
+		t = &bufferedTrace{start: s.StartTime(), end: s.EndTime(), buffered: p.now()}
+		p.traces[traceID] = t
+	}
+
+	if len(t.spans) < maxBufferedSpansPerTrace {
+		t.spans = append(t.spans, s)
+	} else {
+		tailSamplingDroppedSpans.Inc()
+	}
+	if s.Status().Code == codes.Error {
+		t.hasError = true
+	}
+	if s.StartTime().Before(t.start) {
+		t.start = s.StartTime()
+	}
+	if s.EndTime().After(t.end) {
+		t.end = s.EndTime()
+	}
+
+	// The local root span is the last span of the trace to end in this service
+	if parent := s.Parent(); !parent.IsValid() || parent.IsRemote() {
+		delete(p.traces, traceID)
+		decided = append(decided, t)
+	}
+}
+
+// expireLocked removes and returns the buffered traces that exceeded maxBufferedTraceAge,
+// scanning at most once per expireInterval. The caller must hold p.mu.
+func (p *tailSamplingProcessor) expireLocked() (expired []*bufferedTrace) {
+	now := p.now()
+	if now.Sub(p.lastExpired) < expireInterval {
+		return nil
+	}
+	p.lastExpired = now
+
+	cutoff := now.Add(-maxBufferedTraceAge)
+	for traceID, t := range p.traces {
+		if t.buffered.Before(cutoff) {
+			delete(p.traces, traceID)
+			expired = append(expired, t)
+		}
+	}
+	return expired
+}
+
+// export forwards the spans of the given trace to the wrapped processor if the trace is
+// retained by the sampling policy.
+func (p *tailSamplingProcessor) export(t *bufferedTrace) {
+	decision := p.decide(t)
+	tailSamplingDecisions.WithLabelValues(decision).Inc()
+	if decision == "dropped" {
+		return
+	}
+
+	for _, s := range t.spans {
+		p.next.OnEnd(s)
+	}
+}
+
+func (p *tailSamplingProcessor) decide(t *bufferedTrace) string {
+	if t.hasError {
+		return "error"
+	}
+	if t.end.Sub(t.start) >= p.policy.LatencyThreshold {
+		return "latency"
+	}
+	if p.sample() < p.policy.SampleRate {
+		return "sampled"
+	}
+	return "dropped"
+}
+
+// Shutdown decides upon all buffered traces before shutting down the wrapped processor.
+func (p *tailSamplingProcessor) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	traces := p.traces
+	p.traces = map[oteltrace.TraceID]*bufferedTrace{}
+	p.mu.Unlock()
+
+	for _, t := range traces {
+		p.export(t)
+	}
+	return p.next.Shutdown(ctx)
+}
+
+func (p *tailSamplingProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
@@ -0,0 +1,151 @@
+package tracer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	oteltracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestNewTailSamplingPolicy(t *testing.T) {
+	c := &schema.TailSampling{
+		Enabled:    true,
+		SampleRate: pointers.Ptr(0.1),
+		Services: map[string]schema.TailSamplingOverride{
+			"gitserver": {LatencyThresholdMs: pointers.Ptr(5000)},
+			"searcher":  {Enabled: pointers.Ptr(false)},
+		},
+	}
+
+	assert.Equal(t, tailSamplingPolicy{}, newTailSamplingPolicy(nil, "frontend"))
+	assert.Equal(t, tailSamplingPolicy{}, newTailSamplingPolicy(&schema.TailSampling{}, "frontend"))
+	assert.Equal(t, tailSamplingPolicy{}, newTailSamplingPolicy(c, "searcher"))
+	assert.Equal(t, tailSamplingPolicy{
+		Enabled:          true,
+		LatencyThreshold: defaultTailSamplingLatencyThreshold,
+		SampleRate:       0.1,
+	}, newTailSamplingPolicy(c, "frontend"))
+	assert.Equal(t, tailSamplingPolicy{
+		Enabled:          true,
+		LatencyThreshold: 5 * time.Second,
+		SampleRate:       0.1,
+	}, newTailSamplingPolicy(c, "gitserver"))
+
+	// Services may enable tail sampling on their own
+	assert.Equal(t, tailSamplingPolicy{
+		Enabled:          true,
+		LatencyThreshold: defaultTailSamplingLatencyThreshold,
+		SampleRate:       defaultTailSamplingSampleRate,
+	}, newTailSamplingPolicy(&schema.TailSampling{
+		Services: map[string]schema.TailSamplingOverride{"frontend": {Enabled: pointers.Ptr(true)}},
+	}, "frontend"))
+}
+
+func TestTailSamplingProcessor(t *testing.T) {
+	ctx := context.Background()
+	start := time.Unix(1587396557, 0)
+
+	setup := func(sample float64) (*tailSamplingProcessor, *tracetest.SpanRecorder, oteltrace.Tracer) {
+		recorder := tracetest.NewSpanRecorder()
+		processor := newTailSamplingProcessor(recorder, tailSamplingPolicy{
+			Enabled:          true,
+			LatencyThreshold: time.Second,
+			SampleRate:       0.5,
+		})
+		processor.sample = func() float64 { return sample }
+		provider := oteltracesdk.NewTracerProvider(oteltracesdk.WithSpanProcessor(processor))
+		return processor, recorder, provider.Tracer(t.Name())
+	}
+
+	// startTrace starts a root span with a single child span.
+	startTrace := func(ctx context.Context, tracer oteltrace.Tracer) (oteltrace.Span, oteltrace.Span) {
+		ctx, root := tracer.Start(ctx, "root", oteltrace.WithTimestamp(start))
+		_, child := tracer.Start(ctx, "child", oteltrace.WithTimestamp(start))
+		return root, child
+	}
+	endTrace := func(root, child oteltrace.Span, duration time.Duration) {
+		child.End(oteltrace.WithTimestamp(start.Add(duration / 2)))
+		root.End(oteltrace.WithTimestamp(start.Add(duration)))
+	}
+
+	t.Run("dropped", func(t *testing.T) {
+		_, recorder, tracer := setup(0.9)
+		root, child := startTrace(ctx, tracer)
+		endTrace(root, child, 10*time.Millisecond)
+		assert.Empty(t, recorder.Ended())
+	})
+
+	t.Run("sampled", func(t *testing.T) {
+		_, recorder, tracer := setup(0.1)
+		root, child := startTrace(ctx, tracer)
+		endTrace(root, child, 10*time.Millisecond)
+		assert.Len(t, recorder.Ended(), 2)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, recorder, tracer := setup(0.9)
+		root, child := startTrace(ctx, tracer)
+		child.SetStatus(codes.Error, "boom")
+		endTrace(root, child, 10*time.Millisecond)
+		assert.Len(t, recorder.Ended(), 2)
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		_, recorder, tracer := setup(0.9)
+		root, child := startTrace(ctx, tracer)
+		endTrace(root, child, 2*time.Second)
+		assert.Len(t, recorder.Ended(), 2)
+	})
+
+	t.Run("buffered until local root ends", func(t *testing.T) {
+		_, recorder, tracer := setup(0.1)
+		remoteCtx := oteltrace.ContextWithRemoteSpanContext(ctx, oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+			TraceID:    oteltrace.TraceID{0x01},
+			SpanID:     oteltrace.SpanID{0x01},
+			TraceFlags: oteltrace.FlagsSampled,
+			Remote:     true,
+		}))
+
+		root, child := startTrace(remoteCtx, tracer)
+		child.End()
+		assert.Empty(t, recorder.Ended())
+		root.End()
+		assert.Len(t, recorder.Ended(), 2)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		processor, recorder, tracer := setup(0.9)
+		now := start
+		processor.now = func() time.Time { return now }
+
+		// The root span of the first trace never ends
+		_, child := startTrace(ctx, tracer)
+		child.SetStatus(codes.Error, "boom")
+		child.End(oteltrace.WithTimestamp(start))
+		assert.Empty(t, recorder.Ended())
+
+		now = start.Add(maxBufferedTraceAge + time.Second)
+		root, child := startTrace(ctx, tracer)
+		endTrace(root, child, 10*time.Millisecond)
+		assert.Len(t, recorder.Ended(), 1)
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+		processor, recorder, tracer := setup(0.9)
+		_, child := startTrace(ctx, tracer)
+		child.SetStatus(codes.Error, "boom")
+		child.End()
+		assert.Empty(t, recorder.Ended())
+
+		assert.NoError(t, processor.Shutdown(ctx))
+		assert.Len(t, recorder.Ended(), 1)
+	})
+}
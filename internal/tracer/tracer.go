@@ -24,6 +24,8 @@ import (
 type options struct {
 	TracerType
 	externalURL string
+	// tailSampling wraps the span processor in tail-based sampling, if enabled.
+	tailSampling tailSamplingPolicy
 	// these values are not configurable by site config
 	resource log.Resource
 }
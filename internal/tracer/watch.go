@@ -6,6 +6,7 @@ import (
 	"github.com/sourcegraph/log"
 	oteltracesdk "go.opentelemetry.io/otel/sdk/trace"
 
+	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/trace/policy"
 )
 
@@ -39,6 +40,7 @@ func newConfWatcher(
 			tracingConfig  = siteConfig.ObservabilityTracing
 			previousPolicy = policy.GetTracePolicy()
 			setTracerType  = None
+			tailSampling   tailSamplingPolicy
 			debugChanged   bool
 		)
 
@@ -71,16 +73,27 @@ func newConfWatcher(
 				setTracerType = DefaultTracerType
 			}
 
+			// Tail-based sampling only applies when every request is traced, so that
+			// selectively requested traces are always exported
+			if newPolicy == policy.TraceAll {
+				tailSampling = newTailSamplingPolicy(tracingConfig.TailSampling, env.MyName)
+			}
+
 			// Configure debug mode
 			debugChanged = debugMode.CompareAndSwap(debugMode.Load(), tracingConfig.Debug)
+
+			// Configure spans for database queries
+			policy.SetDatabaseQueryTracing(tracingConfig.DatabaseQueries)
 		} else {
 			debugChanged = debugMode.CompareAndSwap(debugMode.Load(), false)
+			policy.SetDatabaseQueryTracing(false)
 		}
 
 		// collect options
 		opts := options{
-			TracerType:  setTracerType,
-			externalURL: siteConfig.ExternalURL,
+			TracerType:   setTracerType,
+			externalURL:  siteConfig.ExternalURL,
+			tailSampling: tailSampling,
 			// Stays the same
 			resource: oldOpts.resource,
 		}
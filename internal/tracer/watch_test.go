@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/log"
 	"github.com/sourcegraph/log/logtest"
@@ -12,6 +13,7 @@ import (
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/sourcegraph/sourcegraph/internal/trace/policy"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -62,6 +64,7 @@ func TestConfigWatcher(t *testing.T) {
 		assert.True(t, updated)
 		// should set global policy
 		assert.Equal(t, policy.TraceNone, policy.GetTracePolicy())
+		assert.False(t, policy.DatabaseQueryTracing())
 	})
 
 	t.Run("enable tracing with 'observability.tracing: {}'", func(t *testing.T) {
@@ -146,8 +149,9 @@ func TestConfigWatcher(t *testing.T) {
 			get: func() Configuration {
 				return Configuration{
 					ObservabilityTracing: &schema.ObservabilityTracing{
-						Debug:    true,
-						Sampling: "all",
+						Debug:           true,
+						Sampling:        "all",
+						DatabaseQueries: true,
 					},
 				}
 			},
@@ -181,6 +185,7 @@ func TestConfigWatcher(t *testing.T) {
 
 		// should set global policy
 		assert.Equal(t, policy.TraceAll, policy.GetTracePolicy())
+		assert.True(t, policy.DatabaseQueryTracing())
 
 		t.Run("sanity check - swap existing processor with another", func(t *testing.T) {
 			spansRecorder2 := tracetest.NewSpanRecorder()
@@ -210,4 +215,42 @@ func TestConfigWatcher(t *testing.T) {
 			assert.Len(t, spansRecorder1.Ended(), spanCount1)
 		})
 	})
+
+	t.Run("tail sampling applies only with sampling all", func(t *testing.T) {
+		tracingConfig := &schema.ObservabilityTracing{
+			Sampling: "selective",
+			TailSampling: &schema.TailSampling{
+				Enabled:            true,
+				LatencyThresholdMs: pointers.Ptr(2000),
+			},
+		}
+		mockConf := &mockConfig{
+			get: func() Configuration {
+				return Configuration{ObservabilityTracing: tracingConfig}
+			},
+		}
+
+		var lastOpts options
+		doUpdate := newConfWatcher(
+			logger,
+			mockConf,
+			provider,
+			func(logger log.Logger, opts options, debug bool) (oteltracesdk.SpanProcessor, error) {
+				lastOpts = opts
+				return noopProcessor, nil
+			},
+			debugMode,
+		)
+
+		doUpdate()
+		assert.False(t, lastOpts.tailSampling.Enabled)
+
+		tracingConfig.Sampling = "all"
+		doUpdate()
+		assert.Equal(t, tailSamplingPolicy{
+			Enabled:          true,
+			LatencyThreshold: 2 * time.Second,
+			SampleRate:       defaultTailSamplingSampleRate,
+		}, lastOpts.tailSampling)
+	})
 }
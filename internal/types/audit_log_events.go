@@ -0,0 +1,38 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Actions recorded in the audit log.
+const (
+	AuditLogActionOrgMemberAdded   = "org_member:added"
+	AuditLogActionOrgMemberRemoved = "org_member:removed"
+
+	AuditLogActionExternalServiceCreated = "external_service:created"
+	AuditLogActionExternalServiceUpdated = "external_service:updated"
+	AuditLogActionExternalServiceDeleted = "external_service:deleted"
+
+	AuditLogActionRepoPermissionsSet          = "repo_permissions:set"
+	AuditLogActionRepoPermissionsUnrestricted = "repo_permissions:unrestricted"
+
+	AuditLogActionAccessTokenCreated = "access_token:created"
+)
+
+// AuditLogEvent is a change recorded in the audit log, together with the actor
+// that made it.
+type AuditLogEvent struct {
+	ID         int64           `json:"id"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityID   string          `json:"entityID"`
+	OrgID      *int32          `json:"orgID,omitempty"`
+	Argument   json.RawMessage `json:"argument"`
+	CreatedAt  time.Time       `json:"createdAt"`
+
+	// ActorUserID is nil for changes made by internal or anonymous actors.
+	ActorUserID       *int32 `json:"actorUserID,omitempty"`
+	ActorIP           string `json:"actorIP,omitempty"`
+	ActorForwardedFor string `json:"actorForwardedFor,omitempty"`
+}
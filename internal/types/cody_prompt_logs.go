@@ -0,0 +1,40 @@
+package types
+
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+)
+
+// CodyPromptLog is the record of the prompt and response of a Cody completions
+// request.
+type CodyPromptLog struct {
+	ID int64
+	// UserID is the ID of the user who made the request, or 0 for anonymous users
+	// and deleted users.
+	UserID          int32
+	AnonymousUserID string
+	Intent          string
+	Provider        string
+	Model           string
+	Prompt          *EncryptableCodyPrompt
+	Response        *encryption.Encryptable
+	CreatedAt       time.Time
+}
+
+// CodyPromptMessage is a message of the prompt of a Cody completions request.
+type CodyPromptMessage struct {
+	Speaker string `json:"speaker"`
+	Text    string `json:"text"`
+}
+
+type EncryptableCodyPrompt = encryption.JSONEncryptable[[]CodyPromptMessage]
+
+func NewUnencryptedCodyPrompt(messages []CodyPromptMessage) *EncryptableCodyPrompt {
+	prompt, _ := encryption.NewUnencryptedJSON(messages)
+	return prompt
+}
+
+func NewEncryptedCodyPrompt(cipher, keyID string, key encryption.Key) *EncryptableCodyPrompt {
+	return encryption.NewEncryptedJSON[[]CodyPromptMessage](cipher, keyID, key)
+}
@@ -0,0 +1,32 @@
+package types
+
+import "time"
+
+// NotificationKind is the kind of event a notification is about.
+type NotificationKind string
+
+const (
+	NotificationKindCodeMonitor    NotificationKind = "CODE_MONITOR"
+	NotificationKindBatchChange    NotificationKind = "BATCH_CHANGE"
+	NotificationKindPermissionSync NotificationKind = "PERMISSION_SYNC"
+	NotificationKindEmbeddingJob   NotificationKind = "EMBEDDING_JOB"
+)
+
+// Notification is a notification of a user.
+type Notification struct {
+	ID     int64
+	UserID int32
+	Kind   NotificationKind
+	Title  string
+	Body   string
+	// URL is the URL of the subject of the notification, relative to the external
+	// URL.
+	URL string
+	// InApp is whether the notification is shown in the notifications inbox.
+	InApp bool
+	// DigestPending is whether the notification is yet to be sent in an email
+	// digest.
+	DigestPending bool
+	ReadAt        *time.Time
+	CreatedAt     time.Time
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event types published to the transactional outbox.
+const (
+	OutboxEventAccessTokenUsageSpike = "access_token:usage_spike"
+	OutboxEventAuditLog              = "audit_log:event"
+	OutboxEventRepoCreated           = "repo:created"
+	OutboxEventUploadProcessed       = "upload:processed"
+	OutboxEventUserCreated           = "user:created"
+	OutboxEventUserDeactivated       = "user:deactivated"
+)
+
+// OutboxEvent is an event recorded in the transactional outbox in the same
+// transaction as the change it describes.
+type OutboxEvent struct {
+	ID int64
+	// TxID is the ID of the transaction that published the event.
+	TxID int64
+
+	EventType     string
+	AggregateType string
+	AggregateID   string
+	Payload       json.RawMessage
+	CreatedAt     time.Time
+}
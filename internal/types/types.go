@@ -1926,6 +1926,20 @@ type SearchContext struct {
 	// e.g. repo:^github\.com/org rev:bar archive:no f:sub/dir
 	Query string
 
+	// RepositoryQueries are repository queries (e.g. repo:^github\.com/org archived:no) whose matching
+	// repositories are part of the search context. They are re-resolved periodically.
+	RepositoryQueries []string
+
+	// IncludedSearchContextIDs are the IDs of the search contexts whose repositories are part of this search context.
+	IncludedSearchContextIDs []int64
+
+	// Version is incremented whenever the definition of the search context, or of a search context it includes, changes.
+	Version int32
+
+	// MaterializedVersion is the version of the search context its materialized repositories were resolved for.
+	// Only search contexts with included search contexts or repository queries are materialized.
+	MaterializedVersion int32
+
 	// Whether the search context is auto-defined by Sourcegraph. Auto-defined search contexts are not editable by users.
 	AutoDefined bool
 
@@ -2045,6 +2059,14 @@ type AccessRequest struct {
 	AdditionalInfo   string
 	Status           AccessRequestStatus
 	DecisionByUserID *int32
+
+	// Username, EmailVerified and ExternalAccount are set for requests made when
+	// signing in with an auth provider that does not allow signup.
+	Username        string
+	EmailVerified   bool
+	ExternalAccount *extsvc.AccountSpec
+	// UserID is the account created when the request was approved.
+	UserID *int32
 }
 
 const (
@@ -0,0 +1,457 @@
+package uploadstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// azureAPI is the subset of the Azure Blob Storage REST API used by azureStore.
+type azureAPI interface {
+	CreateContainer(ctx context.Context, container string) error
+	GetBlob(ctx context.Context, container, blob string) (io.ReadCloser, error)
+	PutBlock(ctx context.Context, container, blob, blockID string, content []byte) error
+	PutBlockList(ctx context.Context, container, blob string, blockIDs []string) error
+	DeleteBlob(ctx context.Context, container, blob string) error
+	ListBlobs(ctx context.Context, container, prefix, marker string) (*azureBlobList, error)
+	SignedURL(container, blob string, expiry time.Time) (string, error)
+}
+
+// azureBlobList is a page of the List Blobs operation.
+type azureBlobList struct {
+	Blobs      []azureBlob `xml:"Blobs>Blob"`
+	NextMarker string      `xml:"NextMarker"`
+}
+
+type azureBlob struct {
+	Name       string `xml:"Name"`
+	Properties struct {
+		CreationTime  azureTime `xml:"Creation-Time"`
+		ContentLength int64     `xml:"Content-Length"`
+	} `xml:"Properties"`
+}
+
+// azureTime is a timestamp in the RFC 1123 format used by the Blob service.
+type azureTime struct{ time.Time }
+
+func (t *azureTime) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	parsed, err := time.Parse(time.RFC1123, s)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// azureError is an error response of the Blob service.
+type azureError struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *azureError) Error() string {
+	return fmt.Sprintf("azure blob storage: %d %s: %s", e.StatusCode, e.Code, strings.TrimSpace(e.Message))
+}
+
+func (e *azureError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+const (
+	// azureAPIVersion is the version of the Blob service REST API we speak.
+	azureAPIVersion = "2021-08-06"
+
+	// azureStorageResource is the OAuth resource of Azure Storage, requested
+	// when authenticating with a managed identity.
+	azureStorageResource = "https://storage.azure.com/"
+
+	// azureIMDSEndpoint is the token endpoint of the Azure instance metadata service.
+	azureIMDSEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+)
+
+// azureRESTClient talks to the Blob service REST API. Requests are authorized
+// with the account key (Shared Key), a SAS token or a token of the managed
+// identity of the VM or pod, in that order of preference.
+//
+// We sign requests ourselves instead of depending on azblob and azidentity,
+// which would pull newer versions of shared dependencies into the module for
+// a handful of operations. The signatures are tested against the ones the SDK
+// computes for the same requests.
+type azureRESTClient struct {
+	endpoint string
+	account  string
+	key      []byte
+	sasToken url.Values
+	doer     httpcli.Doer
+
+	// imdsEndpoint is the endpoint managed identity tokens are requested from,
+	// or empty when not using a managed identity.
+	imdsEndpoint string
+	tokenMu      sync.Mutex
+	token        string
+	tokenExpiry  time.Time
+}
+
+var _ azureAPI = &azureRESTClient{}
+
+func newAzureRESTClient(config AzureConfig, doer httpcli.Doer) (*azureRESTClient, error) {
+	if config.AccountName == "" {
+		return nil, errors.New("azure blob storage: account name is required")
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", config.AccountName)
+	}
+
+	client := &azureRESTClient{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		account:  config.AccountName,
+		doer:     doer,
+	}
+
+	switch {
+	case config.AccountKey != "":
+		key, err := base64.StdEncoding.DecodeString(config.AccountKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "azure blob storage: account key is not valid base64")
+		}
+		client.key = key
+	case config.SASToken != "":
+		token, err := url.ParseQuery(strings.TrimPrefix(config.SASToken, "?"))
+		if err != nil {
+			return nil, errors.Wrap(err, "azure blob storage: invalid SAS token")
+		}
+		client.sasToken = token
+	case config.UseManagedIdentity:
+		client.imdsEndpoint = azureIMDSEndpoint
+	default:
+		return nil, errors.New("azure blob storage: one of account key, SAS token or managed identity is required")
+	}
+
+	return client, nil
+}
+
+func (c *azureRESTClient) CreateContainer(ctx context.Context, container string) error {
+	resp, err := c.do(ctx, http.MethodPut, container, "", url.Values{"restype": {"container"}}, nil, nil)
+	if err != nil {
+		var azErr *azureError
+		if errors.As(err, &azErr) && azErr.Code == "ContainerAlreadyExists" {
+			return nil
+		}
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c *azureRESTClient) GetBlob(ctx context.Context, container, blob string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, container, blob, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (c *azureRESTClient) PutBlock(ctx context.Context, container, blob, blockID string, content []byte) error {
+	query := url.Values{"comp": {"block"}, "blockid": {blockID}}
+	resp, err := c.do(ctx, http.MethodPut, container, blob, query, nil, content)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c *azureRESTClient) PutBlockList(ctx context.Context, container, blob string, blockIDs []string) error {
+	var body bytes.Buffer
+	body.WriteString(xml.Header)
+	body.WriteString("<BlockList>")
+	for _, id := range blockIDs {
+		body.WriteString("<Latest>")
+		if err := xml.EscapeText(&body, []byte(id)); err != nil {
+			return err
+		}
+		body.WriteString("</Latest>")
+	}
+	body.WriteString("</BlockList>")
+
+	header := http.Header{"Content-Type": {"application/xml"}}
+	resp, err := c.do(ctx, http.MethodPut, container, blob, url.Values{"comp": {"blocklist"}}, header, body.Bytes())
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c *azureRESTClient) DeleteBlob(ctx context.Context, container, blob string) error {
+	resp, err := c.do(ctx, http.MethodDelete, container, blob, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c *azureRESTClient) ListBlobs(ctx context.Context, container, prefix, marker string) (*azureBlobList, error) {
+	query := url.Values{
+		"restype":    {"container"},
+		"comp":       {"list"},
+		"maxresults": {strconv.Itoa(maxKeys)},
+	}
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	if marker != "" {
+		query.Set("marker", marker)
+	}
+
+	resp, err := c.do(ctx, http.MethodGet, container, "", query, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var list azureBlobList
+	if err := xml.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, errors.Wrap(err, "azure blob storage: failed to decode blob list")
+	}
+	return &list, nil
+}
+
+// SignedURL returns a URL with a service SAS granting read access to the blob
+// until expiry. Signing requires the account key.
+func (c *azureRESTClient) SignedURL(container, blob string, expiry time.Time) (string, error) {
+	if c.key == nil {
+		return "", errors.Wrap(ErrSignedURLsUnsupported, "azure blob storage: signed URLs require an account key")
+	}
+
+	const (
+		permissions = "r"
+		resource    = "b"
+	)
+	se := expiry.UTC().Format("2006-01-02T15:04:05Z")
+	canonicalResource := fmt.Sprintf("/blob/%s/%s/%s", c.account, container, blob)
+
+	// https://learn.microsoft.com/en-us/rest/api/storageservices/create-service-sas#version-2020-12-06-and-later
+	stringToSign := strings.Join([]string{
+		permissions,
+		"", // signedStart
+		se,
+		canonicalResource,
+		"", // signedIdentifier
+		"", // signedIP
+		"", // signedProtocol
+		azureAPIVersion,
+		resource,
+		"", // signedSnapshotTime
+		"", // signedEncryptionScope
+		"", // rscc
+		"", // rscd
+		"", // rsce
+		"", // rscl
+		"", // rsct
+	}, "\n")
+
+	query := url.Values{
+		"sv":  {azureAPIVersion},
+		"sr":  {resource},
+		"sp":  {permissions},
+		"se":  {se},
+		"sig": {c.sign(stringToSign)},
+	}
+	return c.blobURL(container, blob) + "?" + query.Encode(), nil
+}
+
+func (c *azureRESTClient) blobURL(container, blob string) string {
+	u := c.endpoint + "/" + url.PathEscape(container)
+	if blob != "" {
+		segments := strings.Split(blob, "/")
+		for i, segment := range segments {
+			segments[i] = url.PathEscape(segment)
+		}
+		u += "/" + strings.Join(segments, "/")
+	}
+	return u
+}
+
+// do sends an authorized request for the given container or blob and returns
+// the response if it has a success status. Otherwise, the error returned by the
+// service is returned as an *azureError.
+func (c *azureRESTClient) do(ctx context.Context, method, container, blob string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	u, err := url.Parse(c.blobURL(container, blob))
+	if err != nil {
+		return nil, err
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	for k, v := range c.sasToken {
+		query[k] = v
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.ContentLength = int64(len(body))
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureAPIVersion)
+
+	switch {
+	case c.key != nil:
+		req.Header.Set("Authorization", fmt.Sprintf("SharedKey %s:%s", c.account, c.sign(c.stringToSign(req))))
+	case c.imdsEndpoint != "":
+		token, err := c.managedIdentityToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	azErr := &azureError{StatusCode: resp.StatusCode}
+	if data, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024)); err == nil && len(data) > 0 {
+		_ = xml.Unmarshal(data, azErr)
+	}
+	if azErr.Code == "" {
+		azErr.Code = resp.Header.Get("x-ms-error-code")
+	}
+	return nil, azErr
+}
+
+// stringToSign returns the Shared Key string to sign of the request.
+//
+// https://learn.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key
+func (c *azureRESTClient) stringToSign(req *http.Request) string {
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+
+	msHeaders := map[string][]string{}
+	for k, v := range req.Header {
+		if k := strings.ToLower(k); strings.HasPrefix(k, "x-ms-") {
+			msHeaders[k] = append(msHeaders[k], v...)
+		}
+	}
+	msHeaderNames := make([]string, 0, len(msHeaders))
+	for k := range msHeaders {
+		msHeaderNames = append(msHeaderNames, k)
+	}
+	sort.Strings(msHeaderNames)
+	var canonicalHeaders strings.Builder
+	for _, k := range msHeaderNames {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", k, strings.Join(msHeaders[k], ","))
+	}
+
+	var canonicalResource strings.Builder
+	canonicalResource.WriteString("/" + c.account + req.URL.EscapedPath())
+	query := req.URL.Query()
+	params := make([]string, 0, len(query))
+	for k := range query {
+		params = append(params, k)
+	}
+	sort.Strings(params)
+	for _, k := range params {
+		values := query[k]
+		sort.Strings(values)
+		fmt.Fprintf(&canonicalResource, "\n%s:%s", strings.ToLower(k), strings.Join(values, ","))
+	}
+
+	return strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, we send x-ms-date instead
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		canonicalHeaders.String() + canonicalResource.String(),
+	}, "\n")
+}
+
+func (c *azureRESTClient) sign(s string) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(s))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// managedIdentityToken returns an access token for Azure Storage of the managed
+// identity, requesting a new one from the instance metadata service shortly
+// before the cached one expires.
+func (c *azureRESTClient) managedIdentityToken(ctx context.Context) (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if c.token != "" && time.Now().Add(5*time.Minute).Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	query := url.Values{"api-version": {"2018-02-01"}, "resource": {azureStorageResource}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.imdsEndpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata", "true")
+
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "azure blob storage: failed to request managed identity token")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Newf("azure blob storage: unexpected status %d requesting managed identity token", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresOn   string `json:"expires_on"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", errors.Wrap(err, "azure blob storage: failed to decode managed identity token")
+	}
+	expiresOn, err := strconv.ParseInt(token.ExpiresOn, 10, 64)
+	if err != nil {
+		return "", errors.Wrap(err, "azure blob storage: invalid managed identity token expiry")
+	}
+
+	c.token = token.AccessToken
+	c.tokenExpiry = time.Unix(expiresOn, 0)
+	return c.token, nil
+}
@@ -0,0 +1,307 @@
+package uploadstore
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	sglog "github.com/sourcegraph/log"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	sgiterator "github.com/sourcegraph/sourcegraph/lib/iterator"
+)
+
+type azureStore struct {
+	container    string
+	manageBucket bool
+	client       azureAPI
+	blockSize    int
+	operations   *Operations
+}
+
+var _ Store = &azureStore{}
+var _ URLSigner = &azureStore{}
+
+type AzureConfig struct {
+	// AccountName is the name of the storage account. The bucket of the store is
+	// a container of this account.
+	AccountName string
+	// AccountKey is a shared key of the storage account. It is required to sign
+	// URLs.
+	AccountKey string
+	// SASToken is a shared access signature with access to the container, used
+	// if no account key is set.
+	SASToken string
+	// UseManagedIdentity authenticates as the managed identity of the VM or pod
+	// if neither an account key nor a SAS token is set.
+	UseManagedIdentity bool
+	// Endpoint overrides the default blob service endpoint of the account,
+	// https://<account>.blob.core.windows.net. This is useful with Azurite.
+	Endpoint string
+}
+
+// azureBlockSize is the size of the blocks uploads are staged in. Blobs consist of
+// at most 50,000 blocks, so this limits the size of uploads to about 195GiB.
+const azureBlockSize = 4 * 1024 * 1024
+
+// newAzureFromConfig creates a new store backed by Azure Blob Storage.
+func newAzureFromConfig(ctx context.Context, config Config, operations *Operations) (Store, error) {
+	client, err := newAzureRESTClient(config.Azure, httpcli.ExternalDoer)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAzureWithClient(client, config.Bucket, config.ManageBucket, operations), nil
+}
+
+func newAzureWithClient(client azureAPI, container string, manageBucket bool, operations *Operations) *azureStore {
+	return &azureStore{
+		container:    container,
+		manageBucket: manageBucket,
+		client:       client,
+		blockSize:    azureBlockSize,
+		operations:   operations,
+	}
+}
+
+func (s *azureStore) Init(ctx context.Context) error {
+	if !s.manageBucket {
+		return nil
+	}
+
+	if err := s.client.CreateContainer(ctx, s.container); err != nil {
+		return errors.Wrap(err, "failed to create container")
+	}
+
+	return nil
+}
+
+func (s *azureStore) List(ctx context.Context) (*sgiterator.Iterator[string], error) {
+	return s.list(ctx, "", func(azureBlob) bool { return true }, s.operations.List), nil
+}
+
+func (s *azureStore) ListOlderThan(ctx context.Context, prefix string, maxAge time.Duration) (*sgiterator.Iterator[string], error) {
+	return s.list(ctx, prefix, func(blob azureBlob) bool {
+		return time.Since(blob.Properties.CreationTime.Time) >= maxAge
+	}, s.operations.ListOlderThan), nil
+}
+
+// list returns an iterator over the names of the blobs with the given prefix
+// that match the given predicate.
+func (s *azureStore) list(ctx context.Context, prefix string, match func(azureBlob) bool, operation *observation.Operation) *sgiterator.Iterator[string] {
+	marker := ""
+	done := false
+
+	next := func() ([]string, error) {
+		for !done {
+			page, err := s.client.ListBlobs(ctx, s.container, prefix, marker)
+			if err != nil {
+				operation.Logger.Error("Failed to list blobs in Azure container", sglog.Error(err))
+				return nil, err
+			}
+			marker = page.NextMarker
+			done = marker == ""
+
+			keys := make([]string, 0, len(page.Blobs))
+			for _, blob := range page.Blobs {
+				if match(blob) {
+					keys = append(keys, blob.Name)
+				}
+			}
+
+			// An empty batch signals exhaustion to the iterator
+			if len(keys) > 0 {
+				return keys, nil
+			}
+		}
+
+		return nil, nil
+	}
+
+	return sgiterator.New[string](next)
+}
+
+func (s *azureStore) Get(ctx context.Context, key string) (_ io.ReadCloser, err error) {
+	ctx, _, endObservation := s.operations.Get.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("key", key),
+	}})
+	done := func() { endObservation(1, observation.Args{}) }
+
+	rc, err := s.client.GetBlob(ctx, s.container, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get object")
+	}
+
+	return NewExtraCloser(rc, done), nil
+}
+
+func (s *azureStore) Upload(ctx context.Context, key string, r io.Reader) (_ int64, err error) {
+	ctx, _, endObservation := s.operations.Upload.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	blocks := &azureBlockWriter{store: s, blob: key}
+	if err := blocks.stage(ctx, r); err != nil {
+		return 0, errors.Wrap(err, "failed to upload object")
+	}
+	if err := blocks.commit(ctx); err != nil {
+		return 0, errors.Wrap(err, "failed to upload object")
+	}
+
+	return blocks.size, nil
+}
+
+// Compose stages the content of the source blobs as blocks of the destination
+// blob and commits them in order. Unlike S3 and GCS, the Blob service cannot
+// concatenate existing blobs, so the content of the sources passes through this
+// process.
+func (s *azureStore) Compose(ctx context.Context, destination string, sources ...string) (_ int64, err error) {
+	ctx, _, endObservation := s.operations.Compose.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("destination", destination),
+		attribute.StringSlice("sources", sources),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	defer func() {
+		if err == nil {
+			// Delete sources on success
+			if err := s.deleteSources(ctx, sources); err != nil {
+				log15.Error("Failed to delete source objects", "error", err)
+			}
+		}
+	}()
+
+	blocks := &azureBlockWriter{store: s, blob: destination}
+	for _, source := range sources {
+		if err := s.stageBlob(ctx, blocks, source); err != nil {
+			return 0, errors.Wrap(err, "failed to compose objects")
+		}
+	}
+	if err := blocks.commit(ctx); err != nil {
+		return 0, errors.Wrap(err, "failed to compose objects")
+	}
+
+	return blocks.size, nil
+}
+
+func (s *azureStore) stageBlob(ctx context.Context, blocks *azureBlockWriter, source string) error {
+	rc, err := s.client.GetBlob(ctx, s.container, source)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get source object %q", source)
+	}
+	defer rc.Close()
+
+	return blocks.stage(ctx, rc)
+}
+
+func (s *azureStore) Delete(ctx context.Context, key string) (err error) {
+	ctx, _, endObservation := s.operations.Delete.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return errors.Wrap(s.client.DeleteBlob(ctx, s.container, key), "failed to delete object")
+}
+
+func (s *azureStore) ExpireObjects(ctx context.Context, prefix string, maxAge time.Duration) (err error) {
+	ctx, _, endObservation := s.operations.ExpireObjects.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("prefix", prefix),
+		attribute.Stringer("maxAge", maxAge),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	marker := ""
+	for {
+		page, err := s.client.ListBlobs(ctx, s.container, prefix, marker)
+		if err != nil {
+			s.operations.ExpireObjects.Logger.Error("Failed to list blobs in Azure container", sglog.Error(err))
+			break // we'll try again later
+		}
+
+		for _, blob := range page.Blobs {
+			if time.Since(blob.Properties.CreationTime.Time) < maxAge {
+				continue
+			}
+			if err := s.client.DeleteBlob(ctx, s.container, blob.Name); err != nil {
+				s.operations.ExpireObjects.Logger.Error("Failed to delete expired Azure blob",
+					sglog.Error(err),
+					sglog.String("container", s.container),
+					sglog.String("blob", blob.Name))
+			}
+		}
+
+		if page.NextMarker == "" {
+			break
+		}
+		marker = page.NextMarker
+	}
+	return nil
+}
+
+// SignedURL returns a URL with a shared access signature granting read access to
+// the object at the given key until the given expiry.
+func (s *azureStore) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return s.client.SignedURL(s.container, key, time.Now().Add(expiry))
+}
+
+func (s *azureStore) deleteSources(ctx context.Context, sources []string) error {
+	return ForEachString(sources, func(index int, source string) error {
+		if err := s.client.DeleteBlob(ctx, s.container, source); err != nil {
+			return errors.Wrap(err, "failed to delete source object")
+		}
+
+		return nil
+	})
+}
+
+// azureBlockWriter stages content as blocks of a blob, which becomes visible
+// once the list of staged blocks is committed.
+type azureBlockWriter struct {
+	store    *azureStore
+	blob     string
+	blockIDs []string
+	size     int64
+	buf      []byte
+}
+
+// stage reads r to the end and stages its content as blocks of at most the
+// block size of the store.
+func (w *azureBlockWriter) stage(ctx context.Context, r io.Reader) error {
+	if w.buf == nil {
+		w.buf = make([]byte, w.store.blockSize)
+	}
+
+	for {
+		n, err := io.ReadFull(r, w.buf)
+		if n > 0 {
+			// Block IDs must have the same length within a blob.
+			id := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("block-%06d", len(w.blockIDs))))
+			if err := w.store.client.PutBlock(ctx, w.store.container, w.blob, id, w.buf[:n]); err != nil {
+				return errors.Wrap(err, "failed to stage block")
+			}
+			w.blockIDs = append(w.blockIDs, id)
+			w.size += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// commit writes the blob from the blocks staged so far.
+func (w *azureBlockWriter) commit(ctx context.Context) error {
+	if err := w.store.client.PutBlockList(ctx, w.store.container, w.blob, w.blockIDs); err != nil {
+		return errors.Wrap(err, "failed to commit blocks")
+	}
+	return nil
+}
@@ -0,0 +1,493 @@
+package uploadstore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
+)
+
+// encryptedMagic prefixes the objects written by EncryptedStore. Objects without it
+// were written before encryption was enabled and are read as is.
+var encryptedMagic = []byte("\x00sgenc\x01\n")
+
+const (
+	// encryptedSegmentSize is the size of the plaintext segments objects are sealed
+	// in, so that objects can be encrypted and decrypted while streaming.
+	encryptedSegmentSize = 64 * 1024
+	dataKeySize          = 32
+	noncePrefixSize      = 7
+)
+
+// EncryptedStore is a store that encrypts objects client-side before they are written
+// to the underlying store. Every object is encrypted with its own random data key,
+// which is itself encrypted with a key of the keyring and stored in a header in front
+// of the object (envelope encryption). Objects are decrypted transparently when read.
+//
+// Rotating the keyring key does not require re-encrypting the objects: Rewrap only
+// re-encrypts their data keys.
+type EncryptedStore struct {
+	store Store
+	key   func() encryption.Key
+}
+
+var _ Store = &EncryptedStore{}
+
+// NewEncryptedStore returns a store that encrypts the objects written to the given
+// store with the key returned by key. The key is looked up on every write so that
+// changes to the keyring take effect without a restart. If it returns nil, new
+// objects are written unencrypted.
+func NewEncryptedStore(store Store, key func() encryption.Key) *EncryptedStore {
+	return &EncryptedStore{store: store, key: key}
+}
+
+func (s *EncryptedStore) Init(ctx context.Context) error {
+	return s.store.Init(ctx)
+}
+
+func (s *EncryptedStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	rc, err := s.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	r := bufio.NewReaderSize(rc, encryptedSegmentSize)
+	header, ok, err := readEncryptedHeader(r)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	if !ok {
+		return &readCloser{Reader: r, Closer: rc}, nil
+	}
+
+	aead, err := s.openDataKey(ctx, header)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+
+	return &readCloser{Reader: newDecryptingReader(r, aead, header.noncePrefix), Closer: rc}, nil
+}
+
+func (s *EncryptedStore) Upload(ctx context.Context, key string, r io.Reader) (int64, error) {
+	encryptionKey := s.key()
+	if encryptionKey == nil {
+		return s.store.Upload(ctx, key, r)
+	}
+
+	header, aead, err := newEncryptedHeader(ctx, encryptionKey)
+	if err != nil {
+		return 0, err
+	}
+
+	er := newEncryptingReader(r, aead, header.noncePrefix)
+	if _, err := s.store.Upload(ctx, key, io.MultiReader(bytes.NewReader(header.bytes()), er)); err != nil {
+		return 0, err
+	}
+
+	return er.n, nil
+}
+
+// Compose concatenates the decrypted content of the sources. Encrypted objects cannot
+// be concatenated by the underlying store, so the content passes through this process.
+func (s *EncryptedStore) Compose(ctx context.Context, destination string, sources ...string) (int64, error) {
+	if s.key() == nil {
+		return s.store.Compose(ctx, destination, sources...)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		for _, source := range sources {
+			if err := s.copyTo(ctx, pw, source); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.Close()
+	}()
+
+	n, err := s.Upload(ctx, destination, pr)
+	pr.CloseWithError(err)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to compose objects")
+	}
+
+	// Delete sources on success
+	if err := ForEachString(sources, func(index int, source string) error {
+		return s.store.Delete(ctx, source)
+	}); err != nil {
+		log15.Error("Failed to delete source objects", "error", err)
+	}
+
+	return n, nil
+}
+
+func (s *EncryptedStore) copyTo(ctx context.Context, w io.Writer, key string) error {
+	rc, err := s.Get(ctx, key)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get source object %q", key)
+	}
+	defer rc.Close()
+
+	_, err = io.Copy(w, rc)
+	return err
+}
+
+func (s *EncryptedStore) Delete(ctx context.Context, key string) error {
+	return s.store.Delete(ctx, key)
+}
+
+func (s *EncryptedStore) ExpireObjects(ctx context.Context, prefix string, maxAge time.Duration) error {
+	return s.store.ExpireObjects(ctx, prefix, maxAge)
+}
+
+func (s *EncryptedStore) ListOlderThan(ctx context.Context, prefix string, maxAge time.Duration) (*iterator.Iterator[string], error) {
+	return s.store.ListOlderThan(ctx, prefix, maxAge)
+}
+
+func (s *EncryptedStore) List(ctx context.Context) (*iterator.Iterator[string], error) {
+	return s.store.List(ctx)
+}
+
+// Rewrap rewrites the object at the given key if it is not encrypted with the current
+// version of the key: the data key of encrypted objects is re-encrypted with the current
+// key, while the encrypted content is copied unchanged. Unencrypted objects are
+// encrypted. Rewrap reports whether the object was rewritten.
+//
+// Rewriting an object resets its age as reported by the underlying store.
+func (s *EncryptedStore) Rewrap(ctx context.Context, key string) (bool, error) {
+	encryptionKey := s.key()
+	if encryptionKey == nil {
+		return false, nil
+	}
+	version, err := encryptionKey.Version(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get key version")
+	}
+
+	rc, err := s.store.Get(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	defer rc.Close()
+
+	r := bufio.NewReaderSize(rc, encryptedSegmentSize)
+	header, ok, err := readEncryptedHeader(r)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		if _, err := s.Upload(ctx, key, r); err != nil {
+			return false, errors.Wrap(err, "failed to encrypt object")
+		}
+		return true, nil
+	}
+	if header.keyVersion == version.JSON() {
+		return false, nil
+	}
+
+	dataKey, err := s.decryptDataKey(ctx, header)
+	if err != nil {
+		return false, err
+	}
+	wrapped, err := encryptionKey.Encrypt(ctx, dataKey)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to encrypt data key")
+	}
+	header.keyVersion = version.JSON()
+	header.wrappedKey = wrapped
+
+	// The new object becomes visible only once it is completely written, after the
+	// old object has been read to the end.
+	if _, err := s.store.Upload(ctx, key, io.MultiReader(bytes.NewReader(header.bytes()), r)); err != nil {
+		return false, errors.Wrap(err, "failed to rewrite object")
+	}
+	return true, nil
+}
+
+func (s *EncryptedStore) decryptDataKey(ctx context.Context, header *encryptedHeader) ([]byte, error) {
+	encryptionKey := s.key()
+	if encryptionKey == nil {
+		return nil, errors.New("object is encrypted but no encryption key is configured")
+	}
+
+	secret, err := encryptionKey.Decrypt(ctx, header.wrappedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt data key")
+	}
+	dataKey := []byte(secret.Secret())
+	if len(dataKey) != dataKeySize {
+		return nil, errors.New("invalid data key")
+	}
+	return dataKey, nil
+}
+
+func (s *EncryptedStore) openDataKey(ctx context.Context, header *encryptedHeader) (cipher.AEAD, error) {
+	dataKey, err := s.decryptDataKey(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+	return newSegmentAEAD(dataKey)
+}
+
+// encryptedHeader is written in front of encrypted objects:
+//
+//	magic | uint16 len | key version | uint32 len | encrypted data key | nonce prefix
+type encryptedHeader struct {
+	keyVersion  string
+	wrappedKey  []byte
+	noncePrefix []byte
+}
+
+func newEncryptedHeader(ctx context.Context, key encryption.Key) (*encryptedHeader, cipher.AEAD, error) {
+	version, err := key.Version(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get key version")
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, err
+	}
+	noncePrefix := make([]byte, noncePrefixSize)
+	if _, err := rand.Read(noncePrefix); err != nil {
+		return nil, nil, err
+	}
+
+	wrapped, err := key.Encrypt(ctx, dataKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to encrypt data key")
+	}
+	aead, err := newSegmentAEAD(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &encryptedHeader{
+		keyVersion:  version.JSON(),
+		wrappedKey:  wrapped,
+		noncePrefix: noncePrefix,
+	}, aead, nil
+}
+
+func (h *encryptedHeader) bytes() []byte {
+	var buf bytes.Buffer
+	buf.Write(encryptedMagic)
+	_ = binary.Write(&buf, binary.BigEndian, uint16(len(h.keyVersion)))
+	buf.WriteString(h.keyVersion)
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(h.wrappedKey)))
+	buf.Write(h.wrappedKey)
+	buf.Write(h.noncePrefix)
+	return buf.Bytes()
+}
+
+// readEncryptedHeader reads the header of an encrypted object from r. If the object
+// is not encrypted, nothing is consumed from r and false is returned.
+func readEncryptedHeader(r *bufio.Reader) (*encryptedHeader, bool, error) {
+	magic, err := r.Peek(len(encryptedMagic))
+	if err != nil && err != io.EOF {
+		return nil, false, err
+	}
+	if !bytes.Equal(magic, encryptedMagic) {
+		return nil, false, nil
+	}
+	if _, err := r.Discard(len(encryptedMagic)); err != nil {
+		return nil, false, err
+	}
+
+	var versionLen uint16
+	if err := binary.Read(r, binary.BigEndian, &versionLen); err != nil {
+		return nil, false, errors.Wrap(err, "malformed encryption header")
+	}
+	version := make([]byte, versionLen)
+	if _, err := io.ReadFull(r, version); err != nil {
+		return nil, false, errors.Wrap(err, "malformed encryption header")
+	}
+
+	var wrappedLen uint32
+	if err := binary.Read(r, binary.BigEndian, &wrappedLen); err != nil {
+		return nil, false, errors.Wrap(err, "malformed encryption header")
+	}
+	if wrappedLen > 64*1024 {
+		return nil, false, errors.New("malformed encryption header: data key too large")
+	}
+	wrapped := make([]byte, wrappedLen)
+	if _, err := io.ReadFull(r, wrapped); err != nil {
+		return nil, false, errors.Wrap(err, "malformed encryption header")
+	}
+
+	noncePrefix := make([]byte, noncePrefixSize)
+	if _, err := io.ReadFull(r, noncePrefix); err != nil {
+		return nil, false, errors.Wrap(err, "malformed encryption header")
+	}
+
+	return &encryptedHeader{
+		keyVersion:  string(version),
+		wrappedKey:  wrapped,
+		noncePrefix: noncePrefix,
+	}, true, nil
+}
+
+func newSegmentAEAD(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating AES cipher")
+	}
+	return cipher.NewGCM(block)
+}
+
+// segmentNonce returns the nonce of the segment with the given index: the nonce
+// prefix, the big-endian index, and a byte marking the last segment, so that
+// segments cannot be reordered and truncation is detected.
+func segmentNonce(prefix []byte, index uint32, last bool) []byte {
+	nonce := make([]byte, 0, noncePrefixSize+5)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, index)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+// encryptingReader reads the plaintext of r and returns it sealed in segments.
+type encryptingReader struct {
+	r           *bufio.Reader
+	aead        cipher.AEAD
+	noncePrefix []byte
+	index       uint32
+	plaintext   []byte
+	sealed      []byte
+	pending     []byte
+	done        bool
+	// n is the number of plaintext bytes read.
+	n int64
+}
+
+func newEncryptingReader(r io.Reader, aead cipher.AEAD, noncePrefix []byte) *encryptingReader {
+	return &encryptingReader{
+		r:           bufio.NewReaderSize(r, encryptedSegmentSize),
+		aead:        aead,
+		noncePrefix: noncePrefix,
+		plaintext:   make([]byte, encryptedSegmentSize),
+	}
+}
+
+func (e *encryptingReader) Read(p []byte) (int, error) {
+	for len(e.pending) == 0 {
+		if e.done {
+			return 0, io.EOF
+		}
+		if err := e.sealNext(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, e.pending)
+	e.pending = e.pending[n:]
+	return n, nil
+}
+
+func (e *encryptingReader) sealNext() error {
+	n, err := io.ReadFull(e.r, e.plaintext)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	last := err != nil
+	if !last {
+		// A full segment is the last one if nothing follows it.
+		if _, err := e.r.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	e.n += int64(n)
+	e.sealed = e.aead.Seal(e.sealed[:0], segmentNonce(e.noncePrefix, e.index, last), e.plaintext[:n], nil)
+	e.pending = e.sealed
+	e.index++
+	e.done = last
+	return nil
+}
+
+// decryptingReader reads segments sealed by encryptingReader from r and returns their
+// plaintext.
+type decryptingReader struct {
+	r           *bufio.Reader
+	aead        cipher.AEAD
+	noncePrefix []byte
+	index       uint32
+	ciphertext  []byte
+	plaintext   []byte
+	pending     []byte
+	done        bool
+}
+
+func newDecryptingReader(r *bufio.Reader, aead cipher.AEAD, noncePrefix []byte) *decryptingReader {
+	return &decryptingReader{
+		r:           r,
+		aead:        aead,
+		noncePrefix: noncePrefix,
+		ciphertext:  make([]byte, encryptedSegmentSize+aead.Overhead()),
+	}
+}
+
+func (d *decryptingReader) Read(p []byte) (int, error) {
+	for len(d.pending) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.openNext(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	return n, nil
+}
+
+func (d *decryptingReader) openNext() error {
+	n, err := io.ReadFull(d.r, d.ciphertext)
+	if err == io.EOF {
+		return errors.New("encrypted object is truncated")
+	}
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	last := err != nil
+	if !last {
+		if _, err := d.r.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	plaintext, err := d.aead.Open(d.plaintext[:0], segmentNonce(d.noncePrefix, d.index, last), d.ciphertext[:n], nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to decrypt object")
+	}
+	d.plaintext = plaintext
+	d.pending = plaintext
+	d.index++
+	d.done = last
+	return nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,242 @@
+package uploadstore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	et "github.com/sourcegraph/sourcegraph/internal/encryption/testing"
+)
+
+// versionedTestKey is a test key with a configurable version. Like KMS keys, it can
+// decrypt values encrypted with all of its versions.
+type versionedTestKey struct {
+	et.TestKey
+	version string
+}
+
+func (k *versionedTestKey) Version(ctx context.Context) (encryption.KeyVersion, error) {
+	return encryption.KeyVersion{Type: "testkey", Version: k.version}, nil
+}
+
+func TestEncryptedStoreRoundTrip(t *testing.T) {
+	inner := newMemoryStore()
+	store := NewEncryptedStore(inner, func() encryption.Key { return &versionedTestKey{version: "1"} })
+
+	for name, contents := range map[string]string{
+		"empty":             "",
+		"small":             "secret scip payload",
+		"segment":           strings.Repeat("a", encryptedSegmentSize),
+		"multiple segments": strings.Repeat("0123456789", encryptedSegmentSize/4),
+	} {
+		t.Run(name, func(t *testing.T) {
+			n, err := store.Upload(context.Background(), name, strings.NewReader(contents))
+			if err != nil {
+				t.Fatalf("unexpected error uploading: %s", err)
+			}
+			if n != int64(len(contents)) {
+				t.Errorf("unexpected size. want=%d have=%d", len(contents), n)
+			}
+
+			stored := inner.objects[name].contents
+			if !strings.HasPrefix(stored, string(encryptedMagic)) {
+				t.Fatalf("expected object to be encrypted")
+			}
+			if contents != "" && strings.Contains(stored, contents) {
+				t.Fatalf("expected object not to contain the plaintext")
+			}
+
+			if diff := cmp.Diff(contents, readObject(t, store, name)); diff != "" {
+				t.Errorf("unexpected contents (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEncryptedStoreUnencryptedObjects(t *testing.T) {
+	inner := newMemoryStore()
+	inner.put("legacy", "written before encryption", time.Now())
+	store := NewEncryptedStore(inner, func() encryption.Key { return &versionedTestKey{version: "1"} })
+
+	if diff := cmp.Diff("written before encryption", readObject(t, store, "legacy")); diff != "" {
+		t.Errorf("unexpected contents (-want +got):\n%s", diff)
+	}
+
+	// Without a key, objects are written as is.
+	store = NewEncryptedStore(inner, func() encryption.Key { return nil })
+	if _, err := store.Upload(context.Background(), "plain", strings.NewReader("plain")); err != nil {
+		t.Fatalf("unexpected error uploading: %s", err)
+	}
+	if diff := cmp.Diff("plain", inner.objects["plain"].contents); diff != "" {
+		t.Errorf("unexpected stored contents (-want +got):\n%s", diff)
+	}
+}
+
+func TestEncryptedStoreTamperedObjects(t *testing.T) {
+	inner := newMemoryStore()
+	store := NewEncryptedStore(inner, func() encryption.Key { return &versionedTestKey{version: "1"} })
+
+	contents := strings.Repeat("x", 3*encryptedSegmentSize)
+	if _, err := store.Upload(context.Background(), "key", strings.NewReader(contents)); err != nil {
+		t.Fatalf("unexpected error uploading: %s", err)
+	}
+	stored := inner.objects["key"].contents
+
+	for name, tampered := range map[string]string{
+		"truncated segment":  stored[:len(stored)-10],
+		"dropped segment":    stored[:len(stored)-encryptedSegmentSize-16],
+		"flipped ciphertext": stored[:len(stored)-1] + string(stored[len(stored)-1]^1),
+	} {
+		inner.put("tampered", tampered, time.Now())
+		rc, err := store.Get(context.Background(), "tampered")
+		if err != nil {
+			t.Fatalf("unexpected error getting %s object: %s", name, err)
+		}
+		if _, err := io.ReadAll(rc); err == nil {
+			t.Errorf("expected error reading %s object", name)
+		}
+		rc.Close()
+	}
+
+	// Encrypted objects cannot be read without a key.
+	store = NewEncryptedStore(inner, func() encryption.Key { return nil })
+	if _, err := store.Get(context.Background(), "key"); err == nil {
+		t.Fatalf("expected error reading encrypted object without a key")
+	}
+}
+
+func TestEncryptedStoreCompose(t *testing.T) {
+	inner := newMemoryStore()
+	inner.put("part-0", "unencrypted ", time.Now())
+	store := NewEncryptedStore(inner, func() encryption.Key { return &versionedTestKey{version: "1"} })
+	if _, err := store.Upload(context.Background(), "part-1", strings.NewReader("encrypted")); err != nil {
+		t.Fatalf("unexpected error uploading: %s", err)
+	}
+
+	n, err := store.Compose(context.Background(), "composed", "part-0", "part-1")
+	if err != nil {
+		t.Fatalf("unexpected error composing: %s", err)
+	}
+	if n != 21 {
+		t.Errorf("unexpected size. want=%d have=%d", 21, n)
+	}
+	if diff := cmp.Diff("unencrypted encrypted", readObject(t, store, "composed")); diff != "" {
+		t.Errorf("unexpected contents (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"composed"}, inner.keys()); diff != "" {
+		t.Errorf("unexpected keys (-want +got):\n%s", diff)
+	}
+}
+
+func TestEncryptedStoreRewrap(t *testing.T) {
+	inner := newMemoryStore()
+	key := &versionedTestKey{version: "1"}
+	store := NewEncryptedStore(inner, func() encryption.Key { return key })
+
+	contents := strings.Repeat("index ", encryptedSegmentSize)
+	if _, err := store.Upload(context.Background(), "current", strings.NewReader(contents)); err != nil {
+		t.Fatalf("unexpected error uploading: %s", err)
+	}
+	inner.put("legacy", "unencrypted", time.Now())
+
+	// Nothing to do for objects encrypted with the current key version.
+	if ok, err := store.Rewrap(context.Background(), "current"); err != nil {
+		t.Fatalf("unexpected error rewrapping: %s", err)
+	} else if ok {
+		t.Fatalf("unexpected rewrap of object encrypted with the current key version")
+	}
+
+	key.version = "2"
+	before := inner.objects["current"].contents
+	if ok, err := store.Rewrap(context.Background(), "current"); err != nil {
+		t.Fatalf("unexpected error rewrapping: %s", err)
+	} else if !ok {
+		t.Fatalf("expected object to be rewrapped")
+	}
+
+	after := inner.objects["current"].contents
+	header, _, err := readEncryptedHeader(bufioReader(after))
+	if err != nil {
+		t.Fatalf("unexpected error reading header: %s", err)
+	}
+	if want := (encryption.KeyVersion{Type: "testkey", Version: "2"}).JSON(); header.keyVersion != want {
+		t.Errorf("unexpected key version. want=%s have=%s", want, header.keyVersion)
+	}
+	// The encrypted content is copied as is.
+	if !strings.HasSuffix(after, before[len(before)-encryptedSegmentSize:]) {
+		t.Errorf("expected encrypted content to be unchanged")
+	}
+	if diff := cmp.Diff(contents, readObject(t, store, "current")); diff != "" {
+		t.Errorf("unexpected contents (-want +got):\n%s", diff)
+	}
+
+	// Unencrypted objects are encrypted.
+	if ok, err := store.Rewrap(context.Background(), "legacy"); err != nil {
+		t.Fatalf("unexpected error rewrapping: %s", err)
+	} else if !ok {
+		t.Fatalf("expected object to be encrypted")
+	}
+	if !strings.HasPrefix(inner.objects["legacy"].contents, string(encryptedMagic)) {
+		t.Fatalf("expected object to be encrypted")
+	}
+	if diff := cmp.Diff("unencrypted", readObject(t, store, "legacy")); diff != "" {
+		t.Errorf("unexpected contents (-want +got):\n%s", diff)
+	}
+}
+
+func TestKeyRotator(t *testing.T) {
+	inner := newMemoryStore()
+	key := &versionedTestKey{version: "1"}
+	store := NewEncryptedStore(inner, func() encryption.Key { return key })
+
+	for _, k := range []string{"uploads/1", "uploads/2", "other/1"} {
+		if _, err := store.Upload(context.Background(), k, strings.NewReader(k)); err != nil {
+			t.Fatalf("unexpected error uploading: %s", err)
+		}
+	}
+
+	key.version = "2"
+	rotator := &keyRotator{store: store, prefix: "uploads/", logger: logtest.Scoped(t)}
+	if err := rotator.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error rotating keys: %s", err)
+	}
+
+	want := (encryption.KeyVersion{Type: "testkey", Version: "2"}).JSON()
+	for k, rotated := range map[string]bool{"uploads/1": true, "uploads/2": true, "other/1": false} {
+		header, _, err := readEncryptedHeader(bufioReader(inner.objects[k].contents))
+		if err != nil {
+			t.Fatalf("unexpected error reading header: %s", err)
+		}
+		if (header.keyVersion == want) != rotated {
+			t.Errorf("unexpected key version of %q: %s", k, header.keyVersion)
+		}
+	}
+}
+
+func readObject(t *testing.T, store Store, key string) string {
+	t.Helper()
+
+	rc, err := store.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("unexpected error getting %q: %s", key, err)
+	}
+	defer rc.Close()
+
+	contents, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("unexpected error reading %q: %s", key, err)
+	}
+	return string(contents)
+}
+
+func bufioReader(s string) *bufio.Reader {
+	return bufio.NewReader(bytes.NewReader([]byte(s)))
+}
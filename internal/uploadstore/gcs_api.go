@@ -17,6 +17,7 @@ type gcsBucketHandle interface {
 	Create(ctx context.Context, projectID string, attrs *storage.BucketAttrs) error
 	Object(name string) gcsObjectHandle
 	Objects(ctx context.Context, q *storage.Query) gcsObjectIterator
+	SignedURL(object string, opts *storage.SignedURLOptions) (string, error)
 }
 
 type gcsObjectHandle interface {
@@ -71,6 +72,10 @@ func (s *bucketHandleShim) Objects(ctx context.Context, q *storage.Query) gcsObj
 	return &objectIteratorShim{handle: s.handle.Objects(ctx, q)}
 }
 
+func (s *bucketHandleShim) SignedURL(object string, opts *storage.SignedURLOptions) (string, error) {
+	return s.handle.SignedURL(object, opts)
+}
+
 func (s *objectHandleShim) Delete(ctx context.Context) error {
 	return s.handle.Delete(ctx)
 }
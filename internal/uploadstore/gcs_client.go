@@ -3,6 +3,7 @@ package uploadstore
 import (
 	"context"
 	"io"
+	"net/http"
 	"time"
 
 	"cloud.google.com/go/storage"
@@ -27,6 +28,7 @@ type gcsStore struct {
 }
 
 var _ Store = &gcsStore{}
+var _ URLSigner = &gcsStore{}
 
 type GCSConfig struct {
 	ProjectID               string
@@ -108,6 +110,36 @@ func (s *gcsStore) List(ctx context.Context) (*sgiterator.Iterator[string], erro
 	return sgiterator.New[string](next), nil
 }
 
+func (s *gcsStore) ListOlderThan(ctx context.Context, prefix string, maxAge time.Duration) (*sgiterator.Iterator[string], error) {
+	query := storage.Query{Prefix: prefix}
+
+	// Performance optimization
+	query.SetAttrSelection([]string{"Name", "Created"})
+
+	iter := s.client.Bucket(s.bucket).Objects(ctx, &query)
+
+	next := func() ([]string, error) {
+		var keys []string
+		for len(keys) < maxKeys {
+			attr, err := iter.Next()
+			if err != nil && err != iterator.Done {
+				s.operations.ListOlderThan.Logger.Error("Failed to list objects in GCS bucket", sglog.Error(err))
+				return nil, err
+			}
+			if err == iterator.Done {
+				break
+			}
+			if time.Since(attr.Created) >= maxAge {
+				keys = append(keys, attr.Name)
+			}
+		}
+
+		return keys, nil
+	}
+
+	return sgiterator.New[string](next), nil
+}
+
 func (s *gcsStore) Get(ctx context.Context, key string) (_ io.ReadCloser, err error) {
 	ctx, _, endObservation := s.operations.Get.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
 		attribute.String("key", key),
@@ -220,6 +252,21 @@ func (s *gcsStore) ExpireObjects(ctx context.Context, prefix string, maxAge time
 	return nil
 }
 
+// SignedURL returns a V4 signed URL to read the object at the given key. Signing uses
+// the service account of the configured credentials.
+func (s *gcsStore) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	url, err := s.client.Bucket(s.bucket).SignedURL(key, &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  http.MethodGet,
+		Expires: time.Now().Add(expiry),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign URL")
+	}
+
+	return url, nil
+}
+
 func (s *gcsStore) create(ctx context.Context, bucket gcsBucketHandle) error {
 	return bucket.Create(ctx, s.config.ProjectID, nil)
 }
@@ -306,3 +306,27 @@ type nopCloser struct {
 func (nopCloser) Close() error {
 	return nil
 }
+
+func TestGCSSignedURL(t *testing.T) {
+	gcsClient := NewMockGcsAPI()
+	bucketHandle := NewMockGcsBucketHandle()
+	gcsClient.BucketFunc.SetDefaultReturn(bucketHandle)
+	bucketHandle.SignedURLFunc.SetDefaultReturn("https://storage.googleapis.com/test-bucket/test-key?X-Goog-Signature=sig", nil)
+
+	client := testGCSClient(gcsClient, false)
+	url, err := SignedURL(context.Background(), client, "test-key", time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error signing URL: %s", err)
+	}
+	if want := "https://storage.googleapis.com/test-bucket/test-key?X-Goog-Signature=sig"; url != want {
+		t.Errorf("unexpected URL. want=%s have=%s", want, url)
+	}
+
+	if calls := bucketHandle.SignedURLFunc.History(); len(calls) != 1 {
+		t.Fatalf("unexpected number of SignedURL calls. want=%d have=%d", 1, len(calls))
+	} else if value := calls[0].Arg0; value != "test-key" {
+		t.Errorf("unexpected object argument. want=%s have=%s", "test-key", value)
+	} else if opts := calls[0].Arg1; opts.Method != "GET" || opts.Scheme != storage.SigningSchemeV4 {
+		t.Errorf("unexpected options. want GET with V4 signing, have=%+v", opts)
+	}
+}
@@ -0,0 +1,59 @@
+package uploadstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type keyRotator struct {
+	store  *EncryptedStore
+	prefix string
+	logger log.Logger
+}
+
+// NewKeyRotator returns a background routine that periodically rewraps the objects with
+// the given prefix of the given store that are not encrypted with the current version of
+// its key. See EncryptedStore.Rewrap.
+func NewKeyRotator(ctx context.Context, store *EncryptedStore, name, prefix string, interval time.Duration) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		&keyRotator{
+			store:  store,
+			prefix: prefix,
+			logger: log.Scoped("uploadstore-key-rotator", "rewraps upload store objects with the current encryption key"),
+		},
+		goroutine.WithName(name),
+		goroutine.WithDescription("re-encrypts the data keys of upload store objects with the current encryption key"),
+		goroutine.WithInterval(interval),
+	)
+}
+
+func (r *keyRotator) Handle(ctx context.Context) error {
+	it, err := r.store.ListOlderThan(ctx, r.prefix, 0)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	rewrapped := 0
+	for it.Next() {
+		ok, err := r.store.Rewrap(ctx, it.Current())
+		if err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "rewrap %q", it.Current()))
+			continue
+		}
+		if ok {
+			rewrapped++
+		}
+	}
+	if rewrapped > 0 {
+		r.logger.Info("rewrapped upload store objects", log.Int("count", rewrapped))
+	}
+
+	return errors.Append(errs, it.Err())
+}
@@ -73,6 +73,22 @@ func (s *lazyStore) ExpireObjects(ctx context.Context, prefix string, maxAge tim
 	return s.store.ExpireObjects(ctx, prefix, maxAge)
 }
 
+func (s *lazyStore) ListOlderThan(ctx context.Context, prefix string, maxAge time.Duration) (*iterator.Iterator[string], error) {
+	if err := s.initOnce(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.store.ListOlderThan(ctx, prefix, maxAge)
+}
+
+func (s *lazyStore) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if err := s.initOnce(ctx); err != nil {
+		return "", err
+	}
+
+	return SignedURL(ctx, s.store, key, expiry)
+}
+
 // initOnce serializes access to the underlying store's Init method. If the
 // Init method completes successfully, all future calls to this function will
 // no-op.
@@ -38,6 +38,9 @@ type MockStore struct {
 	// ListFunc is an instance of a mock function object controlling the
 	// behavior of the method List.
 	ListFunc *StoreListFunc
+	// ListOlderThanFunc is an instance of a mock function object
+	// controlling the behavior of the method ListOlderThan.
+	ListOlderThanFunc *StoreListOlderThanFunc
 	// UploadFunc is an instance of a mock function object controlling the
 	// behavior of the method Upload.
 	UploadFunc *StoreUploadFunc
@@ -77,6 +80,11 @@ func NewMockStore() *MockStore {
 				return
 			},
 		},
+		ListOlderThanFunc: &StoreListOlderThanFunc{
+			defaultHook: func(context.Context, string, time.Duration) (r0 *iterator.Iterator[string], r1 error) {
+				return
+			},
+		},
 		UploadFunc: &StoreUploadFunc{
 			defaultHook: func(context.Context, string, io.Reader) (r0 int64, r1 error) {
 				return
@@ -119,6 +127,11 @@ func NewStrictMockStore() *MockStore {
 				panic("unexpected invocation of MockStore.List")
 			},
 		},
+		ListOlderThanFunc: &StoreListOlderThanFunc{
+			defaultHook: func(context.Context, string, time.Duration) (*iterator.Iterator[string], error) {
+				panic("unexpected invocation of MockStore.ListOlderThan")
+			},
+		},
 		UploadFunc: &StoreUploadFunc{
 			defaultHook: func(context.Context, string, io.Reader) (int64, error) {
 				panic("unexpected invocation of MockStore.Upload")
@@ -149,6 +162,9 @@ func NewMockStoreFrom(i uploadstore.Store) *MockStore {
 		ListFunc: &StoreListFunc{
 			defaultHook: i.List,
 		},
+		ListOlderThanFunc: &StoreListOlderThanFunc{
+			defaultHook: i.ListOlderThan,
+		},
 		UploadFunc: &StoreUploadFunc{
 			defaultHook: i.Upload,
 		},
@@ -795,6 +811,116 @@ func (c StoreListFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// StoreListOlderThanFunc describes the behavior when the ListOlderThan
+// method of the parent MockStore instance is invoked.
+type StoreListOlderThanFunc struct {
+	defaultHook func(context.Context, string, time.Duration) (*iterator.Iterator[string], error)
+	hooks       []func(context.Context, string, time.Duration) (*iterator.Iterator[string], error)
+	history     []StoreListOlderThanFuncCall
+	mutex       sync.Mutex
+}
+
+// ListOlderThan delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockStore) ListOlderThan(v0 context.Context, v1 string, v2 time.Duration) (*iterator.Iterator[string], error) {
+	r0, r1 := m.ListOlderThanFunc.nextHook()(v0, v1, v2)
+	m.ListOlderThanFunc.appendCall(StoreListOlderThanFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ListOlderThan method
+// of the parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreListOlderThanFunc) SetDefaultHook(hook func(context.Context, string, time.Duration) (*iterator.Iterator[string], error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListOlderThan method of the parent MockStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreListOlderThanFunc) PushHook(hook func(context.Context, string, time.Duration) (*iterator.Iterator[string], error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreListOlderThanFunc) SetDefaultReturn(r0 *iterator.Iterator[string], r1 error) {
+	f.SetDefaultHook(func(context.Context, string, time.Duration) (*iterator.Iterator[string], error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreListOlderThanFunc) PushReturn(r0 *iterator.Iterator[string], r1 error) {
+	f.PushHook(func(context.Context, string, time.Duration) (*iterator.Iterator[string], error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreListOlderThanFunc) nextHook() func(context.Context, string, time.Duration) (*iterator.Iterator[string], error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreListOlderThanFunc) appendCall(r0 StoreListOlderThanFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreListOlderThanFuncCall objects
+// describing the invocations of this function.
+func (f *StoreListOlderThanFunc) History() []StoreListOlderThanFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreListOlderThanFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreListOlderThanFuncCall is an object that describes an invocation of
+// method ListOlderThan on an instance of MockStore.
+type StoreListOlderThanFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 time.Duration
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *iterator.Iterator[string]
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreListOlderThanFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreListOlderThanFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // StoreUploadFunc describes the behavior when the Upload method of the
 // parent MockStore instance is invoked.
 type StoreUploadFunc struct {
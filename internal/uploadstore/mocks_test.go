@@ -183,6 +183,9 @@ type MockGcsBucketHandle struct {
 	// ObjectsFunc is an instance of a mock function object controlling the
 	// behavior of the method Objects.
 	ObjectsFunc *GcsBucketHandleObjectsFunc
+	// SignedURLFunc is an instance of a mock function object controlling
+	// the behavior of the method SignedURL.
+	SignedURLFunc *GcsBucketHandleSignedURLFunc
 }
 
 // NewMockGcsBucketHandle creates a new mock of the gcsBucketHandle
@@ -210,6 +213,11 @@ func NewMockGcsBucketHandle() *MockGcsBucketHandle {
 				return
 			},
 		},
+		SignedURLFunc: &GcsBucketHandleSignedURLFunc{
+			defaultHook: func(string, *storage.SignedURLOptions) (r0 string, r1 error) {
+				return
+			},
+		},
 	}
 }
 
@@ -237,6 +245,11 @@ func NewStrictMockGcsBucketHandle() *MockGcsBucketHandle {
 				panic("unexpected invocation of MockGcsBucketHandle.Objects")
 			},
 		},
+		SignedURLFunc: &GcsBucketHandleSignedURLFunc{
+			defaultHook: func(string, *storage.SignedURLOptions) (string, error) {
+				panic("unexpected invocation of MockGcsBucketHandle.SignedURL")
+			},
+		},
 	}
 }
 
@@ -268,6 +281,9 @@ func NewMockGcsBucketHandleFrom(i surrogateMockGcsBucketHandle) *MockGcsBucketHa
 		ObjectsFunc: &GcsBucketHandleObjectsFunc{
 			defaultHook: i.Objects,
 		},
+		SignedURLFunc: &GcsBucketHandleSignedURLFunc{
+			defaultHook: i.SignedURL,
+		},
 	}
 }
 
@@ -691,6 +707,114 @@ func (c GcsBucketHandleObjectsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// GcsBucketHandleSignedURLFunc describes the behavior when the SignedURL
+// method of the parent MockGcsBucketHandle instance is invoked.
+type GcsBucketHandleSignedURLFunc struct {
+	defaultHook func(string, *storage.SignedURLOptions) (string, error)
+	hooks       []func(string, *storage.SignedURLOptions) (string, error)
+	history     []GcsBucketHandleSignedURLFuncCall
+	mutex       sync.Mutex
+}
+
+// SignedURL delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockGcsBucketHandle) SignedURL(v0 string, v1 *storage.SignedURLOptions) (string, error) {
+	r0, r1 := m.SignedURLFunc.nextHook()(v0, v1)
+	m.SignedURLFunc.appendCall(GcsBucketHandleSignedURLFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the SignedURL method of
+// the parent MockGcsBucketHandle instance is invoked and the hook queue is
+// empty.
+func (f *GcsBucketHandleSignedURLFunc) SetDefaultHook(hook func(string, *storage.SignedURLOptions) (string, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SignedURL method of the parent MockGcsBucketHandle instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *GcsBucketHandleSignedURLFunc) PushHook(hook func(string, *storage.SignedURLOptions) (string, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GcsBucketHandleSignedURLFunc) SetDefaultReturn(r0 string, r1 error) {
+	f.SetDefaultHook(func(string, *storage.SignedURLOptions) (string, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GcsBucketHandleSignedURLFunc) PushReturn(r0 string, r1 error) {
+	f.PushHook(func(string, *storage.SignedURLOptions) (string, error) {
+		return r0, r1
+	})
+}
+
+func (f *GcsBucketHandleSignedURLFunc) nextHook() func(string, *storage.SignedURLOptions) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GcsBucketHandleSignedURLFunc) appendCall(r0 GcsBucketHandleSignedURLFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GcsBucketHandleSignedURLFuncCall objects
+// describing the invocations of this function.
+func (f *GcsBucketHandleSignedURLFunc) History() []GcsBucketHandleSignedURLFuncCall {
+	f.mutex.Lock()
+	history := make([]GcsBucketHandleSignedURLFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GcsBucketHandleSignedURLFuncCall is an object that describes an
+// invocation of method SignedURL on an instance of MockGcsBucketHandle.
+type GcsBucketHandleSignedURLFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 string
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 *storage.SignedURLOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 string
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GcsBucketHandleSignedURLFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GcsBucketHandleSignedURLFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // MockGcsComposer is a mock implementation of the gcsComposer interface
 // (from the package
 // github.com/sourcegraph/sourcegraph/internal/uploadstore) used for unit
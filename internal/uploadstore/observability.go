@@ -14,6 +14,7 @@ type Operations struct {
 	Delete        *observation.Operation
 	ExpireObjects *observation.Operation
 	List          *observation.Operation
+	ListOlderThan *observation.Operation
 }
 
 func NewOperations(observationCtx *observation.Context, domain, storeName string) *Operations {
@@ -39,5 +40,6 @@ func NewOperations(observationCtx *observation.Context, domain, storeName string
 		Delete:        op("Delete"),
 		ExpireObjects: op("ExpireObjects"),
 		List:          op("List"),
+		ListOlderThan: op("ListOlderThan"),
 	}
 }
@@ -28,10 +28,12 @@ type s3Store struct {
 	manageBucket bool
 	client       s3API
 	uploader     s3Uploader
+	presigner    *s3.PresignClient
 	operations   *Operations
 }
 
 var _ Store = &s3Store{}
+var _ URLSigner = &s3Store{}
 
 type S3Config struct {
 	Region          string
@@ -52,7 +54,9 @@ func newS3FromConfig(ctx context.Context, config Config, operations *Operations)
 	s3Client := s3.NewFromConfig(cfg, s3ClientOptions(config.S3))
 	api := &s3APIShim{s3Client}
 	uploader := &s3UploaderShim{manager.NewUploader(s3Client)}
-	return newS3WithClients(api, uploader, config.Bucket, config.ManageBucket, operations), nil
+	store := newS3WithClients(api, uploader, config.Bucket, config.ManageBucket, operations)
+	store.presigner = s3.NewPresignClient(s3Client)
+	return store, nil
 }
 
 func newS3WithClients(client s3API, uploader s3Uploader, bucket string, manageBucket bool, operations *Operations) *s3Store {
@@ -112,6 +116,39 @@ func (s *s3Store) List(ctx context.Context) (*iterator.Iterator[string], error)
 	return iterator.New[string](next), nil
 }
 
+func (s *s3Store) ListOlderThan(ctx context.Context, prefix string, maxAge time.Duration) (*iterator.Iterator[string], error) {
+	paginator := s.client.NewListObjectsV2Paginator(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	next := func() ([]string, error) {
+		for paginator.HasMorePages() {
+			nextPage, err := paginator.NextPage(ctx)
+			if err != nil {
+				s.operations.ListOlderThan.Logger.Error("Failed to paginate S3 bucket", sglog.Error(err))
+				return nil, err
+			}
+
+			keys := make([]string, 0, len(nextPage.Contents))
+			for _, c := range nextPage.Contents {
+				if c.Key != nil && c.LastModified != nil && time.Since(*c.LastModified) >= maxAge {
+					keys = append(keys, *c.Key)
+				}
+			}
+
+			// An empty batch signals exhaustion to the iterator
+			if len(keys) > 0 {
+				return keys, nil
+			}
+		}
+
+		return nil, nil
+	}
+
+	return iterator.New[string](next), nil
+}
+
 func (s *s3Store) Get(ctx context.Context, key string) (_ io.ReadCloser, err error) {
 	ctx, _, endObservation := s.operations.Get.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
 		attribute.String("key", key),
@@ -346,6 +383,23 @@ func (s *s3Store) ExpireObjects(ctx context.Context, prefix string, maxAge time.
 	return nil
 }
 
+// SignedURL returns a presigned URL to read the object at the given key.
+func (s *s3Store) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if s.presigner == nil {
+		return "", ErrSignedURLsUnsupported
+	}
+
+	req, err := s.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to presign URL")
+	}
+
+	return req.URL, nil
+}
+
 func (s *s3Store) create(ctx context.Context) error {
 	_, err := s.client.CreateBucket(ctx, &s3.CreateBucketInput{
 		Bucket: aws.String(s.bucket),
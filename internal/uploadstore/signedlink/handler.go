@@ -0,0 +1,104 @@
+package signedlink
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Object is an object of an upload store served through signed links.
+type Object struct {
+	ID            int
+	SigningSecret []byte
+	// ObjectKey is the key of the object in the upload store, or empty if the
+	// object is not available (yet).
+	ObjectKey   string
+	ContentType string
+	Filename    string
+}
+
+// ErrNotFound is returned by a LookupFunc if the object does not exist, or the
+// requester may not download it.
+var ErrNotFound = errors.New("object not found")
+
+// LookupFunc returns the object with the given ID, or ErrNotFound.
+type LookupFunc func(ctx context.Context, id int) (*Object, error)
+
+// NewHandler returns the handler serving the signed links created by URL. The
+// route must provide the id variable. Name describes the objects in logs and
+// error messages, e.g. "search export".
+func NewHandler(logger log.Logger, name string, uploadStore uploadstore.Store, lookup LookupFunc) http.Handler {
+	return &handler{
+		logger:      logger,
+		name:        name,
+		uploadStore: uploadStore,
+		lookup:      lookup,
+	}
+}
+
+type handler struct {
+	logger      log.Logger
+	name        string
+	uploadStore uploadstore.Store
+	lookup      LookupFunc
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	object, ok := h.authorize(r.Context(), r)
+	if !ok {
+		// Do not reveal whether the object exists.
+		http.Error(w, "invalid or expired download link", http.StatusForbidden)
+		return
+	}
+	if object.ObjectKey == "" {
+		http.Error(w, fmt.Sprintf("%s is not available", h.name), http.StatusNotFound)
+		return
+	}
+
+	rc, err := h.uploadStore.Get(r.Context(), object.ObjectKey)
+	if err != nil {
+		h.logger.Error("failed to read "+h.name, log.Int("id", object.ID), log.Error(err))
+		http.Error(w, fmt.Sprintf("%s is unavailable", h.name), http.StatusInternalServerError)
+		return
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Type", object.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", object.Filename))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err := io.Copy(w, rc); err != nil {
+		h.logger.Warn("failed to stream "+h.name, log.Int("id", object.ID), log.Error(err))
+	}
+}
+
+// authorize returns the object the request downloads if its link is valid.
+func (h *handler) authorize(ctx context.Context, r *http.Request) (*Object, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return nil, false
+	}
+	expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	object, err := h.lookup(ctx, id)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			h.logger.Error("failed to get "+h.name, log.Int("id", id), log.Error(err))
+		}
+		return nil, false
+	}
+	if !verify(object.SigningSecret, object.ID, expires, r.URL.Query().Get("signature"), time.Now()) {
+		return nil, false
+	}
+	return object, true
+}
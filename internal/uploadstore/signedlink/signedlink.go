@@ -0,0 +1,48 @@
+// Package signedlink serves the objects of an upload store through links that
+// are signed with a per-object secret and expire, so that they can be handed to
+// users without proxying every download through an authenticated API.
+package signedlink
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// TTL is how long signed links are valid.
+const TTL = time.Hour
+
+// URL returns the link at path/id to download the object with the given ID,
+// signed with secret and valid until expires. Anyone with the link can download
+// the object, so callers must only hand it to users that may access the object.
+func URL(externalURL *url.URL, path string, id int, secret []byte, expires time.Time) string {
+	u := externalURL.ResolveReference(&url.URL{Path: fmt.Sprintf("%s/%d", path, id)})
+	u.RawQuery = url.Values{
+		"expires":   []string{strconv.FormatInt(expires.Unix(), 10)},
+		"signature": []string{hex.EncodeToString(sign(secret, id, expires.Unix()))},
+	}.Encode()
+	return u.String()
+}
+
+func sign(secret []byte, id int, expires int64) []byte {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%d:%d", id, expires)
+	return mac.Sum(nil)
+}
+
+// verify reports whether signature is a valid signature of a link to the
+// object with the given ID and secret, and the link has not expired at now.
+func verify(secret []byte, id int, expires int64, signature string, now time.Time) bool {
+	if now.Unix() > expires {
+		return false
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(got, sign(secret, id, expires))
+}
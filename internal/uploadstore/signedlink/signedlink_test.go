@@ -0,0 +1,83 @@
+package signedlink
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+)
+
+func TestVerify(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Now()
+	expires := now.Add(TTL).Unix()
+
+	externalURL, err := url.Parse("https://sourcegraph.example.com")
+	require.NoError(t, err)
+	u, err := url.Parse(URL(externalURL, "/.api/objects", 1, secret, time.Unix(expires, 0)))
+	require.NoError(t, err)
+	require.Equal(t, "/.api/objects/1", u.Path)
+	signature := u.Query().Get("signature")
+
+	require.True(t, verify(secret, 1, expires, signature, now))
+	require.False(t, verify(secret, 1, expires, signature, now.Add(2*TTL)), "expired")
+	require.False(t, verify(secret, 1, expires+1, signature, now), "extended expiry")
+	require.False(t, verify(secret, 2, expires, signature, now), "other object")
+	require.False(t, verify([]byte("other"), 1, expires, signature, now), "other secret")
+	require.False(t, verify(secret, 1, expires, "not hex", now))
+}
+
+func TestHandler(t *testing.T) {
+	completed := &Object{ID: 1, SigningSecret: []byte("secret"), ObjectKey: "objects/1.json", ContentType: "application/json", Filename: "object-1.json"}
+	pending := &Object{ID: 2, SigningSecret: []byte("secret")}
+	forbidden := &Object{ID: 3, SigningSecret: []byte("secret"), ObjectKey: "objects/3.json"}
+
+	uploadStore := mocks.NewMockStore()
+	uploadStore.GetFunc.SetDefaultHook(func(_ context.Context, key string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("contents of " + key)), nil
+	})
+
+	router := mux.NewRouter()
+	router.Path("/.api/objects/{id}").Handler(NewHandler(logtest.Scoped(t), "object", uploadStore, func(_ context.Context, id int) (*Object, error) {
+		switch id {
+		case completed.ID:
+			return completed, nil
+		case pending.ID:
+			return pending, nil
+		}
+		// Objects the requester may not download are not found.
+		return nil, ErrNotFound
+	}))
+	externalURL, err := url.Parse("http://example.com")
+	require.NoError(t, err)
+	get := func(object *Object, expires time.Time) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, URL(externalURL, "/.api/objects", object.ID, object.SigningSecret, expires), nil))
+		return rec
+	}
+
+	rec := get(completed, time.Now().Add(time.Minute))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "contents of objects/1.json", rec.Body.String())
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.Equal(t, `attachment; filename="object-1.json"`, rec.Header().Get("Content-Disposition"))
+
+	rec = get(pending, time.Now().Add(time.Minute))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = get(completed, time.Now().Add(-time.Minute))
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = get(forbidden, time.Now().Add(time.Minute))
+	require.Equal(t, http.StatusForbidden, rec.Code)
+}
@@ -33,14 +33,42 @@ type Store interface {
 	// the age of the object exceeds the given max age.
 	ExpireObjects(ctx context.Context, prefix string, maxAge time.Duration) error
 
+	// ListOlderThan returns an iterator over all keys with the given prefix whose
+	// object age exceeds the given max age.
+	ListOlderThan(ctx context.Context, prefix string, maxAge time.Duration) (*iterator.Iterator[string], error)
+
 	// List returns an iterator over all keys.
 	List(ctx context.Context) (*iterator.Iterator[string], error)
 }
 
+// URLSigner is implemented by stores that can hand out URLs granting temporary read
+// access to an object, so that clients can download it directly from the blob store.
+type URLSigner interface {
+	// SignedURL returns a URL to read the object at the given key that is valid for
+	// the given duration.
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// ErrSignedURLsUnsupported is returned by SignedURL if the store or its credentials
+// cannot sign URLs.
+var ErrSignedURLsUnsupported = errors.New("upload store does not support signed URLs")
+
+// SignedURL returns a URL to read the object at the given key that is valid for the
+// given duration, or ErrSignedURLsUnsupported if the store cannot sign URLs.
+func SignedURL(ctx context.Context, store Store, key string, expiry time.Duration) (string, error) {
+	signer, ok := store.(URLSigner)
+	if !ok {
+		return "", ErrSignedURLsUnsupported
+	}
+
+	return signer.SignedURL(ctx, key, expiry)
+}
+
 var storeConstructors = map[string]func(ctx context.Context, config Config, operations *Operations) (Store, error){
 	"s3":        newS3FromConfig,
 	"blobstore": newS3FromConfig,
 	"gcs":       newGCSFromConfig,
+	"azure":     newAzureFromConfig,
 }
 
 // CreateLazy initialize a new store from the given configuration that is initialized
@@ -0,0 +1,187 @@
+package uploadstore
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"cloud.google.com/go/storage"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
+)
+
+// tieredStore writes new objects into a hot store. Objects that have been moved into
+// the cold store (see NewTierer) remain readable through the tiered store.
+type tieredStore struct {
+	hot        Store
+	cold       Store
+	tieringAge time.Duration
+}
+
+var _ Store = &tieredStore{}
+
+// NewTieredStore returns a store that writes to the given hot store and transparently
+// falls back to the given cold store when reading objects that are no longer present
+// in the hot store. The tiering age is the age at which objects are moved into the cold
+// store, and is used to expire cold objects relative to their original creation time.
+func NewTieredStore(hot, cold Store, tieringAge time.Duration) Store {
+	return &tieredStore{hot: hot, cold: cold, tieringAge: tieringAge}
+}
+
+func (s *tieredStore) Init(ctx context.Context) error {
+	if err := s.hot.Init(ctx); err != nil {
+		return err
+	}
+
+	return s.cold.Init(ctx)
+}
+
+func (s *tieredStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	rc, err := s.hot.Get(ctx, key)
+	if err != nil {
+		if !isNotFoundError(err) {
+			return nil, err
+		}
+
+		return s.cold.Get(ctx, key)
+	}
+
+	return &fallbackReader{
+		rc:       rc,
+		fallback: func() (io.ReadCloser, error) { return s.cold.Get(ctx, key) },
+	}, nil
+}
+
+func (s *tieredStore) Upload(ctx context.Context, key string, r io.Reader) (int64, error) {
+	return s.hot.Upload(ctx, key, r)
+}
+
+func (s *tieredStore) Compose(ctx context.Context, destination string, sources ...string) (int64, error) {
+	return s.hot.Compose(ctx, destination, sources...)
+}
+
+// Delete removes the object from both tiers. As the object generally exists in only one
+// tier, errors indicating that the object does not exist are ignored.
+func (s *tieredStore) Delete(ctx context.Context, key string) error {
+	var errs error
+	if err := s.hot.Delete(ctx, key); err != nil && !isNotFoundError(err) {
+		errs = errors.Append(errs, err)
+	}
+	if err := s.cold.Delete(ctx, key); err != nil && !isNotFoundError(err) {
+		errs = errors.Append(errs, err)
+	}
+
+	return errs
+}
+
+// ExpireObjects expires objects in both tiers. An object in the cold store was created
+// when it was moved there at the tiering age, so it is expired once it has been in the
+// cold store for the remainder of the given max age.
+func (s *tieredStore) ExpireObjects(ctx context.Context, prefix string, maxAge time.Duration) error {
+	coldMaxAge := maxAge - s.tieringAge
+	if coldMaxAge < 0 {
+		coldMaxAge = 0
+	}
+
+	return errors.Append(
+		s.hot.ExpireObjects(ctx, prefix, maxAge),
+		s.cold.ExpireObjects(ctx, prefix, coldMaxAge),
+	)
+}
+
+func (s *tieredStore) List(ctx context.Context) (*iterator.Iterator[string], error) {
+	hot, err := s.hot.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cold, err := s.cold.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return concatIterators(hot, cold), nil
+}
+
+func (s *tieredStore) ListOlderThan(ctx context.Context, prefix string, maxAge time.Duration) (*iterator.Iterator[string], error) {
+	hot, err := s.hot.ListOlderThan(ctx, prefix, maxAge)
+	if err != nil {
+		return nil, err
+	}
+	cold, err := s.cold.ListOlderThan(ctx, prefix, maxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	return concatIterators(hot, cold), nil
+}
+
+// fallbackReader reads from the hot store reader. If the very first read fails because
+// the object does not exist, which is how a missing object surfaces for lazily-fetching
+// stores, the reader switches over to a reader from the cold store.
+type fallbackReader struct {
+	rc       io.ReadCloser
+	fallback func() (io.ReadCloser, error)
+	started  bool
+}
+
+func (r *fallbackReader) Read(p []byte) (int, error) {
+	n, err := r.rc.Read(p)
+	if r.started || n > 0 || err == nil || err == io.EOF || !isNotFoundError(err) {
+		r.started = true
+		return n, err
+	}
+	r.started = true
+
+	rc, fallbackErr := r.fallback()
+	if fallbackErr != nil {
+		return 0, errors.Append(err, fallbackErr)
+	}
+
+	_ = r.rc.Close()
+	r.rc = rc
+	return r.rc.Read(p)
+}
+
+func (r *fallbackReader) Close() error {
+	return r.rc.Close()
+}
+
+// isNotFoundError returns true if the given error indicates that the requested object
+// does not exist in the backing store.
+func isNotFoundError(err error) bool {
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return true
+	}
+
+	var noSuchKey *s3types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return true
+	}
+
+	var notFound *s3types.NotFound
+	if errors.As(err, &notFound) {
+		return true
+	}
+
+	var azErr *azureError
+	return errors.As(err, &azErr) && azErr.NotFound()
+}
+
+func concatIterators(its ...*iterator.Iterator[string]) *iterator.Iterator[string] {
+	return iterator.New(func() ([]string, error) {
+		for len(its) > 0 {
+			if its[0].Next() {
+				return []string{its[0].Current()}, nil
+			}
+			if err := its[0].Err(); err != nil {
+				return nil, err
+			}
+
+			its = its[1:]
+		}
+
+		return nil, nil
+	})
+}
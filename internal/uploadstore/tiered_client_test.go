@@ -0,0 +1,278 @@
+package uploadstore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
+)
+
+func TestTieredStoreGet(t *testing.T) {
+	hot := newMemoryStore()
+	cold := newMemoryStore()
+	hot.put("hot-key", "hot content", time.Now())
+	cold.put("cold-key", "cold content", time.Now())
+
+	store := NewTieredStore(hot, cold, time.Hour*72)
+
+	for key, expected := range map[string]string{
+		"hot-key":  "hot content",
+		"cold-key": "cold content",
+	} {
+		rc, err := store.Get(context.Background(), key)
+		if err != nil {
+			t.Fatalf("unexpected error getting %q: %s", key, err)
+		}
+		contents, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatalf("unexpected error reading %q: %s", key, err)
+		}
+		_ = rc.Close()
+
+		if diff := cmp.Diff(expected, string(contents)); diff != "" {
+			t.Errorf("unexpected contents for %q (-want +got):\n%s", key, diff)
+		}
+	}
+
+	rc, err := store.Get(context.Background(), "missing-key")
+	if err != nil {
+		t.Fatalf("unexpected error getting missing key: %s", err)
+	}
+	if _, err := io.ReadAll(rc); err == nil {
+		t.Fatalf("expected error reading missing key")
+	}
+}
+
+func TestTieredStoreGetTransientError(t *testing.T) {
+	hot := newMemoryStore()
+	cold := newMemoryStore()
+	cold.put("key", "stale content", time.Now())
+	hot.getErr = errors.New("connection reset by peer")
+
+	store := NewTieredStore(hot, cold, time.Hour*72)
+
+	rc, err := store.Get(context.Background(), "key")
+	if err != nil {
+		t.Fatalf("unexpected error getting key: %s", err)
+	}
+	defer rc.Close()
+
+	if _, err := io.ReadAll(rc); err == nil || !strings.Contains(err.Error(), "connection reset by peer") {
+		t.Fatalf("expected hot store error to be surfaced, got %v", err)
+	}
+}
+
+func TestTieredStoreAzureHotStore(t *testing.T) {
+	hot := testAzureClient(t, newFakeAzureServer(t), true)
+	cold := newMemoryStore()
+	cold.put("key", "cold content", time.Now())
+
+	store := NewTieredStore(hot, cold, time.Hour*72)
+
+	rc, err := store.Get(context.Background(), "key")
+	if err != nil {
+		t.Fatalf("unexpected error getting key: %s", err)
+	}
+	contents, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("unexpected error reading key: %s", err)
+	}
+	_ = rc.Close()
+	if diff := cmp.Diff("cold content", string(contents)); diff != "" {
+		t.Errorf("unexpected contents (-want +got):\n%s", diff)
+	}
+
+	if err := store.Delete(context.Background(), "key"); err != nil {
+		t.Fatalf("unexpected error deleting key: %s", err)
+	}
+	if keys := cold.keys(); len(keys) != 0 {
+		t.Errorf("unexpected cold keys: %v", keys)
+	}
+}
+
+func TestTieredStoreDelete(t *testing.T) {
+	hot := newMemoryStore()
+	cold := newMemoryStore()
+	cold.put("key", "content", time.Now())
+
+	store := NewTieredStore(hot, cold, time.Hour*72)
+
+	// The object is missing from the hot store
+	if err := store.Delete(context.Background(), "key"); err != nil {
+		t.Fatalf("unexpected error deleting key: %s", err)
+	}
+	if keys := cold.keys(); len(keys) != 0 {
+		t.Errorf("unexpected cold keys: %v", keys)
+	}
+
+	hot.deleteErr = errors.New("access denied")
+	if err := store.Delete(context.Background(), "key"); err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Fatalf("expected hot store delete error to be returned, got %v", err)
+	}
+}
+
+func TestTieredStoreExpireObjects(t *testing.T) {
+	hot := newMemoryStore()
+	cold := newMemoryStore()
+	hot.put("upload-1.lsif.gz", "young", time.Now().Add(-time.Hour*48))
+	hot.put("upload-2.lsif.gz", "old", time.Now().Add(-time.Hour*200))
+	// Moved into cold storage at 72h; now 172h old in total
+	cold.put("upload-3.lsif.gz", "expired", time.Now().Add(-time.Hour*100))
+	// Moved into cold storage at 72h; now 122h old in total
+	cold.put("upload-4.lsif.gz", "retained", time.Now().Add(-time.Hour*50))
+
+	store := NewTieredStore(hot, cold, time.Hour*72)
+	if err := store.ExpireObjects(context.Background(), "upload-", time.Hour*168); err != nil {
+		t.Fatalf("unexpected error expiring objects: %s", err)
+	}
+
+	if diff := cmp.Diff([]string{"upload-1.lsif.gz"}, hot.keys()); diff != "" {
+		t.Errorf("unexpected hot keys (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"upload-4.lsif.gz"}, cold.keys()); diff != "" {
+		t.Errorf("unexpected cold keys (-want +got):\n%s", diff)
+	}
+}
+
+func TestTierer(t *testing.T) {
+	hot := newMemoryStore()
+	cold := newMemoryStore()
+	hot.put("upload-1.lsif.gz", "old upload", time.Now().Add(-time.Hour*96))
+	hot.put("upload-2.lsif.gz", "new upload", time.Now())
+	hot.put("other-3.lsif.gz", "old other", time.Now().Add(-time.Hour*96))
+
+	tierer := &tierer{hot: hot, cold: cold, prefix: "upload-", maxAge: time.Hour * 72}
+	if err := tierer.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error tiering objects: %s", err)
+	}
+
+	if diff := cmp.Diff([]string{"other-3.lsif.gz", "upload-2.lsif.gz"}, hot.keys()); diff != "" {
+		t.Errorf("unexpected hot keys (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"upload-1.lsif.gz"}, cold.keys()); diff != "" {
+		t.Errorf("unexpected cold keys (-want +got):\n%s", diff)
+	}
+}
+
+type memoryObject struct {
+	contents string
+	created  time.Time
+}
+
+// memoryStore is an in-memory Store. Like the S3 store, reads of missing objects fail
+// lazily on the first read rather than on Get.
+type memoryStore struct {
+	mu        sync.Mutex
+	objects   map[string]memoryObject
+	getErr    error
+	deleteErr error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{objects: map[string]memoryObject{}}
+}
+
+func (s *memoryStore) put(key, contents string, created time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = memoryObject{contents: contents, created: created}
+}
+
+func (s *memoryStore) keys() []string {
+	it, _ := s.List(context.Background())
+	var keys []string
+	for it.Next() {
+		keys = append(keys, it.Current())
+	}
+	return keys
+}
+
+func (s *memoryStore) Init(ctx context.Context) error { return nil }
+
+func (s *memoryStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if s.getErr != nil {
+		return io.NopCloser(errorReader{s.getErr}), nil
+	}
+
+	s.mu.Lock()
+	object, ok := s.objects[key]
+	s.mu.Unlock()
+	if !ok {
+		return io.NopCloser(errorReader{errors.Wrapf(storage.ErrObjectNotExist, "object %q", key)}), nil
+	}
+	return io.NopCloser(strings.NewReader(object.contents)), nil
+}
+
+func (s *memoryStore) Upload(ctx context.Context, key string, r io.Reader) (int64, error) {
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, r)
+	if err != nil {
+		return 0, err
+	}
+	s.put(key, buf.String(), time.Now())
+	return n, nil
+}
+
+func (s *memoryStore) Compose(ctx context.Context, destination string, sources ...string) (int64, error) {
+	return 0, errors.New("unimplemented")
+}
+
+func (s *memoryStore) Delete(ctx context.Context, key string) error {
+	if s.deleteErr != nil {
+		return s.deleteErr
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.objects[key]; !ok {
+		return errors.Wrapf(storage.ErrObjectNotExist, "object %q", key)
+	}
+
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *memoryStore) ExpireObjects(ctx context.Context, prefix string, maxAge time.Duration) error {
+	it, err := s.ListOlderThan(ctx, prefix, maxAge)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for it.Next() {
+		delete(s.objects, it.Current())
+	}
+
+	return it.Err()
+}
+
+func (s *memoryStore) List(ctx context.Context) (*iterator.Iterator[string], error) {
+	return s.ListOlderThan(ctx, "", 0)
+}
+
+func (s *memoryStore) ListOlderThan(ctx context.Context, prefix string, maxAge time.Duration) (*iterator.Iterator[string], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for key, object := range s.objects {
+		if strings.HasPrefix(key, prefix) && time.Since(object.created) >= maxAge {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return iterator.From[string](keys), nil
+}
+
+type errorReader struct{ err error }
+
+func (r errorReader) Read(p []byte) (int, error) { return 0, r.err }
@@ -0,0 +1,69 @@
+package uploadstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type tierer struct {
+	hot    Store
+	cold   Store
+	prefix string
+	maxAge time.Duration
+}
+
+// NewTierer returns a background routine that periodically moves objects with the given
+// prefix older than the given max age from the hot store into the cold store. Use
+// NewTieredStore to read objects regardless of the tier in which they are stored.
+func NewTierer(ctx context.Context, hot, cold Store, prefix string, maxAge time.Duration, interval time.Duration) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		&tierer{
+			hot:    hot,
+			cold:   cold,
+			prefix: prefix,
+			maxAge: maxAge,
+		},
+		goroutine.WithName("codeintel.upload-store-tierer"),
+		goroutine.WithDescription("moves aged entries in the code intel upload store to cold storage"),
+		goroutine.WithInterval(interval),
+	)
+}
+
+func (t *tierer) Handle(ctx context.Context) error {
+	it, err := t.hot.ListOlderThan(ctx, t.prefix, t.maxAge)
+	if err != nil {
+		return err
+	}
+
+	for it.Next() {
+		if err := t.move(ctx, it.Current()); err != nil {
+			return err
+		}
+	}
+
+	return it.Err()
+}
+
+// move copies the object at the given key into the cold store, then removes it from the
+// hot store. The object is readable from at least one tier at all times.
+func (t *tierer) move(ctx context.Context, key string) error {
+	rc, err := t.hot.Get(ctx, key)
+	if err != nil {
+		return errors.Wrap(err, "hot.Get")
+	}
+	defer rc.Close()
+
+	if _, err := t.cold.Upload(ctx, key, rc); err != nil {
+		return errors.Wrap(err, "cold.Upload")
+	}
+
+	if err := t.hot.Delete(ctx, key); err != nil {
+		return errors.Wrap(err, "hot.Delete")
+	}
+
+	return nil
+}
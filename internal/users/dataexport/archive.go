@@ -0,0 +1,236 @@
+package dataexport
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// category is a kind of personal data of users stored in a table.
+type category struct {
+	Name string
+	// Description is shown to users in the manifest of the archive.
+	Description string
+
+	Table string
+	// UserColumn is the column of Table referencing the user.
+	UserColumn string
+	// Columns are the exported columns of Table. Secrets, such as password
+	// hashes and tokens, are never exported.
+	Columns string
+
+	// Retained is true if the data is not deleted together with the user,
+	// because it is required to audit the use of the instance.
+	Retained bool
+}
+
+// categories are all kinds of personal data exported for a user. A category
+// must be added for every new table that stores personal data of users.
+var categories = []category{
+	{
+		Name:        "profile",
+		Description: "The profile of the user.",
+		Table:       "users",
+		UserColumn:  "id",
+		Columns:     "id, username, display_name, avatar_url, created_at, updated_at, deleted_at, site_admin, tags, tos_accepted",
+	},
+	{
+		Name:        "emails",
+		Description: "The email addresses of the user.",
+		Table:       "user_emails",
+		UserColumn:  "user_id",
+		Columns:     "email, is_primary, created_at, verified_at",
+	},
+	{
+		Name:        "external_accounts",
+		Description: "The accounts on code hosts and authentication providers linked to the user.",
+		Table:       "user_external_accounts",
+		UserColumn:  "user_id",
+		// The account data is only exported when it is not encrypted. The
+		// authentication data contains credentials and is never exported.
+		Columns: "id, service_type, service_id, account_id, CASE WHEN encryption_key_id = '' THEN account_data END AS account_data, created_at, updated_at, deleted_at",
+	},
+	{
+		Name:        "saved_searches",
+		Description: "The saved searches of the user.",
+		Table:       "saved_searches",
+		UserColumn:  "user_id",
+		Columns:     "id, description, query, notify_owner, notify_slack, created_at, updated_at",
+	},
+	{
+		Name:        "batch_changes",
+		Description: "The batch changes created by the user.",
+		Table:       "batch_changes",
+		UserColumn:  "creator_id",
+		Columns:     "id, name, description, created_at, updated_at, closed_at, last_applied_at",
+	},
+	{
+		Name:        "audit_events",
+		Description: "The security events of the audit log caused by the user.",
+		Table:       "security_event_logs",
+		UserColumn:  "user_id",
+		Columns:     "id, name, url, source, argument, version, timestamp",
+		Retained:    true,
+	},
+}
+
+// manifest describes the contents of an archive.
+type manifest struct {
+	UserID      int32              `json:"userID"`
+	GeneratedAt time.Time          `json:"generatedAt"`
+	Categories  []manifestCategory `json:"categories"`
+}
+
+type manifestCategory struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	File        string `json:"file"`
+	Records     int    `json:"records"`
+}
+
+// writeArchive writes a zip archive containing one JSON file per category
+// with all records of the user, and a manifest.json describing them.
+func (s *store) writeArchive(ctx context.Context, userID int32, now time.Time, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	m := manifest{UserID: userID, GeneratedAt: now}
+	for _, c := range categories {
+		file := c.Name + ".json"
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: file, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+		n, err := s.writeRecords(ctx, c, userID, f)
+		if err != nil {
+			return errors.Wrapf(err, "exporting %s", c.Name)
+		}
+		m.Categories = append(m.Categories, manifestCategory{
+			Name:        c.Name,
+			Description: c.Description,
+			File:        file,
+			Records:     n,
+		})
+	}
+
+	f, err := zw.CreateHeader(&zip.FileHeader{Name: "manifest.json", Method: zip.Deflate, Modified: now})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(m); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+const recordsFmtstr = `
+SELECT row_to_json(t) FROM (SELECT %s FROM %s WHERE %s = %s ORDER BY 1) t
+`
+
+// writeRecords writes the records of the category of the user to w as a JSON
+// array, and returns their number.
+func (s *store) writeRecords(ctx context.Context, c category, userID int32, w io.Writer) (_ int, err error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(
+		recordsFmtstr,
+		sqlf.Sprintf(c.Columns),
+		sqlf.Sprintf(c.Table),
+		sqlf.Sprintf(c.UserColumn),
+		userID,
+	))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { err = errors.Append(err, rows.Close()) }()
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return 0, err
+	}
+	n := 0
+	for rows.Next() {
+		var record json.RawMessage
+		if err := rows.Scan(&record); err != nil {
+			return 0, err
+		}
+		sep := ",\n"
+		if n == 0 {
+			sep = "\n"
+		}
+		if _, err := io.WriteString(w, sep); err != nil {
+			return 0, err
+		}
+		if _, err := w.Write(record); err != nil {
+			return 0, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if _, err := io.WriteString(w, "\n]\n"); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// deletionReport certifies that the personal data of a deleted user has been
+// deleted, except for the data that is retained for auditing.
+type deletionReport struct {
+	UserID      int32     `json:"userID"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Certified is true if no records of the user remain in categories that are
+	// not retained.
+	Certified  bool                     `json:"certified"`
+	Categories []deletionReportCategory `json:"categories"`
+	// DeletedExports are the archives of earlier exports of the user that were
+	// deleted from the upload store.
+	DeletedExports []string `json:"deletedExports"`
+}
+
+type deletionReportCategory struct {
+	Name             string `json:"name"`
+	Retained         bool   `json:"retained"`
+	RemainingRecords int    `json:"remainingRecords"`
+}
+
+const remainingRecordsFmtstr = `
+SELECT COUNT(*) FROM %s WHERE %s = %s
+`
+
+// newDeletionReport counts the records of the user that remain in every
+// category.
+func (s *store) newDeletionReport(ctx context.Context, userID int32, deletedExports []string, now time.Time) (*deletionReport, error) {
+	r := &deletionReport{
+		UserID:         userID,
+		GeneratedAt:    now,
+		Certified:      true,
+		DeletedExports: deletedExports,
+	}
+	for _, c := range categories {
+		n, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(
+			remainingRecordsFmtstr,
+			sqlf.Sprintf(c.Table),
+			sqlf.Sprintf(c.UserColumn),
+			userID,
+		)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "counting %s", c.Name)
+		}
+		if n > 0 && !c.Retained {
+			r.Certified = false
+		}
+		r.Categories = append(r.Categories, deletionReportCategory{
+			Name:             c.Name,
+			Retained:         c.Retained,
+			RemainingRecords: n,
+		})
+	}
+	return r, nil
+}
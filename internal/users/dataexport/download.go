@@ -0,0 +1,62 @@
+package dataexport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/signedlink"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DownloadURL returns a link to download the archive or report of the
+// completed job, signed with the secret of the job and valid until expires. The
+// link must only be handed to the user or a site admin, and only works for them.
+func DownloadURL(externalURL *url.URL, job *Job, expires time.Time) string {
+	return signedlink.URL(externalURL, "/.api/users/data-export", job.ID, job.SigningSecret, expires)
+}
+
+// NewDownloadHandler returns the handler serving the signed download links of
+// user data exports and deletion reports created by DownloadURL.
+func NewDownloadHandler(logger log.Logger, db database.DB, uploadStore uploadstore.Store) http.Handler {
+	return signedlink.NewHandler(logger, "user data export", uploadStore, downloadLookup(db, NewStore(db)))
+}
+
+func downloadLookup(db database.DB, store Store) signedlink.LookupFunc {
+	return func(ctx context.Context, id int) (*signedlink.Object, error) {
+		job, err := store.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return nil, signedlink.ErrNotFound
+			}
+			return nil, err
+		}
+		// 🚨 SECURITY: Even with a valid link, only the user and site admins can
+		// download the data of the user. Deletion reports are only available to
+		// site admins, since the user does not exist anymore.
+		if err := auth.CheckSiteAdminOrSameUser(ctx, db, job.UserID); err != nil {
+			return nil, signedlink.ErrNotFound
+		}
+
+		object := &signedlink.Object{
+			ID:            job.ID,
+			SigningSecret: job.SigningSecret,
+			ContentType:   "application/zip",
+			Filename:      fmt.Sprintf("user-data-export-%d.zip", job.ID),
+		}
+		if job.Kind == KindDeletionReport {
+			object.ContentType, object.Filename = "application/json", fmt.Sprintf("user-deletion-report-%d.json", job.ID)
+		}
+		if job.ObjectKey != nil {
+			object.ObjectKey = *job.ObjectKey
+		}
+		return object, nil
+	}
+}
@@ -0,0 +1,67 @@
+package dataexport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/signedlink"
+)
+
+type fakeStore struct {
+	Store
+	jobs map[int]*Job
+}
+
+func (s *fakeStore) GetByID(_ context.Context, id int) (*Job, error) {
+	if job, ok := s.jobs[id]; ok {
+		return job, nil
+	}
+	return nil, ErrNotFound
+}
+
+func TestDownloadLookup(t *testing.T) {
+	exportKey, reportKey := "user-data-exports/1.zip", "user-deletion-reports/2.json"
+	export := &Job{ID: 1, UserID: 1, Kind: KindExport, ObjectKey: &exportKey, SigningSecret: []byte("secret")}
+	report := &Job{ID: 2, UserID: 3, Kind: KindDeletionReport, ObjectKey: &reportKey, SigningSecret: []byte("secret")}
+
+	users := database.NewMockUserStore()
+	users.GetByIDFunc.SetDefaultHook(func(_ context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, SiteAdmin: id == 2}, nil
+	})
+	db := database.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+
+	lookup := downloadLookup(db, &fakeStore{jobs: map[int]*Job{1: export, 2: report}})
+	as := func(userID int32) context.Context {
+		return actor.WithActor(context.Background(), actor.FromUser(userID))
+	}
+
+	// The user can download their own export.
+	object, err := lookup(as(1), 1)
+	require.NoError(t, err)
+	require.Equal(t, exportKey, object.ObjectKey)
+	require.Equal(t, "application/zip", object.ContentType)
+	require.Equal(t, "user-data-export-1.zip", object.Filename)
+
+	// Site admins can download exports and deletion reports.
+	_, err = lookup(as(2), 1)
+	require.NoError(t, err)
+	object, err = lookup(as(2), 2)
+	require.NoError(t, err)
+	require.Equal(t, "application/json", object.ContentType)
+	require.Equal(t, "user-deletion-report-2.json", object.Filename)
+
+	// Other users and anonymous visitors cannot, even with a valid link.
+	_, err = lookup(as(4), 1)
+	require.ErrorIs(t, err, signedlink.ErrNotFound)
+	_, err = lookup(context.Background(), 1)
+	require.ErrorIs(t, err, signedlink.ErrNotFound)
+
+	_, err = lookup(as(1), 3)
+	require.ErrorIs(t, err, signedlink.ErrNotFound)
+}
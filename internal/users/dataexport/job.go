@@ -0,0 +1,116 @@
+package dataexport
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/executor"
+)
+
+const tableName = "user_data_export_jobs"
+
+// Kind is the kind of a job.
+type Kind string
+
+const (
+	// KindExport exports all personal data of a user into an archive.
+	KindExport Kind = "export"
+	// KindDeletionReport certifies that the personal data of a deleted user has
+	// been deleted.
+	KindDeletionReport Kind = "deletion_report"
+)
+
+// Job exports the personal data of a user, or reports on its deletion, and
+// uploads the result to the upload store.
+type Job struct {
+	ID              int
+	State           string
+	FailureMessage  *string
+	QueuedAt        time.Time
+	StartedAt       *time.Time
+	FinishedAt      *time.Time
+	ProcessAfter    *time.Time
+	NumResets       int
+	NumFailures     int
+	LastHeartbeatAt time.Time
+	ExecutionLogs   []executor.ExecutionLogEntry
+	WorkerHostname  string
+
+	// UserID is the user whose data is exported. The user does not exist
+	// anymore once their deletion is reported.
+	UserID int32
+	Kind   Kind
+	// RequestedBy is the user who requested the job, or nil if it was enqueued
+	// by Sourcegraph or the user has since been deleted.
+	RequestedBy *int32
+	// ObjectKey is the key of the archive or report in the upload store, or nil
+	// until the job completed. The key of an archive is cleared again once the
+	// user is deleted.
+	ObjectKey *string
+	// SigningSecret signs the download links of the archive or report.
+	SigningSecret []byte
+	CreatedAt     time.Time
+}
+
+func (j *Job) RecordID() int {
+	return j.ID
+}
+
+func (j *Job) RecordUID() string {
+	return strconv.Itoa(j.ID)
+}
+
+var jobColumns = []*sqlf.Query{
+	sqlf.Sprintf("id"),
+	sqlf.Sprintf("state"),
+	sqlf.Sprintf("failure_message"),
+	sqlf.Sprintf("queued_at"),
+	sqlf.Sprintf("started_at"),
+	sqlf.Sprintf("finished_at"),
+	sqlf.Sprintf("process_after"),
+	sqlf.Sprintf("num_resets"),
+	sqlf.Sprintf("num_failures"),
+	sqlf.Sprintf("last_heartbeat_at"),
+	sqlf.Sprintf("execution_logs"),
+	sqlf.Sprintf("worker_hostname"),
+	sqlf.Sprintf("user_id"),
+	sqlf.Sprintf("kind"),
+	sqlf.Sprintf("requested_by"),
+	sqlf.Sprintf("object_key"),
+	sqlf.Sprintf("signing_secret"),
+	sqlf.Sprintf("created_at"),
+}
+
+func scanJob(s dbutil.Scanner) (*Job, error) {
+	var job Job
+	var executionLogs []executor.ExecutionLogEntry
+
+	if err := s.Scan(
+		&job.ID,
+		&job.State,
+		&job.FailureMessage,
+		&job.QueuedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+		&job.ProcessAfter,
+		&job.NumResets,
+		&job.NumFailures,
+		&dbutil.NullTime{Time: &job.LastHeartbeatAt},
+		pq.Array(&executionLogs),
+		&job.WorkerHostname,
+		&job.UserID,
+		&job.Kind,
+		&job.RequestedBy,
+		&job.ObjectKey,
+		&job.SigningSecret,
+		&job.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	job.ExecutionLogs = append(job.ExecutionLogs, executionLogs...)
+	return &job, nil
+}
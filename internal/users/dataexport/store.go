@@ -0,0 +1,165 @@
+package dataexport
+
+import (
+	"context"
+	"crypto/rand"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var (
+	// ErrNotFound is returned when a job does not exist.
+	ErrNotFound = errors.New("user data export not found")
+	// ErrExportInProgress is returned when enqueueing the export of a user whose
+	// data is already being exported.
+	ErrExportInProgress = errors.New("the data of the user is already being exported")
+)
+
+// Store enqueues and lists the data exports and deletion reports of users.
+type Store interface {
+	// EnqueueExport enqueues the export of all personal data of the user,
+	// requested by the user requestedBy. ErrExportInProgress is returned if an
+	// export of the user is already queued or running.
+	EnqueueExport(ctx context.Context, userID, requestedBy int32) (*Job, error)
+
+	// EnqueueDeletionReport enqueues the report on the deletion of the personal
+	// data of the user, once the user has been permanently deleted. The report
+	// also deletes the archives of earlier exports of the user.
+	EnqueueDeletionReport(ctx context.Context, userID int32, requestedBy *int32) (*Job, error)
+
+	// GetByID returns the job with the given ID, or ErrNotFound.
+	GetByID(ctx context.Context, id int) (*Job, error)
+
+	// ListByUser returns the most recently enqueued jobs of the user.
+	ListByUser(ctx context.Context, userID int32, limit int) ([]*Job, error)
+}
+
+type store struct {
+	*basestore.Store
+}
+
+// NewStore returns a Store backed by the given database.
+func NewStore(db database.DB) Store {
+	return &store{Store: basestore.NewWithHandle(db.Handle())}
+}
+
+const enqueueFmtstr = `
+INSERT INTO user_data_export_jobs (user_id, kind, requested_by, signing_secret)
+SELECT %s, %s, %s, %s
+WHERE %s
+RETURNING id
+`
+
+const noActiveExportCondFmtstr = `
+NOT EXISTS (
+	SELECT 1 FROM user_data_export_jobs
+	WHERE user_id = %s AND kind = 'export' AND state IN ('queued', 'processing', 'errored')
+)
+`
+
+func (s *store) EnqueueExport(ctx context.Context, userID, requestedBy int32) (*Job, error) {
+	job, err := s.enqueue(ctx, userID, KindExport, &requestedBy, sqlf.Sprintf(noActiveExportCondFmtstr, userID))
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrExportInProgress
+	}
+	return job, nil
+}
+
+func (s *store) EnqueueDeletionReport(ctx context.Context, userID int32, requestedBy *int32) (*Job, error) {
+	return s.enqueue(ctx, userID, KindDeletionReport, requestedBy, sqlf.Sprintf("TRUE"))
+}
+
+// enqueue enqueues a job if cond holds, and returns nil otherwise.
+func (s *store) enqueue(ctx context.Context, userID int32, kind Kind, requestedBy *int32, cond *sqlf.Query) (*Job, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	id, ok, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(
+		enqueueFmtstr,
+		userID, kind, requestedBy, secret,
+		cond,
+	)))
+	if err != nil || !ok {
+		return nil, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+func (s *store) GetByID(ctx context.Context, id int) (*Job, error) {
+	jobs, err := s.list(ctx, sqlf.Sprintf("id = %s", id), 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, ErrNotFound
+	}
+	return jobs[0], nil
+}
+
+func (s *store) ListByUser(ctx context.Context, userID int32, limit int) ([]*Job, error) {
+	return s.list(ctx, sqlf.Sprintf("user_id = %s", userID), limit)
+}
+
+const listFmtstr = `
+SELECT %s
+FROM user_data_export_jobs
+WHERE %s
+ORDER BY id DESC
+%s
+`
+
+func (s *store) list(ctx context.Context, cond *sqlf.Query, limit int) ([]*Job, error) {
+	limitClause := sqlf.Sprintf("")
+	if limit > 0 {
+		limitClause = sqlf.Sprintf("LIMIT %s", limit)
+	}
+	return basestore.NewSliceScanner(scanJob)(s.Query(ctx, sqlf.Sprintf(
+		listFmtstr,
+		sqlf.Join(jobColumns, ","),
+		cond,
+		limitClause,
+	)))
+}
+
+const setResultFmtstr = `
+UPDATE user_data_export_jobs SET object_key = %s WHERE id = %s
+`
+
+// setResult records the upload of the archive or report of the job.
+func (s *store) setResult(ctx context.Context, id int, objectKey string) error {
+	return s.Exec(ctx, sqlf.Sprintf(setResultFmtstr, objectKey, id))
+}
+
+const exportObjectKeysFmtstr = `
+SELECT object_key FROM user_data_export_jobs
+WHERE user_id = %s AND kind = 'export' AND object_key IS NOT NULL AND finished_at > %s
+ORDER BY id
+`
+
+// exportObjectKeys returns the keys of the archives of the exports of the
+// user that finished after the given time. Older archives have already been
+// deleted from the upload store by the expirer.
+func (s *store) exportObjectKeys(ctx context.Context, userID int32, finishedAfter time.Time) ([]string, error) {
+	return basestore.ScanStrings(s.Query(ctx, sqlf.Sprintf(exportObjectKeysFmtstr, userID, finishedAfter)))
+}
+
+const clearExportObjectKeysFmtstr = `
+UPDATE user_data_export_jobs SET object_key = NULL
+WHERE user_id = %s AND kind = 'export'
+`
+
+// clearExportObjectKeys records the deletion of the archives of all exports of
+// the user from the upload store.
+func (s *store) clearExportObjectKeys(ctx context.Context, userID int32) error {
+	return s.Exec(ctx, sqlf.Sprintf(clearExportObjectKeysFmtstr, userID))
+}
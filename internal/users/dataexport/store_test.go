@@ -0,0 +1,62 @@
+package dataexport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+
+	user, err := db.Users().Create(ctx, database.NewUser{Username: "alice"})
+	require.NoError(t, err)
+	admin, err := db.Users().Create(ctx, database.NewUser{Username: "admin"})
+	require.NoError(t, err)
+
+	s := NewStore(db)
+
+	job, err := s.EnqueueExport(ctx, user.ID, admin.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "queued", job.State)
+	assert.Equal(t, user.ID, job.UserID)
+	assert.Equal(t, KindExport, job.Kind)
+	require.NotNil(t, job.RequestedBy)
+	assert.Equal(t, admin.ID, *job.RequestedBy)
+	assert.Nil(t, job.ObjectKey)
+	assert.Len(t, job.SigningSecret, 32)
+
+	_, err = s.EnqueueExport(ctx, user.ID, user.ID)
+	require.ErrorIs(t, err, ErrExportInProgress)
+
+	report, err := s.EnqueueDeletionReport(ctx, user.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, KindDeletionReport, report.Kind)
+	assert.Nil(t, report.RequestedBy)
+
+	require.NoError(t, s.(*store).setResult(ctx, job.ID, "user-data-exports/1.zip"))
+	job, err = s.GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, job.ObjectKey)
+	assert.Equal(t, "user-data-exports/1.zip", *job.ObjectKey)
+
+	jobs, err := s.ListByUser(ctx, user.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, report.ID, jobs[0].ID)
+
+	_, err = s.GetByID(ctx, 12345)
+	require.ErrorIs(t, err, ErrNotFound)
+}
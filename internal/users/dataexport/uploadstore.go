@@ -0,0 +1,19 @@
+package dataexport
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/signedlink"
+)
+
+// UploadStoreConfigInst configures the upload store that user data exports and
+// deletion reports are written to by the worker and downloaded from through the
+// frontend. It is loaded by the services that read or write user data exports.
+var UploadStoreConfigInst = signedlink.NewUploadStoreConfig("USER_DATA_EXPORTS", "user data exports and deletion reports", "user-data-exports")
+
+// NewUploadStore returns the upload store configured by conf.
+func NewUploadStore(ctx context.Context, observationCtx *observation.Context, conf *signedlink.UploadStoreConfig) (uploadstore.Store, error) {
+	return signedlink.NewUploadStore(ctx, observationCtx, conf, "user_data_exports")
+}
@@ -0,0 +1,168 @@
+package dataexport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var retention = env.MustGetDuration("USER_DATA_EXPORTS_RETENTION", 7*24*time.Hour, "The time user data exports are kept in the upload store.")
+
+const (
+	// exportObjectKeyPrefix is the prefix of the keys of all archives in the
+	// upload store. Archives are deleted after USER_DATA_EXPORTS_RETENTION.
+	exportObjectKeyPrefix = "user-data-exports/"
+	// deletionReportObjectKeyPrefix is the prefix of the keys of all deletion
+	// reports in the upload store. Deletion reports are kept indefinitely, as
+	// they are evidence of the deletion.
+	deletionReportObjectKeyPrefix = "user-deletion-reports/"
+)
+
+// NewWorker returns the routines running user data exports: a worker
+// processing the jobs enqueued through the Store, its resetter, and an expirer
+// deleting archives older than USER_DATA_EXPORTS_RETENTION from the upload
+// store.
+func NewWorker(ctx context.Context, observationCtx *observation.Context, db database.DB, uploadStore uploadstore.Store) []goroutine.BackgroundRoutine {
+	workerStore := dbworkerstore.New(observationCtx, db.Handle(), dbworkerstore.Options[*Job]{
+		Name:              "user_data_exports_worker_store",
+		TableName:         tableName,
+		ColumnExpressions: jobColumns,
+		Scan:              dbworkerstore.BuildWorkerScan(scanJob),
+		OrderByExpression: sqlf.Sprintf("id"),
+		MaxNumResets:      5,
+		StalledMaxAge:     time.Minute,
+		RetryAfter:        time.Minute,
+		MaxNumRetries:     3,
+	})
+
+	h := &handler{
+		store:       &store{Store: basestore.NewWithHandle(db.Handle())},
+		uploadStore: uploadStore,
+		now:         time.Now,
+	}
+
+	worker := dbworker.NewWorker[*Job](ctx, workerStore, h, workerutil.WorkerOptions{
+		Name:              "user_data_exports_worker",
+		Description:       "exports the personal data of users and reports on its deletion",
+		NumHandlers:       1,
+		Interval:          10 * time.Second,
+		HeartbeatInterval: 15 * time.Second,
+		Metrics:           workerutil.NewMetrics(observationCtx, "user_data_exports_worker"),
+	})
+
+	resetter := dbworker.NewResetter(observationCtx.Logger.Scoped("resetter", ""), workerStore, dbworker.ResetterOptions{
+		Name:     "user_data_exports_worker_resetter",
+		Interval: time.Minute,
+		Metrics:  dbworker.NewResetterMetrics(observationCtx, "user_data_exports_worker"),
+	})
+
+	expirer := uploadstore.NewExpirer(ctx, uploadStore, exportObjectKeyPrefix, retention, time.Hour)
+
+	return []goroutine.BackgroundRoutine{worker, resetter, expirer}
+}
+
+type handler struct {
+	store       *store
+	uploadStore uploadstore.Store
+	now         func() time.Time
+}
+
+var _ workerutil.Handler[*Job] = &handler{}
+
+func (h *handler) Handle(ctx context.Context, logger log.Logger, job *Job) error {
+	switch job.Kind {
+	case KindExport:
+		key := fmt.Sprintf("%s%d.zip", exportObjectKeyPrefix, job.ID)
+		if err := h.export(ctx, job, key); err != nil {
+			return err
+		}
+		logger.Info("exported user data", log.Int32("user", job.UserID), log.String("key", key))
+		return h.store.setResult(ctx, job.ID, key)
+
+	case KindDeletionReport:
+		key := fmt.Sprintf("%s%d.json", deletionReportObjectKeyPrefix, job.ID)
+		report, err := h.reportDeletion(ctx, job, key)
+		if err != nil {
+			return err
+		}
+		logger.Info("reported user data deletion", log.Int32("user", job.UserID), log.Bool("certified", report.Certified), log.String("key", key))
+		return h.store.setResult(ctx, job.ID, key)
+
+	default:
+		return errcode.MakeNonRetryable(errors.Newf("unknown user data export kind %q", job.Kind))
+	}
+}
+
+// export uploads the archive of the personal data of the user of the job to
+// the upload store at key.
+func (h *handler) export(ctx context.Context, job *Job, key string) error {
+	pr, pw := io.Pipe()
+	uploadErr := make(chan error, 1)
+	go func() {
+		_, err := h.uploadStore.Upload(ctx, key, pr)
+		// Unblocks writeArchive if the upload failed
+		pr.CloseWithError(err)
+		uploadErr <- err
+	}()
+
+	err := h.store.writeArchive(ctx, job.UserID, h.now(), pw)
+	// A nil error completes the upload
+	pw.CloseWithError(err)
+	if uerr := <-uploadErr; err == nil && uerr != nil {
+		err = errors.Wrap(uerr, "upload")
+	}
+	return err
+}
+
+// reportDeletion deletes the archives of earlier exports of the user of the job
+// from the upload store, and uploads a report on the personal data of the user
+// remaining in the database to the upload store at key.
+func (h *handler) reportDeletion(ctx context.Context, job *Job, key string) (*deletionReport, error) {
+	now := h.now()
+
+	keys, err := h.store.exportObjectKeys(ctx, job.UserID, now.Add(-retention))
+	if err != nil {
+		return nil, err
+	}
+	deleted := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if err := h.uploadStore.Delete(ctx, k); err != nil {
+			return nil, errors.Wrapf(err, "deleting export %s", k)
+		}
+		deleted = append(deleted, k)
+	}
+	if err := h.store.clearExportObjectKeys(ctx, job.UserID); err != nil {
+		return nil, err
+	}
+
+	report, err := h.store.newDeletionReport(ctx, job.UserID, deleted, now)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.uploadStore.Upload(ctx, key, bytes.NewReader(b)); err != nil {
+		return nil, errors.Wrap(err, "upload")
+	}
+	return report, nil
+}
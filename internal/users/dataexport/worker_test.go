@@ -0,0 +1,139 @@
+package dataexport
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+)
+
+func TestHandler(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+	now := time.Now()
+
+	user, err := db.Users().Create(ctx, database.NewUser{
+		Username:        "alice",
+		Email:           "alice@example.com",
+		EmailIsVerified: true,
+		Password:        "hunter2hunter2",
+	})
+	require.NoError(t, err)
+	_, err = db.SavedSearches().Create(ctx, &types.SavedSearch{Description: "todos", Query: "TODO", UserID: &user.ID})
+	require.NoError(t, err)
+
+	s := NewStore(db)
+	// Security events are written to the audit log by default.
+	err = s.(*store).Exec(ctx, sqlf.Sprintf(
+		"INSERT INTO security_event_logs (name, url, user_id, anonymous_user_id, source, argument, version, timestamp) VALUES ('PasswordChanged', '', %s, '', 'BACKEND', '{}', 'dev', %s)",
+		user.ID, now,
+	))
+	require.NoError(t, err)
+
+	uploaded := map[string][]byte{}
+	uploadStore := mocks.NewMockStore()
+	uploadStore.UploadFunc.SetDefaultHook(func(_ context.Context, key string, r io.Reader) (int64, error) {
+		b, err := io.ReadAll(r)
+		uploaded[key] = b
+		return int64(len(b)), err
+	})
+	h := &handler{
+		store:       &store{Store: basestore.NewWithHandle(db.Handle())},
+		uploadStore: uploadStore,
+		now:         func() time.Time { return now },
+	}
+
+	exportJob, err := s.EnqueueExport(ctx, user.ID, user.ID)
+	require.NoError(t, err)
+	require.NoError(t, h.Handle(ctx, logger, exportJob))
+	// The worker store marks the job as completed.
+	err = h.store.Exec(ctx, sqlf.Sprintf("UPDATE user_data_export_jobs SET state = 'completed', finished_at = %s WHERE id = %s", now, exportJob.ID))
+	require.NoError(t, err)
+
+	t.Run("export", func(t *testing.T) {
+		archive := uploaded["user-data-exports/1.zip"]
+		require.NotEmpty(t, archive)
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		require.NoError(t, err)
+
+		files := map[string][]byte{}
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			files[f.Name], err = io.ReadAll(rc)
+			require.NoError(t, err)
+			rc.Close()
+		}
+
+		var m manifest
+		require.NoError(t, json.Unmarshal(files["manifest.json"], &m))
+		require.Len(t, m.Categories, len(categories))
+		records := map[string]int{}
+		for _, c := range m.Categories {
+			records[c.Name] = c.Records
+
+			var rows []map[string]any
+			require.NoError(t, json.Unmarshal(files[c.File], &rows), c.File)
+			assert.Len(t, rows, c.Records, c.Name)
+		}
+		assert.Equal(t, map[string]int{
+			"profile":           1,
+			"emails":            1,
+			"external_accounts": 0,
+			"saved_searches":    1,
+			"batch_changes":     0,
+			"audit_events":      1,
+		}, records)
+
+		assert.Contains(t, string(files["profile.json"]), `"username":"alice"`)
+		assert.NotContains(t, string(files["profile.json"]), "passwd")
+	})
+
+	t.Run("deletion report", func(t *testing.T) {
+		require.NoError(t, db.Users().HardDelete(ctx, user.ID))
+
+		reportJob, err := s.EnqueueDeletionReport(ctx, user.ID, nil)
+		require.NoError(t, err)
+		require.NoError(t, h.Handle(ctx, logger, reportJob))
+
+		deleted := uploadStore.DeleteFunc.History()
+		require.Len(t, deleted, 1)
+		assert.Equal(t, "user-data-exports/1.zip", deleted[0].Arg1)
+
+		var report deletionReport
+		require.NoError(t, json.Unmarshal(uploaded["user-deletion-reports/2.json"], &report))
+		assert.True(t, report.Certified)
+		assert.Equal(t, []string{"user-data-exports/1.zip"}, report.DeletedExports)
+		for _, c := range report.Categories {
+			if c.Name == "audit_events" {
+				assert.True(t, c.Retained)
+				assert.Equal(t, 1, c.RemainingRecords)
+			} else {
+				assert.Zero(t, c.RemainingRecords, c.Name)
+			}
+		}
+
+		job, err := s.GetByID(ctx, exportJob.ID)
+		require.NoError(t, err)
+		assert.Nil(t, job.ObjectKey, "the archive of the export was deleted")
+	})
+}
@@ -0,0 +1,48 @@
+package outbound
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// PostJSONTimeout is how long PostJSON waits for the receiver to respond.
+const PostJSONTimeout = 30 * time.Second
+
+// PostJSON sends the payload marshalled to JSON to the given URL. It is used by
+// the notifications of features that post to user-configured webhooks, such as
+// Slack incoming webhooks, and returns an error if the receiver does not respond
+// with a 2xx status code.
+func PostJSON(ctx context.Context, doer httpcli.Doer, url string, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "marshal failed")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, PostJSONTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(raw))
+	if err != nil {
+		return errors.Wrap(err, "failed new request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := doer.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to post webhook")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("unexpected status code %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
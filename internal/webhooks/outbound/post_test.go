@@ -0,0 +1,31 @@
+package outbound
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostJSON(t *testing.T) {
+	var got map[string]string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		if got["fail"] != "" {
+			http.Error(w, "boom", http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer s.Close()
+
+	assert.NoError(t, PostJSON(context.Background(), http.DefaultClient, s.URL, map[string]string{"text": "hello"}))
+	assert.Equal(t, map[string]string{"text": "hello"}, got)
+
+	err := PostJSON(context.Background(), http.DefaultClient, s.URL, map[string]string{"fail": "yes"})
+	assert.ErrorContains(t, err, "unexpected status code 502: boom")
+}
@@ -0,0 +1,56 @@
+package outbound
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers set on every outbound webhook request.
+const (
+	// HeaderEventType is the event type of the payload.
+	HeaderEventType = "X-Sourcegraph-Webhook-Event-Type"
+	// HeaderID identifies the event. Retried deliveries of the same event use
+	// the same ID, so receivers can use it to ignore duplicate deliveries.
+	HeaderID = "X-Sourcegraph-Webhook-ID"
+	// HeaderSignature is the hex encoded HMAC-SHA256 of the payload, keyed with
+	// the webhook secret.
+	HeaderSignature = "X-Sourcegraph-Webhook-Signature"
+	// HeaderTimestamp is the time the request was sent, in seconds since the
+	// Unix epoch.
+	HeaderTimestamp = "X-Sourcegraph-Webhook-Timestamp"
+	// HeaderTimestampSignature is the hex encoded HMAC-SHA256 of the timestamp
+	// and the payload, joined by a period and keyed with the webhook secret.
+	// Receivers can use it to reject replayed requests.
+	HeaderTimestampSignature = "X-Sourcegraph-Webhook-Timestamp-Signature"
+)
+
+// Sign returns the signature of the payload sent in the HeaderSignature header.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignWithTimestamp returns the signature of the timestamp and payload sent in
+// the HeaderTimestampSignature header.
+func SignWithTimestamp(secret string, timestamp time.Time, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SetHeaders sets the event type, ID, timestamp, and signature headers of an
+// outbound webhook request.
+func SetHeaders(header http.Header, id int64, eventType, secret string, timestamp time.Time, payload []byte) {
+	header.Set(HeaderEventType, eventType)
+	header.Set(HeaderID, strconv.FormatInt(id, 10))
+	header.Set(HeaderSignature, Sign(secret, payload))
+	header.Set(HeaderTimestamp, strconv.FormatInt(timestamp.Unix(), 10))
+	header.Set(HeaderTimestampSignature, SignWithTimestamp(secret, timestamp, payload))
+}
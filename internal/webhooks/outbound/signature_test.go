@@ -0,0 +1,23 @@
+package outbound
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetHeaders(t *testing.T) {
+	payload := []byte(`{"a":1}`)
+	timestamp := time.Unix(1690000000, 0)
+
+	header := http.Header{}
+	SetHeaders(header, 42, "repo:created", "secret", timestamp, payload)
+
+	assert.Equal(t, "repo:created", header.Get(HeaderEventType))
+	assert.Equal(t, "42", header.Get(HeaderID))
+	assert.Equal(t, "aa9e2e3575f5d7098b6caccd790888c36d5fdb63342a73bada2d6a51747a8494", header.Get(HeaderSignature))
+	assert.Equal(t, "1690000000", header.Get(HeaderTimestamp))
+	assert.Equal(t, "fec68ae044ceddb4aef0654c72f1948b08424b1462cca7f239aba3642fc43a8e", header.Get(HeaderTimestampSignature))
+}
@@ -0,0 +1,38 @@
+package workerutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// CrashStore is an optional extension of the Store interface for stores that count the
+// crashes of records, so that records that repeatedly crash their handler are moved to
+// a dead-letter queue instead of being retried forever.
+type CrashStore[T Record] interface {
+	// MarkCrashed records that the handler of the record crashed with the given failure
+	// message and stack trace. This method returns a boolean flag indicating if the record
+	// crashed too often and was moved to the dead-letter queue.
+	MarkCrashed(ctx context.Context, rec T, failureMessage, stack string) (bool, error)
+}
+
+// PanicError is the error of a handler that panicked.
+type PanicError struct {
+	Value any
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack string
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("handler panicked: %v", e.Value)
+}
+
+// IsPanic returns the PanicError in err's chain, if any.
+func IsPanic(err error) (*PanicError, bool) {
+	var e *PanicError
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
@@ -0,0 +1,93 @@
+// Package cron runs recurring jobs registered in code on cron schedules. Runs are
+// coordinated through the database, so each scheduled run of a job executes once
+// across all instances of a service, and runs missed while no instance was running
+// are caught up according to the catch-up policy of the job.
+package cron
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/cronexpr"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Job is a recurring job.
+type Job struct {
+	// Name identifies the job across restarts and instances. Renaming a job resets
+	// its run history.
+	Name        string
+	Description string
+	// Schedule is a cron expression, such as "*/15 * * * *" or "@daily". Schedules
+	// are evaluated in UTC.
+	Schedule string
+	// CatchUp determines how runs missed while no scheduler was running are handled.
+	CatchUp CatchUpPolicy
+	// Handler runs the job for the given scheduled time.
+	Handler func(ctx context.Context, scheduledAt time.Time) error
+}
+
+// CatchUpPolicy determines how a job handles runs that were missed.
+type CatchUpPolicy int
+
+const (
+	// CatchUpSkip skips missed runs. The job runs again at its next scheduled time.
+	CatchUpSkip CatchUpPolicy = iota
+	// CatchUpOnce runs the job once for any number of missed runs, with the most
+	// recent scheduled time.
+	CatchUpOnce
+	// CatchUpAll runs the job for each missed run, oldest first. At most the
+	// MaxCatchUpRuns most recent missed runs are run.
+	CatchUpAll
+)
+
+// MaxCatchUpRuns is the maximum number of missed runs of a job with the CatchUpAll
+// policy that are caught up.
+const MaxCatchUpRuns = 100
+
+func (j Job) validate() (*cronexpr.Expression, error) {
+	if j.Name == "" {
+		return nil, errors.New("cron job has no name")
+	}
+	if j.Handler == nil {
+		return nil, errors.Newf("cron job %q has no handler", j.Name)
+	}
+	expr, err := cronexpr.Parse(j.Schedule)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cron job %q has an invalid schedule", j.Name)
+	}
+	return expr, nil
+}
+
+// dueRuns returns the scheduled times after last up to now that the job runs
+// according to its catch-up policy, oldest first, and the most recent scheduled
+// time up to now, which is zero if no run is due. Scheduled times older than
+// tolerance are considered missed.
+func dueRuns(expr *cronexpr.Expression, policy CatchUpPolicy, last, now time.Time, tolerance time.Duration) (runs []time.Time, latest time.Time) {
+	var due []time.Time
+	for t := expr.Next(last.UTC()); !t.IsZero() && !t.After(now); t = expr.Next(t) {
+		latest = t
+		if policy == CatchUpAll {
+			if len(due) == MaxCatchUpRuns {
+				due = due[1:]
+			}
+			due = append(due, t)
+		}
+	}
+	if latest.IsZero() {
+		return nil, latest
+	}
+
+	switch policy {
+	case CatchUpAll:
+		return due, latest
+	case CatchUpOnce:
+		return []time.Time{latest}, latest
+	default:
+		if now.Sub(latest) > tolerance {
+			return nil, latest
+		}
+		return []time.Time{latest}, latest
+	}
+}
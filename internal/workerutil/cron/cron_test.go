@@ -0,0 +1,153 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/hashicorp/cronexpr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestDueRuns(t *testing.T) {
+	hourly := cronexpr.MustParse("@hourly")
+	last := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time { return time.Date(2023, 6, 1, hour, minute, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name       string
+		policy     CatchUpPolicy
+		now        time.Time
+		wantRuns   []time.Time
+		wantLatest time.Time
+	}{
+		{name: "not due", policy: CatchUpAll, now: at(10, 59)},
+		{name: "due", policy: CatchUpSkip, now: at(11, 0), wantRuns: []time.Time{at(11, 0)}, wantLatest: at(11, 0)},
+		{name: "skip missed", policy: CatchUpSkip, now: at(13, 30), wantLatest: at(13, 0)},
+		{name: "skip missed but due", policy: CatchUpSkip, now: at(13, 0).Add(10 * time.Second), wantRuns: []time.Time{at(13, 0)}, wantLatest: at(13, 0)},
+		{name: "once", policy: CatchUpOnce, now: at(13, 30), wantRuns: []time.Time{at(13, 0)}, wantLatest: at(13, 0)},
+		{name: "all", policy: CatchUpAll, now: at(13, 30), wantRuns: []time.Time{at(11, 0), at(12, 0), at(13, 0)}, wantLatest: at(13, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs, latest := dueRuns(hourly, tt.policy, last, tt.now, time.Minute)
+			assert.Equal(t, tt.wantRuns, runs)
+			assert.Equal(t, tt.wantLatest, latest)
+		})
+	}
+
+	t.Run("all is capped", func(t *testing.T) {
+		now := last.Add(1000 * time.Hour)
+		runs, latest := dueRuns(hourly, CatchUpAll, last, now, time.Minute)
+		require.Len(t, runs, MaxCatchUpRuns)
+		assert.Equal(t, now, latest)
+		assert.Equal(t, now, runs[len(runs)-1])
+	})
+}
+
+type fakeStore struct {
+	last     map[string]time.Time
+	finished map[string]error
+}
+
+func (s *fakeStore) LastScheduled(_ context.Context, name string) (time.Time, bool, error) {
+	last, ok := s.last[name]
+	return last, ok, nil
+}
+
+func (s *fakeStore) Init(_ context.Context, name string, scheduledAt time.Time) error {
+	if _, ok := s.last[name]; !ok {
+		s.last[name] = scheduledAt
+	}
+	return nil
+}
+
+func (s *fakeStore) Claim(_ context.Context, name string, last, scheduledAt time.Time) (bool, error) {
+	if !s.last[name].Equal(last) {
+		return false, nil
+	}
+	s.last[name] = scheduledAt
+	return true, nil
+}
+
+func (s *fakeStore) Finish(_ context.Context, name string, runErr error) error {
+	s.finished[name] = runErr
+	return nil
+}
+
+func TestRunner(t *testing.T) {
+	start := time.Date(2023, 6, 1, 10, 30, 0, 0, time.UTC)
+	store := &fakeStore{last: map[string]time.Time{}, finished: map[string]error{}}
+	clock := glock.NewMockClockAt(start)
+
+	var runs []time.Time
+	newRunner := func(policy CatchUpPolicy) *runner {
+		job := Job{
+			Name:     "test",
+			Schedule: "@hourly",
+			CatchUp:  policy,
+			Handler: func(_ context.Context, scheduledAt time.Time) error {
+				runs = append(runs, scheduledAt)
+				return nil
+			},
+		}
+		expr, err := job.validate()
+		require.NoError(t, err)
+		return &runner{job: job, expr: expr, store: store, clock: clock}
+	}
+	ctx := context.Background()
+
+	// The first time the job is scheduled, it does not run.
+	r := newRunner(CatchUpAll)
+	require.NoError(t, r.Handle(ctx))
+	assert.Empty(t, runs)
+	assert.Equal(t, start, store.last["test"])
+
+	// It runs at its next scheduled time.
+	clock.SetCurrent(time.Date(2023, 6, 1, 11, 0, 5, 0, time.UTC))
+	require.NoError(t, r.Handle(ctx))
+	require.NoError(t, r.Handle(ctx))
+	assert.Equal(t, []time.Time{time.Date(2023, 6, 1, 11, 0, 0, 0, time.UTC)}, runs)
+
+	// Missed runs are caught up.
+	runs = nil
+	clock.SetCurrent(time.Date(2023, 6, 1, 14, 10, 0, 0, time.UTC))
+	require.NoError(t, r.Handle(ctx))
+	assert.Len(t, runs, 3)
+
+	// With CatchUpSkip, missed runs are skipped.
+	runs = nil
+	clock.SetCurrent(time.Date(2023, 6, 1, 18, 10, 0, 0, time.UTC))
+	r = newRunner(CatchUpSkip)
+	require.NoError(t, r.Handle(ctx))
+	assert.Empty(t, runs)
+	assert.Equal(t, time.Date(2023, 6, 1, 18, 0, 0, 0, time.UTC), store.last["test"])
+
+	// Errors are recorded.
+	r.job.Handler = func(context.Context, time.Time) error { return errors.New("oops") }
+	clock.SetCurrent(time.Date(2023, 6, 1, 19, 0, 1, 0, time.UTC))
+	require.Error(t, r.Handle(ctx))
+	require.Error(t, store.finished["test"])
+}
+
+func TestNewSchedulerValidatesJobs(t *testing.T) {
+	handler := func(context.Context, time.Time) error { return nil }
+	store := &fakeStore{}
+
+	_, err := newScheduler(context.Background(), store, glock.NewMockClock(), Job{Name: "a", Schedule: "@daily", Handler: handler})
+	require.NoError(t, err)
+
+	_, err = newScheduler(context.Background(), store, glock.NewMockClock(), Job{Name: "a", Schedule: "not a schedule", Handler: handler})
+	require.Error(t, err)
+
+	_, err = newScheduler(context.Background(), store, glock.NewMockClock(),
+		Job{Name: "a", Schedule: "@daily", Handler: handler},
+		Job{Name: "a", Schedule: "@hourly", Handler: handler},
+	)
+	require.Error(t, err)
+}
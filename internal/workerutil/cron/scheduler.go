@@ -0,0 +1,118 @@
+package cron
+
+import (
+	"context"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/hashicorp/cronexpr"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// pollInterval is how often schedulers check whether runs of their jobs are due. It
+// bounds how late a run starts, so cron schedules have a resolution of one minute.
+const pollInterval = 15 * time.Second
+
+// NewScheduler returns a background routine that runs the jobs on their schedules.
+// Each job runs in its own goroutine, so a long-running job does not delay the
+// others.
+//
+// The first time a job is scheduled, it runs at its next scheduled time. After that,
+// runs that were missed because no scheduler was running are caught up according to
+// the catch-up policy of the job.
+func NewScheduler(ctx context.Context, db database.DB, jobs ...Job) (goroutine.BackgroundRoutine, error) {
+	return newScheduler(ctx, newStore(db), glock.NewRealClock(), jobs...)
+}
+
+func newScheduler(ctx context.Context, store store, clock glock.Clock, jobs ...Job) (goroutine.BackgroundRoutine, error) {
+	names := make(map[string]struct{}, len(jobs))
+	routines := make(goroutine.CombinedRoutine, 0, len(jobs))
+	for _, job := range jobs {
+		expr, err := job.validate()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := names[job.Name]; ok {
+			return nil, errors.Newf("cron job %q is registered more than once", job.Name)
+		}
+		names[job.Name] = struct{}{}
+
+		r := &runner{job: job, expr: expr, store: store, clock: clock}
+		routines = append(routines, goroutine.NewPeriodicGoroutine(
+			ctx,
+			r,
+			goroutine.WithName("cron."+job.Name),
+			goroutine.WithDescription(job.Description),
+			goroutine.WithInterval(pollInterval),
+		))
+	}
+	return routines, nil
+}
+
+// runner runs the due runs of a single job.
+type runner struct {
+	job   Job
+	expr  *cronexpr.Expression
+	store store
+	clock glock.Clock
+}
+
+var _ goroutine.Handler = &runner{}
+
+func (r *runner) Handle(ctx context.Context) error {
+	now := r.clock.Now().UTC()
+
+	last, ok, err := r.store.LastScheduled(ctx, r.job.Name)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return r.store.Init(ctx, r.job.Name, now)
+	}
+
+	runs, latest := dueRuns(r.expr, r.job.CatchUp, last, now, 2*pollInterval)
+	if latest.IsZero() {
+		return nil
+	}
+
+	if r.job.CatchUp == CatchUpAll {
+		// Claim the runs one by one, so that runs are not lost if this scheduler
+		// stops while catching up.
+		for _, scheduledAt := range runs {
+			claimed, err := r.store.Claim(ctx, r.job.Name, last, scheduledAt)
+			if err != nil || !claimed {
+				return err
+			}
+			if err := r.run(ctx, scheduledAt); err != nil {
+				return err
+			}
+			last = scheduledAt
+		}
+		return nil
+	}
+
+	// Claim the latest scheduled time, skipping the runs before it.
+	claimed, err := r.store.Claim(ctx, r.job.Name, last, latest)
+	if err != nil || !claimed {
+		return err
+	}
+	for _, scheduledAt := range runs {
+		if err := r.run(ctx, scheduledAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run runs the job and records its outcome. The error of the job is returned to be
+// logged by the periodic goroutine.
+func (r *runner) run(ctx context.Context, scheduledAt time.Time) error {
+	runErr := r.job.Handler(ctx, scheduledAt)
+	if err := r.store.Finish(ctx, r.job.Name, runErr); err != nil {
+		return errors.Append(runErr, err)
+	}
+	return runErr
+}
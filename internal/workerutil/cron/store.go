@@ -0,0 +1,78 @@
+package cron
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+// store records the runs of cron jobs.
+type store interface {
+	// LastScheduled returns the scheduled time of the last claimed run of the job. It
+	// returns false if the job has never been scheduled.
+	LastScheduled(ctx context.Context, name string) (time.Time, bool, error)
+
+	// Init records the job as scheduled at the given time, unless it has been
+	// scheduled before.
+	Init(ctx context.Context, name string, scheduledAt time.Time) error
+
+	// Claim advances the last scheduled time of the job from last to scheduledAt. It
+	// returns false if another scheduler advanced it first.
+	Claim(ctx context.Context, name string, last, scheduledAt time.Time) (bool, error)
+
+	// Finish records the outcome of the last run of the job.
+	Finish(ctx context.Context, name string, runErr error) error
+}
+
+type dbStore struct {
+	*basestore.Store
+}
+
+func newStore(db database.DB) store {
+	return &dbStore{Store: basestore.NewWithHandle(db.Handle())}
+}
+
+func (s *dbStore) LastScheduled(ctx context.Context, name string) (time.Time, bool, error) {
+	return basestore.ScanFirstTime(s.Query(ctx, sqlf.Sprintf(
+		"SELECT last_scheduled_at FROM workerutil_cron_jobs WHERE name = %s",
+		name,
+	)))
+}
+
+func (s *dbStore) Init(ctx context.Context, name string, scheduledAt time.Time) error {
+	return s.Exec(ctx, sqlf.Sprintf(
+		"INSERT INTO workerutil_cron_jobs (name, last_scheduled_at) VALUES (%s, %s) ON CONFLICT (name) DO NOTHING",
+		name, scheduledAt,
+	))
+}
+
+const claimFmtstr = `
+UPDATE workerutil_cron_jobs
+SET last_scheduled_at = %s, updated_at = NOW()
+WHERE name = %s AND last_scheduled_at = %s
+`
+
+func (s *dbStore) Claim(ctx context.Context, name string, last, scheduledAt time.Time) (bool, error) {
+	res, err := s.ExecResult(ctx, sqlf.Sprintf(claimFmtstr, scheduledAt, name, last))
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (s *dbStore) Finish(ctx context.Context, name string, runErr error) error {
+	var lastError *string
+	if runErr != nil {
+		msg := runErr.Error()
+		lastError = &msg
+	}
+	return s.Exec(ctx, sqlf.Sprintf(
+		"UPDATE workerutil_cron_jobs SET last_finished_at = NOW(), last_error = %s, updated_at = NOW() WHERE name = %s",
+		lastError, name,
+	))
+}
@@ -123,6 +123,17 @@ loop:
 		r.options.Metrics.RecordResets.Add(float64(len(resetLastHeartbeatsByIDs)))
 		r.options.Metrics.RecordResetFailures.Add(float64(len(failedLastHeartbeatsByIDs)))
 
+		if deadLetterStore, ok := r.store.(store.DeadLetterStore); ok {
+			requeuedIDs, err := deadLetterStore.RequeueDeadLetters(r.ctx)
+			if err != nil && r.ctx.Err() == nil {
+				r.options.Metrics.Errors.Inc()
+				r.logger.Error("Failed to requeue dead letters", log.String("name", r.options.Name), log.Error(err))
+			}
+			for _, id := range requeuedIDs {
+				r.logger.Info("Requeued record from the dead-letter queue", log.String("name", r.options.Name), log.Int("id", id))
+			}
+		}
+
 		select {
 		case <-r.clock.After(r.options.Interval):
 		case <-r.ctx.Done():
@@ -13,11 +13,13 @@ type operations struct {
 	dequeue                 *observation.Operation
 	heartbeat               *observation.Operation
 	markComplete            *observation.Operation
+	markCrashed             *observation.Operation
 	markErrored             *observation.Operation
 	markFailed              *observation.Operation
 	maxDurationInQueue      *observation.Operation
 	queuedCount             *observation.Operation
 	requeue                 *observation.Operation
+	requeueDeadLetters      *observation.Operation
 	resetStalled            *observation.Operation
 	updateExecutionLogEntry *observation.Operation
 	canceledJobs            *observation.Operation
@@ -62,11 +64,13 @@ func newOperations(observationCtx *observation.Context, storeName string) *opera
 		dequeue:                 op("Dequeue"),
 		heartbeat:               op("Heartbeat"),
 		markComplete:            op("MarkComplete"),
+		markCrashed:             op("MarkCrashed"),
 		markErrored:             op("MarkErrored"),
 		markFailed:              op("MarkFailed"),
 		maxDurationInQueue:      op("MaxDurationInQueue"),
 		queuedCount:             op("QueuedCount"),
 		requeue:                 op("Requeue"),
+		requeueDeadLetters:      op("RequeueDeadLetters"),
 		resetStalled:            op("ResetStalled"),
 		updateExecutionLogEntry: op("UpdateExecutionLogEntry"),
 		canceledJobs:            op("CanceledJobs"),
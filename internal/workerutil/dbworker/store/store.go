@@ -21,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/deadletter"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -238,6 +239,12 @@ type Options[T workerutil.Record] struct {
 	// Setting this value to zero will disable retries entirely.
 	MaxNumRetries int
 
+	// OnStateChange, if set, is called after the store has moved a record to a new state: when
+	// it is dequeued, requeued, marked as completed, errored or failed, or reset by ResetStalled.
+	// This can be used to notify clients waiting for the record to be processed, for example via
+	// redispubsub.RecordStateChangeHook.
+	OnStateChange func(ctx context.Context, id int)
+
 	// clock is used to mock out the wall clock used for heartbeat updates.
 	clock glock.Clock
 }
@@ -519,6 +526,7 @@ func (s *store[T]) Dequeue(ctx context.Context, workerHostname string, condition
 		return ret, false, nil
 	}
 	trace.AddEvent("TODO Domain Owner", attribute.Int("recordID", records[0].RecordID()))
+	s.stateChanged(ctx, records[0].RecordID())
 
 	return records[0], true, nil
 }
@@ -702,12 +710,17 @@ func (s *store[T]) Requeue(ctx context.Context, id int, after time.Time) (err er
 	}})
 	defer endObservation(1, observation.Args{})
 
-	return s.Exec(ctx, s.formatQuery(
+	if err := s.Exec(ctx, s.formatQuery(
 		requeueQuery,
 		quote(s.options.TableName),
 		after,
 		id,
-	))
+	)); err != nil {
+		return err
+	}
+
+	s.stateChanged(ctx, id)
+	return nil
 }
 
 const requeueQuery = `
@@ -844,6 +857,9 @@ func (s *store[T]) MarkComplete(ctx context.Context, id int, options MarkFinalOp
 	conds = append(conds, options.ToSQLConds(s.formatQuery)...)
 
 	_, ok, err := basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(markCompleteQuery, quote(s.options.TableName), sqlf.Join(conds, "AND"))))
+	if ok {
+		s.stateChanged(ctx, id)
+	}
 	return ok, err
 }
 
@@ -871,6 +887,9 @@ func (s *store[T]) MarkErrored(ctx context.Context, id int, failureMessage strin
 
 	q := s.formatQuery(markErroredQuery, quote(s.options.TableName), s.options.MaxNumRetries, failureMessage, sqlf.Join(conds, "AND"))
 	_, ok, err := basestore.ScanFirstInt(s.Query(ctx, q))
+	if ok {
+		s.stateChanged(ctx, id)
+	}
 	return ok, err
 }
 
@@ -901,6 +920,9 @@ func (s *store[T]) MarkFailed(ctx context.Context, id int, failureMessage string
 
 	q := s.formatQuery(markFailedQuery, quote(s.options.TableName), failureMessage, sqlf.Join(conds, "AND"))
 	_, ok, err := basestore.ScanFirstInt(s.Query(ctx, q))
+	if ok {
+		s.stateChanged(ctx, id)
+	}
 	return ok, err
 }
 
@@ -914,6 +936,100 @@ WHERE %s
 RETURNING {id}
 `
 
+// DeadLetterStore is implemented by stores that move records that repeatedly crash their worker to the
+// dead-letter queue instead of retrying them forever.
+type DeadLetterStore interface {
+	// MarkCrashed records that the handler of the record crashed. The record is requeued until it has been
+	// reset or crashed `MaxNumResets` times, after which it is marked as failed and recorded as a dead letter.
+	// This method returns a boolean flag indicating if the record was moved to the dead-letter queue.
+	MarkCrashed(ctx context.Context, id int, failureMessage, stack string) (bool, error)
+
+	// RequeueDeadLetters requeues the failed records whose dead letters were marked for retry, and deletes
+	// the dead letters. This method returns the identifiers of the requeued records.
+	RequeueDeadLetters(ctx context.Context) ([]int, error)
+}
+
+var _ DeadLetterStore = &store[workerutil.Record]{}
+
+func (s *store[T]) MarkCrashed(ctx context.Context, id int, failureMessage, stack string) (_ bool, err error) {
+	ctx, _, endObservation := s.operations.markCrashed.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("id", id),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	max := s.options.MaxNumResets
+	result, ok, err := scanFirstCrashResult(s.Query(ctx, s.formatQuery(markCrashedQuery, quote(s.options.TableName), max, max, max, max, failureMessage, id)))
+	if err != nil || !ok {
+		return false, err
+	}
+	s.stateChanged(ctx, id)
+
+	if result.state != "failed" {
+		return false, nil
+	}
+	if err := deadletter.NewStore(s.Handle()).Add(ctx, s.options.Name, id, failureMessage, stack, result.numResets); err != nil {
+		return false, errors.Wrap(err, "recording dead letter")
+	}
+	return true, nil
+}
+
+type crashResult struct {
+	state     string
+	numResets int
+}
+
+var scanFirstCrashResult = basestore.NewFirstScanner(func(s dbutil.Scanner) (r crashResult, err error) {
+	err = s.Scan(&r.state, &r.numResets)
+	return r, err
+})
+
+const markCrashedQuery = `
+UPDATE %s
+SET
+	{state} = CASE WHEN {num_resets} < %s THEN 'queued' ELSE 'failed' END,
+	{queued_at} = CASE WHEN {num_resets} < %s THEN clock_timestamp() ELSE {queued_at} END,
+	{started_at} = CASE WHEN {num_resets} < %s THEN NULL ELSE {started_at} END,
+	{finished_at} = CASE WHEN {num_resets} < %s THEN NULL ELSE clock_timestamp() END,
+	{failure_message} = %s,
+	{num_resets} = {num_resets} + 1
+WHERE {id} = %s AND {state} = 'processing'
+RETURNING {state}, {num_resets}
+`
+
+func (s *store[T]) RequeueDeadLetters(ctx context.Context) (ids []int, err error) {
+	ctx, _, endObservation := s.operations.requeueDeadLetters.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	ids, err = basestore.ScanInts(s.Query(ctx, s.formatQuery(requeueDeadLettersQuery, s.options.Name, quote(s.options.TableName))))
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		s.stateChanged(ctx, id)
+	}
+	return ids, nil
+}
+
+const requeueDeadLettersQuery = `
+WITH retried AS (
+	DELETE FROM workerutil_dead_letters
+	WHERE store_name = %s AND retry_requested_at IS NOT NULL
+	RETURNING record_id
+)
+UPDATE %s
+SET
+	{state} = 'queued',
+	{queued_at} = clock_timestamp(),
+	{started_at} = NULL,
+	{finished_at} = NULL,
+	{process_after} = NULL,
+	{failure_message} = NULL,
+	{num_resets} = 0,
+	{num_failures} = 0
+WHERE {id} IN (SELECT record_id FROM retried) AND {state} = 'failed'
+RETURNING {id}
+`
+
 const defaultResetFailureMessage = "job processor died while handling this message too many times"
 
 // ResetStalled moves all processing records that have not received a heartbeat within `StalledMaxAge` back to the
@@ -966,6 +1082,19 @@ func (s *store[T]) ResetStalled(ctx context.Context) (resetLastHeartbeatsByIDs,
 	}
 	trace.AddEvent("TODO Domain Owner", attribute.Int("numErroredIDs", len(failedLastHeartbeatsByIDs)))
 
+	for id := range resetLastHeartbeatsByIDs {
+		s.stateChanged(ctx, id)
+	}
+	deadLetters := deadletter.NewStore(s.Handle())
+	for id := range failedLastHeartbeatsByIDs {
+		s.stateChanged(ctx, id)
+
+		// The record crashed once more than it was reset.
+		if err := deadLetters.Add(ctx, s.options.Name, id, resetFailureMessage, "", s.options.MaxNumResets+1); err != nil {
+			return resetLastHeartbeatsByIDs, failedLastHeartbeatsByIDs, errors.Wrap(err, "recording dead letter")
+		}
+	}
+
 	return resetLastHeartbeatsByIDs, failedLastHeartbeatsByIDs, nil
 }
 
@@ -1028,6 +1157,13 @@ WHERE {id} IN (SELECT {id} FROM stalled)
 RETURNING {id}, {last_heartbeat_at}
 `
 
+// stateChanged calls the OnStateChange hook of the store, if any.
+func (s *store[T]) stateChanged(ctx context.Context, id int) {
+	if s.options.OnStateChange != nil {
+		s.options.OnStateChange(ctx, id)
+	}
+}
+
 func (s *store[T]) formatQuery(query string, args ...any) *sqlf.Query {
 	return sqlf.Sprintf(s.columnReplacer.Replace(query), args...)
 }
@@ -1109,3 +1109,41 @@ func TestStoreCanceledJobs(t *testing.T) {
 
 	require.ElementsMatch(t, toCancel, []string{"3"}, "invalid set of jobs returned")
 }
+
+func TestStoreOnStateChange(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, created_at)
+		VALUES
+			(1, 'queued', NOW() - '1 minute'::interval),
+			(2, 'processing', NOW() - '2 minute'::interval),
+			(3, 'completed', NOW() - '3 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	var changed []int
+	options := defaultTestStoreOptions(nil, testScanRecord)
+	options.OnStateChange = func(_ context.Context, id int) { changed = append(changed, id) }
+	store := testStore(db, options)
+	ctx := context.Background()
+
+	if _, ok, err := store.Dequeue(ctx, "test", nil); err != nil || !ok {
+		t.Fatalf("unexpected error dequeueing record: %v", err)
+	}
+	if _, err := store.MarkComplete(ctx, 2, MarkFinalOptions{}); err != nil {
+		t.Fatalf("unexpected error marking record as completed: %s", err)
+	}
+	// Records that are not processing are not updated.
+	if _, err := store.MarkErrored(ctx, 3, "oops", MarkFinalOptions{}); err != nil {
+		t.Fatalf("unexpected error marking record as errored: %s", err)
+	}
+	if err := store.Requeue(ctx, 1, time.Now()); err != nil {
+		t.Fatalf("unexpected error requeueing record: %s", err)
+	}
+
+	if diff := cmp.Diff([]int{1, 2, 1}, changed); diff != "" {
+		t.Errorf("unexpected state changes (-want +got):\n%s", diff)
+	}
+}
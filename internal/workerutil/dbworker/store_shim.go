@@ -2,6 +2,7 @@ package dbworker
 
 import (
 	"context"
+	"time"
 
 	"github.com/keegancsmith/sqlf"
 
@@ -16,6 +17,8 @@ type storeShim[T workerutil.Record] struct {
 }
 
 var _ workerutil.Store[workerutil.Record] = &storeShim[workerutil.Record]{}
+var _ workerutil.DelayedStore[workerutil.Record] = &storeShim[workerutil.Record]{}
+var _ workerutil.CrashStore[workerutil.Record] = &storeShim[workerutil.Record]{}
 
 // newStoreShim wraps the given store in a shim.
 func newStoreShim[T workerutil.Record](store store.Store[T]) workerutil.Store[T] {
@@ -57,6 +60,20 @@ func (s *storeShim[T]) MarkErrored(ctx context.Context, rec T, errorMessage stri
 	return s.Store.MarkErrored(ctx, rec.RecordID(), errorMessage, store.MarkFinalOptions{})
 }
 
+func (s *storeShim[T]) Requeue(ctx context.Context, rec T, after time.Time) error {
+	return s.Store.Requeue(ctx, rec.RecordID(), after)
+}
+
+// MarkCrashed calls into the inner store if it supports dead letters, and marks the record
+// as errored otherwise.
+func (s *storeShim[T]) MarkCrashed(ctx context.Context, rec T, failureMessage, stack string) (bool, error) {
+	if deadLetterStore, ok := s.Store.(store.DeadLetterStore); ok {
+		return deadLetterStore.MarkCrashed(ctx, rec.RecordID(), failureMessage, stack)
+	}
+	_, err := s.Store.MarkErrored(ctx, rec.RecordID(), failureMessage, store.MarkFinalOptions{})
+	return false, err
+}
+
 // ErrNotConditions occurs when a PreDequeue handler returns non-sql query extra arguments.
 var ErrNotConditions = errors.New("expected slice of *sqlf.Query values")
 
@@ -0,0 +1,145 @@
+// Package deadletter records worker records that repeatedly crashed their worker.
+// Instead of being retried forever, such records are moved to the failed state and
+// recorded as dead letters, which site admins can inspect and retry or discard.
+package deadletter
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// DeadLetter is a record of a dbworker store that crashed its worker too often.
+type DeadLetter struct {
+	ID int
+	// StoreName is the name of the dbworker store the record belongs to.
+	StoreName string
+	RecordID  int
+	// FailureMessage and Stack describe the last crash. Stack is empty if the
+	// worker did not panic, e.g. because the process was killed.
+	FailureMessage string
+	Stack          string
+	NumCrashes     int
+	// RetryRequestedAt is set once a retry was requested, until the resetter of
+	// the store requeued the record.
+	RetryRequestedAt *time.Time
+	CreatedAt        time.Time
+}
+
+// ListOptions filter the dead letters returned by List.
+type ListOptions struct {
+	// StoreName, if set, returns only the dead letters of the given store.
+	StoreName string
+	// After, if set, returns only dead letters with a greater ID.
+	After int
+	Limit int
+}
+
+// Store records dead letters.
+type Store interface {
+	// Add records the record of the store as a dead letter. If the record is
+	// already a dead letter, its crash details are updated.
+	Add(ctx context.Context, storeName string, recordID int, failureMessage, stack string, numCrashes int) error
+
+	// List returns the dead letters matching the options, ordered by ID.
+	List(ctx context.Context, opts ListOptions) ([]*DeadLetter, error)
+
+	// Count returns the number of dead letters of the store, or of all stores if
+	// storeName is empty.
+	Count(ctx context.Context, storeName string) (int, error)
+
+	// RequestRetry marks the dead letters for retry. Their records are requeued
+	// by the resetter of their store, which then deletes the dead letters.
+	RequestRetry(ctx context.Context, ids []int) error
+
+	// Discard deletes the dead letters, leaving their records failed.
+	Discard(ctx context.Context, ids []int) error
+}
+
+type store struct {
+	*basestore.Store
+}
+
+// NewStore returns a Store backed by the given database handle.
+func NewStore(handle basestore.TransactableHandle) Store {
+	return &store{Store: basestore.NewWithHandle(handle)}
+}
+
+const addFmtstr = `
+INSERT INTO workerutil_dead_letters (store_name, record_id, failure_message, stack, num_crashes)
+VALUES (%s, %s, %s, %s, %s)
+ON CONFLICT (store_name, record_id) DO UPDATE SET
+	failure_message = EXCLUDED.failure_message,
+	stack = EXCLUDED.stack,
+	num_crashes = EXCLUDED.num_crashes,
+	retry_requested_at = NULL,
+	created_at = NOW()
+`
+
+func (s *store) Add(ctx context.Context, storeName string, recordID int, failureMessage, stack string, numCrashes int) error {
+	return s.Exec(ctx, sqlf.Sprintf(addFmtstr, storeName, recordID, dbutil.NewNullString(failureMessage), dbutil.NewNullString(stack), numCrashes))
+}
+
+const listFmtstr = `
+SELECT id, store_name, record_id, failure_message, stack, num_crashes, retry_requested_at, created_at
+FROM workerutil_dead_letters
+WHERE %s
+ORDER BY id
+LIMIT %s
+`
+
+func (s *store) List(ctx context.Context, opts ListOptions) ([]*DeadLetter, error) {
+	return basestore.NewSliceScanner(scanDeadLetter)(s.Query(ctx, sqlf.Sprintf(listFmtstr, opts.conds(), opts.Limit)))
+}
+
+func (s *store) Count(ctx context.Context, storeName string) (int, error) {
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(
+		"SELECT COUNT(*) FROM workerutil_dead_letters WHERE %s",
+		ListOptions{StoreName: storeName}.conds(),
+	)))
+	return count, err
+}
+
+func (o ListOptions) conds() *sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.StoreName != "" {
+		conds = append(conds, sqlf.Sprintf("store_name = %s", o.StoreName))
+	}
+	if o.After != 0 {
+		conds = append(conds, sqlf.Sprintf("id > %s", o.After))
+	}
+	return sqlf.Join(conds, "AND")
+}
+
+func (s *store) RequestRetry(ctx context.Context, ids []int) error {
+	return s.Exec(ctx, sqlf.Sprintf(
+		"UPDATE workerutil_dead_letters SET retry_requested_at = NOW() WHERE id = ANY(%s) AND retry_requested_at IS NULL",
+		pq.Array(ids),
+	))
+}
+
+func (s *store) Discard(ctx context.Context, ids []int) error {
+	return s.Exec(ctx, sqlf.Sprintf("DELETE FROM workerutil_dead_letters WHERE id = ANY(%s)", pq.Array(ids)))
+}
+
+func scanDeadLetter(s dbutil.Scanner) (*DeadLetter, error) {
+	var d DeadLetter
+	if err := s.Scan(
+		&d.ID,
+		&d.StoreName,
+		&d.RecordID,
+		&dbutil.NullString{S: &d.FailureMessage},
+		&dbutil.NullString{S: &d.Stack},
+		&d.NumCrashes,
+		&d.RetryRequestedAt,
+		&d.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
@@ -0,0 +1,45 @@
+package workerutil
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DelayedStore is an optional extension of the Store interface for stores that can put
+// a record back into the queue to be processed later.
+type DelayedStore[T Record] interface {
+	// Requeue updates the state of the record to queued, so that it is not dequeued again
+	// before the given time.
+	Requeue(ctx context.Context, rec T, after time.Time) error
+}
+
+// runAfterError is returned by handlers that want to process a record again later.
+type runAfterError struct {
+	after time.Time
+}
+
+func (e *runAfterError) Error() string {
+	return fmt.Sprintf("record deferred until %s", e.after.Format(time.RFC3339))
+}
+
+// RunAfter returns an error that a handler returns to process the record again after the
+// given time, e.g. because a resource the record depends on is not available yet. The record
+// is requeued rather than marked as errored, so deferring does not count towards the maximum
+// number of attempts. If the store of the worker is not a DelayedStore, the record is marked
+// as errored instead.
+func RunAfter(after time.Time) error {
+	return &runAfterError{after: after}
+}
+
+// IsRunAfter returns the time the record should be processed again, if err was created by
+// RunAfter.
+func IsRunAfter(err error) (time.Time, bool) {
+	var e *runAfterError
+	if errors.As(err, &e) {
+		return e.after, true
+	}
+	return time.Time{}, false
+}
@@ -3,6 +3,7 @@ package workerutil
 import (
 	"context"
 	"fmt"
+	"runtime/debug"
 	"strconv"
 	"sync"
 	"time"
@@ -225,6 +226,11 @@ loop:
 				log.Error(err))
 		}
 
+		if w.recorder != nil {
+			// Polling for work is a sign of life even if there is nothing to do
+			go w.recorder.LogHeartbeat(w)
+		}
+
 		delay := w.options.Interval
 		if ok {
 			// If we had a successful dequeue, do not wait the poll interval.
@@ -290,6 +296,12 @@ func (w *Worker[T]) dequeueAndHandle() (dequeued bool, err error) {
 		}
 	}()
 
+	if w.recorder != nil && w.recorder.Paused(w) {
+		// A site admin paused or drained this worker: let the running jobs finish
+		// but do not pick up new ones until it is resumed
+		return false, nil
+	}
+
 	dequeueable, extraDequeueArguments, err := w.preDequeueHook(w.dequeueCtx)
 	if err != nil {
 		return false, errors.Wrap(err, "Handler.PreDequeueHook")
@@ -392,7 +404,11 @@ func (w *Worker[T]) handle(ctx, workerContext context.Context, record T) (err er
 
 	// Open namespace for logger to avoid key collisions on fields
 	start := time.Now()
-	handleErr = w.handler.Handle(ctx, handleLog.With(log.Namespace("handle")), record)
+	if w.recorder != nil {
+		go w.recorder.LogRunStart(w, start)
+		ctx = recorder.WithHeartbeat(ctx, w.recorder, w)
+	}
+	handleErr = w.callHandler(ctx, handleLog.With(log.Namespace("handle")), record)
 
 	if w.options.MaximumRuntimePerJob > 0 && errors.Is(handleErr, context.DeadlineExceeded) {
 		handleErr = errors.Wrap(handleErr, fmt.Sprintf("job exceeded maximum execution time of %s", w.options.MaximumRuntimePerJob))
@@ -402,6 +418,31 @@ func (w *Worker[T]) handle(ctx, workerContext context.Context, record T) (err er
 		go w.recorder.LogRun(w, duration, handleErr)
 	}
 
+	if panicErr, ok := IsPanic(handleErr); ok {
+		if crashStore, ok := w.store.(CrashStore[T]); ok {
+			deadLettered, err := crashStore.MarkCrashed(workerContext, record, handleErr.Error(), panicErr.Stack)
+			if err != nil {
+				return errors.Wrap(err, "store.MarkCrashed")
+			}
+			if deadLettered {
+				handleLog.Error("Moved record to the dead-letter queue after repeated crashes", log.Error(handleErr), log.String("stack", panicErr.Stack))
+			} else {
+				handleLog.Error("Requeued record after crash", log.Error(handleErr), log.String("stack", panicErr.Stack))
+			}
+			return nil
+		}
+	}
+
+	if after, ok := IsRunAfter(handleErr); ok {
+		if delayedStore, ok := w.store.(DelayedStore[T]); ok {
+			if err := delayedStore.Requeue(workerContext, record, after); err != nil {
+				return errors.Wrap(err, "store.Requeue")
+			}
+			handleLog.Debug("Requeued record", log.Time("after", after))
+			return nil
+		}
+	}
+
 	if errcode.IsNonRetryable(handleErr) || handleErr != nil && w.isJobCanceled(record.RecordUID(), handleErr, ctx.Err()) {
 		if marked, markErr := w.store.MarkFailed(workerContext, record, handleErr.Error()); markErr != nil {
 			return errors.Wrap(markErr, "store.MarkFailed")
@@ -426,6 +467,18 @@ func (w *Worker[T]) handle(ctx, workerContext context.Context, record T) (err er
 	return nil
 }
 
+// callHandler invokes the handler, converting a panic into a PanicError so that a record
+// that crashes its handler does not take down the worker.
+func (w *Worker[T]) callHandler(ctx context.Context, logger log.Logger, record T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: string(debug.Stack())}
+		}
+	}()
+
+	return w.handler.Handle(ctx, logger, record)
+}
+
 // isJobCanceled returns true if the job has been canceled through the Cancel interface.
 // If the context is canceled, and the job is still part of the running ID set,
 // we know that it has been canceled for that reason.
@@ -469,3 +522,9 @@ func (w *Worker[T]) Interval() time.Duration {
 func (w *Worker[T]) RegisterRecorder(r *recorder.Recorder) {
 	w.recorder = r
 }
+
+// InFlight returns the number of jobs the worker is processing, which the recorder saves
+// so that a drained worker can be told apart from one that is still finishing its jobs.
+func (w *Worker[T]) InFlight() int {
+	return len(w.runningIDSet.Slice())
+}
@@ -151,6 +151,136 @@ func TestWorkerHandlerNonRetryableFailure(t *testing.T) {
 	}
 }
 
+// delayedTestStore is a store that supports requeueing records.
+type delayedTestStore struct {
+	*MockStore[*TestRecord]
+	requeued map[int]time.Time
+}
+
+func (s *delayedTestStore) Requeue(_ context.Context, rec *TestRecord, after time.Time) error {
+	s.requeued[rec.RecordID()] = after
+	return nil
+}
+
+func TestWorkerHandlerRunAfter(t *testing.T) {
+	after := time.Now().Add(time.Hour)
+	options := WorkerOptions{
+		Name:           "test",
+		WorkerHostname: "test",
+		NumHandlers:    1,
+		Interval:       time.Second,
+		Metrics:        NewMetrics(&observation.TestContext, ""),
+	}
+
+	t.Run("delayed store", func(t *testing.T) {
+		store := &delayedTestStore{MockStore: NewMockStore[*TestRecord](), requeued: map[int]time.Time{}}
+		handler := NewMockHandler[*TestRecord]()
+		dequeueClock := glock.NewMockClock()
+
+		store.DequeueFunc.PushReturn(&TestRecord{ID: 42}, true, nil)
+		store.DequeueFunc.SetDefaultReturn(nil, false, nil)
+		handler.HandleFunc.SetDefaultReturn(errors.Wrap(RunAfter(after), "waiting for dependency"))
+
+		worker := newWorker(context.Background(), Store[*TestRecord](store), Handler[*TestRecord](handler), options, dequeueClock, glock.NewMockClock(), glock.NewMockClock())
+		go func() { worker.Start() }()
+		dequeueClock.BlockingAdvance(time.Second)
+		worker.Stop()
+
+		if have, ok := store.requeued[42]; !ok || !have.Equal(after) {
+			t.Errorf("unexpected requeue. want=%s have=%s", after, have)
+		}
+		if callCount := len(store.MarkErroredFunc.History()); callCount != 0 {
+			t.Errorf("unexpected mark errored call count. want=%d have=%d", 0, callCount)
+		}
+		if callCount := len(store.MarkCompleteFunc.History()); callCount != 0 {
+			t.Errorf("unexpected mark complete call count. want=%d have=%d", 0, callCount)
+		}
+	})
+
+	t.Run("store without requeue", func(t *testing.T) {
+		store := NewMockStore[*TestRecord]()
+		handler := NewMockHandler[*TestRecord]()
+		dequeueClock := glock.NewMockClock()
+
+		store.DequeueFunc.PushReturn(&TestRecord{ID: 42}, true, nil)
+		store.DequeueFunc.SetDefaultReturn(nil, false, nil)
+		store.MarkErroredFunc.SetDefaultReturn(true, nil)
+		handler.HandleFunc.SetDefaultReturn(RunAfter(after))
+
+		worker := newWorker(context.Background(), Store[*TestRecord](store), Handler[*TestRecord](handler), options, dequeueClock, glock.NewMockClock(), glock.NewMockClock())
+		go func() { worker.Start() }()
+		dequeueClock.BlockingAdvance(time.Second)
+		worker.Stop()
+
+		if callCount := len(store.MarkErroredFunc.History()); callCount != 1 {
+			t.Errorf("unexpected mark errored call count. want=%d have=%d", 1, callCount)
+		}
+	})
+}
+
+// crashTestStore is a store that counts the crashes of records.
+type crashTestStore struct {
+	*MockStore[*TestRecord]
+	stacks map[int]string
+}
+
+func (s *crashTestStore) MarkCrashed(_ context.Context, rec *TestRecord, _, stack string) (bool, error) {
+	s.stacks[rec.RecordID()] = stack
+	return true, nil
+}
+
+func TestWorkerHandlerPanic(t *testing.T) {
+	options := WorkerOptions{
+		Name:           "test",
+		WorkerHostname: "test",
+		NumHandlers:    1,
+		Interval:       time.Second,
+		Metrics:        NewMetrics(&observation.TestContext, ""),
+	}
+
+	t.Run("crash store", func(t *testing.T) {
+		store := &crashTestStore{MockStore: NewMockStore[*TestRecord](), stacks: map[int]string{}}
+		handler := NewMockHandler[*TestRecord]()
+		dequeueClock := glock.NewMockClock()
+
+		store.DequeueFunc.PushReturn(&TestRecord{ID: 42}, true, nil)
+		store.DequeueFunc.SetDefaultReturn(nil, false, nil)
+		handler.HandleFunc.SetDefaultHook(func(context.Context, log.Logger, *TestRecord) error { panic("oops") })
+
+		worker := newWorker(context.Background(), Store[*TestRecord](store), Handler[*TestRecord](handler), options, dequeueClock, glock.NewMockClock(), glock.NewMockClock())
+		go func() { worker.Start() }()
+		dequeueClock.BlockingAdvance(time.Second)
+		worker.Stop()
+
+		if stack, ok := store.stacks[42]; !ok || !strings.Contains(stack, "TestWorkerHandlerPanic") {
+			t.Errorf("expected record to be marked crashed with the stack of the handler. have=%q", stack)
+		}
+		if callCount := len(store.MarkErroredFunc.History()); callCount != 0 {
+			t.Errorf("unexpected mark errored call count. want=%d have=%d", 0, callCount)
+		}
+	})
+
+	t.Run("store without crash counts", func(t *testing.T) {
+		store := NewMockStore[*TestRecord]()
+		handler := NewMockHandler[*TestRecord]()
+		dequeueClock := glock.NewMockClock()
+
+		store.DequeueFunc.PushReturn(&TestRecord{ID: 42}, true, nil)
+		store.DequeueFunc.SetDefaultReturn(nil, false, nil)
+		store.MarkErroredFunc.SetDefaultReturn(true, nil)
+		handler.HandleFunc.SetDefaultHook(func(context.Context, log.Logger, *TestRecord) error { panic("oops") })
+
+		worker := newWorker(context.Background(), Store[*TestRecord](store), Handler[*TestRecord](handler), options, dequeueClock, glock.NewMockClock(), glock.NewMockClock())
+		go func() { worker.Start() }()
+		dequeueClock.BlockingAdvance(time.Second)
+		worker.Stop()
+
+		if callCount := len(store.MarkErroredFunc.History()); callCount != 1 {
+			t.Errorf("unexpected mark errored call count. want=%d have=%d", 1, callCount)
+		}
+	})
+}
+
 func TestWorkerConcurrent(t *testing.T) {
 	NumTestRecords := 50
 
@@ -44,6 +44,7 @@ type ChangesetTemplate struct {
 	Body      string                       `json:"body,omitempty" yaml:"body"`
 	Branch    string                       `json:"branch,omitempty" yaml:"branch"`
 	Fork      *bool                        `json:"fork,omitempty" yaml:"fork"`
+	Reviewers []string                     `json:"reviewers,omitempty" yaml:"reviewers"`
 	Commit    ExpandedGitCommitDescription `json:"commit,omitempty" yaml:"commit"`
 	Published *overridable.BoolOrString    `json:"published" yaml:"published"`
 }
@@ -78,6 +78,10 @@ type ChangesetSpec struct {
 	Body  string `json:"body,omitempty"`
 	Fork  *bool  `json:"fork,omitempty"`
 
+	// Reviewers are the usernames of the users to request reviews from when
+	// the changeset is published.
+	Reviewers []string `json:"reviewers,omitempty"`
+
 	Commits []GitCommitDescription `json:"commits,omitempty"`
 
 	Published PublishedValue `json:"published,omitempty"`
@@ -101,6 +105,7 @@ func (c *ChangesetSpec) MarshalJSON() ([]byte, error) {
 		HeadRef        string                 `json:"headRef,omitempty"`
 		Title          string                 `json:"title,omitempty"`
 		Body           string                 `json:"body,omitempty"`
+		Reviewers      []string               `json:"reviewers,omitempty"`
 		Commits        []GitCommitDescription `json:"commits,omitempty"`
 		Published      *PublishedValue        `json:"published,omitempty"`
 	}{
@@ -112,6 +117,7 @@ func (c *ChangesetSpec) MarshalJSON() ([]byte, error) {
 		HeadRef:        c.HeadRef,
 		Title:          c.Title,
 		Body:           c.Body,
+		Reviewers:      c.Reviewers,
 		Commits:        c.Commits,
 	}
 	if !c.Published.Nil() {
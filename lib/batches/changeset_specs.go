@@ -121,10 +121,11 @@ func BuildChangesetSpecs(input *ChangesetSpecInput, binaryDiffs bool, fallbackAu
 			BaseRef:        input.Repository.BaseRef,
 			BaseRev:        input.Repository.BaseRev,
 
-			HeadRef: git.EnsureRefPrefix(branch),
-			Title:   title,
-			Body:    body,
-			Fork:    fork,
+			HeadRef:   git.EnsureRefPrefix(branch),
+			Title:     title,
+			Body:      body,
+			Fork:      fork,
+			Reviewers: input.Template.Reviewers,
 			Commits: []GitCommitDescription{
 				{
 					Version:     version,
@@ -146,6 +146,19 @@ func TestCreateChangesetSpecs(t *testing.T) {
 			},
 			wantErr: "",
 		},
+		{
+			name: "reviewers",
+			input: inputWith(defaultInput, func(input *ChangesetSpecInput) {
+				input.Template.Published = parsePublishedFieldString(t, "false")
+				input.Template.Reviewers = []string{"alice", "bob"}
+			}),
+			want: []*ChangesetSpec{
+				specWith(defaultChangesetSpec, func(s *ChangesetSpec) {
+					s.Reviewers = []string{"alice", "bob"}
+				}),
+			},
+			wantErr: "",
+		},
 		{
 			name:   "publish with fallback author",
 			input:  defaultInput,
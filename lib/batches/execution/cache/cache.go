@@ -69,16 +69,26 @@ func resolveStepsEnvironment(globalEnv []string, steps []batches.Step) ([]map[st
 	return envs, nil
 }
 
+// keyVersion is hashed into every cache key. This package is shared with
+// src-cli, so it has to be bumped whenever the way a key is derived from the
+// same inputs changes. Otherwise, clients on different versions would look up
+// results under keys that the other side computed differently.
+//
+// Version 2 leaves out the batch change attributes if no step references them.
+const keyVersion = 2
+
 func marshalAndHash(key *CacheKey, envs []map[string]string, metadata []MountMetadata) (string, error) {
 	raw, err := json.Marshal(struct {
 		*CacheKey
 		Environments []map[string]string
 		// Omit if empty to be backwards compatible.
 		MountsMetadata []MountMetadata `json:"MountsMetadata,omitempty"`
+		KeyVersion     int
 	}{
 		CacheKey:       key,
 		Environments:   envs,
 		MountsMetadata: metadata,
+		KeyVersion:     keyVersion,
 	})
 	if err != nil {
 		return "", err
@@ -119,6 +129,19 @@ func (key CacheKey) Key() (string, error) {
 	if err != nil {
 		return "", err
 	}
+
+	// The batch change attributes only affect the result of the steps if they
+	// are referenced in templates. Leaving them out otherwise means that
+	// results are reused across batch specs that only differ in their name or
+	// description.
+	referencesAttributes, err := stepsReferenceBatchChange(clone.Steps, envs)
+	if err != nil {
+		return "", err
+	}
+	if !referencesAttributes {
+		clone.BatchChangeAttributes = nil
+	}
+
 	metadata, err := key.mountsMetadata()
 	if err != nil {
 		return "", err
@@ -131,6 +154,36 @@ func (key CacheKey) Key() (string, error) {
 	return fmt.Sprintf("%s-step-%d", hash, key.StepIndex), err
 }
 
+// stepsReferenceBatchChange returns true if any of the templates of the steps
+// references the batch_change template variable. The environments are the
+// resolved environments of the steps, since their values are rendered as
+// templates too.
+func stepsReferenceBatchChange(steps []batches.Step, envs []map[string]string) (bool, error) {
+	for i, step := range steps {
+		templates := []string{step.IfCondition(), step.Run}
+		for _, v := range envs[i] {
+			templates = append(templates, v)
+		}
+		for _, v := range step.Files {
+			templates = append(templates, v)
+		}
+		for _, o := range step.Outputs {
+			templates = append(templates, o.Value)
+		}
+
+		for _, tmpl := range templates {
+			references, err := template.ReferencesBatchChange(tmpl)
+			if err != nil {
+				return false, errors.Wrapf(err, "parsing templates of step %d", i)
+			}
+			if references {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 func (key CacheKey) Slug() string {
 	return SlugForRepo(key.Repository.Name, key.Repository.BaseRev)
 }
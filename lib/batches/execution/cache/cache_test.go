@@ -10,6 +10,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/lib/batches"
 	"github.com/sourcegraph/sourcegraph/lib/batches/env"
+	"github.com/sourcegraph/sourcegraph/lib/batches/template"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -39,6 +40,10 @@ func TestKeyer_Key(t *testing.T) {
 	err = json.Unmarshal([]byte(`["SOME_ENV"]`), &stepEnv)
 	require.NoError(t, err)
 
+	var batchChangeStepEnv env.Environment
+	err = json.Unmarshal([]byte(`{"NAME": "${{ batch_change.name }}"}`), &batchChangeStepEnv)
+	require.NoError(t, err)
+
 	modDate := time.Date(2022, 1, 2, 3, 5, 6, 7, time.UTC)
 
 	tests := []struct {
@@ -54,7 +59,91 @@ func TestKeyer_Key(t *testing.T) {
 				Steps:      []batches.Step{{Run: "foo"}},
 				StepIndex:  0,
 			},
-			expectedKey: "NxWM6tGwnsIG5EoaivFOsg-step-0",
+			expectedKey: "hbrwvL-mk52rH8cXzbY2BA-step-0",
+		},
+		{
+			name: "unused batch change attributes",
+			keyer: &CacheKey{
+				Repository:            repo,
+				Steps:                 []batches.Step{{Run: "foo"}},
+				BatchChangeAttributes: &template.BatchChangeAttributes{Name: "my-batch-change"},
+				StepIndex:             0,
+			},
+			// Same as "simple": the attributes don't affect the result.
+			expectedKey: "hbrwvL-mk52rH8cXzbY2BA-step-0",
+		},
+		{
+			name: "used batch change attributes",
+			keyer: &CacheKey{
+				Repository:            repo,
+				Steps:                 []batches.Step{{Run: "echo ${{ batch_change.name }}"}},
+				BatchChangeAttributes: &template.BatchChangeAttributes{Name: "my-batch-change"},
+				StepIndex:             0,
+			},
+			expectedKey: "cOoI2W8WmX0SIvIBaP-oEw-step-0",
+		},
+		{
+			name: "changed batch change attributes",
+			keyer: &CacheKey{
+				Repository:            repo,
+				Steps:                 []batches.Step{{Run: "echo ${{ batch_change.name }}"}},
+				BatchChangeAttributes: &template.BatchChangeAttributes{Name: "other-batch-change"},
+				StepIndex:             0,
+			},
+			expectedKey: "sr3j7c1w4WwbT2ptISWfWw-step-0",
+		},
+		{
+			name: "batch change mentioned outside of templates",
+			keyer: &CacheKey{
+				Repository:            repo,
+				Steps:                 []batches.Step{{Run: "foo batch_change"}},
+				BatchChangeAttributes: &template.BatchChangeAttributes{Name: "my-batch-change"},
+				StepIndex:             0,
+			},
+			// The attributes are left out because they aren't referenced in a template.
+			expectedKey: "f-qQqdtGArQxmJSZPXf7TA-step-0",
+		},
+		{
+			name: "batch change attributes used in files",
+			keyer: &CacheKey{
+				Repository:            repo,
+				Steps:                 []batches.Step{{Run: "foo", Files: map[string]string{"name.txt": "${{ index batch_change \"name\" }}"}}},
+				BatchChangeAttributes: &template.BatchChangeAttributes{Name: "my-batch-change"},
+				StepIndex:             0,
+			},
+			expectedKey: "KnQRwk245BdK55kWsw1pCw-step-0",
+		},
+		{
+			name: "batch change attributes used in step env",
+			keyer: &CacheKey{
+				Repository:            repo,
+				Steps:                 []batches.Step{{Run: "foo", Env: batchChangeStepEnv}},
+				BatchChangeAttributes: &template.BatchChangeAttributes{Name: "my-batch-change"},
+				StepIndex:             0,
+			},
+			expectedKey: "szLWaiyC3QXBR98KpowrsQ-step-0",
+		},
+		{
+			name: "batch change attributes used in previous step",
+			keyer: &CacheKey{
+				Repository: repo,
+				Steps: []batches.Step{
+					{Run: "foo", If: "${{ eq batch_change.name \"my-batch-change\" }}"},
+					{Run: "bar"},
+				},
+				BatchChangeAttributes: &template.BatchChangeAttributes{Name: "my-batch-change"},
+				StepIndex:             1,
+			},
+			expectedKey: "YkVwUGonx5vFoDnqWujgLA-step-1",
+		},
+		{
+			name: "invalid step template",
+			keyer: &CacheKey{
+				Repository: repo,
+				Steps:      []batches.Step{{Run: "${{ foo }}"}},
+				StepIndex:  0,
+			},
+			expectedError: errors.New(`parsing templates of step 0: template: references:1: function "foo" not defined`),
 		},
 		{
 			name: "multiple steps",
@@ -66,7 +155,7 @@ func TestKeyer_Key(t *testing.T) {
 				},
 				StepIndex: 1,
 			},
-			expectedKey: "_zR95x8sdhauCYxjtOHCbA-step-1",
+			expectedKey: "tRRNhCn_eEb40DsF9cTLlg-step-1",
 		},
 		{
 			name: "step env",
@@ -75,7 +164,7 @@ func TestKeyer_Key(t *testing.T) {
 				Steps:      []batches.Step{{Run: "foo", Env: singleStepEnv}},
 				StepIndex:  0,
 			},
-			expectedKey: "wKBVeg3u99TBq2U0qxx6cA-step-0",
+			expectedKey: "P0p7rbyZjmSJx_rvNAl0cQ-step-0",
 		},
 		{
 			name: "multiple step envs",
@@ -84,7 +173,7 @@ func TestKeyer_Key(t *testing.T) {
 				Steps:      []batches.Step{{Run: "foo", Env: multipleStepEnv}},
 				StepIndex:  0,
 			},
-			expectedKey: "YO88Tvj7bzCjP5pRag-9bQ-step-0",
+			expectedKey: "oEZqcpl-upW8K7DlXYPGCw-step-0",
 		},
 		{
 			name: "null step env",
@@ -93,7 +182,7 @@ func TestKeyer_Key(t *testing.T) {
 				Steps:      []batches.Step{{Run: "foo", Env: nullStepEnv}},
 				StepIndex:  0,
 			},
-			expectedKey: "wFqInfgY7mpK9F4qpW2iew-step-0",
+			expectedKey: "aGH-XS6-n4GUoqtLYepuQA-step-0",
 		},
 		{
 			name: "mount metadata",
@@ -105,7 +194,7 @@ func TestKeyer_Key(t *testing.T) {
 				},
 				StepIndex: 0,
 			},
-			expectedKey: "cpXzPMXfSM2ZmXYW_gAEyw-step-0",
+			expectedKey: "93thSKW45XoGoUHi-ERBhw-step-0",
 		},
 		{
 			name: "multiple mount metadata",
@@ -120,7 +209,7 @@ func TestKeyer_Key(t *testing.T) {
 				},
 				StepIndex: 0,
 			},
-			expectedKey: "ZSeYkHFJFD0TYsbKsgVjqQ-step-0",
+			expectedKey: "9WFq4HGnfUb8DB48wfu1gA-step-0",
 		},
 		{
 			name: "mount metadata error",
@@ -142,7 +231,7 @@ func TestKeyer_Key(t *testing.T) {
 				GlobalEnv:  []string{"SOME_ENV=FOO", "FAZ=BAZ"},
 				StepIndex:  0,
 			},
-			expectedKey: "sBrPdQ_SaeTHL5cNRtl0uA-step-0",
+			expectedKey: "zKR3eLOKS185_VoB49Y3GA-step-0",
 		},
 		{
 			name: "env var not in global env",
@@ -152,7 +241,7 @@ func TestKeyer_Key(t *testing.T) {
 				GlobalEnv:  []string{"FAZ=BAZ"},
 				StepIndex:  0,
 			},
-			expectedKey: "cs52Db_-N1MfDw2_0FFulw-step-0",
+			expectedKey: "y8qrG4UX6gC_HTOorSM_SA-step-0",
 		},
 		{
 			name: "no global env but forwarded",
@@ -161,7 +250,7 @@ func TestKeyer_Key(t *testing.T) {
 				Steps:      []batches.Step{{Run: "foo", Env: stepEnv}},
 				StepIndex:  0,
 			},
-			expectedKey: "cs52Db_-N1MfDw2_0FFulw-step-0",
+			expectedKey: "y8qrG4UX6gC_HTOorSM_SA-step-0",
 		},
 		{
 			name: "malformed global env",
@@ -328,6 +328,12 @@ const BatchSpecJSON = `{
           "type": "boolean",
           "description": "Whether to publish the changeset to a fork of the target repository. If omitted, the changeset will be published to a branch directly on the target repository, unless the global ` + "`" + `batches.enforceFork` + "`" + ` setting is enabled. If set, this property will override any global setting."
         },
+        "reviewers": {
+          "type": "array",
+          "description": "The usernames of the users to request reviews from when the changeset is published, in addition to the default reviewers of the repository. Supported on Bitbucket Server / Bitbucket Data Center and Gerrit; ignored on other code hosts.",
+          "items": { "type": "string" },
+          "examples": [["alice", "bob"]]
+        },
         "commit": {
           "title": "ExpandedGitCommitDescription",
           "type": "object",
@@ -68,6 +68,11 @@ const ChangesetSpecJSON = `{
         },
         "title": { "type": "string", "description": "The title of the changeset on the code host." },
         "body": { "type": "string", "description": "The body (description) of the changeset on the code host." },
+        "reviewers": {
+          "type": "array",
+          "description": "The usernames of the users to request reviews from when the changeset is published.",
+          "items": { "type": "string" }
+        },
         "commits": {
           "type": "array",
           "description": "The Git commits with the proposed changes. These commits are pushed to the head ref.",
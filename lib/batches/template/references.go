@@ -0,0 +1,83 @@
+package template
+
+import (
+	"text/template/parse"
+)
+
+// ReferencesBatchChange parses the input as a step template and returns true if
+// it references the batch_change template variable, i.e. if the result of
+// rendering it depends on the attributes of the batch change.
+func ReferencesBatchChange(input string) (bool, error) {
+	t, err := New("references", input, "", (&StepContext{}).ToFuncMap())
+	if err != nil {
+		return false, err
+	}
+	if t.Tree == nil {
+		return false, nil
+	}
+
+	return referencesIdent(t.Tree.Root, "batch_change"), nil
+}
+
+// referencesIdent walks the given parse.Node and returns true if any of its
+// sub-nodes is the identifier with the given name, e.g. a function call or
+// the start of a field chain.
+func referencesIdent(n parse.Node, ident string) bool {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, c := range n.Nodes {
+			if referencesIdent(c, ident) {
+				return true
+			}
+		}
+
+	case *parse.ActionNode:
+		return referencesIdent(n.Pipe, ident)
+
+	case *parse.IfNode:
+		return referencesBranch(&n.BranchNode, ident)
+
+	case *parse.RangeNode:
+		return referencesBranch(&n.BranchNode, ident)
+
+	case *parse.WithNode:
+		return referencesBranch(&n.BranchNode, ident)
+
+	case *parse.TemplateNode:
+		return referencesIdent(n.Pipe, ident)
+
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for _, c := range n.Cmds {
+			if referencesIdent(c, ident) {
+				return true
+			}
+		}
+
+	case *parse.CommandNode:
+		for _, a := range n.Args {
+			if referencesIdent(a, ident) {
+				return true
+			}
+		}
+
+	case *parse.ChainNode:
+		return referencesIdent(n.Node, ident)
+
+	case *parse.IdentifierNode:
+		return n.Ident == ident
+	}
+
+	return false
+}
+
+func referencesBranch(n *parse.BranchNode, ident string) bool {
+	return referencesIdent(n.Pipe, ident) ||
+		referencesIdent(n.List, ident) ||
+		referencesIdent(n.ElseList, ident)
+}
@@ -0,0 +1,36 @@
+package template
+
+import "testing"
+
+func TestReferencesBatchChange(t *testing.T) {
+	tests := map[string]bool{
+		``:                                  false,
+		`echo batch_change`:                 false,
+		`${{ repository.name }}`:            false,
+		`${{ outputs.batch_change }}`:       false,
+		`${{ batch_change.name }}`:          true,
+		`${{ index batch_change "name" }}`:  true,
+		`${{ eq batch_change.name "foo" }}`: true,
+		`${{ batch_change.name | printf }}`: true,
+		`${{ $name := batch_change.name }}`: true,
+		`${{ if true }}${{ else }}${{ batch_change.description }}${{ end }}`:       true,
+		`${{ range $p := split batch_change.description " " }}${{ $p }}${{ end }}`: true,
+		`${{ with repository.name }}${{ . }}${{ end }}`:                            false,
+	}
+
+	for tmpl, want := range tests {
+		t.Run(tmpl, func(t *testing.T) {
+			got, err := ReferencesBatchChange(tmpl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Fatalf("wrong result. want=%t, got=%t", want, got)
+			}
+		})
+	}
+
+	if _, err := ReferencesBatchChange(`${{ unknown_variable }}`); err == nil {
+		t.Fatal("expected error for undefined function")
+	}
+}
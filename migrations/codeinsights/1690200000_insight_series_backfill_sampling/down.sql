@@ -0,0 +1,4 @@
+ALTER TABLE insight_series
+    DROP COLUMN IF EXISTS sampling_strategy,
+    DROP COLUMN IF EXISTS sampling_tag_pattern,
+    DROP COLUMN IF EXISTS sampling_commit_interval;
@@ -0,0 +1,8 @@
+ALTER TABLE insight_series
+    ADD COLUMN IF NOT EXISTS sampling_strategy text NOT NULL DEFAULT 'interval',
+    ADD COLUMN IF NOT EXISTS sampling_tag_pattern text,
+    ADD COLUMN IF NOT EXISTS sampling_commit_interval integer;
+
+COMMENT ON COLUMN insight_series.sampling_strategy IS 'Determines the points in time at which the series is sampled during its backfill: interval, monthly, release-tags or commits.';
+COMMENT ON COLUMN insight_series.sampling_tag_pattern IS 'The regular expression that tags must match to be sampled with the release-tags strategy.';
+COMMENT ON COLUMN insight_series.sampling_commit_interval IS 'The number of commits between samples with the commits strategy.';
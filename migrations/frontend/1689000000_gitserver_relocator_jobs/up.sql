@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS gitserver_relocator_jobs_repo_id_idx ON gitserver_relocator_jobs (repo_id);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS repo_legal_holds
+(
+    repo_id    INTEGER PRIMARY KEY REFERENCES repo(id) ON DELETE CASCADE,
+    reason     TEXT                     NOT NULL,
+    created_by INTEGER                  REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
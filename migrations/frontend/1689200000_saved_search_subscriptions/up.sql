@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS saved_search_subscriptions
+(
+    id                      SERIAL PRIMARY KEY,
+    saved_search_id         INTEGER                  NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    user_id                 INTEGER                  NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    notify_email            BOOLEAN                  NOT NULL DEFAULT FALSE,
+    webhook_url             TEXT,
+    slack_webhook_url       TEXT,
+    interval_minutes        INTEGER                  NOT NULL DEFAULT 60 CHECK (interval_minutes > 0),
+    digest_interval_minutes INTEGER                  NOT NULL DEFAULT 0 CHECK (digest_interval_minutes >= 0),
+    muted                   BOOLEAN                  NOT NULL DEFAULT FALSE,
+    last_result_keys        TEXT[],
+    pending_results         JSONB                    NOT NULL DEFAULT '[]',
+    next_run_at             TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_run_at             TIMESTAMP WITH TIME ZONE,
+    last_notified_at        TIMESTAMP WITH TIME ZONE,
+    last_error              TEXT,
+    created_at              TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (saved_search_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS saved_search_subscriptions_next_run_at ON saved_search_subscriptions (next_run_at);
+
+COMMENT ON COLUMN saved_search_subscriptions.last_result_keys IS 'The keys of the results of the last run. NULL until the first run, which only records the results.';
+COMMENT ON COLUMN saved_search_subscriptions.pending_results IS 'New results that have not been delivered yet, because the subscription delivers digests.';
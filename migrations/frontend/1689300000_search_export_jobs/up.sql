@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS search_export_jobs
+(
+    id                SERIAL PRIMARY KEY,
+    state             TEXT                     DEFAULT 'queued',
+    queued_at         TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    failure_message   TEXT,
+    started_at        TIMESTAMP WITH TIME ZONE,
+    finished_at       TIMESTAMP WITH TIME ZONE,
+    process_after     TIMESTAMP WITH TIME ZONE,
+    num_resets        INTEGER                  NOT NULL DEFAULT 0,
+    num_failures      INTEGER                  NOT NULL DEFAULT 0,
+    last_heartbeat_at TIMESTAMP WITH TIME ZONE,
+    execution_logs    JSON[],
+    worker_hostname   TEXT                     NOT NULL DEFAULT '',
+
+    user_id           INTEGER                  NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    query             TEXT                     NOT NULL,
+    format            TEXT                     NOT NULL CHECK (format IN ('csv', 'jsonl')),
+    result_count      INTEGER                  NOT NULL DEFAULT 0,
+    object_key        TEXT,
+    signing_secret    BYTEA                    NOT NULL,
+    created_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS search_export_jobs_user_id ON search_export_jobs (user_id);
+
+COMMENT ON COLUMN search_export_jobs.object_key IS 'The key of the export in the upload store. NULL until the export completed.';
+COMMENT ON COLUMN search_export_jobs.signing_secret IS 'The secret the download links of the export are signed with.';
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS search_context_materialized_repos;
+DROP TABLE IF EXISTS search_context_includes;
+
+ALTER TABLE search_contexts
+    DROP COLUMN IF EXISTS repository_queries,
+    DROP COLUMN IF EXISTS version,
+    DROP COLUMN IF EXISTS materialized_version,
+    DROP COLUMN IF EXISTS materialized_at;
@@ -0,0 +1,34 @@
+ALTER TABLE search_contexts
+    ADD COLUMN IF NOT EXISTS repository_queries   TEXT[]                   NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS version              INTEGER                  NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS materialized_version INTEGER                  NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS materialized_at      TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN search_contexts.repository_queries IS 'Repository queries (e.g. repo:^github\.com/org archived:no) whose matching repositories are part of the search context.';
+COMMENT ON COLUMN search_contexts.version IS 'Incremented whenever the definition of the search context, or of a search context it includes, changes.';
+COMMENT ON COLUMN search_contexts.materialized_version IS 'The version of the search context that search_context_materialized_repos was computed for.';
+
+CREATE TABLE IF NOT EXISTS search_context_includes
+(
+    search_context_id          BIGINT NOT NULL REFERENCES search_contexts(id) ON DELETE CASCADE DEFERRABLE,
+    included_search_context_id BIGINT NOT NULL REFERENCES search_contexts(id) ON DELETE CASCADE DEFERRABLE,
+    PRIMARY KEY (search_context_id, included_search_context_id),
+    CONSTRAINT search_context_includes_not_self CHECK (search_context_id <> included_search_context_id)
+);
+
+CREATE INDEX IF NOT EXISTS search_context_includes_included_search_context_id ON search_context_includes (included_search_context_id);
+
+COMMENT ON TABLE search_context_includes IS 'The search contexts whose repositories are part of another search context.';
+
+CREATE TABLE IF NOT EXISTS search_context_materialized_repos
+(
+    search_context_id BIGINT  NOT NULL REFERENCES search_contexts(id) ON DELETE CASCADE DEFERRABLE,
+    version           INTEGER NOT NULL,
+    repo_id           INTEGER NOT NULL REFERENCES repo(id) ON DELETE CASCADE DEFERRABLE,
+    revision          TEXT    NOT NULL,
+    PRIMARY KEY (search_context_id, version, repo_id, revision)
+);
+
+CREATE INDEX IF NOT EXISTS search_context_materialized_repos_repo_id ON search_context_materialized_repos (repo_id);
+
+COMMENT ON TABLE search_context_materialized_repos IS 'The repositories and revisions of search contexts composed from other search contexts and repository queries, resolved for a version of the search context.';
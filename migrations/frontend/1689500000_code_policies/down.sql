@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS code_policy_violations;
+DROP TABLE IF EXISTS code_policies;
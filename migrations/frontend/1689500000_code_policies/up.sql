@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS code_policies
+(
+    id               SERIAL PRIMARY KEY,
+    name             TEXT                     NOT NULL UNIQUE,
+    description      TEXT                     NOT NULL DEFAULT '',
+    query            TEXT                     NOT NULL,
+    repo_query       TEXT                     NOT NULL DEFAULT '',
+    webhook_url      TEXT,
+    enabled          BOOLEAN                  NOT NULL DEFAULT TRUE,
+    interval_minutes INTEGER                  NOT NULL DEFAULT 60 CHECK (interval_minutes > 0),
+    creator_id       INTEGER                  REFERENCES users(id) ON DELETE SET NULL,
+    next_run_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_run_at      TIMESTAMP WITH TIME ZONE,
+    last_error       TEXT,
+    created_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS code_policies_next_run_at ON code_policies (next_run_at) WHERE enabled;
+
+COMMENT ON COLUMN code_policies.repo_query IS 'Repository filters (such as "repo:^github\.com/org/") that select the repositories the policy query runs over.';
+COMMENT ON COLUMN code_policies.creator_id IS 'The site admin that created the policy. The policy query runs as this user.';
+
+CREATE TABLE IF NOT EXISTS code_policy_violations
+(
+    id             SERIAL PRIMARY KEY,
+    policy_id      INTEGER                  NOT NULL REFERENCES code_policies(id) ON DELETE CASCADE,
+    repo_id        INTEGER                  NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    fingerprint    TEXT                     NOT NULL,
+    path           TEXT                     NOT NULL DEFAULT '',
+    commit         TEXT                     NOT NULL DEFAULT '',
+    line_number    INTEGER                  NOT NULL DEFAULT 0,
+    preview        TEXT                     NOT NULL DEFAULT '',
+    state          TEXT                     NOT NULL DEFAULT 'open' CHECK (state IN ('open', 'resolved')),
+    first_seen_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resolved_at    TIMESTAMP WITH TIME ZONE,
+    resolved_by_id INTEGER                  REFERENCES users(id) ON DELETE SET NULL,
+    UNIQUE (policy_id, repo_id, fingerprint)
+);
+
+CREATE INDEX IF NOT EXISTS code_policy_violations_repo_id_state ON code_policy_violations (repo_id, state);
+CREATE INDEX IF NOT EXISTS code_policy_violations_policy_id_state ON code_policy_violations (policy_id, state);
+
+COMMENT ON COLUMN code_policy_violations.fingerprint IS 'Identifies the violation across runs, independently of the commit and line it was found at.';
+COMMENT ON COLUMN code_policy_violations.resolved_by_id IS 'The user that resolved the violation manually. NULL if the violation was resolved because the policy query no longer matches it.';
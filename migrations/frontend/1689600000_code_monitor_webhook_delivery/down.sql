@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS cm_webhook_dead_letters;
+
+ALTER TABLE cm_webhooks DROP COLUMN IF EXISTS signing_secret;
+ALTER TABLE cm_webhooks DROP COLUMN IF EXISTS payload_template;
@@ -0,0 +1,21 @@
+ALTER TABLE cm_webhooks ADD COLUMN IF NOT EXISTS payload_template TEXT;
+ALTER TABLE cm_webhooks ADD COLUMN IF NOT EXISTS signing_secret TEXT;
+
+COMMENT ON COLUMN cm_webhooks.payload_template IS 'An optional Go template rendered over the monitor results to build the request body. The default JSON payload is sent when NULL.';
+COMMENT ON COLUMN cm_webhooks.signing_secret IS 'An optional shared secret used to sign deliveries with an HMAC-SHA256 of the request body.';
+
+CREATE TABLE IF NOT EXISTS cm_webhook_dead_letters
+(
+    id              BIGSERIAL PRIMARY KEY,
+    webhook         BIGINT                   NOT NULL REFERENCES cm_webhooks(id) ON DELETE CASCADE,
+    action_job      INTEGER                  REFERENCES cm_action_jobs(id) ON DELETE SET NULL,
+    payload         TEXT                     NOT NULL,
+    failure_message TEXT                     NOT NULL,
+    num_attempts    INTEGER                  NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS cm_webhook_dead_letters_webhook ON cm_webhook_dead_letters (webhook, id);
+
+COMMENT ON TABLE cm_webhook_dead_letters IS 'Code monitor webhook deliveries that kept failing after all retries were exhausted.';
+COMMENT ON COLUMN cm_webhook_dead_letters.payload IS 'The rendered request body of the last delivery attempt.';
@@ -0,0 +1 @@
+ALTER TABLE cm_webhooks DROP COLUMN IF EXISTS encryption_key_id;
@@ -0,0 +1,3 @@
+ALTER TABLE cm_webhooks ADD COLUMN IF NOT EXISTS encryption_key_id TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN cm_webhooks.encryption_key_id IS 'The identifier of the key used to encrypt signing_secret. Empty if the secret is stored in plaintext.';
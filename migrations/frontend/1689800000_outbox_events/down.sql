@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS outbox_consumer_offsets;
+DROP TABLE IF EXISTS outbox_events;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id bigserial PRIMARY KEY,
+    txid bigint NOT NULL DEFAULT txid_current(),
+    event_type text NOT NULL,
+    aggregate_type text NOT NULL,
+    aggregate_id text NOT NULL,
+    payload jsonb NOT NULL DEFAULT '{}'::jsonb,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS outbox_events_txid_id ON outbox_events (txid, id);
+CREATE INDEX IF NOT EXISTS outbox_events_created_at ON outbox_events (created_at);
+
+COMMENT ON COLUMN outbox_events.txid IS 'The ID of the transaction that published the event. Events are delivered in (txid, id) order once all transactions with a lower ID have finished, so that events committed out of order are never skipped.';
+
+CREATE TABLE IF NOT EXISTS outbox_consumer_offsets (
+    consumer_group text PRIMARY KEY,
+    last_txid bigint NOT NULL DEFAULT 0,
+    last_event_id bigint NOT NULL DEFAULT 0,
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
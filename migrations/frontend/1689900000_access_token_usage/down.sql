@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS access_token_usage_alerts;
+DROP TABLE IF EXISTS access_token_usage;
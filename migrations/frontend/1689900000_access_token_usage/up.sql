@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS access_token_usage (
+    access_token_id bigint NOT NULL REFERENCES access_tokens(id) ON DELETE CASCADE,
+    bucket timestamp with time zone NOT NULL,
+    endpoint text NOT NULL,
+    operation text NOT NULL DEFAULT '',
+    request_count integer NOT NULL DEFAULT 0,
+    error_count integer NOT NULL DEFAULT 0,
+    response_bytes bigint NOT NULL DEFAULT 0,
+    latency_buckets integer[] NOT NULL DEFAULT '{}',
+    PRIMARY KEY (access_token_id, bucket, endpoint, operation)
+);
+
+CREATE INDEX IF NOT EXISTS access_token_usage_bucket ON access_token_usage (bucket);
+
+COMMENT ON COLUMN access_token_usage.bucket IS 'The start of the hour during which the requests were made.';
+COMMENT ON COLUMN access_token_usage.latency_buckets IS 'The number of requests per latency bucket. The bounds of the buckets are defined in the database package.';
+
+CREATE TABLE IF NOT EXISTS access_token_usage_alerts (
+    id bigserial PRIMARY KEY,
+    access_token_id bigint NOT NULL REFERENCES access_tokens(id) ON DELETE CASCADE,
+    bucket timestamp with time zone NOT NULL,
+    request_count integer NOT NULL,
+    baseline_request_count double precision NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    UNIQUE (access_token_id, bucket)
+);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS batch_change_rollout_policies (
+    batch_change_id bigint PRIMARY KEY REFERENCES batch_changes(id) ON DELETE CASCADE DEFERRABLE,
+    max_changesets integer NOT NULL,
+    window_seconds integer NOT NULL DEFAULT 86400,
+    prioritize_by_activity boolean NOT NULL DEFAULT false,
+    min_merge_rate double precision,
+    max_ci_failure_rate double precision,
+    window_started_at timestamp with time zone,
+    published_in_window integer NOT NULL DEFAULT 0,
+    paused_at timestamp with time zone,
+    pause_reason text,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT batch_change_rollout_policies_max_changesets_positive CHECK (max_changesets > 0),
+    CONSTRAINT batch_change_rollout_policies_window_seconds_positive CHECK (window_seconds > 0)
+);
+
+COMMENT ON TABLE batch_change_rollout_policies IS 'Limits how many changesets of a batch change are published per time window.';
+COMMENT ON COLUMN batch_change_rollout_policies.min_merge_rate IS 'The rollout is paused when the share of published changesets that are merged drops below this value.';
+COMMENT ON COLUMN batch_change_rollout_policies.max_ci_failure_rate IS 'The rollout is paused when the share of published changesets with completed checks whose checks failed exceeds this value.';
+COMMENT ON COLUMN batch_change_rollout_policies.published_in_window IS 'The number of changesets published since window_started_at.';
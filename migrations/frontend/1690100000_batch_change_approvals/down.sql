@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS batch_spec_approvals;
+DROP TABLE IF EXISTS batch_change_approvers;
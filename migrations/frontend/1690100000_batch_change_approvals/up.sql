@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS batch_change_approvers (
+    batch_change_id bigint NOT NULL REFERENCES batch_changes(id) ON DELETE CASCADE DEFERRABLE,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (batch_change_id, user_id)
+);
+
+COMMENT ON TABLE batch_change_approvers IS 'Users who must approve the batch spec of a batch change before its changesets can be published.';
+
+CREATE TABLE IF NOT EXISTS batch_spec_approvals (
+    batch_spec_id bigint NOT NULL REFERENCES batch_specs(id) ON DELETE CASCADE DEFERRABLE,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (batch_spec_id, user_id)
+);
+
+COMMENT ON TABLE batch_spec_approvals IS 'Approvals of batch specs by the approvers of their batch change.';
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS executor_job_artifacts
+(
+    id             SERIAL PRIMARY KEY,
+    queue          TEXT                     NOT NULL,
+    job_id         INTEGER                  NOT NULL,
+    name           TEXT                     NOT NULL,
+    content_type   TEXT                     NOT NULL,
+    size           BIGINT                   NOT NULL,
+    object_key     TEXT                     NOT NULL,
+    signing_secret BYTEA                    NOT NULL,
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at     TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS executor_job_artifacts_queue_job_id_name ON executor_job_artifacts (queue, job_id, name);
+CREATE INDEX IF NOT EXISTS executor_job_artifacts_expires_at ON executor_job_artifacts (expires_at);
+
+COMMENT ON COLUMN executor_job_artifacts.object_key IS 'The key of the artifact in the upload store.';
+COMMENT ON COLUMN executor_job_artifacts.signing_secret IS 'The secret the download links of the artifact are signed with.';
+COMMENT ON COLUMN executor_job_artifacts.expires_at IS 'The time after which the artifact is deleted according to the retention policy.';
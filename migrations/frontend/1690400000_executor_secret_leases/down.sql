@@ -0,0 +1 @@
+DROP TABLE IF EXISTS executor_secret_leases;
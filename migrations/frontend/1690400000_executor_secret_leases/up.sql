@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS executor_secret_leases
+(
+    id         SERIAL PRIMARY KEY,
+    queue      TEXT                     NOT NULL,
+    job_id     INTEGER                  NOT NULL,
+    provider   TEXT                     NOT NULL,
+    lease_id   TEXT                     NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS executor_secret_leases_queue_job_id ON executor_secret_leases (queue, job_id);
+
+COMMENT ON TABLE executor_secret_leases IS 'Leases of secrets resolved from external secret managers for executor jobs. Leases are revoked and deleted once their job completed.';
+COMMENT ON COLUMN executor_secret_leases.provider IS 'The secret manager that issued the lease, e.g. vault.';
+COMMENT ON COLUMN executor_secret_leases.lease_id IS 'The ID of the lease in the secret manager.';
+COMMENT ON COLUMN executor_secret_leases.expires_at IS 'The time the lease expires in the secret manager, if known.';
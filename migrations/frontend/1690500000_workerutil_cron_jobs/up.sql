@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS workerutil_cron_jobs
+(
+    name              TEXT PRIMARY KEY,
+    last_scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_finished_at  TIMESTAMP WITH TIME ZONE,
+    last_error        TEXT,
+    updated_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE workerutil_cron_jobs IS 'The run history of recurring jobs registered in code. Schedulers claim a run by advancing last_scheduled_at, so each scheduled run is executed once across all instances.';
+COMMENT ON COLUMN workerutil_cron_jobs.last_scheduled_at IS 'The scheduled time of the last claimed run. Runs scheduled after it and before now were missed.';
+COMMENT ON COLUMN workerutil_cron_jobs.last_error IS 'The error of the last run, or NULL if it succeeded.';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS workerutil_dead_letters;
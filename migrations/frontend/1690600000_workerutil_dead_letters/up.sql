@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS workerutil_dead_letters
+(
+    id                 SERIAL PRIMARY KEY,
+    store_name         TEXT                     NOT NULL,
+    record_id          INTEGER                  NOT NULL,
+    failure_message    TEXT,
+    stack              TEXT,
+    num_crashes        INTEGER                  NOT NULL,
+    retry_requested_at TIMESTAMP WITH TIME ZONE,
+    created_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS workerutil_dead_letters_store_name_record_id ON workerutil_dead_letters (store_name, record_id);
+
+COMMENT ON TABLE workerutil_dead_letters IS 'Worker records that repeatedly crashed their worker and were moved to the failed state instead of being retried.';
+COMMENT ON COLUMN workerutil_dead_letters.store_name IS 'The name of the dbworker store the record belongs to.';
+COMMENT ON COLUMN workerutil_dead_letters.stack IS 'The stack trace of the last crash, if the handler panicked.';
+COMMENT ON COLUMN workerutil_dead_letters.retry_requested_at IS 'When a site admin requested to retry the record. The record is requeued by the resetter of its store.';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS redis_key_value_expires_at;
+
+ALTER TABLE redis_key_value DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,5 @@
+ALTER TABLE redis_key_value ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS redis_key_value_expires_at ON redis_key_value (expires_at) WHERE expires_at IS NOT NULL;
+
+COMMENT ON COLUMN redis_key_value.expires_at IS 'When the value expires. Expired values are ignored when read and deleted periodically.';
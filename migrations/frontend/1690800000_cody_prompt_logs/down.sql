@@ -0,0 +1 @@
+DROP TABLE IF EXISTS cody_prompt_logs;
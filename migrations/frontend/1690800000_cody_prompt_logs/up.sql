@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS cody_prompt_logs (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL DEFERRABLE,
+    anonymous_user_id TEXT,
+    intent TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    model TEXT NOT NULL,
+    prompt TEXT NOT NULL,
+    response TEXT NOT NULL,
+    encryption_key_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS cody_prompt_logs_created_at ON cody_prompt_logs (created_at);
+
+COMMENT ON TABLE cody_prompt_logs IS 'The prompts and responses of Cody completions requests, recorded for audit purposes if enabled in the site configuration.';
+COMMENT ON COLUMN cody_prompt_logs.prompt IS 'The JSON encoded messages of the prompt, encrypted with the key identified by encryption_key_id.';
+COMMENT ON COLUMN cody_prompt_logs.response IS 'The completion, encrypted with the key identified by encryption_key_id.';
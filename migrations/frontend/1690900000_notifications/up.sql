@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+    kind TEXT NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    url TEXT NOT NULL DEFAULT '',
+    in_app BOOLEAN NOT NULL DEFAULT TRUE,
+    digest_pending BOOLEAN NOT NULL DEFAULT FALSE,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_id_id ON notifications (user_id, id) WHERE in_app;
+CREATE INDEX IF NOT EXISTS notifications_digest_pending ON notifications (user_id, created_at) WHERE digest_pending;
+
+COMMENT ON TABLE notifications IS 'The notifications of users, shown in their notifications inbox and collected for their email digests.';
+COMMENT ON COLUMN notifications.url IS 'The URL of the subject of the notification, relative to the external URL.';
+COMMENT ON COLUMN notifications.in_app IS 'Whether the notification is shown in the notifications inbox of the user.';
+COMMENT ON COLUMN notifications.digest_pending IS 'Whether the notification is yet to be sent in an email digest.';
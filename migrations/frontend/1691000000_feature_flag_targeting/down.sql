@@ -0,0 +1,3 @@
+ALTER TABLE feature_flags
+    DROP COLUMN IF EXISTS rollout_bucket_by,
+    DROP COLUMN IF EXISTS targeting_rules;
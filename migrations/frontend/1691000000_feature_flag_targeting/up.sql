@@ -0,0 +1,9 @@
+ALTER TABLE feature_flags
+    ADD COLUMN IF NOT EXISTS rollout_bucket_by text NOT NULL DEFAULT 'user',
+    ADD COLUMN IF NOT EXISTS targeting_rules jsonb NOT NULL DEFAULT '[]'::jsonb;
+
+ALTER TABLE feature_flags DROP CONSTRAINT IF EXISTS feature_flags_rollout_bucket_by_check;
+ALTER TABLE feature_flags ADD CONSTRAINT feature_flags_rollout_bucket_by_check CHECK (rollout_bucket_by IN ('user', 'org'));
+
+COMMENT ON COLUMN feature_flags.rollout_bucket_by IS 'Whether rollout flags bucket users by their own ID (user) or by the IDs of their organizations (org).';
+COMMENT ON COLUMN feature_flags.targeting_rules IS 'Ordered rules that set the value of the flag for the members of an organization or the holders of a role, ahead of the value or rollout of the flag.';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS repo_archival;
+DROP TABLE IF EXISTS repo_archival_policy;
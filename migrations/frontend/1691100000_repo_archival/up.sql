@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS repo_archival_policy
+(
+    id                    INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    enabled               BOOLEAN                  NOT NULL DEFAULT FALSE,
+    inactive_months       INTEGER                  NOT NULL DEFAULT 12 CHECK (inactive_months > 0),
+    reduce_sync_frequency BOOLEAN                  NOT NULL DEFAULT TRUE,
+    drop_embeddings       BOOLEAN                  NOT NULL DEFAULT FALSE,
+    drop_codeintel        BOOLEAN                  NOT NULL DEFAULT FALSE,
+    updated_by            INTEGER                  REFERENCES users(id) ON DELETE SET NULL,
+    updated_at            TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO repo_archival_policy (id) VALUES (1) ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS repo_archival
+(
+    repo_id     INTEGER PRIMARY KEY REFERENCES repo(id) ON DELETE CASCADE,
+    override    TEXT                     CHECK (override IN ('never', 'always')),
+    archived_at TIMESTAMP WITH TIME ZONE,
+    restored_at TIMESTAMP WITH TIME ZONE,
+    updated_by  INTEGER                  REFERENCES users(id) ON DELETE SET NULL,
+    updated_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN repo_archival.override IS 'never: the repository is never archived, always: the repository is archived regardless of its activity.';
+COMMENT ON COLUMN repo_archival.restored_at IS 'Repositories restored on demand are not archived again until they have been inactive for the period of the policy since their restoration.';
+
+-- The set of archived repositories is read by every search indexer
+-- configuration request and anti-joined by the repository list, the
+-- auto-indexing scheduler and the embeddings scheduler. The partial index keeps
+-- these lookups to the archived rows.
+CREATE INDEX IF NOT EXISTS repo_archival_archived_repo_id_idx ON repo_archival (repo_id) WHERE archived_at IS NOT NULL;
+
+-- Used to check whether the archival state of any repository changed since a
+-- given time.
+CREATE INDEX IF NOT EXISTS repo_archival_updated_at_idx ON repo_archival (updated_at);
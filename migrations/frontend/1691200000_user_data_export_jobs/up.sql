@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS user_data_export_jobs
+(
+    id                SERIAL PRIMARY KEY,
+    state             TEXT                     DEFAULT 'queued',
+    queued_at         TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    failure_message   TEXT,
+    started_at        TIMESTAMP WITH TIME ZONE,
+    finished_at       TIMESTAMP WITH TIME ZONE,
+    process_after     TIMESTAMP WITH TIME ZONE,
+    num_resets        INTEGER                  NOT NULL DEFAULT 0,
+    num_failures      INTEGER                  NOT NULL DEFAULT 0,
+    last_heartbeat_at TIMESTAMP WITH TIME ZONE,
+    execution_logs    JSON[],
+    worker_hostname   TEXT                     NOT NULL DEFAULT '',
+
+    user_id           INTEGER                  NOT NULL,
+    kind              TEXT                     NOT NULL CHECK (kind IN ('export', 'deletion_report')),
+    requested_by      INTEGER                  REFERENCES users(id) ON DELETE SET NULL,
+    object_key        TEXT,
+    signing_secret    BYTEA                    NOT NULL,
+    created_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS user_data_export_jobs_user_id ON user_data_export_jobs (user_id);
+
+COMMENT ON COLUMN user_data_export_jobs.user_id IS 'The user whose data is exported. Not a foreign key, so that the deletion report of a user outlives them.';
+COMMENT ON COLUMN user_data_export_jobs.object_key IS 'The key of the archive or report in the upload store. NULL until the job completed.';
+COMMENT ON COLUMN user_data_export_jobs.signing_secret IS 'The secret the download links of the archive or report are signed with.';
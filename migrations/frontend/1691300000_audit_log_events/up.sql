@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS audit_log_events (
+    id BIGSERIAL PRIMARY KEY,
+    action TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    org_id INTEGER,
+    actor_user_id INTEGER,
+    actor_ip TEXT,
+    actor_forwarded_for TEXT,
+    argument JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_events_created_at ON audit_log_events (created_at);
+CREATE INDEX IF NOT EXISTS audit_log_events_org_id_created_at ON audit_log_events (org_id, created_at) WHERE org_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS audit_log_events_action_created_at ON audit_log_events (action, created_at);
+
+COMMENT ON TABLE audit_log_events IS 'Changes to organizations, code host connections, repository permissions and access tokens, with the actor that made them.';
+COMMENT ON COLUMN audit_log_events.org_id IS 'The organization the change belongs to. It is not a foreign key so that events outlive the organization.';
+COMMENT ON COLUMN audit_log_events.actor_user_id IS 'The user that made the change, or NULL for internal and anonymous actors. It is not a foreign key so that events outlive the user.';
+COMMENT ON COLUMN audit_log_events.actor_ip IS 'The IP address of the client that made the change, if it was made in a request.';
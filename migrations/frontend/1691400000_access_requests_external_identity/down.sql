@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS access_requests_external_account;
+
+ALTER TABLE IF EXISTS access_requests
+    DROP COLUMN IF EXISTS username,
+    DROP COLUMN IF EXISTS email_verified,
+    DROP COLUMN IF EXISTS external_service_type,
+    DROP COLUMN IF EXISTS external_service_id,
+    DROP COLUMN IF EXISTS external_client_id,
+    DROP COLUMN IF EXISTS external_account_id,
+    DROP COLUMN IF EXISTS user_id;
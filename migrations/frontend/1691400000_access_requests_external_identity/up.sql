@@ -0,0 +1,13 @@
+ALTER TABLE IF EXISTS access_requests
+    ADD COLUMN IF NOT EXISTS username TEXT,
+    ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS external_service_type TEXT,
+    ADD COLUMN IF NOT EXISTS external_service_id TEXT,
+    ADD COLUMN IF NOT EXISTS external_client_id TEXT,
+    ADD COLUMN IF NOT EXISTS external_account_id TEXT,
+    ADD COLUMN IF NOT EXISTS user_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS access_requests_external_account ON access_requests (external_service_type, external_service_id, external_client_id, external_account_id) WHERE external_account_id IS NOT NULL;
+
+COMMENT ON COLUMN access_requests.external_account_id IS 'The external identity the request was made with, if the user signed in with an auth provider that does not allow signup. Approving the request creates an account bound to it.';
+COMMENT ON COLUMN access_requests.user_id IS 'The account created when the request was approved.';
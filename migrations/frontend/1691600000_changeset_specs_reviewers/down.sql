@@ -0,0 +1 @@
+ALTER TABLE changeset_specs DROP COLUMN IF EXISTS reviewers;
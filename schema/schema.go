@@ -54,6 +54,22 @@ type AWSKMSEncryptionKey struct {
 	Type            string `json:"type"`
 }
 
+// AWSSecretsManager description: The AWS Secrets Manager executor secrets are resolved from.
+type AWSSecretsManager struct {
+	// CredentialsFile description: The path to an AWS shared credentials file. If not set, the default AWS credential chain is used.
+	CredentialsFile string `json:"credentialsFile,omitempty"`
+	// Region description: The AWS region of the secrets. Defaults to the region of the default AWS configuration.
+	Region string `json:"region,omitempty"`
+}
+
+// AccessTokenUsageAlerts description: Raise alerts when the hourly number of API requests made with an access token is far above its usual hourly number of requests. Alerts are recorded in the security event log, and sent to outbound webhooks subscribed to the access_token:usage_spike event. Alerts are disabled if this is not set.
+type AccessTokenUsageAlerts struct {
+	// MinimumRequests description: Only raise an alert when at least this many requests were made during the hour.
+	MinimumRequests int `json:"minimumRequests,omitempty"`
+	// SpikeFactor description: Raise an alert when the hourly number of requests is at least this many times the average hourly number of requests of the access token over the previous 7 days.
+	SpikeFactor float64 `json:"spikeFactor,omitempty"`
+}
+
 // App description: Configuration options for App only.
 type App struct {
 	// DotcomAuthToken description: Authentication token for Sourcegraph.com. If present, indicates that the App account is connected to a Sourcegraph.com account.
@@ -86,12 +102,16 @@ type AuditLog struct {
 type AuthAccessRequest struct {
 	// Enabled description: Enable/disable the access request feature, which allows users to request access if built-in signup is disabled.
 	Enabled *bool `json:"enabled,omitempty"`
+	// ExternalIdentities description: Submit an access request when a user signs in with an auth provider that does not allow signup, instead of rejecting them. Approving the request creates their account bound to the external identity.
+	ExternalIdentities bool `json:"externalIdentities,omitempty"`
 }
 
 // AuthAccessTokens description: Settings for access tokens, which enable external tools to access the Sourcegraph API with the privileges of the user.
 type AuthAccessTokens struct {
 	// Allow description: Allow or restrict the use of access tokens. The default is "all-users-create", which enables all users to create access tokens. Use "none" to disable access tokens entirely. Use "site-admin-create" to restrict creation of new tokens to admin users (existing tokens will still work until revoked).
 	Allow string `json:"allow,omitempty"`
+	// UsageAlerts description: Raise alerts when the hourly number of API requests made with an access token is far above its usual hourly number of requests. Alerts are recorded in the security event log, and sent to outbound webhooks subscribed to the access_token:usage_spike event. Alerts are disabled if this is not set.
+	UsageAlerts *AccessTokenUsageAlerts `json:"usageAlerts,omitempty"`
 }
 
 // AuthLockout description: The config options for account lockout
@@ -531,6 +551,8 @@ type ChangesetTemplate struct {
 	Fork bool `json:"fork,omitempty"`
 	// Published description: Whether to publish the changeset. An unpublished changeset can be previewed on Sourcegraph by any person who can view the batch change, but its commit, branch, and pull request aren't created on the code host. A published changeset results in a commit, branch, and pull request being created on the code host. If omitted, the publication state is controlled from the Batch Changes UI.
 	Published any `json:"published,omitempty"`
+	// Reviewers description: The usernames of the users to request reviews from when the changeset is published, in addition to the default reviewers of the repository. Supported on Bitbucket Server / Bitbucket Data Center and Gerrit; ignored on other code hosts.
+	Reviewers []string `json:"reviewers,omitempty"`
 	// Title description: The title of the changeset.
 	Title string `json:"title"`
 }
@@ -558,6 +580,36 @@ type Codeintel struct {
 	Weight int `json:"weight"`
 }
 
+// CodyContextFilters description: Restricts the repositories whose content may be sent to the LLM as context for Cody. Filters are enforced on the server when context is retrieved and when completions are requested. Organizations can restrict their members further with the cody.contextFilters setting of the organization.
+type CodyContextFilters struct {
+	// Exclude description: Regular expressions matching the names of repositories whose content must never be sent, even if they are included.
+	Exclude []string `json:"exclude,omitempty"`
+	// Include description: Regular expressions matching the names of the only repositories whose content may be sent. If empty, all repositories are included.
+	Include []string `json:"include,omitempty"`
+}
+
+// CodyContextRetrieval description: Configures how context for Cody is retrieved. Results from embeddings similarity search, keyword search, and the precise code graph are merged according to the weight of each source. A source with a weight of 0 is disabled.
+type CodyContextRetrieval struct {
+	// CodeGraphMaxSymbols description: The maximum number of symbols mentioned in the prompt for which callers and callees are looked up.
+	CodeGraphMaxSymbols int `json:"codeGraphMaxSymbols,omitempty"`
+	// CodeGraphWeight description: The weight of code graph results, which are the callers and callees of symbols mentioned in the prompt. Code graph results require precise code intelligence for the repository.
+	CodeGraphWeight *float64 `json:"codeGraphWeight,omitempty"`
+	// EmbeddingsWeight description: The weight of embeddings similarity search results.
+	EmbeddingsWeight *float64 `json:"embeddingsWeight,omitempty"`
+	// KeywordWeight description: The weight of keyword search results.
+	KeywordWeight *float64 `json:"keywordWeight,omitempty"`
+}
+
+// CodyPromptLogging description: Records the prompts and responses of Cody completions requests for audit purposes. Records are encrypted with encryption.keys.codyPromptLogKey if configured, and can be exported by site admins with the codyPromptLogs GraphQL query.
+type CodyPromptLogging struct {
+	// Enabled description: Whether prompts and responses are recorded.
+	Enabled bool `json:"enabled,omitempty"`
+	// RedactPatterns description: Regular expressions matching personal data to redact from prompts and responses before they are recorded. Email addresses are always redacted.
+	RedactPatterns []string `json:"redactPatterns,omitempty"`
+	// Retention description: How long records are retained. The string format is that of the Duration type in the Go time package (https://golang.org/pkg/time/#ParseDuration). Values lower than 1 hour will be treated as 1 hour. By default, this is "720h", or thirty days.
+	Retention string `json:"retention,omitempty"`
+}
+
 // CodyGateway description: Configuration related to the Cody Gateway service management. This should only be used on sourcegraph.com.
 type CodyGateway struct {
 	// BigQueryDataset description: The dataset to pull BigQuery Cody Gateway related events from.
@@ -568,6 +620,14 @@ type CodyGateway struct {
 	BigQueryTable string `json:"bigQueryTable,omitempty"`
 }
 
+// CommitSignatures description: Configuration for verifying the signatures of commits and tags. Signatures made with a trusted key are reported as verified.
+type CommitSignatures struct {
+	// CodeHostVerification description: Whether to fall back to the verification status reported by the code host for signatures that are not made with a trusted key. Only supported for GitHub.
+	CodeHostVerification bool `json:"codeHostVerification,omitempty"`
+	// TrustedKeys description: The keys trusted to sign commits and tags.
+	TrustedKeys []*TrustedSigningKey `json:"trustedKeys,omitempty"`
+}
+
 // Completions description: Configuration for the completions service.
 type Completions struct {
 	// AccessToken description: The access token used to authenticate with the external completions provider. If using the default provider 'sourcegraph', and if 'licenseKey' is set, a default access token is generated.
@@ -582,20 +642,64 @@ type Completions struct {
 	CompletionModelMaxTokens int `json:"completionModelMaxTokens,omitempty"`
 	// Enabled description: DEPRECATED. Use cody.enabled instead to turn Cody on/off.
 	Enabled *bool `json:"enabled,omitempty"`
-	// Endpoint description: The endpoint under which to reach the provider. Currently only used for provider types "sourcegraph", "openai" and "anthropic". The default values are "https://cody-gateway.sourcegraph.com", "https://api.openai.com/v1/chat/completions", and "https://api.anthropic.com/v1/complete" for Sourcegraph, OpenAI, and Anthropic, respectively.
+	// Endpoint description: The endpoint under which to reach the provider. The default values are "https://cody-gateway.sourcegraph.com", "https://api.openai.com/v1/chat/completions", and "https://api.anthropic.com/v1/complete" for Sourcegraph, OpenAI, and Anthropic, respectively. The provider "azure-openai" has no default endpoint, it must be set to the chat completions URL of the deployment. To use a self-hosted model with an OpenAI-compatible API, use the provider "openai" with the endpoint of the model server.
 	Endpoint string `json:"endpoint,omitempty"`
 	// FastChatModel description: The model used for fast chat completions.
 	FastChatModel string `json:"fastChatModel,omitempty"`
 	// FastChatModelMaxTokens description: The maximum number of tokens to use as client when talking to fastChatModel. If not set, clients need to set their own limit.
 	FastChatModelMaxTokens int `json:"fastChatModelMaxTokens,omitempty"`
+	// Guardrails description: Guardrails enforced by the server on the prompts and completions of all completions requests.
+	Guardrails *CompletionsGuardrails `json:"guardrails,omitempty"`
 	// Model description: DEPRECATED. Use chatModel instead.
 	Model string `json:"model,omitempty"`
 	// PerUserCodeCompletionsDailyLimit description: If > 0, enables the maximum number of code completions requests allowed to be made by a single user account in a day. On instances that allow anonymous requests, the rate limit is enforced by IP.
 	PerUserCodeCompletionsDailyLimit int `json:"perUserCodeCompletionsDailyLimit,omitempty"`
 	// PerUserDailyLimit description: If > 0, enables the maximum number of completions requests allowed to be made by a single user account in a day. On instances that allow anonymous requests, the rate limit is enforced by IP.
 	PerUserDailyLimit int `json:"perUserDailyLimit,omitempty"`
+	// PerUserDailyTokenLimit description: If > 0, enables the maximum number of tokens, counting both prompts and completions, a single user account may use for completions of any kind in a day. On instances that allow anonymous requests, the limit is enforced by IP.
+	PerUserDailyTokenLimit int `json:"perUserDailyTokenLimit,omitempty"`
 	// Provider description: The external completions provider. Defaults to 'sourcegraph'.
 	Provider string `json:"provider,omitempty"`
+	// Routes description: Routes the completions requests of an intent to a dedicated model endpoint. Requests of intents without a route use the provider and models configured above.
+	Routes []*CompletionsRoute `json:"routes,omitempty"`
+}
+
+// CompletionsGuardrailPolicy description: A policy refusing the completions requests whose prompts match a pattern.
+type CompletionsGuardrailPolicy struct {
+	// Message description: The message returned to the user when the policy refuses a request.
+	Message string `json:"message,omitempty"`
+	// Name description: The name of the policy, recorded in the audit log when the policy refuses a request.
+	Name string `json:"name"`
+	// Pattern description: The regular expression matched against the prompt.
+	Pattern string `json:"pattern"`
+}
+
+// CompletionsGuardrails description: Guardrails enforced by the server on the prompts and completions of all completions requests.
+type CompletionsGuardrails struct {
+	// Policies description: Policies refusing the requests whose prompts match a pattern.
+	Policies []*CompletionsGuardrailPolicy `json:"policies,omitempty"`
+	// RedactSecrets description: Redact secrets, such as access tokens and private keys, from prompts before they are sent to the provider.
+	RedactSecrets bool `json:"redactSecrets,omitempty"`
+	// SecretPatterns description: Regular expressions matching secrets to redact in addition to the built-in ones. If a pattern has a capture group, only the text matched by the first group is redacted.
+	SecretPatterns []string `json:"secretPatterns,omitempty"`
+	// SnippetAttribution description: Search the code indexed on this instance and on Sourcegraph.com for the code snippets of completions. "audit" records completions that match existing code, "block" additionally withholds them from the user. Blocking buffers the completions of streaming requests until they are finished.
+	SnippetAttribution string `json:"snippetAttribution,omitempty"`
+	// SnippetAttributionMinLines description: The minimum number of non-blank lines of a code snippet for it to be searched for.
+	SnippetAttributionMinLines int `json:"snippetAttributionMinLines,omitempty"`
+}
+
+// CompletionsRoute description: A model endpoint serving the completions requests of an intent.
+type CompletionsRoute struct {
+	// AccessToken description: The access token used to authenticate with the provider. If using the provider 'sourcegraph', and if 'licenseKey' is set, a default access token is generated.
+	AccessToken string `json:"accessToken,omitempty"`
+	// Endpoint description: The endpoint under which to reach the provider. Defaults to the default endpoint of the provider.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Intent description: The intent of the requests served by the endpoint: "chat" for chat, "edit" for edits of code, and "autocomplete" for code completions.
+	Intent string `json:"intent"`
+	// Model description: The model serving the requests.
+	Model string `json:"model"`
+	// Provider description: The provider of the endpoint.
+	Provider string `json:"provider"`
 }
 
 // CustomGitFetchMapping description: Mapping from Git clone URl domain/path to git fetch command. The `domainPath` field contains the Git clone URL domain/path part. The `fetch` field contains the custom git fetch command.
@@ -730,6 +834,11 @@ type EncryptionKeys struct {
 	BatchChangesCredentialKey *EncryptionKey `json:"batchChangesCredentialKey,omitempty"`
 	// CacheSize description: number of values to keep in LRU cache
 	CacheSize int `json:"cacheSize,omitempty"`
+	// CodeIntelUploadKey description: Encrypts precise code intelligence uploads (LSIF and SCIP payloads) written to the upload store.
+	CodeIntelUploadKey *EncryptionKey `json:"codeIntelUploadKey,omitempty"`
+	CodyPromptLogKey   *EncryptionKey `json:"codyPromptLogKey,omitempty"`
+	// EmbeddingsIndexKey description: Encrypts embeddings indexes written to the embeddings upload store.
+	EmbeddingsIndexKey *EncryptionKey `json:"embeddingsIndexKey,omitempty"`
 	// EnableCache description: enable LRU cache for decryption APIs
 	EnableCache            bool           `json:"enableCache,omitempty"`
 	ExecutorSecretKey      *EncryptionKey `json:"executorSecretKey,omitempty"`
@@ -803,10 +912,29 @@ type ExcludedOtherRepo struct {
 	Pattern string `json:"pattern,omitempty"`
 }
 
+// ExecutorQueueQuota description: Limits of concurrently processing jobs of an executor queue. A limit of 0 means unlimited.
+type ExecutorQueueQuota struct {
+	// MaxProcessing description: The maximum number of jobs of the queue processed at the same time.
+	MaxProcessing int `json:"maxProcessing,omitempty"`
+	// MaxProcessingPerTenant description: The maximum number of jobs of a single tenant processed at the same time. Tenants are users for the batches queue and repositories for the codeintel queue.
+	MaxProcessingPerTenant int `json:"maxProcessingPerTenant,omitempty"`
+}
+
 // ExecutorsMultiqueue description: The configuration for multiqueue executors.
 type ExecutorsMultiqueue struct {
 	// DequeueCacheConfig description: The configuration for the dequeue cache of multiqueue executors. Each queue defines a limit of dequeues in the expiration window as well as a weight, indicating how frequently a queue is picked at random. For example, a weight of 4 for batches and 1 for codeintel means out of 5 dequeues, statistically batches will be picked 4 times and codeintel 1 time (unless one of those queues is at its limit).
 	DequeueCacheConfig *DequeueCacheConfig `json:"dequeueCacheConfig,omitempty"`
+	// Priorities description: The priority class of each queue. While a queue of the interactive class has jobs, multiqueue executors do not dequeue jobs from queues of the bulk class. Queues of the same class are picked by their weight. By default, batches is interactive and codeintel is bulk.
+	Priorities map[string]string `json:"priorities,omitempty"`
+	// Quotas description: Limits of concurrently processing jobs by queue name.
+	Quotas map[string]ExecutorQueueQuota `json:"quotas,omitempty"`
+}
+
+// ExecutorsSecretManagers description: External secret managers that executor secrets can reference instead of storing their value in Sourcegraph. The value of an executor secret of the form `vault://<path>#<field>`, `awssm://<secret-id>#<key>` or `gcpsm://projects/<project>/secrets/<secret>/versions/<version>` is resolved from the respective secret manager when a job starts.
+type ExecutorsSecretManagers struct {
+	AwsSecretsManager *AWSSecretsManager  `json:"awsSecretsManager,omitempty"`
+	GcpSecretManager  *GCPSecretManager   `json:"gcpSecretManager,omitempty"`
+	Vault             *VaultSecretManager `json:"vault,omitempty"`
 }
 type ExistingChangesetSpec struct {
 	// BaseRepository description: The GraphQL ID of the repository that contains the existing changeset on the code host.
@@ -843,6 +971,8 @@ type ExperimentalFeatures struct {
 	EnableStorm bool `json:"enableStorm,omitempty"`
 	// EventLogging description: Enables user event logging inside of the Sourcegraph instance. This will allow admins to have greater visibility of user activity, such as frequently viewed pages, frequent searches, and more. These event logs (and any specific user actions) are only stored locally, and never leave this Sourcegraph instance.
 	EventLogging string `json:"eventLogging,omitempty"`
+	// GitPartialClone description: Clone repositories without their file contents, which are fetched on demand when they are read. This reduces the disk usage of gitserver for huge repositories. Only applies to Git repositories cloned after it is configured.
+	GitPartialClone *GitPartialClone `json:"gitPartialClone,omitempty"`
 	// GitServerPinnedRepos description: List of repositories pinned to specific gitserver instances. The specified repositories will remain at their pinned servers on scaling the cluster. If the specified pinned server differs from the current server that stores the repository, then it must be re-cloned to the specified server.
 	GitServerPinnedRepos map[string]string `json:"gitServerPinnedRepos,omitempty"`
 	// GoPackages description: Allow adding Go package host connections
@@ -966,6 +1096,12 @@ type ExportUsageTelemetry struct {
 	BatchSize int `json:"batchSize,omitempty"`
 	// Enabled description: Toggles whether or not to export Sourcegraph telemetry. If enabled events will be scraped and sent to an analytics store. This is an opt-in setting, and only should only be enabled for customers that have agreed to event level data collection.
 	Enabled bool `json:"enabled,omitempty"`
+	// EventAllowlist description: If set, only the events with these names are exported. Events must also be in the export allowlist of the instance.
+	EventAllowlist []string `json:"eventAllowlist,omitempty"`
+	// RedactionRules description: Fields removed from the exported events before they are delivered.
+	RedactionRules []*TelemetryRedactionRule `json:"redactionRules,omitempty"`
+	// Sink description: Where batches of exported events are delivered. Defaults to the pubsub topic configured with the EXPORT_USAGE_DATA_TOPIC_NAME and EXPORT_USAGE_DATA_TOPIC_PROJECT environment variables.
+	Sink *TelemetryExportSink `json:"sink,omitempty"`
 	// TopicName description: Destination pubsub topic name to export usage data
 	TopicName string `json:"topicName,omitempty"`
 	// TopicProjectName description: GCP project name containing the usage data pubsub topic
@@ -1015,6 +1151,12 @@ type FusionClient struct {
 	Retries int `json:"retries,omitempty"`
 }
 
+// GCPSecretManager description: The Google Cloud Secret Manager executor secrets are resolved from.
+type GCPSecretManager struct {
+	// CredentialsFile description: The path to a service account key file. If not set, application default credentials are used.
+	CredentialsFile string `json:"credentialsFile,omitempty"`
+}
+
 // GerritAuthProvider description: Gerrit auth provider
 type GerritAuthProvider struct {
 	DisplayName   string  `json:"displayName,omitempty"`
@@ -1040,6 +1182,8 @@ type GerritConnection struct {
 	Password string `json:"password"`
 	// Projects description: An array of project strings specifying which Gerrit projects to mirror on Sourcegraph. If empty, all projects will be mirrored.
 	Projects []string `json:"projects,omitempty"`
+	// SyncOpenChanges description: If true, the open changes of each project are synced, and the current patch set of each is indexed for search as a virtual branch named after its ref, such as refs/changes/34/1234/2.
+	SyncOpenChanges bool `json:"syncOpenChanges,omitempty"`
 	// Url description: URL of a Gerrit instance, such as https://gerrit.example.com.
 	Url string `json:"url"`
 	// Username description: A username for authentication withe the Gerrit code host.
@@ -1204,6 +1348,14 @@ type GitHubWebhook struct {
 	Secret string `json:"secret"`
 }
 
+// GitLFS description: Configuration for files stored in Git Large File Storage (LFS). Repositories are cloned without LFS content, so by default only the pointers to LFS files are served.
+type GitLFS struct {
+	// FetchContent description: Fetch the content of LFS files from the LFS server of the code host when they are requested through the raw file API, instead of serving their pointers.
+	FetchContent bool `json:"fetchContent,omitempty"`
+	// MaxContentSize description: The maximum size in bytes of LFS files whose content is fetched. Pointers are served for larger files.
+	MaxContentSize *int `json:"maxContentSize,omitempty"`
+}
+
 // GitLabAuthProvider description: Configures the GitLab OAuth authentication provider for SSO. In addition to specifying this configuration object, you must also create a OAuth App on your GitLab instance: https://docs.gitlab.com/ee/integration/oauth_provider.html. The application should have `api` and `read_user` scopes and the callback URL set to the concatenation of your Sourcegraph instance URL and "/.auth/gitlab/callback".
 type GitLabAuthProvider struct {
 	// AllowGroups description: Restricts new logins and signups (if allowSignup is true) to members of these GitLab groups. Existing sessions won't be invalidated. Make sure to inform the full path for groups or subgroups instead of their names. Leave empty or unset for no group restrictions.
@@ -1307,6 +1459,18 @@ type GitLabWebhook struct {
 	Secret string `json:"secret"`
 }
 
+// GitPartialClone description: Clone repositories without their file contents, which are fetched on demand when they are read. This reduces the disk usage of gitserver for huge repositories. Only applies to Git repositories cloned after it is configured.
+type GitPartialClone struct {
+	// All description: Partially clone all Git repositories.
+	All bool `json:"all,omitempty"`
+	// Filter description: The filter with which repositories are partially cloned. `blob:none` omits file contents, `tree:0` also omits directory listings.
+	Filter string `json:"filter,omitempty"`
+	// PromotionThreshold description: The number of commands that read file contents of a partially cloned repository after which it is promoted to a full clone, as fetching file contents on demand costs more than keeping them on disk. 0 disables promotion.
+	PromotionThreshold *int `json:"promotionThreshold,omitempty"`
+	// Repos description: Git clone URL domain/path of the repositories to partially clone, in addition to all repositories if `all` is enabled. Patterns can contain `*` wildcards.
+	Repos []string `json:"repos,omitempty"`
+}
+
 // GitRecorder description: Record git operations that are executed on configured repositories. The following commands are not recorded: show, log, rev-parse and diff.
 type GitRecorder struct {
 	// Repos description: List of repositories whose git operations should be recorded.
@@ -1361,6 +1525,47 @@ type GoRateLimit struct {
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
 
+type GraphQLClientComplexityBudget struct {
+	// Ip description: The IP address of the unauthenticated client the budget applies to. This is the address the connection is received from, not the X-Forwarded-For header, which clients can spoof.
+	Ip string `json:"ip,omitempty"`
+	// MaxDepth description: The maximum nesting depth of a query. 0 means unlimited.
+	MaxDepth int `json:"maxDepth,omitempty"`
+	// MaxFieldCount description: The maximum estimated number of fields returned by a query. 0 means unlimited.
+	MaxFieldCount int `json:"maxFieldCount,omitempty"`
+	// UserID description: The database ID of the user the budget applies to.
+	UserID int `json:"userID,omitempty"`
+}
+type GraphQLComplexityBudget struct {
+	// MaxDepth description: The maximum nesting depth of a query. 0 means unlimited.
+	MaxDepth int `json:"maxDepth,omitempty"`
+	// MaxFieldCount description: The maximum estimated number of fields returned by a query. 0 means unlimited.
+	MaxFieldCount int `json:"maxFieldCount,omitempty"`
+}
+type GraphQLPersistedQuery struct {
+	// Id description: A unique name for the query, used in metrics and logs.
+	Id string `json:"id"`
+	// Query description: The GraphQL query document.
+	Query string `json:"query"`
+}
+
+// GraphqlComplexityBudgets description: Limits on the complexity of GraphQL queries, which reject expensive queries before they are executed. The complexity of a query is measured by its estimated number of fields, taking pagination arguments into account, and its maximum nesting depth. Persisted queries are not subject to these limits.
+type GraphqlComplexityBudgets struct {
+	// Anonymous description: The budget for unauthenticated clients. If unset, the default budget applies.
+	Anonymous *GraphQLComplexityBudget `json:"anonymous,omitempty"`
+	// Clients description: Budgets for specific clients, which take precedence over the default and anonymous budgets.
+	Clients []*GraphQLClientComplexityBudget `json:"clients,omitempty"`
+	// Default description: The budget for authenticated clients.
+	Default *GraphQLComplexityBudget `json:"default,omitempty"`
+}
+
+// GraphqlPersistedQueries description: Persisted GraphQL queries, which clients can execute by sending the SHA-256 hash of the query instead of the query itself. Persisted queries are not subject to the GraphQL complexity budgets.
+type GraphqlPersistedQueries struct {
+	// AnonymousAllowlistOnly description: If true, unauthenticated requests to the GraphQL API may only execute persisted queries. Note that this also prevents anonymous visitors from using the web application, so it is only suitable for instances whose unauthenticated API clients use persisted queries.
+	AnonymousAllowlistOnly bool `json:"anonymousAllowlistOnly,omitempty"`
+	// Queries description: The persisted queries. A query is identified by the hex-encoded SHA-256 hash of its text.
+	Queries []*GraphQLPersistedQuery `json:"queries,omitempty"`
+}
+
 // HTTPHeaderAuthProvider description: Configures the HTTP header authentication provider (which authenticates users by consulting an HTTP request header set by an authentication proxy such as https://github.com/bitly/oauth2_proxy).
 type HTTPHeaderAuthProvider struct {
 	// EmailHeader description: The name (case-insensitive) of an HTTP header whose value is taken to be the email of the client requesting the page. Set this value when using an HTTP proxy that authenticates requests, and you don't want the extra configurability of the other authentication methods.
@@ -1493,6 +1698,16 @@ type Notice struct {
 	// Message description: The message to display. Markdown formatting is supported.
 	Message string `json:"message"`
 }
+
+// NotificationPreference description: The channels a kind of notification is delivered to.
+type NotificationPreference struct {
+	// Email description: Whether to send the notifications by email to your primary email address, either immediately or as a daily digest.
+	Email string `json:"email,omitempty"`
+	// InApp description: Whether to show the notifications in the notifications inbox.
+	InApp *bool `json:"inApp,omitempty"`
+	// Slack description: Whether to post the notifications to the Slack webhook of notifications.slackWebhookURL.
+	Slack bool `json:"slack,omitempty"`
+}
 type Notifications struct {
 	// Key description: e.g. '2023-03-10-my-key'; MUST START WITH YYYY-MM-DD; a globally unique key used to track whether the message has been dismissed.
 	Key string `json:"key"`
@@ -1641,10 +1856,14 @@ type ObservabilityClient struct {
 
 // ObservabilityTracing description: Configures distributed tracing within Sourcegraph. To learn more, refer to https://docs.sourcegraph.com/admin/observability/tracing
 type ObservabilityTracing struct {
+	// DatabaseQueries description: Creates a span for every query issued through a database store within an existing trace, annotated with the sanitized SQL statement, the number of query arguments, and the number of affected rows. Queries never start traces of their own. May have performance implications in production.
+	DatabaseQueries bool `json:"databaseQueries,omitempty"`
 	// Debug description: Turns on debug logging of tracing client requests. This can be useful for debugging connectivity issues between the tracing client and tracing backend, the performance overhead of tracing, and other issues related to the use of distributed tracing. May have performance implications in production.
 	Debug bool `json:"debug,omitempty"`
 	// Sampling description: Determines the conditions under which distributed traces are recorded. "none" turns off tracing entirely. "selective" (default) sends traces whenever `?trace=1` is present in the URL (though background jobs may still emit traces). "all" sends traces on every request. Note that this only affects the behavior of the distributed tracing client. To learn more about additional sampling and traace export configuration with the default tracing type "opentelemetry", refer to https://docs.sourcegraph.com/admin/observability/opentelemetry#tracing
 	Sampling string `json:"sampling,omitempty"`
+	// TailSampling description: Decides whether to export each trace once its root span within a service has ended, instead of when the trace starts. Traces containing an error and traces whose duration exceeds the latency threshold are always exported, and a fraction of the remaining traces is exported at random. Only applies when "sampling" is "all"; traces requested selectively are always exported.
+	TailSampling *TailSampling `json:"tailSampling,omitempty"`
 	// Type description: Determines what tracing provider to enable. For "opentelemetry", the required backend is an OpenTelemetry collector instance (deployed by default with Sourcegraph). For "jaeger", a Jaeger instance is required to be configured via Jaeger client environment variables: https://github.com/jaegertracing/jaeger-client-go#environment-variables
 	Type string `json:"type,omitempty"`
 	// UrlTemplate description: Template for linking to trace URLs - '{{ .TraceID }}' is replaced with the trace ID, and {{ .ExternalURL }} is replaced with the value of 'externalURL'. If none is set, no links are generated.
@@ -1796,6 +2015,8 @@ type PerforceConnection struct {
 	Depots []string `json:"depots,omitempty"`
 	// FusionClient description: Configuration for the experimental p4-fusion client
 	FusionClient *FusionClient `json:"fusionClient,omitempty"`
+	// ImportShelvedChangelists description: Import the shelved changelists of each depot as commits on top of the imported changes, under hidden refs of the form `refs/shelved/<changelist>`, so that they can be used by review tooling. Shelved changelists are imported when the depot is fetched, and their refs are removed once they are no longer shelved.
+	ImportShelvedChangelists bool `json:"importShelvedChangelists,omitempty"`
 	// MaxChanges description: Only import at most n changes when possible (git p4 clone --max-changes).
 	MaxChanges float64 `json:"maxChanges,omitempty"`
 	// P4Client description: Client specified as an option for p4 CLI (P4CLIENT, also enables '--use-client-spec')
@@ -1844,6 +2065,8 @@ type Phabricator struct {
 type PhabricatorConnection struct {
 	// Repos description: The list of repositories available on Phabricator.
 	Repos []*Repos `json:"repos,omitempty"`
+	// SyncRepositories description: If true, the Git repositories of the Diffusion application are cloned and kept up to date like the repositories of other code hosts, instead of only being linked to. Requires `token`. This also supports Phorge, the community fork of Phabricator.
+	SyncRepositories bool `json:"syncRepositories,omitempty"`
 	// Token description: API token for the Phabricator instance.
 	Token string `json:"token,omitempty"`
 	// Url description: URL of a Phabricator instance, such as https://phabricator.example.com
@@ -1901,6 +2124,32 @@ type RepoPurgeWorker struct {
 	// IntervalMinutes description: Interval in minutes at which to run purge jobs. Set to 0 to disable.
 	IntervalMinutes int `json:"intervalMinutes,omitempty"`
 }
+type RepoSyncCodeHost struct {
+	// Kind description: The kind of the external services the rule applies to.
+	Kind string `json:"kind"`
+	// MaxConcurrentSyncs description: The maximum number of external services matching the rule that are synced concurrently. It is in addition to repoConcurrentExternalServiceSyncers. When unset, only repoConcurrentExternalServiceSyncers applies.
+	MaxConcurrentSyncs int `json:"maxConcurrentSyncs,omitempty"`
+	// Url description: The URL of the code host the rule applies to. When unset, the rule applies to all external services of the kind.
+	Url string `json:"url,omitempty"`
+	// Windows description: The time windows in which periodic syncs of the matching external services may start. When unset, they may start at any time. Syncs triggered by site admins are not restricted.
+	Windows []*RepoSyncWindow `json:"windows,omitempty"`
+}
+
+// RepoSyncScheduling description: Per-code-host scheduling of external service syncs, which list the repositories of code hosts, and of the git updates of repositories.
+type RepoSyncScheduling struct {
+	// ArchivedIntervalHours description: The number of hours between the scheduled git updates of the repositories archived by the repository archival policy, if the policy reduces their sync frequency.
+	ArchivedIntervalHours *int `json:"archivedIntervalHours,omitempty"`
+	// CodeHosts description: Scheduling rules for the external services of code hosts. An external service is scheduled according to the first rule that matches it.
+	CodeHosts []*RepoSyncCodeHost `json:"codeHosts,omitempty"`
+	// RecentlyActiveMinutes description: Repositories that users accessed within this many minutes have their scheduled git updates queued ahead of the updates of other repositories. Set to 0 to disable.
+	RecentlyActiveMinutes *int `json:"recentlyActiveMinutes,omitempty"`
+}
+type RepoSyncWindow struct {
+	// End description: The end of the window, in the format HH:MM (UTC). If it is before the start, the window spans midnight.
+	End string `json:"end"`
+	// Start description: The start of the window, in the format HH:MM (UTC).
+	Start string `json:"start"`
+}
 type Repos struct {
 	// Callsign description: The unique Phabricator identifier for the repository, like 'MUX'.
 	Callsign string `json:"callsign"`
@@ -2015,6 +2264,21 @@ type SMTPServerConfig struct {
 	// Username description: The username to use when communicating with the SMTP server.
 	Username string `json:"username,omitempty"`
 }
+
+// SearchIndexAdmissionControl description: Admission control for search indexing. zoekt-sourcegraph-indexserver reports its memory and disk pressure, and indexing of large repositories is postponed while the pressure on the indexserver is high. Small repositories are always indexed, and large repositories are indexed anyway once they have been postponed for too long.
+type SearchIndexAdmissionControl struct {
+	// Enabled description: Whether admission control is enabled.
+	Enabled bool `json:"enabled,omitempty"`
+	// MaxPostponementHours description: The number of hours after which a repository whose indexing has been postponed since is indexed regardless of the pressure on the indexserver.
+	MaxPostponementHours int `json:"maxPostponementHours,omitempty"`
+	// PostponePercent description: The memory or disk usage of an indexserver, in percent, from which indexing of all large repositories is postponed.
+	PostponePercent int `json:"postponePercent,omitempty"`
+	// SmallRepoSizeMB description: Repositories whose estimated indexing cost is at most this many megabytes are always indexed.
+	SmallRepoSizeMB *int `json:"smallRepoSizeMB,omitempty"`
+	// ThrottlePercent description: The memory or disk usage of an indexserver, in percent, from which large repositories are only indexed if their estimated indexing cost fits into the free memory and disk of the indexserver.
+	ThrottlePercent int `json:"throttlePercent,omitempty"`
+}
+
 type SearchIndexRevisionsRule struct {
 	// Name description: Regular expression which matches against the name of a repository (e.g. "^github\.com/owner/name$").
 	Name string `json:"name,omitempty"`
@@ -2022,16 +2286,26 @@ type SearchIndexRevisionsRule struct {
 	Revisions []string `json:"revisions"`
 }
 
-// SearchLimits description: Limits that search applies for number of repositories searched and timeouts.
+// SearchLimits description: Limits that search applies for number of repositories searched, timeouts and the resources a single query or user may use.
 type SearchLimits struct {
 	// CommitDiffMaxRepos description: The maximum number of repositories to search across when doing a "type:diff" or "type:commit". The user is prompted to narrow their query if the limit is exceeded. There is a separate limit (commitDiffWithTimeFilterMaxRepos) when "after:" or "before:" is specified because those queries are faster. Defaults to 50.
 	CommitDiffMaxRepos int `json:"commitDiffMaxRepos,omitempty"`
 	// CommitDiffWithTimeFilterMaxRepos description: The maximum number of repositories to search across when doing a "type:diff" or "type:commit" with a "after:" or "before:" filter. The user is prompted to narrow their query if the limit is exceeded. There is a separate limit (commitDiffMaxRepos) when "after:" or "before:" is not specified because those queries are slower. Defaults to 10000.
 	CommitDiffWithTimeFilterMaxRepos int `json:"commitDiffWithTimeFilterMaxRepos,omitempty"`
+	// MaxQueryDurationSeconds description: The maximum wall time of a single query, including all of its sub-queries (e.g. "or" expressions and Smart Search). Unlike maxTimeoutSeconds, this can't be raised with "timeout:". When it is hit, the results found so far are returned and the search progress reports that the limit was hit. Any value less than or equal to zero means unlimited.
+	MaxQueryDurationSeconds int `json:"maxQueryDurationSeconds,omitempty"`
 	// MaxRepos description: The maximum number of repositories to search across. The user is prompted to narrow their query if exceeded. Any value less than or equal to zero means unlimited.
 	MaxRepos int `json:"maxRepos,omitempty"`
+	// MaxReposScanned description: The maximum number of repositories a single query searches. Repositories beyond the limit are not searched, and the search progress reports that the limit was hit. Any value less than or equal to zero means unlimited.
+	MaxReposScanned int `json:"maxReposScanned,omitempty"`
+	// MaxResultBytes description: The maximum size in bytes of the matched content (file paths, matched chunks, symbols and commit messages) a single query returns. When it is hit, the search is stopped and the search progress reports that the limit was hit. Any value less than or equal to zero means unlimited.
+	MaxResultBytes int `json:"maxResultBytes,omitempty"`
 	// MaxTimeoutSeconds description: The maximum value for "timeout:" that search will respect. "timeout:" values larger than maxTimeoutSeconds are capped at maxTimeoutSeconds. Note: You need to ensure your load balancer / reverse proxy in front of Sourcegraph won't timeout the request for larger values. Note: Too many large rearch requests may harm Soucregraph for other users. Defaults to 1 minute.
 	MaxTimeoutSeconds int `json:"maxTimeoutSeconds,omitempty"`
+	// UserQueriesPerMinute description: The number of search queries a single user may run per minute. Structural search queries count as 5 queries. Queries beyond the limit are rejected with an alert. Queries run by Sourcegraph itself (e.g. code monitors and code insights) are not limited. Any value less than or equal to zero means unlimited.
+	UserQueriesPerMinute int `json:"userQueriesPerMinute,omitempty"`
+	// UserQueryBurst description: The number of search queries a single user may run at once before userQueriesPerMinute applies. Defaults to userQueriesPerMinute.
+	UserQueryBurst int `json:"userQueryBurst,omitempty"`
 }
 
 // SearchSanitization description: Allows site admins to specify a list of regular expressions representing matched content that should be omitted from search results. Also allows admins to specify the name of an organization within their Sourcegraph instance whose members are trusted and will not have their search results sanitized. Enable this feature by adding at least one valid regular expression to the value of the `sanitizePatterns` field on this object. Site admins will not have their searches sanitized.
@@ -2104,6 +2378,8 @@ type Settings struct {
 	CodeIntelTraceExtension bool `json:"codeIntel.traceExtension,omitempty"`
 	// CodeIntelligenceAutoIndexPopularRepoLimit description: Up to this number of repos are auto-indexed automatically. Ordered by star count.
 	CodeIntelligenceAutoIndexPopularRepoLimit int `json:"codeIntelligence.autoIndexPopularRepoLimit,omitempty"`
+	// CodyContextFilters description: Restricts the repositories whose content may be sent to the LLM as context for Cody, in addition to the cody.contextFilters site configuration. This setting is only honored in organization settings, and applies to all members of the organization.
+	CodyContextFilters *SettingsCodyContextFilters `json:"cody.contextFilters,omitempty"`
 	// ExperimentalFeatures description: Experimental features and settings.
 	ExperimentalFeatures *SettingsExperimentalFeatures `json:"experimentalFeatures,omitempty"`
 	// FileSidebarVisibleByDefault description: Whether the sidebar on the repo view should be open by default.
@@ -2128,6 +2404,8 @@ type Settings struct {
 	//
 	// Usually this setting is used in global and organization settings. If set in user settings, the message will only be displayed to that single user.
 	Notices []*Notice `json:"notices,omitempty"`
+	// Notifications description: Where to deliver the notifications of Sourcegraph features, such as code monitors, batch changes, permission syncs and embedding jobs. This setting is only honored in user settings.
+	Notifications *SettingsNotifications `json:"notifications,omitempty"`
 	// OpenInEditor description: Group of settings related to opening files in an editor.
 	OpenInEditor *SettingsOpenInEditor `json:"openInEditor,omitempty"`
 	// OrgsAllMembersBatchChangesAdmin description: If enabled, all members of the org will be treated as admins (e.g. can edit, apply, delete) for all batch changes created in that org.
@@ -2200,6 +2478,7 @@ func (v *Settings) UnmarshalJSON(data []byte) error {
 	delete(m, "codeIntel.mixPreciseAndSearchBasedReferences")
 	delete(m, "codeIntel.traceExtension")
 	delete(m, "codeIntelligence.autoIndexPopularRepoLimit")
+	delete(m, "cody.contextFilters")
 	delete(m, "experimentalFeatures")
 	delete(m, "fileSidebarVisibleByDefault")
 	delete(m, "history.defaultPageSize")
@@ -2207,6 +2486,7 @@ func (v *Settings) UnmarshalJSON(data []byte) error {
 	delete(m, "insights.aggregations.extendedTimeout")
 	delete(m, "motd")
 	delete(m, "notices")
+	delete(m, "notifications")
 	delete(m, "openInEditor")
 	delete(m, "orgs.allMembersBatchChangesAdmin")
 	delete(m, "perforce.codeHostToSwarmMap")
@@ -2229,6 +2509,14 @@ func (v *Settings) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// SettingsCodyContextFilters description: Restricts the repositories whose content may be sent to the LLM as context for Cody, in addition to the cody.contextFilters site configuration. This setting is only honored in organization settings, and applies to all members of the organization.
+type SettingsCodyContextFilters struct {
+	// Exclude description: Regular expressions matching the names of repositories whose content must never be sent, even if they are included.
+	Exclude []string `json:"exclude,omitempty"`
+	// Include description: Regular expressions matching the names of the only repositories whose content may be sent. If empty, all repositories are included.
+	Include []string `json:"include,omitempty"`
+}
+
 // SettingsExperimentalFeatures description: Experimental features and settings.
 type SettingsExperimentalFeatures struct {
 	// ApplySearchQuerySuggestionOnEnter description: This changes the behavior of the autocompletion feature in the search query input. If set the first suggestion won't be selected by default and a selected suggestion can be selected by pressing Enter (application by pressing Tab continues to work)
@@ -2348,6 +2636,20 @@ func (v *SettingsExperimentalFeatures) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// SettingsNotifications description: Where to deliver the notifications of Sourcegraph features, such as code monitors, batch changes, permission syncs and embedding jobs. This setting is only honored in user settings.
+type SettingsNotifications struct {
+	// BatchChanges description: Notifications of changesets of your batch changes that failed to publish or update.
+	BatchChanges *NotificationPreference `json:"batchChanges,omitempty"`
+	// CodeMonitors description: Notifications of code monitors that found new results.
+	CodeMonitors *NotificationPreference `json:"codeMonitors,omitempty"`
+	// EmbeddingJobs description: Notifications of embedding jobs that failed. Only sent to site admins.
+	EmbeddingJobs *NotificationPreference `json:"embeddingJobs,omitempty"`
+	// PermissionSync description: Notifications of permission syncs you scheduled that failed.
+	PermissionSync *NotificationPreference `json:"permissionSync,omitempty"`
+	// SlackWebhookURL description: The Slack incoming webhook URL that notifications are posted to, for the kinds of notifications with Slack delivery enabled.
+	SlackWebhookURL string `json:"slackWebhookURL,omitempty"`
+}
+
 // SettingsOpenInEditor description: Group of settings related to opening files in an editor.
 type SettingsOpenInEditor struct {
 	// CustomUrlPattern description: If you add "custom" to openineditor.editorIds, this must be set. Use the placeholders "%file", "%line", and "%col" to mark where the file path, line number, and column number must be insterted. Example URL for IntelliJ IDEA: "idea://open?file=%file&line=%line&column=%col"
@@ -2386,6 +2688,8 @@ type SiteConfiguration struct {
 	AuthAccessRequest *AuthAccessRequest `json:"auth.accessRequest,omitempty"`
 	// AuthAccessTokens description: Settings for access tokens, which enable external tools to access the Sourcegraph API with the privileges of the user.
 	AuthAccessTokens *AuthAccessTokens `json:"auth.accessTokens,omitempty"`
+	// AuthDeletedUsersRetentionDays description: The number of days that deleted users are retained and can be restored by site admins. Deleted users are permanently deleted once this period has passed. If 0 or unset, deleted users are retained indefinitely.
+	AuthDeletedUsersRetentionDays int `json:"auth.deletedUsersRetentionDays,omitempty"`
 	// AuthEnableUsernameChanges description: Enables users to change their username after account creation. Warning: setting this to be true has security implications if you have enabled (or will at any point in the future enable) repository permissions with an option that relies on username equivalency between Sourcegraph and an external service or authentication provider. Do NOT set this to true if you are using non-built-in authentication OR rely on username equivalency for repository permissions.
 	AuthEnableUsernameChanges bool `json:"auth.enableUsernameChanges,omitempty"`
 	// AuthLockout description: The config options for account lockout
@@ -2448,6 +2752,10 @@ type SiteConfiguration struct {
 	CloneProgressLog bool `json:"cloneProgress.log,omitempty"`
 	// CodeIntelAutoIndexingAllowGlobalPolicies description: Whether auto-indexing policies may apply to all repositories on the Sourcegraph instance. Default is false. The policyRepositoryMatchLimit setting still applies to such auto-indexing policies.
 	CodeIntelAutoIndexingAllowGlobalPolicies *bool `json:"codeIntelAutoIndexing.allowGlobalPolicies,omitempty"`
+	// CodeIntelAutoIndexingDependencyIndexingMaxDepth description: The maximum number of transitive dependency hops from a non-package repository for which dependency packages will be auto-indexed. A value of 1 indexes only direct dependencies. Default is -1, which is unlimited.
+	CodeIntelAutoIndexingDependencyIndexingMaxDepth *int `json:"codeIntelAutoIndexing.dependencyIndexingMaxDepth,omitempty"`
+	// CodeIntelAutoIndexingDependencyVersionPins description: Pins the maximum version of a dependency package that will be auto-indexed. Keys have the form `<scheme>:<package name>` and values are semantic versions. Versions above the pin, and versions that are not valid semantic versions, are not auto-indexed.
+	CodeIntelAutoIndexingDependencyVersionPins map[string]string `json:"codeIntelAutoIndexing.dependencyVersionPins,omitempty"`
 	// CodeIntelAutoIndexingEnabled description: Enables/disables the code intel auto-indexing feature. Currently experimental.
 	CodeIntelAutoIndexingEnabled *bool `json:"codeIntelAutoIndexing.enabled,omitempty"`
 	// CodeIntelAutoIndexingIndexerMap description: Overrides the default Docker images used by auto-indexing.
@@ -2464,8 +2772,14 @@ type SiteConfiguration struct {
 	CodeIntelRankingDocumentReferenceCountsGraphKey string `json:"codeIntelRanking.documentReferenceCountsGraphKey,omitempty"`
 	// CodeIntelRankingStaleResultsAge description: The interval at which to run the reduce job that computes document reference counts. Default is 24hrs.
 	CodeIntelRankingStaleResultsAge int `json:"codeIntelRanking.staleResultsAge,omitempty"`
+	// CodyContextFilters description: Restricts the repositories whose content may be sent to the LLM as context for Cody. Filters are enforced on the server when context is retrieved and when completions are requested. Organizations can restrict their members further with the cody.contextFilters setting of the organization.
+	CodyContextFilters *CodyContextFilters `json:"cody.contextFilters,omitempty"`
+	// CodyContextRetrieval description: Configures how context for Cody is retrieved. Results from embeddings similarity search, keyword search, and the precise code graph are merged according to the weight of each source. A source with a weight of 0 is disabled.
+	CodyContextRetrieval *CodyContextRetrieval `json:"cody.contextRetrieval,omitempty"`
 	// CodyEnabled description: Enable or disable Cody instance-wide. When Cody is disabled, all Cody endpoints and GraphQL queries will return errors, Cody will not show up in the site-admin sidebar, and Cody in the global navbar will only show a call-to-action for site-admins to enable Cody.
 	CodyEnabled *bool `json:"cody.enabled,omitempty"`
+	// CodyPromptLogging description: Records the prompts and responses of Cody completions requests for audit purposes. Records are encrypted with encryption.keys.codyPromptLogKey if configured, and can be exported by site admins with the codyPromptLogs GraphQL query.
+	CodyPromptLogging *CodyPromptLogging `json:"cody.promptLogging,omitempty"`
 	// CodyRestrictUsersFeatureFlag description: Restrict Cody to only be enabled for users that have a feature flag labeled "cody" set to true. You must create a feature flag with this ID after enabling this setting: https://docs.sourcegraph.com/dev/how-to/use_feature_flags#create-a-feature-flag. This setting only has an effect if cody.enabled is true.
 	CodyRestrictUsersFeatureFlag *bool `json:"cody.restrictUsersFeatureFlag,omitempty"`
 	// Completions description: Configuration for the completions service.
@@ -2514,6 +2828,8 @@ type SiteConfiguration struct {
 	ExecutorsLsifGoImage string `json:"executors.lsifGoImage,omitempty"`
 	// ExecutorsMultiqueue description: The configuration for multiqueue executors.
 	ExecutorsMultiqueue *ExecutorsMultiqueue `json:"executors.multiqueue,omitempty"`
+	// ExecutorsSecretManagers description: External secret managers that executor secrets can reference instead of storing their value in Sourcegraph. The value of an executor secret of the form `vault://<path>#<field>`, `awssm://<secret-id>#<key>` or `gcpsm://projects/<project>/secrets/<secret>/versions/<version>` is resolved from the respective secret manager when a job starts.
+	ExecutorsSecretManagers *ExecutorsSecretManagers `json:"executors.secretManagers,omitempty"`
 	// ExecutorsSrcCLIImage description: The image to use for src-cli in executors. Use this value to pull from a custom image registry.
 	ExecutorsSrcCLIImage string `json:"executors.srcCLIImage,omitempty"`
 	// ExecutorsSrcCLIImageTag description: The tag to use for the src-cli image in executors. Use this value to use a custom tag. Sourcegraph by default uses the best match, so use this setting only if you really need to overwrite it and make sure to keep it updated.
@@ -2527,8 +2843,12 @@ type SiteConfiguration struct {
 	ExternalURL string `json:"externalURL,omitempty"`
 	// GitCloneURLToRepositoryName description: JSON array of configuration that maps from Git clone URL to repository name. Sourcegraph automatically resolves remote clone URLs to their proper code host. However, there may be non-remote clone URLs (e.g., in submodule declarations) that Sourcegraph cannot automatically map to a code host. In this case, use this field to specify the mapping. The mappings are tried in the order they are specified and take precedence over automatic mappings.
 	GitCloneURLToRepositoryName []*CloneURLToRepositoryName `json:"git.cloneURLToRepositoryName,omitempty"`
+	// GitCommitSignatures description: Configuration for verifying the signatures of commits and tags. Signatures made with a trusted key are reported as verified.
+	GitCommitSignatures *CommitSignatures `json:"git.commitSignatures,omitempty"`
 	// GitHubApp description: DEPRECATED: The config options for Sourcegraph GitHub App.
 	GitHubApp *GitHubApp `json:"gitHubApp,omitempty"`
+	// GitLFS description: Configuration for files stored in Git Large File Storage (LFS). Repositories are cloned without LFS content, so by default only the pointers to LFS files are served.
+	GitLFS *GitLFS `json:"git.lfs,omitempty"`
 	// GitLongCommandTimeout description: Maximum number of seconds that a long Git command (e.g. clone or remote update) is allowed to execute. The default is 3600 seconds, or 1 hour.
 	GitLongCommandTimeout int `json:"gitLongCommandTimeout,omitempty"`
 	// GitMaxCodehostRequestsPerSecond description: Maximum number of remote code host git operations (e.g. clone or ls-remote) to be run per second per gitserver. Default is -1, which is unlimited.
@@ -2539,6 +2859,10 @@ type SiteConfiguration struct {
 	GitRecorder *GitRecorder `json:"gitRecorder,omitempty"`
 	// GitUpdateInterval description: JSON array of repo name patterns and update intervals. If a repo matches a pattern, the associated interval will be used. If it matches no patterns a default backoff heuristic will be used. Pattern matches are attempted in the order they are provided.
 	GitUpdateInterval []*UpdateIntervalRule `json:"gitUpdateInterval,omitempty"`
+	// GraphqlComplexityBudgets description: Limits on the complexity of GraphQL queries, which reject expensive queries before they are executed. The complexity of a query is measured by its estimated number of fields, taking pagination arguments into account, and its maximum nesting depth. Persisted queries are not subject to these limits.
+	GraphqlComplexityBudgets *GraphqlComplexityBudgets `json:"graphql.complexityBudgets,omitempty"`
+	// GraphqlPersistedQueries description: Persisted GraphQL queries, which clients can execute by sending the SHA-256 hash of the query instead of the query itself. Persisted queries are not subject to the GraphQL complexity budgets.
+	GraphqlPersistedQueries *GraphqlPersistedQueries `json:"graphql.persistedQueries,omitempty"`
 	// HtmlBodyBottom description: HTML to inject at the bottom of the `<body>` element on each page, for analytics scripts. Requires env var ENABLE_INJECT_HTML=true.
 	HtmlBodyBottom string `json:"htmlBodyBottom,omitempty"`
 	// HtmlBodyTop description: HTML to inject at the top of the `<body>` element on each page, for analytics scripts. Requires env var ENABLE_INJECT_HTML=true.
@@ -2635,16 +2959,22 @@ type SiteConfiguration struct {
 	RepoListUpdateInterval int `json:"repoListUpdateInterval,omitempty"`
 	// RepoPurgeWorker description: Configuration for repository purge worker.
 	RepoPurgeWorker *RepoPurgeWorker `json:"repoPurgeWorker,omitempty"`
+	// RepoSyncScheduling description: Per-code-host scheduling of external service syncs, which list the repositories of code hosts, and of the git updates of repositories.
+	RepoSyncScheduling *RepoSyncScheduling `json:"repoSyncScheduling,omitempty"`
 	// ScimAuthToken description: DISCLAIMER: UNDER DEVELOPMENT. THE ENDPOINT DOES NOT COMPLY WITH THE SCIM STANDARD YET. The SCIM auth token is used to authenticate SCIM requests. If not set, SCIM is disabled.
 	ScimAuthToken string `json:"scim.authToken,omitempty"`
 	// ScimIdentityProvider description: Identity provider used for SCIM support.  "STANDARD" should be used unless a more specific value is available
 	ScimIdentityProvider string `json:"scim.identityProvider,omitempty"`
+	// SearchIndexAdmissionControl description: Admission control for search indexing. zoekt-sourcegraph-indexserver reports its memory and disk pressure, and indexing of large repositories is postponed while the pressure on the indexserver is high. Small repositories are always indexed, and large repositories are indexed anyway once they have been postponed for too long.
+	SearchIndexAdmissionControl *SearchIndexAdmissionControl `json:"search.index.admissionControl,omitempty"`
 	// SearchIndexSymbolsEnabled description: Whether indexed symbol search is enabled. This is contingent on the indexed search configuration, and is true by default for instances with indexed search enabled. Enabling this will cause every repository to re-index, which is a time consuming (several hours) operation. Additionally, it requires more storage and ram to accommodate the added symbols information in the search index.
 	SearchIndexSymbolsEnabled *bool `json:"search.index.symbols.enabled,omitempty"`
 	// SearchLargeFiles description: A list of file glob patterns where matching files will be indexed and searched regardless of their size. Files still need to be valid utf-8 to be indexed. The glob pattern syntax can be found here: https://github.com/bmatcuk/doublestar#patterns.
 	SearchLargeFiles []string `json:"search.largeFiles,omitempty"`
-	// SearchLimits description: Limits that search applies for number of repositories searched and timeouts.
+	// SearchLimits description: Limits that search applies for number of repositories searched, timeouts and the resources a single query or user may use.
 	SearchLimits *SearchLimits `json:"search.limits,omitempty"`
+	// SearchResultCacheTTLSeconds description: How long, in seconds, the results of searches scoped to a single repository at a commit ID (e.g. the searches of search-based code navigation for definitions and references) are cached. Cached results are invalidated when the repository is updated. Set to 0 to disable the cache.
+	SearchResultCacheTTLSeconds *int `json:"search.resultCacheTTLSeconds,omitempty"`
 	// SyntaxHighlighting description: Syntax highlighting configuration
 	SyntaxHighlighting *SyntaxHighlighting `json:"syntaxHighlighting,omitempty"`
 	// UpdateChannel description: The channel on which to automatically check for Sourcegraph updates.
@@ -2688,6 +3018,7 @@ func (v *SiteConfiguration) UnmarshalJSON(data []byte) error {
 	delete(m, "app")
 	delete(m, "auth.accessRequest")
 	delete(m, "auth.accessTokens")
+	delete(m, "auth.deletedUsersRetentionDays")
 	delete(m, "auth.enableUsernameChanges")
 	delete(m, "auth.lockout")
 	delete(m, "auth.minPasswordLength")
@@ -2720,7 +3051,10 @@ func (v *SiteConfiguration) UnmarshalJSON(data []byte) error {
 	delete(m, "codeIntelRanking.documentReferenceCountsEnabled")
 	delete(m, "codeIntelRanking.documentReferenceCountsGraphKey")
 	delete(m, "codeIntelRanking.staleResultsAge")
+	delete(m, "cody.contextFilters")
+	delete(m, "cody.contextRetrieval")
 	delete(m, "cody.enabled")
+	delete(m, "cody.promptLogging")
 	delete(m, "cody.restrictUsersFeatureFlag")
 	delete(m, "completions")
 	delete(m, "corsOrigin")
@@ -2744,6 +3078,7 @@ func (v *SiteConfiguration) UnmarshalJSON(data []byte) error {
 	delete(m, "executors.frontendURL")
 	delete(m, "executors.lsifGoImage")
 	delete(m, "executors.multiqueue")
+	delete(m, "executors.secretManagers")
 	delete(m, "executors.srcCLIImage")
 	delete(m, "executors.srcCLIImageTag")
 	delete(m, "experimentalFeatures")
@@ -2757,6 +3092,8 @@ func (v *SiteConfiguration) UnmarshalJSON(data []byte) error {
 	delete(m, "gitMaxConcurrentClones")
 	delete(m, "gitRecorder")
 	delete(m, "gitUpdateInterval")
+	delete(m, "graphql.complexityBudgets")
+	delete(m, "graphql.persistedQueries")
 	delete(m, "htmlBodyBottom")
 	delete(m, "htmlBodyTop")
 	delete(m, "htmlHeadBottom")
@@ -2852,12 +3189,23 @@ type Step struct {
 type SubRepoPermissions struct {
 	// Enabled description: Enables sub-repo permission checking
 	Enabled bool `json:"enabled,omitempty"`
+	// PathRules description: Path rules layered on top of the sub-repo permissions synced from code hosts. A path matched by a deny rule is always hidden and a path matched by an allow rule is always visible, regardless of the code host permissions. Deny rules take precedence over allow rules.
+	PathRules []*SubRepoPermissionsPathRule `json:"pathRules,omitempty"`
 	// UserCacheSize description: The number of user permissions to cache
 	UserCacheSize int `json:"userCacheSize,omitempty"`
 	// UserCacheTTLSeconds description: The TTL in seconds for cached user permissions
 	UserCacheTTLSeconds int `json:"userCacheTTLSeconds,omitempty"`
 }
 
+type SubRepoPermissionsPathRule struct {
+	// Access description: Whether the matched paths are always visible or always hidden.
+	Access string `json:"access"`
+	// Path description: Glob pattern matched against file paths, such as "secrets/**".
+	Path string `json:"path"`
+	// Repository description: Regular expression matched against the full repository name, such as "github\.com/acme/.*". The rule applies to all repositories if it is empty.
+	Repository string `json:"repository,omitempty"`
+}
+
 // SymbolConfiguration description: Configure symbol generation
 type SymbolConfiguration struct {
 	// Engine description: Manually specify overrides for symbol generation engine per language
@@ -2890,7 +3238,60 @@ type SyntaxHighlightingLanguagePatterns struct {
 	Pattern string `json:"pattern"`
 }
 
+// TailSampling description: Decides whether to export each trace once its root span within a service has ended, instead of when the trace starts. Traces containing an error and traces whose duration exceeds the latency threshold are always exported, and a fraction of the remaining traces is exported at random. Only applies when "sampling" is "all"; traces requested selectively are always exported.
+type TailSampling struct {
+	// Enabled description: Enables tail-based sampling of traces.
+	Enabled bool `json:"enabled,omitempty"`
+	// LatencyThresholdMs description: Traces lasting at least this many milliseconds are always exported. The default is 1000.
+	LatencyThresholdMs *int `json:"latencyThresholdMs,omitempty"`
+	// SampleRate description: The fraction of traces without errors and below the latency threshold that are exported. The default is 0.01.
+	SampleRate *float64 `json:"sampleRate,omitempty"`
+	// Services description: Overrides of the tail sampling policy by service name, e.g. "frontend" or "gitserver".
+	Services map[string]TailSamplingOverride `json:"services,omitempty"`
+}
+
+// TailSamplingOverride description: Overrides the tail sampling policy for a single service. Unset properties are inherited from the global tail sampling policy.
+type TailSamplingOverride struct {
+	// Enabled description: Enables or disables tail-based sampling of traces for this service.
+	Enabled *bool `json:"enabled,omitempty"`
+	// LatencyThresholdMs description: Traces lasting at least this many milliseconds are always exported.
+	LatencyThresholdMs *int `json:"latencyThresholdMs,omitempty"`
+	// SampleRate description: The fraction of traces without errors and below the latency threshold that are exported.
+	SampleRate *float64 `json:"sampleRate,omitempty"`
+}
+
 // TlsExternal description: Global TLS/SSL settings for Sourcegraph to use when communicating with code hosts.
+// TelemetryExportSink description: Where batches of exported events are delivered.
+type TelemetryExportSink struct {
+	// AccessKeyID description: The access key ID of the s3 sink. Uses the default AWS credentials of the worker if not set.
+	AccessKeyID string `json:"accessKeyID,omitempty"`
+	// Authorization description: The value of the Authorization header sent to the endpoint of the https sink.
+	Authorization string `json:"authorization,omitempty"`
+	// Bucket description: The bucket batches are written to. Required for the s3 sink.
+	Bucket string `json:"bucket,omitempty"`
+	// Endpoint description: The endpoint of an S3-compatible service used instead of AWS by the s3 sink.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Prefix description: The prefix of the keys of the objects written by the s3 sink.
+	Prefix string `json:"prefix,omitempty"`
+	// Region description: The AWS region of the bucket of the s3 sink.
+	Region string `json:"region,omitempty"`
+	// SecretAccessKey description: The secret access key of the s3 sink.
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	// Type description: The kind of sink: "pubsub" publishes to the Sourcegraph analytics pubsub topic, "s3" writes an object per batch to an S3 bucket, "https" POSTs each batch to an endpoint, and "none" discards events without exporting them.
+	Type string `json:"type"`
+	// Url description: The endpoint batches are POSTed to as a JSON array of events. Required for the https sink.
+	Url string `json:"url,omitempty"`
+}
+
+// TelemetryRedactionRule description: A field removed from exported events.
+type TelemetryRedactionRule struct {
+	// Events description: The names of the events the rule applies to. Applies to all events if not set.
+	Events []string `json:"events,omitempty"`
+	// Field description: The field of the exported events to remove. The fields describing the instance, such as "initial_admin_email", are removed from all events.
+	Field string `json:"field"`
+	// Keys description: The keys of the public argument to remove, if the field is "public_argument". Removes the whole public argument if not set.
+	Keys []string `json:"keys,omitempty"`
+}
 type TlsExternal struct {
 	// Certificates description: TLS certificates to accept. This is only necessary if you are using self-signed certificates or an internal CA. Can be an internal CA certificate or a self-signed certificate. To get the certificate of a webserver run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh. NOTE: System Certificate Authorities are automatically included.
 	Certificates []string `json:"certificates,omitempty"`
@@ -2912,6 +3313,15 @@ type TransformChangesGroup struct {
 	// Repository description: Only apply this transformation in the repository with this name (as it is known to Sourcegraph).
 	Repository string `json:"repository,omitempty"`
 }
+
+// TrustedSigningKey description: A key trusted to sign commits and tags.
+type TrustedSigningKey struct {
+	// Key description: An ASCII-armored OpenPGP public key, or an SSH public key in authorized_keys format.
+	Key string `json:"key"`
+	// Name description: A name identifying the owner of the key, reported as the signer of verified signatures.
+	Name string `json:"name,omitempty"`
+}
+
 type UpdateIntervalRule struct {
 	// Interval description: An integer representing the number of minutes to wait until the next update
 	Interval int `json:"interval"`
@@ -2923,6 +3333,16 @@ type UsernameIdentity struct {
 }
 
 // WebhookLogging description: Configuration for logging incoming webhooks.
+
+// VaultSecretManager description: A HashiCorp Vault server executor secrets are resolved from. Dynamic secrets are leased for the duration of the job and revoked once it completed.
+type VaultSecretManager struct {
+	// Address description: The URL of the Vault server.
+	Address string `json:"address"`
+	// Namespace description: The Vault Enterprise namespace the referenced paths are in.
+	Namespace string `json:"namespace,omitempty"`
+	// Token description: The token Sourcegraph authenticates to Vault with. It must be allowed to read the referenced paths and to revoke leases.
+	Token string `json:"token,omitempty"`
+}
 type WebhookLogging struct {
 	// Enabled description: Whether incoming webhooks are logged. If omitted, logging is enabled on sites without encryption. If one or more encryption keys are present, this setting must be enabled manually; as webhooks may contain sensitive data, admins of encrypted sites may want to enable webhook encryption via encryption.keys.webhookLogKey.
 	Enabled *bool `json:"enabled,omitempty"`